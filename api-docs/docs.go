@@ -24,7 +24,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/admin/queue/stats": {
+        "/api/v1/admin/adapters": {
             "get": {
                 "security": [
                     {
@@ -34,56 +34,87 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get current queue statistics",
+                "description": "List the adapters that can be configured, with their settings, where those come from (the environment, or the database once changed through this API) and whether the adapter is registered. API keys are masked; keys referencing a secret store show the reference.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Get queue statistics",
+                "summary": "List adapters",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transcription.AdapterStatus"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/api-keys": {
-            "get": {
+        "/api/v1/admin/adapters/reload": {
+            "post": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all API keys for the current user (without exposing the actual keys)",
+                "description": "Register the adapters again whose stored settings changed since they were registered, for example when edited in the database directly",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "api-keys"
+                    "admin"
                 ],
-                "summary": "List API keys",
+                "summary": "Reload adapters",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.APIKeysWrapper"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transcription.AdapterStatus"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/admin/adapters/{id}": {
+            "put": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new API key for external API access",
+                "description": "Enable or disable an adapter, or set its endpoint or API key, replacing the environment's settings (ENABLE_DEFAULT_ADAPTERS, LOCAL_WHISPERX_BASE_URL, RUNPOD_ENDPOINT_ID, ...). The adapter is registered again right away; jobs already running finish with the previous settings. Other processes pick the change up within 30 seconds.",
                 "consumes": [
                     "application/json"
                 ],
@@ -91,25 +122,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "api-keys"
+                    "admin"
                 ],
-                "summary": "Create API key",
+                "summary": "Configure an adapter",
                 "parameters": [
                     {
-                        "description": "API key creation details",
+                        "type": "string",
+                        "description": "Adapter model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settings",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateAPIKeyRequest"
+                            "$ref": "#/definitions/api.AdapterConfigRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.CreateAPIKeyResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transcription.AdapterStatus"
+                            }
                         }
                     },
                     "400": {
@@ -120,43 +161,9 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/api-keys/{id}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete an API key",
-                "tags": [
-                    "api-keys"
-                ],
-                "summary": "Delete API key",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -164,8 +171,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -174,49 +181,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/auth/change-password": {
-            "post": {
+            },
+            "delete": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Change the current user's password",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove the settings set through this API, so the adapter is registered with the environment's again",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Change user password",
+                "summary": "Reset an adapter",
                 "parameters": [
                     {
-                        "description": "Password change details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.ChangePasswordRequest"
-                        }
+                        "type": "string",
+                        "description": "Adapter model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transcription.AdapterStatus"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -224,8 +227,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -236,14 +239,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/change-username": {
+        "/api/v1/admin/alignment-models": {
             "post": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Change the current user's username",
+                "description": "Download the WhisperX alignment models for the given language codes ahead of time. Jobs in a language whose alignment model cannot be loaded still complete, with segment-level timestamps and alignment_fallback set on the job.",
                 "consumes": [
                     "application/json"
                 ],
@@ -251,17 +257,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Change username",
+                "summary": "Prefetch alignment models",
                 "parameters": [
                     {
-                        "description": "Username change details",
+                        "description": "Languages",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.ChangeUsernameRequest"
+                            "$ref": "#/definitions/api.PrefetchAlignmentModelsRequest"
                         }
                     }
                 ],
@@ -270,9 +276,7 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -284,8 +288,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -293,8 +297,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -305,35 +309,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/login": {
-            "post": {
-                "description": "Authenticate user and return JWT token",
-                "consumes": [
-                    "application/json"
+        "/api/v1/admin/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Report, per day, week (from Monday) or month, the jobs and executions that started, how many failed and the failure rate, the minutes of audio transcribed, how long completed executions took on average, and how many jobs were transcribed in each language, overall or by adapter, profile or submitting user. Replays are not counted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Login",
+                "summary": "Usage analytics",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.LoginRequest"
-                        }
+                        "type": "string",
+                        "default": "day",
+                        "description": "Interval: day, week or month",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping: adapter, profile or user; none by default",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD, UTC); defaults to 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD, UTC); defaults to today",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.LoginResponse"
+                            "$ref": "#/definitions/api.UsageAnalyticsResponse"
                         }
                     },
                     "400": {
@@ -345,8 +370,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -357,24 +382,91 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/logout": {
-            "post": {
+        "/api/v1/admin/audit": {
+            "get": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Logout user and invalidate token (client-side action)",
+                "description": "List who did what, newest first: every successful change made through the API (jobs created, transcripts edited, keys created, users deleted, ...) with the actor, client IP, user agent, and route",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
+                ],
+                "summary": "List audit events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username or API key name",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. job.created",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource type, e.g. transcription",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events before this RFC 3339 time",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of events (1-1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Events to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Logout user",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AuditEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -385,64 +477,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/refresh": {
-            "post": {
-                "description": "Rotate refresh token and return new access token",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Refresh access token",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.RefreshTokenResponse"
-                        }
+        "/api/v1/admin/audit/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "/api/v1/auth/register": {
-            "post": {
-                "description": "Register the initial admin user (only allowed when no users exist)",
-                "consumes": [
-                    "application/json"
                 ],
+                "description": "Download the audit events matching the filters as JSON Lines, oldest first, one event per line",
                 "produces": [
-                    "application/json"
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Register initial admin user",
+                "summary": "Export audit log",
                 "parameters": [
                     {
-                        "description": "Registration details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "Username or API key name",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. job.created",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource type, e.g. transcription",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events before this RFC 3339 time",
+                        "name": "until",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "JSON Lines",
                         "schema": {
-                            "$ref": "#/definitions/api.LoginResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -453,41 +554,50 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/api/v1/auth/registration-status": {
+        "/api/v1/admin/aws-tag-routes": {
             "get": {
-                "description": "Check if the application requires initial user registration",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the tag routes of the AWS Transcribe compatible endpoint for the request's organization, highest priority first, with the minutes each route's jobs transcribed this month",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Check registration status",
+                "summary": "List AWS tag routes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.RegistrationStatusResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AWSTagRoute"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/chat/models": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -496,19 +606,33 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of available OpenAI chat models",
+                "description": "Route jobs submitted through the AWS Transcribe compatible endpoint with tag tag_key (equal to tag_value, if given) to a profile and output bucket, and cap the minutes they may transcribe per month. When several routes match a job, the one with the highest priority applies.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
+                ],
+                "summary": "Create an AWS tag route",
+                "parameters": [
+                    {
+                        "description": "Route",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSTagRouteRequest"
+                        }
+                    }
                 ],
-                "summary": "Get available chat models",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/api.ChatModelsResponse"
+                            "$ref": "#/definitions/models.AWSTagRoute"
                         }
                     },
                     "400": {
@@ -519,21 +643,12 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/api/v1/chat/sessions": {
-            "post": {
+        "/api/v1/admin/aws-tag-routes/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -542,7 +657,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new chat session for a transcription",
+                "description": "Replace an AWS tag route. Its usage this month is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -550,25 +665,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Create a new chat session",
+                "summary": "Update an AWS tag route",
                 "parameters": [
                     {
-                        "description": "Chat session creation request",
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Route",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.ChatCreateRequest"
+                            "$ref": "#/definitions/api.AWSTagRouteRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ChatSessionResponse"
+                            "$ref": "#/definitions/models.AWSTagRoute"
                         }
                     },
                     "400": {
@@ -588,9 +710,37 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete an AWS tag route",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -601,7 +751,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/chat/sessions/{session_id}": {
+        "/api/v1/admin/backups": {
             "get": {
                 "security": [
                     {
@@ -611,36 +761,21 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific chat session with all its messages",
+                "description": "List the backup archives in the backup directory, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
-                ],
-                "summary": "Get a chat session with messages",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Chat Session ID",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List backups",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ChatSessionWithMessages"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/backup.Info"
                             }
                         }
                     },
@@ -655,7 +790,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -664,34 +799,27 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a chat session and all its messages",
+                "description": "Snapshot the database and transcripts into a backup archive, copying it to BACKUP_S3_URI when configured unless upload is false. Archives beyond BACKUP_RETENTION are removed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Delete a chat session",
+                "summary": "Create a backup",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Chat Session ID",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Copy the archive to S3 (default true)",
+                        "name": "upload",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/backup.Info"
                         }
                     },
                     "500": {
@@ -706,7 +834,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/chat/sessions/{session_id}/messages": {
+        "/api/v1/admin/backups/restore": {
             "post": {
                 "security": [
                     {
@@ -716,40 +844,40 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Send a message to a chat session and get streaming response",
+                "description": "Stage a backup, uploaded as the \"file\" form field or named in a JSON body, to replace the database and transcripts at the next restart. The server cannot replace the database it is using, so restart it to finish the restore.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Send a message to a chat session",
+                "summary": "Restore a backup",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Chat Session ID",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Message content",
-                        "name": "message",
+                        "description": "Backup in the backup directory",
+                        "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.ChatMessageRequest"
+                            "$ref": "#/definitions/api.RestoreBackupRequest"
                         }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Backup archive",
+                        "name": "file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Streaming response",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -769,21 +897,12 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/api/v1/chat/sessions/{session_id}/title": {
-            "put": {
+        "/api/v1/admin/backups/{name}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -792,52 +911,28 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the title of a chat session",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download a backup archive from the backup directory",
                 "produces": [
-                    "application/json"
+                    "application/gzip"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Update chat session title",
+                "summary": "Download a backup",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Chat Session ID",
-                        "name": "session_id",
+                        "description": "Backup name",
+                        "name": "name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Title update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ChatSessionResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "file"
                         }
                     },
                     "404": {
@@ -848,21 +943,12 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/api/v1/chat/sessions/{session_id}/title/auto": {
-            "post": {
+        "/api/v1/admin/benchmarks": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -871,41 +957,26 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Uses the configured LLM to summarize the first exchange into a concise title. Only updates if the current title appears default/user-unset.",
+                "description": "List the adapter benchmarks, newest first, without their samples and runs",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
-                ],
-                "summary": "Auto-generate chat session title",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Chat Session ID",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List adapter benchmarks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ChatSessionResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Benchmark"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -914,10 +985,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/chat/transcriptions/{transcription_id}/sessions": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -926,31 +995,63 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all chat sessions for a specific transcription",
+                "description": "Run a reference audio set through several registered transcription adapters in the background and score each transcript against its ground truth, with the word and character error rates, latency and cost of every adapter. Upload each audio file as audio and its ground truth, as plain text, as a reference file of the same name with another extension (e.g. call1.wav and call1.txt). Adapters are called with the parameters of profile_name, those of the parameters JSON or the defaults, without diarization. Runs happen one at a time while no job is processing.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chat"
+                    "admin"
                 ],
-                "summary": "Get chat sessions for a transcription",
+                "summary": "Start an adapter benchmark",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Transcription ID",
-                        "name": "transcription_id",
-                        "in": "path",
+                        "description": "Comma-separated transcription adapters, e.g. whisperx,openai_whisper",
+                        "name": "model_ids",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Audio files",
+                        "name": "audio",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Ground-truth transcripts, named after their audio",
+                        "name": "reference",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Benchmark name",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile whose parameters the adapters are called with",
+                        "name": "profile_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameters JSON, when no profile is named",
+                        "name": "parameters",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.ChatSessionResponse"
-                            }
+                            "$ref": "#/definitions/models.Benchmark"
                         }
                     },
                     "400": {
@@ -962,8 +1063,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -974,26 +1075,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/llm/config": {
+        "/api/v1/admin/benchmarks/{id}": {
             "get": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current active LLM configuration",
+                "description": "Get a benchmark's comparison report: every run with its transcript, error rates, latency and cost, and a summary per adapter",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "llm"
+                    "admin"
+                ],
+                "summary": "Get an adapter benchmark",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Benchmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get LLM configuration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.LLMConfigResponse"
+                            "$ref": "#/definitions/api.BenchmarkReport"
                         }
                     },
                     "404": {
@@ -1007,43 +1120,35 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create or update LLM configuration settings",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete a benchmark with its runs and audio. A running benchmark stops after its current run.",
                 "tags": [
-                    "llm"
+                    "admin"
                 ],
-                "summary": "Create or update LLM configuration",
+                "summary": "Delete an adapter benchmark",
                 "parameters": [
                     {
-                        "description": "LLM configuration details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.LLMConfigRequest"
-                        }
+                        "type": "string",
+                        "description": "Benchmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.LLMConfigResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1054,7 +1159,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/notes/{note_id}": {
+        "/api/v1/admin/diagnostics": {
             "get": {
                 "security": [
                     {
@@ -1064,42 +1169,34 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a note by its ID",
+                "description": "Produce a redacted diagnostics bundle to attach to bug reports: versions of Scriberr and its tools, the configuration with secrets and URL credentials stripped, model environment statuses, queue state and recent execution errors with paths and URLs removed. It contains no transcripts, titles, file names or usage volumes.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "admin"
                 ],
-                "summary": "Get a note",
+                "summary": "Get diagnostics bundle",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Note ID",
-                        "name": "note_id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Serve as a file attachment",
+                        "name": "download",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Note"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.DiagnosticsBundle"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/admin/environments": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1108,105 +1205,26 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the content of a note",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Report the progress of the background Python environment bootstrap for each adapter. Jobs that need an environment in the pending or preparing state stay queued until it is ready.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
-                ],
-                "summary": "Update a note",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Note ID",
-                        "name": "note_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Note update payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.NoteUpdateRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Get model environment status",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Note"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete a note by its ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "notes"
-                ],
-                "summary": "Delete a note",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Note ID",
-                        "name": "note_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/v1/profiles": {
+        "/api/v1/admin/imports": {
             "get": {
                 "security": [
                     {
@@ -1216,21 +1234,30 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of all transcription profiles",
+                "description": "List the library imports of the workspaces the request may see, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "admin"
                 ],
-                "summary": "List transcription profiles",
+                "summary": "List library imports",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.TranscriptionProfile"
+                                "$ref": "#/definitions/models.LibraryImport"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
@@ -1245,7 +1272,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new transcription profile",
+                "description": "Walk an S3 prefix or a local directory under LIBRARY_IMPORT_DIR in the background and create a job in the request's workspace for every audio file found. Files already imported into the workspace, by location or content, are skipped unless dedup is false; dry runs only list what they would import. Jobs are queued at most rate_per_minute a minute and only while few of the import's jobs are waiting.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1253,25 +1280,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "admin"
                 ],
-                "summary": "Create transcription profile",
+                "summary": "Start a library import",
                 "parameters": [
                     {
-                        "description": "Profile data",
-                        "name": "profile",
+                        "description": "Import",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionProfile"
+                            "$ref": "#/definitions/api.LibraryImportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionProfile"
+                            "$ref": "#/definitions/models.LibraryImport"
                         }
                     },
                     "400": {
@@ -1282,11 +1309,20 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/v1/profiles/{id}": {
+        "/api/v1/admin/imports/{id}": {
             "get": {
                 "security": [
                     {
@@ -1296,18 +1332,18 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a transcription profile by ID",
+                "description": "Get the progress of a library import",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "admin"
                 ],
-                "summary": "Get transcription profile",
+                "summary": "Get a library import",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Profile ID",
+                        "description": "Import ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1317,7 +1353,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionProfile"
+                            "$ref": "#/definitions/models.LibraryImport"
                         }
                     },
                     "404": {
@@ -1330,8 +1366,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/admin/imports/{id}/cancel": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1340,50 +1378,23 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a transcription profile",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Stop a running library import. The jobs it already queued are kept, and running the import again skips them.",
                 "tags": [
-                    "profiles"
+                    "admin"
                 ],
-                "summary": "Update transcription profile",
+                "summary": "Cancel a library import",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Profile ID",
+                        "description": "Import ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated profile data",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.TranscriptionProfile"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.TranscriptionProfile"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                    "202": {
+                        "description": "Accepted"
                     },
                     "404": {
                         "description": "Not Found",
@@ -1395,8 +1406,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/imports/{id}/files": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1405,26 +1418,52 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a transcription profile",
+                "description": "List the files a library import found, in the order found, with what it did with each: queued, planned (dry run), duplicate or failed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "admin"
                 ],
-                "summary": "Delete transcription profile",
+                "summary": "List the files of a library import",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Profile ID",
+                        "description": "Import ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only files with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size (1-1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Files to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.LibraryImportFilesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1444,8 +1483,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/profiles/{id}/set-default": {
-            "post": {
+        "/api/v1/admin/integrity": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1454,47 +1493,54 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark the specified profile as the default profile",
+                "description": "Get the report of the latest integrity check, run at startup: jobs whose audio files or S3 objects are missing, and processing jobs no worker was running, with what was done about each",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
-                ],
-                "summary": "Set default transcription profile",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Profile ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Get the integrity report",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/service.IntegrityReport"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "404": {
-                        "description": "Not Found",
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check every job against its files now. Unfinished jobs whose audio is missing are failed, processing jobs no worker is running are queued again, and completed jobs missing a file are flagged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Run an integrity check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/service.IntegrityReport"
                         }
                     },
                     "500": {
@@ -1509,52 +1555,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/summaries": {
+        "/api/v1/admin/notification-templates": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     },
-                    {
-                        "BearerAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all summarization templates",
+                "description": "List the message templates that replace the built-in webhook payload, EventBridge event detail and email body. Channels without one send the built-in message.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "summaries"
+                    "admin"
                 ],
-                "summary": "List summarization templates",
+                "summary": "List notification templates",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.SummaryTemplate"
+                                "$ref": "#/definitions/models.NotificationTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/admin/notification-templates/{channel}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     },
-                    {
-                        "BearerAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new summarization template",
+                "description": "Replace a channel's built-in message with a Go template rendered with the job's fields: .JobID, .Title, .Status, .Event, .AudioPath, .AudioURI, .Transcript, .Text, .Summary, .ErrorMessage, .Tags, .Metadata, .CreatedAt, .CompletedAt, .Link (emails only) and .Job. Functions: json, default, truncate, upper, lower and rfc3339. JSON templates (EventBridge, or a JSON content type) must render valid JSON; use json to quote values. Email templates render the plain-text body.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1562,25 +1613,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "summaries"
+                    "admin"
                 ],
-                "summary": "Create summarization template",
+                "summary": "Set a notification template",
                 "parameters": [
                     {
-                        "description": "Template payload",
+                        "type": "string",
+                        "description": "Channel (webhook, eventbridge or email)",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.SummaryTemplateRequest"
+                            "$ref": "#/definitions/api.NotificationTemplateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SummaryTemplate"
+                            "$ref": "#/definitions/models.NotificationTemplate"
                         }
                     },
                     "400": {
@@ -1592,8 +1650,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1602,32 +1660,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/summaries/settings": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get global summarization settings",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Remove a channel's template, so it sends the built-in message again",
                 "tags": [
-                    "summaries"
+                    "admin"
+                ],
+                "summary": "Reset a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel (webhook, eventbridge or email)",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get summary settings",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.SummarySettingsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1636,14 +1698,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/notification-templates/{channel}/preview": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Create or update global summarization settings",
+                "description": "Render a template without saving it, with the fields of the given job or of a sample one",
                 "consumes": [
                     "application/json"
                 ],
@@ -1651,17 +1718,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "summaries"
+                    "admin"
                 ],
-                "summary": "Save summary settings",
+                "summary": "Preview a notification template",
                 "parameters": [
                     {
-                        "description": "Settings payload",
+                        "type": "string",
+                        "description": "Channel (webhook, eventbridge or email)",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template and optional job",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.SummarySettingsRequest"
+                            "$ref": "#/definitions/api.NotificationTemplatePreviewRequest"
                         }
                     }
                 ],
@@ -1669,7 +1743,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.SummarySettingsResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1681,8 +1758,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1693,49 +1770,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/summaries/{id}": {
+        "/api/v1/admin/quarantine": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     },
-                    {
-                        "BearerAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a summarization template by ID",
+                "description": "List jobs whose uploads were flagged by the upload scanner and are held for review",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "summaries"
-                ],
-                "summary": "Get summarization template",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Template ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List quarantined jobs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SummaryTemplate"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TranscriptionJob"
                             }
                         }
                     },
@@ -1749,57 +1808,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/admin/quarantine/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     },
-                    {
-                        "BearerAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a summarization template by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete a flagged job together with its uploaded files",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "summaries"
+                    "admin"
                 ],
-                "summary": "Update summarization template",
+                "summary": "Delete a quarantined job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Template ID",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Template payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.SummaryTemplateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SummaryTemplate"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1807,8 +1856,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1826,35 +1875,58 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/quarantine/{id}/release": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Delete a summarization template by ID",
+                "description": "Mark a flagged upload as safe after review. The job is queued for processing and is not scanned again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "summaries"
+                    "admin"
                 ],
-                "summary": "Delete summarization template",
+                "summary": "Release a quarantined job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Template ID",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -1869,8 +1941,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/summarize": {
-            "post": {
+        "/api/v1/admin/queue/stats": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1879,57 +1951,26 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Stream an LLM-generated summary for provided content; persists latest summary for the transcription",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get current queue statistics, with the host's free GPU memory and CPU load and the GPU memory reserved by running local jobs under resources",
                 "produces": [
-                    "text/event-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "summarize"
-                ],
-                "summary": "Summarize content",
-                "parameters": [
-                    {
-                        "description": "Summarize request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.SummarizeRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Get queue statistics",
                 "responses": {
                     "200": {
-                        "description": "Event stream",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/v1/transcription/list": {
+        "/api/v1/admin/quick-transcription/cleanup": {
             "get": {
                 "security": [
                     {
@@ -1937,7 +1978,27 @@ const docTemplate = `{
                     },
                     {
                         "BearerAuth": []
-                    },
+                    }
+                ],
+                "description": "Report the retention and storage policies of quick transcription temp files and the space reclaimed since startup",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get quick transcription cleanup statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transcription.QuickCleanupStats"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
                         "ApiKeyAuth": []
                     },
@@ -1945,82 +2006,25 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all transcription jobs with optional search and filtering\nGet a list of all transcription jobs with optional search and filtering",
+                "description": "Apply the quick transcription retention and storage policies now instead of waiting for the next scheduled run",
                 "produces": [
-                    "application/json",
                     "application/json"
                 ],
                 "tags": [
-                    "transcription",
-                    "transcription"
-                ],
-                "summary": "List all transcription records",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search in title and audio filename",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search in title and audio filename",
-                        "name": "q",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Clean up quick transcriptions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/transcription.QuickCleanupStats"
                         }
                     }
                 }
             }
         },
-        "/api/v1/transcription/models": {
+        "/api/v1/admin/reproducibility/components": {
             "get": {
                 "security": [
                     {
@@ -2030,72 +2034,37 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of supported WhisperX models",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "transcription"
-                ],
-                "summary": "Get supported models",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/transcription/quick": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Submit an audio file for temporary transcription (data discarded after 6 hours)",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "List the versions of the adapters, Python packages or model checkpoints that jobs were transcribed with, and how many jobs each produced. Only each job's latest execution counts, so reprocessed jobs move to the version that reprocessed them.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Submit quick transcription job",
+                "summary": "List component versions",
                 "parameters": [
-                    {
-                        "type": "file",
-                        "description": "Audio file",
-                        "name": "audio",
-                        "in": "formData",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "JSON string of transcription parameters",
-                        "name": "parameters",
-                        "in": "formData"
+                        "description": "Component kind (adapter, package, checkpoint)",
+                        "name": "kind",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Profile name to use for transcription",
-                        "name": "profile_name",
-                        "in": "formData"
+                        "description": "Only this component, e.g. whisperx or torch",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/transcription.QuickTranscriptionJob"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ComponentVersion"
+                            }
                         }
                     },
                     "400": {
@@ -2119,7 +2088,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/quick/{id}": {
+        "/api/v1/admin/reproducibility/jobs": {
             "get": {
                 "security": [
                     {
@@ -2129,32 +2098,57 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current status of a quick transcription job",
+                "description": "List the jobs whose latest execution ran on a component, on one of the given versions if any, e.g. the jobs a since-fixed adapter or package release transcribed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Get quick transcription status",
+                "summary": "List jobs produced by a component version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Component kind (adapter, package, checkpoint)",
+                        "name": "kind",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Component name, e.g. whisperx or torch",
+                        "name": "name",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated versions",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/transcription.QuickTranscriptionJob"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ComponentJob"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2165,7 +2159,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/submit": {
+        "/api/v1/admin/reproducibility/reprocess": {
             "post": {
                 "security": [
                     {
@@ -2175,109 +2169,33 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Submit an audio file for transcription with WhisperX",
+                "description": "Transcribe again, with their own parameters, the completed and failed jobs whose latest execution ran on a component version. Their transcripts and derived results are cleared as for a re-transcription. Finalized jobs and jobs that are queued or processing are skipped.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Submit a transcription job",
+                "summary": "Reprocess jobs produced by a component version",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Audio file",
-                        "name": "audio",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Job title",
-                        "name": "title",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Enable speaker diarization",
-                        "name": "diarization",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "default": "base",
-                        "description": "Whisper model",
-                        "name": "model",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Language code",
-                        "name": "language",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 16,
-                        "description": "Batch size",
-                        "name": "batch_size",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "default": "float16",
-                        "description": "Compute type",
-                        "name": "compute_type",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "default": "auto",
-                        "description": "Device",
-                        "name": "device",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Enable VAD filter",
-                        "name": "vad_filter",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "default": 0.5,
-                        "description": "VAD onset",
-                        "name": "vad_onset",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "default": 0.363,
-                        "description": "VAD offset",
-                        "name": "vad_offset",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Minimum speakers for diarization",
-                        "name": "min_speakers",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Maximum speakers for diarization",
-                        "name": "max_speakers",
-                        "in": "formData"
+                        "description": "Component version and optional jobs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReprocessRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
+                            "$ref": "#/definitions/api.ReprocessResponse"
                         }
                     },
                     "400": {
@@ -2301,7 +2219,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/upload": {
+        "/api/v1/admin/retention/enforce": {
             "post": {
                 "security": [
                     {
@@ -2311,50 +2229,23 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload an audio file without starting transcription",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Apply the enforced retention policies now rather than at the next scheduled run",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
-                ],
-                "summary": "Upload audio file",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "Audio file",
-                        "name": "audio",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Job title",
-                        "name": "title",
-                        "in": "formData"
-                    }
+                    "admin"
                 ],
+                "summary": "Enforce retention",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
+                            "$ref": "#/definitions/service.RetentionReport"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2365,8 +2256,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/upload-multitrack": {
-            "post": {
+        "/api/v1/admin/retention/policies": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -2375,45 +2266,21 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload multiple audio files for multi-track transcription",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "List the retention policies of the request's organization, or of the workspaces outside every organization",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
-                ],
-                "summary": "Upload multi-track audio files",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Job title",
-                        "name": "title",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Audio track files",
-                        "name": "files",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List retention policies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RetentionPolicy"
                             }
                         }
                     },
@@ -2427,9 +2294,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/transcription/upload-video": {
+            },
             "post": {
                 "security": [
                     {
@@ -2439,37 +2304,33 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload a video file, extract audio from it using ffmpeg, and create a transcription job",
+                "description": "Delete or archive the audio or the whole of jobs older than after_days, for every job of the request's organization (or of the workspaces outside every organization), only those transcribed with a profile, or only those with a tag. A tag's policy applies in place of a profile's, which applies in place of the policy for every job. Archive policies copy the files to archive_uri in storage_class (default GLACIER) first. Policies start as a dry run, reported by the preview, until enforced.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Upload video file for transcription",
+                "summary": "Create a retention policy",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Video file",
-                        "name": "video",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Job title",
-                        "name": "title",
-                        "in": "formData"
+                        "description": "Policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RetentionPolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
+                            "$ref": "#/definitions/models.RetentionPolicy"
                         }
                     },
                     "400": {
@@ -2480,21 +2341,12 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/api/v1/transcription/youtube": {
-            "post": {
+        "/api/v1/admin/retention/policies/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -2503,7 +2355,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Download audio from a YouTube video URL and prepare it for transcription",
+                "description": "Replace a retention policy, e.g. to enforce it once its preview looks right",
                 "consumes": [
                     "application/json"
                 ],
@@ -2511,17 +2363,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Download audio from YouTube URL",
+                "summary": "Update a retention policy",
                 "parameters": [
                     {
-                        "description": "YouTube download request",
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.YouTubeDownloadRequest"
+                            "$ref": "#/definitions/api.RetentionPolicyRequest"
                         }
                     }
                 ],
@@ -2529,7 +2388,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
+                            "$ref": "#/definitions/models.RetentionPolicy"
                         }
                     },
                     "400": {
@@ -2541,8 +2400,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2551,10 +2410,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/transcription/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -2563,29 +2420,22 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific transcription job",
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Get transcription job details",
+                "summary": "Delete a retention policy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Job ID",
+                        "type": "integer",
+                        "description": "Policy ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -2597,15 +2447,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/retention/preview": {
+            "get": {
                 "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    },
                     {
                         "ApiKeyAuth": []
                     },
@@ -2613,53 +2459,23 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a transcription job and its associated files\nDelete a transcription job and its associated files",
+                "description": "Dry run: list what every retention policy would delete or archive now, and whether the policy is enforced, without changing anything",
                 "produces": [
-                    "application/json",
                     "application/json"
                 ],
                 "tags": [
-                    "transcription",
-                    "transcription"
-                ],
-                "summary": "Delete transcription job",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Preview retention",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/service.RetentionReport"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2670,41 +2486,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/audio": {
+        "/api/v1/admin/shadow-runs": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Serve the audio file for a transcription job",
+                "description": "List the latest jobs transcribed again by a candidate adapter in shadow mode, with each run's word agreement with the transcript users received, and a summary per candidate. Transcripts are left out; fetch a run for its transcript.",
                 "produces": [
-                    "audio/mpeg",
-                    "audio/wav",
-                    "audio/mp4"
+                    "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Get audio file",
+                "summary": "List shadow runs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only runs of this candidate adapter",
+                        "name": "model_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of runs (1-500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/api.ShadowRunsResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2715,7 +2538,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/execution": {
+        "/api/v1/admin/shadow-runs/{id}": {
             "get": {
                 "security": [
                     {
@@ -2725,18 +2548,18 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get execution parameters and timing for a transcription job",
+                "description": "Get a shadow run with the candidate adapter's transcript",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Get transcription job execution data",
+                "summary": "Get shadow run",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Job ID",
+                        "description": "Shadow run ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2746,7 +2569,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJobExecution"
+                            "$ref": "#/definitions/models.ShadowRun"
                         }
                     },
                     "404": {
@@ -2761,8 +2584,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/kill": {
-            "post": {
+        "/api/v1/admin/storage": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -2771,31 +2594,28 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cancel a currently running transcription job",
+                "description": "Report the disk usage of the upload directory (in total and by workspace), transcripts, backups, the database and the downloaded-file cache, with the jobs whose audio takes the most space",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Kill running transcription job",
+                "summary": "Get storage usage",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 20,
+                        "description": "How many of the largest jobs to list (1-1000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/service.StorageReport"
                         }
                     },
                     "400": {
@@ -2807,8 +2627,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2819,8 +2639,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/merge-status": {
-            "get": {
+        "/api/v1/admin/storage/cleanup": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -2829,110 +2649,82 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current merge status for a multi-track job",
+                "description": "Delete cached downloads and partial downloads older than max_age_minutes, except those of jobs waiting or being processed and downloads still being written, and apply the quick transcription retention and storage policies now",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "admin"
                 ],
-                "summary": "Get multi-track merge status",
+                "summary": "Clean up storage",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 60,
+                        "description": "Only remove downloads at least this old (at least 15)",
+                        "name": "max_age_minutes",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.StorageCleanupResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/transcription/{id}/notes": {
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/api-keys": {
             "get": {
                 "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all notes attached to a transcription, ordered by time and creation",
+                "description": "Get all API keys for the current user (without exposing the actual keys)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
-                ],
-                "summary": "List notes for a transcription",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Transcription ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "api-keys"
                 ],
+                "summary": "List API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Note"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.APIKeysWrapper"
                         }
                     }
                 }
             },
             "post": {
                 "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new note attached to the specified transcription",
+                "description": "Create a new API key for external API access. The key acts with the given role (editor by default), narrowed by its scopes: submit keys only submit jobs and follow them, read keys only read, and only admin-scoped keys change or delete data. Keys can expire and be rate limited per minute.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2940,24 +2732,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "api-keys"
                 ],
-                "summary": "Create a note for a transcription",
+                "summary": "Create API key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Transcription ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Note create payload",
+                        "description": "API key creation details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.NoteCreateRequest"
+                            "$ref": "#/definitions/api.CreateAPIKeyRequest"
                         }
                     }
                 ],
@@ -2965,7 +2750,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Note"
+                            "$ref": "#/definitions/api.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -2976,41 +2761,26 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/api/v1/transcription/{id}/speakers": {
-            "get": {
+        "/api/v1/api-keys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all custom speaker names for a transcription job",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete an API key",
                 "tags": [
-                    "transcription"
+                    "api-keys"
                 ],
-                "summary": "Get speaker mappings for a transcription",
+                "summary": "Delete API key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Transcription Job ID",
+                        "type": "integer",
+                        "description": "API Key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3020,9 +2790,9 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.SpeakerMappingResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -3043,28 +2813,18 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/auth/change-password": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates or creates custom speaker names for a transcription job",
+                "description": "Change the current user's password",
                 "consumes": [
                     "application/json"
                 ],
@@ -3072,39 +2832,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "auth"
                 ],
-                "summary": "Update speaker mappings for a transcription",
+                "summary": "Change user password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Transcription Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Speaker mappings to update",
+                        "description": "Password change details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.SpeakerMappingsUpdateRequest"
+                            "$ref": "#/definitions/api.ChangePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.SpeakerMappingResponse"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3112,8 +2856,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3121,8 +2865,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3133,17 +2877,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/start": {
+        "/api/v1/auth/change-username": {
             "post": {
                 "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Start transcription for an already uploaded audio file",
+                "description": "Change the current user's username",
                 "consumes": [
                     "application/json"
                 ],
@@ -3151,24 +2892,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "auth"
                 ],
-                "summary": "Start transcription for uploaded file",
+                "summary": "Change username",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Transcription parameters",
-                        "name": "parameters",
+                        "description": "Username change details",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.WhisperXParams"
+                            "$ref": "#/definitions/api.ChangeUsernameRequest"
                         }
                     }
                 ],
@@ -3176,7 +2910,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -3188,8 +2925,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3200,42 +2946,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/status": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
+        "/api/v1/auth/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get the current status of a transcription job",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "auth"
                 ],
-                "summary": "Get job status",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionJob"
+                            "$ref": "#/definitions/api.LoginResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3246,60 +2998,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/summary": {
-            "get": {
+        "/api/v1/auth/logout": {
+            "post": {
                 "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the most recent saved summary for the given transcription",
+                "description": "Logout user and invalidate token (client-side action)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "summarize"
-                ],
-                "summary": "Get latest summary for transcription",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Transcription ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Logout user",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Summary"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "Rotate refresh token and return new access token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh access token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.RefreshTokenResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3310,20 +3055,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/title": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update the title of an audio file / transcription",
+        "/api/v1/auth/register": {
+            "post": {
+                "description": "Register the initial admin user (only allowed when no users exist)",
                 "consumes": [
                     "application/json"
                 ],
@@ -3331,36 +3065,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "auth"
                 ],
-                "summary": "Update transcription title",
+                "summary": "Register initial admin user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Title update request",
+                        "description": "Registration details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.RegisterRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.LoginResponse"
                         }
                     },
                     "400": {
@@ -3372,8 +3095,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3384,54 +3107,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/transcription/{id}/track-progress": {
+        "/api/v1/auth/registration-status": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get real-time progress information for individual tracks in a multi-track job",
+                "description": "Check if the application requires initial user registration",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
-                ],
-                "summary": "Get multi-track job progress",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Check registration status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.RegistrationStatusResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/transcription/{id}/transcript": {
+        "/api/v1/chat/models": {
             "get": {
                 "security": [
                     {
@@ -3441,29 +3137,19 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the transcript for a completed transcription job",
+                "description": "Get list of available OpenAI chat models",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transcription"
-                ],
-                "summary": "Get transcript",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "chat"
                 ],
+                "summary": "Get available chat models",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.ChatModelsResponse"
                         }
                     },
                     "400": {
@@ -3475,8 +3161,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3487,30 +3173,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/default-profile": {
+        "/api/v1/chat/policy": {
             "get": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the default transcription profile for the current user",
+                "description": "Get the system prompt, banned topics and context limits in effect for chats in the request's workspace, including the instance-wide policy. Admins can read the policy stored for another workspace, or the instance-wide one with workspace=*.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "chat"
+                ],
+                "summary": "Get chat policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace whose stored policy to get (admins only); * for the instance-wide policy",
+                        "name": "workspace",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get user's default profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TranscriptionProfile"
+                            "$ref": "#/definitions/api.ChatPolicyResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3520,13 +3226,16 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Set the default transcription profile for the current user",
+                "description": "Set the system prompt, banned topics and context limits applied to chats in the request's workspace, another workspace, or with workspace=* every workspace, organizations included. A workspace's policy applies on top of the instance-wide one: both system prompts and all banned topics apply, and the stricter limits. The system prompt is put before the assistant's instructions; messages raising a banned topic are refused with 422 and recorded in the audit log, and the assistant is told not to discuss them. max_context_tokens caps the estimated tokens per request below the model's window and max_history_messages limits how much of the conversation is sent; 0 leaves either unlimited.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3534,17 +3243,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "chat"
                 ],
-                "summary": "Set user's default profile",
+                "summary": "Update chat policy",
                 "parameters": [
                     {
-                        "description": "Default profile request",
+                        "type": "string",
+                        "description": "Workspace to set the policy of; * for the instance-wide policy",
+                        "name": "workspace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chat policy",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.SetUserDefaultProfileRequest"
+                            "$ref": "#/definitions/api.ChatPolicyRequest"
                         }
                     }
                 ],
@@ -3552,10 +3267,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.ChatPolicyResponse"
                         }
                     },
                     "400": {
@@ -3567,8 +3279,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3579,30 +3291,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/settings": {
-            "get": {
+        "/api/v1/chat/sessions": {
+            "post": {
                 "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current user's settings including auto-transcription preference",
+                "description": "Create a new chat session for a transcription",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "chat"
+                ],
+                "summary": "Create a new chat session",
+                "parameters": [
+                    {
+                        "description": "Chat session creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ChatCreateRequest"
+                        }
+                    }
                 ],
-                "summary": "Get user settings",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/api.UserSettingsResponse"
+                            "$ref": "#/definitions/api.ChatSessionResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3620,40 +3358,157 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/chat/sessions/{session_id}": {
+            "get": {
                 "security": [
                     {
-                        "BearerAuth": []
-                    }
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
-                "description": "Update the current user's settings",
-                "consumes": [
+                "description": "Get a specific chat session with all its messages",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Get a chat session with messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ChatSessionWithMessages"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a chat session and all its messages",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "chat"
                 ],
-                "summary": "Update user settings",
+                "summary": "Delete a chat session",
                 "parameters": [
                     {
-                        "description": "Settings update request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Chat Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/chat/sessions/{session_id}/messages": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a message to a chat session and get streaming response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Send a message to a chat session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message content",
+                        "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateUserSettingsRequest"
+                            "$ref": "#/definitions/api.ChatMessageRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Streaming response",
                         "schema": {
-                            "$ref": "#/definitions/api.UserSettingsResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -3665,8 +3520,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3686,19 +3541,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Check if the API is healthy",
+        "/api/v1/chat/sessions/{session_id}/title": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the title of a chat session",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "chat"
+                ],
+                "summary": "Update chat session title",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Title update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ChatSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3709,214 +3620,15115 @@ const docTemplate = `{
                 }
             }
         },
-        "/transcription/{id}/logs": {
-            "get": {
-                "description": "Get the raw transcription logs for a job",
+        "/api/v1/chat/sessions/{session_id}/title/auto": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uses the configured LLM to summarize the first exchange into a concise title. Only updates if the current title appears default/user-unset.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "transcription"
+                    "chat"
                 ],
-                "summary": "Get transcription logs",
+                "summary": "Auto-generate chat session title",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Job ID",
-                        "name": "id",
+                        "description": "Chat Session ID",
+                        "name": "session_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Log content",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.ChatSessionResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.APIKeyListResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/api/v1/chat/transcriptions/{transcription_id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all chat sessions for a specific transcription",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Get chat sessions for a transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "transcription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.ChatSessionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/chat/workspace": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Answer a question using the most relevant passages from every completed transcription, retrieved from the embedding index. The answer cites job IDs and timestamps as [job:\u003cid\u003e @ HH:MM:SS]. Use from/to to limit retrieval to transcriptions created in a date range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Chat across all transcripts",
+                "parameters": [
+                    {
+                        "description": "Conversation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkspaceChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkspaceChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/collections": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the collections of the caller's workspace it may access, by name, with the number of jobs filed directly in each and the caller's role in it. Collections nest through parent_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "List collections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.CollectionResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a collection to the caller's workspace, inside parent_id if given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Create a collection",
+                "parameters": [
+                    {
+                        "description": "Collection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Get a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename a collection, or move it, with everything inside it, into another given as parent_id, or to the top level without one. Only admins may move a collection out of a restricted one into one it does not restrict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Update a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a collection. Its jobs and collections move into its parent, or to the top level. Only admins may delete restricted collections, whose contents would lose the restriction.",
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Delete a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export the bundles of the jobs in a collection and the collections inside it that the caller may access as one zip, a directory per collection and recording (see GET /api/v1/transcription/{id}/bundle)",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Export a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}/jobs": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move jobs of the collection's workspace into it, out of any other collection they were in. List a collection's jobs with collection_id on GET /api/v1/transcription/list. Only admins may move jobs out of a restricted collection into one it does not restrict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "File jobs in a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Jobs (at most 500)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionJobsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}/jobs/{job_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a job of the collection to the top level, filed in no collection. Only admins may take jobs out of restricted collections.",
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Take a job out of a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get whether a collection is restricted, and the members who may access it then",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Get the permissions of a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionPermissionsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restrict a collection, the collections inside it and their jobs to admins and the given members, each with at most the given role (editor or viewer) in it, or open it to the workspace again. Restricted jobs are left out of the jobs list and answered 404 to everyone else. Replaces the members.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Set the permissions of a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permissions",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionPermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CollectionPermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/config/openai/validate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validate the provided OpenAI API key and return available Whisper models",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Validate OpenAI API Key",
+                "parameters": [
+                    {
+                        "description": "API Key",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidateOpenAIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/correction-rules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the find-and-replace rules of the request's organization, in the order they apply",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "List correction rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CorrectionRule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a find-and-replace rule that corrects the transcripts of the request's organization once they are transcribed. Exact rules replace their text where it is a whole word or phrase; regex rules replace matches of a regular expression, and may refer to its groups ($1) in their replacement. Rules ignore case unless case_sensitive is set, and apply in order of position.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "Create a correction rule",
+                "parameters": [
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CorrectionRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CorrectionRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/correction-rules/apply": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Correct the stored transcripts of completed jobs with the organization's enabled rules, e.g. after adding a rule. Without job_ids, every completed job of the organization is corrected. Finalized jobs and jobs that are not completed are skipped. Each corrected transcript is recorded in the audit log.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "Apply correction rules to existing transcripts",
+                "parameters": [
+                    {
+                        "description": "Jobs to correct",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.ApplyCorrectionRulesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ApplyCorrectionRulesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/correction-rules/preview": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Correct text, or the transcript of a job, with the organization's enabled rules or with an unsaved rule, without saving anything. For a job, only the segments that would change are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "Preview correction rules",
+                "parameters": [
+                    {
+                        "description": "Text or job, and optional rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CorrectionPreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CorrectionPreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/correction-rules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "Get a correction rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CorrectionRule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a correction rule. Transcripts it already corrected are left as they are until rules are applied to them again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "Update a correction rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CorrectionRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CorrectionRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "correction-rules"
+                ],
+                "summary": "Delete a correction rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/costs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Report what the jobs completed between two days are estimated to have cost at the per-minute adapter prices of ADAPTER_COST_PER_MINUTE, grouped by day, profile, API key or the adapter that transcribed them. Each job's own estimate is its estimated_cost. Replays are not counted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Report job costs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Grouping: day, profile, api_key or adapter",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD, UTC); defaults to 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD, UTC); defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CostReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/custody/public-key": {
+            "get": {
+                "description": "Return the Ed25519 public key custody manifests are signed with, so they can be verified without this instance: the signature covers the manifest serialized as JSON without its signature field",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get custody signing public key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustodyPublicKey"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feeds": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the RSS/podcast feed subscriptions of the request's workspace",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "List feed subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FeedSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register an RSS/podcast feed. New episodes are downloaded and transcribed with the given profile (or the default profile), and jobs are tagged with the episode title, publish date and GUID. On the first poll only the newest backfill_count episodes are transcribed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Subscribe to feed",
+                "parameters": [
+                    {
+                        "description": "Feed subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FeedSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feeds/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a feed subscription by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get feed subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedSubscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a feed subscription's URL, profile, poll interval or active flag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FeedSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop polling a feed. Transcriptions already created from it are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Delete feed subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feeds/{id}/episodes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the episodes seen in a feed and the transcription job created for each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "List feed episodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FeedEpisode"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feeds/{id}/poll": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the feed immediately and queue any new episodes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Poll feed now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/llm/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current active LLM configuration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "llm"
+                ],
+                "summary": "Get LLM configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.LLMConfigResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create or update LLM configuration settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "llm"
+                ],
+                "summary": "Create or update LLM configuration",
+                "parameters": [
+                    {
+                        "description": "LLM configuration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LLMConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.LLMConfigResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/models/{id}/schema": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the parameters a registered adapter accepts, with their types, defaults, ranges, options and UI groups, so forms can be built from them instead of hardcoding WhisperX flags. model_families are the profile model_family values that select the adapter. Model IDs are those listed by GET /api/v1/transcription/models.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get model parameter schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Model ID, e.g. whisperx",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transcription.ModelSchema"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notes/{note_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a note by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Get a note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Note"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the content of a note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Update a note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note update payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.NoteUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Note"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a note by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Delete a note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the organizations of the instance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organizations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Organization"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an organization. Its members, and API keys created for it, only see its jobs, profiles and LLM configuration, and its files are stored under a prefix of its own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create organization",
+                "parameters": [
+                    {
+                        "description": "Organization",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/organizations/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an organization with its memberships, profiles and LLM configuration, and deactivate its API keys. Organizations that still have jobs cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Delete organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/organizations/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the users of an organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organization members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a user to an organization. Users act in their first organization unless they pick another with the X-Organization-ID header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Add organization member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AddOrganizationMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/organizations/{id}/members/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a user from an organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove organization member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of all transcription profiles",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "List transcription profiles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TranscriptionProfile"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new transcription profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Create transcription profile",
+                "parameters": [
+                    {
+                        "description": "Profile data",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a transcription profile by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Get transcription profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a transcription profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Update transcription profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated profile data",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a transcription profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Delete transcription profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a profile from a version of another, by default its current one, so the parameters a job ran with can be reproduced or tweaked without changing the original. The clone starts at version 1 and is not the default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Clone transcription profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.CloneProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}/set-default": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark the specified profile as the default profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Set default transcription profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the versions of a transcription profile, oldest first. Every change of the profile's name, description or parameters is kept as its next version; jobs record the version they were queued with in profile_version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "List profile versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProfileVersion"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a transcription profile as it was at one of its versions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Get profile version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProfileVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quota": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get how many minutes of audio the caller's workspace has transcribed this month, out of its monthly quota. Submissions are rejected with 429 once the quota is used up, until the next month (UTC).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get transcription quota",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.QuotaResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recordings": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start collecting a recording made in the browser. Upload MediaRecorder chunks in order with PUT /api/v1/recordings/{id}/chunks/{index}, then finalize the session into a job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Start a recording session",
+                "parameters": [
+                    {
+                        "description": "Recording",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StartRecordingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecordingSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recordings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a recording session; after a page reload, resume uploading from chunk_count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Get a recording session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecordingSession"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a recording session. The uploaded audio is removed unless the session was finalized into a job.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Discard a recording session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recordings/{id}/chunks/{index}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Append the raw bytes of a MediaRecorder chunk. Chunks must arrive in order starting at 0; re-sending an already stored chunk is a no-op, so uploads can be retried safely. A recording may not grow past RECORDING_MAX_MB.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Append a recording chunk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Chunk index",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecordingSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recordings/{id}/finalize": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn the uploaded chunks into a transcription job. Finalizing again returns the same job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Finalize a recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.FinalizeRecordingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the scheduled reports of the request's workspace",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "List report schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.ReportScheduleResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule a report on the request's workspace. Whenever the cron expression (five fields, or @daily, @weekly and the like) fires in the given timezone (default UTC), a report covering the time since the previous one is compiled: jobs submitted by status, month-to-date transcribed audio, new transcripts, mentions of the flagged keywords in them, and failed jobs with their errors. It is rendered as Markdown or JSON and delivered to every destination given: emailed to email_to (needs SMTP), POSTed to webhook_url and/or written under s3_uri.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Create report schedule",
+                "parameters": [
+                    {
+                        "description": "Report schedule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReportScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReportScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a report schedule by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get report schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReportScheduleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a report schedule's settings; it next runs when its cron expression next fires",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Update report schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report schedule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReportScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReportScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop a scheduled report. Reports already delivered are not affected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Delete report schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/schedules/{id}/preview": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render the report the schedule would deliver now, in its format, without delivering it or starting a new period",
+                "produces": [
+                    "text/markdown",
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Preview report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rendered report",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/schedules/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compile the schedule's report and deliver it now; the next scheduled report covers the time from here on. Answers 502 with the report if any destination failed, which is also kept in last_error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Run report now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.Report"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search transcript text, titles, summaries and notes. Returns matching transcriptions ordered by relevance, each with snippets, highlight offsets (Unicode code points into the snippet) and, for transcript segments and notes, start/end timestamps in seconds. The last word is prefix-matched.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Full-text search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum transcriptions to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Maximum matches per transcription",
+                        "name": "matches",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/speakers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the speaker directory of the caller's workspace, with the number of jobs each speaker is mapped in",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "speakers"
+                ],
+                "summary": "List speakers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.SpeakerWithJobs"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a person to the speaker directory. Naming a diarized speaker in a job's speaker mappings also adds them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "speakers"
+                ],
+                "summary": "Create a speaker",
+                "parameters": [
+                    {
+                        "description": "Speaker",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SpeakerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Speaker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/speakers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a speaker and every job it is mapped in, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "speakers"
+                ],
+                "summary": "Get a speaker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Speaker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SpeakerResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename a speaker in the directory and in every job it is mapped in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "speakers"
+                ],
+                "summary": "Rename a speaker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Speaker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SpeakerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Speaker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a speaker from the directory. Jobs it is mapped in keep the speaker's name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "speakers"
+                ],
+                "summary": "Delete a speaker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Speaker ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/summaries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all summarization templates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "List summarization templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SummaryTemplate"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new summarization template",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "Create summarization template",
+                "parameters": [
+                    {
+                        "description": "Template payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SummaryTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SummaryTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/summaries/settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get global summarization settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "Get summary settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SummarySettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create or update global summarization settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "Save summary settings",
+                "parameters": [
+                    {
+                        "description": "Settings payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SummarySettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SummarySettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/summaries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a summarization template by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "Get summarization template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SummaryTemplate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a summarization template by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "Update summarization template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SummaryTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SummaryTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a summarization template by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summaries"
+                ],
+                "summary": "Delete summarization template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/summarize": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream an LLM-generated summary; persists latest summary for the transcription. With a template, the stored transcript is summarized with the template's prompt, chunk by chunk when it is long, and content is ignored; otherwise content is sent as the prompt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "summarize"
+                ],
+                "summary": "Summarize content",
+                "parameters": [
+                    {
+                        "description": "Summarize request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SummarizeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the tags of the caller's workspace, most used first, with the number of jobs each is assigned to. With q, only the tags whose key, value or key=value name starts with it are listed, for autocompletion.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prefix to autocomplete",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "At most this many tags; 10 by default with q, else all",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.TagWithJobs"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a tag to the caller's workspace. Jobs submitted with tags, e.g. through the AWS Transcribe compatible endpoint, add theirs too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Create a tag",
+                "parameters": [
+                    {
+                        "description": "Tag",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename or recolor a tag. Renaming it renames it on every job it is assigned to.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Update a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a tag from every job it is assigned to and delete it. Tags that retention policies apply to cannot be deleted.",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcribe/quick": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transcribe a clip of up to FAST_TRANSCRIPTION_MAX_SECONDS (default 60) with the small local fast model and return the transcript in the response, for voice memos and commands. Nothing is stored: no job is created and the audio is deleted once transcribed. The transcription is stopped and 504 returned if it takes longer than FAST_TRANSCRIPTION_TIMEOUT_SECONDS (default 30), or the shorter timeout the request gives.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Transcribe a short clip synchronously",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Audio clip",
+                        "name": "audio",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON string of transcription parameters; the language and task are used",
+                        "name": "parameters",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile whose language and task to use",
+                        "name": "profile_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds to wait for the transcript, at most the configured timeout",
+                        "name": "timeout",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.TranscriptResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/aws-transcribe": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List jobs, newest first, as AWS Transcribe's ListTranscriptionJobs does. Pass the NextToken of a response to get the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "List AWS transcribe compatible jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "QUEUED, IN_PROGRESS, FAILED or COMPLETED",
+                        "name": "Status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only jobs whose TranscriptionJobName contains this",
+                        "name": "JobNameContains",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Jobs per page, up to 100",
+                        "name": "MaxResults",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the page to get",
+                        "name": "NextToken",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSListTranscriptionJobsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submit AWS transcribe compatible job. The highest-priority tag route matching the job's Tags chooses its profile, output bucket and monthly quota; jobs matching none are transcribed with the default profile. Settings.VocabularyName and Settings.VocabularyFilterName name a custom vocabulary and vocabulary filter; VocabularyFilterMethod is remove (the default), mask or tag. ParameterOverrides replaces parameters of the profile for this job only, and is checked against the schema of the adapter it selects.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Submit AWS transcribe compatible job",
+                "parameters": [
+                    {
+                        "description": "API Key",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSTranscribeJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/aws-transcribe/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a job as AWS Transcribe's GetTranscriptionJob does, by its ID or TranscriptionJobName (the newest job of that name). Statuses are QUEUED, IN_PROGRESS, FAILED (with FailureReason) and COMPLETED. Transcript.TranscriptFileUri of completed jobs is a presigned URL, valid for 15 minutes, of the transcript in the output bucket, or else the job's transcript endpoint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get AWS transcribe compatible job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID or TranscriptionJobName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "TranscriptionJob: AWSTranscriptionJob",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a job, by its ID or TranscriptionJobName (the newest job of that name), with its files, as AWS Transcribe's DeleteTranscriptionJob does. Jobs in progress cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Delete AWS transcribe compatible job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID or TranscriptionJobName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/bundle": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export the bundles of several recordings as one zip, a directory per recording (see GET /api/v1/transcription/{id}/bundle)",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Export several recording bundles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated job IDs (at most 100)",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/custody/verify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check that a JSON custody manifest was signed by this instance and has not been altered since",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Verify chain-of-custody manifest",
+                "parameters": [
+                    {
+                        "description": "Signed manifest",
+                        "name": "manifest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/export.CustodyManifest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CustodyVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/list": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List transcription jobs, filtered, sorted and a page at a time. Pages are taken by page number, or for large lists by cursor: pass the next_cursor of a page, with the same filters and sort, to get the page after it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "List all transcription records",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, ignored with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page, at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC 3339 time, or YYYY-MM-DD in UTC)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC 3339 time, or YYYY-MM-DD in UTC)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile the job was queued with",
+                        "name": "profile_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Model family the job was queued with, e.g. whisper or runpod-whisperx",
+                        "name": "adapter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag key, or key=value",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID, or none for the jobs filed in no collection",
+                        "name": "collection_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum audio length in seconds; only completed jobs have one",
+                        "name": "min_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum audio length in seconds",
+                        "name": "max_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in title, audio filename and tags",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "created_at, updated_at, title, status, profile, adapter, language or duration",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "asc or desc",
+                        "name": "sort_order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/models": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of supported WhisperX models",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get supported models",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/multitrack": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a multi-track job from named tracks, such as \"host\" and \"guest\", and queue it. Each track is transcribed on its own, with the job's parameters and the track's overrides, and the transcripts are merged by timestamp with the track name as the speaker. Send multipart/form-data with the request as JSON in the manifest field and each track's audio in the field its file names, or JSON whose tracks all give a URL (e.g. presigned S3 URLs) to download the audio from; jobs with URL tracks stay \"downloading\" until all are fetched.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Create a multi-track job",
+                "parameters": [
+                    {
+                        "description": "Multi-track request (JSON requests)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.MultiTrackRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Multi-track request as JSON (multipart requests)",
+                        "name": "manifest",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/quick": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submit an audio file for temporary transcription (data discarded after 6 hours). With fast=true, clips of up to a minute are transcribed before the response by a small local model, without diarization or preprocessing, for voice commands and memos; the response is the completed job.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Submit quick transcription job",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Audio file",
+                        "name": "audio",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON string of transcription parameters",
+                        "name": "parameters",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile name to use for transcription",
+                        "name": "profile_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Transcribe a short clip synchronously",
+                        "name": "fast",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transcription.QuickTranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/quick/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current status of a quick transcription job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get quick transcription status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transcription.QuickTranscriptionJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/review-queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the completed jobs with enough low-confidence segments to need review, oldest first, without their transcripts. Segments are flagged when the mean score of their words is below REVIEW_CONFIDENCE_THRESHOLD, and jobs need review from REVIEW_MIN_FLAGGED_SEGMENTS flagged segments.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "List jobs that need review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Jobs per page (default and at most 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jobs to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TranscriptionJob"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/submit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submit an audio file for transcription with WhisperX",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Submit a transcription job",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Audio file",
+                        "name": "audio",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Enable speaker diarization",
+                        "name": "diarization",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "base",
+                        "description": "Whisper model",
+                        "name": "model",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fine-tuned CTranslate2 Whisper checkpoint (absolute path or Hugging Face repo ID), used instead of model",
+                        "name": "custom_model",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code",
+                        "name": "language",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tag each segment with its spoken language (code-switching audio)",
+                        "name": "segment_language_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Batch size",
+                        "name": "batch_size",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "float16",
+                        "description": "Compute type",
+                        "name": "compute_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "auto",
+                        "description": "Device",
+                        "name": "device",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Enable VAD filter",
+                        "name": "vad_filter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.5,
+                        "description": "VAD onset",
+                        "name": "vad_onset",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.363,
+                        "description": "VAD offset",
+                        "name": "vad_offset",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum speakers for diarization",
+                        "name": "min_speakers",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum speakers for diarization",
+                        "name": "max_speakers",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Record speaker voice embeddings so speakers can be enrolled as voiceprints",
+                        "name": "speaker_embeddings",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Estimate each diarized speaker's gender and age range (opt-in; workspaces can disable it)",
+                        "name": "speaker_attributes",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom vocabulary whose phrases the model is prompted with and whose misspellings are corrected",
+                        "name": "vocabulary_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Vocabulary filter whose words are removed, masked or tagged in the transcript",
+                        "name": "vocabulary_filter_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "remove",
+                        "description": "remove, mask or tag",
+                        "name": "vocabulary_filter_method",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "When the recording started (RFC 3339); read from the file's metadata if omitted",
+                        "name": "recorded_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the parameters, resolve the adapters, probe the audio and estimate duration and cost; nothing is saved or queued and a DryRunResponse is returned",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/upload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload an audio file. Transcription starts right away when a profile or parameter overrides are given, or with the user's default profile when auto-transcription is on.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Upload audio file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Audio file",
+                        "name": "audio",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "When the recording started (RFC 3339); read from the file's metadata if omitted",
+                        "name": "recorded_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile to queue the job with right away",
+                        "name": "profile_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object of parameters replacing the profile's, e.g. {\\",
+                        "name": "parameter_overrides",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/upload-multitrack": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload multiple audio files for multi-track transcription, named after their files, to be started with is_multi_track_enabled. Superseded by POST /api/v1/transcription/multitrack, which names the tracks, takes per-track parameters and queues the job.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Upload multi-track audio files",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Audio track files",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/upload-video": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload an MP4, MKV, MOV or WebM video, extract its first audio track using ffmpeg, and create a transcription job. The video is kept, unless keep_video is false, so it can be downloaded with burned-in or sidecar subtitles once transcribed.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Upload video file for transcription",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Video file",
+                        "name": "video",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "When the recording started (RFC 3339); read from the file's metadata if omitted",
+                        "name": "recorded_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Keep the video for subtitled downloads",
+                        "name": "keep_video",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/url": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download audio from a YouTube, podcast or other media page URL with yt-dlp and queue it for transcription. The job stays in the \"downloading\" status while ingest_progress is reported, then moves to \"pending\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Submit transcription job from URL",
+                "parameters": [
+                    {
+                        "description": "URL job request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.URLJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/youtube": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download audio from a YouTube video URL and prepare it for transcription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Download audio from YouTube URL",
+                "parameters": [
+                    {
+                        "description": "YouTube download request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.YouTubeDownloadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get details of a specific transcription job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get transcription job details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a transcription job and its associated files\nDelete a transcription job and its associated files",
+                "produces": [
+                    "application/json",
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription",
+                    "transcription"
+                ],
+                "summary": "Delete transcription job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/annotations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the comment threads anchored to a transcript, in transcript order, each with its replies. Filter by resolved state, or by threads mentioning a user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "List annotations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only resolved (true) or open (false) threads",
+                        "name": "resolved",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only threads mentioning this username",
+                        "name": "mention",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.AnnotationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Comment on a segment (segment_index) or a time range (start_time, end_time) of a transcript, or reply to a thread (parent_id). @username mentions of existing users are recorded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Create an annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Annotation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.AnnotationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/annotations/{annotation_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edit the content of your own comment, or resolve or reopen a thread",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Update an annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Annotation ID",
+                        "name": "annotation_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AnnotationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete your own comment, or any comment as an admin; deleting a thread also deletes its replies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Delete an annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Annotation ID",
+                        "name": "annotation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/audio": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Serve the audio file for a transcription job. Range requests are supported, so players can seek without downloading the whole file. With format=opus or format=mp3 audio in other formats is transcoded as it is streamed, for browsers that cannot play the original; transcoded streams do not support Range requests. Audio stored in S3 is redirected to a presigned URL when AUDIO_S3_REDIRECT is set or redirect=true is given, rather than passing through the server.",
+                "produces": [
+                    "audio/mpeg",
+                    "audio/wav",
+                    "audio/mp4",
+                    "audio/ogg"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get audio file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transcode to this format (opus, mp3)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Redirect S3-stored audio to a presigned URL (default AUDIO_S3_REDIRECT)",
+                        "name": "redirect",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "302": {
+                        "description": "Presigned S3 URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/audio-url": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return a short-lived URL for the job's audio that needs no Authorization header, for media elements and share links. Local files are served through /api/v1/media/{id}/audio with an HMAC signature, relative to the server unless PUBLIC_BASE_URL is set; audio stored in S3 gets a presigned S3 URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get a signed audio playback URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lifetime in seconds (default PLAYBACK_URL_TTL_SECONDS, at most 1 hour)",
+                        "name": "ttl",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PlaybackURLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/bundle": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export a recording's full context as a zip: its speaker names, summaries, notes and chat conversations as context.json and context.md, and for completed jobs the transcript as transcript.json and transcript.srt",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Export a recording bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a queued or running job. Local processes are killed and calls to remote adapters (RunPod, Modal) are cancelled through their APIs, so they stop billing; the worker is then free for the next job. The job is marked cancelled, and can be started again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Cancel transcription job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/chapters": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Split the transcript into topical chapters with titles and start/end times using the active LLM, replacing any earlier chapters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Generate chapters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.GenerateChaptersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.Chapter"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/custody": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a signed manifest of a job for evidentiary use: the SHA-256 of its audio now and when it was processed, the hash of the transcript, every processing run with model and adapter versions, and the audit trail of who finalized or changed it. JSON manifests can be checked with the verify endpoint; the PDF report embeds the signed JSON. Each export is itself recorded in the audit log.",
+                "produces": [
+                    "application/json",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get chain-of-custody manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "json or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/export.CustodyManifest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compute a word-level diff between two transcripts of a job, with the word and character error rates (WER, CER) of against measured with base as the reference, for model evaluation. A transcript is the job's current one (current, the default base), one of its revisions (revision:N, or N), the output of a candidate adapter in a shadow run of the job (shadow:ID, admins only) or the transcript of another job, such as the same audio transcribed with another profile (job:ID). Words are compared ignoring case and surrounding punctuation; chunks give them as written.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Diff two transcripts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transcript to compare",
+                        "name": "against",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reference transcript (default current)",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.TranscriptDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/execution": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get execution parameters and timing for a transcription job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get transcription job execution data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJobExecution"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the transcript as SubRip (srt), WebVTT (vtt), JSON (json), a WebVTT chapter track (chapters), synced lyrics with timed lines (lrc) or timed words (elrc, from word-level timestamps), study text interleaving each timed segment with its translation (bilingual, once the transcript has been translated), or a spreadsheet of segments (csv). Speakers use their custom names, and chapters, when generated, are included: as bracketed titles in SRT, NOTE blocks in VTT and a chapters array in JSON. JSON also includes the translation.\nWhen the recording's start time is known, CSV adds absolute start and end times and JSON its start time. Times are shown in the requested time zone, and CSV dates, decimal separators and field separators follow the requested locale; both default to the user's settings, then to UTC and ISO.\nWebVTT exports open with a NOTE block naming the job, title, model and language, give speakers as \u003cv\u003e voice spans so players can style each voice, and carry the requested cue settings on every cue.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Export transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "srt",
+                        "description": "Export format (srt, vtt, json, chapters, lrc, elrc, bilingual, csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone, e.g. Europe/Berlin",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale, e.g. en-US or de-DE",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "WebVTT cue line: a line number (negative counts from the bottom) or a percentage",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "WebVTT cue position as a percentage, e.g. 50%",
+                        "name": "position",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "WebVTT cue width as a percentage",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "WebVTT cue text alignment (start, center, end, left, right)",
+                        "name": "align",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/finalize": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lock a completed transcript as a record. Once finalized, it cannot be edited or reprocessed, by hand or by automation, except by admins; finalizing and every later admin change are recorded in the audit log.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Finalize transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.FinalizeTranscriptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unlock a finalized transcript so it can be edited and reprocessed again. Admins only; recorded in the audit log.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Unfinalize transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.FinalizeTranscriptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/flashcards": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download a flashcard deck as CSV or an Anki package (apkg). With source=notes (the default) each note becomes a card with the highlighted quote on the front and the note on the back; notes without text are skipped. With source=qa the active LLM extracts comprehension questions, or, when language is set, vocabulary cards translating phrases from the transcript into that language.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Export flashcards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Deck format (csv, apkg)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "notes",
+                        "description": "Card source (notes, qa)",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of LLM-extracted cards (1-100)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Translate vocabulary cards into this language (qa only)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "LLM model (qa only; defaults to the default summary model)",
+                        "name": "model",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/kill": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a currently running transcription job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Kill running transcription job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/merge-status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current merge status for a multi-track job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get multi-track merge status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/notes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all notes attached to a transcription, ordered by time and creation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "List notes for a transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Note"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new note attached to the specified transcription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Create a note for a transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note create payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.NoteCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Note"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/realign": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Regenerate the segment and word timestamps of a transcript from its text by forced alignment against the job's audio, e.g. after heavy editing left word timings out of step with the text. Segments may be given to replace the stored ones; each is aligned within its own time window, which should still roughly cover its speech. Segments that cannot be aligned keep their times. Needs the local WhisperX environment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Repair transcript timestamps",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Edited segments",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.RealignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transcription.RealignResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/recorded-at": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the wall-clock time the recording started, so exports and the transcript API can show absolute times alongside offsets. Overrides a time read from the file's metadata; null clears it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Set recording start time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Start time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateRecordedAtRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-run an execution of a job with the parameters, adapters and audio recorded in its snapshot, in a debug sandbox that leaves the job untouched. The replay is recorded as an execution of its own; the response reports whether the audio and adapter environments still match the original, what changed, and whether the output is identical to the job's transcript.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Replay job execution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReplayJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transcription.ReplayResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/review": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the segments of a transcript flagged for their low confidence, with their words' scores and a signed URL playing each segment (a media fragment, #t=start,end, of the job's audio) for quick correction. Audio URLs are left out when the job's audio is unavailable or hidden from the requester.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "List low-confidence segments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a job out of the review queue, whether or not flagged segments are left.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Mark a transcript reviewed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/review/segments/{index}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Correct the text of a segment flagged for low confidence, or confirm it by sending no text, which clears its flag. The transcript's full text is corrected along with it; its word timings are kept, and can be regenerated with the realign endpoint. Once no flagged segment is left, a job that needed review is marked reviewed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Review a low-confidence segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Segment index",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Corrected text",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReviewSegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.TranscriptSegment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the transcripts a job has had, oldest first, without their content. Every transcript saved for the job, by transcription, re-transcription, correction rules or edits, is kept as a revision. The model that produced each is given when the transcript records it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "List transcript revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TranscriptRevision"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/sentiment": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Label every transcript segment with its sentiment (positive, neutral or negative, with a score from -1 to 1) and dominant emotion using the active LLM, replacing any earlier analysis. The result is returned with the transcript.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Analyze segment sentiment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.AnalyzeSentimentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.SegmentSentiment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/speakers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves all custom speaker names for a transcription job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get speaker mappings for a transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.SpeakerMappingResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates or creates custom speaker names for a transcription job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Update speaker mappings for a transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Speaker mappings to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SpeakerMappingsUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.SpeakerMappingResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/speakers/attributes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the coarse gender and age range estimated from each diarized speaker's voice. Estimation is opt-in: it runs only for diarized jobs whose parameters set speaker_attributes, in workspaces that have not disabled it. Speakers with under 3 seconds of speech are left out, and a gender below 60% confidence is reported as unknown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get speaker attributes of a transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SpeakerAttribute"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/speakers/identify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Match the job's diarized speakers against the workspace's voiceprints again, for example after enrolling new voices. Matched speakers are named after their voiceprint unless they were named by hand.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Identify speakers by voiceprint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.VoiceprintMatchResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/start": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start transcription for an already uploaded audio file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Start transcription for uploaded file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transcription parameters",
+                        "name": "parameters",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WhisperXParams"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the parameters, resolve the adapters, check the audio and estimate duration and cost; the job is not changed or queued and a DryRunResponse is returned",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current status of a transcription job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the most recent saved summary for the given transcription",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summarize"
+                ],
+                "summary": "Get latest summary for transcription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transcription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Summary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List a job's tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign a tag to a job, by tag_id, or by name (key or key=value), adding the tag to the workspace if it has none by that name. The job's tags field, S3 object tags and AWS Transcribe responses follow its assigned tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Tag a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TagAssignmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/tags/{tag_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Untag a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/title": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the title of an audio file / transcription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Update transcription title",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Title update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/track-progress": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get real-time progress information for individual tracks in a multi-track job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get multi-track job progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/transcript": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the transcript for a completed transcription job, with chapters and per-segment sentiment once they have been generated, and the seconds of speech per language when segments are tagged with their language. When the recording's start time is known, the wall-clock start and end of each segment are included as segment_times.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of segment_times; defaults to the user's setting, then UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/translation": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Translate the transcript segment by segment into another language using the active LLM, replacing any earlier translation. The translation is included in JSON exports and interleaved with the original in bilingual exports.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Translate transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TranslateTranscriptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.Translation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transcription/{id}/video": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the video a job's audio was extracted from. With subtitles=burn the transcript, with speaker names, is drawn onto the frames and the video re-encoded as MP4, which takes a while for long videos. With subtitles=sidecar a zip holds the original video with SRT and WebVTT files named after it, which players load alongside. Subtitles need the job to be transcribed.",
+                "produces": [
+                    "video/mp4",
+                    "application/zip"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Download a job's video",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitles to add (burn, sidecar)",
+                        "name": "subtitles",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/default-profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the default transcription profile for the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Get user's default profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranscriptionProfile"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the default transcription profile for the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Set user's default profile",
+                "parameters": [
+                    {
+                        "description": "Default profile request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SetUserDefaultProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the organizations of the current user, oldest membership first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List my organizations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Organization"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/settings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's settings including auto-transcription preference, the time zone and locale used in exports and email notification preferences",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserSettingsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the current user's settings. Jobs that finish are emailed to notification_email when SMTP is configured, according to notify_on_completed and notify_on_failed; notify_attach_transcript attaches the transcript as text.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update user settings",
+                "parameters": [
+                    {
+                        "description": "Settings update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateUserSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/takeout": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export everything stored about the current user as a zip: the account and its settings as account.json, and the bundle of every recording in the user's own workspace under recordings/ (see GET /api/v1/transcription/{id}/bundle)",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Download my data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the accounts and their roles",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an account. Admins manage users and API keys, editors create and edit jobs, and viewers have read-only access.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "Account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change an account's role or reset its password. The last admin cannot be demoted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an account and sign it out. Admins cannot delete themselves or the last admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/vocabularies": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the custom vocabularies of the request's organization by name, as AWS Transcribe's ListVocabularies does",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "List custom vocabularies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSListVocabulariesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a custom vocabulary, as AWS Transcribe's CreateVocabulary does. Jobs naming it in Settings.VocabularyName, or profiles in vocabulary_name, are prompted with its phrases so the model favours their spelling, and have the misspellings in Corrections replaced in their transcript.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Create a custom vocabulary",
+                "parameters": [
+                    {
+                        "description": "Vocabulary",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabulary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/vocabularies/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a custom vocabulary with its phrases and corrections, as AWS Transcribe's GetVocabulary does",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Get a custom vocabulary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "VocabularyName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabulary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the phrases and corrections of a custom vocabulary, as AWS Transcribe's UpdateVocabulary does. Queued jobs naming it use the new ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Update a custom vocabulary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "VocabularyName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vocabulary",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabulary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a custom vocabulary; queued jobs naming it are transcribed without it",
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Delete a custom vocabulary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "VocabularyName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/vocabulary-filters": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the vocabulary filters of the request's organization by name, as AWS Transcribe's ListVocabularyFilters does",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "List vocabulary filters",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSListVocabularyFiltersResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a vocabulary filter, as AWS Transcribe's CreateVocabularyFilter does. Jobs naming it in Settings.VocabularyFilterName have its words removed, masked with *** or tagged in their transcript, after Settings.VocabularyFilterMethod.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Create a vocabulary filter",
+                "parameters": [
+                    {
+                        "description": "Vocabulary filter",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyFilterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyFilter"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/vocabulary-filters/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a vocabulary filter with its words, as AWS Transcribe's GetVocabularyFilter does",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Get a vocabulary filter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "VocabularyFilterName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyFilter"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the words of a vocabulary filter, as AWS Transcribe's UpdateVocabularyFilter does",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Update a vocabulary filter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "VocabularyFilterName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vocabulary filter",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyFilterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AWSVocabularyFilter"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a vocabulary filter; queued jobs naming it are left unfiltered",
+                "tags": [
+                    "vocabularies"
+                ],
+                "summary": "Delete a vocabulary filter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "VocabularyFilterName",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/voiceprints": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the voiceprints enrolled in the caller's workspace",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voiceprints"
+                ],
+                "summary": "List voiceprints",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Voiceprint"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enroll a diarized speaker of a completed job as a named voiceprint. Speakers in later diarized jobs whose voice matches are named automatically. The job must have been diarized with speaker embeddings, which happens automatically once the workspace has a voiceprint or when speaker_embeddings is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voiceprints"
+                ],
+                "summary": "Enroll a voiceprint",
+                "parameters": [
+                    {
+                        "description": "Speaker to enroll",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EnrollVoiceprintRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Voiceprint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/voiceprints/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a voiceprint. Speakers already named after it keep their names.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voiceprints"
+                ],
+                "summary": "Delete a voiceprint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voiceprint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workspace/field-permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the job fields hidden from editors and viewers in the request's workspace, or for admins another with the workspace query parameter. Roles without an entry see every field.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspace"
+                ],
+                "summary": "List field permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace to get the permissions of (admins only)",
+                        "name": "workspace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.FieldPermissionResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workspace/field-permissions/{role}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hide job fields from the editors or viewers of the request's workspace, or of another with the workspace query parameter. Hiding audio stops playback, audio URLs and bundles and leaves the audio's paths and source URL out of jobs; hiding transcript leaves out the transcript and what is derived from its text, and stops transcript reads, exports, flashcards, bundles, sentiment, chapters, translation and search hits in it; hiding summary leaves out summaries and action items and their search hits. Admins always see every field. An empty list shows every field again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspace"
+                ],
+                "summary": "Set field permissions of a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role (editor or viewer)",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace to set the permissions of",
+                        "name": "workspace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Hidden fields: audio, transcript and/or summary",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FieldPermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.FieldPermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workspace/settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the privacy settings of the request's workspace; admins can read another workspace's",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspace"
+                ],
+                "summary": "Get workspace settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace to get the settings of (admins only)",
+                        "name": "workspace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkspaceSettings"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the privacy settings of the request's workspace, or of another with the workspace query parameter. Disabling speaker attributes stops their estimation in the workspace's jobs, whatever the jobs' parameters request, and deletes those already estimated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspace"
+                ],
+                "summary": "Update workspace settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace to set the settings of",
+                        "name": "workspace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Workspace settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkspaceSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkspaceSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/versions": {
+            "get": {
+                "description": "List the versions of the API with whether each is current, and when deprecated versions stop being served. v2 serves the same routes as v1 with structured error bodies: {\"error\": {\"code\": \"not_found\", \"message\": \"...\"}}. Responses name the version that served them in the API-Version header, and deprecated versions send Deprecation, Sunset and successor-version Link headers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "List API versions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.APIVersionInfo"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API is healthy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transcription/{id}/logs": {
+            "get": {
+                "description": "Get the raw transcription logs for a job",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "transcription"
+                ],
+                "summary": "Get transcription logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIKeyListResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "key_preview": {
+                    "type": "string"
+                },
+                "last_used": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "the organization the key is confined to, if any",
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.APIKeysWrapper": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.APIKeyListResponse"
+                    }
+                }
+            }
+        },
+        "api.APIVersionInfo": {
+            "type": "object",
+            "properties": {
+                "deprecated_at": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "current, supported or deprecated",
+                    "type": "string"
+                },
+                "sunset_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AWSListTranscriptionJobsResponse": {
+            "type": "object",
+            "properties": {
+                "NextToken": {
+                    "type": "string"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "TranscriptionJobSummaries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AWSTranscriptionJobSummary"
+                    }
+                }
+            }
+        },
+        "api.AWSListVocabulariesResponse": {
+            "type": "object",
+            "properties": {
+                "Vocabularies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AWSVocabulary"
+                    }
+                }
+            }
+        },
+        "api.AWSListVocabularyFiltersResponse": {
+            "type": "object",
+            "properties": {
+                "VocabularyFilters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AWSVocabularyFilter"
+                    }
+                }
+            }
+        },
+        "api.AWSTagRouteRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "tag_key"
+            ],
+            "properties": {
+                "monthly_quota_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "output_bucket_name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "profile_id": {
+                    "type": "string"
+                },
+                "tag_key": {
+                    "type": "string"
+                },
+                "tag_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AWSTranscribeJobRequest": {
+            "type": "object"
+        },
+        "api.AWSTranscriptionJobSummary": {
+            "type": "object",
+            "properties": {
+                "CompletionTime": {
+                    "type": "string"
+                },
+                "CreationTime": {
+                    "type": "string"
+                },
+                "FailureReason": {
+                    "type": "string"
+                },
+                "LanguageCode": {
+                    "type": "string"
+                },
+                "OutputLocationType": {
+                    "description": "CUSTOMER_BUCKET or SERVICE_BUCKET",
+                    "type": "string"
+                },
+                "TranscriptionJobID": {
+                    "type": "string"
+                },
+                "TranscriptionJobName": {
+                    "type": "string"
+                },
+                "TranscriptionJobStatus": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AWSVocabulary": {
+            "type": "object",
+            "properties": {
+                "Corrections": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "LanguageCode": {
+                    "type": "string"
+                },
+                "LastModifiedTime": {
+                    "type": "string"
+                },
+                "Phrases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "VocabularyName": {
+                    "type": "string"
+                },
+                "VocabularyState": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AWSVocabularyFilter": {
+            "type": "object",
+            "properties": {
+                "LanguageCode": {
+                    "type": "string"
+                },
+                "LastModifiedTime": {
+                    "type": "string"
+                },
+                "VocabularyFilterName": {
+                    "type": "string"
+                },
+                "Words": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.AWSVocabularyFilterRequest": {
+            "type": "object",
+            "properties": {
+                "LanguageCode": {
+                    "type": "string"
+                },
+                "VocabularyFilterName": {
+                    "type": "string"
+                },
+                "Words": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.AWSVocabularyRequest": {
+            "type": "object",
+            "properties": {
+                "Corrections": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "LanguageCode": {
+                    "type": "string"
+                },
+                "Phrases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "VocabularyName": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AdapterConfigRequest": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "description": "APIKey is only returned masked; modal-whisperx takes\n\"token_id:token_secret\". A reference to a secret store, such as\nvault://secret/data/runpod#api_key, is fetched and refreshed instead.",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "endpoint": {
+                    "description": "Endpoint is a WhisperX server URL for whisperx, a RunPod endpoint ID or\nURL for runpod-whisperx and an app name for modal-whisperx",
+                    "type": "string"
+                }
+            }
+        },
+        "api.AddOrganizationMemberRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.AnalyzeSentimentRequest": {
+            "type": "object",
+            "properties": {
+                "model": {
+                    "description": "defaults to the default summary model",
+                    "type": "string"
+                }
+            }
+        },
+        "api.AnnotationResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author is the username, or the API key name, of whoever wrote the comment",
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AnnotationResponse"
+                    }
+                },
+                "resolved": {
+                    "type": "boolean"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "string"
+                },
+                "segment_index": {
+                    "description": "Anchor: the segment commented on, if any, and the time range it covers (in seconds)",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "number"
+                },
+                "transcription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ApplyCorrectionRulesRequest": {
+            "type": "object",
+            "properties": {
+                "job_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.ApplyCorrectionRulesResponse": {
+            "type": "object",
+            "properties": {
+                "corrected": {
+                    "description": "job ID to replacements",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "skipped": {
+                    "description": "job ID to reason",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.AuditEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEvent"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.BenchmarkModelSummary": {
+            "type": "object",
+            "properties": {
+                "cer": {
+                    "type": "number"
+                },
+                "completed": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "description": "Cost totals the completed runs, when the adapter is priced",
+                    "type": "number"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mean_processing_duration": {
+                    "description": "MeanProcessingDuration averages the duration of the completed runs, in milliseconds",
+                    "type": "integer"
+                },
+                "model_id": {
+                    "type": "string"
+                },
+                "real_time_factor": {
+                    "description": "RealTimeFactor is the processing time of the completed runs over the\nduration of their audio; below 1 is faster than real time",
+                    "type": "number"
+                },
+                "runs": {
+                    "type": "integer"
+                },
+                "wer": {
+                    "description": "WER and CER are the word and character errors of the completed runs over\nthe words and characters of their references",
+                    "type": "number"
+                }
+            }
+        },
+        "api.BenchmarkReport": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency of the costs, when adapters are priced",
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model_ids": {
+                    "description": "ModelIDs are the comma-separated transcription adapters compared",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "description": "Parameters every adapter is called with, converted for each; diarization is skipped",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WhisperXParams"
+                        }
+                    ]
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BenchmarkRun"
+                    }
+                },
+                "samples": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BenchmarkSample"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "summary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.BenchmarkModelSummary"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "confirmPassword",
+                "currentPassword",
+                "newPassword"
+            ],
+            "properties": {
+                "confirmPassword": {
+                    "type": "string"
+                },
+                "currentPassword": {
+                    "type": "string"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "api.ChangeUsernameRequest": {
+            "type": "object",
+            "required": [
+                "newUsername",
+                "password"
+            ],
+            "properties": {
+                "newUsername": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChatCreateRequest": {
+            "type": "object",
+            "required": [
+                "model",
+                "transcription_id"
+            ],
+            "properties": {
+                "model": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transcription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChatMessageRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChatMessageResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChatModelsResponse": {
+            "type": "object",
+            "properties": {
+                "models": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.ChatPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "banned_topics": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_context_tokens": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_history_messages": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "system_prompt": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "api.ChatPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "banned_topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_context_tokens": {
+                    "description": "MaxContextTokens caps the estimated tokens sent per request, below the\nmodel's own window (0: the model's window)",
+                    "type": "integer"
+                },
+                "max_history_messages": {
+                    "description": "MaxHistoryMessages limits a request to the most recent messages of the\nconversation (0: all)",
+                    "type": "integer"
+                },
+                "system_prompt": {
+                    "description": "prepended to the built-in instructions",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChatSessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_activity_at": {
+                    "type": "string"
+                },
+                "last_message": {
+                    "$ref": "#/definitions/api.ChatMessageResponse"
+                },
+                "message_count": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transcription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChatSessionWithMessages": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_activity_at": {
+                    "type": "string"
+                },
+                "last_message": {
+                    "$ref": "#/definitions/api.ChatMessageResponse"
+                },
+                "message_count": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ChatMessageResponse"
+                    }
+                },
+                "model": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transcription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CloneProfileRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name defaults to the profile's name followed by \"(copy)\"",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version defaults to the profile's current version",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CollectionJobsRequest": {
+            "type": "object",
+            "required": [
+                "job_ids"
+            ],
+            "properties": {
+                "job_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.CollectionMemberRequest": {
+            "type": "object",
+            "required": [
+                "role",
+                "user_id"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CollectionPermissionsRequest": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.CollectionMemberRequest"
+                    }
+                },
+                "restricted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.CollectionPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionMember"
+                    }
+                },
+                "restricted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.CollectionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CollectionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "restricted": {
+                    "description": "Restricted collections, and the collections and jobs inside them, are\nonly accessible to admins and their members",
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CorrectionPreviewRequest": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "rule": {
+                    "$ref": "#/definitions/api.CorrectionRuleRequest"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CorrectionPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "replacements": {
+                    "type": "integer"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.CorrectionPreviewSegment"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CorrectionPreviewSegment": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "number"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.CorrectionRuleRequest": {
+            "type": "object",
+            "required": [
+                "find"
+            ],
+            "properties": {
+                "case_sensitive": {
+                    "type": "boolean"
+                },
+                "enabled": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "find": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "regex": {
+                    "type": "boolean"
+                },
+                "replace": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CostReportResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "first day, inclusive",
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CostSummary"
+                    }
+                },
+                "to": {
+                    "description": "last day, inclusive",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "organization_id": {
+                    "description": "OrganizationID confines the key to an organization; defaults to the\norganization the request acts in",
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "requests per minute; 0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "role": {
+                    "description": "admin, editor or viewer; defaults to editor",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "submit, read and/or admin; defaults to every scope",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateAnnotationRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "defaults to start_time",
+                    "type": "number"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "segment_index": {
+                    "description": "defaults the time range to the segment's",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.CreateOrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "api.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "role",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "role": {
+                    "description": "admin, editor or viewer",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "api.CustodyPublicKey": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "pem": {
+                    "description": "PKIX, for tools such as openssl",
+                    "type": "string"
+                },
+                "public_key": {
+                    "description": "base64 of the raw 32-byte key",
+                    "type": "string"
+                }
+            }
+        },
+        "api.CustodyVerification": {
+            "type": "object",
+            "properties": {
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.DiagnosticsBundle": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "environments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/registry.EnvironmentStatus"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "models": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "queue": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "recent_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DiagnosticsError"
+                    }
+                },
+                "system": {
+                    "$ref": "#/definitions/api.DiagnosticsSystem"
+                },
+                "versions": {
+                    "$ref": "#/definitions/api.DiagnosticsVersions"
+                }
+            }
+        },
+        "api.DiagnosticsError": {
+            "type": "object",
+            "properties": {
+                "diarize": {
+                    "type": "boolean"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "model_family": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DiagnosticsSystem": {
+            "type": "object",
+            "properties": {
+                "arch": {
+                    "type": "string"
+                },
+                "cpus": {
+                    "type": "integer"
+                },
+                "goroutines": {
+                    "type": "integer"
+                },
+                "heap_mb": {
+                    "type": "integer"
+                },
+                "os": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DiagnosticsVersions": {
+            "type": "object",
+            "properties": {
+                "built": {
+                    "type": "string"
+                },
+                "commit": {
+                    "type": "string"
+                },
+                "go": {
+                    "type": "string"
+                },
+                "scriberr": {
+                    "type": "string"
+                },
+                "tools": {
+                    "description": "first line of each tool's version output, or the error",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.EnrollVoiceprintRequest": {
+            "type": "object",
+            "required": [
+                "job_id",
+                "name",
+                "speaker"
+            ],
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "speaker": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.FeedSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "backfill_count": {
+                    "type": "integer"
+                },
+                "poll_interval_minutes": {
+                    "type": "integer"
+                },
+                "profile_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.FieldPermissionRequest": {
+            "type": "object",
+            "properties": {
+                "hidden_fields": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.FieldPermissionResponse": {
+            "type": "object",
+            "properties": {
+                "hidden_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.FinalizeRecordingRequest": {
+            "type": "object",
+            "properties": {
+                "profile_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.FinalizeTranscriptRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "api.GenerateChaptersRequest": {
+            "type": "object",
+            "properties": {
+                "model": {
+                    "description": "defaults to the default summary model",
+                    "type": "string"
+                }
+            }
+        },
+        "api.LLMConfigRequest": {
+            "type": "object",
+            "required": [
+                "provider"
+            ],
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "base_url": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "openai_base_url": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "enum": [
+                        "ollama",
+                        "openai"
+                    ]
+                }
+            }
+        },
+        "api.LLMConfigResponse": {
+            "type": "object",
+            "properties": {
+                "base_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "has_api_key": {
+                    "description": "Don't return actual API key",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "openai_base_url": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LibraryImportFilesResponse": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LibraryImportFile"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.LibraryImportRequest": {
+            "type": "object",
+            "required": [
+                "source"
+            ],
+            "properties": {
+                "dedup": {
+                    "description": "Dedup skips files already imported into the workspace (default true)",
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "profile_id": {
+                    "type": "string"
+                },
+                "rate_per_minute": {
+                    "description": "RatePerMinute caps how many jobs are queued a minute (default LIBRARY_IMPORT_RATE_PER_MINUTE)",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "source": {
+                    "description": "Source is an s3://bucket/prefix or a directory under LIBRARY_IMPORT_DIR",
+                    "type": "string"
+                }
+            }
+        },
+        "api.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "type": "integer"
+                        },
+                        "role": {
+                            "type": "string"
+                        },
+                        "username": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "api.MultiTrackRequest": {
+            "type": "object",
+            "properties": {
+                "parameters": {
+                    "description": "Parameters apply to every track; the default profile's are used without them",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WhisperXParams"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TrackRequest"
+                    }
+                }
+            }
+        },
+        "api.NoteCreateRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "quote"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "end_time": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "end_word_index": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "quote": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "start_time": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "start_word_index": {
+                    "description": "Use gte=0 so 0 is valid (first word/time); avoid 'required' which fails for zero values",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "api.NoteUpdateRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "api.NotificationTemplatePreviewRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "content_type": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "job_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.NotificationTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "content_type": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "api.PlaybackURLResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.PrefetchAlignmentModelsRequest": {
+            "type": "object",
+            "required": [
+                "languages"
+            ],
+            "properties": {
+                "languages": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.QuotaResponse": {
+            "type": "object",
+            "properties": {
+                "limit_minutes": {
+                    "description": "0: unlimited",
+                    "type": "integer"
+                },
+                "month": {
+                    "description": "YYYY-MM in UTC",
+                    "type": "string"
+                },
+                "resets_at": {
+                    "type": "string"
+                },
+                "used_minutes": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.RealignRequest": {
+            "type": "object",
+            "properties": {
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.RealignSegment"
+                    }
+                }
+            }
+        },
+        "api.RealignSegment": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "number"
+                },
+                "speaker": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RefreshTokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "confirmPassword",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "confirmPassword": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "api.RegistrationStatusResponse": {
+            "type": "object",
+            "properties": {
+                "registration_enabled": {
+                    "description": "Match tests expecting snake_case key",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.ReplayJobRequest": {
+            "type": "object",
+            "properties": {
+                "execution_id": {
+                    "description": "defaults to the latest execution",
+                    "type": "string"
+                },
+                "keep_sandbox": {
+                    "description": "keep the sandbox directory for inspection",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.ReportScheduleRequest": {
+            "type": "object",
+            "required": [
+                "cron",
+                "name"
+            ],
+            "properties": {
+                "cron": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "email_to": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "s3_uri": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReportScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron is a five-field cron expression, or @daily, @weekly and the like,\nevaluated in Timezone",
+                    "type": "string"
+                },
+                "email_to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "description": "LastRunAt ends the period the previous report covered, where the next begins",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "s3_uri": {
+                    "description": "S3URI is the s3://bucket/prefix reports are written under",
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReprocessRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "job_ids": {
+                    "description": "JobIDs limits reprocessing to these of the affected jobs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.ReprocessResponse": {
+            "type": "object",
+            "properties": {
+                "queued": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "skipped": {
+                    "description": "job ID to reason",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.RestoreBackupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RetentionPolicyRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "after_days",
+                "target"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "delete",
+                        "archive"
+                    ]
+                },
+                "after_days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "archive_uri": {
+                    "type": "string"
+                },
+                "enforced": {
+                    "type": "boolean"
+                },
+                "profile_id": {
+                    "type": "string"
+                },
+                "storage_class": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string",
+                    "enum": [
+                        "audio",
+                        "job"
+                    ]
+                }
+            }
+        },
+        "api.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "audio_url_expires_at": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "low_confidence_segments": {
+                    "type": "integer"
+                },
+                "review_status": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ReviewSegment"
+                    }
+                }
+            }
+        },
+        "api.ReviewSegment": {
+            "type": "object",
+            "properties": {
+                "audio_url": {
+                    "description": "AudioURL plays the segment, with a little audio around it, without\ncredentials until AudioURLExpiresAt; absent when the audio is not available",
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "end": {
+                    "type": "number"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "speaker": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "words": {
+                    "description": "Words are the segment's words with their scores",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.TranscriptWord"
+                    }
+                }
+            }
+        },
+        "api.ReviewSegmentRequest": {
+            "type": "object",
+            "properties": {
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SetUserDefaultProfileRequest": {
+            "type": "object",
+            "required": [
+                "profile_id"
+            ],
+            "properties": {
+                "profile_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ShadowModelSummary": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mean_processing_duration": {
+                    "description": "MeanProcessingDuration averages the duration of the completed runs, in milliseconds",
+                    "type": "integer"
+                },
+                "mean_word_agreement": {
+                    "description": "MeanWordAgreement averages the word agreement of the completed runs",
+                    "type": "number"
+                },
+                "model_id": {
+                    "type": "string"
+                },
+                "runs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.ShadowRunsResponse": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShadowRun"
+                    }
+                },
+                "summary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ShadowModelSummary"
+                    }
+                }
+            }
+        },
+        "api.SpeakerMappingRequest": {
+            "type": "object",
+            "required": [
+                "original_speaker"
+            ],
+            "properties": {
+                "custom_name": {
+                    "type": "string"
+                },
+                "original_speaker": {
+                    "type": "string"
+                },
+                "speaker_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SpeakerMappingResponse": {
+            "type": "object",
+            "properties": {
+                "custom_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "original_speaker": {
+                    "type": "string"
+                },
+                "speaker_id": {
+                    "type": "string"
+                },
+                "voiceprint_id": {
+                    "description": "VoiceprintID is set when the name was assigned by voiceprint matching",
+                    "type": "string"
+                }
+            }
+        },
+        "api.SpeakerMappingsUpdateRequest": {
+            "type": "object",
+            "required": [
+                "mappings"
+            ],
+            "properties": {
+                "mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SpeakerMappingRequest"
+                    }
+                }
+            }
+        },
+        "api.SpeakerRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SpeakerResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.SpeakerAppearance"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.StartRecordingRequest": {
+            "type": "object",
+            "required": [
+                "mime_type"
+            ],
+            "properties": {
+                "mime_type": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.StorageCleanupResponse": {
+            "type": "object",
+            "properties": {
+                "quick_transcription": {
+                    "$ref": "#/definitions/transcription.QuickCleanupStats"
+                },
+                "storage": {
+                    "$ref": "#/definitions/service.StorageCleanup"
+                }
+            }
+        },
+        "api.SummarizeRequest": {
+            "type": "object",
+            "required": [
+                "model",
+                "transcription_id"
+            ],
+            "properties": {
+                "content": {
+                    "description": "prompt sent as is when no template is given",
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "transcription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SummarySettingsRequest": {
+            "type": "object",
+            "required": [
+                "default_model"
+            ],
+            "properties": {
+                "default_model": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "api.SummarySettingsResponse": {
+            "type": "object",
+            "properties": {
+                "default_model": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SummaryTemplateRequest": {
+            "type": "object",
+            "required": [
+                "model",
+                "name",
+                "prompt"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "prompt": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "api.TagAssignmentRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.TagRequest": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.TrackRequest": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the speaker the track's words are attributed to",
+                    "type": "string"
+                },
+                "offset": {
+                    "description": "seconds the track starts after the first",
+                    "type": "number"
+                },
+                "parameters": {
+                    "description": "Parameters overrides the job's parameters for this track, e.g. its language",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.TranscriptDiffResponse": {
+            "type": "object",
+            "properties": {
+                "against": {
+                    "type": "string"
+                },
+                "base": {
+                    "type": "string"
+                },
+                "chunks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transcription.DiffChunk"
+                    }
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/transcription.DiffStats"
+                }
+            }
+        },
+        "api.TranslateTranscriptRequest": {
+            "type": "object",
+            "required": [
+                "language"
+            ],
+            "properties": {
+                "language": {
+                    "description": "e.g. \"English\" or \"de\"",
+                    "type": "string"
+                },
+                "model": {
+                    "description": "defaults to the default summary model",
+                    "type": "string"
+                }
+            }
+        },
+        "api.URLJobRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "parameters": {
+                    "$ref": "#/definitions/models.WhisperXParams"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateAnnotationRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.UpdateRecordedAtRequest": {
+            "type": "object",
+            "properties": {
+                "recorded_at": {
+                    "description": "RFC 3339 with an offset; null to clear",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateUserSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "auto_transcription_enabled": {
+                    "type": "boolean"
+                },
+                "locale": {
+                    "description": "e.g. en-US or de-DE; \"\" for ISO",
+                    "type": "string"
+                },
+                "notification_email": {
+                    "description": "\"\" turns email notifications off",
+                    "type": "string"
+                },
+                "notify_attach_transcript": {
+                    "type": "boolean"
+                },
+                "notify_on_completed": {
+                    "type": "boolean"
+                },
+                "notify_on_failed": {
+                    "type": "boolean"
+                },
+                "time_zone": {
+                    "description": "IANA name, e.g. Europe/Berlin; \"\" for UTC",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UsageAnalyticsResponse": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UsageSummary"
+                    }
+                },
+                "from": {
+                    "description": "first day, inclusive",
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "to": {
+                    "description": "last day, inclusive",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UserSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "auto_transcription_enabled": {
+                    "type": "boolean"
+                },
+                "default_profile_id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "notification_email": {
+                    "type": "string"
+                },
+                "notify_attach_transcript": {
+                    "type": "boolean"
+                },
+                "notify_on_completed": {
+                    "type": "boolean"
+                },
+                "notify_on_failed": {
+                    "type": "boolean"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ValidateOpenAIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.VoiceprintMatchResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "speaker": {
+                    "type": "string"
+                },
+                "voiceprint_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.WorkspaceChatMessage": {
+            "type": "object",
+            "required": [
+                "content",
+                "role"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "assistant"
+                    ]
+                }
+            }
+        },
+        "api.WorkspaceChatRequest": {
+            "type": "object",
+            "required": [
+                "messages",
+                "model"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.WorkspaceChatMessage"
+                    }
+                },
+                "model": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.WorkspaceChatResponse": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.RetrievedChunk"
+                    }
+                }
+            }
+        },
+        "api.WorkspaceSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "speaker_attributes_disabled": {
+                    "description": "SpeakerAttributesDisabled stops speaker attribute estimation in the\nworkspace and deletes the attributes already estimated",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.WorkspaceSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "speaker_attributes_deleted": {
+                    "description": "SpeakerAttributesDeleted counts the speaker attributes deleted by disabling them",
+                    "type": "integer"
+                },
+                "speaker_attributes_disabled": {
+                    "description": "SpeakerAttributesDisabled stops speaker attribute estimation in the\nworkspace's jobs, whatever their profiles request",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.YouTubeDownloadRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "backup.Info": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "Location is the s3:// URI the archive was copied to, if any",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "export.CustodyAudio": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error explains why the audio could not be hashed",
+                    "type": "string"
+                },
+                "matches_processed_audio": {
+                    "description": "MatchesProcessedAudio reports whether the audio is the audio the latest\nexecution transcribed; absent when either hash is unknown",
+                    "type": "boolean"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "source_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.CustodyEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.CustodyExecution": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "environment_hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "input_sha256": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "model_family": {
+                    "type": "string"
+                },
+                "replay_of": {
+                    "description": "replays run in a sandbox and leave the job untouched",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.CustodyStep"
+                    }
+                }
+            }
+        },
+        "export.CustodyJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "finalized_at": {
+                    "type": "string"
+                },
+                "finalized_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.CustodyManifest": {
+            "type": "object",
+            "properties": {
+                "audio": {
+                    "$ref": "#/definitions/export.CustodyAudio"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.CustodyEvent"
+                    }
+                },
+                "executions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.CustodyExecution"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "generated_by": {
+                    "type": "string"
+                },
+                "job": {
+                    "$ref": "#/definitions/export.CustodyJob"
+                },
+                "signature": {
+                    "$ref": "#/definitions/export.CustodySignature"
+                },
+                "transcript": {
+                    "$ref": "#/definitions/export.CustodyTranscript"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "export.CustodySignature": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "description": "see CustodyKeyID",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "hex",
+                    "type": "string"
+                }
+            }
+        },
+        "export.CustodyStep": {
+            "type": "object",
+            "properties": {
+                "adapter_version": {
+                    "type": "string"
+                },
+                "environment_hash": {
+                    "type": "string"
+                },
+                "model_id": {
+                    "type": "string"
+                },
+                "stage": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.CustodyTranscript": {
+            "type": "object",
+            "properties": {
+                "post_processed_at": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.ParameterSchema": {
+            "type": "object",
+            "properties": {
+                "default": {},
+                "description": {
+                    "type": "string"
+                },
+                "group": {
+                    "description": "For UI grouping: \"basic\", \"advanced\", \"quality\"",
+                    "type": "string"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "description": "For enum-like parameters",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "\"int\", \"float\", \"string\", \"bool\", \"[]string\"",
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.TranscriptResult": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "model_used": {
+                    "type": "string"
+                },
+                "processing_time": {
+                    "type": "integer"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.TranscriptSegment"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "word_segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.TranscriptWord"
+                    }
+                }
+            }
+        },
+        "interfaces.TranscriptSegment": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "Confidence is the mean score of the segment's scored words, and\nLowConfidence flags it for review when that is below the threshold",
+                    "type": "number"
+                },
+                "end": {
+                    "type": "number"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "low_confidence": {
+                    "type": "boolean"
+                },
+                "speaker": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.TranscriptWord": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "speaker": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "number"
+                },
+                "vocabulary_filter_match": {
+                    "description": "VocabularyFilterMatch flags a word of the job's vocabulary filter when\nits method is tag",
+                    "type": "boolean"
+                },
+                "word": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AWSTagRoute": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "monthly_quota_minutes": {
+                    "description": "MonthlyQuotaMinutes caps the audio the route's jobs may transcribe per\ncalendar month, on top of the workspace quota; 0: unlimited",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "empty outside every organization",
+                    "type": "string"
+                },
+                "output_bucket_name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "profile_id": {
+                    "description": "ProfileID replaces the default profile; OutputBucketName replaces the\nbucket the request names",
+                    "type": "string"
+                },
+                "tag_key": {
+                    "type": "string"
+                },
+                "tag_value": {
+                    "description": "empty: any value",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "used_minutes": {
+                    "description": "UsedMinutes is the audio the route's jobs transcribed this month, when listed",
+                    "type": "number"
+                }
+            }
+        },
+        "models.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "description": "username or API key name",
+                    "type": "string"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "JSON-serialized map[string]interface{}",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Benchmark": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model_ids": {
+                    "description": "ModelIDs are the comma-separated transcription adapters compared",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "description": "Parameters every adapter is called with, converted for each; diarization is skipped",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WhisperXParams"
+                        }
+                    ]
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BenchmarkRun"
+                    }
+                },
+                "samples": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BenchmarkSample"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BenchmarkRun": {
+            "type": "object",
+            "properties": {
+                "benchmark_id": {
+                    "type": "string"
+                },
+                "cer": {
+                    "type": "number"
+                },
+                "char_errors": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "cost": {
+                    "description": "at ADAPTER_COST_PER_MINUTE; unset when unpriced",
+                    "type": "number"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "model_id": {
+                    "type": "string"
+                },
+                "processing_duration": {
+                    "description": "milliseconds",
+                    "type": "integer"
+                },
+                "reference_chars": {
+                    "type": "integer"
+                },
+                "reference_words": {
+                    "description": "Word and character errors against the sample's reference, and the\nresulting error rates",
+                    "type": "integer"
+                },
+                "sample_id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "text": {
+                    "description": "Text is the adapter's transcript",
+                    "type": "string"
+                },
+                "wer": {
+                    "type": "number"
+                },
+                "word_errors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BenchmarkSample": {
+            "type": "object",
+            "properties": {
+                "audio_seconds": {
+                    "type": "number"
+                },
+                "benchmark_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "the uploaded file name",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Reference is the ground-truth transcript the adapters are scored against",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Collection": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "restricted": {
+                    "description": "Restricted collections, and the collections and jobs inside them, are\nonly accessible to admins and their members",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CollectionMember": {
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ComponentJob": {
+            "type": "object",
+            "properties": {
+                "execution_id": {
+                    "type": "string"
+                },
+                "finalized_at": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ComponentVersion": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CorrectionRule": {
+            "type": "object",
+            "properties": {
+                "case_sensitive": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Enabled has no database default, which GORM would apply to disabled rules",
+                    "type": "boolean"
+                },
+                "find": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "empty outside every organization",
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "regex": {
+                    "type": "boolean"
+                },
+                "replace": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CostSummary": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "number"
+                },
+                "jobs": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Key is the day (2006-01-02), profile ID, API key ID or adapter model ID;\nempty for jobs of no profile or key",
+                    "type": "string"
+                },
+                "minutes": {
+                    "description": "of audio sent to the adapters",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "of the profile or API key",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "AUDIO_DOWNLOAD_FAILED",
+                "ADAPTER_TIMEOUT",
+                "DIARIZATION_OOM",
+                "INVALID_PARAMS",
+                "AUDIO_QUARANTINED",
+                "JOB_CANCELLED",
+                "JOB_INTERRUPTED",
+                "TRANSCRIPTION_FAILED"
+            ],
+            "x-enum-comments": {
+                "ErrorAdapterTimeout": "a model adapter did not finish in time",
+                "ErrorAudioDownloadFailed": "the audio could not be fetched from its URL or S3",
+                "ErrorAudioQuarantined": "the upload scan flagged the audio",
+                "ErrorDiarizationOOM": "diarization ran out of memory",
+                "ErrorInvalidParams": "an adapter rejected the job's parameters",
+                "ErrorJobCancelled": "the job was cancelled or killed by a user",
+                "ErrorJobInterrupted": "the server or worker running the job stopped",
+                "ErrorTranscriptionFailed": "any other failure"
+            },
+            "x-enum-descriptions": [
+                "the audio could not be fetched from its URL or S3",
+                "a model adapter did not finish in time",
+                "diarization ran out of memory",
+                "an adapter rejected the job's parameters",
+                "the upload scan flagged the audio",
+                "the job was cancelled or killed by a user",
+                "the server or worker running the job stopped",
+                "any other failure"
+            ],
+            "x-enum-varnames": [
+                "ErrorAudioDownloadFailed",
+                "ErrorAdapterTimeout",
+                "ErrorDiarizationOOM",
+                "ErrorInvalidParams",
+                "ErrorAudioQuarantined",
+                "ErrorJobCancelled",
+                "ErrorJobInterrupted",
+                "ErrorTranscriptionFailed"
+            ]
+        },
+        "models.FeedEpisode": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "enclosure_url": {
+                    "type": "string"
+                },
+                "guid": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "type": "boolean"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeedSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "backfill_count": {
+                    "description": "Number of most recent episodes to transcribe when the feed is first polled;\nolder back-catalog items are recorded as seen and skipped",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_polled_at": {
+                    "type": "string"
+                },
+                "poll_interval_minutes": {
+                    "type": "integer"
+                },
+                "profile_id": {
+                    "description": "Profile whose parameters are used for episode jobs (default profile when empty)",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "description": "Workspace of the subscriber; episode jobs are created in it",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobStage": {
+            "type": "object",
+            "properties": {
+                "ended_at": {
+                    "description": "unset while the job is in the stage",
+                    "type": "string"
+                },
+                "stage": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobStatus": {
+            "type": "string",
+            "enum": [
+                "uploaded",
+                "downloading",
+                "pending",
+                "processing",
+                "completed",
+                "failed",
+                "quarantined",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "StatusUploaded",
+                "StatusDownloading",
+                "StatusPending",
+                "StatusProcessing",
+                "StatusCompleted",
+                "StatusFailed",
+                "StatusQuarantined",
+                "StatusCancelled"
+            ]
+        },
+        "models.LibraryImport": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "dedup": {
+                    "description": "Dedup skips files already imported into the workspace, by location or content",
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "description": "DryRun imports list the files they would queue without creating jobs",
+                    "type": "boolean"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "files_found": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profile_id": {
+                    "type": "string"
+                },
+                "queued": {
+                    "type": "integer"
+                },
+                "rate_per_minute": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "s3://bucket/prefix or a directory",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LibraryImportFile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "description": "Fingerprint identifies the file's content: the S3 ETag with the size, or\nthe SHA-256 of local files",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "import_id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "description": "Workspace the file was imported into, for deduplication across imports",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MultiTrackFile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "description": "Original filename (used as speaker name)",
+                    "type": "string"
+                },
+                "file_path": {
+                    "description": "Full path to audio file",
+                    "type": "string"
+                },
+                "gain": {
+                    "description": "Gain value from .aup file",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mute": {
+                    "description": "Whether track is muted",
+                    "type": "boolean"
+                },
+                "offset": {
+                    "description": "Offset in seconds from .aup file",
+                    "type": "number"
+                },
+                "pan": {
+                    "description": "Pan value from .aup file (-1.0 to 1.0)",
+                    "type": "number"
+                },
+                "track_index": {
+                    "description": "Order of the track",
+                    "type": "integer"
+                },
+                "track_name": {
+                    "description": "TrackName is the speaker name given when the track was uploaded; the file\nname is used without one",
+                    "type": "string"
+                },
+                "transcription_job": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    ]
+                },
+                "transcription_job_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Note": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "The user's note content (markdown/plain)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "number"
+                },
+                "end_word_index": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quote": {
+                    "description": "The exact quoted text chosen by the user",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "Time bounds for the selection (in seconds)",
+                    "type": "number"
+                },
+                "start_word_index": {
+                    "description": "Indexed selection into transcript by word positions",
+                    "type": "integer"
+                },
+                "transcription": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    ]
+                },
+                "transcription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationTemplate": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "description": "ContentType is sent with webhook bodies; EventBridge details are always\nJSON and email bodies plain text",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Organization": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProfileVersion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/models.WhisperXParams"
+                },
+                "profile_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "from 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecordingSession": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "chunk_count": {
+                    "description": "ChunkCount is the index the next chunk must have; clients resume from it\nafter a reload",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "description": "JobID is set once the recording has been finalized",
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RetentionPolicy": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "after_days": {
+                    "type": "integer"
+                },
+                "archive_uri": {
+                    "description": "ArchiveURI is the s3://bucket/prefix archived files are copied to, under\n\u003cworkspace\u003e/\u003cjob ID\u003e/",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "enforced": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "description": "empty outside every organization",
+                    "type": "string"
+                },
+                "profile_id": {
+                    "description": "nil: every profile",
+                    "type": "string"
+                },
+                "storage_class": {
+                    "description": "StorageClass of archived objects, e.g. GLACIER or DEEP_ARCHIVE",
+                    "type": "string"
+                },
+                "tag_id": {
+                    "description": "nil: tagged or not",
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ShadowRun": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model_id": {
+                    "description": "the candidate",
+                    "type": "string"
+                },
+                "primary_model_id": {
+                    "type": "string"
+                },
+                "processing_duration": {
+                    "description": "milliseconds",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "transcript": {
+                    "description": "Transcript is the candidate's transcript, in the job transcript format",
+                    "type": "string"
+                },
+                "transcription_job_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "word_agreement": {
+                    "description": "WordAgreement is 1 minus the word error rate of the candidate's text\nmeasured against the primary transcript (0 when they share no words)",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Speaker": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SpeakerAttribute": {
+            "type": "object",
+            "properties": {
+                "age_range": {
+                    "description": "AgeRange is one of \"child\", \"13-17\", \"18-29\", \"30-44\", \"45-59\" or \"60+\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "gender_confidence": {
+                    "description": "GenderConfidence is the model's probability for the estimated gender",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "speaker": {
+                    "type": "string"
+                },
+                "speech_seconds": {
+                    "description": "SpeechSeconds is how much of the speaker's speech the estimate is based on",
+                    "type": "number"
+                },
+                "transcription_job_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StageTimings": {
+            "type": "object",
+            "properties": {
+                "alignment_ms": {
+                    "type": "integer"
+                },
+                "conversion_ms": {
+                    "type": "integer"
+                },
+                "diarization_ms": {
+                    "type": "integer"
+                },
+                "download_ms": {
+                    "type": "integer"
+                },
+                "post_processing_ms": {
+                    "type": "integer"
+                },
+                "transcription_ms": {
+                    "type": "integer"
+                },
+                "upload_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Summary": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "transcription": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    ]
+                },
+                "transcription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SummaryTemplate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "#rrggbb, for display",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TranscriptRevision": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "model_used": {
+                    "description": "ModelUsed is the model the transcript says produced it, if any",
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "from 1",
+                    "type": "integer"
+                },
+                "transcript": {
+                    "type": "string"
+                },
+                "transcription_job_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TranscriptionJob": {
+            "type": "object",
+            "properties": {
+                "action_items": {
+                    "description": "Results of the post-transcription automation configured in Parameters",
+                    "type": "string"
+                },
+                "alignment_fallback": {
+                    "description": "why timestamps are segment-level only, when alignment was skipped",
+                    "type": "string"
+                },
+                "api_key_id": {
+                    "description": "APIKeyID is the API key that submitted the job, if one did",
+                    "type": "integer"
+                },
+                "audio_archive_uri": {
+                    "type": "string"
+                },
+                "audio_expired_at": {
+                    "description": "Set once a retention policy removed the job's audio; AudioArchiveURI is\nwhere it was archived to, if it was",
+                    "type": "string"
+                },
+                "audio_path": {
+                    "type": "string"
+                },
+                "audio_seconds": {
+                    "description": "length of the audio transcribed, once the job completed",
+                    "type": "number"
+                },
+                "audio_sha256": {
+                    "description": "AudioSHA256 is the hex SHA-256 of the job's audio, taken when it is first\nprocessed. DuplicateOf is the job whose transcript the job reused, see\nWhisperXParams.DeduplicateAudio.",
+                    "type": "string"
+                },
+                "audio_uri": {
+                    "type": "string"
+                },
+                "aup_file_path": {
+                    "type": "string"
+                },
+                "aws_tag_route_id": {
+                    "description": "AWS tag route whose policy the job was submitted under; its usage counts\nagainst the route's quota too",
+                    "type": "integer"
+                },
+                "cancel_requested_at": {
+                    "type": "string"
+                },
+                "chapters": {
+                    "description": "JSON-serialized []service.Chapter",
+                    "type": "string"
+                },
+                "collection_id": {
+                    "description": "CollectionID is the collection the job is filed in, if any",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diarization": {
+                    "type": "boolean"
+                },
+                "duplicate_of": {
+                    "type": "string"
+                },
+                "error_code": {
+                    "description": "why the job failed, for clients to branch on",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ErrorCode"
+                        }
+                    ]
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "estimated_cost": {
+                    "description": "EstimatedCost is what the job's executions are estimated to have cost at\nthe configured per-minute adapter prices",
+                    "type": "number"
+                },
+                "finalized_at": {
+                    "description": "A finalized transcript is a record that only admins may change or reprocess",
+                    "type": "string"
+                },
+                "finalized_by": {
+                    "type": "string"
+                },
+                "heartbeat_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "individual_transcripts": {
+                    "description": "JSON-serialized map[string]*string",
+                    "type": "string"
+                },
+                "ingest_progress": {
+                    "description": "0-100 while a source URL is being downloaded",
+                    "type": "number"
+                },
+                "is_multi_track": {
+                    "type": "boolean"
+                },
+                "low_confidence_segments": {
+                    "description": "Confidence review: LowConfidenceSegments counts the transcript segments\nflagged below the confidence threshold. Jobs with enough of them need\nreview (ReviewStatus) until a reviewer has seen to them.",
+                    "type": "integer"
+                },
+                "merge_error": {
+                    "type": "string"
+                },
+                "merge_status": {
+                    "description": "none, pending, processing, completed, failed",
+                    "type": "string"
+                },
+                "merged_audio_path": {
+                    "type": "string"
+                },
+                "multi_track_files": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MultiTrackFile"
+                    }
+                },
+                "multi_track_folder": {
+                    "type": "string"
+                },
+                "output_bucket_name": {
+                    "type": "string"
+                },
+                "parameter_overrides": {
+                    "description": "ParameterOverrides are the parameters, as JSON, the submission replaced\nin the profile's",
+                    "type": "string"
+                },
+                "parameters": {
+                    "description": "WhisperX parameters",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WhisperXParams"
+                        }
+                    ]
+                },
+                "post_process_error": {
+                    "type": "string"
+                },
+                "post_processed_at": {
                     "type": "string"
                 },
-                "id": {
+                "profile_id": {
+                    "description": "Profile whose parameters the job was queued with, and the version of it\nthey were, see ProfileVersion",
+                    "type": "string"
+                },
+                "profile_version": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "progress": {
+                    "description": "0-100 while the job is being transcribed",
+                    "type": "number"
                 },
-                "key_preview": {
+                "recorded_at": {
+                    "description": "Wall-clock start of the recording, so transcripts can show absolute times",
                     "type": "string"
                 },
-                "last_used": {
+                "recorded_at_source": {
+                    "description": "user or metadata",
                     "type": "string"
                 },
-                "name": {
+                "review_status": {
                     "type": "string"
                 },
-                "updated_at": {
+                "reviewed_at": {
                     "type": "string"
-                }
-            }
-        },
-        "api.APIKeysWrapper": {
-            "type": "object",
-            "properties": {
-                "api_keys": {
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "scan_signature": {
+                    "type": "string"
+                },
+                "scan_status": {
+                    "type": "string"
+                },
+                "segment_sentiment": {
+                    "description": "JSON-serialized []service.SegmentSentiment",
+                    "type": "string"
+                },
+                "source_url": {
+                    "type": "string"
+                },
+                "stage": {
+                    "description": "what a processing job is doing, see StageTranscribing",
+                    "type": "string"
+                },
+                "stage_timings": {
+                    "description": "StageTimings are how long the job spent in each stage",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.StageTimings"
+                        }
+                    ]
+                },
+                "stages": {
+                    "description": "Stages are only loaded with the job's status",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api.APIKeyListResponse"
+                        "$ref": "#/definitions/models.JobStage"
                     }
-                }
-            }
-        },
-        "api.ChangePasswordRequest": {
-            "type": "object",
-            "required": [
-                "confirmPassword",
-                "currentPassword",
-                "newPassword"
-            ],
-            "properties": {
-                "confirmPassword": {
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "summary": {
                     "type": "string"
                 },
-                "currentPassword": {
+                "tags": {
+                    "description": "JSON-serialized []TagEntry, mirroring the job's tag assignments",
                     "type": "string"
                 },
-                "newPassword": {
-                    "type": "string",
-                    "minLength": 6
-                }
-            }
-        },
-        "api.ChangeUsernameRequest": {
-            "type": "object",
-            "required": [
-                "newUsername",
-                "password"
-            ],
-            "properties": {
-                "newUsername": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
+                "title": {
+                    "type": "string"
                 },
-                "password": {
+                "transcript": {
                     "type": "string"
-                }
-            }
-        },
-        "api.ChatCreateRequest": {
-            "type": "object",
-            "required": [
-                "model",
-                "transcription_id"
-            ],
-            "properties": {
-                "model": {
+                },
+                "translation": {
+                    "description": "JSON-serialized service.Translation",
                     "type": "string"
                 },
-                "title": {
+                "updated_at": {
                     "type": "string"
                 },
-                "transcription_id": {
+                "user_id": {
+                    "description": "UserID is the signed-in user that submitted the job, if one did",
+                    "type": "integer"
+                },
+                "video_path": {
+                    "description": "uploaded video the audio was extracted from, when kept",
                     "type": "string"
-                }
-            }
-        },
-        "api.ChatMessageRequest": {
-            "type": "object",
-            "required": [
-                "content"
-            ],
-            "properties": {
-                "content": {
+                },
+                "worker_id": {
+                    "description": "WorkerID is the worker process that claimed the job, which refreshes\nHeartbeatAt while it runs. CancelRequestedAt asks that worker to kill it.",
+                    "type": "string"
+                },
+                "workspace": {
                     "type": "string"
                 }
             }
         },
-        "api.ChatMessageResponse": {
+        "models.TranscriptionJobExecution": {
             "type": "object",
             "properties": {
-                "content": {
+                "actual_parameters": {
+                    "description": "Parameters used for this execution (may differ from job parameters due to profiles)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WhisperXParams"
+                        }
+                    ]
+                },
+                "adapter": {
+                    "description": "Adapter is the transcription adapter that produced the result, which is a\nfallback when the job failed over from the adapters in FailedAdapters",
+                    "type": "string"
+                },
+                "audio_seconds": {
+                    "description": "length of the audio transcribed, counted against quotas",
+                    "type": "number"
+                },
+                "completed_at": {
                     "type": "string"
                 },
+                "cost": {
+                    "description": "Cost is estimated from the adapters' per-minute prices; nil when none is priced",
+                    "type": "number"
+                },
                 "created_at": {
+                    "description": "Metadata",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "environment_hash": {
+                    "description": "over the steps' environment hashes",
+                    "type": "string"
                 },
-                "role": {
+                "error_message": {
                     "type": "string"
-                }
-            }
-        },
-        "api.ChatModelsResponse": {
-            "type": "object",
-            "properties": {
-                "models": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "api.ChatSessionResponse": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                },
+                "failed_adapters": {
+                    "description": "JSON-serialized []AdapterAttempt",
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "input_hash": {
+                    "description": "SHA-256 of the audio",
+                    "type": "string"
                 },
-                "last_activity_at": {
+                "merge_duration": {
+                    "description": "Merge phase duration in milliseconds",
+                    "type": "integer"
+                },
+                "merge_end_time": {
                     "type": "string"
                 },
-                "last_message": {
-                    "$ref": "#/definitions/api.ChatMessageResponse"
+                "merge_start_time": {
+                    "type": "string"
                 },
-                "message_count": {
+                "multi_track_timings": {
+                    "description": "Multi-track specific timing data",
+                    "type": "string"
+                },
+                "processing_duration": {
+                    "description": "Duration in milliseconds",
                     "type": "integer"
                 },
-                "model": {
+                "replay_of": {
+                    "description": "ReplayOf is the execution a debug replay re-ran; replays leave the job untouched",
                     "type": "string"
                 },
-                "provider": {
+                "skipped_seconds": {
+                    "description": "SkippedSeconds is the silence and music voice activity detection cut\nout before the audio was sent to a remote adapter",
+                    "type": "number"
+                },
+                "snapshot": {
+                    "description": "What the adapters were called with, recorded for single-track jobs so the\nexecution can be replayed",
                     "type": "string"
                 },
-                "title": {
+                "started_at": {
+                    "description": "Execution timing",
                     "type": "string"
                 },
-                "transcription_id": {
+                "status": {
+                    "description": "Execution results",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.JobStatus"
+                        }
+                    ]
+                },
+                "transcription_job": {
+                    "description": "Relationship",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TranscriptionJob"
+                        }
+                    ]
+                },
+                "transcription_job_id": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -3924,150 +18736,127 @@ const docTemplate = `{
                 }
             }
         },
-        "api.ChatSessionWithMessages": {
+        "models.TranscriptionProfile": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "id": {
+                "description": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "last_activity_at": {
+                "id": {
                     "type": "string"
                 },
-                "last_message": {
-                    "$ref": "#/definitions/api.ChatMessageResponse"
-                },
-                "message_count": {
-                    "type": "integer"
-                },
-                "messages": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api.ChatMessageResponse"
-                    }
-                },
-                "model": {
-                    "type": "string"
+                "is_default": {
+                    "type": "boolean"
                 },
-                "provider": {
+                "name": {
                     "type": "string"
                 },
-                "title": {
+                "organization_id": {
+                    "description": "empty outside every organization",
                     "type": "string"
                 },
-                "transcription_id": {
-                    "type": "string"
+                "parameters": {
+                    "$ref": "#/definitions/models.WhisperXParams"
                 },
                 "updated_at": {
                     "type": "string"
-                }
-            }
-        },
-        "api.CreateAPIKeyRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "description": {
-                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "version": {
+                    "description": "Version counts the profile's changes of name, description or parameters, from 1",
+                    "type": "integer"
                 }
             }
         },
-        "api.CreateAPIKeyResponse": {
+        "models.UsageSummary": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "average_latency_seconds": {
+                    "description": "AverageLatencySeconds is how long completed executions took on average",
+                    "type": "number"
+                },
+                "executions": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failure_rate": {
+                    "description": "FailureRate is the share of executions that failed",
+                    "type": "number"
                 },
-                "id": {
+                "jobs": {
                     "type": "integer"
                 },
                 "key": {
+                    "description": "Key is the adapter model ID, profile ID or user ID when grouped; empty\nfor jobs of no profile or user",
                     "type": "string"
                 },
+                "languages": {
+                    "description": "Languages counts the jobs transcribed by the language detected or given",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "minutes": {
+                    "description": "of audio transcribed",
+                    "type": "number"
+                },
                 "name": {
+                    "description": "of the profile or user",
                     "type": "string"
-                }
-            }
-        },
-        "api.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
+                },
+                "period": {
+                    "description": "Period is the first day of the interval (2006-01-02)",
                     "type": "string"
                 }
             }
         },
-        "api.LLMConfigRequest": {
+        "models.User": {
             "type": "object",
-            "required": [
-                "provider"
-            ],
             "properties": {
-                "api_key": {
+                "auto_transcription_enabled": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "base_url": {
+                "default_profile_id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "id": {
+                    "type": "integer"
                 },
-                "provider": {
-                    "type": "string",
-                    "enum": [
-                        "ollama",
-                        "openai"
-                    ]
-                }
-            }
-        },
-        "api.LLMConfigResponse": {
-            "type": "object",
-            "properties": {
-                "base_url": {
+                "locale": {
+                    "description": "date and number format of exports; empty is ISO",
                     "type": "string"
                 },
-                "created_at": {
+                "notification_email": {
+                    "description": "Email notifications about the user's jobs; none without an address",
                     "type": "string"
                 },
-                "has_api_key": {
-                    "description": "Don't return actual API key",
+                "notify_attach_transcript": {
                     "type": "boolean"
                 },
-                "id": {
-                    "type": "integer"
+                "notify_on_completed": {
+                    "type": "boolean"
                 },
-                "is_active": {
+                "notify_on_failed": {
                     "type": "boolean"
                 },
-                "provider": {
+                "role": {
+                    "description": "accounts created before roles existed are backfilled to admin",
                     "type": "string"
                 },
-                "updated_at": {
+                "time_zone": {
+                    "description": "IANA name for times in exports; empty is UTC",
                     "type": "string"
-                }
-            }
-        },
-        "api.LoginRequest": {
-            "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
-            "properties": {
-                "password": {
+                },
+                "updated_at": {
                     "type": "string"
                 },
                 "username": {
@@ -4075,327 +18864,373 @@ const docTemplate = `{
                 }
             }
         },
-        "api.LoginResponse": {
+        "models.Voiceprint": {
             "type": "object",
             "properties": {
-                "token": {
+                "created_at": {
                     "type": "string"
                 },
-                "user": {
-                    "type": "object",
-                    "properties": {
-                        "id": {
-                            "type": "integer"
-                        },
-                        "username": {
-                            "type": "string"
-                        }
-                    }
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "samples": {
+                    "description": "Samples is the number of enrolled speakers averaged into the embedding",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "api.NoteCreateRequest": {
+        "models.WhisperXParams": {
             "type": "object",
-            "required": [
-                "content",
-                "quote"
-            ],
             "properties": {
-                "content": {
-                    "type": "string",
-                    "minLength": 1
+                "adapter_timeout_seconds": {
+                    "description": "per attempt; 0 waits for the adapter",
+                    "type": "integer"
                 },
-                "end_time": {
-                    "type": "number",
-                    "minimum": 0
+                "align_model": {
+                    "description": "Alignment settings",
+                    "type": "string"
                 },
-                "end_word_index": {
-                    "type": "integer",
-                    "minimum": 0
+                "api_key": {
+                    "description": "OpenAI settings",
+                    "type": "string"
                 },
-                "quote": {
-                    "type": "string",
-                    "minLength": 1
+                "attention_context_left": {
+                    "description": "NVIDIA Parakeet-specific parameters for long-form audio",
+                    "type": "integer"
                 },
-                "start_time": {
-                    "type": "number",
-                    "minimum": 0
+                "attention_context_right": {
+                    "type": "integer"
                 },
-                "start_word_index": {
-                    "description": "Use gte=0 so 0 is valid (first word/time); avoid 'required' which fails for zero values",
-                    "type": "integer",
-                    "minimum": 0
-                }
-            }
-        },
-        "api.NoteUpdateRequest": {
-            "type": "object",
-            "required": [
-                "content"
-            ],
-            "properties": {
-                "content": {
-                    "type": "string",
-                    "minLength": 1
-                }
-            }
-        },
-        "api.RefreshTokenResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
+                "auto_action_items": {
+                    "type": "boolean"
+                },
+                "auto_chapters": {
+                    "type": "boolean"
+                },
+                "auto_sentiment": {
+                    "type": "boolean"
+                },
+                "auto_summary_template_id": {
+                    "description": "Post-transcription automation, run with the active LLM once the job completes",
                     "type": "string"
-                }
-            }
-        },
-        "api.RegisterRequest": {
-            "type": "object",
-            "required": [
-                "confirmPassword",
-                "password",
-                "username"
-            ],
-            "properties": {
-                "confirmPassword": {
+                },
+                "auto_tag": {
+                    "type": "boolean"
+                },
+                "batch_size": {
+                    "type": "integer"
+                },
+                "beam_size": {
+                    "type": "integer"
+                },
+                "best_of": {
+                    "type": "integer"
+                },
+                "callback_url": {
+                    "description": "Webhook settings",
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "chunk_size": {
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
-                }
-            }
-        },
-        "api.RegistrationStatusResponse": {
-            "type": "object",
-            "properties": {
-                "registration_enabled": {
-                    "description": "Match tests expecting snake_case key",
+                "compression_ratio_threshold": {
+                    "type": "number"
+                },
+                "compute_type": {
+                    "type": "string"
+                },
+                "condition_on_previous_text": {
                     "type": "boolean"
-                }
-            }
-        },
-        "api.SetUserDefaultProfileRequest": {
-            "type": "object",
-            "required": [
-                "profile_id"
-            ],
-            "properties": {
-                "profile_id": {
+                },
+                "custom_model": {
+                    "description": "fine-tuned CTranslate2 checkpoint (absolute path or Hugging Face repo ID) used instead of Model",
                     "type": "string"
-                }
-            }
-        },
-        "api.SpeakerMappingRequest": {
-            "type": "object",
-            "required": [
-                "custom_name",
-                "original_speaker"
-            ],
-            "properties": {
-                "custom_name": {
+                },
+                "deduplicate_audio": {
+                    "description": "DeduplicateAudio completes a job with the transcript of an earlier job\nof the same profile on identical audio instead of transcribing it again",
+                    "type": "boolean"
+                },
+                "device": {
+                    "description": "Device and computation",
+                    "type": "string"
+                },
+                "device_index": {
+                    "type": "integer"
+                },
+                "diarize": {
+                    "description": "Diarization settings",
+                    "type": "boolean"
+                },
+                "diarize_model": {
+                    "description": "Options: 'pyannote', 'nvidia_sortformer'",
+                    "type": "string"
+                },
+                "fallback_model_families": {
+                    "description": "Adapter failover: comma-separated model families tried in order when the\none before fails or runs past AdapterTimeoutSeconds, e.g. \"runpod-whisperx,whisper\"",
+                    "type": "string"
+                },
+                "fp16": {
+                    "type": "boolean"
+                },
+                "hf_token": {
+                    "description": "Token and progress",
+                    "type": "string"
+                },
+                "highlight_words": {
+                    "type": "boolean"
+                },
+                "hotwords": {
+                    "description": "phrases WhisperX biases decoding towards",
+                    "type": "string"
+                },
+                "initial_prompt": {
+                    "type": "string"
+                },
+                "interpolate_method": {
                     "type": "string"
                 },
-                "original_speaker": {
-                    "type": "string"
-                }
-            }
-        },
-        "api.SpeakerMappingResponse": {
-            "type": "object",
-            "properties": {
-                "custom_name": {
+                "is_multi_track_enabled": {
+                    "description": "Multi-track transcription settings",
+                    "type": "boolean"
+                },
+                "language": {
                     "type": "string"
                 },
-                "id": {
+                "length_penalty": {
+                    "type": "number"
+                },
+                "logprob_threshold": {
+                    "type": "number"
+                },
+                "max_line_count": {
                     "type": "integer"
                 },
-                "original_speaker": {
+                "max_line_width": {
+                    "description": "Output formatting",
+                    "type": "integer"
+                },
+                "max_speakers": {
+                    "type": "integer"
+                },
+                "min_speakers": {
+                    "type": "integer"
+                },
+                "model": {
+                    "description": "Model parameters",
                     "type": "string"
-                }
-            }
-        },
-        "api.SpeakerMappingsUpdateRequest": {
-            "type": "object",
-            "required": [
-                "mappings"
-            ],
-            "properties": {
-                "mappings": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api.SpeakerMappingRequest"
-                    }
-                }
-            }
-        },
-        "api.SummarizeRequest": {
-            "type": "object",
-            "required": [
-                "content",
-                "model",
-                "transcription_id"
-            ],
-            "properties": {
-                "content": {
+                },
+                "model_cache_only": {
+                    "type": "boolean"
+                },
+                "model_dir": {
                     "type": "string"
                 },
-                "model": {
+                "model_family": {
+                    "description": "Model family (whisper or nvidia)",
                     "type": "string"
                 },
-                "template_id": {
+                "nemo_adapter_name": {
+                    "description": "enable only this adapter; default enables all in the checkpoint",
                     "type": "string"
                 },
-                "transcription_id": {
+                "nemo_adapter_path": {
+                    "description": "NeMo adapter/LoRA weights (Parakeet and Canary), loaded onto the base model for each job",
                     "type": "string"
-                }
-            }
-        },
-        "api.SummarySettingsRequest": {
-            "type": "object",
-            "required": [
-                "default_model"
-            ],
-            "properties": {
-                "default_model": {
-                    "type": "string",
-                    "minLength": 1
-                }
-            }
-        },
-        "api.SummarySettingsResponse": {
-            "type": "object",
-            "properties": {
-                "default_model": {
+                },
+                "no_align": {
+                    "type": "boolean"
+                },
+                "no_speech_threshold": {
+                    "type": "number"
+                },
+                "output_format": {
+                    "description": "Output settings",
                     "type": "string"
-                }
-            }
-        },
-        "api.SummaryTemplateRequest": {
-            "type": "object",
-            "required": [
-                "model",
-                "name",
-                "prompt"
-            ],
-            "properties": {
-                "description": {
+                },
+                "patience": {
+                    "type": "number"
+                },
+                "preprocess_denoise": {
+                    "description": "RNNoise denoising (needs RNNOISE_MODEL_PATH)",
+                    "type": "boolean"
+                },
+                "preprocess_normalize": {
+                    "description": "Audio preprocessing with ffmpeg before the adapter runs",
+                    "type": "boolean"
+                },
+                "preprocess_resample": {
+                    "description": "resample to 16kHz mono",
+                    "type": "boolean"
+                },
+                "preprocess_trim_silence": {
+                    "description": "trim leading and trailing silence",
+                    "type": "boolean"
+                },
+                "preprocess_vad": {
+                    "description": "cut silence and music before remote adapters",
+                    "type": "boolean"
+                },
+                "print_progress": {
+                    "type": "boolean"
+                },
+                "return_char_alignments": {
+                    "type": "boolean"
+                },
+                "segment_language_id": {
+                    "description": "SegmentLanguageID tags each segment with its spoken language, for code-switching audio",
+                    "type": "boolean"
+                },
+                "segment_resolution": {
                     "type": "string"
                 },
-                "model": {
-                    "type": "string",
-                    "minLength": 1
+                "speaker_attributes": {
+                    "description": "SpeakerAttributes estimates each diarized speaker's gender and age range from\ntheir voice, for research datasets; workspaces can disable it",
+                    "type": "boolean"
                 },
-                "name": {
-                    "type": "string",
-                    "minLength": 1
+                "speaker_embeddings": {
+                    "type": "boolean"
                 },
-                "prompt": {
-                    "type": "string",
-                    "minLength": 1
-                }
-            }
-        },
-        "api.UpdateUserSettingsRequest": {
-            "type": "object",
-            "properties": {
-                "auto_transcription_enabled": {
+                "suppress_numerals": {
                     "type": "boolean"
-                }
-            }
-        },
-        "api.UserSettingsResponse": {
-            "type": "object",
-            "properties": {
-                "auto_transcription_enabled": {
+                },
+                "suppress_tokens": {
+                    "type": "string"
+                },
+                "task": {
+                    "description": "Task and language",
+                    "type": "string"
+                },
+                "temperature": {
+                    "description": "Transcription quality settings",
+                    "type": "number"
+                },
+                "temperature_increment_on_fallback": {
+                    "type": "number"
+                },
+                "threads": {
+                    "type": "integer"
+                },
+                "vad_method": {
+                    "description": "VAD (Voice Activity Detection) settings",
+                    "type": "string"
+                },
+                "vad_offset": {
+                    "type": "number"
+                },
+                "vad_onset": {
+                    "type": "number"
+                },
+                "verbose": {
                     "type": "boolean"
                 },
-                "default_profile_id": {
+                "vocabulary_filter_method": {
+                    "description": "remove (default), mask or tag",
+                    "type": "string"
+                },
+                "vocabulary_filter_name": {
+                    "type": "string"
+                },
+                "vocabulary_name": {
+                    "description": "Custom vocabulary, by name: its phrases are added to the prompt and\nhotwords, and the filter's words are removed, masked or tagged in the\ntranscript",
                     "type": "string"
                 }
             }
         },
-        "api.YouTubeDownloadRequest": {
+        "models.WorkspaceSettings": {
             "type": "object",
-            "required": [
-                "url"
-            ],
             "properties": {
-                "title": {
+                "speaker_attributes_disabled": {
+                    "description": "SpeakerAttributesDisabled stops speaker attribute estimation in the\nworkspace's jobs, whatever their profiles request",
+                    "type": "boolean"
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "url": {
+                "updated_by": {
+                    "type": "string"
+                },
+                "workspace": {
                     "type": "string"
                 }
             }
         },
-        "models.JobStatus": {
+        "registry.EnvironmentState": {
             "type": "string",
             "enum": [
-                "uploaded",
                 "pending",
-                "processing",
-                "completed",
+                "preparing",
+                "ready",
                 "failed"
             ],
             "x-enum-varnames": [
-                "StatusUploaded",
-                "StatusPending",
-                "StatusProcessing",
-                "StatusCompleted",
-                "StatusFailed"
+                "EnvironmentPending",
+                "EnvironmentPreparing",
+                "EnvironmentReady",
+                "EnvironmentFailed"
             ]
         },
-        "models.MultiTrackFile": {
+        "registry.EnvironmentStatus": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "completed_at": {
                     "type": "string"
                 },
-                "file_name": {
-                    "description": "Original filename (used as speaker name)",
+                "elapsed_seconds": {
+                    "type": "number"
+                },
+                "error": {
                     "type": "string"
                 },
-                "file_path": {
-                    "description": "Full path to audio file",
+                "model_id": {
                     "type": "string"
                 },
-                "gain": {
-                    "description": "Gain value from .aup file",
-                    "type": "number"
+                "started_at": {
+                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "state": {
+                    "$ref": "#/definitions/registry.EnvironmentState"
                 },
-                "mute": {
-                    "description": "Whether track is muted",
-                    "type": "boolean"
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.SpeakerAppearance": {
+            "type": "object",
+            "properties": {
+                "job_created_at": {
+                    "type": "string"
                 },
-                "offset": {
-                    "description": "Offset in seconds from .aup file",
-                    "type": "number"
+                "job_id": {
+                    "type": "string"
+                },
+                "original_speaker": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.SpeakerWithJobs": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "pan": {
-                    "description": "Pan value from .aup file (-1.0 to 1.0)",
-                    "type": "number"
+                "id": {
+                    "type": "string"
                 },
-                "track_index": {
-                    "description": "Order of the track",
+                "job_count": {
                     "type": "integer"
                 },
-                "transcription_job": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TranscriptionJob"
-                        }
-                    ]
-                },
-                "transcription_job_id": {
+                "name": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -4403,445 +19238,604 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Note": {
+        "repository.TagWithJobs": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "The user's note content (markdown/plain)",
+                "color": {
+                    "description": "#rrggbb, for display",
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "end_time": {
-                    "type": "number"
+                "id": {
+                    "type": "string"
                 },
-                "end_word_index": {
+                "job_count": {
                     "type": "integer"
                 },
-                "id": {
+                "key": {
                     "type": "string"
                 },
-                "quote": {
-                    "description": "The exact quoted text chosen by the user",
+                "updated_at": {
                     "type": "string"
                 },
-                "start_time": {
-                    "description": "Time bounds for the selection (in seconds)",
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.Chapter": {
+            "type": "object",
+            "properties": {
+                "end": {
                     "type": "number"
                 },
-                "start_word_index": {
-                    "description": "Indexed selection into transcript by word positions",
+                "first_segment": {
+                    "description": "index into the transcript's segments",
                     "type": "integer"
                 },
-                "transcription": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TranscriptionJob"
-                        }
-                    ]
+                "last_segment": {
+                    "type": "integer"
                 },
-                "transcription_id": {
-                    "type": "string"
+                "start": {
+                    "type": "number"
                 },
-                "updated_at": {
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "models.Summary": {
+        "service.DirectoryUsage": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
+                "bytes": {
+                    "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
+                "files": {
+                    "type": "integer"
                 },
-                "id": {
+                "path": {
                     "type": "string"
+                }
+            }
+        },
+        "service.DownloadCacheUsage": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
                 },
-                "model": {
+                "files": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.IntegrityIssue": {
+            "type": "object",
+            "properties": {
+                "action": {
                     "type": "string"
                 },
-                "template_id": {
+                "job_id": {
                     "type": "string"
                 },
-                "transcription": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TranscriptionJob"
-                        }
-                    ]
+                "kind": {
+                    "type": "string"
                 },
-                "transcription_id": {
+                "path": {
                     "type": "string"
                 },
-                "updated_at": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "models.SummaryTemplate": {
+        "service.IntegrityReport": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "errors": {
+                    "description": "Lookups that failed, so the files they were for could not be checked",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "description": {
+                "finished_at": {
                     "type": "string"
                 },
-                "id": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.IntegrityIssue"
+                    }
+                },
+                "jobs_checked": {
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
+                }
+            }
+        },
+        "service.JobStorage": {
+            "type": "object",
+            "properties": {
+                "audio_bytes": {
+                    "type": "integer"
                 },
-                "model": {
+                "files": {
+                    "type": "integer"
+                },
+                "job_id": {
                     "type": "string"
                 },
-                "name": {
+                "status": {
                     "type": "string"
                 },
-                "prompt": {
+                "title": {
                     "type": "string"
                 },
-                "updated_at": {
+                "workspace": {
                     "type": "string"
                 }
             }
         },
-        "models.TranscriptionJob": {
+        "service.Report": {
             "type": "object",
             "properties": {
-                "audio_path": {
-                    "type": "string"
+                "failed_jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ReportJob"
+                    }
                 },
-                "aup_file_path": {
-                    "type": "string"
+                "jobs_created": {
+                    "description": "JobsCreated counts the jobs submitted in the period by their current status",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
                 },
-                "created_at": {
+                "keyword_hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ReportKeywordHit"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
-                "diarization": {
-                    "type": "boolean"
+                "new_transcripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ReportJob"
+                    }
                 },
-                "error_message": {
+                "period_end": {
                     "type": "string"
                 },
-                "id": {
+                "period_start": {
                     "type": "string"
                 },
-                "individual_transcripts": {
-                    "description": "JSON-serialized map[string]*string",
+                "schedule_id": {
                     "type": "string"
                 },
-                "is_multi_track": {
-                    "type": "boolean"
+                "usage_audio_minutes": {
+                    "type": "number"
                 },
-                "merge_error": {
+                "usage_month": {
+                    "description": "Month-to-date transcribed audio, as counted against the quota",
                     "type": "string"
                 },
-                "merge_status": {
-                    "description": "none, pending, processing, completed, failed",
+                "workspace": {
                     "type": "string"
-                },
-                "merged_audio_path": {
+                }
+            }
+        },
+        "service.ReportJob": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "multi_track_files": {
-                    "description": "Relationships",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.MultiTrackFile"
-                    }
-                },
-                "multi_track_folder": {
+                "finished_at": {
                     "type": "string"
                 },
-                "parameters": {
-                    "description": "WhisperX parameters",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.WhisperXParams"
-                        }
-                    ]
-                },
-                "status": {
-                    "$ref": "#/definitions/models.JobStatus"
-                },
-                "summary": {
+                "id": {
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                }
+            }
+        },
+        "service.ReportKeywordHit": {
+            "type": "object",
+            "properties": {
+                "excerpt": {
+                    "type": "string"
                 },
-                "transcript": {
+                "job_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "keyword": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "models.TranscriptionJobExecution": {
+        "service.RetentionAction": {
             "type": "object",
             "properties": {
-                "actual_parameters": {
-                    "description": "Parameters used for this execution (may differ from job parameters due to profiles)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.WhisperXParams"
-                        }
-                    ]
-                },
-                "completed_at": {
+                "action": {
                     "type": "string"
                 },
-                "created_at": {
-                    "description": "Metadata",
+                "age_days": {
+                    "type": "integer"
+                },
+                "archive_uri": {
                     "type": "string"
                 },
-                "error_message": {
+                "enforced": {
+                    "description": "Enforced is whether the policy applies it; other actions are only reported",
+                    "type": "boolean"
+                },
+                "error": {
                     "type": "string"
                 },
-                "id": {
+                "job_id": {
                     "type": "string"
                 },
-                "merge_duration": {
-                    "description": "Merge phase duration in milliseconds",
+                "policy_id": {
                     "type": "integer"
                 },
-                "merge_end_time": {
+                "result": {
                     "type": "string"
                 },
-                "merge_start_time": {
+                "target": {
                     "type": "string"
                 },
-                "multi_track_timings": {
-                    "description": "Multi-track specific timing data",
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.RetentionReport": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.RetentionAction"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "generated_at": {
                     "type": "string"
                 },
-                "processing_duration": {
-                    "description": "Duration in milliseconds",
+                "jobs_checked": {
                     "type": "integer"
+                }
+            }
+        },
+        "service.RetrievedChunk": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "number"
                 },
-                "started_at": {
-                    "description": "Execution timing",
+                "job_created_at": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "Execution results",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JobStatus"
-                        }
-                    ]
+                "job_id": {
+                    "type": "string"
                 },
-                "transcription_job": {
-                    "description": "Relationship",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.TranscriptionJob"
-                        }
-                    ]
+                "score": {
+                    "type": "number"
                 },
-                "transcription_job_id": {
+                "start_time": {
+                    "type": "number"
+                },
+                "text": {
                     "type": "string"
                 },
-                "updated_at": {
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "models.TranscriptionProfile": {
+        "service.SegmentSentiment": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "emotion": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "end": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "score": {
+                    "description": "-1 (most negative) to 1 (most positive)",
+                    "type": "number"
                 },
-                "is_default": {
-                    "type": "boolean"
+                "segment": {
+                    "description": "index into the transcript's segments",
+                    "type": "integer"
                 },
-                "name": {
+                "sentiment": {
+                    "description": "positive, neutral or negative",
                     "type": "string"
                 },
-                "parameters": {
-                    "$ref": "#/definitions/models.WhisperXParams"
-                },
-                "updated_at": {
+                "speaker": {
                     "type": "string"
+                },
+                "start": {
+                    "type": "number"
                 }
             }
         },
-        "models.WhisperXParams": {
+        "service.StorageCleanup": {
             "type": "object",
             "properties": {
-                "align_model": {
-                    "description": "Alignment settings",
-                    "type": "string"
-                },
-                "attention_context_left": {
-                    "description": "NVIDIA Parakeet-specific parameters for long-form audio",
+                "bytes_reclaimed": {
                     "type": "integer"
                 },
-                "attention_context_right": {
+                "download_bytes": {
                     "type": "integer"
                 },
-                "batch_size": {
+                "downloads_removed": {
                     "type": "integer"
                 },
-                "beam_size": {
+                "max_age_seconds": {
                     "type": "integer"
                 },
-                "best_of": {
+                "partial_file_bytes": {
                     "type": "integer"
                 },
-                "chunk_size": {
+                "partial_files_removed": {
                     "type": "integer"
+                }
+            }
+        },
+        "service.StorageReport": {
+            "type": "object",
+            "properties": {
+                "backups": {
+                    "$ref": "#/definitions/service.DirectoryUsage"
                 },
-                "compression_ratio_threshold": {
-                    "type": "number"
+                "database": {
+                    "description": "Database counts the database file with its WAL and shared memory files",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.DirectoryUsage"
+                        }
+                    ]
                 },
-                "compute_type": {
+                "database_replica": {
+                    "$ref": "#/definitions/service.DirectoryUsage"
+                },
+                "download_cache": {
+                    "$ref": "#/definitions/service.DownloadCacheUsage"
+                },
+                "generated_at": {
                     "type": "string"
                 },
-                "condition_on_previous_text": {
-                    "type": "boolean"
+                "job_audio_bytes": {
+                    "type": "integer"
                 },
-                "device": {
-                    "description": "Device and computation",
+                "jobs_with_audio": {
+                    "description": "JobsWithAudio and JobAudioBytes count the jobs with audio on disk;\nLargestJobs lists the largest of them",
+                    "type": "integer"
+                },
+                "largest_jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.JobStorage"
+                    }
+                },
+                "transcripts": {
+                    "$ref": "#/definitions/service.DirectoryUsage"
+                },
+                "uploads": {
+                    "$ref": "#/definitions/service.DirectoryUsage"
+                },
+                "workspaces": {
+                    "description": "Workspaces splits the uploads by workspace, largest first; the legacy\nworkspace includes the quick transcription temp files",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.WorkspaceStorage"
+                    }
+                }
+            }
+        },
+        "service.Translation": {
+            "type": "object",
+            "properties": {
+                "language": {
                     "type": "string"
                 },
-                "device_index": {
+                "segments": {
+                    "description": "Segments holds one translated line per transcript segment, \"\" where the LLM\nreturned none",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "service.WorkspaceStorage": {
+            "type": "object",
+            "properties": {
+                "bytes": {
                     "type": "integer"
                 },
-                "diarize": {
-                    "description": "Diarization settings",
-                    "type": "boolean"
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "transcription.AdapterStatus": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "description": "APIKey is the key masked, or the reference to the secret store holding it;\nAPIKeySource is that store, or \"value\" for keys set directly",
+                    "type": "string"
                 },
-                "diarize_model": {
-                    "description": "Options: 'pyannote', 'nvidia_sortformer'",
+                "api_key_source": {
                     "type": "string"
                 },
-                "fp16": {
+                "enabled": {
                     "type": "boolean"
                 },
-                "hf_token": {
-                    "description": "Token and progress",
+                "endpoint": {
                     "type": "string"
                 },
-                "highlight_words": {
+                "has_api_key": {
                     "type": "boolean"
                 },
-                "initial_prompt": {
+                "kind": {
                     "type": "string"
                 },
-                "interpolate_method": {
+                "model_id": {
                     "type": "string"
                 },
-                "is_multi_track_enabled": {
-                    "description": "Multi-track transcription settings",
+                "registered": {
                     "type": "boolean"
                 },
-                "language": {
+                "source": {
+                    "description": "Source is where the settings come from: environment, or database when set\nthrough the admin API",
                     "type": "string"
                 },
-                "length_penalty": {
-                    "type": "number"
+                "supports_api_key": {
+                    "type": "boolean"
                 },
-                "logprob_threshold": {
+                "supports_endpoint": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "transcription.DiffChunk": {
+            "type": "object",
+            "properties": {
+                "hypothesis": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "transcription.DiffStats": {
+            "type": "object",
+            "properties": {
+                "cer": {
                     "type": "number"
                 },
-                "max_line_count": {
+                "char_errors": {
                     "type": "integer"
                 },
-                "max_line_width": {
-                    "description": "Output formatting",
+                "deletions": {
                     "type": "integer"
                 },
-                "max_speakers": {
+                "hypothesis_words": {
                     "type": "integer"
                 },
-                "min_speakers": {
+                "insertions": {
                     "type": "integer"
                 },
-                "model": {
-                    "description": "Model parameters",
-                    "type": "string"
-                },
-                "model_cache_only": {
-                    "type": "boolean"
-                },
-                "model_dir": {
-                    "type": "string"
+                "reference_chars": {
+                    "type": "integer"
                 },
-                "model_family": {
-                    "description": "Model family (whisper or nvidia)",
-                    "type": "string"
+                "reference_words": {
+                    "type": "integer"
                 },
-                "no_align": {
-                    "type": "boolean"
+                "substitutions": {
+                    "type": "integer"
                 },
-                "no_speech_threshold": {
+                "wer": {
                     "type": "number"
-                },
-                "output_format": {
-                    "description": "Output settings",
+                }
+            }
+        },
+        "transcription.ModelSchema": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string"
                 },
-                "patience": {
-                    "type": "number"
-                },
-                "print_progress": {
-                    "type": "boolean"
-                },
-                "return_char_alignments": {
-                    "type": "boolean"
-                },
-                "segment_resolution": {
+                "display_name": {
                     "type": "string"
                 },
-                "speaker_embeddings": {
-                    "type": "boolean"
+                "kind": {
+                    "description": "transcription, diarization or composite",
+                    "type": "string"
                 },
-                "suppress_numerals": {
-                    "type": "boolean"
+                "model_families": {
+                    "description": "ModelFamilies are the profile model_family values that select the adapter",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "suppress_tokens": {
+                "model_id": {
                     "type": "string"
                 },
-                "task": {
-                    "description": "Task and language",
-                    "type": "string"
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.ParameterSchema"
+                    }
+                }
+            }
+        },
+        "transcription.QuickCleanupStats": {
+            "type": "object",
+            "properties": {
+                "active_jobs": {
+                    "type": "integer"
                 },
-                "temperature": {
-                    "description": "Transcription quality settings",
-                    "type": "number"
+                "bytes_reclaimed": {
+                    "type": "integer"
                 },
-                "temperature_increment_on_fallback": {
-                    "type": "number"
+                "files_removed": {
+                    "type": "integer"
                 },
-                "threads": {
+                "jobs_expired": {
                     "type": "integer"
                 },
-                "vad_method": {
-                    "description": "VAD (Voice Activity Detection) settings",
+                "last_run_at": {
                     "type": "string"
                 },
-                "vad_offset": {
-                    "type": "number"
+                "retention_seconds": {
+                    "type": "integer"
                 },
-                "vad_onset": {
-                    "type": "number"
+                "runs": {
+                    "type": "integer"
                 },
-                "verbose": {
-                    "type": "boolean"
+                "storage_bytes": {
+                    "description": "as of the last run",
+                    "type": "integer"
+                },
+                "storage_limit_bytes": {
+                    "description": "0: unlimited",
+                    "type": "integer"
                 }
             }
         },
@@ -4873,6 +19867,65 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "transcription.RealignResult": {
+            "type": "object",
+            "properties": {
+                "aligned_segments": {
+                    "description": "Segments that could not be aligned keep the times they had",
+                    "type": "integer"
+                },
+                "transcript": {
+                    "$ref": "#/definitions/interfaces.TranscriptResult"
+                },
+                "unaligned_segments": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transcription.ReplayResult": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Changes lists the differences between the original's adapters and today's,\ne.g. \"whisperx: version 3.3.1 -\u003e 3.4.0\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "environment_matches": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "execution": {
+                    "description": "the replay, with its own snapshot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TranscriptionJobExecution"
+                        }
+                    ]
+                },
+                "input_matches": {
+                    "description": "The audio and adapter environments compared with the original's",
+                    "type": "boolean"
+                },
+                "output_matches": {
+                    "description": "OutputMatches reports whether the transcript is identical to the job's\ncurrent transcript",
+                    "type": "boolean"
+                },
+                "replay_of": {
+                    "type": "string"
+                },
+                "sandbox_dir": {
+                    "description": "kept on request for inspection",
+                    "type": "string"
+                },
+                "transcript": {
+                    "$ref": "#/definitions/interfaces.TranscriptResult"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,133 @@
+// Command scriberr-bundle builds an offline bundle containing the Python
+// interpreters, wheels and model weights the local adapters need, so Scriberr can
+// be installed on hosts without internet access. Run it on a connected machine
+// with the same OS, architecture and GPU backend as the target, copy the output
+// directory over and point OFFLINE_BUNDLE_DIR at it.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"scriberr/internal/transcription/adapters"
+	"scriberr/pkg/logger"
+)
+
+// bundleStep prepares one adapter environment while filling the bundle
+type bundleStep struct {
+	name    string
+	prepare func(context.Context) error
+}
+
+func main() {
+	output := flag.String("output", "scriberr-bundle", "Directory to write the bundle to")
+	whisperModels := flag.String("whisper-models", "small", "Comma-separated Whisper models to include")
+	alignLanguages := flag.String("align-languages", "en", "Comma-separated languages whose alignment models to include")
+	hfToken := flag.String("hf-token", os.Getenv("HF_TOKEN"), "Hugging Face token for the gated pyannote models (diarization is skipped without one)")
+	gpuBackend := flag.String("gpu-backend", os.Getenv("GPU_BACKEND"), "GPU backend to build for: cpu, cuda, rocm or mps (default: detect)")
+	skipNeMo := flag.Bool("skip-nemo", false, "Skip the Parakeet, Canary and Sortformer environment and checkpoints")
+	flag.Parse()
+
+	logger.Init(os.Getenv("LOG_LEVEL"))
+
+	if *gpuBackend != "" {
+		os.Setenv("GPU_BACKEND", *gpuBackend)
+	}
+
+	if err := build(*output, splitList(*whisperModels), splitList(*alignLanguages), *hfToken, *skipNeMo); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build offline bundle: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func build(output string, whisperModels, alignLanguages []string, hfToken string, skipNeMo bool) error {
+	dir, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	adapters.ConfigureOfflineBundleBuild(dir)
+
+	// Environments are created in a staging directory only to fill the bundle's caches
+	staging, err := os.MkdirTemp(dir, ".staging-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	ctx := context.Background()
+	nemoEnvPath := filepath.Join(staging, "parakeet")
+	whisperx := adapters.NewWhisperXAdapter(staging)
+	pyannote := adapters.NewPyAnnoteAdapter(filepath.Join(staging, "pyannote"))
+
+	steps := []bundleStep{
+		{"whisperx", whisperx.PrepareEnvironment},
+		{"pyannote", pyannote.PrepareEnvironment},
+	}
+	if !skipNeMo {
+		// Parakeet, Canary and Sortformer share one environment, as in the server
+		steps = append(steps,
+			bundleStep{"parakeet", adapters.NewParakeetAdapter(nemoEnvPath).PrepareEnvironment},
+			bundleStep{"canary", adapters.NewCanaryAdapter(nemoEnvPath).PrepareEnvironment},
+			bundleStep{"sortformer", adapters.NewSortformerAdapter(nemoEnvPath).PrepareEnvironment},
+		)
+	}
+
+	for _, step := range steps {
+		logger.Info("Preparing environment", "model", step.name)
+		if err := step.prepare(ctx); err != nil {
+			return fmt.Errorf("failed to prepare %s: %w", step.name, err)
+		}
+	}
+
+	logger.Info("Downloading WhisperX models", "models", whisperModels, "align_languages", alignLanguages)
+	if err := whisperx.PrefetchModels(ctx, whisperModels, alignLanguages, hfToken); err != nil {
+		return fmt.Errorf("failed to download WhisperX models: %w", err)
+	}
+
+	diarization := hfToken != ""
+	if diarization {
+		logger.Info("Downloading pyannote pipeline and speaker attribute model")
+		if err := pyannote.PrefetchModels(ctx, hfToken); err != nil {
+			return fmt.Errorf("failed to download pyannote pipeline: %w", err)
+		}
+	} else {
+		logger.Warn("No Hugging Face token given, diarization models are not included")
+	}
+
+	manifest := adapters.BundleManifest{
+		CreatedAt:      time.Now().UTC(),
+		GOOS:           runtime.GOOS,
+		GOARCH:         runtime.GOARCH,
+		GPUBackend:     adapters.DetectGPUBackend(),
+		WhisperModels:  whisperModels,
+		AlignLanguages: alignLanguages,
+		Diarization:    diarization,
+		NeMo:           !skipNeMo,
+	}
+	if err := adapters.WriteBundleManifest(dir, manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Printf("Offline bundle written to %s\n", dir)
+	fmt.Printf("Copy it to the target host and set OFFLINE_BUNDLE_DIR to its location.\n")
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
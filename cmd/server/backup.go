@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/backup"
+	"scriberr/internal/config"
+	"scriberr/internal/database"
+	"scriberr/pkg/logger"
+)
+
+// runBackup implements `scriberr backup`: it writes a backup archive into the
+// backup directory, or to -output, and copies it to BACKUP_S3_URI with -upload
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := fs.String("output", "", "Write the archive to this file instead of the backup directory")
+	upload := fs.Bool("upload", false, "Copy the archive to BACKUP_S3_URI")
+	includeUploads := fs.Bool("include-uploads", false, "Include uploaded audio (default: BACKUP_INCLUDE_UPLOADS)")
+	fs.Parse(args)
+
+	logger.Init(os.Getenv("LOG_LEVEL"))
+	cfg := config.Load()
+	if *includeUploads {
+		cfg.BackupIncludeUploads = true
+	}
+	if *upload && cfg.BackupS3URI == "" {
+		fmt.Fprintln(os.Stderr, "-upload needs BACKUP_S3_URI to be set")
+		return 2
+	}
+	if err := database.Initialize(cfg.DatabasePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+
+	svc := backup.NewService(cfg, database.DB, version)
+	if *output == "" {
+		info, err := svc.Run(context.Background(), *upload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+			return 1
+		}
+		fmt.Println(filepath.Join(cfg.BackupDir, info.Name))
+		if info.Location != "" {
+			fmt.Println(info.Location)
+		}
+		return 0
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *output, err)
+		return 1
+	}
+	_, err = backup.Create(database.DB, backup.PathsFromConfig(cfg), backup.Options{AppVersion: version, IncludeUploads: cfg.BackupIncludeUploads}, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+		fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+		return 1
+	}
+	fmt.Println(*output)
+	if *upload {
+		uri := cfg.BackupS3URI + "/" + filepath.Base(*output)
+		if err := svc.Upload(context.Background(), *output, uri); err != nil {
+			fmt.Fprintf(os.Stderr, "Upload failed: %v\n", err)
+			return 1
+		}
+		fmt.Println(uri)
+	}
+	return 0
+}
+
+// runRestore implements `scriberr restore <archive>`: it replaces the database
+// and transcripts with those in a local or s3:// archive. The server must be
+// stopped first.
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: scriberr restore <archive.tar.gz | s3://bucket/key>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	source := fs.Arg(0)
+
+	logger.Init(os.Getenv("LOG_LEVEL"))
+	cfg := config.Load()
+
+	if strings.HasPrefix(source, "s3://") {
+		if err := os.MkdirAll(cfg.BackupDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create backup directory: %v\n", err)
+			return 1
+		}
+		local := filepath.Join(cfg.BackupDir, filepath.Base(source))
+		if err := backup.NewService(cfg, nil, version).Fetch(context.Background(), source, local); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to download %s: %v\n", source, err)
+			return 1
+		}
+		source = local
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open %s: %v\n", source, err)
+		return 1
+	}
+	defer f.Close()
+	manifest, err := backup.Restore(f, backup.PathsFromConfig(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Restore failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Restored backup from %s (Scriberr %s, %d files)\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"), manifest.AppVersion, manifest.Files)
+	return 0
+}
@@ -7,27 +7,28 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"scriberr/internal/models"
-	"scriberr/internal/transcription/interfaces"
 	"syscall"
 	"time"
 
 	_ "scriberr/api-docs" // Import generated Swagger docs
 	"scriberr/internal/api"
 	"scriberr/internal/auth"
+	"scriberr/internal/backup"
 	"scriberr/internal/config"
 	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/mail"
 	"scriberr/internal/queue"
 	"scriberr/internal/repository"
+	"scriberr/internal/scanner"
+	"scriberr/internal/secrets"
 	"scriberr/internal/service"
 	"scriberr/internal/transcription"
-	"scriberr/internal/transcription/adapters"
-	"scriberr/internal/transcription/registry"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
 
 	"github.com/google/uuid"
-	"github.com/modal-labs/libmodal/modal-go"
 )
 
 // Version information (set by GoReleaser)
@@ -61,7 +62,34 @@ var (
 // @name Authorization
 // @description JWT token with Bearer prefix
 
+// Process roles. Without a role the process both serves the API and runs
+// jobs; serve and worker split the two across processes, possibly on other
+// machines, that coordinate through the shared database.
+const (
+	roleAll    = ""
+	roleServe  = "serve"  // HTTP API and background services; queued jobs are left to workers
+	roleWorker = "worker" // runs queued jobs only
+)
+
+// adapterReloadInterval is how often processes pick up adapter settings
+// changed through another process's admin API
+const adapterReloadInterval = 30 * time.Second
+
 func main() {
+	// Maintenance subcommands run instead of the server
+	role := roleAll
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "backup":
+			os.Exit(runBackup(os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(os.Args[2:]))
+		case roleServe, roleWorker:
+			role = os.Args[1]
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
 	// Handle version flag
 	var showVersion = flag.Bool("version", false, "Show version information")
 	flag.Parse()
@@ -75,14 +103,40 @@ func main() {
 
 	// Initialize structured logging first
 	logger.Init(os.Getenv("LOG_LEVEL"))
-	logger.Info("Starting Scriberr", "version", version)
+	logger.Info("Starting Scriberr", "version", version, "role", role)
 
 	// Load configuration
 	logger.Startup("config", "Loading configuration")
 	cfg := config.Load()
 
-	// Register adapters with config-based paths
-	registerAdapters(cfg)
+	// Export traces when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Init(tracing.Config{
+		Endpoint:       cfg.OTLPEndpoint,
+		TracesEndpoint: cfg.OTLPTracesEndpoint,
+		Headers:        cfg.OTLPHeaders,
+		ServiceName:    cfg.OTELServiceName,
+		Version:        version,
+	})
+	if err != nil {
+		logger.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	// Adapter credentials and Hugging Face tokens may reference a secret store
+	secrets.SetDefault(secrets.NewResolver(secrets.Config{
+		RefreshInterval: time.Duration(cfg.SecretsRefreshMinutes) * time.Minute,
+		VaultAddr:       cfg.VaultAddr,
+		VaultToken:      cfg.VaultToken,
+		Dir:             cfg.SecretsDir,
+	}))
+
+	// Restore a backup staged through the admin API while the database is closed
+	if manifest, err := backup.ApplyPendingRestore(cfg); err != nil {
+		logger.Error("Failed to restore staged backup", "error", err)
+		os.Exit(1)
+	} else if manifest != nil {
+		logger.Info("Restored staged backup", "created_at", manifest.CreatedAt, "files", manifest.Files)
+	}
 
 	// Initialize database
 	logger.Startup("database", "Connecting to database")
@@ -91,6 +145,13 @@ func main() {
 		os.Exit(1)
 	}
 	defer database.Close()
+	if cfg.DatabaseReplicaPath != "" {
+		logger.Startup("database", "Connecting to read replica")
+		if err := database.InitializeReplica(cfg.DatabaseReplicaPath); err != nil {
+			logger.Error("Failed to connect to read replica", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize authentication service
 	logger.Startup("auth", "Setting up authentication")
@@ -107,9 +168,15 @@ func main() {
 	chatRepo := repository.NewChatRepository(database.DB)
 	noteRepo := repository.NewNoteRepository(database.DB)
 	speakerMappingRepo := repository.NewSpeakerMappingRepository(database.DB)
+	speakerRepo := repository.NewSpeakerRepository(database.DB)
+	searchRepo := repository.NewSearchRepository(database.DB)
+	recordingRepo := repository.NewRecordingRepository(database.DB)
+	feedRepo := repository.NewFeedRepository(database.DB)
+	retentionRepo := repository.NewRetentionRepository(database.DB)
+	adapterConfigRepo := repository.NewAdapterConfigRepository(database.DB)
 
 	// Generate system API key
-	_, err := createSystemAPIKey(apiKeyRepo)
+	_, err = createSystemAPIKey(apiKeyRepo)
 	if err != nil {
 		logger.Error("Failed to create System API key", "error", err)
 		os.Exit(1)
@@ -119,22 +186,92 @@ func main() {
 	logger.Startup("service", "Initializing services")
 	userService := service.NewUserService(userRepo, authService)
 	fileService := service.NewFileService()
+	voiceprints := service.NewVoiceprintService(cfg, speakerMappingRepo)
+	speakerAttributes := service.NewSpeakerAttributeService(repository.NewSpeakerAttributeRepository(database.DB))
+	jobDeleter := service.NewJobDeleter(cfg.UploadDir, fileService, jobRepo, chatRepo, noteRepo, summaryRepo, speakerMappingRepo)
+	storage := service.NewStorageService(cfg, repository.NewStorageRepository(database.DB), fileService)
 
 	// Initialize unified transcription processor
 	logger.Startup("transcription", "Initializing transcription service")
 	unifiedProcessor := transcription.NewUnifiedJobProcessor(jobRepo)
+	uploadScanner, err := scanner.New(cfg)
+	if err != nil {
+		logger.Error("Failed to initialize upload scanner", "error", err)
+		os.Exit(1)
+	}
+	if uploadScanner != nil {
+		logger.Startup("scanner", "Scanning uploads before processing", "mode", cfg.ScanMode)
+		unifiedProcessor.SetScanner(uploadScanner)
+	}
+	unifiedProcessor.SetVoiceprints(voiceprints)
+	vocabularyRepo := repository.NewVocabularyRepository(database.DB)
+	unifiedProcessor.SetVocabularies(vocabularyRepo)
+	correctionRuleRepo := repository.NewCorrectionRuleRepository(database.DB)
+	unifiedProcessor.SetCorrectionRules(correctionRuleRepo)
+	unifiedProcessor.SetSpeakerAttributes(speakerAttributes)
+	unifiedProcessor.SetShadow(cfg.ShadowModelID, cfg.ShadowPercent)
+	unifiedProcessor.SetReviewThresholds(cfg.ReviewConfidenceThreshold, cfg.ReviewMinFlaggedSegments)
+	unifiedProcessor.SetDenoiseModel(cfg.RNNoiseModelPath)
+	unifiedProcessor.SetCostModel(cfg.AdapterCostPerMinute)
+	unifiedProcessor.SetResourceLimits(cfg.GPUMemoryHeadroomMB, cfg.MaxLoadPerCPU)
+	unifiedProcessor.SetChunking(time.Duration(cfg.ChunkMinMinutes)*time.Minute, cfg.ChunkSeconds, cfg.ChunkOverlapSeconds, cfg.ChunkWorkers)
+
+	// Register adapters with the settings changed through the admin API,
+	// falling back to the environment's, and pick up later changes
+	if err := unifiedProcessor.SetAdapterConfigs(transcription.NewAdapterEnvironment(cfg), adapterConfigRepo); err != nil {
+		logger.Error("Failed to register adapters", "error", err)
+		os.Exit(1)
+	}
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go unifiedProcessor.WatchAdapterConfigs(watchCtx, adapterReloadInterval)
 	s3Processor, err := transcription.NewS3JobProcessor(unifiedProcessor, jobRepo, fileService, cfg.UploadDir)
 	if err != nil {
 		logger.Error("Failed to initialize S3 processor", "error", err)
 		os.Exit(1)
 	}
 
-	// Bootstrap embedded Python environment (for all adapters)
-	logger.Startup("python", "Preparing Python environment")
-	if err := unifiedProcessor.InitEmbeddedPythonEnv(); err != nil {
-		logger.Error("Failed to prepare Python environment", "error", err)
+	mailer := mail.NewSender(mail.Config{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
+	emailNotifier := transcription.NewEmailNotifier(mailer, jobRepo, userRepo, cfg.PublicBaseURL)
+	if emailNotifier != nil {
+		defer emailNotifier.Stop()
+	}
+
+	// Publish job lifecycle events to EventBridge, the configured brokers and
+	// the submitters' email addresses
+	eventBus, err := newEventBus(cfg, s3Processor, emailNotifier)
+	if err != nil {
+		logger.Error("Failed to set up job events", "error", err)
+		os.Exit(1)
+	}
+	if err := eventBus.WatchJobs(database.DB); err != nil {
+		logger.Error("Failed to watch jobs for events", "error", err)
 		os.Exit(1)
 	}
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	// Bootstrap embedded Python environments (for all adapters) in the background so
+	// the API is served immediately; jobs wait in the queue until their models are ready
+	if role != roleServe {
+		logger.Startup("python", "Preparing Python environments in the background")
+		unifiedProcessor.StartEmbeddedPythonEnv()
+	}
+
+	if role == roleWorker {
+		runWorker(cfg, s3Processor, eventBus, shutdownTracing)
+		return
+	}
+
+	// Benchmarks are queued through the API and run in the process serving it
+	benchmarkRepo := repository.NewBenchmarkRepository(database.DB)
+	unifiedProcessor.SetBenchmarks(benchmarkRepo)
 
 	// Initialize quick transcription service
 	logger.Startup("quick-transcription", "Initializing quick transcription service")
@@ -143,34 +280,125 @@ func main() {
 		logger.Error("Failed to initialize quick transcription service", "error", err)
 		os.Exit(1)
 	}
+	if cfg.FastWarmup {
+		go func() {
+			if err := quickTranscriptionService.WarmFastModel(context.Background()); err != nil {
+				logger.Warn("Failed to warm up the fast transcription model", "model", cfg.FastModel, "error", err)
+			}
+		}()
+	}
 
 	// Initialize task queue
 	logger.Startup("queue", "Starting background processing")
 	taskQueue := queue.NewTaskQueue(3, s3Processor) // 3 workers
+	taskQueue.SetEvents(eventBus)
+	if role == roleServe {
+		taskQueue.DisableWorkers()
+	}
+
+	// Check jobs against their files before workers pick them up
+	logger.Startup("integrity", "Checking jobs against their files")
+	integrity := service.NewIntegrityService(jobRepo, fileService, taskQueue)
+	if _, err := integrity.Run(context.Background()); err != nil {
+		logger.Error("Integrity check failed", "error", err)
+	}
+
 	taskQueue.Start()
 	defer taskQueue.Stop()
 
+	// Start RSS/podcast feed poller
+	logger.Startup("feeds", "Starting feed subscription poller")
+	urlIngest := service.NewURLIngestService(cfg, jobRepo, taskQueue)
+	feedService := service.NewFeedService(cfg, feedRepo, profileRepo, urlIngest)
+	feedService.Start()
+	defer feedService.Stop()
+
+	// Start transcript embedding indexer for workspace chat
+	logger.Startup("embeddings", "Starting transcript embedding indexer")
+	embeddingIndex := service.NewEmbeddingIndexService(cfg, llmConfigRepo, repository.NewEmbeddingRepository(database.DB))
+	embeddingIndex.Start()
+	defer embeddingIndex.Stop()
+
+	// Start post-transcription automation (auto-summary, action items, tags)
+	logger.Startup("post-processing", "Starting post-transcription automation")
+	postProcessing := service.NewPostProcessingService(jobRepo, llmConfigRepo, summaryRepo)
+	postProcessing.Start()
+	defer postProcessing.Stop()
+
+	// Start scheduled backups
+	backups := backup.NewService(cfg, database.DB, version)
+	if cfg.BackupIntervalHours > 0 {
+		logger.Startup("backup", "Scheduling backups", "interval_hours", cfg.BackupIntervalHours, "retention", cfg.BackupRetention)
+	}
+	backups.Start()
+	defer backups.Stop()
+
+	// Enforce job retention policies
+	logger.Startup("retention", "Scheduling retention policy enforcement")
+	retention := service.NewRetentionService(cfg, retentionRepo, jobRepo, noteRepo, fileService, jobDeleter)
+	retention.Start()
+	defer retention.Stop()
+
+	// Remove browser recordings abandoned before being finalized
+	recordingCleanup := service.NewRecordingCleanupService(cfg, recordingRepo, fileService)
+	recordingCleanup.Start()
+	defer recordingCleanup.Stop()
+
+	// Bulk library imports run in the background until cancelled
+	libraryImports := service.NewLibraryImportService(cfg, repository.NewLibraryImportRepository(database.DB), jobRepo, profileRepo, fileService, taskQueue)
+	defer libraryImports.Stop()
+
+	// Deliver scheduled workspace reports
+	logger.Startup("reports", "Starting report scheduler")
+	reportService := service.NewReportService(repository.NewReportRepository(database.DB), fileService, mailer)
+	reportService.Start()
+	defer reportService.Stop()
+
 	// Initialize API handlers
-	handler := api.NewHandler(
-		cfg,
-		authService,
-		userService,
-		fileService,
-		jobRepo,
-		apiKeyRepo,
-		profileRepo,
-		userRepo,
-		llmConfigRepo,
-		summaryRepo,
-		chatRepo,
-		noteRepo,
-		speakerMappingRepo,
-		taskQueue,
-		unifiedProcessor,
-		quickTranscriptionService,
-	)
+	handler := api.NewHandler(cfg, api.Dependencies{
+		AuthService:         authService,
+		UserService:         userService,
+		FileService:         fileService,
+		JobRepo:             jobRepo,
+		APIKeyRepo:          apiKeyRepo,
+		ProfileRepo:         profileRepo,
+		UserRepo:            userRepo,
+		LLMConfigRepo:       llmConfigRepo,
+		SummaryRepo:         summaryRepo,
+		ChatRepo:            chatRepo,
+		NoteRepo:            noteRepo,
+		SpeakerMappingRepo:  speakerMappingRepo,
+		SpeakerRepo:         speakerRepo,
+		TagRepo:             repository.NewTagRepository(database.DB),
+		CollectionRepo:      repository.NewCollectionRepository(database.DB),
+		SearchRepo:          searchRepo,
+		RecordingRepo:       recordingRepo,
+		RetentionRepo:       retentionRepo,
+		AWSTagRouteRepo:     repository.NewAWSTagRouteRepository(database.DB),
+		VocabularyRepo:      vocabularyRepo,
+		CorrectionRuleRepo:  correctionRuleRepo,
+		BenchmarkRepo:       benchmarkRepo,
+		TaskQueue:           taskQueue,
+		UnifiedProcessor:    unifiedProcessor,
+		QuickTranscription:  quickTranscriptionService,
+		URLIngest:           urlIngest,
+		FeedService:         feedService,
+		EmbeddingIndex:      embeddingIndex,
+		Voiceprints:         voiceprints,
+		SpeakerAttributes:   speakerAttributes,
+		Integrity:           integrity,
+		Backups:             backups,
+		JobDeleter:          jobDeleter,
+		Retention:           retention,
+		LibraryImports:      libraryImports,
+		Storage:             storage,
+		ReportService:       reportService,
+		FieldPermissionRepo: repository.NewFieldPermissionRepository(database.DB),
+		AdapterConfigRepo:   adapterConfigRepo,
+	})
 
 	// Set up router
+	api.SetBuildInfo(version, commit, date)
 	router := api.SetupRoutes(handler, authService)
 
 	// Create server
@@ -211,9 +439,88 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Warn("Failed to flush traces", "error", err)
+	}
+
 	logger.Info("Server stopped")
 }
 
+// runWorker runs queued jobs until interrupted, claiming them from the shared
+// database alongside any other worker processes
+func runWorker(cfg *config.Config, processor queue.JobProcessor, eventBus *events.Bus, shutdownTracing func(context.Context) error) {
+	taskQueue := queue.NewTaskQueue(3, processor) // 3 workers
+	taskQueue.SetWorkerID(cfg.WorkerID)
+	taskQueue.SetEvents(eventBus)
+	logger.Startup("queue", "Starting worker", "worker", taskQueue.WorkerID())
+	taskQueue.Start()
+	logger.Info("Scriberr worker is ready", "worker", taskQueue.WorkerID())
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down worker")
+	taskQueue.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Warn("Failed to flush traces", "error", err)
+	}
+	logger.Info("Worker stopped")
+}
+
+// newEventBus creates the bus job lifecycle events are published on, with a
+// notifier for EventBridge, each configured broker and gateway, and email when
+// SMTP is set up
+func newEventBus(cfg *config.Config, s3Processor *transcription.S3JobProcessor, emailNotifier *transcription.EmailNotifier) (*events.Bus, error) {
+	notifiers := []events.Notifier{s3Processor.EventBridgeNotifier()}
+	if emailNotifier != nil {
+		logger.Startup("events", "Emailing job notifications", "smtp_host", cfg.SMTPHost)
+		notifiers = append(notifiers, emailNotifier)
+	}
+	if kafka := events.NewKafkaNotifier(events.KafkaConfig{
+		RESTURL:  cfg.KafkaRESTURL,
+		Topic:    cfg.KafkaTopic,
+		Username: cfg.KafkaUsername,
+		Password: cfg.KafkaPassword,
+	}); kafka != nil {
+		logger.Startup("events", "Publishing job events to Kafka", "topic", cfg.KafkaTopic)
+		notifiers = append(notifiers, kafka)
+	}
+	nats, err := events.NewNATSNotifier(events.NATSConfig{URL: cfg.NATSURL, SubjectPrefix: cfg.NATSSubjectPrefix})
+	if err != nil {
+		return nil, err
+	}
+	if nats != nil {
+		logger.Startup("events", "Publishing job events to NATS", "subjects", cfg.NATSSubjectPrefix+".*")
+		notifiers = append(notifiers, nats)
+	}
+	mqtt, err := events.NewMQTTNotifier(events.MQTTConfig{URL: cfg.MQTTURL, TopicPrefix: cfg.MQTTTopicPrefix, Retain: cfg.MQTTRetain})
+	if err != nil {
+		return nil, err
+	}
+	if mqtt != nil {
+		logger.Startup("events", "Publishing job events to MQTT", "topics", cfg.MQTTTopicPrefix+"/+")
+		notifiers = append(notifiers, mqtt)
+	}
+	apprise, err := events.NewAppriseNotifier(events.AppriseConfig{
+		URL:           cfg.AppriseURL,
+		URLs:          cfg.AppriseURLs,
+		Tag:           cfg.AppriseTag,
+		PublicBaseURL: cfg.PublicBaseURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if apprise != nil {
+		logger.Startup("events", "Announcing finished jobs through Apprise")
+		notifiers = append(notifiers, apprise)
+	}
+	return events.NewBus(notifiers...), nil
+}
+
 func createSystemAPIKey(repo repository.APIKeyRepository) (*models.APIKey, error) {
 	ctx := context.Background()
 	keys, err := repo.ListActive(ctx)
@@ -233,6 +540,7 @@ func createSystemAPIKey(repo repository.APIKeyRepository) (*models.APIKey, error
 		Name:        "System",
 		Description: &des,
 		IsActive:    true,
+		Role:        models.RoleAdmin,
 	}
 
 	if err := repo.Create(ctx, &sysKey); err != nil {
@@ -241,51 +549,3 @@ func createSystemAPIKey(repo repository.APIKeyRepository) (*models.APIKey, error
 
 	return &sysKey, nil
 }
-
-func registerAdapters(cfg *config.Config) {
-	logger.Info("Registering adapters with environment path", "whisperx_env", cfg.WhisperXEnv)
-
-	// Shared environment path for NVIDIA models (NeMo-based)
-	nvidiaEnvPath := filepath.Join(cfg.WhisperXEnv, "parakeet")
-
-	// Dedicated environment path for PyAnnote (to avoid dependency conflicts)
-	pyannoteEnvPath := filepath.Join(cfg.WhisperXEnv, "pyannote")
-
-	// Register transcription adapters
-	whisperx := adapters.NewWhisperXAdapter(cfg.WhisperXEnv)
-	mc, err := modal.NewClient()
-	if err != nil {
-		logger.Warn("Failed to initialize Modal client. Skipping Modal Adapter", "error", err)
-	}
-
-	registry.RegisterTranscriptionAdapter(interfaces.ModalWhisperX, adapters.NewModalAdapter(whisperx, mc))
-	registry.RegisterTranscriptionAdapter(interfaces.RunPodWhisperX, adapters.NewRunPodAdapter(whisperx))
-
-	hasLocalWhisperX := false
-	if localRunpodEndpoint := os.Getenv("LOCAL_WHISPERX_BASE_URL"); localRunpodEndpoint != "" {
-		registry.RegisterTranscriptionAdapter("whisperx", adapters.NewRunPodAdapter(whisperx, adapters.WithRunpodBaseURL(localRunpodEndpoint), adapters.WithRunpodModelFamily(interfaces.LocalWhisperX)))
-		hasLocalWhisperX = true
-	}
-
-	if val := os.Getenv("ENABLE_DEFAULT_ADAPTERS"); val == "" {
-		return
-	}
-
-	if !hasLocalWhisperX {
-		registry.RegisterTranscriptionAdapter("whisperx", whisperx)
-	}
-	registry.RegisterTranscriptionAdapter("parakeet",
-		adapters.NewParakeetAdapter(nvidiaEnvPath))
-	registry.RegisterTranscriptionAdapter("canary",
-		adapters.NewCanaryAdapter(nvidiaEnvPath)) // Shares with Parakeet
-	registry.RegisterTranscriptionAdapter("openai_whisper",
-		adapters.NewOpenAIAdapter(cfg.OpenAIAPIKey))
-
-	// Register diarization adapters
-	registry.RegisterDiarizationAdapter("pyannote",
-		adapters.NewPyAnnoteAdapter(pyannoteEnvPath)) // Dedicated environment
-	registry.RegisterDiarizationAdapter("sortformer",
-		adapters.NewSortformerAdapter(nvidiaEnvPath)) // Shares with Parakeet
-
-	logger.Info("Adapter registration complete")
-}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AdapterConfigRequest changes how an adapter is registered. Omitted fields
+// keep their stored value, or the environment's when there is none.
+type AdapterConfigRequest struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Endpoint is a WhisperX server URL for whisperx, a RunPod endpoint ID or
+	// URL for runpod-whisperx and an app name for modal-whisperx
+	Endpoint *string `json:"endpoint,omitempty"`
+	// APIKey is only returned masked; modal-whisperx takes
+	// "token_id:token_secret". A reference to a secret store, such as
+	// vault://secret/data/runpod#api_key, is fetched and refreshed instead.
+	APIKey *string `json:"api_key,omitempty"`
+}
+
+// adapterConfigError answers an adapter configuration error, reporting whether
+// there was one
+func adapterConfigError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, transcription.ErrAdapterConfigDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Adapter configuration is not enabled"})
+	case errors.Is(err, transcription.ErrUnknownAdapter):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown adapter"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+	return true
+}
+
+// reloadAdapters registers the adapters again after their settings changed
+// and answers with their settings
+func (h *Handler) reloadAdapters(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := h.unifiedProcessor.ReloadAdapters(ctx)
+	if errors.Is(err, transcription.ErrAdapterConfigDisabled) {
+		adapterConfigError(c, err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload adapters"})
+		return
+	}
+	adapters, err := h.unifiedProcessor.ListAdapters(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list adapters"})
+		return
+	}
+	c.JSON(http.StatusOK, adapters)
+}
+
+// @Summary List adapters
+// @Description List the adapters that can be configured, with their settings, where those come from (the environment, or the database once changed through this API) and whether the adapter is registered. API keys are masked; keys referencing a secret store show the reference.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} transcription.AdapterStatus
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/adapters [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListAdapters(c *gin.Context) {
+	adapters, err := h.unifiedProcessor.ListAdapters(c.Request.Context())
+	if errors.Is(err, transcription.ErrAdapterConfigDisabled) {
+		adapterConfigError(c, err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list adapters"})
+		return
+	}
+	c.JSON(http.StatusOK, adapters)
+}
+
+// @Summary Configure an adapter
+// @Description Enable or disable an adapter, or set its endpoint or API key, replacing the environment's settings (ENABLE_DEFAULT_ADAPTERS, LOCAL_WHISPERX_BASE_URL, RUNPOD_ENDPOINT_ID, ...). The adapter is registered again right away; jobs already running finish with the previous settings. Other processes pick the change up within 30 seconds.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Adapter model ID"
+// @Param request body AdapterConfigRequest true "Settings"
+// @Success 200 {array} transcription.AdapterStatus
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/adapters/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateAdapterConfig(c *gin.Context) {
+	var req AdapterConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	config, err := h.adapterConfigRepo.FindByModelID(ctx, c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		config = &models.AdapterConfig{ModelID: c.Param("id"), Enabled: true}
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load adapter settings"})
+		return
+	}
+	if req.Enabled != nil {
+		config.Enabled = *req.Enabled
+	}
+	if req.Endpoint != nil {
+		endpoint := strings.TrimSpace(*req.Endpoint)
+		config.Endpoint = &endpoint
+	}
+	if req.APIKey != nil {
+		key := strings.TrimSpace(*req.APIKey)
+		config.APIKey = &key
+	}
+	if adapterConfigError(c, h.unifiedProcessor.ValidateAdapterConfig(ctx, config)) {
+		return
+	}
+
+	config.UpdatedBy = h.requestAuthor(c)
+	if err := h.adapterConfigRepo.Save(ctx, config); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save adapter settings"})
+		return
+	}
+	logger.Info("Adapter settings changed", "model_id", config.ModelID, "enabled", config.Enabled, "by", config.UpdatedBy)
+	h.reloadAdapters(c)
+}
+
+// @Summary Reset an adapter
+// @Description Remove the settings set through this API, so the adapter is registered with the environment's again
+// @Tags admin
+// @Produce json
+// @Param id path string true "Adapter model ID"
+// @Success 200 {array} transcription.AdapterStatus
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/adapters/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteAdapterConfig(c *gin.Context) {
+	if err := h.adapterConfigRepo.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Adapter has no stored settings"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove adapter settings"})
+		return
+	}
+	h.reloadAdapters(c)
+}
+
+// @Summary Reload adapters
+// @Description Register the adapters again whose stored settings changed since they were registered, for example when edited in the database directly
+// @Tags admin
+// @Produce json
+// @Success 200 {array} transcription.AdapterStatus
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/adapters/reload [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ReloadAdapters(c *gin.Context) {
+	h.reloadAdapters(c)
+}
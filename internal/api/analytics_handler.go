@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageAnalyticsResponse is what transcription did per interval
+type UsageAnalyticsResponse struct {
+	Interval string                `json:"interval"`
+	GroupBy  string                `json:"group_by,omitempty"`
+	From     string                `json:"from"` // first day, inclusive
+	To       string                `json:"to"`   // last day, inclusive
+	Buckets  []models.UsageSummary `json:"buckets"`
+}
+
+// @Summary Usage analytics
+// @Description Report, per day, week (from Monday) or month, the jobs and executions that started, how many failed and the failure rate, the minutes of audio transcribed, how long completed executions took on average, and how many jobs were transcribed in each language, overall or by adapter, profile or submitting user. Replays are not counted.
+// @Tags admin
+// @Produce json
+// @Param interval query string false "Interval: day, week or month" default(day)
+// @Param group_by query string false "Grouping: adapter, profile or user; none by default"
+// @Param from query string false "First day (YYYY-MM-DD, UTC); defaults to 30 days ago"
+// @Param to query string false "Last day (YYYY-MM-DD, UTC); defaults to today"
+// @Success 200 {object} UsageAnalyticsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/analytics [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetUsageAnalytics(c *gin.Context) {
+	interval := c.DefaultQuery("interval", models.IntervalDay)
+	switch interval {
+	case models.IntervalDay, models.IntervalWeek, models.IntervalMonth:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be day, week or month"})
+		return
+	}
+	groupBy := c.Query("group_by")
+	switch groupBy {
+	case "", models.UsageByAdapter, models.UsageByProfile, models.UsageByUser:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be adapter, profile or user"})
+		return
+	}
+	from, to, ok := reportRange(c)
+	if !ok {
+		return
+	}
+
+	buckets, err := h.jobRepo.SummarizeUsage(c.Request.Context(), interval, groupBy, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to summarize usage"})
+		return
+	}
+	if buckets == nil {
+		buckets = []models.UsageSummary{}
+	}
+	c.JSON(http.StatusOK, UsageAnalyticsResponse{
+		Interval: interval,
+		GroupBy:  groupBy,
+		From:     from.Format(time.DateOnly),
+		To:       to.Format(time.DateOnly),
+		Buckets:  buckets,
+	})
+}
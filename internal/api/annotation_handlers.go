@@ -0,0 +1,402 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// mentionPattern matches @username mentions; a trailing full stop ends the mention
+var mentionPattern = regexp.MustCompile(`@([\w-]+(?:\.[\w-]+)*)`)
+
+// CreateAnnotationRequest comments on a segment or time range of a transcript,
+// or replies to a thread with ParentID. Replies share the thread's anchor.
+type CreateAnnotationRequest struct {
+	Content      string   `json:"content" binding:"required"`
+	ParentID     *string  `json:"parent_id,omitempty"`
+	SegmentIndex *int     `json:"segment_index,omitempty"` // defaults the time range to the segment's
+	StartTime    *float64 `json:"start_time,omitempty"`
+	EndTime      *float64 `json:"end_time,omitempty"` // defaults to start_time
+}
+
+// UpdateAnnotationRequest edits a comment or resolves its thread
+type UpdateAnnotationRequest struct {
+	Content  *string `json:"content,omitempty"`
+	Resolved *bool   `json:"resolved,omitempty"`
+}
+
+// AnnotationResponse is an annotation with its mentions and, for threads, replies
+type AnnotationResponse struct {
+	models.Annotation
+	Mentions []string             `json:"mentions"`
+	Replies  []AnnotationResponse `json:"replies,omitempty"`
+}
+
+func newAnnotationResponse(annotation models.Annotation) AnnotationResponse {
+	response := AnnotationResponse{Annotation: annotation, Mentions: []string{}}
+	if annotation.Mentions != nil {
+		_ = json.Unmarshal([]byte(*annotation.Mentions), &response.Mentions)
+	}
+	return response
+}
+
+// mentions reports whether username is mentioned in the thread
+func (a AnnotationResponse) mentions(username string) bool {
+	for _, mention := range a.Mentions {
+		if strings.EqualFold(mention, username) {
+			return true
+		}
+	}
+	for _, reply := range a.Replies {
+		if reply.mentions(username) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestAuthor names the user or API key making the request
+func (h *Handler) requestAuthor(c *gin.Context) string {
+	if username, exists := c.Get("username"); exists {
+		return username.(string)
+	}
+	if key, exists := c.Get("api_key"); exists {
+		if apiKey, err := h.apiKeyRepo.FindByKey(c.Request.Context(), key.(string)); err == nil {
+			return apiKey.Name
+		}
+	}
+	return ""
+}
+
+// parseMentions returns the existing users @mentioned in content, each once
+func (h *Handler) parseMentions(c *gin.Context, content string) []string {
+	mentions := []string{}
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		if seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+		if user, err := h.userRepo.FindByUsername(c.Request.Context(), username); err == nil {
+			mentions = append(mentions, user.Username)
+		}
+	}
+	return mentions
+}
+
+// annotatedJob loads the job in the path
+func (h *Handler) annotatedJob(c *gin.Context) (*models.TranscriptionJob, bool) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transcription"})
+		return nil, false
+	}
+	return job, true
+}
+
+// @Summary List annotations
+// @Description List the comment threads anchored to a transcript, in transcript order, each with its replies. Filter by resolved state, or by threads mentioning a user.
+// @Tags notes
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param resolved query bool false "Only resolved (true) or open (false) threads"
+// @Param mention query string false "Only threads mentioning this username"
+// @Success 200 {array} AnnotationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/annotations [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListAnnotations(c *gin.Context) {
+	var resolved *bool
+	if value := c.Query("resolved"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "resolved must be true or false"})
+			return
+		}
+		resolved = &parsed
+	}
+	mention := strings.TrimPrefix(c.Query("mention"), "@")
+
+	job, ok := h.annotatedJob(c)
+	if !ok {
+		return
+	}
+	annotations, err := h.noteRepo.ListAnnotations(c.Request.Context(), job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch annotations"})
+		return
+	}
+
+	threads := []AnnotationResponse{}
+	index := make(map[string]int)
+	for _, annotation := range annotations {
+		if annotation.ParentID == nil {
+			index[annotation.ID] = len(threads)
+			threads = append(threads, newAnnotationResponse(annotation))
+		}
+	}
+	for _, annotation := range annotations {
+		if annotation.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*annotation.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, newAnnotationResponse(annotation))
+		}
+	}
+
+	filtered := threads[:0]
+	for _, thread := range threads {
+		if resolved != nil && thread.Resolved != *resolved {
+			continue
+		}
+		if mention != "" && !thread.mentions(mention) {
+			continue
+		}
+		filtered = append(filtered, thread)
+	}
+	c.JSON(http.StatusOK, filtered)
+}
+
+// @Summary Create an annotation
+// @Description Comment on a segment (segment_index) or a time range (start_time, end_time) of a transcript, or reply to a thread (parent_id). @username mentions of existing users are recorded.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body CreateAnnotationRequest true "Annotation"
+// @Success 201 {object} AnnotationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/annotations [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateAnnotation(c *gin.Context) {
+	var req CreateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+		return
+	}
+
+	job, ok := h.annotatedJob(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	annotation := &models.Annotation{TranscriptionID: job.ID, Author: h.requestAuthor(c), Content: content}
+
+	switch {
+	case req.ParentID != nil:
+		parent, err := h.noteRepo.FindAnnotation(ctx, job.ID, *req.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Parent annotation not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch annotation"})
+			return
+		}
+		// Replies to a reply join the same thread
+		threadID := parent.ID
+		if parent.ParentID != nil {
+			threadID = *parent.ParentID
+		}
+		annotation.ParentID = &threadID
+		annotation.SegmentIndex = parent.SegmentIndex
+		annotation.StartTime = parent.StartTime
+		annotation.EndTime = parent.EndTime
+
+	case req.SegmentIndex != nil:
+		var transcript struct {
+			Segments []struct {
+				Start float64 `json:"start"`
+				End   float64 `json:"end"`
+			} `json:"segments"`
+		}
+		if job.Transcript == nil || json.Unmarshal([]byte(*job.Transcript), &transcript) != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+			return
+		}
+		if *req.SegmentIndex < 0 || *req.SegmentIndex >= len(transcript.Segments) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "segment_index is out of range"})
+			return
+		}
+		segment := transcript.Segments[*req.SegmentIndex]
+		annotation.SegmentIndex = req.SegmentIndex
+		annotation.StartTime = segment.Start
+		annotation.EndTime = segment.End
+		if req.StartTime != nil {
+			annotation.StartTime = *req.StartTime
+		}
+		if req.EndTime != nil {
+			annotation.EndTime = *req.EndTime
+		}
+
+	case req.StartTime != nil:
+		annotation.StartTime = *req.StartTime
+		annotation.EndTime = *req.StartTime
+		if req.EndTime != nil {
+			annotation.EndTime = *req.EndTime
+		}
+
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An annotation needs a segment_index, a start_time or a parent_id"})
+		return
+	}
+	if annotation.StartTime < 0 || annotation.EndTime < annotation.StartTime {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be >= start_time >= 0"})
+		return
+	}
+
+	mentions, _ := json.Marshal(h.parseMentions(c, content))
+	encoded := string(mentions)
+	annotation.Mentions = &encoded
+	if err := h.noteRepo.CreateAnnotation(ctx, annotation); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create annotation"})
+		return
+	}
+	c.JSON(http.StatusCreated, newAnnotationResponse(*annotation))
+}
+
+// @Summary Update an annotation
+// @Description Edit the content of your own comment, or resolve or reopen a thread
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param annotation_id path string true "Annotation ID"
+// @Param request body UpdateAnnotationRequest true "Changes"
+// @Success 200 {object} AnnotationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/annotations/{annotation_id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateAnnotation(c *gin.Context) {
+	var req UpdateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.Content == nil && req.Resolved == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	annotation, err := h.noteRepo.FindAnnotation(ctx, c.Param("id"), c.Param("annotation_id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Annotation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch annotation"})
+		return
+	}
+	author := h.requestAuthor(c)
+
+	if req.Content != nil {
+		content := strings.TrimSpace(*req.Content)
+		if content == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+			return
+		}
+		if annotation.Author != author {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the author can edit a comment"})
+			return
+		}
+		mentions, _ := json.Marshal(h.parseMentions(c, content))
+		encoded := string(mentions)
+		annotation.Content = content
+		annotation.Mentions = &encoded
+	}
+	if req.Resolved != nil && *req.Resolved != annotation.Resolved {
+		if annotation.ParentID != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only threads can be resolved"})
+			return
+		}
+		annotation.Resolved = *req.Resolved
+		annotation.ResolvedBy = nil
+		annotation.ResolvedAt = nil
+		if annotation.Resolved {
+			now := time.Now()
+			annotation.ResolvedBy = &author
+			annotation.ResolvedAt = &now
+		}
+	}
+
+	if err := h.noteRepo.UpdateAnnotation(ctx, annotation); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update annotation"})
+		return
+	}
+	c.JSON(http.StatusOK, newAnnotationResponse(*annotation))
+}
+
+// @Summary Delete an annotation
+// @Description Delete your own comment, or any comment as an admin; deleting a thread also deletes its replies
+// @Tags notes
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param annotation_id path string true "Annotation ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/annotations/{annotation_id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteAnnotation(c *gin.Context) {
+	ctx := c.Request.Context()
+	annotation, err := h.noteRepo.FindAnnotation(ctx, c.Param("id"), c.Param("annotation_id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Annotation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch annotation"})
+		return
+	}
+	if annotation.Author != h.requestAuthor(c) && !models.RoleAllows(c.GetString("role"), models.RoleAdmin) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author or an admin can delete a comment"})
+		return
+	}
+
+	if err := h.noteRepo.DeleteAnnotation(ctx, annotation.TranscriptionID, annotation.ID); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete annotation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Annotation deleted"})
+}
@@ -6,22 +6,20 @@ import (
 	"path/filepath"
 	"scriberr/internal/database"
 	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/service"
 	"scriberr/pkg/logger"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// @Summary Get audio file
-// @Description Serve the audio file for a transcription job
-// @Tags transcription
-// @Produce audio/mpeg,audio/wav,audio/mp4
-// @Param id path string true "Job ID"
-// @Success 200 {file} binary
-// @Failure 404 {object} map[string]string
-// @Router /api/v1/transcription/{id}/audio [get]
-// @Security ApiKeyAuth
+// GetAudioFileWrapper serves audio stored in S3 through decorated, first
+// downloading it into the job's workspace, or redirects to a presigned URL
+// when redirects are enabled and no transcoding is asked for
 func (h *Handler) GetAudioFileWrapper(decorated gin.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		jobID := c.Param("id")
@@ -41,9 +39,32 @@ func (h *Handler) GetAudioFileWrapper(decorated gin.HandlerFunc) gin.HandlerFunc
 			return
 		}
 
-		// Download audio file from S3
+		redirect := h.config.AudioS3Redirect
+		if value := c.Query("redirect"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "redirect must be true or false"})
+				return
+			}
+			redirect = parsed
+		}
+		if redirect && c.Query("format") == "" {
+			h.redirectToPresignedAudio(c, &job)
+			return
+		}
+
+		// Download audio file from S3 into the job's workspace
+		uploadDir, err := service.WorkspaceDir(h.config.UploadDir, job.Workspace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+			return
+		}
+		if err := h.fileService.CreateDirectory(uploadDir); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+			return
+		}
 		filename := filepath.Base(*job.AudioUri)
-		audioPath := filepath.Join(h.config.UploadDir, filename)
+		audioPath := filepath.Join(uploadDir, filename)
 		if _, err := os.Stat(audioPath); os.IsNotExist(err) {
 			logger.Debug("Downloading audio", "uri", *job.AudioUri, "audio_path", audioPath)
 			err := h.fileService.DownloadFile(c.Request.Context(), *job.AudioUri, audioPath)
@@ -53,8 +74,10 @@ func (h *Handler) GetAudioFileWrapper(decorated gin.HandlerFunc) gin.HandlerFunc
 			}
 		}
 
+		// Caching the audio locally does not change the record, so it is allowed
+		// on finalized jobs
 		job.AudioPath = audioPath
-		if err := h.jobRepo.Update(c.Request.Context(), &job); err != nil {
+		if err := h.jobRepo.Update(repository.WithFinalizedOverride(c.Request.Context()), &job); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update audio path"})
 			return
 		}
@@ -62,3 +85,29 @@ func (h *Handler) GetAudioFileWrapper(decorated gin.HandlerFunc) gin.HandlerFunc
 		decorated(c)
 	}
 }
+
+// redirectToPresignedAudio redirects to a presigned URL of a job's S3 audio, so
+// the file is not downloaded and served through the server. The checks the
+// local path applies are made first, since the URL bypasses them.
+func (h *Handler) redirectToPresignedAudio(c *gin.Context, job *models.TranscriptionJob) {
+	if !models.WorkspaceVisible(c.Request.Context(), job.Workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status == models.StatusQuarantined {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Audio file is quarantined pending review"})
+		return
+	}
+	ttl := time.Duration(h.config.PlaybackURLTTLSeconds) * time.Second
+	if ttl <= 0 || ttl > maxPlaybackURLTTL {
+		ttl = maxPlaybackURLTTL
+	}
+	presigned, err := h.fileService.PresignURL(c.Request.Context(), *job.AudioUri, ttl)
+	if err != nil {
+		logger.Error("Failed to presign audio URL", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create audio URL"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, presigned)
+}
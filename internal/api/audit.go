@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordAudit appends an event about a resource to the audit log on behalf of the
+// request's user or API key. Failures are logged rather than returned, since the
+// operation being recorded has already happened.
+func (h *Handler) recordAudit(c *gin.Context, action, resourceType, resourceID string, details map[string]interface{}) {
+	h.recordAuditEvent(c, action, resourceType, resourceID, details, 0)
+	c.Set(auditRecordedKey, true)
+}
+
+// recordAuditEvent appends an event with the request's metadata and, once the
+// request has been handled, its response status
+func (h *Handler) recordAuditEvent(c *gin.Context, action, resourceType, resourceID string, details map[string]interface{}, status int) {
+	actor := h.requestAuthor(c)
+	if actor == "" {
+		actor = "anonymous"
+	}
+	event := models.AuditEvent{
+		Action:         action,
+		Actor:          actor,
+		ResourceType:   resourceType,
+		ResourceID:     resourceID,
+		OrganizationID: c.GetString("organization_id"),
+		ClientIP:       c.ClientIP(),
+		Method:         c.Request.Method,
+		Path:           truncateAuditField(c.Request.URL.Path, 255),
+		Status:         status,
+		UserAgent:      truncateAuditField(c.Request.UserAgent(), 255),
+	}
+	if len(details) > 0 {
+		if data, err := json.Marshal(details); err == nil {
+			encoded := string(data)
+			event.Details = &encoded
+		}
+	}
+	if err := database.DB.WithContext(c.Request.Context()).Create(&event).Error; err != nil {
+		logger.Error("Failed to record audit event", "action", action, "resource_id", resourceID, "error", err)
+	}
+}
+
+// truncateAuditField cuts a request field to the length of its audit column
+func truncateAuditField(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// auditRecordedKey marks a request whose handler recorded its own audit event
+const auditRecordedKey = "audit_recorded"
+
+// auditActions names the audited routes; other mutating routes are recorded as
+// "<resource>.created", "<resource>.updated", "<resource>.deleted", or after
+// the action at the end of their path
+var auditActions = map[string]string{
+	"POST /api/v1/auth/register":                           models.AuditUserCreated,
+	"POST /api/v1/auth/change-password":                    models.AuditPasswordChanged,
+	"POST /api/v1/auth/change-username":                    models.AuditUsernameChanged,
+	"POST /api/v1/api-keys/":                               models.AuditAPIKeyCreated,
+	"DELETE /api/v1/api-keys/:id":                          models.AuditAPIKeyDeleted,
+	"POST /api/v1/users":                                   models.AuditUserCreated,
+	"PUT /api/v1/users/:id":                                models.AuditUserUpdated,
+	"DELETE /api/v1/users/:id":                             models.AuditUserDeleted,
+	"POST /api/v1/transcription/upload":                    models.AuditJobCreated,
+	"POST /api/v1/transcription/upload-video":              models.AuditJobCreated,
+	"POST /api/v1/transcription/upload-multitrack":         models.AuditJobCreated,
+	"POST /api/v1/transcription/multitrack":                models.AuditJobCreated,
+	"POST /api/v1/transcription/youtube":                   models.AuditJobCreated,
+	"POST /api/v1/transcription/url":                       models.AuditJobCreated,
+	"POST /api/v1/transcription/submit":                    models.AuditJobCreated,
+	"POST /api/v1/transcription/aws-transcribe":            models.AuditJobCreated,
+	"DELETE /api/v1/transcription/:id":                     models.AuditJobDeleted,
+	"PUT /api/v1/transcription/:id/title":                  models.AuditTranscriptEdited,
+	"PUT /api/v1/transcription/:id/recorded-at":            models.AuditTranscriptEdited,
+	"POST /api/v1/transcription/:id/speakers":              models.AuditTranscriptEdited,
+	"POST /api/v1/transcription/:id/speakers/identify":     models.AuditTranscriptEdited,
+	"PUT /api/v1/transcription/:id/review/segments/:index": models.AuditTranscriptEdited,
+}
+
+// auditSkippedRoutes change no stored data beyond sessions, or only compute
+// results, so they are left out of the audit log
+var auditSkippedRoutes = map[string]bool{
+	"POST /api/v1/auth/login":                   true,
+	"POST /api/v1/auth/refresh":                 true,
+	"POST /api/v1/auth/logout":                  true,
+	"POST /api/v1/transcription/custody/verify": true,
+	"POST /api/v1/correction-rules/preview":     true,
+}
+
+// auditResourceTypes maps route groups to the resource types they are recorded as
+var auditResourceTypes = map[string]string{
+	"api-keys": "api_key",
+	"users":    "user",
+	"auth":     "user",
+}
+
+// AuditMutations records every successful mutating request in the audit log,
+// unless its handler recorded a more specific event itself. It runs before
+// authentication, so it reads the actor once the request has been handled.
+func (h *Handler) AuditMutations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		route := c.Request.Method + " " + middleware.RouteKey(c)
+		if c.FullPath() == "" || auditSkippedRoutes[route] {
+			c.Next()
+			return
+		}
+
+		// The IDs of created resources are only in the response
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if c.GetBool(auditRecordedKey) || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		resourceType, action := auditRoute(c.Request.Method, middleware.RouteKey(c))
+		if named, ok := auditActions[route]; ok {
+			action = named
+		}
+		resourceID := c.Param("id")
+		if resourceID == "" {
+			resourceID = writer.createdID()
+		}
+		details := map[string]interface{}{}
+		for _, param := range c.Params {
+			if param.Key != "id" {
+				details[param.Key] = param.Value
+			}
+		}
+		h.recordAuditEvent(c, action, resourceType, resourceID, details, c.Writer.Status())
+	}
+}
+
+// auditRoute derives a resource type and action from a route. The resource is
+// the route group; the action is the verb of the method, qualified by the last
+// static segment below it ("annotations_deleted"), or that segment itself when
+// a POST ends in it ("start", "finalize").
+func auditRoute(method, fullPath string) (string, string) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(fullPath, "/api/v1/"), "/"), "/")
+	resourceType := strings.ReplaceAll(segments[0], "-", "_")
+	if mapped, ok := auditResourceTypes[segments[0]]; ok {
+		resourceType = mapped
+	}
+
+	verb := map[string]string{
+		http.MethodPost:   "created",
+		http.MethodPut:    "updated",
+		http.MethodPatch:  "updated",
+		http.MethodDelete: "deleted",
+	}[method]
+	for i := len(segments) - 1; i > 0; i-- {
+		if strings.HasPrefix(segments[i], ":") {
+			continue
+		}
+		action := strings.ReplaceAll(segments[i], "-", "_")
+		if method == http.MethodPost && i == len(segments)-1 {
+			verb = action
+		} else {
+			verb = action + "_" + verb
+		}
+		break
+	}
+	return resourceType, resourceType + "." + verb
+}
+
+// auditResponseWriter keeps the start of a response, where the ID of a created
+// resource is found
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	head []byte
+}
+
+// auditResponseHead is how much of a response is kept to find a created ID
+const auditResponseHead = 8 << 10
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditResponseWriter) keep(data []byte) {
+	if room := auditResponseHead - len(w.head); room > 0 {
+		if len(data) > room {
+			data = data[:room]
+		}
+		w.head = append(w.head, data...)
+	}
+}
+
+// createdID returns the id field of a JSON response, if any
+func (w *auditResponseWriter) createdID() string {
+	var body struct {
+		ID interface{} `json:"id"`
+	}
+	if err := json.Unmarshal(w.head, &body); err != nil || body.ID == nil {
+		return ""
+	}
+	switch id := body.ID.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AuditEventsResponse is a page of the audit log, newest first
+type AuditEventsResponse struct {
+	Events []models.AuditEvent `json:"events"`
+	Total  int64               `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+// auditQuery filters the audit log by the request's query parameters,
+// answering 400 if a time is not RFC 3339
+func auditQuery(c *gin.Context) (*gorm.DB, bool) {
+	query := database.DB.WithContext(c.Request.Context()).Model(&models.AuditEvent{})
+	for param, column := range map[string]string{
+		"actor":           "actor",
+		"action":          "action",
+		"resource_type":   "resource_type",
+		"resource_id":     "resource_id",
+		"organization_id": "organization_id",
+	} {
+		if value := c.Query(param); value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	for param, condition := range map[string]string{"since": "created_at >= ?", "until": "created_at < ?"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC 3339 time"})
+			return nil, false
+		}
+		query = query.Where(condition, t)
+	}
+	return query, true
+}
+
+// @Summary List audit events
+// @Description List who did what, newest first: every successful change made through the API (jobs created, transcripts edited, keys created, users deleted, ...) with the actor, client IP, user agent, and route
+// @Tags admin
+// @Produce json
+// @Param actor query string false "Username or API key name"
+// @Param action query string false "Action, e.g. job.created"
+// @Param resource_type query string false "Resource type, e.g. transcription"
+// @Param resource_id query string false "Resource ID"
+// @Param organization_id query string false "Organization ID"
+// @Param since query string false "Only events at or after this RFC 3339 time"
+// @Param until query string false "Only events before this RFC 3339 time"
+// @Param limit query int false "Maximum number of events (1-1000)" default(100)
+// @Param offset query int false "Events to skip" default(0)
+// @Success 200 {object} AuditEventsResponse
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/admin/audit [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListAuditEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+		return
+	}
+	query, ok := auditQuery(c)
+	if !ok {
+		return
+	}
+
+	response := AuditEventsResponse{Events: []models.AuditEvent{}, Limit: limit, Offset: offset}
+	if err := query.Count(&response.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit events"})
+		return
+	}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&response.Events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit events"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Export audit log
+// @Description Download the audit events matching the filters as JSON Lines, oldest first, one event per line
+// @Tags admin
+// @Produce application/x-ndjson
+// @Param actor query string false "Username or API key name"
+// @Param action query string false "Action, e.g. job.created"
+// @Param resource_type query string false "Resource type, e.g. transcription"
+// @Param resource_id query string false "Resource ID"
+// @Param organization_id query string false "Organization ID"
+// @Param since query string false "Only events at or after this RFC 3339 time"
+// @Param until query string false "Only events before this RFC 3339 time"
+// @Success 200 {string} string "JSON Lines"
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/admin/audit/export [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportAuditEvents(c *gin.Context) {
+	query, ok := auditQuery(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s.jsonl", time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	// Events are written in batches, in the order they were recorded, so large
+	// logs are never held in memory
+	encoder := json.NewEncoder(c.Writer)
+	var batch []models.AuditEvent
+	err := query.FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := encoder.Encode(&batch[i]); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	}).Error
+	if err != nil {
+		// The response has started, so the client sees a truncated file
+		logger.Error("Failed to export audit log", "error", err)
+	}
+}
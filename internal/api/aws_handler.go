@@ -2,33 +2,51 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"scriberr/internal/models"
+	"scriberr/internal/service"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/middleware"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/transcribe"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// AWSTranscribeJobRequest is an AWS Transcribe StartTranscriptionJob request,
+// with Scriberr parameters replacing the profile's
+type AWSTranscribeJobRequest struct {
+	transcribe.StartTranscriptionJobInput
+	// ParameterOverrides are job parameters, named as in profiles, e.g.
+	// {"model": "large-v3"}; they are applied after the request's settings
+	ParameterOverrides map[string]interface{} `json:"ParameterOverrides,omitempty"`
+}
+
 // @Summary Submit AWS transcribe compatible job
-// @Description Submit AWS transcribe compatible job
+// @Description Submit AWS transcribe compatible job. The highest-priority tag route matching the job's Tags chooses its profile, output bucket and monthly quota; jobs matching none are transcribed with the default profile. Settings.VocabularyName and Settings.VocabularyFilterName name a custom vocabulary and vocabulary filter; VocabularyFilterMethod is remove (the default), mask or tag. ParameterOverrides replaces parameters of the profile for this job only, and is checked against the schema of the adapter it selects.
 // @Tags config
 // @Accept json
 // @Produce json
-// @Param request body transcribe.StartTranscriptionJobInput true "API Key"
+// @Param request body AWSTranscribeJobRequest true "API Key"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/transcription/aws-transcribe [post]
 // @Security ApiKeyAuth
 // @Security BearerAuth
 func (h *Handler) SubmitAWSTranscribeJob(c *gin.Context) {
-	var req transcribe.StartTranscriptionJobInput
+	var req AWSTranscribeJobRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
 		return
@@ -36,13 +54,40 @@ func (h *Handler) SubmitAWSTranscribeJob(c *gin.Context) {
 
 	if req.Media == nil || req.Media.MediaFileUri == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Media.MediaFileUri is required"})
+		return
 	}
 
-	profile := h.getDefaultProfile(c.Request.Context())
+	route, err := h.matchAWSTagRoute(c.Request.Context(), req.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to match tag routes"})
+		return
+	}
+
+	var profile *models.TranscriptionProfile
+	if route != nil && route.ProfileID != nil {
+		if profile, err = h.profileRepo.FindByID(c.Request.Context(), *route.ProfileID); err != nil {
+			logger.Error("Tag route profile not found", "route_id", route.ID, "profile_id", *route.ProfileID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job. Profile of tag route " + route.Name + " not found."})
+			return
+		}
+	} else {
+		profile = h.getDefaultProfile(c.Request.Context())
+	}
 	if profile == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job. Default profile not found."})
 		return
 	}
+	if route != nil && route.MonthlyQuotaMinutes > 0 {
+		err := service.CheckQuota(c.Request.Context(), h.jobRepo, route.QuotaKey(), route.MonthlyQuotaMinutes)
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			quotaExceeded(c)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check quota"})
+			return
+		}
+	}
 
 	mediaURI := *req.Media.MediaFileUri
 	params := profile.Parameters
@@ -52,6 +97,13 @@ func (h *Handler) SubmitAWSTranscribeJob(c *gin.Context) {
 	}
 
 	params.Diarize = true
+	if req.Settings != nil && !h.applyAWSVocabularySettings(c, req.Settings.VocabularyName, req.Settings.VocabularyFilterName, string(req.Settings.VocabularyFilterMethod), &params) {
+		return
+	}
+	overrides, ok := h.applyParameterOverrides(c, &params, req.ParameterOverrides)
+	if !ok {
+		return
+	}
 	var tags *string
 	if len(req.Tags) > 0 {
 		bytes, err := json.Marshal(req.Tags)
@@ -63,15 +115,25 @@ func (h *Handler) SubmitAWSTranscribeJob(c *gin.Context) {
 	}
 
 	job := models.TranscriptionJob{
-		ID:               uuid.New().String(),
-		AudioPath:        mediaURI,
-		AudioUri:         &mediaURI,
-		Title:            req.TranscriptionJobName,
-		OutputBucketName: req.OutputBucketName,
-		Parameters:       params,
-		Diarization:      params.Diarize,
-		Tags:             tags,
-		Status:           models.StatusPending,
+		ID:                 uuid.New().String(),
+		AudioPath:          mediaURI,
+		AudioUri:           &mediaURI,
+		Title:              req.TranscriptionJobName,
+		OutputBucketName:   req.OutputBucketName,
+		Parameters:         params,
+		Diarization:        params.Diarize,
+		Tags:               tags,
+		Status:             models.StatusPending,
+		Workspace:          h.requestWorkspace(c),
+		ProfileID:          &profile.ID,
+		ProfileVersion:     &profile.Version,
+		ParameterOverrides: overrides,
+	}
+	if route != nil {
+		job.AWSTagRouteID = &route.ID
+		if route.OutputBucketName != nil {
+			job.OutputBucketName = route.OutputBucketName
+		}
 	}
 
 	if err := h.jobRepo.Create(c.Request.Context(), &job); err != nil {
@@ -80,7 +142,7 @@ func (h *Handler) SubmitAWSTranscribeJob(c *gin.Context) {
 	}
 
 	// Enqueue the job for transcription
-	if err := h.taskQueue.EnqueueJob(job.ID); err != nil {
+	if err := h.enqueueJob(c.Request.Context(), job.ID); err != nil {
 		logger.Error("Failed to enqueue job", "job_id", job.ID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue job"})
 		return
@@ -96,6 +158,9 @@ func (h *Handler) SubmitAWSTranscribeJob(c *gin.Context) {
 
 		"Tags": req.Tags,
 	}
+	if route != nil {
+		result["TagRoute"] = route.Name
+	}
 
 	if job.OutputBucketName != nil {
 		result["OutputBucketName"] = *job.OutputBucketName
@@ -117,3 +182,338 @@ func (h *Handler) getDefaultProfile(ctx context.Context) *models.TranscriptionPr
 
 	return profile
 }
+
+// matchAWSTagRoute returns the highest-priority route of the request's
+// organization matching tags, or nil if none does
+func (h *Handler) matchAWSTagRoute(ctx context.Context, tags []types.Tag) (*models.AWSTagRoute, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil {
+			values[*tag.Key] = aws.ToString(tag.Value)
+		}
+	}
+	routes, err := h.awsTagRouteRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range routes {
+		if routes[i].Matches(values) {
+			return &routes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Job statuses of the AWS Transcribe API
+const (
+	awsStatusQueued     = "QUEUED"
+	awsStatusInProgress = "IN_PROGRESS"
+	awsStatusFailed     = "FAILED"
+	awsStatusCompleted  = "COMPLETED"
+)
+
+// awsStatuses maps AWS Transcribe job statuses to the job statuses they cover
+var awsStatuses = map[string][]models.JobStatus{
+	awsStatusQueued:     {models.StatusUploaded, models.StatusDownloading, models.StatusPending},
+	awsStatusInProgress: {models.StatusProcessing},
+	awsStatusFailed:     {models.StatusFailed, models.StatusQuarantined, models.StatusCancelled},
+	awsStatusCompleted:  {models.StatusCompleted},
+}
+
+// awsStatus returns the AWS Transcribe status of a job status
+func awsStatus(status models.JobStatus) string {
+	for awsStatus, statuses := range awsStatuses {
+		for _, s := range statuses {
+			if s == status {
+				return awsStatus
+			}
+		}
+	}
+	return awsStatusQueued
+}
+
+// Presigned transcript URLs, like those of AWS Transcribe, expire after 15 minutes
+const awsTranscriptURLTTL = 15 * time.Minute
+
+// Page sizes of ListTranscriptionJobs, as in AWS Transcribe
+const (
+	awsDefaultMaxResults = 5
+	awsMaxResults        = 100
+)
+
+// AWSMedia locates a job's audio
+type AWSMedia struct {
+	MediaFileUri *string `json:"MediaFileUri,omitempty"`
+}
+
+// AWSTranscript locates a completed job's transcript
+type AWSTranscript struct {
+	TranscriptFileUri *string `json:"TranscriptFileUri,omitempty"`
+}
+
+// AWSSettings are the custom vocabulary and vocabulary filter a job was submitted with
+type AWSSettings struct {
+	VocabularyName         *string `json:"VocabularyName,omitempty"`
+	VocabularyFilterName   *string `json:"VocabularyFilterName,omitempty"`
+	VocabularyFilterMethod *string `json:"VocabularyFilterMethod,omitempty"`
+}
+
+// AWSTranscriptionJob mirrors the TranscriptionJob of AWS Transcribe's
+// GetTranscriptionJob, with the job's ID as TranscriptionJobID
+type AWSTranscriptionJob struct {
+	TranscriptionJobID     string         `json:"TranscriptionJobID"`
+	TranscriptionJobName   string         `json:"TranscriptionJobName"`
+	TranscriptionJobStatus string         `json:"TranscriptionJobStatus"`
+	LanguageCode           *string        `json:"LanguageCode,omitempty"`
+	Media                  *AWSMedia      `json:"Media,omitempty"`
+	Transcript             *AWSTranscript `json:"Transcript,omitempty"`
+	Settings               *AWSSettings   `json:"Settings,omitempty"`
+	FailureReason          *string        `json:"FailureReason,omitempty"`
+	CreationTime           time.Time      `json:"CreationTime"`
+	CompletionTime         *time.Time     `json:"CompletionTime,omitempty"`
+	Tags                   []types.Tag    `json:"Tags,omitempty"`
+}
+
+// AWSTranscriptionJobSummary mirrors an entry of AWS Transcribe's
+// ListTranscriptionJobs
+type AWSTranscriptionJobSummary struct {
+	TranscriptionJobID     string     `json:"TranscriptionJobID"`
+	TranscriptionJobName   string     `json:"TranscriptionJobName"`
+	TranscriptionJobStatus string     `json:"TranscriptionJobStatus"`
+	LanguageCode           *string    `json:"LanguageCode,omitempty"`
+	FailureReason          *string    `json:"FailureReason,omitempty"`
+	OutputLocationType     string     `json:"OutputLocationType"` // CUSTOMER_BUCKET or SERVICE_BUCKET
+	CreationTime           time.Time  `json:"CreationTime"`
+	CompletionTime         *time.Time `json:"CompletionTime,omitempty"`
+}
+
+// AWSListTranscriptionJobsResponse mirrors AWS Transcribe's ListTranscriptionJobs output
+type AWSListTranscriptionJobsResponse struct {
+	Status                    *string                      `json:"Status,omitempty"`
+	NextToken                 *string                      `json:"NextToken,omitempty"`
+	TranscriptionJobSummaries []AWSTranscriptionJobSummary `json:"TranscriptionJobSummaries"`
+}
+
+// newAWSTranscriptionJobSummary describes a job as ListTranscriptionJobs does
+func newAWSTranscriptionJobSummary(job *models.TranscriptionJob) AWSTranscriptionJobSummary {
+	summary := AWSTranscriptionJobSummary{
+		TranscriptionJobID:     job.ID,
+		TranscriptionJobName:   getAWSJobName(job),
+		TranscriptionJobStatus: awsStatus(job.Status),
+		LanguageCode:           job.Parameters.Language,
+		OutputLocationType:     "SERVICE_BUCKET",
+		CreationTime:           job.CreatedAt,
+	}
+	if job.OutputBucketName != nil {
+		summary.OutputLocationType = "CUSTOMER_BUCKET"
+	}
+	if summary.TranscriptionJobStatus == awsStatusCompleted || summary.TranscriptionJobStatus == awsStatusFailed {
+		completedAt := job.UpdatedAt
+		summary.CompletionTime = &completedAt
+	}
+	if summary.TranscriptionJobStatus == awsStatusFailed {
+		summary.FailureReason = job.ErrorMessage
+	}
+	return summary
+}
+
+// getAWSJobName returns the job's TranscriptionJobName, or its ID for jobs
+// submitted without one
+func getAWSJobName(job *models.TranscriptionJob) string {
+	if job.Title != nil && *job.Title != "" {
+		return *job.Title
+	}
+	return job.ID
+}
+
+// findAWSTranscribeJob finds the job named in the path, by ID or else by
+// TranscriptionJobName, responding 404 when the request cannot see one
+func (h *Handler) findAWSTranscribeJob(c *gin.Context) (*models.TranscriptionJob, bool) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	job, err := h.jobRepo.FindByID(ctx, name)
+	if err != nil || !models.WorkspaceVisible(ctx, job.Workspace) {
+		job, err = h.jobRepo.FindByTitle(ctx, name)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "NotFoundException: The requested job couldn't be found."})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return nil, false
+	}
+	return job, true
+}
+
+// @Summary Get AWS transcribe compatible job
+// @Description Get a job as AWS Transcribe's GetTranscriptionJob does, by its ID or TranscriptionJobName (the newest job of that name). Statuses are QUEUED, IN_PROGRESS, FAILED (with FailureReason) and COMPLETED. Transcript.TranscriptFileUri of completed jobs is a presigned URL, valid for 15 minutes, of the transcript in the output bucket, or else the job's transcript endpoint.
+// @Tags config
+// @Produce json
+// @Param name path string true "Job ID or TranscriptionJobName"
+// @Success 200 {object} map[string]interface{} "TranscriptionJob: AWSTranscriptionJob"
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/aws-transcribe/{name} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetAWSTranscribeJob(c *gin.Context) {
+	found, ok := h.findAWSTranscribeJob(c)
+	if !ok {
+		return
+	}
+	job, err := h.newJobFieldFilter(c).job(found)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	summary := newAWSTranscriptionJobSummary(job)
+	result := AWSTranscriptionJob{
+		TranscriptionJobID:     summary.TranscriptionJobID,
+		TranscriptionJobName:   summary.TranscriptionJobName,
+		TranscriptionJobStatus: summary.TranscriptionJobStatus,
+		LanguageCode:           summary.LanguageCode,
+		FailureReason:          summary.FailureReason,
+		CreationTime:           summary.CreationTime,
+		CompletionTime:         summary.CompletionTime,
+	}
+	if job.AudioUri != nil {
+		result.Media = &AWSMedia{MediaFileUri: job.AudioUri}
+	}
+	if job.Parameters.VocabularyName != nil || job.Parameters.VocabularyFilterName != nil {
+		result.Settings = &AWSSettings{
+			VocabularyName:         job.Parameters.VocabularyName,
+			VocabularyFilterName:   job.Parameters.VocabularyFilterName,
+			VocabularyFilterMethod: job.Parameters.VocabularyFilterMethod,
+		}
+	}
+	if job.Tags != nil {
+		// Jobs tagged by the auto-tagger store a map instead; they have no AWS tags
+		_ = json.Unmarshal([]byte(*job.Tags), &result.Tags)
+	}
+	// The transcript is left out when the request's role may not see it
+	if result.TranscriptionJobStatus == awsStatusCompleted && job.Transcript != nil {
+		transcriptURI := h.requestBaseURL(c) + "/api/v1/transcription/" + job.ID + "/transcript"
+		if job.OutputBucketName != nil {
+			objectURI := "s3://" + *job.OutputBucketName + "/" + service.TranscriptObjectName(job)
+			if presigned, err := h.fileService.PresignURL(c.Request.Context(), objectURI, awsTranscriptURLTTL); err == nil {
+				transcriptURI = presigned
+			} else {
+				logger.Warn("Failed to presign transcript URL", "job_id", job.ID, "error", err)
+				transcriptURI = objectURI
+			}
+		}
+		result.Transcript = &AWSTranscript{TranscriptFileUri: &transcriptURI}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"TranscriptionJob": result})
+}
+
+// @Summary List AWS transcribe compatible jobs
+// @Description List jobs, newest first, as AWS Transcribe's ListTranscriptionJobs does. Pass the NextToken of a response to get the next page.
+// @Tags config
+// @Produce json
+// @Param Status query string false "QUEUED, IN_PROGRESS, FAILED or COMPLETED"
+// @Param JobNameContains query string false "Only jobs whose TranscriptionJobName contains this"
+// @Param MaxResults query int false "Jobs per page, up to 100" default(5)
+// @Param NextToken query string false "Token of the page to get"
+// @Success 200 {object} AWSListTranscriptionJobsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/aws-transcribe [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListAWSTranscribeJobs(c *gin.Context) {
+	var statuses []models.JobStatus
+	status := c.Query("Status")
+	if status != "" {
+		var ok bool
+		if statuses, ok = awsStatuses[status]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "BadRequestException: Status must be QUEUED, IN_PROGRESS, FAILED or COMPLETED"})
+			return
+		}
+	}
+	maxResults := awsDefaultMaxResults
+	if value := c.Query("MaxResults"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > awsMaxResults {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "BadRequestException: MaxResults must be between 1 and 100"})
+			return
+		}
+		maxResults = n
+	}
+	offset := 0
+	if token := c.Query("NextToken"); token != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(token)
+		if err == nil {
+			offset, err = strconv.Atoi(string(decoded))
+		}
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "BadRequestException: Invalid NextToken"})
+			return
+		}
+	}
+
+	// One more job than asked for tells whether there is a next page
+	jobs, err := h.jobRepo.ListByStatusAndTitle(c.Request.Context(), statuses, c.Query("JobNameContains"), offset, maxResults+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	response := AWSListTranscriptionJobsResponse{TranscriptionJobSummaries: []AWSTranscriptionJobSummary{}}
+	if status != "" {
+		response.Status = &status
+	}
+	if len(jobs) > maxResults {
+		jobs = jobs[:maxResults]
+		next := base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset + maxResults)))
+		response.NextToken = &next
+	}
+	for i := range jobs {
+		response.TranscriptionJobSummaries = append(response.TranscriptionJobSummaries, newAWSTranscriptionJobSummary(&jobs[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Delete AWS transcribe compatible job
+// @Description Delete a job, by its ID or TranscriptionJobName (the newest job of that name), with its files, as AWS Transcribe's DeleteTranscriptionJob does. Jobs in progress cannot be deleted.
+// @Tags config
+// @Produce json
+// @Param name path string true "Job ID or TranscriptionJobName"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/aws-transcribe/{name} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteAWSTranscribeJob(c *gin.Context) {
+	job, ok := h.findAWSTranscribeJob(c)
+	if !ok {
+		return
+	}
+	if job.Status == models.StatusProcessing {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot delete job that is currently processing"})
+		return
+	}
+	// Finalized transcripts are only deleted by admins, as through DELETE /transcription/{id}
+	if job.FinalizedAt != nil && c.GetString("role") != models.RoleAdmin {
+		c.JSON(http.StatusConflict, gin.H{"error": finalizedMessage})
+		return
+	}
+
+	if err := h.jobDeleter.Delete(c.Request.Context(), job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job: " + err.Error()})
+		return
+	}
+	if job.FinalizedAt != nil {
+		h.recordAudit(c, models.AuditFinalizedJobModified, "transcription", job.ID, map[string]interface{}{"route": c.Request.Method + " " + middleware.RouteKey(c)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
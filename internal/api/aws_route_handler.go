@@ -0,0 +1,174 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AWSTagRouteRequest creates or replaces an AWS tag route
+type AWSTagRouteRequest struct {
+	Name                string  `json:"name" binding:"required"`
+	TagKey              string  `json:"tag_key" binding:"required"`
+	TagValue            string  `json:"tag_value,omitempty"`
+	Priority            int     `json:"priority"`
+	ProfileID           *string `json:"profile_id,omitempty"`
+	OutputBucketName    *string `json:"output_bucket_name,omitempty"`
+	MonthlyQuotaMinutes int     `json:"monthly_quota_minutes" binding:"min=0"`
+}
+
+// bindAWSTagRoute validates a request into route, answering 400 if it is invalid
+func (h *Handler) bindAWSTagRoute(c *gin.Context, route *models.AWSTagRoute) bool {
+	var req AWSTagRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return false
+	}
+	if req.ProfileID != nil && *req.ProfileID == "" {
+		req.ProfileID = nil
+	}
+	if req.ProfileID != nil {
+		if _, err := h.profileRepo.FindByID(c.Request.Context(), *req.ProfileID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+			return false
+		}
+	}
+	if req.OutputBucketName != nil {
+		if bucket := strings.TrimSpace(*req.OutputBucketName); bucket != "" {
+			req.OutputBucketName = &bucket
+		} else {
+			req.OutputBucketName = nil
+		}
+	}
+
+	route.Name = strings.TrimSpace(req.Name)
+	route.TagKey = strings.TrimSpace(req.TagKey)
+	route.TagValue = req.TagValue
+	route.Priority = req.Priority
+	route.ProfileID = req.ProfileID
+	route.OutputBucketName = req.OutputBucketName
+	route.MonthlyQuotaMinutes = req.MonthlyQuotaMinutes
+	return true
+}
+
+// awsTagRoute loads the route named in the path, answering 404 if there is none
+func (h *Handler) awsTagRoute(c *gin.Context) (*models.AWSTagRoute, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid route ID"})
+		return nil, false
+	}
+	route, err := h.awsTagRouteRepo.FindByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag route not found"})
+		return nil, false
+	}
+	return route, true
+}
+
+// @Summary List AWS tag routes
+// @Description List the tag routes of the AWS Transcribe compatible endpoint for the request's organization, highest priority first, with the minutes each route's jobs transcribed this month
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.AWSTagRoute
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/aws-tag-routes [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListAWSTagRoutes(c *gin.Context) {
+	ctx := c.Request.Context()
+	routes, err := h.awsTagRouteRepo.List(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tag routes"})
+		return
+	}
+	month := models.QuotaMonth(time.Now())
+	for i := range routes {
+		used, err := h.jobRepo.FindQuotaUsage(ctx, routes[i].QuotaKey(), month)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read tag route usage"})
+			return
+		}
+		routes[i].UsedMinutes = used / 60
+	}
+	c.JSON(http.StatusOK, routes)
+}
+
+// @Summary Create an AWS tag route
+// @Description Route jobs submitted through the AWS Transcribe compatible endpoint with tag tag_key (equal to tag_value, if given) to a profile and output bucket, and cap the minutes they may transcribe per month. When several routes match a job, the one with the highest priority applies.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body AWSTagRouteRequest true "Route"
+// @Success 201 {object} models.AWSTagRoute
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/admin/aws-tag-routes [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateAWSTagRoute(c *gin.Context) {
+	route := &models.AWSTagRoute{
+		OrganizationID: c.GetString("organization_id"),
+		CreatedBy:      h.requestAuthor(c),
+	}
+	if !h.bindAWSTagRoute(c, route) {
+		return
+	}
+	if err := h.awsTagRouteRepo.Create(c.Request.Context(), route); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag route"})
+		return
+	}
+	c.JSON(http.StatusCreated, route)
+}
+
+// @Summary Update an AWS tag route
+// @Description Replace an AWS tag route. Its usage this month is kept.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Route ID"
+// @Param request body AWSTagRouteRequest true "Route"
+// @Success 200 {object} models.AWSTagRoute
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/aws-tag-routes/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateAWSTagRoute(c *gin.Context) {
+	route, ok := h.awsTagRoute(c)
+	if !ok {
+		return
+	}
+	if !h.bindAWSTagRoute(c, route) {
+		return
+	}
+	if err := h.awsTagRouteRepo.Update(c.Request.Context(), route); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag route"})
+		return
+	}
+	c.JSON(http.StatusOK, route)
+}
+
+// @Summary Delete an AWS tag route
+// @Tags admin
+// @Param id path int true "Route ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/aws-tag-routes/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteAWSTagRoute(c *gin.Context) {
+	route, ok := h.awsTagRoute(c)
+	if !ok {
+		return
+	}
+	if err := h.awsTagRouteRepo.Delete(c.Request.Context(), route.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag route"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
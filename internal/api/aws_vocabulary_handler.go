@@ -0,0 +1,515 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// awsVocabularyName is the pattern of AWS Transcribe vocabulary and filter names
+var awsVocabularyName = regexp.MustCompile(`^[0-9a-zA-Z._-]{1,200}$`)
+
+// Vocabularies are usable as soon as they are saved, unlike in AWS Transcribe
+// where they are built in the background
+const awsVocabularyStateReady = "READY"
+
+// AWSVocabularyRequest creates or replaces a custom vocabulary, as AWS
+// Transcribe's CreateVocabulary and UpdateVocabulary do. Updates take the name
+// from the path. Corrections, which AWS Transcribe does not have, map known
+// misspellings to the phrase they are replaced with in transcripts.
+type AWSVocabularyRequest struct {
+	VocabularyName string            `json:"VocabularyName"`
+	LanguageCode   string            `json:"LanguageCode"`
+	Phrases        []string          `json:"Phrases"`
+	Corrections    map[string]string `json:"Corrections,omitempty"`
+}
+
+// AWSVocabulary mirrors AWS Transcribe's GetVocabulary output, with the
+// vocabulary's phrases and corrections
+type AWSVocabulary struct {
+	VocabularyName   string            `json:"VocabularyName"`
+	LanguageCode     string            `json:"LanguageCode"`
+	VocabularyState  string            `json:"VocabularyState"`
+	LastModifiedTime time.Time         `json:"LastModifiedTime"`
+	Phrases          []string          `json:"Phrases,omitempty"`
+	Corrections      map[string]string `json:"Corrections,omitempty"`
+}
+
+// AWSListVocabulariesResponse mirrors AWS Transcribe's ListVocabularies output
+type AWSListVocabulariesResponse struct {
+	Vocabularies []AWSVocabulary `json:"Vocabularies"`
+}
+
+// AWSVocabularyFilterRequest creates or replaces a vocabulary filter, as AWS
+// Transcribe's CreateVocabularyFilter and UpdateVocabularyFilter do. Updates
+// take the name from the path.
+type AWSVocabularyFilterRequest struct {
+	VocabularyFilterName string   `json:"VocabularyFilterName"`
+	LanguageCode         string   `json:"LanguageCode"`
+	Words                []string `json:"Words"`
+}
+
+// AWSVocabularyFilter mirrors AWS Transcribe's GetVocabularyFilter output,
+// with the filter's words
+type AWSVocabularyFilter struct {
+	VocabularyFilterName string    `json:"VocabularyFilterName"`
+	LanguageCode         string    `json:"LanguageCode"`
+	LastModifiedTime     time.Time `json:"LastModifiedTime"`
+	Words                []string  `json:"Words,omitempty"`
+}
+
+// AWSListVocabularyFiltersResponse mirrors AWS Transcribe's ListVocabularyFilters output
+type AWSListVocabularyFiltersResponse struct {
+	VocabularyFilters []AWSVocabularyFilter `json:"VocabularyFilters"`
+}
+
+func newAWSVocabulary(vocabulary *models.Vocabulary, withPhrases bool) AWSVocabulary {
+	result := AWSVocabulary{
+		VocabularyName:   vocabulary.Name,
+		LanguageCode:     vocabulary.LanguageCode,
+		VocabularyState:  awsVocabularyStateReady,
+		LastModifiedTime: vocabulary.UpdatedAt,
+	}
+	if withPhrases {
+		result.Phrases = vocabulary.PhraseList()
+		for _, correction := range vocabulary.CorrectionList() {
+			if result.Corrections == nil {
+				result.Corrections = map[string]string{}
+			}
+			result.Corrections[correction.Misspelling] = correction.Phrase
+		}
+	}
+	return result
+}
+
+// joinCorrections stores corrections as models.Vocabulary does, reporting
+// false if a misspelling or phrase is blank
+func joinCorrections(corrections map[string]string) (string, bool) {
+	lines := make([]string, 0, len(corrections))
+	for misspelling, phrase := range corrections {
+		misspelling, phrase = strings.Join(strings.Fields(misspelling), " "), strings.Join(strings.Fields(phrase), " ")
+		if misspelling == "" || phrase == "" {
+			return "", false
+		}
+		lines = append(lines, misspelling+"\t"+phrase)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n"), true
+}
+
+func newAWSVocabularyFilter(filter *models.VocabularyFilter, withWords bool) AWSVocabularyFilter {
+	result := AWSVocabularyFilter{
+		VocabularyFilterName: filter.Name,
+		LanguageCode:         filter.LanguageCode,
+		LastModifiedTime:     filter.UpdatedAt,
+	}
+	if withWords {
+		result.Words = filter.WordList()
+	}
+	return result
+}
+
+// awsBadRequest answers 400 with an AWS Transcribe style BadRequestException
+func awsBadRequest(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": "BadRequestException: " + message})
+}
+
+// bindAWSVocabulary validates a request into vocabulary, answering 400 if it is
+// invalid; name is the vocabulary's name when the path gives it
+func (h *Handler) bindAWSVocabulary(c *gin.Context, vocabulary *models.Vocabulary, name string) bool {
+	var req AWSVocabularyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		awsBadRequest(c, "Invalid request: "+err.Error())
+		return false
+	}
+	if name == "" {
+		name = req.VocabularyName
+	}
+	if !awsVocabularyName.MatchString(name) {
+		awsBadRequest(c, "VocabularyName must be 1 to 200 letters, digits, dots, dashes and underscores.")
+		return false
+	}
+	phrases := joinLines(req.Phrases)
+	if phrases == "" {
+		awsBadRequest(c, "Phrases must not be empty.")
+		return false
+	}
+	corrections, ok := joinCorrections(req.Corrections)
+	if !ok {
+		awsBadRequest(c, "Corrections must map misspellings to phrases.")
+		return false
+	}
+	vocabulary.Name = name
+	vocabulary.LanguageCode = req.LanguageCode
+	vocabulary.Phrases = phrases
+	vocabulary.Corrections = corrections
+	return true
+}
+
+// bindAWSVocabularyFilter validates a request into filter, answering 400 if it
+// is invalid; name is the filter's name when the path gives it
+func (h *Handler) bindAWSVocabularyFilter(c *gin.Context, filter *models.VocabularyFilter, name string) bool {
+	var req AWSVocabularyFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		awsBadRequest(c, "Invalid request: "+err.Error())
+		return false
+	}
+	if name == "" {
+		name = req.VocabularyFilterName
+	}
+	if !awsVocabularyName.MatchString(name) {
+		awsBadRequest(c, "VocabularyFilterName must be 1 to 200 letters, digits, dots, dashes and underscores.")
+		return false
+	}
+	for _, word := range req.Words {
+		if len(strings.Fields(word)) > 1 {
+			awsBadRequest(c, "Words must be single words: "+word)
+			return false
+		}
+	}
+	words := joinLines(req.Words)
+	if words == "" {
+		awsBadRequest(c, "Words must not be empty.")
+		return false
+	}
+	filter.Name = name
+	filter.LanguageCode = req.LanguageCode
+	filter.Words = words
+	return true
+}
+
+// awsVocabulary loads the vocabulary named in the path, answering 404 if there is none
+func (h *Handler) awsVocabulary(c *gin.Context) (*models.Vocabulary, bool) {
+	vocabulary, err := h.vocabularyRepo.FindVocabulary(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "NotFoundException: The requested vocabulary couldn't be found."})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get vocabulary"})
+		return nil, false
+	}
+	return vocabulary, true
+}
+
+// awsVocabularyFilter loads the filter named in the path, answering 404 if there is none
+func (h *Handler) awsVocabularyFilter(c *gin.Context) (*models.VocabularyFilter, bool) {
+	filter, err := h.vocabularyRepo.FindFilter(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "NotFoundException: The requested vocabulary filter couldn't be found."})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get vocabulary filter"})
+		return nil, false
+	}
+	return filter, true
+}
+
+// @Summary List custom vocabularies
+// @Description List the custom vocabularies of the request's organization by name, as AWS Transcribe's ListVocabularies does
+// @Tags vocabularies
+// @Produce json
+// @Success 200 {object} AWSListVocabulariesResponse
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/vocabularies [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListVocabularies(c *gin.Context) {
+	vocabularies, err := h.vocabularyRepo.ListVocabularies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list vocabularies"})
+		return
+	}
+	response := AWSListVocabulariesResponse{Vocabularies: make([]AWSVocabulary, 0, len(vocabularies))}
+	for i := range vocabularies {
+		response.Vocabularies = append(response.Vocabularies, newAWSVocabulary(&vocabularies[i], false))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Create a custom vocabulary
+// @Description Create a custom vocabulary, as AWS Transcribe's CreateVocabulary does. Jobs naming it in Settings.VocabularyName, or profiles in vocabulary_name, are prompted with its phrases so the model favours their spelling, and have the misspellings in Corrections replaced in their transcript.
+// @Tags vocabularies
+// @Accept json
+// @Produce json
+// @Param request body AWSVocabularyRequest true "Vocabulary"
+// @Success 200 {object} AWSVocabulary
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/vocabularies [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateVocabulary(c *gin.Context) {
+	vocabulary := &models.Vocabulary{
+		OrganizationID: c.GetString("organization_id"),
+		CreatedBy:      h.requestAuthor(c),
+	}
+	if !h.bindAWSVocabulary(c, vocabulary, "") {
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := h.vocabularyRepo.FindVocabulary(ctx, vocabulary.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "ConflictException: A vocabulary named " + vocabulary.Name + " already exists."})
+		return
+	}
+	if err := h.vocabularyRepo.CreateVocabulary(ctx, vocabulary); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vocabulary"})
+		return
+	}
+	c.JSON(http.StatusOK, newAWSVocabulary(vocabulary, false))
+}
+
+// @Summary Get a custom vocabulary
+// @Description Get a custom vocabulary with its phrases and corrections, as AWS Transcribe's GetVocabulary does
+// @Tags vocabularies
+// @Produce json
+// @Param name path string true "VocabularyName"
+// @Success 200 {object} AWSVocabulary
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/vocabularies/{name} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetVocabulary(c *gin.Context) {
+	vocabulary, ok := h.awsVocabulary(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newAWSVocabulary(vocabulary, true))
+}
+
+// @Summary Update a custom vocabulary
+// @Description Replace the phrases and corrections of a custom vocabulary, as AWS Transcribe's UpdateVocabulary does. Queued jobs naming it use the new ones.
+// @Tags vocabularies
+// @Accept json
+// @Produce json
+// @Param name path string true "VocabularyName"
+// @Param request body AWSVocabularyRequest true "Vocabulary"
+// @Success 200 {object} AWSVocabulary
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/vocabularies/{name} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateVocabulary(c *gin.Context) {
+	vocabulary, ok := h.awsVocabulary(c)
+	if !ok {
+		return
+	}
+	if !h.bindAWSVocabulary(c, vocabulary, vocabulary.Name) {
+		return
+	}
+	if err := h.vocabularyRepo.UpdateVocabulary(c.Request.Context(), vocabulary); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vocabulary"})
+		return
+	}
+	c.JSON(http.StatusOK, newAWSVocabulary(vocabulary, false))
+}
+
+// @Summary Delete a custom vocabulary
+// @Description Delete a custom vocabulary; queued jobs naming it are transcribed without it
+// @Tags vocabularies
+// @Param name path string true "VocabularyName"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/vocabularies/{name} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteVocabulary(c *gin.Context) {
+	vocabulary, ok := h.awsVocabulary(c)
+	if !ok {
+		return
+	}
+	if err := h.vocabularyRepo.DeleteVocabulary(c.Request.Context(), vocabulary.Name); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vocabulary"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List vocabulary filters
+// @Description List the vocabulary filters of the request's organization by name, as AWS Transcribe's ListVocabularyFilters does
+// @Tags vocabularies
+// @Produce json
+// @Success 200 {object} AWSListVocabularyFiltersResponse
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/vocabulary-filters [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListVocabularyFilters(c *gin.Context) {
+	filters, err := h.vocabularyRepo.ListFilters(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list vocabulary filters"})
+		return
+	}
+	response := AWSListVocabularyFiltersResponse{VocabularyFilters: make([]AWSVocabularyFilter, 0, len(filters))}
+	for i := range filters {
+		response.VocabularyFilters = append(response.VocabularyFilters, newAWSVocabularyFilter(&filters[i], false))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Create a vocabulary filter
+// @Description Create a vocabulary filter, as AWS Transcribe's CreateVocabularyFilter does. Jobs naming it in Settings.VocabularyFilterName have its words removed, masked with *** or tagged in their transcript, after Settings.VocabularyFilterMethod.
+// @Tags vocabularies
+// @Accept json
+// @Produce json
+// @Param request body AWSVocabularyFilterRequest true "Vocabulary filter"
+// @Success 200 {object} AWSVocabularyFilter
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/vocabulary-filters [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateVocabularyFilter(c *gin.Context) {
+	filter := &models.VocabularyFilter{
+		OrganizationID: c.GetString("organization_id"),
+		CreatedBy:      h.requestAuthor(c),
+	}
+	if !h.bindAWSVocabularyFilter(c, filter, "") {
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := h.vocabularyRepo.FindFilter(ctx, filter.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "ConflictException: A vocabulary filter named " + filter.Name + " already exists."})
+		return
+	}
+	if err := h.vocabularyRepo.CreateFilter(ctx, filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vocabulary filter"})
+		return
+	}
+	c.JSON(http.StatusOK, newAWSVocabularyFilter(filter, false))
+}
+
+// @Summary Get a vocabulary filter
+// @Description Get a vocabulary filter with its words, as AWS Transcribe's GetVocabularyFilter does
+// @Tags vocabularies
+// @Produce json
+// @Param name path string true "VocabularyFilterName"
+// @Success 200 {object} AWSVocabularyFilter
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/vocabulary-filters/{name} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetVocabularyFilter(c *gin.Context) {
+	filter, ok := h.awsVocabularyFilter(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newAWSVocabularyFilter(filter, true))
+}
+
+// @Summary Update a vocabulary filter
+// @Description Replace the words of a vocabulary filter, as AWS Transcribe's UpdateVocabularyFilter does
+// @Tags vocabularies
+// @Accept json
+// @Produce json
+// @Param name path string true "VocabularyFilterName"
+// @Param request body AWSVocabularyFilterRequest true "Vocabulary filter"
+// @Success 200 {object} AWSVocabularyFilter
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/vocabulary-filters/{name} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateVocabularyFilter(c *gin.Context) {
+	filter, ok := h.awsVocabularyFilter(c)
+	if !ok {
+		return
+	}
+	if !h.bindAWSVocabularyFilter(c, filter, filter.Name) {
+		return
+	}
+	if err := h.vocabularyRepo.UpdateFilter(c.Request.Context(), filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vocabulary filter"})
+		return
+	}
+	c.JSON(http.StatusOK, newAWSVocabularyFilter(filter, false))
+}
+
+// @Summary Delete a vocabulary filter
+// @Description Delete a vocabulary filter; queued jobs naming it are left unfiltered
+// @Tags vocabularies
+// @Param name path string true "VocabularyFilterName"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/vocabulary-filters/{name} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteVocabularyFilter(c *gin.Context) {
+	filter, ok := h.awsVocabularyFilter(c)
+	if !ok {
+		return
+	}
+	if err := h.vocabularyRepo.DeleteFilter(c.Request.Context(), filter.Name); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vocabulary filter"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// vocabularyProblem checks the custom vocabulary and vocabulary filter params
+// name, defaulting the filter method, and describes what is wrong, if anything
+func (h *Handler) vocabularyProblem(ctx context.Context, params *models.WhisperXParams) string {
+	params.VocabularyName = trimmedOrNil(params.VocabularyName)
+	params.VocabularyFilterName = trimmedOrNil(params.VocabularyFilterName)
+	params.VocabularyFilterMethod = trimmedOrNil(params.VocabularyFilterMethod)
+	if params.VocabularyName != nil {
+		if _, err := h.vocabularyRepo.FindVocabulary(ctx, *params.VocabularyName); err != nil {
+			return fmt.Sprintf("vocabulary %q not found", *params.VocabularyName)
+		}
+	}
+	if params.VocabularyFilterName == nil {
+		params.VocabularyFilterMethod = nil
+		return ""
+	}
+	if _, err := h.vocabularyRepo.FindFilter(ctx, *params.VocabularyFilterName); err != nil {
+		return fmt.Sprintf("vocabulary filter %q not found", *params.VocabularyFilterName)
+	}
+	if params.VocabularyFilterMethod == nil {
+		method := models.VocabularyFilterRemove
+		params.VocabularyFilterMethod = &method
+	}
+	if !models.ValidVocabularyFilterMethod(*params.VocabularyFilterMethod) {
+		return "vocabulary filter method must be remove, mask or tag"
+	}
+	return ""
+}
+
+// validateVocabulary checks the vocabulary and filter params name, responding
+// with 400 when they cannot be used
+func (h *Handler) validateVocabulary(c *gin.Context, params *models.WhisperXParams) bool {
+	if problem := h.vocabularyProblem(c.Request.Context(), params); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return false
+	}
+	return true
+}
+
+// applyAWSVocabularySettings sets the custom vocabulary and vocabulary filter
+// a StartTranscriptionJob request names on params, answering 400 if one is
+// not found or the filter method is invalid
+func (h *Handler) applyAWSVocabularySettings(c *gin.Context, vocabularyName, filterName *string, filterMethod string, params *models.WhisperXParams) bool {
+	if vocabularyName != nil {
+		params.VocabularyName = vocabularyName
+	}
+	if filterName != nil {
+		params.VocabularyFilterName = filterName
+		params.VocabularyFilterMethod = &filterMethod
+	}
+	if problem := h.vocabularyProblem(c.Request.Context(), params); problem != "" {
+		awsBadRequest(c, problem)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"scriberr/internal/backup"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RestoreBackupRequest names a backup in the backup directory to restore
+type RestoreBackupRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// @Summary List backups
+// @Description List the backup archives in the backup directory, newest first
+// @Tags admin
+// @Produce json
+// @Success 200 {array} backup.Info
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/backups [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListBackups(c *gin.Context) {
+	backups, err := h.backups.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list backups"})
+		return
+	}
+	c.JSON(http.StatusOK, backups)
+}
+
+// @Summary Create a backup
+// @Description Snapshot the database and transcripts into a backup archive, copying it to BACKUP_S3_URI when configured unless upload is false. Archives beyond BACKUP_RETENTION are removed.
+// @Tags admin
+// @Produce json
+// @Param upload query bool false "Copy the archive to S3 (default true)"
+// @Success 201 {object} backup.Info
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/backups [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateBackup(c *gin.Context) {
+	upload := true
+	if v := c.Query("upload"); v != "" {
+		upload, _ = strconv.ParseBool(v)
+	}
+	info, err := h.backups.Run(c.Request.Context(), upload)
+	if err != nil {
+		logger.Error("Backup failed", "error", err)
+		if info != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Backup created but could not be copied to S3", "backup": info})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create backup"})
+		return
+	}
+	c.JSON(http.StatusCreated, info)
+}
+
+// @Summary Download a backup
+// @Description Download a backup archive from the backup directory
+// @Tags admin
+// @Produce application/gzip
+// @Param name path string true "Backup name"
+// @Success 200 {file} binary
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/backups/{name} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DownloadBackup(c *gin.Context) {
+	path, err := h.backups.Path(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found"})
+		return
+	}
+	c.FileAttachment(path, c.Param("name"))
+}
+
+// @Summary Restore a backup
+// @Description Stage a backup, uploaded as the "file" form field or named in a JSON body, to replace the database and transcripts at the next restart. The server cannot replace the database it is using, so restart it to finish the restore.
+// @Tags admin
+// @Accept json,mpfd
+// @Produce json
+// @Param request body RestoreBackupRequest false "Backup in the backup directory"
+// @Param file formData file false "Backup archive"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/backups/restore [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) RestoreBackup(c *gin.Context) {
+	var archive io.ReadCloser
+	if header, err := c.FormFile("file"); err == nil {
+		f, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded backup"})
+			return
+		}
+		archive = f
+	} else {
+		var req RestoreBackupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upload a backup as \"file\" or give the name of one"})
+			return
+		}
+		path, err := h.backups.Path(req.Name)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found"})
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open backup"})
+			return
+		}
+		archive = f
+	}
+	defer archive.Close()
+
+	manifest, err := h.backups.StageRestore(archive)
+	if err != nil {
+		if errors.Is(err, backup.ErrInvalidArchive) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to stage backup for restore", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stage backup"})
+		return
+	}
+	logger.Warn("Backup staged for restore at next startup", "created_at", manifest.CreatedAt)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Backup staged; restart the server to restore it",
+		"manifest": manifest,
+	})
+}
@@ -0,0 +1,327 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/registry"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxBenchmarkReferenceBytes bounds a ground-truth transcript
+const maxBenchmarkReferenceBytes = 1 << 20
+
+// BenchmarkReport is a benchmark with its samples and runs, and a summary of
+// the runs of each adapter
+type BenchmarkReport struct {
+	*models.Benchmark
+	Summary []BenchmarkModelSummary `json:"summary"`
+	// Currency of the costs, when adapters are priced
+	Currency string `json:"currency,omitempty"`
+}
+
+// BenchmarkModelSummary aggregates the runs of one adapter of a benchmark
+type BenchmarkModelSummary struct {
+	ModelID   string `json:"model_id"`
+	Runs      int    `json:"runs"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	// WER and CER are the word and character errors of the completed runs over
+	// the words and characters of their references
+	WER *float64 `json:"wer,omitempty"`
+	CER *float64 `json:"cer,omitempty"`
+	// MeanProcessingDuration averages the duration of the completed runs, in milliseconds
+	MeanProcessingDuration *int64 `json:"mean_processing_duration,omitempty"`
+	// RealTimeFactor is the processing time of the completed runs over the
+	// duration of their audio; below 1 is faster than real time
+	RealTimeFactor *float64 `json:"real_time_factor,omitempty"`
+	// Cost totals the completed runs, when the adapter is priced
+	Cost *float64 `json:"cost,omitempty"`
+}
+
+// summarizeBenchmark aggregates a benchmark's runs per adapter, in the order
+// the adapters were listed
+func summarizeBenchmark(benchmark *models.Benchmark) []BenchmarkModelSummary {
+	audioSeconds := make(map[uint]float64, len(benchmark.Samples))
+	for _, sample := range benchmark.Samples {
+		if sample.AudioSeconds != nil {
+			audioSeconds[sample.ID] = *sample.AudioSeconds
+		}
+	}
+	summary := []BenchmarkModelSummary{}
+	for _, modelID := range benchmark.ModelIDList() {
+		s := BenchmarkModelSummary{ModelID: modelID}
+		var refWords, wordErrors, refChars, charErrors int
+		var duration int64
+		var seconds, cost float64
+		priced := false
+		for _, run := range benchmark.Runs {
+			if run.ModelID != modelID {
+				continue
+			}
+			s.Runs++
+			switch run.Status {
+			case models.StatusCompleted:
+				s.Completed++
+				refWords += run.ReferenceWords
+				wordErrors += run.WordErrors
+				refChars += run.ReferenceChars
+				charErrors += run.CharErrors
+				if run.ProcessingDuration != nil {
+					duration += *run.ProcessingDuration
+				}
+				seconds += audioSeconds[run.SampleID]
+				if run.Cost != nil {
+					cost += *run.Cost
+					priced = true
+				}
+			case models.StatusFailed:
+				s.Failed++
+			}
+		}
+		if s.Completed > 0 {
+			wer := benchmarkErrorRate(wordErrors, refWords)
+			cer := benchmarkErrorRate(charErrors, refChars)
+			meanDuration := duration / int64(s.Completed)
+			s.WER, s.CER, s.MeanProcessingDuration = &wer, &cer, &meanDuration
+			if seconds > 0 {
+				factor := float64(duration) / 1000 / seconds
+				s.RealTimeFactor = &factor
+			}
+		}
+		if priced {
+			s.Cost = &cost
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
+
+// benchmarkErrorRate divides errors by the size of the references, counting
+// any error against empty references as a rate of 1
+func benchmarkErrorRate(errors, reference int) float64 {
+	if reference == 0 {
+		if errors == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(errors) / float64(reference)
+}
+
+// benchmarkDir is where a benchmark's audio is kept
+func (h *Handler) benchmarkDir(id string) string {
+	return filepath.Join(h.config.UploadDir, "benchmarks", id)
+}
+
+// readBenchmarkReference reads an uploaded ground-truth transcript
+func readBenchmarkReference(header *multipart.FileHeader) (string, error) {
+	f, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxBenchmarkReferenceBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxBenchmarkReferenceBytes {
+		return "", fmt.Errorf("larger than %d bytes", maxBenchmarkReferenceBytes)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// @Summary Start an adapter benchmark
+// @Description Run a reference audio set through several registered transcription adapters in the background and score each transcript against its ground truth, with the word and character error rates, latency and cost of every adapter. Upload each audio file as audio and its ground truth, as plain text, as a reference file of the same name with another extension (e.g. call1.wav and call1.txt). Adapters are called with the parameters of profile_name, those of the parameters JSON or the defaults, without diarization. Runs happen one at a time while no job is processing.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param model_ids formData string true "Comma-separated transcription adapters, e.g. whisperx,openai_whisper"
+// @Param audio formData file true "Audio files" multiple
+// @Param reference formData file true "Ground-truth transcripts, named after their audio" multiple
+// @Param name formData string false "Benchmark name"
+// @Param profile_name formData string false "Profile whose parameters the adapters are called with"
+// @Param parameters formData string false "Parameters JSON, when no profile is named"
+// @Success 202 {object} models.Benchmark
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/benchmarks [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateBenchmark(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse multipart form"})
+		return
+	}
+
+	var modelIDs []string
+	seen := make(map[string]bool)
+	for _, modelID := range strings.Split(c.PostForm("model_ids"), ",") {
+		modelID = strings.TrimSpace(modelID)
+		if modelID == "" || seen[modelID] {
+			continue
+		}
+		if _, err := registry.GetRegistry().GetTranscriptionAdapter(modelID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown transcription adapter %q", modelID)})
+			return
+		}
+		seen[modelID] = true
+		modelIDs = append(modelIDs, modelID)
+	}
+	if len(modelIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model_ids must name at least one transcription adapter"})
+		return
+	}
+
+	audioFiles := form.File["audio"]
+	if len(audioFiles) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No audio files uploaded"})
+		return
+	}
+	references := make(map[string]string)
+	for _, header := range form.File["reference"] {
+		text, err := readBenchmarkReference(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to read reference %s: %v", header.Filename, err)})
+			return
+		}
+		references[benchmarkSampleKey(header.Filename)] = text
+	}
+	for _, header := range audioFiles {
+		if references[benchmarkSampleKey(header.Filename)] == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("No reference transcript for %s", header.Filename)})
+			return
+		}
+	}
+
+	params, ok := h.quickTranscriptionParams(c)
+	if !ok {
+		return
+	}
+	benchmark := models.Benchmark{
+		ID:         uuid.New().String(),
+		Name:       strings.TrimSpace(c.PostForm("name")),
+		ModelIDs:   strings.Join(modelIDs, ","),
+		Parameters: params,
+		Status:     models.StatusPending,
+		CreatedBy:  h.requestAuthor(c),
+	}
+	dir := h.benchmarkDir(benchmark.ID)
+	for _, header := range audioFiles {
+		path, err := h.fileService.SaveUpload(header, dir)
+		if err != nil {
+			h.fileService.RemoveDirectory(dir)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save file %s", header.Filename)})
+			return
+		}
+		benchmark.Samples = append(benchmark.Samples, models.BenchmarkSample{
+			Name:      filepath.Base(header.Filename),
+			AudioPath: path,
+			Reference: references[benchmarkSampleKey(header.Filename)],
+		})
+	}
+
+	ctx := c.Request.Context()
+	if err := h.benchmarkRepo.Create(ctx, &benchmark); err != nil {
+		h.fileService.RemoveDirectory(dir)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create benchmark"})
+		return
+	}
+	if err := h.unifiedProcessor.QueueBenchmark(benchmark.ID); err != nil {
+		if deleteErr := h.benchmarkRepo.Delete(ctx, benchmark.ID); deleteErr != nil {
+			logger.Error("Failed to remove unqueued benchmark", "benchmark_id", benchmark.ID, "error", deleteErr)
+		}
+		h.fileService.RemoveDirectory(dir)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Benchmark cannot be run: " + err.Error()})
+		return
+	}
+	logger.Info("Benchmark queued", "benchmark_id", benchmark.ID, "samples", len(benchmark.Samples), "model_ids", benchmark.ModelIDs)
+	c.JSON(http.StatusAccepted, benchmark)
+}
+
+// benchmarkSampleKey matches an audio file with its reference by name
+func benchmarkSampleKey(filename string) string {
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// @Summary List adapter benchmarks
+// @Description List the adapter benchmarks, newest first, without their samples and runs
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.Benchmark
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/benchmarks [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListBenchmarks(c *gin.Context) {
+	benchmarks, err := h.benchmarkRepo.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list benchmarks"})
+		return
+	}
+	c.JSON(http.StatusOK, benchmarks)
+}
+
+// @Summary Get an adapter benchmark
+// @Description Get a benchmark's comparison report: every run with its transcript, error rates, latency and cost, and a summary per adapter
+// @Tags admin
+// @Produce json
+// @Param id path string true "Benchmark ID"
+// @Success 200 {object} BenchmarkReport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/benchmarks/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetBenchmark(c *gin.Context) {
+	benchmark, err := h.benchmarkRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Benchmark not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get benchmark"})
+		return
+	}
+	report := BenchmarkReport{Benchmark: benchmark, Summary: summarizeBenchmark(benchmark)}
+	if len(h.config.AdapterCostPerMinute) > 0 {
+		report.Currency = h.config.CostCurrency
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Delete an adapter benchmark
+// @Description Delete a benchmark with its runs and audio. A running benchmark stops after its current run.
+// @Tags admin
+// @Param id path string true "Benchmark ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/benchmarks/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteBenchmark(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.benchmarkRepo.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Benchmark not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete benchmark"})
+		return
+	}
+	if err := h.fileService.RemoveDirectory(h.benchmarkDir(id)); err != nil {
+		logger.Warn("Failed to remove benchmark audio", "benchmark_id", id, "error", err)
+	}
+	c.Status(http.StatusNoContent)
+}
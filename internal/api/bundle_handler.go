@@ -0,0 +1,246 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBundleJobs caps the jobs of a batch export
+const maxBundleJobs = 100
+
+// recordingBundle gathers a job's speaker names, summaries, notes and chats
+func (h *Handler) recordingBundle(ctx context.Context, job *models.TranscriptionJob) (*export.Bundle, error) {
+	bundle := &export.Bundle{
+		Job: export.BundleJob{
+			ID:         job.ID,
+			Title:      jobTitle(job),
+			Status:     string(job.Status),
+			CreatedAt:  job.CreatedAt,
+			RecordedAt: job.RecordedAt,
+			Tags:       job.TagNames(),
+		},
+		Speakers:   []export.BundleSpeaker{},
+		Summaries:  []export.BundleSummary{},
+		Notes:      []export.BundleNote{},
+		Chats:      []export.BundleChat{},
+		ExportedAt: time.Now(),
+	}
+
+	mappings, err := h.speakerMappingRepo.ListByJob(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list speakers: %w", err)
+	}
+	for _, mapping := range mappings {
+		bundle.Speakers = append(bundle.Speakers, export.BundleSpeaker{Label: mapping.OriginalSpeaker, Name: mapping.CustomName})
+	}
+
+	summaries, err := h.summaryRepo.ListSummaries(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list summaries: %w", err)
+	}
+	for _, summary := range summaries {
+		item := export.BundleSummary{Model: summary.Model, Content: summary.Content, CreatedAt: summary.CreatedAt}
+		if summary.TemplateID != nil {
+			item.TemplateID = *summary.TemplateID
+		}
+		bundle.Summaries = append(bundle.Summaries, item)
+	}
+
+	notes, err := h.noteRepo.ListByJob(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	for _, note := range notes {
+		bundle.Notes = append(bundle.Notes, export.BundleNote{
+			StartTime: note.StartTime,
+			EndTime:   note.EndTime,
+			Quote:     note.Quote,
+			Content:   note.Content,
+			CreatedAt: note.CreatedAt,
+			UpdatedAt: note.UpdatedAt,
+		})
+	}
+
+	sessions, err := h.chatRepo.ListByJob(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chats: %w", err)
+	}
+	for _, session := range sessions {
+		messages, err := h.chatRepo.GetMessages(ctx, session.ID, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chat messages: %w", err)
+		}
+		chat := export.BundleChat{Title: session.Title, Model: session.Model, CreatedAt: session.CreatedAt, Messages: []export.BundleChatMessage{}}
+		for _, message := range messages {
+			chat.Messages = append(chat.Messages, export.BundleChatMessage{Role: message.Role, Content: message.Content, CreatedAt: message.CreatedAt})
+		}
+		bundle.Chats = append(bundle.Chats, chat)
+	}
+	return bundle, nil
+}
+
+// writeJobBundle adds a job's bundle, and its transcript when it has one, to a zip
+func (h *Handler) writeJobBundle(ctx context.Context, zw *zip.Writer, dir string, job *models.TranscriptionJob) error {
+	bundle, err := h.recordingBundle(ctx, job)
+	if err != nil {
+		return err
+	}
+	var doc *export.Document
+	if job.Status == models.StatusCompleted && job.Transcript != nil {
+		if doc, err = h.exportDocument(ctx, job); err != nil {
+			return err
+		}
+	}
+	return export.WriteBundle(zw, dir, bundle, doc)
+}
+
+// bundleDir names a job's directory in a batch export
+func bundleDir(job *models.TranscriptionJob) string {
+	return exportFileName(jobTitle(job)) + " - " + job.ID + "/"
+}
+
+// sendZip answers with a zip built by write
+func sendZip(c *gin.Context, fileName string, write func(zw *zip.Writer) error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if err := write(zw); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build export: " + err.Error()})
+		return
+	}
+	if err := zw.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build export"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, fileName))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// @Summary Export a recording bundle
+// @Description Export a recording's full context as a zip: its speaker names, summaries, notes and chat conversations as context.json and context.md, and for completed jobs the transcript as transcript.json and transcript.srt
+// @Tags transcription
+// @Produce application/zip
+// @Param id path string true "Job ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/bundle [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportBundle(c *gin.Context) {
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	sendZip(c, exportFileName(jobTitle(job)), func(zw *zip.Writer) error {
+		return h.writeJobBundle(ctx, zw, "", job)
+	})
+}
+
+// @Summary Export several recording bundles
+// @Description Export the bundles of several recordings as one zip, a directory per recording (see GET /api/v1/transcription/{id}/bundle)
+// @Tags transcription
+// @Produce application/zip
+// @Param ids query string true "Comma-separated job IDs (at most 100)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/bundle [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportBundles(c *gin.Context) {
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxBundleJobs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ids must list between 1 and %d job IDs", maxBundleJobs)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	jobs := make([]*models.TranscriptionJob, 0, len(ids))
+	for _, id := range ids {
+		job, err := h.jobRepo.FindByID(ctx, id)
+		if err != nil || !models.WorkspaceVisible(ctx, job.Workspace) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found: " + id})
+			return
+		}
+		if _, ok, err := h.jobCollectionRole(c, job); err != nil || !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found: " + id})
+			return
+		}
+		jobs = append(jobs, job)
+	}
+	sendZip(c, "scriberr-export", func(zw *zip.Writer) error {
+		for _, job := range jobs {
+			if err := h.writeJobBundle(ctx, zw, bundleDir(job), job); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// @Summary Download my data
+// @Description Export everything stored about the current user as a zip: the account and its settings as account.json, and the bundle of every recording in the user's own workspace under recordings/ (see GET /api/v1/transcription/{id}/bundle)
+// @Tags users
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/user/takeout [get]
+// @Security BearerAuth
+func (h *Handler) GetTakeout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	ctx := c.Request.Context()
+	user, err := h.userService.GetUser(ctx, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	jobs, _, err := h.jobRepo.ListByUser(ctx, user.ID, 0, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list recordings"})
+		return
+	}
+
+	sendZip(c, exportFileName(user.Username)+"-takeout", func(zw *zip.Writer) error {
+		account, err := json.MarshalIndent(user, "", "  ")
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create("account.json")
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(account); err != nil {
+			return err
+		}
+		for i := range jobs {
+			if err := h.writeJobBundle(ctx, zw, "recordings/"+bundleDir(&jobs[i]), &jobs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -76,27 +76,40 @@ type Segment struct {
 
 // getLLMService returns a provider-agnostic LLM service based on active config
 func (h *Handler) getLLMService(ctx context.Context) (llm.Service, string, error) {
+	svc, cfg, err := h.activeLLMService(ctx)
+	if cfg == nil {
+		return nil, "", err
+	}
+	return svc, cfg.Provider, err
+}
+
+// activeLLMService returns the service of the active LLM config with the config
+func (h *Handler) activeLLMService(ctx context.Context) (llm.Service, *models.LLMConfig, error) {
 	cfg, err := h.llmConfigRepo.GetActive(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, "", fmt.Errorf("no active LLM configuration found")
+			return nil, nil, fmt.Errorf("no active LLM configuration found")
 		}
-		return nil, "", fmt.Errorf("failed to get LLM config: %w", err)
+		return nil, nil, fmt.Errorf("failed to get LLM config: %w", err)
 	}
-	switch strings.ToLower(cfg.Provider) {
-	case "openai":
-		if cfg.APIKey == nil || *cfg.APIKey == "" {
-			return nil, cfg.Provider, fmt.Errorf("OpenAI API key not configured")
-		}
-		return llm.NewOpenAIService(*cfg.APIKey, cfg.OpenAIBaseURL), cfg.Provider, nil
-	case "ollama":
-		if cfg.BaseURL == nil || *cfg.BaseURL == "" {
-			return nil, cfg.Provider, fmt.Errorf("Ollama base URL not configured")
+	svc, err := llm.NewFromConfig(cfg)
+	if err != nil {
+		return nil, cfg, err
+	}
+	return svc, cfg, nil
+}
+
+// getCachedLLMService returns the configured LLM service with its deterministic
+// replies cached, for summaries and extractions that are rerun after edits
+func (h *Handler) getCachedLLMService(ctx context.Context) (llm.Service, string, error) {
+	svc, cfg, err := h.activeLLMService(ctx)
+	if err != nil {
+		if cfg == nil {
+			return nil, "", err
 		}
-		return llm.NewOllamaService(*cfg.BaseURL), cfg.Provider, nil
-	default:
-		return nil, cfg.Provider, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
+		return nil, cfg.Provider, err
 	}
+	return llm.WithCache(svc, h.summaryRepo, cfg), cfg.Provider, nil
 }
 
 // @Summary Get available chat models
@@ -150,7 +163,7 @@ func (h *Handler) CreateChatSession(c *gin.Context) {
 
 	// Verify transcription exists and has completed transcript
 	transcription, err := h.jobRepo.FindByID(c.Request.Context(), req.TranscriptionID)
-	if err != nil {
+	if err != nil || !models.WorkspaceVisible(c.Request.Context(), transcription.Workspace) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
 		return
 	}
@@ -410,6 +423,15 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 		return
 	}
 
+	// Refused messages are not saved, so they never reach the model later
+	policy, ok := h.requireChatPolicy(c, session.Transcription.Workspace)
+	if !ok {
+		return
+	}
+	if h.refuseBannedTopic(c, policy, req.Content, "chat_session", sessionID) {
+		return
+	}
+
 	// Save user message
 	userMessage := &models.ChatMessage{
 		SessionID:     sessionID,
@@ -440,12 +462,8 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 		}
 	}
 
-	// Get context window
-	contextWindow, err := svc.GetContextWindow(c.Request.Context(), session.Model)
-	if err != nil {
-		fmt.Printf("Failed to get context window for model %s: %v. Using default 4096.\n", session.Model, err)
-		contextWindow = 4096
-	}
+	// Get context window, capped by the workspace's policy
+	contextWindow := policyContextWindow(c.Request.Context(), svc, session.Model, policy)
 
 	// Build OpenAI messages including transcript context
 	var openaiMessages []llm.ChatMessage
@@ -498,7 +516,7 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 		cleanTranscript := sb.String()
 		fmt.Printf("Debug: Clean transcript length: %d\n", len(cleanTranscript))
 		
-		systemContent := fmt.Sprintf("You are a helpful assistant analyzing this transcript. Please answer questions and provide insights based on the following transcript:\n\n%s", cleanTranscript)
+		systemContent := withPolicyInstructions(policy, fmt.Sprintf("You are a helpful assistant analyzing this transcript. Please answer questions and provide insights based on the following transcript:\n\n%s", cleanTranscript))
 
 		fmt.Printf("Injecting transcript of length %d into chat context for session %s\n", len(systemContent), sessionID)
 
@@ -522,8 +540,16 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 		}
 	}
 
-	// Add conversation history
-	for _, msg := range messages {
+	// Without a transcript the policy's instructions need a system message of their own
+	if len(openaiMessages) == 0 {
+		if instructions := policy.Instructions(); instructions != "" {
+			openaiMessages = append(openaiMessages, llm.ChatMessage{Role: "system", Content: instructions})
+			currentTokenCount += len(instructions) / 4
+		}
+	}
+
+	// Add conversation history, as much of it as the policy allows
+	for _, msg := range recentMessages(policy, messages) {
 		msgTokens := len(msg.Content) / 4
 		openaiMessages = append(openaiMessages, llm.ChatMessage{
 			Role:    msg.Role,
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChatPolicyRequest sets the chat policy of a workspace
+type ChatPolicyRequest struct {
+	SystemPrompt       string   `json:"system_prompt" binding:"max=10000"`
+	BannedTopics       []string `json:"banned_topics" binding:"max=100,dive,max=200"`
+	MaxContextTokens   int      `json:"max_context_tokens" binding:"min=0"`
+	MaxHistoryMessages int      `json:"max_history_messages" binding:"min=0"`
+}
+
+// ChatPolicyResponse is a workspace's chat policy
+type ChatPolicyResponse struct {
+	*models.ChatPolicy
+	BannedTopics []string `json:"banned_topics"`
+}
+
+func newChatPolicyResponse(policy *models.ChatPolicy) ChatPolicyResponse {
+	return ChatPolicyResponse{ChatPolicy: policy, BannedTopics: policy.BannedTopicList()}
+}
+
+// chatPolicy returns the chat policy in effect in a workspace: the instance-wide
+// policy with the workspace's own on top
+func (h *Handler) chatPolicy(ctx context.Context, workspace string) (*models.ChatPolicy, error) {
+	instance, err := h.chatRepo.GetPolicy(ctx, models.InstanceChatPolicy)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := h.chatRepo.GetPolicy(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	return instance.Merge(policy), nil
+}
+
+// requireChatPolicy returns the chat policy in effect in a workspace. Chats are
+// refused with 503 when it cannot be loaded, rather than run unconstrained.
+func (h *Handler) requireChatPolicy(c *gin.Context, workspace string) (*models.ChatPolicy, bool) {
+	policy, err := h.chatPolicy(c.Request.Context(), workspace)
+	if err != nil {
+		logger.Error("Failed to load chat policy", "workspace", workspace, "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Chat policy unavailable"})
+		return nil, false
+	}
+	return policy, true
+}
+
+// chatPolicyWorkspace returns the workspace whose policy a request reads or
+// sets: the request's own, or for admins the one named by the workspace query
+// parameter, "*" naming the instance-wide policy outside organizations
+func (h *Handler) chatPolicyWorkspace(c *gin.Context) (string, bool) {
+	workspace, named := c.GetQuery("workspace")
+	if !named {
+		return h.requestWorkspace(c), true
+	}
+	if c.GetString("role") != models.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can manage other workspaces' chat policies"})
+		return "", false
+	}
+	if workspace == models.InstanceChatPolicy {
+		if c.GetString("organization_id") != "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The instance-wide chat policy cannot be managed from an organization"})
+			return "", false
+		}
+		return workspace, true
+	}
+	if !models.WorkspaceVisible(c.Request.Context(), workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+		return "", false
+	}
+	return workspace, true
+}
+
+// refuseBannedTopic answers 422 if text raises a topic the policy bans,
+// recording the refusal in the audit log
+func (h *Handler) refuseBannedTopic(c *gin.Context, policy *models.ChatPolicy, text, resourceType, resourceID string) bool {
+	topic := policy.BannedTopicIn(text)
+	if topic == "" {
+		return false
+	}
+	h.recordAudit(c, models.AuditChatRefused, resourceType, resourceID, map[string]interface{}{"topic": topic})
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Chat about \"" + topic + "\" is not allowed in this workspace", "topic": topic})
+	return true
+}
+
+// withPolicyInstructions puts the policy's instructions before a system prompt
+func withPolicyInstructions(policy *models.ChatPolicy, systemContent string) string {
+	if instructions := policy.Instructions(); instructions != "" {
+		return instructions + "\n\n" + systemContent
+	}
+	return systemContent
+}
+
+// recentMessages keeps the policy's number of most recent messages
+func recentMessages[T any](policy *models.ChatPolicy, messages []T) []T {
+	if policy.MaxHistoryMessages > 0 && len(messages) > policy.MaxHistoryMessages {
+		return messages[len(messages)-policy.MaxHistoryMessages:]
+	}
+	return messages
+}
+
+// policyContextWindow returns the model's context window capped by the policy
+func policyContextWindow(ctx context.Context, svc llm.Service, model string, policy *models.ChatPolicy) int {
+	contextWindow, err := svc.GetContextWindow(ctx, model)
+	if err != nil {
+		logger.Debug("Failed to get context window, using default", "model", model, "error", err)
+		contextWindow = 4096
+	}
+	return policy.ContextWindow(contextWindow)
+}
+
+// @Summary Get chat policy
+// @Description Get the system prompt, banned topics and context limits in effect for chats in the request's workspace, including the instance-wide policy. Admins can read the policy stored for another workspace, or the instance-wide one with workspace=*.
+// @Tags chat
+// @Produce json
+// @Param workspace query string false "Workspace whose stored policy to get (admins only); * for the instance-wide policy"
+// @Success 200 {object} ChatPolicyResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/chat/policy [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetChatPolicy(c *gin.Context) {
+	workspace, ok := h.chatPolicyWorkspace(c)
+	if !ok {
+		return
+	}
+	var policy *models.ChatPolicy
+	var err error
+	if _, named := c.GetQuery("workspace"); named {
+		policy, err = h.chatRepo.GetPolicy(c.Request.Context(), workspace)
+	} else {
+		policy, err = h.chatPolicy(c.Request.Context(), workspace)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat policy"})
+		return
+	}
+	c.JSON(http.StatusOK, newChatPolicyResponse(policy))
+}
+
+// @Summary Update chat policy
+// @Description Set the system prompt, banned topics and context limits applied to chats in the request's workspace, another workspace, or with workspace=* every workspace, organizations included. A workspace's policy applies on top of the instance-wide one: both system prompts and all banned topics apply, and the stricter limits. The system prompt is put before the assistant's instructions; messages raising a banned topic are refused with 422 and recorded in the audit log, and the assistant is told not to discuss them. max_context_tokens caps the estimated tokens per request below the model's window and max_history_messages limits how much of the conversation is sent; 0 leaves either unlimited.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param workspace query string false "Workspace to set the policy of; * for the instance-wide policy"
+// @Param request body ChatPolicyRequest true "Chat policy"
+// @Success 200 {object} ChatPolicyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/chat/policy [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateChatPolicy(c *gin.Context) {
+	workspace, ok := h.chatPolicyWorkspace(c)
+	if !ok {
+		return
+	}
+	var req ChatPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	var topics []string
+	for _, topic := range req.BannedTopics {
+		// Topics are stored one per line
+		if topic = strings.Join(strings.Fields(topic), " "); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+
+	policy := &models.ChatPolicy{
+		Workspace:          workspace,
+		SystemPrompt:       strings.TrimSpace(req.SystemPrompt),
+		BannedTopics:       strings.Join(topics, "\n"),
+		MaxContextTokens:   req.MaxContextTokens,
+		MaxHistoryMessages: req.MaxHistoryMessages,
+		UpdatedBy:          h.requestAuthor(c),
+	}
+	if err := h.chatRepo.SavePolicy(c.Request.Context(), policy); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save chat policy"})
+		return
+	}
+	c.JSON(http.StatusOK, newChatPolicyResponse(policy))
+}
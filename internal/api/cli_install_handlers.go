@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -134,22 +133,7 @@ echo "Installation complete!"
 func (h *Handler) GetInstallScript(c *gin.Context) {
 	token := c.Query("token")
 
-	// Determine server URL from request if not configured
-	scheme := "http"
-	if c.Request.TLS != nil {
-		scheme = "https"
-	}
-	host := c.Request.Host
-	serverURL := fmt.Sprintf("%s://%s", scheme, host)
-
-	// If behind a proxy (common in prod), use X-Forwarded-Proto/Host
-	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
-		scheme = proto
-	}
-	if forwardedHost := c.GetHeader("X-Forwarded-Host"); forwardedHost != "" {
-		host = forwardedHost
-	}
-	serverURL = fmt.Sprintf("%s://%s", scheme, host)
+	serverURL := h.requestBaseURL(c)
 
 	tmpl, err := template.New("install").Parse(installScriptTemplate)
 	if err != nil {
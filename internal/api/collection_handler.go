@@ -0,0 +1,669 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCollectionJobs caps the jobs moved, or exported, at once
+const maxCollectionJobs = 500
+
+// CollectionRequest creates or replaces a collection
+type CollectionRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description,omitempty"`
+	ParentID    *string `json:"parent_id,omitempty"`
+}
+
+// CollectionResponse is a collection with the role the request has in it
+type CollectionResponse struct {
+	repository.CollectionWithJobs
+	Role string `json:"role"`
+}
+
+// CollectionJobsRequest files jobs in a collection
+type CollectionJobsRequest struct {
+	JobIDs []string `json:"job_ids" binding:"required"`
+}
+
+// CollectionPermissionsRequest restricts a collection to its members, or opens
+// it to the whole workspace again
+type CollectionPermissionsRequest struct {
+	Restricted bool                      `json:"restricted"`
+	Members    []CollectionMemberRequest `json:"members"`
+}
+
+// CollectionMemberRequest gives a user access to a restricted collection
+type CollectionMemberRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+// CollectionPermissionsResponse is who may access a collection
+type CollectionPermissionsResponse struct {
+	Restricted bool                      `json:"restricted"`
+	Members    []models.CollectionMember `json:"members"`
+}
+
+// isReadRequest reports whether a request only reads, as RequireRoleForWrites
+// tells them apart
+func isReadRequest(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// workspaceCollections returns the collections of a workspace, and the role
+// the request has in each it may access
+func (h *Handler) workspaceCollections(c *gin.Context, workspace string) ([]repository.CollectionWithJobs, map[string]string, error) {
+	ctx := c.Request.Context()
+	listed, err := h.collectionRepo.ListByWorkspace(ctx, workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	memberships := map[string]string{}
+	if userID, exists := c.Get("user_id"); exists {
+		if memberships, err = h.collectionRepo.UserMemberships(ctx, workspace, userID.(uint)); err != nil {
+			return nil, nil, err
+		}
+	}
+	collections := make([]models.Collection, len(listed))
+	for i := range listed {
+		collections[i] = listed[i].Collection
+	}
+	return listed, models.CollectionRoles(collections, c.GetString("role"), memberships), nil
+}
+
+// hiddenCollections returns the collections of the request's workspace it may
+// not access, whose jobs it does not see
+func (h *Handler) hiddenCollections(c *gin.Context) ([]string, error) {
+	if c.GetString("role") == models.RoleAdmin {
+		return nil, nil
+	}
+	collections, roles, err := h.workspaceCollections(c, h.requestWorkspace(c))
+	if err != nil {
+		return nil, err
+	}
+	var hidden []string
+	for _, collection := range collections {
+		if _, ok := roles[collection.ID]; !ok {
+			hidden = append(hidden, collection.ID)
+		}
+	}
+	return hidden, nil
+}
+
+// HideRestrictedCollections leaves the jobs of collections the request may not
+// access out of the jobs it lists or searches, through its context
+func (h *Handler) HideRestrictedCollections() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hidden, err := h.hiddenCollections(c)
+		if err != nil {
+			logger.Error("Failed to check collection access", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check collection access"})
+			c.Abort()
+			return
+		}
+		if len(hidden) > 0 {
+			c.Request = c.Request.WithContext(models.WithHiddenCollections(c.Request.Context(), hidden))
+		}
+		c.Next()
+	}
+}
+
+// jobCollectionRole returns the role the request has in a job's collection,
+// or false if it may not access the collection. Jobs filed in none are
+// accessible with the request's role.
+func (h *Handler) jobCollectionRole(c *gin.Context, job *models.TranscriptionJob) (string, bool, error) {
+	role := c.GetString("role")
+	if job.CollectionID == nil || role == models.RoleAdmin {
+		return role, true, nil
+	}
+	_, roles, err := h.workspaceCollections(c, job.Workspace)
+	if err != nil {
+		return "", false, err
+	}
+	role, ok := roles[*job.CollectionID]
+	return role, ok, nil
+}
+
+// checkCollectionAccess answers 404, as notFound, to requests for a job filed
+// in a collection they may not access, and 403 to requests changing a job of
+// a collection they may only view
+func (h *Handler) checkCollectionAccess(c *gin.Context, job *models.TranscriptionJob, notFound string) bool {
+	role, ok, err := h.jobCollectionRole(c, job)
+	if err != nil {
+		logger.Error("Failed to check collection access", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check collection access"})
+		c.Abort()
+		return false
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		c.Abort()
+		return false
+	}
+	if !isReadRequest(c) && !models.RoleAllows(role, models.RoleEditor) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You may only view the jobs of this collection"})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// accessibleCollection loads a collection of the request's workspace, hiding
+// those it may not access and answering 403 when it needs at least role in it
+func (h *Handler) accessibleCollection(c *gin.Context, id, role string) (*repository.CollectionWithJobs, string, map[string]string, bool) {
+	collections, roles, err := h.workspaceCollections(c, h.requestWorkspace(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get collection"})
+		return nil, "", nil, false
+	}
+	granted, ok := roles[id]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return nil, "", nil, false
+	}
+	if !models.RoleAllows(granted, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You may only view this collection"})
+		return nil, "", nil, false
+	}
+	for i := range collections {
+		if collections[i].ID == id {
+			return &collections[i], granted, roles, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+	return nil, "", nil, false
+}
+
+// checkKeepsRestricted answers 403 to requests, but for admins, moving jobs or
+// collections of a workspace from one collection (nil for the top level) into
+// another that is not restricted by every collection restricting the first,
+// which would show them to users the restrictions keep them from
+func (h *Handler) checkKeepsRestricted(c *gin.Context, workspace string, from, to *string) bool {
+	if c.GetString("role") == models.RoleAdmin {
+		return true
+	}
+	listed, err := h.collectionRepo.ListByWorkspace(c.Request.Context(), workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get collection"})
+		return false
+	}
+	collections := make([]models.Collection, len(listed))
+	for i := range listed {
+		collections[i] = listed[i].Collection
+	}
+	target := models.CollectionRestrictions(collections, to)
+	for id := range models.CollectionRestrictions(collections, from) {
+		if !target[id] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only admins may move jobs or collections out of a restricted collection"})
+			return false
+		}
+	}
+	return true
+}
+
+// collectionRequest validates a collection request, including that the
+// request may add collections to its parent
+func (h *Handler) collectionRequest(c *gin.Context) (*CollectionRequest, bool) {
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return nil, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name must be between 1 and 255 characters"})
+		return nil, false
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+	if req.ParentID != nil {
+		_, roles, err := h.workspaceCollections(c, h.requestWorkspace(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get collection"})
+			return nil, false
+		}
+		role, ok := roles[*req.ParentID]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent collection not found"})
+			return nil, false
+		}
+		if !models.RoleAllows(role, models.RoleEditor) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You may only view the parent collection"})
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+// @Summary List collections
+// @Description List the collections of the caller's workspace it may access, by name, with the number of jobs filed directly in each and the caller's role in it. Collections nest through parent_id.
+// @Tags collections
+// @Produce json
+// @Success 200 {array} CollectionResponse
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListCollections(c *gin.Context) {
+	collections, roles, err := h.workspaceCollections(c, h.requestWorkspace(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list collections"})
+		return
+	}
+	responses := []CollectionResponse{}
+	for _, collection := range collections {
+		if role, ok := roles[collection.ID]; ok {
+			responses = append(responses, CollectionResponse{CollectionWithJobs: collection, Role: role})
+		}
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// @Summary Create a collection
+// @Description Add a collection to the caller's workspace, inside parent_id if given
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param request body CollectionRequest true "Collection"
+// @Success 201 {object} models.Collection
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateCollection(c *gin.Context) {
+	req, ok := h.collectionRequest(c)
+	if !ok {
+		return
+	}
+	collection := &models.Collection{
+		Workspace:   h.requestWorkspace(c),
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := h.collectionRepo.Create(c.Request.Context(), collection); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create collection"})
+		return
+	}
+	c.JSON(http.StatusCreated, collection)
+}
+
+// @Summary Get a collection
+// @Tags collections
+// @Produce json
+// @Param id path string true "Collection ID"
+// @Success 200 {object} CollectionResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetCollection(c *gin.Context) {
+	collection, role, _, ok := h.accessibleCollection(c, c.Param("id"), models.RoleViewer)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, CollectionResponse{CollectionWithJobs: *collection, Role: role})
+}
+
+// @Summary Update a collection
+// @Description Rename a collection, or move it, with everything inside it, into another given as parent_id, or to the top level without one. Only admins may move a collection out of a restricted one into one it does not restrict.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID"
+// @Param request body CollectionRequest true "Collection"
+// @Success 200 {object} models.Collection
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateCollection(c *gin.Context) {
+	found, _, _, ok := h.accessibleCollection(c, c.Param("id"), models.RoleEditor)
+	if !ok {
+		return
+	}
+	req, ok := h.collectionRequest(c)
+	if !ok {
+		return
+	}
+	collection := found.Collection
+
+	// A collection cannot be moved into itself or one of its own collections
+	if req.ParentID != nil {
+		collections, err := h.collectionRepo.ListByWorkspace(c.Request.Context(), collection.Workspace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get collection"})
+			return
+		}
+		parents := make(map[string]*string, len(collections))
+		for _, other := range collections {
+			parents[other.ID] = other.ParentID
+		}
+		for id, depth := req.ParentID, 0; id != nil && depth <= len(collections); id, depth = parents[*id], depth+1 {
+			if *id == collection.ID {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "A collection cannot be moved inside itself"})
+				return
+			}
+		}
+	}
+
+	moved := (req.ParentID == nil) != (collection.ParentID == nil) ||
+		(req.ParentID != nil && *req.ParentID != *collection.ParentID)
+	if moved && !h.checkKeepsRestricted(c, collection.Workspace, collection.ParentID, req.ParentID) {
+		return
+	}
+
+	collection.Name, collection.Description, collection.ParentID = req.Name, req.Description, req.ParentID
+	if err := h.collectionRepo.Update(c.Request.Context(), &collection); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update collection"})
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+// @Summary Delete a collection
+// @Description Delete a collection. Its jobs and collections move into its parent, or to the top level. Only admins may delete restricted collections, whose contents would lose the restriction.
+// @Tags collections
+// @Param id path string true "Collection ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteCollection(c *gin.Context) {
+	collection, _, roles, ok := h.accessibleCollection(c, c.Param("id"), models.RoleEditor)
+	if !ok {
+		return
+	}
+	if collection.ParentID != nil && !models.RoleAllows(roles[*collection.ParentID], models.RoleEditor) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You may only view the parent collection, which its contents would move to"})
+		return
+	}
+	if !h.checkKeepsRestricted(c, collection.Workspace, &collection.ID, collection.ParentID) {
+		return
+	}
+	if err := h.collectionRepo.DeleteCollection(c.Request.Context(), &collection.Collection); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete collection"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary File jobs in a collection
+// @Description Move jobs of the collection's workspace into it, out of any other collection they were in. List a collection's jobs with collection_id on GET /api/v1/transcription/list. Only admins may move jobs out of a restricted collection into one it does not restrict.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID"
+// @Param request body CollectionJobsRequest true "Jobs (at most 500)"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id}/jobs [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) AddCollectionJobs(c *gin.Context) {
+	collection, _, roles, ok := h.accessibleCollection(c, c.Param("id"), models.RoleEditor)
+	if !ok {
+		return
+	}
+	var req CollectionJobsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if len(req.JobIDs) == 0 || len(req.JobIDs) > maxCollectionJobs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("job_ids must list between 1 and %d job IDs", maxCollectionJobs)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	sources := map[string]bool{}
+	for _, id := range req.JobIDs {
+		job, err := h.jobRepo.FindByID(ctx, id)
+		if err != nil || job.Workspace != collection.Workspace || !models.WorkspaceVisible(ctx, job.Workspace) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found: " + id})
+			return
+		}
+		// Jobs are moved out of their collection too
+		if job.CollectionID != nil {
+			role, visible := roles[*job.CollectionID]
+			if c.GetString("role") == models.RoleAdmin {
+				role, visible = models.RoleAdmin, true
+			}
+			if !visible {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Job not found: " + id})
+				return
+			}
+			if !models.RoleAllows(role, models.RoleEditor) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You may only view the collection of job " + id})
+				return
+			}
+			sources[*job.CollectionID] = true
+		}
+	}
+	for source := range sources {
+		if !h.checkKeepsRestricted(c, collection.Workspace, &source, &collection.ID) {
+			return
+		}
+	}
+	if err := h.collectionRepo.MoveJobs(ctx, req.JobIDs, &collection.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"moved": len(req.JobIDs)})
+}
+
+// @Summary Take a job out of a collection
+// @Description Move a job of the collection to the top level, filed in no collection. Only admins may take jobs out of restricted collections.
+// @Tags collections
+// @Param id path string true "Collection ID"
+// @Param job_id path string true "Job ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id}/jobs/{job_id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) RemoveCollectionJob(c *gin.Context) {
+	collection, _, _, ok := h.accessibleCollection(c, c.Param("id"), models.RoleEditor)
+	if !ok {
+		return
+	}
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("job_id"))
+	if err != nil || job.CollectionID == nil || *job.CollectionID != collection.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The job is not in this collection"})
+		return
+	}
+	if !h.checkKeepsRestricted(c, collection.Workspace, &collection.ID, nil) {
+		return
+	}
+	if err := h.collectionRepo.MoveJobs(c.Request.Context(), []string{job.ID}, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move job"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Export a collection
+// @Description Export the bundles of the jobs in a collection and the collections inside it that the caller may access as one zip, a directory per collection and recording (see GET /api/v1/transcription/{id}/bundle)
+// @Tags collections
+// @Produce application/zip
+// @Param id path string true "Collection ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id}/export [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportCollection(c *gin.Context) {
+	collection, _, roles, ok := h.accessibleCollection(c, c.Param("id"), models.RoleViewer)
+	if !ok {
+		return
+	}
+	hidden, err := h.hiddenJobFields(c, collection.Workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check field permissions"})
+		return
+	}
+	if hidden[models.JobFieldTranscript] || hidden[models.JobFieldAudio] {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role may not export the jobs of this workspace"})
+		return
+	}
+
+	// The directory of each accessible collection inside the exported one
+	collections, err := h.collectionRepo.ListByWorkspace(c.Request.Context(), collection.Workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list collections"})
+		return
+	}
+	dirs := map[string]string{collection.ID: ""}
+	for added := true; added; {
+		added = false
+		for _, child := range collections {
+			if _, done := dirs[child.ID]; done || child.ParentID == nil {
+				continue
+			}
+			parentDir, ok := dirs[*child.ParentID]
+			if _, visible := roles[child.ID]; !ok || !visible {
+				continue
+			}
+			dirs[child.ID] = parentDir + exportFileName(child.Name) + " - " + child.ID + "/"
+			added = true
+		}
+	}
+	ids := make([]string, 0, len(dirs))
+	for id := range dirs {
+		ids = append(ids, id)
+	}
+
+	page, err := h.jobRepo.ListJobs(c.Request.Context(), repository.JobListQuery{CollectionIDs: ids, SortOrder: "asc", Limit: maxCollectionJobs + 1})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	if len(page.Jobs) > maxCollectionJobs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Collections of more than %d jobs are exported a collection inside them at a time", maxCollectionJobs)})
+		return
+	}
+	ctx := c.Request.Context()
+	sendZip(c, exportFileName(collection.Name), func(zw *zip.Writer) error {
+		for i := range page.Jobs {
+			job := &page.Jobs[i]
+			if err := h.writeJobBundle(ctx, zw, dirs[*job.CollectionID]+bundleDir(job), job); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// @Summary Get the permissions of a collection
+// @Description Get whether a collection is restricted, and the members who may access it then
+// @Tags collections
+// @Produce json
+// @Param id path string true "Collection ID"
+// @Success 200 {object} CollectionPermissionsResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id}/permissions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetCollectionPermissions(c *gin.Context) {
+	collection, _, _, ok := h.accessibleCollection(c, c.Param("id"), models.RoleViewer)
+	if !ok {
+		return
+	}
+	members, err := h.collectionRepo.ListMembers(c.Request.Context(), collection.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list members"})
+		return
+	}
+	c.JSON(http.StatusOK, CollectionPermissionsResponse{Restricted: collection.Restricted, Members: members})
+}
+
+// @Summary Set the permissions of a collection
+// @Description Restrict a collection, the collections inside it and their jobs to admins and the given members, each with at most the given role (editor or viewer) in it, or open it to the workspace again. Restricted jobs are left out of the jobs list and answered 404 to everyone else. Replaces the members.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID"
+// @Param request body CollectionPermissionsRequest true "Permissions"
+// @Success 200 {object} CollectionPermissionsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/collections/{id}/permissions [put]
+// @Security BearerAuth
+func (h *Handler) UpdateCollectionPermissions(c *gin.Context) {
+	found, _, _, ok := h.accessibleCollection(c, c.Param("id"), models.RoleViewer)
+	if !ok {
+		return
+	}
+	var req CollectionPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	collection := found.Collection
+	collection.Restricted = req.Restricted
+	members := make([]models.CollectionMember, 0, len(req.Members))
+	seen := map[uint]bool{}
+	for _, member := range req.Members {
+		if member.Role != models.RoleEditor && member.Role != models.RoleViewer {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Member roles must be editor or viewer"})
+			return
+		}
+		if seen[member.UserID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User " + strconv.FormatUint(uint64(member.UserID), 10) + " is listed twice"})
+			return
+		}
+		seen[member.UserID] = true
+		if _, err := h.userService.GetUser(c.Request.Context(), member.UserID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "User " + strconv.FormatUint(uint64(member.UserID), 10) + " not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+			return
+		}
+		members = append(members, models.CollectionMember{CollectionID: collection.ID, UserID: member.UserID, Role: member.Role})
+	}
+
+	if err := h.collectionRepo.SetPermissions(c.Request.Context(), &collection, members); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save permissions"})
+		return
+	}
+	c.JSON(http.StatusOK, CollectionPermissionsResponse{Restricted: collection.Restricted, Members: members})
+}
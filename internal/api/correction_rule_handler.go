@@ -0,0 +1,424 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// correctionRulePageSize is how many jobs are corrected per page when rules
+// are applied to every completed job
+const correctionRulePageSize = 100
+
+// CorrectionRuleRequest creates or replaces a correction rule
+type CorrectionRuleRequest struct {
+	Name          string `json:"name"`
+	Find          string `json:"find" binding:"required"`
+	Replace       string `json:"replace"`
+	Regex         bool   `json:"regex"`
+	CaseSensitive bool   `json:"case_sensitive"`
+	Enabled       *bool  `json:"enabled,omitempty"` // default true
+	Position      int    `json:"position"`
+}
+
+// CorrectionPreviewRequest corrects text, or the transcript of a job, without
+// saving it. Rule tries an unsaved rule instead of the organization's rules.
+type CorrectionPreviewRequest struct {
+	Text  string                 `json:"text,omitempty"`
+	JobID string                 `json:"job_id,omitempty"`
+	Rule  *CorrectionRuleRequest `json:"rule,omitempty"`
+}
+
+// CorrectionPreviewSegment is a transcript segment a preview changed
+type CorrectionPreviewSegment struct {
+	Index  int     `json:"index"`
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+	Before string  `json:"before"`
+	After  string  `json:"after"`
+}
+
+// CorrectionPreviewResponse is corrected text, or the changed segments of a
+// job's transcript, and how many replacements were made
+type CorrectionPreviewResponse struct {
+	Text         string                     `json:"text,omitempty"`
+	Segments     []CorrectionPreviewSegment `json:"segments,omitempty"`
+	Replacements int                        `json:"replacements"`
+}
+
+// ApplyCorrectionRulesRequest selects the jobs to correct; none selects every
+// completed job
+type ApplyCorrectionRulesRequest struct {
+	JobIDs []string `json:"job_ids"`
+}
+
+// ApplyCorrectionRulesResponse reports the jobs corrected
+type ApplyCorrectionRulesResponse struct {
+	Corrected map[string]int    `json:"corrected"` // job ID to replacements
+	Unchanged int               `json:"unchanged"`
+	Skipped   map[string]string `json:"skipped"` // job ID to reason
+}
+
+// correctionRuleFromRequest validates a request into rule, returning why it is invalid
+func correctionRuleFromRequest(req *CorrectionRuleRequest, rule *models.CorrectionRule) string {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Find = req.Find
+	rule.Replace = req.Replace
+	rule.Regex = req.Regex
+	rule.CaseSensitive = req.CaseSensitive
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.Position = req.Position
+	if _, err := transcription.CompileCorrectionRule(*rule); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// bindCorrectionRule validates a request into rule, answering 400 if it is invalid
+func bindCorrectionRule(c *gin.Context, rule *models.CorrectionRule) bool {
+	var req CorrectionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return false
+	}
+	if problem := correctionRuleFromRequest(&req, rule); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return false
+	}
+	return true
+}
+
+// correctionRule loads the rule named in the path, answering 404 if there is none
+func (h *Handler) correctionRule(c *gin.Context) (*models.CorrectionRule, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return nil, false
+	}
+	rule, err := h.correctionRuleRepo.FindByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Correction rule not found"})
+		return nil, false
+	}
+	return rule, true
+}
+
+// enabledCorrectionRules compiles the enabled rules ctx may see
+func (h *Handler) enabledCorrectionRules(ctx context.Context) (transcription.CorrectionRules, error) {
+	rules, err := h.correctionRuleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var compiled transcription.CorrectionRules
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if c, err := transcription.CompileCorrectionRule(rule); err == nil {
+			compiled = append(compiled, c)
+		}
+	}
+	return compiled, nil
+}
+
+// @Summary List correction rules
+// @Description List the find-and-replace rules of the request's organization, in the order they apply
+// @Tags correction-rules
+// @Produce json
+// @Success 200 {array} models.CorrectionRule
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/correction-rules [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListCorrectionRules(c *gin.Context) {
+	rules, err := h.correctionRuleRepo.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list correction rules"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Create a correction rule
+// @Description Create a find-and-replace rule that corrects the transcripts of the request's organization once they are transcribed. Exact rules replace their text where it is a whole word or phrase; regex rules replace matches of a regular expression, and may refer to its groups ($1) in their replacement. Rules ignore case unless case_sensitive is set, and apply in order of position.
+// @Tags correction-rules
+// @Accept json
+// @Produce json
+// @Param request body CorrectionRuleRequest true "Rule"
+// @Success 201 {object} models.CorrectionRule
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/correction-rules [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateCorrectionRule(c *gin.Context) {
+	rule := &models.CorrectionRule{
+		OrganizationID: c.GetString("organization_id"),
+		CreatedBy:      h.requestAuthor(c),
+	}
+	if !bindCorrectionRule(c, rule) {
+		return
+	}
+	if err := h.correctionRuleRepo.Create(c.Request.Context(), rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create correction rule"})
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary Get a correction rule
+// @Tags correction-rules
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.CorrectionRule
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/correction-rules/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetCorrectionRule(c *gin.Context) {
+	if rule, ok := h.correctionRule(c); ok {
+		c.JSON(http.StatusOK, rule)
+	}
+}
+
+// @Summary Update a correction rule
+// @Description Replace a correction rule. Transcripts it already corrected are left as they are until rules are applied to them again.
+// @Tags correction-rules
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param request body CorrectionRuleRequest true "Rule"
+// @Success 200 {object} models.CorrectionRule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/correction-rules/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateCorrectionRule(c *gin.Context) {
+	rule, ok := h.correctionRule(c)
+	if !ok {
+		return
+	}
+	if !bindCorrectionRule(c, rule) {
+		return
+	}
+	if err := h.correctionRuleRepo.Update(c.Request.Context(), rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update correction rule"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Delete a correction rule
+// @Tags correction-rules
+// @Param id path int true "Rule ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/correction-rules/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteCorrectionRule(c *gin.Context) {
+	rule, ok := h.correctionRule(c)
+	if !ok {
+		return
+	}
+	if err := h.correctionRuleRepo.Delete(c.Request.Context(), rule.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete correction rule"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Preview correction rules
+// @Description Correct text, or the transcript of a job, with the organization's enabled rules or with an unsaved rule, without saving anything. For a job, only the segments that would change are returned.
+// @Tags correction-rules
+// @Accept json
+// @Produce json
+// @Param request body CorrectionPreviewRequest true "Text or job, and optional rule"
+// @Success 200 {object} CorrectionPreviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/correction-rules/preview [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) PreviewCorrectionRules(c *gin.Context) {
+	var req CorrectionPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if (req.Text == "") == (req.JobID == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exactly one of text and job_id is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var rules transcription.CorrectionRules
+	if req.Rule != nil {
+		var rule models.CorrectionRule
+		if problem := correctionRuleFromRequest(req.Rule, &rule); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+		compiled, _ := transcription.CompileCorrectionRule(rule)
+		rules = transcription.CorrectionRules{compiled}
+	} else {
+		var err error
+		if rules, err = h.enabledCorrectionRules(ctx); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load correction rules"})
+			return
+		}
+	}
+
+	if req.Text != "" {
+		text, n := rules.Apply(req.Text)
+		c.JSON(http.StatusOK, CorrectionPreviewResponse{Text: text, Replacements: n})
+		return
+	}
+
+	job, err := h.jobRepo.FindByID(ctx, req.JobID)
+	if err != nil || !models.WorkspaceVisible(ctx, job.Workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+		return
+	}
+	if job, err = h.newJobFieldFilter(c).job(job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+	if job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+	var transcript interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(*job.Transcript), &transcript); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transcript"})
+		return
+	}
+	resp := CorrectionPreviewResponse{Segments: []CorrectionPreviewSegment{}}
+	for i, segment := range transcript.Segments {
+		text, n := rules.Apply(segment.Text)
+		if n == 0 {
+			continue
+		}
+		resp.Segments = append(resp.Segments, CorrectionPreviewSegment{
+			Index: i, Start: segment.Start, End: segment.End, Before: segment.Text, After: text,
+		})
+		resp.Replacements += n
+	}
+	if len(transcript.Segments) == 0 {
+		resp.Text, resp.Replacements = rules.Apply(transcript.Text)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Apply correction rules to existing transcripts
+// @Description Correct the stored transcripts of completed jobs with the organization's enabled rules, e.g. after adding a rule. Without job_ids, every completed job of the organization is corrected. Finalized jobs and jobs that are not completed are skipped. Each corrected transcript is recorded in the audit log.
+// @Tags correction-rules
+// @Accept json
+// @Produce json
+// @Param request body ApplyCorrectionRulesRequest false "Jobs to correct"
+// @Success 200 {object} ApplyCorrectionRulesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/correction-rules/apply [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ApplyCorrectionRules(c *gin.Context) {
+	var req ApplyCorrectionRulesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			return
+		}
+	}
+	ctx := c.Request.Context()
+	rules, err := h.enabledCorrectionRules(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load correction rules"})
+		return
+	}
+
+	jobIDs := req.JobIDs
+	if len(jobIDs) == 0 {
+		statuses := []models.JobStatus{models.StatusCompleted}
+		for offset := 0; ; offset += correctionRulePageSize {
+			jobs, err := h.jobRepo.ListByStatusAndTitle(ctx, statuses, "", offset, correctionRulePageSize)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+				return
+			}
+			for _, job := range jobs {
+				jobIDs = append(jobIDs, job.ID)
+			}
+			if len(jobs) < correctionRulePageSize {
+				break
+			}
+		}
+	}
+
+	resp := ApplyCorrectionRulesResponse{Corrected: map[string]int{}, Skipped: map[string]string{}}
+	for _, id := range jobIDs {
+		n, reason := h.correctJob(ctx, id, rules)
+		switch {
+		case reason != "":
+			resp.Skipped[id] = reason
+		case n == 0:
+			resp.Unchanged++
+		default:
+			resp.Corrected[id] = n
+			h.recordAudit(c, models.AuditTranscriptEdited, "transcription", id, map[string]interface{}{"correction_rules": n})
+		}
+	}
+	logger.Info("Applied correction rules to existing jobs", "corrected", len(resp.Corrected),
+		"unchanged", resp.Unchanged, "skipped", len(resp.Skipped))
+	c.JSON(http.StatusOK, resp)
+}
+
+// correctJob corrects a job's stored transcript, returning how many
+// replacements were made, or why it was skipped
+func (h *Handler) correctJob(ctx context.Context, jobID string, rules transcription.CorrectionRules) (int, string) {
+	job, err := h.jobRepo.FindByID(ctx, jobID)
+	if err != nil || !models.WorkspaceVisible(ctx, job.Workspace) {
+		return 0, "job not found"
+	}
+	if job.FinalizedAt != nil {
+		return 0, "job is finalized"
+	}
+	if job.Status != models.StatusCompleted {
+		return 0, "job is " + string(job.Status)
+	}
+	if job.Transcript == nil {
+		return 0, "transcript not available"
+	}
+	var transcript interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(*job.Transcript), &transcript); err != nil {
+		return 0, "transcript could not be parsed"
+	}
+	n := rules.ApplyTranscript(&transcript)
+	if n == 0 {
+		return 0, ""
+	}
+	encoded, err := json.Marshal(transcript)
+	if err != nil {
+		return 0, "transcript could not be encoded"
+	}
+	if err := h.jobRepo.UpdateTranscript(ctx, jobID, string(encoded)); err != nil {
+		if errors.Is(err, repository.ErrJobFinalized) {
+			return 0, "job is finalized"
+		}
+		logger.Error("Failed to save corrected transcript", "job_id", jobID, "error", err)
+		return 0, "failed to update job"
+	}
+	return n, ""
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reportDays is how far back cost and usage reports go by default
+const reportDays = 30
+
+// CostReportResponse is what jobs are estimated to have cost, by group
+type CostReportResponse struct {
+	Currency string               `json:"currency"`
+	GroupBy  string               `json:"group_by"`
+	From     string               `json:"from"` // first day, inclusive
+	To       string               `json:"to"`   // last day, inclusive
+	Groups   []models.CostSummary `json:"groups"`
+	Total    float64              `json:"total"`
+}
+
+// reportRange parses the from and to days of a report, defaulting to the last
+// 30 days, and answers 400 if they are not YYYY-MM-DD or out of order
+func reportRange(c *gin.Context) (time.Time, time.Time, bool) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to, from := today, today.AddDate(0, 0, 1-reportDays)
+	for param, day := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a date (YYYY-MM-DD)"})
+			return time.Time{}, time.Time{}, false
+		}
+		*day = t
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// @Summary Report job costs
+// @Description Report what the jobs completed between two days are estimated to have cost at the per-minute adapter prices of ADAPTER_COST_PER_MINUTE, grouped by day, profile, API key or the adapter that transcribed them. Each job's own estimate is its estimated_cost. Replays are not counted.
+// @Tags transcription
+// @Produce json
+// @Param group_by query string false "Grouping: day, profile, api_key or adapter" default(day)
+// @Param from query string false "First day (YYYY-MM-DD, UTC); defaults to 30 days ago"
+// @Param to query string false "Last day (YYYY-MM-DD, UTC); defaults to today"
+// @Success 200 {object} CostReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/costs [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetCosts(c *gin.Context) {
+	groupBy := c.DefaultQuery("group_by", models.CostByDay)
+	switch groupBy {
+	case models.CostByDay, models.CostByProfile, models.CostByAPIKey, models.CostByAdapter:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be day, profile, api_key or adapter"})
+		return
+	}
+	from, to, ok := reportRange(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.jobRepo.SummarizeCosts(c.Request.Context(), groupBy, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to summarize costs"})
+		return
+	}
+	response := CostReportResponse{
+		Currency: h.config.CostCurrency,
+		GroupBy:  groupBy,
+		From:     from.Format(time.DateOnly),
+		To:       to.Format(time.DateOnly),
+		Groups:   []models.CostSummary{},
+	}
+	for _, group := range groups {
+		response.Total += group.Cost
+		response.Groups = append(response.Groups, group)
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustodyVerification reports whether a manifest's signature is valid
+type CustodyVerification struct {
+	Valid bool `json:"valid"`
+}
+
+// CustodyPublicKey is the key custody manifests are verified with
+type CustodyPublicKey struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`
+	PublicKey string `json:"public_key"` // base64 of the raw 32-byte key
+	PEM       string `json:"pem"`        // PKIX, for tools such as openssl
+}
+
+// @Summary Get chain-of-custody manifest
+// @Description Generate a signed manifest of a job for evidentiary use: the SHA-256 of its audio now and when it was processed, the hash of the transcript, every processing run with model and adapter versions, and the audit trail of who finalized or changed it. JSON manifests can be checked with the verify endpoint; the PDF report embeds the signed JSON. Each export is itself recorded in the audit log.
+// @Tags transcription
+// @Produce json
+// @Produce application/pdf
+// @Param id path string true "Job ID"
+// @Param format query string false "json or pdf" default(json)
+// @Success 200 {object} export.CustodyManifest
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/transcription/{id}/custody [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetCustodyManifest(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or pdf"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var executions []models.TranscriptionJobExecution
+	if err := database.DB.WithContext(ctx).Where("transcription_job_id = ?", job.ID).
+		Order("started_at ASC").Find(&executions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load executions"})
+		return
+	}
+	var events []models.AuditEvent
+	if err := database.DB.WithContext(ctx).Where("resource_type = ? AND resource_id = ?", "transcription", job.ID).
+		Order("created_at ASC, id ASC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load audit events"})
+		return
+	}
+
+	manifest := h.custodyManifest(c, job, executions, events)
+	if err := manifest.Sign(h.config.CustodySigningKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign manifest"})
+		return
+	}
+	h.recordAudit(c, models.AuditCustodyExported, "transcription", job.ID, map[string]interface{}{"format": format})
+
+	filename := fmt.Sprintf("custody-%s.%s", job.ID, format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "pdf" {
+		data, err := export.CustodyPDF(manifest)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render manifest"})
+			return
+		}
+		c.Data(http.StatusOK, "application/pdf", data)
+		return
+	}
+	c.JSON(http.StatusOK, manifest)
+}
+
+// custodyManifest assembles the unsigned manifest of a job
+func (h *Handler) custodyManifest(c *gin.Context, job *models.TranscriptionJob, executions []models.TranscriptionJobExecution, events []models.AuditEvent) *export.CustodyManifest {
+	utc := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		u := t.UTC()
+		return &u
+	}
+
+	manifest := &export.CustodyManifest{
+		Version:     export.CustodyManifestVersion,
+		GeneratedAt: time.Now().UTC(),
+		GeneratedBy: h.requestAuthor(c),
+		Job: export.CustodyJob{
+			ID:          job.ID,
+			Status:      string(job.Status),
+			CreatedAt:   job.CreatedAt.UTC(),
+			UpdatedAt:   job.UpdatedAt.UTC(),
+			FinalizedAt: utc(job.FinalizedAt),
+		},
+		Transcript: export.CustodyTranscript{PostProcessedAt: utc(job.PostProcessedAt)},
+		Executions: []export.CustodyExecution{},
+		Events:     []export.CustodyEvent{},
+	}
+	if job.Title != nil {
+		manifest.Job.Title = *job.Title
+	}
+	if job.FinalizedBy != nil {
+		manifest.Job.FinalizedBy = *job.FinalizedBy
+	}
+	if job.SourceURL != nil {
+		manifest.Audio.SourceURL = config.RedactURL(*job.SourceURL)
+	}
+	if job.Transcript != nil {
+		sum := sha256.Sum256([]byte(*job.Transcript))
+		manifest.Transcript.SHA256 = hex.EncodeToString(sum[:])
+	}
+
+	if hash, size, err := hashAudio(job.AudioPath); err != nil {
+		logger.Warn("Failed to hash audio for custody manifest", "job_id", job.ID, "error", err)
+		manifest.Audio.Error = "audio file is not readable"
+	} else {
+		manifest.Audio.SHA256, manifest.Audio.SizeBytes = hash, size
+	}
+
+	var processedHash string
+	for _, execution := range executions {
+		entry := export.CustodyExecution{
+			ID:          execution.ID,
+			StartedAt:   execution.StartedAt.UTC(),
+			CompletedAt: utc(execution.CompletedAt),
+			Status:      string(execution.Status),
+			ModelFamily: execution.ActualParameters.ModelFamily,
+			Model:       execution.ActualParameters.Model,
+		}
+		if execution.InputHash != nil {
+			entry.InputSHA256 = *execution.InputHash
+		}
+		if execution.EnvironmentHash != nil {
+			entry.EnvironmentHash = *execution.EnvironmentHash
+		}
+		if execution.ReplayOf != nil {
+			entry.ReplayOf = *execution.ReplayOf
+		} else if entry.InputSHA256 != "" {
+			processedHash = entry.InputSHA256
+		}
+		// Adapter parameters may hold credentials, so only the adapters are listed
+		if execution.Snapshot != nil {
+			var snapshot models.ExecutionSnapshot
+			if err := json.Unmarshal([]byte(*execution.Snapshot), &snapshot); err == nil {
+				for _, step := range snapshot.Steps {
+					entry.Steps = append(entry.Steps, export.CustodyStep{
+						Stage:           step.Stage,
+						ModelID:         step.ModelID,
+						AdapterVersion:  step.AdapterVersion,
+						EnvironmentHash: step.EnvironmentHash,
+					})
+				}
+			}
+		}
+		manifest.Executions = append(manifest.Executions, entry)
+	}
+	if processedHash != "" && manifest.Audio.SHA256 != "" {
+		matches := processedHash == manifest.Audio.SHA256
+		manifest.Audio.MatchesProcessedAudio = &matches
+	}
+
+	for _, event := range events {
+		entry := export.CustodyEvent{At: event.CreatedAt.UTC(), Action: event.Action, Actor: event.Actor}
+		if event.Details != nil {
+			entry.Details = *event.Details
+		}
+		manifest.Events = append(manifest.Events, entry)
+	}
+	return manifest
+}
+
+// hashAudio returns the hex SHA-256 and size of an audio file
+func hashAudio(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// @Summary Verify chain-of-custody manifest
+// @Description Check that a JSON custody manifest was signed by this instance and has not been altered since
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param manifest body export.CustodyManifest true "Signed manifest"
+// @Success 200 {object} CustodyVerification
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/transcription/custody/verify [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) VerifyCustodyManifest(c *gin.Context) {
+	var manifest export.CustodyManifest
+	if err := c.ShouldBindJSON(&manifest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manifest: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, CustodyVerification{Valid: manifest.Verify(h.custodyPublicKey())})
+}
+
+// custodyPublicKey returns the public half of the custody signing key, or nil
+// if none is configured
+func (h *Handler) custodyPublicKey() ed25519.PublicKey {
+	if len(h.config.CustodySigningKey) != ed25519.PrivateKeySize {
+		return nil
+	}
+	return h.config.CustodySigningKey.Public().(ed25519.PublicKey)
+}
+
+// @Summary Get custody signing public key
+// @Description Return the Ed25519 public key custody manifests are signed with, so they can be verified without this instance: the signature covers the manifest serialized as JSON without its signature field
+// @Tags transcription
+// @Produce json
+// @Success 200 {object} CustodyPublicKey
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/custody/public-key [get]
+func (h *Handler) GetCustodyPublicKey(c *gin.Context) {
+	key := h.custodyPublicKey()
+	if key == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No custody signing key configured"})
+		return
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode public key"})
+		return
+	}
+	c.JSON(http.StatusOK, CustodyPublicKey{
+		Algorithm: export.CustodySignatureAlgorithm,
+		KeyID:     export.CustodyKeyID(key),
+		PublicKey: base64.StdEncoding.EncodeToString(key),
+		PEM:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	})
+}
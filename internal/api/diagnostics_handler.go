@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/registry"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Diagnostics bundle limits
+const (
+	diagnosticsRecentErrors   = 20
+	diagnosticsMessageChars   = 300
+	diagnosticsToolTimeout    = 5 * time.Second
+	diagnosticsToolOutputLine = 200
+)
+
+// buildInfo describes the running binary; the server sets it at startup
+var buildInfo = DiagnosticsVersions{Scriberr: "dev", Commit: "none", Built: "unknown"}
+
+// SetBuildInfo records the version of the running binary for diagnostics
+func SetBuildInfo(version, commit, date string) {
+	buildInfo.Scriberr, buildInfo.Commit, buildInfo.Built = version, commit, date
+}
+
+// DiagnosticsBundle is a redacted snapshot of an installation for bug reports.
+// It holds no transcripts, titles, file names, credentials or usage volumes.
+type DiagnosticsBundle struct {
+	GeneratedAt  time.Time                    `json:"generated_at"`
+	Versions     DiagnosticsVersions          `json:"versions"`
+	System       DiagnosticsSystem            `json:"system"`
+	Config       map[string]interface{}       `json:"config"`
+	Environments []registry.EnvironmentStatus `json:"environments"`
+	Models       map[string]bool              `json:"models"`
+	Queue        map[string]interface{}       `json:"queue"`
+	RecentErrors []DiagnosticsError           `json:"recent_errors"`
+}
+
+// DiagnosticsVersions lists the versions of Scriberr and the tools it runs
+type DiagnosticsVersions struct {
+	Scriberr string            `json:"scriberr"`
+	Commit   string            `json:"commit"`
+	Built    string            `json:"built"`
+	Go       string            `json:"go"`
+	Tools    map[string]string `json:"tools"` // first line of each tool's version output, or the error
+}
+
+// DiagnosticsSystem describes the host
+type DiagnosticsSystem struct {
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	CPUs       int    `json:"cpus"`
+	Goroutines int    `json:"goroutines"`
+	HeapMB     uint64 `json:"heap_mb"`
+}
+
+// DiagnosticsError is a recent failed execution, with paths and URLs removed
+// from its message
+type DiagnosticsError struct {
+	FailedAt    *time.Time `json:"failed_at,omitempty"`
+	ModelFamily string     `json:"model_family"`
+	Model       string     `json:"model"`
+	Diarize     bool       `json:"diarize"`
+	Message     string     `json:"message"`
+}
+
+var (
+	diagnosticsURLPattern  = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://\S+`)
+	diagnosticsPathPattern = regexp.MustCompile(`(?:[A-Za-z]:)?(?:[/\\][^\s/\\:'"]+){2,}[/\\]?`)
+)
+
+// redactErrorMessage removes URLs and file paths, which may name users' files,
+// from an error message
+func redactErrorMessage(message string) string {
+	message = diagnosticsURLPattern.ReplaceAllString(message, "[url]")
+	message = diagnosticsPathPattern.ReplaceAllString(message, "[path]")
+	if len(message) > diagnosticsMessageChars {
+		message = message[:diagnosticsMessageChars] + "..."
+	}
+	return message
+}
+
+// toolVersion runs a tool's version command and returns the first line of output
+func toolVersion(ctx context.Context, name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsToolTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("unavailable: %v", err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if len(line) > diagnosticsToolOutputLine {
+		line = line[:diagnosticsToolOutputLine]
+	}
+	return line
+}
+
+// @Summary Get diagnostics bundle
+// @Description Produce a redacted diagnostics bundle to attach to bug reports: versions of Scriberr and its tools, the configuration with secrets and URL credentials stripped, model environment statuses, queue state and recent execution errors with paths and URLs removed. It contains no transcripts, titles, file names or usage volumes.
+// @Tags admin
+// @Produce json
+// @Param download query bool false "Serve as a file attachment"
+// @Success 200 {object} DiagnosticsBundle
+// @Router /api/v1/admin/diagnostics [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetDiagnostics(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	versions := buildInfo
+	versions.Go = runtime.Version()
+	versions.Tools = map[string]string{
+		"uv":     toolVersion(ctx, h.config.UVPath, "--version"),
+		"ffmpeg": toolVersion(ctx, "ffmpeg", "-version"),
+		"yt-dlp": toolVersion(ctx, "yt-dlp", "--version"),
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	// Job totals measure usage rather than health
+	queueStats := h.taskQueue.GetQueueStats()
+	delete(queueStats, "completed_jobs")
+
+	var executions []models.TranscriptionJobExecution
+	if err := database.DB.WithContext(ctx).
+		Where("status = ? AND replay_of IS NULL", models.StatusFailed).
+		Order("started_at DESC").Limit(diagnosticsRecentErrors).
+		Find(&executions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load recent errors"})
+		return
+	}
+	recentErrors := make([]DiagnosticsError, 0, len(executions))
+	for _, execution := range executions {
+		message := ""
+		if execution.ErrorMessage != nil {
+			message = redactErrorMessage(*execution.ErrorMessage)
+		}
+		recentErrors = append(recentErrors, DiagnosticsError{
+			FailedAt:    execution.CompletedAt,
+			ModelFamily: execution.ActualParameters.ModelFamily,
+			Model:       execution.ActualParameters.Model,
+			Diarize:     execution.ActualParameters.Diarize,
+			Message:     message,
+		})
+	}
+
+	environments := h.unifiedProcessor.GetEnvironmentStatus()
+	for i := range environments {
+		environments[i].Error = redactErrorMessage(environments[i].Error)
+	}
+
+	bundle := DiagnosticsBundle{
+		GeneratedAt: time.Now().UTC(),
+		Versions:    versions,
+		System: DiagnosticsSystem{
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+			CPUs:       runtime.NumCPU(),
+			Goroutines: runtime.NumGoroutine(),
+			HeapMB:     mem.HeapAlloc / (1024 * 1024),
+		},
+		Config:       h.config.Redacted(),
+		Environments: environments,
+		Models:       h.unifiedProcessor.GetModelStatus(ctx),
+		Queue:        queueStats,
+		RecentErrors: recentErrors,
+	}
+
+	if c.Query("download") == "true" {
+		filename := fmt.Sprintf("scriberr-diagnostics-%s.json", bundle.GeneratedAt.Format("20060102-150405"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	c.JSON(http.StatusOK, bundle)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranscriptDiffResponse is a word-level diff of two transcripts of a job,
+// with the error rates of the second measured against the first
+type TranscriptDiffResponse struct {
+	JobID   string `json:"job_id"`
+	Base    string `json:"base"`
+	Against string `json:"against"`
+	*transcription.TextDiff
+}
+
+// diffText resolves a transcript reference of a job to its text, returning
+// why it cannot be compared and the status to answer with if it cannot.
+// References are current, revision:N (or N), shadow:ID and job:ID.
+func (h *Handler) diffText(c *gin.Context, job *models.TranscriptionJob, ref string) (string, int, string) {
+	ctx := c.Request.Context()
+	kind, value, _ := strings.Cut(ref, ":")
+	if _, err := strconv.Atoi(kind); err == nil && value == "" {
+		kind, value = "revision", kind
+	}
+
+	var encoded *string
+	switch kind {
+	case "", "current":
+		encoded = job.Transcript
+	case "revision":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return "", http.StatusBadRequest, "Invalid revision " + value
+		}
+		revision, err := h.jobRepo.FindTranscriptRevision(ctx, job.ID, n)
+		if err != nil {
+			return "", http.StatusNotFound, "Revision " + value + " not found"
+		}
+		encoded = &revision.Transcript
+	case "shadow":
+		// Candidate transcripts are only ever shown to admins
+		if !models.RoleAllows(c.GetString("role"), models.RoleAdmin) {
+			return "", http.StatusForbidden, "Only admins may compare shadow runs"
+		}
+		run, err := h.jobRepo.FindShadowRun(ctx, value)
+		if err != nil || run.TranscriptionJobID != job.ID {
+			return "", http.StatusNotFound, "Shadow run " + value + " not found"
+		}
+		encoded = run.Transcript
+	case "job":
+		other, err := h.jobRepo.FindByID(ctx, value)
+		if err != nil || !models.WorkspaceVisible(ctx, other.Workspace) {
+			return "", http.StatusNotFound, "Job " + value + " not found"
+		}
+		if other, err = h.newJobFieldFilter(c).job(other); err != nil {
+			return "", http.StatusInternalServerError, "Failed to get job"
+		}
+		encoded = other.Transcript
+	default:
+		return "", http.StatusBadRequest, "Unknown transcript " + ref + "; use current, revision:N, shadow:ID or job:ID"
+	}
+	if encoded == nil || *encoded == "" {
+		return "", http.StatusNotFound, "Transcript " + ref + " not available"
+	}
+	text, err := transcription.DecodeTranscriptText(*encoded)
+	if err != nil {
+		return "", http.StatusInternalServerError, "Failed to parse transcript " + ref
+	}
+	return text, 0, ""
+}
+
+// @Summary List transcript revisions
+// @Description List the transcripts a job has had, oldest first, without their content. Every transcript saved for the job, by transcription, re-transcription, correction rules or edits, is kept as a revision. The model that produced each is given when the transcript records it.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {array} models.TranscriptRevision
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/revisions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListTranscriptRevisions(c *gin.Context) {
+	revisions, err := h.jobRepo.ListTranscriptRevisions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list revisions"})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// @Summary Diff two transcripts
+// @Description Compute a word-level diff between two transcripts of a job, with the word and character error rates (WER, CER) of against measured with base as the reference, for model evaluation. A transcript is the job's current one (current, the default base), one of its revisions (revision:N, or N), the output of a candidate adapter in a shadow run of the job (shadow:ID, admins only) or the transcript of another job, such as the same audio transcribed with another profile (job:ID). Words are compared ignoring case and surrounding punctuation; chunks give them as written.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param against query string true "Transcript to compare"
+// @Param base query string false "Reference transcript (default current)"
+// @Success 200 {object} TranscriptDiffResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/diff [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DiffTranscripts(c *gin.Context) {
+	against, base := c.Query("against"), c.DefaultQuery("base", "current")
+	if against == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "against is required"})
+		return
+	}
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+		return
+	}
+	reference, status, problem := h.diffText(c, job, base)
+	if problem != "" {
+		c.JSON(status, gin.H{"error": problem})
+		return
+	}
+	hypothesis, status, problem := h.diffText(c, job, against)
+	if problem != "" {
+		c.JSON(status, gin.H{"error": problem})
+		return
+	}
+	c.JSON(http.StatusOK, TranscriptDiffResponse{
+		JobID:    job.ID,
+		Base:     base,
+		Against:  against,
+		TextDiff: transcription.DiffTexts(reference, hypothesis),
+	})
+}
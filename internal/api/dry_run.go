@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DryRunResponse is what submitting a job would do, without the job being
+// created or queued
+type DryRunResponse struct {
+	DryRun bool `json:"dry_run"`
+	*transcription.DryRunResult
+	Currency string `json:"currency,omitempty"` // of estimated_cost
+}
+
+// dryRunRequested reports whether a submission asks for a dry run, with the
+// dry_run query parameter or form field
+func dryRunRequested(c *gin.Context) bool {
+	value := c.Query("dry_run")
+	if value == "" {
+		value = c.PostForm("dry_run")
+	}
+	dryRun, _ := strconv.ParseBool(value)
+	return dryRun
+}
+
+// respondDryRun answers with what processing job would do
+func (h *Handler) respondDryRun(c *gin.Context, job *models.TranscriptionJob) {
+	result, err := h.unifiedProcessor.DryRun(c.Request.Context(), job)
+	if err != nil {
+		logger.Error("Dry run failed", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to plan job"})
+		return
+	}
+	// A dry run changes nothing, so there is nothing to audit
+	c.Set(auditRecordedKey, true)
+	response := DryRunResponse{DryRun: true, DryRunResult: result}
+	if result.EstimatedCost != nil {
+		response.Currency = h.config.CostCurrency
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,452 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportExtensions maps export formats to file extensions
+var exportExtensions = map[string]string{
+	export.FormatSRT:      ".srt",
+	export.FormatVTT:      ".vtt",
+	export.FormatJSON:     ".json",
+	export.FormatChapters: ".chapters.vtt",
+
+	export.FormatLRC:         ".lrc",
+	export.FormatEnhancedLRC: ".lrc",
+
+	export.FormatBilingual: ".bilingual.txt",
+
+	export.FormatCSV: ".csv",
+}
+
+// @Summary Export transcript
+// @Description Download the transcript as SubRip (srt), WebVTT (vtt), JSON (json), a WebVTT chapter track (chapters), synced lyrics with timed lines (lrc) or timed words (elrc, from word-level timestamps), study text interleaving each timed segment with its translation (bilingual, once the transcript has been translated), or a spreadsheet of segments (csv). Speakers use their custom names, and chapters, when generated, are included: as bracketed titles in SRT, NOTE blocks in VTT and a chapters array in JSON. JSON also includes the translation.
+// @Description When the recording's start time is known, CSV adds absolute start and end times and JSON its start time. Times are shown in the requested time zone, and CSV dates, decimal separators and field separators follow the requested locale; both default to the user's settings, then to UTC and ISO.
+// @Description WebVTT exports open with a NOTE block naming the job, title, model and language, give speakers as <v> voice spans so players can style each voice, and carry the requested cue settings on every cue.
+// @Tags transcription
+// @Produce plain
+// @Param id path string true "Job ID"
+// @Param format query string false "Export format (srt, vtt, json, chapters, lrc, elrc, bilingual, csv)" default(srt)
+// @Param timezone query string false "IANA time zone, e.g. Europe/Berlin"
+// @Param locale query string false "Locale, e.g. en-US or de-DE"
+// @Param line query string false "WebVTT cue line: a line number (negative counts from the bottom) or a percentage"
+// @Param position query string false "WebVTT cue position as a percentage, e.g. 50%"
+// @Param size query string false "WebVTT cue width as a percentage"
+// @Param align query string false "WebVTT cue text alignment (start, center, end, left, right)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/transcription/{id}/export [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportTranscript(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatSRT))
+	ext, ok := exportExtensions[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported export format %q", format)})
+		return
+	}
+
+	locale, location, err := h.exportLocale(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cueSettings := export.VTTCueSettings{Line: c.Query("line"), Position: c.Query("position"), Size: c.Query("size"), Align: c.Query("align")}
+	if err := cueSettings.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	title := jobTitle(job)
+	doc, err := h.exportDocument(ctx, job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transcript"})
+		return
+	}
+	if format == export.FormatChapters && len(doc.Chapters) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Chapters have not been generated for this transcript"})
+		return
+	}
+	if format == export.FormatBilingual && doc.Translation == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This transcript has not been translated"})
+		return
+	}
+	doc.Locale, doc.TimeZone = locale, location
+	doc.CueSettings = cueSettings
+
+	content, contentType, err := export.Render(doc, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render export"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s%s"`, exportFileName(title), ext))
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// jobTitle returns a job's title, or its ID when it has none
+func jobTitle(job *models.TranscriptionJob) string {
+	if job.Title != nil && *job.Title != "" {
+		return *job.Title
+	}
+	return job.ID
+}
+
+// exportDocument prepares a completed job's transcript for export, with its
+// chapters, translation, speaker names and tags
+func (h *Handler) exportDocument(ctx context.Context, job *models.TranscriptionJob) (*export.Document, error) {
+	doc, err := export.NewDocument(job.ID, jobTitle(job), *job.Transcript)
+	if err != nil {
+		return nil, err
+	}
+	if job.Chapters != nil {
+		if err := json.Unmarshal([]byte(*job.Chapters), &doc.Chapters); err != nil {
+			logger.Warn("Ignoring unreadable chapters", "job_id", job.ID, "error", err)
+		}
+	}
+	if job.Translation != nil {
+		if err := json.Unmarshal([]byte(*job.Translation), &doc.Translation); err != nil {
+			logger.Warn("Ignoring unreadable translation", "job_id", job.ID, "error", err)
+		}
+	}
+	if mappings, err := h.speakerMappingRepo.ListByJob(ctx, job.ID); err == nil && len(mappings) > 0 {
+		doc.Speakers = make(map[string]string, len(mappings))
+		for _, mapping := range mappings {
+			doc.Speakers[mapping.OriginalSpeaker] = mapping.CustomName
+		}
+	}
+	doc.RecordedAt = job.RecordedAt
+	doc.Tags = job.TagNames()
+	return doc, nil
+}
+
+// exportLocale returns the locale and time zone of an export: those requested,
+// else the user's settings, else the defaults
+func (h *Handler) exportLocale(c *gin.Context) (export.Locale, *time.Location, error) {
+	localeName, timeZone := c.Query("locale"), c.Query("timezone")
+	if userID, ok := c.Get("user_id"); ok && (localeName == "" || timeZone == "") {
+		if user, err := h.userRepo.FindByID(c.Request.Context(), userID.(uint)); err == nil {
+			if localeName == "" {
+				localeName = user.Locale
+			}
+			if timeZone == "" {
+				timeZone = user.TimeZone
+			}
+		}
+	}
+	locale, err := export.LookupLocale(localeName)
+	if err != nil {
+		return export.Locale{}, nil, err
+	}
+	location, err := export.LoadTimeZone(timeZone)
+	if err != nil {
+		return export.Locale{}, nil, err
+	}
+	return locale, location, nil
+}
+
+// exportFileName reduces a job title to a safe download file name
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r == '"' || r == '/' || r == '\\' || r < 0x20:
+			return '_'
+		default:
+			return r
+		}
+	}, strings.TrimSpace(title))
+	if name == "" {
+		return "transcript"
+	}
+	return name
+}
+
+// GenerateChaptersRequest selects the model used for chaptering
+type GenerateChaptersRequest struct {
+	Model string `json:"model,omitempty"` // defaults to the default summary model
+}
+
+// @Summary Generate chapters
+// @Description Split the transcript into topical chapters with titles and start/end times using the active LLM, replacing any earlier chapters
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body GenerateChaptersRequest false "Options"
+// @Success 200 {array} service.Chapter
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/chapters [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GenerateTranscriptChapters(c *gin.Context) {
+	var req GenerateChaptersRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	svc, _, err := h.getCachedLLMService(ctx)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	model := req.Model
+	if model == "" {
+		if settings, err := h.summaryRepo.GetSettings(ctx); err == nil {
+			model = settings.DefaultModel
+		}
+	}
+
+	chapters, err := service.GenerateChapters(ctx, svc, model, job.Transcript)
+	if err != nil {
+		logger.Error("Chaptering failed", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Chaptering failed: " + err.Error()})
+		return
+	}
+
+	data, err := json.Marshal(chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode chapters"})
+		return
+	}
+	encoded := string(data)
+	job.Chapters = &encoded
+	if err := h.jobRepo.UpdatePostProcessing(ctx, job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save chapters"})
+		return
+	}
+
+	c.JSON(http.StatusOK, chapters)
+}
+
+// TranslateTranscriptRequest selects the target language and model of a translation
+type TranslateTranscriptRequest struct {
+	Language string `json:"language" binding:"required"` // e.g. "English" or "de"
+	Model    string `json:"model,omitempty"`             // defaults to the default summary model
+}
+
+// @Summary Translate transcript
+// @Description Translate the transcript segment by segment into another language using the active LLM, replacing any earlier translation. The translation is included in JSON exports and interleaved with the original in bilingual exports.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body TranslateTranscriptRequest true "Options"
+// @Success 200 {object} service.Translation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/translation [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) TranslateTranscript(c *gin.Context) {
+	var req TranslateTranscriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	language := strings.TrimSpace(req.Language)
+	if language == "" || len(language) > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Language must be between 1 and 50 characters"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	svc, _, err := h.getCachedLLMService(ctx)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	model := req.Model
+	if model == "" {
+		if settings, err := h.summaryRepo.GetSettings(ctx); err == nil {
+			model = settings.DefaultModel
+		}
+	}
+
+	translation, err := service.TranslateTranscript(ctx, svc, model, job.Transcript, language)
+	if err != nil {
+		logger.Error("Translation failed", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Translation failed: " + err.Error()})
+		return
+	}
+
+	data, err := json.Marshal(translation)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode translation"})
+		return
+	}
+	encoded := string(data)
+	job.Translation = &encoded
+	if err := h.jobRepo.UpdatePostProcessing(ctx, job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// Flashcard sources
+const (
+	flashcardSourceNotes = "notes" // highlighted quotes and the notes written on them
+	flashcardSourceQA    = "qa"    // cards extracted by the LLM
+)
+
+const (
+	defaultFlashcardCount = 20
+	maxFlashcardCount     = 100
+)
+
+// @Summary Export flashcards
+// @Description Download a flashcard deck as CSV or an Anki package (apkg). With source=notes (the default) each note becomes a card with the highlighted quote on the front and the note on the back; notes without text are skipped. With source=qa the active LLM extracts comprehension questions, or, when language is set, vocabulary cards translating phrases from the transcript into that language.
+// @Tags transcription
+// @Produce octet-stream
+// @Param id path string true "Job ID"
+// @Param format query string false "Deck format (csv, apkg)" default(csv)
+// @Param source query string false "Card source (notes, qa)" default(notes)
+// @Param count query int false "Maximum number of LLM-extracted cards (1-100)" default(20)
+// @Param language query string false "Translate vocabulary cards into this language (qa only)"
+// @Param model query string false "LLM model (qa only; defaults to the default summary model)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/flashcards [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportFlashcards(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatFlashcardsCSV))
+	if format != export.FormatFlashcardsCSV && format != export.FormatFlashcardsAnki {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported flashcard format %q", format)})
+		return
+	}
+	source := strings.ToLower(c.DefaultQuery("source", flashcardSourceNotes))
+	if source != flashcardSourceNotes && source != flashcardSourceQA {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source must be notes or qa"})
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(defaultFlashcardCount)))
+	if err != nil || count < 1 || count > maxFlashcardCount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("count must be between 1 and %d", maxFlashcardCount)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	title := job.ID
+	if job.Title != nil && *job.Title != "" {
+		title = *job.Title
+	}
+	deck := &export.Deck{JobID: job.ID, Name: title}
+
+	switch source {
+	case flashcardSourceNotes:
+		notes, err := h.noteRepo.ListByJob(ctx, job.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list notes"})
+			return
+		}
+		sort.SliceStable(notes, func(i, j int) bool { return notes[i].StartTime < notes[j].StartTime })
+		for _, note := range notes {
+			quote := strings.TrimSpace(note.Quote)
+			content := strings.TrimSpace(note.Content)
+			if quote == "" || content == "" {
+				continue
+			}
+			deck.Cards = append(deck.Cards, service.Flashcard{Front: quote, Back: content, Start: note.StartTime})
+		}
+	case flashcardSourceQA:
+		svc, _, err := h.getCachedLLMService(ctx)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		model := c.Query("model")
+		if model == "" {
+			if settings, err := h.summaryRepo.GetSettings(ctx); err == nil {
+				model = settings.DefaultModel
+			}
+		}
+		deck.Cards, err = service.ExtractFlashcards(ctx, svc, model, job.Transcript, service.FlashcardOptions{
+			Count:    count,
+			Language: strings.TrimSpace(c.Query("language")),
+		})
+		if err != nil {
+			logger.Error("Flashcard extraction failed", "job_id", job.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Flashcard extraction failed: " + err.Error()})
+			return
+		}
+	}
+	if len(deck.Cards) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No flashcards: add notes to highlighted passages or use source=qa"})
+		return
+	}
+
+	content, contentType, err := export.RenderDeck(deck, format)
+	if err != nil {
+		logger.Error("Failed to render flashcards", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render flashcards"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, exportFileName(title), format))
+	c.Data(http.StatusOK, contentType, content)
+}
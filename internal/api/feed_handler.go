@@ -0,0 +1,245 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedSubscriptionRequest represents a request to create or update a feed subscription
+type FeedSubscriptionRequest struct {
+	URL                 string  `json:"url" binding:"required"`
+	Title               string  `json:"title,omitempty"`
+	ProfileID           *string `json:"profile_id,omitempty"`
+	PollIntervalMinutes int     `json:"poll_interval_minutes,omitempty"`
+	BackfillCount       int     `json:"backfill_count,omitempty"`
+	Active              *bool   `json:"active,omitempty"`
+}
+
+// @Summary List feed subscriptions
+// @Description Get the RSS/podcast feed subscriptions of the request's workspace
+// @Tags feeds
+// @Produce json
+// @Success 200 {array} models.FeedSubscription
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/feeds [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListFeedSubscriptions(c *gin.Context) {
+	subs, err := h.feedService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list feed subscriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+// @Summary Subscribe to feed
+// @Description Register an RSS/podcast feed. New episodes are downloaded and transcribed with the given profile (or the default profile), and jobs are tagged with the episode title, publish date and GUID. On the first poll only the newest backfill_count episodes are transcribed.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param request body FeedSubscriptionRequest true "Feed subscription"
+// @Success 201 {object} models.FeedSubscription
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/feeds [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateFeedSubscription(c *gin.Context) {
+	var req FeedSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.validFeedProfile(c, req.ProfileID) {
+		return
+	}
+
+	sub := models.FeedSubscription{
+		URL:                 req.URL,
+		Title:               req.Title,
+		ProfileID:           req.ProfileID,
+		PollIntervalMinutes: req.PollIntervalMinutes,
+		BackfillCount:       req.BackfillCount,
+		Workspace:           h.requestWorkspace(c),
+	}
+	if err := h.feedService.Subscribe(c.Request.Context(), &sub); err != nil {
+		if errors.Is(err, service.ErrInvalidSourceURL) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to create feed subscription", "url", req.URL, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feed subscription"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// @Summary Get feed subscription
+// @Description Get a feed subscription by ID
+// @Tags feeds
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} models.FeedSubscription
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/feeds/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetFeedSubscription(c *gin.Context) {
+	sub, err := h.feedService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed subscription not found"})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// @Summary Update feed subscription
+// @Description Update a feed subscription's URL, profile, poll interval or active flag
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body FeedSubscriptionRequest true "Feed subscription"
+// @Success 200 {object} models.FeedSubscription
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/feeds/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateFeedSubscription(c *gin.Context) {
+	sub, err := h.feedService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed subscription not found"})
+		return
+	}
+
+	var req FeedSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.validFeedProfile(c, req.ProfileID) {
+		return
+	}
+
+	sub.URL = req.URL
+	sub.ProfileID = req.ProfileID
+	sub.PollIntervalMinutes = req.PollIntervalMinutes
+	sub.BackfillCount = req.BackfillCount
+	if req.Title != "" {
+		sub.Title = req.Title
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	if err := h.feedService.Update(c.Request.Context(), sub); err != nil {
+		if errors.Is(err, service.ErrInvalidSourceURL) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feed subscription"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// @Summary Delete feed subscription
+// @Description Stop polling a feed. Transcriptions already created from it are kept.
+// @Tags feeds
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/feeds/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteFeedSubscription(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.feedService.Get(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed subscription not found"})
+		return
+	}
+
+	if err := h.feedService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete feed subscription"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feed subscription deleted successfully"})
+}
+
+// @Summary List feed episodes
+// @Description List the episodes seen in a feed and the transcription job created for each
+// @Tags feeds
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} models.FeedEpisode
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/feeds/{id}/episodes [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListFeedEpisodes(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.feedService.Get(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed subscription not found"})
+		return
+	}
+
+	episodes, err := h.feedService.ListEpisodes(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list feed episodes"})
+		return
+	}
+	c.JSON(http.StatusOK, episodes)
+}
+
+// @Summary Poll feed now
+// @Description Fetch the feed immediately and queue any new episodes
+// @Tags feeds
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/v1/feeds/{id}/poll [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) PollFeedSubscription(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.feedService.Get(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed subscription not found"})
+		return
+	}
+
+	queued, err := h.feedService.Poll(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"queued": queued})
+}
+
+// validFeedProfile checks that an explicitly requested profile exists
+func (h *Handler) validFeedProfile(c *gin.Context, profileID *string) bool {
+	if profileID == nil || *profileID == "" {
+		return true
+	}
+	if _, err := h.profileRepo.FindByID(c.Request.Context(), *profileID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldPermissionRequest sets the job fields hidden from a role
+type FieldPermissionRequest struct {
+	HiddenFields []string `json:"hidden_fields" binding:"max=10"`
+}
+
+// FieldPermissionResponse is the job fields hidden from a role in a workspace
+type FieldPermissionResponse struct {
+	*models.FieldPermission
+	HiddenFields []string `json:"hidden_fields"`
+}
+
+func newFieldPermissionResponse(permission *models.FieldPermission) FieldPermissionResponse {
+	return FieldPermissionResponse{FieldPermission: permission, HiddenFields: permission.HiddenFieldSet().List()}
+}
+
+// hiddenJobFields returns the job fields the request's role may not see in a
+// workspace; admins see every field
+func (h *Handler) hiddenJobFields(c *gin.Context, workspace string) (models.JobFieldSet, error) {
+	role := c.GetString("role")
+	if models.RoleAllows(role, models.RoleAdmin) {
+		return nil, nil
+	}
+	permission, err := h.fieldPermissionRepo.Find(c.Request.Context(), workspace, role)
+	if err != nil {
+		return nil, err
+	}
+	return permission.HiddenFieldSet(), nil
+}
+
+// jobFieldFilter caches hiddenJobFields per workspace while serializing a
+// list of jobs
+type jobFieldFilter struct {
+	h      *Handler
+	c      *gin.Context
+	hidden map[string]models.JobFieldSet
+}
+
+func (h *Handler) newJobFieldFilter(c *gin.Context) *jobFieldFilter {
+	return &jobFieldFilter{h: h, c: c, hidden: map[string]models.JobFieldSet{}}
+}
+
+// fields returns the fields hidden in a workspace
+func (f *jobFieldFilter) fields(workspace string) (models.JobFieldSet, error) {
+	if hidden, ok := f.hidden[workspace]; ok {
+		return hidden, nil
+	}
+	hidden, err := f.h.hiddenJobFields(f.c, workspace)
+	if err != nil {
+		return nil, err
+	}
+	f.hidden[workspace] = hidden
+	return hidden, nil
+}
+
+// job returns job without the fields hidden from the request
+func (f *jobFieldFilter) job(job *models.TranscriptionJob) (*models.TranscriptionJob, error) {
+	hidden, err := f.fields(job.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	return job.Redacted(hidden), nil
+}
+
+// jobs returns jobs without the fields hidden from the request
+func (f *jobFieldFilter) jobs(jobs []models.TranscriptionJob) ([]models.TranscriptionJob, error) {
+	redacted := make([]models.TranscriptionJob, len(jobs))
+	for i := range jobs {
+		job, err := f.job(&jobs[i])
+		if err != nil {
+			return nil, err
+		}
+		redacted[i] = *job
+	}
+	return redacted, nil
+}
+
+// RequireJobField answers 403 to requests for a field, of the job named in the
+// given path parameter, that the request's role may not see. Unknown jobs are
+// left to the handler.
+func (h *Handler) RequireJobField(param, field string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param(param)
+		if id == "" {
+			c.Next()
+			return
+		}
+		job, err := h.jobRepo.FindByID(c.Request.Context(), id)
+		if err != nil {
+			c.Next()
+			return
+		}
+		hidden, err := h.hiddenJobFields(c, job.Workspace)
+		if err != nil {
+			logger.Error("Failed to get field permissions", "workspace", job.Workspace, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check field permissions"})
+			c.Abort()
+			return
+		}
+		if hidden[field] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role may not access the " + field + " of this job"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// @Summary List field permissions
+// @Description Get the job fields hidden from editors and viewers in the request's workspace, or for admins another with the workspace query parameter. Roles without an entry see every field.
+// @Tags workspace
+// @Produce json
+// @Param workspace query string false "Workspace to get the permissions of (admins only)"
+// @Success 200 {array} FieldPermissionResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/workspace/field-permissions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListFieldPermissions(c *gin.Context) {
+	workspace, ok := h.settingsWorkspace(c)
+	if !ok {
+		return
+	}
+	permissions, err := h.fieldPermissionRepo.List(c.Request.Context(), workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list field permissions"})
+		return
+	}
+	responses := make([]FieldPermissionResponse, len(permissions))
+	for i := range permissions {
+		responses[i] = newFieldPermissionResponse(&permissions[i])
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// @Summary Set field permissions of a role
+// @Description Hide job fields from the editors or viewers of the request's workspace, or of another with the workspace query parameter. Hiding audio stops playback, audio URLs and bundles and leaves the audio's paths and source URL out of jobs; hiding transcript leaves out the transcript and what is derived from its text, and stops transcript reads, exports, flashcards, bundles, sentiment, chapters, translation and search hits in it; hiding summary leaves out summaries and action items and their search hits. Admins always see every field. An empty list shows every field again.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param role path string true "Role (editor or viewer)"
+// @Param workspace query string false "Workspace to set the permissions of"
+// @Param request body FieldPermissionRequest true "Hidden fields: audio, transcript and/or summary"
+// @Success 200 {object} FieldPermissionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/workspace/field-permissions/{role} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateFieldPermission(c *gin.Context) {
+	workspace, ok := h.settingsWorkspace(c)
+	if !ok {
+		return
+	}
+	role := c.Param("role")
+	if role != models.RoleEditor && role != models.RoleViewer {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be editor or viewer"})
+		return
+	}
+	var req FieldPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	hidden := models.JobFieldSet{}
+	for _, field := range req.HiddenFields {
+		if !models.ValidJobField(field) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown field " + field + "; fields are audio, transcript and summary"})
+			return
+		}
+		hidden[field] = true
+	}
+
+	permission := &models.FieldPermission{
+		Workspace:    workspace,
+		Role:         role,
+		HiddenFields: strings.Join(hidden.List(), "\n"),
+		UpdatedBy:    h.requestAuthor(c),
+	}
+	if err := h.fieldPermissionRepo.Save(c.Request.Context(), permission); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save field permissions"})
+		return
+	}
+	c.JSON(http.StatusOK, newFieldPermissionResponse(permission))
+}
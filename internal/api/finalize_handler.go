@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FinalizeTranscriptRequest optionally explains a finalization
+type FinalizeTranscriptRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500"`
+}
+
+// finalizedMessage explains a change refused because the transcript is finalized
+const finalizedMessage = "Transcript is finalized; only an admin can change it"
+
+// finalizedLockedRoutes change a job's transcript or reprocess it, so they are
+// closed to non-admins once the transcript is finalized. The repositories refuse
+// such writes too, wherever they come from; the routes are checked up front so
+// admin changes through them are audited.
+var finalizedLockedRoutes = map[string]bool{
+	"POST /api/v1/transcription/:id/start":             true,
+	"POST /api/v1/transcription/:id/replay":            true,
+	"PUT /api/v1/transcription/:id/title":              true,
+	"PUT /api/v1/transcription/:id/recorded-at":        true,
+	"POST /api/v1/transcription/:id/speakers":          true,
+	"POST /api/v1/transcription/:id/speakers/identify": true,
+	"POST /api/v1/transcription/:id/sentiment":         true,
+	"POST /api/v1/transcription/:id/chapters":          true,
+	"POST /api/v1/transcription/:id/translation":       true,
+	"DELETE /api/v1/transcription/:id":                 true,
+}
+
+// RequireUnfinalized rejects changes to finalized transcripts with a 409, except
+// by admins, whose writes are let through the repositories and whose successful
+// changes through finalizedLockedRoutes are recorded in the audit log
+func (h *Handler) RequireUnfinalized() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") == models.RoleAdmin {
+			c.Request = c.Request.WithContext(repository.WithFinalizedOverride(c.Request.Context()))
+		}
+		route := c.Request.Method + " " + middleware.RouteKey(c)
+		if !finalizedLockedRoutes[route] {
+			c.Next()
+			return
+		}
+		job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+		if err != nil || job.FinalizedAt == nil {
+			c.Next()
+			return
+		}
+		if c.GetString("role") != models.RoleAdmin {
+			c.JSON(http.StatusConflict, gin.H{"error": finalizedMessage})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+		if c.Writer.Status() < http.StatusBadRequest {
+			h.recordAudit(c, models.AuditFinalizedJobModified, "transcription", job.ID, map[string]interface{}{"route": route})
+		}
+	}
+}
+
+// finalizedConflict responds with a 409 if err is a write the repositories
+// refused because the job is finalized, and reports whether it did
+func finalizedConflict(c *gin.Context, err error) bool {
+	if !errors.Is(err, repository.ErrJobFinalized) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": finalizedMessage})
+	return true
+}
+
+// finalizableJob loads the job in the path
+func (h *Handler) finalizableJob(c *gin.Context) (*models.TranscriptionJob, bool) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return nil, false
+	}
+	return job, true
+}
+
+// @Summary Finalize transcript
+// @Description Lock a completed transcript as a record. Once finalized, it cannot be edited or reprocessed, by hand or by automation, except by admins; finalizing and every later admin change are recorded in the audit log.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body FinalizeTranscriptRequest false "Reason"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/transcription/{id}/finalize [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) FinalizeTranscript(c *gin.Context) {
+	var req FinalizeTranscriptRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			return
+		}
+	}
+	job, ok := h.finalizableJob(c)
+	if !ok {
+		return
+	}
+	if job.FinalizedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Transcript is already finalized"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only completed transcripts can be finalized"})
+		return
+	}
+
+	now := time.Now()
+	author := h.requestAuthor(c)
+	job.FinalizedAt = &now
+	job.FinalizedBy = &author
+	if err := h.jobRepo.Update(c.Request.Context(), job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finalize transcript"})
+		return
+	}
+
+	details := map[string]interface{}{}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		details["reason"] = reason
+	}
+	h.recordAudit(c, models.AuditTranscriptFinalized, "transcription", job.ID, details)
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Unfinalize transcript
+// @Description Unlock a finalized transcript so it can be edited and reprocessed again. Admins only; recorded in the audit log.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body FinalizeTranscriptRequest false "Reason"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/transcription/{id}/finalize [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UnfinalizeTranscript(c *gin.Context) {
+	var req FinalizeTranscriptRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			return
+		}
+	}
+	job, ok := h.finalizableJob(c)
+	if !ok {
+		return
+	}
+	if job.FinalizedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Transcript is not finalized"})
+		return
+	}
+
+	details := map[string]interface{}{
+		"finalized_at": job.FinalizedAt,
+		"finalized_by": job.FinalizedBy,
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		details["reason"] = reason
+	}
+	job.FinalizedAt = nil
+	job.FinalizedBy = nil
+	if err := h.jobRepo.Update(repository.WithFinalizedOverride(c.Request.Context()), job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfinalize transcript"})
+		return
+	}
+
+	h.recordAudit(c, models.AuditTranscriptUnfinalized, "transcription", job.ID, details)
+	c.JSON(http.StatusOK, job)
+}
@@ -2,29 +2,40 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"scriberr/internal/audio"
 	"scriberr/internal/auth"
+	"scriberr/internal/backup"
 	"scriberr/internal/config"
 	"scriberr/internal/database"
+	"scriberr/internal/export"
 	"scriberr/internal/models"
 	"scriberr/internal/processing"
 	"scriberr/internal/queue"
 	"scriberr/internal/repository"
+	"scriberr/internal/secrets"
 	"scriberr/internal/service"
 	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/registry"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -46,49 +57,123 @@ type Handler struct {
 	chatRepo            repository.ChatRepository
 	noteRepo            repository.NoteRepository
 	speakerMappingRepo  repository.SpeakerMappingRepository
+	speakerRepo         repository.SpeakerRepository
+	tagRepo             repository.TagRepository
+	collectionRepo      repository.CollectionRepository
+	searchRepo          repository.SearchRepository
+	recordingRepo       repository.RecordingRepository
 	taskQueue           *queue.TaskQueue
 	unifiedProcessor    *transcription.UnifiedJobProcessor
 	quickTranscription  *transcription.QuickTranscriptionService
 	multiTrackProcessor *processing.MultiTrackProcessor
+	urlIngest           service.URLIngestService
+	feedService         service.FeedService
+	embeddingIndex      service.EmbeddingIndexService
+	voiceprints         service.VoiceprintService
+	speakerAttributes   service.SpeakerAttributeService
+	integrity           service.IntegrityService
+	backups             *backup.Service
+	jobDeleter          service.JobDeleter
+	retention           service.RetentionService
+	retentionRepo       repository.RetentionRepository
+	awsTagRouteRepo     repository.AWSTagRouteRepository
+	vocabularyRepo      repository.VocabularyRepository
+	correctionRuleRepo  repository.CorrectionRuleRepository
+	benchmarkRepo       repository.BenchmarkRepository
+	libraryImports      service.LibraryImportService
+	storage             service.StorageService
+	reportService       service.ReportService
+	fieldPermissionRepo repository.FieldPermissionRepository
+	adapterConfigRepo   repository.AdapterConfigRepository
+}
+
+// Dependencies are the repositories and services the handlers are built from
+type Dependencies struct {
+	AuthService         *auth.AuthService
+	UserService         service.UserService
+	FileService         service.FileService
+	JobRepo             repository.JobRepository
+	APIKeyRepo          repository.APIKeyRepository
+	ProfileRepo         repository.ProfileRepository
+	UserRepo            repository.UserRepository
+	LLMConfigRepo       repository.LLMConfigRepository
+	SummaryRepo         repository.SummaryRepository
+	ChatRepo            repository.ChatRepository
+	NoteRepo            repository.NoteRepository
+	SpeakerMappingRepo  repository.SpeakerMappingRepository
+	SpeakerRepo         repository.SpeakerRepository
+	TagRepo             repository.TagRepository
+	CollectionRepo      repository.CollectionRepository
+	SearchRepo          repository.SearchRepository
+	RecordingRepo       repository.RecordingRepository
+	RetentionRepo       repository.RetentionRepository
+	AWSTagRouteRepo     repository.AWSTagRouteRepository
+	VocabularyRepo      repository.VocabularyRepository
+	CorrectionRuleRepo  repository.CorrectionRuleRepository
+	BenchmarkRepo       repository.BenchmarkRepository
+	TaskQueue           *queue.TaskQueue
+	UnifiedProcessor    *transcription.UnifiedJobProcessor
+	QuickTranscription  *transcription.QuickTranscriptionService
+	URLIngest           service.URLIngestService
+	FeedService         service.FeedService
+	EmbeddingIndex      service.EmbeddingIndexService
+	Voiceprints         service.VoiceprintService
+	SpeakerAttributes   service.SpeakerAttributeService
+	Integrity           service.IntegrityService
+	Backups             *backup.Service
+	JobDeleter          service.JobDeleter
+	Retention           service.RetentionService
+	LibraryImports      service.LibraryImportService
+	Storage             service.StorageService
+	ReportService       service.ReportService
+	FieldPermissionRepo repository.FieldPermissionRepository
+	AdapterConfigRepo   repository.AdapterConfigRepository
 }
 
 // NewHandler creates a new handler
-func NewHandler(
-	cfg *config.Config,
-	authService *auth.AuthService,
-	userService service.UserService,
-	fileService service.FileService,
-	jobRepo repository.JobRepository,
-	apiKeyRepo repository.APIKeyRepository,
-	profileRepo repository.ProfileRepository,
-	userRepo repository.UserRepository,
-	llmConfigRepo repository.LLMConfigRepository,
-	summaryRepo repository.SummaryRepository,
-	chatRepo repository.ChatRepository,
-	noteRepo repository.NoteRepository,
-	speakerMappingRepo repository.SpeakerMappingRepository,
-	taskQueue *queue.TaskQueue,
-	unifiedProcessor *transcription.UnifiedJobProcessor,
-	quickTranscription *transcription.QuickTranscriptionService,
-) *Handler {
+func NewHandler(cfg *config.Config, deps Dependencies) *Handler {
 	return &Handler{
 		config:              cfg,
-		authService:         authService,
-		userService:         userService,
-		fileService:         fileService,
-		jobRepo:             jobRepo,
-		apiKeyRepo:          apiKeyRepo,
-		profileRepo:         profileRepo,
-		userRepo:            userRepo,
-		llmConfigRepo:       llmConfigRepo,
-		summaryRepo:         summaryRepo,
-		chatRepo:            chatRepo,
-		noteRepo:            noteRepo,
-		speakerMappingRepo:  speakerMappingRepo,
-		taskQueue:           taskQueue,
-		unifiedProcessor:    unifiedProcessor,
-		quickTranscription:  quickTranscription,
+		authService:         deps.AuthService,
+		userService:         deps.UserService,
+		fileService:         deps.FileService,
+		jobRepo:             deps.JobRepo,
+		apiKeyRepo:          deps.APIKeyRepo,
+		profileRepo:         deps.ProfileRepo,
+		userRepo:            deps.UserRepo,
+		llmConfigRepo:       deps.LLMConfigRepo,
+		summaryRepo:         deps.SummaryRepo,
+		chatRepo:            deps.ChatRepo,
+		noteRepo:            deps.NoteRepo,
+		speakerMappingRepo:  deps.SpeakerMappingRepo,
+		speakerRepo:         deps.SpeakerRepo,
+		tagRepo:             deps.TagRepo,
+		collectionRepo:      deps.CollectionRepo,
+		searchRepo:          deps.SearchRepo,
+		recordingRepo:       deps.RecordingRepo,
+		taskQueue:           deps.TaskQueue,
+		unifiedProcessor:    deps.UnifiedProcessor,
+		quickTranscription:  deps.QuickTranscription,
 		multiTrackProcessor: processing.NewMultiTrackProcessor(),
+		urlIngest:           deps.URLIngest,
+		feedService:         deps.FeedService,
+		embeddingIndex:      deps.EmbeddingIndex,
+		voiceprints:         deps.Voiceprints,
+		speakerAttributes:   deps.SpeakerAttributes,
+		integrity:           deps.Integrity,
+		backups:             deps.Backups,
+		jobDeleter:          deps.JobDeleter,
+		retention:           deps.Retention,
+		retentionRepo:       deps.RetentionRepo,
+		awsTagRouteRepo:     deps.AWSTagRouteRepo,
+		vocabularyRepo:      deps.VocabularyRepo,
+		correctionRuleRepo:  deps.CorrectionRuleRepo,
+		benchmarkRepo:       deps.BenchmarkRepo,
+		libraryImports:      deps.LibraryImports,
+		storage:             deps.Storage,
+		reportService:       deps.ReportService,
+		fieldPermissionRepo: deps.FieldPermissionRepo,
+		adapterConfigRepo:   deps.AdapterConfigRepo,
 	}
 }
 
@@ -111,6 +196,7 @@ type LoginResponse struct {
 	User  struct {
 		ID       uint   `json:"id"`
 		Username string `json:"username"`
+		Role     string `json:"role"`
 	} `json:"user"`
 }
 
@@ -144,6 +230,13 @@ type ChangeUsernameRequest struct {
 type CreateAPIKeyRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100"`
 	Description string `json:"description,omitempty"`
+	Role        string `json:"role,omitempty"` // admin, editor or viewer; defaults to editor
+	// OrganizationID confines the key to an organization; defaults to the
+	// organization the request acts in
+	OrganizationID *string    `json:"organization_id,omitempty"`
+	Scopes         []string   `json:"scopes,omitempty"` // submit, read and/or admin; defaults to every scope
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	RateLimit      int        `json:"rate_limit,omitempty" binding:"min=0"` // requests per minute; 0 is unlimited
 }
 
 // CreateAPIKeyResponse represents the create API key response
@@ -192,14 +285,20 @@ type LLMConfigResponse struct {
 
 // APIKeyListResponse represents an API key in the list (without the actual key)
 type APIKeyListResponse struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	KeyPreview  string `json:"key_preview"`
-	IsActive    bool   `json:"is_active"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
-	LastUsed    string `json:"last_used,omitempty"`
+	ID             uint     `json:"id"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	KeyPreview     string   `json:"key_preview"`
+	IsActive       bool     `json:"is_active"`
+	Role           string   `json:"role"`
+	OrganizationID string   `json:"organization_id,omitempty"` // the organization the key is confined to, if any
+	Scopes         []string `json:"scopes,omitempty"`
+	ExpiresAt      string   `json:"expires_at,omitempty"`
+	RateLimit      int      `json:"rate_limit"`
+	CreatedAt      string   `json:"created_at"`
+	UpdatedAt      string   `json:"updated_at"`
+	LastUsed       string   `json:"last_used,omitempty"`
+	LastUsedIP     string   `json:"last_used_ip,omitempty"`
 }
 
 // APIKeysWrapper wraps the API keys list response
@@ -221,30 +320,44 @@ func transformAPIKeyForList(apiKey models.APIKey) APIKeyListResponse {
 		lastUsed = apiKey.LastUsed.Format(time.RFC3339)
 	}
 
+	expiresAt := ""
+	if apiKey.ExpiresAt != nil {
+		expiresAt = apiKey.ExpiresAt.Format(time.RFC3339)
+	}
+
 	description := ""
 	if apiKey.Description != nil {
 		description = *apiKey.Description
 	}
 
 	return APIKeyListResponse{
-		ID:          apiKey.ID,
-		Name:        apiKey.Name,
-		Description: description,
-		KeyPreview:  keyPreview,
-		IsActive:    apiKey.IsActive,
-		CreatedAt:   apiKey.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   apiKey.UpdatedAt.Format(time.RFC3339),
-		LastUsed:    lastUsed,
+		ID:             apiKey.ID,
+		Name:           apiKey.Name,
+		Description:    description,
+		KeyPreview:     keyPreview,
+		IsActive:       apiKey.IsActive,
+		Role:           apiKey.Role,
+		OrganizationID: apiKey.OrganizationID,
+		Scopes:         apiKey.ScopeList(),
+		ExpiresAt:      expiresAt,
+		RateLimit:      apiKey.RateLimit,
+		CreatedAt:      apiKey.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      apiKey.UpdatedAt.Format(time.RFC3339),
+		LastUsed:       lastUsed,
+		LastUsedIP:     apiKey.LastUsedIP,
 	}
 }
 
 // @Summary Upload audio file
-// @Description Upload an audio file without starting transcription
+// @Description Upload an audio file. Transcription starts right away when a profile or parameter overrides are given, or with the user's default profile when auto-transcription is on.
 // @Tags transcription
 // @Accept multipart/form-data
 // @Produce json
 // @Param audio formData file true "Audio file"
 // @Param title formData string false "Job title"
+// @Param recorded_at formData string false "When the recording started (RFC 3339); read from the file's metadata if omitted"
+// @Param profile_id formData string false "Profile to queue the job with right away"
+// @Param parameter_overrides formData string false "JSON object of parameters replacing the profile's, e.g. {\"language\": \"de\"}; queues the job with the default profile when profile_id is omitted"
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -261,9 +374,21 @@ func (h *Handler) UploadAudio(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
 		return
 	}
+	recordedAt, ok := recordedAtForm(c)
+	if !ok {
+		return
+	}
+	profile, overrides, ok := h.uploadProfile(c)
+	if !ok {
+		return
+	}
 
-	// Save file using FileService
-	uploadDir := h.config.UploadDir
+	// Save file into the requester's workspace using FileService
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
 	filePath, err := h.fileService.SaveUpload(header, uploadDir)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
@@ -278,11 +403,14 @@ func (h *Handler) UploadAudio(c *gin.Context) {
 		ID:        jobID,
 		AudioPath: filePath,
 		Status:    models.StatusUploaded,
+		Workspace: workspace,
 	}
 
 	if title := c.PostForm("title"); title != "" {
 		job.Title = &title
 	}
+	anchorRecording(c.Request.Context(), &job, recordedAt, filePath)
+	job.ParameterOverrides = overrides
 
 	// Save to database using Repository
 	if err := h.jobRepo.Create(c.Request.Context(), &job); err != nil {
@@ -291,60 +419,131 @@ func (h *Handler) UploadAudio(c *gin.Context) {
 		return
 	}
 
-	// Check for auto-transcription if user is authenticated via JWT
-	if userID, exists := c.Get("user_id"); exists {
-		// Use UserService to get user
-		user, err := h.userService.GetUser(c.Request.Context(), userID.(uint))
-		if err == nil && user.AutoTranscriptionEnabled {
-			// Get user's default profile or use system default
-			var profile *models.TranscriptionProfile
+	if profile != nil {
+		h.queueWithProfile(c.Request.Context(), &job, profile)
+	} else {
+		// Check for auto-transcription if user is authenticated via JWT
+		h.applyAutoTranscription(c, &job)
+	}
 
-			if user.DefaultProfileID != nil {
-				profile, _ = h.profileRepo.FindByID(c.Request.Context(), *user.DefaultProfileID)
-			}
+	c.JSON(http.StatusOK, job)
+}
 
-			// If no user default or user default not found, try to find a system default
-			if profile == nil {
-				profile, _ = h.profileRepo.FindDefault(c.Request.Context())
-			}
+// uploadProfile returns the profile an upload is queued with, its parameters
+// replaced by the upload's overrides, and the overrides as JSON. Uploads
+// giving neither a profile nor overrides have none.
+func (h *Handler) uploadProfile(c *gin.Context) (*models.TranscriptionProfile, *string, bool) {
+	profileID, overridesJSON := c.PostForm("profile_id"), c.PostForm("parameter_overrides")
+	if profileID == "" && overridesJSON == "" {
+		return nil, nil, true
+	}
 
-			// If still no profile found, use the first available profile
-			if profile == nil {
-				profiles, _, _ := h.profileRepo.List(c.Request.Context(), 0, 1)
-				if len(profiles) > 0 {
-					profile = &profiles[0]
-				}
-			}
+	var profile *models.TranscriptionProfile
+	if profileID != "" {
+		var err error
+		if profile, err = h.profileRepo.FindByID(c.Request.Context(), profileID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+			return nil, nil, false
+		}
+	} else if profile = h.getDefaultProfile(c.Request.Context()); profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No profile to apply the parameter overrides to"})
+		return nil, nil, false
+	}
 
-			// If we found a profile, update the job and queue it
-			if profile != nil {
-				job.Parameters = profile.Parameters
-				job.Diarization = profile.Parameters.Diarize
-				job.Status = models.StatusPending
-
-				// Update the job in database
-				if err := h.jobRepo.Update(c.Request.Context(), &job); err == nil {
-					// Enqueue the job for transcription
-					if err := h.taskQueue.EnqueueJob(jobID); err != nil {
-						// If enqueueing fails, revert status but don't fail the upload
-						job.Status = models.StatusUploaded
-						h.jobRepo.Update(c.Request.Context(), &job)
-					}
-				}
-			}
+	var overrides map[string]interface{}
+	if overridesJSON != "" {
+		if err := json.Unmarshal([]byte(overridesJSON), &overrides); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameter_overrides JSON"})
+			return nil, nil, false
 		}
 	}
+	overridden := *profile
+	applied, ok := h.applyParameterOverrides(c, &overridden.Parameters, overrides)
+	if !ok {
+		return nil, nil, false
+	}
+	return &overridden, applied, true
+}
 
-	c.JSON(http.StatusOK, job)
+// applyAutoTranscription queues a newly uploaded job with the user's default
+// profile when the JWT user has auto-transcription enabled
+func (h *Handler) applyAutoTranscription(c *gin.Context, job *models.TranscriptionJob) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return
+	}
+	// Use UserService to get user
+	user, err := h.userService.GetUser(c.Request.Context(), userID.(uint))
+	if err != nil || !user.AutoTranscriptionEnabled {
+		return
+	}
+
+	// Get user's default profile or use system default
+	var profile *models.TranscriptionProfile
+
+	if user.DefaultProfileID != nil {
+		profile, _ = h.profileRepo.FindByID(c.Request.Context(), *user.DefaultProfileID)
+	}
+
+	// If no user default or user default not found, try to find a system default
+	if profile == nil {
+		profile, _ = h.profileRepo.FindDefault(c.Request.Context())
+	}
+
+	// If still no profile found, use the first available profile
+	if profile == nil {
+		profiles, _, _ := h.profileRepo.List(c.Request.Context(), 0, 1)
+		if len(profiles) > 0 {
+			profile = &profiles[0]
+		}
+	}
+
+	// If we found a profile, update the job and queue it
+	if profile != nil {
+		h.queueWithProfile(c.Request.Context(), job, profile)
+	}
+}
+
+// queueWithProfile applies the profile's parameters to an uploaded job and queues it.
+// Jobs of a workspace out of quota stay uploaded, to be started later.
+func (h *Handler) queueWithProfile(ctx context.Context, job *models.TranscriptionJob, profile *models.TranscriptionProfile) {
+	if err := service.CheckQuota(ctx, h.jobRepo, job.Workspace, h.config.MonthlyQuotaMinutes); err != nil {
+		logger.Info("Not queueing job", "job_id", job.ID, "reason", err)
+		return
+	}
+	job.Parameters = profile.Parameters
+	job.Diarization = profile.Parameters.Diarize
+	job.ProfileID = &profile.ID
+	job.ProfileVersion = &profile.Version
+	job.Status = models.StatusPending
+
+	// Update the job in database
+	if err := h.jobRepo.Update(ctx, job); err == nil {
+		// Enqueue the job for transcription
+		if err := h.enqueueJob(ctx, job.ID); err != nil {
+			// If enqueueing fails, revert status but don't fail the upload
+			job.Status = models.StatusUploaded
+			h.jobRepo.Update(ctx, job)
+		}
+	}
+}
+
+// enqueueJob queues a job for transcription. The worker continues the trace of
+// ctx, so a job's processing is traced under the request that queued it.
+func (h *Handler) enqueueJob(ctx context.Context, jobID string) error {
+	tracing.LinkJob(tracing.WithJobID(ctx, jobID), jobID)
+	return h.taskQueue.EnqueueJob(jobID)
 }
 
 // @Summary Upload video file for transcription
-// @Description Upload a video file, extract audio from it using ffmpeg, and create a transcription job
+// @Description Upload an MP4, MKV, MOV or WebM video, extract its first audio track using ffmpeg, and create a transcription job. The video is kept, unless keep_video is false, so it can be downloaded with burned-in or sidecar subtitles once transcribed.
 // @Tags transcription
 // @Accept multipart/form-data
 // @Produce json
 // @Param video formData file true "Video file"
 // @Param title formData string false "Job title"
+// @Param recorded_at formData string false "When the recording started (RFC 3339); read from the file's metadata if omitted"
+// @Param keep_video formData bool false "Keep the video for subtitled downloads" default(true)
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -358,9 +557,28 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Video file is required"})
 		return
 	}
+	if !audio.IsVideo(filepath.Ext(header.Filename)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported video format; upload MP4, MKV, MOV or WebM"})
+		return
+	}
+	recordedAt, ok := recordedAtForm(c)
+	if !ok {
+		return
+	}
+	keepVideo := true
+	if value := c.PostForm("keep_video"); value != "" {
+		if keepVideo, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "keep_video must be true or false"})
+			return
+		}
+	}
 
-	// Save file using FileService
-	uploadDir := h.config.UploadDir
+	// Save file into the requester's workspace using FileService
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
 	videoPath, err := h.fileService.SaveUpload(header, uploadDir)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
@@ -371,12 +589,11 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 	jobID := filepath.Base(videoPath)
 	jobID = jobID[:len(jobID)-len(filepath.Ext(jobID))]
 
-	// Extract audio using ffmpeg (keep this logic here for now, or move to a MediaService)
 	audioPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + ".mp3"
-	cmd := exec.Command("ffmpeg", "-i", videoPath, "-vn", "-acodec", "libmp3lame", "-q:a", "2", audioPath)
-	if err := cmd.Run(); err != nil {
+	if err := audio.ExtractAudio(c.Request.Context(), videoPath, audioPath); err != nil {
 		h.fileService.RemoveFile(videoPath)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to extract audio from video"})
+		h.fileService.RemoveFile(audioPath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to extract audio from video; it may have no audio track"})
 		return
 	}
 
@@ -385,11 +602,16 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 		ID:        jobID,
 		AudioPath: audioPath, // Use the extracted audio path
 		Status:    models.StatusUploaded,
+		Workspace: workspace,
+	}
+	if keepVideo {
+		job.VideoPath = &videoPath
 	}
 
 	if title := c.PostForm("title"); title != "" {
 		job.Title = &title
 	}
+	anchorRecording(c.Request.Context(), &job, recordedAt, videoPath)
 
 	// Save to database
 	if err := h.jobRepo.Create(c.Request.Context(), &job); err != nil {
@@ -399,47 +621,19 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 		return
 	}
 
-	// Clean up video file as we only need audio
-	// TODO: Make this configurable? Some users might want to keep the video.
-	h.fileService.RemoveFile(videoPath)
+	if !keepVideo {
+		h.fileService.RemoveFile(videoPath)
+	}
 
 	// Check for auto-transcription (same logic as UploadAudio)
-	if userID, exists := c.Get("user_id"); exists {
-		user, err := h.userService.GetUser(c.Request.Context(), userID.(uint))
-		if err == nil && user.AutoTranscriptionEnabled {
-			var profile *models.TranscriptionProfile
-			if user.DefaultProfileID != nil {
-				profile, _ = h.profileRepo.FindByID(c.Request.Context(), *user.DefaultProfileID)
-			}
-			if profile == nil {
-				profile, _ = h.profileRepo.FindDefault(c.Request.Context())
-			}
-			if profile == nil {
-				profiles, _, _ := h.profileRepo.List(c.Request.Context(), 0, 1)
-				if len(profiles) > 0 {
-					profile = &profiles[0]
-				}
-			}
-
-			if profile != nil {
-				job.Parameters = profile.Parameters
-				job.Diarization = profile.Parameters.Diarize
-				job.Status = models.StatusPending
-				if err := h.jobRepo.Update(c.Request.Context(), &job); err == nil {
-					if err := h.taskQueue.EnqueueJob(jobID); err != nil {
-						job.Status = models.StatusUploaded
-						h.jobRepo.Update(c.Request.Context(), &job)
-					}
-				}
-			}
-		}
-	}
+	h.applyAutoTranscription(c, &job)
 
 	c.JSON(http.StatusOK, job)
 }
 
 // @Summary Upload multi-track audio files
-// @Description Upload multiple audio files for multi-track transcription
+// @Description Upload multiple audio files for multi-track transcription, named after their files, to be started with is_multi_track_enabled. Superseded by POST /api/v1/transcription/multitrack, which names the tracks, takes per-track parameters and queues the job.
+// @Deprecated
 // @Tags transcription
 // @Accept multipart/form-data
 // @Produce json
@@ -467,7 +661,11 @@ func (h *Handler) UploadMultiTrack(c *gin.Context) {
 
 	// Create a unique job ID
 	jobID := uuid.New().String()
-	uploadDir := h.config.UploadDir
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
 
 	// Create job directory
 	jobDir := filepath.Join(uploadDir, jobID)
@@ -504,6 +702,7 @@ func (h *Handler) UploadMultiTrack(c *gin.Context) {
 		Status:          models.StatusUploaded,
 		IsMultiTrack:    true,
 		MultiTrackFiles: trackFiles,
+		Workspace:       workspace,
 	}
 
 	if title := c.PostForm("title"); title != "" {
@@ -659,7 +858,9 @@ func (h *Handler) GetTrackProgress(c *gin.Context) {
 // @Param title formData string false "Job title"
 // @Param diarization formData boolean false "Enable speaker diarization"
 // @Param model formData string false "Whisper model" default(base)
+// @Param custom_model formData string false "Fine-tuned CTranslate2 Whisper checkpoint (absolute path or Hugging Face repo ID), used instead of model"
 // @Param language formData string false "Language code"
+// @Param segment_language_id formData boolean false "Tag each segment with its spoken language (code-switching audio)"
 // @Param batch_size formData int false "Batch size" default(16)
 // @Param compute_type formData string false "Compute type" default(float16)
 // @Param device formData string false "Device" default(auto)
@@ -668,6 +869,13 @@ func (h *Handler) GetTrackProgress(c *gin.Context) {
 // @Param vad_offset formData number false "VAD offset" default(0.363)
 // @Param min_speakers formData int false "Minimum speakers for diarization"
 // @Param max_speakers formData int false "Maximum speakers for diarization"
+// @Param speaker_embeddings formData boolean false "Record speaker voice embeddings so speakers can be enrolled as voiceprints"
+// @Param speaker_attributes formData boolean false "Estimate each diarized speaker's gender and age range (opt-in; workspaces can disable it)"
+// @Param vocabulary_name formData string false "Custom vocabulary whose phrases the model is prompted with and whose misspellings are corrected"
+// @Param vocabulary_filter_name formData string false "Vocabulary filter whose words are removed, masked or tagged in the transcript"
+// @Param vocabulary_filter_method formData string false "remove, mask or tag" default(remove)
+// @Param recorded_at formData string false "When the recording started (RFC 3339); read from the file's metadata if omitted"
+// @Param dry_run formData boolean false "Only validate the parameters, resolve the adapters, probe the audio and estimate duration and cost; nothing is saved or queued and a DryRunResponse is returned"
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -681,9 +889,17 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
 		return
 	}
+	recordedAt, ok := recordedAtForm(c)
+	if !ok {
+		return
+	}
 
-	// Save file using FileService
-	uploadDir := h.config.UploadDir
+	// Save file into the requester's workspace using FileService
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
 	filePath, err := h.fileService.SaveUpload(header, uploadDir)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
@@ -709,6 +925,10 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		VadOnset:    getFormFloatWithDefault(c, "vad_onset", 0.500),
 		VadOffset:   getFormFloatWithDefault(c, "vad_offset", 0.363),
 		Diarize:     diarize,
+
+		SegmentLanguageID: getFormBoolWithDefault(c, "segment_language_id", false),
+		SpeakerEmbeddings: getFormBoolWithDefault(c, "speaker_embeddings", false),
+		SpeakerAttributes: getFormBoolWithDefault(c, "speaker_attributes", false),
 	}
 
 	if lang := c.PostForm("language"); lang != "" {
@@ -731,6 +951,26 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		params.HfToken = &hfToken
 	}
 
+	if customModel := c.PostForm("custom_model"); customModel != "" {
+		params.CustomModel = &customModel
+	}
+	if !h.validateCustomWeights(c, &params) {
+		h.fileService.RemoveFile(filePath)
+		return
+	}
+	if vocabulary := c.PostForm("vocabulary_name"); vocabulary != "" {
+		params.VocabularyName = &vocabulary
+	}
+	if filter := c.PostForm("vocabulary_filter_name"); filter != "" {
+		params.VocabularyFilterName = &filter
+		method := c.PostForm("vocabulary_filter_method")
+		params.VocabularyFilterMethod = &method
+	}
+	if !h.validateVocabulary(c, &params) {
+		h.fileService.RemoveFile(filePath)
+		return
+	}
+
 	// Parse and validate diarization model
 	diarizeModel := getFormValueWithDefault(c, "diarize_model", "pyannote")
 	if diarizeModel != "pyannote" && diarizeModel != "nvidia_sortformer" {
@@ -747,11 +987,20 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		Status:      models.StatusPending,
 		Diarization: diarize,
 		Parameters:  params,
+		Workspace:   workspace,
 	}
 
 	if title := c.PostForm("title"); title != "" {
 		job.Title = &title
 	}
+	anchorRecording(c.Request.Context(), &job, recordedAt, filePath)
+
+	// A dry run only plans the job; its upload is not kept
+	if dryRunRequested(c) {
+		defer h.fileService.RemoveFile(filePath)
+		h.respondDryRun(c, &job)
+		return
+	}
 
 	// Save to database
 	if err := h.jobRepo.Create(c.Request.Context(), &job); err != nil {
@@ -761,7 +1010,7 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 	}
 
 	// Enqueue job
-	if err := h.taskQueue.EnqueueJob(jobID); err != nil {
+	if err := h.enqueueJob(c.Request.Context(), jobID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue job"})
 		return
 	}
@@ -791,15 +1040,21 @@ func (h *Handler) GetJobStatus(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job status"})
 		return
 	}
+	redacted, err := h.newJobFieldFilter(c).job(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job status"})
+		return
+	}
 
-	c.JSON(http.StatusOK, job)
+	c.JSON(http.StatusOK, redacted)
 }
 
 // @Summary Get transcript
-// @Description Get the transcript for a completed transcription job
+// @Description Get the transcript for a completed transcription job, with chapters and per-segment sentiment once they have been generated, and the seconds of speech per language when segments are tagged with their language. When the recording's start time is known, the wall-clock start and end of each segment are included as segment_times.
 // @Tags transcription
 // @Produce json
 // @Param id path string true "Job ID"
+// @Param timezone query string false "IANA time zone of segment_times; defaults to the user's setting, then UTC"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {object} map[string]string
 // @Failure 400 {object} map[string]string
@@ -808,6 +1063,11 @@ func (h *Handler) GetJobStatus(c *gin.Context) {
 // @Security BearerAuth
 func (h *Handler) GetTranscript(c *gin.Context) {
 	jobID := c.Param("id")
+	_, location, err := h.exportLocale(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var job models.TranscriptionJob
 	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
@@ -837,65 +1097,202 @@ func (h *Handler) GetTranscript(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"job_id":     job.ID,
 		"title":      job.Title,
 		"transcript": transcript,
 		"created_at": job.CreatedAt,
 		"updated_at": job.UpdatedAt,
-	})
+	}
+
+	// Per-segment sentiment and chapters, when they have been generated
+	if job.SegmentSentiment != nil {
+		var sentiment []service.SegmentSentiment
+		if err := json.Unmarshal([]byte(*job.SegmentSentiment), &sentiment); err == nil {
+			response["sentiment"] = sentiment
+		}
+	}
+	if job.Chapters != nil {
+		var chapters []service.Chapter
+		if err := json.Unmarshal([]byte(*job.Chapters), &chapters); err == nil {
+			response["chapters"] = chapters
+		}
+	}
+	// Seconds of speech per language, when segments were tagged with their language
+	if languages := segmentLanguageDurations(*job.Transcript); len(languages) > 0 {
+		response["languages"] = languages
+	}
+	if job.RecordedAt != nil {
+		response["recorded_at"] = job.RecordedAt.In(location)
+		response["segment_times"] = segmentTimes(*job.Transcript, *job.RecordedAt, location)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// segmentLanguageDurations sums segment durations by the language tagged on them
+func segmentLanguageDurations(transcript string) map[string]float64 {
+	var parsed struct {
+		Segments []struct {
+			Start    float64 `json:"start"`
+			End      float64 `json:"end"`
+			Language string  `json:"language"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(transcript), &parsed); err != nil {
+		return nil
+	}
+	durations := make(map[string]float64)
+	for _, seg := range parsed.Segments {
+		if seg.Language != "" {
+			durations[seg.Language] += seg.End - seg.Start
+		}
+	}
+	return durations
 }
 
 // @Summary List all transcription records
-// @Description Get a list of all transcription jobs with optional search and filtering
-// @Tags transcription
-// @Produce json
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
-// @Param status query string false "Filter by status"
-// @Param q query string false "Search in title and audio filename"
-// @Success 200 {object} map[string]interface{}
-// @Router /api/v1/transcription/list [get]
-// @Security ApiKeyAuth
-// @Security BearerAuth
-// @Summary List all transcription records
-// @Description Get a list of all transcription jobs with optional search and filtering
+// @Description List transcription jobs, filtered, sorted and a page at a time. Pages are taken by page number, or for large lists by cursor: pass the next_cursor of a page, with the same filters and sort, to get the page after it.
 // @Tags transcription
 // @Produce json
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
-// @Param status query string false "Filter by status"
-// @Param q query string false "Search in title and audio filename"
+// @Param page query int false "Page number, ignored with cursor" default(1)
+// @Param limit query int false "Items per page, at most 500" default(10)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param status query string false "Comma-separated statuses"
+// @Param since query string false "Created at or after (RFC 3339 time, or YYYY-MM-DD in UTC)"
+// @Param until query string false "Created before (RFC 3339 time, or YYYY-MM-DD in UTC)"
+// @Param profile_id query string false "Profile the job was queued with"
+// @Param adapter query string false "Model family the job was queued with, e.g. whisper or runpod-whisperx"
+// @Param tag query string false "Tag key, or key=value"
+// @Param tag_id query string false "Tag ID"
+// @Param collection_id query string false "Collection ID, or none for the jobs filed in no collection"
+// @Param language query string false "Language code"
+// @Param min_duration query number false "Minimum audio length in seconds; only completed jobs have one"
+// @Param max_duration query number false "Maximum audio length in seconds"
+// @Param q query string false "Search in title, audio filename and tags"
+// @Param sort_by query string false "created_at, updated_at, title, status, profile, adapter, language or duration" default(created_at)
+// @Param sort_order query string false "asc or desc" default(desc)
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/transcription/list [get]
 // @Security ApiKeyAuth
 // @Security BearerAuth
 func (h *Handler) ListTranscriptionJobs(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	limit = min(limit, maxJobListLimit)
 
-	sortBy := c.Query("sort_by")
-	sortOrder := c.Query("sort_order")
-	searchQuery := c.Query("q")
+	query, ok := jobListQuery(c)
+	if !ok {
+		return
+	}
+	query.Offset = (page - 1) * limit
+	query.Limit = limit
 
-	jobs, total, err := h.jobRepo.ListWithParams(c.Request.Context(), offset, limit, sortBy, sortOrder, searchQuery)
+	result, err := h.jobRepo.ListJobs(c.Request.Context(), query)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor is invalid, or was issued for another sort"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	// Fields hidden from the request's role are left out of each job
+	jobs, err := h.newJobFieldFilter(c).jobs(result.Jobs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
 		return
 	}
 
+	pagination := gin.H{
+		"page":  page,
+		"limit": limit,
+		"total": result.Total,
+		"pages": (result.Total + int64(limit) - 1) / int64(limit),
+	}
+	if result.NextCursor != "" {
+		pagination["next_cursor"] = result.NextCursor
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"jobs": jobs,
-		"pagination": gin.H{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-			"pages": (total + int64(limit) - 1) / int64(limit),
-		},
+		"jobs":       jobs,
+		"pagination": pagination,
 	})
 }
 
+// maxJobListLimit bounds the page size of the jobs list
+const maxJobListLimit = 500
+
+// jobListQuery parses the filters and sort of the jobs list, answering 400
+// if one is malformed
+func jobListQuery(c *gin.Context) (repository.JobListQuery, bool) {
+	query := repository.JobListQuery{
+		ProfileID:   c.Query("profile_id"),
+		ModelFamily: c.Query("adapter"),
+		Tag:         c.Query("tag"),
+		TagID:       c.Query("tag_id"),
+		Language:    c.Query("language"),
+		Search:      c.Query("q"),
+		SortBy:      c.Query("sort_by"),
+		SortOrder:   strings.ToLower(c.Query("sort_order")),
+		Cursor:      c.Query("cursor"),
+	}
+	switch collectionID := c.Query("collection_id"); collectionID {
+	case "":
+	case "none":
+		query.NoCollection = true
+	default:
+		query.CollectionIDs = []string{collectionID}
+	}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			query.Statuses = append(query.Statuses, models.JobStatus(status))
+		}
+	}
+	if _, ok := repository.JobSortFields[query.SortBy]; query.SortBy != "" && !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort_by must be one of created_at, updated_at, title, status, profile, adapter, language or duration"})
+		return query, false
+	}
+	if query.SortOrder != "" && query.SortOrder != "asc" && query.SortOrder != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort_order must be asc or desc"})
+		return query, false
+	}
+
+	for param, bound := range map[string]**time.Time{"since": &query.CreatedAfter, "until": &query.CreatedBefore} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, value); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC 3339 time or a date (YYYY-MM-DD)"})
+				return query, false
+			}
+		}
+		*bound = &t
+	}
+	for param, bound := range map[string]**float64{"min_duration": &query.MinDuration, "max_duration": &query.MaxDuration} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a number of seconds"})
+			return query, false
+		}
+		*bound = &seconds
+	}
+	return query, true
+}
+
 // @Summary Get transcription job details
 // @Description Get details of a specific transcription job
 // @Tags transcription
@@ -914,8 +1311,13 @@ func (h *Handler) GetTranscriptionJob(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
+	redacted, err := h.newJobFieldFilter(c).job(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
 
-	c.JSON(http.StatusOK, job)
+	c.JSON(http.StatusOK, redacted)
 }
 
 // @Summary Start transcription for uploaded file
@@ -925,6 +1327,7 @@ func (h *Handler) GetTranscriptionJob(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Job ID"
 // @Param parameters body models.WhisperXParams true "Transcription parameters"
+// @Param dry_run query boolean false "Only validate the parameters, resolve the adapters, check the audio and estimate duration and cost; the job is not changed or queued and a DryRunResponse is returned"
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
@@ -944,8 +1347,8 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 		return
 	}
 
-	// Allow transcription for uploaded, completed, and failed jobs (re-transcription)
-	if job.Status != models.StatusUploaded && job.Status != models.StatusCompleted && job.Status != models.StatusFailed {
+	// Allow transcription for uploaded, completed, failed and cancelled jobs (re-transcription)
+	if job.Status != models.StatusUploaded && job.Status != models.StatusCompleted && job.Status != models.StatusFailed && job.Status != models.StatusCancelled {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot start transcription: job is currently processing or pending"})
 		return
 	}
@@ -1023,6 +1426,16 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 		}
 	}
 
+	if !h.validateCustomWeights(c, &requestParams) {
+		return
+	}
+	if !validateFailover(c, &requestParams) {
+		return
+	}
+	if !h.validateVocabulary(c, &requestParams) {
+		return
+	}
+
 	// Validate multi-track compatibility
 	if job.IsMultiTrack && !requestParams.IsMultiTrackEnabled {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Multi-track audio requires multi-track transcription to be enabled in the parameters"})
@@ -1040,6 +1453,19 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 		return
 	}
 
+	if dryRunRequested(c) {
+		planned := job
+		planned.Parameters = requestParams
+		if job.IsMultiTrack {
+			if err := database.DB.Where("transcription_job_id = ?", job.ID).Find(&planned.MultiTrackFiles).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job tracks"})
+				return
+			}
+		}
+		h.respondDryRun(c, &planned)
+		return
+	}
+
 	// Update job with parameters
 	job.Parameters = requestParams
 	job.Diarization = requestParams.Diarize
@@ -1049,15 +1475,25 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 	job.Transcript = nil
 	job.Summary = nil
 	job.ErrorMessage = nil
+	job.ErrorCode = ""
+	job.DuplicateOf = nil
+	job.ActionItems = nil
+	job.SegmentSentiment = nil
+	job.Chapters = nil
+	job.PostProcessedAt = nil
+	job.PostProcessError = nil
 
 	// Save updated job
-	if err := database.DB.Save(&job).Error; err != nil {
+	if err := h.jobRepo.Update(c.Request.Context(), &job); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job"})
 		return
 	}
 
 	// Enqueue job for transcription
-	if err := h.taskQueue.EnqueueJob(jobID); err != nil {
+	if err := h.enqueueJob(c.Request.Context(), jobID); err != nil {
 		logger.Error("Failed to enqueue job", "job_id", jobID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue job"})
 		return
@@ -1119,6 +1555,40 @@ func (h *Handler) KillJob(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Job cancellation requested"})
 }
 
+// @Summary Cancel transcription job
+// @Description Cancel a queued or running job. Local processes are killed and calls to remote adapters (RunPod, Modal) are cancelled through their APIs, so they stop billing; the worker is then free for the next job. The job is marked cancelled, and can be started again.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/transcription/{id}/cancel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CancelJob(c *gin.Context) {
+	jobID := c.Param("id")
+	if _, err := h.jobRepo.FindByID(c.Request.Context(), jobID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	if err := h.taskQueue.CancelJob(jobID); err != nil {
+		if errors.Is(err, queue.ErrJobNotCancellable) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Job is not queued or running"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": jobID, "status": string(models.StatusCancelled)})
+}
+
 // UpdateTranscriptionTitle updates the title of a transcription job
 // @Summary Update transcription title
 // @Description Update the title of an audio file / transcription
@@ -1207,63 +1677,8 @@ func (h *Handler) DeleteTranscriptionJob(c *gin.Context) {
 		return
 	}
 
-	// Delete files
-	if job.IsMultiTrack && job.MultiTrackFolder != nil {
-		h.fileService.RemoveDirectory(*job.MultiTrackFolder)
-	} else {
-		h.fileService.RemoveFile(job.AudioPath)
-	}
-
-	// Also remove .aup file if exists
-	if job.AupFilePath != nil {
-		h.fileService.RemoveFile(*job.AupFilePath)
-	}
-
-	// Manually delete related records to handle legacy DBs without CASCADE constraints
-	// 1. Delete Chat Sessions (and their messages via GORM hooks or manual if needed, but let's assume messages are cascaded by session deletion or we delete them too)
-	// Actually, we should use the repositories if available, or direct DB calls if not exposed.
-	// Since we have repositories, let's try to use them or add methods.
-	// However, for speed and robustness here, we can use the jobRepo's DB instance if we had access, but we don't directly.
-	// We should add DeleteByJobID methods to repositories or use a transaction.
-	// Given the constraints, let's add a helper in jobRepo or just rely on the fact that we can't easily access other repos here without adding them to Handler if they aren't already.
-	// Wait, Handler HAS all repos.
-
-	ctx := c.Request.Context()
-
-	// Delete Chat Sessions
-	// We need a method in ChatRepository to delete by JobID or TranscriptionID
-	if err := h.chatRepo.DeleteByJobID(ctx, jobID); err != nil {
-		// Log error but continue? Or fail? Best to try to clean up as much as possible.
-		fmt.Printf("Failed to delete chat sessions for job %s: %v\n", jobID, err)
-	}
-
-	// Delete Notes
-	if err := h.noteRepo.DeleteByTranscriptionID(ctx, jobID); err != nil {
-		fmt.Printf("Failed to delete notes for job %s: %v\n", jobID, err)
-	}
-
-	// Delete Summaries
-	if err := h.summaryRepo.DeleteByTranscriptionID(ctx, jobID); err != nil {
-		fmt.Printf("Failed to delete summaries for job %s: %v\n", jobID, err)
-	}
-
-	// Delete Speaker Mappings
-	if err := h.speakerMappingRepo.DeleteByJobID(ctx, jobID); err != nil {
-		fmt.Printf("Failed to delete speaker mappings for job %s: %v\n", jobID, err)
-	}
-
-	// Delete Job Executions
-	if err := h.jobRepo.DeleteExecutionsByJobID(ctx, jobID); err != nil {
-		fmt.Printf("Failed to delete job executions for job %s: %v\n", jobID, err)
-	}
-
-	// Delete MultiTrack Files (DB records)
-	if err := h.jobRepo.DeleteMultiTrackFilesByJobID(ctx, jobID); err != nil {
-		fmt.Printf("Failed to delete multi-track file records for job %s: %v\n", jobID, err)
-	}
-
-	// Delete from database
-	if err := h.jobRepo.Delete(c.Request.Context(), jobID); err != nil {
+	// Delete files and related records, then the job
+	if err := h.jobDeleter.Delete(c.Request.Context(), job); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job: " + err.Error()})
 		return
 	}
@@ -1296,7 +1711,7 @@ func (h *Handler) GetJobExecutionData(c *gin.Context) {
 	}
 
 	var execution models.TranscriptionJobExecution
-	if err := database.DB.Where("transcription_job_id = ? AND status = ?", jobID, models.StatusCompleted).
+	if err := database.DB.Where("transcription_job_id = ? AND status = ? AND replay_of IS NULL", jobID, models.StatusCompleted).
 		Order("completed_at DESC").
 		First(&execution).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -1315,11 +1730,23 @@ func (h *Handler) GetJobExecutionData(c *gin.Context) {
 		"completed_at":         execution.CompletedAt,
 		"processing_duration":  execution.ProcessingDuration,
 		"actual_parameters":    execution.ActualParameters,
+		"snapshot":             execution.Snapshot,
+		"environment_hash":     execution.EnvironmentHash,
+		"input_hash":           execution.InputHash,
 		"status":               execution.Status,
 		"error_message":        execution.ErrorMessage,
 		"created_at":           execution.CreatedAt,
 		"updated_at":           execution.UpdatedAt,
 		"is_multi_track":       job.IsMultiTrack,
+		"adapter":              execution.Adapter,
+	}
+
+	// Adapters the job failed over from
+	if execution.FailedAdapters != nil {
+		var attempts []models.AdapterAttempt
+		if err := json.Unmarshal([]byte(*execution.FailedAdapters), &attempts); err == nil {
+			response["failed_adapters"] = attempts
+		}
 	}
 
 	// Add multi-track specific data if available
@@ -1343,16 +1770,26 @@ func (h *Handler) GetJobExecutionData(c *gin.Context) {
 }
 
 // @Summary Get audio file
-// @Description Serve the audio file for a transcription job
+// @Description Serve the audio file for a transcription job. Range requests are supported, so players can seek without downloading the whole file. With format=opus or format=mp3 audio in other formats is transcoded as it is streamed, for browsers that cannot play the original; transcoded streams do not support Range requests. Audio stored in S3 is redirected to a presigned URL when AUDIO_S3_REDIRECT is set or redirect=true is given, rather than passing through the server.
 // @Tags transcription
-// @Produce audio/mpeg,audio/wav,audio/mp4
+// @Produce audio/mpeg,audio/wav,audio/mp4,audio/ogg
 // @Param id path string true "Job ID"
+// @Param format query string false "Transcode to this format (opus, mp3)"
+// @Param redirect query bool false "Redirect S3-stored audio to a presigned URL (default AUDIO_S3_REDIRECT)"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
 // @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Success 302 {string} string "Presigned S3 URL"
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /api/v1/transcription/{id}/audio [get]
 // @Security ApiKeyAuth
 func (h *Handler) GetAudioFile(c *gin.Context) {
 	jobID := c.Param("id")
+	format, ok := audioStreamFormat(c)
+	if !ok {
+		return
+	}
 
 	var job models.TranscriptionJob
 	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
@@ -1364,61 +1801,106 @@ func (h *Handler) GetAudioFile(c *gin.Context) {
 		return
 	}
 
-	// Debug logging
-	fmt.Printf("DEBUG: GetAudioFile for job %s\n", jobID)
-	fmt.Printf("DEBUG: Job status: %s\n", job.Status)
-	fmt.Printf("DEBUG: Audio path: '%s'\n", job.AudioPath)
+	// Signed playback URLs carry no credentials; everyone else must see the job
+	if !models.WorkspaceVisible(c.Request.Context(), job.Workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	// Never serve media the upload scanner flagged
+	if job.Status == models.StatusQuarantined {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Audio file is quarantined pending review"})
+		return
+	}
 
 	// For multi-track jobs, prefer merged audio if available
 	audioPath := job.AudioPath
 	if job.IsMultiTrack && job.MergedAudioPath != nil && *job.MergedAudioPath != "" {
-		// Check if merged audio file exists
 		if _, err := os.Stat(*job.MergedAudioPath); err == nil {
 			audioPath = *job.MergedAudioPath
-			fmt.Printf("DEBUG: Using merged audio: %s\n", audioPath)
 		} else {
-			fmt.Printf("DEBUG: Merged audio not found, falling back to original: %s\n", job.AudioPath)
+			logger.Debug("Merged audio not found, serving the original", "job_id", job.ID, "merged_audio_path", *job.MergedAudioPath)
 		}
 	}
 
 	// Check if audio file exists
 	if audioPath == "" {
-		fmt.Printf("DEBUG: Audio path is empty\n")
 		c.JSON(http.StatusNotFound, gin.H{"error": "Audio file path not found"})
 		return
 	}
 
+	// Only serve files from the job's own workspace
+	audioPath, err := h.scopedJobPath(&job, audioPath)
+	if err != nil {
+		logger.Warn("Audio path is outside the job's workspace", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audio file not found"})
+		return
+	}
+
 	// Check if file exists on filesystem
 	if _, err := os.Stat(audioPath); os.IsNotExist(err) {
-		fmt.Printf("DEBUG: Audio file does not exist on disk: %s\n", audioPath)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Audio file not found on disk"})
 		return
 	}
 
-	fmt.Printf("DEBUG: Audio file exists, serving: %s\n", audioPath)
-
-	// Set appropriate content type based on file extension
-	ext := filepath.Ext(job.AudioPath)
-	switch ext {
-	case ".mp3":
-		c.Header("Content-Type", "audio/mpeg")
-	case ".wav":
-		c.Header("Content-Type", "audio/wav")
-	case ".m4a":
-		c.Header("Content-Type", "audio/mp4")
-	case ".ogg":
-		c.Header("Content-Type", "audio/ogg")
-	default:
-		c.Header("Content-Type", "audio/mpeg")
-	}
-
 	// Add CORS headers for audio
 	c.Header("Access-Control-Allow-Origin", "*")
 	c.Header("Access-Control-Allow-Methods", "GET")
-	c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-API-Key")
+	c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-API-Key, Range")
+	c.Header("Access-Control-Expose-Headers", "Accept-Ranges, Content-Length, Content-Range")
+
+	ext := strings.ToLower(filepath.Ext(audioPath))
+	if format != "" && audioFormatExtensions[format] != ext {
+		// Streamed from ffmpeg as it encodes, so the length is unknown and
+		// ranges cannot be served
+		c.Header("Content-Type", audio.StreamFormats[format])
+		c.Header("Accept-Ranges", "none")
+		c.Status(http.StatusOK)
+		if err := audio.Transcode(c.Request.Context(), audioPath, format, c.Writer); err != nil {
+			logger.Error("Failed to transcode audio", "job_id", job.ID, "format", format, "error", err)
+		}
+		return
+	}
+
+	contentType, ok := audioContentTypes[ext]
+	if !ok {
+		contentType = "audio/mpeg"
+	}
+	c.Header("Content-Type", contentType)
+
+	// Served from disk with Range and If-Range support
+	c.File(audioPath)
+}
+
+// audioContentTypes are the content types of the audio files served as is
+var audioContentTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".m4a":  "audio/mp4",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".flac": "audio/flac",
+}
 
-	// Serve the audio file
-	c.File(job.AudioPath)
+// audioFormatExtensions are the file extensions of audio already in a stream
+// format, which is served without transcoding
+var audioFormatExtensions = map[string]string{
+	"opus": ".opus",
+	"mp3":  ".mp3",
+}
+
+// audioStreamFormat reads the format query parameter, responding with 400 if
+// it names no stream format
+func audioStreamFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		return "", true
+	}
+	if _, ok := audio.StreamFormats[format]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported format %q; use opus or mp3", format)})
+		return "", false
+	}
+	return format, true
 }
 
 // @Summary Login
@@ -1466,6 +1948,7 @@ func (h *Handler) Login(c *gin.Context) {
 	response := LoginResponse{Token: token}
 	response.User.ID = user.ID
 	response.User.Username = user.Username
+	response.User.Role = user.Role
 
 	logger.AuthEvent("login", req.Username, c.ClientIP(), true)
 	c.JSON(http.StatusOK, response)
@@ -1558,10 +2041,11 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	// Create user
+	// Create user; the first account administers the instance
 	user := models.User{
 		Username: req.Username,
 		Password: hashedPassword,
+		Role:     models.RoleAdmin,
 	}
 
 	if err := database.DB.Create(&user).Error; err != nil {
@@ -1587,6 +2071,7 @@ func (h *Handler) Register(c *gin.Context) {
 	response := LoginResponse{Token: token}
 	response.User.ID = user.ID
 	response.User.Username = user.Username
+	response.User.Role = user.Role
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -1793,7 +2278,7 @@ func (h *Handler) ListAPIKeys(c *gin.Context) {
 }
 
 // @Summary Create API key
-// @Description Create a new API key for external API access
+// @Description Create a new API key for external API access. The key acts with the given role (editor by default), narrowed by its scopes: submit keys only submit jobs and follow them, read keys only read, and only admin-scoped keys change or delete data. Keys can expire and be rate limited per minute.
 // @Tags api-keys
 // @Accept json
 // @Produce json
@@ -1809,15 +2294,51 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 		return
 	}
 
+	role := req.Role
+	if role == "" {
+		role = models.RoleEditor
+	}
+	if !models.ValidRole(role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be admin, editor or viewer"})
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !models.ValidScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Scopes must be submit, read or admin"})
+			return
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expiry must be in the future"})
+		return
+	}
+
+	organizationID := c.GetString("organization_id")
+	if req.OrganizationID != nil {
+		organizationID = *req.OrganizationID
+	}
+	if organizationID != "" {
+		if _, err := h.userRepo.FindOrganization(c.Request.Context(), organizationID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
+			return
+		}
+	}
+
 	// Generate a secure API key
 	apiKey := generateSecureAPIKey(32)
 
 	// Create the API key record
 	newKey := models.APIKey{
-		Key:         apiKey,
-		Name:        req.Name,
-		Description: &req.Description,
-		IsActive:    true,
+		Key:            apiKey,
+		Name:           req.Name,
+		Description:    &req.Description,
+		IsActive:       true,
+		Role:           role,
+		OrganizationID: organizationID,
+		Scopes:         strings.Join(req.Scopes, ","),
+		ExpiresAt:      req.ExpiresAt,
+		RateLimit:      req.RateLimit,
 	}
 
 	if err := h.apiKeyRepo.Create(c.Request.Context(), &newKey); err != nil {
@@ -1924,6 +2445,10 @@ func (h *Handler) SaveLLMConfig(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing configuration"})
 		return
 	}
+	// An organization still using the instance's config gets a config of its own
+	if existingConfig != nil && existingConfig.OrganizationID != c.GetString("organization_id") {
+		existingConfig, err = nil, gorm.ErrRecordNotFound
+	}
 
 	// Handle API Key logic for OpenAI
 	var apiKeyToSave *string
@@ -2004,7 +2529,7 @@ func generateSecureAPIKey(length int) string {
 }
 
 // @Summary Get queue statistics
-// @Description Get current queue statistics
+// @Description Get current queue statistics, with the host's free GPU memory and CPU load and the GPU memory reserved by running local jobs under resources
 // @Tags admin
 // @Produce json
 // @Success 200 {object} map[string]interface{}
@@ -2013,9 +2538,108 @@ func generateSecureAPIKey(length int) string {
 // @Security BearerAuth
 func (h *Handler) GetQueueStats(c *gin.Context) {
 	stats := h.taskQueue.GetQueueStats()
+	if h.unifiedProcessor != nil {
+		stats["resources"] = h.unifiedProcessor.ResourceStatus(c.Request.Context())
+	}
 	c.JSON(http.StatusOK, stats)
 }
 
+// @Summary Get quick transcription cleanup statistics
+// @Description Report the retention and storage policies of quick transcription temp files and the space reclaimed since startup
+// @Tags admin
+// @Produce json
+// @Success 200 {object} transcription.QuickCleanupStats
+// @Router /api/v1/admin/quick-transcription/cleanup [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetQuickTranscriptionCleanupStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.quickTranscription.CleanupStats())
+}
+
+// @Summary Clean up quick transcriptions
+// @Description Apply the quick transcription retention and storage policies now instead of waiting for the next scheduled run
+// @Tags admin
+// @Produce json
+// @Success 200 {object} transcription.QuickCleanupStats
+// @Router /api/v1/admin/quick-transcription/cleanup [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) RunQuickTranscriptionCleanup(c *gin.Context) {
+	c.JSON(http.StatusOK, h.quickTranscription.Cleanup())
+}
+
+// @Summary Get model environment status
+// @Description Report the progress of the background Python environment bootstrap for each adapter. Jobs that need an environment in the pending or preparing state stay queued until it is ready.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/environments [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetEnvironmentStatus(c *gin.Context) {
+	environments := h.unifiedProcessor.GetEnvironmentStatus()
+
+	ready := 0
+	for _, env := range environments {
+		if env.State == registry.EnvironmentReady {
+			ready++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"environments": environments,
+		"ready":        ready,
+		"total":        len(environments),
+	})
+}
+
+// PrefetchAlignmentModelsRequest lists the languages to download alignment models for
+type PrefetchAlignmentModelsRequest struct {
+	Languages []string `json:"languages" binding:"required,min=1"`
+}
+
+// @Summary Prefetch alignment models
+// @Description Download the WhisperX alignment models for the given language codes ahead of time. Jobs in a language whose alignment model cannot be loaded still complete, with segment-level timestamps and alignment_fallback set on the job.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body PrefetchAlignmentModelsRequest true "Languages"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/alignment-models [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) PrefetchAlignmentModels(c *gin.Context) {
+	var req PrefetchAlignmentModelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, language := range req.Languages {
+		if !languageCodePattern.MatchString(language) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid language code %q", language)})
+			return
+		}
+	}
+
+	if err := h.unifiedProcessor.PrefetchAlignmentModels(c.Request.Context(), req.Languages); err != nil {
+		if errors.Is(err, transcription.ErrAlignmentPrefetchUnsupported) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to prefetch alignment models", "languages", req.Languages, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to download alignment models: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"languages": req.Languages})
+}
+
+// languageCodePattern matches the ISO 639 codes WhisperX uses for languages
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
 // @Summary Get supported models
 // @Description Get list of supported WhisperX models
 // @Tags transcription
@@ -2034,6 +2658,25 @@ func (h *Handler) GetSupportedModels(c *gin.Context) {
 	})
 }
 
+// @Summary Get model parameter schema
+// @Description Get the parameters a registered adapter accepts, with their types, defaults, ranges, options and UI groups, so forms can be built from them instead of hardcoding WhisperX flags. model_families are the profile model_family values that select the adapter. Model IDs are those listed by GET /api/v1/transcription/models.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Model ID, e.g. whisperx"
+// @Success 200 {object} transcription.ModelSchema
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/models/{id}/schema [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetModelSchema(c *gin.Context) {
+	schema, err := h.unifiedProcessor.GetModelSchema(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
 // Health check endpoint
 // @Summary Health check
 // @Description Check if the API is healthy
@@ -2126,6 +2769,15 @@ func (h *Handler) CreateProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile name is required"})
 		return
 	}
+	if !h.validateCustomWeights(c, &profile.Parameters) {
+		return
+	}
+	if !validateFailover(c, &profile.Parameters) {
+		return
+	}
+	if !h.validateVocabulary(c, &profile.Parameters) {
+		return
+	}
 
 	// Check if profile name already exists
 	// TODO: Add FindByName to ProfileRepository if needed, or rely on unique constraint error
@@ -2141,6 +2793,99 @@ func (h *Handler) CreateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, profile)
 }
 
+// validateCustomWeights checks the custom Whisper checkpoint and NeMo adapter
+// weights in params, if any, responding with 400 when they cannot be used
+func (h *Handler) validateCustomWeights(c *gin.Context, params *models.WhisperXParams) bool {
+	params.NemoAdapterPath = trimmedOrNil(params.NemoAdapterPath)
+	params.NemoAdapterName = trimmedOrNil(params.NemoAdapterName)
+	if params.NemoAdapterPath != nil {
+		if err := adapters.ValidateNemoAdapter(*params.NemoAdapterPath); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+	} else if params.NemoAdapterName != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nemo_adapter_name requires nemo_adapter_path"})
+		return false
+	}
+
+	params.CustomModel = trimmedOrNil(params.CustomModel)
+	if params.CustomModel == nil {
+		return true
+	}
+	customModel := *params.CustomModel
+
+	hfToken := ""
+	if params.HfToken != nil {
+		resolved, err := secrets.Resolve(c.Request.Context(), *params.HfToken)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		hfToken = resolved
+	}
+	if err := adapters.ValidateCustomWhisperModel(c.Request.Context(), customModel, hfToken); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// validateFailover checks the adapter failover settings in params, responding
+// with 400 when a fallback model family is unknown
+func validateFailover(c *gin.Context, params *models.WhisperXParams) bool {
+	if params.AdapterTimeoutSeconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "adapter_timeout_seconds must not be negative"})
+		return false
+	}
+	params.FallbackModelFamilies = trimmedOrNil(params.FallbackModelFamilies)
+	for _, family := range params.FallbackChain() {
+		if !transcription.KnownModelFamily(family) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown fallback model family %q", family)})
+			return false
+		}
+	}
+	return true
+}
+
+// applyParameterOverrides replaces the given fields of params, named as in
+// JSON, and checks the result as a profile's parameters would be and against
+// the schema of the adapters it selects, responding with 400 when it cannot
+// be used. It returns the overrides as JSON to be kept on the job.
+func (h *Handler) applyParameterOverrides(c *gin.Context, params *models.WhisperXParams, overrides map[string]interface{}) (*string, bool) {
+	if len(overrides) == 0 {
+		return nil, true
+	}
+	merged, err := params.WithOverrides(overrides)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !h.validateCustomWeights(c, &merged) || !validateFailover(c, &merged) || !h.validateVocabulary(c, &merged) {
+		return nil, false
+	}
+	if err := h.unifiedProcessor.ValidateJobParameters(merged); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid parameters: " + err.Error()})
+		return nil, false
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	*params = merged
+	value := string(data)
+	return &value, true
+}
+
+// trimmedOrNil trims an optional string, treating blank as unset
+func trimmedOrNil(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
+
 // @Summary Get transcription profile
 // @Description Get a transcription profile by ID
 // @Tags profiles
@@ -2196,6 +2941,15 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile name is required"})
 		return
 	}
+	if !h.validateCustomWeights(c, &updatedProfile.Parameters) {
+		return
+	}
+	if !validateFailover(c, &updatedProfile.Parameters) {
+		return
+	}
+	if !h.validateVocabulary(c, &updatedProfile.Parameters) {
+		return
+	}
 
 	// Check if profile name already exists (excluding current profile)
 	// TODO: Add check to repository
@@ -2204,6 +2958,7 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 	// We need to preserve ID and CreatedAt, and update other fields
 	// GORM Save updates all fields.
 	updatedProfile.ID = existingProfile.ID
+	updatedProfile.OrganizationID = existingProfile.OrganizationID
 	updatedProfile.CreatedAt = existingProfile.CreatedAt
 
 	if err := h.profileRepo.Update(c.Request.Context(), &updatedProfile); err != nil {
@@ -2285,16 +3040,18 @@ type QuickTranscriptionRequest struct {
 }
 
 // @Summary Submit quick transcription job
-// @Description Submit an audio file for temporary transcription (data discarded after 6 hours)
+// @Description Submit an audio file for temporary transcription (data discarded after 6 hours). With fast=true, clips of up to a minute are transcribed before the response by a small local model, without diarization or preprocessing, for voice commands and memos; the response is the completed job.
 // @Tags transcription
 // @Accept multipart/form-data
 // @Produce json
 // @Param audio formData file true "Audio file"
 // @Param parameters formData string false "JSON string of transcription parameters"
 // @Param profile_name formData string false "Profile name to use for transcription"
+// @Param fast formData bool false "Transcribe a short clip synchronously"
 // @Success 200 {object} transcription.QuickTranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /api/v1/transcription/quick [post]
 // @Security ApiKeyAuth
 // @Security BearerAuth
@@ -2307,6 +3064,40 @@ func (h *Handler) SubmitQuickTranscription(c *gin.Context) {
 	}
 	defer file.Close()
 
+	params, ok := h.quickTranscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	if fast, _ := strconv.ParseBool(c.DefaultPostForm("fast", c.Query("fast"))); fast {
+		job, err := h.quickTranscription.SubmitFastJob(c.Request.Context(), file, header.Filename, params)
+		switch {
+		case errors.Is(err, transcription.ErrClipTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, transcription.ErrFastModelNotReady):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to transcribe clip: %v", err)})
+		default:
+			c.JSON(http.StatusOK, job)
+		}
+		return
+	}
+
+	// Submit quick transcription job
+	job, err := h.quickTranscription.SubmitQuickJob(file, header.Filename, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to submit quick transcription: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// quickTranscriptionParams reads the parameters of a quick transcription from
+// the form: those of profile_name, the parameters JSON or the defaults.
+// It answers 400 or 500 and returns false if they cannot be read.
+func (h *Handler) quickTranscriptionParams(c *gin.Context) (models.WhisperXParams, bool) {
 	var params models.WhisperXParams
 
 	// Check if profile_name was provided
@@ -2316,17 +3107,17 @@ func (h *Handler) SubmitQuickTranscription(c *gin.Context) {
 		if err := database.DB.Where("name = ?", profileName).First(&profile).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Profile '%s' not found", profileName)})
-				return
+				return params, false
 			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load profile"})
-			return
+			return params, false
 		}
 		params = profile.Parameters
 	} else if parametersJSON := c.PostForm("parameters"); parametersJSON != "" {
 		// Parse parameters from JSON string
 		if err := json.Unmarshal([]byte(parametersJSON), &params); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters JSON"})
-			return
+			return params, false
 		}
 	} else {
 		// Use default parameters with all required fields
@@ -2385,15 +3176,7 @@ func (h *Handler) SubmitQuickTranscription(c *gin.Context) {
 			PrintProgress:     false,
 		}
 	}
-
-	// Submit quick transcription job
-	job, err := h.quickTranscription.SubmitQuickJob(file, header.Filename, params)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to submit quick transcription: %v", err)})
-		return
-	}
-
-	c.JSON(http.StatusOK, job)
+	return params, true
 }
 
 // @Summary Get quick transcription status
@@ -2447,8 +3230,12 @@ func (h *Handler) DownloadFromYouTube(c *gin.Context) {
 		return
 	}
 
-	// Create upload directory
-	uploadDir := h.config.UploadDir
+	// Create upload directory in the requester's workspace
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
 		return
@@ -2537,6 +3324,7 @@ func (h *Handler) DownloadFromYouTube(c *gin.Context) {
 		ID:        jobID,
 		AudioPath: actualFilePath,
 		Status:    models.StatusUploaded,
+		Workspace: workspace,
 	}
 
 	// Set title
@@ -2682,15 +3470,41 @@ func (h *Handler) SetUserDefaultProfile(c *gin.Context) {
 type UserSettingsResponse struct {
 	AutoTranscriptionEnabled bool    `json:"auto_transcription_enabled"`
 	DefaultProfileID         *string `json:"default_profile_id,omitempty"`
+	TimeZone                 string  `json:"time_zone"`
+	Locale                   string  `json:"locale"`
+	NotificationEmail        string  `json:"notification_email"`
+	NotifyOnCompleted        bool    `json:"notify_on_completed"`
+	NotifyOnFailed           bool    `json:"notify_on_failed"`
+	NotifyAttachTranscript   bool    `json:"notify_attach_transcript"`
 }
 
 // UpdateUserSettingsRequest represents the request to update user settings
 type UpdateUserSettingsRequest struct {
-	AutoTranscriptionEnabled *bool `json:"auto_transcription_enabled,omitempty"`
+	AutoTranscriptionEnabled *bool   `json:"auto_transcription_enabled,omitempty"`
+	TimeZone                 *string `json:"time_zone,omitempty"`          // IANA name, e.g. Europe/Berlin; "" for UTC
+	Locale                   *string `json:"locale,omitempty"`             // e.g. en-US or de-DE; "" for ISO
+	NotificationEmail        *string `json:"notification_email,omitempty"` // "" turns email notifications off
+	NotifyOnCompleted        *bool   `json:"notify_on_completed,omitempty"`
+	NotifyOnFailed           *bool   `json:"notify_on_failed,omitempty"`
+	NotifyAttachTranscript   *bool   `json:"notify_attach_transcript,omitempty"`
+}
+
+// newUserSettingsResponse returns the settings of user
+func newUserSettingsResponse(user *models.User) UserSettingsResponse {
+	return UserSettingsResponse{
+		AutoTranscriptionEnabled: user.AutoTranscriptionEnabled,
+		DefaultProfileID:         user.DefaultProfileID,
+		TimeZone:                 user.TimeZone,
+		Locale:                   user.Locale,
+		NotificationEmail:        user.NotificationEmail,
+		NotifyOnCompleted:        user.NotifyOnCompleted,
+		NotifyOnFailed:           user.NotifyOnFailed,
+		NotifyAttachTranscript:   user.NotifyAttachTranscript,
+	}
 }
 
 // @Summary Get user settings
-// @Description Get the current user's settings including auto-transcription preference
+// @Description Get the current user's settings including auto-transcription preference, the time zone and locale used in exports and email notification preferences
 // @Tags user
 // @Produce json
 // @Success 200 {object} UserSettingsResponse
@@ -2711,16 +3525,11 @@ func (h *Handler) GetUserSettings(c *gin.Context) {
 		return
 	}
 
-	response := UserSettingsResponse{
-		AutoTranscriptionEnabled: user.AutoTranscriptionEnabled,
-		DefaultProfileID:         user.DefaultProfileID,
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, newUserSettingsResponse(user))
 }
 
 // @Summary Update user settings
-// @Description Update the current user's settings
+// @Description Update the current user's settings. Jobs that finish are emailed to notification_email when SMTP is configured, according to notify_on_completed and notify_on_failed; notify_attach_transcript attaches the transcript as text.
 // @Tags user
 // @Accept json
 // @Produce json
@@ -2754,6 +3563,39 @@ func (h *Handler) UpdateUserSettings(c *gin.Context) {
 	if req.AutoTranscriptionEnabled != nil {
 		user.AutoTranscriptionEnabled = *req.AutoTranscriptionEnabled
 	}
+	if req.TimeZone != nil {
+		if _, err := export.LoadTimeZone(*req.TimeZone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		user.TimeZone = *req.TimeZone
+	}
+	if req.Locale != nil {
+		if _, err := export.LookupLocale(*req.Locale); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		user.Locale = *req.Locale
+	}
+	if req.NotificationEmail != nil {
+		email := strings.TrimSpace(*req.NotificationEmail)
+		if email != "" {
+			if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification email address"})
+				return
+			}
+		}
+		user.NotificationEmail = email
+	}
+	if req.NotifyOnCompleted != nil {
+		user.NotifyOnCompleted = *req.NotifyOnCompleted
+	}
+	if req.NotifyOnFailed != nil {
+		user.NotifyOnFailed = *req.NotifyOnFailed
+	}
+	if req.NotifyAttachTranscript != nil {
+		user.NotifyAttachTranscript = *req.NotifyAttachTranscript
+	}
 
 	// Save updated user
 	if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
@@ -2761,10 +3603,5 @@ func (h *Handler) UpdateUserSettings(c *gin.Context) {
 		return
 	}
 
-	response := UserSettingsResponse{
-		AutoTranscriptionEnabled: user.AutoTranscriptionEnabled,
-		DefaultProfileID:         user.DefaultProfileID,
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, newUserSettingsResponse(user))
 }
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get the integrity report
+// @Description Get the report of the latest integrity check, run at startup: jobs whose audio files or S3 objects are missing, and processing jobs no worker was running, with what was done about each
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.IntegrityReport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/integrity [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetIntegrityReport(c *gin.Context) {
+	report := h.integrity.LastReport()
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No integrity check has run yet"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Run an integrity check
+// @Description Check every job against its files now. Unfinished jobs whose audio is missing are failed, processing jobs no worker is running are queued again, and completed jobs missing a file are flagged.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.IntegrityReport
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/integrity [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) RunIntegrityCheck(c *gin.Context) {
+	report, err := h.integrity.Run(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to run integrity check"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
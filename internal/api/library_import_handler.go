@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LibraryImportRequest starts a bulk library import
+type LibraryImportRequest struct {
+	// Source is an s3://bucket/prefix or a directory under LIBRARY_IMPORT_DIR
+	Source    string  `json:"source" binding:"required"`
+	ProfileID *string `json:"profile_id,omitempty"`
+	DryRun    bool    `json:"dry_run"`
+	// Dedup skips files already imported into the workspace (default true)
+	Dedup *bool `json:"dedup,omitempty"`
+	// RatePerMinute caps how many jobs are queued a minute (default LIBRARY_IMPORT_RATE_PER_MINUTE)
+	RatePerMinute int `json:"rate_per_minute,omitempty" binding:"min=0"`
+}
+
+// LibraryImportFilesResponse is a page of the files an import found
+type LibraryImportFilesResponse struct {
+	Files  []models.LibraryImportFile `json:"files"`
+	Total  int64                      `json:"total"`
+	Limit  int                        `json:"limit"`
+	Offset int                        `json:"offset"`
+}
+
+// @Summary Start a library import
+// @Description Walk an S3 prefix or a local directory under LIBRARY_IMPORT_DIR in the background and create a job in the request's workspace for every audio file found. Files already imported into the workspace, by location or content, are skipped unless dedup is false; dry runs only list what they would import. Jobs are queued at most rate_per_minute a minute and only while few of the import's jobs are waiting.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body LibraryImportRequest true "Import"
+// @Success 202 {object} models.LibraryImport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/imports [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) StartLibraryImport(c *gin.Context) {
+	var req LibraryImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.ProfileID != nil && *req.ProfileID == "" {
+		req.ProfileID = nil
+	}
+	if req.ProfileID != nil {
+		if _, err := h.profileRepo.FindByID(c.Request.Context(), *req.ProfileID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+			return
+		}
+	}
+
+	imp := models.LibraryImport{
+		Workspace:     h.requestWorkspace(c),
+		Source:        req.Source,
+		ProfileID:     req.ProfileID,
+		DryRun:        req.DryRun,
+		Dedup:         req.Dedup == nil || *req.Dedup,
+		RatePerMinute: req.RatePerMinute,
+		CreatedBy:     h.requestAuthor(c),
+	}
+	if err := h.libraryImports.Start(c.Request.Context(), &imp); err != nil {
+		if errors.Is(err, service.ErrInvalidImportSource) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to start library import", "source", req.Source, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start library import"})
+		return
+	}
+	c.JSON(http.StatusAccepted, imp)
+}
+
+// @Summary List library imports
+// @Description List the library imports of the workspaces the request may see, newest first
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.LibraryImport
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/imports [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListLibraryImports(c *gin.Context) {
+	imports, err := h.libraryImports.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list library imports"})
+		return
+	}
+	c.JSON(http.StatusOK, imports)
+}
+
+// @Summary Get a library import
+// @Description Get the progress of a library import
+// @Tags admin
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} models.LibraryImport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/imports/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetLibraryImport(c *gin.Context) {
+	imp, err := h.libraryImports.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library import not found"})
+		return
+	}
+	c.JSON(http.StatusOK, imp)
+}
+
+// @Summary List the files of a library import
+// @Description List the files a library import found, in the order found, with what it did with each: queued, planned (dry run), duplicate or failed
+// @Tags admin
+// @Produce json
+// @Param id path string true "Import ID"
+// @Param status query string false "Only files with this status"
+// @Param limit query int false "Page size (1-1000)" default(100)
+// @Param offset query int false "Files to skip" default(0)
+// @Success 200 {object} LibraryImportFilesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/imports/{id}/files [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListLibraryImportFiles(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+		return
+	}
+
+	files, total, err := h.libraryImports.ListFiles(c.Request.Context(), c.Param("id"), c.Query("status"), offset, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library import not found"})
+		return
+	}
+	c.JSON(http.StatusOK, LibraryImportFilesResponse{Files: files, Total: total, Limit: limit, Offset: offset})
+}
+
+// @Summary Cancel a library import
+// @Description Stop a running library import. The jobs it already queued are kept, and running the import again skips them.
+// @Tags admin
+// @Param id path string true "Import ID"
+// @Success 202
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/imports/{id}/cancel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CancelLibraryImport(c *gin.Context) {
+	if err := h.libraryImports.Cancel(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No running library import with that ID"})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
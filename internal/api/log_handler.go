@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"scriberr/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,8 +22,19 @@ import (
 func (h *Handler) GetJobLogs(c *gin.Context) {
 	jobID := c.Param("id")
 
-	// Construct path to log file
-	logPath := filepath.Join(h.config.TranscriptsDir, jobID, "transcription.log")
+	job, err := h.jobRepo.FindByID(c.Request.Context(), jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	// Construct path to log file inside the job's workspace
+	outputDir, err := service.WorkspaceDir(h.config.TranscriptsDir, job.Workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
+	logPath := filepath.Join(outputDir, job.ID, "transcription.log")
 
 	// Check if file exists
 	exists, err := h.fileService.FileExists(logPath)
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxTracks caps the tracks of a multi-track job
+	maxTracks = 16
+	// maxTrackNameLength matches the track_name column
+	maxTrackNameLength = 100
+)
+
+// MultiTrackRequest creates a multi-track job from named tracks
+type MultiTrackRequest struct {
+	Title *string `json:"title,omitempty"`
+	// Parameters apply to every track; the default profile's are used without them
+	Parameters *models.WhisperXParams `json:"parameters,omitempty"`
+	Tracks     []TrackRequest         `json:"tracks"`
+}
+
+// TrackRequest is one track of a multi-track job. Its audio is either uploaded
+// in the multipart field named by File or downloaded from URL.
+type TrackRequest struct {
+	// Name is the speaker the track's words are attributed to
+	Name   string  `json:"name"`
+	File   string  `json:"file,omitempty"`
+	URL    string  `json:"url,omitempty"`
+	Offset float64 `json:"offset,omitempty"` // seconds the track starts after the first
+	// Parameters overrides the job's parameters for this track, e.g. its language
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// @Summary Create a multi-track job
+// @Description Create a multi-track job from named tracks, such as "host" and "guest", and queue it. Each track is transcribed on its own, with the job's parameters and the track's overrides, and the transcripts are merged by timestamp with the track name as the speaker. Send multipart/form-data with the request as JSON in the manifest field and each track's audio in the field its file names, or JSON whose tracks all give a URL (e.g. presigned S3 URLs) to download the audio from; jobs with URL tracks stay "downloading" until all are fetched.
+// @Tags transcription
+// @Accept json,multipart/form-data
+// @Produce json
+// @Param request body MultiTrackRequest false "Multi-track request (JSON requests)"
+// @Param manifest formData string false "Multi-track request as JSON (multipart requests)"
+// @Success 202 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/multitrack [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateMultiTrackJob(c *gin.Context) {
+	var req MultiTrackRequest
+	multipart := strings.HasPrefix(c.ContentType(), "multipart/")
+	if multipart {
+		manifest := c.PostForm("manifest")
+		if manifest == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "manifest is required"})
+			return
+		}
+		if err := json.Unmarshal([]byte(manifest), &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manifest: " + err.Error()})
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateTracks(req.Tracks, multipart); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Use explicit parameters when given, otherwise fall back to the default profile
+	var params models.WhisperXParams
+	if req.Parameters != nil {
+		params = *req.Parameters
+	} else if profile := h.getDefaultProfile(c.Request.Context()); profile != nil {
+		params = profile.Parameters
+	}
+	if req.Parameters != nil && req.Parameters.Diarize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Diarization must be disabled when using multi-track transcription"})
+		return
+	}
+	params.IsMultiTrackEnabled = true
+	params.Diarize = false
+	if !h.validateCustomWeights(c, &params) {
+		return
+	}
+	overrides := make([]*string, len(req.Tracks))
+	for i, track := range req.Tracks {
+		if len(track.Parameters) == 0 {
+			continue
+		}
+		trackParams, err := params.WithOverrides(track.Parameters)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("track %q: %v", track.Name, err)})
+			return
+		}
+		if !h.validateCustomWeights(c, &trackParams) {
+			return
+		}
+		data, _ := json.Marshal(track.Parameters)
+		value := string(data)
+		overrides[i] = &value
+	}
+
+	jobID := uuid.New().String()
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
+	jobDir := filepath.Join(uploadDir, jobID)
+	if err := h.fileService.CreateDirectory(jobDir); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job directory"})
+		return
+	}
+
+	var downloads []service.TrackDownload
+	trackFiles := make([]models.MultiTrackFile, len(req.Tracks))
+	for i, track := range req.Tracks {
+		trackFile := models.MultiTrackFile{
+			TranscriptionJobID: jobID,
+			TrackName:          track.Name,
+			TrackIndex:         i,
+			Offset:             track.Offset,
+			Gain:               1.0,
+			Parameters:         overrides[i],
+		}
+		if track.File != "" {
+			fileHeader, err := c.FormFile(track.File)
+			if err != nil {
+				h.fileService.RemoveDirectory(jobDir)
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("track %q: no file uploaded in field %q", track.Name, track.File)})
+				return
+			}
+			path, err := h.fileService.SaveUpload(fileHeader, jobDir)
+			if err != nil {
+				h.fileService.RemoveDirectory(jobDir)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save file %s", fileHeader.Filename)})
+				return
+			}
+			trackFile.FilePath = path
+			trackFile.FileName = track.Name + filepath.Ext(fileHeader.Filename)
+		} else {
+			ext := trackURLExt(track.URL)
+			trackFile.FilePath = filepath.Join(jobDir, uuid.New().String()+ext)
+			trackFile.FileName = track.Name + ext
+			downloads = append(downloads, service.TrackDownload{URL: track.URL, Path: trackFile.FilePath})
+		}
+		trackFiles[i] = trackFile
+	}
+
+	job := models.TranscriptionJob{
+		ID:              jobID,
+		Title:           req.Title,
+		Status:          models.StatusPending,
+		IsMultiTrack:    true,
+		MultiTrackFiles: trackFiles,
+		Parameters:      params,
+		Workspace:       workspace,
+	}
+	if job.Title == nil {
+		defaultTitle := fmt.Sprintf("Multi-track Job %s", jobID)
+		job.Title = &defaultTitle
+	}
+
+	if len(downloads) > 0 {
+		if err := h.urlIngest.SubmitTracks(c.Request.Context(), &job, downloads); err != nil {
+			h.fileService.RemoveDirectory(jobDir)
+			if errors.Is(err, service.ErrInvalidSourceURL) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, service.ErrQuotaExceeded) {
+				quotaExceeded(c)
+				return
+			}
+			logger.Error("Failed to submit multi-track job", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+			return
+		}
+		c.JSON(http.StatusAccepted, job)
+		return
+	}
+
+	if err := h.jobRepo.Create(c.Request.Context(), &job); err != nil {
+		h.fileService.RemoveDirectory(jobDir)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return
+	}
+	if err := h.enqueueJob(c.Request.Context(), jobID); err != nil {
+		// The pending-job scanner picks it up
+		logger.Warn("Failed to enqueue multi-track job", "job_id", jobID, "error", err)
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// validateTracks checks that tracks have distinct names and each exactly one
+// source; uploaded files need a multipart request
+func validateTracks(tracks []TrackRequest, multipart bool) error {
+	if len(tracks) == 0 {
+		return errors.New("at least one track is required")
+	}
+	if len(tracks) > maxTracks {
+		return fmt.Errorf("at most %d tracks are allowed", maxTracks)
+	}
+	names := make(map[string]bool, len(tracks))
+	for i := range tracks {
+		track := &tracks[i]
+		track.Name = strings.TrimSpace(track.Name)
+		if track.Name == "" {
+			return fmt.Errorf("track %d has no name", i+1)
+		}
+		if len(track.Name) > maxTrackNameLength || strings.ContainsAny(track.Name, `/\`) {
+			return fmt.Errorf("track name %q is invalid", track.Name)
+		}
+		key := strings.ToLower(track.Name)
+		if names[key] {
+			return fmt.Errorf("track name %q is used twice", track.Name)
+		}
+		names[key] = true
+		if (track.File == "") == (track.URL == "") {
+			return fmt.Errorf("track %q needs either a file or a URL", track.Name)
+		}
+		if track.File != "" && !multipart {
+			return fmt.Errorf("track %q: uploaded files need a multipart request", track.Name)
+		}
+		if track.Offset < 0 {
+			return fmt.Errorf("track %q has a negative offset", track.Name)
+		}
+	}
+	return nil
+}
+
+// trackURLExt returns the file extension of the path of a track's URL, which
+// presigned URLs keep
+func trackURLExt(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	ext := filepath.Ext(u.Path)
+	if len(ext) > 8 {
+		return ""
+	}
+	return strings.ToLower(ext)
+}
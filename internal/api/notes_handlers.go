@@ -134,6 +134,9 @@ func (h *Handler) CreateNote(c *gin.Context) {
 	}
 
 	if err := h.noteRepo.Create(c.Request.Context(), n); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
 		log.Printf("notes.CreateNote: DB error creating note for transcription %s (start=%d end=%d startTime=%.3f endTime=%.3f): %v", transcriptionID, n.StartWordIndex, n.EndWordIndex, n.StartTime, n.EndTime, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create note"})
 		return
@@ -205,6 +208,9 @@ func (h *Handler) UpdateNote(c *gin.Context) {
 	n.UpdatedAt = time.Now()
 
 	if err := h.noteRepo.Update(c.Request.Context(), n); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update note"})
 		return
 	}
@@ -225,6 +231,9 @@ func (h *Handler) UpdateNote(c *gin.Context) {
 func (h *Handler) DeleteNote(c *gin.Context) {
 	noteID := c.Param("note_id")
 	if err := h.noteRepo.Delete(c.Request.Context(), noteID); err != nil {
+		if finalizedConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete note"})
 		return
 	}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationTemplateRequest sets a channel's message template
+type NotificationTemplateRequest struct {
+	Body        string `json:"body" binding:"required,max=65536"`
+	ContentType string `json:"content_type" binding:"max=100"`
+}
+
+// NotificationTemplatePreviewRequest renders a template without saving it,
+// with a job's fields or sample ones
+type NotificationTemplatePreviewRequest struct {
+	NotificationTemplateRequest
+	JobID string `json:"job_id"`
+}
+
+// notificationChannel returns the channel named in the path, answering 404 for
+// unknown ones
+func notificationChannel(c *gin.Context) (string, bool) {
+	channel := c.Param("channel")
+	if !slices.Contains(models.NotificationChannels, channel) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown notification channel"})
+		return "", false
+	}
+	return channel, true
+}
+
+// newNotificationTemplate validates a request by rendering it with sample data
+func newNotificationTemplate(c *gin.Context, channel string, req NotificationTemplateRequest) (*models.NotificationTemplate, bool) {
+	tmpl := &models.NotificationTemplate{
+		Channel:     channel,
+		Body:        req.Body,
+		ContentType: strings.TrimSpace(req.ContentType),
+	}
+	switch {
+	case channel == models.NotificationChannelEmail:
+		tmpl.ContentType = "text/plain; charset=utf-8"
+	case tmpl.ContentType == "" || channel == models.NotificationChannelEventBridge:
+		tmpl.ContentType = "application/json"
+	}
+	if _, err := webhook.Render(tmpl, sampleTemplateData()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template: " + err.Error()})
+		return nil, false
+	}
+	return tmpl, true
+}
+
+// sampleTemplateData is a completed job to check and preview templates with
+func sampleTemplateData() webhook.TemplateData {
+	title := "Weekly sync"
+	transcript := `{"text":"Hello and welcome.","segments":[{"start":0,"end":1.5,"text":"Hello and welcome.","speaker":"SPEAKER_00"}],"language":"en"}`
+	summary := "A short weekly sync."
+	job := &models.TranscriptionJob{
+		ID:         "00000000-0000-0000-0000-000000000000",
+		Title:      &title,
+		Status:     models.StatusCompleted,
+		AudioPath:  "data/uploads/sample.wav",
+		Transcript: &transcript,
+		Summary:    &summary,
+		CreatedAt:  time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+	}
+	data := webhook.NewTemplateData(job, "COMPLETED")
+	data.CompletedAt = job.CreatedAt.Add(3 * time.Minute)
+	data.Metadata = map[string]interface{}{"model": "large-v3", "model_family": "whisper", "duration_ms": 180000}
+	data.Link = "https://scriberr.example.com/audio/" + job.ID
+	return data
+}
+
+// @Summary List notification templates
+// @Description List the message templates that replace the built-in webhook payload, EventBridge event detail and email body. Channels without one send the built-in message.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.NotificationTemplate
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/notification-templates [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListNotificationTemplates(c *gin.Context) {
+	templates := []models.NotificationTemplate{}
+	if err := database.DB.WithContext(c.Request.Context()).Order("channel").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list notification templates"})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Summary Set a notification template
+// @Description Replace a channel's built-in message with a Go template rendered with the job's fields: .JobID, .Title, .Status, .Event, .AudioPath, .AudioURI, .Transcript, .Text, .Summary, .ErrorMessage, .Tags, .Metadata, .CreatedAt, .CompletedAt, .Link (emails only) and .Job. Functions: json, default, truncate, upper, lower and rfc3339. JSON templates (EventBridge, or a JSON content type) must render valid JSON; use json to quote values. Email templates render the plain-text body.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param channel path string true "Channel (webhook, eventbridge or email)"
+// @Param request body NotificationTemplateRequest true "Template"
+// @Success 200 {object} models.NotificationTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/notification-templates/{channel} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateNotificationTemplate(c *gin.Context) {
+	channel, ok := notificationChannel(c)
+	if !ok {
+		return
+	}
+	var req NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	tmpl, ok := newNotificationTemplate(c, channel, req)
+	if !ok {
+		return
+	}
+	tmpl.UpdatedBy = h.requestAuthor(c)
+	if err := database.DB.WithContext(c.Request.Context()).Save(tmpl).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save notification template"})
+		return
+	}
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// @Summary Reset a notification template
+// @Description Remove a channel's template, so it sends the built-in message again
+// @Tags admin
+// @Param channel path string true "Channel (webhook, eventbridge or email)"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/notification-templates/{channel} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteNotificationTemplate(c *gin.Context) {
+	channel, ok := notificationChannel(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.WithContext(c.Request.Context()).Delete(&models.NotificationTemplate{}, "channel = ?", channel).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification template"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Preview a notification template
+// @Description Render a template without saving it, with the fields of the given job or of a sample one
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param channel path string true "Channel (webhook, eventbridge or email)"
+// @Param request body NotificationTemplatePreviewRequest true "Template and optional job"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/notification-templates/{channel}/preview [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) PreviewNotificationTemplate(c *gin.Context) {
+	channel, ok := notificationChannel(c)
+	if !ok {
+		return
+	}
+	var req NotificationTemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	tmpl, ok := newNotificationTemplate(c, channel, req.NotificationTemplateRequest)
+	if !ok {
+		return
+	}
+
+	data := sampleTemplateData()
+	if req.JobID != "" {
+		job, err := h.jobRepo.FindByID(c.Request.Context(), req.JobID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		event := string(job.Status)
+		if channel == models.NotificationChannelEventBridge {
+			event = strings.ToUpper(event)
+		}
+		data = webhook.NewTemplateData(job, event)
+	}
+	body, err := webhook.Render(tmpl, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"content_type": tmpl.ContentType, "body": string(body)})
+}
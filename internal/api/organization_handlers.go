@@ -0,0 +1,310 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateOrganizationRequest creates an organization
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// AddOrganizationMemberRequest adds a user to an organization
+type AddOrganizationMemberRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// RequireJobAccess hides jobs of other organizations: a request naming, in the
+// given path parameter, a job outside its organization, or in a collection it
+// may not access, gets a 404 as if the job did not exist. Jobs of collections
+// the request may only view cannot be changed. Unknown IDs are left to the
+// handler.
+func (h *Handler) RequireJobAccess(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param(param)
+		if id == "" {
+			c.Next()
+			return
+		}
+		job, err := h.jobRepo.FindByID(c.Request.Context(), id)
+		if err == nil && !models.WorkspaceVisible(c.Request.Context(), job.Workspace) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			c.Abort()
+			return
+		}
+		if err == nil && !h.checkCollectionAccess(c, job, "Transcription job not found") {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireNoteAccess hides notes on jobs the request cannot see, as
+// RequireJobAccess does for the jobs themselves
+func (h *Handler) RequireNoteAccess(param string) gin.HandlerFunc {
+	return h.requireParentJobAccess(param, "Note not found", func(ctx context.Context, id string) (string, error) {
+		note, err := h.noteRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return note.TranscriptionID, nil
+	})
+}
+
+// RequireChatSessionAccess hides chat sessions on jobs the request cannot see
+func (h *Handler) RequireChatSessionAccess(param string) gin.HandlerFunc {
+	return h.requireParentJobAccess(param, "Chat session not found", func(ctx context.Context, id string) (string, error) {
+		session, err := h.chatRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return session.TranscriptionID, nil
+	})
+}
+
+// requireParentJobAccess responds 404 to requests naming, in the given path
+// parameter, a record whose job is outside the request's workspace or
+// collections. jobOf
+// returns the record's job; unknown records are left to the handler.
+func (h *Handler) requireParentJobAccess(param, notFound string, jobOf func(ctx context.Context, id string) (string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param(param)
+		if id == "" {
+			c.Next()
+			return
+		}
+		jobID, err := jobOf(c.Request.Context(), id)
+		if err != nil {
+			c.Next()
+			return
+		}
+		job, err := h.jobRepo.FindByID(c.Request.Context(), jobID)
+		if err == nil && !models.WorkspaceVisible(c.Request.Context(), job.Workspace) {
+			c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+			c.Abort()
+			return
+		}
+		if err == nil && !h.checkCollectionAccess(c, job, notFound) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// managedOrganization loads the organization in the path
+func (h *Handler) managedOrganization(c *gin.Context) (*models.Organization, bool) {
+	organization, err := h.userRepo.FindOrganization(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get organization"})
+		return nil, false
+	}
+	return organization, true
+}
+
+// @Summary List organizations
+// @Description List the organizations of the instance
+// @Tags organizations
+// @Produce json
+// @Success 200 {array} models.Organization
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/organizations [get]
+// @Security BearerAuth
+func (h *Handler) ListOrganizations(c *gin.Context) {
+	organizations, err := h.userRepo.ListOrganizations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list organizations"})
+		return
+	}
+	c.JSON(http.StatusOK, organizations)
+}
+
+// @Summary Create organization
+// @Description Create an organization. Its members, and API keys created for it, only see its jobs, profiles and LLM configuration, and its files are stored under a prefix of its own.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param request body CreateOrganizationRequest true "Organization"
+// @Success 201 {object} models.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/organizations [post]
+// @Security BearerAuth
+func (h *Handler) CreateOrganization(c *gin.Context) {
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization name is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	organizations, err := h.userRepo.ListOrganizations(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organization"})
+		return
+	}
+	for _, existing := range organizations {
+		if strings.EqualFold(existing.Name, name) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Organization name already exists"})
+			return
+		}
+	}
+
+	organization := models.Organization{Name: name}
+	if err := h.userRepo.CreateOrganization(ctx, &organization); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organization"})
+		return
+	}
+	c.JSON(http.StatusCreated, organization)
+}
+
+// @Summary Delete organization
+// @Description Delete an organization with its memberships, profiles and LLM configuration, and deactivate its API keys. Organizations that still have jobs cannot be deleted.
+// @Tags organizations
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/organizations/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteOrganization(c *gin.Context) {
+	organization, ok := h.managedOrganization(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	jobs, err := h.userRepo.CountOrganizationJobs(ctx, organization.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete organization"})
+		return
+	}
+	if jobs > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Organization still has transcription jobs"})
+		return
+	}
+	if err := h.userRepo.DeleteOrganization(ctx, organization.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete organization"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Organization deleted successfully"})
+}
+
+// @Summary List organization members
+// @Description List the users of an organization
+// @Tags organizations
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {array} models.User
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/organizations/{id}/members [get]
+// @Security BearerAuth
+func (h *Handler) ListOrganizationMembers(c *gin.Context) {
+	organization, ok := h.managedOrganization(c)
+	if !ok {
+		return
+	}
+	users, err := h.userRepo.ListOrganizationMembers(c.Request.Context(), organization.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list members"})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Add organization member
+// @Description Add a user to an organization. Users act in their first organization unless they pick another with the X-Organization-ID header.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param request body AddOrganizationMemberRequest true "Member"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/organizations/{id}/members [post]
+// @Security BearerAuth
+func (h *Handler) AddOrganizationMember(c *gin.Context) {
+	organization, ok := h.managedOrganization(c)
+	if !ok {
+		return
+	}
+	var req AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.userRepo.FindByID(ctx, req.UserID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err := h.userRepo.AddOrganizationMember(ctx, organization.ID, req.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add member"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member added successfully"})
+}
+
+// @Summary Remove organization member
+// @Description Remove a user from an organization
+// @Tags organizations
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/organizations/{id}/members/{user_id} [delete]
+// @Security BearerAuth
+func (h *Handler) RemoveOrganizationMember(c *gin.Context) {
+	organization, ok := h.managedOrganization(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if err := h.userRepo.RemoveOrganizationMember(c.Request.Context(), organization.ID, uint(userID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// @Summary List my organizations
+// @Description List the organizations of the current user, oldest membership first
+// @Tags organizations
+// @Produce json
+// @Success 200 {array} models.Organization
+// @Router /api/v1/user/organizations [get]
+// @Security BearerAuth
+func (h *Handler) ListMyOrganizations(c *gin.Context) {
+	organizations, err := h.userRepo.ListUserOrganizations(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list organizations"})
+		return
+	}
+	c.JSON(http.StatusOK, organizations)
+}
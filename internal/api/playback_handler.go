@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPlaybackURLTTL bounds the lifetime of a playback URL, whether requested by
+// the client or configured; a leaked link stops working within the hour
+const maxPlaybackURLTTL = time.Hour
+
+// PlaybackURLResponse is a short-lived URL that plays a job's audio without credentials
+type PlaybackURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// @Summary Get a signed audio playback URL
+// @Description Return a short-lived URL for the job's audio that needs no Authorization header, for media elements and share links. Local files are served through /api/v1/media/{id}/audio with an HMAC signature, relative to the server unless PUBLIC_BASE_URL is set; audio stored in S3 gets a presigned S3 URL.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param ttl query int false "Lifetime in seconds (default PLAYBACK_URL_TTL_SECONDS, at most 1 hour)"
+// @Success 200 {object} PlaybackURLResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/audio-url [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetAudioPlaybackURL(c *gin.Context) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status == models.StatusQuarantined {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Audio file is quarantined pending review"})
+		return
+	}
+
+	ttl := time.Duration(h.config.PlaybackURLTTLSeconds) * time.Second
+	if value := c.Query("ttl"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ttl must be a positive number of seconds"})
+			return
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	if ttl > maxPlaybackURLTTL {
+		ttl = maxPlaybackURLTTL
+	}
+	playback, err := h.playbackURL(c.Request.Context(), job, ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create playback URL"})
+		return
+	}
+	c.JSON(http.StatusOK, playback)
+}
+
+// playbackURL returns a URL that plays a job's audio without credentials for ttl
+func (h *Handler) playbackURL(ctx context.Context, job *models.TranscriptionJob, ttl time.Duration) (*PlaybackURLResponse, error) {
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+
+	if job.AudioUri != nil && strings.HasPrefix(*job.AudioUri, "s3://") {
+		presigned, err := h.fileService.PresignURL(ctx, *job.AudioUri, ttl)
+		if err != nil {
+			logger.Error("Failed to presign audio URL", "job_id", job.ID, "error", err)
+			return nil, err
+		}
+		return &PlaybackURLResponse{URL: presigned, ExpiresAt: expiresAt}, nil
+	}
+
+	expires := expiresAt.Unix()
+	signature, err := h.signPlayback(job.ID, expires)
+	if err != nil {
+		logger.Error("Failed to sign playback URL", "job_id", job.ID, "error", err)
+		return nil, err
+	}
+	playbackURL := fmt.Sprintf("%s/api/v1/media/%s/audio?expires=%d&signature=%s",
+		h.config.PublicBaseURL, url.PathEscape(job.ID), expires, signature)
+	return &PlaybackURLResponse{URL: playbackURL, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyPlaybackSignature authorizes a media request by its playback signature in
+// place of credentials
+func (h *Handler) VerifyPlaybackSignature(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Playback URL has expired"})
+		return
+	}
+	expected, err := h.signPlayback(c.Param("id"), expires)
+	if err != nil {
+		logger.Error("Failed to sign playback URL", "job_id", c.Param("id"), "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify playback URL"})
+		return
+	}
+	if !hmac.Equal([]byte(expected), []byte(c.Query("signature"))) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid playback signature"})
+		return
+	}
+	c.Next()
+}
+
+// playbackKeyInfo separates the playback signing key from other keys derived
+// from the JWT secret
+const playbackKeyInfo = "scriberr audio playback v1"
+
+// signPlayback returns the signature authorizing playback of a job's audio until
+// expires. The key is derived from the JWT secret rather than being the secret
+// itself, so a playback signature can never stand in for a token signature.
+func (h *Handler) signPlayback(jobID string, expires int64) (string, error) {
+	key, err := hkdf.Key(sha256.New, []byte(h.config.JWTSecret), nil, playbackKeyInfo, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive playback key: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "audio-playback:%s:%d", jobID, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// requestBaseURL returns the scheme and host clients reach the server at: the
+// configured PUBLIC_BASE_URL, or else the request's own scheme and Host.
+// Forwarded headers are not trusted since any client can set them.
+func (h *Handler) requestBaseURL(c *gin.Context) string {
+	if h.config.PublicBaseURL != "" {
+		return h.config.PublicBaseURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CloneProfileRequest picks the version of a profile to clone and the clone's name
+type CloneProfileRequest struct {
+	// Name defaults to the profile's name followed by "(copy)"
+	Name *string `json:"name,omitempty"`
+	// Version defaults to the profile's current version
+	Version *int `json:"version,omitempty"`
+}
+
+// @Summary List profile versions
+// @Description List the versions of a transcription profile, oldest first. Every change of the profile's name, description or parameters is kept as its next version; jobs record the version they were queued with in profile_version.
+// @Tags profiles
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Success 200 {array} models.ProfileVersion
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/profiles/{id}/versions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListProfileVersions(c *gin.Context) {
+	versions, err := h.profileRepo.ListVersions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Summary Get profile version
+// @Description Get a transcription profile as it was at one of its versions
+// @Tags profiles
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Param version path int true "Version"
+// @Success 200 {object} models.ProfileVersion
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/profiles/{id}/versions/{version} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetProfileVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+	found, err := h.profileRepo.FindVersion(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile version not found"})
+		return
+	}
+	c.JSON(http.StatusOK, found)
+}
+
+// @Summary Clone transcription profile
+// @Description Create a profile from a version of another, by default its current one, so the parameters a job ran with can be reproduced or tweaked without changing the original. The clone starts at version 1 and is not the default.
+// @Tags profiles
+// @Accept json
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Param request body CloneProfileRequest false "Options"
+// @Success 201 {object} models.TranscriptionProfile
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/profiles/{id}/clone [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CloneProfile(c *gin.Context) {
+	var req CloneProfileRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	profile, err := h.profileRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+		return
+	}
+	version := profile.Version
+	if req.Version != nil {
+		version = *req.Version
+	}
+	source, err := h.profileRepo.FindVersion(ctx, profile.ID, version)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile version not found"})
+		return
+	}
+
+	clone := models.TranscriptionProfile{
+		Name:        source.Name + " (copy)",
+		Description: source.Description,
+		Parameters:  source.Parameters,
+	}
+	if req.Name != nil {
+		clone.Name = *req.Name
+	}
+	if clone.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile name is required"})
+		return
+	}
+	if err := h.profileRepo.Create(ctx, &clone); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone profile"})
+		return
+	}
+	c.JSON(http.StatusCreated, clone)
+}
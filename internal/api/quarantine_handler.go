@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List quarantined jobs
+// @Description List jobs whose uploads were flagged by the upload scanner and are held for review
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.TranscriptionJob
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/quarantine [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListQuarantinedJobs(c *gin.Context) {
+	jobs, err := h.jobRepo.ListByStatus(c.Request.Context(), models.StatusQuarantined)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list quarantined jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// quarantinedJob loads the job in the path and checks it is quarantined
+func (h *Handler) quarantinedJob(c *gin.Context) (*models.TranscriptionJob, bool) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return nil, false
+	}
+	if job.Status != models.StatusQuarantined {
+		c.JSON(http.StatusConflict, gin.H{"error": "Job is not quarantined"})
+		return nil, false
+	}
+	return job, true
+}
+
+// @Summary Release a quarantined job
+// @Description Mark a flagged upload as safe after review. The job is queued for processing and is not scanned again.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/quarantine/{id}/release [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ReleaseQuarantinedJob(c *gin.Context) {
+	job, ok := h.quarantinedJob(c)
+	if !ok {
+		return
+	}
+
+	job.ScanStatus = models.ScanReleased
+	job.Status = models.StatusPending
+	job.ErrorMessage = nil
+	job.ErrorCode = ""
+	if err := h.jobRepo.Update(c.Request.Context(), job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release job"})
+		return
+	}
+	if err := h.enqueueJob(c.Request.Context(), job.ID); err != nil {
+		// The job scanner picks up pending jobs, so this is not fatal
+		logger.Warn("Failed to enqueue released job", "job_id", job.ID, "error", err)
+	}
+
+	logger.Info("Quarantined job released", "job_id", job.ID, "signature", job.ScanSignature)
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Delete a quarantined job
+// @Description Delete a flagged job together with its uploaded files
+// @Tags admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/quarantine/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteQuarantinedJob(c *gin.Context) {
+	if _, ok := h.quarantinedJob(c); !ok {
+		return
+	}
+	h.DeleteTranscriptionJob(c)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultQuickTranscribeTimeout bounds synchronous transcriptions when
+// FAST_TRANSCRIPTION_TIMEOUT_SECONDS is unset
+const defaultQuickTranscribeTimeout = 30 * time.Second
+
+// @Summary Transcribe a short clip synchronously
+// @Description Transcribe a clip of up to FAST_TRANSCRIPTION_MAX_SECONDS (default 60) with the small local fast model and return the transcript in the response, for voice memos and commands. Nothing is stored: no job is created and the audio is deleted once transcribed. The transcription is stopped and 504 returned if it takes longer than FAST_TRANSCRIPTION_TIMEOUT_SECONDS (default 30), or the shorter timeout the request gives.
+// @Tags transcription
+// @Accept multipart/form-data
+// @Produce json
+// @Param audio formData file true "Audio clip"
+// @Param parameters formData string false "JSON string of transcription parameters; the language and task are used"
+// @Param profile_name formData string false "Profile whose language and task to use"
+// @Param timeout formData int false "Seconds to wait for the transcript, at most the configured timeout"
+// @Success 200 {object} interfaces.TranscriptResult
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /api/v1/transcribe/quick [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) TranscribeQuick(c *gin.Context) {
+	file, header, err := c.Request.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
+		return
+	}
+	defer file.Close()
+
+	timeout := time.Duration(h.config.FastTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultQuickTranscribeTimeout
+	}
+	if value := c.DefaultPostForm("timeout", c.Query("timeout")); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "timeout must be a positive number of seconds"})
+			return
+		}
+		timeout = min(timeout, time.Duration(seconds)*time.Second)
+	}
+
+	params, ok := h.quickTranscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	start := time.Now()
+	result, err := h.quickTranscription.TranscribeClip(ctx, file, header.Filename, params)
+	switch {
+	case errors.Is(err, transcription.ErrClipTooLong):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, transcription.ErrFastModelNotReady):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logger.Warn("Quick transcription timed out", "filename", header.Filename, "timeout", timeout)
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": fmt.Sprintf("Transcription did not finish within %s", timeout)})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to transcribe clip: %v", err)})
+	default:
+		result.ProcessingTime = time.Since(start)
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaResponse reports a workspace's transcription quota for the month
+type QuotaResponse struct {
+	Month        string    `json:"month"`         // YYYY-MM in UTC
+	LimitMinutes int       `json:"limit_minutes"` // 0: unlimited
+	UsedMinutes  float64   `json:"used_minutes"`
+	ResetsAt     time.Time `json:"resets_at"`
+}
+
+// RequireQuota rejects job submissions with a 429 once the request's workspace
+// has used up its monthly transcription minutes
+func (h *Handler) RequireQuota() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodPost || !middleware.SubmitScopeRoutes[c.Request.Method+" "+middleware.RouteKey(c)] {
+			c.Next()
+			return
+		}
+		if !h.checkQuota(c, h.requestWorkspace(c)) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// checkQuota answers 429 with Retry-After, or 500 if usage cannot be read, and
+// returns false when workspace may not submit more audio this month
+func (h *Handler) checkQuota(c *gin.Context, workspace string) bool {
+	err := service.CheckQuota(c.Request.Context(), h.jobRepo, workspace, h.config.MonthlyQuotaMinutes)
+	if errors.Is(err, service.ErrQuotaExceeded) {
+		quotaExceeded(c)
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check quota"})
+		return false
+	}
+	return true
+}
+
+// quotaExceeded answers 429, telling the client to retry when the quota resets
+func quotaExceeded(c *gin.Context) {
+	now := time.Now()
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(models.QuotaResetsAt(now).Sub(now).Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Monthly transcription quota used up"})
+}
+
+// @Summary Get transcription quota
+// @Description Get how many minutes of audio the caller's workspace has transcribed this month, out of its monthly quota. Submissions are rejected with 429 once the quota is used up, until the next month (UTC).
+// @Tags transcription
+// @Produce json
+// @Success 200 {object} QuotaResponse
+// @Router /api/v1/quota [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetQuota(c *gin.Context) {
+	now := time.Now()
+	month := models.QuotaMonth(now)
+	used, err := h.jobRepo.FindQuotaUsage(c.Request.Context(), h.requestWorkspace(c), month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get quota"})
+		return
+	}
+	c.JSON(http.StatusOK, QuotaResponse{
+		Month:        month,
+		LimitMinutes: max(h.config.MonthlyQuotaMinutes, 0),
+		UsedMinutes:  math.Round(used/60*100) / 100,
+		ResetsAt:     models.QuotaResetsAt(now),
+	})
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RealignSegment is an edited transcript segment to align against the audio
+type RealignSegment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+	Speaker *string `json:"speaker,omitempty"`
+}
+
+// RealignRequest optionally replaces the transcript's segments before alignment
+type RealignRequest struct {
+	Segments []RealignSegment `json:"segments,omitempty"`
+}
+
+// realignSegments validates edited segments and converts them to transcript segments
+func realignSegments(segments []RealignSegment) ([]interfaces.TranscriptSegment, string) {
+	if segments == nil {
+		return nil, ""
+	}
+	if len(segments) == 0 {
+		return nil, "segments must not be empty"
+	}
+	result := make([]interfaces.TranscriptSegment, len(segments))
+	for i, seg := range segments {
+		if seg.Start < 0 || seg.End < seg.Start {
+			return nil, "segment times must be non-negative and end after they start"
+		}
+		if strings.TrimSpace(seg.Text) == "" {
+			return nil, "segment text must not be empty"
+		}
+		result[i] = interfaces.TranscriptSegment{Start: seg.Start, End: seg.End, Text: seg.Text, Speaker: seg.Speaker}
+	}
+	return result, ""
+}
+
+// @Summary Repair transcript timestamps
+// @Description Regenerate the segment and word timestamps of a transcript from its text by forced alignment against the job's audio, e.g. after heavy editing left word timings out of step with the text. Segments may be given to replace the stored ones; each is aligned within its own time window, which should still roughly cover its speech. Segments that cannot be aligned keep their times. Needs the local WhisperX environment.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body RealignRequest false "Edited segments"
+// @Success 200 {object} transcription.RealignResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/realign [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) RealignTranscript(c *gin.Context) {
+	var req RealignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	segments, problem := realignSegments(req.Segments)
+	if problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+	if job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	// Multi-track transcripts are timed against the merged audio
+	audioPath := job.AudioPath
+	if job.IsMultiTrack {
+		audioPath = ""
+		if job.MergedAudioPath != nil {
+			audioPath = *job.MergedAudioPath
+		}
+	}
+	if audioPath != "" {
+		audioPath, err = h.scopedJobPath(job, audioPath)
+	}
+	if audioPath == "" || err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The job's audio is not available"})
+		return
+	}
+	if _, err := os.Stat(audioPath); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The job's audio is no longer on disk"})
+		return
+	}
+
+	result, err := h.unifiedProcessor.RealignTranscript(ctx, job, audioPath, segments)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, result)
+	case finalizedConflict(c, err):
+	case errors.Is(err, transcription.ErrRealignUnsupported), errors.Is(err, transcription.ErrRealignEnvironmentPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, transcription.ErrRealignNoLanguage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Realignment failed", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Realignment failed: " + err.Error()})
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateRecordedAtRequest sets or clears when a recording started
+type UpdateRecordedAtRequest struct {
+	RecordedAt *time.Time `json:"recorded_at"` // RFC 3339 with an offset; null to clear
+}
+
+// SegmentTime is the wall-clock span of a transcript segment
+type SegmentTime struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// recordedAtForm parses the optional recorded_at form field of an upload,
+// answering 400 if it is not an RFC 3339 time
+func recordedAtForm(c *gin.Context) (*time.Time, bool) {
+	value := c.PostForm("recorded_at")
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "recorded_at must be an RFC 3339 time, e.g. 2024-05-01T14:03:22+02:00"})
+		return nil, false
+	}
+	return &t, true
+}
+
+// anchorRecording sets when a new job's recording started: the time given on
+// upload, or else the creation time in the media file's metadata, if any
+func anchorRecording(ctx context.Context, job *models.TranscriptionJob, given *time.Time, mediaPath string) {
+	if given != nil {
+		job.RecordedAt, job.RecordedAtSource = given, models.RecordedAtUser
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	recordedAt, err := audio.ProbeCreationTime(ctx, mediaPath)
+	if err != nil {
+		logger.Debug("Could not read recording time from metadata", "job_id", job.ID, "error", err)
+		return
+	}
+	if recordedAt != nil {
+		job.RecordedAt, job.RecordedAtSource = recordedAt, models.RecordedAtMetadata
+	}
+}
+
+// @Summary Set recording start time
+// @Description Set the wall-clock time the recording started, so exports and the transcript API can show absolute times alongside offsets. Overrides a time read from the file's metadata; null clears it.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body UpdateRecordedAtRequest true "Start time"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/transcription/{id}/recorded-at [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateRecordedAt(c *gin.Context) {
+	var req UpdateRecordedAtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	job.RecordedAt, job.RecordedAtSource = req.RecordedAt, ""
+	if req.RecordedAt != nil {
+		job.RecordedAtSource = models.RecordedAtUser
+	}
+	if err := h.jobRepo.Update(c.Request.Context(), job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update recording time"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// segmentTimes returns the wall-clock span of each segment of a transcript
+// recorded at recordedAt, in location
+func segmentTimes(transcript string, recordedAt time.Time, location *time.Location) []SegmentTime {
+	var parsed struct {
+		Segments []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(transcript), &parsed); err != nil {
+		return nil
+	}
+	start := recordedAt.In(location)
+	times := make([]SegmentTime, len(parsed.Segments))
+	for i, seg := range parsed.Segments {
+		times[i] = SegmentTime{
+			Start: start.Add(time.Duration(seg.Start * float64(time.Second))),
+			End:   start.Add(time.Duration(seg.End * float64(time.Second))),
+		}
+	}
+	return times
+}
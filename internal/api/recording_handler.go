@@ -0,0 +1,404 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxRecordingChunkSize bounds a single MediaRecorder chunk upload
+	maxRecordingChunkSize = 64 << 20
+	// maxRecordingChunks bounds the chunks of a session, about 14 hours of
+	// audio at MediaRecorder's usual one-second timeslice
+	maxRecordingChunks = 50000
+)
+
+// recordingLock serialises chunk appends and finalization of a session; it is
+// kept in recordingLocks only while requests hold or wait for it
+type recordingLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+var (
+	recordingLocksMu sync.Mutex
+	recordingLocks   = map[string]*recordingLock{}
+)
+
+func lockRecordingID(id string) func() {
+	recordingLocksMu.Lock()
+	lock := recordingLocks[id]
+	if lock == nil {
+		lock = &recordingLock{}
+		recordingLocks[id] = lock
+	}
+	lock.refs++
+	recordingLocksMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		recordingLocksMu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(recordingLocks, id)
+		}
+		recordingLocksMu.Unlock()
+	}
+}
+
+// lockRecording loads the session in the path and locks it. The session is
+// looked up before locking, so unknown IDs never take a lock, and loaded again
+// once locked to see the changes of the request that held the lock before.
+func (h *Handler) lockRecording(c *gin.Context) (*models.RecordingSession, func(), bool) {
+	if _, ok := h.recordingSession(c); !ok {
+		return nil, nil, false
+	}
+	unlock := lockRecordingID(c.Param("id"))
+	session, ok := h.recordingSession(c)
+	if !ok {
+		unlock()
+		return nil, nil, false
+	}
+	return session, unlock, true
+}
+
+// StartRecordingRequest starts a browser recording session
+type StartRecordingRequest struct {
+	Title    *string `json:"title,omitempty"`
+	MimeType string  `json:"mime_type" binding:"required"`
+}
+
+// FinalizeRecordingRequest turns a recording into a job. With a profile the job is
+// queued with its parameters; otherwise the user's auto-transcription setting applies.
+type FinalizeRecordingRequest struct {
+	ProfileID *string `json:"profile_id,omitempty"`
+}
+
+// recordingExtension picks the file extension for a MediaRecorder MIME type such
+// as "audio/webm;codecs=opus"
+func recordingExtension(mimeType string) (string, error) {
+	base := strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0])
+	switch base {
+	case "audio/webm", "video/webm":
+		return ".webm", nil
+	case "audio/ogg":
+		return ".ogg", nil
+	case "audio/mp4", "video/mp4":
+		return ".m4a", nil
+	case "audio/wav", "audio/x-wav":
+		return ".wav", nil
+	default:
+		return "", fmt.Errorf("unsupported recording type %q", mimeType)
+	}
+}
+
+// recordingSession loads the session in the path, hiding sessions of other workspaces
+func (h *Handler) recordingSession(c *gin.Context) (*models.RecordingSession, bool) {
+	session, err := h.recordingRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil || session.Workspace != h.requestWorkspace(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recording session not found"})
+		return nil, false
+	}
+	return session, true
+}
+
+// @Summary Start a recording session
+// @Description Start collecting a recording made in the browser. Upload MediaRecorder chunks in order with PUT /api/v1/recordings/{id}/chunks/{index}, then finalize the session into a job.
+// @Tags recordings
+// @Accept json
+// @Produce json
+// @Param request body StartRecordingRequest true "Recording"
+// @Success 201 {object} models.RecordingSession
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/recordings [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) StartRecording(c *gin.Context) {
+	var req StartRecordingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ext, err := recordingExtension(req.MimeType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workspace, uploadDir, err := h.workspaceUploadDir(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+		return
+	}
+	recordingsDir := filepath.Join(uploadDir, "recordings")
+	if err := h.fileService.CreateDirectory(recordingsDir); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create recordings directory"})
+		return
+	}
+
+	id := uuid.New().String()
+	session := models.RecordingSession{
+		ID:        id,
+		Title:     req.Title,
+		MimeType:  req.MimeType,
+		Workspace: workspace,
+		FilePath:  filepath.Join(recordingsDir, id+ext),
+	}
+	if err := h.recordingRepo.Create(c.Request.Context(), &session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create recording session"})
+		return
+	}
+	c.JSON(http.StatusCreated, session)
+}
+
+// @Summary Get a recording session
+// @Description Get a recording session; after a page reload, resume uploading from chunk_count
+// @Tags recordings
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.RecordingSession
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/recordings/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetRecording(c *gin.Context) {
+	session, ok := h.recordingSession(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// @Summary Append a recording chunk
+// @Description Append the raw bytes of a MediaRecorder chunk. Chunks must arrive in order starting at 0; re-sending an already stored chunk is a no-op, so uploads can be retried safely. A recording may not grow past RECORDING_MAX_MB.
+// @Tags recordings
+// @Accept application/octet-stream
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param index path int true "Chunk index"
+// @Success 200 {object} models.RecordingSession
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]interface{}
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/recordings/{id}/chunks/{index} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) AppendRecordingChunk(c *gin.Context) {
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chunk index"})
+		return
+	}
+
+	session, unlock, ok := h.lockRecording(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	if session.JobID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Recording has already been finalized", "job_id": *session.JobID})
+		return
+	}
+	if index < session.ChunkCount {
+		c.JSON(http.StatusOK, session)
+		return
+	}
+	if index > session.ChunkCount {
+		c.JSON(http.StatusConflict, gin.H{"error": "Chunk out of order", "expected_index": session.ChunkCount})
+		return
+	}
+	if session.ChunkCount >= maxRecordingChunks {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Recording has too many chunks"})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRecordingChunkSize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Chunk is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read chunk"})
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Chunk is empty"})
+		return
+	}
+	if limit := int64(h.config.RecordingMaxMB) << 20; limit > 0 && session.Bytes+int64(len(data)) > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Recording exceeds the %d MB limit", h.config.RecordingMaxMB)})
+		return
+	}
+
+	if err := appendRecordingData(session, data); err != nil {
+		logger.Error("Failed to store recording chunk", "session_id", session.ID, "index", index, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store chunk"})
+		return
+	}
+	if _, err := h.recordingRepo.AppendChunk(c.Request.Context(), session.ID, index, int64(len(data))); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record chunk"})
+		return
+	}
+
+	session.ChunkCount++
+	session.Bytes += int64(len(data))
+	c.JSON(http.StatusOK, session)
+}
+
+// appendRecordingData appends a chunk to the session file, first dropping any bytes
+// left by an earlier append that was never recorded
+func appendRecordingData(session *models.RecordingSession, data []byte) error {
+	f, err := os.OpenFile(session.FilePath, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(session.Bytes); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(data, session.Bytes); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// @Summary Finalize a recording
+// @Description Turn the uploaded chunks into a transcription job. Finalizing again returns the same job.
+// @Tags recordings
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param request body FinalizeRecordingRequest false "Options"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/recordings/{id}/finalize [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) FinalizeRecording(c *gin.Context) {
+	var req FinalizeRecordingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	session, unlock, ok := h.lockRecording(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+	ctx := c.Request.Context()
+
+	if session.JobID != nil {
+		job, err := h.jobRepo.FindByID(ctx, *session.JobID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+		return
+	}
+	if session.ChunkCount == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recording has no audio"})
+		return
+	}
+
+	var profile *models.TranscriptionProfile
+	if req.ProfileID != nil && *req.ProfileID != "" {
+		var err error
+		profile, err = h.profileRepo.FindByID(ctx, *req.ProfileID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+			return
+		}
+		// Refuse before the recording is moved, so it can be finalized later
+		if !h.checkQuota(c, session.Workspace) {
+			return
+		}
+	}
+
+	// Move the recording next to regular uploads; the job takes the session's ID
+	audioPath := filepath.Join(filepath.Dir(filepath.Dir(session.FilePath)), filepath.Base(session.FilePath))
+	if err := os.Rename(session.FilePath, audioPath); err != nil {
+		logger.Error("Failed to move recording", "session_id", session.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finalize recording"})
+		return
+	}
+
+	job := models.TranscriptionJob{
+		ID:        session.ID,
+		Title:     session.Title,
+		AudioPath: audioPath,
+		Status:    models.StatusUploaded,
+		Workspace: session.Workspace,
+	}
+	if err := h.jobRepo.Create(ctx, &job); err != nil {
+		os.Rename(audioPath, session.FilePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return
+	}
+
+	session.JobID = &job.ID
+	session.FilePath = audioPath
+	if err := h.recordingRepo.Update(ctx, session); err != nil {
+		logger.Warn("Failed to mark recording as finalized", "session_id", session.ID, "error", err)
+	}
+
+	if profile != nil {
+		h.queueWithProfile(ctx, &job, profile)
+	} else {
+		h.applyAutoTranscription(c, &job)
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Discard a recording session
+// @Description Delete a recording session. The uploaded audio is removed unless the session was finalized into a job.
+// @Tags recordings
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/recordings/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteRecording(c *gin.Context) {
+	session, unlock, ok := h.lockRecording(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	if session.JobID == nil {
+		h.fileService.RemoveFile(session.FilePath)
+	}
+	if err := h.recordingRepo.Delete(c.Request.Context(), session.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete recording session"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recording session deleted"})
+}
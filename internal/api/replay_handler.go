@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReplayJobRequest selects the execution to replay
+type ReplayJobRequest struct {
+	ExecutionID string `json:"execution_id,omitempty"` // defaults to the latest execution
+	KeepSandbox bool   `json:"keep_sandbox,omitempty"` // keep the sandbox directory for inspection
+}
+
+// @Summary Replay job execution
+// @Description Re-run an execution of a job with the parameters, adapters and audio recorded in its snapshot, in a debug sandbox that leaves the job untouched. The replay is recorded as an execution of its own; the response reports whether the audio and adapter environments still match the original, what changed, and whether the output is identical to the job's transcript.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body ReplayJobRequest false "Options"
+// @Success 200 {object} transcription.ReplayResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/replay [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ReplayJob(c *gin.Context) {
+	var req ReplayJobRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	jobID := c.Param("id")
+	result, err := h.unifiedProcessor.ReplayExecution(c.Request.Context(), jobID, req.ExecutionID, req.KeepSandbox)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, result)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job or execution not found"})
+	case finalizedConflict(c, err):
+	case errors.Is(err, transcription.ErrReplayUnsupported), errors.Is(err, transcription.ErrReplayEnvironmentPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Replay failed", "job_id", jobID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Replay failed: " + err.Error()})
+	}
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportScheduleRequest creates or updates a report schedule
+type ReportScheduleRequest struct {
+	Name       string   `json:"name" binding:"required,max=255"`
+	Cron       string   `json:"cron" binding:"required,max=100"`
+	Timezone   string   `json:"timezone,omitempty" binding:"max=64"`
+	Format     string   `json:"format,omitempty"`
+	Keywords   []string `json:"keywords" binding:"max=100,dive,max=200"`
+	EmailTo    []string `json:"email_to" binding:"max=50,dive,max=254"`
+	WebhookURL string   `json:"webhook_url,omitempty"`
+	S3URI      string   `json:"s3_uri,omitempty"`
+	Enabled    *bool    `json:"enabled,omitempty"`
+}
+
+// ReportScheduleResponse is a report schedule
+type ReportScheduleResponse struct {
+	*models.ReportSchedule
+	Keywords []string `json:"keywords"`
+	EmailTo  []string `json:"email_to"`
+}
+
+func newReportScheduleResponse(schedule *models.ReportSchedule) ReportScheduleResponse {
+	return ReportScheduleResponse{ReportSchedule: schedule, Keywords: schedule.KeywordList(), EmailTo: schedule.Recipients()}
+}
+
+// apply copies the request onto a schedule; lists are stored one item per line
+func (req *ReportScheduleRequest) apply(schedule *models.ReportSchedule) {
+	schedule.Name = strings.TrimSpace(req.Name)
+	schedule.Cron = strings.TrimSpace(req.Cron)
+	schedule.Timezone = strings.TrimSpace(req.Timezone)
+	schedule.Format = req.Format
+	schedule.Keywords = joinLines(req.Keywords)
+	schedule.EmailTo = joinLines(req.EmailTo)
+	schedule.WebhookURL = req.WebhookURL
+	schedule.S3URI = req.S3URI
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+}
+
+func joinLines(items []string) string {
+	var lines []string
+	for _, item := range items {
+		if item = strings.Join(strings.Fields(item), " "); item != "" {
+			lines = append(lines, item)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// @Summary List report schedules
+// @Description Get the scheduled reports of the request's workspace
+// @Tags reports
+// @Produce json
+// @Success 200 {array} ReportScheduleResponse
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/reports/schedules [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListReportSchedules(c *gin.Context) {
+	schedules, err := h.reportService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list report schedules"})
+		return
+	}
+	responses := make([]ReportScheduleResponse, len(schedules))
+	for i := range schedules {
+		responses[i] = newReportScheduleResponse(&schedules[i])
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// @Summary Create report schedule
+// @Description Schedule a report on the request's workspace. Whenever the cron expression (five fields, or @daily, @weekly and the like) fires in the given timezone (default UTC), a report covering the time since the previous one is compiled: jobs submitted by status, month-to-date transcribed audio, new transcripts, mentions of the flagged keywords in them, and failed jobs with their errors. It is rendered as Markdown or JSON and delivered to every destination given: emailed to email_to (needs SMTP), POSTed to webhook_url and/or written under s3_uri.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body ReportScheduleRequest true "Report schedule"
+// @Success 201 {object} ReportScheduleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/reports/schedules [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateReportSchedule(c *gin.Context) {
+	var req ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := &models.ReportSchedule{
+		Workspace: h.requestWorkspace(c),
+		Enabled:   true,
+		CreatedBy: h.requestAuthor(c),
+	}
+	req.apply(schedule)
+	if err := h.reportService.Create(c.Request.Context(), schedule); err != nil {
+		if errors.Is(err, service.ErrInvalidReportSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to create report schedule", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report schedule"})
+		return
+	}
+	c.JSON(http.StatusCreated, newReportScheduleResponse(schedule))
+}
+
+// @Summary Get report schedule
+// @Description Get a report schedule by ID
+// @Tags reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} ReportScheduleResponse
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/reports/schedules/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetReportSchedule(c *gin.Context) {
+	schedule, err := h.reportService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, newReportScheduleResponse(schedule))
+}
+
+// @Summary Update report schedule
+// @Description Replace a report schedule's settings; it next runs when its cron expression next fires
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param request body ReportScheduleRequest true "Report schedule"
+// @Success 200 {object} ReportScheduleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/reports/schedules/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateReportSchedule(c *gin.Context) {
+	schedule, err := h.reportService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+	var req ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.apply(schedule)
+	if err := h.reportService.Update(c.Request.Context(), schedule); err != nil {
+		if errors.Is(err, service.ErrInvalidReportSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report schedule"})
+		return
+	}
+	c.JSON(http.StatusOK, newReportScheduleResponse(schedule))
+}
+
+// @Summary Delete report schedule
+// @Description Stop a scheduled report. Reports already delivered are not affected.
+// @Tags reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/reports/schedules/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteReportSchedule(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.reportService.Get(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+	if err := h.reportService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete report schedule"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Report schedule deleted successfully"})
+}
+
+// @Summary Preview report
+// @Description Render the report the schedule would deliver now, in its format, without delivering it or starting a new period
+// @Tags reports
+// @Produce text/markdown
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {string} string "Rendered report"
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/reports/schedules/{id}/preview [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) PreviewReport(c *gin.Context) {
+	schedule, err := h.reportService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+	report, err := h.reportService.Preview(c.Request.Context(), schedule)
+	if err != nil {
+		logger.Error("Failed to compile report", "schedule_id", schedule.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compile report"})
+		return
+	}
+	body, contentType, _, err := service.RenderReport(report, schedule.Format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render report"})
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// @Summary Run report now
+// @Description Compile the schedule's report and deliver it now; the next scheduled report covers the time from here on. Answers 502 with the report if any destination failed, which is also kept in last_error.
+// @Tags reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} service.Report
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]interface{}
+// @Router /api/v1/reports/schedules/{id}/run [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) RunReport(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.reportService.Get(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+	report, err := h.reportService.Run(c.Request.Context(), id)
+	if err != nil {
+		if report == nil {
+			logger.Error("Failed to run report", "schedule_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compile report"})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// componentKinds are the kinds of components executions are stamped with
+var componentKinds = map[string]bool{
+	models.ComponentAdapter:    true,
+	models.ComponentPackage:    true,
+	models.ComponentCheckpoint: true,
+}
+
+// ReprocessRequest selects the jobs of a component version to transcribe again
+type ReprocessRequest struct {
+	Kind     string   `json:"kind" binding:"required"`
+	Name     string   `json:"name" binding:"required"`
+	Versions []string `json:"versions"`
+	// JobIDs limits reprocessing to these of the affected jobs
+	JobIDs []string `json:"job_ids"`
+}
+
+// ReprocessResponse reports which affected jobs were queued again
+type ReprocessResponse struct {
+	Queued  []string          `json:"queued"`
+	Skipped map[string]string `json:"skipped"` // job ID to reason
+}
+
+// componentKind checks a component kind, responding with 400 if it is unknown
+func componentKind(c *gin.Context, kind string) bool {
+	if !componentKinds[kind] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be adapter, package or checkpoint"})
+		return false
+	}
+	return true
+}
+
+// splitVersions splits a comma-separated version list
+func splitVersions(s string) []string {
+	var versions []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// @Summary List component versions
+// @Description List the versions of the adapters, Python packages or model checkpoints that jobs were transcribed with, and how many jobs each produced. Only each job's latest execution counts, so reprocessed jobs move to the version that reprocessed them.
+// @Tags admin
+// @Produce json
+// @Param kind query string true "Component kind (adapter, package, checkpoint)"
+// @Param name query string false "Only this component, e.g. whisperx or torch"
+// @Success 200 {array} models.ComponentVersion
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reproducibility/components [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListComponentVersions(c *gin.Context) {
+	kind := c.Query("kind")
+	if !componentKind(c, kind) {
+		return
+	}
+	versions, err := h.jobRepo.ListComponentVersions(c.Request.Context(), kind, c.Query("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list component versions"})
+		return
+	}
+	if versions == nil {
+		versions = []models.ComponentVersion{}
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Summary List jobs produced by a component version
+// @Description List the jobs whose latest execution ran on a component, on one of the given versions if any, e.g. the jobs a since-fixed adapter or package release transcribed.
+// @Tags admin
+// @Produce json
+// @Param kind query string true "Component kind (adapter, package, checkpoint)"
+// @Param name query string true "Component name, e.g. whisperx or torch"
+// @Param version query string false "Comma-separated versions"
+// @Success 200 {array} models.ComponentJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reproducibility/jobs [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListComponentJobs(c *gin.Context) {
+	kind, name := c.Query("kind"), c.Query("name")
+	if !componentKind(c, kind) {
+		return
+	}
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	jobs, err := h.jobRepo.ListComponentJobs(c.Request.Context(), kind, name, splitVersions(c.Query("version")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	if jobs == nil {
+		jobs = []models.ComponentJob{}
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// @Summary Reprocess jobs produced by a component version
+// @Description Transcribe again, with their own parameters, the completed and failed jobs whose latest execution ran on a component version. Their transcripts and derived results are cleared as for a re-transcription. Finalized jobs and jobs that are queued or processing are skipped.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ReprocessRequest true "Component version and optional jobs"
+// @Success 200 {object} ReprocessResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reproducibility/reprocess [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ReprocessComponentJobs(c *gin.Context) {
+	var req ReprocessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind and name are required"})
+		return
+	}
+	if !componentKind(c, req.Kind) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	affected, err := h.jobRepo.ListComponentJobs(ctx, req.Kind, req.Name, req.Versions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	selected := make(map[string]bool, len(req.JobIDs))
+	for _, id := range req.JobIDs {
+		selected[id] = true
+	}
+
+	resp := ReprocessResponse{Queued: []string{}, Skipped: map[string]string{}}
+	for _, affectedJob := range affected {
+		if len(selected) > 0 && !selected[affectedJob.JobID] {
+			continue
+		}
+		delete(selected, affectedJob.JobID)
+		if reason := h.reprocessJob(ctx, affectedJob.JobID); reason != "" {
+			resp.Skipped[affectedJob.JobID] = reason
+			continue
+		}
+		resp.Queued = append(resp.Queued, affectedJob.JobID)
+	}
+	for id := range selected {
+		resp.Skipped[id] = "not produced by this component version"
+	}
+	logger.Info("Reprocessing jobs by component version", "kind", req.Kind, "name", req.Name,
+		"versions", req.Versions, "queued", len(resp.Queued), "skipped", len(resp.Skipped))
+	c.JSON(http.StatusOK, resp)
+}
+
+// reprocessJob clears a job's results and queues it again with its parameters,
+// returning why it was skipped if it was
+func (h *Handler) reprocessJob(ctx context.Context, jobID string) string {
+	job, err := h.jobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return "job not found"
+	}
+	if job.FinalizedAt != nil {
+		return "job is finalized"
+	}
+	if job.Status != models.StatusCompleted && job.Status != models.StatusFailed && job.Status != models.StatusCancelled {
+		return "job is " + string(job.Status)
+	}
+
+	job.Status = models.StatusPending
+	job.Transcript = nil
+	job.Summary = nil
+	job.ErrorMessage = nil
+	job.ErrorCode = ""
+	job.DuplicateOf = nil
+	job.ActionItems = nil
+	job.SegmentSentiment = nil
+	job.Chapters = nil
+	job.PostProcessedAt = nil
+	job.PostProcessError = nil
+	job.LowConfidenceSegments = 0
+	job.ReviewStatus = ""
+	if err := h.jobRepo.Update(ctx, job); err != nil {
+		if errors.Is(err, repository.ErrJobFinalized) {
+			return "job is finalized"
+		}
+		logger.Error("Failed to update job for reprocessing", "job_id", jobID, "error", err)
+		return "failed to update job"
+	}
+	if err := h.enqueueJob(ctx, jobID); err != nil {
+		logger.Error("Failed to enqueue job", "job_id", jobID, "error", err)
+		return "failed to enqueue job"
+	}
+	return ""
+}
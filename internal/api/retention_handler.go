@@ -0,0 +1,225 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultArchiveStorageClass is used for archive policies that name none
+const defaultArchiveStorageClass = string(types.StorageClassGlacier)
+
+// RetentionPolicyRequest creates or replaces a retention policy
+type RetentionPolicyRequest struct {
+	ProfileID    *string `json:"profile_id,omitempty"`
+	TagID        *string `json:"tag_id,omitempty"`
+	Target       string  `json:"target" binding:"required,oneof=audio job"`
+	Action       string  `json:"action" binding:"required,oneof=delete archive"`
+	AfterDays    int     `json:"after_days" binding:"required,min=1"`
+	ArchiveURI   string  `json:"archive_uri,omitempty"`
+	StorageClass string  `json:"storage_class,omitempty"`
+	Enforced     bool    `json:"enforced"`
+}
+
+// bindRetentionPolicy validates a request into policy, answering 400 if it is invalid
+func (h *Handler) bindRetentionPolicy(c *gin.Context, policy *models.RetentionPolicy) bool {
+	var req RetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return false
+	}
+	if req.ProfileID != nil && *req.ProfileID == "" {
+		req.ProfileID = nil
+	}
+	if req.ProfileID != nil {
+		if _, err := h.profileRepo.FindByID(c.Request.Context(), *req.ProfileID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+			return false
+		}
+	}
+	if req.TagID != nil && *req.TagID == "" {
+		req.TagID = nil
+	}
+	if req.TagID != nil {
+		if _, err := h.tagRepo.FindInWorkspace(c.Request.Context(), h.requestWorkspace(c), *req.TagID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tag not found"})
+			return false
+		}
+	}
+
+	policy.ProfileID = req.ProfileID
+	policy.TagID = req.TagID
+	policy.Target = req.Target
+	policy.Action = req.Action
+	policy.AfterDays = req.AfterDays
+	policy.Enforced = req.Enforced
+	policy.ArchiveURI = ""
+	policy.StorageClass = ""
+	if req.Action == models.RetentionActionArchive {
+		uri := strings.TrimSuffix(strings.TrimSpace(req.ArchiveURI), "/")
+		if bucket, _, _ := strings.Cut(strings.TrimPrefix(uri, "s3://"), "/"); !strings.HasPrefix(uri, "s3://") || bucket == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Archive policies need an archive_uri of the form s3://bucket/prefix"})
+			return false
+		}
+		storageClass := strings.ToUpper(strings.TrimSpace(req.StorageClass))
+		if storageClass == "" {
+			storageClass = defaultArchiveStorageClass
+		}
+		if !slices.Contains(types.StorageClass("").Values(), types.StorageClass(storageClass)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown S3 storage class: " + storageClass})
+			return false
+		}
+		policy.ArchiveURI = uri
+		policy.StorageClass = storageClass
+	}
+	return true
+}
+
+// retentionPolicy loads the policy named in the path, answering 404 if there is none
+func (h *Handler) retentionPolicy(c *gin.Context) (*models.RetentionPolicy, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy ID"})
+		return nil, false
+	}
+	policy, err := h.retentionRepo.FindByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Retention policy not found"})
+		return nil, false
+	}
+	return policy, true
+}
+
+// @Summary List retention policies
+// @Description List the retention policies of the request's organization, or of the workspaces outside every organization
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.RetentionPolicy
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/retention/policies [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListRetentionPolicies(c *gin.Context) {
+	policies, err := h.retentionRepo.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list retention policies"})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// @Summary Create a retention policy
+// @Description Delete or archive the audio or the whole of jobs older than after_days, for every job of the request's organization (or of the workspaces outside every organization), only those transcribed with a profile, or only those with a tag. A tag's policy applies in place of a profile's, which applies in place of the policy for every job. Archive policies copy the files to archive_uri in storage_class (default GLACIER) first. Policies start as a dry run, reported by the preview, until enforced.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body RetentionPolicyRequest true "Policy"
+// @Success 201 {object} models.RetentionPolicy
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/admin/retention/policies [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateRetentionPolicy(c *gin.Context) {
+	policy := &models.RetentionPolicy{
+		OrganizationID: c.GetString("organization_id"),
+		CreatedBy:      h.requestAuthor(c),
+	}
+	if !h.bindRetentionPolicy(c, policy) {
+		return
+	}
+	if err := h.retentionRepo.Create(c.Request.Context(), policy); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create retention policy"})
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// @Summary Update a retention policy
+// @Description Replace a retention policy, e.g. to enforce it once its preview looks right
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Param request body RetentionPolicyRequest true "Policy"
+// @Success 200 {object} models.RetentionPolicy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/retention/policies/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateRetentionPolicy(c *gin.Context) {
+	policy, ok := h.retentionPolicy(c)
+	if !ok {
+		return
+	}
+	if !h.bindRetentionPolicy(c, policy) {
+		return
+	}
+	if err := h.retentionRepo.Update(c.Request.Context(), policy); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update retention policy"})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Delete a retention policy
+// @Tags admin
+// @Param id path int true "Policy ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/retention/policies/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteRetentionPolicy(c *gin.Context) {
+	policy, ok := h.retentionPolicy(c)
+	if !ok {
+		return
+	}
+	if err := h.retentionRepo.Delete(c.Request.Context(), policy.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete retention policy"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Preview retention
+// @Description Dry run: list what every retention policy would delete or archive now, and whether the policy is enforced, without changing anything
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.RetentionReport
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/retention/preview [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) PreviewRetention(c *gin.Context) {
+	report, err := h.retention.Preview(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview retention"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Enforce retention
+// @Description Apply the enforced retention policies now rather than at the next scheduled run
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.RetentionReport
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/retention/enforce [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) EnforceRetention(c *gin.Context) {
+	report, err := h.retention.Enforce(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enforce retention"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
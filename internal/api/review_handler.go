@@ -0,0 +1,306 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// reviewSnippetPadding is the audio, in seconds, a segment's snippet plays
+// before and after it
+const reviewSnippetPadding = 0.5
+
+// reviewQueueLimit bounds a page of the review queue
+const reviewQueueLimit = 100
+
+// ReviewSegment is a low-confidence transcript segment to review
+type ReviewSegment struct {
+	Index      int     `json:"index"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Text       string  `json:"text"`
+	Speaker    *string `json:"speaker,omitempty"`
+	Confidence float64 `json:"confidence"`
+	// Words are the segment's words with their scores
+	Words []interfaces.TranscriptWord `json:"words"`
+	// AudioURL plays the segment, with a little audio around it, without
+	// credentials until AudioURLExpiresAt; absent when the audio is not available
+	AudioURL string `json:"audio_url,omitempty"`
+}
+
+// ReviewResponse lists the low-confidence segments of a job
+type ReviewResponse struct {
+	JobID                 string          `json:"job_id"`
+	ReviewStatus          string          `json:"review_status"`
+	LowConfidenceSegments int             `json:"low_confidence_segments"`
+	Segments              []ReviewSegment `json:"segments"`
+	AudioURLExpiresAt     *time.Time      `json:"audio_url_expires_at,omitempty"`
+}
+
+// ReviewSegmentRequest corrects a low-confidence segment; without text it
+// confirms the segment as transcribed
+type ReviewSegmentRequest struct {
+	Text *string `json:"text,omitempty"`
+}
+
+// reviewTranscript loads the job named in the path with its parsed transcript,
+// answering with an error if it has none
+func (h *Handler) reviewTranscript(c *gin.Context) (*models.TranscriptionJob, *interfaces.TranscriptResult, bool) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			return nil, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return nil, nil, false
+	}
+	if job.Transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript not available"})
+		return nil, nil, false
+	}
+	var transcript interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(*job.Transcript), &transcript); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transcript"})
+		return nil, nil, false
+	}
+	return job, &transcript, true
+}
+
+// segmentWords returns the words timed within a segment
+func segmentWords(transcript *interfaces.TranscriptResult, seg interfaces.TranscriptSegment) []interfaces.TranscriptWord {
+	words := []interfaces.TranscriptWord{}
+	for _, word := range transcript.WordSegments {
+		if word.Start >= seg.Start && word.Start < seg.End {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// @Summary List jobs that need review
+// @Description List the completed jobs with enough low-confidence segments to need review, oldest first, without their transcripts. Segments are flagged when the mean score of their words is below REVIEW_CONFIDENCE_THRESHOLD, and jobs need review from REVIEW_MIN_FLAGGED_SEGMENTS flagged segments.
+// @Tags transcription
+// @Produce json
+// @Param limit query int false "Jobs per page (default and at most 100)"
+// @Param offset query int false "Jobs to skip"
+// @Success 200 {array} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/review-queue [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListReviewQueue(c *gin.Context) {
+	limit, offset := reviewQueueLimit, 0
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = min(n, reviewQueueLimit)
+	}
+	if value := c.Query("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+			return
+		}
+		offset = n
+	}
+	jobs, err := h.jobRepo.ListNeedingReview(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	redacted, err := h.newJobFieldFilter(c).jobs(jobs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, redacted)
+}
+
+// @Summary List low-confidence segments
+// @Description List the segments of a transcript flagged for their low confidence, with their words' scores and a signed URL playing each segment (a media fragment, #t=start,end, of the job's audio) for quick correction. Audio URLs are left out when the job's audio is unavailable or hidden from the requester.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} ReviewResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/review [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetReviewSegments(c *gin.Context) {
+	job, transcript, ok := h.reviewTranscript(c)
+	if !ok {
+		return
+	}
+	resp := ReviewResponse{
+		JobID:                 job.ID,
+		ReviewStatus:          job.ReviewStatus,
+		LowConfidenceSegments: job.LowConfidenceSegments,
+		Segments:              []ReviewSegment{},
+	}
+
+	var audioURL string
+	hidden, err := h.hiddenJobFields(c, job.Workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check field permissions"})
+		return
+	}
+	if !hidden[models.JobFieldAudio] && job.AudioExpiredAt == nil && job.Status != models.StatusQuarantined && !job.IsMultiTrack {
+		ttl := min(time.Duration(h.config.PlaybackURLTTLSeconds)*time.Second, maxPlaybackURLTTL)
+		if playback, err := h.playbackURL(c.Request.Context(), job, ttl); err == nil {
+			audioURL = playback.URL
+			resp.AudioURLExpiresAt = &playback.ExpiresAt
+		}
+	}
+
+	for i, seg := range transcript.Segments {
+		if !seg.LowConfidence {
+			continue
+		}
+		segment := ReviewSegment{
+			Index: i, Start: seg.Start, End: seg.End, Text: seg.Text, Speaker: seg.Speaker,
+			Words: segmentWords(transcript, seg),
+		}
+		if seg.Confidence != nil {
+			segment.Confidence = *seg.Confidence
+		}
+		if audioURL != "" {
+			segment.AudioURL = fmt.Sprintf("%s#t=%.2f,%.2f", audioURL, max(seg.Start-reviewSnippetPadding, 0), seg.End+reviewSnippetPadding)
+		}
+		resp.Segments = append(resp.Segments, segment)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Review a low-confidence segment
+// @Description Correct the text of a segment flagged for low confidence, or confirm it by sending no text, which clears its flag. The transcript's full text is corrected along with it; its word timings are kept, and can be regenerated with the realign endpoint. Once no flagged segment is left, a job that needed review is marked reviewed.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param index path int true "Segment index"
+// @Param request body ReviewSegmentRequest false "Corrected text"
+// @Success 200 {object} interfaces.TranscriptSegment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/review/segments/{index} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ReviewSegment(c *gin.Context) {
+	var req ReviewSegmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			return
+		}
+	}
+	if req.Text != nil && strings.TrimSpace(*req.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text must not be empty"})
+		return
+	}
+	job, transcript, ok := h.reviewTranscript(c)
+	if !ok {
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 || index >= len(transcript.Segments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+		return
+	}
+
+	seg := &transcript.Segments[index]
+	if req.Text != nil && *req.Text != seg.Text {
+		// Segments of some adapters start with a space the full text does not repeat
+		before, after := strings.TrimSpace(seg.Text), strings.TrimSpace(*req.Text)
+		transcript.Text = strings.Replace(transcript.Text, before, after, 1)
+		seg.Text = *req.Text
+	}
+	seg.LowConfidence = false
+
+	encoded, err := json.Marshal(transcript)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode transcript"})
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.jobRepo.UpdateTranscript(ctx, job.ID, string(encoded)); err != nil {
+		if !finalizedConflict(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save transcript"})
+		}
+		return
+	}
+
+	job.LowConfidenceSegments = 0
+	for _, s := range transcript.Segments {
+		if s.LowConfidence {
+			job.LowConfidenceSegments++
+		}
+	}
+	if job.ReviewStatus == models.ReviewNeeded && job.LowConfidenceSegments == 0 {
+		h.completeReview(c, job)
+	}
+	if err := h.jobRepo.UpdateReview(ctx, job); err != nil {
+		logger.Error("Failed to update review", "job_id", job.ID, "error", err)
+	}
+	c.JSON(http.StatusOK, seg)
+}
+
+// @Summary Mark a transcript reviewed
+// @Description Take a job out of the review queue, whether or not flagged segments are left.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/review [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CompleteReview(c *gin.Context) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+		return
+	}
+	h.completeReview(c, job)
+	if err := h.jobRepo.UpdateReview(c.Request.Context(), job); err != nil {
+		if !finalizedConflict(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review"})
+		}
+		return
+	}
+	redacted, err := h.newJobFieldFilter(c).job(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+	c.JSON(http.StatusOK, redacted)
+}
+
+// completeReview marks a job reviewed by the request's user
+func (h *Handler) completeReview(c *gin.Context, job *models.TranscriptionJob) {
+	now := time.Now()
+	reviewer := h.requestAuthor(c)
+	job.ReviewStatus = models.ReviewComplete
+	job.ReviewedAt = &now
+	job.ReviewedBy = &reviewer
+}
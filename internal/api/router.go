@@ -2,8 +2,11 @@ package api
 
 import (
 	"scriberr/internal/auth"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
 	"scriberr/internal/web"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/metrics"
 	"scriberr/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -20,9 +23,19 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 	// Create Gin router without default middleware
 	router := gin.New()
 
+	// Only trust X-Forwarded-For from configured proxies, so the client IP the
+	// rate limiter and audit log see cannot be spoofed
+	if err := router.SetTrustedProxies(handler.config.TrustedProxies); err != nil {
+		logger.Warn("Invalid TRUSTED_PROXIES, trusting no proxies", "error", err)
+		_ = router.SetTrustedProxies(nil)
+	}
+
 	// Add recovery middleware
 	router.Use(gin.Recovery())
 
+	// Trace requests, continuing the caller's trace from its traceparent header
+	router.Use(middleware.TracingMiddleware())
+
 	// Add custom logger middleware
 	router.Use(logger.GinLogger())
 
@@ -34,6 +47,7 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
+		c.Header("Access-Control-Expose-Headers", "API-Version, Deprecation, Sunset, Link")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -46,6 +60,9 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 	// Health check endpoint (no auth required)
 	router.GET("/health", handler.HealthCheck)
 
+	// Prometheus metrics (no auth required)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -53,191 +70,504 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 	router.GET("/install.sh", handler.GetInstallScript)
 	router.GET("/install-cli.sh", handler.GetInstallScript)
 
-	// API v1 routes
+	// API routes, rate limited per client IP; API keys are also limited per key.
+	// Successful changes are recorded in the audit log. v2 serves the same
+	// routes with structured error bodies; v1 announces its deprecation and
+	// sunset once they are configured.
+	middleware.SetDefaultAPIKeyRateLimit(handler.config.RateLimitPerAPIKey)
+	router.GET("/api/versions", handler.ListAPIVersions)
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.APIVersion("1"))
+	if deprecation, ok := handler.v1Deprecation(); ok {
+		v1.Use(middleware.Deprecate(deprecation))
+	}
+	v1.Use(middleware.RateLimitMiddleware(handler.config), handler.AuditMutations())
+	registerAPIRoutes(v1, handler, authService)
+
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIVersion("2"), structuredErrors(), middleware.RateLimitMiddleware(handler.config), handler.AuditMutations())
+	registerAPIRoutes(v2, handler, authService)
+
+	// Set up static file serving for React app
+	web.SetupStaticRoutes(router, authService)
+
+	return router
+}
+
+// registerAPIRoutes registers the routes of the API on the group of a version
+func registerAPIRoutes(api *gin.RouterGroup, handler *Handler, authService *auth.AuthService) {
+	// Authentication routes (no auth required)
+	auth := api.Group("/auth")
 	{
-		// Authentication routes (no auth required)
-		auth := v1.Group("/auth")
+		auth.GET("/registration-status", handler.GetRegistrationStatus)
+		auth.POST("/register", handler.Register)
+		auth.POST("/login", handler.Login)
+		auth.POST("/refresh", handler.Refresh)
+		auth.POST("/logout", handler.Logout)
+
+		// Account management routes (require authentication)
+		authProtected := auth.Group("")
+		// Account management must require JWT (API keys do not represent a user)
+		authProtected.Use(middleware.JWTOnlyMiddleware(authService))
 		{
-			auth.GET("/registration-status", handler.GetRegistrationStatus)
-			auth.POST("/register", handler.Register)
-			auth.POST("/login", handler.Login)
-			auth.POST("/refresh", handler.Refresh)
-			auth.POST("/logout", handler.Logout)
-
-			// Account management routes (require authentication)
-			authProtected := auth.Group("")
-			// Account management must require JWT (API keys do not represent a user)
-			authProtected.Use(middleware.JWTOnlyMiddleware(authService))
+			authProtected.POST("/change-password", handler.ChangePassword)
+			authProtected.POST("/change-username", handler.ChangeUsername)
+
+			// CLI Authentication routes
+			cliAuth := authProtected.Group("/cli")
 			{
-				authProtected.POST("/change-password", handler.ChangePassword)
-				authProtected.POST("/change-username", handler.ChangeUsername)
-
-				// CLI Authentication routes
-				cliAuth := authProtected.Group("/cli")
-				{
-					cliAuth.GET("/authorize", handler.AuthorizeCLI)
-					cliAuth.POST("/authorize", handler.ConfirmCLIAuthorization)
-				}
+				cliAuth.GET("/authorize", handler.AuthorizeCLI)
+				cliAuth.POST("/authorize", handler.ConfirmCLIAuthorization)
 			}
 		}
+	}
 
-		// Public CLI routes (no auth required to download, script handles auth)
-		cliPublic := v1.Group("/cli")
-		{
-			cliPublic.GET("/download", handler.DownloadCLIBinary)
-			cliPublic.GET("/install", handler.GetInstallScript)
-		}
-		// API Key management routes (require authentication)
-		apiKeys := v1.Group("/api-keys")
-		// API key management restricted to JWT-authenticated users
-		apiKeys.Use(middleware.JWTOnlyMiddleware(authService))
-		{
-			apiKeys.GET("/", handler.ListAPIKeys)
-			apiKeys.POST("/", handler.CreateAPIKey)
-			apiKeys.DELETE("/:id", handler.DeleteAPIKey)
-		}
+	// Public CLI routes (no auth required to download, script handles auth)
+	cliPublic := api.Group("/cli")
+	{
+		cliPublic.GET("/download", handler.DownloadCLIBinary)
+		cliPublic.GET("/install", handler.GetInstallScript)
+	}
 
-		// Transcription routes (require authentication)
-		transcription := v1.Group("/transcription")
-		transcription.Use(middleware.AuthMiddleware(authService))
-		{
-			// File upload routes - disable compression for these
-			uploadRoutes := transcription.Group("")
-			uploadRoutes.Use(middleware.NoCompressionMiddleware())
-			{
-				uploadRoutes.POST("/upload", handler.UploadAudio)
-				uploadRoutes.POST("/upload-video", handler.UploadVideo)
-				uploadRoutes.POST("/upload-multitrack", handler.UploadMultiTrack)
-				uploadRoutes.GET("/:id/audio", handler.GetAudioFileWrapper(handler.GetAudioFile)) // Audio streaming shouldn't be compressed
-			}
+	// Public custody key, so signed manifests can be verified offline
+	api.GET("/custody/public-key", handler.GetCustodyPublicKey)
 
-			// Regular API routes with compression
-			transcription.POST("/youtube", handler.DownloadFromYouTube)
-			transcription.POST("/submit", handler.SubmitJob)
-			transcription.POST("/:id/start", handler.StartTranscription)
-			transcription.POST("/:id/kill", handler.KillJob)
-			transcription.GET("/:id/logs", handler.GetJobLogs)
-			transcription.GET("/:id/status", handler.GetJobStatus)
-			transcription.GET("/:id/transcript", handler.GetTranscript)
-			transcription.GET("/:id/execution", handler.GetJobExecutionData)
-			transcription.GET("/:id/merge-status", handler.GetMergeStatus)
-			transcription.GET("/:id/track-progress", handler.GetTrackProgress)
-			transcription.PUT("/:id/title", handler.UpdateTranscriptionTitle)
-			transcription.GET("/:id/summary", handler.GetSummaryForTranscription)
-			transcription.GET("/:id", handler.GetTranscriptionJob)
-			transcription.DELETE("/:id", handler.DeleteTranscriptionJob)
-			transcription.GET("/list", handler.ListTranscriptionJobs)
-			transcription.GET("/models", handler.GetSupportedModels)
-			// Notes for a transcription
-			transcription.GET("/:id/notes", handler.ListNotes)
-			transcription.POST("/:id/notes", handler.CreateNote)
-
-			// Speaker mappings for a transcription
-			transcription.GET("/:id/speakers", handler.GetSpeakerMappings)
-			transcription.POST("/:id/speakers", handler.UpdateSpeakerMappings)
-
-			// Quick transcription endpoints
-			transcription.POST("/quick", handler.SubmitQuickTranscription)
-			transcription.GET("/quick/:id", handler.GetQuickTranscriptionStatus)
-
-			// AWS transcribe compatible endpoint
-			transcription.POST("/aws-transcribe", handler.SubmitAWSTranscribeJob)
-		}
+	// API Key management routes (require an admin)
+	apiKeys := api.Group("/api-keys")
+	// API key management restricted to JWT-authenticated users
+	apiKeys.Use(middleware.JWTOnlyMiddleware(authService), middleware.RequireRole(models.RoleAdmin))
+	{
+		apiKeys.GET("/", handler.ListAPIKeys)
+		apiKeys.POST("/", handler.CreateAPIKey)
+		apiKeys.DELETE("/:id", handler.DeleteAPIKey)
+	}
 
-		// Profile routes (require authentication)
-		profiles := v1.Group("/profiles")
-		profiles.Use(middleware.AuthMiddleware(authService))
-		{
-			profiles.GET("/", handler.ListProfiles)
-			profiles.POST("/", handler.CreateProfile)
-			profiles.GET("/:id", handler.GetProfile)
-			profiles.PUT("/:id", handler.UpdateProfile)
-			profiles.DELETE("/:id", handler.DeleteProfile)
-			profiles.POST("/:id/set-default", handler.SetDefaultProfile)
-		}
+	// User management routes (require an admin)
+	users := api.Group("/users")
+	users.Use(middleware.JWTOnlyMiddleware(authService), middleware.RequireRole(models.RoleAdmin))
+	{
+		users.GET("", handler.ListUsers)
+		users.POST("", handler.CreateUser)
+		users.PUT("/:id", handler.UpdateUser)
+		users.DELETE("/:id", handler.DeleteUser)
+	}
 
-		// User routes (require authentication)
-		user := v1.Group("/user")
-		user.Use(middleware.JWTOnlyMiddleware(authService))
-		{
-			user.GET("/default-profile", handler.GetUserDefaultProfile)
-			user.POST("/default-profile", handler.SetUserDefaultProfile)
-			user.GET("/settings", handler.GetUserSettings)
-			user.PUT("/settings", handler.UpdateUserSettings)
-		}
+	// Browser recording sessions (require authentication)
+	recordings := api.Group("/recordings")
+	recordings.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		recordings.POST("", handler.StartRecording)
+		recordings.GET("/:id", handler.GetRecording)
+		recordings.PUT("/:id/chunks/:index", middleware.NoCompressionMiddleware(), handler.AppendRecordingChunk)
+		recordings.POST("/:id/finalize", handler.FinalizeRecording)
+		recordings.DELETE("/:id", handler.DeleteRecording)
+	}
 
-		// Admin routes (require authentication)
-		admin := v1.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(authService))
-		{
-			queue := admin.Group("/queue")
-			{
-				queue.GET("/stats", handler.GetQueueStats)
-			}
-		}
+	// Signed playback URLs authorize themselves, so media elements and share
+	// links need no credentials
+	media := api.Group("/media")
+	media.Use(middleware.NoCompressionMiddleware())
+	{
+		media.GET("/:id/audio", handler.VerifyPlaybackSignature, handler.GetAudioFileWrapper(handler.GetAudioFile))
+	}
 
-		// LLM configuration routes (require authentication)
-		llm := v1.Group("/llm")
-		llm.Use(middleware.AuthMiddleware(authService))
+	// Transcription routes (require authentication)
+	requireTranscript := handler.RequireJobField("id", models.JobFieldTranscript)
+	transcription := api.Group("/transcription")
+	hideCollections := handler.HideRestrictedCollections()
+	transcription.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor), handler.RequireJobAccess("id"), handler.RequireUnfinalized(), handler.RequireQuota())
+	{
+		// File upload routes - disable compression for these
+		uploadRoutes := transcription.Group("")
+		uploadRoutes.Use(middleware.NoCompressionMiddleware())
 		{
-			llm.GET("/config", handler.GetLLMConfig)
-			llm.POST("/config", handler.SaveLLMConfig)
+			uploadRoutes.POST("/upload", handler.UploadAudio)
+			uploadRoutes.POST("/upload-video", handler.UploadVideo)
+			uploadRoutes.POST("/upload-multitrack", handler.UploadMultiTrack)
+			uploadRoutes.POST("/multitrack", handler.CreateMultiTrackJob)
+			uploadRoutes.GET("/:id/audio", handler.RequireJobField("id", models.JobFieldAudio), handler.GetAudioFileWrapper(handler.GetAudioFile)) // Audio streaming shouldn't be compressed
+			uploadRoutes.GET("/:id/audio-url", handler.RequireJobField("id", models.JobFieldAudio), handler.GetAudioPlaybackURL)
+			uploadRoutes.GET("/:id/video", handler.RequireJobField("id", models.JobFieldAudio), handler.GetVideo)
 		}
 
-		// Summarization templates routes (require authentication)
-		summaries := v1.Group("/summaries")
-		summaries.Use(middleware.AuthMiddleware(authService))
-		{
-			summaries.GET("/", handler.ListSummaryTemplates)
-			summaries.POST("/", handler.CreateSummaryTemplate)
-			summaries.GET("/:id", handler.GetSummaryTemplate)
-			summaries.PUT("/:id", handler.UpdateSummaryTemplate)
-			summaries.DELETE("/:id", handler.DeleteSummaryTemplate)
-			summaries.GET("/settings", handler.GetSummarySettings)
-			summaries.POST("/settings", handler.SaveSummarySettings)
-		}
+		// Regular API routes with compression
+		transcription.POST("/youtube", handler.DownloadFromYouTube)
+		transcription.POST("/url", handler.SubmitURLJob)
+		transcription.POST("/submit", handler.SubmitJob)
+		transcription.POST("/:id/start", handler.StartTranscription)
+		transcription.POST("/:id/kill", handler.KillJob)
+		transcription.POST("/:id/cancel", handler.CancelJob)
+		transcription.GET("/:id/logs", handler.GetJobLogs)
+		transcription.GET("/:id/status", handler.GetJobStatus)
+		transcription.GET("/:id/transcript", requireTranscript, handler.GetTranscript)
+		transcription.POST("/:id/sentiment", requireTranscript, handler.AnalyzeTranscriptSentiment)
+		transcription.POST("/:id/chapters", requireTranscript, handler.GenerateTranscriptChapters)
+		transcription.POST("/:id/translation", requireTranscript, handler.TranslateTranscript)
+		transcription.GET("/:id/export", requireTranscript, readReplica, handler.ExportTranscript)
+		transcription.GET("/:id/flashcards", requireTranscript, readReplica, handler.ExportFlashcards)
+		transcription.GET("/:id/bundle", requireTranscript, handler.RequireJobField("id", models.JobFieldAudio), readReplica, handler.ExportBundle)
+		transcription.GET("/bundle", readReplica, handler.ExportBundles)
+		transcription.GET("/:id/revisions", requireTranscript, handler.ListTranscriptRevisions)
+		transcription.GET("/:id/diff", requireTranscript, readReplica, handler.DiffTranscripts)
+		transcription.GET("/:id/execution", handler.GetJobExecutionData)
+		transcription.POST("/:id/replay", handler.ReplayJob)
+		transcription.POST("/:id/realign", requireTranscript, handler.RequireJobField("id", models.JobFieldAudio), handler.RealignTranscript)
+		transcription.POST("/:id/finalize", handler.FinalizeTranscript)
+		transcription.GET("/review-queue", hideCollections, readReplica, handler.ListReviewQueue)
+		transcription.GET("/:id/review", requireTranscript, handler.GetReviewSegments)
+		transcription.POST("/:id/review", handler.CompleteReview)
+		transcription.PUT("/:id/review/segments/:index", requireTranscript, handler.ReviewSegment)
+		transcription.GET("/:id/custody", handler.GetCustodyManifest)
+		transcription.POST("/custody/verify", handler.VerifyCustodyManifest)
+		transcription.DELETE("/:id/finalize", middleware.RequireRole(models.RoleAdmin), handler.UnfinalizeTranscript)
+		transcription.GET("/:id/merge-status", handler.GetMergeStatus)
+		transcription.GET("/:id/track-progress", handler.GetTrackProgress)
+		transcription.PUT("/:id/title", handler.UpdateTranscriptionTitle)
+		transcription.PUT("/:id/recorded-at", handler.UpdateRecordedAt)
+		transcription.GET("/:id/summary", handler.RequireJobField("id", models.JobFieldSummary), handler.GetSummaryForTranscription)
+		transcription.GET("/:id", handler.GetTranscriptionJob)
+		transcription.DELETE("/:id", handler.DeleteTranscriptionJob)
+		transcription.GET("/list", hideCollections, readReplica, handler.ListTranscriptionJobs)
+		transcription.GET("/models", handler.GetSupportedModels)
+		// Notes for a transcription
+		transcription.GET("/:id/notes", handler.ListNotes)
+		transcription.POST("/:id/notes", handler.CreateNote)
+
+		// Time-coded comment threads
+		transcription.GET("/:id/annotations", handler.ListAnnotations)
+		transcription.POST("/:id/annotations", handler.CreateAnnotation)
+		transcription.PUT("/:id/annotations/:annotation_id", handler.UpdateAnnotation)
+		transcription.DELETE("/:id/annotations/:annotation_id", handler.DeleteAnnotation)
+
+		// Speaker mappings for a transcription
+		transcription.GET("/:id/speakers", handler.GetSpeakerMappings)
+		transcription.POST("/:id/speakers", handler.UpdateSpeakerMappings)
+		transcription.POST("/:id/speakers/identify", handler.IdentifySpeakers)
+		transcription.GET("/:id/speakers/attributes", handler.GetSpeakerAttributes)
+
+		// Tags of a transcription
+		transcription.GET("/:id/tags", handler.ListJobTags)
+		transcription.POST("/:id/tags", handler.AssignJobTag)
+		transcription.DELETE("/:id/tags/:tag_id", handler.UnassignJobTag)
+
+		// Quick transcription endpoints
+		transcription.POST("/quick", handler.SubmitQuickTranscription)
+		transcription.GET("/quick/:id", handler.GetQuickTranscriptionStatus)
+
+		// AWS transcribe compatible endpoint
+		transcription.POST("/aws-transcribe", handler.SubmitAWSTranscribeJob)
+		transcription.GET("/aws-transcribe", hideCollections, handler.ListAWSTranscribeJobs)
+		transcription.GET("/aws-transcribe/:name", hideCollections, handler.GetAWSTranscribeJob)
+		transcription.DELETE("/aws-transcribe/:name", hideCollections, handler.DeleteAWSTranscribeJob)
+	}
 
-		// Chat routes (require authentication)
-		chat := v1.Group("/chat")
-		chat.Use(middleware.AuthMiddleware(authService))
-		{
-			chat.GET("/models", handler.GetChatModels)
-			chat.POST("/sessions", handler.CreateChatSession)
-			chat.GET("/transcriptions/:transcription_id/sessions", handler.GetChatSessions)
-			chat.GET("/sessions/:session_id", handler.GetChatSession)
-			chat.POST("/sessions/:session_id/messages", handler.SendChatMessage)
-			chat.PUT("/sessions/:session_id/title", handler.UpdateChatSessionTitle)
-			chat.POST("/sessions/:session_id/title/auto", handler.AutoGenerateChatTitle)
-			chat.DELETE("/sessions/:session_id", handler.DeleteChatSession)
-		}
+	// Synchronous transcription of short clips (require authentication)
+	transcribe := api.Group("/transcribe")
+	transcribe.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor), handler.RequireQuota())
+	{
+		transcribe.POST("/quick", handler.TranscribeQuick)
+	}
 
-		// Notes routes (require authentication)
-		notes := v1.Group("/notes")
-		notes.Use(middleware.AuthMiddleware(authService))
-		{
-			notes.GET("/:note_id", handler.GetNote)
-			notes.PUT("/:note_id", handler.UpdateNote)
-			notes.DELETE("/:note_id", handler.DeleteNote)
-		}
+	// Monthly transcription quota (require authentication)
+	api.GET("/quota", middleware.AuthMiddleware(authService), handler.GetQuota)
+
+	// Estimated job costs (require authentication)
+	api.GET("/costs", middleware.AuthMiddleware(authService), readReplica, handler.GetCosts)
+
+	// Workspace privacy settings (require authentication; admins change them)
+	api.GET("/workspace/settings", middleware.AuthMiddleware(authService), handler.GetWorkspaceSettings)
+	api.PUT("/workspace/settings", middleware.AuthMiddleware(authService), middleware.RequireRole(models.RoleAdmin), handler.UpdateWorkspaceSettings)
+	api.GET("/workspace/field-permissions", middleware.AuthMiddleware(authService), handler.ListFieldPermissions)
+	api.PUT("/workspace/field-permissions/:role", middleware.AuthMiddleware(authService), middleware.RequireRole(models.RoleAdmin), handler.UpdateFieldPermission)
+
+	// Full-text search (require authentication)
+	search := api.Group("/search")
+	search.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		search.GET("", hideCollections, readReplica, handler.Search)
+	}
+
+	// Speaker directory routes (require authentication)
+	speakers := api.Group("/speakers")
+	speakers.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		speakers.GET("", handler.ListSpeakers)
+		speakers.POST("", handler.CreateSpeaker)
+		speakers.GET("/:id", handler.GetSpeaker)
+		speakers.PUT("/:id", handler.UpdateSpeaker)
+		speakers.DELETE("/:id", handler.DeleteSpeaker)
+	}
 
-		// Summarization route (require authentication)
-		summarize := v1.Group("/summarize")
-		summarize.Use(middleware.AuthMiddleware(authService))
+	// Tag routes (require authentication)
+	tags := api.Group("/tags")
+	tags.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		tags.GET("", handler.ListTags)
+		tags.POST("", handler.CreateTag)
+		tags.PUT("/:id", handler.UpdateTag)
+		tags.DELETE("/:id", handler.DeleteTag)
+	}
+
+	// Collection routes (require authentication)
+	collections := api.Group("/collections")
+	collections.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		collections.GET("", handler.ListCollections)
+		collections.POST("", handler.CreateCollection)
+		collections.GET("/:id", handler.GetCollection)
+		collections.PUT("/:id", handler.UpdateCollection)
+		collections.DELETE("/:id", handler.DeleteCollection)
+		collections.POST("/:id/jobs", handler.AddCollectionJobs)
+		collections.DELETE("/:id/jobs/:job_id", handler.RemoveCollectionJob)
+		collections.GET("/:id/export", readReplica, handler.ExportCollection)
+		collections.GET("/:id/permissions", handler.GetCollectionPermissions)
+		collections.PUT("/:id/permissions", middleware.RequireRole(models.RoleAdmin), handler.UpdateCollectionPermissions)
+	}
+
+	// Voiceprint routes (require authentication)
+	voiceprints := api.Group("/voiceprints")
+	voiceprints.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		voiceprints.GET("", handler.ListVoiceprints)
+		voiceprints.POST("", handler.EnrollVoiceprint)
+		voiceprints.DELETE("/:id", handler.DeleteVoiceprint)
+	}
+
+	// RSS/podcast feed subscription routes (require authentication)
+	feeds := api.Group("/feeds")
+	feeds.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		feeds.GET("/", handler.ListFeedSubscriptions)
+		feeds.POST("/", handler.CreateFeedSubscription)
+		feeds.GET("/:id", handler.GetFeedSubscription)
+		feeds.PUT("/:id", handler.UpdateFeedSubscription)
+		feeds.DELETE("/:id", handler.DeleteFeedSubscription)
+		feeds.GET("/:id/episodes", handler.ListFeedEpisodes)
+		feeds.POST("/:id/poll", handler.PollFeedSubscription)
+	}
+
+	// Scheduled reports
+	reports := api.Group("/reports")
+	reports.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		reports.GET("/schedules", handler.ListReportSchedules)
+		reports.POST("/schedules", handler.CreateReportSchedule)
+		reports.GET("/schedules/:id", handler.GetReportSchedule)
+		reports.PUT("/schedules/:id", handler.UpdateReportSchedule)
+		reports.DELETE("/schedules/:id", handler.DeleteReportSchedule)
+		reports.GET("/schedules/:id/preview", handler.PreviewReport)
+		reports.POST("/schedules/:id/run", handler.RunReport)
+	}
+
+	// Find-and-replace correction rules (require authentication; admins manage them)
+	correctionRules := api.Group("/correction-rules")
+	correctionRules.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleAdmin))
+	{
+		correctionRules.GET("", handler.ListCorrectionRules)
+		correctionRules.POST("", handler.CreateCorrectionRule)
+		correctionRules.POST("/preview", handler.PreviewCorrectionRules)
+		correctionRules.POST("/apply", handler.ApplyCorrectionRules)
+		correctionRules.GET("/:id", handler.GetCorrectionRule)
+		correctionRules.PUT("/:id", handler.UpdateCorrectionRule)
+		correctionRules.DELETE("/:id", handler.DeleteCorrectionRule)
+	}
+
+	// Custom vocabularies and vocabulary filters, named after AWS Transcribe's (require authentication)
+	vocabularies := api.Group("/vocabularies")
+	vocabularies.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		vocabularies.GET("", handler.ListVocabularies)
+		vocabularies.POST("", handler.CreateVocabulary)
+		vocabularies.GET("/:name", handler.GetVocabulary)
+		vocabularies.PUT("/:name", handler.UpdateVocabulary)
+		vocabularies.DELETE("/:name", handler.DeleteVocabulary)
+	}
+	vocabularyFilters := api.Group("/vocabulary-filters")
+	vocabularyFilters.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		vocabularyFilters.GET("", handler.ListVocabularyFilters)
+		vocabularyFilters.POST("", handler.CreateVocabularyFilter)
+		vocabularyFilters.GET("/:name", handler.GetVocabularyFilter)
+		vocabularyFilters.PUT("/:name", handler.UpdateVocabularyFilter)
+		vocabularyFilters.DELETE("/:name", handler.DeleteVocabularyFilter)
+	}
+
+	// Model routes (require authentication)
+	modelRoutes := api.Group("/models")
+	modelRoutes.Use(middleware.AuthMiddleware(authService))
+	{
+		modelRoutes.GET("/:id/schema", handler.GetModelSchema)
+	}
+
+	// Profile routes (require authentication)
+	profiles := api.Group("/profiles")
+	profiles.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		profiles.GET("/", handler.ListProfiles)
+		profiles.POST("/", handler.CreateProfile)
+		profiles.GET("/:id", handler.GetProfile)
+		profiles.PUT("/:id", handler.UpdateProfile)
+		profiles.DELETE("/:id", handler.DeleteProfile)
+		profiles.POST("/:id/set-default", handler.SetDefaultProfile)
+		profiles.POST("/:id/clone", handler.CloneProfile)
+		profiles.GET("/:id/versions", handler.ListProfileVersions)
+		profiles.GET("/:id/versions/:version", handler.GetProfileVersion)
+	}
+
+	// User routes (require authentication)
+	user := api.Group("/user")
+	user.Use(middleware.JWTOnlyMiddleware(authService))
+	{
+		user.GET("/default-profile", handler.GetUserDefaultProfile)
+		user.POST("/default-profile", handler.SetUserDefaultProfile)
+		user.GET("/settings", handler.GetUserSettings)
+		user.PUT("/settings", handler.UpdateUserSettings)
+		user.GET("/organizations", handler.ListMyOrganizations)
+		user.GET("/takeout", readReplica, handler.GetTakeout)
+	}
+
+	// Organization management routes (require an admin)
+	organizations := api.Group("/organizations")
+	organizations.Use(middleware.JWTOnlyMiddleware(authService), middleware.RequireRole(models.RoleAdmin))
+	{
+		organizations.GET("", handler.ListOrganizations)
+		organizations.POST("", handler.CreateOrganization)
+		organizations.DELETE("/:id", handler.DeleteOrganization)
+		organizations.GET("/:id/members", handler.ListOrganizationMembers)
+		organizations.POST("/:id/members", handler.AddOrganizationMember)
+		organizations.DELETE("/:id/members/:user_id", handler.RemoveOrganizationMember)
+	}
+
+	// Admin routes (require an admin)
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(authService), middleware.RequireRole(models.RoleAdmin))
+	{
+		queue := admin.Group("/queue")
 		{
-			summarize.POST("/", handler.Summarize)
+			queue.GET("/stats", handler.GetQueueStats)
 		}
-
-		// Config routes (require authentication)
-		config := v1.Group("/config")
-		config.Use(middleware.AuthMiddleware(authService))
+		admin.GET("/environments", handler.GetEnvironmentStatus)
+		admin.GET("/diagnostics", handler.GetDiagnostics)
+		admin.GET("/quick-transcription/cleanup", handler.GetQuickTranscriptionCleanupStats)
+		admin.POST("/quick-transcription/cleanup", handler.RunQuickTranscriptionCleanup)
+		admin.POST("/alignment-models", handler.PrefetchAlignmentModels)
+		admin.GET("/adapters", handler.ListAdapters)
+		admin.POST("/adapters/reload", handler.ReloadAdapters)
+		admin.PUT("/adapters/:id", handler.UpdateAdapterConfig)
+		admin.DELETE("/adapters/:id", handler.DeleteAdapterConfig)
+		admin.GET("/shadow-runs", handler.ListShadowRuns)
+		admin.GET("/shadow-runs/:id", handler.GetShadowRun)
+		admin.GET("/benchmarks", handler.ListBenchmarks)
+		admin.POST("/benchmarks", handler.CreateBenchmark)
+		admin.GET("/benchmarks/:id", handler.GetBenchmark)
+		admin.DELETE("/benchmarks/:id", handler.DeleteBenchmark)
+		admin.GET("/audit", readReplica, handler.ListAuditEvents)
+		admin.GET("/audit/export", readReplica, handler.ExportAuditEvents)
+		admin.GET("/integrity", handler.GetIntegrityReport)
+		admin.POST("/integrity", handler.RunIntegrityCheck)
+		admin.GET("/backups", handler.ListBackups)
+		admin.POST("/backups", handler.CreateBackup)
+		admin.POST("/backups/restore", handler.RestoreBackup)
+		admin.GET("/backups/:name", handler.DownloadBackup)
+		admin.GET("/notification-templates", handler.ListNotificationTemplates)
+		admin.PUT("/notification-templates/:channel", handler.UpdateNotificationTemplate)
+		admin.DELETE("/notification-templates/:channel", handler.DeleteNotificationTemplate)
+		admin.POST("/notification-templates/:channel/preview", handler.PreviewNotificationTemplate)
+		admin.GET("/retention/policies", handler.ListRetentionPolicies)
+		admin.POST("/retention/policies", handler.CreateRetentionPolicy)
+		admin.PUT("/retention/policies/:id", handler.UpdateRetentionPolicy)
+		admin.DELETE("/retention/policies/:id", handler.DeleteRetentionPolicy)
+		admin.GET("/retention/preview", handler.PreviewRetention)
+		admin.POST("/retention/enforce", handler.EnforceRetention)
+		admin.GET("/imports", handler.ListLibraryImports)
+		admin.POST("/imports", handler.StartLibraryImport)
+		admin.GET("/imports/:id", handler.GetLibraryImport)
+		admin.GET("/imports/:id/files", handler.ListLibraryImportFiles)
+		admin.POST("/imports/:id/cancel", handler.CancelLibraryImport)
+		admin.GET("/storage", handler.GetStorageUsage)
+		admin.GET("/analytics", readReplica, handler.GetUsageAnalytics)
+		admin.POST("/storage/cleanup", handler.CleanupStorage)
+		admin.GET("/aws-tag-routes", handler.ListAWSTagRoutes)
+		admin.POST("/aws-tag-routes", handler.CreateAWSTagRoute)
+		admin.PUT("/aws-tag-routes/:id", handler.UpdateAWSTagRoute)
+		admin.DELETE("/aws-tag-routes/:id", handler.DeleteAWSTagRoute)
+		admin.GET("/reproducibility/components", handler.ListComponentVersions)
+		admin.GET("/reproducibility/jobs", handler.ListComponentJobs)
+		admin.POST("/reproducibility/reprocess", handler.ReprocessComponentJobs)
+
+		quarantine := admin.Group("/quarantine")
 		{
-			config.POST("/openai/validate", handler.ValidateOpenAIKey)
+			quarantine.GET("", handler.ListQuarantinedJobs)
+			quarantine.POST("/:id/release", handler.ReleaseQuarantinedJob)
+			quarantine.DELETE("/:id", handler.DeleteQuarantinedJob)
 		}
 	}
 
-	// Set up static file serving for React app
-	web.SetupStaticRoutes(router, authService)
+	// LLM configuration routes (require authentication; changes require an admin)
+	llm := api.Group("/llm")
+	llm.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleAdmin))
+	{
+		llm.GET("/config", handler.GetLLMConfig)
+		llm.POST("/config", handler.SaveLLMConfig)
+	}
 
-	return router
+	// Summarization templates routes (require authentication)
+	summaries := api.Group("/summaries")
+	summaries.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		summaries.GET("/", handler.ListSummaryTemplates)
+		summaries.POST("/", handler.CreateSummaryTemplate)
+		summaries.GET("/:id", handler.GetSummaryTemplate)
+		summaries.PUT("/:id", handler.UpdateSummaryTemplate)
+		summaries.DELETE("/:id", handler.DeleteSummaryTemplate)
+		summaries.GET("/settings", handler.GetSummarySettings)
+		summaries.POST("/settings", handler.SaveSummarySettings)
+	}
+
+	// Chat routes (require authentication)
+	chat := api.Group("/chat")
+	chat.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor), handler.RequireJobAccess("transcription_id"), handler.RequireChatSessionAccess("session_id"))
+	{
+		chat.GET("/models", handler.GetChatModels)
+		chat.POST("/sessions", handler.CreateChatSession)
+		chat.GET("/transcriptions/:transcription_id/sessions", handler.GetChatSessions)
+		chat.GET("/sessions/:session_id", handler.GetChatSession)
+		chat.POST("/sessions/:session_id/messages", handler.SendChatMessage)
+		chat.PUT("/sessions/:session_id/title", handler.UpdateChatSessionTitle)
+		chat.POST("/sessions/:session_id/title/auto", handler.AutoGenerateChatTitle)
+		chat.DELETE("/sessions/:session_id", handler.DeleteChatSession)
+		chat.POST("/workspace", hideCollections, handler.WorkspaceChat)
+		chat.GET("/policy", handler.GetChatPolicy)
+		chat.PUT("/policy", middleware.RequireRole(models.RoleAdmin), handler.UpdateChatPolicy)
+	}
+
+	// Notes routes (require authentication)
+	notes := api.Group("/notes")
+	notes.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor), handler.RequireNoteAccess("note_id"), handler.RequireUnfinalized())
+	{
+		notes.GET("/:note_id", handler.GetNote)
+		notes.PUT("/:note_id", handler.UpdateNote)
+		notes.DELETE("/:note_id", handler.DeleteNote)
+	}
+
+	// Summarization route (require authentication)
+	summarize := api.Group("/summarize")
+	summarize.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor), handler.RequireUnfinalized())
+	{
+		summarize.POST("/", handler.Summarize)
+	}
+
+	// Config routes (require authentication)
+	config := api.Group("/config")
+	config.Use(middleware.AuthMiddleware(authService), middleware.RequireRoleForWrites(models.RoleEditor))
+	{
+		config.POST("/openai/validate", handler.ValidateOpenAIKey)
+	}
+}
+
+// readReplica serves a route's queries from the read replica, when one is
+// configured. Only for routes that do not read back their own writes.
+func readReplica(c *gin.Context) {
+	c.Request = c.Request.WithContext(database.WithReplica(c.Request.Context()))
+	c.Next()
 }
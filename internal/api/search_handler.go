@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Full-text search
+// @Description Search transcript text, titles, summaries and notes. Returns matching transcriptions ordered by relevance, each with snippets, highlight offsets (Unicode code points into the snippet) and, for transcript segments and notes, start/end timestamps in seconds. The last word is prefix-matched.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search query"
+// @Param limit query int false "Maximum transcriptions to return" default(20)
+// @Param matches query int false "Maximum matches per transcription" default(5)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/search [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	matches, _ := strconv.Atoi(c.DefaultQuery("matches", "5"))
+	if matches <= 0 || matches > 50 {
+		matches = 5
+	}
+
+	results, err := h.searchRepo.Search(c.Request.Context(), query, limit, matches)
+	if err != nil {
+		logger.Error("Search failed", "query", query, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	if results, err = h.filterSearchResults(c, results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   query,
+		"results": results,
+	})
+}
+
+// filterSearchResults drops the transcript and summary matches the request's
+// role may not see in each result's workspace, and results left without matches
+func (h *Handler) filterSearchResults(c *gin.Context, results []models.SearchResult) ([]models.SearchResult, error) {
+	filter := h.newJobFieldFilter(c)
+	filtered := results[:0]
+	for _, result := range results {
+		hidden, err := filter.fields(result.Workspace)
+		if err != nil {
+			return nil, err
+		}
+		if len(hidden) == 0 {
+			filtered = append(filtered, result)
+			continue
+		}
+		matches := []models.SearchMatch{}
+		for _, match := range result.Matches {
+			if (match.Kind == "segment" && hidden[models.JobFieldTranscript]) || (match.Kind == "summary" && hidden[models.JobFieldSummary]) {
+				continue
+			}
+			matches = append(matches, match)
+		}
+		if len(matches) > 0 {
+			result.Matches = matches
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
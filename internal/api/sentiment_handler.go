@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyzeSentimentRequest selects the model used for sentiment analysis
+type AnalyzeSentimentRequest struct {
+	Model string `json:"model,omitempty"` // defaults to the default summary model
+}
+
+// @Summary Analyze segment sentiment
+// @Description Label every transcript segment with its sentiment (positive, neutral or negative, with a score from -1 to 1) and dominant emotion using the active LLM, replacing any earlier analysis. The result is returned with the transcript.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body AnalyzeSentimentRequest false "Options"
+// @Success 200 {array} service.SegmentSentiment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/sentiment [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) AnalyzeTranscriptSentiment(c *gin.Context) {
+	var req AnalyzeSentimentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	svc, _, err := h.getCachedLLMService(ctx)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	model := req.Model
+	if model == "" {
+		if settings, err := h.summaryRepo.GetSettings(ctx); err == nil {
+			model = settings.DefaultModel
+		}
+	}
+
+	sentiment, err := service.AnalyzeSentiment(ctx, svc, model, job.Transcript)
+	if err != nil {
+		logger.Error("Sentiment analysis failed", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sentiment analysis failed: " + err.Error()})
+		return
+	}
+
+	data, err := json.Marshal(sentiment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode sentiment"})
+		return
+	}
+	encoded := string(data)
+	job.SegmentSentiment = &encoded
+	if err := h.jobRepo.UpdatePostProcessing(ctx, job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save sentiment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sentiment)
+}
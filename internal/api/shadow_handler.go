@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ShadowRunsResponse lists shadow runs with a summary per candidate adapter
+type ShadowRunsResponse struct {
+	Runs    []models.ShadowRun   `json:"runs"`
+	Summary []ShadowModelSummary `json:"summary"`
+}
+
+// ShadowModelSummary aggregates the listed shadow runs of one candidate adapter
+type ShadowModelSummary struct {
+	ModelID   string `json:"model_id"`
+	Runs      int    `json:"runs"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	// MeanWordAgreement averages the word agreement of the completed runs
+	MeanWordAgreement *float64 `json:"mean_word_agreement,omitempty"`
+	// MeanProcessingDuration averages the duration of the completed runs, in milliseconds
+	MeanProcessingDuration *int64 `json:"mean_processing_duration,omitempty"`
+}
+
+// summarizeShadowRuns aggregates runs per candidate, in order of first appearance
+func summarizeShadowRuns(runs []models.ShadowRun) []ShadowModelSummary {
+	summary := []ShadowModelSummary{}
+	index := make(map[string]int)
+	agreement := make(map[string]float64)
+	duration := make(map[string]int64)
+	for _, run := range runs {
+		i, ok := index[run.ModelID]
+		if !ok {
+			i = len(summary)
+			index[run.ModelID] = i
+			summary = append(summary, ShadowModelSummary{ModelID: run.ModelID})
+		}
+		s := &summary[i]
+		s.Runs++
+		switch run.Status {
+		case models.StatusCompleted:
+			s.Completed++
+			if run.WordAgreement != nil {
+				agreement[run.ModelID] += *run.WordAgreement
+			}
+			if run.ProcessingDuration != nil {
+				duration[run.ModelID] += *run.ProcessingDuration
+			}
+		case models.StatusFailed:
+			s.Failed++
+		}
+	}
+	for i := range summary {
+		s := &summary[i]
+		if s.Completed > 0 {
+			meanAgreement := agreement[s.ModelID] / float64(s.Completed)
+			meanDuration := duration[s.ModelID] / int64(s.Completed)
+			s.MeanWordAgreement = &meanAgreement
+			s.MeanProcessingDuration = &meanDuration
+		}
+	}
+	return summary
+}
+
+// @Summary List shadow runs
+// @Description List the latest jobs transcribed again by a candidate adapter in shadow mode, with each run's word agreement with the transcript users received, and a summary per candidate. Transcripts are left out; fetch a run for its transcript.
+// @Tags admin
+// @Produce json
+// @Param model_id query string false "Only runs of this candidate adapter"
+// @Param limit query int false "Maximum number of runs (1-500)" default(100)
+// @Success 200 {object} ShadowRunsResponse
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/admin/shadow-runs [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListShadowRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+		return
+	}
+	runs, err := h.jobRepo.ListShadowRuns(c.Request.Context(), c.Query("model_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list shadow runs"})
+		return
+	}
+	c.JSON(http.StatusOK, ShadowRunsResponse{Runs: runs, Summary: summarizeShadowRuns(runs)})
+}
+
+// @Summary Get shadow run
+// @Description Get a shadow run with the candidate adapter's transcript
+// @Tags admin
+// @Produce json
+// @Param id path string true "Shadow run ID"
+// @Success 200 {object} models.ShadowRun
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/shadow-runs/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetShadowRun(c *gin.Context) {
+	run, err := h.jobRepo.FindShadowRun(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shadow run not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shadow run"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
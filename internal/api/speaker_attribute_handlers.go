@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WorkspaceSettingsRequest sets the privacy settings of a workspace
+type WorkspaceSettingsRequest struct {
+	// SpeakerAttributesDisabled stops speaker attribute estimation in the
+	// workspace and deletes the attributes already estimated
+	SpeakerAttributesDisabled bool `json:"speaker_attributes_disabled"`
+}
+
+// WorkspaceSettingsResponse is a workspace's settings after an update
+type WorkspaceSettingsResponse struct {
+	*models.WorkspaceSettings
+	// SpeakerAttributesDeleted counts the speaker attributes deleted by disabling them
+	SpeakerAttributesDeleted int64 `json:"speaker_attributes_deleted"`
+}
+
+// @Summary Get speaker attributes of a transcription
+// @Description List the coarse gender and age range estimated from each diarized speaker's voice. Estimation is opt-in: it runs only for diarized jobs whose parameters set speaker_attributes, in workspaces that have not disabled it. Speakers with under 3 seconds of speech are left out, and a gender below 60% confidence is reported as unknown.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription Job ID"
+// @Success 200 {array} models.SpeakerAttribute
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/speakers/attributes [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetSpeakerAttributes(c *gin.Context) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription job"})
+		return
+	}
+	attributes, err := h.speakerAttributes.List(c.Request.Context(), job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker attributes"})
+		return
+	}
+	c.JSON(http.StatusOK, attributes)
+}
+
+// settingsWorkspace returns the workspace whose settings a request reads or
+// sets: the request's own, or for admins a visible one named by the workspace
+// query parameter
+func (h *Handler) settingsWorkspace(c *gin.Context) (string, bool) {
+	workspace, named := c.GetQuery("workspace")
+	if !named {
+		return h.requestWorkspace(c), true
+	}
+	if c.GetString("role") != models.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can manage other workspaces' settings"})
+		return "", false
+	}
+	if !models.WorkspaceVisible(c.Request.Context(), workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+		return "", false
+	}
+	return workspace, true
+}
+
+// @Summary Get workspace settings
+// @Description Get the privacy settings of the request's workspace; admins can read another workspace's
+// @Tags workspace
+// @Produce json
+// @Param workspace query string false "Workspace to get the settings of (admins only)"
+// @Success 200 {object} models.WorkspaceSettings
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/workspace/settings [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetWorkspaceSettings(c *gin.Context) {
+	workspace, ok := h.settingsWorkspace(c)
+	if !ok {
+		return
+	}
+	settings, err := h.speakerAttributes.Settings(c.Request.Context(), workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workspace settings"})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary Update workspace settings
+// @Description Set the privacy settings of the request's workspace, or of another with the workspace query parameter. Disabling speaker attributes stops their estimation in the workspace's jobs, whatever the jobs' parameters request, and deletes those already estimated.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param workspace query string false "Workspace to set the settings of"
+// @Param request body WorkspaceSettingsRequest true "Workspace settings"
+// @Success 200 {object} WorkspaceSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/workspace/settings [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateWorkspaceSettings(c *gin.Context) {
+	workspace, ok := h.settingsWorkspace(c)
+	if !ok {
+		return
+	}
+	var req WorkspaceSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	settings := &models.WorkspaceSettings{
+		Workspace:                 workspace,
+		SpeakerAttributesDisabled: req.SpeakerAttributesDisabled,
+		UpdatedBy:                 h.requestAuthor(c),
+	}
+	deleted, err := h.speakerAttributes.UpdateSettings(c.Request.Context(), settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save workspace settings"})
+		return
+	}
+	c.JSON(http.StatusOK, WorkspaceSettingsResponse{WorkspaceSettings: settings, SpeakerAttributesDeleted: deleted})
+}
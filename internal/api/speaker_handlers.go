@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SpeakerRequest creates or renames a speaker in the directory
+type SpeakerRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// SpeakerResponse is a directory speaker with the jobs it is mapped in
+type SpeakerResponse struct {
+	models.Speaker
+	Jobs []repository.SpeakerAppearance `json:"jobs"`
+}
+
+// speakerName validates a speaker name from a request
+func speakerName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && len(name) <= 100
+}
+
+// directorySpeaker loads the speaker in the path, hiding speakers of other workspaces
+func (h *Handler) directorySpeaker(c *gin.Context) (*models.Speaker, bool) {
+	speaker, err := h.speakerRepo.FindInWorkspace(c.Request.Context(), h.requestWorkspace(c), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Speaker not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker"})
+		return nil, false
+	}
+	return speaker, true
+}
+
+// @Summary List speakers
+// @Description List the speaker directory of the caller's workspace, with the number of jobs each speaker is mapped in
+// @Tags speakers
+// @Produce json
+// @Success 200 {array} repository.SpeakerWithJobs
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/speakers [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListSpeakers(c *gin.Context) {
+	speakers, err := h.speakerRepo.ListByWorkspace(c.Request.Context(), h.requestWorkspace(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list speakers"})
+		return
+	}
+	c.JSON(http.StatusOK, speakers)
+}
+
+// @Summary Create a speaker
+// @Description Add a person to the speaker directory. Naming a diarized speaker in a job's speaker mappings also adds them.
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param request body SpeakerRequest true "Speaker"
+// @Success 201 {object} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/speakers [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateSpeaker(c *gin.Context) {
+	var req SpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	name, ok := speakerName(req.Name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name must be between 1 and 100 characters"})
+		return
+	}
+
+	workspace := h.requestWorkspace(c)
+	if _, err := h.speakerRepo.FindByName(c.Request.Context(), workspace, name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A speaker with this name already exists"})
+		return
+	}
+	speaker := &models.Speaker{Workspace: workspace, Name: name}
+	if err := h.speakerRepo.Create(c.Request.Context(), speaker); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create speaker"})
+		return
+	}
+	c.JSON(http.StatusCreated, speaker)
+}
+
+// @Summary Get a speaker
+// @Description Get a speaker and every job it is mapped in, newest first
+// @Tags speakers
+// @Produce json
+// @Param id path string true "Speaker ID"
+// @Success 200 {object} SpeakerResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/speakers/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetSpeaker(c *gin.Context) {
+	speaker, ok := h.directorySpeaker(c)
+	if !ok {
+		return
+	}
+	jobs, err := h.speakerRepo.ListAppearances(c.Request.Context(), speaker.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list the speaker's jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, SpeakerResponse{Speaker: *speaker, Jobs: jobs})
+}
+
+// @Summary Rename a speaker
+// @Description Rename a speaker in the directory and in every job it is mapped in
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param id path string true "Speaker ID"
+// @Param request body SpeakerRequest true "New name"
+// @Success 200 {object} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/speakers/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateSpeaker(c *gin.Context) {
+	var req SpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	name, ok := speakerName(req.Name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name must be between 1 and 100 characters"})
+		return
+	}
+	speaker, ok := h.directorySpeaker(c)
+	if !ok {
+		return
+	}
+
+	if name != speaker.Name {
+		if _, err := h.speakerRepo.FindByName(c.Request.Context(), speaker.Workspace, name); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "A speaker with this name already exists"})
+			return
+		}
+		if err := h.speakerRepo.Rename(c.Request.Context(), speaker, name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename speaker"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, speaker)
+}
+
+// @Summary Delete a speaker
+// @Description Remove a speaker from the directory. Jobs it is mapped in keep the speaker's name.
+// @Tags speakers
+// @Produce json
+// @Param id path string true "Speaker ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/speakers/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteSpeaker(c *gin.Context) {
+	if err := h.speakerRepo.DeleteSpeaker(c.Request.Context(), h.requestWorkspace(c), c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Speaker not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete speaker"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Speaker deleted successfully"})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
 	"scriberr/internal/models"
 
@@ -9,10 +10,13 @@ import (
 	"gorm.io/gorm"
 )
 
-// SpeakerMappingRequest represents a speaker mapping update request
+// SpeakerMappingRequest represents a speaker mapping update request. The
+// speaker is named either after a directory speaker (speaker_id) or by name, in
+// which case the directory speaker of that name is linked, and created if needed.
 type SpeakerMappingRequest struct {
-	OriginalSpeaker string `json:"original_speaker" binding:"required"`
-	CustomName      string `json:"custom_name" binding:"required"`
+	OriginalSpeaker string  `json:"original_speaker" binding:"required"`
+	CustomName      string  `json:"custom_name"`
+	SpeakerID       *string `json:"speaker_id,omitempty"`
 }
 
 // SpeakerMappingsUpdateRequest represents a bulk speaker mappings update
@@ -25,6 +29,9 @@ type SpeakerMappingResponse struct {
 	ID              uint   `json:"id"`
 	OriginalSpeaker string `json:"original_speaker"`
 	CustomName      string `json:"custom_name"`
+	// VoiceprintID is set when the name was assigned by voiceprint matching
+	VoiceprintID *string `json:"voiceprint_id,omitempty"`
+	SpeakerID    *string `json:"speaker_id,omitempty"`
 }
 
 // GetSpeakerMappings retrieves all speaker mappings for a transcription
@@ -74,6 +81,8 @@ func (h *Handler) GetSpeakerMappings(c *gin.Context) {
 			ID:              mapping.ID,
 			OriginalSpeaker: mapping.OriginalSpeaker,
 			CustomName:      mapping.CustomName,
+			VoiceprintID:    mapping.VoiceprintID,
+			SpeakerID:       mapping.SpeakerID,
 		}
 	}
 
@@ -121,14 +130,39 @@ func (h *Handler) UpdateSpeakerMappings(c *gin.Context) {
 		return
 	}
 
-	// Convert request to model
+	// Convert request to model, linking each name to the speaker directory
+	workspace := h.requestWorkspace(c)
 	var mappings []models.SpeakerMapping
 	for _, mapping := range req.Mappings {
-		mappings = append(mappings, models.SpeakerMapping{
+		converted := models.SpeakerMapping{
 			TranscriptionJobID: jobID,
 			OriginalSpeaker:    mapping.OriginalSpeaker,
-			CustomName:         mapping.CustomName,
-		})
+			CustomName:         strings.TrimSpace(mapping.CustomName),
+		}
+		var speaker *models.Speaker
+		switch {
+		case mapping.SpeakerID != nil:
+			speaker, err = h.speakerRepo.FindInWorkspace(c.Request.Context(), workspace, *mapping.SpeakerID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown speaker: " + *mapping.SpeakerID})
+				return
+			}
+		case converted.CustomName == "":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Each mapping needs a custom_name or speaker_id"})
+			return
+		case converted.CustomName != mapping.OriginalSpeaker:
+			// Speakers left under their generic label are not added to the directory
+			speaker, err = h.speakerRepo.FindOrCreateByName(c.Request.Context(), workspace, converted.CustomName)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update speaker directory"})
+				return
+			}
+		}
+		if speaker != nil {
+			converted.CustomName = speaker.Name
+			converted.SpeakerID = &speaker.ID
+		}
+		mappings = append(mappings, converted)
 	}
 
 	// Update mappings using repository
@@ -151,6 +185,8 @@ func (h *Handler) UpdateSpeakerMappings(c *gin.Context) {
 			ID:              mapping.ID,
 			OriginalSpeaker: mapping.OriginalSpeaker,
 			CustomName:      mapping.CustomName,
+			VoiceprintID:    mapping.VoiceprintID,
+			SpeakerID:       mapping.SpeakerID,
 		}
 	}
 
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/service"
+	"scriberr/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageCleanupResponse reports what a storage cleanup removed
+type StorageCleanupResponse struct {
+	Storage            *service.StorageCleanup          `json:"storage"`
+	QuickTranscription *transcription.QuickCleanupStats `json:"quick_transcription,omitempty"`
+}
+
+// @Summary Get storage usage
+// @Description Report the disk usage of the upload directory (in total and by workspace), transcripts, backups, the database and the downloaded-file cache, with the jobs whose audio takes the most space
+// @Tags admin
+// @Produce json
+// @Param limit query int false "How many of the largest jobs to list (1-1000)" default(20)
+// @Success 200 {object} service.StorageReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/storage [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetStorageUsage(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+		return
+	}
+	report, err := h.storage.Report(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to measure storage usage"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Clean up storage
+// @Description Delete cached downloads and partial downloads older than max_age_minutes, except those of jobs waiting or being processed and downloads still being written, and apply the quick transcription retention and storage policies now
+// @Tags admin
+// @Produce json
+// @Param max_age_minutes query int false "Only remove downloads at least this old (at least 15)" default(60)
+// @Success 200 {object} StorageCleanupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/storage/cleanup [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CleanupStorage(c *gin.Context) {
+	maxAge, err := strconv.Atoi(c.DefaultQuery("max_age_minutes", "60"))
+	if err != nil || time.Duration(maxAge)*time.Minute < service.MinCleanupAge {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("max_age_minutes must be at least %d", int(service.MinCleanupAge/time.Minute))})
+		return
+	}
+	cleanup, err := h.storage.Cleanup(c.Request.Context(), time.Duration(maxAge)*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clean up storage"})
+		return
+	}
+	response := StorageCleanupResponse{Storage: cleanup}
+	if h.quickTranscription != nil {
+		stats := h.quickTranscription.Cleanup()
+		response.QuickTranscription = &stats
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -8,9 +8,9 @@ import (
 	"strings"
 	"time"
 
-	"scriberr/internal/database"
 	"scriberr/internal/llm"
 	"scriberr/internal/models"
+	"scriberr/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -18,20 +18,21 @@ import (
 
 type SummarizeRequest struct {
 	Model           string  `json:"model" binding:"required"`
-	Content         string  `json:"content" binding:"required"`
+	Content         string  `json:"content"` // prompt sent as is when no template is given
 	TranscriptionID string  `json:"transcription_id" binding:"required"`
 	TemplateID      *string `json:"template_id,omitempty"`
 }
 
 // Summarize streams LLM output for a given content prompt
 // @Summary Summarize content
-// @Description Stream an LLM-generated summary for provided content; persists latest summary for the transcription
+// @Description Stream an LLM-generated summary; persists latest summary for the transcription. With a template, the stored transcript is summarized with the template's prompt, chunk by chunk when it is long, and content is ignored; otherwise content is sent as the prompt.
 // @Tags summarize
 // @Accept json
 // @Produce text/event-stream
 // @Param request body SummarizeRequest true "Summarize request"
 // @Success 200 {string} string "Event stream"
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Security BearerAuth
@@ -42,12 +43,36 @@ func (h *Handler) Summarize(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	job, err := h.jobRepo.FindByID(c.Request.Context(), req.TranscriptionID)
+	if err == nil && !models.WorkspaceVisible(c.Request.Context(), job.Workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
+	useTemplate := req.TemplateID != nil && *req.TemplateID != ""
+	if useTemplate && err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
+	if !useTemplate && req.Content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content or template_id is required"})
+		return
+	}
+	if err := h.jobRepo.EnsureUnfinalized(c.Request.Context(), req.TranscriptionID); err != nil {
+		if !finalizedConflict(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		}
+		return
+	}
 
-	svc, provider, err := h.getLLMService(c.Request.Context())
+	svc, provider, err := h.getCachedLLMService(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if useTemplate {
+		h.summarizeWithTemplate(c, svc, job, &req)
+		return
+	}
 
 	// Prepare chat messages: simple single-user message with full content
 	messages := []llm.ChatMessage{{Role: "user", Content: req.Content}}
@@ -73,22 +98,7 @@ func (h *Handler) Summarize(c *gin.Context) {
 
 	// helper to persist any accumulated content
 	persistIfAny := func() {
-		if req.TranscriptionID == "" || finalText == "" {
-			return
-		}
-		sum := &models.Summary{
-			TranscriptionID: req.TranscriptionID,
-			TemplateID:      req.TemplateID,
-			Model:           req.Model,
-			Content:         finalText,
-		}
-		if err := h.summaryRepo.SaveSummary(context.Background(), sum); err != nil {
-			// Fallback: store on the transcription job record
-			_ = database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", req.TranscriptionID).Update("summary", finalText).Error
-		} else {
-			// Also cache on the transcription job for quick access
-			_ = database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", req.TranscriptionID).Update("summary", finalText).Error
-		}
+		h.saveSummary(c, &req, finalText)
 	}
 	for {
 		select {
@@ -164,6 +174,64 @@ func (h *Handler) Summarize(c *gin.Context) {
 	}
 }
 
+// summarizeWithTemplate applies a template's prompt to the stored transcript.
+// Long transcripts are condensed chunk by chunk first, each chunk answered from
+// the LLM cache when unchanged, so the reply is written once it is complete.
+func (h *Handler) summarizeWithTemplate(c *gin.Context, svc llm.Service, job *models.TranscriptionJob, req *SummarizeRequest) {
+	template, err := h.summaryRepo.FindByID(c.Request.Context(), *req.TemplateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	start := time.Now()
+	log.Printf("[summarize] start transcription_id=%s template_id=%s model=%s", req.TranscriptionID, template.ID, req.Model)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	// Allow longer generation time for large transcripts and smaller models
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Minute)
+	defer cancel()
+
+	content, err := service.SummarizeTranscript(ctx, svc, req.Model, job.Transcript, template.Prompt)
+	if err != nil {
+		log.Printf("[summarize] error transcription_id=%s model=%s err=%v duration_ms=%d", req.TranscriptionID, req.Model, err, time.Since(start).Milliseconds())
+		c.Writer.Write([]byte("\n"))
+		return
+	}
+	c.Writer.Write([]byte(content))
+	if flusher, ok := c.Writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	h.saveSummary(c, req, content)
+	log.Printf("[summarize] complete transcription_id=%s model=%s bytes=%d duration_ms=%d", req.TranscriptionID, req.Model, len(content), time.Since(start).Milliseconds())
+}
+
+// saveSummary stores a generated summary as the transcription's latest
+func (h *Handler) saveSummary(c *gin.Context, req *SummarizeRequest, content string) {
+	if req.TranscriptionID == "" || content == "" {
+		return
+	}
+	sum := &models.Summary{
+		TranscriptionID: req.TranscriptionID,
+		TemplateID:      req.TemplateID,
+		Model:           req.Model,
+		Content:         content,
+	}
+	// Kept past the request, with its permission to change finalized jobs
+	persistCtx := context.WithoutCancel(c.Request.Context())
+	if err := h.summaryRepo.SaveSummary(persistCtx, sum); err != nil {
+		log.Printf("[summarize] failed to save summary transcription_id=%s err=%v", req.TranscriptionID, err)
+	}
+	// Stored on the transcription job too, for quick access and as a
+	// fallback when the summary record could not be saved
+	if err := h.jobRepo.UpdateSummary(persistCtx, req.TranscriptionID, content); err != nil {
+		log.Printf("[summarize] failed to store summary on job transcription_id=%s err=%v", req.TranscriptionID, err)
+	}
+}
+
 // GetSummaryForTranscription returns the latest summary for a transcription
 // @Summary Get latest summary for transcription
 // @Description Get the most recent saved summary for the given transcription
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultTagSuggestions is how many tags autocompletion returns by default
+const defaultTagSuggestions = 10
+
+// tagColor matches the #rrggbb colors tags are displayed in
+var tagColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TagRequest creates or replaces a tag
+type TagRequest struct {
+	Key   string `json:"key" binding:"required"`
+	Value string `json:"value,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// TagAssignmentRequest tags a job with an existing tag, by ID, or by name
+// (key or key=value), creating the tag if the workspace has none by that name
+type TagAssignmentRequest struct {
+	TagID string `json:"tag_id,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// checkTag trims the key, value and color of a tag, returning why they are
+// invalid if they are
+func checkTag(tag *models.Tag) string {
+	tag.Key, tag.Value, tag.Color = strings.TrimSpace(tag.Key), strings.TrimSpace(tag.Value), strings.TrimSpace(tag.Color)
+	switch {
+	case tag.Key == "" || len(tag.Key) > 128:
+		return "Key must be between 1 and 128 characters"
+	case strings.Contains(tag.Key, "="):
+		return "Key must not contain ="
+	case len(tag.Value) > 256:
+		return "Value must be at most 256 characters"
+	case tag.Color != "" && !tagColor.MatchString(tag.Color):
+		return "Color must be of the form #rrggbb"
+	}
+	return ""
+}
+
+// workspaceTag loads a tag of the caller's workspace, hiding tags of other workspaces
+func (h *Handler) workspaceTag(c *gin.Context, id string) (*models.Tag, bool) {
+	tag, err := h.tagRepo.FindInWorkspace(c.Request.Context(), h.requestWorkspace(c), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tag"})
+		return nil, false
+	}
+	return tag, true
+}
+
+// @Summary List tags
+// @Description List the tags of the caller's workspace, most used first, with the number of jobs each is assigned to. With q, only the tags whose key, value or key=value name starts with it are listed, for autocompletion.
+// @Tags tags
+// @Produce json
+// @Param q query string false "Prefix to autocomplete"
+// @Param limit query int false "At most this many tags; 10 by default with q, else all"
+// @Success 200 {array} repository.TagWithJobs
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/tags [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListTags(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("q"))
+	limit := 0
+	if prefix != "" {
+		limit = defaultTagSuggestions
+	}
+	if n, err := strconv.Atoi(c.Query("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	tags, err := h.tagRepo.ListByWorkspace(c.Request.Context(), h.requestWorkspace(c), prefix, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tags"})
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary Create a tag
+// @Description Add a tag to the caller's workspace. Jobs submitted with tags, e.g. through the AWS Transcribe compatible endpoint, add theirs too.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body TagRequest true "Tag"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/tags [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	tag := &models.Tag{Workspace: h.requestWorkspace(c), Key: req.Key, Value: req.Value, Color: req.Color}
+	if invalid := checkTag(tag); invalid != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalid})
+		return
+	}
+
+	if _, err := h.tagRepo.FindByName(c.Request.Context(), tag.Workspace, tag.Key, tag.Value); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A tag with this key and value already exists"})
+		return
+	}
+	if err := h.tagRepo.Create(c.Request.Context(), tag); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
+	}
+	c.JSON(http.StatusCreated, tag)
+}
+
+// @Summary Update a tag
+// @Description Rename or recolor a tag. Renaming it renames it on every job it is assigned to.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param request body TagRequest true "Tag"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/tags/{id} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	tag, ok := h.workspaceTag(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	updated := *tag
+	updated.Key, updated.Value, updated.Color = req.Key, req.Value, req.Color
+	if invalid := checkTag(&updated); invalid != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalid})
+		return
+	}
+
+	if updated.Name() != tag.Name() {
+		if _, err := h.tagRepo.FindByName(c.Request.Context(), tag.Workspace, updated.Key, updated.Value); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "A tag with this key and value already exists"})
+			return
+		}
+	}
+	tag = &updated
+	if err := h.tagRepo.Update(c.Request.Context(), tag); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag"})
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+// @Summary Delete a tag
+// @Description Remove a tag from every job it is assigned to and delete it. Tags that retention policies apply to cannot be deleted.
+// @Tags tags
+// @Param id path string true "Tag ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/tags/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteTag(c *gin.Context) {
+	err := h.tagRepo.DeleteTag(c.Request.Context(), h.requestWorkspace(c), c.Param("id"))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+	case errors.Is(err, repository.ErrTagInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": "A retention policy applies to this tag; delete the policy first"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary List a job's tags
+// @Tags tags
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {array} models.Tag
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/tags [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListJobTags(c *gin.Context) {
+	tags, err := h.tagRepo.ListByJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tags"})
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary Tag a job
+// @Description Assign a tag to a job, by tag_id, or by name (key or key=value), adding the tag to the workspace if it has none by that name. The job's tags field, S3 object tags and AWS Transcribe responses follow its assigned tags.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body TagAssignmentRequest true "Tag"
+// @Success 200 {array} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/tags [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) AssignJobTag(c *gin.Context) {
+	var req TagAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := h.jobRepo.FindByID(ctx, c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var tag *models.Tag
+	switch {
+	case req.TagID != "":
+		var ok bool
+		if tag, ok = h.workspaceTag(c, req.TagID); !ok {
+			return
+		}
+	case req.Name != "":
+		named := &models.Tag{Workspace: h.requestWorkspace(c)}
+		named.Key, named.Value = models.ParseTagName(req.Name)
+		if invalid := checkTag(named); invalid != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": invalid})
+			return
+		}
+		found, err := h.tagRepo.FindByName(ctx, named.Workspace, named.Key, named.Value)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			found, err = named, h.tagRepo.Create(ctx, named)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+			return
+		}
+		tag = found
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Give the tag_id or name of the tag"})
+		return
+	}
+
+	if err := h.tagRepo.Assign(ctx, c.Param("id"), tag.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to tag job"})
+		return
+	}
+	h.ListJobTags(c)
+}
+
+// @Summary Untag a job
+// @Tags tags
+// @Param id path string true "Job ID"
+// @Param tag_id path string true "Tag ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/tags/{tag_id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UnassignJobTag(c *gin.Context) {
+	err := h.tagRepo.Unassign(c.Request.Context(), c.Param("id"), c.Param("tag_id"))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "The job does not have this tag"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to untag job"})
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
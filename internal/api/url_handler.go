@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// URLJobRequest represents a request to transcribe audio from a media URL
+type URLJobRequest struct {
+	URL        string                 `json:"url" binding:"required"`
+	Title      *string                `json:"title,omitempty"`
+	Parameters *models.WhisperXParams `json:"parameters,omitempty"`
+}
+
+// @Summary Submit transcription job from URL
+// @Description Download audio from a YouTube, podcast or other media page URL with yt-dlp and queue it for transcription. The job stays in the "downloading" status while ingest_progress is reported, then moves to "pending".
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body URLJobRequest true "URL job request"
+// @Success 202 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/url [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) SubmitURLJob(c *gin.Context) {
+	var req URLJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job := models.TranscriptionJob{
+		ID:        uuid.New().String(),
+		Title:     req.Title,
+		Workspace: h.requestWorkspace(c),
+	}
+
+	// Use explicit parameters when given, otherwise fall back to the default profile
+	if req.Parameters != nil {
+		job.Parameters = *req.Parameters
+	} else if profile := h.getDefaultProfile(c.Request.Context()); profile != nil {
+		job.Parameters = profile.Parameters
+	}
+	job.Diarization = job.Parameters.Diarize
+
+	if job.Parameters.IsMultiTrackEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Multi-track transcription cannot be used with URL sources"})
+		return
+	}
+
+	if err := h.urlIngest.Submit(c.Request.Context(), &job, req.URL); err != nil {
+		if errors.Is(err, service.ErrInvalidSourceURL) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			quotaExceeded(c)
+			return
+		}
+		logger.Error("Failed to submit URL job", "url", req.URL, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
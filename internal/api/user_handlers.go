@@ -0,0 +1,223 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/auth"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateUserRequest creates an account with a role
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Password string `json:"password" binding:"required,min=6"`
+	Role     string `json:"role" binding:"required"` // admin, editor or viewer
+}
+
+// UpdateUserRequest changes an account's role or resets its password
+type UpdateUserRequest struct {
+	Role     *string `json:"role,omitempty"`
+	Password *string `json:"password,omitempty" binding:"omitempty,min=6"`
+}
+
+// managedUser loads the user in the path
+func (h *Handler) managedUser(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	user, err := h.userRepo.FindByID(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return nil, false
+	}
+	return user, true
+}
+
+// isLastAdmin reports whether user is the only admin left, who must not be
+// demoted or deleted
+func (h *Handler) isLastAdmin(c *gin.Context, user *models.User) (bool, error) {
+	if user.Role != models.RoleAdmin {
+		return false, nil
+	}
+	admins, err := h.userRepo.CountByRole(c.Request.Context(), models.RoleAdmin)
+	return admins <= 1, err
+}
+
+// @Summary List users
+// @Description List the accounts and their roles
+// @Tags users
+// @Produce json
+// @Success 200 {array} models.User
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/users [get]
+// @Security BearerAuth
+func (h *Handler) ListUsers(c *gin.Context) {
+	users, _, err := h.userRepo.List(c.Request.Context(), 0, -1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list users"})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Create user
+// @Description Create an account. Admins manage users and API keys, editors create and edit jobs, and viewers have read-only access.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body CreateUserRequest true "Account"
+// @Success 201 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/users [post]
+// @Security BearerAuth
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+	if !models.ValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be admin, editor or viewer"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.userRepo.FindByUsername(ctx, username); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	}
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to secure password"})
+		return
+	}
+	user := models.User{Username: username, Password: hashedPassword, Role: req.Role}
+	if err := h.userRepo.Create(ctx, &user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+// @Summary Update user
+// @Description Change an account's role or reset its password. The last admin cannot be demoted.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body UpdateUserRequest true "Changes"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/users/{id} [put]
+// @Security BearerAuth
+func (h *Handler) UpdateUser(c *gin.Context) {
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	user, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+
+	if req.Role != nil && *req.Role != user.Role {
+		if !models.ValidRole(*req.Role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be admin, editor or viewer"})
+			return
+		}
+		last, err := h.isLastAdmin(c, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check admins"})
+			return
+		}
+		if last {
+			c.JSON(http.StatusConflict, gin.H{"error": "At least one admin is required"})
+			return
+		}
+		user.Role = *req.Role
+	}
+	if req.Password != nil {
+		hashedPassword, err := auth.HashPassword(*req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to secure password"})
+			return
+		}
+		user.Password = hashedPassword
+	}
+
+	if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Delete user
+// @Description Delete an account and sign it out. Admins cannot delete themselves or the last admin.
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/users/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) DeleteUser(c *gin.Context) {
+	user, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+	if userID, exists := c.Get("user_id"); exists && userID.(uint) == user.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot delete your own account"})
+		return
+	}
+	last, err := h.isLastAdmin(c, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check admins"})
+		return
+	}
+	if last {
+		c.JSON(http.StatusConflict, gin.H{"error": "At least one admin is required"})
+		return
+	}
+
+	err = database.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.CollectionMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, user.ID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
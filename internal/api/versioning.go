@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions. v2 serves the routes of v1 through this compatibility layer,
+// which converts their responses to v2's conventions, so both stay served by
+// the same handlers until a route changes enough to need its own.
+const (
+	apiV1Prefix = "/api/v1/"
+	apiV2Prefix = "/api/v2/"
+)
+
+// APIVersionInfo describes a version of the API
+type APIVersionInfo struct {
+	Version      string     `json:"version"`
+	Path         string     `json:"path"`
+	Status       string     `json:"status"` // current, supported or deprecated
+	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
+	SunsetAt     *time.Time `json:"sunset_at,omitempty"`
+}
+
+// v1Deprecation returns the configured deprecation of v1, if any
+func (h *Handler) v1Deprecation() (middleware.Deprecation, bool) {
+	if h.config.APIV1DeprecatedAt.IsZero() {
+		return middleware.Deprecation{}, false
+	}
+	return middleware.Deprecation{
+		Since:  h.config.APIV1DeprecatedAt,
+		Sunset: h.config.APIV1SunsetAt,
+		Successor: func(r *http.Request) string {
+			if !strings.HasPrefix(r.URL.Path, apiV1Prefix) {
+				return ""
+			}
+			successor := *r.URL
+			successor.Path = apiV2Prefix + strings.TrimPrefix(r.URL.Path, apiV1Prefix)
+			successor.RawPath = ""
+			return successor.RequestURI()
+		},
+	}, true
+}
+
+// @Summary List API versions
+// @Description List the versions of the API with whether each is current, and when deprecated versions stop being served. v2 serves the same routes as v1 with structured error bodies: {"error": {"code": "not_found", "message": "..."}}. Responses name the version that served them in the API-Version header, and deprecated versions send Deprecation, Sunset and successor-version Link headers.
+// @Tags system
+// @Produce json
+// @Success 200 {array} APIVersionInfo
+// @Router /api/versions [get]
+func (h *Handler) ListAPIVersions(c *gin.Context) {
+	v1 := APIVersionInfo{Version: "1", Path: strings.TrimSuffix(apiV1Prefix, "/"), Status: "supported"}
+	if deprecation, ok := h.v1Deprecation(); ok {
+		v1.Status = "deprecated"
+		v1.DeprecatedAt = &deprecation.Since
+		if !deprecation.Sunset.IsZero() {
+			v1.SunsetAt = &deprecation.Sunset
+		}
+	}
+	c.JSON(http.StatusOK, []APIVersionInfo{
+		v1,
+		{Version: "2", Path: strings.TrimSuffix(apiV2Prefix, "/"), Status: "current"},
+	})
+}
+
+// errorCodes are the codes of v2 error bodies by status; other statuses are
+// named after their status text
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "invalid_request",
+	http.StatusUnauthorized:        "unauthenticated",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusConflict:            "conflict",
+	http.StatusGone:                "gone",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusInternalServerError: "internal_error",
+	http.StatusServiceUnavailable:  "unavailable",
+	http.StatusGatewayTimeout:      "timeout",
+}
+
+// errorCode returns the v2 error code of a status
+func errorCode(status int) string {
+	if code, ok := errorCodes[status]; ok {
+		return code
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// structuredErrors converts the {"error": "message"} bodies of error responses
+// to v2's {"error": {"code": "...", "message": "..."}}, keeping their other
+// fields. Only error responses are buffered; others are written through.
+func structuredErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &structuredErrorWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		writer.flush()
+	}
+}
+
+// structuredErrorWriter holds back JSON error bodies to convert them
+type structuredErrorWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	buffering bool
+	decided   bool
+}
+
+func (w *structuredErrorWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.buffering = w.Status() >= http.StatusBadRequest &&
+			strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if w.buffering {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *structuredErrorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// flush writes the held back error body, converted
+func (w *structuredErrorWriter) flush() {
+	if !w.buffering {
+		return
+	}
+	data := w.body.Bytes()
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err == nil {
+		if message, ok := body["error"].(string); ok {
+			body["error"] = gin.H{"code": errorCode(w.Status()), "message": message}
+			if converted, err := json.Marshal(body); err == nil {
+				data = converted
+			}
+		}
+	}
+	w.ResponseWriter.Write(data)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Subtitle modes of a video download
+const (
+	subtitlesBurn    = "burn"
+	subtitlesSidecar = "sidecar"
+)
+
+// @Summary Download a job's video
+// @Description Download the video a job's audio was extracted from. With subtitles=burn the transcript, with speaker names, is drawn onto the frames and the video re-encoded as MP4, which takes a while for long videos. With subtitles=sidecar a zip holds the original video with SRT and WebVTT files named after it, which players load alongside. Subtitles need the job to be transcribed.
+// @Tags transcription
+// @Produce video/mp4,application/zip
+// @Param id path string true "Job ID"
+// @Param subtitles query string false "Subtitles to add (burn, sidecar)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/video [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetVideo(c *gin.Context) {
+	mode := strings.ToLower(c.Query("subtitles"))
+	if mode != "" && mode != subtitlesBurn && mode != subtitlesSidecar {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported subtitles %q; use burn or sidecar", mode)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := h.jobRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status == models.StatusQuarantined {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Video is quarantined pending review"})
+		return
+	}
+	if job.VideoPath == nil || *job.VideoPath == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This job has no video"})
+		return
+	}
+	videoPath, err := h.scopedJobPath(job, *job.VideoPath)
+	if err != nil {
+		logger.Warn("Video path is outside the job's workspace", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video file not found"})
+		return
+	}
+	if _, err := os.Stat(videoPath); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video file not found"})
+		return
+	}
+
+	name := exportFileName(jobTitle(job))
+	if mode == "" {
+		c.FileAttachment(videoPath, name+filepath.Ext(videoPath))
+		return
+	}
+
+	hidden, err := h.hiddenJobFields(c, job.Workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check field permissions"})
+		return
+	}
+	if hidden[models.JobFieldTranscript] {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role may not access the " + models.JobFieldTranscript + " of this job"})
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript not available"})
+		return
+	}
+	doc, err := h.exportDocument(ctx, job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transcript"})
+		return
+	}
+
+	if mode == subtitlesSidecar {
+		// Streamed, since the video may not fit in memory
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, name))
+		c.Header("Content-Type", "application/zip")
+		c.Status(http.StatusOK)
+		zw := zip.NewWriter(c.Writer)
+		if err := writeSidecarZip(zw, name, videoPath, doc); err != nil {
+			logger.Error("Failed to write subtitled video", "job_id", job.ID, "error", err)
+		}
+		zw.Close()
+		return
+	}
+
+	workDir, err := os.MkdirTemp("", "scriberr-subtitles-")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create working directory"})
+		return
+	}
+	defer os.RemoveAll(workDir)
+	subtitlesPath := filepath.Join(workDir, "subtitles.srt")
+	if err := os.WriteFile(subtitlesPath, []byte(export.SRT(doc)), 0644); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write subtitles"})
+		return
+	}
+	outputPath := filepath.Join(workDir, "subtitled.mp4")
+	if err := audio.BurnSubtitles(ctx, videoPath, subtitlesPath, outputPath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to burn in subtitles"})
+		return
+	}
+	c.FileAttachment(outputPath, name+".subtitled.mp4")
+}
+
+// writeSidecarZip writes the video, stored uncompressed as it is compressed
+// already, and its SRT and WebVTT subtitles under the same base name
+func writeSidecarZip(zw *zip.Writer, name, videoPath string, doc *export.Document) error {
+	video, err := os.Open(videoPath)
+	if err != nil {
+		return err
+	}
+	defer video.Close()
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name + filepath.Ext(videoPath), Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, video); err != nil {
+		return err
+	}
+
+	subtitles := []struct{ ext, content string }{
+		{".srt", export.SRT(doc)},
+		{".vtt", export.VTT(doc)},
+	}
+	for _, s := range subtitles {
+		w, err := zw.Create(name + s.ext)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, s.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
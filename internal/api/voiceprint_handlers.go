@@ -0,0 +1,161 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// EnrollVoiceprintRequest enrolls a diarized speaker of a job under a name.
+// Enrolling more speakers under the same name refines its voiceprint.
+type EnrollVoiceprintRequest struct {
+	Name    string `json:"name" binding:"required"`
+	JobID   string `json:"job_id" binding:"required"`
+	Speaker string `json:"speaker" binding:"required"`
+}
+
+// VoiceprintMatchResponse is a diarized speaker recognised by voiceprint
+type VoiceprintMatchResponse struct {
+	Speaker      string  `json:"speaker"`
+	VoiceprintID string  `json:"voiceprint_id"`
+	Name         string  `json:"name"`
+	Similarity   float64 `json:"similarity"`
+}
+
+// @Summary List voiceprints
+// @Description List the voiceprints enrolled in the caller's workspace
+// @Tags voiceprints
+// @Produce json
+// @Success 200 {array} models.Voiceprint
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/voiceprints [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListVoiceprints(c *gin.Context) {
+	voiceprints, err := h.speakerMappingRepo.ListVoiceprints(c.Request.Context(), h.requestWorkspace(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list voiceprints"})
+		return
+	}
+	c.JSON(http.StatusOK, voiceprints)
+}
+
+// @Summary Enroll a voiceprint
+// @Description Enroll a diarized speaker of a completed job as a named voiceprint. Speakers in later diarized jobs whose voice matches are named automatically. The job must have been diarized with speaker embeddings, which happens automatically once the workspace has a voiceprint or when speaker_embeddings is set.
+// @Tags voiceprints
+// @Accept json
+// @Produce json
+// @Param request body EnrollVoiceprintRequest true "Speaker to enroll"
+// @Success 200 {object} models.Voiceprint
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/voiceprints [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) EnrollVoiceprint(c *gin.Context) {
+	var req EnrollVoiceprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	name, ok := speakerName(req.Name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name must be between 1 and 100 characters"})
+		return
+	}
+
+	job, err := h.jobRepo.FindByID(c.Request.Context(), req.JobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription job"})
+		return
+	}
+	// A voiceprint can only be taken from a job the requester can see
+	if !models.WorkspaceVisible(c.Request.Context(), job.Workspace) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+		return
+	}
+
+	voiceprint, err := h.voiceprints.Enroll(c.Request.Context(), h.requestWorkspace(c), name, req.JobID, req.Speaker)
+	if err != nil {
+		if errors.Is(err, service.ErrNoSpeakerEmbedding) || errors.Is(err, service.ErrVoiceprintMismatch) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enroll voiceprint"})
+		return
+	}
+	c.JSON(http.StatusOK, voiceprint)
+}
+
+// @Summary Delete a voiceprint
+// @Description Delete a voiceprint. Speakers already named after it keep their names.
+// @Tags voiceprints
+// @Produce json
+// @Param id path string true "Voiceprint ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/voiceprints/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteVoiceprint(c *gin.Context) {
+	if err := h.speakerMappingRepo.DeleteVoiceprint(c.Request.Context(), h.requestWorkspace(c), c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Voiceprint not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete voiceprint"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Voiceprint deleted successfully"})
+}
+
+// @Summary Identify speakers by voiceprint
+// @Description Match the job's diarized speakers against the workspace's voiceprints again, for example after enrolling new voices. Matched speakers are named after their voiceprint unless they were named by hand.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription Job ID"
+// @Success 200 {array} VoiceprintMatchResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/speakers/identify [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) IdentifySpeakers(c *gin.Context) {
+	job, err := h.jobRepo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription job"})
+		return
+	}
+
+	matches, err := h.voiceprints.IdentifySpeakers(c.Request.Context(), job.ID, job.Workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to identify speakers"})
+		return
+	}
+	response := make([]VoiceprintMatchResponse, len(matches))
+	for i, match := range matches {
+		response[i] = VoiceprintMatchResponse{
+			Speaker:      match.Speaker,
+			VoiceprintID: match.Voiceprint.ID,
+			Name:         match.Voiceprint.Name,
+			Similarity:   match.Similarity,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestWorkspace returns the workspace the request's files belong to: the
+// organization's for requests acting in one, otherwise one per user for JWT
+// sessions and one per key for API key requests
+func (h *Handler) requestWorkspace(c *gin.Context) string {
+	if organizationID := c.GetString("organization_id"); organizationID != "" {
+		return models.OrganizationWorkspace(organizationID)
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		return models.UserWorkspace(userID.(uint))
+	}
+	if key, exists := c.Get("api_key"); exists {
+		if apiKey, err := h.apiKeyRepo.FindByKey(c.Request.Context(), key.(string)); err == nil {
+			return models.APIKeyWorkspace(apiKey.ID)
+		}
+	}
+	return ""
+}
+
+// workspaceUploadDir returns the request's workspace and its upload directory
+func (h *Handler) workspaceUploadDir(c *gin.Context) (string, string, error) {
+	workspace := h.requestWorkspace(c)
+	dir, err := service.WorkspaceDir(h.config.UploadDir, workspace)
+	if err != nil {
+		return "", "", err
+	}
+	return workspace, dir, nil
+}
+
+// scopedJobPath resolves a file path of job, rejecting paths outside the job's
+// upload workspace
+func (h *Handler) scopedJobPath(job *models.TranscriptionJob, path string) (string, error) {
+	return h.fileService.ScopePath(h.config.UploadDir, job.Workspace, path)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultWorkspaceChatSources = 8
+	maxWorkspaceChatSources     = 30
+)
+
+// WorkspaceChatMessage is one turn of a workspace chat conversation
+type WorkspaceChatMessage struct {
+	Role    string `json:"role" binding:"required,oneof=user assistant"`
+	Content string `json:"content" binding:"required"`
+}
+
+// WorkspaceChatRequest asks a question across all transcriptions. The conversation
+// is kept by the client; the last message must be the user's question.
+type WorkspaceChatRequest struct {
+	Model    string                 `json:"model" binding:"required"`
+	Messages []WorkspaceChatMessage `json:"messages" binding:"required,min=1,dive"`
+	Sources  int                    `json:"sources,omitempty"`
+	From     *time.Time             `json:"from,omitempty"`
+	To       *time.Time             `json:"to,omitempty"`
+}
+
+// WorkspaceChatResponse is the answer together with the passages it was based on
+type WorkspaceChatResponse struct {
+	Answer  string                   `json:"answer"`
+	Sources []service.RetrievedChunk `json:"sources"`
+}
+
+// @Summary Chat across all transcripts
+// @Description Answer a question using the most relevant passages from every completed transcription, retrieved from the embedding index. The answer cites job IDs and timestamps as [job:<id> @ HH:MM:SS]. Use from/to to limit retrieval to transcriptions created in a date range.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param request body WorkspaceChatRequest true "Conversation"
+// @Success 200 {object} WorkspaceChatResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/v1/chat/workspace [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) WorkspaceChat(c *gin.Context) {
+	var req WorkspaceChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	question := req.Messages[len(req.Messages)-1]
+	if question.Role != "user" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The last message must be from the user"})
+		return
+	}
+
+	limit := req.Sources
+	if limit <= 0 {
+		limit = defaultWorkspaceChatSources
+	}
+	if limit > maxWorkspaceChatSources {
+		limit = maxWorkspaceChatSources
+	}
+
+	svc, _, err := h.getLLMService(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workspace := h.requestWorkspace(c)
+	policy, ok := h.requireChatPolicy(c, workspace)
+	if !ok {
+		return
+	}
+	if h.refuseBannedTopic(c, policy, question.Content, "workspace", workspace) {
+		return
+	}
+	conversation := recentMessages(policy, req.Messages)
+
+	sources, err := h.embeddingIndex.Retrieve(c.Request.Context(), question.Content, service.RetrieveOptions{
+		Limit: limit,
+		From:  req.From,
+		To:    req.To,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrEmbeddingsUnavailable) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to retrieve transcript passages", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search transcripts"})
+		return
+	}
+	if len(sources) == 0 {
+		c.JSON(http.StatusOK, WorkspaceChatResponse{
+			Answer:  "No indexed transcripts are available to answer this question yet.",
+			Sources: sources,
+		})
+		return
+	}
+
+	contextWindow := policyContextWindow(c.Request.Context(), svc, req.Model, policy)
+
+	// Estimate 1 token ~= 4 chars and drop the weakest passages until the
+	// conversation fits, leaving room for the answer
+	var messages []llm.ChatMessage
+	for len(sources) > 0 {
+		messages = workspaceChatMessages(policy, sources, conversation)
+		tokens := 0
+		for _, m := range messages {
+			tokens += len(m.Content) / 4
+		}
+		if tokens <= contextWindow-1000 {
+			break
+		}
+		sources = sources[:len(sources)-1]
+	}
+	if len(sources) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Conversation is too long for this model's context window"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	resp, err := svc.ChatCompletion(ctx, req.Model, messages, 0.0)
+	if err != nil || resp == nil || len(resp.Choices) == 0 {
+		logger.Error("Workspace chat completion failed", "model", req.Model, "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "The LLM did not return an answer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, WorkspaceChatResponse{
+		Answer:  resp.Choices[0].Message.Content,
+		Sources: sources,
+	})
+}
+
+// workspaceChatMessages builds the system prompt with the workspace's chat
+// policy and the retrieved passages, followed by the conversation
+func workspaceChatMessages(policy *models.ChatPolicy, sources []service.RetrievedChunk, conversation []WorkspaceChatMessage) []llm.ChatMessage {
+	var sb strings.Builder
+	sb.WriteString("You are a helpful assistant answering questions about the user's recorded transcripts. ")
+	sb.WriteString("Answer using only the excerpts below. Cite every statement with the excerpt's job ID and timestamp ")
+	sb.WriteString("exactly as given, e.g. [job:<id> @ HH:MM:SS]. If the excerpts do not contain the answer, say so.\n\nExcerpts:\n")
+	for _, src := range sources {
+		title := ""
+		if src.Title != nil {
+			title = *src.Title
+		}
+		fmt.Fprintf(&sb, "\n[job:%s @ %s] %q, recorded %s\n%s\n",
+			src.JobID, formatTime(src.StartTime), title, src.JobCreatedAt.Format("2006-01-02"), src.Text)
+	}
+
+	messages := []llm.ChatMessage{{Role: "system", Content: withPolicyInstructions(policy, sb.String())}}
+	for _, m := range conversation {
+		messages = append(messages, llm.ChatMessage{Role: m.Role, Content: m.Content})
+	}
+	return messages
+}
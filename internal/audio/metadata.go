@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// creationTimeTags are the metadata tags recorders store their start time in,
+// in order of preference. QuickTime's creationdate keeps the local offset.
+var creationTimeTags = []string{"com.apple.quicktime.creationdate", "creation_time", "date"}
+
+// creationTimeLayouts are the layouts the creation time tags are written in
+var creationTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.000000Z",
+	"2006-01-02 15:04:05",
+}
+
+// ProbeCreationTime reads when a media file was recorded from its container
+// metadata using ffprobe. It returns nil when the file carries no usable time.
+func ProbeCreationTime(ctx context.Context, path string) (*time.Time, error) {
+	output, err := exec.CommandContext(ctx, "ffprobe", "-v", "quiet", "-print_format", "json",
+		"-show_entries", "format_tags:stream_tags", path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe struct {
+		Format struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"format"`
+		Streams []struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	tagSets := []map[string]string{probe.Format.Tags}
+	for _, stream := range probe.Streams {
+		tagSets = append(tagSets, stream.Tags)
+	}
+	for _, tag := range creationTimeTags {
+		for _, tags := range tagSets {
+			if t, ok := ParseCreationTime(tags[tag]); ok {
+				return &t, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ParseCreationTime parses a creation time tag. Placeholder dates that devices
+// without a clock write, such as the Unix or QuickTime epoch, are rejected.
+func ParseCreationTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range creationTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if t.Year() < 1980 {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
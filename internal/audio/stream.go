@@ -0,0 +1,42 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// StreamFormats are the formats audio can be transcoded to for playback in
+// browsers, with their content types
+var StreamFormats = map[string]string{
+	"opus": "audio/ogg",
+	"mp3":  "audio/mpeg",
+}
+
+// streamCodecArgs are the ffmpeg encoder and muxer arguments of each stream format
+var streamCodecArgs = map[string][]string{
+	"opus": {"-c:a", "libopus", "-b:a", "64k", "-f", "ogg"},
+	"mp3":  {"-c:a", "libmp3lame", "-b:a", "128k", "-f", "mp3"},
+}
+
+// Transcode writes the first audio track of a file to w in a stream format as
+// ffmpeg encodes it, so long recordings are never held in memory. The output
+// is not seekable; its length is only known once it has been written.
+func Transcode(ctx context.Context, path, format string, w io.Writer) error {
+	codec, ok := streamCodecArgs[format]
+	if !ok {
+		return fmt.Errorf("unsupported stream format %q", format)
+	}
+	args := append([]string{"-hide_banner", "-loglevel", "error", "-i", path, "-map", "0:a:0", "-vn"}, codec...)
+	cmd := exec.CommandContext(ctx, "ffmpeg", append(args, "pipe:1")...)
+	var stderr bytes.Buffer
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("transcoding to %s failed: %w: %s", format, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
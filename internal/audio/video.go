@@ -0,0 +1,56 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"scriberr/pkg/logger"
+)
+
+// VideoExtensions are the video containers accepted for transcription
+var VideoExtensions = map[string]bool{
+	".mp4": true, ".m4v": true, ".mkv": true, ".mov": true, ".webm": true,
+}
+
+// IsVideo reports whether a file extension is an accepted video container
+func IsVideo(ext string) bool {
+	return VideoExtensions[strings.ToLower(ext)]
+}
+
+// ExtractAudio writes the first audio track of a video as mp3
+func ExtractAudio(ctx context.Context, videoPath, audioPath string) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-i", videoPath,
+		"-map", "0:a:0", "-vn", "-acodec", "libmp3lame", "-q:a", "2", "-y", audioPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logger.Error("FFmpeg audio extraction failed", "video", videoPath, "output", string(output), "error", err)
+		return fmt.Errorf("audio extraction failed: %w", err)
+	}
+	return nil
+}
+
+// BurnSubtitles writes the video as mp4 with the subtitles file drawn onto its
+// frames. The video is re-encoded, so this takes about as long as playing it
+// back on a slow machine.
+func BurnSubtitles(ctx context.Context, videoPath, subtitlesPath, outputPath string) error {
+	videoPath, err := filepath.Abs(videoPath)
+	if err != nil {
+		return err
+	}
+	if outputPath, err = filepath.Abs(outputPath); err != nil {
+		return err
+	}
+	// ffmpeg runs in the subtitles' directory so the filter names the file
+	// without the escaping its option syntax needs for full paths
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-i", videoPath,
+		"-vf", "subtitles="+filepath.Base(subtitlesPath), "-c:v", "libx264", "-preset", "veryfast", "-crf", "20",
+		"-c:a", "aac", "-movflags", "+faststart", "-y", outputPath)
+	cmd.Dir = filepath.Dir(subtitlesPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logger.Error("FFmpeg subtitle burn-in failed", "video", videoPath, "output", string(output), "error", err)
+		return fmt.Errorf("subtitle burn-in failed: %w", err)
+	}
+	return nil
+}
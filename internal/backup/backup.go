@@ -0,0 +1,291 @@
+// Package backup snapshots the database and transcript outputs into a
+// .tar.gz archive, restores them from one, and takes scheduled backups that can
+// be copied to S3 and pruned by a retention policy.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Archive layout
+const (
+	manifestName   = "manifest.json"
+	databaseName   = "scriberr.db"
+	transcriptsDir = "transcripts"
+	uploadsDir     = "uploads"
+)
+
+// FormatVersion is the archive layout version written to the manifest
+const FormatVersion = 1
+
+// ErrInvalidArchive is returned for archives that are not Scriberr backups
+var ErrInvalidArchive = errors.New("not a Scriberr backup archive")
+
+// Manifest describes a backup archive
+type Manifest struct {
+	FormatVersion  int       `json:"format_version"`
+	AppVersion     string    `json:"app_version"`
+	CreatedAt      time.Time `json:"created_at"`
+	IncludeUploads bool      `json:"include_uploads"`
+	Files          int       `json:"files"`
+}
+
+// Paths are where a backup is taken from and restored to
+type Paths struct {
+	DatabasePath   string
+	TranscriptsDir string
+	UploadDir      string
+}
+
+// Options select what a backup holds
+type Options struct {
+	AppVersion string
+	// IncludeUploads adds the uploaded audio, which is usually far larger than
+	// everything else
+	IncludeUploads bool
+}
+
+// Create writes a backup of db and the directories in paths to w. The database
+// is snapshotted with VACUUM INTO, so the server can keep running.
+func Create(db *gorm.DB, paths Paths, opts Options, w io.Writer) (*Manifest, error) {
+	snapshotDir, err := os.MkdirTemp(filepath.Dir(paths.DatabasePath), ".backup-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(snapshotDir)
+	snapshot := filepath.Join(snapshotDir, databaseName)
+	if err := db.Exec("VACUUM INTO ?", snapshot).Error; err != nil {
+		return nil, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	manifest := &Manifest{
+		FormatVersion:  FormatVersion,
+		AppVersion:     opts.AppVersion,
+		CreatedAt:      time.Now().UTC(),
+		IncludeUploads: opts.IncludeUploads,
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := addFile(tw, snapshot, databaseName); err != nil {
+		return nil, err
+	}
+	manifest.Files++
+	dirs := map[string]string{transcriptsDir: paths.TranscriptsDir}
+	if opts.IncludeUploads {
+		dirs[uploadsDir] = paths.UploadDir
+	}
+	for _, name := range []string{transcriptsDir, uploadsDir} {
+		dir, ok := dirs[name]
+		if !ok || dir == "" {
+			continue
+		}
+		n, err := addDir(tw, dir, name)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files += n
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0644, Size: int64(len(data)), ModTime: manifest.CreatedAt}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func addFile(tw *tar.Writer, src, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime()}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", src, err)
+	}
+	return nil
+}
+
+// addDir archives the regular files under dir as prefix/<relative path>
+func addDir(tw *tar.Writer, dir, prefix string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if err := addFile(tw, p, path.Join(prefix, filepath.ToSlash(rel))); err != nil {
+			// Files may be removed while the backup runs
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// Restore replaces the database and directories in paths with the archive's
+// contents. The server must not be using the database meanwhile. Directories
+// the archive does not hold are left as they are.
+func Restore(r io.Reader, paths Paths) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	defer gz.Close()
+
+	// Unpack next to the targets first, so a broken archive changes nothing
+	staging, err := os.MkdirTemp(filepath.Dir(paths.DatabasePath), ".restore-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	var manifest *Manifest
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("%w: bad manifest: %v", ErrInvalidArchive, err)
+			}
+			continue
+		}
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("%w: unsafe path %q", ErrInvalidArchive, header.Name)
+		}
+		if name != databaseName && !strings.HasPrefix(name, transcriptsDir+"/") && !strings.HasPrefix(name, uploadsDir+"/") {
+			continue
+		}
+		if err := extractFile(tr, filepath.Join(staging, filepath.FromSlash(name))); err != nil {
+			return nil, err
+		}
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: missing manifest", ErrInvalidArchive)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("backup format %d is newer than this version supports", manifest.FormatVersion)
+	}
+	if _, err := os.Stat(filepath.Join(staging, databaseName)); err != nil {
+		return nil, fmt.Errorf("%w: missing database", ErrInvalidArchive)
+	}
+
+	// The old write-ahead log would be replayed into the restored database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(paths.DatabasePath + suffix); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if err := os.Rename(filepath.Join(staging, databaseName), paths.DatabasePath); err != nil {
+		return nil, fmt.Errorf("failed to restore database: %w", err)
+	}
+	moves := map[string]string{transcriptsDir: paths.TranscriptsDir}
+	if manifest.IncludeUploads {
+		moves[uploadsDir] = paths.UploadDir
+	}
+	for name, target := range moves {
+		if err := replaceDir(filepath.Join(staging, name), target); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+func extractFile(r io.Reader, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	return f.Close()
+}
+
+// replaceDir swaps target for src, keeping the old directory until the swap is done
+func replaceDir(src, target string) error {
+	if target == "" {
+		return nil
+	}
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		if err := os.MkdirAll(src, 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	old := target + ".pre-restore"
+	if err := os.RemoveAll(old); err != nil {
+		return err
+	}
+	if err := os.Rename(target, old); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move aside %s: %w", target, err)
+	}
+	if err := os.Rename(src, target); err != nil {
+		os.Rename(old, target)
+		return fmt.Errorf("failed to restore %s: %w", target, err)
+	}
+	return os.RemoveAll(old)
+}
@@ -0,0 +1,187 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/config"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type note struct {
+	ID   uint
+	Body string
+}
+
+func openDB(t *testing.T, path string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	src := t.TempDir()
+	paths := Paths{
+		DatabasePath:   filepath.Join(src, "scriberr.db"),
+		TranscriptsDir: filepath.Join(src, "transcripts"),
+		UploadDir:      filepath.Join(src, "uploads"),
+	}
+	db := openDB(t, paths.DatabasePath)
+	require.NoError(t, db.AutoMigrate(&note{}))
+	require.NoError(t, db.Create(&note{Body: "kept"}).Error)
+	require.NoError(t, os.MkdirAll(filepath.Join(paths.TranscriptsDir, "job-1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(paths.TranscriptsDir, "job-1", "transcript.json"), []byte(`{"text":"hi"}`), 0644))
+	require.NoError(t, os.MkdirAll(paths.UploadDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(paths.UploadDir, "audio.wav"), []byte("audio"), 0644))
+
+	var archive bytes.Buffer
+	manifest, err := Create(db, paths, Options{AppVersion: "1.2.3"}, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.Files, "uploads are left out by default")
+
+	dst := t.TempDir()
+	target := Paths{
+		DatabasePath:   filepath.Join(dst, "scriberr.db"),
+		TranscriptsDir: filepath.Join(dst, "transcripts"),
+		UploadDir:      filepath.Join(dst, "uploads"),
+	}
+	// What is being restored over is replaced, and a stale WAL is dropped
+	require.NoError(t, os.MkdirAll(target.TranscriptsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(target.TranscriptsDir, "stale.json"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(target.DatabasePath+"-wal", []byte("stale"), 0644))
+
+	restored, err := Restore(bytes.NewReader(archive.Bytes()), target)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", restored.AppVersion)
+
+	data, err := os.ReadFile(filepath.Join(target.TranscriptsDir, "job-1", "transcript.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"text":"hi"}`, string(data))
+	assert.NoFileExists(t, filepath.Join(target.TranscriptsDir, "stale.json"))
+	assert.NoFileExists(t, target.DatabasePath+"-wal")
+	assert.NoDirExists(t, target.UploadDir)
+
+	var notes []note
+	require.NoError(t, openDB(t, target.DatabasePath).Find(&notes).Error)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "kept", notes[0].Body)
+}
+
+func TestRestoreRejectsBadArchives(t *testing.T) {
+	dir := t.TempDir()
+	paths := Paths{DatabasePath: filepath.Join(dir, "scriberr.db"), TranscriptsDir: filepath.Join(dir, "transcripts")}
+	require.NoError(t, os.WriteFile(paths.DatabasePath, []byte("original"), 0644))
+
+	build := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, body := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body))}))
+			_, err := tw.Write([]byte(body))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	cases := map[string][]byte{
+		"not gzip":         []byte("plain text"),
+		"no manifest":      build(map[string]string{databaseName: "db"}),
+		"no database":      build(map[string]string{manifestName: `{"format_version":1}`}),
+		"path traversal":   build(map[string]string{manifestName: `{"format_version":1}`, databaseName: "db", "transcripts/../../escape": "x"}),
+		"absolute path":    build(map[string]string{manifestName: `{"format_version":1}`, databaseName: "db", "/etc/escape": "x"}),
+		"corrupt manifest": build(map[string]string{manifestName: "{", databaseName: "db"}),
+	}
+	for name, archive := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Restore(bytes.NewReader(archive), paths)
+			assert.ErrorIs(t, err, ErrInvalidArchive)
+		})
+	}
+
+	_, err := Restore(bytes.NewReader(build(map[string]string{manifestName: `{"format_version":99}`, databaseName: "db"})), paths)
+	assert.ErrorContains(t, err, "newer")
+
+	// Nothing was touched
+	data, err := os.ReadFile(paths.DatabasePath)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "escape"))
+}
+
+func TestServiceRetentionAndStagedRestore(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		DatabasePath:    filepath.Join(dir, "scriberr.db"),
+		TranscriptsDir:  filepath.Join(dir, "transcripts"),
+		BackupDir:       filepath.Join(dir, "backups"),
+		BackupRetention: 2,
+	}
+	db := openDB(t, cfg.DatabasePath)
+	require.NoError(t, db.AutoMigrate(&note{}))
+	svc := NewService(cfg, db, "test")
+
+	// Older archives from earlier runs
+	require.NoError(t, os.MkdirAll(cfg.BackupDir, 0755))
+	for _, stamp := range []string{"20240101T000000Z", "20240102T000000Z"} {
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.BackupDir, archivePrefix+stamp+archiveSuffix), []byte("old"), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.BackupDir, "unrelated.txt"), []byte("x"), 0644))
+
+	info, err := svc.Run(context.Background(), true)
+	require.NoError(t, err)
+	assert.Empty(t, info.Location, "nothing is uploaded without an S3 URI")
+	assert.WithinDuration(t, time.Now(), info.CreatedAt, time.Minute)
+
+	backups, err := svc.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, info.Name, backups[0].Name)
+	assert.Equal(t, archivePrefix+"20240102T000000Z"+archiveSuffix, backups[1].Name)
+	assert.FileExists(t, filepath.Join(cfg.BackupDir, "unrelated.txt"))
+
+	_, err = svc.Path("../scriberr.db")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// A staged restore is applied on the next startup
+	_, err = svc.StageRestore(bytes.NewReader([]byte("not a backup")))
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+	path, err := svc.Path(info.Name)
+	require.NoError(t, err)
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	_, err = svc.StageRestore(f)
+	f.Close()
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&note{Body: "after backup"}).Error)
+
+	manifest, err := ApplyPendingRestore(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+	assert.NoFileExists(t, filepath.Join(cfg.BackupDir, PendingRestoreName))
+	var count int64
+	require.NoError(t, openDB(t, cfg.DatabasePath).Model(&note{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	manifest, err = ApplyPendingRestore(cfg)
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+}
@@ -0,0 +1,391 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/pkg/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"gorm.io/gorm"
+)
+
+const (
+	archivePrefix = "scriberr-backup-"
+	archiveSuffix = ".tar.gz"
+	// archiveTimeFormat sorts lexically in creation order
+	archiveTimeFormat = "20060102T150405Z"
+	// PendingRestoreName is the archive staged in the backup directory to be
+	// restored at the next startup
+	PendingRestoreName = "pending-restore" + archiveSuffix
+)
+
+// ErrNotFound is returned for backups that do not exist
+var ErrNotFound = errors.New("backup not found")
+
+// Info describes a backup archive in the backup directory
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	// Location is the s3:// URI the archive was copied to, if any
+	Location string `json:"location,omitempty"`
+}
+
+// Service takes backups on demand and on a schedule, copies them to S3 when
+// configured, and prunes old ones
+type Service struct {
+	db        *gorm.DB
+	paths     Paths
+	dir       string
+	interval  time.Duration
+	retention int
+	s3URI     string
+	opts      Options
+
+	s3Once   sync.Once
+	s3Client *s3.Client
+
+	// mu serialises backups so retention never prunes one being written
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewService creates a backup service for db and the directories in cfg
+func NewService(cfg *config.Config, db *gorm.DB, version string) *Service {
+	return &Service{
+		db:        db,
+		paths:     PathsFromConfig(cfg),
+		dir:       cfg.BackupDir,
+		interval:  time.Duration(cfg.BackupIntervalHours) * time.Hour,
+		retention: cfg.BackupRetention,
+		s3URI:     cfg.BackupS3URI,
+		opts:      Options{AppVersion: version, IncludeUploads: cfg.BackupIncludeUploads},
+		stop:      make(chan struct{}),
+	}
+}
+
+// PathsFromConfig returns the paths a backup covers
+func PathsFromConfig(cfg *config.Config) Paths {
+	return Paths{DatabasePath: cfg.DatabasePath, TranscriptsDir: cfg.TranscriptsDir, UploadDir: cfg.UploadDir}
+}
+
+// Start takes a backup every configured interval; it does nothing when
+// scheduled backups are off
+func (s *Service) Start() {
+	if s.interval <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts scheduled backups and waits for a running one to finish
+func (s *Service) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if _, err := s.Run(context.Background(), s.s3URI != ""); err != nil {
+				logger.Error("Scheduled backup failed", "error", err)
+			}
+		}
+	}
+}
+
+// Run takes a backup into the backup directory, copies it to S3 when upload is
+// set and S3 is configured, and prunes archives beyond the retention count
+func (s *Service) Run(ctx context.Context, upload bool) (*Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	now := time.Now().UTC()
+	name := archivePrefix + now.Format(archiveTimeFormat) + archiveSuffix
+	target := filepath.Join(s.dir, name)
+
+	tmp, err := os.CreateTemp(s.dir, "."+name+".*.part")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	manifest, err := Create(s.db, s.paths, s.opts, tmp)
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{Name: name, Size: stat.Size(), CreatedAt: manifest.CreatedAt}
+	logger.Info("Backup created", "path", target, "files", manifest.Files, "size", info.Size)
+
+	if upload && s.s3URI != "" {
+		uri := s.s3URI + "/" + name
+		if err := s.Upload(ctx, target, uri); err != nil {
+			return info, fmt.Errorf("backup created but upload failed: %w", err)
+		}
+		info.Location = uri
+	}
+	s.prune(ctx, upload)
+	return info, nil
+}
+
+// List returns the backups in the backup directory, newest first
+func (s *Service) List() ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	backups := []Info{}
+	for _, entry := range entries {
+		created, ok := parseArchiveName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Info{Name: entry.Name(), Size: stat.Size(), CreatedAt: created})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups, nil
+}
+
+// Path returns the path of a backup in the backup directory
+func (s *Service) Path(name string) (string, error) {
+	if _, ok := parseArchiveName(name); !ok {
+		return "", ErrNotFound
+	}
+	p := filepath.Join(s.dir, name)
+	if _, err := os.Stat(p); err != nil {
+		return "", ErrNotFound
+	}
+	return p, nil
+}
+
+// StageRestore checks the archive in r and saves it to be restored at the next
+// startup, as the database cannot be replaced while the server uses it
+func (s *Service) StageRestore(r io.Reader) (*Manifest, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, "."+PendingRestoreName+".*.part")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	manifest, err := Inspect(tmp)
+	tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, PendingRestoreName)); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Inspect reads an archive's manifest, checking it holds a database
+func Inspect(r io.Reader) (*Manifest, error) {
+	staging, err := os.MkdirTemp("", "scriberr-inspect-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+	// Restoring into a scratch directory validates the whole archive
+	return Restore(r, Paths{DatabasePath: filepath.Join(staging, databaseName)})
+}
+
+// ApplyPendingRestore restores the archive staged by StageRestore, if any. It
+// must run before the database is opened.
+func ApplyPendingRestore(cfg *config.Config) (*Manifest, error) {
+	pending := filepath.Join(cfg.BackupDir, PendingRestoreName)
+	f, err := os.Open(pending)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := Restore(f, PathsFromConfig(cfg))
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	return manifest, os.Remove(pending)
+}
+
+// prune removes archives beyond the retention count, locally and in S3
+func (s *Service) prune(ctx context.Context, remote bool) {
+	if s.retention <= 0 {
+		return
+	}
+	backups, err := s.List()
+	if err != nil {
+		logger.Warn("Failed to list backups for retention", "error", err)
+		return
+	}
+	for _, old := range backups[min(s.retention, len(backups)):] {
+		if err := os.Remove(filepath.Join(s.dir, old.Name)); err != nil {
+			logger.Warn("Failed to remove old backup", "name", old.Name, "error", err)
+		}
+	}
+	if remote && s.s3URI != "" {
+		if err := s.pruneS3(ctx); err != nil {
+			logger.Warn("Failed to prune backups in S3", "error", err)
+		}
+	}
+}
+
+func (s *Service) pruneS3(ctx context.Context) error {
+	bucket, prefix, err := parseS3Prefix(s.s3URI)
+	if err != nil {
+		return err
+	}
+	client := s.client()
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(path.Join(prefix, archivePrefix)),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, obj := range page.Contents {
+			if _, ok := parseArchiveName(path.Base(aws.ToString(obj.Key))); ok {
+				keys = append(keys, aws.ToString(obj.Key))
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	for _, key := range keys[min(s.retention, len(keys)):] {
+		if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upload copies the archive at src to an s3:// URI
+func (s *Service) Upload(ctx context.Context, src, uri string) error {
+	bucket, key, err := parseS3Prefix(uri)
+	if err != nil || key == "" {
+		return fmt.Errorf("invalid S3 URI: %s", uri)
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = s.client().PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        f,
+		ContentType: aws.String("application/gzip"),
+	})
+	return err
+}
+
+// Fetch downloads the archive at an s3:// URI to dest
+func (s *Service) Fetch(ctx context.Context, uri, dest string) error {
+	bucket, key, err := parseS3Prefix(uri)
+	if err != nil || key == "" {
+		return fmt.Errorf("invalid S3 URI: %s", uri)
+	}
+	result, err := s.client().GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return err
+	}
+	defer result.Body.Close()
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, result.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *Service) client() *s3.Client {
+	s.s3Once.Do(func() {
+		cfg, _ := awsconfig.LoadDefaultConfig(context.Background())
+		s.s3Client = s3.NewFromConfig(cfg)
+	})
+	return s.s3Client
+}
+
+// parseArchiveName returns the creation time encoded in a backup's name
+func parseArchiveName(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, archivePrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	stamp, ok = strings.CutSuffix(stamp, archiveSuffix)
+	if !ok {
+		return time.Time{}, false
+	}
+	created, err := time.Parse(archiveTimeFormat, stamp)
+	return created, err == nil
+}
+
+// parseS3Prefix splits s3://bucket/key into bucket and key; the key may be empty
+func parseS3Prefix(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok || rest == "" {
+		return "", "", fmt.Errorf("invalid S3 URI: %s", uri)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("invalid S3 URI: %s", uri)
+	}
+	return bucket, key, nil
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var benchmarkCmd = &cobra.Command{
+	Use:   "benchmark [folder]",
+	Short: "Compare transcription adapters on a reference audio set",
+	Long: `Upload the audio files of a folder, each with its ground truth in a .txt file
+of the same name (e.g. call1.wav and call1.txt), to be transcribed by every
+adapter given with --models, then wait for the comparison report. Requires an
+admin account.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runBenchmark,
+}
+
+var (
+	benchmarkModels  string
+	benchmarkName    string
+	benchmarkProfile string
+	benchmarkNoWait  bool
+)
+
+// benchmarkPollInterval is how often a running benchmark is checked
+const benchmarkPollInterval = 10 * time.Second
+
+func init() {
+	rootCmd.AddCommand(benchmarkCmd)
+	benchmarkCmd.Flags().StringVarP(&benchmarkModels, "models", "m", "", "Comma-separated transcription adapters to compare, e.g. whisperx,openai_whisper")
+	benchmarkCmd.Flags().StringVar(&benchmarkName, "name", "", "Benchmark name (default: the folder's name)")
+	benchmarkCmd.Flags().StringVar(&benchmarkProfile, "profile", "", "Profile whose parameters the adapters are called with")
+	benchmarkCmd.Flags().BoolVar(&benchmarkNoWait, "no-wait", false, "Print the benchmark ID instead of waiting for the report")
+	benchmarkCmd.MarkFlagRequired("models")
+}
+
+// benchmarkReport is the part of a benchmark report the CLI prints
+type benchmarkReport struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Currency string `json:"currency"`
+	Summary  []struct {
+		ModelID                string   `json:"model_id"`
+		Runs                   int      `json:"runs"`
+		Completed              int      `json:"completed"`
+		Failed                 int      `json:"failed"`
+		WER                    *float64 `json:"wer"`
+		CER                    *float64 `json:"cer"`
+		MeanProcessingDuration *int64   `json:"mean_processing_duration"`
+		RealTimeFactor         *float64 `json:"real_time_factor"`
+		Cost                   *float64 `json:"cost"`
+	} `json:"summary"`
+}
+
+func runBenchmark(cmd *cobra.Command, args []string) {
+	folder, err := filepath.Abs(args[0])
+	if err != nil {
+		log.Fatalf("Failed to get absolute path: %v", err)
+	}
+	samples, err := benchmarkSamples(folder)
+	if err != nil {
+		log.Fatal(err)
+	}
+	name := benchmarkName
+	if name == "" {
+		name = filepath.Base(folder)
+	}
+
+	fmt.Printf("Uploading %d samples...\n", len(samples))
+	id, err := CreateBenchmark(name, benchmarkModels, benchmarkProfile, samples)
+	if err != nil {
+		log.Fatalf("Failed to start benchmark: %v", err)
+	}
+	if benchmarkNoWait {
+		fmt.Println(id)
+		return
+	}
+
+	fmt.Printf("Benchmark %s started, waiting for the report...\n", id)
+	for {
+		report, err := GetBenchmark(id)
+		if err != nil {
+			log.Fatalf("Failed to get benchmark: %v", err)
+		}
+		if report.Status == "completed" {
+			printBenchmarkReport(report)
+			return
+		}
+		time.Sleep(benchmarkPollInterval)
+	}
+}
+
+// benchmarkSamples returns the audio files of folder, each of which must have
+// a .txt reference beside it
+func benchmarkSamples(folder string) ([]string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", folder, err)
+	}
+	var samples []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isAudioFile(strings.ToLower(filepath.Ext(entry.Name()))) {
+			continue
+		}
+		path := filepath.Join(folder, entry.Name())
+		if _, err := os.Stat(benchmarkReferencePath(path)); err != nil {
+			return nil, fmt.Errorf("no reference transcript for %s: %w", entry.Name(), err)
+		}
+		samples = append(samples, path)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no audio files in %s", folder)
+	}
+	return samples, nil
+}
+
+// benchmarkReferencePath is where the ground truth of an audio file is read from
+func benchmarkReferencePath(audioPath string) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".txt"
+}
+
+// CreateBenchmark uploads samples and their references to be transcribed by
+// modelIDs, returning the benchmark's ID
+func CreateBenchmark(name, modelIDs, profile string, samples []string) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fields := map[string]string{"name": name, "model_ids": modelIDs, "profile_name": profile}
+	for field, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(field, value); err != nil {
+			return "", fmt.Errorf("failed to write %s field: %w", field, err)
+		}
+	}
+	for _, sample := range samples {
+		if err := addFormFile(writer, "audio", sample); err != nil {
+			return "", err
+		}
+		if err := addFormFile(writer, "reference", benchmarkReferencePath(sample)); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close writer: %w", err)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := apiRequest("POST", "/api/v1/admin/benchmarks", writer.FormDataContentType(), body, http.StatusAccepted, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// GetBenchmark fetches a benchmark's report
+func GetBenchmark(id string) (*benchmarkReport, error) {
+	var report benchmarkReport
+	if err := apiRequest("GET", "/api/v1/admin/benchmarks/"+id, "", nil, http.StatusOK, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// addFormFile copies a file into a multipart form
+func addFormFile(writer *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	part, err := writer.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return nil
+}
+
+// apiRequest sends an authenticated request to the server and decodes its
+// JSON response into out, failing unless it answers with status
+func apiRequest(method, path, contentType string, body io.Reader, status int, out interface{}) error {
+	config := GetConfig()
+	if config.ServerURL == "" {
+		return fmt.Errorf("server URL not configured. Please run 'scriberr login' or 'scriberr install'")
+	}
+	if config.Token == "" {
+		return fmt.Errorf("not logged in (token missing). Please run 'scriberr login'")
+	}
+	req, err := http.NewRequest(method, config.ServerURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Bearer "+config.Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// printBenchmarkReport prints the summary of each adapter as a table
+func printBenchmarkReport(report *benchmarkReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ADAPTER\tRUNS\tFAILED\tWER\tCER\tMEAN LATENCY\tRTF\tCOST")
+	for _, s := range report.Summary {
+		wer, cer, latency, rtf, cost := "-", "-", "-", "-", "-"
+		if s.WER != nil {
+			wer = fmt.Sprintf("%.2f%%", *s.WER*100)
+		}
+		if s.CER != nil {
+			cer = fmt.Sprintf("%.2f%%", *s.CER*100)
+		}
+		if s.MeanProcessingDuration != nil {
+			latency = (time.Duration(*s.MeanProcessingDuration) * time.Millisecond).String()
+		}
+		if s.RealTimeFactor != nil {
+			rtf = fmt.Sprintf("%.2f", *s.RealTimeFactor)
+		}
+		if s.Cost != nil {
+			cost = strings.TrimSpace(fmt.Sprintf("%.4f %s", *s.Cost, report.Currency))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", s.ModelID, s.Runs, s.Failed, wer, cer, latency, rtf, cost)
+	}
+	w.Flush()
+}
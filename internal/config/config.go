@@ -1,13 +1,17 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"scriberr/pkg/logger"
 
@@ -22,10 +26,17 @@ type Config struct {
 
 	// Database configuration
 	DatabasePath string
+	// Read replica of the database, serving list, search and export queries
+	// (empty: everything reads from the primary)
+	DatabaseReplicaPath string
 
 	// JWT configuration
 	JWTSecret string
 
+	// Key signing chain-of-custody manifests, so anyone holding the published
+	// public key can verify them. Never serialized, not even redacted.
+	CustodySigningKey ed25519.PrivateKey `json:"-"`
+
 	// File storage
 	UploadDir      string
 	TranscriptsDir string
@@ -34,8 +45,186 @@ type Config struct {
 	UVPath      string
 	WhisperXEnv string
 
+	// Adapters registered when the admin API has not configured them: the
+	// local adapters (any ENABLE_DEFAULT_ADAPTERS value), and whisperx served
+	// by a WhisperX server instead of the local environment
+	EnableDefaultAdapters bool
+	LocalWhisperXBaseURL  string
+
 	// OpenAI configuration
 	OpenAIAPIKey string
+
+	// Secret stores that adapter credentials and Hugging Face tokens may
+	// reference instead of holding the secret (aws-sm://, vault://,
+	// docker-secret://); referenced secrets are fetched again after
+	// SecretsRefreshMinutes
+	SecretsRefreshMinutes int
+	VaultAddr             string
+	VaultToken            string
+	SecretsDir            string
+
+	// Remote media ingestion (yt-dlp)
+	YtDlpTimeoutMinutes int
+	YtDlpMaxFileSize    string
+
+	// RSS/podcast feed subscriptions
+	FeedPollIntervalMinutes int
+
+	// Transcript embedding index for workspace chat (empty model: provider default)
+	EmbeddingModel string
+
+	// Browser recording sessions: the most a recording may hold (0: unlimited),
+	// and how long an unfinalized session may sit idle before it is removed
+	// (0: kept until deleted)
+	RecordingMaxMB           int
+	RecordingSessionTTLHours int
+
+	// Lifetime of signed audio playback URLs
+	PlaybackURLTTLSeconds int
+	// Redirect audio requests for S3-stored audio to a presigned URL rather
+	// than downloading and serving it
+	AudioS3Redirect bool
+
+	// Scheme and host clients reach the server at, e.g. https://scriberr.example.com,
+	// used in links the server hands out. Empty: playback links are relative.
+	PublicBaseURL string
+
+	// Upload scanning before processing (mode: "", clamav or http)
+	ScanMode           string
+	ClamAVAddress      string
+	ScanHTTPURL        string
+	ScanTimeoutSeconds int
+
+	// Confidence review: transcript segments whose words score below
+	// ReviewConfidenceThreshold on average are flagged (0: none), and jobs with
+	// at least ReviewMinFlaggedSegments of them need review (0: none do)
+	ReviewConfidenceThreshold float64
+	ReviewMinFlaggedSegments  int
+
+	// Minimum cosine similarity for a diarized speaker to be named after an
+	// enrolled voiceprint
+	VoiceprintMatchThreshold float64
+
+	// Quick transcription cleanup: temp audio and results are deleted once past
+	// the retention period, and oldest first while the directory is over its
+	// size cap (0: unlimited)
+	QuickRetentionMinutes       int
+	QuickMaxStorageMB           int
+	QuickCleanupIntervalMinutes int
+
+	// Cost model: estimated cost per minute of audio of each adapter, by model
+	// ID (e.g. openai_whisper=0.006), in CostCurrency; unpriced adapters are free
+	AdapterCostPerMinute map[string]float64
+	CostCurrency         string
+
+	// Local jobs wait while the GPU cannot fit their models with
+	// GPUMemoryHeadroomMB to spare, or while the load per CPU is at least
+	// MaxLoadPerCPU (0: ignored), unless no other local job is running
+	GPUMemoryHeadroomMB int
+	MaxLoadPerCPU       float64
+
+	// Name a worker process claims jobs under in the shared database, so it can
+	// tell its own interrupted jobs from those of other workers (empty: host name)
+	WorkerID string
+
+	// API v1 phase-out: from APIV1DeprecatedAt, v1 responses announce that v1
+	// is deprecated in favour of v2, and from APIV1SunsetAt v1 answers 410 Gone
+	// (zero: not announced)
+	APIV1DeprecatedAt time.Time
+	APIV1SunsetAt     time.Time
+
+	// Fast path for clips of up to FastMaxSeconds: transcribed synchronously by
+	// the small FastModel, warmed up at startup unless FastWarmup is off.
+	// FastTimeoutSeconds bounds a synchronous transcription.
+	FastModel          string
+	FastMaxSeconds     int
+	FastWarmup         bool
+	FastTimeoutSeconds int
+
+	// Shadow evaluation: this percentage of completed jobs is also transcribed in
+	// the background by a candidate adapter, for offline comparison (0: disabled)
+	ShadowModelID string
+	ShadowPercent float64
+
+	// RNNoiseModelPath is the RNNoise model file ffmpeg's arnndn filter loads
+	// for profiles that denoise their audio; without one denoising is skipped
+	RNNoiseModelPath string
+
+	// Long-audio chunking: audio of at least ChunkMinMinutes (0: disabled) is
+	// split in pauses into chunks of about ChunkSeconds overlapping by
+	// ChunkOverlapSeconds, and up to ChunkWorkers are transcribed at once
+	ChunkMinMinutes     int
+	ChunkSeconds        int
+	ChunkOverlapSeconds int
+	ChunkWorkers        int
+
+	// Request rate limits in requests per minute (0: unlimited). API keys with a
+	// limit of their own use it instead of the default.
+	RateLimitPerIP     int
+	RateLimitPerAPIKey int
+
+	// Proxies (IPs or CIDRs) whose X-Forwarded-For is trusted for the client IP.
+	// None by default, so clients cannot pick their own IP.
+	TrustedProxies []string
+
+	// Audio minutes each workspace may transcribe per calendar month (0: unlimited)
+	MonthlyQuotaMinutes int
+
+	// OpenTelemetry trace export over OTLP/HTTP, read from the standard OTEL_*
+	// variables. Tracing is off without an endpoint.
+	OTLPEndpoint       string
+	OTLPTracesEndpoint string
+	OTLPHeaders        map[string]string
+	OTELServiceName    string
+
+	// Backups of the database and transcripts: taken every BackupIntervalHours
+	// (0: only on demand) into BackupDir, optionally copied to BackupS3URI
+	// (s3://bucket/prefix), keeping the newest BackupRetention archives
+	BackupDir            string
+	BackupIntervalHours  int
+	BackupRetention      int
+	BackupS3URI          string
+	BackupIncludeUploads bool
+
+	// How often retention policies are enforced (0: only on demand)
+	RetentionIntervalHours int
+
+	// Bulk library imports: local imports must lie under LibraryImportDir
+	// (empty: only S3 imports), and jobs are queued at most
+	// LibraryImportRatePerMinute a minute with at most LibraryImportMaxPending
+	// of an import's jobs waiting at a time
+	LibraryImportDir           string
+	LibraryImportRatePerMinute int
+	LibraryImportMaxPending    int
+
+	// SMTP server scheduled reports are emailed through (empty host: email
+	// delivery is unavailable)
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// Kafka REST proxy job lifecycle events are produced through, to KafkaTopic
+	// (empty URL: not published to Kafka)
+	KafkaRESTURL  string
+	KafkaTopic    string
+	KafkaUsername string
+	KafkaPassword string
+
+	// NATS server and MQTT broker job lifecycle events are published to, on a
+	// subject or topic per event type under the prefix (empty URL: not published)
+	NATSURL           string
+	NATSSubjectPrefix string
+	MQTTURL           string
+	MQTTTopicPrefix   string
+	MQTTRetain        bool
+
+	// Apprise API endpoint finished jobs are announced through, to AppriseURLs
+	// or the services configured under the endpoint's key (empty: not announced)
+	AppriseURL  string
+	AppriseURLs string
+	AppriseTag  string
 }
 
 // Load loads configuration from environment variables and .env file
@@ -55,6 +244,116 @@ func Load() *Config {
 		UVPath:         findUVPath(),
 		WhisperXEnv:    getEnv("WHISPERX_ENV", "data/whisperx-env"),
 		OpenAIAPIKey:   getEnv("OPENAI_API_KEY", ""),
+
+		EnableDefaultAdapters: getEnv("ENABLE_DEFAULT_ADAPTERS", "") != "",
+		LocalWhisperXBaseURL:  getEnv("LOCAL_WHISPERX_BASE_URL", ""),
+
+		SecretsRefreshMinutes: getEnvAsInt("SECRETS_REFRESH_MINUTES", 5),
+		VaultAddr:             getEnv("VAULT_ADDR", ""),
+		VaultToken:            getEnv("VAULT_TOKEN", ""),
+		SecretsDir:            getEnv("SECRETS_DIR", "/run/secrets"),
+
+		CustodySigningKey: getCustodySigningKey(),
+
+		DatabaseReplicaPath: getEnv("DATABASE_REPLICA_PATH", ""),
+
+		YtDlpTimeoutMinutes: getEnvAsInt("YTDLP_TIMEOUT_MINUTES", 60),
+		YtDlpMaxFileSize:    getEnv("YTDLP_MAX_FILESIZE", "2G"),
+
+		FeedPollIntervalMinutes: getEnvAsInt("FEED_POLL_INTERVAL_MINUTES", 60),
+
+		EmbeddingModel: getEnv("EMBEDDING_MODEL", ""),
+
+		RecordingMaxMB:           getEnvAsInt("RECORDING_MAX_MB", 2048),
+		RecordingSessionTTLHours: getEnvAsInt("RECORDING_SESSION_TTL_HOURS", 24),
+
+		PlaybackURLTTLSeconds: getEnvAsInt("PLAYBACK_URL_TTL_SECONDS", 900),
+		AudioS3Redirect:       getEnvAsBool("AUDIO_S3_REDIRECT", false),
+		PublicBaseURL:         strings.TrimSuffix(getEnv("PUBLIC_BASE_URL", ""), "/"),
+
+		ScanMode:           getEnv("SCAN_MODE", ""),
+		ClamAVAddress:      getEnv("CLAMAV_ADDRESS", ""),
+		ScanHTTPURL:        getEnv("SCAN_HTTP_URL", ""),
+		ScanTimeoutSeconds: getEnvAsInt("SCAN_TIMEOUT_SECONDS", 300),
+
+		ReviewConfidenceThreshold: getEnvAsFloat("REVIEW_CONFIDENCE_THRESHOLD", 0.5),
+		ReviewMinFlaggedSegments:  getEnvAsInt("REVIEW_MIN_FLAGGED_SEGMENTS", 3),
+
+		VoiceprintMatchThreshold: getEnvAsFloat("VOICEPRINT_MATCH_THRESHOLD", 0.6),
+
+		QuickRetentionMinutes:       getEnvAsInt("QUICK_TRANSCRIPTION_RETENTION_MINUTES", 360),
+		QuickMaxStorageMB:           getEnvAsInt("QUICK_TRANSCRIPTION_MAX_STORAGE_MB", 0),
+		QuickCleanupIntervalMinutes: getEnvAsInt("QUICK_TRANSCRIPTION_CLEANUP_INTERVAL_MINUTES", 15),
+
+		AdapterCostPerMinute: getEnvAsFloatMap("ADAPTER_COST_PER_MINUTE"),
+		CostCurrency:         getEnv("COST_CURRENCY", "USD"),
+
+		GPUMemoryHeadroomMB: getEnvAsInt("GPU_MEMORY_HEADROOM_MB", 512),
+		MaxLoadPerCPU:       getEnvAsFloat("MAX_LOAD_PER_CPU", 0),
+
+		WorkerID: getEnv("WORKER_ID", ""),
+
+		APIV1DeprecatedAt: getEnvAsDate("API_V1_DEPRECATED_AT"),
+		APIV1SunsetAt:     getEnvAsDate("API_V1_SUNSET_AT"),
+
+		FastModel:          getEnv("FAST_TRANSCRIPTION_MODEL", "base"),
+		FastMaxSeconds:     getEnvAsInt("FAST_TRANSCRIPTION_MAX_SECONDS", 60),
+		FastWarmup:         getEnvAsBool("FAST_TRANSCRIPTION_WARMUP", true),
+		FastTimeoutSeconds: getEnvAsInt("FAST_TRANSCRIPTION_TIMEOUT_SECONDS", 30),
+
+		ShadowModelID: getEnv("SHADOW_MODEL_ID", ""),
+		ShadowPercent: getEnvAsFloat("SHADOW_PERCENT", 0),
+
+		RNNoiseModelPath: getEnv("RNNOISE_MODEL_PATH", ""),
+
+		ChunkMinMinutes:     getEnvAsInt("CHUNK_MIN_MINUTES", 0),
+		ChunkSeconds:        getEnvAsInt("CHUNK_SECONDS", 600),
+		ChunkOverlapSeconds: getEnvAsInt("CHUNK_OVERLAP_SECONDS", 2),
+		ChunkWorkers:        getEnvAsInt("CHUNK_WORKERS", 4),
+
+		RateLimitPerIP:     getEnvAsInt("RATE_LIMIT_PER_IP", 0),
+		RateLimitPerAPIKey: getEnvAsInt("RATE_LIMIT_PER_API_KEY", 0),
+		TrustedProxies:     getEnvAsList("TRUSTED_PROXIES"),
+
+		MonthlyQuotaMinutes: getEnvAsInt("MONTHLY_QUOTA_MINUTES", 0),
+
+		OTLPEndpoint:       getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTLPTracesEndpoint: getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", ""),
+		OTLPHeaders:        getEnvAsMap("OTEL_EXPORTER_OTLP_HEADERS"),
+		OTELServiceName:    getEnv("OTEL_SERVICE_NAME", "scriberr"),
+
+		BackupDir:            getEnv("BACKUP_DIR", "data/backups"),
+		BackupIntervalHours:  getEnvAsInt("BACKUP_INTERVAL_HOURS", 0),
+		BackupRetention:      getEnvAsInt("BACKUP_RETENTION", 7),
+		BackupS3URI:          strings.TrimSuffix(getEnv("BACKUP_S3_URI", ""), "/"),
+		BackupIncludeUploads: getEnvAsBool("BACKUP_INCLUDE_UPLOADS", false),
+
+		RetentionIntervalHours: getEnvAsInt("RETENTION_INTERVAL_HOURS", 24),
+
+		LibraryImportDir:           getEnv("LIBRARY_IMPORT_DIR", ""),
+		LibraryImportRatePerMinute: getEnvAsInt("LIBRARY_IMPORT_RATE_PER_MINUTE", 30),
+		LibraryImportMaxPending:    getEnvAsInt("LIBRARY_IMPORT_MAX_PENDING", 20),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
+
+		KafkaRESTURL:  getEnv("KAFKA_REST_URL", ""),
+		KafkaTopic:    getEnv("KAFKA_TOPIC", "scriberr.jobs"),
+		KafkaUsername: getEnv("KAFKA_USERNAME", ""),
+		KafkaPassword: getEnv("KAFKA_PASSWORD", ""),
+
+		NATSURL:           getEnv("NATS_URL", ""),
+		NATSSubjectPrefix: getEnv("NATS_SUBJECT_PREFIX", "scriberr.jobs"),
+		MQTTURL:           getEnv("MQTT_URL", ""),
+		MQTTTopicPrefix:   getEnv("MQTT_TOPIC_PREFIX", "scriberr/jobs"),
+		MQTTRetain:        getEnvAsBool("MQTT_RETAIN", false),
+
+		AppriseURL:  getEnv("APPRISE_URL", ""),
+		AppriseURLs: getEnv("APPRISE_URLS", ""),
+		AppriseTag:  getEnv("APPRISE_TAG", ""),
 	}
 }
 
@@ -76,6 +375,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat gets an environment variable as float64 with a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvAsBool gets an environment variable as bool with a default value
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -86,6 +395,66 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsFloatMap gets a comma-separated list of key=number pairs, skipping
+// invalid numbers
+func getEnvAsFloatMap(key string) map[string]float64 {
+	values := make(map[string]float64)
+	for name, value := range getEnvAsMap(key) {
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			logger.Warn("Invalid number, ignoring", "key", key, "name", name, "value", value)
+			continue
+		}
+		values[name] = number
+	}
+	return values
+}
+
+// getEnvAsDate gets an environment variable as a date (2006-01-02) or time
+// (RFC 3339), zero when unset or invalid
+func getEnvAsDate(key string) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	logger.Warn("Invalid date, ignoring", "key", key, "value", value)
+	return time.Time{}
+}
+
+// getEnvAsList gets a comma-separated environment variable as a list, skipping
+// empty entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsMap gets a comma-separated list of key=value pairs, as in
+// OTEL_EXPORTER_OTLP_HEADERS. Values may be percent-encoded.
+func getEnvAsMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range getEnvAsList(key) {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if decoded, err := url.PathUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values
+}
+
 // getJWTSecret gets JWT secret from env or generates a secure random one
 func getJWTSecret() string {
 	if secret := os.Getenv("JWT_SECRET"); secret != "" {
@@ -110,6 +479,35 @@ func getJWTSecret() string {
 	return secret
 }
 
+// getCustodySigningKey gets the custody signing key from env (a base64 Ed25519
+// seed), or from a key file, generating and persisting one on first start
+func getCustodySigningKey() ed25519.PrivateKey {
+	if encoded := os.Getenv("CUSTODY_SIGNING_KEY"); encoded != "" {
+		if seed, err := base64.StdEncoding.DecodeString(encoded); err == nil && len(seed) == ed25519.SeedSize {
+			return ed25519.NewKeyFromSeed(seed)
+		}
+		logger.Warn("CUSTODY_SIGNING_KEY is not a base64 Ed25519 seed, ignoring it")
+	}
+	keyFile := getEnv("CUSTODY_SIGNING_KEY_FILE", "data/custody_signing_key")
+	if data, err := os.ReadFile(keyFile); err == nil {
+		if seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil && len(seed) == ed25519.SeedSize {
+			return ed25519.NewKeyFromSeed(seed)
+		}
+		logger.Warn("Custody signing key file is invalid, generating a new key", "path", keyFile)
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		logger.Warn("Could not generate custody signing key", "error", err)
+		return nil
+	}
+	_ = os.MkdirAll(filepath.Dir(keyFile), 0755)
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key.Seed())), 0600); err != nil {
+		logger.Warn("Could not persist custody signing key; manifests signed now will not verify after a restart", "path", keyFile, "error", err)
+	}
+	logger.Debug("Generated custody signing key", "path", keyFile)
+	return key
+}
+
 // findUVPath finds UV package manager in common locations
 func findUVPath() string {
 	if uvPath := os.Getenv("UV_PATH"); uvPath != "" {
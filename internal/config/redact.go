@@ -0,0 +1,71 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// secretFieldMarkers identify configuration fields holding credentials
+var secretFieldMarkers = []string{"secret", "apikey", "password", "token", "credential"}
+
+// Redacted returns the configuration keyed by field name, safe to share: secrets
+// are replaced by whether they are set, and credentials and query strings are
+// stripped from URLs. Fields tagged json:"-" are left out, and binary values
+// such as keys only report whether they are set.
+func (c *Config) Redacted() map[string]interface{} {
+	redacted := make(map[string]interface{})
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		fieldValue := v.Field(i)
+		value := fieldValue.Interface()
+		if s, ok := value.(string); ok {
+			value = redactValue(field.Name, s)
+		} else if fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.Uint8 {
+			value = ""
+			if fieldValue.Len() > 0 {
+				value = "[set]"
+			}
+		}
+		redacted[field.Name] = value
+	}
+	return redacted
+}
+
+// redactValue redacts a string configuration value by its field name
+func redactValue(name, value string) string {
+	lower := strings.ToLower(name)
+	for _, marker := range secretFieldMarkers {
+		if strings.Contains(lower, marker) {
+			if value == "" {
+				return ""
+			}
+			return "[set]"
+		}
+	}
+	if strings.HasSuffix(lower, "url") || strings.Contains(value, "://") {
+		return RedactURL(value)
+	}
+	return value
+}
+
+// RedactURL strips the credentials, query and fragment of a URL; values that do
+// not parse are hidden entirely
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "[redacted]"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
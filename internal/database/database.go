@@ -57,6 +57,10 @@ func Initialize(dbPath string) error {
 	sqlDB.SetConnMaxLifetime(30 * time.Minute) // Reset connections every 30 minutes
 	sqlDB.SetConnMaxIdleTime(5 * time.Minute)  // Close idle connections after 5 minutes
 
+	// Checked before migrating, which adds the role column and the tag tables
+	legacyRoleTables := tablesWithoutRoles(DB)
+	tagsPromoted := DB.Migrator().HasTable(&models.JobTag{})
+
 	// Auto migrate the schema
 	if err := DB.AutoMigrate(
 		&models.TranscriptionJob{},
@@ -74,9 +78,57 @@ func Initialize(dbPath string) error {
 		&models.Summary{},
 		&models.Note{},
 		&models.RefreshToken{},
+		&models.FeedSubscription{},
+		&models.FeedEpisode{},
+		&models.TranscriptChunk{},
+		&models.TranscriptIndexState{},
+		&models.RecordingSession{},
+		&models.Voiceprint{},
+		&models.JobSpeakerEmbedding{},
+		&models.SpeakerAttribute{},
+		&models.WorkspaceSettings{},
+		&models.Speaker{},
+		&models.Annotation{},
+		&models.ShadowRun{},
+		&models.TranscriptRevision{},
+		&models.Benchmark{},
+		&models.BenchmarkSample{},
+		&models.BenchmarkRun{},
+		&models.ProfileVersion{},
+		&models.AdapterConfig{},
+		&models.ExecutionComponent{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.AuditEvent{},
+		&models.QuotaUsage{},
+		&models.LLMOutput{},
+		&models.ChatPolicy{},
+		&models.NotificationTemplate{},
+		&models.RetentionPolicy{},
+		&models.AWSTagRoute{},
+		&models.Vocabulary{},
+		&models.VocabularyFilter{},
+		&models.CorrectionRule{},
+		&models.LibraryImport{},
+		&models.LibraryImportFile{},
+		&models.ReportSchedule{},
+		&models.FieldPermission{},
+		&models.JobStage{},
+		&models.Tag{},
+		&models.JobTag{},
+		&models.Collection{},
+		&models.CollectionMember{},
 	); err != nil {
 		return fmt.Errorf("failed to auto migrate: %v", err)
 	}
+	if err := backfillLegacyRoles(DB, legacyRoleTables); err != nil {
+		return err
+	}
+	if !tagsPromoted {
+		if err := backfillJobTags(DB); err != nil {
+			return err
+		}
+	}
 
 	// Cleanup duplicate speaker mappings before creating unique index (for backward compatibility)
 	// Keep the latest mapping for each (job_id, original_speaker) pair
@@ -99,11 +151,34 @@ func Initialize(dbPath string) error {
 		return fmt.Errorf("failed to create unique constraint for speaker mappings: %v", err)
 	}
 
+	// Jobs completed before their audio's length was kept on them take it
+	// from their executions, so the jobs list can filter and sort by it
+	backfillQuery := `
+		UPDATE transcription_jobs
+		SET audio_seconds = (
+			SELECT MAX(audio_seconds)
+			FROM transcription_job_executions
+			WHERE transcription_job_id = transcription_jobs.id
+		)
+		WHERE audio_seconds IS NULL AND status = 'completed'
+	`
+	if err := DB.Exec(backfillQuery).Error; err != nil {
+		fmt.Printf("Warning: Failed to backfill job audio lengths: %v\n", err)
+	}
+
+	// Full-text search over titles, transcripts, summaries and notes
+	if err := initSearchIndex(DB); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // Close closes the database connection gracefully
 func Close() error {
+	if err := CloseReplica(); err != nil {
+		return err
+	}
 	if DB == nil {
 		return nil
 	}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	// replica is the read replica's connection pool, nil when none is
+	// configured. Query callbacks load it while InitializeReplica and
+	// CloseReplica swap it.
+	replica atomic.Pointer[sql.DB]
+	// replicaMu serialises InitializeReplica and CloseReplica
+	replicaMu sync.Mutex
+	// routedDB is the primary whose queries are routed to the replica
+	routedDB *gorm.DB
+)
+
+type replicaKey struct{}
+
+// replicaPoolKey keeps a statement's own pool while it reads from the replica
+const replicaPoolKey = "scriberr:replica_pool"
+
+// WithReplica marks ctx so queries run with it read from the replica, when one
+// is configured. Writes always go to the primary, so only requests that do not
+// read back their own writes (lists, searches and exports) should use it.
+func WithReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaKey{}, true)
+}
+
+func usesReplica(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	marked, _ := ctx.Value(replicaKey{}).(bool)
+	return marked
+}
+
+// InitializeReplica opens a query-only connection to the read replica at
+// dbPath, kept in sync with the primary by the deployment (for example by
+// Litestream or LiteFS), and routes the queries of contexts marked with
+// WithReplica to it. Initialize must have been called first.
+func InitializeReplica(dbPath string) error {
+	if DB == nil {
+		return fmt.Errorf("primary database is not initialized")
+	}
+
+	dsn := fmt.Sprintf("%s?"+
+		"_pragma=query_only(1)&"+ // Refuse writes
+		"_pragma=cache_size(-64000)&"+
+		"_pragma=temp_store(MEMORY)&"+
+		"_pragma=mmap_size(268435456)&"+
+		"_timeout=30000",
+		dbPath)
+	replicaDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to read replica: %v", err)
+	}
+	pool, err := replicaDB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get read replica sql.DB: %v", err)
+	}
+	if err := pool.Ping(); err != nil {
+		pool.Close()
+		return fmt.Errorf("read replica ping failed: %v", err)
+	}
+
+	// Reads only, so more connections than the primary
+	pool.SetMaxOpenConns(20)
+	pool.SetMaxIdleConns(10)
+	pool.SetConnMaxLifetime(30 * time.Minute)
+	pool.SetConnMaxIdleTime(5 * time.Minute)
+
+	replicaMu.Lock()
+	defer replicaMu.Unlock()
+	if routedDB != DB {
+		if err := registerReplicaRouting(DB); err != nil {
+			pool.Close()
+			return fmt.Errorf("failed to register read replica routing: %v", err)
+		}
+		routedDB = DB
+	}
+	if old := replica.Swap(pool); old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// registerReplicaRouting routes db's queries and row scans to the replica
+func registerReplicaRouting(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("*").Register("replica:route", routeToReplica); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("*").Register("replica:restore", restorePrimary); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("*").Register("replica:route", routeToReplica); err != nil {
+		return err
+	}
+	return db.Callback().Row().After("*").Register("replica:restore", restorePrimary)
+}
+
+// routeToReplica points a marked query outside a transaction at the replica
+func routeToReplica(db *gorm.DB) {
+	if !usesReplica(db.Statement.Context) {
+		return
+	}
+	pool := replica.Load()
+	if pool == nil {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	db.Statement.Settings.Store(replicaPoolKey, db.Statement.ConnPool)
+	db.Statement.ConnPool = pool
+}
+
+// restorePrimary gives a statement back its own pool, so a write reusing it
+// still reaches the primary
+func restorePrimary(db *gorm.DB) {
+	if pool, ok := db.Statement.Settings.LoadAndDelete(replicaPoolKey); ok {
+		db.Statement.ConnPool = pool.(gorm.ConnPool)
+	}
+}
+
+// CloseReplica closes the read replica; queries read from the primary again
+func CloseReplica() error {
+	replicaMu.Lock()
+	defer replicaMu.Unlock()
+	pool := replica.Swap(nil)
+	if pool == nil {
+		return nil
+	}
+	return pool.Close()
+}
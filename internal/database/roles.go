@@ -0,0 +1,36 @@
+package database
+
+import (
+	"fmt"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// roleTables are the tables whose rows carry a role
+var roleTables = []interface{}{&models.User{}, &models.APIKey{}}
+
+// tablesWithoutRoles returns the role tables that exist but predate the role
+// column. It must run before the schema is migrated.
+func tablesWithoutRoles(db *gorm.DB) []interface{} {
+	var tables []interface{}
+	for _, table := range roleTables {
+		if db.Migrator().HasTable(table) && !db.Migrator().HasColumn(table, "Role") {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// backfillLegacyRoles gives accounts and API keys created before roles existed
+// the full access they had. New rows default to the least privileged role, so
+// the column default cannot be relied on for these.
+func backfillLegacyRoles(db *gorm.DB, tables []interface{}) error {
+	for _, table := range tables {
+		if err := db.Model(table).Where("1 = 1").Update("role", models.RoleAdmin).Error; err != nil {
+			return fmt.Errorf("failed to backfill roles: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// The search index is an FTS5 table kept in sync by triggers, so every code path
+// that writes titles, transcripts, summaries or notes is covered. Transcripts are
+// indexed per segment so matches carry the segment's timestamps.
+const (
+	createSearchIndexSQL = `
+		CREATE VIRTUAL TABLE search_index USING fts5(
+			content,
+			job_id UNINDEXED,
+			kind UNINDEXED,
+			source_id UNINDEXED,
+			start_time UNINDEXED,
+			end_time UNINDEXED,
+			tokenize = 'unicode61 remove_diacritics 2'
+		)`
+
+	// Invalid or missing transcripts index no segments instead of failing the write
+	segmentsSource = `json_each(CASE WHEN json_valid(%[1]s.transcript) THEN %[1]s.transcript ELSE '{}' END, '$.segments')`
+
+	insertTitleSQL = `
+		INSERT INTO search_index(content, job_id, kind, source_id)
+		SELECT %[1]s.title, %[1]s.id, 'title', %[1]s.id WHERE %[1]s.title IS NOT NULL AND %[1]s.title != ''`
+
+	insertSegmentsSQL = `
+		INSERT INTO search_index(content, job_id, kind, source_id, start_time, end_time)
+		SELECT json_extract(s.value, '$.text'), %[1]s.id, 'segment', s.key,
+			json_extract(s.value, '$.start'), json_extract(s.value, '$.end')
+		FROM ` + segmentsSource + ` s
+		WHERE json_extract(s.value, '$.text') IS NOT NULL`
+
+	insertSummarySQL = `
+		INSERT INTO search_index(content, job_id, kind, source_id)
+		VALUES (NEW.content, NEW.transcription_id, 'summary', NEW.id)`
+
+	insertNoteSQL = `
+		INSERT INTO search_index(content, job_id, kind, source_id, start_time, end_time)
+		VALUES (NEW.content, NEW.transcription_id, 'note', NEW.id, NEW.start_time, NEW.end_time)`
+)
+
+func searchIndexTriggers() []string {
+	return []string{
+		`CREATE TRIGGER IF NOT EXISTS search_jobs_ai AFTER INSERT ON transcription_jobs BEGIN` +
+			fmt.Sprintf(insertTitleSQL, "NEW") + `;` +
+			fmt.Sprintf(insertSegmentsSQL, "NEW") + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_jobs_title_au AFTER UPDATE OF title ON transcription_jobs
+		WHEN OLD.title IS NOT NEW.title BEGIN
+			DELETE FROM search_index WHERE job_id = OLD.id AND kind = 'title';` +
+			fmt.Sprintf(insertTitleSQL, "NEW") + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_jobs_transcript_au AFTER UPDATE OF transcript ON transcription_jobs
+		WHEN OLD.transcript IS NOT NEW.transcript BEGIN
+			DELETE FROM search_index WHERE job_id = OLD.id AND kind = 'segment';` +
+			fmt.Sprintf(insertSegmentsSQL, "NEW") + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_jobs_ad AFTER DELETE ON transcription_jobs BEGIN
+			DELETE FROM search_index WHERE job_id = OLD.id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_summaries_ai AFTER INSERT ON summaries BEGIN` +
+			insertSummarySQL + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_summaries_au AFTER UPDATE OF content, transcription_id ON summaries BEGIN
+			DELETE FROM search_index WHERE kind = 'summary' AND source_id = OLD.id;` +
+			insertSummarySQL + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_summaries_ad AFTER DELETE ON summaries BEGIN
+			DELETE FROM search_index WHERE kind = 'summary' AND source_id = OLD.id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_notes_ai AFTER INSERT ON notes BEGIN` +
+			insertNoteSQL + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_notes_au AFTER UPDATE OF content, start_time, end_time ON notes BEGIN
+			DELETE FROM search_index WHERE kind = 'note' AND source_id = OLD.id;` +
+			insertNoteSQL + `;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS search_notes_ad AFTER DELETE ON notes BEGIN
+			DELETE FROM search_index WHERE kind = 'note' AND source_id = OLD.id;
+		END`,
+	}
+}
+
+// backfillSearchIndexSQL indexes rows that existed before the search index was created
+func backfillSearchIndexSQL() []string {
+	return []string{
+		`INSERT INTO search_index(content, job_id, kind, source_id)
+		SELECT title, id, 'title', id FROM transcription_jobs WHERE title IS NOT NULL AND title != ''`,
+		`INSERT INTO search_index(content, job_id, kind, source_id, start_time, end_time)
+		SELECT json_extract(s.value, '$.text'), j.id, 'segment', s.key,
+			json_extract(s.value, '$.start'), json_extract(s.value, '$.end')
+		FROM transcription_jobs j, ` + fmt.Sprintf(segmentsSource, "j") + ` s
+		WHERE json_extract(s.value, '$.text') IS NOT NULL`,
+		`INSERT INTO search_index(content, job_id, kind, source_id)
+		SELECT content, transcription_id, 'summary', id FROM summaries`,
+		`INSERT INTO search_index(content, job_id, kind, source_id, start_time, end_time)
+		SELECT content, transcription_id, 'note', id, start_time, end_time FROM notes`,
+	}
+}
+
+// initSearchIndex creates the full-text search index and its sync triggers,
+// populating it from existing data the first time it is created
+func initSearchIndex(db *gorm.DB) error {
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'search_index'").Scan(&count).Error; err != nil {
+		return fmt.Errorf("failed to check search index: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if count == 0 {
+			if err := tx.Exec(createSearchIndexSQL).Error; err != nil {
+				return fmt.Errorf("failed to create search index: %v", err)
+			}
+			for _, stmt := range backfillSearchIndexSQL() {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return fmt.Errorf("failed to populate search index: %v", err)
+				}
+			}
+		}
+		for _, stmt := range searchIndexTriggers() {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to create search index trigger: %v", err)
+			}
+		}
+		return nil
+	})
+}
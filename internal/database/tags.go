@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backfillJobTags assigns jobs created before tags were their own records the
+// tags stored in their Tags. It must run once, when the tag tables are first
+// created.
+func backfillJobTags(db *gorm.DB) error {
+	var jobs []models.TranscriptionJob
+	if err := db.Select("id", "workspace", "tags").Where("tags IS NOT NULL AND tags <> ''").Find(&jobs).Error; err != nil {
+		return fmt.Errorf("failed to backfill job tags: %v", err)
+	}
+	skipped := 0
+	for i := range jobs {
+		job := &jobs[i]
+		entries, err := job.TagEntries()
+		if err != nil {
+			skipped++
+			continue
+		}
+		for _, entry := range entries {
+			tag := models.Tag{Workspace: job.Workspace, Key: entry.Key, Value: entry.Value}
+			if err := db.Where("workspace = ? AND key = ? AND value = ?", tag.Workspace, tag.Key, tag.Value).FirstOrCreate(&tag).Error; err != nil {
+				return fmt.Errorf("failed to backfill job tags: %v", err)
+			}
+			assignment := models.JobTag{TranscriptionJobID: job.ID, TagID: tag.ID}
+			if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignment).Error; err != nil {
+				return fmt.Errorf("failed to backfill job tags: %v", err)
+			}
+		}
+	}
+	if skipped > 0 {
+		fmt.Printf("Warning: Left the tags of %d jobs unassigned, as they are not a tag list\n", skipped)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AppriseConfig locates an Apprise API server (https://github.com/caronc/apprise-api)
+// and the services it notifies
+type AppriseConfig struct {
+	// URL is the server's notify endpoint: http://apprise:8000/notify/ with
+	// URLs, or http://apprise:8000/notify/<key> for the services saved under key
+	URL string
+	// URLs are Apprise service URLs, e.g. pover://user@token or
+	// tgram://bot/chat, separated by commas or spaces
+	URLs string
+	// Tag limits a saved configuration to the services with this tag
+	Tag string
+	// PublicBaseURL, when set, links notifications to the job in the web
+	// interface
+	PublicBaseURL string
+}
+
+// AppriseNotifier announces finished jobs through Apprise, which forwards them
+// to Pushover, Gotify, Telegram, ntfy, Discord and the other services it supports
+type AppriseNotifier struct {
+	cfg    AppriseConfig
+	client *http.Client
+}
+
+// NewAppriseNotifier creates a notifier; without an endpoint it returns nil
+func NewAppriseNotifier(cfg AppriseConfig) (*AppriseNotifier, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid Apprise URL %q: expected http(s)://host/notify/[key]", cfg.URL)
+	}
+	cfg.PublicBaseURL = strings.TrimSuffix(cfg.PublicBaseURL, "/")
+	return &AppriseNotifier{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Name identifies the notifier in logs
+func (a *AppriseNotifier) Name() string {
+	return "apprise"
+}
+
+// appriseNotification is the body of POST /notify/ and /notify/<key>
+type appriseNotification struct {
+	URLs   string `json:"urls,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Type   string `json:"type"` // info, success, warning or failure
+	Format string `json:"format"`
+}
+
+// Send announces a completed or failed job; other events are not sent
+func (a *AppriseNotifier) Send(ctx context.Context, event Event) error {
+	notification, ok := a.notification(event)
+	if !ok {
+		return nil
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Apprise notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Apprise request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Apprise unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("Apprise returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// notification builds the message announcing an event, reporting false for
+// events that are not announced
+func (a *AppriseNotifier) notification(event Event) (appriseNotification, bool) {
+	title := event.Job.ID
+	if event.Job.Title != nil && *event.Job.Title != "" {
+		title = *event.Job.Title
+	}
+	notification := appriseNotification{URLs: a.cfg.URLs, Tag: a.cfg.Tag, Format: "text"}
+
+	var body strings.Builder
+	switch event.Type {
+	case JobCompleted:
+		notification.Title = "Transcription completed: " + title
+		notification.Type = "success"
+		fmt.Fprintf(&body, "%q is ready.", title)
+	case JobFailed:
+		notification.Title = "Transcription failed: " + title
+		notification.Type = "failure"
+		fmt.Fprintf(&body, "%q failed.", title)
+		if event.Job.ErrorMessage != nil && *event.Job.ErrorMessage != "" {
+			fmt.Fprintf(&body, "\nError: %s", *event.Job.ErrorMessage)
+		}
+	default:
+		return notification, false
+	}
+	if a.cfg.PublicBaseURL != "" {
+		fmt.Fprintf(&body, "\n%s/audio/%s", a.cfg.PublicBaseURL, event.Job.ID)
+	}
+	notification.Body = body.String()
+	return notification, true
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// brokerTimeout bounds a whole delivery to NATS or MQTT, from dialing to the
+// broker's acknowledgement
+const brokerTimeout = 10 * time.Second
+
+// eventName is the event type without its "job." prefix, e.g. completed,
+// which NATS subjects and MQTT topics end in
+func eventName(eventType string) string {
+	return strings.TrimPrefix(eventType, "job.")
+}
+
+// parseBrokerURL parses a broker URL, telling whether its scheme asks for TLS
+func parseBrokerURL(raw string, schemes, tlsSchemes []string) (*url.URL, bool, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return u, false, nil
+		}
+	}
+	for _, scheme := range tlsSchemes {
+		if u.Scheme == scheme {
+			return u, true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("unsupported scheme %q", u.Scheme)
+}
+
+// dialBroker connects to the broker at u, over TLS if useTLS, with the
+// deadline of ctx, or brokerTimeout, on the whole connection
+func dialBroker(ctx context.Context, u *url.URL, defaultPort string, useTLS bool) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, brokerTimeout)
+	defer cancel()
+
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	if useTLS {
+		return upgradeTLS(conn, u.Hostname())
+	}
+	return conn, nil
+}
+
+// upgradeTLS starts TLS on an established connection
+func upgradeTLS(conn net.Conn, serverName string) (net.Conn, error) {
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12})
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return tlsConn, nil
+}
@@ -0,0 +1,182 @@
+// Package events publishes job lifecycle events to notifiers such as message
+// brokers.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is the version of the Event JSON. It is raised whenever a
+// field is removed or changes meaning; new fields may appear within a version.
+const SchemaVersion = 1
+
+// Job lifecycle event types
+const (
+	JobCreated    = "job.created"
+	JobProcessing = "job.processing"
+	JobCompleted  = "job.completed"
+	JobFailed     = "job.failed"
+	JobCancelled  = "job.cancelled"
+)
+
+// Event is a job lifecycle event as published
+type Event struct {
+	SchemaVersion int       `json:"schema_version"`
+	ID            string    `json:"id"` // unique per event, for deduplication
+	Type          string    `json:"type"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Job           JobInfo   `json:"job"`
+}
+
+// JobInfo is what an event says about its job; the transcript is left out,
+// as it is fetched through the API
+type JobInfo struct {
+	ID           string    `json:"id"`
+	Title        *string   `json:"title,omitempty"`
+	Status       string    `json:"status"`
+	Workspace    string    `json:"workspace,omitempty"`
+	ProfileID    *string   `json:"profile_id,omitempty"`
+	ModelFamily  string    `json:"model_family"`
+	Model        string    `json:"model,omitempty"`
+	Language     *string   `json:"language,omitempty"`
+	AudioURI     *string   `json:"audio_uri,omitempty"`
+	SourceURL    *string   `json:"source_url,omitempty"`
+	ErrorMessage *string   `json:"error_message,omitempty"`
+	ErrorCode    string    `json:"error_code,omitempty"`
+	Tags         []string  `json:"tags,omitempty"` // key or key=value
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewJobEvent describes what happened to a job
+func NewJobEvent(eventType string, job *models.TranscriptionJob) Event {
+	return Event{
+		SchemaVersion: SchemaVersion,
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		OccurredAt:    time.Now().UTC(),
+		Job: JobInfo{
+			ID:           job.ID,
+			Title:        job.Title,
+			Status:       string(job.Status),
+			Workspace:    job.Workspace,
+			ProfileID:    job.ProfileID,
+			ModelFamily:  job.Parameters.ModelFamily,
+			Model:        job.Parameters.Model,
+			Language:     job.Parameters.Language,
+			AudioURI:     job.AudioUri,
+			SourceURL:    job.SourceURL,
+			ErrorMessage: job.ErrorMessage,
+			ErrorCode:    string(job.ErrorCode),
+			Tags:         job.TagNames(),
+			CreatedAt:    job.CreatedAt,
+			UpdatedAt:    job.UpdatedAt,
+		},
+	}
+}
+
+// Notifier delivers events to one destination, such as a message broker or
+// EventBridge. It may ignore the event types it has no use for.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+// queueSize is how many events may wait for delivery before new ones are dropped
+const queueSize = 1000
+
+// sendTimeout bounds the delivery of one event to one notifier
+const sendTimeout = 30 * time.Second
+
+// Bus hands events to its notifiers in the background, so publishing never holds
+// up a job. Events are delivered in the order they were published; when the
+// notifiers fall behind by more than queueSize events, new ones are dropped.
+type Bus struct {
+	notifiers []Notifier
+	events    chan Event
+	wg        sync.WaitGroup
+	mu        sync.RWMutex // guards stopped against closing events mid-send
+	stopped   bool
+}
+
+// NewBus creates a bus delivering to notifiers; without any it publishes nothing
+func NewBus(notifiers ...Notifier) *Bus {
+	return &Bus{notifiers: notifiers, events: make(chan Event, queueSize)}
+}
+
+// Enabled reports whether the bus has anywhere to publish to
+func (b *Bus) Enabled() bool {
+	return b != nil && len(b.notifiers) > 0
+}
+
+// Start delivers published events until Stop
+func (b *Bus) Start() {
+	if !b.Enabled() {
+		return
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for event := range b.events {
+			b.deliver(event)
+		}
+	}()
+}
+
+// Stop delivers the events already published and stops
+func (b *Bus) Stop() {
+	if !b.Enabled() {
+		return
+	}
+	b.mu.Lock()
+	if !b.stopped {
+		b.stopped = true
+		close(b.events)
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// Publish queues an event for delivery
+func (b *Bus) Publish(event Event) {
+	if !b.Enabled() {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.stopped {
+		return
+	}
+	select {
+	case b.events <- event:
+	default:
+		logger.Warn("Event queue full, dropping event", "type", event.Type, "job_id", event.Job.ID)
+	}
+}
+
+// PublishJob queues an event about a job
+func (b *Bus) PublishJob(eventType string, job *models.TranscriptionJob) {
+	if !b.Enabled() {
+		return
+	}
+	b.Publish(NewJobEvent(eventType, job))
+}
+
+func (b *Bus) deliver(event Event) {
+	for _, notifier := range b.notifiers {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := notifier.Send(ctx, event); err != nil {
+			logger.Error("Failed to publish event", "notifier", notifier.Name(), "type", event.Type, "job_id", event.Job.ID, "error", err)
+		} else {
+			logger.Debug("Published event", "notifier", notifier.Name(), "type", event.Type, "job_id", event.Job.ID)
+		}
+		cancel()
+	}
+}
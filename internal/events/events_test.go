@@ -0,0 +1,331 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"scriberr/internal/models"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingNotifier remembers the events sent to it
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingNotifier) Name() string { return "recording" }
+
+func (s *recordingNotifier) Send(ctx context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestKafkaNotifier(t *testing.T) {
+	title := "Standup"
+	job := &models.TranscriptionJob{ID: "job-1", Title: &title, Status: models.StatusCompleted}
+	job.Parameters.ModelFamily = "whisper"
+	event := NewJobEvent(JobCompleted, job)
+
+	t.Run("Produces a keyed JSON record", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/topics/scriberr.jobs", r.URL.Path)
+			assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+			user, password, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "producer", user)
+			assert.Equal(t, "secret", password)
+
+			var body struct {
+				Records []struct {
+					Key   string `json:"key"`
+					Value Event  `json:"value"`
+				} `json:"records"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Len(t, body.Records, 1)
+			assert.Equal(t, "job-1", body.Records[0].Key)
+			assert.Equal(t, SchemaVersion, body.Records[0].Value.SchemaVersion)
+			assert.Equal(t, JobCompleted, body.Records[0].Value.Type)
+			assert.Equal(t, "completed", body.Records[0].Value.Job.Status)
+			assert.Equal(t, "Standup", *body.Records[0].Value.Job.Title)
+			w.Write([]byte(`{"offsets":[{"partition":0,"offset":7,"error_code":null,"error":null}]}`))
+		}))
+		defer server.Close()
+
+		notifier := NewKafkaNotifier(KafkaConfig{RESTURL: server.URL + "/", Topic: "scriberr.jobs", Username: "producer", Password: "secret"})
+		assert.NoError(t, notifier.Send(context.Background(), event))
+	})
+
+	t.Run("Retries server errors", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"offsets":[{"partition":0,"offset":8}]}`))
+		}))
+		defer server.Close()
+
+		notifier := NewKafkaNotifier(KafkaConfig{RESTURL: server.URL, Topic: "jobs"})
+		assert.NoError(t, notifier.Send(context.Background(), event))
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("Reports rejected records", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Topic not found."}`))
+		}))
+		defer server.Close()
+
+		notifier := NewKafkaNotifier(KafkaConfig{RESTURL: server.URL, Topic: "missing"})
+		err := notifier.Send(context.Background(), event)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Topic not found")
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Disabled without a proxy", func(t *testing.T) {
+		assert.Nil(t, NewKafkaNotifier(KafkaConfig{Topic: "jobs"}))
+	})
+}
+
+func TestWatchJobs(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}))
+
+	notifier := &recordingNotifier{}
+	bus := NewBus(notifier)
+	require.NoError(t, bus.WatchJobs(db))
+	bus.Start()
+
+	require.NoError(t, db.Create(&models.TranscriptionJob{ID: "queued", AudioPath: "a.wav", Status: models.StatusPending}).Error)
+	// Track jobs of multi-track jobs are internal
+	require.NoError(t, db.Create(&models.TranscriptionJob{ID: "track", AudioPath: "b.wav", Status: models.StatusProcessing}).Error)
+	bus.PublishJob(JobProcessing, &models.TranscriptionJob{ID: "queued", Status: models.StatusProcessing})
+	bus.Stop()
+
+	// Publishing after Stop is a no-op
+	bus.PublishJob(JobFailed, &models.TranscriptionJob{ID: "queued"})
+
+	require.Len(t, notifier.events, 2)
+	assert.Equal(t, JobCreated, notifier.events[0].Type)
+	assert.Equal(t, "queued", notifier.events[0].Job.ID)
+	assert.Equal(t, "pending", notifier.events[0].Job.Status)
+	assert.NotEmpty(t, notifier.events[0].ID)
+	assert.Equal(t, JobProcessing, notifier.events[1].Type)
+	assert.NotEqual(t, notifier.events[0].ID, notifier.events[1].ID)
+}
+
+// serveOnce accepts one connection on a local port and hands it to handle
+func serveOnce(t *testing.T, handle func(conn net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}()
+	return listener.Addr().String()
+}
+
+func TestNATSNotifier(t *testing.T) {
+	event := NewJobEvent(JobCompleted, &models.TranscriptionJob{ID: "job-1", Status: models.StatusCompleted})
+
+	t.Run("Publishes to the event's subject", func(t *testing.T) {
+		received := make(chan string, 1)
+		addr := serveOnce(t, func(conn net.Conn) {
+			reader := bufio.NewReader(conn)
+			conn.Write([]byte("INFO {\"server_id\":\"test\",\"auth_required\":true}\r\n"))
+			connect, _ := reader.ReadString('\n')
+			assert.Contains(t, connect, `"user":"scriberr"`)
+			assert.Contains(t, connect, `"pass":"secret"`)
+			pub, _ := reader.ReadString('\n')
+			payload, _ := reader.ReadString('\n')
+			ping, _ := reader.ReadString('\n')
+			assert.Equal(t, "PING\r\n", ping)
+			conn.Write([]byte("PONG\r\n"))
+			received <- pub + payload
+		})
+
+		notifier, err := NewNATSNotifier(NATSConfig{URL: "nats://scriberr:secret@" + addr})
+		require.NoError(t, err)
+		require.NoError(t, notifier.Send(context.Background(), event))
+
+		message := <-received
+		header, payload, _ := strings.Cut(message, "\r\n")
+		fields := strings.Fields(header)
+		require.Len(t, fields, 3)
+		assert.Equal(t, "PUB", fields[0])
+		assert.Equal(t, "scriberr.jobs.completed", fields[1])
+		payload = strings.TrimSuffix(payload, "\r\n")
+		assert.Equal(t, fmt.Sprint(len(payload)), fields[2])
+		var published Event
+		require.NoError(t, json.Unmarshal([]byte(payload), &published))
+		assert.Equal(t, "job-1", published.Job.ID)
+	})
+
+	t.Run("Reports server errors", func(t *testing.T) {
+		addr := serveOnce(t, func(conn net.Conn) {
+			conn.Write([]byte("INFO {}\r\n"))
+			bufio.NewReader(conn).ReadString('\n')
+			conn.Write([]byte("-ERR 'Authorization Violation'\r\n"))
+		})
+		notifier, err := NewNATSNotifier(NATSConfig{URL: "nats://" + addr, SubjectPrefix: "home"})
+		require.NoError(t, err)
+		err = notifier.Send(context.Background(), event)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Authorization Violation")
+	})
+
+	t.Run("Rejects other schemes", func(t *testing.T) {
+		_, err := NewNATSNotifier(NATSConfig{URL: "http://localhost:4222"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMQTTNotifier(t *testing.T) {
+	event := NewJobEvent(JobFailed, &models.TranscriptionJob{ID: "job-2", Status: models.StatusFailed})
+
+	t.Run("Publishes at QoS 1 to the event's topic", func(t *testing.T) {
+		published := make(chan []byte, 1)
+		addr := serveOnce(t, func(conn net.Conn) {
+			reader := bufio.NewReader(conn)
+			packetType, body, err := readMQTTPacket(reader)
+			require.NoError(t, err)
+			assert.Equal(t, byte(mqttConnect), packetType)
+			assert.Equal(t, "MQTT", string(body[2:6]))
+			assert.Equal(t, byte(0x02|0x80|0x40), body[7], "clean session with user name and password")
+			assert.Contains(t, string(body), "homeassistant")
+			conn.Write([]byte{mqttConnAck, 2, 0, 0})
+
+			header, err := reader.ReadByte()
+			require.NoError(t, err)
+			assert.Equal(t, byte(mqttPublish|1<<1|1), header, "QoS 1, retained")
+			reader.UnreadByte()
+			_, body, err = readMQTTPacket(reader)
+			require.NoError(t, err)
+			published <- body
+			conn.Write([]byte{mqttPubAck, 2, 0, mqttPacketID})
+			packetType, _, _ = readMQTTPacket(reader)
+			assert.Equal(t, byte(mqttDisconnect), packetType)
+		})
+
+		notifier, err := NewMQTTNotifier(MQTTConfig{URL: "mqtt://homeassistant:secret@" + addr, Retain: true})
+		require.NoError(t, err)
+		require.NoError(t, notifier.Send(context.Background(), event))
+
+		body := <-published
+		topicLength := int(binary.BigEndian.Uint16(body))
+		assert.Equal(t, "scriberr/jobs/failed", string(body[2:2+topicLength]))
+		assert.Equal(t, uint16(mqttPacketID), binary.BigEndian.Uint16(body[2+topicLength:]))
+		var payload Event
+		require.NoError(t, json.Unmarshal(body[4+topicLength:], &payload))
+		assert.Equal(t, "job-2", payload.Job.ID)
+		assert.Equal(t, JobFailed, payload.Type)
+	})
+
+	t.Run("Reports refused connections", func(t *testing.T) {
+		addr := serveOnce(t, func(conn net.Conn) {
+			readMQTTPacket(bufio.NewReader(conn))
+			conn.Write([]byte{mqttConnAck, 2, 0, 5})
+		})
+		notifier, err := NewMQTTNotifier(MQTTConfig{URL: "mqtt://" + addr})
+		require.NoError(t, err)
+		err = notifier.Send(context.Background(), event)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not authorized")
+	})
+
+	t.Run("Encodes long remaining lengths", func(t *testing.T) {
+		packet := mqttPacket(mqttPublish, make([]byte, 321))
+		assert.Equal(t, []byte{mqttPublish, 0xc1, 0x02}, packet[:3])
+		packetType, body, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet)))
+		require.NoError(t, err)
+		assert.Equal(t, byte(mqttPublish), packetType)
+		assert.Len(t, body, 321)
+	})
+}
+
+func TestAppriseNotifier(t *testing.T) {
+	title := "Standup"
+	message := "CUDA out of memory"
+	completed := NewJobEvent(JobCompleted, &models.TranscriptionJob{ID: "job-1", Title: &title, Status: models.StatusCompleted})
+	failed := NewJobEvent(JobFailed, &models.TranscriptionJob{ID: "job-2", Status: models.StatusFailed, ErrorMessage: &message})
+
+	t.Run("Notifies the given services of finished jobs", func(t *testing.T) {
+		var received []appriseNotification
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/notify/", r.URL.Path)
+			var notification appriseNotification
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+			received = append(received, notification)
+		}))
+		defer server.Close()
+
+		notifier, err := NewAppriseNotifier(AppriseConfig{URL: server.URL + "/notify/", URLs: "pover://user@token, tgram://bot/chat", PublicBaseURL: "https://scriberr.example.com/"})
+		require.NoError(t, err)
+		require.NoError(t, notifier.Send(context.Background(), completed))
+		require.NoError(t, notifier.Send(context.Background(), failed))
+		require.NoError(t, notifier.Send(context.Background(), NewJobEvent(JobCreated, &models.TranscriptionJob{ID: "job-3"})))
+
+		require.Len(t, received, 2)
+		assert.Equal(t, "pover://user@token, tgram://bot/chat", received[0].URLs)
+		assert.Equal(t, "Transcription completed: Standup", received[0].Title)
+		assert.Equal(t, "success", received[0].Type)
+		assert.Equal(t, "\"Standup\" is ready.\nhttps://scriberr.example.com/audio/job-1", received[0].Body)
+		assert.Equal(t, "Transcription failed: job-2", received[1].Title)
+		assert.Equal(t, "failure", received[1].Type)
+		assert.Contains(t, received[1].Body, "Error: CUDA out of memory")
+	})
+
+	t.Run("Reports failed notifications", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var notification appriseNotification
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+			assert.Empty(t, notification.URLs)
+			assert.Equal(t, "family", notification.Tag)
+			w.WriteHeader(http.StatusFailedDependency)
+			w.Write([]byte("One or more notification could not be sent."))
+		}))
+		defer server.Close()
+
+		notifier, err := NewAppriseNotifier(AppriseConfig{URL: server.URL + "/notify/scriberr", Tag: "family"})
+		require.NoError(t, err)
+		err = notifier.Send(context.Background(), completed)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "424")
+	})
+
+	t.Run("Rejects other schemes", func(t *testing.T) {
+		_, err := NewAppriseNotifier(AppriseConfig{URL: "apprise://localhost"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// WatchJobs publishes job.created whenever a transcription job is inserted
+// through db. Multi-track track jobs are internal and created already
+// processing, so they are skipped.
+func (b *Bus) WatchJobs(db *gorm.DB) error {
+	if !b.Enabled() {
+		return nil
+	}
+	return db.Callback().Create().After("gorm:create").Register("events:job_created", func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.TranscriptionJob:
+			b.jobCreated(dest)
+		case []models.TranscriptionJob:
+			for i := range dest {
+				b.jobCreated(&dest[i])
+			}
+		case *[]models.TranscriptionJob:
+			for i := range *dest {
+				b.jobCreated(&(*dest)[i])
+			}
+		}
+	})
+}
+
+func (b *Bus) jobCreated(job *models.TranscriptionJob) {
+	if job.Status == models.StatusProcessing {
+		return
+	}
+	b.PublishJob(JobCreated, job)
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// kafkaAttempts is how often a record is offered to the proxy before giving up
+const kafkaAttempts = 3
+
+// KafkaConfig locates a Kafka REST proxy (Confluent REST Proxy, Redpanda's
+// HTTP proxy or anything else speaking its v2 API) and the topic to produce to
+type KafkaConfig struct {
+	RESTURL  string // e.g. http://kafka-rest:8082
+	Topic    string
+	Username string // basic auth, when the proxy requires it
+	Password string
+}
+
+// KafkaNotifier produces events as JSON records keyed by job ID, so the events of
+// a job land on one partition in order
+type KafkaNotifier struct {
+	cfg    KafkaConfig
+	client *http.Client
+}
+
+// NewKafkaNotifier creates a notifier; without a proxy URL and topic it returns nil
+func NewKafkaNotifier(cfg KafkaConfig) *KafkaNotifier {
+	if cfg.RESTURL == "" || cfg.Topic == "" {
+		return nil
+	}
+	cfg.RESTURL = strings.TrimSuffix(cfg.RESTURL, "/")
+	return &KafkaNotifier{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name identifies the notifier in logs
+func (k *KafkaNotifier) Name() string {
+	return "kafka"
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		Partition int     `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Send produces the event to the topic, retrying when the proxy is unreachable
+// or answers with a server error
+func (k *KafkaNotifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string][]kafkaRecord{
+		"records": {{Key: event.Job.ID, Value: event}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Kafka record: %w", err)
+	}
+	endpoint := k.cfg.RESTURL + "/topics/" + url.PathEscape(k.cfg.Topic)
+
+	var lastErr error
+	for attempt := 0; attempt < kafkaAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		retry, err := k.produce(ctx, endpoint, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// produce posts one batch of records, reporting whether a failure is worth retrying
+func (k *KafkaNotifier) produce(ctx context.Context, endpoint string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create Kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if k.cfg.Username != "" {
+		req.SetBasicAuth(k.cfg.Username, k.cfg.Password)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("Kafka REST proxy unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("Kafka REST proxy returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("Kafka REST proxy returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.Unmarshal(data, &produced); err != nil {
+		return false, fmt.Errorf("unexpected Kafka REST proxy response: %w", err)
+	}
+	for _, offset := range produced.Offsets {
+		if offset.Error != nil || offset.ErrorCode != nil {
+			message := ""
+			if offset.Error != nil {
+				message = *offset.Error
+			}
+			// Broker errors such as a leader election are usually transient
+			return true, fmt.Errorf("Kafka rejected the record: %s", message)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,220 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// MQTTConfig locates an MQTT broker and the topics to publish to
+type MQTTConfig struct {
+	// URL is mqtt://[user:password@]host[:1883], or mqtts:// for TLS (port 8883)
+	URL string
+	// TopicPrefix is what topics start with, e.g. scriberr/jobs for
+	// scriberr/jobs/completed
+	TopicPrefix string
+	// Retain asks the broker to keep the last event of each topic for clients
+	// that subscribe later
+	Retain bool
+}
+
+// MQTTNotifier publishes events as JSON, at QoS 1, to a topic per event type
+type MQTTNotifier struct {
+	cfg    MQTTConfig
+	url    *url.URL
+	useTLS bool
+}
+
+// NewMQTTNotifier creates a notifier; without a URL it returns nil
+func NewMQTTNotifier(cfg MQTTConfig) (*MQTTNotifier, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	u, useTLS, err := parseBrokerURL(cfg.URL, []string{"mqtt", "tcp"}, []string{"mqtts", "ssl"})
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT URL: %w", err)
+	}
+	if cfg.TopicPrefix == "" {
+		cfg.TopicPrefix = "scriberr/jobs"
+	}
+	return &MQTTNotifier{cfg: cfg, url: u, useTLS: useTLS}, nil
+}
+
+// Name identifies the notifier in logs
+func (m *MQTTNotifier) Name() string {
+	return "mqtt"
+}
+
+// Topic returns the topic events of a type are published to
+func (m *MQTTNotifier) Topic(eventType string) string {
+	return m.cfg.TopicPrefix + "/" + eventName(eventType)
+}
+
+// MQTT 3.1.1 control packet types, shifted into the fixed header
+const (
+	mqttConnect    = 1 << 4
+	mqttConnAck    = 2 << 4
+	mqttPublish    = 3 << 4
+	mqttPubAck     = 4 << 4
+	mqttDisconnect = 14 << 4
+)
+
+// mqttKeepAlive is the keep-alive announced in CONNECT, in seconds; the
+// connection never lives that long
+const mqttKeepAlive = 30
+
+// mqttPacketID identifies the one PUBLISH of a connection
+const mqttPacketID = 1
+
+// mqttConnectErrors explains CONNACK return codes
+var mqttConnectErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// Send connects with a clean session, publishes the event at QoS 1 and waits
+// for the broker's PUBACK, then disconnects
+func (m *MQTTNotifier) Send(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	defaultPort := "1883"
+	if m.useTLS {
+		defaultPort = "8883"
+	}
+	conn, err := dialBroker(ctx, m.url, defaultPort, m.useTLS)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	if _, err := conn.Write(m.connectPacket()); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	packetType, body, err := readMQTTPacket(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if packetType != mqttConnAck || len(body) != 2 {
+		return fmt.Errorf("unexpected MQTT packet 0x%02x instead of CONNACK", packetType)
+	}
+	if code := body[1]; code != 0 {
+		reason, ok := mqttConnectErrors[code]
+		if !ok {
+			reason = fmt.Sprintf("return code %d", code)
+		}
+		return fmt.Errorf("MQTT broker refused the connection: %s", reason)
+	}
+
+	if _, err := conn.Write(m.publishPacket(m.Topic(event.Type), payload)); err != nil {
+		return fmt.Errorf("failed to publish to MQTT broker: %w", err)
+	}
+	for {
+		packetType, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read PUBACK: %w", err)
+		}
+		if packetType == mqttPubAck && len(body) == 2 && binary.BigEndian.Uint16(body) == mqttPacketID {
+			break
+		}
+	}
+
+	_, _ = conn.Write([]byte{mqttDisconnect, 0})
+	return nil
+}
+
+// connectPacket builds the CONNECT packet, with the URL's credentials
+func (m *MQTTNotifier) connectPacket() []byte {
+	flags := byte(0x02) // clean session
+	var payload []byte
+	payload = appendMQTTString(payload, "scriberr-"+uuid.New().String()[:8])
+	if user := m.url.User; user != nil {
+		flags |= 0x80
+		payload = appendMQTTString(payload, user.Username())
+		if password, ok := user.Password(); ok {
+			flags |= 0x40
+			payload = appendMQTTString(payload, password)
+		}
+	}
+
+	body := appendMQTTString(nil, "MQTT")
+	body = append(body, 4, flags) // protocol level 4 is MQTT 3.1.1
+	body = binary.BigEndian.AppendUint16(body, mqttKeepAlive)
+	return mqttPacket(mqttConnect, append(body, payload...))
+}
+
+// publishPacket builds a QoS 1 PUBLISH packet
+func (m *MQTTNotifier) publishPacket(topic string, payload []byte) []byte {
+	header := byte(mqttPublish | 1<<1) // QoS 1
+	if m.cfg.Retain {
+		header |= 1
+	}
+	body := appendMQTTString(nil, topic)
+	body = binary.BigEndian.AppendUint16(body, mqttPacketID)
+	return mqttPacket(header, append(body, payload...))
+}
+
+// mqttPacket prefixes body with the fixed header and its remaining length
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// appendMQTTString appends a length-prefixed UTF-8 string
+func appendMQTTString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// readMQTTPacket reads one packet, returning its type (flags masked out) and body
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed MQTT remaining length")
+		}
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xf0, body, nil
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NATSConfig locates a NATS server and the subjects to publish to
+type NATSConfig struct {
+	// URL is nats://[user:password@]host[:4222], or tls:// for TLS; a user
+	// without a password is sent as a token
+	URL string
+	// SubjectPrefix is what subjects start with, e.g. scriberr.jobs for
+	// scriberr.jobs.completed
+	SubjectPrefix string
+}
+
+// NATSNotifier publishes events as JSON to a subject per event type
+type NATSNotifier struct {
+	cfg    NATSConfig
+	url    *url.URL
+	useTLS bool
+}
+
+// NewNATSNotifier creates a notifier; without a URL it returns nil
+func NewNATSNotifier(cfg NATSConfig) (*NATSNotifier, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	u, useTLS, err := parseBrokerURL(cfg.URL, []string{"nats"}, []string{"tls"})
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = "scriberr.jobs"
+	}
+	return &NATSNotifier{cfg: cfg, url: u, useTLS: useTLS}, nil
+}
+
+// Name identifies the notifier in logs
+func (n *NATSNotifier) Name() string {
+	return "nats"
+}
+
+// Subject returns the subject events of a type are published to
+func (n *NATSNotifier) Subject(eventType string) string {
+	return n.cfg.SubjectPrefix + "." + eventName(eventType)
+}
+
+// natsInfo is the part of the server's INFO this client needs
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+}
+
+// Send connects, publishes the event and waits for the server to have
+// processed it (a PING answered by PONG), then disconnects
+func (n *NATSNotifier) Send(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	conn, err := dialBroker(ctx, n.url, "4222", n.useTLS)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer func() { conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read NATS INFO: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting: %q", strings.TrimSpace(line))
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "INFO ")), &info); err != nil {
+		return fmt.Errorf("invalid NATS INFO: %w", err)
+	}
+	if info.TLSRequired && !n.useTLS {
+		if conn, err = upgradeTLS(conn, n.url.Hostname()); err != nil {
+			return err
+		}
+		reader = bufio.NewReader(conn)
+	}
+
+	connect := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "scriberr",
+		"lang":     "go",
+		"version":  "1",
+	}
+	if user := n.url.User; user != nil {
+		if password, ok := user.Password(); ok {
+			connect["user"] = user.Username()
+			connect["pass"] = password
+		} else {
+			connect["auth_token"] = user.Username()
+		}
+	}
+	connectJSON, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("CONNECT %s\r\nPUB %s %d\r\n%s\r\nPING\r\n", connectJSON, n.Subject(event.Type), len(payload), payload)
+	if _, err := conn.Write([]byte(message)); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read NATS reply: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS rejected the event: %s", strings.Trim(strings.TrimPrefix(line, "-ERR"), " '"))
+		}
+		// +OK, a server PING or an updated INFO: keep waiting for PONG
+	}
+}
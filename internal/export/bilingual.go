@@ -0,0 +1,56 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatBilingual interleaves each segment with its translation, for study
+const FormatBilingual = "bilingual"
+
+// Bilingual renders a plain-text transcript for language learners: each
+// segment's timestamp and original line followed by its translated line.
+// Chapter titles are written as headings ahead of their first segment.
+func Bilingual(doc *Document) string {
+	var b strings.Builder
+	if doc.Title != "" {
+		b.WriteString(doc.Title)
+		b.WriteString("\n")
+		if doc.Translation != nil && doc.Translation.Language != "" {
+			language := doc.Transcript.Language
+			if language == "" {
+				language = "original"
+			}
+			fmt.Fprintf(&b, "(%s / %s)\n", language, doc.Translation.Language)
+		}
+		b.WriteString("\n")
+	}
+
+	chapters := doc.chapterStarts()
+	for i, seg := range doc.Transcript.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if chapter, ok := chapters[i]; ok {
+			fmt.Fprintf(&b, "## %s\n\n", chapter.Title)
+		}
+		if speaker := doc.speaker(seg); speaker != "" {
+			text = speaker + ": " + text
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", formatTimestamp(seg.Start, "."), text)
+		if translated := doc.translated(i); translated != "" {
+			fmt.Fprintf(&b, "%s %s\n", strings.Repeat(" ", len("[00:00:00.000]")), translated)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// translated returns the translation of segment i, or ""
+func (d *Document) translated(i int) string {
+	if d.Translation == nil || i >= len(d.Translation.Segments) {
+		return ""
+	}
+	return d.Translation.Segments[i]
+}
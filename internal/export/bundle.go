@@ -0,0 +1,164 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bundle is everything recorded about a recording besides its transcript: the
+// speaker names, summaries, notes and chats, archived alongside the transcript
+type Bundle struct {
+	Job        BundleJob       `json:"job"`
+	Speakers   []BundleSpeaker `json:"speakers"`
+	Summaries  []BundleSummary `json:"summaries"`
+	Notes      []BundleNote    `json:"notes"`
+	Chats      []BundleChat    `json:"chats"`
+	ExportedAt time.Time       `json:"exported_at"`
+}
+
+// BundleJob identifies the recording
+type BundleJob struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+	Tags       []string   `json:"tags"` // key or key=value
+}
+
+// BundleSpeaker is the name given to a diarization label
+type BundleSpeaker struct {
+	Label string `json:"label"`
+	Name  string `json:"name"`
+}
+
+// BundleSummary is a generated summary
+type BundleSummary struct {
+	TemplateID string    `json:"template_id,omitempty"`
+	Model      string    `json:"model"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BundleNote is a note on a passage of the transcript
+type BundleNote struct {
+	StartTime float64   `json:"start_time"`
+	EndTime   float64   `json:"end_time"`
+	Quote     string    `json:"quote"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BundleChat is a chat conversation about the recording
+type BundleChat struct {
+	Title     string              `json:"title"`
+	Model     string              `json:"model"`
+	CreatedAt time.Time           `json:"created_at"`
+	Messages  []BundleChatMessage `json:"messages"`
+}
+
+// BundleChatMessage is one message of a chat
+type BundleChatMessage struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BundleMarkdown renders the bundle as a readable document
+func BundleMarkdown(b *Bundle) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", b.Job.Title)
+	fmt.Fprintf(&sb, "- Job: %s\n- Created: %s\n", b.Job.ID, b.Job.CreatedAt.UTC().Format(time.RFC3339))
+	if b.Job.RecordedAt != nil {
+		fmt.Fprintf(&sb, "- Recorded: %s\n", b.Job.RecordedAt.UTC().Format(time.RFC3339))
+	}
+	if len(b.Job.Tags) > 0 {
+		fmt.Fprintf(&sb, "- Tags: %s\n", strings.Join(b.Job.Tags, ", "))
+	}
+	fmt.Fprintf(&sb, "- Exported: %s\n", b.ExportedAt.UTC().Format(time.RFC3339))
+
+	if len(b.Speakers) > 0 {
+		sb.WriteString("\n## Speakers\n\n| Label | Name |\n| --- | --- |\n")
+		for _, speaker := range b.Speakers {
+			fmt.Fprintf(&sb, "| %s | %s |\n", markdownCell(speaker.Label), markdownCell(speaker.Name))
+		}
+	}
+
+	if len(b.Summaries) > 0 {
+		sb.WriteString("\n## Summaries\n")
+		for _, summary := range b.Summaries {
+			fmt.Fprintf(&sb, "\n### %s (%s)\n\n%s\n", summary.CreatedAt.UTC().Format(time.RFC3339), summary.Model, strings.TrimSpace(summary.Content))
+		}
+	}
+
+	if len(b.Notes) > 0 {
+		sb.WriteString("\n## Notes\n")
+		for _, note := range b.Notes {
+			fmt.Fprintf(&sb, "\n**%s - %s**\n\n", formatTimestamp(note.StartTime, "."), formatTimestamp(note.EndTime, "."))
+			for _, line := range strings.Split(strings.TrimSpace(note.Quote), "\n") {
+				fmt.Fprintf(&sb, "> %s\n", line)
+			}
+			fmt.Fprintf(&sb, "\n%s\n", strings.TrimSpace(note.Content))
+		}
+	}
+
+	if len(b.Chats) > 0 {
+		sb.WriteString("\n## Chats\n")
+		for _, chat := range b.Chats {
+			fmt.Fprintf(&sb, "\n### %s\n\n_%s, %s_\n", chat.Title, chat.Model, chat.CreatedAt.UTC().Format(time.RFC3339))
+			for _, message := range chat.Messages {
+				role := "Assistant"
+				if message.Role == "user" {
+					role = "User"
+				}
+				fmt.Fprintf(&sb, "\n**%s:** %s\n", role, strings.TrimSpace(message.Content))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
+
+// bundleFile is a file written into a bundle
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// WriteBundle adds a recording to a zip under dir: its context as JSON and
+// Markdown and, when doc is not nil, its transcript as JSON and SRT
+func WriteBundle(zw *zip.Writer, dir string, b *Bundle, doc *Document) error {
+	context, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	files := []bundleFile{
+		{"context.json", context},
+		{"context.md", []byte(BundleMarkdown(b))},
+	}
+	if doc != nil {
+		transcript, err := JSON(doc)
+		if err != nil {
+			return err
+		}
+		files = append(files, bundleFile{"transcript.json", transcript}, bundleFile{"transcript.srt", []byte(SRT(doc))})
+	}
+	for _, file := range files {
+		w, err := zw.Create(dir + file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
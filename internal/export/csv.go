@@ -0,0 +1,53 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"time"
+)
+
+// FormatCSV is a spreadsheet of the transcript's segments
+const FormatCSV = "csv"
+
+// CSV renders a row per segment with its start and end offsets in seconds, its
+// speaker and text. When the recording's start is known, the absolute start and
+// end times are added. Numbers, dates and the field separator follow the
+// document's locale, and times its time zone.
+func CSV(doc *Document) ([]byte, error) {
+	locale, location := doc.locale(), doc.timeZone()
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = locale.CSVSeparator
+
+	header := []string{"start", "end", "speaker", "text"}
+	if doc.RecordedAt != nil {
+		header = []string{"start", "end", "start_time", "end_time", "speaker", "text"}
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, seg := range doc.Transcript.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		row := []string{locale.FormatSeconds(seg.Start), locale.FormatSeconds(seg.End)}
+		if doc.RecordedAt != nil {
+			row = append(row,
+				locale.FormatDateTime(doc.RecordedAt.Add(offset(seg.Start)), location),
+				locale.FormatDateTime(doc.RecordedAt.Add(offset(seg.End)), location))
+		}
+		row = append(row, doc.speaker(seg), text)
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// offset converts an offset in seconds into the recording to a duration
+func offset(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
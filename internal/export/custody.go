@@ -0,0 +1,229 @@
+package export
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CustodyManifestVersion is the layout version of custody manifests
+const CustodyManifestVersion = 1
+
+// CustodySignatureAlgorithm signs manifests with the instance's private key;
+// anyone with its public key can verify them
+const CustodySignatureAlgorithm = "Ed25519"
+
+// CustodyManifest documents where a transcript came from and everything that
+// happened to it, for evidentiary use. It is signed so tampering can be detected.
+type CustodyManifest struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	GeneratedBy string    `json:"generated_by"`
+
+	Job        CustodyJob         `json:"job"`
+	Audio      CustodyAudio       `json:"audio"`
+	Transcript CustodyTranscript  `json:"transcript"`
+	Executions []CustodyExecution `json:"executions"`
+	Events     []CustodyEvent     `json:"events"`
+
+	Signature *CustodySignature `json:"signature,omitempty"`
+}
+
+// CustodyJob identifies the job
+type CustodyJob struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+	FinalizedBy string     `json:"finalized_by,omitempty"`
+}
+
+// CustodyAudio fingerprints the job's audio as it is stored now
+type CustodyAudio struct {
+	SourceURL string `json:"source_url,omitempty"`
+	SHA256    string `json:"sha256,omitempty"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	// Error explains why the audio could not be hashed
+	Error string `json:"error,omitempty"`
+	// MatchesProcessedAudio reports whether the audio is the audio the latest
+	// execution transcribed; absent when either hash is unknown
+	MatchesProcessedAudio *bool `json:"matches_processed_audio,omitempty"`
+}
+
+// CustodyTranscript fingerprints the stored transcript
+type CustodyTranscript struct {
+	SHA256          string     `json:"sha256,omitempty"`
+	PostProcessedAt *time.Time `json:"post_processed_at,omitempty"`
+}
+
+// CustodyExecution is one processing run of the job
+type CustodyExecution struct {
+	ID              string        `json:"id"`
+	StartedAt       time.Time     `json:"started_at"`
+	CompletedAt     *time.Time    `json:"completed_at,omitempty"`
+	Status          string        `json:"status"`
+	ModelFamily     string        `json:"model_family"`
+	Model           string        `json:"model"`
+	InputSHA256     string        `json:"input_sha256,omitempty"`
+	EnvironmentHash string        `json:"environment_hash,omitempty"`
+	ReplayOf        string        `json:"replay_of,omitempty"` // replays run in a sandbox and leave the job untouched
+	Steps           []CustodyStep `json:"steps,omitempty"`
+}
+
+// CustodyStep is one adapter call of an execution
+type CustodyStep struct {
+	Stage           string `json:"stage"`
+	ModelID         string `json:"model_id"`
+	AdapterVersion  string `json:"adapter_version"`
+	EnvironmentHash string `json:"environment_hash,omitempty"`
+}
+
+// CustodyEvent is an audit log entry about the job: who did what, and when
+type CustodyEvent struct {
+	At      time.Time `json:"at"`
+	Action  string    `json:"action"`
+	Actor   string    `json:"actor"`
+	Details string    `json:"details,omitempty"`
+}
+
+// CustodySignature signs the manifest as serialized without its signature
+type CustodySignature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"` // see CustodyKeyID
+	Value     string `json:"value"`  // hex
+}
+
+// CustodyKeyID identifies a public key: the hex SHA-256 of the key, shortened,
+// so a verifier can tell which published key a manifest was signed with
+func CustodyKeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Sign signs the manifest with key
+func (m *CustodyManifest) Sign(key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid custody signing key")
+	}
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	m.Signature = &CustodySignature{
+		Algorithm: CustodySignatureAlgorithm,
+		KeyID:     CustodyKeyID(key.Public().(ed25519.PublicKey)),
+		Value:     hex.EncodeToString(ed25519.Sign(key, data)),
+	}
+	return nil
+}
+
+// Verify reports whether the manifest carries a valid signature made with the
+// private key of key
+func (m *CustodyManifest) Verify(key ed25519.PublicKey) bool {
+	if m.Signature == nil || m.Signature.Algorithm != CustodySignatureAlgorithm || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	signature, err := hex.DecodeString(m.Signature.Value)
+	if err != nil {
+		return false
+	}
+	data, err := m.signedBytes()
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, data, signature)
+}
+
+// signedBytes returns the manifest as serialized without its signature
+func (m *CustodyManifest) signedBytes() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return data, nil
+}
+
+// CustodyPDF renders a signed manifest as a printable report. The signature
+// covers the JSON manifest, which the report embeds so it can be verified.
+func CustodyPDF(m *CustodyManifest) ([]byte, error) {
+	const timeLayout = "2006-01-02 15:04:05 MST"
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.UTC().Format(timeLayout)
+	}
+
+	lines := []string{
+		"CHAIN-OF-CUSTODY MANIFEST",
+		"",
+		fmt.Sprintf("Job:             %s", m.Job.ID),
+		fmt.Sprintf("Title:           %s", m.Job.Title),
+		fmt.Sprintf("Status:          %s", m.Job.Status),
+		fmt.Sprintf("Created:         %s", formatTime(&m.Job.CreatedAt)),
+		fmt.Sprintf("Last updated:    %s", formatTime(&m.Job.UpdatedAt)),
+		fmt.Sprintf("Finalized:       %s %s", formatTime(m.Job.FinalizedAt), m.Job.FinalizedBy),
+		fmt.Sprintf("Generated:       %s by %s", formatTime(&m.GeneratedAt), m.GeneratedBy),
+		"",
+		"AUDIO",
+		fmt.Sprintf("SHA-256:         %s", m.Audio.SHA256),
+		fmt.Sprintf("Size:            %d bytes", m.Audio.SizeBytes),
+	}
+	if m.Audio.SourceURL != "" {
+		lines = append(lines, fmt.Sprintf("Source:          %s", m.Audio.SourceURL))
+	}
+	if m.Audio.Error != "" {
+		lines = append(lines, fmt.Sprintf("Hash error:      %s", m.Audio.Error))
+	}
+	if m.Audio.MatchesProcessedAudio != nil {
+		lines = append(lines, fmt.Sprintf("Matches processed audio: %t", *m.Audio.MatchesProcessedAudio))
+	}
+	lines = append(lines,
+		"",
+		"TRANSCRIPT",
+		fmt.Sprintf("SHA-256:         %s", m.Transcript.SHA256),
+		fmt.Sprintf("Post-processed:  %s", formatTime(m.Transcript.PostProcessedAt)),
+		"",
+		"PROCESSING",
+	)
+	for _, execution := range m.Executions {
+		kind := "Execution"
+		if execution.ReplayOf != "" {
+			kind = "Replay of " + execution.ReplayOf
+		}
+		lines = append(lines,
+			fmt.Sprintf("%s %s: %s, %s/%s", kind, execution.ID, execution.Status, execution.ModelFamily, execution.Model),
+			fmt.Sprintf("  Started %s, completed %s", formatTime(&execution.StartedAt), formatTime(execution.CompletedAt)),
+			fmt.Sprintf("  Input SHA-256 %s", execution.InputSHA256),
+		)
+		for _, step := range execution.Steps {
+			lines = append(lines, fmt.Sprintf("  %s: %s %s (environment %s)", step.Stage, step.ModelID, step.AdapterVersion, step.EnvironmentHash))
+		}
+	}
+	lines = append(lines, "", "EVENTS")
+	for _, event := range m.Events {
+		line := fmt.Sprintf("%s  %s  %s", formatTime(&event.At), event.Action, event.Actor)
+		if event.Details != "" {
+			line += "  " + event.Details
+		}
+		lines = append(lines, line)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	lines = append(lines, "", "SIGNATURE")
+	if m.Signature != nil {
+		lines = append(lines, fmt.Sprintf("%s key %s %s", m.Signature.Algorithm, m.Signature.KeyID, m.Signature.Value))
+	}
+	lines = append(lines, "", "SIGNED MANIFEST (JSON)", string(data))
+	return TextPDF(lines), nil
+}
@@ -0,0 +1,268 @@
+// Package export renders stored transcripts as subtitle and data files.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"scriberr/internal/service"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Supported export formats
+const (
+	FormatSRT  = "srt"
+	FormatVTT  = "vtt"
+	FormatJSON = "json"
+	// FormatChapters is a WebVTT chapter track
+	FormatChapters = "chapters"
+)
+
+// Document is a transcript prepared for export
+type Document struct {
+	JobID      string
+	Title      string
+	Transcript interfaces.TranscriptResult
+	Chapters   []service.Chapter
+	// Translation, when generated, holds a translated line per segment
+	Translation *service.Translation
+	// Speakers maps diarization labels (SPEAKER_00) to display names
+	Speakers map[string]string
+	// RecordedAt, when known, is when the recording started, so exports can
+	// show absolute times alongside offsets
+	RecordedAt *time.Time
+	// Tags are the job's tags, as key or key=value
+	Tags []string
+	// Locale and TimeZone format dates, clock times and numbers; the zero
+	// values are ISO dates in UTC
+	Locale   Locale
+	TimeZone *time.Location
+	// CueSettings positions the cues of WebVTT exports
+	CueSettings VTTCueSettings
+}
+
+// NewDocument parses a stored transcript
+func NewDocument(jobID, title, transcript string) (*Document, error) {
+	doc := &Document{JobID: jobID, Title: title}
+	if err := json.Unmarshal([]byte(transcript), &doc.Transcript); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	return doc, nil
+}
+
+// Render renders the document in the given format, returning the content and its MIME type
+func Render(doc *Document, format string) ([]byte, string, error) {
+	switch format {
+	case FormatSRT:
+		return []byte(SRT(doc)), "application/x-subrip; charset=utf-8", nil
+	case FormatVTT:
+		return []byte(VTT(doc)), "text/vtt; charset=utf-8", nil
+	case FormatJSON:
+		data, err := JSON(doc)
+		return data, "application/json", err
+	case FormatChapters:
+		return []byte(Chapters(doc)), "text/vtt; charset=utf-8", nil
+	case FormatLRC:
+		return []byte(LRC(doc)), "text/plain; charset=utf-8", nil
+	case FormatEnhancedLRC:
+		return []byte(EnhancedLRC(doc)), "text/plain; charset=utf-8", nil
+	case FormatBilingual:
+		return []byte(Bilingual(doc)), "text/plain; charset=utf-8", nil
+	case FormatCSV:
+		data, err := CSV(doc)
+		return data, "text/csv; charset=utf-8", err
+	default:
+		return nil, "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// locale returns the document's locale, or the default locale
+func (d *Document) locale() Locale {
+	if d.Locale.Name == "" {
+		return locales[DefaultLocale]
+	}
+	return d.Locale
+}
+
+// timeZone returns the document's time zone, or UTC
+func (d *Document) timeZone() *time.Location {
+	if d.TimeZone == nil {
+		return time.UTC
+	}
+	return d.TimeZone
+}
+
+// speaker returns the display name of a segment's speaker, or ""
+func (d *Document) speaker(seg interfaces.TranscriptSegment) string {
+	if seg.Speaker == nil || *seg.Speaker == "" {
+		return ""
+	}
+	if name, ok := d.Speakers[*seg.Speaker]; ok && name != "" {
+		return name
+	}
+	return *seg.Speaker
+}
+
+// chapterStarts maps the first segment of each chapter to the chapter
+func (d *Document) chapterStarts() map[int]service.Chapter {
+	starts := make(map[int]service.Chapter, len(d.Chapters))
+	for _, chapter := range d.Chapters {
+		starts[chapter.FirstSegment] = chapter
+	}
+	return starts
+}
+
+// SRT renders SubRip subtitles. The first cue of each chapter is prefixed with
+// the chapter title in brackets.
+func SRT(doc *Document) string {
+	var b strings.Builder
+	chapters := doc.chapterStarts()
+	cue := 1
+	for i, seg := range doc.Transcript.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if speaker := doc.speaker(seg); speaker != "" {
+			text = speaker + ": " + text
+		}
+		if chapter, ok := chapters[i]; ok {
+			text = "[" + chapter.Title + "]\n" + text
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", cue, formatTimestamp(seg.Start, ","), formatTimestamp(seg.End, ","), text)
+		cue++
+	}
+	return b.String()
+}
+
+// VTT renders WebVTT captions. A NOTE block at the top names the job, its title,
+// the model and the language. Speakers are given as <v> voice spans, so players
+// can style each voice, and every cue carries the document's cue settings.
+// Chapters are written as NOTE blocks ahead of their first cue; use Chapters for
+// a chapter track. Segments tagged with a language other than the transcript's
+// are wrapped in <lang> spans.
+func VTT(doc *Document) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	b.WriteString("NOTE\n")
+	if doc.Title != "" {
+		fmt.Fprintf(&b, "Title: %s\n", vttNoteText(doc.Title))
+	}
+	fmt.Fprintf(&b, "Job: %s\n", vttNoteText(doc.JobID))
+	if model := doc.Transcript.ModelUsed; model != "" {
+		fmt.Fprintf(&b, "Model: %s\n", vttNoteText(model))
+	}
+	if language := doc.Transcript.Language; language != "" {
+		fmt.Fprintf(&b, "Language: %s\n", vttNoteText(language))
+	}
+	if len(doc.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", vttNoteText(strings.Join(doc.Tags, ", ")))
+	}
+	b.WriteString("\n")
+
+	settings := doc.CueSettings.String()
+	chapters := doc.chapterStarts()
+	for i, seg := range doc.Transcript.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if chapter, ok := chapters[i]; ok {
+			fmt.Fprintf(&b, "NOTE Chapter: %s\n\n", vttNoteText(chapter.Title))
+		}
+		text = vttCueText(text)
+		if seg.Language != nil && *seg.Language != "" && *seg.Language != doc.Transcript.Language {
+			text = "<lang " + *seg.Language + ">" + text + "</lang>"
+		}
+		if speaker := doc.speaker(seg); speaker != "" {
+			text = "<v " + vttCueText(strings.Join(strings.Fields(speaker), " ")) + ">" + text
+		}
+		fmt.Fprintf(&b, "%s --> %s%s\n%s\n\n", formatTimestamp(seg.Start, "."), formatTimestamp(seg.End, "."), settings, text)
+	}
+	return b.String()
+}
+
+// Chapters renders the chapters as a WebVTT chapter track (<track kind="chapters">)
+func Chapters(doc *Document) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for i, chapter := range doc.Chapters {
+		fmt.Fprintf(&b, "chapter-%d\n%s --> %s\n%s\n\n", i+1, formatTimestamp(chapter.Start, "."), formatTimestamp(chapter.End, "."), vttCueText(chapter.Title))
+	}
+	return b.String()
+}
+
+// jsonExport is the layout of JSON exports
+type jsonExport struct {
+	JobID        string                         `json:"job_id"`
+	Title        string                         `json:"title,omitempty"`
+	Language     string                         `json:"language,omitempty"`
+	RecordedAt   *time.Time                     `json:"recorded_at,omitempty"` // in the export's time zone
+	Tags         []string                       `json:"tags,omitempty"`
+	Text         string                         `json:"text"`
+	Segments     []interfaces.TranscriptSegment `json:"segments"`
+	WordSegments []interfaces.TranscriptWord    `json:"word_segments,omitempty"`
+	Chapters     []service.Chapter              `json:"chapters,omitempty"`
+	Translation  *service.Translation           `json:"translation,omitempty"`
+}
+
+// JSON renders the transcript with its chapters and translation, speakers renamed to their display names
+func JSON(doc *Document) ([]byte, error) {
+	segments := make([]interfaces.TranscriptSegment, len(doc.Transcript.Segments))
+	for i, seg := range doc.Transcript.Segments {
+		if speaker := doc.speaker(seg); speaker != "" {
+			seg.Speaker = &speaker
+		}
+		segments[i] = seg
+	}
+	words := make([]interfaces.TranscriptWord, len(doc.Transcript.WordSegments))
+	for i, word := range doc.Transcript.WordSegments {
+		if word.Speaker != nil {
+			if name, ok := doc.Speakers[*word.Speaker]; ok && name != "" {
+				word.Speaker = &name
+			}
+		}
+		words[i] = word
+	}
+
+	var recordedAt *time.Time
+	if doc.RecordedAt != nil {
+		t := doc.RecordedAt.In(doc.timeZone())
+		recordedAt = &t
+	}
+
+	return json.MarshalIndent(jsonExport{
+		JobID:        doc.JobID,
+		Title:        doc.Title,
+		Language:     doc.Transcript.Language,
+		RecordedAt:   recordedAt,
+		Tags:         doc.Tags,
+		Text:         doc.Transcript.Text,
+		Segments:     segments,
+		WordSegments: words,
+		Chapters:     doc.Chapters,
+		Translation:  doc.Translation,
+	}, "", "  ")
+}
+
+// formatTimestamp formats seconds as HH:MM:SS<sep>mmm
+func formatTimestamp(seconds float64, sep string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// vttCueText escapes the characters WebVTT cue text reserves
+func vttCueText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// vttNoteText keeps a NOTE block on one line and free of the cue arrow
+func vttNoteText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "-->", "->")
+}
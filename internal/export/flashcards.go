@@ -0,0 +1,266 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/service"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Flashcard export formats
+const (
+	FormatFlashcardsCSV  = "csv"
+	FormatFlashcardsAnki = "apkg"
+)
+
+// Deck is a set of flashcards exported from one job
+type Deck struct {
+	JobID string
+	Name  string
+	Cards []service.Flashcard
+}
+
+// RenderDeck renders the deck in the given format, returning the content and its MIME type
+func RenderDeck(deck *Deck, format string) ([]byte, string, error) {
+	switch format {
+	case FormatFlashcardsCSV:
+		data, err := FlashcardsCSV(deck)
+		return data, "text/csv; charset=utf-8", err
+	case FormatFlashcardsAnki:
+		data, err := AnkiPackage(deck)
+		return data, "application/octet-stream", err
+	default:
+		return nil, "", fmt.Errorf("unsupported flashcard format %q", format)
+	}
+}
+
+// FlashcardsCSV renders the deck as CSV with the header lines Anki's importer
+// reads (Anki 2.1.55+); other flashcard apps can skip the # lines. Each card's
+// timestamp is added to its back.
+func FlashcardsCSV(deck *Deck) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("#separator:Comma\n#html:false\n#columns:Front,Back,Tags\n")
+	w := csv.NewWriter(&buf)
+	tag := deckTag(deck.Name)
+	for _, card := range deck.Cards {
+		if err := w.Write([]string{card.Front, cardBack(card), tag}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// cardBack is the back of a card followed by where it is heard in the recording
+func cardBack(card service.Flashcard) string {
+	return fmt.Sprintf("%s\n(%s)", card.Back, formatTimestamp(card.Start, ".")[:8])
+}
+
+// deckTag reduces a deck name to a single Anki tag
+func deckTag(name string) string {
+	tag := strings.Join(strings.Fields(name), "_")
+	if tag == "" {
+		return "scriberr"
+	}
+	return tag
+}
+
+// Anki collection schema (version 11), as read by Anki's .apkg importer
+var ankiSchema = []string{
+	`CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null)`,
+	`CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null)`,
+	`CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null)`,
+	`CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null)`,
+	`CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null)`,
+	`CREATE INDEX ix_notes_usn on notes (usn)`,
+	`CREATE INDEX ix_cards_usn on cards (usn)`,
+	`CREATE INDEX ix_revlog_usn on revlog (usn)`,
+	`CREATE INDEX ix_cards_nid on cards (nid)`,
+	`CREATE INDEX ix_cards_sched on cards (did, queue, due)`,
+	`CREATE INDEX ix_revlog_cid on revlog (cid)`,
+	`CREATE INDEX ix_notes_csum on notes (csum)`,
+}
+
+// AnkiPackage renders the deck as an Anki package (.apkg): a zip holding a
+// collection database and an empty media map. Deck, note type and note IDs are
+// derived from the job, so importing a newer export updates the cards in place.
+func AnkiPackage(deck *Deck) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "scriberr-anki-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	collectionPath := filepath.Join(dir, "collection.anki2")
+	if err := writeAnkiCollection(collectionPath, deck, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to build Anki collection: %w", err)
+	}
+	collection, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"collection.anki2", collection},
+		{"media", []byte("{}")},
+	} {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeAnkiCollection(path string, deck *Deck, now time.Time) error {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	deckID := ankiID("deck:" + deck.JobID)
+	modelID := ankiID("model:scriberr-basic")
+	mod := now.Unix()
+
+	models, decks, dconf, conf, err := ankiCollectionConfig(deck.Name, deckID, modelID, mod)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range ankiSchema {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+			mod, mod*1000, mod*1000, conf, models, decks, dconf).Error; err != nil {
+			return err
+		}
+
+		tags := " " + deckTag(deck.Name) + " "
+		for i, card := range deck.Cards {
+			front := html.EscapeString(card.Front)
+			back := strings.ReplaceAll(html.EscapeString(cardBack(card)), "\n", "<br>")
+			noteID := mod*1000 + int64(i)
+			if err := tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+				noteID, ankiGUID(deck.JobID, card), modelID, mod, tags, front+"\x1f"+back, front, ankiChecksum(card.Front)).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+				noteID, noteID, deckID, mod, i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ankiCollectionConfig returns the note type, deck, deck options and collection
+// settings JSON stored in the col table
+func ankiCollectionConfig(deckName string, deckID, modelID, mod int64) (models, decks, dconf, conf string, err error) {
+	model := map[string]any{
+		"id": modelID, "name": "Scriberr Basic", "type": 0, "mod": mod, "usn": -1, "sortf": 0, "did": deckID,
+		"tmpls": []map[string]any{{
+			"name": "Card 1", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+			"qfmt": "{{Front}}",
+			"afmt": "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+		}},
+		"flds": []map[string]any{
+			{"name": "Front", "ord": 0, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{}},
+			{"name": "Back", "ord": 1, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{}},
+		},
+		"css":       ".card { font-family: arial; font-size: 20px; text-align: center; color: black; background-color: white; }",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []string{},
+		"vers":      []string{},
+		"req":       []any{[]any{0, "all", []int{0}}},
+	}
+	deckConfig := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "desc": "", "mod": mod, "usn": -1, "dyn": 0, "conf": 1,
+			"collapsed": false, "browserCollapsed": false, "extendNew": 10, "extendRev": 50,
+			"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+		}
+	}
+	options := map[string]any{
+		"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+		"new":   map[string]any{"delays": []int{1, 10}, "ints": []int{1, 4, 7}, "initialFactor": 2500, "order": 1, "perDay": 20, "bury": true, "separate": true},
+		"rev":   map[string]any{"perDay": 100, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "bury": true, "minSpace": 1},
+		"lapse": map[string]any{"delays": []int{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0},
+	}
+	settings := map[string]any{
+		"activeDecks": []int64{1}, "curDeck": 1, "newSpread": 0, "collapseTime": 1200, "timeLim": 0,
+		"estTimes": true, "dueCounts": true, "curModel": modelID, "nextPos": 1,
+		"sortType": "noteFld", "sortBackwards": false, "addToCur": true,
+	}
+
+	values := []any{
+		map[string]any{strconv.FormatInt(modelID, 10): model},
+		map[string]any{"1": deckConfig(1, "Default"), strconv.FormatInt(deckID, 10): deckConfig(deckID, deckName)},
+		map[string]any{"1": options},
+		settings,
+	}
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		encoded[i] = string(data)
+	}
+	return encoded[0], encoded[1], encoded[2], encoded[3], nil
+}
+
+// ankiID derives a stable deck or note type ID from key
+func ankiID(key string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int64(1<<30 | h.Sum32()>>2)
+}
+
+// ankiGUID identifies a note across exports of the same job
+func ankiGUID(jobID string, card service.Flashcard) string {
+	sum := sha1.Sum([]byte(jobID + "\x00" + card.Front))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ankiChecksum is Anki's duplicate-detection checksum of the sort field
+func ankiChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	checksum, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+	return checksum
+}
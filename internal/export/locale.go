@@ -0,0 +1,87 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Time zones must resolve in containers without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// DefaultLocale formats dates as ISO 8601 and numbers with a decimal point
+const DefaultLocale = "iso"
+
+// Locale controls how dates and numbers are written in exports
+type Locale struct {
+	Name       string
+	DateLayout string // Go layout of a calendar date
+	Decimal    string // decimal separator
+	// CSVSeparator separates CSV fields; locales with a decimal comma use a
+	// semicolon, as their spreadsheet applications expect
+	CSVSeparator rune
+}
+
+// locales are the supported export locales
+var locales = map[string]Locale{
+	"iso":   {Name: "iso", DateLayout: "2006-01-02", Decimal: ".", CSVSeparator: ','},
+	"en-US": {Name: "en-US", DateLayout: "01/02/2006", Decimal: ".", CSVSeparator: ','},
+	"en-GB": {Name: "en-GB", DateLayout: "02/01/2006", Decimal: ".", CSVSeparator: ','},
+	"de-DE": {Name: "de-DE", DateLayout: "02.01.2006", Decimal: ",", CSVSeparator: ';'},
+	"fr-FR": {Name: "fr-FR", DateLayout: "02/01/2006", Decimal: ",", CSVSeparator: ';'},
+	"es-ES": {Name: "es-ES", DateLayout: "02/01/2006", Decimal: ",", CSVSeparator: ';'},
+	"it-IT": {Name: "it-IT", DateLayout: "02/01/2006", Decimal: ",", CSVSeparator: ';'},
+	"nl-NL": {Name: "nl-NL", DateLayout: "02-01-2006", Decimal: ",", CSVSeparator: ';'},
+	"ja-JP": {Name: "ja-JP", DateLayout: "2006/01/02", Decimal: ".", CSVSeparator: ','},
+}
+
+// Locales lists the names of the supported locales
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupLocale returns a supported locale; "" is the default locale
+func LookupLocale(name string) (Locale, error) {
+	if name == "" {
+		name = DefaultLocale
+	}
+	if locale, ok := locales[name]; ok {
+		return locale, nil
+	}
+	return Locale{}, fmt.Errorf("unsupported locale %q (supported: %s)", name, strings.Join(Locales(), ", "))
+}
+
+// LoadTimeZone resolves an IANA time zone name; "" is UTC
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return location, nil
+}
+
+// FormatSeconds writes a duration in seconds with millisecond precision and the
+// locale's decimal separator
+func (l Locale) FormatSeconds(seconds float64) string {
+	s := strconv.FormatFloat(seconds, 'f', 3, 64)
+	if l.Decimal != "." {
+		s = strings.Replace(s, ".", l.Decimal, 1)
+	}
+	return s
+}
+
+// FormatDateTime writes an absolute time as the locale's date and a 24-hour
+// clock time, in the given time zone
+func (l Locale) FormatDateTime(t time.Time, location *time.Location) string {
+	return t.In(location).Format(l.DateLayout + " 15:04:05")
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// LRC lyrics formats
+const (
+	FormatLRC = "lrc"
+	// FormatEnhancedLRC adds <mm:ss.xx> word timestamps for karaoke-style players
+	FormatEnhancedLRC = "elrc"
+)
+
+// LRC renders synced lyrics with one timed line per segment
+func LRC(doc *Document) string {
+	return renderLRC(doc, false)
+}
+
+// EnhancedLRC renders synced lyrics with each word timed, falling back to plain
+// line timing for segments without word-level timestamps
+func EnhancedLRC(doc *Document) string {
+	return renderLRC(doc, true)
+}
+
+func renderLRC(doc *Document, wordTimed bool) string {
+	var b strings.Builder
+	if doc.Title != "" {
+		fmt.Fprintf(&b, "[ti:%s]\n", lrcText(doc.Title))
+	}
+	if n := len(doc.Transcript.Segments); n > 0 {
+		length := int64(doc.Transcript.Segments[n-1].End + 0.5)
+		fmt.Fprintf(&b, "[length:%02d:%02d]\n", length/60, length%60)
+	}
+	b.WriteString("[re:Scriberr]\n")
+
+	words := doc.Transcript.WordSegments
+	next := 0
+	for i, seg := range doc.Transcript.Segments {
+		// Words belong to the segment they start in
+		boundary := math.Inf(1)
+		if i+1 < len(doc.Transcript.Segments) {
+			boundary = doc.Transcript.Segments[i+1].Start
+		}
+		first := next
+		for next < len(words) && words[next].Start < boundary {
+			next++
+		}
+
+		text := lrcText(seg.Text)
+		if text == "" {
+			continue
+		}
+		if wordTimed && next > first {
+			text = lrcWordLine(words[first:next], seg.End)
+		}
+		fmt.Fprintf(&b, "[%s]%s\n", lrcTimestamp(seg.Start), text)
+	}
+	return b.String()
+}
+
+// lrcWordLine renders words as "<mm:ss.xx> word" pairs closed by the line's end
+// time. Words the aligner could not time are written without a tag.
+func lrcWordLine(words []interfaces.TranscriptWord, end float64) string {
+	parts := make([]string, 0, len(words)+1)
+	for _, word := range words {
+		text := lrcText(word.Word)
+		if text == "" {
+			continue
+		}
+		if word.Start > 0 || word.End > 0 {
+			text = "<" + lrcTimestamp(word.Start) + "> " + text
+		}
+		parts = append(parts, text)
+	}
+	parts = append(parts, "<"+lrcTimestamp(end)+">")
+	return strings.Join(parts, " ")
+}
+
+// lrcTimestamp formats seconds as mm:ss.xx, minutes running past 59
+func lrcTimestamp(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	cs := int64(seconds*100 + 0.5)
+	return fmt.Sprintf("%02d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
+
+// lrcText keeps text on one line, without the brackets LRC uses for tags
+func lrcText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.NewReplacer("[", "(", "]", ")", "<", "(", ">", ")").Replace(text)
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Layout of plain-text PDF pages: US Letter in points, 9pt Courier
+const (
+	pdfPageWidth    = 612
+	pdfPageHeight   = 792
+	pdfMargin       = 54
+	pdfFontSize     = 9
+	pdfLineHeight   = 11
+	pdfLineChars    = 92 // Courier glyphs are 0.6em wide
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+)
+
+// TextPDF lays out lines of text on as many pages as needed, wrapping long lines.
+// Characters outside printable ASCII are replaced with '?'.
+func TextPDF(lines []string) []byte {
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapLine(pdfText(line), pdfLineChars)...)
+	}
+	var pages [][]string
+	for len(wrapped) > pdfLinesPerPage {
+		pages = append(pages, wrapped[:pdfLinesPerPage])
+		wrapped = wrapped[pdfLinesPerPage:]
+	}
+	pages = append(pages, wrapped)
+
+	// Objects: 1 catalog, 2 page tree, 3 font, then a page and its contents per page
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscaper.Replace(line))
+		}
+		content.WriteString("ET")
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfEscaper escapes text for a PDF string literal
+var pdfEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+
+// pdfText expands tabs and replaces characters the font cannot show
+func pdfText(line string) string {
+	var b strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// wrapLine breaks a line into lines of at most width characters, at a space
+// where possible
+func wrapLine(line string, width int) []string {
+	var lines []string
+	for len(line) > width {
+		cut := width
+		if space := strings.LastIndexByte(line[:cut], ' '); space > width/2 {
+			cut = space + 1
+		}
+		lines = append(lines, line[:cut])
+		line = line[cut:]
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// vttPercentage is a WebVTT percentage, 0% to 100%
+	vttPercentage = regexp.MustCompile(`^(100(\.0+)?|\d{1,2}(\.\d+)?)%$`)
+	// vttLineNumber is a line number, counted from the bottom when negative
+	vttLineNumber = regexp.MustCompile(`^-?\d{1,3}$`)
+	vttAlignments = []string{"start", "center", "end", "left", "right"}
+)
+
+// VTTCueSettings positions WebVTT cues; empty settings are left to the player
+type VTTCueSettings struct {
+	Line     string `json:"line,omitempty"`     // a line number or percentage from the top, e.g. "-2" or "85%"
+	Position string `json:"position,omitempty"` // a percentage across the video, e.g. "50%"
+	Size     string `json:"size,omitempty"`     // width as a percentage of the video
+	Align    string `json:"align,omitempty"`    // start, center, end, left or right
+}
+
+// Validate checks the settings against the WebVTT cue setting syntax
+func (s VTTCueSettings) Validate() error {
+	if s.Line != "" && !vttPercentage.MatchString(s.Line) && !vttLineNumber.MatchString(s.Line) {
+		return fmt.Errorf("invalid cue line %q: use a line number or a percentage", s.Line)
+	}
+	if s.Position != "" && !vttPercentage.MatchString(s.Position) {
+		return fmt.Errorf("invalid cue position %q: use a percentage", s.Position)
+	}
+	if s.Size != "" && !vttPercentage.MatchString(s.Size) {
+		return fmt.Errorf("invalid cue size %q: use a percentage", s.Size)
+	}
+	if s.Align != "" && !slices.Contains(vttAlignments, s.Align) {
+		return fmt.Errorf("invalid cue alignment %q: use one of %s", s.Align, strings.Join(vttAlignments, ", "))
+	}
+	return nil
+}
+
+// String returns the settings as written after a cue's timings, with a leading
+// space, or "" when there are none
+func (s VTTCueSettings) String() string {
+	var b strings.Builder
+	for _, setting := range []struct{ name, value string }{
+		{"line", s.Line}, {"position", s.Position}, {"size", s.Size}, {"align", s.Align},
+	} {
+		if setting.value != "" {
+			fmt.Fprintf(&b, " %s:%s", setting.name, setting.value)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+// Cache stores LLM replies by request hash
+type Cache interface {
+	FindLLMOutput(ctx context.Context, hash string) (*models.LLMOutput, error)
+	SaveLLMOutput(ctx context.Context, output *models.LLMOutput) error
+}
+
+// RequestHash identifies a request by the endpoint it is sent to, its model and
+// its messages. Endpoints can serve different models under the same name, so
+// replies are not shared between providers or base URLs.
+func RequestHash(provider, baseURL, model string, messages []ChatMessage) string {
+	h := sha256.New()
+	for _, part := range []string{strings.ToLower(provider), baseURL, model} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, m := range messages {
+		// Separators keep ("ab", "c") and ("a", "bc") apart
+		h.Write([]byte{0})
+		h.Write([]byte(m.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedService answers repeated deterministic requests from a cache
+type cachedService struct {
+	Service
+	cache    Cache
+	provider string
+	baseURL  string
+}
+
+// WithCache wraps svc, built from cfg, so that requests at temperature 0 are
+// answered from cache when the same model at the same endpoint has seen the
+// same messages before. Summaries and extractions send a transcript chunk per
+// request, so after an edit only the changed chunks reach the LLM again. Chats
+// are not served through a cache.
+func WithCache(svc Service, cache Cache, cfg *models.LLMConfig) Service {
+	baseURL := cfg.BaseURL
+	if strings.EqualFold(cfg.Provider, "openai") {
+		baseURL = cfg.OpenAIBaseURL
+	}
+	cached := &cachedService{Service: svc, cache: cache, provider: cfg.Provider}
+	if baseURL != nil {
+		cached.baseURL = strings.TrimRight(*baseURL, "/")
+	}
+	return cached
+}
+
+func (s *cachedService) hash(model string, messages []ChatMessage) string {
+	return RequestHash(s.provider, s.baseURL, model, messages)
+}
+
+func (s *cachedService) lookup(ctx context.Context, hash string) (string, bool) {
+	output, err := s.cache.FindLLMOutput(ctx, hash)
+	if err != nil || output == nil {
+		return "", false
+	}
+	return output.Output, true
+}
+
+func (s *cachedService) store(ctx context.Context, hash, model, content string) {
+	if strings.TrimSpace(content) == "" {
+		return
+	}
+	if err := s.cache.SaveLLMOutput(ctx, &models.LLMOutput{Hash: hash, Model: model, Output: content}); err != nil {
+		logger.Warn("Failed to cache LLM output", "model", model, "error", err)
+	}
+}
+
+// ChatCompletion answers from cache, or asks the LLM and caches its reply
+func (s *cachedService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	if temperature != 0 {
+		return s.Service.ChatCompletion(ctx, model, messages, temperature)
+	}
+	hash := s.hash(model, messages)
+	if content, ok := s.lookup(ctx, hash); ok {
+		resp := &ChatResponse{Model: model}
+		resp.Choices = make([]struct {
+			Index   int `json:"index"`
+			Message struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		}, 1)
+		resp.Choices[0].Message.Role = "assistant"
+		resp.Choices[0].Message.Content = content
+		resp.Choices[0].FinishReason = "stop"
+		return resp, nil
+	}
+
+	resp, err := s.Service.ChatCompletion(ctx, model, messages, temperature)
+	if err == nil && resp != nil && len(resp.Choices) > 0 {
+		s.store(ctx, hash, model, resp.Choices[0].Message.Content)
+	}
+	return resp, err
+}
+
+// ChatCompletionStream replays a cached reply as a single chunk, or streams the
+// LLM's reply and caches it once the stream ends without error
+func (s *cachedService) ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error) {
+	if temperature != 0 {
+		return s.Service.ChatCompletionStream(ctx, model, messages, temperature)
+	}
+	hash := s.hash(model, messages)
+	if content, ok := s.lookup(ctx, hash); ok {
+		contentChan := make(chan string, 1)
+		contentChan <- content
+		close(contentChan)
+		// The error channel is left open: a closed one could be read before the
+		// buffered reply, ending the stream early
+		return contentChan, make(chan error)
+	}
+
+	upstream, upstreamErr := s.Service.ChatCompletionStream(ctx, model, messages, temperature)
+	contentChan := make(chan string, 100)
+	errorChan := make(chan error, 1)
+	go func() {
+		// The content channel closes first, so readers drain it before seeing
+		// the closed error channel
+		defer close(errorChan)
+		defer close(contentChan)
+
+		var b strings.Builder
+		for upstream != nil || upstreamErr != nil {
+			select {
+			case chunk, ok := <-upstream:
+				if !ok {
+					upstream = nil
+					continue
+				}
+				b.WriteString(chunk)
+				contentChan <- chunk
+			case err, ok := <-upstreamErr:
+				if !ok {
+					upstreamErr = nil
+					continue
+				}
+				if err != nil {
+					errorChan <- err
+					return
+				}
+			}
+		}
+		if ctx.Err() == nil {
+			s.store(ctx, hash, model, b.String())
+		}
+	}()
+	return contentChan, errorChan
+}
@@ -1,6 +1,12 @@
 package llm
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/models"
+)
 
 // Service is a provider-agnostic LLM interface
 type Service interface {
@@ -9,3 +15,21 @@ type Service interface {
 	ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error)
 	GetContextWindow(ctx context.Context, model string) (int, error)
 }
+
+// NewFromConfig returns the service for a stored LLM configuration
+func NewFromConfig(cfg *models.LLMConfig) (Service, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "openai":
+		if cfg.APIKey == nil || *cfg.APIKey == "" {
+			return nil, fmt.Errorf("OpenAI API key not configured")
+		}
+		return withTracing(NewOpenAIService(*cfg.APIKey, cfg.OpenAIBaseURL), "openai"), nil
+	case "ollama":
+		if cfg.BaseURL == nil || *cfg.BaseURL == "" {
+			return nil, fmt.Errorf("Ollama base URL not configured")
+		}
+		return withTracing(NewOllamaService(*cfg.BaseURL), "ollama"), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
+	}
+}
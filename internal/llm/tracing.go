@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+
+	"scriberr/pkg/tracing"
+)
+
+// tracedService records a span per LLM call
+type tracedService struct {
+	Service
+	provider string
+}
+
+// withTracing wraps svc so its completions are traced under the caller's span
+func withTracing(svc Service, provider string) Service {
+	return &tracedService{Service: svc, provider: provider}
+}
+
+func (s *tracedService) start(ctx context.Context, name, model string, messages []ChatMessage) (context.Context, *tracing.Span) {
+	return tracing.StartKind(ctx, name, tracing.KindClient,
+		tracing.String("gen_ai.system", s.provider),
+		tracing.String("gen_ai.request.model", model),
+		tracing.Int("gen_ai.request.messages", len(messages)),
+	)
+}
+
+// ChatCompletion traces a non-streaming completion
+func (s *tracedService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	ctx, span := s.start(ctx, "llm.chat_completion", model, messages)
+	defer span.End()
+	resp, err := s.Service.ChatCompletion(ctx, model, messages, temperature)
+	span.RecordError(err)
+	if resp != nil && resp.Usage.TotalTokens > 0 {
+		span.SetAttributes(
+			tracing.Int("gen_ai.usage.input_tokens", resp.Usage.PromptTokens),
+			tracing.Int("gen_ai.usage.output_tokens", resp.Usage.CompletionTokens),
+		)
+	}
+	return resp, err
+}
+
+// ChatCompletionStream traces a streaming completion until its stream ends
+func (s *tracedService) ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error) {
+	ctx, span := s.start(ctx, "llm.chat_completion_stream", model, messages)
+	if span == nil {
+		return s.Service.ChatCompletionStream(ctx, model, messages, temperature)
+	}
+	upstream, upstreamErr := s.Service.ChatCompletionStream(ctx, model, messages, temperature)
+	contentChan := make(chan string, 100)
+	errorChan := make(chan error, 1)
+	go func() {
+		defer span.End()
+		defer close(errorChan)
+		defer close(contentChan)
+
+		for upstream != nil || upstreamErr != nil {
+			select {
+			case chunk, ok := <-upstream:
+				if !ok {
+					upstream = nil
+					continue
+				}
+				contentChan <- chunk
+			case err, ok := <-upstreamErr:
+				if !ok {
+					upstreamErr = nil
+					continue
+				}
+				if err != nil {
+					span.RecordError(err)
+					errorChan <- err
+					return
+				}
+			}
+		}
+	}()
+	return contentChan, errorChan
+}
@@ -0,0 +1,191 @@
+// Package mail sends plain-text email, optionally with attachments, through an
+// SMTP server.
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sendTimeout bounds a whole delivery, from dialing to QUIT
+const sendTimeout = 30 * time.Second
+
+// ErrNotConfigured is returned when sending without an SMTP server
+var ErrNotConfigured = errors.New("SMTP is not configured")
+
+// Config locates the SMTP server and the sender address
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Sender delivers messages through the configured server
+type Sender struct {
+	cfg Config
+}
+
+// NewSender creates a sender; without a host it reports itself disabled
+func NewSender(cfg Config) *Sender {
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	return &Sender{cfg: cfg}
+}
+
+// Enabled reports whether an SMTP server is configured
+func (s *Sender) Enabled() bool {
+	return s != nil && s.cfg.Host != "" && s.cfg.From != ""
+}
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Filename    string
+	ContentType string // e.g. text/plain; charset=utf-8
+	Data        []byte
+}
+
+// Send emails body to the recipients. STARTTLS is used whenever the server
+// offers it, and credentials are only sent over TLS.
+func (s *Sender) Send(ctx context.Context, to []string, subject, body string, attachments ...Attachment) error {
+	if !s.Enabled() {
+		return ErrNotConfigured
+	}
+	if len(to) == 0 {
+		return errors.New("no recipients")
+	}
+	msg, err := buildMessage(s.cfg.From, to, subject, body, time.Now(), attachments...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		// PlainAuth itself refuses to send credentials unencrypted to remote hosts
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.cfg.From); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage formats a plain-text UTF-8 message, as multipart/mixed when it
+// has attachments, refusing header values that would inject headers of their own
+func buildMessage(from string, to []string, subject, body string, date time.Time, attachments ...Attachment) ([]byte, error) {
+	values := append([]string{from, subject}, to...)
+	for _, attachment := range attachments {
+		values = append(values, attachment.Filename, attachment.ContentType)
+	}
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errors.New("header values must not contain line breaks")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + encodeHeader(subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\n", "\r\n")
+	if len(attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+		b.WriteString(body)
+		return []byte(b.String()), nil
+	}
+
+	var random [12]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return nil, err
+	}
+	boundary := "scriberr-" + hex.EncodeToString(random[:])
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"" + boundary + "\"\r\n\r\n")
+	b.WriteString("--" + boundary + "\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(body + "\r\n")
+	for _, attachment := range attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		b.WriteString("--" + boundary + "\r\n")
+		b.WriteString("Content-Type: " + contentType + "\r\n")
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		b.WriteString("Content-Disposition: " + mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}) + "\r\n\r\n")
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			b.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		b.WriteString(encoded + "\r\n")
+	}
+	b.WriteString("--" + boundary + "--\r\n")
+	return []byte(b.String()), nil
+}
+
+// encodeHeader encodes non-ASCII header text as an RFC 2047 encoded word
+func encodeHeader(value string) string {
+	for _, r := range value {
+		if r > 127 {
+			return mime.QEncoding.Encode("UTF-8", value)
+		}
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	msg, err := buildMessage("reports@example.com", []string{"a@example.com", "b@example.com"}, "Weekly report – ops", "line one\nline two", date)
+	require.NoError(t, err)
+
+	text := string(msg)
+	assert.Contains(t, text, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, text, "Subject: =?UTF-8?q?Weekly_report_=E2=80=93_ops?=\r\n")
+	assert.True(t, strings.HasSuffix(text, "\r\n\r\nline one\r\nline two"))
+
+	_, err = buildMessage("reports@example.com", []string{"a@example.com\r\nBcc: x@example.com"}, "Report", "", date)
+	assert.Error(t, err)
+}
+
+func TestBuildMessageWithAttachment(t *testing.T) {
+	date := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	transcript := strings.Repeat("Hello there. ", 20)
+	msg, err := buildMessage("jobs@example.com", []string{"a@example.com"}, "Transcription completed", "See attached", date,
+		Attachment{Filename: "Standup.txt", ContentType: "text/plain; charset=utf-8", Data: []byte(transcript)})
+	require.NoError(t, err)
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(msg))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	part, err := reader.NextPart()
+	require.NoError(t, err)
+	body, _ := io.ReadAll(part)
+	assert.Equal(t, "See attached", strings.TrimSpace(string(body)))
+
+	part, err = reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "Standup.txt", part.FileName())
+	encoded, _ := io.ReadAll(part)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, transcript, string(data))
+
+	_, err = reader.NextPart()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = buildMessage("jobs@example.com", []string{"a@example.com"}, "Report", "", date, Attachment{Filename: "a.txt\r\nBcc: x@example.com"})
+	assert.Error(t, err)
+}
+
+func TestSendWithoutServer(t *testing.T) {
+	sender := NewSender(Config{})
+	assert.False(t, sender.Enabled())
+	assert.ErrorIs(t, sender.Send(context.Background(), []string{"a@example.com"}, "Report", ""), ErrNotConfigured)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AdapterConfig overrides how an adapter is registered, set through the admin
+// API instead of the environment. Adapters without one keep the environment's
+// settings.
+type AdapterConfig struct {
+	ModelID string `json:"model_id" gorm:"primaryKey;type:varchar(100)"`
+	Enabled bool   `json:"enabled" gorm:"not null;default:true"`
+	// Endpoint is where a remote adapter is reached: a base URL, a RunPod
+	// endpoint ID or a Modal app name; nil keeps the environment's
+	Endpoint *string `json:"endpoint,omitempty" gorm:"type:text"`
+	// APIKey authenticates with the endpoint; nil keeps the environment's
+	APIKey    *string   `json:"-" gorm:"type:text"`
+	UpdatedBy string    `json:"updated_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,36 @@
+package models
+
+// Usage analytics intervals
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week" // starting on Monday
+	IntervalMonth = "month"
+)
+
+// Usage analytics groupings
+const (
+	UsageByAdapter = "adapter"
+	UsageByProfile = "profile"
+	UsageByUser    = "user"
+)
+
+// UsageSummary is what the executions of an interval did, overall or for one
+// adapter, profile or user. Replays are not counted.
+type UsageSummary struct {
+	// Period is the first day of the interval (2006-01-02)
+	Period string `json:"period"`
+	// Key is the adapter model ID, profile ID or user ID when grouped; empty
+	// for jobs of no profile or user
+	Key        string `json:"key,omitempty"`
+	Name       string `json:"name,omitempty"` // of the profile or user
+	Jobs       int64  `json:"jobs"`
+	Executions int64  `json:"executions"`
+	Failed     int64  `json:"failed"`
+	// FailureRate is the share of executions that failed
+	FailureRate float64 `json:"failure_rate"`
+	Minutes     float64 `json:"minutes"` // of audio transcribed
+	// AverageLatencySeconds is how long completed executions took on average
+	AverageLatencySeconds float64 `json:"average_latency_seconds"`
+	// Languages counts the jobs transcribed by the language detected or given
+	Languages map[string]int64 `json:"languages" gorm:"-"`
+}
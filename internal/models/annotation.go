@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Annotation is a comment anchored to a segment or time range of a transcript.
+// Top-level annotations open a thread; replies point at it with ParentID and
+// share its anchor. Only threads are resolved.
+type Annotation struct {
+	ID              string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionID string  `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	ParentID        *string `json:"parent_id,omitempty" gorm:"type:varchar(36);index"`
+
+	// Anchor: the segment commented on, if any, and the time range it covers (in seconds)
+	SegmentIndex *int    `json:"segment_index,omitempty" gorm:"type:int"`
+	StartTime    float64 `json:"start_time" gorm:"type:real;not null"`
+	EndTime      float64 `json:"end_time" gorm:"type:real;not null"`
+
+	// Author is the username, or the API key name, of whoever wrote the comment
+	Author  string `json:"author" gorm:"type:varchar(100);not null"`
+	Content string `json:"content" gorm:"type:text;not null"`
+	// Mentions lists the users @mentioned in Content
+	Mentions *string `json:"-" gorm:"type:text"` // JSON-serialized []string
+
+	Resolved   bool       `json:"resolved" gorm:"type:boolean;not null;default:false"`
+	ResolvedBy *string    `json:"resolved_by,omitempty" gorm:"type:varchar(100)"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	Transcription TranscriptionJob `json:"-" gorm:"foreignKey:TranscriptionID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (a *Annotation) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Audit actions
+const (
+	AuditTranscriptFinalized   = "transcript.finalized"
+	AuditTranscriptUnfinalized = "transcript.unfinalized"
+	// AuditFinalizedJobModified records an admin changing a finalized job
+	AuditFinalizedJobModified = "transcript.finalized_modified"
+	AuditCustodyExported      = "transcript.custody_exported"
+
+	AuditJobCreated       = "job.created"
+	AuditJobDeleted       = "job.deleted"
+	AuditTranscriptEdited = "transcript.edited"
+	AuditAPIKeyCreated    = "api_key.created"
+	AuditAPIKeyDeleted    = "api_key.deleted"
+	AuditUserCreated      = "user.created"
+	AuditUserUpdated      = "user.updated"
+	AuditUserDeleted      = "user.deleted"
+	AuditPasswordChanged  = "auth.password_changed"
+	AuditUsernameChanged  = "auth.username_changed"
+	// AuditChatRefused records a chat message refused for raising a banned topic
+	AuditChatRefused = "chat.refused"
+)
+
+// AuditEvent records who did what to which resource, and when
+type AuditEvent struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Action         string    `json:"action" gorm:"type:varchar(50);not null;index"`
+	Actor          string    `json:"actor" gorm:"type:varchar(100);not null"` // username or API key name
+	ResourceType   string    `json:"resource_type" gorm:"type:varchar(50);not null"`
+	ResourceID     string    `json:"resource_id" gorm:"type:varchar(64);not null;index"`
+	OrganizationID string    `json:"organization_id,omitempty" gorm:"type:varchar(36);not null;default:'';index"`
+	Details        *string   `json:"details,omitempty" gorm:"type:text"` // JSON-serialized map[string]interface{}
+	ClientIP       string    `json:"client_ip,omitempty" gorm:"type:varchar(64)"`
+	Method         string    `json:"method,omitempty" gorm:"type:varchar(10)"`
+	Path           string    `json:"path,omitempty" gorm:"type:varchar(255)"`
+	Status         int       `json:"status,omitempty"`
+	UserAgent      string    `json:"user_agent,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AWSTagRoute applies a team's policy to jobs submitted through the AWS
+// Transcribe compatible endpoint with a tag, e.g. CostCenter=research: the
+// profile they are transcribed with, the bucket results are written to and a
+// monthly quota of their own. Of the routes matching a job's tags, the one with
+// the highest priority applies; jobs matching none use the default profile.
+type AWSTagRoute struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID string `json:"organization_id" gorm:"type:varchar(36);not null;default:'';index"` // empty outside every organization
+	Name           string `json:"name" gorm:"type:varchar(100);not null"`
+	TagKey         string `json:"tag_key" gorm:"type:varchar(128);not null"`
+	TagValue       string `json:"tag_value,omitempty" gorm:"type:varchar(256)"` // empty: any value
+	Priority       int    `json:"priority" gorm:"not null;default:0"`
+	// ProfileID replaces the default profile; OutputBucketName replaces the
+	// bucket the request names
+	ProfileID        *string `json:"profile_id,omitempty" gorm:"type:varchar(36)"`
+	OutputBucketName *string `json:"output_bucket_name,omitempty" gorm:"type:text"`
+	// MonthlyQuotaMinutes caps the audio the route's jobs may transcribe per
+	// calendar month, on top of the workspace quota; 0: unlimited
+	MonthlyQuotaMinutes int       `json:"monthly_quota_minutes" gorm:"not null;default:0"`
+	CreatedBy           string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt           time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// UsedMinutes is the audio the route's jobs transcribed this month, when listed
+	UsedMinutes float64 `json:"used_minutes" gorm:"-"`
+}
+
+// Matches reports whether a job with tags falls under the route
+func (r *AWSTagRoute) Matches(tags map[string]string) bool {
+	value, ok := tags[r.TagKey]
+	return ok && (r.TagValue == "" || r.TagValue == value)
+}
+
+// QuotaKey is what the route's usage is recorded under, alongside the
+// workspaces' usage
+func (r *AWSTagRoute) QuotaKey() string {
+	return AWSTagRouteQuotaKey(r.ID)
+}
+
+// AWSTagRouteQuotaKey returns what a route's usage is recorded under
+func AWSTagRouteQuotaKey(id uint) string {
+	return fmt.Sprintf("awsroute-%d", id)
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Benchmark runs a reference audio set through several transcription adapters
+// and scores each transcript against the sample's ground truth, so adapters
+// can be compared on the same audio before one is made the default
+type Benchmark struct {
+	ID   string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name string `json:"name" gorm:"type:varchar(200);not null;default:''"`
+	// ModelIDs are the comma-separated transcription adapters compared
+	ModelIDs string `json:"model_ids" gorm:"type:text;not null"`
+	// Parameters every adapter is called with, converted for each; diarization is skipped
+	Parameters WhisperXParams `json:"parameters" gorm:"embedded;embeddedPrefix:param_"`
+
+	Status       JobStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	ErrorMessage *string   `json:"error_message,omitempty" gorm:"type:text"`
+	CreatedBy    string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Samples []BenchmarkSample `json:"samples,omitempty" gorm:"foreignKey:BenchmarkID;constraint:OnDelete:CASCADE"`
+	Runs    []BenchmarkRun    `json:"runs,omitempty" gorm:"foreignKey:BenchmarkID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (b *Benchmark) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ModelIDList returns the adapters the benchmark compares
+func (b *Benchmark) ModelIDList() []string {
+	var modelIDs []string
+	for _, modelID := range strings.Split(b.ModelIDs, ",") {
+		if modelID = strings.TrimSpace(modelID); modelID != "" {
+			modelIDs = append(modelIDs, modelID)
+		}
+	}
+	return modelIDs
+}
+
+// BenchmarkSample is an audio file of a benchmark with its ground truth
+type BenchmarkSample struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	BenchmarkID string `json:"benchmark_id" gorm:"type:varchar(36);not null;index"`
+	Name        string `json:"name" gorm:"type:varchar(255);not null"` // the uploaded file name
+	AudioPath   string `json:"-" gorm:"type:text;not null"`
+	// Reference is the ground-truth transcript the adapters are scored against
+	Reference    string   `json:"reference" gorm:"type:text;not null"`
+	AudioSeconds *float64 `json:"audio_seconds,omitempty"`
+}
+
+// BenchmarkRun is one adapter transcribing one sample of a benchmark
+type BenchmarkRun struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	BenchmarkID  string    `json:"benchmark_id" gorm:"type:varchar(36);not null;index"`
+	SampleID     uint      `json:"sample_id" gorm:"not null"`
+	ModelID      string    `json:"model_id" gorm:"type:varchar(100);not null"`
+	Status       JobStatus `json:"status" gorm:"type:varchar(20);not null"`
+	ErrorMessage *string   `json:"error_message,omitempty" gorm:"type:text"`
+
+	// Text is the adapter's transcript
+	Text *string `json:"text,omitempty" gorm:"type:text"`
+	// Word and character errors against the sample's reference, and the
+	// resulting error rates
+	ReferenceWords int      `json:"reference_words"`
+	WordErrors     int      `json:"word_errors"`
+	WER            *float64 `json:"wer,omitempty"`
+	ReferenceChars int      `json:"reference_chars"`
+	CharErrors     int      `json:"char_errors"`
+	CER            *float64 `json:"cer,omitempty"`
+
+	ProcessingDuration *int64   `json:"processing_duration,omitempty"` // milliseconds
+	Cost               *float64 `json:"cost,omitempty"`                // at ADAPTER_COST_PER_MINUTE; unset when unpriced
+
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
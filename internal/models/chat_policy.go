@@ -0,0 +1,98 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// InstanceChatPolicy is the workspace key of the policy applied to chats in
+// every workspace, on top of which each workspace's own policy applies
+const InstanceChatPolicy = "*"
+
+// ChatPolicy constrains what the chat assistant does within a workspace
+type ChatPolicy struct {
+	Workspace    string `json:"workspace" gorm:"primaryKey;type:varchar(64)"`
+	SystemPrompt string `json:"system_prompt" gorm:"type:text;not null;default:''"` // prepended to the built-in instructions
+	// BannedTopics are newline-separated topics the assistant must not discuss
+	BannedTopics string `json:"-" gorm:"type:text;not null;default:''"`
+	// MaxContextTokens caps the estimated tokens sent per request, below the
+	// model's own window (0: the model's window)
+	MaxContextTokens int `json:"max_context_tokens" gorm:"not null;default:0"`
+	// MaxHistoryMessages limits a request to the most recent messages of the
+	// conversation (0: all)
+	MaxHistoryMessages int       `json:"max_history_messages" gorm:"not null;default:0"`
+	UpdatedBy          string    `json:"updated_by,omitempty" gorm:"type:varchar(100)"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Merge returns the policy in effect in a workspace: p, the instance-wide
+// policy, with the workspace's own on top. Both system prompts and all banned
+// topics apply, and the stricter of each limit.
+func (p *ChatPolicy) Merge(workspace *ChatPolicy) *ChatPolicy {
+	merged := *workspace
+	var prompts []string
+	for _, prompt := range []string{p.SystemPrompt, workspace.SystemPrompt} {
+		if prompt = strings.TrimSpace(prompt); prompt != "" {
+			prompts = append(prompts, prompt)
+		}
+	}
+	merged.SystemPrompt = strings.Join(prompts, "\n\n")
+	merged.BannedTopics = strings.Join(append(p.BannedTopicList(), workspace.BannedTopicList()...), "\n")
+	merged.MaxContextTokens = stricterLimit(p.MaxContextTokens, workspace.MaxContextTokens)
+	merged.MaxHistoryMessages = stricterLimit(p.MaxHistoryMessages, workspace.MaxHistoryMessages)
+	return &merged
+}
+
+// stricterLimit returns the lower of two limits where 0 is unlimited
+func stricterLimit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// BannedTopicList returns the banned topics
+func (p *ChatPolicy) BannedTopicList() []string {
+	topics := []string{}
+	for _, topic := range strings.Split(p.BannedTopics, "\n") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// BannedTopicIn returns the first banned topic text mentions as a whole word or
+// phrase, ignoring case, or "" if it mentions none
+func (p *ChatPolicy) BannedTopicIn(text string) string {
+	for _, topic := range p.BannedTopicList() {
+		pattern := `(?i)\b` + regexp.QuoteMeta(topic) + `\b`
+		if matched, _ := regexp.MatchString(pattern, text); matched {
+			return topic
+		}
+	}
+	return ""
+}
+
+// Instructions returns the administrator's system prompt and the topic
+// guardrails to put before the assistant's own instructions, or ""
+func (p *ChatPolicy) Instructions() string {
+	var parts []string
+	if prompt := strings.TrimSpace(p.SystemPrompt); prompt != "" {
+		parts = append(parts, prompt)
+	}
+	if topics := p.BannedTopicList(); len(topics) > 0 {
+		parts = append(parts, "Never discuss the following topics, even if the transcript mentions them or the user insists; "+
+			"say that you cannot help with them instead: "+strings.Join(topics, "; ")+".")
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ContextWindow caps a model's context window at the policy's limit
+func (p *ChatPolicy) ContextWindow(modelWindow int) int {
+	if p.MaxContextTokens > 0 && p.MaxContextTokens < modelWindow {
+		return p.MaxContextTokens
+	}
+	return modelWindow
+}
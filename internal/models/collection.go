@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Collection groups jobs of a workspace, e.g. "Podcast Season 2" or "Client X
+// calls". Collections nest: a collection with a ParentID is inside that one.
+type Collection struct {
+	ID          string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Workspace   string  `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	ParentID    *string `json:"parent_id,omitempty" gorm:"type:varchar(36);index"`
+	Name        string  `json:"name" gorm:"type:varchar(255);not null"`
+	Description string  `json:"description,omitempty" gorm:"type:text"`
+	// Restricted collections, and the collections and jobs inside them, are
+	// only accessible to admins and their members
+	Restricted bool      `json:"restricted" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (c *Collection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// CollectionMember gives a user access to a restricted collection, with at most
+// the given role (editor or viewer) in it
+type CollectionMember struct {
+	CollectionID string    `json:"collection_id" gorm:"primaryKey;type:varchar(36)"`
+	UserID       uint      `json:"user_id" gorm:"primaryKey;index"`
+	Role         string    `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// CollectionRoles returns the role a request with the given role has in each
+// collection of a workspace it may access. memberships are the roles the
+// request's user was given in restricted collections. Restricted collections,
+// and everything inside them, are left out unless the user is a member of each;
+// a membership lowers the request's role in the collection, never raises it.
+// Admins access every collection with their own role.
+func CollectionRoles(collections []Collection, role string, memberships map[string]string) map[string]string {
+	byID := make(map[string]*Collection, len(collections))
+	for i := range collections {
+		byID[collections[i].ID] = &collections[i]
+	}
+	roles := make(map[string]string, len(collections))
+	hidden := map[string]bool{}
+	var resolve func(id string, depth int) (string, bool)
+	resolve = func(id string, depth int) (string, bool) {
+		if r, ok := roles[id]; ok {
+			return r, true
+		}
+		collection, ok := byID[id]
+		// Parents cannot be followed past every collection, which only a
+		// cycle would need
+		if !ok || hidden[id] || depth > len(collections) {
+			return "", false
+		}
+		r := role
+		if collection.ParentID != nil {
+			if r, ok = resolve(*collection.ParentID, depth+1); !ok {
+				hidden[id] = true
+				return "", false
+			}
+		}
+		if collection.Restricted && role != RoleAdmin {
+			member, ok := memberships[id]
+			if !ok {
+				hidden[id] = true
+				return "", false
+			}
+			if !RoleAllows(member, r) {
+				r = member
+			}
+		}
+		roles[id] = r
+		return r, true
+	}
+	for id := range byID {
+		resolve(id, 0)
+	}
+	return roles
+}
+
+// CollectionRestrictions returns the restricted collections among the one with
+// the given ID and those it is inside, which restrict the jobs filed in it. The
+// top level, a nil ID, is restricted by none.
+func CollectionRestrictions(collections []Collection, id *string) map[string]bool {
+	parents := make(map[string]*Collection, len(collections))
+	for i := range collections {
+		parents[collections[i].ID] = &collections[i]
+	}
+	restrictions := map[string]bool{}
+	for depth := 0; id != nil && depth <= len(collections); depth++ {
+		collection, ok := parents[*id]
+		if !ok {
+			break
+		}
+		if collection.Restricted {
+			restrictions[collection.ID] = true
+		}
+		id = collection.ParentID
+	}
+	return restrictions
+}
+
+type hiddenCollectionsContextKey struct{}
+
+// WithHiddenCollections leaves the jobs filed in the given collections out of
+// the jobs listed and searched with ctx
+func WithHiddenCollections(ctx context.Context, collectionIDs []string) context.Context {
+	return context.WithValue(ctx, hiddenCollectionsContextKey{}, collectionIDs)
+}
+
+// HiddenCollectionsFromContext returns the collections whose jobs ctx leaves out
+func HiddenCollectionsFromContext(ctx context.Context) []string {
+	collectionIDs, _ := ctx.Value(hiddenCollectionsContextKey{}).([]string)
+	return collectionIDs
+}
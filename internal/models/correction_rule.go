@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// CorrectionRule finds and replaces text in an organization's transcripts once
+// they are transcribed, e.g. "scribber" with "Scriberr". Exact rules match
+// their text as a whole word or phrase; regex rules match a regular expression
+// and may refer to its groups ($1) in their replacement. Rules apply in order
+// of position, then creation.
+type CorrectionRule struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID string `json:"organization_id" gorm:"type:varchar(36);not null;default:'';index"` // empty outside every organization
+	Name           string `json:"name" gorm:"type:varchar(100);not null;default:''"`
+	Find           string `json:"find" gorm:"type:text;not null"`
+	Replace        string `json:"replace" gorm:"type:text;not null;default:''"`
+	Regex          bool   `json:"regex" gorm:"not null;default:false"`
+	CaseSensitive  bool   `json:"case_sensitive" gorm:"not null;default:false"`
+	// Enabled has no database default, which GORM would apply to disabled rules
+	Enabled   bool      `json:"enabled" gorm:"not null"`
+	Position  int       `json:"position" gorm:"not null;default:0"`
+	CreatedBy string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// Cost report groupings
+const (
+	CostByDay     = "day"
+	CostByProfile = "profile"
+	CostByAPIKey  = "api_key"
+	CostByAdapter = "adapter"
+)
+
+// CostSummary is what the executions of a group of jobs are estimated to have
+// cost. Replays are not counted.
+type CostSummary struct {
+	// Key is the day (2006-01-02), profile ID, API key ID or adapter model ID;
+	// empty for jobs of no profile or key
+	Key     string  `json:"key"`
+	Name    string  `json:"name,omitempty"` // of the profile or API key
+	Jobs    int64   `json:"jobs"`
+	Minutes float64 `json:"minutes"` // of audio sent to the adapters
+	Cost    float64 `json:"cost"`
+}
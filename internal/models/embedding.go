@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// TranscriptChunk is a run of consecutive transcript segments embedded for
+// retrieval across all transcriptions
+type TranscriptChunk struct {
+	ID         uint    `json:"id" gorm:"primaryKey;autoIncrement"`
+	JobID      string  `json:"job_id" gorm:"type:varchar(36);not null;index"`
+	ChunkIndex int     `json:"chunk_index" gorm:"type:int;not null"`
+	StartTime  float64 `json:"start_time" gorm:"type:real"`
+	EndTime    float64 `json:"end_time" gorm:"type:real"`
+	Text       string  `json:"text" gorm:"type:text;not null"`
+	Model      string  `json:"model" gorm:"type:varchar(100);not null;index"`
+	// Little-endian float32 vector; decoded into Vector when read
+	Embedding []byte    `json:"-" gorm:"type:blob;not null"`
+	Vector    []float32 `json:"-" gorm:"-"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Job TranscriptionJob `json:"-" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
+
+// TranscriptIndexState records when a job's transcript was last embedded, so
+// edited transcripts and embedding model changes trigger re-indexing
+type TranscriptIndexState struct {
+	JobID      string    `json:"job_id" gorm:"primaryKey;type:varchar(36)"`
+	Model      string    `json:"model" gorm:"type:varchar(100);not null"`
+	ChunkCount int       `json:"chunk_count" gorm:"type:int;not null;default:0"`
+	IndexedAt  time.Time `json:"indexed_at"`
+
+	// Relationships
+	Job TranscriptionJob `json:"-" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "errors"
+
+// ErrorCode says why a job failed, for clients to branch on; ErrorMessage
+// carries the details for people
+type ErrorCode string
+
+const (
+	ErrorAudioDownloadFailed ErrorCode = "AUDIO_DOWNLOAD_FAILED" // the audio could not be fetched from its URL or S3
+	ErrorAdapterTimeout      ErrorCode = "ADAPTER_TIMEOUT"       // a model adapter did not finish in time
+	ErrorDiarizationOOM      ErrorCode = "DIARIZATION_OOM"       // diarization ran out of memory
+	ErrorInvalidParams       ErrorCode = "INVALID_PARAMS"        // an adapter rejected the job's parameters
+	ErrorAudioQuarantined    ErrorCode = "AUDIO_QUARANTINED"     // the upload scan flagged the audio
+	ErrorJobCancelled        ErrorCode = "JOB_CANCELLED"         // the job was cancelled or killed by a user
+	ErrorJobInterrupted      ErrorCode = "JOB_INTERRUPTED"       // the server or worker running the job stopped
+	ErrorTranscriptionFailed ErrorCode = "TRANSCRIPTION_FAILED"  // any other failure
+)
+
+// JobError is an error with the code recorded on the job it failed
+type JobError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *JobError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorCode gives err a code, unless it already has one: the code given
+// closest to the failure is the most precise
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil || ErrorCodeOf(err) != "" {
+		return err
+	}
+	return &JobError{Code: code, Err: err}
+}
+
+// ErrorCodeOf returns the code of err, or "" when it has none
+func ErrorCodeOf(err error) ErrorCode {
+	var jobErr *JobError
+	if errors.As(err, &jobErr) {
+		return jobErr.Code
+	}
+	return ""
+}
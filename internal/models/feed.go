@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// FeedSubscription is an RSS/podcast feed that is polled for new episodes
+type FeedSubscription struct {
+	ID    string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	URL   string `json:"url" gorm:"type:text;not null"`
+	Title string `json:"title" gorm:"type:varchar(255)"`
+	// Workspace of the subscriber; episode jobs are created in it
+	Workspace string `json:"workspace,omitempty" gorm:"type:varchar(64);index;default:''"`
+
+	// Profile whose parameters are used for episode jobs (default profile when empty)
+	ProfileID *string `json:"profile_id,omitempty" gorm:"type:varchar(36)"`
+
+	PollIntervalMinutes int `json:"poll_interval_minutes" gorm:"type:int;not null;default:60"`
+	// Number of most recent episodes to transcribe when the feed is first polled;
+	// older back-catalog items are recorded as seen and skipped
+	BackfillCount int  `json:"backfill_count" gorm:"type:int;not null;default:0"`
+	Active        bool `json:"active" gorm:"type:boolean;not null;default:true"`
+
+	LastPolledAt *time.Time `json:"last_polled_at,omitempty"`
+	LastError    *string    `json:"last_error,omitempty" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// FeedEpisode records a feed item that has been seen, and the job created for it
+type FeedEpisode struct {
+	ID             uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID string     `json:"subscription_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_feed_episode_guid"`
+	GUID           string     `json:"guid" gorm:"type:text;not null;uniqueIndex:idx_feed_episode_guid"`
+	Title          string     `json:"title" gorm:"type:text"`
+	PublishedAt    *time.Time `json:"published_at,omitempty"`
+	EnclosureURL   string     `json:"enclosure_url" gorm:"type:text"`
+	JobID          *string    `json:"job_id,omitempty" gorm:"type:varchar(36);index"`
+	Skipped        bool       `json:"skipped" gorm:"type:boolean;not null;default:false"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Subscription FeedSubscription `json:"-" gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Job fields that can be hidden from a role
+const (
+	JobFieldAudio      = "audio"      // the audio and where it came from
+	JobFieldTranscript = "transcript" // the transcript and everything derived from its text
+	JobFieldSummary    = "summary"    // summaries and action items
+)
+
+// JobFields lists the fields that can be hidden
+var JobFields = []string{JobFieldAudio, JobFieldTranscript, JobFieldSummary}
+
+// ValidJobField reports whether field is one of JobFields
+func ValidJobField(field string) bool {
+	for _, f := range JobFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldPermission hides fields of a workspace's jobs from the editors or
+// viewers of the workspace, e.g. the audio of HR-sensitive recordings or raw
+// transcripts where only summaries should be read. Admins see every field.
+type FieldPermission struct {
+	Workspace string `json:"workspace" gorm:"primaryKey;type:varchar(64)"`
+	Role      string `json:"role" gorm:"primaryKey;type:varchar(20)"`
+	// HiddenFields are newline-separated JobFields
+	HiddenFields string    `json:"-" gorm:"type:text;not null;default:''"`
+	UpdatedBy    string    `json:"updated_by,omitempty" gorm:"type:varchar(100)"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// HiddenFieldSet returns the hidden fields
+func (p *FieldPermission) HiddenFieldSet() JobFieldSet {
+	set := JobFieldSet{}
+	for _, field := range strings.Split(p.HiddenFields, "\n") {
+		if field = strings.TrimSpace(field); field != "" {
+			set[field] = true
+		}
+	}
+	return set
+}
+
+// JobFieldSet is a set of JobFields
+type JobFieldSet map[string]bool
+
+// List returns the fields in JobFields order
+func (s JobFieldSet) List() []string {
+	fields := []string{}
+	for _, field := range JobFields {
+		if s[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Redacted returns job as a role that may not see the hidden fields gets it: a
+// copy without them, or job itself when nothing is hidden
+func (job *TranscriptionJob) Redacted(hidden JobFieldSet) *TranscriptionJob {
+	if len(hidden) == 0 {
+		return job
+	}
+	redacted := *job
+	if hidden[JobFieldAudio] {
+		redacted.AudioPath = ""
+		redacted.AudioUri = nil
+		redacted.SourceURL = nil
+		redacted.VideoPath = nil
+		redacted.AupFilePath = nil
+		redacted.MultiTrackFolder = nil
+		redacted.MergedAudioPath = nil
+		redacted.AudioArchiveURI = nil
+		redacted.MultiTrackFiles = nil
+	}
+	if hidden[JobFieldTranscript] {
+		redacted.Transcript = nil
+		redacted.IndividualTranscripts = nil
+		redacted.SegmentSentiment = nil
+		redacted.Chapters = nil
+		redacted.Translation = nil
+	}
+	if hidden[JobFieldSummary] {
+		redacted.Summary = nil
+		redacted.ActionItems = nil
+	}
+	return &redacted
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Stages a processing job goes through, stored in TranscriptionJob.Stage
+const (
+	StageDownloading      = "downloading"       // fetching the audio from S3
+	StageConverting       = "converting"        // preprocessing the audio for the adapters
+	StageTranscribing     = "transcribing"      // speech recognition, locally or remotely
+	StageAligning         = "aligning"          // word-level alignment
+	StageDiarizing        = "diarizing"         // speaker diarization
+	StageUploadingResults = "uploading_results" // writing the transcript to the output bucket
+	// StagePostProcessing is the LLM automation run once a job completed,
+	// which is only timed, not entered
+	StagePostProcessing = "post_processing"
+)
+
+// JobStage is a stretch of time a job's latest run spent in a stage. A stage
+// can recur, e.g. once for each chunk of long audio transcribed in turn.
+type JobStage struct {
+	ID                 uint       `json:"-" gorm:"primaryKey;autoIncrement"`
+	TranscriptionJobID string     `json:"-" gorm:"type:varchar(36);not null;index"`
+	Stage              string     `json:"stage" gorm:"type:varchar(32);not null"`
+	StartedAt          time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt            *time.Time `json:"ended_at,omitempty"` // unset while the job is in the stage
+}
+
+// StageTimings are how long, in milliseconds, a job's latest run spent in each
+// stage, summed over the stage's stretches; stages it did not go through are
+// unset
+type StageTimings struct {
+	DownloadMs       *int64 `json:"download_ms,omitempty"`
+	ConversionMs     *int64 `json:"conversion_ms,omitempty"`
+	TranscriptionMs  *int64 `json:"transcription_ms,omitempty"`
+	AlignmentMs      *int64 `json:"alignment_ms,omitempty"`
+	DiarizationMs    *int64 `json:"diarization_ms,omitempty"`
+	UploadMs         *int64 `json:"upload_ms,omitempty"`
+	PostProcessingMs *int64 `json:"post_processing_ms,omitempty"`
+}
+
+// Add adds time spent in a stage
+func (t *StageTimings) Add(stage string, d time.Duration) {
+	var field **int64
+	switch stage {
+	case StageDownloading:
+		field = &t.DownloadMs
+	case StageConverting:
+		field = &t.ConversionMs
+	case StageTranscribing:
+		field = &t.TranscriptionMs
+	case StageAligning:
+		field = &t.AlignmentMs
+	case StageDiarizing:
+		field = &t.DiarizationMs
+	case StageUploadingResults:
+		field = &t.UploadMs
+	case StagePostProcessing:
+		field = &t.PostProcessingMs
+	default:
+		return
+	}
+	ms := d.Milliseconds()
+	if *field != nil {
+		ms += **field
+	}
+	*field = &ms
+}
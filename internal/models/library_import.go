@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// States of a library import
+const (
+	LibraryImportRunning   = "running"
+	LibraryImportCompleted = "completed"
+	LibraryImportFailed    = "failed"
+	LibraryImportCancelled = "cancelled"
+)
+
+// What a library import did with a file
+const (
+	LibraryImportFileQueued    = "queued"
+	LibraryImportFilePlanned   = "planned" // found by a dry run
+	LibraryImportFileDuplicate = "duplicate"
+	LibraryImportFileFailed    = "failed"
+)
+
+// LibraryImport walks an S3 prefix or a local directory and creates a job for
+// every audio file found, for onboarding an existing recording archive
+type LibraryImport struct {
+	ID        string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Workspace string  `json:"workspace" gorm:"type:varchar(100);not null;default:'';index"`
+	Source    string  `json:"source" gorm:"type:text;not null"` // s3://bucket/prefix or a directory
+	ProfileID *string `json:"profile_id,omitempty" gorm:"type:varchar(36)"`
+	// DryRun imports list the files they would queue without creating jobs
+	DryRun bool `json:"dry_run"`
+	// Dedup skips files already imported into the workspace, by location or content
+	Dedup         bool   `json:"dedup"`
+	RatePerMinute int    `json:"rate_per_minute"`
+	Status        string `json:"status" gorm:"type:varchar(20);not null;index"`
+
+	FilesFound int `json:"files_found"`
+	Queued     int `json:"queued"`
+	Duplicates int `json:"duplicates"`
+	Failed     int `json:"failed"`
+
+	Error      *string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedBy  string     `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// LibraryImportFile records a file found by a library import
+type LibraryImportFile struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	ImportID string `json:"import_id" gorm:"type:varchar(36);not null;index"`
+	// Workspace the file was imported into, for deduplication across imports
+	Workspace string `json:"workspace" gorm:"type:varchar(100);not null;default:'';index:idx_library_import_files_source"`
+	Source    string `json:"source" gorm:"type:text;not null;index:idx_library_import_files_source"`
+	Size      int64  `json:"size"`
+	// Fingerprint identifies the file's content: the S3 ETag with the size, or
+	// the SHA-256 of local files
+	Fingerprint string    `json:"fingerprint,omitempty" gorm:"type:varchar(100);index"`
+	Status      string    `json:"status" gorm:"type:varchar(20);not null"`
+	JobID       *string   `json:"job_id,omitempty" gorm:"type:varchar(36);index"`
+	Error       string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Channels whose messages can be templated
+const (
+	NotificationChannelWebhook     = "webhook"
+	NotificationChannelEventBridge = "eventbridge"
+	NotificationChannelEmail       = "email"
+)
+
+// NotificationChannels lists the templatable channels
+var NotificationChannels = []string{NotificationChannelWebhook, NotificationChannelEventBridge, NotificationChannelEmail}
+
+// NotificationTemplate replaces the built-in message of a channel with a Go
+// template rendered with the job's fields
+type NotificationTemplate struct {
+	Channel string `json:"channel" gorm:"primaryKey;type:varchar(32)"`
+	Body    string `json:"body" gorm:"type:text;not null"`
+	// ContentType is sent with webhook bodies; EventBridge details are always
+	// JSON and email bodies plain text
+	ContentType string    `json:"content_type" gorm:"type:varchar(100);not null;default:'application/json'"`
+	UpdatedBy   string    `json:"updated_by,omitempty" gorm:"type:varchar(100)"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Organization is a team sharing an instance with others. Its jobs, profiles, LLM
+// configuration and stored files are isolated from those of other organizations.
+type Organization struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// OrganizationMember makes a user a member of an organization
+type OrganizationMember struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID string    `json:"organization_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_org_member"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_org_member;index"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Organization Organization `json:"-" gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+	User         User         `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// organizationWorkspacePrefix starts the workspace of every organization's jobs
+const organizationWorkspacePrefix = "org-"
+
+// OrganizationWorkspace returns the workspace holding an organization's jobs and files
+func OrganizationWorkspace(organizationID string) string {
+	return organizationWorkspacePrefix + organizationID
+}
+
+// IsOrganizationWorkspace reports whether a workspace belongs to an organization
+func IsOrganizationWorkspace(workspace string) bool {
+	return strings.HasPrefix(workspace, organizationWorkspacePrefix)
+}
+
+// WorkspaceOrganization returns the organization owning a workspace, or "" for
+// workspaces of no organization
+func WorkspaceOrganization(workspace string) string {
+	if !IsOrganizationWorkspace(workspace) {
+		return ""
+	}
+	return strings.TrimPrefix(workspace, organizationWorkspacePrefix)
+}
+
+// UserWorkspace returns the workspace of a user acting outside every organization
+func UserWorkspace(userID uint) string {
+	return fmt.Sprintf("user-%d", userID)
+}
+
+// APIKeyWorkspace returns the workspace of an API key outside every organization
+func APIKeyWorkspace(keyID uint) string {
+	return fmt.Sprintf("apikey-%d", keyID)
+}
+
+// WorkspaceVisible reports whether the data of a workspace is visible to a
+// context: the organization ctx is scoped to sees only its own workspace, and a
+// context scoped to a workspace sees only that one and the legacy workspace
+// shared by jobs created before workspaces existed
+func WorkspaceVisible(ctx context.Context, workspace string) bool {
+	if organizationID, ok := OrganizationFromContext(ctx); ok && WorkspaceOrganization(workspace) != organizationID {
+		return false
+	}
+	if own, ok := WorkspaceFromContext(ctx); ok && workspace != own && workspace != "" {
+		return false
+	}
+	return true
+}
+
+type organizationContextKey struct{}
+
+// WithOrganization scopes ctx to an organization; an empty ID scopes it to the
+// data outside every organization. Contexts never scoped, such as those of
+// background work, see all data.
+func WithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationContextKey{}, organizationID)
+}
+
+// OrganizationFromContext returns the organization ctx is scoped to, if any
+func OrganizationFromContext(ctx context.Context) (string, bool) {
+	organizationID, ok := ctx.Value(organizationContextKey{}).(string)
+	return organizationID, ok
+}
+
+type workspaceContextKey struct{}
+
+// WithWorkspace scopes ctx to a single workspace. Requests of users and keys
+// outside every organization are scoped to their own workspace unless they are
+// admins, who see the data of every workspace outside the organizations.
+func WithWorkspace(ctx context.Context, workspace string) context.Context {
+	return context.WithValue(ctx, workspaceContextKey{}, workspace)
+}
+
+// WorkspaceFromContext returns the workspace ctx is scoped to, if any
+func WorkspaceFromContext(ctx context.Context) (string, bool) {
+	workspace, ok := ctx.Value(workspaceContextKey{}).(string)
+	return workspace, ok
+}
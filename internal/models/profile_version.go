@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ProfileVersion is a transcription profile as it was at one of its versions.
+// Every change of a profile's name, description or parameters is kept as its
+// next version, so jobs can be traced back to the exact parameters they ran
+// with and old behavior reproduced by cloning the version.
+type ProfileVersion struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	ProfileID   string         `json:"profile_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_profile_version"`
+	Version     int            `json:"version" gorm:"not null;uniqueIndex:idx_profile_version"` // from 1
+	Name        string         `json:"name" gorm:"type:varchar(255);not null"`
+	Description *string        `json:"description,omitempty" gorm:"type:text"`
+	Parameters  WhisperXParams `json:"parameters" gorm:"embedded;embeddedPrefix:param_"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// NewProfileVersion snapshots a profile at its current version
+func NewProfileVersion(profile *TranscriptionProfile) *ProfileVersion {
+	return &ProfileVersion{
+		ProfileID:   profile.ID,
+		Version:     profile.Version,
+		Name:        profile.Name,
+		Description: profile.Description,
+		Parameters:  profile.Parameters,
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// QuotaUsage is the audio a workspace, or the jobs of an AWS tag route, had
+// transcribed in a calendar month. It outlives the jobs, so deleting jobs does
+// not restore quota.
+type QuotaUsage struct {
+	Workspace    string    `json:"workspace" gorm:"primaryKey;type:varchar(64)"`
+	Month        string    `json:"month" gorm:"primaryKey;type:varchar(7)"` // YYYY-MM in UTC
+	AudioSeconds float64   `json:"audio_seconds" gorm:"not null;default:0"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// QuotaMonth returns the month that usage at t counts against
+func QuotaMonth(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// QuotaResetsAt returns when the month that t falls in ends
+func QuotaResetsAt(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
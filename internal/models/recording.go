@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// RecordingSession collects the MediaRecorder chunks of a recording made in the
+// browser until it is finalized into a transcription job
+type RecordingSession struct {
+	ID        string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Title     *string `json:"title,omitempty" gorm:"type:text"`
+	MimeType  string  `json:"mime_type" gorm:"type:varchar(100);not null"`
+	Workspace string  `json:"-" gorm:"type:varchar(64);index;default:''"`
+	FilePath  string  `json:"-" gorm:"type:text;not null"`
+
+	// ChunkCount is the index the next chunk must have; clients resume from it
+	// after a reload
+	ChunkCount int   `json:"chunk_count" gorm:"type:int;not null;default:0"`
+	Bytes      int64 `json:"bytes" gorm:"not null;default:0"`
+
+	// JobID is set once the recording has been finalized
+	JobID *string `json:"job_id,omitempty" gorm:"type:varchar(36)"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Formats a scheduled report is rendered in
+const (
+	ReportFormatMarkdown = "markdown"
+	ReportFormatJSON     = "json"
+)
+
+// ReportSchedule compiles a report on a workspace's activity whenever its cron
+// expression fires, and delivers it by email, webhook and/or S3
+type ReportSchedule struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Workspace string `json:"workspace,omitempty" gorm:"type:varchar(64);index;default:''"`
+	Name      string `json:"name" gorm:"type:varchar(255);not null"`
+	// Cron is a five-field cron expression, or @daily, @weekly and the like,
+	// evaluated in Timezone
+	Cron     string `json:"cron" gorm:"type:varchar(100);not null"`
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	Format   string `json:"format" gorm:"type:varchar(20);not null;default:'markdown'"`
+	// Keywords are newline-separated terms flagged when new transcripts mention them
+	Keywords string `json:"-" gorm:"type:text;not null;default:''"`
+	// EmailTo are newline-separated recipients
+	EmailTo    string `json:"-" gorm:"type:text;not null;default:''"`
+	WebhookURL string `json:"webhook_url,omitempty" gorm:"type:text"`
+	// S3URI is the s3://bucket/prefix reports are written under
+	S3URI   string `json:"s3_uri,omitempty" gorm:"type:text"`
+	Enabled bool   `json:"enabled" gorm:"type:boolean;not null;default:true"`
+
+	// LastRunAt ends the period the previous report covered, where the next begins
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty" gorm:"index"`
+	LastError *string    `json:"last_error,omitempty" gorm:"type:text"`
+
+	CreatedBy string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// KeywordList returns the flagged keywords
+func (r *ReportSchedule) KeywordList() []string {
+	return splitLines(r.Keywords)
+}
+
+// Recipients returns the email recipients
+func (r *ReportSchedule) Recipients() []string {
+	return splitLines(r.EmailTo)
+}
+
+func splitLines(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, "\n") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
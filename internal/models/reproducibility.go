@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Kinds of execution components
+const (
+	ComponentAdapter    = "adapter"
+	ComponentPackage    = "package"
+	ComponentCheckpoint = "checkpoint"
+)
+
+// ExecutionComponent is one thing an execution ran on: an adapter, a Python
+// package of its environment or a model checkpoint, with the version used.
+// Jobs produced by a version later found to be faulty are found through them.
+type ExecutionComponent struct {
+	ID                 uint      `json:"-" gorm:"primaryKey;autoIncrement"`
+	ExecutionID        string    `json:"execution_id" gorm:"type:varchar(36);not null;index"`
+	TranscriptionJobID string    `json:"transcription_job_id" gorm:"type:varchar(36);not null;index"`
+	Stage              string    `json:"stage" gorm:"type:varchar(20)"` // of the step, e.g. "transcription"
+	Kind               string    `json:"kind" gorm:"type:varchar(20);not null;index:idx_execution_component"`
+	Name               string    `json:"name" gorm:"type:varchar(200);not null;index:idx_execution_component"`
+	Version            string    `json:"version" gorm:"type:varchar(100);index:idx_execution_component"` // empty for checkpoints
+	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// ComponentVersion is a version of a component and how many jobs' latest
+// executions ran on it
+type ComponentVersion struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Jobs    int64  `json:"jobs"`
+}
+
+// ComponentJob is a job whose latest execution ran on a component version
+type ComponentJob struct {
+	JobID       string     `json:"job_id"`
+	Title       *string    `json:"title,omitempty"`
+	Status      JobStatus  `json:"status"`
+	Workspace   string     `json:"workspace,omitempty"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+	ExecutionID string     `json:"execution_id"`
+	Version     string     `json:"version"`
+	StartedAt   time.Time  `json:"started_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// What a retention policy removes
+const (
+	RetentionTargetAudio = "audio" // the job's audio files, keeping the transcript
+	RetentionTargetJob   = "job"   // the whole job
+)
+
+// What a retention policy does with what it removes
+const (
+	RetentionActionDelete  = "delete"
+	RetentionActionArchive = "archive" // copy to S3 in an archival storage class first
+)
+
+// RetentionPolicy removes the audio or whole jobs of an organization, or of
+// the workspaces outside every organization, once they are older than
+// AfterDays. A policy for a profile applies to the jobs transcribed with it in
+// place of the policy for all profiles, and a policy for a tag to the jobs
+// tagged with it in place of both. Policies that are not enforced are only
+// reported by the dry run.
+type RetentionPolicy struct {
+	ID             uint    `json:"id" gorm:"primaryKey"`
+	OrganizationID string  `json:"organization_id" gorm:"type:varchar(36);not null;default:'';index"` // empty outside every organization
+	ProfileID      *string `json:"profile_id,omitempty" gorm:"type:varchar(36)"`                      // nil: every profile
+	TagID          *string `json:"tag_id,omitempty" gorm:"type:varchar(36);index"`                    // nil: tagged or not
+	Target         string  `json:"target" gorm:"type:varchar(10);not null"`
+	Action         string  `json:"action" gorm:"type:varchar(10);not null"`
+	AfterDays      int     `json:"after_days" gorm:"not null"`
+	// ArchiveURI is the s3://bucket/prefix archived files are copied to, under
+	// <workspace>/<job ID>/
+	ArchiveURI string `json:"archive_uri,omitempty" gorm:"type:text"`
+	// StorageClass of archived objects, e.g. GLACIER or DEEP_ARCHIVE
+	StorageClass string    `json:"storage_class,omitempty" gorm:"type:varchar(32)"`
+	Enforced     bool      `json:"enforced" gorm:"not null;default:false"`
+	CreatedBy    string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Specificity ranks policies applying to the same job: a tag's policy wins over
+// a profile's own policy, which wins over the policy for every profile
+func (p *RetentionPolicy) Specificity() int {
+	specificity := 0
+	if p.TagID != nil {
+		specificity += 2
+	}
+	if p.ProfileID != nil {
+		specificity++
+	}
+	return specificity
+}
+
+// AppliesTo reports whether the policy covers a job of its organization,
+// whose tag assignments are loaded
+func (p *RetentionPolicy) AppliesTo(job *TranscriptionJob) bool {
+	if p.ProfileID != nil && (job.ProfileID == nil || *job.ProfileID != *p.ProfileID) {
+		return false
+	}
+	if p.TagID == nil {
+		return true
+	}
+	for _, assignment := range job.TagAssignments {
+		if assignment.TagID == *p.TagID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// SearchHighlight marks a matched term inside a snippet, as [Start, End) offsets
+// in Unicode code points
+type SearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchMatch is a single full-text hit within a transcription. Kind is one of
+// "title", "segment", "summary" or "note"; SourceID is the segment index or the
+// summary/note ID. Segment and note matches carry their timestamps in seconds.
+type SearchMatch struct {
+	Kind       string            `json:"kind"`
+	SourceID   string            `json:"source_id"`
+	StartTime  *float64          `json:"start_time,omitempty"`
+	EndTime    *float64          `json:"end_time,omitempty"`
+	Snippet    string            `json:"snippet"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// SearchResult groups the matches for one transcription, best match first
+type SearchResult struct {
+	JobID     string        `json:"job_id"`
+	Workspace string        `json:"-"`
+	Title     *string       `json:"title,omitempty"`
+	Status    JobStatus     `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	Matches   []SearchMatch `json:"matches"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShadowRun is a job transcribed again with a candidate adapter, for offline
+// comparison with the adapter that served it. Shadow results are never returned
+// to users.
+type ShadowRun struct {
+	ID                 string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionJobID string `json:"transcription_job_id" gorm:"type:varchar(36);not null;index"`
+	ModelID            string `json:"model_id" gorm:"type:varchar(100);not null;index"` // the candidate
+	PrimaryModelID     string `json:"primary_model_id" gorm:"type:varchar(100);not null"`
+
+	Status       JobStatus `json:"status" gorm:"type:varchar(20);not null"`
+	ErrorMessage *string   `json:"error_message,omitempty" gorm:"type:text"`
+
+	// Transcript is the candidate's transcript, in the job transcript format
+	Transcript *string `json:"transcript,omitempty" gorm:"type:text"`
+	// WordAgreement is 1 minus the word error rate of the candidate's text
+	// measured against the primary transcript (0 when they share no words)
+	WordAgreement *float64 `json:"word_agreement,omitempty"`
+
+	StartedAt          *time.Time `json:"started_at,omitempty"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	ProcessingDuration *int64     `json:"processing_duration,omitempty"` // milliseconds
+	CreatedAt          time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	TranscriptionJob TranscriptionJob `json:"-" gorm:"foreignKey:TranscriptionJobID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (s *ShadowRun) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Speaker is a person in the workspace's speaker directory. Speaker mappings
+// naming a diarized speaker link to it, so one record tracks the person across
+// jobs and renaming it renames them everywhere.
+type Speaker struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Workspace string    `json:"-" gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_speaker_workspace_name"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_speaker_workspace_name"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (s *Speaker) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Coarse genders a speaker's voice can be estimated as
+const (
+	SpeakerGenderFemale  = "female"
+	SpeakerGenderMale    = "male"
+	SpeakerGenderUnknown = "unknown"
+)
+
+// SpeakerAttribute is the coarse gender and age range estimated from the voice
+// of one diarized speaker in a job. Only the coarse estimate is kept, never the
+// model's raw age prediction.
+type SpeakerAttribute struct {
+	ID                 uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	TranscriptionJobID string `json:"transcription_job_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_job_speaker_attribute"`
+	Speaker            string `json:"speaker" gorm:"type:varchar(50);not null;uniqueIndex:idx_job_speaker_attribute"`
+	Gender             string `json:"gender" gorm:"type:varchar(20);not null"`
+	// GenderConfidence is the model's probability for the estimated gender
+	GenderConfidence float64 `json:"gender_confidence" gorm:"type:real;not null;default:0"`
+	// AgeRange is one of "child", "13-17", "18-29", "30-44", "45-59" or "60+"
+	AgeRange string `json:"age_range" gorm:"type:varchar(20);not null"`
+	// SpeechSeconds is how much of the speaker's speech the estimate is based on
+	SpeechSeconds float64   `json:"speech_seconds" gorm:"type:real;not null;default:0"`
+	Model         string    `json:"model" gorm:"type:varchar(200)"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	TranscriptionJob TranscriptionJob `json:"-" gorm:"foreignKey:TranscriptionJobID;constraint:OnDelete:CASCADE"`
+}
+
+// WorkspaceSettings are the privacy settings of a workspace
+type WorkspaceSettings struct {
+	Workspace string `json:"workspace" gorm:"primaryKey;type:varchar(64)"`
+	// SpeakerAttributesDisabled stops speaker attribute estimation in the
+	// workspace's jobs, whatever their profiles request
+	SpeakerAttributesDisabled bool      `json:"speaker_attributes_disabled" gorm:"not null;default:false"`
+	UpdatedBy                 string    `json:"updated_by,omitempty" gorm:"type:varchar(100)"`
+	UpdatedAt                 time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
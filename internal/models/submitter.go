@@ -0,0 +1,31 @@
+package models
+
+import "context"
+
+type apiKeyContextKey struct{}
+
+type userContextKey struct{}
+
+// WithAPIKey records on ctx the API key a request was made with, so the jobs it
+// creates are attributed to the key
+func WithAPIKey(ctx context.Context, keyID uint) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, keyID)
+}
+
+// APIKeyFromContext returns the API key ctx was made with, if any
+func APIKeyFromContext(ctx context.Context) (uint, bool) {
+	keyID, ok := ctx.Value(apiKeyContextKey{}).(uint)
+	return keyID, ok
+}
+
+// WithUser records on ctx the user a request was made by, so the jobs it
+// creates are attributed to them
+func WithUser(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userContextKey{}, userID)
+}
+
+// UserFromContext returns the user ctx was made by, if any
+func UserFromContext(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(userContextKey{}).(uint)
+	return userID, ok
+}
@@ -53,3 +53,15 @@ func (s *Summary) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// LLMOutput caches the reply to a deterministic LLM request, keyed by a hash of
+// the model and prompt. Transcript chunks and their prompt template make up the
+// prompt, so unchanged chunks are not sent to the LLM again.
+type LLMOutput struct {
+	Hash      string    `json:"hash" gorm:"primaryKey;type:varchar(64)"`
+	Model     string    `json:"model" gorm:"type:varchar(255);not null"`
+	Output    string    `json:"output" gorm:"type:text;not null"`
+	Hits      int       `json:"hits" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UsedAt    time.Time `json:"used_at" gorm:"index"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag labels jobs of a workspace. Like the AWS tags jobs are submitted with
+// through the AWS Transcribe compatible endpoint, a tag is a key with an
+// optional value, e.g. team=sales. The tags assigned to a job are also kept,
+// in the AWS shape, in TranscriptionJob.Tags.
+type Tag struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Workspace string    `json:"-" gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_tag_workspace_key_value"`
+	Key       string    `json:"key" gorm:"type:varchar(128);not null;uniqueIndex:idx_tag_workspace_key_value"`
+	Value     string    `json:"value,omitempty" gorm:"type:varchar(256);not null;default:'';uniqueIndex:idx_tag_workspace_key_value"`
+	Color     string    `json:"color,omitempty" gorm:"type:varchar(7)"` // #rrggbb, for display
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Name is how the tag is written: its key, or key=value
+func (t *Tag) Name() string {
+	return TagName(t.Key, t.Value)
+}
+
+// TagName writes a tag as key, or key=value when it has a value
+func TagName(key, value string) string {
+	if value == "" {
+		return key
+	}
+	return key + "=" + value
+}
+
+// ParseTagName splits a tag written as key or key=value
+func ParseTagName(name string) (key, value string) {
+	key, value, _ = strings.Cut(name, "=")
+	return key, value
+}
+
+// JobTag assigns a tag to a job
+type JobTag struct {
+	TranscriptionJobID string    `json:"-" gorm:"primaryKey;type:varchar(36)"`
+	TagID              string    `json:"-" gorm:"primaryKey;type:varchar(36);index"`
+	CreatedAt          time.Time `json:"-" gorm:"autoCreateTime"`
+}
+
+// TagEntry is a tag as stored in TranscriptionJob.Tags, in the shape of AWS tags
+type TagEntry struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+// TagEntries returns the tags stored in the job's Tags
+func (j *TranscriptionJob) TagEntries() ([]TagEntry, error) {
+	if j.Tags == nil || *j.Tags == "" {
+		return nil, nil
+	}
+	var entries []TagEntry
+	if err := json.Unmarshal([]byte(*j.Tags), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// TagNames returns the names of the job's tags, for exports and notifications;
+// unreadable tags are left out
+func (j *TranscriptionJob) TagNames() []string {
+	entries, _ := j.TagEntries()
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, TagName(entry.Key, entry.Value))
+	}
+	return names
+}
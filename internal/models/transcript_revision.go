@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TranscriptRevision is a transcript a job has had. Every transcript saved for
+// a job, by transcription, re-transcription, corrections or edits, is kept as
+// its next revision, so revisions and the adapters behind them can be compared.
+type TranscriptRevision struct {
+	ID                 uint   `json:"id" gorm:"primaryKey"`
+	TranscriptionJobID string `json:"transcription_job_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_transcript_revision"`
+	Revision           int    `json:"revision" gorm:"not null;uniqueIndex:idx_transcript_revision"` // from 1
+	// ModelUsed is the model the transcript says produced it, if any
+	ModelUsed  string    `json:"model_used,omitempty" gorm:"type:varchar(100);not null;default:''"`
+	Transcript string    `json:"transcript,omitempty" gorm:"type:text;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	TranscriptionJob TranscriptionJob `json:"-" gorm:"foreignKey:TranscriptionJobID;constraint:OnDelete:CASCADE"`
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,13 +15,24 @@ import (
 type TranscriptionJob struct {
 	ID                    string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
 	Title                 *string   `json:"title,omitempty" gorm:"type:text"`
-	Status                JobStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Status                JobStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
 	AudioPath             string    `json:"audio_path" gorm:"type:text;not null"`
 	AudioUri              *string   `json:"audio_uri,omitempty" gorm:"type:text"`
+	SourceURL             *string   `json:"source_url,omitempty" gorm:"type:text"`
+	VideoPath             *string   `json:"video_path,omitempty" gorm:"type:text"` // uploaded video the audio was extracted from, when kept
+	Workspace             string    `json:"workspace,omitempty" gorm:"type:varchar(64);index;default:''"`
+	IngestProgress        float64   `json:"ingest_progress" gorm:"type:real;default:0"`         // 0-100 while a source URL is being downloaded
+	Progress              float64   `json:"progress" gorm:"type:real;default:0"`                // 0-100 while the job is being transcribed
+	Stage                 string    `json:"stage,omitempty" gorm:"type:varchar(32);default:''"` // what a processing job is doing, see StageTranscribing
 	Transcript            *string   `json:"transcript,omitempty" gorm:"type:text"`
+	AudioSeconds          *float64  `json:"audio_seconds,omitempty" gorm:"index"` // length of the audio transcribed, once the job completed
 	Diarization           bool      `json:"diarization" gorm:"type:boolean;default:false"`
 	Summary               *string   `json:"summary,omitempty" gorm:"type:text"`
 	ErrorMessage          *string   `json:"error_message,omitempty" gorm:"type:text"`
+	ErrorCode             ErrorCode `json:"error_code,omitempty" gorm:"type:varchar(40);default:''"` // why the job failed, for clients to branch on
+	ScanStatus            string    `json:"scan_status,omitempty" gorm:"type:varchar(20);default:''"`
+	ScanSignature         *string   `json:"scan_signature,omitempty" gorm:"type:text"`
+	AlignmentFallback     *string   `json:"alignment_fallback,omitempty" gorm:"type:text"` // why timestamps are segment-level only, when alignment was skipped
 	IsMultiTrack          bool      `json:"is_multi_track" gorm:"type:boolean;default:false"`
 	AupFilePath           *string   `json:"aup_file_path,omitempty" gorm:"type:text"`
 	OutputBucketName      *string   `json:"output_bucket_name,omitempty" gorm:"type:text"`
@@ -26,37 +41,138 @@ type TranscriptionJob struct {
 	MergeStatus           string    `json:"merge_status" gorm:"type:varchar(20);default:'none'"` // none, pending, processing, completed, failed
 	MergeError            *string   `json:"merge_error,omitempty" gorm:"type:text"`
 	IndividualTranscripts *string   `json:"individual_transcripts,omitempty" gorm:"type:text"` // JSON-serialized map[string]*string
-	Tags                  *string   `json:"tags,omitempty" gorm:"type:text"`                   // JSON-serialized map[string]*string
-	CreatedAt             time.Time `json:"created_at" gorm:"autoCreateTime"`
+	Tags                  *string   `json:"tags,omitempty" gorm:"type:text"`                   // JSON-serialized []TagEntry, mirroring the job's tag assignments
+	CreatedAt             time.Time `json:"created_at" gorm:"autoCreateTime;index"`
 	UpdatedAt             time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// WhisperX parameters
 	Parameters WhisperXParams `json:"parameters" gorm:"embedded"`
 
+	// Results of the post-transcription automation configured in Parameters
+	ActionItems      *string    `json:"action_items,omitempty" gorm:"type:text"`      // JSON-serialized []string
+	SegmentSentiment *string    `json:"segment_sentiment,omitempty" gorm:"type:text"` // JSON-serialized []service.SegmentSentiment
+	Chapters         *string    `json:"chapters,omitempty" gorm:"type:text"`          // JSON-serialized []service.Chapter
+	Translation      *string    `json:"translation,omitempty" gorm:"type:text"`       // JSON-serialized service.Translation
+	PostProcessedAt  *time.Time `json:"post_processed_at,omitempty"`
+	PostProcessError *string    `json:"post_process_error,omitempty" gorm:"type:text"`
+
+	// A finalized transcript is a record that only admins may change or reprocess
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+	FinalizedBy *string    `json:"finalized_by,omitempty" gorm:"type:varchar(100)"`
+
+	// Wall-clock start of the recording, so transcripts can show absolute times
+	RecordedAt       *time.Time `json:"recorded_at,omitempty"`
+	RecordedAtSource string     `json:"recorded_at_source,omitempty" gorm:"type:varchar(20);default:''"` // user or metadata
+
+	// Profile whose parameters the job was queued with, and the version of it
+	// they were, see ProfileVersion
+	ProfileID      *string `json:"profile_id,omitempty" gorm:"type:varchar(36);index"`
+	ProfileVersion *int    `json:"profile_version,omitempty"`
+	// ParameterOverrides are the parameters, as JSON, the submission replaced
+	// in the profile's
+	ParameterOverrides *string `json:"parameter_overrides,omitempty" gorm:"type:text"`
+
+	// AudioSHA256 is the hex SHA-256 of the job's audio, taken when it is first
+	// processed. DuplicateOf is the job whose transcript the job reused, see
+	// WhisperXParams.DeduplicateAudio.
+	AudioSHA256 string  `json:"audio_sha256,omitempty" gorm:"column:audio_sha256;type:varchar(64);index;default:''"`
+	DuplicateOf *string `json:"duplicate_of,omitempty" gorm:"type:varchar(36)"`
+
+	// CollectionID is the collection the job is filed in, if any
+	CollectionID *string `json:"collection_id,omitempty" gorm:"type:varchar(36);index"`
+
+	// APIKeyID is the API key that submitted the job, if one did
+	APIKeyID *uint `json:"api_key_id,omitempty" gorm:"index"`
+	// UserID is the signed-in user that submitted the job, if one did
+	UserID *uint `json:"user_id,omitempty" gorm:"index"`
+	// EstimatedCost is what the job's executions are estimated to have cost at
+	// the configured per-minute adapter prices
+	EstimatedCost *float64 `json:"estimated_cost,omitempty"`
+
+	// AWS tag route whose policy the job was submitted under; its usage counts
+	// against the route's quota too
+	AWSTagRouteID *uint `json:"aws_tag_route_id,omitempty" gorm:"index"`
+
+	// Set once a retention policy removed the job's audio; AudioArchiveURI is
+	// where it was archived to, if it was
+	AudioExpiredAt  *time.Time `json:"audio_expired_at,omitempty"`
+	AudioArchiveURI *string    `json:"audio_archive_uri,omitempty" gorm:"type:text"`
+
+	// WorkerID is the worker process that claimed the job, which refreshes
+	// HeartbeatAt while it runs. CancelRequestedAt asks that worker to kill it.
+	WorkerID          string     `json:"worker_id,omitempty" gorm:"type:varchar(255);index;default:''"`
+	HeartbeatAt       *time.Time `json:"heartbeat_at,omitempty"`
+	CancelRequestedAt *time.Time `json:"cancel_requested_at,omitempty"`
+	// StageTimings are how long the job spent in each stage
+	StageTimings StageTimings `json:"stage_timings" gorm:"embedded;embeddedPrefix:timing_"`
+
+	// Confidence review: LowConfidenceSegments counts the transcript segments
+	// flagged below the confidence threshold. Jobs with enough of them need
+	// review (ReviewStatus) until a reviewer has seen to them.
+	LowConfidenceSegments int        `json:"low_confidence_segments" gorm:"not null;default:0"`
+	ReviewStatus          string     `json:"review_status,omitempty" gorm:"type:varchar(20);index;default:''"`
+	ReviewedAt            *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy            *string    `json:"reviewed_by,omitempty" gorm:"type:varchar(100)"`
+
 	// Relationships
 	MultiTrackFiles []MultiTrackFile `json:"multi_track_files,omitempty" gorm:"foreignKey:TranscriptionJobID"`
+	// Stages are only loaded with the job's status
+	Stages []JobStage `json:"stages,omitempty" gorm:"foreignKey:TranscriptionJobID"`
+	// TagAssignments are only loaded for retention; Tags has the job's tags
+	TagAssignments []JobTag `json:"-" gorm:"foreignKey:TranscriptionJobID"`
 }
 
 // JobStatus represents the status of a transcription job
 type JobStatus string
 
 const (
-	StatusUploaded   JobStatus = "uploaded"
-	StatusPending    JobStatus = "pending"
-	StatusProcessing JobStatus = "processing"
-	StatusCompleted  JobStatus = "completed"
-	StatusFailed     JobStatus = "failed"
+	StatusUploaded    JobStatus = "uploaded"
+	StatusDownloading JobStatus = "downloading"
+	StatusPending     JobStatus = "pending"
+	StatusProcessing  JobStatus = "processing"
+	StatusCompleted   JobStatus = "completed"
+	StatusFailed      JobStatus = "failed"
+	StatusQuarantined JobStatus = "quarantined"
+	StatusCancelled   JobStatus = "cancelled"
+)
+
+// Where TranscriptionJob.RecordedAt came from
+const (
+	RecordedAtUser     = "user"     // given when uploading, or set later
+	RecordedAtMetadata = "metadata" // read from the media file's creation time
+)
+
+// Upload scan outcomes stored in TranscriptionJob.ScanStatus; empty means not scanned
+const (
+	ScanClean       = "clean"
+	ScanQuarantined = "quarantined"
+	ScanReleased    = "released" // flagged, then released by an admin
+)
+
+// Review states stored in TranscriptionJob.ReviewStatus; empty means no review is needed
+const (
+	ReviewNeeded   = "needs_review"
+	ReviewComplete = "reviewed"
 )
 
 // WhisperXParams contains parameters for WhisperX transcription
 type WhisperXParams struct {
 	// Model family (whisper or nvidia)
 	ModelFamily string `json:"model_family" gorm:"type:varchar(20);default:'whisper'"`
+	// Adapter failover: comma-separated model families tried in order when the
+	// one before fails or runs past AdapterTimeoutSeconds, e.g. "runpod-whisperx,whisper"
+	FallbackModelFamilies *string `json:"fallback_model_families,omitempty" gorm:"type:text"`
+	AdapterTimeoutSeconds int     `json:"adapter_timeout_seconds" gorm:"type:int;default:0"` // per attempt; 0 waits for the adapter
+
+	// DeduplicateAudio completes a job with the transcript of an earlier job
+	// of the same profile on identical audio instead of transcribing it again
+	DeduplicateAudio bool `json:"deduplicate_audio" gorm:"type:boolean;default:false"`
 
 	// Model parameters
 	Model          string  `json:"model" gorm:"type:varchar(50);default:'small'"`
 	ModelCacheOnly bool    `json:"model_cache_only" gorm:"type:boolean;default:false"`
 	ModelDir       *string `json:"model_dir,omitempty" gorm:"type:text"`
+	CustomModel    *string `json:"custom_model,omitempty" gorm:"type:text"` // fine-tuned CTranslate2 checkpoint (absolute path or Hugging Face repo ID) used instead of Model
 
 	// Device and computation
 	Device      string `json:"device" gorm:"type:varchar(20);default:'cpu'"`
@@ -72,6 +188,15 @@ type WhisperXParams struct {
 	// Task and language
 	Task     string  `json:"task" gorm:"type:varchar(20);default:'transcribe'"`
 	Language *string `json:"language,omitempty" gorm:"type:varchar(10)"`
+	// SegmentLanguageID tags each segment with its spoken language, for code-switching audio
+	SegmentLanguageID bool `json:"segment_language_id" gorm:"type:boolean;default:false"`
+
+	// Audio preprocessing with ffmpeg before the adapter runs
+	PreprocessNormalize   bool `json:"preprocess_normalize" gorm:"type:boolean;default:false"`    // EBU R128 loudness normalization
+	PreprocessTrimSilence bool `json:"preprocess_trim_silence" gorm:"type:boolean;default:false"` // trim leading and trailing silence
+	PreprocessResample    bool `json:"preprocess_resample" gorm:"type:boolean;default:false"`     // resample to 16kHz mono
+	PreprocessDenoise     bool `json:"preprocess_denoise" gorm:"type:boolean;default:false"`      // RNNoise denoising (needs RNNOISE_MODEL_PATH)
+	PreprocessVAD         bool `json:"preprocess_vad" gorm:"type:boolean;default:false"`          // cut silence and music before remote adapters
 
 	// Alignment settings
 	AlignModel           *string `json:"align_model,omitempty" gorm:"type:varchar(100)"`
@@ -91,6 +216,9 @@ type WhisperXParams struct {
 	MaxSpeakers       *int   `json:"max_speakers,omitempty" gorm:"type:int"`
 	DiarizeModel      string `json:"diarize_model" gorm:"type:varchar(50);default:'pyannote'"` // Options: 'pyannote', 'nvidia_sortformer'
 	SpeakerEmbeddings bool   `json:"speaker_embeddings" gorm:"type:boolean;default:false"`
+	// SpeakerAttributes estimates each diarized speaker's gender and age range from
+	// their voice, for research datasets; workspaces can disable it
+	SpeakerAttributes bool `json:"speaker_attributes" gorm:"type:boolean;default:false"`
 
 	// Transcription quality settings
 	Temperature                    float64 `json:"temperature" gorm:"type:real;default:0"`
@@ -101,6 +229,7 @@ type WhisperXParams struct {
 	SuppressTokens                 *string `json:"suppress_tokens,omitempty" gorm:"type:text"`
 	SuppressNumerals               bool    `json:"suppress_numerals" gorm:"type:boolean;default:false"`
 	InitialPrompt                  *string `json:"initial_prompt,omitempty" gorm:"type:text"`
+	Hotwords                       *string `json:"hotwords,omitempty" gorm:"type:text"` // phrases WhisperX biases decoding towards
 	ConditionOnPreviousText        bool    `json:"condition_on_previous_text" gorm:"type:boolean;default:false"`
 	Fp16                           bool    `json:"fp16" gorm:"type:boolean;default:true"`
 	TemperatureIncrementOnFallback float64 `json:"temperature_increment_on_fallback" gorm:"type:real;default:0.2"`
@@ -122,33 +251,130 @@ type WhisperXParams struct {
 	AttentionContextLeft  int `json:"attention_context_left" gorm:"type:int;default:256"`
 	AttentionContextRight int `json:"attention_context_right" gorm:"type:int;default:256"`
 
+	// NeMo adapter/LoRA weights (Parakeet and Canary), loaded onto the base model for each job
+	NemoAdapterPath *string `json:"nemo_adapter_path,omitempty" gorm:"type:text"`         // absolute path to a checkpoint saved with save_adapters()
+	NemoAdapterName *string `json:"nemo_adapter_name,omitempty" gorm:"type:varchar(100)"` // enable only this adapter; default enables all in the checkpoint
+
+	// Custom vocabulary, by name: its phrases are added to the prompt and
+	// hotwords, and the filter's words are removed, masked or tagged in the
+	// transcript
+	VocabularyName         *string `json:"vocabulary_name,omitempty" gorm:"type:varchar(200)"`
+	VocabularyFilterName   *string `json:"vocabulary_filter_name,omitempty" gorm:"type:varchar(200)"`
+	VocabularyFilterMethod *string `json:"vocabulary_filter_method,omitempty" gorm:"type:varchar(10)"` // remove (default), mask or tag
+
 	// Multi-track transcription settings
 	IsMultiTrackEnabled bool `json:"is_multi_track_enabled" gorm:"type:boolean;default:false"`
 
 	// Webhook settings
 	CallbackURL *string `json:"callback_url,omitempty" gorm:"type:text"`
 
+	// Post-transcription automation, run with the active LLM once the job completes
+	AutoSummaryTemplateID *string `json:"auto_summary_template_id,omitempty" gorm:"type:varchar(36)"`
+	AutoActionItems       bool    `json:"auto_action_items" gorm:"type:boolean;default:false"`
+	AutoTag               bool    `json:"auto_tag" gorm:"type:boolean;default:false"`
+	AutoSentiment         bool    `json:"auto_sentiment" gorm:"type:boolean;default:false"`
+	AutoChapters          bool    `json:"auto_chapters" gorm:"type:boolean;default:false"`
+
 	// OpenAI settings
 	APIKey *string `json:"api_key,omitempty" gorm:"type:text"`
 }
 
+// AudioPreprocessing is the ffmpeg preprocessing a job's audio gets before the adapter runs
+type AudioPreprocessing struct {
+	Normalize   bool `json:"normalize,omitempty"`
+	TrimSilence bool `json:"trim_silence,omitempty"`
+	Resample    bool `json:"resample,omitempty"`
+	Denoise     bool `json:"denoise,omitempty"`
+	// VAD cuts the audio down to its speech before remote adapters, which bill by the minute
+	VAD bool `json:"vad,omitempty"`
+}
+
+// Enabled reports whether any preprocessing is requested
+func (p AudioPreprocessing) Enabled() bool {
+	return p.Normalize || p.TrimSilence || p.Resample || p.Denoise || p.VAD
+}
+
+// Preprocessing returns the audio preprocessing the parameters request
+func (p WhisperXParams) Preprocessing() AudioPreprocessing {
+	return AudioPreprocessing{
+		Normalize:   p.PreprocessNormalize,
+		TrimSilence: p.PreprocessTrimSilence,
+		Resample:    p.PreprocessResample,
+		Denoise:     p.PreprocessDenoise,
+		VAD:         p.PreprocessVAD,
+	}
+}
+
+// FallbackChain returns the model families to fail over to, in order, without
+// the primary family or repeats
+func (p WhisperXParams) FallbackChain() []string {
+	if p.FallbackModelFamilies == nil {
+		return nil
+	}
+	seen := map[string]bool{p.ModelFamily: true}
+	var chain []string
+	for _, family := range strings.Split(*p.FallbackModelFamilies, ",") {
+		family = strings.TrimSpace(family)
+		if family == "" || seen[family] {
+			continue
+		}
+		seen[family] = true
+		chain = append(chain, family)
+	}
+	return chain
+}
+
 // BeforeCreate sets the ID if not already set
 func (tj *TranscriptionJob) BeforeCreate(tx *gorm.DB) error {
 	if tj.ID == "" {
 		tj.ID = uuid.New().String()
 	}
+	if keyID, ok := APIKeyFromContext(tx.Statement.Context); ok && tj.APIKeyID == nil {
+		tj.APIKeyID = &keyID
+	}
+	if userID, ok := UserFromContext(tx.Statement.Context); ok && tj.UserID == nil {
+		tj.UserID = &userID
+	}
 	return nil
 }
 
+// User roles, from most to least privileged
+const (
+	RoleAdmin  = "admin"  // manages users and API keys, and everything editors do
+	RoleEditor = "editor" // creates and edits jobs and their content
+	RoleViewer = "viewer" // read-only
+)
+
+// roleRanks orders the roles by privilege
+var roleRanks = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// ValidRole reports whether role is one of the user roles
+func ValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// RoleAllows reports whether role grants at least the privileges of required
+func RoleAllows(role, required string) bool {
+	return ValidRole(role) && roleRanks[role] >= roleRanks[required]
+}
+
 // User represents a user for authentication
 type User struct {
-	ID                       uint      `json:"id" gorm:"primaryKey"`
-	Username                 string    `json:"username" gorm:"uniqueIndex;not null;type:varchar(50)"`
-	Password                 string    `json:"-" gorm:"not null;type:varchar(255)"`
-	DefaultProfileID         *string   `json:"default_profile_id,omitempty" gorm:"type:varchar(36)"`
-	AutoTranscriptionEnabled bool      `json:"auto_transcription_enabled" gorm:"not null;default:false"`
-	CreatedAt                time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt                time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                       uint    `json:"id" gorm:"primaryKey"`
+	Username                 string  `json:"username" gorm:"uniqueIndex;not null;type:varchar(50)"`
+	Password                 string  `json:"-" gorm:"not null;type:varchar(255)"`
+	Role                     string  `json:"role" gorm:"type:varchar(20);not null;default:viewer"` // accounts created before roles existed are backfilled to admin
+	DefaultProfileID         *string `json:"default_profile_id,omitempty" gorm:"type:varchar(36)"`
+	AutoTranscriptionEnabled bool    `json:"auto_transcription_enabled" gorm:"not null;default:false"`
+	TimeZone                 string  `json:"time_zone" gorm:"type:varchar(64);not null;default:''"` // IANA name for times in exports; empty is UTC
+	Locale                   string  `json:"locale" gorm:"type:varchar(10);not null;default:''"`    // date and number format of exports; empty is ISO
+	// Email notifications about the user's jobs; none without an address
+	NotificationEmail      string    `json:"notification_email,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	NotifyOnCompleted      bool      `json:"notify_on_completed" gorm:"not null;default:true"`
+	NotifyOnFailed         bool      `json:"notify_on_failed" gorm:"not null;default:true"`
+	NotifyAttachTranscript bool      `json:"notify_attach_transcript" gorm:"not null;default:false"`
+	CreatedAt              time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt              time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // APIKey represents an API key for external authentication
@@ -159,10 +385,59 @@ type APIKey struct {
 	Description *string `json:"description,omitempty" gorm:"type:text"`
 	// IsActive should persist explicit false values; avoid default tag to prevent
 	// GORM from overriding false with DB defaults during inserts.
-	IsActive  bool       `json:"is_active" gorm:"type:boolean;not null"`
-	LastUsed  *time.Time `json:"last_used,omitempty"`
-	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	IsActive       bool       `json:"is_active" gorm:"type:boolean;not null"`
+	Role           string     `json:"role" gorm:"type:varchar(20);not null;default:viewer"`              // keys created before roles existed are backfilled to admin
+	OrganizationID string     `json:"organization_id" gorm:"type:varchar(36);not null;default:'';index"` // empty for keys outside every organization
+	Scopes         string     `json:"scopes" gorm:"type:varchar(100);not null;default:''"`               // comma-separated; empty grants everything the role does
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	RateLimit      int        `json:"rate_limit" gorm:"not null;default:0"` // requests per minute; 0 is unlimited
+	LastUsed       *time.Time `json:"last_used,omitempty"`
+	LastUsedIP     string     `json:"last_used_ip,omitempty" gorm:"type:varchar(64)"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// API key scopes narrow what a key may do within its role
+const (
+	ScopeSubmit = "submit" // submit jobs and follow their progress
+	ScopeRead   = "read"   // read everything
+	ScopeAdmin  = "admin"  // everything, including changing and deleting data
+)
+
+// ValidScope reports whether scope is one of the API key scopes
+func ValidScope(scope string) bool {
+	return scope == ScopeSubmit || scope == ScopeRead || scope == ScopeAdmin
+}
+
+// ScopeList returns the key's scopes
+func (ak *APIKey) ScopeList() []string {
+	var scopes []string
+	for _, scope := range strings.Split(ak.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// HasScope reports whether the key was granted scope. Unscoped keys, created
+// before scopes existed, hold every scope.
+func (ak *APIKey) HasScope(scope string) bool {
+	scopes := ak.ScopeList()
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, s := range scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the key has expired by now
+func (ak *APIKey) Expired(now time.Time) bool {
+	return ak.ExpiresAt != nil && !now.Before(*ak.ExpiresAt)
 }
 
 // BeforeCreate sets the API key if not already set
@@ -175,13 +450,16 @@ func (ak *APIKey) BeforeCreate(tx *gorm.DB) error {
 
 // TranscriptionProfile represents a saved transcription configuration profile
 type TranscriptionProfile struct {
-	ID          string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
-	Name        string         `json:"name" gorm:"type:varchar(255);not null"`
-	Description *string        `json:"description,omitempty" gorm:"type:text"`
-	IsDefault   bool           `json:"is_default" gorm:"type:boolean;default:false"`
-	Parameters  WhisperXParams `json:"parameters" gorm:"embedded"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	ID             string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name           string         `json:"name" gorm:"type:varchar(255);not null"`
+	Description    *string        `json:"description,omitempty" gorm:"type:text"`
+	IsDefault      bool           `json:"is_default" gorm:"type:boolean;default:false"`
+	OrganizationID string         `json:"organization_id" gorm:"type:varchar(36);not null;default:'';index"` // empty outside every organization
+	Parameters     WhisperXParams `json:"parameters" gorm:"embedded"`
+	// Version counts the profile's changes of name, description or parameters, from 1
+	Version   int       `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // BeforeCreate sets the ID if not already set
@@ -192,11 +470,11 @@ func (tp *TranscriptionProfile) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// BeforeSave ensures only one profile can be default
+// BeforeSave ensures only one profile per organization can be default
 func (tp *TranscriptionProfile) BeforeSave(tx *gorm.DB) error {
 	if tp.IsDefault {
-		// Set all other profiles to not default
-		if err := tx.Model(&TranscriptionProfile{}).Where("id != ?", tp.ID).Update("is_default", false).Error; err != nil {
+		// Set all other profiles of the same organization to not default
+		if err := tx.Model(&TranscriptionProfile{}).Where("id != ? AND organization_id = ?", tp.ID, tp.OrganizationID).Update("is_default", false).Error; err != nil {
 			return err
 		}
 	}
@@ -205,21 +483,22 @@ func (tp *TranscriptionProfile) BeforeSave(tx *gorm.DB) error {
 
 // LLMConfig represents LLM configuration settings
 type LLMConfig struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	Provider      string    `json:"provider" gorm:"not null;type:varchar(50)"`  // "ollama" or "openai"
-	BaseURL       *string   `json:"base_url,omitempty" gorm:"type:text"`        // For Ollama
-	OpenAIBaseURL *string   `json:"openai_base_url,omitempty" gorm:"type:text"` // For OpenAI custom endpoint
-	APIKey        *string   `json:"api_key,omitempty" gorm:"type:text"`         // For OpenAI (encrypted)
-	IsActive      bool      `json:"is_active" gorm:"type:boolean;default:false"`
-	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Provider       string    `json:"provider" gorm:"not null;type:varchar(50)"`  // "ollama" or "openai"
+	BaseURL        *string   `json:"base_url,omitempty" gorm:"type:text"`        // For Ollama
+	OpenAIBaseURL  *string   `json:"openai_base_url,omitempty" gorm:"type:text"` // For OpenAI custom endpoint
+	APIKey         *string   `json:"api_key,omitempty" gorm:"type:text"`         // For OpenAI (encrypted)
+	IsActive       bool      `json:"is_active" gorm:"type:boolean;default:false"`
+	OrganizationID string    `json:"organization_id" gorm:"type:varchar(36);not null;default:'';index"` // empty for the instance's own config
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
-// BeforeSave ensures only one LLM config can be active
+// BeforeSave ensures only one LLM config per organization can be active
 func (lc *LLMConfig) BeforeSave(tx *gorm.DB) error {
 	if lc.IsActive {
-		// Set all other configs to not active
-		if err := tx.Model(&LLMConfig{}).Where("id != ?", lc.ID).Update("is_active", false).Error; err != nil {
+		// Set all other configs of the same organization to not active
+		if err := tx.Model(&LLMConfig{}).Where("id != ? AND organization_id = ?", lc.ID, lc.OrganizationID).Update("is_active", false).Error; err != nil {
 			return err
 		}
 	}
@@ -291,6 +570,14 @@ type MultiTrackTiming struct {
 	Duration  int64     `json:"duration"` // Duration in milliseconds
 }
 
+// AdapterAttempt is a transcription adapter a job failed over from
+type AdapterAttempt struct {
+	ModelFamily string `json:"model_family"`
+	ModelID     string `json:"model_id,omitempty"`
+	Error       string `json:"error"`
+	Duration    int64  `json:"duration"` // Duration in milliseconds
+}
+
 // TranscriptionJobExecution represents execution metadata for completed transcription jobs
 type TranscriptionJobExecution struct {
 	ID                 string `json:"id" gorm:"primaryKey;type:varchar(36)"`
@@ -300,6 +587,10 @@ type TranscriptionJobExecution struct {
 	StartedAt          time.Time  `json:"started_at" gorm:"not null"`
 	CompletedAt        *time.Time `json:"completed_at,omitempty"`
 	ProcessingDuration *int64     `json:"processing_duration,omitempty"` // Duration in milliseconds
+	AudioSeconds       *float64   `json:"audio_seconds,omitempty"`       // length of the audio transcribed, counted against quotas
+	// SkippedSeconds is the silence and music voice activity detection cut
+	// out before the audio was sent to a remote adapter
+	SkippedSeconds *float64 `json:"skipped_seconds,omitempty"`
 
 	// Multi-track specific timing data
 	MultiTrackTimings *string    `json:"multi_track_timings,omitempty" gorm:"type:text"` // JSON-serialized []MultiTrackTiming
@@ -310,6 +601,21 @@ type TranscriptionJobExecution struct {
 	// Parameters used for this execution (may differ from job parameters due to profiles)
 	ActualParameters WhisperXParams `json:"actual_parameters" gorm:"embedded;embeddedPrefix:actual_"`
 
+	// What the adapters were called with, recorded for single-track jobs so the
+	// execution can be replayed
+	Snapshot        *string `json:"snapshot,omitempty" gorm:"type:text"`                // JSON-serialized ExecutionSnapshot
+	EnvironmentHash *string `json:"environment_hash,omitempty" gorm:"type:varchar(64)"` // over the steps' environment hashes
+	InputHash       *string `json:"input_hash,omitempty" gorm:"type:varchar(64)"`       // SHA-256 of the audio
+	// ReplayOf is the execution a debug replay re-ran; replays leave the job untouched
+	ReplayOf *string `json:"replay_of,omitempty" gorm:"type:varchar(36);index"`
+
+	// Adapter is the transcription adapter that produced the result, which is a
+	// fallback when the job failed over from the adapters in FailedAdapters
+	Adapter        *string `json:"adapter,omitempty" gorm:"type:varchar(50)"`
+	FailedAdapters *string `json:"failed_adapters,omitempty" gorm:"type:text"` // JSON-serialized []AdapterAttempt
+	// Cost is estimated from the adapters' per-minute prices; nil when none is priced
+	Cost *float64 `json:"cost,omitempty"`
+
 	// Execution results
 	Status       JobStatus `json:"status" gorm:"type:varchar(20);not null"`
 	ErrorMessage *string   `json:"error_message,omitempty" gorm:"type:text"`
@@ -339,12 +645,43 @@ func (tje *TranscriptionJobExecution) CalculateProcessingDuration() {
 	}
 }
 
+// ExecutionSnapshot records each adapter call of an execution
+type ExecutionSnapshot struct {
+	Steps []ExecutionStep `json:"steps"`
+	// Preprocessing is the audio preprocessing run before the adapters, if any
+	Preprocessing *AudioPreprocessing `json:"preprocessing,omitempty"`
+	// Chunking is how long audio was split to be transcribed in parallel, if it was
+	Chunking *AudioChunking `json:"chunking,omitempty"`
+}
+
+// AudioChunking splits long audio into chunks transcribed in parallel
+type AudioChunking struct {
+	ChunkSeconds   float64 `json:"chunk_seconds"`
+	OverlapSeconds float64 `json:"overlap_seconds"`
+}
+
+// ExecutionStep is one adapter call: the adapter, the state of its environment
+// and the exact parameters it was given
+type ExecutionStep struct {
+	Stage           string                 `json:"stage"` // "transcription" or "diarization"
+	ModelID         string                 `json:"model_id"`
+	AdapterVersion  string                 `json:"adapter_version"`
+	EnvironmentHash string                 `json:"environment_hash"`
+	Parameters      map[string]interface{} `json:"parameters"`
+	// Packages are the locked versions of the Python packages the adapter's
+	// transcripts depend on, and Checkpoints the models it loaded
+	Packages    map[string]string `json:"packages,omitempty"`
+	Checkpoints []string          `json:"checkpoints,omitempty"`
+}
+
 // SpeakerMapping represents custom speaker names for a transcription job
 type SpeakerMapping struct {
 	ID                 uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	TranscriptionJobID string    `json:"transcription_job_id" gorm:"type:varchar(36);not null;index"`
-	OriginalSpeaker    string    `json:"original_speaker" gorm:"type:varchar(50);not null"` // e.g., "speaker_00"
-	CustomName         string    `json:"custom_name" gorm:"type:varchar(100);not null"`     // e.g., "John Doe"
+	OriginalSpeaker    string    `json:"original_speaker" gorm:"type:varchar(50);not null"`  // e.g., "speaker_00"
+	CustomName         string    `json:"custom_name" gorm:"type:varchar(100);not null"`      // e.g., "John Doe"
+	VoiceprintID       *string   `json:"voiceprint_id,omitempty" gorm:"type:varchar(36)"`    // set when named by voiceprint matching
+	SpeakerID          *string   `json:"speaker_id,omitempty" gorm:"type:varchar(36);index"` // entry in the speaker directory
 	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt          time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -371,6 +708,43 @@ type MultiTrackFile struct {
 	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt          time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	// TrackName is the speaker name given when the track was uploaded; the file
+	// name is used without one
+	TrackName string `json:"track_name,omitempty" gorm:"type:varchar(100)"`
+	// Parameters overrides the job's parameters for this track
+	Parameters *string `json:"-" gorm:"type:text"` // JSON-serialized map of WhisperXParams fields
+
 	// Relationships
 	TranscriptionJob TranscriptionJob `json:"transcription_job,omitempty" gorm:"foreignKey:TranscriptionJobID;constraint:OnDelete:CASCADE"`
 }
+
+// ParameterOverrides returns the job parameters the track overrides, if any
+func (f MultiTrackFile) ParameterOverrides() (map[string]interface{}, error) {
+	if f.Parameters == nil || *f.Parameters == "" {
+		return nil, nil
+	}
+	var overrides map[string]interface{}
+	if err := json.Unmarshal([]byte(*f.Parameters), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid track parameters: %w", err)
+	}
+	return overrides, nil
+}
+
+// WithOverrides returns the parameters with the given fields, named as in
+// JSON, replaced. Unknown fields are an error.
+func (p WhisperXParams) WithOverrides(overrides map[string]interface{}) (WhisperXParams, error) {
+	if len(overrides) == 0 {
+		return p, nil
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return p, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	merged := p
+	if err := decoder.Decode(&merged); err != nil {
+		return p, fmt.Errorf("invalid parameters: %w", err)
+	}
+	return merged, nil
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Methods a vocabulary filter applies to the words it matches
+const (
+	VocabularyFilterRemove = "remove" // drop the word
+	VocabularyFilterMask   = "mask"   // replace it with ***
+	VocabularyFilterTag    = "tag"    // keep it, flagged in the word timings
+)
+
+// ValidVocabularyFilterMethod reports whether method is a vocabulary filter method
+func ValidVocabularyFilterMethod(method string) bool {
+	switch method {
+	case VocabularyFilterRemove, VocabularyFilterMask, VocabularyFilterTag:
+		return true
+	}
+	return false
+}
+
+// Vocabulary is a custom vocabulary, as in AWS Transcribe: terms, acronyms and
+// proper nouns that jobs naming it, directly or through their profile, are
+// prompted with so the model favours their spelling, and whose known
+// misspellings are corrected in their transcripts. Names are unique within an organization.
+type Vocabulary struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID string `json:"organization_id" gorm:"type:varchar(36);not null;default:'';uniqueIndex:idx_vocabulary_org_name"` // empty outside every organization
+	Name           string `json:"name" gorm:"type:varchar(200);not null;uniqueIndex:idx_vocabulary_org_name"`
+	LanguageCode   string `json:"language_code" gorm:"type:varchar(10);not null;default:''"`
+	// Phrases are newline-separated
+	Phrases string `json:"-" gorm:"type:text;not null;default:''"`
+	// Corrections are newline-separated misspellings, each followed by a tab
+	// and the phrase it is corrected to
+	Corrections string    `json:"-" gorm:"type:text;not null;default:''"`
+	CreatedBy   string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// PhraseList returns the vocabulary's phrases
+func (v *Vocabulary) PhraseList() []string {
+	return splitLines(v.Phrases)
+}
+
+// VocabularyCorrection replaces a misspelling of a phrase in transcripts
+type VocabularyCorrection struct {
+	Misspelling string
+	Phrase      string
+}
+
+// CorrectionList returns the vocabulary's corrections
+func (v *Vocabulary) CorrectionList() []VocabularyCorrection {
+	corrections := []VocabularyCorrection{}
+	for _, line := range splitLines(v.Corrections) {
+		misspelling, phrase, ok := strings.Cut(line, "\t")
+		misspelling, phrase = strings.TrimSpace(misspelling), strings.TrimSpace(phrase)
+		if ok && misspelling != "" && phrase != "" {
+			corrections = append(corrections, VocabularyCorrection{Misspelling: misspelling, Phrase: phrase})
+		}
+	}
+	return corrections
+}
+
+// VocabularyFilter is a list of words, such as profanity, that jobs naming it
+// have removed, masked or tagged in their transcript. Names are unique within
+// an organization.
+type VocabularyFilter struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID string `json:"organization_id" gorm:"type:varchar(36);not null;default:'';uniqueIndex:idx_vocabulary_filter_org_name"` // empty outside every organization
+	Name           string `json:"name" gorm:"type:varchar(200);not null;uniqueIndex:idx_vocabulary_filter_org_name"`
+	LanguageCode   string `json:"language_code" gorm:"type:varchar(10);not null;default:''"`
+	// Words are newline-separated
+	Words     string    `json:"-" gorm:"type:text;not null;default:''"`
+	CreatedBy string    `json:"created_by,omitempty" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// WordList returns the filtered words
+func (f *VocabularyFilter) WordList() []string {
+	return splitLines(f.Words)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Voiceprint is the enrolled voice of a named person, matched against the
+// speakers diarized in new jobs of the same workspace
+type Voiceprint struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Workspace string `json:"-" gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_voiceprint_workspace_name"`
+	Name      string `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_voiceprint_workspace_name"`
+	// Samples is the number of enrolled speakers averaged into the embedding
+	Samples int `json:"samples" gorm:"type:int;not null;default:1"`
+	// Little-endian float32 vector; decoded into Vector when read
+	Embedding []byte    `json:"-" gorm:"type:blob;not null"`
+	Vector    []float32 `json:"-" gorm:"-"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (v *Voiceprint) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// JobSpeakerEmbedding is the voice embedding of one diarized speaker in a job,
+// kept so the speaker can later be enrolled as a voiceprint
+type JobSpeakerEmbedding struct {
+	ID                 uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	TranscriptionJobID string `json:"transcription_job_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_job_speaker_embedding"`
+	Speaker            string `json:"speaker" gorm:"type:varchar(50);not null;uniqueIndex:idx_job_speaker_embedding"`
+	// VoiceprintID and Similarity record the voiceprint the speaker was matched to
+	VoiceprintID *string   `json:"voiceprint_id,omitempty" gorm:"type:varchar(36)"`
+	Similarity   *float64  `json:"similarity,omitempty" gorm:"type:real"`
+	Embedding    []byte    `json:"-" gorm:"type:blob;not null"`
+	Vector       []float32 `json:"-" gorm:"-"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	TranscriptionJob TranscriptionJob `json:"-" gorm:"foreignKey:TranscriptionJobID;constraint:OnDelete:CASCADE"`
+}
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -13,13 +14,36 @@ import (
 	"time"
 
 	"scriberr/internal/database"
+	"scriberr/internal/events"
 	"scriberr/internal/models"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+
+	"gorm.io/gorm"
 )
 
+// ErrJobDeferred is returned (wrapped) by a JobProcessor when a job cannot start
+// yet, for example while its model environment is still being installed. The job
+// goes back to pending and is retried after deferRetryInterval.
+var ErrJobDeferred = errors.New("job deferred")
+
+const deferRetryInterval = 15 * time.Second
+
+// ErrJobQuarantined is returned (wrapped) by a JobProcessor when the upload scan
+// flagged the job's media. The job is held as quarantined until an admin reviews it.
+var ErrJobQuarantined = errors.New("job quarantined")
+
+// ErrJobCancelled is the cause of a job's context being cancelled through
+// CancelJob, as opposed to killed. Adapters stop their remote calls either way.
+var ErrJobCancelled = errors.New("job cancelled")
+
+// ErrJobNotCancellable is returned by CancelJob for jobs neither queued nor
+// running
+var ErrJobNotCancellable = errors.New("job is not queued or running")
+
 // RunningJob tracks both context cancellation and OS process
 type RunningJob struct {
-	Cancel  context.CancelFunc
+	Cancel  context.CancelCauseFunc
 	Process *exec.Cmd
 }
 
@@ -34,12 +58,17 @@ type TaskQueue struct {
 	wg                sync.WaitGroup
 	processor         JobProcessor
 	runningJobs       map[string]*RunningJob
+	deferredJobs      map[string]time.Time // guarded by jobsMutex
 	jobsMutex         sync.RWMutex
 	workerMutex       sync.Mutex
 	autoScale         bool
 	lastScaleTime     time.Time
 	executedJobsCount int
 	executedJobsMutex sync.RWMutex
+	workerID          string // name jobs are claimed under
+	shared            bool   // other worker processes share the database
+	submitOnly        bool   // jobs are run by separate worker processes
+	events            *events.Bus
 }
 
 // JobProcessor defines the interface for processing jobs
@@ -105,24 +134,31 @@ func NewTaskQueue(legacyWorkers int, processor JobProcessor) *TaskQueue {
 		cancel:            cancel,
 		processor:         processor,
 		runningJobs:       make(map[string]*RunningJob),
+		deferredJobs:      make(map[string]time.Time),
 		autoScale:         autoScale,
 		lastScaleTime:     time.Now(),
 		executedJobsCount: 0,
+		workerID:          defaultWorkerID(),
 	}
 }
 
 // Start starts the task queue workers
 func (tq *TaskQueue) Start() {
+	// Reset any zombie jobs from previous runs synchronously before starting workers
+	tq.ResetZombieJobs()
+	if tq.submitOnly {
+		logger.Debug("Task queue is submit-only, jobs are left to worker processes")
+		return
+	}
+
 	workers := int(atomic.LoadInt64(&tq.currentWorkers))
 	logger.Debug("Starting task queue",
 		"workers", workers,
 		"min_workers", tq.minWorkers,
 		"max_workers", tq.maxWorkers,
 		"max_workers", tq.maxWorkers,
-		"auto_scale", tq.autoScale)
-
-	// Reset any zombie jobs from previous runs synchronously before starting workers
-	tq.ResetZombieJobs()
+		"auto_scale", tq.autoScale,
+		"worker", tq.workerID)
 
 	// Start initial workers
 	for i := 0; i < workers; i++ {
@@ -161,6 +197,10 @@ func (tq *TaskQueue) EnqueueJob(jobID string) error {
 		return fmt.Errorf("queue is shutting down")
 	default:
 	}
+	if tq.submitOnly {
+		// A worker process claims it from the database
+		return nil
+	}
 
 	tq.jobsMutex.Lock()
 	runningJobs := len(tq.runningJobs)
@@ -208,14 +248,22 @@ func (tq *TaskQueue) worker(id int) {
 				return
 			}
 
-			// Update job status to processing
-			if err := tq.updateJobStatus(jobID, models.StatusProcessing); err != nil {
-				logger.Error("Failed to update job status", "worker_id", id, "job_id", jobID, "error", err)
+			// Claim the job, unless another worker got to it first
+			if claimed, err := tq.claimJob(jobID); err != nil || !claimed {
+				if err != nil {
+					logger.Error("Failed to claim job", "worker_id", id, "job_id", jobID, "error", err)
+				} else {
+					logger.Debug("Job already claimed", "worker_id", id, "job_id", jobID)
+				}
+				tq.executedJobsMutex.Lock()
+				tq.executedJobsCount--
+				tq.executedJobsMutex.Unlock()
 				continue
 			}
+			tq.publishJob(jobID, events.JobProcessing)
 
 			// Create context for this job and track it
-			jobCtx, jobCancel := context.WithCancel(tq.ctx)
+			jobCtx, jobCancel := context.WithCancelCause(tq.ctx)
 			runningJob := &RunningJob{
 				Cancel:  jobCancel,
 				Process: nil, // Will be set by registerProcess callback
@@ -234,28 +282,54 @@ func (tq *TaskQueue) worker(id int) {
 				tq.jobsMutex.Unlock()
 			}
 
-			// Process the job with process registration
-			err := tq.processor.ProcessJobWithProcess(jobCtx, jobID, registerProcess)
+			// Process the job with process registration, continuing the trace
+			// of the request that queued it
+			traceCtx, span := tracing.StartJob(jobCtx, jobID, "queue.process_job", tracing.Int("worker.id", id))
+			err := tq.processor.ProcessJobWithProcess(traceCtx, jobID, registerProcess)
+			if !errors.Is(err, ErrJobDeferred) {
+				span.RecordError(err)
+			}
+			span.End()
 
 			// Remove job from running jobs
 			tq.jobsMutex.Lock()
 			delete(tq.runningJobs, jobID)
 			tq.jobsMutex.Unlock()
+			tq.endJobStage(jobID)
 
 			// Handle result
 			if err != nil {
-				if jobCtx.Err() == context.Canceled {
+				if errors.Is(err, ErrJobDeferred) && jobCtx.Err() == nil {
+					logger.Info("Job deferred", "worker_id", id, "job_id", jobID, "reason", err)
+					tq.jobsMutex.Lock()
+					tq.deferredJobs[jobID] = time.Now().Add(deferRetryInterval)
+					tq.jobsMutex.Unlock()
+					tq.updateJobStatus(jobID, models.StatusPending)
+				} else if errors.Is(err, ErrJobQuarantined) {
+					logger.Warn("Job quarantined", "worker_id", id, "job_id", jobID, "reason", err)
+					tq.updateJobStatus(jobID, models.StatusQuarantined)
+					tq.updateJobError(jobID, models.ErrorAudioQuarantined, err.Error())
+					tq.publishJob(jobID, events.JobFailed)
+				} else if errors.Is(context.Cause(jobCtx), ErrJobCancelled) {
+					logger.Info("Job cancelled", "worker_id", id, "job_id", jobID)
+					tq.updateJobStatus(jobID, models.StatusCancelled)
+					tq.updateJobError(jobID, models.ErrorJobCancelled, "Job was cancelled by user")
+					tq.publishJob(jobID, events.JobCancelled)
+				} else if jobCtx.Err() == context.Canceled {
 					logger.Info("Job cancelled", "worker_id", id, "job_id", jobID)
 					tq.updateJobStatus(jobID, models.StatusFailed)
-					tq.updateJobError(jobID, "Job was cancelled by user")
+					tq.updateJobError(jobID, models.ErrorJobCancelled, "Job was cancelled by user")
+					tq.publishJob(jobID, events.JobFailed)
 				} else {
 					logger.Error("Job processing failed", "worker_id", id, "job_id", jobID, "error", err)
 					tq.updateJobStatus(jobID, models.StatusFailed)
-					tq.updateJobError(jobID, err.Error())
+					tq.updateJobError(jobID, failureCode(err), err.Error())
+					tq.publishJob(jobID, events.JobFailed)
 				}
 			} else {
 				logger.Debug("Job processed successfully", "worker_id", id, "job_id", jobID)
 				tq.updateJobStatus(jobID, models.StatusCompleted)
+				tq.publishJob(jobID, events.JobCompleted)
 			}
 
 			// I am free now, let's start next one if available
@@ -280,6 +354,8 @@ func (tq *TaskQueue) jobScanner() {
 	for {
 		select {
 		case <-ticker.C:
+			tq.heartbeat()
+			tq.requeueStaleJobs()
 			tq.scanPendingJobs()
 		case <-tq.ctx.Done():
 			logger.Debug("Job scanner stopped")
@@ -295,13 +371,17 @@ func (tq *TaskQueue) scanPendingJobs() {
 	runningJobs := len(tq.runningJobs)
 	tq.jobsMutex.Unlock()
 	availableWorkers := workers - runningJobs
-	if availableWorkers == 0 {
+	if availableWorkers <= 0 {
 		return
 	}
 
 	var jobs []models.TranscriptionJob
 
-	if err := database.DB.Where("status = ?", models.StatusPending).Limit(availableWorkers).Find(&jobs).Error; err != nil {
+	query := database.DB.Where("status = ?", models.StatusPending)
+	if deferred := tq.deferredJobIDs(); len(deferred) > 0 {
+		query = query.Where("id NOT IN ?", deferred)
+	}
+	if err := query.Limit(availableWorkers).Find(&jobs).Error; err != nil {
 		logger.Error("Failed to scan pending jobs", "error", err)
 		return
 	}
@@ -317,6 +397,23 @@ func (tq *TaskQueue) scanPendingJobs() {
 	}
 }
 
+// deferredJobIDs returns jobs still waiting out their retry delay, dropping expired entries
+func (tq *TaskQueue) deferredJobIDs() []string {
+	tq.jobsMutex.Lock()
+	defer tq.jobsMutex.Unlock()
+
+	now := time.Now()
+	var ids []string
+	for jobID, retryAt := range tq.deferredJobs {
+		if now.After(retryAt) {
+			delete(tq.deferredJobs, jobID)
+			continue
+		}
+		ids = append(ids, jobID)
+	}
+	return ids
+}
+
 // KillJob aggressively terminates a running job
 func (tq *TaskQueue) KillJob(jobID string) error {
 	tq.jobsMutex.Lock()
@@ -333,10 +430,19 @@ func (tq *TaskQueue) KillJob(jobID string) error {
 			return fmt.Errorf("job %s not found: %v", jobID, err)
 		}
 
+		if tq.runningElsewhere(&job) {
+			// The worker running it kills it on its next heartbeat
+			logger.Info("Asking worker to cancel job", "job_id", jobID, "worker", job.WorkerID)
+			return database.DB.Model(&models.TranscriptionJob{}).
+				Where("id = ?", jobID).
+				UpdateColumn("cancel_requested_at", time.Now()).Error
+		}
+
 		if job.Status == models.StatusProcessing {
 			logger.Info("Found zombie job in DB, marking as failed", "job_id", jobID)
 			tq.updateJobStatus(jobID, models.StatusFailed)
-			tq.updateJobError(jobID, "Job was forcefully terminated by user (zombie process)")
+			tq.updateJobError(jobID, models.ErrorJobCancelled, "Job was forcefully terminated by user (zombie process)")
+			tq.publishJob(jobID, events.JobFailed)
 			return nil
 		}
 
@@ -344,7 +450,20 @@ func (tq *TaskQueue) KillJob(jobID string) error {
 	}
 
 	logger.Info("Killing job", "job_id", jobID)
+	tq.stopRunningJob(jobID, runningJob, context.Canceled)
 
+	// Immediately update job status without waiting for process to finish
+	go func() {
+		tq.updateJobStatus(jobID, models.StatusFailed)
+		tq.updateJobError(jobID, models.ErrorJobCancelled, "Job was forcefully terminated by user")
+	}()
+
+	return nil
+}
+
+// stopRunningJob kills a job's processes and cancels its context with cause.
+// The caller holds jobsMutex.
+func (tq *TaskQueue) stopRunningJob(jobID string, runningJob *RunningJob, cause error) {
 	// Check if this is a multi-track job and handle accordingly
 	if mtProcessor, ok := tq.processor.(MultiTrackJobProcessor); ok && mtProcessor.IsMultiTrackJob(jobID) {
 		logger.Debug("Terminating multi-track job", "job_id", jobID)
@@ -364,25 +483,80 @@ func (tq *TaskQueue) KillJob(jobID string) error {
 		}
 	}
 
-	// Also cancel the context for cleanup
-	runningJob.Cancel()
+	// Also cancel the context, which stops remote adapter calls and releases
+	// the worker
+	runningJob.Cancel(cause)
+}
 
-	// Immediately update job status without waiting for process to finish
-	go func() {
-		tq.updateJobStatus(jobID, models.StatusFailed)
-		tq.updateJobError(jobID, "Job was forcefully terminated by user")
-	}()
+// CancelJob cancels a queued or running job, stopping its local processes and
+// the calls remote adapters (RunPod, Modal) are making for it, and marks it
+// cancelled. Jobs running in another worker process are cancelled by it on
+// its next heartbeat.
+func (tq *TaskQueue) CancelJob(jobID string) error {
+	tq.jobsMutex.Lock()
+	defer tq.jobsMutex.Unlock()
+
+	if runningJob, exists := tq.runningJobs[jobID]; exists {
+		logger.Info("Cancelling job", "job_id", jobID)
+		tq.stopRunningJob(jobID, runningJob, ErrJobCancelled)
+		// The worker records the cancellation again once the job has stopped
+		tq.updateJobStatus(jobID, models.StatusCancelled)
+		tq.updateJobError(jobID, models.ErrorJobCancelled, "Job was cancelled by user")
+		return nil
+	}
+
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		return fmt.Errorf("job %s not found: %w", jobID, err)
+	}
+	updates := map[string]interface{}{
+		"status":        models.StatusCancelled,
+		"error_message": "Job was cancelled by user",
+		"error_code":    models.ErrorJobCancelled,
+	}
+	switch {
+	case tq.runningElsewhere(&job):
+		logger.Info("Asking worker to cancel job", "job_id", jobID, "worker", job.WorkerID)
+		updates["cancel_requested_at"] = time.Now()
+	case job.Status == models.StatusPending || job.Status == models.StatusProcessing:
+		// Queued, or left processing by a worker that is gone
+		delete(tq.deferredJobs, jobID)
+	default:
+		return ErrJobNotCancellable
+	}
 
+	// Only while the job is still where it was found, so a job claimed or
+	// finished meanwhile is not marked cancelled
+	result := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id = ? AND status = ?", jobID, job.Status).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobNotCancellable
+	}
+	logger.Info("Job cancelled", "job_id", jobID, "previous_status", job.Status)
+	tq.publishJob(jobID, events.JobCancelled)
 	return nil
 }
 
-// IsJobRunning checks if a job is currently being processed
+// IsJobRunning checks if a job is currently being processed, here or by
+// another worker process that is still heartbeating
 func (tq *TaskQueue) IsJobRunning(jobID string) bool {
 	tq.jobsMutex.RLock()
-	defer tq.jobsMutex.RUnlock()
-
 	_, exists := tq.runningJobs[jobID]
-	return exists
+	tq.jobsMutex.RUnlock()
+	if exists {
+		return true
+	}
+
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "status", "worker_id", "heartbeat_at").
+		Where("id = ?", jobID).First(&job).Error; err != nil {
+		return false
+	}
+	return tq.runningElsewhere(&job)
 }
 
 // updateJobStatus updates the status of a job
@@ -392,17 +566,49 @@ func (tq *TaskQueue) updateJobStatus(jobID string, status models.JobStatus) erro
 		Update("status", status).Error
 }
 
-// updateJobError updates the error message of a job
-func (tq *TaskQueue) updateJobError(jobID string, errorMsg string) error {
+// SetEvents publishes the jobs' lifecycle events to bus as they are claimed and finish
+func (tq *TaskQueue) SetEvents(bus *events.Bus) {
+	tq.events = bus
+}
+
+// publishJob publishes an event with the job as it is now in the database
+func (tq *TaskQueue) publishJob(jobID, eventType string) {
+	if !tq.events.Enabled() {
+		return
+	}
+	var job models.TranscriptionJob
+	if err := database.DB.Omit("transcript", "individual_transcripts").Where("id = ?", jobID).First(&job).Error; err != nil {
+		logger.Warn("Failed to load job for event", "job_id", jobID, "type", eventType, "error", err)
+		return
+	}
+	tq.events.PublishJob(eventType, &job)
+}
+
+// updateJobError updates the error code and message of a job
+func (tq *TaskQueue) updateJobError(jobID string, code models.ErrorCode, errorMsg string) error {
 	return database.DB.Model(&models.TranscriptionJob{}).
 		Where("id = ?", jobID).
-		Update("error_message", errorMsg).Error
+		Updates(map[string]interface{}{"error_code": code, "error_message": errorMsg}).Error
+}
+
+// failureCode is the error code of a job that failed with err: the code the
+// processor gave it, or one guessed from err
+func failureCode(err error) models.ErrorCode {
+	if code := models.ErrorCodeOf(err); code != "" {
+		return code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.ErrorAdapterTimeout
+	}
+	return models.ErrorTranscriptionFailed
 }
 
 // GetJobStatus gets the status of a job
 func (tq *TaskQueue) GetJobStatus(jobID string) (*models.TranscriptionJob, error) {
 	var job models.TranscriptionJob
-	err := database.DB.Where("id = ?", jobID).First(&job).Error
+	err := database.DB.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("started_at, id")
+	}).Where("id = ?", jobID).First(&job).Error
 	if err != nil {
 		return nil, err
 	}
@@ -492,15 +698,44 @@ func (tq *TaskQueue) GetQueueStats() map[string]interface{} {
 		"processing_jobs": processingCount,
 		"completed_jobs":  completedCount,
 		"failed_jobs":     failedCount,
+		"worker_id":       tq.workerID,
+		"submit_only":     tq.submitOnly,
+		"workers":         tq.workerStatuses(),
 	}
 }
 
-// ResetZombieJobs finds jobs stuck in processing state from previous runs and marks them as failed
+// ResetZombieJobs finds jobs stuck in processing state from previous runs and
+// queues them again. A worker sharing the database with others only takes back
+// its own jobs and those of workers that stopped heartbeating.
 func (tq *TaskQueue) ResetZombieJobs() {
+	// URL downloads cannot be resumed, so interrupted ones are failed outright.
+	// They run in the API process, not in worker processes.
+	if !tq.shared {
+		result := database.DB.Model(&models.TranscriptionJob{}).
+			Where("status = ?", models.StatusDownloading).
+			Updates(map[string]interface{}{
+				"status":        models.StatusFailed,
+				"error_message": "Download interrupted by server restart",
+				"error_code":    models.ErrorJobInterrupted,
+			})
+		if result.Error != nil {
+			logger.Error("Failed to reset interrupted downloads", "error", result.Error)
+		} else if result.RowsAffected > 0 {
+			logger.Info("Failed interrupted URL downloads from previous run", "count", result.RowsAffected)
+		}
+	}
+	if tq.submitOnly {
+		return
+	}
+
 	var zombieJobs []models.TranscriptionJob
 
 	// Find all jobs with status "processing"
-	if err := database.DB.Where("status = ?", models.StatusProcessing).Find(&zombieJobs).Error; err != nil {
+	query := database.DB.Where("status = ?", models.StatusProcessing)
+	if tq.shared {
+		query = query.Where("worker_id = ? OR heartbeat_at < ?", tq.workerID, time.Now().Add(-heartbeatTimeout))
+	}
+	if err := query.Find(&zombieJobs).Error; err != nil {
 		logger.Error("Failed to scan for zombie jobs", "error", err)
 		return
 	}
@@ -521,7 +756,7 @@ func (tq *TaskQueue) ResetZombieJobs() {
 		}
 
 		// Update error message
-		if err := tq.updateJobError(job.ID, "Job interrupted by server restart"); err != nil {
+		if err := tq.updateJobError(job.ID, models.ErrorJobInterrupted, "Job interrupted by server restart"); err != nil {
 			logger.Error("Failed to update zombie job error message", "job_id", job.ID, "error", err)
 		}
 	}
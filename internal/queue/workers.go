@@ -0,0 +1,237 @@
+package queue
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/metrics"
+)
+
+// heartbeatTimeout is how long a claimed job may go without a heartbeat from
+// its worker before it is taken to be interrupted and queued again. Workers
+// refresh the heartbeats of their jobs on every scan.
+const heartbeatTimeout = 2 * time.Minute
+
+// WorkerStatus is a worker process with jobs running, as seen in the database
+type WorkerStatus struct {
+	ID            string    `json:"id"`
+	RunningJobs   int       `json:"running_jobs"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	// Stale is set once the worker stopped heartbeating; its jobs are queued again
+	Stale bool `json:"stale"`
+}
+
+// defaultWorkerID names the process after its host
+func defaultWorkerID() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "scriberr"
+}
+
+// SetWorkerID makes the queue one of several worker processes sharing the
+// database, claiming jobs as id (empty: the host name). On start it then only
+// queues again the jobs it claimed itself, leaving those of other workers be
+// until they stop heartbeating, and it leaves URL downloads to the API process.
+func (tq *TaskQueue) SetWorkerID(id string) {
+	if id != "" {
+		tq.workerID = id
+	}
+	tq.shared = true
+}
+
+// DisableWorkers makes the queue submit-only, for an API process whose jobs
+// are run by separate worker processes: queued jobs are left pending in the
+// database for a worker to claim, and kills are passed on to the worker
+// running the job.
+func (tq *TaskQueue) DisableWorkers() {
+	tq.submitOnly = true
+}
+
+// WorkerID returns the name the queue claims jobs under
+func (tq *TaskQueue) WorkerID() string {
+	return tq.workerID
+}
+
+// claimJob moves a pending job to processing under this worker, returning
+// false if it is no longer pending, e.g. because another worker claimed it
+func (tq *TaskQueue) claimJob(jobID string) (bool, error) {
+	result := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id = ? AND status = ?", jobID, models.StatusPending).
+		Updates(map[string]interface{}{
+			"status":              models.StatusProcessing,
+			"worker_id":           tq.workerID,
+			"heartbeat_at":        time.Now(),
+			"cancel_requested_at": nil,
+			"stage":               "",
+			"error_code":          "",
+		})
+	if result.Error != nil || result.RowsAffected != 1 {
+		return false, result.Error
+	}
+	// The stages recorded are those of the job's latest run
+	if err := database.DB.Where("transcription_job_id = ?", jobID).Delete(&models.JobStage{}).Error; err != nil {
+		logger.Warn("Failed to clear job stages", "job_id", jobID, "error", err)
+	}
+	return true, nil
+}
+
+// endJobStage ends the stage a job that stopped running was in, and records
+// how long its run spent in each stage on the job and in the stage metrics
+func (tq *TaskQueue) endJobStage(jobID string) {
+	if err := database.DB.Model(&models.JobStage{}).
+		Where("transcription_job_id = ? AND ended_at IS NULL", jobID).
+		Update("ended_at", time.Now()).Error; err != nil {
+		logger.Warn("Failed to end job stage", "job_id", jobID, "error", err)
+	}
+
+	var job models.TranscriptionJob
+	var stages []models.JobStage
+	if err := database.DB.Select("id", "model_family", "timing_post_processing_ms").
+		Where("id = ?", jobID).First(&job).Error; err != nil {
+		logger.Warn("Failed to load job for stage timings", "job_id", jobID, "error", err)
+		return
+	}
+	if err := database.DB.Where("transcription_job_id = ?", jobID).Find(&stages).Error; err != nil {
+		logger.Warn("Failed to load job stages", "job_id", jobID, "error", err)
+	}
+	durations := make(map[string]time.Duration)
+	for _, stage := range stages {
+		if stage.EndedAt != nil {
+			durations[stage.Stage] += stage.EndedAt.Sub(stage.StartedAt)
+		}
+	}
+	// Post-processing is timed once the job has completed
+	timings := models.StageTimings{PostProcessingMs: job.StageTimings.PostProcessingMs}
+	for stage, d := range durations {
+		timings.Add(stage, d)
+		metrics.JobStageDuration.Observe(d.Seconds(), stage, job.Parameters.ModelFamily)
+	}
+
+	if err := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		UpdateColumns(map[string]interface{}{
+			"stage":                   "",
+			"timing_download_ms":      timings.DownloadMs,
+			"timing_conversion_ms":    timings.ConversionMs,
+			"timing_transcription_ms": timings.TranscriptionMs,
+			"timing_alignment_ms":     timings.AlignmentMs,
+			"timing_diarization_ms":   timings.DiarizationMs,
+			"timing_upload_ms":        timings.UploadMs,
+		}).Error; err != nil {
+		logger.Warn("Failed to end job stage", "job_id", jobID, "error", err)
+	}
+}
+
+// runningElsewhere reports whether a processing job is held by another worker
+// that is still heartbeating
+func (tq *TaskQueue) runningElsewhere(job *models.TranscriptionJob) bool {
+	return job.Status == models.StatusProcessing &&
+		job.WorkerID != "" && job.WorkerID != tq.workerID &&
+		job.HeartbeatAt != nil && time.Since(*job.HeartbeatAt) < heartbeatTimeout
+}
+
+// runningJobIDs returns the jobs running in this process
+func (tq *TaskQueue) runningJobIDs() []string {
+	tq.jobsMutex.RLock()
+	defer tq.jobsMutex.RUnlock()
+
+	ids := make([]string, 0, len(tq.runningJobs))
+	for jobID := range tq.runningJobs {
+		ids = append(ids, jobID)
+	}
+	return ids
+}
+
+// heartbeat marks the jobs running here as alive, and kills those another
+// process asked to cancel
+func (tq *TaskQueue) heartbeat() {
+	ids := tq.runningJobIDs()
+	if len(ids) == 0 {
+		return
+	}
+
+	// UpdateColumn leaves updated_at alone
+	if err := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id IN ? AND worker_id = ?", ids, tq.workerID).
+		UpdateColumn("heartbeat_at", time.Now()).Error; err != nil {
+		logger.Error("Failed to record job heartbeats", "worker", tq.workerID, "error", err)
+		return
+	}
+
+	// Jobs marked cancelled were cancelled through CancelJob, the others killed
+	var requests []models.TranscriptionJob
+	if err := database.DB.Model(&models.TranscriptionJob{}).
+		Select("id", "status").
+		Where("id IN ? AND cancel_requested_at IS NOT NULL", ids).
+		Find(&requests).Error; err != nil {
+		logger.Error("Failed to check for cancel requests", "worker", tq.workerID, "error", err)
+		return
+	}
+	for _, job := range requests {
+		logger.Info("Cancelling job on request", "job_id", job.ID, "worker", tq.workerID)
+		stop := tq.KillJob
+		if job.Status == models.StatusCancelled {
+			stop = tq.CancelJob
+		}
+		if err := stop(job.ID); err != nil {
+			logger.Warn("Failed to cancel job", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// requeueStaleJobs queues again the jobs whose worker stopped heartbeating,
+// e.g. because it crashed or lost its connection to the database
+func (tq *TaskQueue) requeueStaleJobs() {
+	query := database.DB.Model(&models.TranscriptionJob{}).
+		Where("status = ? AND worker_id <> '' AND heartbeat_at < ?", models.StatusProcessing, time.Now().Add(-heartbeatTimeout))
+	if running := tq.runningJobIDs(); len(running) > 0 {
+		query = query.Where("id NOT IN ?", running)
+	}
+	result := query.Updates(map[string]interface{}{
+		"status":        models.StatusPending,
+		"error_message": "Job interrupted: its worker stopped responding",
+		"error_code":    models.ErrorJobInterrupted,
+	})
+	if result.Error != nil {
+		logger.Error("Failed to requeue jobs of stale workers", "error", result.Error)
+	} else if result.RowsAffected > 0 {
+		logger.Warn("Requeued jobs of workers that stopped responding", "count", result.RowsAffected)
+	}
+}
+
+// workerStatuses reports the workers holding processing jobs
+func (tq *TaskQueue) workerStatuses() []WorkerStatus {
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("worker_id", "heartbeat_at").
+		Where("status = ? AND worker_id <> ''", models.StatusProcessing).
+		Find(&jobs).Error; err != nil {
+		logger.Error("Failed to list workers", "error", err)
+		return []WorkerStatus{}
+	}
+
+	byID := make(map[string]*WorkerStatus)
+	for _, job := range jobs {
+		worker, ok := byID[job.WorkerID]
+		if !ok {
+			worker = &WorkerStatus{ID: job.WorkerID}
+			byID[job.WorkerID] = worker
+		}
+		worker.RunningJobs++
+		if job.HeartbeatAt != nil && job.HeartbeatAt.After(worker.LastHeartbeat) {
+			worker.LastHeartbeat = *job.HeartbeatAt
+		}
+	}
+
+	workers := make([]WorkerStatus, 0, len(byID))
+	for _, worker := range byID {
+		worker.Stale = time.Since(worker.LastHeartbeat) >= heartbeatTimeout
+		workers = append(workers, *worker)
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].ID < workers[j].ID })
+	return workers
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AdapterConfigRepository handles the adapter settings set through the admin API
+type AdapterConfigRepository interface {
+	List(ctx context.Context) ([]models.AdapterConfig, error)
+	FindByModelID(ctx context.Context, modelID string) (*models.AdapterConfig, error)
+	// Save creates or replaces the settings of config.ModelID
+	Save(ctx context.Context, config *models.AdapterConfig) error
+	Delete(ctx context.Context, modelID string) error
+}
+
+type adapterConfigRepository struct {
+	db *gorm.DB
+}
+
+func NewAdapterConfigRepository(db *gorm.DB) AdapterConfigRepository {
+	return &adapterConfigRepository{db: db}
+}
+
+func (r *adapterConfigRepository) List(ctx context.Context) ([]models.AdapterConfig, error) {
+	configs := []models.AdapterConfig{}
+	err := r.db.WithContext(ctx).Order("model_id").Find(&configs).Error
+	return configs, err
+}
+
+func (r *adapterConfigRepository) FindByModelID(ctx context.Context, modelID string) (*models.AdapterConfig, error) {
+	var config models.AdapterConfig
+	if err := r.db.WithContext(ctx).Where("model_id = ?", modelID).First(&config).Error; err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (r *adapterConfigRepository) Save(ctx context.Context, config *models.AdapterConfig) error {
+	return r.db.WithContext(ctx).Save(config).Error
+}
+
+func (r *adapterConfigRepository) Delete(ctx context.Context, modelID string) error {
+	result := r.db.WithContext(ctx).Where("model_id = ?", modelID).Delete(&models.AdapterConfig{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AWSTagRouteRepository handles the tag routes of the AWS Transcribe compatible endpoint
+type AWSTagRouteRepository interface {
+	// List returns the routes ctx may see, highest priority first
+	List(ctx context.Context) ([]models.AWSTagRoute, error)
+	FindByID(ctx context.Context, id uint) (*models.AWSTagRoute, error)
+	Create(ctx context.Context, route *models.AWSTagRoute) error
+	Update(ctx context.Context, route *models.AWSTagRoute) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type awsTagRouteRepository struct {
+	db *gorm.DB
+}
+
+func NewAWSTagRouteRepository(db *gorm.DB) AWSTagRouteRepository {
+	return &awsTagRouteRepository{db: db}
+}
+
+func (r *awsTagRouteRepository) List(ctx context.Context) ([]models.AWSTagRoute, error) {
+	routes := []models.AWSTagRoute{}
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Order("priority DESC, id ASC").Find(&routes).Error
+	return routes, err
+}
+
+func (r *awsTagRouteRepository) FindByID(ctx context.Context, id uint) (*models.AWSTagRoute, error) {
+	var route models.AWSTagRoute
+	if err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("id = ?", id).First(&route).Error; err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+func (r *awsTagRouteRepository) Create(ctx context.Context, route *models.AWSTagRoute) error {
+	return r.db.WithContext(ctx).Create(route).Error
+}
+
+func (r *awsTagRouteRepository) Update(ctx context.Context, route *models.AWSTagRoute) error {
+	return r.db.WithContext(ctx).Save(route).Error
+}
+
+func (r *awsTagRouteRepository) Delete(ctx context.Context, id uint) error {
+	result := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("id = ?", id).Delete(&models.AWSTagRoute{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
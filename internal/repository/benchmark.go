@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BenchmarkRepository handles adapter benchmarks with their samples and runs
+type BenchmarkRepository interface {
+	// Create stores a benchmark with its samples, and a pending run of each of
+	// its adapters on each sample
+	Create(ctx context.Context, benchmark *models.Benchmark) error
+	// FindByID returns a benchmark with its samples and runs
+	FindByID(ctx context.Context, id string) (*models.Benchmark, error)
+	// List returns the benchmarks without their samples and runs, newest first
+	List(ctx context.Context) ([]models.Benchmark, error)
+	// ListUnfinished returns the IDs of the benchmarks still pending or processing, oldest first
+	ListUnfinished(ctx context.Context) ([]string, error)
+	// Update, UpdateSample and UpdateRun return gorm.ErrRecordNotFound once
+	// the benchmark has been deleted
+	Update(ctx context.Context, benchmark *models.Benchmark) error
+	UpdateSample(ctx context.Context, sample *models.BenchmarkSample) error
+	UpdateRun(ctx context.Context, run *models.BenchmarkRun) error
+	Delete(ctx context.Context, id string) error
+}
+
+type benchmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBenchmarkRepository(db *gorm.DB) BenchmarkRepository {
+	return &benchmarkRepository{db: db}
+}
+
+func (r *benchmarkRepository) Create(ctx context.Context, benchmark *models.Benchmark) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		benchmark.Runs = nil
+		if err := tx.Create(benchmark).Error; err != nil {
+			return err
+		}
+		for _, sample := range benchmark.Samples {
+			for _, modelID := range benchmark.ModelIDList() {
+				benchmark.Runs = append(benchmark.Runs, models.BenchmarkRun{
+					BenchmarkID: benchmark.ID,
+					SampleID:    sample.ID,
+					ModelID:     modelID,
+					Status:      models.StatusPending,
+				})
+			}
+		}
+		if len(benchmark.Runs) == 0 {
+			return nil
+		}
+		return tx.Create(&benchmark.Runs).Error
+	})
+}
+
+func (r *benchmarkRepository) FindByID(ctx context.Context, id string) (*models.Benchmark, error) {
+	var benchmark models.Benchmark
+	err := r.db.WithContext(ctx).
+		Preload("Samples", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Runs", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("id = ?", id).First(&benchmark).Error
+	if err != nil {
+		return nil, err
+	}
+	return &benchmark, nil
+}
+
+func (r *benchmarkRepository) List(ctx context.Context) ([]models.Benchmark, error) {
+	benchmarks := []models.Benchmark{}
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&benchmarks).Error
+	return benchmarks, err
+}
+
+func (r *benchmarkRepository) ListUnfinished(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&models.Benchmark{}).
+		Where("status IN ?", []models.JobStatus{models.StatusPending, models.StatusProcessing}).
+		Order("created_at ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *benchmarkRepository) Update(ctx context.Context, benchmark *models.Benchmark) error {
+	return updateBenchmarkRow(r.db.WithContext(ctx).Model(benchmark).Select("*").Omit("Samples", "Runs").Updates(benchmark))
+}
+
+func (r *benchmarkRepository) UpdateSample(ctx context.Context, sample *models.BenchmarkSample) error {
+	return updateBenchmarkRow(r.db.WithContext(ctx).Model(sample).Select("*").Updates(sample))
+}
+
+func (r *benchmarkRepository) UpdateRun(ctx context.Context, run *models.BenchmarkRun) error {
+	return updateBenchmarkRow(r.db.WithContext(ctx).Model(run).Select("*").Updates(run))
+}
+
+// updateBenchmarkRow reports a row deleted while its benchmark ran as not
+// found, rather than saving it again
+func updateBenchmarkRow(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *benchmarkRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.Benchmark{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("benchmark_id = ?", id).Delete(&models.BenchmarkRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("benchmark_id = ?", id).Delete(&models.BenchmarkSample{}).Error
+	})
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CollectionRepository handles the collections jobs are filed in and who may
+// access the restricted ones
+type CollectionRepository interface {
+	// ListByWorkspace lists every collection of a workspace, by name
+	ListByWorkspace(ctx context.Context, workspace string) ([]CollectionWithJobs, error)
+	FindInWorkspace(ctx context.Context, workspace, id string) (*models.Collection, error)
+	Create(ctx context.Context, collection *models.Collection) error
+	Update(ctx context.Context, collection *models.Collection) error
+	// DeleteCollection deletes a collection, moving its jobs and collections
+	// into its parent
+	DeleteCollection(ctx context.Context, collection *models.Collection) error
+	// MoveJobs files jobs in a collection, or in none when collectionID is nil
+	MoveJobs(ctx context.Context, jobIDs []string, collectionID *string) error
+	ListMembers(ctx context.Context, collectionID string) ([]models.CollectionMember, error)
+	// SetPermissions saves whether a collection is restricted and replaces its members
+	SetPermissions(ctx context.Context, collection *models.Collection, members []models.CollectionMember) error
+	// UserMemberships returns the roles a user was given in the collections of
+	// a workspace, by collection ID
+	UserMemberships(ctx context.Context, workspace string, userID uint) (map[string]string, error)
+}
+
+// CollectionWithJobs is a collection with the number of jobs filed directly in it
+type CollectionWithJobs struct {
+	models.Collection
+	JobCount int64 `json:"job_count"`
+}
+
+type collectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+func (r *collectionRepository) ListByWorkspace(ctx context.Context, workspace string) ([]CollectionWithJobs, error) {
+	collections := []CollectionWithJobs{}
+	err := r.db.WithContext(ctx).
+		Table("collections").
+		Select("collections.*, (SELECT COUNT(*) FROM transcription_jobs j WHERE j.collection_id = collections.id) AS job_count").
+		Where("collections.workspace = ?", workspace).
+		Order("collections.name ASC, collections.id ASC").
+		Scan(&collections).Error
+	return collections, err
+}
+
+func (r *collectionRepository) FindInWorkspace(ctx context.Context, workspace, id string) (*models.Collection, error) {
+	var collection models.Collection
+	if err := r.db.WithContext(ctx).Where("workspace = ? AND id = ?", workspace, id).First(&collection).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *collectionRepository) Create(ctx context.Context, collection *models.Collection) error {
+	return r.db.WithContext(ctx).Create(collection).Error
+}
+
+func (r *collectionRepository) Update(ctx context.Context, collection *models.Collection) error {
+	return r.db.WithContext(ctx).Save(collection).Error
+}
+
+func (r *collectionRepository) DeleteCollection(ctx context.Context, collection *models.Collection) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TranscriptionJob{}).Where("collection_id = ?", collection.ID).
+			Update("collection_id", collection.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Collection{}).Where("parent_id = ?", collection.ID).
+			Update("parent_id", collection.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(collection).Error
+	})
+}
+
+func (r *collectionRepository) MoveJobs(ctx context.Context, jobIDs []string, collectionID *string) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id IN ?", jobIDs).
+		Update("collection_id", collectionID).Error
+}
+
+func (r *collectionRepository) ListMembers(ctx context.Context, collectionID string) ([]models.CollectionMember, error) {
+	members := []models.CollectionMember{}
+	err := r.db.WithContext(ctx).Where("collection_id = ?", collectionID).Order("user_id ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *collectionRepository) SetPermissions(ctx context.Context, collection *models.Collection, members []models.CollectionMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(collection).Update("restricted", collection.Restricted).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionMember{}).Error; err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		return tx.Create(&members).Error
+	})
+}
+
+func (r *collectionRepository) UserMemberships(ctx context.Context, workspace string, userID uint) (map[string]string, error) {
+	var members []models.CollectionMember
+	err := r.db.WithContext(ctx).
+		Joins("JOIN collections c ON c.id = collection_members.collection_id").
+		Where("c.workspace = ? AND collection_members.user_id = ?", workspace, userID).
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]string, len(members))
+	for _, member := range members {
+		roles[member.CollectionID] = member.Role
+	}
+	return roles, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CorrectionRuleRepository handles the find-and-replace rules transcripts are corrected with
+type CorrectionRuleRepository interface {
+	// List returns the rules ctx may see, in the order they apply
+	List(ctx context.Context) ([]models.CorrectionRule, error)
+	FindByID(ctx context.Context, id uint) (*models.CorrectionRule, error)
+	Create(ctx context.Context, rule *models.CorrectionRule) error
+	Update(ctx context.Context, rule *models.CorrectionRule) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type correctionRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCorrectionRuleRepository(db *gorm.DB) CorrectionRuleRepository {
+	return &correctionRuleRepository{db: db}
+}
+
+func (r *correctionRuleRepository) List(ctx context.Context) ([]models.CorrectionRule, error) {
+	rules := []models.CorrectionRule{}
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Order("position ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *correctionRuleRepository) FindByID(ctx context.Context, id uint) (*models.CorrectionRule, error) {
+	var rule models.CorrectionRule
+	if err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("id = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *correctionRuleRepository) Create(ctx context.Context, rule *models.CorrectionRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *correctionRuleRepository) Update(ctx context.Context, rule *models.CorrectionRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *correctionRuleRepository) Delete(ctx context.Context, id uint) error {
+	result := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("id = ?", id).Delete(&models.CorrectionRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
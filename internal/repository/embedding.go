@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmbeddingRepository stores embedded transcript chunks for retrieval
+type EmbeddingRepository interface {
+	// ListStaleJobs returns completed jobs that were never embedded with model,
+	// or whose transcript changed since
+	ListStaleJobs(ctx context.Context, model string, limit int) ([]models.TranscriptionJob, error)
+	ReplaceChunks(ctx context.Context, jobID, model string, chunks []models.TranscriptChunk) error
+	// ListChunks returns every chunk embedded with model, optionally limited to
+	// jobs created within [from, to]
+	ListChunks(ctx context.Context, model string, from, to *time.Time) ([]EmbeddedChunk, error)
+	// ListJobOrganizations returns the organizations owning jobs
+	ListJobOrganizations(ctx context.Context) ([]string, error)
+}
+
+// EmbeddedChunk is a stored chunk together with its job's title and creation time
+type EmbeddedChunk struct {
+	models.TranscriptChunk
+	Title        *string
+	JobCreatedAt time.Time
+}
+
+type embeddingRepository struct {
+	db *gorm.DB
+}
+
+func NewEmbeddingRepository(db *gorm.DB) EmbeddingRepository {
+	return &embeddingRepository{db: db}
+}
+
+func (r *embeddingRepository) ListStaleJobs(ctx context.Context, model string, limit int) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := scopeJobs(ctx, r.db.WithContext(ctx), "transcription_jobs.workspace").
+		Joins("LEFT JOIN transcript_index_states s ON s.job_id = transcription_jobs.id").
+		Where("transcription_jobs.status = ? AND transcription_jobs.transcript IS NOT NULL", models.StatusCompleted).
+		Where("s.job_id IS NULL OR s.model != ? OR s.indexed_at < transcription_jobs.updated_at", model).
+		Order("transcription_jobs.updated_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *embeddingRepository) ReplaceChunks(ctx context.Context, jobID, model string, chunks []models.TranscriptChunk) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", jobID).Delete(&models.TranscriptChunk{}).Error; err != nil {
+			return err
+		}
+		for i := range chunks {
+			chunks[i].JobID = jobID
+			chunks[i].Model = model
+			chunks[i].Embedding = encodeVector(chunks[i].Vector)
+		}
+		if len(chunks) > 0 {
+			if err := tx.Omit("Job").CreateInBatches(chunks, 100).Error; err != nil {
+				return err
+			}
+		}
+		state := models.TranscriptIndexState{
+			JobID:      jobID,
+			Model:      model,
+			ChunkCount: len(chunks),
+			IndexedAt:  time.Now(),
+		}
+		return tx.Omit("Job").Clauses(clause.OnConflict{UpdateAll: true}).Create(&state).Error
+	})
+}
+
+func (r *embeddingRepository) ListChunks(ctx context.Context, model string, from, to *time.Time) ([]EmbeddedChunk, error) {
+	query := r.db.WithContext(ctx).
+		Table("transcript_chunks").
+		Select("transcript_chunks.*, j.title AS title, j.created_at AS job_created_at").
+		Joins("JOIN transcription_jobs j ON j.id = transcript_chunks.job_id").
+		Where("transcript_chunks.model = ?", model)
+	query = scopeCollections(ctx, scopeJobs(ctx, query, "j.workspace"), "j.collection_id")
+	if from != nil {
+		query = query.Where("j.created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("j.created_at <= ?", *to)
+	}
+
+	var chunks []EmbeddedChunk
+	if err := query.Scan(&chunks).Error; err != nil {
+		return nil, err
+	}
+	for i := range chunks {
+		chunks[i].Vector = decodeVector(chunks[i].Embedding)
+		chunks[i].Embedding = nil
+	}
+	return chunks, nil
+}
+
+func (r *embeddingRepository) ListJobOrganizations(ctx context.Context) ([]string, error) {
+	var workspaces []string
+	err := r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("workspace LIKE ?", models.OrganizationWorkspace("%")).
+		Distinct().Pluck("workspace", &workspaces).Error
+	if err != nil {
+		return nil, err
+	}
+	organizations := make([]string, len(workspaces))
+	for i, workspace := range workspaces {
+		organizations[i] = models.WorkspaceOrganization(workspace)
+	}
+	return organizations, nil
+}
+
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FieldPermissionRepository handles the job fields hidden from each role of a workspace
+type FieldPermissionRepository interface {
+	// Find returns a role's permission in a workspace, with nothing hidden if
+	// none was set
+	Find(ctx context.Context, workspace, role string) (*models.FieldPermission, error)
+	List(ctx context.Context, workspace string) ([]models.FieldPermission, error)
+	Save(ctx context.Context, permission *models.FieldPermission) error
+}
+
+type fieldPermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewFieldPermissionRepository(db *gorm.DB) FieldPermissionRepository {
+	return &fieldPermissionRepository{db: db}
+}
+
+func (r *fieldPermissionRepository) Find(ctx context.Context, workspace, role string) (*models.FieldPermission, error) {
+	permission := models.FieldPermission{Workspace: workspace, Role: role}
+	err := r.db.WithContext(ctx).Where("workspace = ? AND role = ?", workspace, role).Limit(1).Find(&permission).Error
+	return &permission, err
+}
+
+func (r *fieldPermissionRepository) List(ctx context.Context, workspace string) ([]models.FieldPermission, error) {
+	permissions := []models.FieldPermission{}
+	err := r.db.WithContext(ctx).Where("workspace = ?", workspace).Order("role ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *fieldPermissionRepository) Save(ctx context.Context, permission *models.FieldPermission) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(permission).Error
+}
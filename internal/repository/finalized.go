@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrJobFinalized is returned by writes that would change or reprocess a
+// finalized transcript
+var ErrJobFinalized = errors.New("transcript is finalized")
+
+type finalizedOverrideKey struct{}
+
+// WithFinalizedOverride allows the writes made with the returned context to
+// change finalized transcripts. It is meant for admins only.
+func WithFinalizedOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, finalizedOverrideKey{}, true)
+}
+
+// FinalizedOverride reports whether ctx may change finalized transcripts
+func FinalizedOverride(ctx context.Context) bool {
+	override, _ := ctx.Value(finalizedOverrideKey{}).(bool)
+	return override
+}
+
+// ensureUnfinalized returns ErrJobFinalized if the stored job is finalized and
+// ctx has no override. Unknown jobs are left to the write itself.
+func ensureUnfinalized(ctx context.Context, db *gorm.DB, jobID string) error {
+	if FinalizedOverride(ctx) {
+		return nil
+	}
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ? AND finalized_at IS NOT NULL", jobID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrJobFinalized
+	}
+	return nil
+}
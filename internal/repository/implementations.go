@@ -2,15 +2,37 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
 	"scriberr/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository handles user-specific database operations
 type UserRepository interface {
 	Repository[models.User]
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	CountByRole(ctx context.Context, role string) (int64, error)
+
+	// Organizations and their members
+	ListOrganizations(ctx context.Context) ([]models.Organization, error)
+	FindOrganization(ctx context.Context, id string) (*models.Organization, error)
+	CreateOrganization(ctx context.Context, organization *models.Organization) error
+	// DeleteOrganization deletes an organization with its memberships, profiles and
+	// LLM configs, and deactivates its API keys
+	DeleteOrganization(ctx context.Context, id string) error
+	CountOrganizationJobs(ctx context.Context, id string) (int64, error)
+	ListOrganizationMembers(ctx context.Context, id string) ([]models.User, error)
+	AddOrganizationMember(ctx context.Context, id string, userID uint) error
+	RemoveOrganizationMember(ctx context.Context, id string, userID uint) error
+	// ListUserOrganizations returns a user's organizations, oldest membership first
+	ListUserOrganizations(ctx context.Context, userID uint) ([]models.Organization, error)
 }
 
 type userRepository struct {
@@ -32,17 +54,101 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 	return &user, nil
 }
 
+func (r *userRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
 // JobRepository handles transcription job operations
 type JobRepository interface {
 	Repository[models.TranscriptionJob]
 	FindWithAssociations(ctx context.Context, id string) (*models.TranscriptionJob, error)
-	ListWithParams(ctx context.Context, offset, limit int, sortBy, sortOrder, searchQuery string) ([]models.TranscriptionJob, int64, error)
+	// ListJobs lists the jobs visible to ctx matching a query, a page at a time
+	ListJobs(ctx context.Context, q JobListQuery) (*JobListPage, error)
 	ListByUser(ctx context.Context, userID uint, offset, limit int) ([]models.TranscriptionJob, int64, error)
+	// FindByTitle returns the newest job visible to ctx with the title
+	FindByTitle(ctx context.Context, title string) (*models.TranscriptionJob, error)
+	// ListByStatusAndTitle lists the jobs visible to ctx, newest first, in any of
+	// statuses (all when empty) whose title contains titleContains, without
+	// their transcripts
+	ListByStatusAndTitle(ctx context.Context, statuses []models.JobStatus, titleContains string, offset, limit int) ([]models.TranscriptionJob, error)
+	// UpdateTranscript saves a job's transcript, keeping it as the job's next
+	// revision
 	UpdateTranscript(ctx context.Context, jobID string, transcript string) error
+	// ListTranscriptRevisions returns a job's revisions, oldest first, without
+	// their transcripts
+	ListTranscriptRevisions(ctx context.Context, jobID string) ([]models.TranscriptRevision, error)
+	FindTranscriptRevision(ctx context.Context, jobID string, revision int) (*models.TranscriptRevision, error)
+	DeleteTranscriptRevisionsByJobID(ctx context.Context, jobID string) error
+	UpdateSummary(ctx context.Context, jobID string, summary string) error
+	UpdateIngestProgress(ctx context.Context, jobID string, progress float64) error
+	UpdateProgress(ctx context.Context, jobID string, progress float64) error
+	// EnterStage ends the stage the job is in, if any, and starts stage;
+	// an empty stage only ends the current one
+	EnterStage(ctx context.Context, jobID, stage string) error
+	UpdateScanResult(ctx context.Context, jobID string, scanStatus string, signature *string) error
+	UpdateAlignmentFallback(ctx context.Context, jobID string, reason *string) error
+	UpdateAudioSHA256(ctx context.Context, jobID string, sum string) error
+	UpdateAudioSeconds(ctx context.Context, jobID string, seconds float64) error
+	// FindDuplicate returns the newest completed job that job can reuse the
+	// transcript of: one of the same workspace, profile version and parameter
+	// overrides whose audio has the same hash
+	FindDuplicate(ctx context.Context, job *models.TranscriptionJob) (*models.TranscriptionJob, error)
+	UpdateDuplicateOf(ctx context.Context, jobID string, originalID string) error
+	ListByStatus(ctx context.Context, status models.JobStatus) ([]models.TranscriptionJob, error)
+	ListForIntegrityCheck(ctx context.Context) ([]models.TranscriptionJob, error)
+	ListPendingPostProcessing(ctx context.Context, limit int) ([]models.TranscriptionJob, error)
+	UpdatePostProcessing(ctx context.Context, job *models.TranscriptionJob) error
+	// UpdateReview saves a job's low-confidence segment count and review state
+	UpdateReview(ctx context.Context, job *models.TranscriptionJob) error
+	// ListNeedingReview lists the jobs visible to ctx that need review, oldest
+	// first, without their transcripts
+	ListNeedingReview(ctx context.Context, offset, limit int) ([]models.TranscriptionJob, error)
+	// EnsureUnfinalized returns ErrJobFinalized if the job is finalized and ctx
+	// carries no admin override. Update, UpdateTranscript, UpdatePostProcessing
+	// and CreateExecution check it themselves.
+	EnsureUnfinalized(ctx context.Context, jobID string) error
 	CreateExecution(ctx context.Context, execution *models.TranscriptionJobExecution) error
 	UpdateExecution(ctx context.Context, execution *models.TranscriptionJobExecution) error
+	// FindExecution returns an execution of a job, or its latest non-replay
+	// execution when executionID is empty
+	FindExecution(ctx context.Context, jobID, executionID string) (*models.TranscriptionJobExecution, error)
 	DeleteExecutionsByJobID(ctx context.Context, jobID string) error
+
+	// What executions ran on, for finding the jobs a faulty version produced.
+	// Only each job's latest execution, not counting replays, is reported.
+	CreateExecutionComponents(ctx context.Context, components []models.ExecutionComponent) error
+	// ListComponentVersions returns the versions of components of a kind, of
+	// one component if name is set, with how many jobs ran on each
+	ListComponentVersions(ctx context.Context, kind, name string) ([]models.ComponentVersion, error)
+	// ListComponentJobs returns the jobs that ran on a component, on one of
+	// the given versions if any
+	ListComponentJobs(ctx context.Context, kind, name string, versions []string) ([]models.ComponentJob, error)
+
+	// Estimated costs at the configured per-minute adapter prices
+	AddJobCost(ctx context.Context, jobID string, cost float64) error
+	// SummarizeCosts totals the costs of the executions completed in [from, to)
+	// by day, profile, API key or adapter (see models.CostByDay)
+	SummarizeCosts(ctx context.Context, groupBy string, from, to time.Time) ([]models.CostSummary, error)
+	// SummarizeUsage totals the executions started in [from, to) by day, week
+	// or month, and by adapter, profile or user when groupBy is set
+	SummarizeUsage(ctx context.Context, interval, groupBy string, from, to time.Time) ([]models.UsageSummary, error)
+
+	// Shadow runs of candidate adapters
+	CreateShadowRun(ctx context.Context, run *models.ShadowRun) error
+	UpdateShadowRun(ctx context.Context, run *models.ShadowRun) error
+	FindShadowRun(ctx context.Context, id string) (*models.ShadowRun, error)
+	// ListShadowRuns returns the latest shadow runs, of one candidate if modelID
+	// is set, without their transcripts
+	ListShadowRuns(ctx context.Context, modelID string, limit int) ([]models.ShadowRun, error)
+	DeleteShadowRunsByJobID(ctx context.Context, jobID string) error
 	DeleteMultiTrackFilesByJobID(ctx context.Context, jobID string) error
+	DeleteTagAssignmentsByJobID(ctx context.Context, jobID string) error
+
+	// Monthly transcription quotas, in seconds of audio per workspace
+	AddQuotaUsage(ctx context.Context, workspace, month string, seconds float64) error
+	FindQuotaUsage(ctx context.Context, workspace, month string) (float64, error)
 }
 
 type jobRepository struct {
@@ -67,59 +173,310 @@ func (r *jobRepository) FindWithAssociations(ctx context.Context, id string) (*m
 	return &job, nil
 }
 
-func (r *jobRepository) ListWithParams(ctx context.Context, offset, limit int, sortBy, sortOrder, searchQuery string) ([]models.TranscriptionJob, int64, error) {
+func (r *jobRepository) FindByTitle(ctx context.Context, title string) (*models.TranscriptionJob, error) {
+	var job models.TranscriptionJob
+	err := scopeCollections(ctx, scopeJobs(ctx, r.db.WithContext(ctx), "workspace"), "collection_id").
+		Where("title = ?", title).
+		Order("created_at desc").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) ListByStatusAndTitle(ctx context.Context, statuses []models.JobStatus, titleContains string, offset, limit int) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	db := scopeJobs(ctx, r.db.WithContext(ctx).Model(&models.TranscriptionJob{}), "workspace")
+	db = scopeCollections(ctx, db, "collection_id").Omit("transcript", "individual_transcripts")
+	if len(statuses) > 0 {
+		db = db.Where("status IN ?", statuses)
+	}
+	if titleContains != "" {
+		db = db.Where("title LIKE ?", "%"+titleContains+"%")
+	}
+	err := db.Order("created_at desc").Offset(offset).Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// ListByUser lists the jobs of a user's own workspace, newest first. A limit
+// of 0 or less lists them all.
+func (r *jobRepository) ListByUser(ctx context.Context, userID uint, offset, limit int) ([]models.TranscriptionJob, int64, error) {
 	var jobs []models.TranscriptionJob
 	var count int64
+	db := r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).Where("workspace = ?", models.UserWorkspace(userID))
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	db = db.Order("created_at desc").Offset(offset)
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, count, nil
+}
 
-	db := r.db.WithContext(ctx).Model(&models.TranscriptionJob{})
+// Create saves a new job and assigns it the tags in its Tags
+func (r *jobRepository) Create(ctx context.Context, job *models.TranscriptionJob) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return assignStoredTags(tx, job)
+	})
+}
 
-	// Apply search filter
-	if searchQuery != "" {
-		search := "%" + searchQuery + "%"
-		db = db.Where("title LIKE ? OR audio_path LIKE ?", search, search)
+// Update saves the job, unless the stored job is finalized
+func (r *jobRepository) Update(ctx context.Context, job *models.TranscriptionJob) error {
+	if err := r.EnsureUnfinalized(ctx, job.ID); err != nil {
+		return err
 	}
+	return r.BaseRepository.Update(ctx, job)
+}
 
-	// Count total matching records
-	if err := db.Count(&count).Error; err != nil {
-		return nil, 0, err
+func (r *jobRepository) EnsureUnfinalized(ctx context.Context, jobID string) error {
+	return ensureUnfinalized(ctx, r.db, jobID)
+}
+
+func (r *jobRepository) UpdateTranscript(ctx context.Context, jobID string, transcript string) error {
+	if err := r.EnsureUnfinalized(ctx, jobID); err != nil {
+		return err
 	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous *string
+		if err := tx.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Select("transcript").Scan(&previous).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Update("transcript", transcript).Error; err != nil {
+			return err
+		}
+		if previous != nil && *previous == transcript {
+			return nil
+		}
 
-	// Apply sorting
-	if sortBy != "" {
-		if sortOrder == "" {
-			sortOrder = "desc"
+		var latest int
+		if err := tx.Model(&models.TranscriptRevision{}).Where("transcription_job_id = ?", jobID).
+			Select("COALESCE(MAX(revision), 0)").Scan(&latest).Error; err != nil {
+			return err
 		}
-		db = db.Order(sortBy + " " + sortOrder)
-	} else {
-		// Default sort
-		db = db.Order("created_at desc")
+		// Transcripts saved before revisions were kept, or by multi-track
+		// processing, become the first revision once replaced
+		if latest == 0 && previous != nil && *previous != "" {
+			latest++
+			if err := tx.Create(newTranscriptRevision(jobID, latest, *previous)).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(newTranscriptRevision(jobID, latest+1, transcript)).Error
+	})
+}
+
+// newTranscriptRevision keeps a transcript as a revision of a job
+func newTranscriptRevision(jobID string, revision int, transcript string) *models.TranscriptRevision {
+	var produced struct {
+		ModelUsed string `json:"model_used"`
 	}
+	_ = json.Unmarshal([]byte(transcript), &produced)
+	return &models.TranscriptRevision{
+		TranscriptionJobID: jobID,
+		Revision:           revision,
+		ModelUsed:          produced.ModelUsed,
+		Transcript:         transcript,
+	}
+}
 
-	// Apply pagination
-	err := db.Offset(offset).Limit(limit).Find(&jobs).Error
-	if err != nil {
-		return nil, 0, err
+func (r *jobRepository) ListTranscriptRevisions(ctx context.Context, jobID string) ([]models.TranscriptRevision, error) {
+	revisions := []models.TranscriptRevision{}
+	err := r.db.WithContext(ctx).Omit("transcript").Where("transcription_job_id = ?", jobID).
+		Order("revision ASC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *jobRepository) FindTranscriptRevision(ctx context.Context, jobID string, revision int) (*models.TranscriptRevision, error) {
+	var found models.TranscriptRevision
+	if err := r.db.WithContext(ctx).Where("transcription_job_id = ? AND revision = ?", jobID, revision).First(&found).Error; err != nil {
+		return nil, err
 	}
+	return &found, nil
+}
 
-	return jobs, count, nil
+func (r *jobRepository) DeleteTranscriptRevisionsByJobID(ctx context.Context, jobID string) error {
+	return r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.TranscriptRevision{}).Error
 }
 
-func (r *jobRepository) ListByUser(ctx context.Context, userID uint, offset, limit int) ([]models.TranscriptionJob, int64, error) {
-	// Note: Currently TranscriptionJob doesn't have a UserID field in the provided model.
-	// Assuming we might need to add it or this is a placeholder for future multi-user support.
-	// For now, we'll just return all jobs as the current app seems single-user focused or
-	// missing the link.
-	// TODO: Add UserID to TranscriptionJob model if multi-user isolation is required.
-	return r.List(ctx, offset, limit)
+func (r *jobRepository) UpdateSummary(ctx context.Context, jobID string, summary string) error {
+	if err := r.EnsureUnfinalized(ctx, jobID); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("summary", summary).Error
 }
 
-func (r *jobRepository) UpdateTranscript(ctx context.Context, jobID string, transcript string) error {
+func (r *jobRepository) UpdateIngestProgress(ctx context.Context, jobID string, progress float64) error {
 	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
 		Where("id = ?", jobID).
-		Update("transcript", transcript).Error
+		Update("ingest_progress", progress).Error
+}
+
+func (r *jobRepository) UpdateProgress(ctx context.Context, jobID string, progress float64) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("progress", progress).Error
+}
+
+func (r *jobRepository) EnterStage(ctx context.Context, jobID, stage string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.JobStage{}).
+			Where("transcription_job_id = ? AND ended_at IS NULL", jobID).
+			Update("ended_at", now).Error; err != nil {
+			return err
+		}
+		if stage != "" {
+			if err := tx.Create(&models.JobStage{TranscriptionJobID: jobID, Stage: stage, StartedAt: now}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Update("stage", stage).Error
+	})
+}
+
+func (r *jobRepository) UpdateScanResult(ctx context.Context, jobID string, scanStatus string, signature *string) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Updates(map[string]interface{}{"scan_status": scanStatus, "scan_signature": signature}).Error
+}
+
+func (r *jobRepository) UpdateAlignmentFallback(ctx context.Context, jobID string, reason *string) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("alignment_fallback", reason).Error
+}
+
+func (r *jobRepository) UpdateAudioSHA256(ctx context.Context, jobID string, sum string) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("audio_sha256", sum).Error
+}
+
+func (r *jobRepository) UpdateAudioSeconds(ctx context.Context, jobID string, seconds float64) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("audio_seconds", seconds).Error
+}
+
+func (r *jobRepository) FindDuplicate(ctx context.Context, job *models.TranscriptionJob) (*models.TranscriptionJob, error) {
+	db := r.db.WithContext(ctx).
+		Where("id <> ? AND status = ? AND audio_sha256 = ? AND workspace = ?", job.ID, models.StatusCompleted, job.AudioSHA256, job.Workspace).
+		Where("is_multi_track = ? AND transcript IS NOT NULL AND transcript <> ''", false)
+	if job.ProfileID != nil {
+		db = db.Where("profile_id = ?", *job.ProfileID)
+	} else {
+		db = db.Where("profile_id IS NULL")
+	}
+	if job.ProfileVersion != nil {
+		db = db.Where("profile_version = ?", *job.ProfileVersion)
+	} else {
+		db = db.Where("profile_version IS NULL")
+	}
+	if job.ParameterOverrides != nil {
+		db = db.Where("parameter_overrides = ?", *job.ParameterOverrides)
+	} else {
+		db = db.Where("parameter_overrides IS NULL")
+	}
+	var duplicate models.TranscriptionJob
+	if err := db.Order("created_at desc").First(&duplicate).Error; err != nil {
+		return nil, err
+	}
+	return &duplicate, nil
+}
+
+func (r *jobRepository) UpdateDuplicateOf(ctx context.Context, jobID string, originalID string) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("duplicate_of", originalID).Error
+}
+
+func (r *jobRepository) ListByStatus(ctx context.Context, status models.JobStatus) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// ListForIntegrityCheck returns the jobs whose audio should be on disk or in S3,
+// with their tracks. Transcripts are not loaded: a job with one gets "{}".
+func (r *jobRepository) ListForIntegrityCheck(ctx context.Context) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := r.db.WithContext(ctx).
+		Select("id", "title", "status", "audio_path", "audio_uri", "output_bucket_name", "is_multi_track", "workspace", "audio_expired_at", "created_at",
+			"CASE WHEN transcript IS NULL OR transcript = '' THEN NULL ELSE '{}' END AS transcript").
+		Preload("MultiTrackFiles").
+		Where("status IN ?", []models.JobStatus{models.StatusUploaded, models.StatusPending, models.StatusProcessing, models.StatusCompleted, models.StatusQuarantined}).
+		Order("created_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// ListPendingPostProcessing returns completed jobs that have post-transcription
+// automation configured and have not been post-processed yet
+func (r *jobRepository) ListPendingPostProcessing(ctx context.Context, limit int) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND post_processed_at IS NULL AND finalized_at IS NULL", models.StatusCompleted).
+		Where("auto_summary_template_id IS NOT NULL OR auto_action_items = ? OR auto_tag = ? OR auto_sentiment = ? OR auto_chapters = ?", true, true, true, true).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// UpdatePostProcessing stores the results of post-transcription automation
+func (r *jobRepository) UpdatePostProcessing(ctx context.Context, job *models.TranscriptionJob) error {
+	if err := r.EnsureUnfinalized(ctx, job.ID); err != nil {
+		return err
+	}
+	// Suggested topics are added to the job's tags
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(job).
+			Select("summary", "action_items", "segment_sentiment", "chapters", "translation", "tags", "post_processed_at", "post_process_error", "timing_post_processing_ms").
+			Updates(job).Error
+		if err != nil {
+			return err
+		}
+		return assignStoredTags(tx, job)
+	})
+}
+
+func (r *jobRepository) UpdateReview(ctx context.Context, job *models.TranscriptionJob) error {
+	if err := r.EnsureUnfinalized(ctx, job.ID); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(job).
+		Select("low_confidence_segments", "review_status", "reviewed_at", "reviewed_by").
+		Updates(job).Error
+}
+
+func (r *jobRepository) ListNeedingReview(ctx context.Context, offset, limit int) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	db := scopeJobs(ctx, r.db.WithContext(ctx).Model(&models.TranscriptionJob{}), "workspace")
+	err := scopeCollections(ctx, db, "collection_id").
+		Omit("transcript", "individual_transcripts").
+		Where("review_status = ?", models.ReviewNeeded).
+		Order("created_at asc").Offset(offset).Limit(limit).Find(&jobs).Error
+	return jobs, err
 }
 
+// CreateExecution records a (re)processing run, which finalized jobs do not get
 func (r *jobRepository) CreateExecution(ctx context.Context, execution *models.TranscriptionJobExecution) error {
+	if err := r.EnsureUnfinalized(ctx, execution.TranscriptionJobID); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Create(execution).Error
 }
 
@@ -127,14 +484,251 @@ func (r *jobRepository) UpdateExecution(ctx context.Context, execution *models.T
 	return r.db.WithContext(ctx).Save(execution).Error
 }
 
+func (r *jobRepository) FindExecution(ctx context.Context, jobID, executionID string) (*models.TranscriptionJobExecution, error) {
+	query := r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID)
+	if executionID != "" {
+		query = query.Where("id = ?", executionID)
+	} else {
+		query = query.Where("replay_of IS NULL").Order("started_at DESC")
+	}
+	var execution models.TranscriptionJobExecution
+	if err := query.First(&execution).Error; err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
 func (r *jobRepository) DeleteExecutionsByJobID(ctx context.Context, jobID string) error {
+	if err := r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.JobStage{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.ExecutionComponent{}).Error; err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.TranscriptionJobExecution{}).Error
 }
 
+func (r *jobRepository) CreateExecutionComponents(ctx context.Context, components []models.ExecutionComponent) error {
+	if len(components) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&components).Error
+}
+
+// latestExecutions selects the ID of each job's latest execution that is not a replay
+const latestExecutions = `SELECT e.id FROM transcription_job_executions e
+	WHERE e.replay_of IS NULL AND e.started_at = (
+		SELECT MAX(l.started_at) FROM transcription_job_executions l
+		WHERE l.transcription_job_id = e.transcription_job_id AND l.replay_of IS NULL)`
+
+// componentQuery selects the components of the latest executions of the jobs ctx may see
+func (r *jobRepository) componentQuery(ctx context.Context, kind, name string) *gorm.DB {
+	query := r.db.WithContext(ctx).Table("execution_components AS c").
+		Joins("JOIN transcription_jobs j ON j.id = c.transcription_job_id").
+		Where("c.execution_id IN ("+latestExecutions+")").
+		Where("c.kind = ?", kind)
+	if name != "" {
+		query = query.Where("c.name = ?", name)
+	}
+	return scopeJobs(ctx, query, "j.workspace")
+}
+
+func (r *jobRepository) ListComponentVersions(ctx context.Context, kind, name string) ([]models.ComponentVersion, error) {
+	var versions []models.ComponentVersion
+	err := r.componentQuery(ctx, kind, name).
+		Select("c.kind AS kind, c.name AS name, c.version AS version, COUNT(DISTINCT c.transcription_job_id) AS jobs").
+		Group("c.kind, c.name, c.version").
+		Order("c.name ASC, c.version ASC").
+		Scan(&versions).Error
+	return versions, err
+}
+
+func (r *jobRepository) ListComponentJobs(ctx context.Context, kind, name string, versions []string) ([]models.ComponentJob, error) {
+	query := r.componentQuery(ctx, kind, name)
+	if len(versions) > 0 {
+		query = query.Where("c.version IN ?", versions)
+	}
+	var jobs []models.ComponentJob
+	err := query.
+		Select("DISTINCT j.id AS job_id, j.title AS title, j.status AS status, j.workspace AS workspace, j.finalized_at AS finalized_at, " +
+			"c.execution_id AS execution_id, c.version AS version, e.started_at AS started_at").
+		Joins("JOIN transcription_job_executions e ON e.id = c.execution_id").
+		Order("e.started_at DESC").
+		Scan(&jobs).Error
+	return jobs, err
+}
+
+func (r *jobRepository) AddJobCost(ctx context.Context, jobID string, cost float64) error {
+	return r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("estimated_cost", gorm.Expr("COALESCE(estimated_cost, 0) + ?", cost)).Error
+}
+
+// costGroups are the key and name expressions of each cost grouping
+var costGroups = map[string][2]string{
+	models.CostByDay:     {"date(e.completed_at)", "''"},
+	models.CostByProfile: {"COALESCE(j.profile_id, '')", "COALESCE(MAX(p.name), '')"},
+	models.CostByAPIKey:  {"COALESCE(CAST(j.api_key_id AS TEXT), '')", "COALESCE(MAX(k.name), '')"},
+	models.CostByAdapter: {"COALESCE(e.adapter, '')", "''"},
+}
+
+func (r *jobRepository) SummarizeCosts(ctx context.Context, groupBy string, from, to time.Time) ([]models.CostSummary, error) {
+	group, ok := costGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown cost grouping %q", groupBy)
+	}
+	query := r.db.WithContext(ctx).Table("transcription_job_executions AS e").
+		Joins("JOIN transcription_jobs j ON j.id = e.transcription_job_id").
+		Joins("LEFT JOIN transcription_profiles p ON p.id = j.profile_id").
+		Joins("LEFT JOIN api_keys k ON k.id = j.api_key_id").
+		Where("e.cost IS NOT NULL AND e.replay_of IS NULL").
+		Where("e.completed_at >= ? AND e.completed_at < ?", from, to)
+	var summaries []models.CostSummary
+	err := scopeJobs(ctx, query, "j.workspace").
+		Select(group[0] + " AS key, " + group[1] + " AS name, COUNT(DISTINCT j.id) AS jobs, " +
+			"SUM(e.audio_seconds - COALESCE(e.skipped_seconds, 0)) / 60.0 AS minutes, SUM(e.cost) AS cost").
+		Group(group[0]).
+		Order("key ASC").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+// usagePeriods are the expressions of the first day of each analytics interval
+var usagePeriods = map[string]string{
+	models.IntervalDay:   "date(e.started_at)",
+	models.IntervalWeek:  "date(e.started_at, '-6 days', 'weekday 1')",
+	models.IntervalMonth: "strftime('%Y-%m-01', e.started_at)",
+}
+
+// usageGroups are the key and name expressions of each analytics grouping
+var usageGroups = map[string][2]string{
+	"":                    {"''", "''"},
+	models.UsageByAdapter: {"COALESCE(e.adapter, '')", "''"},
+	models.UsageByProfile: {"COALESCE(j.profile_id, '')", "COALESCE(MAX(p.name), '')"},
+	models.UsageByUser:    {"COALESCE(CAST(j.user_id AS TEXT), '')", "COALESCE(MAX(u.username), '')"},
+}
+
+// usageLanguage is the language a job's transcript was detected in, or else
+// the one it was transcribed with
+const usageLanguage = "COALESCE(NULLIF(CASE WHEN json_valid(j.transcript) THEN json_extract(j.transcript, '$.language') END, ''), NULLIF(j.language, ''), 'unknown')"
+
+func (r *jobRepository) SummarizeUsage(ctx context.Context, interval, groupBy string, from, to time.Time) ([]models.UsageSummary, error) {
+	period, ok := usagePeriods[interval]
+	if !ok {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+	group, ok := usageGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown usage grouping %q", groupBy)
+	}
+	executions := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Table("transcription_job_executions AS e").
+			Joins("JOIN transcription_jobs j ON j.id = e.transcription_job_id").
+			Joins("LEFT JOIN transcription_profiles p ON p.id = j.profile_id").
+			Joins("LEFT JOIN users u ON u.id = j.user_id").
+			Where("e.replay_of IS NULL").
+			Where("e.started_at >= ? AND e.started_at < ?", from, to)
+		return scopeJobs(ctx, query, "j.workspace")
+	}
+
+	var summaries []models.UsageSummary
+	err := executions().
+		Select(period+" AS period, "+group[0]+" AS key, "+group[1]+" AS name, "+
+			"COUNT(DISTINCT j.id) AS jobs, COUNT(*) AS executions, "+
+			"SUM(CASE WHEN e.status = ? THEN 1 ELSE 0 END) AS failed, "+
+			"COALESCE(SUM(CASE WHEN e.status = ? THEN e.audio_seconds END), 0) / 60.0 AS minutes, "+
+			"COALESCE(AVG(CASE WHEN e.status = ? THEN e.processing_duration END), 0) / 1000.0 AS average_latency_seconds",
+			models.StatusFailed, models.StatusCompleted, models.StatusCompleted).
+		Group(period + ", " + group[0]).
+		Order("period ASC, key ASC").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var languages []struct {
+		Period   string
+		Key      string
+		Language string
+		Jobs     int64
+	}
+	err = executions().
+		Where("e.status = ?", models.StatusCompleted).
+		Select(period + " AS period, " + group[0] + " AS key, " + usageLanguage + " AS language, COUNT(DISTINCT j.id) AS jobs").
+		Group(period + ", " + group[0] + ", " + usageLanguage).
+		Scan(&languages).Error
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[[2]string]int, len(summaries))
+	for i := range summaries {
+		summary := &summaries[i]
+		summary.Languages = map[string]int64{}
+		if summary.Executions > 0 {
+			summary.FailureRate = float64(summary.Failed) / float64(summary.Executions)
+		}
+		index[[2]string{summary.Period, summary.Key}] = i
+	}
+	for _, language := range languages {
+		if i, ok := index[[2]string{language.Period, language.Key}]; ok {
+			summaries[i].Languages[language.Language] = language.Jobs
+		}
+	}
+	return summaries, nil
+}
+
+func (r *jobRepository) CreateShadowRun(ctx context.Context, run *models.ShadowRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *jobRepository) UpdateShadowRun(ctx context.Context, run *models.ShadowRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+func (r *jobRepository) FindShadowRun(ctx context.Context, id string) (*models.ShadowRun, error) {
+	var run models.ShadowRun
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *jobRepository) ListShadowRuns(ctx context.Context, modelID string, limit int) ([]models.ShadowRun, error) {
+	query := r.db.WithContext(ctx).Omit("transcript").Order("created_at DESC").Limit(limit)
+	if modelID != "" {
+		query = query.Where("model_id = ?", modelID)
+	}
+	var runs []models.ShadowRun
+	err := query.Find(&runs).Error
+	return runs, err
+}
+
+func (r *jobRepository) DeleteShadowRunsByJobID(ctx context.Context, jobID string) error {
+	return r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.ShadowRun{}).Error
+}
+
 func (r *jobRepository) DeleteMultiTrackFilesByJobID(ctx context.Context, jobID string) error {
 	return r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.MultiTrackFile{}).Error
 }
 
+func (r *jobRepository) DeleteTagAssignmentsByJobID(ctx context.Context, jobID string) error {
+	return r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Delete(&models.JobTag{}).Error
+}
+
+func (r *jobRepository) AddQuotaUsage(ctx context.Context, workspace, month string, seconds float64) error {
+	usage := models.QuotaUsage{Workspace: workspace, Month: month, AudioSeconds: seconds}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "workspace"}, {Name: "month"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"audio_seconds": gorm.Expr("audio_seconds + ?", seconds), "updated_at": time.Now()}),
+	}).Create(&usage).Error
+}
+
+func (r *jobRepository) FindQuotaUsage(ctx context.Context, workspace, month string) (float64, error) {
+	var usage models.QuotaUsage
+	err := r.db.WithContext(ctx).Where("workspace = ? AND month = ?", workspace, month).Limit(1).Find(&usage).Error
+	return usage.AudioSeconds, err
+}
+
 // APIKeyRepository handles API key operations
 type APIKeyRepository interface {
 	Repository[models.APIKey]
@@ -179,6 +773,9 @@ func (r *apiKeyRepository) Revoke(ctx context.Context, id uint) error {
 type ProfileRepository interface {
 	Repository[models.TranscriptionProfile]
 	FindDefault(ctx context.Context) (*models.TranscriptionProfile, error)
+	// ListVersions returns a profile's versions, oldest first
+	ListVersions(ctx context.Context, profileID string) ([]models.ProfileVersion, error)
+	FindVersion(ctx context.Context, profileID string, version int) (*models.ProfileVersion, error)
 }
 
 type profileRepository struct {
@@ -191,9 +788,140 @@ func NewProfileRepository(db *gorm.DB) ProfileRepository {
 	}
 }
 
+// Create assigns the profile to the organization ctx is scoped to, and keeps
+// it as its first version
+func (r *profileRepository) Create(ctx context.Context, profile *models.TranscriptionProfile) error {
+	if organizationID, ok := models.OrganizationFromContext(ctx); ok {
+		profile.OrganizationID = organizationID
+	}
+	profile.Version = 1
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(profile).Error; err != nil {
+			return err
+		}
+		return tx.Create(models.NewProfileVersion(profile)).Error
+	})
+}
+
+// Update keeps the profile as its next version when its name, description or
+// parameters change; marking it default does not make a version
+func (r *profileRepository) Update(ctx context.Context, profile *models.TranscriptionProfile) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous models.TranscriptionProfile
+		if err := tx.First(&previous, "id = ?", profile.ID).Error; err != nil {
+			return err
+		}
+		profile.Version = previous.Version
+		if profileContent(&previous) == profileContent(profile) {
+			return tx.Save(profile).Error
+		}
+
+		var versions int64
+		if err := tx.Model(&models.ProfileVersion{}).Where("profile_id = ?", profile.ID).Count(&versions).Error; err != nil {
+			return err
+		}
+		// Profiles created before versions were kept become their first
+		// version once changed
+		if versions == 0 {
+			if err := tx.Create(models.NewProfileVersion(&previous)).Error; err != nil {
+				return err
+			}
+		}
+		profile.Version++
+		if err := tx.Save(profile).Error; err != nil {
+			return err
+		}
+		return tx.Create(models.NewProfileVersion(profile)).Error
+	})
+}
+
+// profileContent is what of a profile a version keeps, for comparison
+func profileContent(profile *models.TranscriptionProfile) string {
+	content, _ := json.Marshal(models.NewProfileVersion(profile))
+	return string(content)
+}
+
+// Delete removes the profile with its versions
+func (r *profileRepository) Delete(ctx context.Context, id interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("profile_id = ?", id).Delete(&models.ProfileVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.TranscriptionProfile{}, "id = ?", id).Error
+	})
+}
+
+// ListVersions returns a profile created before versions were kept, and never
+// changed since, as its only version
+func (r *profileRepository) ListVersions(ctx context.Context, profileID string) ([]models.ProfileVersion, error) {
+	profile, err := r.FindByID(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	versions := []models.ProfileVersion{}
+	if err := r.db.WithContext(ctx).Where("profile_id = ?", profileID).Order("version ASC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		versions = append(versions, *legacyProfileVersion(profile))
+	}
+	return versions, nil
+}
+
+func (r *profileRepository) FindVersion(ctx context.Context, profileID string, version int) (*models.ProfileVersion, error) {
+	profile, err := r.FindByID(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	var found models.ProfileVersion
+	err = r.db.WithContext(ctx).Where("profile_id = ? AND version = ?", profileID, version).First(&found).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && version == profile.Version {
+		var versions int64
+		if err := r.db.WithContext(ctx).Model(&models.ProfileVersion{}).Where("profile_id = ?", profileID).Count(&versions).Error; err != nil {
+			return nil, err
+		}
+		if versions == 0 {
+			return legacyProfileVersion(profile), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+// legacyProfileVersion is the current version of a profile that has none kept
+func legacyProfileVersion(profile *models.TranscriptionProfile) *models.ProfileVersion {
+	version := models.NewProfileVersion(profile)
+	version.CreatedAt = profile.UpdatedAt
+	return version
+}
+
+func (r *profileRepository) FindByID(ctx context.Context, id interface{}) (*models.TranscriptionProfile, error) {
+	var profile models.TranscriptionProfile
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").First(&profile, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *profileRepository) List(ctx context.Context, offset, limit int) ([]models.TranscriptionProfile, int64, error) {
+	var profiles []models.TranscriptionProfile
+	var count int64
+
+	db := scopeOrganization(ctx, r.db.WithContext(ctx).Model(&models.TranscriptionProfile{}), "organization_id")
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Offset(offset).Limit(limit).Find(&profiles).Error
+	return profiles, count, err
+}
+
 func (r *profileRepository) FindDefault(ctx context.Context) (*models.TranscriptionProfile, error) {
 	var profile models.TranscriptionProfile
-	err := r.db.WithContext(ctx).Where("is_default = ?", true).First(&profile).Error
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("is_default = ?", true).First(&profile).Error
 	if err != nil {
 		return nil, err
 	}
@@ -216,9 +944,28 @@ func NewLLMConfigRepository(db *gorm.DB) LLMConfigRepository {
 	}
 }
 
+// Create assigns the config to the organization ctx is scoped to
+func (r *llmConfigRepository) Create(ctx context.Context, config *models.LLMConfig) error {
+	if organizationID, ok := models.OrganizationFromContext(ctx); ok {
+		config.OrganizationID = organizationID
+	}
+	return r.BaseRepository.Create(ctx, config)
+}
+
+// GetActive returns the active config of the organization ctx is scoped to,
+// falling back to the instance's own config for organizations without one
 func (r *llmConfigRepository) GetActive(ctx context.Context) (*models.LLMConfig, error) {
 	var config models.LLMConfig
-	err := r.db.WithContext(ctx).Where("is_active = ?", true).First(&config).Error
+	if organizationID, _ := models.OrganizationFromContext(ctx); organizationID != "" {
+		err := r.db.WithContext(ctx).Where("is_active = ? AND organization_id = ?", true, organizationID).First(&config).Error
+		if err == nil {
+			return &config, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	err := r.db.WithContext(ctx).Where("is_active = ? AND organization_id = ?", true, "").First(&config).Error
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +979,11 @@ type SummaryRepository interface {
 	SaveSettings(ctx context.Context, settings *models.SummarySetting) error
 	SaveSummary(ctx context.Context, summary *models.Summary) error
 	GetLatestSummary(ctx context.Context, transcriptionID string) (*models.Summary, error)
+	ListSummaries(ctx context.Context, transcriptionID string) ([]models.Summary, error)
 	DeleteByTranscriptionID(ctx context.Context, transcriptionID string) error
+	FindLLMOutput(ctx context.Context, hash string) (*models.LLMOutput, error)
+	SaveLLMOutput(ctx context.Context, output *models.LLMOutput) error
+	DeleteLLMOutputsUnusedSince(ctx context.Context, before time.Time) (int64, error)
 }
 
 type summaryRepository struct {
@@ -260,7 +1011,11 @@ func (r *summaryRepository) SaveSettings(ctx context.Context, settings *models.S
 	return r.db.WithContext(ctx).Save(settings).Error
 }
 
+// SaveSummary stores a summary of a job, unless the job is finalized
 func (r *summaryRepository) SaveSummary(ctx context.Context, summary *models.Summary) error {
+	if err := ensureUnfinalized(ctx, r.db, summary.TranscriptionID); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Create(summary).Error
 }
 
@@ -273,10 +1028,40 @@ func (r *summaryRepository) GetLatestSummary(ctx context.Context, transcriptionI
 	return &summary, nil
 }
 
+// ListSummaries returns every summary generated for a transcription, oldest first
+func (r *summaryRepository) ListSummaries(ctx context.Context, transcriptionID string) ([]models.Summary, error) {
+	var summaries []models.Summary
+	err := r.db.WithContext(ctx).Where("transcription_id = ?", transcriptionID).Order("created_at ASC").Find(&summaries).Error
+	return summaries, err
+}
+
 func (r *summaryRepository) DeleteByTranscriptionID(ctx context.Context, transcriptionID string) error {
 	return r.db.WithContext(ctx).Where("transcription_id = ?", transcriptionID).Delete(&models.Summary{}).Error
 }
 
+// FindLLMOutput returns a cached LLM reply, counting the hit
+func (r *summaryRepository) FindLLMOutput(ctx context.Context, hash string) (*models.LLMOutput, error) {
+	var output models.LLMOutput
+	if err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&output).Error; err != nil {
+		return nil, err
+	}
+	err := r.db.WithContext(ctx).Model(&output).UpdateColumns(map[string]interface{}{
+		"hits":    gorm.Expr("hits + 1"),
+		"used_at": time.Now(),
+	}).Error
+	return &output, err
+}
+
+func (r *summaryRepository) SaveLLMOutput(ctx context.Context, output *models.LLMOutput) error {
+	output.UsedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(output).Error
+}
+
+func (r *summaryRepository) DeleteLLMOutputsUnusedSince(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("used_at < ?", before).Delete(&models.LLMOutput{})
+	return result.RowsAffected, result.Error
+}
+
 // ChatRepository handles chat sessions and messages
 type ChatRepository interface {
 	Repository[models.ChatSession]
@@ -287,6 +1072,8 @@ type ChatRepository interface {
 	DeleteSession(ctx context.Context, id string) error
 	GetMessages(ctx context.Context, sessionID string, limit int) ([]models.ChatMessage, error)
 	DeleteByJobID(ctx context.Context, jobID string) error
+	GetPolicy(ctx context.Context, workspace string) (*models.ChatPolicy, error)
+	SavePolicy(ctx context.Context, policy *models.ChatPolicy) error
 }
 
 type chatRepository struct {
@@ -360,6 +1147,17 @@ func (r *chatRepository) DeleteByJobID(ctx context.Context, jobID string) error
 	return nil
 }
 
+// GetPolicy returns a workspace's chat policy, or an empty policy if none is set
+func (r *chatRepository) GetPolicy(ctx context.Context, workspace string) (*models.ChatPolicy, error) {
+	policy := models.ChatPolicy{Workspace: workspace}
+	err := r.db.WithContext(ctx).Where("workspace = ?", workspace).Limit(1).Find(&policy).Error
+	return &policy, err
+}
+
+func (r *chatRepository) SavePolicy(ctx context.Context, policy *models.ChatPolicy) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(policy).Error
+}
+
 func (r *chatRepository) GetMessages(ctx context.Context, sessionID string, limit int) ([]models.ChatMessage, error) {
 	var messages []models.ChatMessage
 	query := r.db.WithContext(ctx).Where("chat_session_id = ?", sessionID).Order("created_at ASC")
@@ -378,6 +1176,13 @@ type NoteRepository interface {
 	Repository[models.Note]
 	ListByJob(ctx context.Context, jobID string) ([]models.Note, error)
 	DeleteByTranscriptionID(ctx context.Context, transcriptionID string) error
+
+	// Annotations are time-coded comment threads on a transcript
+	ListAnnotations(ctx context.Context, jobID string) ([]models.Annotation, error)
+	FindAnnotation(ctx context.Context, jobID, id string) (*models.Annotation, error)
+	CreateAnnotation(ctx context.Context, annotation *models.Annotation) error
+	UpdateAnnotation(ctx context.Context, annotation *models.Annotation) error
+	DeleteAnnotation(ctx context.Context, jobID, id string) error
 }
 
 type noteRepository struct {
@@ -390,6 +1195,34 @@ func NewNoteRepository(db *gorm.DB) NoteRepository {
 	}
 }
 
+// Notes and annotations on a finalized job are part of the record, so writes
+// to them are refused like writes to the job
+
+func (r *noteRepository) Create(ctx context.Context, note *models.Note) error {
+	if err := ensureUnfinalized(ctx, r.db, note.TranscriptionID); err != nil {
+		return err
+	}
+	return r.BaseRepository.Create(ctx, note)
+}
+
+func (r *noteRepository) Update(ctx context.Context, note *models.Note) error {
+	if err := ensureUnfinalized(ctx, r.db, note.TranscriptionID); err != nil {
+		return err
+	}
+	return r.BaseRepository.Update(ctx, note)
+}
+
+func (r *noteRepository) Delete(ctx context.Context, id interface{}) error {
+	note, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := ensureUnfinalized(ctx, r.db, note.TranscriptionID); err != nil {
+		return err
+	}
+	return r.BaseRepository.Delete(ctx, id)
+}
+
 func (r *noteRepository) ListByJob(ctx context.Context, jobID string) ([]models.Note, error) {
 	var notes []models.Note
 	err := r.db.WithContext(ctx).Where("transcription_id = ?", jobID).Order("created_at DESC").Find(&notes).Error
@@ -400,7 +1233,63 @@ func (r *noteRepository) ListByJob(ctx context.Context, jobID string) ([]models.
 }
 
 func (r *noteRepository) DeleteByTranscriptionID(ctx context.Context, transcriptionID string) error {
-	return r.db.WithContext(ctx).Where("transcription_id = ?", transcriptionID).Delete(&models.Note{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_id = ?", transcriptionID).Delete(&models.Annotation{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("transcription_id = ?", transcriptionID).Delete(&models.Note{}).Error
+	})
+}
+
+// ListAnnotations returns a job's annotations in transcript order, replies after
+// the comments they answer
+func (r *noteRepository) ListAnnotations(ctx context.Context, jobID string) ([]models.Annotation, error) {
+	var annotations []models.Annotation
+	err := r.db.WithContext(ctx).Where("transcription_id = ?", jobID).
+		Order("start_time ASC").Order("created_at ASC").Find(&annotations).Error
+	if err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+func (r *noteRepository) FindAnnotation(ctx context.Context, jobID, id string) (*models.Annotation, error) {
+	var annotation models.Annotation
+	if err := r.db.WithContext(ctx).Where("id = ? AND transcription_id = ?", id, jobID).First(&annotation).Error; err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+func (r *noteRepository) CreateAnnotation(ctx context.Context, annotation *models.Annotation) error {
+	if err := ensureUnfinalized(ctx, r.db, annotation.TranscriptionID); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(annotation).Error
+}
+
+func (r *noteRepository) UpdateAnnotation(ctx context.Context, annotation *models.Annotation) error {
+	if err := ensureUnfinalized(ctx, r.db, annotation.TranscriptionID); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Save(annotation).Error
+}
+
+// DeleteAnnotation deletes an annotation with its replies
+func (r *noteRepository) DeleteAnnotation(ctx context.Context, jobID, id string) error {
+	if err := ensureUnfinalized(ctx, r.db, jobID); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND transcription_id = ?", id, jobID).Delete(&models.Annotation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("parent_id = ?", id).Delete(&models.Annotation{}).Error
+	})
 }
 
 // SpeakerMappingRepository handles speaker mappings
@@ -409,6 +1298,27 @@ type SpeakerMappingRepository interface {
 	ListByJob(ctx context.Context, jobID string) ([]models.SpeakerMapping, error)
 	UpdateMappings(ctx context.Context, jobID string, mappings []models.SpeakerMapping) error
 	DeleteByJobID(ctx context.Context, jobID string) error
+
+	// Voiceprints are scoped to a workspace
+	ListVoiceprints(ctx context.Context, workspace string) ([]models.Voiceprint, error)
+	FindVoiceprintByName(ctx context.Context, workspace, name string) (*models.Voiceprint, error)
+	SaveVoiceprint(ctx context.Context, voiceprint *models.Voiceprint) error
+	DeleteVoiceprint(ctx context.Context, workspace, id string) error
+	HasVoiceprints(ctx context.Context, workspace string) (bool, error)
+
+	ReplaceSpeakerEmbeddings(ctx context.Context, jobID string, embeddings []models.JobSpeakerEmbedding) error
+	ListSpeakerEmbeddings(ctx context.Context, jobID string) ([]models.JobSpeakerEmbedding, error)
+	// ApplyVoiceprintMatches records the job's voiceprint matches and names the
+	// matched speakers. Names set by hand are kept; names from earlier matches
+	// are replaced.
+	ApplyVoiceprintMatches(ctx context.Context, jobID string, matches []VoiceprintMatch) error
+}
+
+// VoiceprintMatch pairs a job's diarized speaker with the voiceprint it matched
+type VoiceprintMatch struct {
+	Speaker    string
+	Voiceprint models.Voiceprint
+	Similarity float64
 }
 
 type speakerMappingRepository struct {
@@ -435,6 +1345,9 @@ func (r *speakerMappingRepository) DeleteByJobID(ctx context.Context, jobID stri
 }
 
 func (r *speakerMappingRepository) UpdateMappings(ctx context.Context, jobID string, mappings []models.SpeakerMapping) error {
+	if err := ensureUnfinalized(ctx, r.db, jobID); err != nil {
+		return err
+	}
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Delete existing mappings for this job
 		if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.SpeakerMapping{}).Error; err != nil {
@@ -450,3 +1363,351 @@ func (r *speakerMappingRepository) UpdateMappings(ctx context.Context, jobID str
 		return nil
 	})
 }
+
+func (r *speakerMappingRepository) ListVoiceprints(ctx context.Context, workspace string) ([]models.Voiceprint, error) {
+	var voiceprints []models.Voiceprint
+	if err := r.db.WithContext(ctx).Where("workspace = ?", workspace).Order("name ASC").Find(&voiceprints).Error; err != nil {
+		return nil, err
+	}
+	for i := range voiceprints {
+		voiceprints[i].Vector = decodeVector(voiceprints[i].Embedding)
+	}
+	return voiceprints, nil
+}
+
+func (r *speakerMappingRepository) FindVoiceprintByName(ctx context.Context, workspace, name string) (*models.Voiceprint, error) {
+	var voiceprint models.Voiceprint
+	if err := r.db.WithContext(ctx).Where("workspace = ? AND name = ?", workspace, name).First(&voiceprint).Error; err != nil {
+		return nil, err
+	}
+	voiceprint.Vector = decodeVector(voiceprint.Embedding)
+	return &voiceprint, nil
+}
+
+func (r *speakerMappingRepository) SaveVoiceprint(ctx context.Context, voiceprint *models.Voiceprint) error {
+	voiceprint.Embedding = encodeVector(voiceprint.Vector)
+	return r.db.WithContext(ctx).Save(voiceprint).Error
+}
+
+func (r *speakerMappingRepository) DeleteVoiceprint(ctx context.Context, workspace, id string) error {
+	result := r.db.WithContext(ctx).Where("workspace = ? AND id = ?", workspace, id).Delete(&models.Voiceprint{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *speakerMappingRepository) HasVoiceprints(ctx context.Context, workspace string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Voiceprint{}).Where("workspace = ?", workspace).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *speakerMappingRepository) ReplaceSpeakerEmbeddings(ctx context.Context, jobID string, embeddings []models.JobSpeakerEmbedding) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.JobSpeakerEmbedding{}).Error; err != nil {
+			return err
+		}
+		for i := range embeddings {
+			embeddings[i].TranscriptionJobID = jobID
+			embeddings[i].Embedding = encodeVector(embeddings[i].Vector)
+		}
+		if len(embeddings) > 0 {
+			return tx.Omit("TranscriptionJob").Create(&embeddings).Error
+		}
+		return nil
+	})
+}
+
+func (r *speakerMappingRepository) ListSpeakerEmbeddings(ctx context.Context, jobID string) ([]models.JobSpeakerEmbedding, error) {
+	var embeddings []models.JobSpeakerEmbedding
+	if err := r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Order("speaker ASC").Find(&embeddings).Error; err != nil {
+		return nil, err
+	}
+	for i := range embeddings {
+		embeddings[i].Vector = decodeVector(embeddings[i].Embedding)
+	}
+	return embeddings, nil
+}
+
+func (r *speakerMappingRepository) ApplyVoiceprintMatches(ctx context.Context, jobID string, matches []VoiceprintMatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.JobSpeakerEmbedding{}).Where("transcription_job_id = ?", jobID).
+			Updates(map[string]interface{}{"voiceprint_id": nil, "similarity": nil}).Error; err != nil {
+			return err
+		}
+		// Earlier automatic names are recomputed; names chosen by hand stay
+		if err := tx.Where("transcription_job_id = ? AND voiceprint_id IS NOT NULL", jobID).Delete(&models.SpeakerMapping{}).Error; err != nil {
+			return err
+		}
+		var manual []string
+		if err := tx.Model(&models.SpeakerMapping{}).Where("transcription_job_id = ?", jobID).Pluck("original_speaker", &manual).Error; err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			voiceprintID := match.Voiceprint.ID
+			similarity := match.Similarity
+			if err := tx.Model(&models.JobSpeakerEmbedding{}).
+				Where("transcription_job_id = ? AND speaker = ?", jobID, match.Speaker).
+				Updates(map[string]interface{}{"voiceprint_id": voiceprintID, "similarity": similarity}).Error; err != nil {
+				return err
+			}
+			if slices.Contains(manual, match.Speaker) {
+				continue
+			}
+			speaker, err := findOrCreateSpeaker(tx, match.Voiceprint.Workspace, match.Voiceprint.Name)
+			if err != nil {
+				return err
+			}
+			mapping := models.SpeakerMapping{
+				TranscriptionJobID: jobID,
+				OriginalSpeaker:    match.Speaker,
+				CustomName:         match.Voiceprint.Name,
+				VoiceprintID:       &voiceprintID,
+				SpeakerID:          &speaker.ID,
+			}
+			if err := tx.Omit("TranscriptionJob").Create(&mapping).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SpeakerRepository handles the speaker directory shared across a workspace's jobs
+type SpeakerRepository interface {
+	Repository[models.Speaker]
+	ListByWorkspace(ctx context.Context, workspace string) ([]SpeakerWithJobs, error)
+	FindInWorkspace(ctx context.Context, workspace, id string) (*models.Speaker, error)
+	FindByName(ctx context.Context, workspace, name string) (*models.Speaker, error)
+	FindOrCreateByName(ctx context.Context, workspace, name string) (*models.Speaker, error)
+	// ListAppearances returns the jobs the speaker is mapped in, newest first
+	ListAppearances(ctx context.Context, speakerID string) ([]SpeakerAppearance, error)
+	// Rename renames the speaker, the mappings linked to it and its voiceprint
+	Rename(ctx context.Context, speaker *models.Speaker, name string) error
+	// DeleteSpeaker removes the speaker; mappings linked to it keep their names
+	DeleteSpeaker(ctx context.Context, workspace, id string) error
+}
+
+// SpeakerWithJobs is a speaker with the number of jobs it is mapped in
+type SpeakerWithJobs struct {
+	models.Speaker
+	JobCount int64 `json:"job_count"`
+}
+
+// SpeakerAppearance is a job a speaker is mapped in
+type SpeakerAppearance struct {
+	JobID           string    `json:"job_id"`
+	Title           *string   `json:"title,omitempty"`
+	OriginalSpeaker string    `json:"original_speaker"`
+	JobCreatedAt    time.Time `json:"job_created_at"`
+}
+
+type speakerRepository struct {
+	*BaseRepository[models.Speaker]
+}
+
+func NewSpeakerRepository(db *gorm.DB) SpeakerRepository {
+	return &speakerRepository{
+		BaseRepository: NewBaseRepository[models.Speaker](db),
+	}
+}
+
+func (r *speakerRepository) ListByWorkspace(ctx context.Context, workspace string) ([]SpeakerWithJobs, error) {
+	var speakers []SpeakerWithJobs
+	err := r.db.WithContext(ctx).
+		Table("speakers").
+		Select("speakers.*, COUNT(DISTINCT sm.transcription_job_id) AS job_count").
+		Joins("LEFT JOIN speaker_mappings sm ON sm.speaker_id = speakers.id").
+		Where("speakers.workspace = ?", workspace).
+		Group("speakers.id").
+		Order("speakers.name ASC").
+		Scan(&speakers).Error
+	return speakers, err
+}
+
+func (r *speakerRepository) FindInWorkspace(ctx context.Context, workspace, id string) (*models.Speaker, error) {
+	var speaker models.Speaker
+	if err := r.db.WithContext(ctx).Where("workspace = ? AND id = ?", workspace, id).First(&speaker).Error; err != nil {
+		return nil, err
+	}
+	return &speaker, nil
+}
+
+func (r *speakerRepository) FindByName(ctx context.Context, workspace, name string) (*models.Speaker, error) {
+	var speaker models.Speaker
+	if err := r.db.WithContext(ctx).Where("workspace = ? AND name = ?", workspace, name).First(&speaker).Error; err != nil {
+		return nil, err
+	}
+	return &speaker, nil
+}
+
+func (r *speakerRepository) FindOrCreateByName(ctx context.Context, workspace, name string) (*models.Speaker, error) {
+	return findOrCreateSpeaker(r.db.WithContext(ctx), workspace, name)
+}
+
+// findOrCreateSpeaker returns the workspace's speaker with the given name,
+// adding it to the directory if needed
+func findOrCreateSpeaker(db *gorm.DB, workspace, name string) (*models.Speaker, error) {
+	speaker := models.Speaker{Workspace: workspace, Name: name}
+	if err := db.Where("workspace = ? AND name = ?", workspace, name).FirstOrCreate(&speaker).Error; err != nil {
+		return nil, err
+	}
+	return &speaker, nil
+}
+
+func (r *speakerRepository) ListAppearances(ctx context.Context, speakerID string) ([]SpeakerAppearance, error) {
+	var appearances []SpeakerAppearance
+	err := r.db.WithContext(ctx).
+		Table("speaker_mappings sm").
+		Select("sm.transcription_job_id AS job_id, j.title AS title, sm.original_speaker AS original_speaker, j.created_at AS job_created_at").
+		Joins("JOIN transcription_jobs j ON j.id = sm.transcription_job_id").
+		Where("sm.speaker_id = ?", speakerID).
+		Order("j.created_at DESC").
+		Scan(&appearances).Error
+	return appearances, err
+}
+
+func (r *speakerRepository) Rename(ctx context.Context, speaker *models.Speaker, name string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SpeakerMapping{}).Where("speaker_id = ?", speaker.ID).Update("custom_name", name).Error; err != nil {
+			return err
+		}
+		// Keep the voiceprint under the same name so later matches link here
+		var taken int64
+		if err := tx.Model(&models.Voiceprint{}).Where("workspace = ? AND name = ?", speaker.Workspace, name).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken == 0 {
+			if err := tx.Model(&models.Voiceprint{}).Where("workspace = ? AND name = ?", speaker.Workspace, speaker.Name).Update("name", name).Error; err != nil {
+				return err
+			}
+		}
+		speaker.Name = name
+		return tx.Save(speaker).Error
+	})
+}
+
+func (r *speakerRepository) DeleteSpeaker(ctx context.Context, workspace, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("workspace = ? AND id = ?", workspace, id).Delete(&models.Speaker{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.SpeakerMapping{}).Where("speaker_id = ?", id).Update("speaker_id", nil).Error
+	})
+}
+
+// FeedRepository handles RSS/podcast feed subscriptions and their seen episodes
+type FeedRepository interface {
+	Repository[models.FeedSubscription]
+	ListActive(ctx context.Context) ([]models.FeedSubscription, error)
+	// ListVisible lists the subscriptions of the workspaces visible to ctx
+	ListVisible(ctx context.Context) ([]models.FeedSubscription, error)
+	ListEpisodes(ctx context.Context, subscriptionID string) ([]models.FeedEpisode, error)
+	HasEpisode(ctx context.Context, subscriptionID, guid string) (bool, error)
+	CreateEpisode(ctx context.Context, episode *models.FeedEpisode) error
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+type feedRepository struct {
+	*BaseRepository[models.FeedSubscription]
+}
+
+func NewFeedRepository(db *gorm.DB) FeedRepository {
+	return &feedRepository{
+		BaseRepository: NewBaseRepository[models.FeedSubscription](db),
+	}
+}
+
+func (r *feedRepository) ListActive(ctx context.Context) ([]models.FeedSubscription, error) {
+	var subscriptions []models.FeedSubscription
+	err := r.db.WithContext(ctx).Where("active = ?", true).Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *feedRepository) ListVisible(ctx context.Context) ([]models.FeedSubscription, error) {
+	var subscriptions []models.FeedSubscription
+	err := scopeJobs(ctx, r.db.WithContext(ctx), "workspace").Order("created_at ASC").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *feedRepository) ListEpisodes(ctx context.Context, subscriptionID string) ([]models.FeedEpisode, error) {
+	var episodes []models.FeedEpisode
+	err := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID).
+		Order("published_at DESC").Order("id DESC").Find(&episodes).Error
+	if err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+func (r *feedRepository) HasEpisode(ctx context.Context, subscriptionID, guid string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.FeedEpisode{}).
+		Where("subscription_id = ? AND guid = ?", subscriptionID, guid).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *feedRepository) CreateEpisode(ctx context.Context, episode *models.FeedEpisode) error {
+	return r.db.WithContext(ctx).Create(episode).Error
+}
+
+func (r *feedRepository) DeleteSubscription(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Episode records only; transcription jobs created from the feed are kept
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.FeedEpisode{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.FeedSubscription{}, "id = ?", id).Error
+	})
+}
+
+// RecordingRepository handles in-progress browser recording sessions
+type RecordingRepository interface {
+	Repository[models.RecordingSession]
+	// AppendChunk records a stored chunk, provided index is the next expected one
+	AppendChunk(ctx context.Context, id string, index int, size int64) (bool, error)
+	// FindStale lists the unfinalized sessions last changed before the given time
+	FindStale(ctx context.Context, before time.Time) ([]models.RecordingSession, error)
+}
+
+type recordingRepository struct {
+	*BaseRepository[models.RecordingSession]
+}
+
+func NewRecordingRepository(db *gorm.DB) RecordingRepository {
+	return &recordingRepository{
+		BaseRepository: NewBaseRepository[models.RecordingSession](db),
+	}
+}
+
+func (r *recordingRepository) AppendChunk(ctx context.Context, id string, index int, size int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RecordingSession{}).
+		Where("id = ? AND chunk_count = ? AND job_id IS NULL", id, index).
+		Updates(map[string]interface{}{
+			"chunk_count": gorm.Expr("chunk_count + 1"),
+			"bytes":       gorm.Expr("bytes + ?", size),
+			"updated_at":  time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *recordingRepository) FindStale(ctx context.Context, before time.Time) ([]models.RecordingSession, error) {
+	var sessions []models.RecordingSession
+	err := r.db.WithContext(ctx).Where("job_id IS NULL AND updated_at < ?", before).Find(&sessions).Error
+	return sessions, err
+}
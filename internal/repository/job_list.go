@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+// ErrInvalidCursor is returned by ListJobs for a cursor it did not issue, or
+// issued for another sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// JobListQuery filters, sorts and pages the jobs list. Zero fields do not
+// filter.
+type JobListQuery struct {
+	Statuses      []models.JobStatus
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	ProfileID     string
+	ModelFamily   string // the adapter family the job was queued with
+	Tag           string // a tag's key, or key=value
+	TagID         string
+	Language      string
+	MinDuration   *float64 // seconds of audio, only known once jobs complete
+	MaxDuration   *float64
+	Search        string // in titles, audio filenames and tags
+	// CollectionIDs only lists the jobs filed in these collections, and
+	// NoCollection those filed in none
+	CollectionIDs []string
+	NoCollection  bool
+
+	SortBy    string // a key of JobSortFields; created_at by default
+	SortOrder string // asc, or desc by default
+
+	// Cursor continues the listing after the previous page, from its
+	// JobListPage.NextCursor, instead of skipping Offset jobs
+	Cursor string
+	Offset int
+	Limit  int
+}
+
+// JobListPage is a page of the jobs list
+type JobListPage struct {
+	Jobs  []models.TranscriptionJob
+	Total int64 // jobs matching the filters, on every page
+	// NextCursor continues the listing after Jobs; empty on the last page
+	NextCursor string
+}
+
+// JobSortFields are what the jobs list sorts by, with the expression each
+// sorts on. Missing values sort as empty, or as -1 for durations, so the
+// cursor can compare them.
+var JobSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "COALESCE(title, '')",
+	"status":     "status",
+	"profile":    "COALESCE(profile_id, '')",
+	"adapter":    "model_family",
+	"language":   "COALESCE(language, '')",
+	"duration":   "COALESCE(audio_seconds, -1)",
+}
+
+// jobCursor is the sort value and ID of the last job of a page
+type jobCursor struct {
+	SortBy string          `json:"s"`
+	Desc   bool            `json:"d"`
+	Value  json.RawMessage `json:"v"`
+	ID     string          `json:"id"`
+}
+
+// jobSortValue is the value a job sorts on, as JobSortFields computes it
+func jobSortValue(job *models.TranscriptionJob, sortBy string) interface{} {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	switch sortBy {
+	case "updated_at":
+		return job.UpdatedAt
+	case "title":
+		return deref(job.Title)
+	case "status":
+		return string(job.Status)
+	case "profile":
+		return deref(job.ProfileID)
+	case "adapter":
+		return job.Parameters.ModelFamily
+	case "language":
+		return deref(job.Parameters.Language)
+	case "duration":
+		if job.AudioSeconds == nil {
+			return float64(-1)
+		}
+		return *job.AudioSeconds
+	default:
+		return job.CreatedAt
+	}
+}
+
+// decodeJobCursor returns the sort value and job ID a cursor continues after
+func decodeJobCursor(cursor, sortBy string, desc bool) (interface{}, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	var c jobCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.SortBy != sortBy || c.Desc != desc || c.ID == "" {
+		return nil, "", ErrInvalidCursor
+	}
+	var value interface{}
+	switch sortBy {
+	case "created_at", "updated_at":
+		var t time.Time
+		err = json.Unmarshal(c.Value, &t)
+		value = t
+	case "duration":
+		var seconds float64
+		err = json.Unmarshal(c.Value, &seconds)
+		value = seconds
+	default:
+		var s string
+		err = json.Unmarshal(c.Value, &s)
+		value = s
+	}
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	return value, c.ID, nil
+}
+
+func (r *jobRepository) ListJobs(ctx context.Context, q JobListQuery) (*JobListPage, error) {
+	db := scopeJobs(ctx, r.db.WithContext(ctx).Model(&models.TranscriptionJob{}), "workspace")
+	db = scopeCollections(ctx, db, "collection_id")
+
+	if len(q.Statuses) > 0 {
+		db = db.Where("status IN ?", q.Statuses)
+	}
+	// Times are stored as text in local time, so bounds are compared in it too
+	if q.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", q.CreatedAfter.Local())
+	}
+	if q.CreatedBefore != nil {
+		db = db.Where("created_at < ?", q.CreatedBefore.Local())
+	}
+	if q.ProfileID != "" {
+		db = db.Where("profile_id = ?", q.ProfileID)
+	}
+	if q.ModelFamily != "" {
+		db = db.Where("model_family = ?", q.ModelFamily)
+	}
+	if q.Tag != "" {
+		key, value, hasValue := strings.Cut(q.Tag, "=")
+		tagged := r.db.Table("job_tags jt").Select("jt.transcription_job_id").
+			Joins("JOIN tags t ON t.id = jt.tag_id").Where("t.key = ?", key)
+		if hasValue {
+			tagged = tagged.Where("t.value = ?", value)
+		}
+		db = db.Where("id IN (?)", tagged)
+	}
+	if q.TagID != "" {
+		db = db.Where("id IN (?)", r.db.Model(&models.JobTag{}).Select("transcription_job_id").Where("tag_id = ?", q.TagID))
+	}
+	if len(q.CollectionIDs) > 0 {
+		db = db.Where("collection_id IN ?", q.CollectionIDs)
+	}
+	if q.NoCollection {
+		db = db.Where("collection_id IS NULL")
+	}
+	if q.Language != "" {
+		db = db.Where("language = ?", q.Language)
+	}
+	if q.MinDuration != nil {
+		db = db.Where("audio_seconds >= ?", *q.MinDuration)
+	}
+	if q.MaxDuration != nil {
+		db = db.Where("audio_seconds <= ?", *q.MaxDuration)
+	}
+	if q.Search != "" {
+		search := "%" + likeEscaper.Replace(q.Search) + "%"
+		db = db.Where(`(title LIKE ? ESCAPE '\' OR audio_path LIKE ? ESCAPE '\' OR tags LIKE ? ESCAPE '\')`, search, search, search)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	sortBy := q.SortBy
+	if _, ok := JobSortFields[sortBy]; !ok {
+		sortBy = "created_at"
+	}
+	expr := JobSortFields[sortBy]
+	desc := !strings.EqualFold(q.SortOrder, "asc")
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if q.Cursor != "" {
+		value, id, err := decodeJobCursor(q.Cursor, sortBy, desc)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("("+expr+" "+comparison+" ? OR ("+expr+" = ? AND id "+comparison+" ?))", value, value, id)
+	} else if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+
+	// The ID breaks ties, so pages neither skip nor repeat jobs
+	var jobs []models.TranscriptionJob
+	if err := db.Order(expr + " " + direction).Order("id " + direction).Limit(q.Limit).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	page := &JobListPage{Jobs: jobs, Total: total}
+	if q.Limit > 0 && len(jobs) == q.Limit {
+		last := &jobs[len(jobs)-1]
+		value, _ := json.Marshal(jobSortValue(last, sortBy))
+		data, _ := json.Marshal(jobCursor{SortBy: sortBy, Desc: desc, Value: value, ID: last.ID})
+		page.NextCursor = base64.RawURLEncoding.EncodeToString(data)
+	}
+	return page, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LibraryImportRepository handles bulk library imports and the files they found
+type LibraryImportRepository interface {
+	Create(ctx context.Context, imp *models.LibraryImport) error
+	Update(ctx context.Context, imp *models.LibraryImport) error
+	FindByID(ctx context.Context, id string) (*models.LibraryImport, error)
+	// List returns the imports of the workspaces ctx may see, newest first
+	List(ctx context.Context) ([]models.LibraryImport, error)
+	// ListRunning returns the imports still marked running, across every workspace
+	ListRunning(ctx context.Context) ([]models.LibraryImport, error)
+	CreateFile(ctx context.Context, file *models.LibraryImportFile) error
+	// ListFiles returns an import's files in the order found, with the total
+	ListFiles(ctx context.Context, importID, status string, offset, limit int) ([]models.LibraryImportFile, int64, error)
+	// IsImported reports whether a file with source or fingerprint was already
+	// queued into workspace, or a job there already reads source
+	IsImported(ctx context.Context, workspace, source, fingerprint string) (bool, error)
+	// CountWaiting counts the jobs an import queued that have not started yet
+	CountWaiting(ctx context.Context, importID string) (int64, error)
+}
+
+type libraryImportRepository struct {
+	db *gorm.DB
+}
+
+func NewLibraryImportRepository(db *gorm.DB) LibraryImportRepository {
+	return &libraryImportRepository{db: db}
+}
+
+func (r *libraryImportRepository) Create(ctx context.Context, imp *models.LibraryImport) error {
+	return r.db.WithContext(ctx).Create(imp).Error
+}
+
+func (r *libraryImportRepository) Update(ctx context.Context, imp *models.LibraryImport) error {
+	return r.db.WithContext(ctx).Save(imp).Error
+}
+
+func (r *libraryImportRepository) FindByID(ctx context.Context, id string) (*models.LibraryImport, error) {
+	var imp models.LibraryImport
+	if err := scopeJobs(ctx, r.db.WithContext(ctx), "workspace").Where("id = ?", id).First(&imp).Error; err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+func (r *libraryImportRepository) List(ctx context.Context) ([]models.LibraryImport, error) {
+	imports := []models.LibraryImport{}
+	err := scopeJobs(ctx, r.db.WithContext(ctx), "workspace").Order("created_at DESC").Find(&imports).Error
+	return imports, err
+}
+
+func (r *libraryImportRepository) ListRunning(ctx context.Context) ([]models.LibraryImport, error) {
+	var imports []models.LibraryImport
+	err := r.db.WithContext(ctx).Where("status = ?", models.LibraryImportRunning).Find(&imports).Error
+	return imports, err
+}
+
+func (r *libraryImportRepository) CreateFile(ctx context.Context, file *models.LibraryImportFile) error {
+	return r.db.WithContext(ctx).Create(file).Error
+}
+
+func (r *libraryImportRepository) ListFiles(ctx context.Context, importID, status string, offset, limit int) ([]models.LibraryImportFile, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.LibraryImportFile{}).Where("import_id = ?", importID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	files := []models.LibraryImportFile{}
+	err := query.Order("id ASC").Offset(offset).Limit(limit).Find(&files).Error
+	return files, total, err
+}
+
+func (r *libraryImportRepository) IsImported(ctx context.Context, workspace, source, fingerprint string) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.LibraryImportFile{}).
+		Where("workspace = ? AND status = ?", workspace, models.LibraryImportFileQueued)
+	if fingerprint != "" {
+		query = query.Where("source = ? OR fingerprint = ?", source, fingerprint)
+	} else {
+		query = query.Where("source = ?", source)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("workspace = ? AND audio_uri = ?", workspace, source).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *libraryImportRepository) CountWaiting(ctx context.Context, importID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Joins("JOIN library_import_files ON library_import_files.job_id = transcription_jobs.id").
+		Where("library_import_files.import_id = ? AND transcription_jobs.status = ?", importID, models.StatusPending).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// organizationCondition restricts rows with an organization_id column to the
+// organization ctx is scoped to. It is empty for unscoped contexts.
+func organizationCondition(ctx context.Context, column string) (string, []interface{}) {
+	organizationID, ok := models.OrganizationFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	return column + " = ?", []interface{}{organizationID}
+}
+
+// jobCondition restricts jobs, through their workspace column, to what ctx may
+// see (see models.WorkspaceVisible): a context scoped to a workspace sees that
+// workspace and the legacy one, an organization
+// sees its own workspace and other requests outside every organization see the
+// workspaces of no organization. It is empty for unscoped contexts.
+func jobCondition(ctx context.Context, column string) (string, []interface{}) {
+	if workspace, ok := models.WorkspaceFromContext(ctx); ok {
+		return column + " IN ?", []interface{}{[]string{workspace, ""}}
+	}
+	organizationID, ok := models.OrganizationFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	if organizationID == "" {
+		return column + " NOT LIKE ?", []interface{}{models.OrganizationWorkspace("%")}
+	}
+	return column + " = ?", []interface{}{models.OrganizationWorkspace(organizationID)}
+}
+
+// scopeOrganization applies organizationCondition to a query
+func scopeOrganization(ctx context.Context, db *gorm.DB, column string) *gorm.DB {
+	if condition, args := organizationCondition(ctx, column); condition != "" {
+		return db.Where(condition, args...)
+	}
+	return db
+}
+
+// collectionCondition leaves out jobs, through their collection_id column, filed
+// in the collections ctx hides (see models.WithHiddenCollections). It is empty
+// when ctx hides none.
+func collectionCondition(ctx context.Context, column string) (string, []interface{}) {
+	hidden := models.HiddenCollectionsFromContext(ctx)
+	if len(hidden) == 0 {
+		return "", nil
+	}
+	return "(" + column + " IS NULL OR " + column + " NOT IN ?)", []interface{}{hidden}
+}
+
+// scopeCollections applies collectionCondition to a query
+func scopeCollections(ctx context.Context, db *gorm.DB, column string) *gorm.DB {
+	if condition, args := collectionCondition(ctx, column); condition != "" {
+		return db.Where(condition, args...)
+	}
+	return db
+}
+
+// scopeJobs applies jobCondition to a query
+func scopeJobs(ctx context.Context, db *gorm.DB, column string) *gorm.DB {
+	if condition, args := jobCondition(ctx, column); condition != "" {
+		return db.Where(condition, args...)
+	}
+	return db
+}
+
+func (r *userRepository) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	var organizations []models.Organization
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&organizations).Error
+	return organizations, err
+}
+
+func (r *userRepository) FindOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	var organization models.Organization
+	if err := r.db.WithContext(ctx).First(&organization, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *userRepository) CreateOrganization(ctx context.Context, organization *models.Organization) error {
+	return r.db.WithContext(ctx).Create(organization).Error
+}
+
+func (r *userRepository) DeleteOrganization(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		// Keys of a deleted organization must not fall back to other data
+		if err := tx.Model(&models.APIKey{}).Where("organization_id = ?", id).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&models.TranscriptionProfile{}, &models.LLMConfig{}} {
+			if err := tx.Where("organization_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Organization{}, "id = ?", id).Error
+	})
+}
+
+func (r *userRepository) CountOrganizationJobs(ctx context.Context, id string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Where("workspace = ?", models.OrganizationWorkspace(id)).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) ListOrganizationMembers(ctx context.Context, id string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).
+		Joins("JOIN organization_members m ON m.user_id = users.id").
+		Where("m.organization_id = ?", id).
+		Order("users.username ASC").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) AddOrganizationMember(ctx context.Context, id string, userID uint) error {
+	member := models.OrganizationMember{OrganizationID: id, UserID: userID}
+	return r.db.WithContext(ctx).Omit("Organization", "User").
+		Where(member).FirstOrCreate(&member).Error
+}
+
+func (r *userRepository) RemoveOrganizationMember(ctx context.Context, id string, userID uint) error {
+	return r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", id, userID).
+		Delete(&models.OrganizationMember{}).Error
+}
+
+func (r *userRepository) ListUserOrganizations(ctx context.Context, userID uint) ([]models.Organization, error) {
+	var organizations []models.Organization
+	err := r.db.WithContext(ctx).
+		Joins("JOIN organization_members m ON m.organization_id = organizations.id").
+		Where("m.user_id = ?", userID).
+		Order("m.created_at ASC").
+		Find(&organizations).Error
+	return organizations, err
+}
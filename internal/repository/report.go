@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReportRepository handles report schedules and the activity reports compile
+type ReportRepository interface {
+	Repository[models.ReportSchedule]
+	// ListVisible lists the schedules of the workspaces visible to ctx
+	ListVisible(ctx context.Context) ([]models.ReportSchedule, error)
+	// ListDue returns the enabled schedules due to run at now
+	ListDue(ctx context.Context, now time.Time) ([]models.ReportSchedule, error)
+	// CountJobsCreated counts a workspace's jobs created in [since, until) by status
+	CountJobsCreated(ctx context.Context, workspace string, since, until time.Time) (map[models.JobStatus]int64, error)
+	// ListJobsFinished returns a workspace's jobs that completed or failed in
+	// [since, until), oldest first
+	ListJobsFinished(ctx context.Context, workspace string, since, until time.Time) ([]models.TranscriptionJob, error)
+	// FindQuotaUsage returns the audio seconds a workspace had transcribed in a month
+	FindQuotaUsage(ctx context.Context, workspace, month string) (float64, error)
+}
+
+type reportRepository struct {
+	*BaseRepository[models.ReportSchedule]
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{
+		BaseRepository: NewBaseRepository[models.ReportSchedule](db),
+	}
+}
+
+func (r *reportRepository) ListVisible(ctx context.Context) ([]models.ReportSchedule, error) {
+	schedules := []models.ReportSchedule{}
+	err := scopeJobs(ctx, r.db.WithContext(ctx), "workspace").Order("created_at ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *reportRepository) ListDue(ctx context.Context, now time.Time) ([]models.ReportSchedule, error) {
+	var schedules []models.ReportSchedule
+	err := r.db.WithContext(ctx).
+		Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *reportRepository) CountJobsCreated(ctx context.Context, workspace string, since, until time.Time) (map[models.JobStatus]int64, error) {
+	var rows []struct {
+		Status models.JobStatus
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.TranscriptionJob{}).
+		Select("status, COUNT(*) AS count").
+		Where("workspace = ? AND created_at >= ? AND created_at < ?", workspace, since, until).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[models.JobStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *reportRepository) ListJobsFinished(ctx context.Context, workspace string, since, until time.Time) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := r.db.WithContext(ctx).
+		Select("id", "title", "status", "workspace", "transcript", "error_message", "created_at", "updated_at").
+		Where("workspace = ? AND status IN ?", workspace, []models.JobStatus{models.StatusCompleted, models.StatusFailed}).
+		Where("updated_at >= ? AND updated_at < ?", since, until).
+		Order("updated_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *reportRepository) FindQuotaUsage(ctx context.Context, workspace, month string) (float64, error) {
+	var usage models.QuotaUsage
+	err := r.db.WithContext(ctx).Where("workspace = ? AND month = ?", workspace, month).Limit(1).Find(&usage).Error
+	return usage.AudioSeconds, err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RetentionRepository handles retention policies
+type RetentionRepository interface {
+	// List returns the policies ctx may see, oldest first
+	List(ctx context.Context) ([]models.RetentionPolicy, error)
+	FindByID(ctx context.Context, id uint) (*models.RetentionPolicy, error)
+	Create(ctx context.Context, policy *models.RetentionPolicy) error
+	Update(ctx context.Context, policy *models.RetentionPolicy) error
+	Delete(ctx context.Context, id uint) error
+	// ListCandidates returns the finished, unfinalized jobs created before
+	// cutoff, with the columns retention needs and their tag assignments,
+	// without their transcripts
+	ListCandidates(ctx context.Context, cutoff time.Time) ([]models.TranscriptionJob, error)
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+func (r *retentionRepository) List(ctx context.Context) ([]models.RetentionPolicy, error) {
+	policies := []models.RetentionPolicy{}
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Order("id ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *retentionRepository) FindByID(ctx context.Context, id uint) (*models.RetentionPolicy, error) {
+	var policy models.RetentionPolicy
+	if err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("id = ?", id).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *retentionRepository) Create(ctx context.Context, policy *models.RetentionPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *retentionRepository) Update(ctx context.Context, policy *models.RetentionPolicy) error {
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+func (r *retentionRepository) Delete(ctx context.Context, id uint) error {
+	result := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("id = ?", id).Delete(&models.RetentionPolicy{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *retentionRepository) ListCandidates(ctx context.Context, cutoff time.Time) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := scopeJobs(ctx, r.db.WithContext(ctx), "workspace").
+		Select("id", "title", "status", "workspace", "profile_id", "audio_path", "audio_uri", "is_multi_track",
+			"multi_track_folder", "merged_audio_path", "aup_file_path", "audio_expired_at", "audio_archive_uri", "created_at").
+		Preload("MultiTrackFiles").
+		Preload("TagAssignments").
+		Where("status IN ?", []models.JobStatus{models.StatusCompleted, models.StatusFailed, models.StatusCancelled}).
+		Where("finalized_at IS NULL AND created_at < ?", cutoff).
+		Order("created_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
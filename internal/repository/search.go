@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Snippet markers used to locate highlighted terms; stripped before returning
+const (
+	highlightOpen  = '\x02'
+	highlightClose = '\x03'
+
+	// maxSearchRows bounds how many index rows are ranked per query
+	maxSearchRows = 1000
+)
+
+// SearchRepository runs full-text queries against the search index
+type SearchRepository interface {
+	Search(ctx context.Context, query string, limit, matchesPerJob int) ([]models.SearchResult, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+type searchRow struct {
+	JobID     string
+	Kind      string
+	SourceID  string
+	StartTime *float64
+	EndTime   *float64
+	Snippet   string
+	Title     *string
+	Workspace string
+	Status    models.JobStatus
+	CreatedAt time.Time
+}
+
+// Search returns up to limit transcriptions matching query, ordered by their best
+// match, with at most matchesPerJob matches each
+func (r *searchRepository) Search(ctx context.Context, query string, limit, matchesPerJob int) ([]models.SearchResult, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return []models.SearchResult{}, nil
+	}
+
+	scope, args := jobCondition(ctx, "j.workspace")
+	if scope != "" {
+		scope = "AND " + scope
+	}
+	if condition, collectionArgs := collectionCondition(ctx, "j.collection_id"); condition != "" {
+		scope += " AND " + condition
+		args = append(args, collectionArgs...)
+	}
+	args = append([]interface{}{match}, append(args, maxSearchRows)...)
+
+	var rows []searchRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT search_index.job_id, search_index.kind, search_index.source_id,
+			search_index.start_time, search_index.end_time,
+			snippet(search_index, 0, char(2), char(3), '…', 32) AS snippet,
+			j.title, j.workspace, j.status, j.created_at
+		FROM search_index
+		JOIN transcription_jobs j ON j.id = search_index.job_id
+		WHERE search_index MATCH ? `+scope+`
+		ORDER BY search_index.rank
+		LIMIT ?`, args...).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := []models.SearchResult{}
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.JobID]
+		if !ok {
+			if len(results) >= limit {
+				continue
+			}
+			i = len(results)
+			index[row.JobID] = i
+			results = append(results, models.SearchResult{
+				JobID:     row.JobID,
+				Workspace: row.Workspace,
+				Title:     row.Title,
+				Status:    row.Status,
+				CreatedAt: row.CreatedAt,
+			})
+		}
+		if len(results[i].Matches) >= matchesPerJob {
+			continue
+		}
+
+		snippet, highlights := parseSnippet(row.Snippet)
+		results[i].Matches = append(results[i].Matches, models.SearchMatch{
+			Kind:       row.Kind,
+			SourceID:   row.SourceID,
+			StartTime:  row.StartTime,
+			EndTime:    row.EndTime,
+			Snippet:    snippet,
+			Highlights: highlights,
+		})
+	}
+	return results, nil
+}
+
+// ftsQuery turns free text into an FTS5 query: every word is quoted so operators
+// and punctuation are matched literally, and the last word is prefix-matched
+func ftsQuery(query string) string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		terms = append(terms, `"`+strings.ReplaceAll(field, `"`, `""`)+`"`)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
+
+// parseSnippet strips the highlight markers and returns their positions
+func parseSnippet(marked string) (string, []models.SearchHighlight) {
+	var b strings.Builder
+	highlights := []models.SearchHighlight{}
+	pos, start := 0, -1
+	for _, r := range marked {
+		switch r {
+		case highlightOpen:
+			start = pos
+		case highlightClose:
+			if start >= 0 {
+				highlights = append(highlights, models.SearchHighlight{Start: start, End: pos})
+				start = -1
+			}
+		default:
+			b.WriteRune(r)
+			pos++
+		}
+	}
+	return b.String(), highlights
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SpeakerAttributeRepository stores the speaker attributes estimated for jobs
+// and the workspace settings that allow them
+type SpeakerAttributeRepository interface {
+	// Replace replaces the speaker attributes recorded for a job
+	Replace(ctx context.Context, jobID string, attributes []models.SpeakerAttribute) error
+	ListByJob(ctx context.Context, jobID string) ([]models.SpeakerAttribute, error)
+	// DeleteInWorkspace removes the speaker attributes of every job in a workspace
+	DeleteInWorkspace(ctx context.Context, workspace string) (int64, error)
+	// GetSettings returns a workspace's settings, or the defaults if none are saved
+	GetSettings(ctx context.Context, workspace string) (*models.WorkspaceSettings, error)
+	SaveSettings(ctx context.Context, settings *models.WorkspaceSettings) error
+}
+
+type speakerAttributeRepository struct {
+	db *gorm.DB
+}
+
+func NewSpeakerAttributeRepository(db *gorm.DB) SpeakerAttributeRepository {
+	return &speakerAttributeRepository{db: db}
+}
+
+func (r *speakerAttributeRepository) Replace(ctx context.Context, jobID string, attributes []models.SpeakerAttribute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.SpeakerAttribute{}).Error; err != nil {
+			return err
+		}
+		for i := range attributes {
+			attributes[i].TranscriptionJobID = jobID
+		}
+		if len(attributes) > 0 {
+			return tx.Omit("TranscriptionJob").Create(&attributes).Error
+		}
+		return nil
+	})
+}
+
+func (r *speakerAttributeRepository) ListByJob(ctx context.Context, jobID string) ([]models.SpeakerAttribute, error) {
+	var attributes []models.SpeakerAttribute
+	err := r.db.WithContext(ctx).Where("transcription_job_id = ?", jobID).Order("speaker ASC").Find(&attributes).Error
+	return attributes, err
+}
+
+func (r *speakerAttributeRepository) DeleteInWorkspace(ctx context.Context, workspace string) (int64, error) {
+	jobs := r.db.Model(&models.TranscriptionJob{}).Select("id").Where("workspace = ?", workspace)
+	result := r.db.WithContext(ctx).Where("transcription_job_id IN (?)", jobs).Delete(&models.SpeakerAttribute{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *speakerAttributeRepository) GetSettings(ctx context.Context, workspace string) (*models.WorkspaceSettings, error) {
+	settings := models.WorkspaceSettings{Workspace: workspace}
+	err := r.db.WithContext(ctx).Where("workspace = ?", workspace).Limit(1).Find(&settings).Error
+	return &settings, err
+}
+
+func (r *speakerAttributeRepository) SaveSettings(ctx context.Context, settings *models.WorkspaceSettings) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(settings).Error
+}
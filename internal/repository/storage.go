@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StorageRepository reads what storage reporting needs of jobs
+type StorageRepository interface {
+	// ListJobFiles returns the jobs ctx may see with only their file columns
+	// and tracks, without their transcripts
+	ListJobFiles(ctx context.Context) ([]models.TranscriptionJob, error)
+	// ListActiveJobFiles returns the jobs of every workspace that are waiting
+	// or being processed, with only their file columns and tracks
+	ListActiveJobFiles(ctx context.Context) ([]models.TranscriptionJob, error)
+}
+
+type storageRepository struct {
+	db *gorm.DB
+}
+
+func NewStorageRepository(db *gorm.DB) StorageRepository {
+	return &storageRepository{db: db}
+}
+
+func (r *storageRepository) ListJobFiles(ctx context.Context) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := scopeJobs(ctx, r.db.WithContext(ctx), "workspace").
+		Select("id", "title", "status", "workspace", "audio_path", "is_multi_track", "multi_track_folder",
+			"merged_audio_path", "aup_file_path", "audio_expired_at", "created_at").
+		Preload("MultiTrackFiles").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *storageRepository) ListActiveJobFiles(ctx context.Context) ([]models.TranscriptionJob, error) {
+	var jobs []models.TranscriptionJob
+	err := r.db.WithContext(ctx).
+		Select("id", "workspace", "audio_path", "is_multi_track", "multi_track_folder", "merged_audio_path", "aup_file_path").
+		Preload("MultiTrackFiles").
+		Where("status IN ?", []models.JobStatus{models.StatusUploaded, models.StatusDownloading, models.StatusPending, models.StatusProcessing}).
+		Find(&jobs).Error
+	return jobs, err
+}
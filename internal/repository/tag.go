@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTagInUse is returned when deleting a tag a retention policy applies to
+var ErrTagInUse = errors.New("tag is used by a retention policy")
+
+// TagRepository handles the tags of workspaces and their assignment to jobs.
+// Assigning tags also rewrites the Tags of the jobs concerned.
+type TagRepository interface {
+	// ListByWorkspace lists the workspace's tags, most used first. A prefix
+	// only lists the tags whose key, value or key=value name starts with it,
+	// for autocompletion; a limit of 0 lists them all.
+	ListByWorkspace(ctx context.Context, workspace, prefix string, limit int) ([]TagWithJobs, error)
+	FindInWorkspace(ctx context.Context, workspace, id string) (*models.Tag, error)
+	FindByName(ctx context.Context, workspace, key, value string) (*models.Tag, error)
+	Create(ctx context.Context, tag *models.Tag) error
+	// Update saves a renamed or recoloured tag, and the tags of its jobs
+	Update(ctx context.Context, tag *models.Tag) error
+	// DeleteTag removes the tag from its jobs and deletes it, or returns
+	// ErrTagInUse while a retention policy applies to it
+	DeleteTag(ctx context.Context, workspace, id string) error
+	// ListByJob returns the job's tags, in the order they were assigned
+	ListByJob(ctx context.Context, jobID string) ([]models.Tag, error)
+	// Assign tags a job; assigning a tag twice does nothing
+	Assign(ctx context.Context, jobID, tagID string) error
+	// Unassign removes a tag from a job, returning gorm.ErrRecordNotFound if
+	// the job did not have it
+	Unassign(ctx context.Context, jobID, tagID string) error
+}
+
+// TagWithJobs is a tag with the number of jobs it is assigned to
+type TagWithJobs struct {
+	models.Tag
+	JobCount int64 `json:"job_count"`
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// likeEscaper escapes LIKE wildcards, for ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *tagRepository) ListByWorkspace(ctx context.Context, workspace, prefix string, limit int) ([]TagWithJobs, error) {
+	tags := []TagWithJobs{}
+	query := r.db.WithContext(ctx).
+		Table("tags").
+		Select("tags.*, COUNT(jt.transcription_job_id) AS job_count").
+		Joins("LEFT JOIN job_tags jt ON jt.tag_id = tags.id").
+		Where("tags.workspace = ?", workspace)
+	if prefix != "" {
+		pattern := likeEscaper.Replace(prefix) + "%"
+		query = query.Where(`(tags.key LIKE ? ESCAPE '\' OR tags.value LIKE ? ESCAPE '\' OR tags.key || '=' || tags.value LIKE ? ESCAPE '\')`,
+			pattern, pattern, pattern)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Group("tags.id").Order("job_count DESC, tags.key ASC, tags.value ASC").Scan(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindInWorkspace(ctx context.Context, workspace, id string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).Where("workspace = ? AND id = ?", workspace, id).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindByName(ctx context.Context, workspace, key, value string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).Where("workspace = ? AND key = ? AND value = ?", workspace, key, value).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(tag).Error; err != nil {
+			return err
+		}
+		var jobIDs []string
+		if err := taggedJobs(tx, tag.ID).Pluck("transcription_job_id", &jobIDs).Error; err != nil {
+			return err
+		}
+		return storeJobTags(tx, jobIDs)
+	})
+}
+
+func (r *tagRepository) DeleteTag(ctx context.Context, workspace, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("workspace = ? AND id = ?", workspace, id).Delete(&models.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var policies int64
+		if err := tx.Model(&models.RetentionPolicy{}).Where("tag_id = ?", id).Count(&policies).Error; err != nil {
+			return err
+		}
+		if policies > 0 {
+			return ErrTagInUse
+		}
+		var jobIDs []string
+		if err := taggedJobs(tx, id).Pluck("transcription_job_id", &jobIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.JobTag{}).Error; err != nil {
+			return err
+		}
+		return storeJobTags(tx, jobIDs)
+	})
+}
+
+func (r *tagRepository) ListByJob(ctx context.Context, jobID string) ([]models.Tag, error) {
+	return jobTags(r.db.WithContext(ctx), jobID)
+}
+
+func (r *tagRepository) Assign(ctx context.Context, jobID, tagID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		assignment := models.JobTag{TranscriptionJobID: jobID, TagID: tagID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignment).Error; err != nil {
+			return err
+		}
+		return storeJobTags(tx, []string{jobID})
+	})
+}
+
+func (r *tagRepository) Unassign(ctx context.Context, jobID, tagID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("transcription_job_id = ? AND tag_id = ?", jobID, tagID).Delete(&models.JobTag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return storeJobTags(tx, []string{jobID})
+	})
+}
+
+// taggedJobs selects the assignments of a tag
+func taggedJobs(db *gorm.DB, tagID string) *gorm.DB {
+	return db.Model(&models.JobTag{}).Where("tag_id = ?", tagID)
+}
+
+// jobTags returns a job's tags, in the order they were assigned
+func jobTags(db *gorm.DB, jobID string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	err := db.Joins("JOIN job_tags jt ON jt.tag_id = tags.id").
+		Where("jt.transcription_job_id = ?", jobID).
+		Order("jt.created_at ASC, tags.key ASC, tags.value ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+// storeJobTags rewrites the Tags of jobs from their tag assignments
+func storeJobTags(tx *gorm.DB, jobIDs []string) error {
+	for _, jobID := range jobIDs {
+		tags, err := jobTags(tx, jobID)
+		if err != nil {
+			return err
+		}
+		var stored *string
+		if len(tags) > 0 {
+			entries := make([]models.TagEntry, 0, len(tags))
+			for _, tag := range tags {
+				entries = append(entries, models.TagEntry{Key: tag.Key, Value: tag.Value})
+			}
+			data, err := json.Marshal(entries)
+			if err != nil {
+				return err
+			}
+			encoded := string(data)
+			stored = &encoded
+		}
+		if err := tx.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Update("tags", stored).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignStoredTags assigns a job the tags stored in its Tags, and only those,
+// adding the tags its workspace does not have yet. Jobs without tags, or whose
+// Tags are not a list of AWS tags, are left as they are.
+func assignStoredTags(tx *gorm.DB, job *models.TranscriptionJob) error {
+	entries, err := job.TagEntries()
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+	tagIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		tag := models.Tag{Workspace: job.Workspace, Key: entry.Key, Value: entry.Value}
+		if err := tx.Where("workspace = ? AND key = ? AND value = ?", tag.Workspace, tag.Key, tag.Value).FirstOrCreate(&tag).Error; err != nil {
+			return err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+		assignment := models.JobTag{TranscriptionJobID: job.ID, TagID: tag.ID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignment).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("transcription_job_id = ? AND tag_id NOT IN ?", job.ID, tagIDs).Delete(&models.JobTag{}).Error
+}
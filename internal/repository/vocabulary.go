@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// VocabularyRepository handles custom vocabularies and vocabulary filters,
+// found by name within the organization of ctx
+type VocabularyRepository interface {
+	ListVocabularies(ctx context.Context) ([]models.Vocabulary, error)
+	FindVocabulary(ctx context.Context, name string) (*models.Vocabulary, error)
+	CreateVocabulary(ctx context.Context, vocabulary *models.Vocabulary) error
+	UpdateVocabulary(ctx context.Context, vocabulary *models.Vocabulary) error
+	DeleteVocabulary(ctx context.Context, name string) error
+
+	ListFilters(ctx context.Context) ([]models.VocabularyFilter, error)
+	FindFilter(ctx context.Context, name string) (*models.VocabularyFilter, error)
+	CreateFilter(ctx context.Context, filter *models.VocabularyFilter) error
+	UpdateFilter(ctx context.Context, filter *models.VocabularyFilter) error
+	DeleteFilter(ctx context.Context, name string) error
+}
+
+type vocabularyRepository struct {
+	db *gorm.DB
+}
+
+func NewVocabularyRepository(db *gorm.DB) VocabularyRepository {
+	return &vocabularyRepository{db: db}
+}
+
+func (r *vocabularyRepository) ListVocabularies(ctx context.Context) ([]models.Vocabulary, error) {
+	vocabularies := []models.Vocabulary{}
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Order("name ASC").Find(&vocabularies).Error
+	return vocabularies, err
+}
+
+func (r *vocabularyRepository) FindVocabulary(ctx context.Context, name string) (*models.Vocabulary, error) {
+	var vocabulary models.Vocabulary
+	if err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("name = ?", name).First(&vocabulary).Error; err != nil {
+		return nil, err
+	}
+	return &vocabulary, nil
+}
+
+func (r *vocabularyRepository) CreateVocabulary(ctx context.Context, vocabulary *models.Vocabulary) error {
+	return r.db.WithContext(ctx).Create(vocabulary).Error
+}
+
+func (r *vocabularyRepository) UpdateVocabulary(ctx context.Context, vocabulary *models.Vocabulary) error {
+	return r.db.WithContext(ctx).Save(vocabulary).Error
+}
+
+func (r *vocabularyRepository) DeleteVocabulary(ctx context.Context, name string) error {
+	result := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("name = ?", name).Delete(&models.Vocabulary{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *vocabularyRepository) ListFilters(ctx context.Context) ([]models.VocabularyFilter, error) {
+	filters := []models.VocabularyFilter{}
+	err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Order("name ASC").Find(&filters).Error
+	return filters, err
+}
+
+func (r *vocabularyRepository) FindFilter(ctx context.Context, name string) (*models.VocabularyFilter, error) {
+	var filter models.VocabularyFilter
+	if err := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("name = ?", name).First(&filter).Error; err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func (r *vocabularyRepository) CreateFilter(ctx context.Context, filter *models.VocabularyFilter) error {
+	return r.db.WithContext(ctx).Create(filter).Error
+}
+
+func (r *vocabularyRepository) UpdateFilter(ctx context.Context, filter *models.VocabularyFilter) error {
+	return r.db.WithContext(ctx).Save(filter).Error
+}
+
+func (r *vocabularyRepository) DeleteFilter(ctx context.Context, name string) error {
+	result := scopeOrganization(ctx, r.db.WithContext(ctx), "organization_id").Where("name = ?", name).Delete(&models.VocabularyFilter{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Package scanner checks uploaded media for malware before it is processed, using
+// a ClamAV daemon or an external HTTP scanning service.
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"scriberr/internal/config"
+)
+
+const (
+	ModeClamAV = "clamav"
+	ModeHTTP   = "http"
+
+	// clamAVChunkSize must stay below clamd's StreamMaxLength chunking limits
+	clamAVChunkSize = 64 * 1024
+)
+
+// Result is the verdict for one file
+type Result struct {
+	Clean bool
+	// Signature names what was found when the file is not clean
+	Signature string
+}
+
+// Scanner scans a file on disk
+type Scanner interface {
+	Scan(ctx context.Context, path string) (Result, error)
+}
+
+// New returns the scanner configured by SCAN_MODE, or nil when scanning is disabled
+func New(cfg *config.Config) (Scanner, error) {
+	timeout := time.Duration(cfg.ScanTimeoutSeconds) * time.Second
+	switch strings.ToLower(cfg.ScanMode) {
+	case "":
+		return nil, nil
+	case ModeClamAV:
+		if cfg.ClamAVAddress == "" {
+			return nil, fmt.Errorf("CLAMAV_ADDRESS is required when SCAN_MODE is %s", ModeClamAV)
+		}
+		return &clamAVScanner{address: cfg.ClamAVAddress, timeout: timeout}, nil
+	case ModeHTTP:
+		if cfg.ScanHTTPURL == "" {
+			return nil, fmt.Errorf("SCAN_HTTP_URL is required when SCAN_MODE is %s", ModeHTTP)
+		}
+		return &httpScanner{url: cfg.ScanHTTPURL, client: &http.Client{Timeout: timeout}}, nil
+	default:
+		return nil, fmt.Errorf("unknown SCAN_MODE %q (expected %s or %s)", cfg.ScanMode, ModeClamAV, ModeHTTP)
+	}
+}
+
+// clamAVScanner streams files to clamd with the INSTREAM command. The address is
+// either a unix socket path or host:port.
+type clamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+func (s *clamAVScanner) Scan(ctx context.Context, path string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+
+	network := "tcp"
+	if strings.HasPrefix(s.address, "/") {
+		network = "unix"
+	}
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, network, s.address)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else if s.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("failed to send INSTREAM: %w", err)
+	}
+	buf := make([]byte, clamAVChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := f.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return Result{}, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return Result{}, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return Result{}, readErr
+		}
+	}
+	// A zero-length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Result{}, fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamAVReply(string(reply))
+}
+
+// parseClamAVReply interprets replies such as "stream: OK" and
+// "stream: Eicar-Signature FOUND"
+func parseClamAVReply(reply string) (Result, error) {
+	reply = strings.TrimRight(reply, "\x00\r\n ")
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return Result{Clean: true}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd error: %s", reply)
+	}
+}
+
+// httpScanner posts the file body to an external service, which answers with
+// {"clean": bool, "signature": "..."}
+type httpScanner struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpScanner) Scan(ctx context.Context, path string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, f)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Filename", filepath.Base(path))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("scanner request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read scanner response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("scanner returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var verdict struct {
+		Clean     *bool  `json:"clean"`
+		Signature string `json:"signature"`
+	}
+	if err := json.Unmarshal(body, &verdict); err != nil || verdict.Clean == nil {
+		return Result{}, fmt.Errorf("invalid scanner response: %s", bytes.TrimSpace(body))
+	}
+	if !*verdict.Clean && verdict.Signature == "" {
+		verdict.Signature = "unknown"
+	}
+	return Result{Clean: *verdict.Clean, Signature: verdict.Signature}, nil
+}
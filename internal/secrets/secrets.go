@@ -0,0 +1,306 @@
+// Package secrets resolves credentials kept in a secret store instead of the
+// environment or the database. A setting holds a reference to the secret:
+//
+//	aws-sm://<secret ID or ARN>[#<JSON key>]  AWS Secrets Manager
+//	vault://<path>[#<key>]                    HashiCorp Vault, e.g. secret/data/runpod#api_key
+//	docker-secret://<name>                    Docker or Kubernetes secret file
+//
+// Other values are used as they are. Resolved secrets are cached and fetched
+// again once they are older than the refresh interval, so rotated credentials
+// are picked up without a restart.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/pkg/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// Reference schemes
+const (
+	SchemeAWSSecretsManager = "aws-sm"
+	SchemeVault             = "vault"
+	SchemeDockerSecret      = "docker-secret"
+)
+
+// fetchTimeout bounds fetching one secret
+const fetchTimeout = 15 * time.Second
+
+// Config locates the secret stores
+type Config struct {
+	// RefreshInterval is how long a fetched secret is used before it is
+	// fetched again; 0 fetches it every time
+	RefreshInterval time.Duration
+	VaultAddr       string
+	VaultToken      string
+	// Dir holds Docker secrets, /run/secrets by default
+	Dir string
+}
+
+// Resolver fetches and caches referenced secrets
+type Resolver struct {
+	cfg    Config
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewResolver creates a resolver for the stores in cfg
+func NewResolver(cfg Config) *Resolver {
+	if cfg.Dir == "" {
+		cfg.Dir = "/run/secrets"
+	}
+	return &Resolver{
+		cfg:    cfg,
+		client: &http.Client{Timeout: fetchTimeout},
+		cache:  make(map[string]cachedSecret),
+	}
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultResolver = NewResolver(Config{})
+)
+
+// SetDefault replaces the resolver used by Resolve
+func SetDefault(r *Resolver) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultResolver = r
+}
+
+// Default returns the resolver used by Resolve
+func Default() *Resolver {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultResolver
+}
+
+// Resolve resolves value with the default resolver
+func Resolve(ctx context.Context, value string) (string, error) {
+	return Default().Resolve(ctx, value)
+}
+
+// parseReference splits a reference into its scheme, the secret's location
+// and the key within it; ok is false for plain values
+func parseReference(value string) (scheme, location, key string, ok bool) {
+	scheme, rest, found := strings.Cut(value, "://")
+	if !found {
+		return "", "", "", false
+	}
+	switch scheme {
+	case SchemeAWSSecretsManager, SchemeVault, SchemeDockerSecret:
+	default:
+		return "", "", "", false
+	}
+	location, key, _ = strings.Cut(rest, "#")
+	return scheme, location, key, true
+}
+
+// IsReference reports whether value names a secret rather than holding it
+func IsReference(value string) bool {
+	_, _, _, ok := parseReference(value)
+	return ok
+}
+
+// Source returns where value comes from: a reference's scheme, or "value"
+func Source(value string) string {
+	if scheme, _, _, ok := parseReference(value); ok {
+		return scheme
+	}
+	return "value"
+}
+
+// Mask returns value fit for display: references as they are, since they hold
+// no secret, and other values with all but their last four characters hidden
+func Mask(value string) string {
+	if value == "" || IsReference(value) {
+		return value
+	}
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", 8) + value[len(value)-4:]
+}
+
+// Resolve returns the secret value references, or value itself when it is
+// not a reference. When a store cannot be reached, the last fetched value is
+// used until it can.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	scheme, location, key, ok := parseReference(value)
+	if !ok {
+		return value, nil
+	}
+
+	r.mu.Lock()
+	cached, found := r.cache[value]
+	r.mu.Unlock()
+	if found && time.Since(cached.fetchedAt) < r.cfg.RefreshInterval {
+		return cached.value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	var secret string
+	var err error
+	switch scheme {
+	case SchemeAWSSecretsManager:
+		secret, err = r.fetchAWS(ctx, location, key)
+	case SchemeVault:
+		secret, err = r.fetchVault(ctx, location, key)
+	case SchemeDockerSecret:
+		secret, err = r.readDockerSecret(location, key)
+	}
+	if err != nil {
+		if found {
+			logger.Warn("Failed to refresh secret, using the last fetched value", "secret", value, "error", err)
+			return cached.value, nil
+		}
+		return "", fmt.Errorf("failed to resolve secret %s: %w", value, err)
+	}
+
+	r.mu.Lock()
+	r.cache[value] = cachedSecret{value: secret, fetchedAt: time.Now()}
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// selectKey picks key out of a JSON object secret; without a key the secret
+// is used whole
+func selectKey(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, so it has no key %q", key)
+	}
+	return fieldString(fields, key)
+}
+
+func fieldString(fields map[string]interface{}, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no key %q", key)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("secret key %q is not a string", key)
+	}
+	return s, nil
+}
+
+// fetchAWS gets a secret from AWS Secrets Manager with the default AWS
+// credentials and region
+func (r *Resolver) fetchAWS(ctx context.Context, secretID, key string) (string, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		return "", errors.New("no AWS region configured")
+	}
+	out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return "", errors.New("secret has no string value")
+	}
+	return selectKey(*out.SecretString, key)
+}
+
+// fetchVault reads a secret from Vault. KV version 2 paths include data/, as
+// in the Vault API; without a key the secret must have a single field.
+func (r *Resolver) fetchVault(ctx context.Context, path, key string) (string, error) {
+	if r.cfg.VaultAddr == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	url := strings.TrimRight(r.cfg.VaultAddr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if r.cfg.VaultToken != "" {
+		req.Header.Set("X-Vault-Token", r.cfg.VaultToken)
+	}
+
+	var resp struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+	status, err := r.doJSON(req, &resp)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d: %s", status, strings.Join(resp.Errors, "; "))
+	}
+	// KV version 2 nests the fields under data.data
+	fields := resp.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		if _, versioned := fields["metadata"]; versioned {
+			fields = nested
+		}
+	}
+	if key == "" {
+		if len(fields) != 1 {
+			return "", errors.New("secret has several fields; name one after #")
+		}
+		for field := range fields {
+			key = field
+		}
+	}
+	return fieldString(fields, key)
+}
+
+// readDockerSecret reads a secret mounted as a file
+func (r *Resolver) readDockerSecret(name, key string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(r.cfg.Dir, name))
+	if err != nil {
+		return "", err
+	}
+	return selectKey(strings.TrimSpace(string(data)), key)
+}
+
+// doJSON sends req and decodes its JSON response into out
+func (r *Resolver) doJSON(req *http.Request, out interface{}) (int, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(data, out); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,153 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainValuesAreUsedAsTheyAre(t *testing.T) {
+	r := NewResolver(Config{})
+	for _, value := range []string{"", "sk-plain", "https://example.com", "s3://bucket/key"} {
+		resolved, err := r.Resolve(context.Background(), value)
+		require.NoError(t, err)
+		assert.Equal(t, value, resolved)
+		assert.False(t, IsReference(value))
+	}
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "", Mask(""))
+	assert.Equal(t, "*****", Mask("short"))
+	assert.Equal(t, "********7890", Mask("sk-1234567890"))
+	assert.Equal(t, "vault://secret/data/runpod#api_key", Mask("vault://secret/data/runpod#api_key"))
+	assert.Equal(t, SchemeVault, Source("vault://secret/data/runpod#api_key"))
+	assert.Equal(t, "value", Source("sk-1234567890"))
+}
+
+func TestDockerSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openai_key"), []byte("sk-docker\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tokens"), []byte(`{"hf":"hf-docker"}`), 0600))
+	r := NewResolver(Config{Dir: dir})
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "docker-secret://openai_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-docker", value)
+	value, err = r.Resolve(ctx, "docker-secret://tokens#hf")
+	require.NoError(t, err)
+	assert.Equal(t, "hf-docker", value)
+
+	_, err = r.Resolve(ctx, "docker-secret://missing")
+	assert.Error(t, err)
+	_, err = r.Resolve(ctx, "docker-secret://../openai_key")
+	assert.Error(t, err)
+}
+
+func TestVaultSecretsAreRefreshed(t *testing.T) {
+	var calls atomic.Int32
+	key := "rp-first"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/runpod":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"data":     map[string]string{"api_key": key, "endpoint": "abc"},
+				"metadata": map[string]interface{}{"version": 2},
+			}})
+		case "/v1/kv/openai":
+			w.Write([]byte(`{"data":{"token":"sk-vault"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	r := NewResolver(Config{VaultAddr: server.URL, VaultToken: "root", RefreshInterval: time.Hour})
+	ctx := context.Background()
+	value, err := r.Resolve(ctx, "vault://secret/data/runpod#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "rp-first", value)
+	value, err = r.Resolve(ctx, "vault://kv/openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-vault", value)
+	_, err = r.Resolve(ctx, "vault://secret/data/runpod")
+	assert.ErrorContains(t, err, "several fields")
+	_, err = r.Resolve(ctx, "vault://secret/data/missing#key")
+	assert.ErrorContains(t, err, "404")
+
+	// Cached until the refresh interval has passed
+	key = "rp-rotated"
+	before := calls.Load()
+	value, err = r.Resolve(ctx, "vault://secret/data/runpod#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "rp-first", value)
+	assert.Equal(t, before, calls.Load())
+
+	r.cfg.RefreshInterval = 0
+	value, err = r.Resolve(ctx, "vault://secret/data/runpod#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "rp-rotated", value)
+
+	// The last fetched value is kept while the store cannot be reached
+	server.Close()
+	value, err = r.Resolve(ctx, "vault://secret/data/runpod#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "rp-rotated", value)
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.Contains(r.Header.Get("Authorization"), "Credential=AKIDTEST/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.SecretId {
+		case "scriberr/hf":
+			w.Write([]byte(`{"Name":"scriberr/hf","SecretString":"hf-aws"}`))
+		case "scriberr/keys":
+			w.Write([]byte(`{"Name":"scriberr/keys","SecretString":"{\"openai\":\"sk-aws\"}"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL", server.URL)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	r := NewResolver(Config{})
+	ctx := context.Background()
+	value, err := r.Resolve(ctx, "aws-sm://scriberr/hf")
+	require.NoError(t, err)
+	assert.Equal(t, "hf-aws", value)
+	value, err = r.Resolve(ctx, "aws-sm://scriberr/keys#openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-aws", value)
+	_, err = r.Resolve(ctx, "aws-sm://scriberr/missing")
+	assert.ErrorContains(t, err, "can't find the specified secret")
+}
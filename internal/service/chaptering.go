@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"scriberr/internal/llm"
+)
+
+// chapterWindowChars bounds the transcript text sent to the LLM per request, so
+// long recordings are chaptered window by window
+const chapterWindowChars = 24000
+
+// Chapter is a topical section of a transcript
+type Chapter struct {
+	Title        string  `json:"title"`
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	FirstSegment int     `json:"first_segment"` // index into the transcript's segments
+	LastSegment  int     `json:"last_segment"`
+}
+
+// GenerateChapters splits a transcript into topical chapters with titles using the
+// given LLM. Chapters cover every segment, in order and without overlap.
+func GenerateChapters(ctx context.Context, svc llm.Service, model string, transcript *string) ([]Chapter, error) {
+	if transcript == nil {
+		return nil, errors.New("transcript is empty")
+	}
+	var parsed struct {
+		Segments []transcriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	segments := parsed.Segments
+	if len(segments) == 0 {
+		return nil, errors.New("transcript has no segments")
+	}
+
+	// Chapter boundaries, found window by window
+	var boundaries []chapterBoundary
+	for start := 0; start < len(segments); {
+		end := start
+		size := 0
+		for end < len(segments) && (end == start || size+len(segments[end].Text) <= chapterWindowChars) {
+			size += len(segments[end].Text)
+			end++
+		}
+
+		previous := ""
+		if len(boundaries) > 0 {
+			previous = boundaries[len(boundaries)-1].title
+		}
+		found, err := chapterBoundaries(ctx, svc, model, segments, start, end, previous)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range found {
+			if len(boundaries) > 0 && b.segment <= boundaries[len(boundaries)-1].segment {
+				continue
+			}
+			boundaries = append(boundaries, b)
+		}
+		start = end
+	}
+	if len(boundaries) == 0 {
+		return nil, errors.New("the LLM returned no chapters")
+	}
+	// The first chapter always opens the transcript
+	boundaries[0].segment = 0
+
+	chapters := make([]Chapter, len(boundaries))
+	for i, b := range boundaries {
+		last := len(segments) - 1
+		if i+1 < len(boundaries) {
+			last = boundaries[i+1].segment - 1
+		}
+		chapters[i] = Chapter{
+			Title:        b.title,
+			Start:        segments[b.segment].Start,
+			End:          segments[last].End,
+			FirstSegment: b.segment,
+			LastSegment:  last,
+		}
+	}
+	return chapters, nil
+}
+
+type chapterBoundary struct {
+	segment int
+	title   string
+}
+
+// chapterBoundaries asks the LLM where chapters start within segments[start:end]
+func chapterBoundaries(ctx context.Context, svc llm.Service, model string, segments []transcriptSegment, start, end int, previousTitle string) ([]chapterBoundary, error) {
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		seg := segments[i]
+		fmt.Fprintf(&b, "[%d] (%s) ", i, formatChapterTime(seg.Start))
+		if seg.Speaker != "" {
+			fmt.Fprintf(&b, "%s: ", seg.Speaker)
+		}
+		b.WriteString(strings.TrimSpace(seg.Text))
+		b.WriteString("\n")
+	}
+
+	continuation := fmt.Sprintf("Segment [%d] opens the first chapter.", start)
+	if previousTitle != "" {
+		continuation = fmt.Sprintf("These segments continue a transcript whose last chapter is titled %q; "+
+			"only start a chapter at segment [%d] if the topic changes there.", previousTitle, start)
+	}
+	prompt := fmt.Sprintf("Transcript segments:\n%s\nInstructions:\n"+
+		"Split the transcript into chapters where the topic changes, each spanning several minutes of conversation. %s "+
+		"Give each chapter a short descriptive title (at most eight words). "+
+		`Reply with only a JSON array of objects ordered by segment: {"segment": <number of the chapter's first segment>, "title": "<title>"}.`,
+		b.String(), continuation)
+
+	reply, err := complete(ctx, svc, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+	first := strings.Index(reply, "[")
+	last := strings.LastIndex(reply, "]")
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("expected a JSON array, got %q", reply)
+	}
+	var found []struct {
+		Segment int    `json:"segment"`
+		Title   string `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(reply[first:last+1]), &found); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of chapters: %w", err)
+	}
+
+	boundaries := make([]chapterBoundary, 0, len(found))
+	for _, f := range found {
+		title := strings.TrimSpace(f.Title)
+		if f.Segment < start || f.Segment >= end || title == "" {
+			continue
+		}
+		boundaries = append(boundaries, chapterBoundary{segment: f.Segment, title: title})
+	}
+	sort.SliceStable(boundaries, func(i, j int) bool { return boundaries[i].segment < boundaries[j].segment })
+	return boundaries, nil
+}
+
+func formatChapterTime(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total/60%60, total%60)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"scriberr/pkg/logger"
+)
+
+// errNonPublicAddress is returned when a download would connect to a loopback,
+// private or link-local address
+var errNonPublicAddress = errors.New("connection to a non-public address refused")
+
+// reservedPrefixes are the special-purpose ranges (RFC 6890 and its updates)
+// that net.IP has no predicate for: shared carrier-grade NAT space, protocol
+// assignments, benchmarking and documentation networks, and the IPv6
+// translation and tunnelling prefixes that can embed an internal IPv4 address
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("fec0::/10"),
+}
+
+// isPublicIP reports whether ip may be fetched from on a user's behalf
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicDialer connects only to public addresses. The check runs on the address
+// being connected to, after DNS resolution, so neither a DNS answer that changes
+// after the URL was validated nor a redirect can reach an internal service.
+var publicDialer = &net.Dialer{
+	Timeout: 30 * time.Second,
+	Control: func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+			return fmt.Errorf("%w: %s", errNonPublicAddress, host)
+		}
+		return nil
+	},
+}
+
+// hopHeaders are connection-specific and not forwarded by the proxy
+var hopHeaders = []string{
+	"Connection", "Proxy-Connection", "Keep-Alive", "Proxy-Authenticate",
+	"Proxy-Authorization", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// egressProxy is an HTTP proxy on the loopback interface through which yt-dlp
+// makes every request, so that each connection it opens, including those that
+// follow redirects, goes through publicDialer
+type egressProxy struct {
+	transport *http.Transport
+}
+
+// startEgressProxy serves an egress proxy until ctx is done and returns its URL
+func startEgressProxy(ctx context.Context) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to start download proxy: %w", err)
+	}
+	proxy := &egressProxy{transport: &http.Transport{
+		DialContext:           publicDialer.DialContext,
+		TLSHandshakeTimeout:   30 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+	}}
+	server := &http.Server{Handler: proxy, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("Download proxy stopped", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+		proxy.transport.CloseIdleConnections()
+	}()
+	return "http://" + listener.Addr().String(), nil
+}
+
+func (p *egressProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if r.URL.Scheme != "http" || r.URL.Host == "" {
+		http.Error(w, "only absolute http URLs can be proxied", http.StatusBadRequest)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	for _, header := range hopHeaders {
+		out.Header.Del(header)
+	}
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for _, header := range hopHeaders {
+		resp.Header.Del(header)
+	}
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// tunnel relays an HTTPS connection to its destination
+func (p *egressProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := publicDialer.DialContext(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		// Bytes the client sent after the CONNECT request may already be buffered
+		io.Copy(upstream, buffered)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+	client.Close()
+	upstream.Close()
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+const (
+	embeddingTickInterval = time.Minute
+	// embeddingJobsPerTick bounds how many transcripts are embedded per tick
+	embeddingJobsPerTick = 20
+	embeddingBatchSize   = 32
+	// maxChunkChars is the target size of a chunk; segments are never split
+	maxChunkChars = 1000
+)
+
+// ErrEmbeddingsUnavailable is returned when the active LLM provider cannot embed text
+var ErrEmbeddingsUnavailable = errors.New("the active LLM provider does not support embeddings")
+
+// EmbeddingIndexService keeps an embedding index of all completed transcripts and
+// retrieves the passages most relevant to a question
+type EmbeddingIndexService interface {
+	IndexPending(ctx context.Context) (int, error)
+	Retrieve(ctx context.Context, query string, opts RetrieveOptions) ([]RetrievedChunk, error)
+	Start()
+	Stop()
+}
+
+// RetrieveOptions limits a retrieval. From and To restrict it to transcriptions
+// created in that range.
+type RetrieveOptions struct {
+	Limit int
+	From  *time.Time
+	To    *time.Time
+}
+
+// RetrievedChunk is a transcript passage relevant to a query
+type RetrievedChunk struct {
+	JobID        string    `json:"job_id"`
+	Title        *string   `json:"title,omitempty"`
+	JobCreatedAt time.Time `json:"job_created_at"`
+	StartTime    float64   `json:"start_time"`
+	EndTime      float64   `json:"end_time"`
+	Text         string    `json:"text"`
+	Score        float64   `json:"score"`
+}
+
+type embeddingIndexService struct {
+	cfg           *config.Config
+	llmConfigRepo repository.LLMConfigRepository
+	embeddingRepo repository.EmbeddingRepository
+
+	// indexMu serialises indexing so a job is never embedded twice concurrently
+	indexMu sync.Mutex
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewEmbeddingIndexService(cfg *config.Config, llmConfigRepo repository.LLMConfigRepository, embeddingRepo repository.EmbeddingRepository) EmbeddingIndexService {
+	return &embeddingIndexService{
+		cfg:           cfg,
+		llmConfigRepo: llmConfigRepo,
+		embeddingRepo: embeddingRepo,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start launches the background indexer
+func (s *embeddingIndexService) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts the background indexer and waits for in-flight indexing to finish
+func (s *embeddingIndexService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *embeddingIndexService) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(embeddingTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if _, err := s.IndexPending(context.Background()); err != nil && !errors.Is(err, ErrEmbeddingsUnavailable) {
+				logger.Debug("Transcript embedding skipped", "error", err)
+			}
+		}
+	}
+}
+
+// embedder returns the active provider's embedder and the embedding model to use
+func (s *embeddingIndexService) embedder(ctx context.Context) (llm.Embedder, string, error) {
+	llmCfg, err := s.llmConfigRepo.GetActive(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("no active LLM configuration: %w", err)
+	}
+	svc, err := llm.NewFromConfig(llmCfg)
+	if err != nil {
+		return nil, "", err
+	}
+	embedder, ok := svc.(llm.Embedder)
+	if !ok {
+		return nil, "", ErrEmbeddingsUnavailable
+	}
+
+	model := s.cfg.EmbeddingModel
+	if model == "" {
+		model = llm.DefaultEmbeddingModel(llmCfg.Provider)
+	}
+	return embedder, model, nil
+}
+
+// IndexPending embeds transcripts that are new or changed since they were last
+// indexed and returns how many were indexed. Unscoped calls index every
+// organization's transcripts, each with its organization's provider.
+func (s *embeddingIndexService) IndexPending(ctx context.Context) (int, error) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+
+	if _, scoped := models.OrganizationFromContext(ctx); scoped {
+		return s.indexPending(ctx)
+	}
+	organizations, err := s.embeddingRepo.ListJobOrganizations(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list organizations to index: %w", err)
+	}
+	indexed := 0
+	var errs []error
+	for _, organizationID := range append([]string{""}, organizations...) {
+		count, err := s.indexPending(models.WithOrganization(ctx, organizationID))
+		indexed += count
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return indexed, errors.Join(errs...)
+}
+
+// indexPending indexes the pending transcripts of the organization ctx is scoped to
+func (s *embeddingIndexService) indexPending(ctx context.Context) (int, error) {
+	embedder, model, err := s.embedder(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	jobs, err := s.embeddingRepo.ListStaleJobs(ctx, model, embeddingJobsPerTick)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list jobs to index: %w", err)
+	}
+
+	indexed := 0
+	for _, job := range jobs {
+		chunks := chunkTranscript(job.Transcript)
+		if err := embedChunks(ctx, embedder, model, chunks); err != nil {
+			// Leave the job stale so it is retried on the next tick
+			return indexed, fmt.Errorf("failed to embed transcript of job %s: %w", job.ID, err)
+		}
+		if err := s.embeddingRepo.ReplaceChunks(ctx, job.ID, model, chunks); err != nil {
+			return indexed, fmt.Errorf("failed to store chunks of job %s: %w", job.ID, err)
+		}
+		indexed++
+	}
+
+	if indexed > 0 {
+		logger.Info("Indexed transcripts for workspace chat", "count", indexed, "model", model)
+	}
+	return indexed, nil
+}
+
+// Retrieve returns the indexed passages most similar to query, best first. Pending
+// transcripts are indexed first so recent jobs are included.
+func (s *embeddingIndexService) Retrieve(ctx context.Context, query string, opts RetrieveOptions) ([]RetrievedChunk, error) {
+	if _, err := s.IndexPending(ctx); err != nil {
+		if errors.Is(err, ErrEmbeddingsUnavailable) {
+			return nil, err
+		}
+		logger.Warn("Failed to index pending transcripts before retrieval", "error", err)
+	}
+
+	embedder, model, err := s.embedder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := embedder.Embed(ctx, model, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	chunks, err := s.embeddingRepo.ListChunks(ctx, model, opts.From, opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chunks: %w", err)
+	}
+
+	results := make([]RetrievedChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		results = append(results, RetrievedChunk{
+			JobID:        chunk.JobID,
+			Title:        chunk.Title,
+			JobCreatedAt: chunk.JobCreatedAt,
+			StartTime:    chunk.StartTime,
+			EndTime:      chunk.EndTime,
+			Text:         chunk.Text,
+			Score:        cosineSimilarity(vectors[0], chunk.Vector),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+// chunkTranscript groups consecutive segments into chunks of about maxChunkChars,
+// prefixing each line with its speaker
+func chunkTranscript(transcript *string) []models.TranscriptChunk {
+	if transcript == nil {
+		return nil
+	}
+	var parsed struct {
+		Segments []struct {
+			Start   float64 `json:"start"`
+			End     float64 `json:"end"`
+			Text    string  `json:"text"`
+			Speaker string  `json:"speaker"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return nil
+	}
+
+	var chunks []models.TranscriptChunk
+	var b strings.Builder
+	var start, end float64
+	flush := func() {
+		if b.Len() == 0 {
+			return
+		}
+		chunks = append(chunks, models.TranscriptChunk{
+			ChunkIndex: len(chunks),
+			StartTime:  start,
+			EndTime:    end,
+			Text:       strings.TrimSpace(b.String()),
+		})
+		b.Reset()
+	}
+
+	for _, seg := range parsed.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if seg.Speaker != "" {
+			text = seg.Speaker + ": " + text
+		}
+		if b.Len() > 0 && b.Len()+len(text) > maxChunkChars {
+			flush()
+		}
+		if b.Len() == 0 {
+			start = seg.Start
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+		end = seg.End
+	}
+	flush()
+	return chunks
+}
+
+// embedChunks fills in the vectors of chunks in batches
+func embedChunks(ctx context.Context, embedder llm.Embedder, model string, chunks []models.TranscriptChunk) error {
+	for i := 0; i < len(chunks); i += embeddingBatchSize {
+		end := i + embeddingBatchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+		inputs := make([]string, 0, end-i)
+		for _, chunk := range chunks[i:end] {
+			inputs = append(inputs, chunk.Text)
+		}
+		vectors, err := embedder.Embed(ctx, model, inputs)
+		if err != nil {
+			return err
+		}
+		for j, vector := range vectors {
+			chunks[i+j].Vector = vector
+		}
+	}
+	return nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag keys attached to jobs created from feed episodes
+const (
+	FeedTagSubscriptionID = "feed-subscription-id"
+	FeedTagEpisodeGUID    = "feed-episode-guid"
+	FeedTagEpisodeTitle   = "feed-episode-title"
+	FeedTagPublishedAt    = "feed-episode-published-at"
+)
+
+const (
+	feedFetchTimeout = 30 * time.Second
+	feedMaxBodySize  = 20 << 20 // 20MB
+	feedTickInterval = time.Minute
+)
+
+// FeedService manages RSS/podcast feed subscriptions and polls them for new
+// episodes, which are downloaded and transcribed with the subscription's profile
+type FeedService interface {
+	Subscribe(ctx context.Context, sub *models.FeedSubscription) error
+	Get(ctx context.Context, id string) (*models.FeedSubscription, error)
+	List(ctx context.Context) ([]models.FeedSubscription, error)
+	Update(ctx context.Context, sub *models.FeedSubscription) error
+	Delete(ctx context.Context, id string) error
+	ListEpisodes(ctx context.Context, id string) ([]models.FeedEpisode, error)
+	Poll(ctx context.Context, id string) (int, error)
+	Start()
+	Stop()
+}
+
+type feedService struct {
+	cfg         *config.Config
+	feedRepo    repository.FeedRepository
+	profileRepo repository.ProfileRepository
+	urlIngest   URLIngestService
+	client      *http.Client
+	// validateURL checks feed URLs before they are fetched
+	validateURL func(string) error
+
+	// pollMu serialises polls so scheduled and manual polls cannot queue an episode twice
+	pollMu sync.Mutex
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewFeedService(cfg *config.Config, feedRepo repository.FeedRepository, profileRepo repository.ProfileRepository, urlIngest URLIngestService) FeedService {
+	return &feedService{
+		cfg:         cfg,
+		feedRepo:    feedRepo,
+		profileRepo: profileRepo,
+		urlIngest:   urlIngest,
+		client: &http.Client{
+			Timeout:   feedFetchTimeout,
+			Transport: &http.Transport{DialContext: publicDialer.DialContext},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				return ValidateSourceURL(req.URL.String())
+			},
+		},
+		validateURL: ValidateSourceURL,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Subscribe validates and stores a new subscription, then polls it in the background
+func (s *feedService) Subscribe(ctx context.Context, sub *models.FeedSubscription) error {
+	sub.URL = strings.TrimSpace(sub.URL)
+	if err := s.validateURL(sub.URL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSourceURL, err)
+	}
+	if sub.ID == "" {
+		sub.ID = uuid.New().String()
+	}
+	if sub.PollIntervalMinutes <= 0 {
+		sub.PollIntervalMinutes = s.cfg.FeedPollIntervalMinutes
+	}
+	sub.Active = true
+	sub.LastPolledAt = nil
+
+	if err := s.feedRepo.Create(ctx, sub); err != nil {
+		return fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	go func(id string) {
+		if _, err := s.Poll(context.Background(), id); err != nil {
+			logger.Warn("Initial feed poll failed", "subscription_id", id, "error", err)
+		}
+	}(sub.ID)
+	return nil
+}
+
+// Get returns a subscription, as not found if its workspace is not visible to ctx
+func (s *feedService) Get(ctx context.Context, id string) (*models.FeedSubscription, error) {
+	sub, err := s.feedRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !models.WorkspaceVisible(ctx, sub.Workspace) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return sub, nil
+}
+
+func (s *feedService) List(ctx context.Context) ([]models.FeedSubscription, error) {
+	return s.feedRepo.ListVisible(ctx)
+}
+
+func (s *feedService) Update(ctx context.Context, sub *models.FeedSubscription) error {
+	sub.URL = strings.TrimSpace(sub.URL)
+	if err := s.validateURL(sub.URL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSourceURL, err)
+	}
+	if sub.PollIntervalMinutes <= 0 {
+		sub.PollIntervalMinutes = s.cfg.FeedPollIntervalMinutes
+	}
+	return s.feedRepo.Update(ctx, sub)
+}
+
+func (s *feedService) Delete(ctx context.Context, id string) error {
+	return s.feedRepo.DeleteSubscription(ctx, id)
+}
+
+func (s *feedService) ListEpisodes(ctx context.Context, id string) ([]models.FeedEpisode, error) {
+	return s.feedRepo.ListEpisodes(ctx, id)
+}
+
+// Start launches the background poller
+func (s *feedService) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts the background poller and waits for an in-flight poll to finish
+func (s *feedService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *feedService) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(feedTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.pollDue()
+		}
+	}
+}
+
+// pollDue polls every active subscription whose interval has elapsed
+func (s *feedService) pollDue() {
+	subs, err := s.feedRepo.ListActive(context.Background())
+	if err != nil {
+		logger.Error("Failed to list feed subscriptions", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		if sub.LastPolledAt != nil && now.Sub(*sub.LastPolledAt) < time.Duration(sub.PollIntervalMinutes)*time.Minute {
+			continue
+		}
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		if _, err := s.Poll(context.Background(), sub.ID); err != nil {
+			logger.Warn("Feed poll failed", "subscription_id", sub.ID, "url", sub.URL, "error", err)
+		}
+	}
+}
+
+// Poll fetches the feed and submits a job for each unseen episode. It returns
+// the number of episodes queued for transcription.
+func (s *feedService) Poll(ctx context.Context, id string) (int, error) {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+
+	sub, err := s.feedRepo.FindByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	firstPoll := sub.LastPolledAt == nil
+	now := time.Now()
+	sub.LastPolledAt = &now
+
+	feed, fetchErr := s.fetch(ctx, sub.URL)
+	if fetchErr != nil {
+		errMsg := fetchErr.Error()
+		sub.LastError = &errMsg
+		if err := s.feedRepo.Update(ctx, sub); err != nil {
+			logger.Error("Failed to update feed subscription", "subscription_id", sub.ID, "error", err)
+		}
+		return 0, fetchErr
+	}
+	sub.LastError = nil
+	if sub.Title == "" {
+		sub.Title = strings.TrimSpace(feed.Channel.Title)
+	}
+
+	queued, seen := 0, 0
+	for _, item := range feed.episodes() {
+		guid := item.guid()
+		if guid == "" {
+			continue
+		}
+		exists, err := s.feedRepo.HasEpisode(ctx, sub.ID, guid)
+		if err != nil {
+			return queued, fmt.Errorf("failed to check episode: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		episode := models.FeedEpisode{
+			SubscriptionID: sub.ID,
+			GUID:           guid,
+			Title:          strings.TrimSpace(item.Title),
+			PublishedAt:    item.publishedAt(),
+			EnclosureURL:   strings.TrimSpace(item.Enclosure.URL),
+		}
+
+		// On the first poll only the newest BackfillCount episodes are transcribed
+		seen++
+		if firstPoll && seen > sub.BackfillCount {
+			episode.Skipped = true
+		} else {
+			jobID, err := s.submitEpisode(ctx, sub, &episode)
+			if errors.Is(err, ErrQuotaExceeded) {
+				// Left unrecorded like any failed episode, so they are picked up
+				// once the quota resets
+				logger.Info("Feed workspace is out of quota, deferring episodes", "subscription_id", sub.ID)
+				break
+			}
+			if err != nil {
+				// Not recorded, so the next poll retries it
+				logger.Warn("Failed to submit feed episode", "subscription_id", sub.ID, "guid", guid, "error", err)
+				continue
+			}
+			episode.JobID = &jobID
+			queued++
+		}
+
+		if err := s.feedRepo.CreateEpisode(ctx, &episode); err != nil {
+			logger.Error("Failed to record feed episode", "subscription_id", sub.ID, "guid", guid, "error", err)
+		}
+	}
+
+	if err := s.feedRepo.Update(ctx, sub); err != nil {
+		logger.Error("Failed to update feed subscription", "subscription_id", sub.ID, "error", err)
+	}
+
+	if queued > 0 {
+		logger.Info("Queued new feed episodes", "subscription_id", sub.ID, "feed", sub.Title, "count", queued)
+	}
+	return queued, nil
+}
+
+// submitEpisode creates a URL ingest job for the episode enclosure, tagged with the episode metadata
+func (s *feedService) submitEpisode(ctx context.Context, sub *models.FeedSubscription, episode *models.FeedEpisode) (string, error) {
+	job := models.TranscriptionJob{
+		ID:        uuid.New().String(),
+		Workspace: sub.Workspace,
+	}
+	if episode.Title != "" {
+		title := episode.Title
+		job.Title = &title
+	}
+	if profile := s.profile(ctx, sub); profile != nil {
+		job.Parameters = profile.Parameters
+		job.ProfileID = &profile.ID
+		job.ProfileVersion = &profile.Version
+	}
+	// Episodes are single audio files
+	job.Parameters.IsMultiTrackEnabled = false
+	job.Diarization = job.Parameters.Diarize
+
+	tags := []models.TagEntry{
+		{Key: FeedTagSubscriptionID, Value: sub.ID},
+		{Key: FeedTagEpisodeGUID, Value: episode.GUID},
+		{Key: FeedTagEpisodeTitle, Value: episode.Title},
+	}
+	if episode.PublishedAt != nil {
+		tags = append(tags, models.TagEntry{Key: FeedTagPublishedAt, Value: episode.PublishedAt.UTC().Format(time.RFC3339)})
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	tagsJSON := string(data)
+	job.Tags = &tagsJSON
+
+	if err := s.urlIngest.Submit(ctx, &job, episode.EnclosureURL); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// profile returns the subscription's profile, falling back to the default profile
+func (s *feedService) profile(ctx context.Context, sub *models.FeedSubscription) *models.TranscriptionProfile {
+	if sub.ProfileID != nil && *sub.ProfileID != "" {
+		profile, err := s.profileRepo.FindByID(ctx, *sub.ProfileID)
+		if err == nil {
+			return profile
+		}
+		logger.Warn("Feed subscription profile not found, using default", "subscription_id", sub.ID, "profile_id", *sub.ProfileID)
+	}
+	profile, _ := s.profileRepo.FindDefault(ctx)
+	return profile
+}
+
+func (s *feedService) fetch(ctx context.Context, feedURL string) (*rssFeed, error) {
+	if err := s.validateURL(feedURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/xml;q=0.9, */*;q=0.8")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch feed: HTTP %d", resp.StatusCode)
+	}
+
+	var feed rssFeed
+	decoder := xml.NewDecoder(io.LimitReader(resp.Body, feedMaxBodySize))
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// Most feeds are UTF-8; ISO-8859-1 and friends decode well enough for titles
+		return input, nil
+	}
+	if err := decoder.Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	return &feed, nil
+}
+
+type rssFeed struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title     string `xml:"title"`
+	GUID      string `xml:"guid"`
+	PubDate   string `xml:"pubDate"`
+	Enclosure struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+// episodes returns the items with a media enclosure, newest first. Items
+// without a readable date follow the dated ones in feed order.
+func (f *rssFeed) episodes() []rssItem {
+	var items []rssItem
+	for _, item := range f.Channel.Items {
+		if strings.TrimSpace(item.Enclosure.URL) != "" {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].publishedAt(), items[j].publishedAt()
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.After(*b)
+	})
+	return items
+}
+
+// guid identifies an episode, falling back to the enclosure URL for feeds without GUIDs
+func (i rssItem) guid() string {
+	if guid := strings.TrimSpace(i.GUID); guid != "" {
+		return guid
+	}
+	return strings.TrimSpace(i.Enclosure.URL)
+}
+
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+func (i rssItem) publishedAt() *time.Time {
+	value := strings.TrimSpace(i.PubDate)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range rssDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingIngest records the enclosures feed polls submit
+type recordingIngest struct {
+	mu   sync.Mutex
+	jobs []models.TranscriptionJob
+	urls []string
+}
+
+func (r *recordingIngest) Submit(ctx context.Context, job *models.TranscriptionJob, sourceURL string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, *job)
+	r.urls = append(r.urls, sourceURL)
+	return nil
+}
+
+func (r *recordingIngest) SubmitTracks(ctx context.Context, job *models.TranscriptionJob, downloads []TrackDownload) error {
+	return errors.New("feeds do not submit multi-track jobs")
+}
+
+func newTestFeedService(t *testing.T, feedXML *string) (*feedService, *recordingIngest, *httptest.Server) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.FeedSubscription{}, &models.FeedEpisode{}, &models.TranscriptionProfile{}, &models.ProfileVersion{}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, *feedXML)
+	}))
+	t.Cleanup(server.Close)
+
+	ingest := &recordingIngest{}
+	svc := NewFeedService(&config.Config{FeedPollIntervalMinutes: 60}, repository.NewFeedRepository(db), repository.NewProfileRepository(db), ingest).(*feedService)
+	// The test server listens on loopback, which the production checks refuse
+	svc.client = server.Client()
+	svc.validateURL = func(string) error { return nil }
+	return svc, ingest, server
+}
+
+func rss(items ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Test Podcast</title>` +
+		strings.Join(items, "") + `</channel></rss>`
+}
+
+func feedItemXML(guid, title, pubDate string) string {
+	item := "<item><title>" + title + "</title>"
+	if guid != "" {
+		item += "<guid>" + guid + "</guid>"
+	}
+	if pubDate != "" {
+		item += "<pubDate>" + pubDate + "</pubDate>"
+	}
+	return item + `<enclosure url="https://cdn.example.com/` + title + `.mp3" type="audio/mpeg"/></item>`
+}
+
+func TestFeedEpisodeOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []string
+		expected []string
+	}{
+		{
+			name: "NewestFirst",
+			items: []string{
+				feedItemXML("a", "old", "Mon, 01 Jan 2024 10:00:00 +0000"),
+				feedItemXML("b", "new", "Wed, 03 Jan 2024 10:00:00 +0000"),
+				feedItemXML("c", "mid", "Tue, 02 Jan 2024 10:00:00 +0000"),
+			},
+			expected: []string{"new", "mid", "old"},
+		},
+		{
+			name: "UndatedLastInFeedOrder",
+			items: []string{
+				feedItemXML("a", "undated1", ""),
+				feedItemXML("b", "old", "Mon, 01 Jan 2024 10:00:00 +0000"),
+				feedItemXML("c", "undated2", "not a date"),
+				feedItemXML("d", "new", "2024-01-05T10:00:00Z"),
+			},
+			expected: []string{"new", "old", "undated1", "undated2"},
+		},
+		{
+			name: "MixedDateLayouts",
+			items: []string{
+				feedItemXML("a", "rfc1123", "Tue, 02 Jan 2024 10:00:00 GMT"),
+				feedItemXML("b", "shortday", "Wed, 3 Jan 2024 10:00:00 +0000"),
+				feedItemXML("c", "noweekday", "1 Jan 2024 10:00:00 +0000"),
+			},
+			expected: []string{"shortday", "rfc1123", "noweekday"},
+		},
+		{
+			name: "ItemsWithoutEnclosureSkipped",
+			items: []string{
+				"<item><title>text post</title><guid>x</guid></item>",
+				feedItemXML("a", "episode", ""),
+			},
+			expected: []string{"episode"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var feed rssFeed
+			require.NoError(t, parseTestFeed(rss(tt.items...), &feed))
+			var titles []string
+			for _, item := range feed.episodes() {
+				titles = append(titles, item.Title)
+			}
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+}
+
+func TestFeedEpisodeGUID(t *testing.T) {
+	tests := []struct {
+		item     rssItem
+		expected string
+	}{
+		{rssItem{GUID: " tag:example.com,2024:1 "}, "tag:example.com,2024:1"},
+		{func() rssItem {
+			var item rssItem
+			item.Enclosure.URL = "https://cdn.example.com/1.mp3"
+			return item
+		}(), "https://cdn.example.com/1.mp3"},
+		{rssItem{}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.item.guid())
+	}
+}
+
+func TestFeedPoll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("BackfillOnFirstPoll", func(t *testing.T) {
+		feedXML := rss(
+			feedItemXML("ep1", "one", "Mon, 01 Jan 2024 10:00:00 +0000"),
+			feedItemXML("ep2", "two", "Tue, 02 Jan 2024 10:00:00 +0000"),
+			feedItemXML("ep3", "three", "Wed, 03 Jan 2024 10:00:00 +0000"),
+		)
+		svc, ingest, server := newTestFeedService(t, &feedXML)
+		sub := &models.FeedSubscription{ID: "sub-1", URL: server.URL, BackfillCount: 2, Workspace: "user-7", Active: true}
+		require.NoError(t, svc.feedRepo.Create(ctx, sub))
+
+		queued, err := svc.Poll(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, queued)
+		assert.Equal(t, []string{"https://cdn.example.com/three.mp3", "https://cdn.example.com/two.mp3"}, ingest.urls)
+		for _, job := range ingest.jobs {
+			assert.Equal(t, "user-7", job.Workspace)
+		}
+
+		episodes, err := svc.ListEpisodes(ctx, sub.ID)
+		require.NoError(t, err)
+		require.Len(t, episodes, 3)
+		skipped := map[string]bool{}
+		for _, episode := range episodes {
+			skipped[episode.GUID] = episode.Skipped
+		}
+		assert.Equal(t, map[string]bool{"ep1": true, "ep2": false, "ep3": false}, skipped)
+
+		stored, err := svc.Get(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Podcast", stored.Title)
+		assert.NotNil(t, stored.LastPolledAt)
+	})
+
+	t.Run("DedupeByGUID", func(t *testing.T) {
+		feedXML := rss(feedItemXML("ep1", "one", "Mon, 01 Jan 2024 10:00:00 +0000"))
+		svc, ingest, server := newTestFeedService(t, &feedXML)
+		sub := &models.FeedSubscription{ID: "sub-2", URL: server.URL, BackfillCount: 10, Active: true}
+		require.NoError(t, svc.feedRepo.Create(ctx, sub))
+
+		queued, err := svc.Poll(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, queued)
+
+		// A re-poll of the same feed queues nothing; a new episode is queued
+		// regardless of the backfill count, which only applies to the first poll
+		queued, err = svc.Poll(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, queued)
+
+		feedXML = rss(
+			feedItemXML("ep1", "one", "Mon, 01 Jan 2024 10:00:00 +0000"),
+			feedItemXML("ep2", "two", "Tue, 02 Jan 2024 10:00:00 +0000"),
+		)
+		queued, err = svc.Poll(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, queued)
+		assert.Len(t, ingest.urls, 2)
+	})
+
+	t.Run("EnclosureURLAsGUID", func(t *testing.T) {
+		feedXML := rss(feedItemXML("", "noguid", ""))
+		svc, _, server := newTestFeedService(t, &feedXML)
+		sub := &models.FeedSubscription{ID: "sub-3", URL: server.URL, BackfillCount: 1, Active: true}
+		require.NoError(t, svc.feedRepo.Create(ctx, sub))
+
+		_, err := svc.Poll(ctx, sub.ID)
+		require.NoError(t, err)
+		episodes, err := svc.ListEpisodes(ctx, sub.ID)
+		require.NoError(t, err)
+		require.Len(t, episodes, 1)
+		assert.Equal(t, "https://cdn.example.com/noguid.mp3", episodes[0].GUID)
+	})
+
+	t.Run("FetchErrorRecorded", func(t *testing.T) {
+		feedXML := "not xml"
+		svc, ingest, server := newTestFeedService(t, &feedXML)
+		sub := &models.FeedSubscription{ID: "sub-4", URL: server.URL, Active: true}
+		require.NoError(t, svc.feedRepo.Create(ctx, sub))
+
+		_, err := svc.Poll(ctx, sub.ID)
+		assert.Error(t, err)
+		assert.Empty(t, ingest.urls)
+		stored, err := svc.Get(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.LastError)
+	})
+
+	t.Run("WorkspaceScoping", func(t *testing.T) {
+		feedXML := rss()
+		svc, _, server := newTestFeedService(t, &feedXML)
+		require.NoError(t, svc.feedRepo.Create(ctx, &models.FeedSubscription{ID: "org-feed", URL: server.URL, Workspace: models.OrganizationWorkspace("acme")}))
+		require.NoError(t, svc.feedRepo.Create(ctx, &models.FeedSubscription{ID: "other-feed", URL: server.URL, Workspace: models.OrganizationWorkspace("globex")}))
+
+		acme := models.WithOrganization(ctx, "acme")
+		subs, err := svc.List(acme)
+		require.NoError(t, err)
+		require.Len(t, subs, 1)
+		assert.Equal(t, "org-feed", subs[0].ID)
+
+		_, err = svc.Get(acme, "other-feed")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func parseTestFeed(data string, feed *rssFeed) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, data)
+	}))
+	defer server.Close()
+	svc := &feedService{client: server.Client(), validateURL: func(string) error { return nil }}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	parsed, err := svc.fetch(ctx, server.URL)
+	if err != nil {
+		return err
+	}
+	*feed = *parsed
+	return nil
+}
@@ -2,20 +2,28 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
+	"scriberr/internal/models"
+	"scriberr/pkg/tracing"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 )
 
 // FileService handles file system operations
@@ -27,13 +35,108 @@ type FileService interface {
 	ReadFile(path string) ([]byte, error)
 	FileExists(path string) (bool, error)
 	DownloadFile(ctx context.Context, url string, saveTo string) error
+	PresignURL(ctx context.Context, url string, ttl time.Duration) (string, error)
+	ObjectExists(ctx context.Context, url string) (bool, error)
+	UploadObject(ctx context.Context, url string, body io.ReadSeeker, storageClass string) error
+	WalkObjects(ctx context.Context, prefixURL string, fn func(ObjectInfo) error) error
+	// DownloadCacheUsage returns the number and total size of the downloaded files still on disk
+	DownloadCacheUsage() (int, int64)
+	// CleanDownloadCache deletes the downloaded files older than maxAge, except
+	// those inUse reports, and returns how many it deleted and their total size
+	CleanDownloadCache(maxAge time.Duration, inUse func(path string) bool) (int, int64)
+	// Downloading reports whether a download to path is being written
+	Downloading(path string) bool
+	ScopePath(root, workspace, path string) (string, error)
+	WorkspaceUsage(root, workspace string) (int64, error)
+}
+
+// Uploads and outputs are partitioned by workspace: each workspace keeps its files
+// under <root>/workspaces/<name>. Files created before workspaces existed stay
+// directly under root and belong to the empty workspace.
+const workspacesDirName = "workspaces"
+
+// ErrPathOutsideWorkspace is returned when a path is not inside its workspace directory
+var ErrPathOutsideWorkspace = errors.New("path is outside the workspace")
+
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// WorkspaceDir returns the directory under root holding a workspace's files
+func WorkspaceDir(root, workspace string) (string, error) {
+	if workspace == "" {
+		return filepath.Clean(root), nil
+	}
+	if !workspaceNamePattern.MatchString(workspace) {
+		return "", fmt.Errorf("invalid workspace name %q", workspace)
+	}
+	return filepath.Join(root, workspacesDirName, workspace), nil
+}
+
+// ScopePath resolves path and checks that it lies inside the workspace's directory
+// under root, so a job can only address files of its own workspace. Symlinks are
+// resolved first, so links cannot be used to escape the workspace.
+func ScopePath(root, workspace, path string) (string, error) {
+	dir, err := WorkspaceDir(root, workspace)
+	if err != nil {
+		return "", err
+	}
+	absDir, err := resolvePath(dir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := resolvePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !withinDir(absDir, absPath) {
+		return "", ErrPathOutsideWorkspace
+	}
+	// The legacy workspace is the root itself, which must not reach into named workspaces
+	if workspace == "" && withinDir(filepath.Join(absDir, workspacesDirName), absPath) {
+		return "", ErrPathOutsideWorkspace
+	}
+	return absPath, nil
+}
+
+// resolvePath returns the absolute path with symlinks resolved as far as the
+// path exists, rejecting dangling symlinks
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+	// A link whose target cannot be resolved could be followed anywhere once
+	// its target is created
+	if info, err := os.Lstat(abs); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return "", fmt.Errorf("%w: dangling symlink %s", ErrPathOutsideWorkspace, filepath.Base(abs))
+	}
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		return filepath.Join(resolved, filepath.Base(abs)), nil
+	}
+	return abs, nil
+}
+
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 type fileService struct {
 	s3Client *s3.Client
 
-	// downloadedFiles stores the absolute file path and the time it was created
+	// downloads coalesces concurrent downloads of the same URL
+	downloads singleflight.Group
+
+	// downloadedFiles stores the absolute file path and the time it was created;
+	// writing counts the downloads being written to each path
 	downloadedFiles    map[string]time.Time
+	writing            map[string]int
 	downloadedFilesMux sync.Mutex
 }
 
@@ -115,81 +218,295 @@ func (s *fileService) FileExists(path string) (bool, error) {
 	return false, err
 }
 
+func (s *fileService) ScopePath(root, workspace, path string) (string, error) {
+	return ScopePath(root, workspace, path)
+}
+
+// WorkspaceUsage returns the total size in bytes of the files in a workspace
+func (s *fileService) WorkspaceUsage(root, workspace string) (int64, error) {
+	dir, err := WorkspaceDir(root, workspace)
+	if err != nil {
+		return 0, err
+	}
+	legacyExclude := filepath.Join(dir, workspacesDirName)
+
+	var total int64
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if workspace == "" && path == legacyExclude {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// downloadTimeout bounds a shared download, which no caller can cancel
+const downloadTimeout = time.Hour
+
+// downloadClient fetches HTTP(S) URLs for DownloadFile
+var downloadClient = &http.Client{Timeout: downloadTimeout}
+
+// DownloadFile downloads an s3:// or HTTP(S) URL to saveTo. Concurrent
+// downloads of the same URL, such as audio playback while a worker fetches
+// the same object, share one download, and the file only appears at saveTo
+// once complete.
 func (s *fileService) DownloadFile(ctx context.Context, url string, saveTo string) error {
-	if strings.HasPrefix(url, "s3://") {
-		return s.downloadS3File(ctx, url, saveTo)
+	saveTo = filepath.Clean(saveTo)
+	// The download is detached from the first caller, so one caller giving up
+	// does not fail the others, but still ends within downloadTimeout
+	shared := context.WithoutCancel(ctx)
+	result := s.downloads.DoChan(url, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(shared, downloadTimeout)
+		defer cancel()
+		return saveTo, s.download(ctx, url, saveTo)
+	})
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return res.Err
+		}
+		// A caller saving the URL elsewhere gets its own copy
+		if path := res.Val.(string); path != saveTo {
+			return s.copyDownload(path, saveTo)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	// Download using HTTP/HTTPS
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// download writes url to a temporary file next to saveTo, then moves it into place
+func (s *fileService) download(ctx context.Context, url string, saveTo string) error {
+	body, err := s.open(ctx, url)
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		return err
 	}
+	defer body.Close()
+	return s.place(body, saveTo)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// copyDownload copies a file downloaded by another caller to saveTo
+func (s *fileService) copyDownload(path, saveTo string) error {
+	f, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
+		return fmt.Errorf("failed to open downloaded file: %w", err)
 	}
-	defer resp.Body.Close()
+	defer f.Close()
+	return s.place(f, saveTo)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+// place writes r to a temporary file next to saveTo, then moves it into place
+func (s *fileService) place(r io.Reader, saveTo string) error {
+	s.startWriting(saveTo)
+	defer s.stopWriting(saveTo)
 
-	// Create the output file
-	outFile, err := os.Create(saveTo)
+	tmp, err := os.CreateTemp(filepath.Dir(saveTo), "."+filepath.Base(saveTo)+".*.part")
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer outFile.Close()
+	defer os.Remove(tmp.Name())
 
-	_, err = io.Copy(outFile, resp.Body)
-	if err != nil {
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	if err := os.Rename(tmp.Name(), saveTo); err != nil {
+		return fmt.Errorf("failed to move downloaded file: %w", err)
+	}
 
 	s.saveDownloadedFiles(saveTo)
-
 	return nil
 }
 
-func (s *fileService) downloadS3File(ctx context.Context, url string, saveTo string) error {
-	trimmed := strings.TrimPrefix(url, "s3://")
-	parts := strings.SplitN(trimmed, "/", 2)
-	if len(parts) != 2 {
-		return fmt.Errorf("invalid S3 URI format: %s", url)
+// open starts reading an s3:// or HTTP(S) URL
+func (s *fileService) open(ctx context.Context, url string) (io.ReadCloser, error) {
+	if strings.HasPrefix(url, "s3://") {
+		return s.openS3Object(ctx, url)
+	}
+
+	// Download using HTTP/HTTPS
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
-	bucket := parts[0]
-	key := parts[1]
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
 
-	result, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+// PresignURL returns a time-limited HTTPS URL for an s3:// object
+func (s *fileService) PresignURL(ctx context.Context, url string, ttl time.Duration) (string, error) {
+	bucket, key, err := parseS3URI(url)
+	if err != nil {
+		return "", err
+	}
+	req, err := s3.NewPresignClient(s.s3Client).PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
-	})
+	}, s3.WithPresignExpires(ttl))
 	if err != nil {
-		return fmt.Errorf("failed to download from S3: %w", err)
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
 	}
-	defer result.Body.Close()
+	return req.URL, nil
+}
 
-	// Create the output file
-	outFile, err := os.Create(saveTo)
+// ObjectExists reports whether an s3:// object exists
+func (s *fileService) ObjectExists(ctx context.Context, url string) (bool, error) {
+	bucket, key, err := parseS3URI(url)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return false, err
+	}
+	ctx, span := tracing.StartKind(ctx, "s3.HeadObject", tracing.KindClient,
+		tracing.String("aws.s3.bucket", bucket), tracing.String("aws.s3.key", key))
+	defer span.End()
+	_, err = s.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
 	}
-	defer outFile.Close()
+	span.RecordError(err)
+	return false, fmt.Errorf("failed to check S3 object: %w", err)
+}
 
-	_, err = outFile.ReadFrom(result.Body)
+// UploadObject writes body to an s3:// object, in storageClass when it is set
+func (s *fileService) UploadObject(ctx context.Context, url string, body io.ReadSeeker, storageClass string) error {
+	bucket, key, err := parseS3URI(url)
 	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return err
+	}
+	ctx, span := tracing.StartKind(ctx, "s3.PutObject", tracing.KindClient,
+		tracing.String("aws.s3.bucket", bucket), tracing.String("aws.s3.key", key))
+	defer span.End()
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if storageClass != "" {
+		input.StorageClass = types.StorageClass(storageClass)
+	}
+	if _, err := s.s3Client.PutObject(ctx, input); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to upload S3 object: %w", err)
 	}
+	return nil
+}
 
-	s.saveDownloadedFiles(saveTo)
+// ObjectInfo describes an S3 object found by WalkObjects
+type ObjectInfo struct {
+	URI  string
+	Size int64
+	ETag string
+}
 
+// WalkObjects calls fn for every object under an s3://bucket/prefix URI, one
+// listing page at a time, and stops at the first error fn returns
+func (s *fileService) WalkObjects(ctx context.Context, prefixURL string, fn func(ObjectInfo) error) error {
+	if !strings.HasPrefix(prefixURL, "s3://") {
+		return fmt.Errorf("invalid S3 URI format: %s", prefixURL)
+	}
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(prefixURL, "s3://"), "/")
+	if bucket == "" {
+		return fmt.Errorf("invalid S3 URI format: %s", prefixURL)
+	}
+	paginator := s3.NewListObjectsV2Paginator(s.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		pageCtx, span := tracing.StartKind(ctx, "s3.ListObjectsV2", tracing.KindClient,
+			tracing.String("aws.s3.bucket", bucket), tracing.String("aws.s3.prefix", prefix))
+		page, err := paginator.NextPage(pageCtx)
+		if err != nil {
+			span.RecordError(err)
+			span.End()
+			return fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+		span.End()
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			info := ObjectInfo{
+				URI:  "s3://" + bucket + "/" + key,
+				Size: aws.ToInt64(obj.Size),
+				ETag: strings.Trim(aws.ToString(obj.ETag), `"`),
+			}
+			if err := fn(info); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// TranscriptObjectName is the key a job's transcript is uploaded under in its
+// output bucket
+func TranscriptObjectName(job *models.TranscriptionJob) string {
+	if job.Title != nil {
+		return *job.Title + ".json"
+	}
+	return job.ID + ".json"
+}
+
+func parseS3URI(url string) (string, string, error) {
+	trimmed := strings.TrimPrefix(url, "s3://")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if !strings.HasPrefix(url, "s3://") || len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid S3 URI format: %s", url)
+	}
+	return parts[0], parts[1], nil
+}
+
+func (s *fileService) openS3Object(ctx context.Context, url string) (io.ReadCloser, error) {
+	bucket, key, err := parseS3URI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.StartKind(ctx, "s3.GetObject", tracing.KindClient,
+		tracing.String("aws.s3.bucket", bucket), tracing.String("aws.s3.key", key))
+	defer span.End()
+	result, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to download from S3: %w", err)
+	}
+	return result.Body, nil
+}
+
 func (s *fileService) saveDownloadedFiles(saveTo string) {
 	s.downloadedFilesMux.Lock()
 	if s.downloadedFiles == nil {
@@ -209,26 +526,65 @@ func (s *fileService) startDownloadedFilesCleanup() {
 	ticker := time.NewTicker(interval)
 	go func() {
 		for range ticker.C {
-			now := time.Now()
-			var toDelete []string
-
-			// Gather candidates under lock
-			s.downloadedFilesMux.Lock()
-			for path, createdAt := range s.downloadedFiles {
-				if now.Sub(createdAt) > maxAge {
-					toDelete = append(toDelete, path)
-				}
-			}
-			s.downloadedFilesMux.Unlock()
-
-			// Delete files and prune map
-			s.downloadedFilesMux.Lock()
-			for _, path := range toDelete {
-				// Attempt to remove file; ignore error if it's already gone
-				_ = os.Remove(path)
-				delete(s.downloadedFiles, path)
-			}
-			s.downloadedFilesMux.Unlock()
+			s.CleanDownloadCache(maxAge, nil)
 		}
 	}()
 }
+
+func (s *fileService) startWriting(path string) {
+	s.downloadedFilesMux.Lock()
+	if s.writing == nil {
+		s.writing = make(map[string]int)
+	}
+	s.writing[path]++
+	s.downloadedFilesMux.Unlock()
+}
+
+func (s *fileService) stopWriting(path string) {
+	s.downloadedFilesMux.Lock()
+	if s.writing[path]--; s.writing[path] <= 0 {
+		delete(s.writing, path)
+	}
+	s.downloadedFilesMux.Unlock()
+}
+
+func (s *fileService) Downloading(path string) bool {
+	s.downloadedFilesMux.Lock()
+	defer s.downloadedFilesMux.Unlock()
+	return s.writing[filepath.Clean(path)] > 0
+}
+
+func (s *fileService) DownloadCacheUsage() (int, int64) {
+	s.downloadedFilesMux.Lock()
+	defer s.downloadedFilesMux.Unlock()
+	files, total := 0, int64(0)
+	for path := range s.downloadedFiles {
+		if info, err := os.Stat(path); err == nil {
+			files++
+			total += info.Size()
+		}
+	}
+	return files, total
+}
+
+func (s *fileService) CleanDownloadCache(maxAge time.Duration, inUse func(path string) bool) (int, int64) {
+	s.downloadedFilesMux.Lock()
+	defer s.downloadedFilesMux.Unlock()
+	now := time.Now()
+	files, reclaimed := 0, int64(0)
+	for path, createdAt := range s.downloadedFiles {
+		if now.Sub(createdAt) < maxAge || s.writing[path] > 0 || (inUse != nil && inUse(path)) {
+			continue
+		}
+		// Files already gone are only pruned from the map
+		if info, err := os.Stat(path); err == nil {
+			if err := os.Remove(path); err != nil {
+				continue
+			}
+			files++
+			reclaimed += info.Size()
+		}
+		delete(s.downloadedFiles, path)
+	}
+	return files, reclaimed
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadFileCoalescesConcurrentDownloads(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("first half "))
+		w.(http.Flusher).Flush()
+		<-release
+		w.Write([]byte("second half"))
+	}))
+	defer server.Close()
+
+	svc := &fileService{downloadedFiles: make(map[string]time.Time)}
+	saveTo := filepath.Join(t.TempDir(), "audio.wav")
+
+	// A caller that gives up does not fail the download for the others
+	impatient, cancel := context.WithCancel(context.Background())
+	impatientErr := make(chan error, 1)
+	go func() { impatientErr <- svc.DownloadFile(impatient, server.URL, saveTo) }()
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = svc.DownloadFile(context.Background(), server.URL, saveTo)
+		}(i)
+	}
+	cancel()
+	assert.ErrorIs(t, <-impatientErr, context.Canceled)
+
+	// Nothing is at the path until the download completes
+	_, err := os.Stat(saveTo)
+	assert.True(t, os.IsNotExist(err))
+
+	// Let the callers join the running download before it finishes
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+	data, err := os.ReadFile(saveTo)
+	require.NoError(t, err)
+	assert.Equal(t, "first half second half", string(data))
+
+	entries, err := os.ReadDir(filepath.Dir(saveTo))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the partial file is moved into place")
+}
+
+func TestDownloadFileSharesDownloadAcrossPaths(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+
+	svc := &fileService{downloadedFiles: make(map[string]time.Time)}
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "playback.wav"), filepath.Join(dir, "worker.wav")}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(paths))
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			errs[i] = svc.DownloadFile(context.Background(), server.URL, path)
+		}(i, path)
+		if i == 0 {
+			require.Eventually(t, func() bool { return requests.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+	for i, path := range paths {
+		require.NoError(t, errs[i])
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "audio", string(data))
+	}
+}
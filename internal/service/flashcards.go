@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+)
+
+// Flashcard is a question and answer, or a phrase and its meaning, drawn from a transcript
+type Flashcard struct {
+	Front string  `json:"front"`
+	Back  string  `json:"back"`
+	Start float64 `json:"start"` // where the card's content is heard, in seconds
+}
+
+// FlashcardOptions controls flashcard extraction
+type FlashcardOptions struct {
+	Count int // maximum number of cards
+	// Language, when set, turns the cards into vocabulary cards: the front quotes a
+	// phrase from the transcript and the back translates it into Language.
+	// Otherwise the cards are comprehension questions in the transcript's language.
+	Language string
+}
+
+// ExtractFlashcards asks the LLM for up to opts.Count flashcards covering the
+// transcript. Long transcripts are handled window by window, each window getting
+// a share of the cards proportional to its length.
+func ExtractFlashcards(ctx context.Context, svc llm.Service, model string, transcript *string, opts FlashcardOptions) ([]Flashcard, error) {
+	if transcript == nil {
+		return nil, errors.New("transcript is empty")
+	}
+	if opts.Count <= 0 {
+		return nil, errors.New("card count must be positive")
+	}
+	var parsed struct {
+		Segments []transcriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	segments := parsed.Segments
+	if len(segments) == 0 {
+		return nil, errors.New("transcript has no segments")
+	}
+
+	total := 0
+	for _, seg := range segments {
+		total += len(seg.Text)
+	}
+
+	cards := make([]Flashcard, 0, opts.Count)
+	for start := 0; start < len(segments) && len(cards) < opts.Count; {
+		end := start
+		size := 0
+		for end < len(segments) && (end == start || size+len(segments[end].Text) <= chapterWindowChars) {
+			size += len(segments[end].Text)
+			end++
+		}
+
+		quota := opts.Count
+		if total > 0 {
+			quota = (opts.Count*size + total - 1) / total
+		}
+		quota = max(1, min(quota, opts.Count-len(cards)))
+
+		found, err := flashcardsForWindow(ctx, svc, model, segments, start, end, quota, opts.Language)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, found...)
+		start = end
+	}
+	if len(cards) == 0 {
+		return nil, errors.New("the LLM returned no flashcards")
+	}
+	if len(cards) > opts.Count {
+		cards = cards[:opts.Count]
+	}
+	return cards, nil
+}
+
+// flashcardsForWindow asks the LLM for up to count cards from segments[start:end]
+func flashcardsForWindow(ctx context.Context, svc llm.Service, model string, segments []transcriptSegment, start, end, count int, language string) ([]Flashcard, error) {
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		fmt.Fprintf(&b, "[%d] %s\n", i, strings.TrimSpace(segments[i].Text))
+	}
+
+	task := "Write comprehension flashcards about the important facts, terms and ideas in the transcript: " +
+		`"front" is a question and "back" its short answer, both in the transcript's language.`
+	if language != "" {
+		task = fmt.Sprintf("Write vocabulary flashcards for a language learner: "+
+			`"front" quotes a useful word, phrase or short sentence exactly as it appears in the transcript, and "back" translates it into %s. `+
+			"Prefer idiomatic or uncommon expressions over basic words.", language)
+	}
+	prompt := fmt.Sprintf("Transcript segments:\n%s\nInstructions:\n"+
+		"%s Write at most %d cards and do not repeat yourself. "+
+		`Reply with only a JSON array of objects: {"front": "<front>", "back": "<back>", "segment": <number of the segment the card comes from>}.`,
+		b.String(), task, count)
+
+	reply, err := complete(ctx, svc, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+	first := strings.Index(reply, "[")
+	last := strings.LastIndex(reply, "]")
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("expected a JSON array, got %q", reply)
+	}
+	var found []struct {
+		Front   string `json:"front"`
+		Back    string `json:"back"`
+		Segment int    `json:"segment"`
+	}
+	if err := json.Unmarshal([]byte(reply[first:last+1]), &found); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of flashcards: %w", err)
+	}
+
+	cards := make([]Flashcard, 0, min(len(found), count))
+	for _, f := range found {
+		front := strings.TrimSpace(f.Front)
+		back := strings.TrimSpace(f.Back)
+		if front == "" || back == "" {
+			continue
+		}
+		segment := f.Segment
+		if segment < start || segment >= end {
+			segment = start
+		}
+		cards = append(cards, Flashcard{Front: front, Back: back, Start: segments[segment].Start})
+		if len(cards) == count {
+			break
+		}
+	}
+	return cards, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+// Kinds of inconsistency found by the integrity check
+const (
+	IntegrityMissingAudio        = "missing_audio"
+	IntegrityMissingS3Audio      = "missing_s3_audio"
+	IntegrityMissingS3Transcript = "missing_s3_transcript"
+	IntegrityOrphanedProcessing  = "orphaned_processing"
+)
+
+// Actions the integrity check took on an inconsistency
+const (
+	IntegrityActionFailed   = "failed"
+	IntegrityActionRequeued = "requeued"
+	IntegrityActionFlagged  = "flagged"
+)
+
+// integrityS3Timeout bounds each S3 lookup
+const integrityS3Timeout = 10 * time.Second
+
+// IntegrityIssue is an inconsistency between a job and its files
+type IntegrityIssue struct {
+	Kind   string `json:"kind"`
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+	Path   string `json:"path,omitempty"`
+	Action string `json:"action"`
+}
+
+// IntegrityReport is the outcome of an integrity check
+type IntegrityReport struct {
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  time.Time        `json:"finished_at"`
+	JobsChecked int              `json:"jobs_checked"`
+	Issues      []IntegrityIssue `json:"issues"`
+	// Lookups that failed, so the files they were for could not be checked
+	Errors []string `json:"errors"`
+}
+
+// IntegrityQueue is the subset of the task queue the integrity check needs
+type IntegrityQueue interface {
+	JobEnqueuer
+	IsJobRunning(jobID string) bool
+}
+
+// IntegrityService checks jobs against their files. Unfinished jobs whose audio
+// is gone are failed, processing jobs no worker is running are queued again,
+// and completed jobs missing a file are flagged in the report for an admin.
+type IntegrityService interface {
+	Run(ctx context.Context) (*IntegrityReport, error)
+	LastReport() *IntegrityReport
+}
+
+type integrityService struct {
+	jobRepo     repository.JobRepository
+	fileService FileService
+	queue       IntegrityQueue
+
+	// runMu serialises checks so a job is never repaired twice
+	runMu sync.Mutex
+	mu    sync.RWMutex
+	last  *IntegrityReport
+}
+
+func NewIntegrityService(jobRepo repository.JobRepository, fileService FileService, queue IntegrityQueue) IntegrityService {
+	return &integrityService{jobRepo: jobRepo, fileService: fileService, queue: queue}
+}
+
+// LastReport returns the report of the latest check, or nil before the first
+func (s *integrityService) LastReport() *IntegrityReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Run checks every job with audio and repairs what it can
+func (s *integrityService) Run(ctx context.Context) (*IntegrityReport, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	report := &IntegrityReport{StartedAt: time.Now(), Issues: []IntegrityIssue{}, Errors: []string{}}
+	jobs, err := s.jobRepo.ListForIntegrityCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.JobsChecked = len(jobs)
+
+	for i := range jobs {
+		s.checkJob(ctx, &jobs[i], report)
+	}
+	report.FinishedAt = time.Now()
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	if len(report.Issues) > 0 || len(report.Errors) > 0 {
+		logger.Warn("Integrity check found problems", "jobs", report.JobsChecked, "issues", len(report.Issues), "errors", len(report.Errors))
+	} else {
+		logger.Info("Integrity check passed", "jobs", report.JobsChecked)
+	}
+	return report, nil
+}
+
+func (s *integrityService) checkJob(ctx context.Context, job *models.TranscriptionJob, report *IntegrityReport) {
+	if kind, path, ok := s.missingAudio(ctx, job, report); ok {
+		action := IntegrityActionFlagged
+		if job.Status != models.StatusCompleted {
+			// Without its audio the job can never run
+			if s.fail(ctx, job.ID, "Audio file missing: "+path, report) {
+				action = IntegrityActionFailed
+			}
+		}
+		report.Issues = append(report.Issues, IntegrityIssue{Kind: kind, JobID: job.ID, Status: string(job.Status), Path: path, Action: action})
+		return
+	}
+
+	if job.Status == models.StatusProcessing && !s.queue.IsJobRunning(job.ID) {
+		action := IntegrityActionFlagged
+		if s.requeue(ctx, job.ID, report) {
+			action = IntegrityActionRequeued
+		}
+		report.Issues = append(report.Issues, IntegrityIssue{Kind: IntegrityOrphanedProcessing, JobID: job.ID, Status: string(job.Status), Action: action})
+	}
+
+	if job.Status == models.StatusCompleted && job.OutputBucketName != nil && *job.OutputBucketName != "" && job.Transcript != nil {
+		uri := "s3://" + *job.OutputBucketName + "/" + TranscriptObjectName(job)
+		if exists, ok := s.objectExists(ctx, uri, report); ok && !exists {
+			report.Issues = append(report.Issues, IntegrityIssue{Kind: IntegrityMissingS3Transcript, JobID: job.ID, Status: string(job.Status), Path: uri, Action: IntegrityActionFlagged})
+		}
+	}
+}
+
+// missingAudio returns the first of the job's audio files that is gone
+func (s *integrityService) missingAudio(ctx context.Context, job *models.TranscriptionJob, report *IntegrityReport) (string, string, bool) {
+	if job.AudioExpiredAt != nil {
+		// Removed by a retention policy
+		return "", "", false
+	}
+	if job.AudioUri != nil && strings.HasPrefix(*job.AudioUri, "s3://") {
+		// The local copy is only kept while the job runs
+		if exists, ok := s.objectExists(ctx, *job.AudioUri, report); ok && !exists {
+			return IntegrityMissingS3Audio, *job.AudioUri, true
+		}
+		return "", "", false
+	}
+
+	paths := []string{job.AudioPath}
+	if job.IsMultiTrack {
+		paths = paths[:0]
+		for _, track := range job.MultiTrackFiles {
+			paths = append(paths, track.FilePath)
+		}
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		exists, err := s.fileService.FileExists(path)
+		if err != nil {
+			report.Errors = append(report.Errors, job.ID+": "+err.Error())
+			continue
+		}
+		if !exists {
+			return IntegrityMissingAudio, path, true
+		}
+	}
+	return "", "", false
+}
+
+func (s *integrityService) objectExists(ctx context.Context, uri string, report *IntegrityReport) (exists bool, ok bool) {
+	ctx, cancel := context.WithTimeout(ctx, integrityS3Timeout)
+	defer cancel()
+	exists, err := s.fileService.ObjectExists(ctx, uri)
+	if err != nil {
+		report.Errors = append(report.Errors, uri+": "+err.Error())
+		return false, false
+	}
+	return exists, true
+}
+
+// fail marks a job failed, reloading it so the update does not clear the
+// columns the check did not load
+func (s *integrityService) fail(ctx context.Context, jobID, message string, report *IntegrityReport) bool {
+	job, err := s.jobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		report.Errors = append(report.Errors, jobID+": "+err.Error())
+		return false
+	}
+	job.Status = models.StatusFailed
+	job.ErrorMessage = &message
+	job.ErrorCode = models.ErrorTranscriptionFailed
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		report.Errors = append(report.Errors, jobID+": "+err.Error())
+		return false
+	}
+	return true
+}
+
+// requeue puts a job back in the queue; the pending-job scanner picks it up
+// even if enqueueing fails
+func (s *integrityService) requeue(ctx context.Context, jobID string, report *IntegrityReport) bool {
+	job, err := s.jobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		report.Errors = append(report.Errors, jobID+": "+err.Error())
+		return false
+	}
+	message := "Job interrupted by server restart"
+	job.Status = models.StatusPending
+	job.ErrorMessage = &message
+	job.ErrorCode = models.ErrorJobInterrupted
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		report.Errors = append(report.Errors, jobID+": "+err.Error())
+		return false
+	}
+	if err := s.queue.EnqueueJob(jobID); err != nil {
+		logger.Warn("Failed to enqueue repaired job", "job_id", jobID, "error", err)
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeObjectStore answers S3 lookups from a set of existing objects
+type fakeObjectStore struct {
+	FileService
+	objects map[string]bool
+}
+
+func (f *fakeObjectStore) FileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *fakeObjectStore) ObjectExists(ctx context.Context, url string) (bool, error) {
+	if url == "s3://broken/audio.wav" {
+		return false, errors.New("access denied")
+	}
+	return f.objects[url], nil
+}
+
+// fakeIntegrityQueue runs the jobs listed in running and records enqueued ones
+type fakeIntegrityQueue struct {
+	mu       sync.Mutex
+	running  map[string]bool
+	enqueued []string
+}
+
+func (q *fakeIntegrityQueue) EnqueueJob(jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.enqueued = append(q.enqueued, jobID)
+	return nil
+}
+
+func (q *fakeIntegrityQueue) IsJobRunning(jobID string) bool {
+	return q.running[jobID]
+}
+
+func TestIntegrityCheck(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.MultiTrackFile{}))
+
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.wav")
+	require.NoError(t, os.WriteFile(present, []byte("audio"), 0644))
+	missing := filepath.Join(dir, "missing.wav")
+	s3URI := func(key string) *string { uri := "s3://bucket/" + key; return &uri }
+	bucket := "out"
+	title := "Weekly sync"
+	transcript := `{"text":"hi"}`
+
+	jobs := []models.TranscriptionJob{
+		{ID: "ok", Status: models.StatusCompleted, AudioPath: present},
+		{ID: "pending-missing", Status: models.StatusPending, AudioPath: missing},
+		{ID: "completed-missing", Status: models.StatusCompleted, AudioPath: missing, Transcript: &transcript},
+		{ID: "failed-missing", Status: models.StatusFailed, AudioPath: missing},
+		{ID: "orphaned", Status: models.StatusProcessing, AudioPath: present},
+		{ID: "running", Status: models.StatusProcessing, AudioPath: present},
+		{ID: "s3-missing", Status: models.StatusUploaded, AudioPath: missing, AudioUri: s3URI("gone.wav")},
+		{ID: "s3-present", Status: models.StatusPending, AudioPath: missing, AudioUri: s3URI("here.wav")},
+		{ID: "s3-unreachable", Status: models.StatusPending, AudioPath: missing, AudioUri: func() *string { uri := "s3://broken/audio.wav"; return &uri }()},
+		{ID: "transcript-missing", Status: models.StatusCompleted, AudioPath: present, OutputBucketName: &bucket, Title: &title, Transcript: &transcript},
+		{ID: "transcript-present", Status: models.StatusCompleted, AudioPath: present, OutputBucketName: &bucket, Transcript: &transcript},
+		{ID: "tracks-missing", Status: models.StatusPending, IsMultiTrack: true, MultiTrackFiles: []models.MultiTrackFile{
+			{FileName: "a.wav", FilePath: present, TrackIndex: 0},
+			{FileName: "b.wav", FilePath: missing, TrackIndex: 1},
+		}},
+	}
+	for i := range jobs {
+		require.NoError(t, db.Create(&jobs[i]).Error)
+	}
+
+	jobRepo := repository.NewJobRepository(db)
+	queue := &fakeIntegrityQueue{running: map[string]bool{"running": true}}
+	files := &fakeObjectStore{objects: map[string]bool{"s3://bucket/here.wav": true, "s3://out/transcript-present.json": true}}
+	svc := NewIntegrityService(jobRepo, files, queue)
+	assert.Nil(t, svc.LastReport())
+
+	report, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, report, svc.LastReport())
+	assert.Equal(t, 11, report.JobsChecked, "failed jobs are not checked")
+
+	issues := map[string]IntegrityIssue{}
+	for _, issue := range report.Issues {
+		issues[issue.JobID] = issue
+	}
+	assert.Len(t, issues, 6)
+	assert.Equal(t, IntegrityIssue{Kind: IntegrityMissingAudio, JobID: "pending-missing", Status: "pending", Path: missing, Action: IntegrityActionFailed}, issues["pending-missing"])
+	assert.Equal(t, IntegrityIssue{Kind: IntegrityMissingAudio, JobID: "completed-missing", Status: "completed", Path: missing, Action: IntegrityActionFlagged}, issues["completed-missing"])
+	assert.Equal(t, IntegrityIssue{Kind: IntegrityOrphanedProcessing, JobID: "orphaned", Status: "processing", Action: IntegrityActionRequeued}, issues["orphaned"])
+	assert.Equal(t, IntegrityIssue{Kind: IntegrityMissingS3Audio, JobID: "s3-missing", Status: "uploaded", Path: "s3://bucket/gone.wav", Action: IntegrityActionFailed}, issues["s3-missing"])
+	assert.Equal(t, IntegrityIssue{Kind: IntegrityMissingS3Transcript, JobID: "transcript-missing", Status: "completed", Path: "s3://out/Weekly sync.json", Action: IntegrityActionFlagged}, issues["transcript-missing"])
+	assert.Equal(t, IntegrityIssue{Kind: IntegrityMissingAudio, JobID: "tracks-missing", Status: "pending", Path: missing, Action: IntegrityActionFailed}, issues["tracks-missing"])
+	assert.Equal(t, []string{"s3://broken/audio.wav: access denied"}, report.Errors)
+
+	status := func(id string) models.JobStatus {
+		job, err := jobRepo.FindByID(context.Background(), id)
+		require.NoError(t, err)
+		return job.Status
+	}
+	assert.Equal(t, models.StatusFailed, status("pending-missing"))
+	assert.Equal(t, models.StatusFailed, status("s3-missing"))
+	assert.Equal(t, models.StatusFailed, status("tracks-missing"))
+	assert.Equal(t, models.StatusCompleted, status("completed-missing"))
+	assert.Equal(t, models.StatusPending, status("orphaned"))
+	assert.Equal(t, models.StatusProcessing, status("running"))
+	assert.Equal(t, models.StatusPending, status("s3-unreachable"), "unchecked jobs are left alone")
+	assert.Equal(t, []string{"orphaned"}, queue.enqueued)
+
+	// Repairing keeps what the check did not load
+	job, err := jobRepo.FindByID(context.Background(), "completed-missing")
+	require.NoError(t, err)
+	require.NotNil(t, job.Transcript)
+	assert.Equal(t, transcript, *job.Transcript)
+
+	// A second run only reports what is still wrong
+	report, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 8, report.JobsChecked)
+	kinds := map[string]string{}
+	for _, issue := range report.Issues {
+		kinds[issue.JobID] = issue.Kind
+	}
+	assert.Equal(t, map[string]string{"completed-missing": IntegrityMissingAudio, "transcript-missing": IntegrityMissingS3Transcript}, kinds)
+}
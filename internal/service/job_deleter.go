@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+// JobDeleter deletes jobs with their files and related records
+type JobDeleter interface {
+	// Delete removes the job's uploaded files, its chats, notes, summaries,
+	// speaker mappings, executions, shadow runs, tracks and tag assignments,
+	// and then the job
+	Delete(ctx context.Context, job *models.TranscriptionJob) error
+	// RemoveAudio removes the job's uploaded audio files, keeping the job
+	RemoveAudio(job *models.TranscriptionJob)
+}
+
+type jobDeleter struct {
+	uploadDir          string
+	fileService        FileService
+	jobRepo            repository.JobRepository
+	chatRepo           repository.ChatRepository
+	noteRepo           repository.NoteRepository
+	summaryRepo        repository.SummaryRepository
+	speakerMappingRepo repository.SpeakerMappingRepository
+}
+
+func NewJobDeleter(uploadDir string, fileService FileService, jobRepo repository.JobRepository, chatRepo repository.ChatRepository,
+	noteRepo repository.NoteRepository, summaryRepo repository.SummaryRepository, speakerMappingRepo repository.SpeakerMappingRepository) JobDeleter {
+	return &jobDeleter{
+		uploadDir:          uploadDir,
+		fileService:        fileService,
+		jobRepo:            jobRepo,
+		chatRepo:           chatRepo,
+		noteRepo:           noteRepo,
+		summaryRepo:        summaryRepo,
+		speakerMappingRepo: speakerMappingRepo,
+	}
+}
+
+func (d *jobDeleter) Delete(ctx context.Context, job *models.TranscriptionJob) error {
+	d.RemoveAudio(job)
+
+	// Related records are deleted one by one for legacy databases without
+	// cascading constraints; failures are logged and the rest still removed
+	jobID := job.ID
+	if err := d.chatRepo.DeleteByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete chat sessions", "job_id", jobID, "error", err)
+	}
+	if err := d.noteRepo.DeleteByTranscriptionID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete notes", "job_id", jobID, "error", err)
+	}
+	if err := d.summaryRepo.DeleteByTranscriptionID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete summaries", "job_id", jobID, "error", err)
+	}
+	if err := d.speakerMappingRepo.DeleteByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete speaker mappings", "job_id", jobID, "error", err)
+	}
+	if err := d.jobRepo.DeleteExecutionsByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete job executions", "job_id", jobID, "error", err)
+	}
+	if err := d.jobRepo.DeleteShadowRunsByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete shadow runs", "job_id", jobID, "error", err)
+	}
+	if err := d.jobRepo.DeleteTranscriptRevisionsByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete transcript revisions", "job_id", jobID, "error", err)
+	}
+	if err := d.jobRepo.DeleteMultiTrackFilesByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete multi-track file records", "job_id", jobID, "error", err)
+	}
+	if err := d.jobRepo.DeleteTagAssignmentsByJobID(ctx, jobID); err != nil {
+		logger.Warn("Failed to delete tag assignments", "job_id", jobID, "error", err)
+	}
+	return d.jobRepo.Delete(ctx, jobID)
+}
+
+func (d *jobDeleter) RemoveAudio(job *models.TranscriptionJob) {
+	if job.IsMultiTrack && job.MultiTrackFolder != nil {
+		d.removePath(job, *job.MultiTrackFolder, true)
+	} else {
+		d.removePath(job, job.AudioPath, false)
+	}
+	if job.MergedAudioPath != nil {
+		d.removePath(job, *job.MergedAudioPath, false)
+	}
+	if job.AupFilePath != nil {
+		d.removePath(job, *job.AupFilePath, false)
+	}
+	if job.VideoPath != nil {
+		d.removePath(job, *job.VideoPath, false)
+	}
+}
+
+// removePath deletes a file or directory of job if it lies inside the job's
+// upload workspace
+func (d *jobDeleter) removePath(job *models.TranscriptionJob, path string, recursive bool) {
+	if path == "" {
+		return
+	}
+	scoped, err := d.fileService.ScopePath(d.uploadDir, job.Workspace, path)
+	if err != nil {
+		logger.Warn("Refusing to delete file outside the job's workspace", "job_id", job.ID, "path", path, "error", err)
+		return
+	}
+	if recursive {
+		d.fileService.RemoveDirectory(scoped)
+	} else {
+		d.fileService.RemoveFile(scoped)
+	}
+}
@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag keys attached to jobs created by library imports
+const (
+	LibraryImportTagID     = "library-import-id"
+	LibraryImportTagSource = "library-import-source"
+)
+
+const (
+	// libraryImportProgressEvery is how many files pass between progress saves
+	libraryImportProgressEvery = 25
+	// libraryImportPendingPoll is how often a throttled import checks the queue
+	libraryImportPendingPoll = 5 * time.Second
+)
+
+// ErrInvalidImportSource is returned for import sources that cannot be walked
+var ErrInvalidImportSource = errors.New("invalid import source")
+
+// libraryImportExtensions are the files an import creates jobs for
+var libraryImportExtensions = map[string]bool{
+	".mp3": true, ".wav": true, ".flac": true, ".m4a": true, ".aac": true, ".ogg": true,
+	".opus": true, ".wma": true, ".mp4": true, ".mov": true, ".mkv": true, ".webm": true,
+}
+
+// LibraryImportService walks an S3 prefix or a local directory in the
+// background and creates a job for every audio file found, skipping files
+// already imported and throttling how fast jobs are queued
+type LibraryImportService interface {
+	// Start validates and records imp, then runs it in the background
+	Start(ctx context.Context, imp *models.LibraryImport) error
+	Get(ctx context.Context, id string) (*models.LibraryImport, error)
+	List(ctx context.Context) ([]models.LibraryImport, error)
+	ListFiles(ctx context.Context, id, status string, offset, limit int) ([]models.LibraryImportFile, int64, error)
+	// Cancel stops a running import; the jobs it queued are kept
+	Cancel(ctx context.Context, id string) error
+	// Stop cancels the running imports and waits for them to finish
+	Stop()
+}
+
+type libraryImportService struct {
+	cfg         *config.Config
+	importRepo  repository.LibraryImportRepository
+	jobRepo     repository.JobRepository
+	profileRepo repository.ProfileRepository
+	fileService FileService
+	queue       JobEnqueuer
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewLibraryImportService(cfg *config.Config, importRepo repository.LibraryImportRepository, jobRepo repository.JobRepository,
+	profileRepo repository.ProfileRepository, fileService FileService, queue JobEnqueuer) LibraryImportService {
+	s := &libraryImportService{
+		cfg:         cfg,
+		importRepo:  importRepo,
+		jobRepo:     jobRepo,
+		profileRepo: profileRepo,
+		fileService: fileService,
+		queue:       queue,
+		cancels:     make(map[string]context.CancelFunc),
+	}
+	s.failInterrupted()
+	return s
+}
+
+// failInterrupted marks imports left running by a previous process as failed;
+// running them again skips what they already queued
+func (s *libraryImportService) failInterrupted() {
+	imports, err := s.importRepo.ListRunning(context.Background())
+	if err != nil {
+		logger.Warn("Failed to list interrupted library imports", "error", err)
+		return
+	}
+	for i := range imports {
+		s.finish(&imports[i], errors.New("interrupted by a server restart"))
+	}
+}
+
+func (s *libraryImportService) Start(ctx context.Context, imp *models.LibraryImport) error {
+	source, err := s.resolveSource(imp.Source)
+	if err != nil {
+		return err
+	}
+	imp.Source = source
+	imp.ID = uuid.New().String()
+	imp.Status = models.LibraryImportRunning
+	if imp.RatePerMinute <= 0 {
+		imp.RatePerMinute = s.cfg.LibraryImportRatePerMinute
+	}
+	if err := s.importRepo.Create(ctx, imp); err != nil {
+		return fmt.Errorf("failed to create import: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancels[imp.ID] = cancel
+	s.mu.Unlock()
+	s.wg.Add(1)
+	go func(imp models.LibraryImport) {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.cancels, imp.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+		s.run(runCtx, &imp)
+	}(*imp)
+	return nil
+}
+
+// resolveSource checks that source is an s3:// prefix or a directory under
+// the configured import directory, and returns it in canonical form
+func (s *libraryImportService) resolveSource(source string) (string, error) {
+	source = strings.TrimSpace(source)
+	if strings.HasPrefix(source, "s3://") {
+		if bucket, _, _ := strings.Cut(strings.TrimPrefix(source, "s3://"), "/"); bucket == "" {
+			return "", fmt.Errorf("%w: S3 sources need a bucket", ErrInvalidImportSource)
+		}
+		return source, nil
+	}
+	if s.cfg.LibraryImportDir == "" {
+		return "", fmt.Errorf("%w: local imports are disabled, set LIBRARY_IMPORT_DIR", ErrInvalidImportSource)
+	}
+	root, err := resolvePath(s.cfg.LibraryImportDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidImportSource, err)
+	}
+	if !filepath.IsAbs(source) {
+		source = filepath.Join(root, source)
+	}
+	dir, err := resolvePath(source)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidImportSource, err)
+	}
+	if !withinDir(root, dir) {
+		return "", fmt.Errorf("%w: %s is outside the import directory", ErrInvalidImportSource, source)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%w: %s is not a directory", ErrInvalidImportSource, source)
+	}
+	return dir, nil
+}
+
+func (s *libraryImportService) Get(ctx context.Context, id string) (*models.LibraryImport, error) {
+	return s.importRepo.FindByID(ctx, id)
+}
+
+func (s *libraryImportService) List(ctx context.Context) ([]models.LibraryImport, error) {
+	return s.importRepo.List(ctx)
+}
+
+func (s *libraryImportService) ListFiles(ctx context.Context, id, status string, offset, limit int) ([]models.LibraryImportFile, int64, error) {
+	if _, err := s.importRepo.FindByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.importRepo.ListFiles(ctx, id, status, offset, limit)
+}
+
+func (s *libraryImportService) Cancel(ctx context.Context, id string) error {
+	if _, err := s.importRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	cancel, ok := s.cancels[id]
+	s.mu.Unlock()
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	cancel()
+	return nil
+}
+
+func (s *libraryImportService) Stop() {
+	s.mu.Lock()
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// importFile is an audio file found in an import's source
+type importFile struct {
+	source string
+	size   int64
+	// etag of S3 objects; local files are hashed when checked for duplicates
+	etag string
+}
+
+func (s *libraryImportService) run(ctx context.Context, imp *models.LibraryImport) {
+	logger.Info("Library import started", "import_id", imp.ID, "source", imp.Source, "dry_run", imp.DryRun)
+	run := &importRun{
+		service: s,
+		imp:     imp,
+		profile: s.profile(ctx, imp),
+		seen:    make(map[string]bool),
+	}
+	if imp.RatePerMinute > 0 {
+		run.interval = time.Minute / time.Duration(imp.RatePerMinute)
+	}
+
+	var err error
+	if strings.HasPrefix(imp.Source, "s3://") {
+		err = s.fileService.WalkObjects(ctx, imp.Source, func(obj ObjectInfo) error {
+			return run.add(ctx, importFile{source: obj.URI, size: obj.Size, etag: obj.ETag})
+		})
+	} else {
+		err = filepath.WalkDir(imp.Source, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				logger.Warn("Skipping unreadable import path", "import_id", imp.ID, "path", p, "error", err)
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			return run.add(ctx, importFile{source: p, size: info.Size()})
+		})
+	}
+	s.finish(imp, err)
+}
+
+// finish records how an import ended
+func (s *libraryImportService) finish(imp *models.LibraryImport, err error) {
+	now := time.Now()
+	imp.FinishedAt = &now
+	switch {
+	case err == nil:
+		imp.Status = models.LibraryImportCompleted
+	case errors.Is(err, context.Canceled):
+		imp.Status = models.LibraryImportCancelled
+	default:
+		imp.Status = models.LibraryImportFailed
+		msg := err.Error()
+		imp.Error = &msg
+	}
+	if err := s.importRepo.Update(context.Background(), imp); err != nil {
+		logger.Error("Failed to update library import", "import_id", imp.ID, "error", err)
+	}
+	logger.Info("Library import finished", "import_id", imp.ID, "status", imp.Status,
+		"found", imp.FilesFound, "queued", imp.Queued, "duplicates", imp.Duplicates, "failed", imp.Failed)
+}
+
+// profile returns the import's profile, falling back to the default profile
+func (s *libraryImportService) profile(ctx context.Context, imp *models.LibraryImport) *models.TranscriptionProfile {
+	if imp.ProfileID != nil && *imp.ProfileID != "" {
+		if profile, err := s.profileRepo.FindByID(ctx, *imp.ProfileID); err == nil {
+			return profile
+		}
+		logger.Warn("Library import profile not found, using default", "import_id", imp.ID, "profile_id", *imp.ProfileID)
+	}
+	profile, _ := s.profileRepo.FindDefault(ctx)
+	return profile
+}
+
+// importRun holds the state of one running import
+type importRun struct {
+	service *libraryImportService
+	imp     *models.LibraryImport
+	profile *models.TranscriptionProfile
+	// seen holds the fingerprints found so far, so a dry run reports copies
+	// within the source as duplicates too
+	seen map[string]bool
+	// interval is the least time between queued jobs; 0 is unthrottled
+	interval  time.Duration
+	lastQueue time.Time
+}
+
+// add records a file found in the source and queues a job for it
+func (r *importRun) add(ctx context.Context, file importFile) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !libraryImportExtensions[strings.ToLower(path.Ext(file.source))] {
+		return nil
+	}
+	s, imp := r.service, r.imp
+	imp.FilesFound++
+	record := models.LibraryImportFile{ImportID: imp.ID, Workspace: imp.Workspace, Source: file.source, Size: file.size}
+
+	status, jobID, err := r.process(ctx, file, &record)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrQuotaExceeded):
+		imp.FilesFound--
+		return err
+	case err != nil:
+		record.Status = models.LibraryImportFileFailed
+		record.Error = err.Error()
+		imp.Failed++
+		logger.Warn("Failed to import file", "import_id", imp.ID, "source", file.source, "error", err)
+	default:
+		record.Status = status
+		record.JobID = jobID
+		switch status {
+		case models.LibraryImportFileDuplicate:
+			imp.Duplicates++
+		case models.LibraryImportFileQueued:
+			imp.Queued++
+		}
+	}
+	if err := s.importRepo.CreateFile(ctx, &record); err != nil {
+		return fmt.Errorf("failed to record imported file: %w", err)
+	}
+	if imp.FilesFound%libraryImportProgressEvery == 0 {
+		if err := s.importRepo.Update(ctx, imp); err != nil {
+			logger.Warn("Failed to save library import progress", "import_id", imp.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// process deduplicates a file and, unless the import is a dry run, queues it
+func (r *importRun) process(ctx context.Context, file importFile, record *models.LibraryImportFile) (string, *string, error) {
+	s, imp := r.service, r.imp
+	if file.etag != "" {
+		record.Fingerprint = fmt.Sprintf("etag:%s:%d", file.etag, file.size)
+	} else if !strings.HasPrefix(file.source, "s3://") {
+		sum, err := hashFile(file.source)
+		if err != nil {
+			return "", nil, err
+		}
+		record.Fingerprint = "sha256:" + sum
+	}
+
+	if imp.Dedup {
+		if record.Fingerprint != "" && r.seen[record.Fingerprint] {
+			return models.LibraryImportFileDuplicate, nil, nil
+		}
+		imported, err := s.importRepo.IsImported(ctx, imp.Workspace, file.source, record.Fingerprint)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to check for duplicates: %w", err)
+		}
+		if imported {
+			return models.LibraryImportFileDuplicate, nil, nil
+		}
+	}
+	if record.Fingerprint != "" {
+		r.seen[record.Fingerprint] = true
+	}
+	if imp.DryRun {
+		return models.LibraryImportFilePlanned, nil, nil
+	}
+
+	if err := r.throttle(ctx); err != nil {
+		return "", nil, err
+	}
+	if err := CheckQuota(ctx, s.jobRepo, imp.Workspace, s.cfg.MonthlyQuotaMinutes); err != nil {
+		return "", nil, err
+	}
+	jobID, err := r.queue(ctx, file)
+	if err != nil {
+		return "", nil, err
+	}
+	return models.LibraryImportFileQueued, &jobID, nil
+}
+
+// throttle waits until the import may queue another job: one every interval,
+// and only while fewer than the configured number of its jobs are waiting
+func (r *importRun) throttle(ctx context.Context) error {
+	if wait := time.Until(r.lastQueue.Add(r.interval)); wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+	if max := r.service.cfg.LibraryImportMaxPending; max > 0 {
+		for {
+			waiting, err := r.service.importRepo.CountWaiting(ctx, r.imp.ID)
+			if err != nil {
+				return fmt.Errorf("failed to count waiting jobs: %w", err)
+			}
+			if waiting < int64(max) {
+				break
+			}
+			if err := sleepContext(ctx, libraryImportPendingPoll); err != nil {
+				return err
+			}
+		}
+	}
+	r.lastQueue = time.Now()
+	return nil
+}
+
+// queue creates and enqueues the job for a file. S3 objects are read in
+// place; local files are copied into the workspace's upload directory.
+func (r *importRun) queue(ctx context.Context, file importFile) (string, error) {
+	s, imp := r.service, r.imp
+	title := path.Base(file.source)
+	job := models.TranscriptionJob{
+		ID:        uuid.New().String(),
+		Title:     &title,
+		Status:    models.StatusPending,
+		Workspace: imp.Workspace,
+	}
+	if r.profile != nil {
+		job.Parameters = r.profile.Parameters
+		job.ProfileID = &r.profile.ID
+		job.ProfileVersion = &r.profile.Version
+	}
+	job.Parameters.IsMultiTrackEnabled = false
+	job.Diarization = job.Parameters.Diarize
+
+	tags, err := json.Marshal([]models.TagEntry{
+		{Key: LibraryImportTagID, Value: imp.ID},
+		{Key: LibraryImportTagSource, Value: file.source},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	tagsJSON := string(tags)
+	job.Tags = &tagsJSON
+
+	if strings.HasPrefix(file.source, "s3://") {
+		uri := file.source
+		job.AudioPath = uri
+		job.AudioUri = &uri
+	} else {
+		dir, err := WorkspaceDir(s.cfg.UploadDir, imp.Workspace)
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create upload directory: %w", err)
+		}
+		job.AudioPath = filepath.Join(dir, job.ID+strings.ToLower(filepath.Ext(file.source)))
+		if err := copyFile(file.source, job.AudioPath); err != nil {
+			os.Remove(job.AudioPath)
+			return "", fmt.Errorf("failed to copy file: %w", err)
+		}
+	}
+
+	if err := s.jobRepo.Create(ctx, &job); err != nil {
+		if job.AudioUri == nil {
+			os.Remove(job.AudioPath)
+		}
+		return "", fmt.Errorf("failed to create job: %w", err)
+	}
+	if err := s.queue.EnqueueJob(job.ID); err != nil {
+		// The job stays pending and is picked up by the queue's pending scan
+		logger.Warn("Failed to enqueue imported job", "import_id", imp.ID, "job_id", job.ID, "error", err)
+	}
+	return job.ID, nil
+}
+
+// hashFile returns the hex SHA-256 of a file's contents
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// sleepContext waits for d, or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeBucket lists a fixed set of S3 objects
+type fakeBucket struct {
+	FileService
+	objects []ObjectInfo
+}
+
+func (f *fakeBucket) WalkObjects(ctx context.Context, prefixURL string, fn func(ObjectInfo) error) error {
+	for _, obj := range f.objects {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openLibraryImportDB opens an in-memory database on a single connection, so
+// imports running in the background see the same data
+func openLibraryImportDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.TranscriptionProfile{}, &models.ProfileVersion{}, &models.QuotaUsage{},
+		&models.LibraryImport{}, &models.LibraryImportFile{}, &models.Tag{}, &models.JobTag{}))
+	return db
+}
+
+func TestLibraryImport(t *testing.T) {
+	db := openLibraryImportDB(t)
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "2020", "q1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "2020", "kickoff.wav"), []byte("kickoff"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "2020", "q1", "review.MP3"), []byte("review"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "2020", "q1", "review-copy.mp3"), []byte("review"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "2020", "agenda.pdf"), []byte("agenda"), 0644))
+
+	cfg := &config.Config{UploadDir: t.TempDir(), LibraryImportDir: root, LibraryImportMaxPending: 10}
+	importRepo := repository.NewLibraryImportRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bucket := &fakeBucket{objects: []ObjectInfo{
+		{URI: "s3://archive/2021/a.m4a", Size: 10, ETag: "abc"},
+		{URI: "s3://archive/2021/a-again.m4a", Size: 10, ETag: "abc"},
+		{URI: "s3://archive/2021/readme.md", Size: 3, ETag: "def"},
+	}}
+	queue := &fakeIntegrityQueue{}
+	svc := NewLibraryImportService(cfg, importRepo, jobRepo, repository.NewProfileRepository(db), bucket, queue)
+	defer svc.Stop()
+
+	runImport := func(imp models.LibraryImport) models.LibraryImport {
+		require.NoError(t, svc.Start(context.Background(), &imp))
+		require.Eventually(t, func() bool {
+			got, err := svc.Get(context.Background(), imp.ID)
+			require.NoError(t, err)
+			imp = *got
+			return imp.Status != models.LibraryImportRunning
+		}, 5*time.Second, 10*time.Millisecond)
+		return imp
+	}
+
+	_, _, err := svc.ListFiles(context.Background(), "missing", "", 0, 10)
+	assert.Error(t, err)
+	for _, source := range []string{t.TempDir(), filepath.Join(root, "..")} {
+		err := svc.Start(context.Background(), &models.LibraryImport{Source: source})
+		assert.ErrorIs(t, err, ErrInvalidImportSource, source)
+	}
+
+	imp := runImport(models.LibraryImport{Source: "2020", Workspace: "team", Dedup: true})
+	assert.Equal(t, models.LibraryImportCompleted, imp.Status)
+	assert.Equal(t, filepath.Join(root, "2020"), imp.Source)
+	assert.Equal(t, 3, imp.FilesFound)
+	assert.Equal(t, 2, imp.Queued)
+	assert.Equal(t, 1, imp.Duplicates)
+	assert.Len(t, queue.enqueued, 2)
+
+	files, total, err := svc.ListFiles(context.Background(), imp.ID, models.LibraryImportFileQueued, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	job, err := jobRepo.FindByID(context.Background(), *files[0].JobID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, job.Status)
+	assert.Equal(t, "team", job.Workspace)
+	assert.Equal(t, "kickoff.wav", *job.Title)
+	data, err := os.ReadFile(job.AudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, "kickoff", string(data), "local files are copied into the workspace")
+	_, err = ScopePath(cfg.UploadDir, "team", job.AudioPath)
+	assert.NoError(t, err)
+
+	// Running again skips what was imported; without dedup everything is queued again
+	imp = runImport(models.LibraryImport{Source: "2020", Workspace: "team", Dedup: true})
+	assert.Equal(t, 3, imp.Duplicates)
+	assert.Equal(t, 0, imp.Queued)
+	imp = runImport(models.LibraryImport{Source: "2020", Workspace: "other", Dedup: true, DryRun: true})
+	assert.Equal(t, 2, imp.FilesFound-imp.Duplicates, "other workspaces import their own copies")
+	imp = runImport(models.LibraryImport{Source: "2020", Workspace: "team", Dedup: false, DryRun: true})
+	assert.Equal(t, 0, imp.Duplicates)
+	assert.Len(t, queue.enqueued, 2)
+
+	// S3 objects are read in place
+	imp = runImport(models.LibraryImport{Source: "s3://archive/2021", Workspace: "team", Dedup: true})
+	assert.Equal(t, 2, imp.FilesFound)
+	assert.Equal(t, 1, imp.Queued)
+	assert.Equal(t, 1, imp.Duplicates)
+	files, _, err = svc.ListFiles(context.Background(), imp.ID, models.LibraryImportFileQueued, 0, 10)
+	require.NoError(t, err)
+	job, err = jobRepo.FindByID(context.Background(), *files[0].JobID)
+	require.NoError(t, err)
+	require.NotNil(t, job.AudioUri)
+	assert.Equal(t, "s3://archive/2021/a.m4a", *job.AudioUri)
+}
+
+func TestLibraryImportThrottle(t *testing.T) {
+	db := openLibraryImportDB(t)
+
+	bucket := &fakeBucket{}
+	for _, key := range []string{"a", "b", "c"} {
+		bucket.objects = append(bucket.objects, ObjectInfo{URI: "s3://archive/" + key + ".wav", ETag: key})
+	}
+	cfg := &config.Config{UploadDir: t.TempDir(), LibraryImportMaxPending: 2}
+	importRepo := repository.NewLibraryImportRepository(db)
+	svc := NewLibraryImportService(cfg, importRepo, repository.NewJobRepository(db), repository.NewProfileRepository(db), bucket, &fakeIntegrityQueue{})
+
+	// No worker picks the jobs up, so the import waits once two are pending
+	imp := models.LibraryImport{Source: "s3://archive", Dedup: true}
+	require.NoError(t, svc.Start(context.Background(), &imp))
+	require.Eventually(t, func() bool {
+		waiting, err := importRepo.CountWaiting(context.Background(), imp.ID)
+		return err == nil && waiting == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	waiting, err := importRepo.CountWaiting(context.Background(), imp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), waiting)
+
+	require.NoError(t, svc.Cancel(context.Background(), imp.ID))
+	require.Eventually(t, func() bool {
+		got, err := svc.Get(context.Background(), imp.ID)
+		return err == nil && got.Status == models.LibraryImportCancelled
+	}, 5*time.Second, 10*time.Millisecond)
+	got, err := svc.Get(context.Background(), imp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Queued)
+	assert.Error(t, svc.Cancel(context.Background(), imp.ID))
+	svc.Stop()
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/metrics"
+)
+
+const (
+	postProcessingTickInterval = 30 * time.Second
+	postProcessingJobsPerTick  = 5
+	postProcessingTimeout      = 30 * time.Minute
+	maxSuggestedTags           = 5
+
+	// llmOutputRetention is how long a cached LLM reply is kept after its last use
+	llmOutputRetention = 30 * 24 * time.Hour
+
+	// PostProcessingTagKey is the tag key of LLM-suggested topics in TranscriptionJob.Tags
+	PostProcessingTagKey = "topic"
+)
+
+// PostProcessingService runs the automation configured on a job's profile (summary,
+// action items, tags, chapters, sentiment) once the job has completed
+type PostProcessingService interface {
+	ProcessPending(ctx context.Context) (int, error)
+	Start()
+	Stop()
+}
+
+type postProcessingService struct {
+	jobRepo       repository.JobRepository
+	llmConfigRepo repository.LLMConfigRepository
+	summaryRepo   repository.SummaryRepository
+
+	// processMu serialises runs so a job is never post-processed twice concurrently
+	processMu sync.Mutex
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+func NewPostProcessingService(jobRepo repository.JobRepository, llmConfigRepo repository.LLMConfigRepository, summaryRepo repository.SummaryRepository) PostProcessingService {
+	return &postProcessingService{
+		jobRepo:       jobRepo,
+		llmConfigRepo: llmConfigRepo,
+		summaryRepo:   summaryRepo,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start launches the background post-processor
+func (s *postProcessingService) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts the background post-processor and waits for the current run to finish
+func (s *postProcessingService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *postProcessingService) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(postProcessingTickInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if _, err := s.ProcessPending(context.Background()); err != nil {
+				logger.Debug("Post-processing skipped", "error", err)
+			}
+			if time.Since(lastPrune) >= time.Hour {
+				s.pruneLLMOutputs(context.Background())
+				lastPrune = time.Now()
+			}
+		}
+	}
+}
+
+// pruneLLMOutputs drops cached LLM replies that have not been used for a while
+func (s *postProcessingService) pruneLLMOutputs(ctx context.Context) {
+	deleted, err := s.summaryRepo.DeleteLLMOutputsUnusedSince(ctx, time.Now().Add(-llmOutputRetention))
+	if err != nil {
+		logger.Warn("Failed to prune cached LLM outputs", "error", err)
+	} else if deleted > 0 {
+		logger.Debug("Pruned cached LLM outputs", "count", deleted)
+	}
+}
+
+// ProcessPending post-processes completed jobs that are waiting for it and returns
+// how many were processed. Jobs whose organization has no usable LLM configuration
+// are not marked done, so they are picked up once one is configured.
+func (s *postProcessingService) ProcessPending(ctx context.Context) (int, error) {
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	jobs, err := s.jobRepo.ListPendingPostProcessing(ctx, postProcessingJobsPerTick)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list jobs to post-process: %w", err)
+	}
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+
+	// Each job is processed with its organization's LLM configuration
+	services := make(map[string]llm.Service)
+	var lastErr error
+	processed := 0
+	for i := range jobs {
+		organizationID := models.WorkspaceOrganization(jobs[i].Workspace)
+		svc, ok := services[organizationID]
+		if !ok {
+			var err error
+			if svc, err = s.llmService(models.WithOrganization(ctx, organizationID)); err != nil {
+				svc, lastErr = nil, err
+			}
+			services[organizationID] = svc
+		}
+		if svc == nil {
+			continue
+		}
+		s.process(ctx, svc, &jobs[i])
+		processed++
+	}
+	if processed == 0 && lastErr != nil {
+		return 0, lastErr
+	}
+	return processed, nil
+}
+
+// llmService returns the service of the active LLM configuration of the
+// organization ctx is scoped to
+func (s *postProcessingService) llmService(ctx context.Context) (llm.Service, error) {
+	llmCfg, err := s.llmConfigRepo.GetActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no active LLM configuration: %w", err)
+	}
+	svc, err := llm.NewFromConfig(llmCfg)
+	if err != nil {
+		return nil, err
+	}
+	return llm.WithCache(svc, s.summaryRepo, llmCfg), nil
+}
+
+// process runs each configured step, recording failures without stopping the others
+func (s *postProcessingService) process(ctx context.Context, svc llm.Service, job *models.TranscriptionJob) {
+	ctx, cancel := context.WithTimeout(ctx, postProcessingTimeout)
+	defer cancel()
+	started := time.Now()
+
+	var errs []error
+	text := TranscriptPlainText(job.Transcript)
+	if text == "" {
+		errs = append(errs, errors.New("transcript is empty"))
+	} else {
+		defaultModel := ""
+		if settings, err := s.summaryRepo.GetSettings(ctx); err == nil {
+			defaultModel = settings.DefaultModel
+		}
+		// Without a default summary model, the other steps use the model of the
+		// profile's summary template
+		if defaultModel == "" && job.Parameters.AutoSummaryTemplateID != nil && *job.Parameters.AutoSummaryTemplateID != "" {
+			if template, err := s.summaryRepo.FindByID(ctx, *job.Parameters.AutoSummaryTemplateID); err == nil {
+				defaultModel = template.Model
+			}
+		}
+
+		if job.Parameters.AutoSummaryTemplateID != nil && *job.Parameters.AutoSummaryTemplateID != "" {
+			if err := s.summarize(ctx, svc, job, defaultModel); err != nil {
+				errs = append(errs, fmt.Errorf("summary: %w", err))
+			}
+		}
+		if job.Parameters.AutoActionItems {
+			if err := s.extractActionItems(ctx, svc, job, text, defaultModel); err != nil {
+				errs = append(errs, fmt.Errorf("action items: %w", err))
+			}
+		}
+		if job.Parameters.AutoTag {
+			if err := s.suggestTags(ctx, svc, job, text, defaultModel); err != nil {
+				errs = append(errs, fmt.Errorf("tags: %w", err))
+			}
+		}
+		if job.Parameters.AutoChapters {
+			if err := s.generateChapters(ctx, svc, job, defaultModel); err != nil {
+				errs = append(errs, fmt.Errorf("chapters: %w", err))
+			}
+		}
+		if job.Parameters.AutoSentiment {
+			if err := s.analyzeSentiment(ctx, svc, job, defaultModel); err != nil {
+				errs = append(errs, fmt.Errorf("sentiment: %w", err))
+			}
+		}
+	}
+
+	now := time.Now()
+	job.PostProcessedAt = &now
+	job.PostProcessError = nil
+	job.StageTimings.PostProcessingMs = nil
+	job.StageTimings.Add(models.StagePostProcessing, now.Sub(started))
+	metrics.JobStageDuration.Observe(now.Sub(started).Seconds(), models.StagePostProcessing, job.Parameters.ModelFamily)
+	if err := errors.Join(errs...); err != nil {
+		msg := err.Error()
+		job.PostProcessError = &msg
+		logger.Warn("Post-processing finished with errors", "job_id", job.ID, "error", msg)
+	} else {
+		logger.Info("Post-processing completed", "job_id", job.ID)
+	}
+	if err := s.jobRepo.UpdatePostProcessing(ctx, job); err != nil {
+		logger.Error("Failed to store post-processing results", "job_id", job.ID, "error", err)
+	}
+}
+
+// summarize generates a summary with the profile's template, stored like a
+// summary requested from the UI
+func (s *postProcessingService) summarize(ctx context.Context, svc llm.Service, job *models.TranscriptionJob, defaultModel string) error {
+	template, err := s.summaryRepo.FindByID(ctx, *job.Parameters.AutoSummaryTemplateID)
+	if err != nil {
+		return fmt.Errorf("template not found: %w", err)
+	}
+	model := template.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	content, err := SummarizeTranscript(ctx, svc, model, job.Transcript, template.Prompt)
+	if err != nil {
+		return err
+	}
+
+	if err := s.summaryRepo.SaveSummary(ctx, &models.Summary{
+		TranscriptionID: job.ID,
+		TemplateID:      &template.ID,
+		Model:           model,
+		Content:         content,
+	}); err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+	job.Summary = &content
+	return nil
+}
+
+func (s *postProcessingService) extractActionItems(ctx context.Context, svc llm.Service, job *models.TranscriptionJob, text, model string) error {
+	prompt := fmt.Sprintf("Transcript:\n%s\n\nInstructions:\n"+
+		"List the action items, tasks and follow-ups agreed in this transcript, naming the owner when it is stated. "+
+		"Reply with only a JSON array of strings, one per action item, or [] if there are none.", text)
+	items, err := completeList(ctx, svc, model, prompt)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	job.ActionItems = &encoded
+	return nil
+}
+
+func (s *postProcessingService) suggestTags(ctx context.Context, svc llm.Service, job *models.TranscriptionJob, text, model string) error {
+	prompt := fmt.Sprintf("Transcript:\n%s\n\nInstructions:\n"+
+		"Suggest up to %d short topic tags (one to three words, lowercase) that describe this transcript. "+
+		"Reply with only a JSON array of strings.", text, maxSuggestedTags)
+	suggested, err := completeList(ctx, svc, model, prompt)
+	if err != nil {
+		return err
+	}
+
+	tags, err := job.TagEntries()
+	if err != nil {
+		return fmt.Errorf("existing tags are not a tag list: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if tag.Key == PostProcessingTagKey {
+			seen[tag.Value] = true
+		}
+	}
+	added := 0
+	for _, value := range suggested {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" || seen[value] || added == maxSuggestedTags {
+			continue
+		}
+		seen[value] = true
+		tags = append(tags, models.TagEntry{Key: PostProcessingTagKey, Value: value})
+		added++
+	}
+
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	job.Tags = &encoded
+	return nil
+}
+
+func (s *postProcessingService) analyzeSentiment(ctx context.Context, svc llm.Service, job *models.TranscriptionJob, model string) error {
+	sentiment, err := AnalyzeSentiment(ctx, svc, model, job.Transcript)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(sentiment)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	job.SegmentSentiment = &encoded
+	return nil
+}
+
+func (s *postProcessingService) generateChapters(ctx context.Context, svc llm.Service, job *models.TranscriptionJob, model string) error {
+	chapters, err := GenerateChapters(ctx, svc, model, job.Transcript)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(chapters)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	job.Chapters = &encoded
+	return nil
+}
+
+// complete sends a single-message prompt and returns the reply
+func complete(ctx context.Context, svc llm.Service, model, prompt string) (string, error) {
+	if model == "" {
+		return "", errors.New("no model configured; set a default summary model")
+	}
+	resp, err := svc.ChatCompletion(ctx, model, []llm.ChatMessage{{Role: "user", Content: prompt}}, 0.0)
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || len(resp.Choices) == 0 {
+		return "", errors.New("the LLM returned no answer")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// completeList sends a prompt asking for a JSON array of strings and parses the
+// reply, tolerating prose or code fences around the array
+func completeList(ctx context.Context, svc llm.Service, model, prompt string) ([]string, error) {
+	reply, err := complete(ctx, svc, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+	start := strings.Index(reply, "[")
+	end := strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("expected a JSON array, got %q", reply)
+	}
+	var items []string
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &items); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of strings: %w", err)
+	}
+	return items, nil
+}
+
+// TranscriptPlainText renders a transcript's segments as "Speaker: text" lines,
+// or "" when it cannot be parsed
+func TranscriptPlainText(transcript *string) string {
+	if transcript == nil {
+		return ""
+	}
+	var parsed struct {
+		Text     string `json:"text"`
+		Segments []struct {
+			Text    string `json:"text"`
+			Speaker string `json:"speaker"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, seg := range parsed.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if seg.Speaker != "" {
+			b.WriteString(seg.Speaker)
+			b.WriteString(": ")
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return strings.TrimSpace(parsed.Text)
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+)
+
+// ErrQuotaExceeded is returned when a workspace has used up its monthly
+// transcription minutes
+var ErrQuotaExceeded = errors.New("monthly transcription quota used up")
+
+// CheckQuota returns ErrQuotaExceeded once workspace has transcribed
+// limitMinutes of audio this month. A limit of 0 or less is unlimited.
+func CheckQuota(ctx context.Context, jobRepo repository.JobRepository, workspace string, limitMinutes int) error {
+	if limitMinutes <= 0 {
+		return nil
+	}
+	used, err := jobRepo.FindQuotaUsage(ctx, workspace, models.QuotaMonth(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+	if used >= float64(limitMinutes)*60 {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+// recordingCleanupInterval is how often abandoned recording sessions are looked for
+const recordingCleanupInterval = time.Hour
+
+// RecordingCleanupService removes browser recording sessions that were never
+// finalized and have sat idle longer than the session lifetime, with their audio
+type RecordingCleanupService interface {
+	Start()
+	Stop()
+	// Cleanup removes the abandoned sessions now and returns how many it removed
+	Cleanup(ctx context.Context) (int, error)
+}
+
+type recordingCleanupService struct {
+	ttl           time.Duration
+	recordingRepo repository.RecordingRepository
+	fileService   FileService
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewRecordingCleanupService(cfg *config.Config, recordingRepo repository.RecordingRepository, fileService FileService) RecordingCleanupService {
+	return &recordingCleanupService{
+		ttl:           time.Duration(cfg.RecordingSessionTTLHours) * time.Hour,
+		recordingRepo: recordingRepo,
+		fileService:   fileService,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start removes abandoned sessions every hour; it does nothing when sessions
+// have no lifetime
+func (s *recordingCleanupService) Start() {
+	if s.ttl <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts the cleanup and waits for a running pass to finish
+func (s *recordingCleanupService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *recordingCleanupService) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(recordingCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if _, err := s.Cleanup(context.Background()); err != nil {
+				logger.Error("Recording session cleanup failed", "error", err)
+			}
+		}
+	}
+}
+
+func (s *recordingCleanupService) Cleanup(ctx context.Context) (int, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+	sessions, err := s.recordingRepo.FindStale(ctx, time.Now().Add(-s.ttl))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, session := range sessions {
+		if err := s.fileService.RemoveFile(session.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Failed to remove abandoned recording", "session_id", session.ID, "error", err)
+			continue
+		}
+		if err := s.recordingRepo.Delete(ctx, session.ID); err != nil {
+			logger.Warn("Failed to delete abandoned recording session", "session_id", session.ID, "error", err)
+			continue
+		}
+		removed++
+	}
+	if removed > 0 {
+		logger.Info("Removed abandoned recording sessions", "count", removed)
+	}
+	return removed, nil
+}
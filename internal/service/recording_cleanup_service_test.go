@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRecordingCleanup(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.RecordingSession{}))
+	repo := repository.NewRecordingRepository(db)
+	dir := t.TempDir()
+
+	session := func(id string, idle time.Duration, jobID *string) string {
+		path := filepath.Join(dir, id+".webm")
+		require.NoError(t, os.WriteFile(path, []byte("audio"), 0644))
+		require.NoError(t, db.Create(&models.RecordingSession{ID: id, MimeType: "audio/webm", FilePath: path, JobID: jobID}).Error)
+		require.NoError(t, db.Model(&models.RecordingSession{}).Where("id = ?", id).UpdateColumn("updated_at", time.Now().Add(-idle)).Error)
+		return path
+	}
+	jobID := "finalized"
+	abandoned := session("abandoned", 48*time.Hour, nil)
+	active := session("active", time.Hour, nil)
+	finalized := session("finalized", 48*time.Hour, &jobID)
+
+	cleanup := NewRecordingCleanupService(&config.Config{RecordingSessionTTLHours: 24}, repo, NewFileService())
+	removed, err := cleanup.Cleanup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	assert.NoFileExists(t, abandoned)
+	assert.FileExists(t, active)
+	assert.FileExists(t, finalized)
+	var ids []string
+	require.NoError(t, db.Model(&models.RecordingSession{}).Order("id").Pluck("id", &ids).Error)
+	assert.Equal(t, []string{"active", "finalized"}, ids)
+
+	// Without a lifetime sessions are kept
+	removed, err = NewRecordingCleanupService(&config.Config{}, repo, NewFileService()).Cleanup(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
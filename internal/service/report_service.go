@@ -0,0 +1,526 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	netmail "net/mail"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/mail"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/cron"
+	"scriberr/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	reportTickInterval   = time.Minute
+	reportWebhookTimeout = 30 * time.Second
+	// reportExcerptRunes is the context kept around a flagged keyword
+	reportExcerptRunes = 80
+)
+
+// ErrInvalidReportSchedule is returned for schedules that cannot run
+var ErrInvalidReportSchedule = errors.New("invalid report schedule")
+
+// Report summarizes a workspace's activity over a period
+type Report struct {
+	ScheduleID  string    `json:"schedule_id"`
+	Name        string    `json:"name"`
+	Workspace   string    `json:"workspace"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// JobsCreated counts the jobs submitted in the period by their current status
+	JobsCreated map[models.JobStatus]int64 `json:"jobs_created"`
+	// Month-to-date transcribed audio, as counted against the quota
+	UsageMonth        string             `json:"usage_month"`
+	UsageAudioMinutes float64            `json:"usage_audio_minutes"`
+	NewTranscripts    []ReportJob        `json:"new_transcripts"`
+	KeywordHits       []ReportKeywordHit `json:"keyword_hits"`
+	FailedJobs        []ReportJob        `json:"failed_jobs"`
+}
+
+// ReportJob is a job that completed or failed in a report's period
+type ReportJob struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// ReportKeywordHit is a flagged keyword mentioned in a new transcript
+type ReportKeywordHit struct {
+	Keyword  string `json:"keyword"`
+	JobID    string `json:"job_id"`
+	Title    string `json:"title"`
+	Mentions int    `json:"mentions"`
+	Excerpt  string `json:"excerpt"`
+}
+
+// ReportService manages report schedules, and compiles and delivers their
+// reports whenever they are due
+type ReportService interface {
+	Create(ctx context.Context, schedule *models.ReportSchedule) error
+	// Get returns a schedule, as not found if its workspace is not visible to ctx
+	Get(ctx context.Context, id string) (*models.ReportSchedule, error)
+	List(ctx context.Context) ([]models.ReportSchedule, error)
+	Update(ctx context.Context, schedule *models.ReportSchedule) error
+	Delete(ctx context.Context, id string) error
+	// Preview compiles the report the schedule would deliver now, without
+	// delivering it
+	Preview(ctx context.Context, schedule *models.ReportSchedule) (*Report, error)
+	// Run compiles and delivers the schedule's report now; the next report
+	// covers the period from here on
+	Run(ctx context.Context, id string) (*Report, error)
+	Start()
+	Stop()
+}
+
+type reportService struct {
+	reportRepo  repository.ReportRepository
+	fileService FileService
+	mailer      *mail.Sender
+	client      *http.Client
+	// validateURL checks webhook URLs before reports are posted to them
+	validateURL func(string) error
+
+	// runMu serialises runs so a scheduled and a manual run cannot cover the
+	// same period twice
+	runMu sync.Mutex
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func NewReportService(reportRepo repository.ReportRepository, fileService FileService, mailer *mail.Sender) ReportService {
+	return &reportService{
+		reportRepo:  reportRepo,
+		fileService: fileService,
+		mailer:      mailer,
+		client: &http.Client{
+			Timeout:   reportWebhookTimeout,
+			Transport: &http.Transport{DialContext: publicDialer.DialContext},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				return ValidateSourceURL(req.URL.String())
+			},
+		},
+		validateURL: ValidateSourceURL,
+		stop:        make(chan struct{}),
+	}
+}
+
+func (s *reportService) Create(ctx context.Context, schedule *models.ReportSchedule) error {
+	if schedule.ID == "" {
+		schedule.ID = uuid.New().String()
+	}
+	if err := s.prepare(schedule, time.Now()); err != nil {
+		return err
+	}
+	return s.reportRepo.Create(ctx, schedule)
+}
+
+func (s *reportService) Get(ctx context.Context, id string) (*models.ReportSchedule, error) {
+	schedule, err := s.reportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !models.WorkspaceVisible(ctx, schedule.Workspace) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return schedule, nil
+}
+
+func (s *reportService) List(ctx context.Context) ([]models.ReportSchedule, error) {
+	return s.reportRepo.ListVisible(ctx)
+}
+
+func (s *reportService) Update(ctx context.Context, schedule *models.ReportSchedule) error {
+	if err := s.prepare(schedule, time.Now()); err != nil {
+		return err
+	}
+	return s.reportRepo.Update(ctx, schedule)
+}
+
+func (s *reportService) Delete(ctx context.Context, id string) error {
+	return s.reportRepo.Delete(ctx, id)
+}
+
+// prepare validates a schedule and sets when it next runs
+func (s *reportService) prepare(schedule *models.ReportSchedule, now time.Time) error {
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	if schedule.Format == "" {
+		schedule.Format = models.ReportFormatMarkdown
+	}
+	next, err := nextReportRun(schedule, now)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReportSchedule, err)
+	}
+	if schedule.Format != models.ReportFormatMarkdown && schedule.Format != models.ReportFormatJSON {
+		return fmt.Errorf("%w: format must be markdown or json", ErrInvalidReportSchedule)
+	}
+
+	recipients := schedule.Recipients()
+	if len(recipients) == 0 && schedule.WebhookURL == "" && schedule.S3URI == "" {
+		return fmt.Errorf("%w: at least one of email_to, webhook_url and s3_uri is required", ErrInvalidReportSchedule)
+	}
+	if len(recipients) > 0 && !s.mailer.Enabled() {
+		return fmt.Errorf("%w: email delivery needs SMTP_HOST and SMTP_FROM to be configured", ErrInvalidReportSchedule)
+	}
+	for _, rcpt := range recipients {
+		if addr, err := netmail.ParseAddress(rcpt); err != nil || addr.Address != rcpt {
+			return fmt.Errorf("%w: invalid email address %q", ErrInvalidReportSchedule, rcpt)
+		}
+	}
+	if schedule.WebhookURL != "" {
+		schedule.WebhookURL = strings.TrimSpace(schedule.WebhookURL)
+		if err := s.validateURL(schedule.WebhookURL); err != nil {
+			return fmt.Errorf("%w: webhook_url: %v", ErrInvalidReportSchedule, err)
+		}
+	}
+	if schedule.S3URI != "" {
+		schedule.S3URI = strings.TrimSuffix(strings.TrimSpace(schedule.S3URI), "/")
+		if !strings.HasPrefix(schedule.S3URI, "s3://") || len(schedule.S3URI) == len("s3://") {
+			return fmt.Errorf("%w: s3_uri must be s3://bucket/prefix", ErrInvalidReportSchedule)
+		}
+	}
+
+	if schedule.Enabled {
+		schedule.NextRunAt = &next
+	} else {
+		schedule.NextRunAt = nil
+	}
+	return nil
+}
+
+// nextReportRun returns when a schedule next fires after now, in UTC
+func nextReportRun(schedule *models.ReportSchedule, now time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown timezone %q", schedule.Timezone)
+	}
+	parsed, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := parsed.Next(now.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %q never fires", schedule.Cron)
+	}
+	return next.UTC(), nil
+}
+
+// Start launches the background scheduler
+func (s *reportService) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts the scheduler and waits for a report being delivered
+func (s *reportService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *reportService) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(reportTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.runDue()
+		}
+	}
+}
+
+// runDue delivers the reports of every schedule that is due
+func (s *reportService) runDue() {
+	schedules, err := s.reportRepo.ListDue(context.Background(), time.Now())
+	if err != nil {
+		logger.Error("Failed to list due report schedules", "error", err)
+		return
+	}
+	for _, schedule := range schedules {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		if _, err := s.Run(context.Background(), schedule.ID); err != nil {
+			logger.Warn("Scheduled report failed", "schedule_id", schedule.ID, "workspace", schedule.Workspace, "error", err)
+		}
+	}
+}
+
+func (s *reportService) Preview(ctx context.Context, schedule *models.ReportSchedule) (*Report, error) {
+	return s.compile(ctx, schedule, time.Now())
+}
+
+// Run compiles the report and delivers it to every destination. The period
+// ends here even when a delivery fails, so one broken destination does not
+// hold back the others; the failure is kept in LastError.
+func (s *reportService) Run(ctx context.Context, id string) (*Report, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	schedule, err := s.reportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	report, err := s.compile(ctx, schedule, now)
+	if err != nil {
+		return nil, err
+	}
+	deliverErr := s.deliver(ctx, schedule, report)
+
+	schedule.LastRunAt = &now
+	schedule.LastError = nil
+	if deliverErr != nil {
+		errMsg := deliverErr.Error()
+		schedule.LastError = &errMsg
+	}
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		if next, err := nextReportRun(schedule, now); err == nil {
+			schedule.NextRunAt = &next
+		}
+	}
+	if err := s.reportRepo.Update(ctx, schedule); err != nil {
+		return report, fmt.Errorf("failed to update report schedule: %w", err)
+	}
+	return report, deliverErr
+}
+
+// compile gathers the workspace's activity since the previous report
+func (s *reportService) compile(ctx context.Context, schedule *models.ReportSchedule, until time.Time) (*Report, error) {
+	since := schedule.CreatedAt
+	if schedule.LastRunAt != nil {
+		since = *schedule.LastRunAt
+	}
+	report := &Report{
+		ScheduleID:     schedule.ID,
+		Name:           schedule.Name,
+		Workspace:      schedule.Workspace,
+		PeriodStart:    since.UTC(),
+		PeriodEnd:      until.UTC(),
+		UsageMonth:     models.QuotaMonth(until),
+		NewTranscripts: []ReportJob{},
+		KeywordHits:    []ReportKeywordHit{},
+		FailedJobs:     []ReportJob{},
+	}
+
+	var err error
+	if report.JobsCreated, err = s.reportRepo.CountJobsCreated(ctx, schedule.Workspace, since, until); err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	usage, err := s.reportRepo.FindQuotaUsage(ctx, schedule.Workspace, report.UsageMonth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	report.UsageAudioMinutes = usage / 60
+
+	jobs, err := s.reportRepo.ListJobsFinished(ctx, schedule.Workspace, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	keywords := schedule.KeywordList()
+	for _, job := range jobs {
+		entry := ReportJob{ID: job.ID, FinishedAt: job.UpdatedAt.UTC()}
+		if job.Title != nil {
+			entry.Title = *job.Title
+		}
+		if job.Status == models.StatusFailed {
+			if job.ErrorMessage != nil {
+				entry.Error = *job.ErrorMessage
+			}
+			report.FailedJobs = append(report.FailedJobs, entry)
+			continue
+		}
+		report.NewTranscripts = append(report.NewTranscripts, entry)
+		if len(keywords) > 0 {
+			report.KeywordHits = append(report.KeywordHits, keywordHits(entry, TranscriptPlainText(job.Transcript), keywords)...)
+		}
+	}
+	return report, nil
+}
+
+// keywordHits finds the keywords text mentions as whole words or phrases,
+// ignoring case
+func keywordHits(job ReportJob, text string, keywords []string) []ReportKeywordHit {
+	var hits []ReportKeywordHit
+	for _, keyword := range keywords {
+		pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+		matches := pattern.FindAllStringIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		hits = append(hits, ReportKeywordHit{
+			Keyword:  keyword,
+			JobID:    job.ID,
+			Title:    job.Title,
+			Mentions: len(matches),
+			Excerpt:  excerpt(text, matches[0][0], matches[0][1]),
+		})
+	}
+	return hits
+}
+
+// excerpt returns the line around text[start:end], cut to reportExcerptRunes
+// on either side
+func excerpt(text string, start, end int) string {
+	lineStart := strings.LastIndex(text[:start], "\n") + 1
+	lineEnd := len(text)
+	if i := strings.Index(text[end:], "\n"); i >= 0 {
+		lineEnd = end + i
+	}
+	before, after := []rune(text[lineStart:start]), []rune(text[end:lineEnd])
+	prefix, suffix := "", ""
+	if len(before) > reportExcerptRunes {
+		before, prefix = before[len(before)-reportExcerptRunes:], "…"
+	}
+	if len(after) > reportExcerptRunes {
+		after, suffix = after[:reportExcerptRunes], "…"
+	}
+	return prefix + string(before) + text[start:end] + string(after) + suffix
+}
+
+// deliver sends the rendered report to each of the schedule's destinations
+func (s *reportService) deliver(ctx context.Context, schedule *models.ReportSchedule, report *Report) error {
+	body, contentType, ext, err := RenderReport(report, schedule.Format)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if recipients := schedule.Recipients(); len(recipients) > 0 {
+		subject := fmt.Sprintf("%s: %s to %s", report.Name, report.PeriodStart.Format("2006-01-02 15:04"), report.PeriodEnd.Format("2006-01-02 15:04 MST"))
+		if err := s.mailer.Send(ctx, recipients, subject, string(body)); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		}
+	}
+	if schedule.WebhookURL != "" {
+		if err := s.postWebhook(ctx, schedule.WebhookURL, body, contentType); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if schedule.S3URI != "" {
+		workspace := schedule.Workspace
+		if workspace == "" {
+			workspace = legacyArchiveWorkspace
+		}
+		key := fmt.Sprintf("%s/%s/%s/%s%s", schedule.S3URI, workspace, schedule.ID, report.PeriodEnd.Format("20060102T150405Z"), ext)
+		if err := s.fileService.UploadObject(ctx, key, bytes.NewReader(body), ""); err != nil {
+			errs = append(errs, fmt.Errorf("s3: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *reportService) postWebhook(ctx context.Context, url string, body []byte, contentType string) error {
+	if err := s.validateURL(url); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "Scriberr-Reports/1.0")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// RenderReport renders a report in a schedule's format, returning the document,
+// its content type and file extension
+func RenderReport(report *Report, format string) ([]byte, string, string, error) {
+	if format == models.ReportFormatJSON {
+		body, err := json.MarshalIndent(report, "", "  ")
+		return body, "application/json", ".json", err
+	}
+	return []byte(renderReportMarkdown(report)), "text/markdown; charset=utf-8", ".md", nil
+}
+
+func renderReportMarkdown(report *Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", report.Name)
+	workspace := report.Workspace
+	if workspace == "" {
+		workspace = "(default)"
+	}
+	fmt.Fprintf(&b, "Workspace %s, %s to %s\n\n", workspace,
+		report.PeriodStart.Format("2006-01-02 15:04 MST"), report.PeriodEnd.Format("2006-01-02 15:04 MST"))
+
+	b.WriteString("## Usage\n\n")
+	var total int64
+	statuses := make([]string, 0, len(report.JobsCreated))
+	for status, count := range report.JobsCreated {
+		total += count
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+	fmt.Fprintf(&b, "- Jobs submitted: %d\n", total)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "  - %s: %d\n", status, report.JobsCreated[models.JobStatus(status)])
+	}
+	fmt.Fprintf(&b, "- Audio transcribed in %s: %.1f minutes\n\n", report.UsageMonth, report.UsageAudioMinutes)
+
+	fmt.Fprintf(&b, "## New transcripts (%d)\n\n", len(report.NewTranscripts))
+	for _, job := range report.NewTranscripts {
+		fmt.Fprintf(&b, "- %s (%s), %s\n", reportJobTitle(job), job.ID, job.FinishedAt.Format("2006-01-02 15:04"))
+	}
+	if len(report.NewTranscripts) == 0 {
+		b.WriteString("None.\n")
+	}
+
+	fmt.Fprintf(&b, "\n## Flagged keywords (%d)\n\n", len(report.KeywordHits))
+	for _, hit := range report.KeywordHits {
+		fmt.Fprintf(&b, "- **%s** in %s (%s), %d mention(s): %s\n", hit.Keyword, reportJobTitle(ReportJob{ID: hit.JobID, Title: hit.Title}), hit.JobID, hit.Mentions, hit.Excerpt)
+	}
+	if len(report.KeywordHits) == 0 {
+		b.WriteString("None.\n")
+	}
+
+	fmt.Fprintf(&b, "\n## Failed jobs (%d)\n\n", len(report.FailedJobs))
+	for _, job := range report.FailedJobs {
+		fmt.Fprintf(&b, "- %s (%s): %s\n", reportJobTitle(job), job.ID, job.Error)
+	}
+	if len(report.FailedJobs) == 0 {
+		b.WriteString("None.\n")
+	}
+	return b.String()
+}
+
+func reportJobTitle(job ReportJob) string {
+	if job.Title == "" {
+		return "Untitled"
+	}
+	return job.Title
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"scriberr/internal/mail"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReportRunDeliversWorkspaceActivity(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.QuotaUsage{}, &models.ReportSchedule{}))
+
+	title := "Board meeting"
+	transcript := `{"segments":[{"speaker":"A","text":"We should discuss the Merger next week."},{"speaker":"B","text":"The merger is confidential."}]}`
+	errMsg := "audio could not be decoded"
+	require.NoError(t, db.Create([]models.TranscriptionJob{
+		{ID: "done", Title: &title, Status: models.StatusCompleted, Workspace: "team", AudioPath: "a.wav", Transcript: &transcript},
+		{ID: "broken", Status: models.StatusFailed, Workspace: "team", AudioPath: "b.wav", ErrorMessage: &errMsg},
+		{ID: "queued", Status: models.StatusPending, Workspace: "team", AudioPath: "c.wav"},
+		{ID: "elsewhere", Status: models.StatusFailed, Workspace: "other", AudioPath: "d.wav"},
+	}).Error)
+	require.NoError(t, db.Create(&models.QuotaUsage{Workspace: "team", Month: models.QuotaMonth(time.Now()), AudioSeconds: 5400}).Error)
+
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer server.Close()
+
+	store := &fakeArchiveStore{objects: map[string]string{}}
+	svc := NewReportService(repository.NewReportRepository(db), store, mail.NewSender(mail.Config{})).(*reportService)
+	svc.client = server.Client()
+	svc.validateURL = func(string) error { return nil }
+
+	ctx := context.Background()
+	schedule := &models.ReportSchedule{
+		Workspace:  "team",
+		Name:       "Weekly",
+		Cron:       "0 9 * * mon",
+		Timezone:   "Europe/Berlin",
+		Format:     models.ReportFormatJSON,
+		Keywords:   "merger\nlayoffs",
+		WebhookURL: server.URL,
+		S3URI:      "s3://reports/scriberr/",
+		Enabled:    true,
+		CreatedAt:  time.Now().Add(-time.Hour),
+	}
+	require.NoError(t, svc.Create(ctx, schedule))
+	require.NotNil(t, schedule.NextRunAt)
+	assert.Equal(t, time.Monday, schedule.NextRunAt.In(mustLoadLocation(t, "Europe/Berlin")).Weekday())
+
+	_, err = svc.Run(ctx, schedule.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[models.JobStatus]int64{models.StatusCompleted: 1, models.StatusFailed: 1, models.StatusPending: 1}, received.JobsCreated)
+	assert.InDelta(t, 90, received.UsageAudioMinutes, 0.001)
+	require.Len(t, received.NewTranscripts, 1)
+	assert.Equal(t, "Board meeting", received.NewTranscripts[0].Title)
+	require.Len(t, received.FailedJobs, 1)
+	assert.Equal(t, errMsg, received.FailedJobs[0].Error)
+	require.Len(t, received.KeywordHits, 1)
+	assert.Equal(t, 2, received.KeywordHits[0].Mentions)
+	assert.Equal(t, "A: We should discuss the Merger next week.", received.KeywordHits[0].Excerpt)
+
+	require.Len(t, store.objects, 1)
+	for key := range store.objects {
+		assert.Regexp(t, `^s3://reports/scriberr/team/`+schedule.ID+`/\d{8}T\d{6}Z\.json$`, key)
+	}
+
+	saved, err := svc.Get(ctx, schedule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, saved.LastRunAt)
+	assert.Nil(t, saved.LastError)
+
+	// The next report starts where this one ended
+	report, err := svc.Preview(ctx, saved)
+	require.NoError(t, err)
+	assert.Empty(t, report.JobsCreated)
+	assert.Empty(t, report.NewTranscripts)
+
+	// Schedules of other workspaces are not visible
+	_, err = svc.Get(models.WithWorkspace(ctx, "other"), schedule.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestReportScheduleValidation(t *testing.T) {
+	svc := &reportService{mailer: mail.NewSender(mail.Config{}), validateURL: func(string) error { return nil }}
+	now := time.Now()
+
+	for name, schedule := range map[string]models.ReportSchedule{
+		"bad cron":       {Cron: "every monday", S3URI: "s3://bucket"},
+		"bad timezone":   {Cron: "@daily", Timezone: "Mars/Olympus", S3URI: "s3://bucket"},
+		"bad format":     {Cron: "@daily", Format: "pdf", S3URI: "s3://bucket"},
+		"no destination": {Cron: "@daily"},
+		"email w/o SMTP": {Cron: "@daily", EmailTo: "ops@example.com"},
+		"bad S3 URI":     {Cron: "@daily", S3URI: "https://bucket"},
+		"never fires":    {Cron: "0 0 31 2 *", S3URI: "s3://bucket"},
+	} {
+		assert.ErrorIs(t, svc.prepare(&schedule, now), ErrInvalidReportSchedule, name)
+	}
+
+	svc.mailer = mail.NewSender(mail.Config{Host: "smtp.example.com", Port: 587, From: "reports@example.com"})
+	bad := models.ReportSchedule{Cron: "@daily", EmailTo: "ops@example.com\nnot an address"}
+	assert.ErrorIs(t, svc.prepare(&bad, now), ErrInvalidReportSchedule)
+	good := models.ReportSchedule{Cron: "@daily", EmailTo: "ops@example.com"}
+	assert.NoError(t, svc.prepare(&good, now))
+	assert.Nil(t, good.NextRunAt, "disabled schedules are not due")
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+// Outcomes of a retention action
+const (
+	RetentionPlanned = "planned" // the dry run's, or a policy that is not enforced
+	RetentionDone    = "done"
+	RetentionFailed  = "failed"
+	// RetentionSkipped is for jobs finalized since the run was planned
+	RetentionSkipped = "skipped"
+)
+
+// legacyArchiveWorkspace names the archive folder of jobs of the legacy,
+// unnamed workspace
+const legacyArchiveWorkspace = "legacy"
+
+// RetentionAction is what a policy does, or would do, to a job
+type RetentionAction struct {
+	JobID     string `json:"job_id"`
+	Workspace string `json:"workspace"`
+	PolicyID  uint   `json:"policy_id"`
+	Target    string `json:"target"`
+	Action    string `json:"action"`
+	AgeDays   int    `json:"age_days"`
+	// Enforced is whether the policy applies it; other actions are only reported
+	Enforced   bool   `json:"enforced"`
+	Result     string `json:"result"`
+	ArchiveURI string `json:"archive_uri,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RetentionReport lists the retention actions of a run
+type RetentionReport struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	DryRun      bool              `json:"dry_run"`
+	JobsChecked int               `json:"jobs_checked"`
+	Actions     []RetentionAction `json:"actions"`
+}
+
+// RetentionService applies retention policies: every interval it deletes or
+// archives the audio or the whole of jobs older than their policy allows
+type RetentionService interface {
+	Start()
+	Stop()
+	// Preview reports what the policies would do now, without doing it
+	Preview(ctx context.Context) (*RetentionReport, error)
+	// Enforce applies the enforced policies now
+	Enforce(ctx context.Context) (*RetentionReport, error)
+}
+
+type retentionService struct {
+	uploadDir     string
+	interval      time.Duration
+	retentionRepo repository.RetentionRepository
+	jobRepo       repository.JobRepository
+	noteRepo      repository.NoteRepository
+	fileService   FileService
+	deleter       JobDeleter
+
+	// mu serialises runs so a job is never archived twice
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewRetentionService(cfg *config.Config, retentionRepo repository.RetentionRepository, jobRepo repository.JobRepository,
+	noteRepo repository.NoteRepository, fileService FileService, deleter JobDeleter) RetentionService {
+	return &retentionService{
+		uploadDir:     cfg.UploadDir,
+		interval:      time.Duration(cfg.RetentionIntervalHours) * time.Hour,
+		retentionRepo: retentionRepo,
+		jobRepo:       jobRepo,
+		noteRepo:      noteRepo,
+		fileService:   fileService,
+		deleter:       deleter,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start enforces the policies every interval; it does nothing when the
+// interval is 0
+func (s *retentionService) Start() {
+	if s.interval <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop halts enforcement and waits for a running pass to finish
+func (s *retentionService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *retentionService) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if _, err := s.Enforce(context.Background()); err != nil {
+				logger.Error("Retention enforcement failed", "error", err)
+			}
+		}
+	}
+}
+
+func (s *retentionService) Preview(ctx context.Context) (*RetentionReport, error) {
+	return s.plan(ctx, true)
+}
+
+func (s *retentionService) Enforce(ctx context.Context) (*RetentionReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, err := s.plan(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	done, failed := 0, 0
+	for i := range report.Actions {
+		action := &report.Actions[i]
+		if !action.Enforced {
+			continue
+		}
+		err := s.apply(ctx, action)
+		if errors.Is(err, repository.ErrJobFinalized) {
+			action.Result = RetentionSkipped
+			continue
+		}
+		if err != nil {
+			action.Result = RetentionFailed
+			action.Error = err.Error()
+			failed++
+			logger.Warn("Retention action failed", "job_id", action.JobID, "policy_id", action.PolicyID, "target", action.Target, "action", action.Action, "error", err)
+			continue
+		}
+		action.Result = RetentionDone
+		done++
+	}
+	if done > 0 || failed > 0 {
+		logger.Info("Retention policies enforced", "jobs_checked", report.JobsChecked, "done", done, "failed", failed)
+	}
+	return report, nil
+}
+
+// plan matches every job with the policies applying to it
+func (s *retentionService) plan(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	now := time.Now()
+	report := &RetentionReport{GeneratedAt: now, DryRun: dryRun, Actions: []RetentionAction{}}
+	policies, err := s.retentionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return report, nil
+	}
+	minDays := policies[0].AfterDays
+	for _, policy := range policies[1:] {
+		minDays = min(minDays, policy.AfterDays)
+	}
+	jobs, err := s.retentionRepo.ListCandidates(ctx, now.AddDate(0, 0, -minDays))
+	if err != nil {
+		return nil, err
+	}
+	report.JobsChecked = len(jobs)
+
+	for i := range jobs {
+		job := &jobs[i]
+		age := int(now.Sub(job.CreatedAt).Hours() / 24)
+		policy := matchRetentionPolicy(policies, job, models.RetentionTargetJob)
+		if policy == nil || age < policy.AfterDays {
+			policy = matchRetentionPolicy(policies, job, models.RetentionTargetAudio)
+			if policy == nil || age < policy.AfterDays || job.AudioExpiredAt != nil || len(localAudioPaths(job)) == 0 {
+				continue
+			}
+		}
+		report.Actions = append(report.Actions, RetentionAction{
+			JobID:     job.ID,
+			Workspace: job.Workspace,
+			PolicyID:  policy.ID,
+			Target:    policy.Target,
+			Action:    policy.Action,
+			AgeDays:   age,
+			Enforced:  policy.Enforced,
+			Result:    RetentionPlanned,
+		})
+	}
+	return report, nil
+}
+
+// matchRetentionPolicy returns the policy for target that applies to job: of
+// the job's organization, its profile's own policy before the policy for every
+// profile, and the shortest of equally specific ones
+func matchRetentionPolicy(policies []models.RetentionPolicy, job *models.TranscriptionJob, target string) *models.RetentionPolicy {
+	organizationID := models.WorkspaceOrganization(job.Workspace)
+	var match *models.RetentionPolicy
+	for i := range policies {
+		policy := &policies[i]
+		if policy.Target != target || policy.OrganizationID != organizationID {
+			continue
+		}
+		if !policy.AppliesTo(job) {
+			continue
+		}
+		if match == nil || policy.Specificity() > match.Specificity() ||
+			policy.Specificity() == match.Specificity() && policy.AfterDays < match.AfterDays {
+			match = policy
+		}
+	}
+	return match
+}
+
+// localAudioPaths returns the job's uploaded audio files, and the video its
+// audio was extracted from
+func localAudioPaths(job *models.TranscriptionJob) []string {
+	var paths []string
+	if job.IsMultiTrack {
+		for _, track := range job.MultiTrackFiles {
+			paths = append(paths, track.FilePath)
+		}
+	} else if job.AudioPath != "" {
+		paths = append(paths, job.AudioPath)
+	}
+	if job.MergedAudioPath != nil && *job.MergedAudioPath != "" {
+		paths = append(paths, *job.MergedAudioPath)
+	}
+	if job.AupFilePath != nil && *job.AupFilePath != "" {
+		paths = append(paths, *job.AupFilePath)
+	}
+	if job.VideoPath != nil && *job.VideoPath != "" {
+		paths = append(paths, *job.VideoPath)
+	}
+	return paths
+}
+
+func (s *retentionService) apply(ctx context.Context, action *RetentionAction) error {
+	policy, err := s.retentionRepo.FindByID(ctx, action.PolicyID)
+	if err != nil {
+		return fmt.Errorf("failed to load policy: %w", err)
+	}
+	// The candidate list holds only some columns, so work on the full job
+	job, err := s.jobRepo.FindWithAssociations(ctx, action.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to load job: %w", err)
+	}
+	// Finalized transcripts are kept, even if finalized since the plan
+	if job.FinalizedAt != nil {
+		return repository.ErrJobFinalized
+	}
+
+	if policy.Action == models.RetentionActionArchive {
+		prefix := archivePrefix(policy.ArchiveURI, job)
+		if job.AudioExpiredAt == nil {
+			if err := s.archiveAudio(ctx, job, prefix, policy.StorageClass); err != nil {
+				return err
+			}
+		}
+		if policy.Target == models.RetentionTargetJob {
+			if err := s.archiveRecord(ctx, job, prefix, policy.StorageClass); err != nil {
+				return err
+			}
+		}
+		action.ArchiveURI = prefix
+	}
+
+	if policy.Target == models.RetentionTargetJob {
+		// Archiving takes a while; the job may have been finalized meanwhile
+		if err := s.jobRepo.EnsureUnfinalized(ctx, job.ID); err != nil {
+			return err
+		}
+		return s.deleter.Delete(ctx, job)
+	}
+	// Record the expiry before removing the files, so a failed write leaves
+	// the audio in place rather than a job pointing at missing files
+	expiredAt := time.Now()
+	job.AudioExpiredAt = &expiredAt
+	if action.ArchiveURI != "" {
+		job.AudioArchiveURI = &action.ArchiveURI
+	}
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return err
+	}
+	s.deleter.RemoveAudio(job)
+	return nil
+}
+
+// archivePrefix is the s3:// folder a job's files are archived to
+func archivePrefix(archiveURI string, job *models.TranscriptionJob) string {
+	workspace := job.Workspace
+	if workspace == "" {
+		workspace = legacyArchiveWorkspace
+	}
+	return strings.TrimSuffix(archiveURI, "/") + "/" + workspace + "/" + job.ID + "/"
+}
+
+// archiveAudio copies the job's uploaded audio files under prefix
+func (s *retentionService) archiveAudio(ctx context.Context, job *models.TranscriptionJob, prefix, storageClass string) error {
+	for _, path := range localAudioPaths(job) {
+		scoped, err := s.fileService.ScopePath(s.uploadDir, job.Workspace, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(scoped)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = s.fileService.UploadObject(ctx, prefix+filepath.Base(scoped), f, storageClass)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveRecord copies the job's record and notes under prefix as job.json
+func (s *retentionService) archiveRecord(ctx context.Context, job *models.TranscriptionJob, prefix, storageClass string) error {
+	notes, err := s.noteRepo.ListByJob(ctx, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+	record, err := json.MarshalIndent(map[string]interface{}{
+		"job":         job,
+		"notes":       notes,
+		"archived_at": time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.fileService.UploadObject(ctx, prefix+"job.json", bytes.NewReader(record), storageClass)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeArchiveStore records uploaded objects and their storage class
+type fakeArchiveStore struct {
+	FileService
+	objects map[string]string
+}
+
+func (f *fakeArchiveStore) ScopePath(root, workspace, path string) (string, error) {
+	return ScopePath(root, workspace, path)
+}
+
+func (f *fakeArchiveStore) UploadObject(ctx context.Context, url string, body io.ReadSeeker, storageClass string) error {
+	if _, err := io.ReadAll(body); err != nil {
+		return err
+	}
+	f.objects[url] = storageClass
+	return nil
+}
+
+// fakeJobDeleter records the jobs and audio it was asked to remove
+type fakeJobDeleter struct {
+	db      *gorm.DB
+	deleted []string
+	removed []string
+}
+
+func (d *fakeJobDeleter) Delete(ctx context.Context, job *models.TranscriptionJob) error {
+	d.deleted = append(d.deleted, job.ID)
+	return d.db.Delete(&models.TranscriptionJob{}, "id = ?", job.ID).Error
+}
+
+func (d *fakeJobDeleter) RemoveAudio(job *models.TranscriptionJob) {
+	d.removed = append(d.removed, job.ID)
+}
+
+func TestRetention(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.MultiTrackFile{}, &models.JobTag{}, &models.Note{}, &models.RetentionPolicy{}))
+
+	uploadDir := t.TempDir()
+	audio := filepath.Join(uploadDir, "audio.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+	profile, legalHold := "meetings", "legal-hold"
+	days := func(n int) time.Time { return time.Now().AddDate(0, 0, -n) }
+
+	jobs := []models.TranscriptionJob{
+		{ID: "old", Status: models.StatusCompleted, AudioPath: audio, CreatedAt: days(100)},
+		{ID: "recent", Status: models.StatusCompleted, AudioPath: audio, CreatedAt: days(40)},
+		{ID: "new", Status: models.StatusCompleted, AudioPath: audio, CreatedAt: days(5)},
+		{ID: "profiled", Status: models.StatusCompleted, AudioPath: audio, ProfileID: &profile, CreatedAt: days(20)},
+		{ID: "held", Status: models.StatusCompleted, AudioPath: audio, ProfileID: &profile, CreatedAt: days(20)},
+		{ID: "pending", Status: models.StatusPending, AudioPath: audio, CreatedAt: days(100)},
+		{ID: "other-org", Status: models.StatusCompleted, Workspace: "org-acme", AudioPath: audio, CreatedAt: days(100)},
+	}
+	for i := range jobs {
+		require.NoError(t, db.Create(&jobs[i]).Error)
+	}
+	require.NoError(t, db.Create(&models.JobTag{TranscriptionJobID: "held", TagID: legalHold}).Error)
+	policies := []models.RetentionPolicy{
+		{Target: models.RetentionTargetAudio, Action: models.RetentionActionArchive, AfterDays: 30,
+			ArchiveURI: "s3://archive/scriberr", StorageClass: "GLACIER"},
+		{Target: models.RetentionTargetAudio, Action: models.RetentionActionDelete, AfterDays: 10, ProfileID: &profile},
+		{Target: models.RetentionTargetJob, Action: models.RetentionActionDelete, AfterDays: 90},
+		{Target: models.RetentionTargetAudio, Action: models.RetentionActionDelete, AfterDays: 3650, TagID: &legalHold},
+	}
+	for i := range policies {
+		require.NoError(t, db.Create(&policies[i]).Error)
+	}
+
+	retentionRepo := repository.NewRetentionRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	files := &fakeArchiveStore{objects: map[string]string{}}
+	deleter := &fakeJobDeleter{db: db}
+	svc := NewRetentionService(&config.Config{UploadDir: uploadDir}, retentionRepo, jobRepo,
+		repository.NewNoteRepository(db), files, deleter)
+
+	actionsByJob := func(report *RetentionReport) map[string]RetentionAction {
+		actions := map[string]RetentionAction{}
+		for _, action := range report.Actions {
+			actions[action.JobID] = action
+		}
+		return actions
+	}
+
+	// Nothing is enforced yet, so the preview and enforcement only report
+	report, err := svc.Preview(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	actions := actionsByJob(report)
+	assert.Len(t, actions, 3, "new, pending and other organizations' jobs are not touched")
+	assert.Equal(t, policies[2].ID, actions["old"].PolicyID, "a job policy wins over an audio policy")
+	assert.Equal(t, policies[0].ID, actions["recent"].PolicyID)
+	assert.Equal(t, policies[1].ID, actions["profiled"].PolicyID, "a profile's own policy wins")
+	assert.NotContains(t, actions, "held", "a tag's policy wins over a profile's")
+	for _, action := range actions {
+		assert.Equal(t, RetentionPlanned, action.Result)
+		assert.False(t, action.Enforced)
+	}
+
+	report, err = svc.Enforce(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, report.Actions, 3)
+	assert.Empty(t, deleter.deleted)
+	assert.Empty(t, deleter.removed)
+	assert.Empty(t, files.objects)
+
+	require.NoError(t, db.Model(&models.RetentionPolicy{}).Where("1 = 1").Update("enforced", true).Error)
+	report, err = svc.Enforce(context.Background())
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	actions = actionsByJob(report)
+	for id, action := range actions {
+		assert.Equal(t, RetentionDone, action.Result, id)
+	}
+	assert.Equal(t, []string{"old"}, deleter.deleted)
+	assert.ElementsMatch(t, []string{"recent", "profiled"}, deleter.removed)
+	assert.Equal(t, map[string]string{"s3://archive/scriberr/legacy/recent/audio.wav": "GLACIER"}, files.objects)
+	assert.Equal(t, "s3://archive/scriberr/legacy/recent/", actions["recent"].ArchiveURI)
+
+	recent, err := jobRepo.FindByID(context.Background(), "recent")
+	require.NoError(t, err)
+	require.NotNil(t, recent.AudioExpiredAt)
+	require.NotNil(t, recent.AudioArchiveURI)
+	assert.Equal(t, "s3://archive/scriberr/legacy/recent/", *recent.AudioArchiveURI)
+	profiled, err := jobRepo.FindByID(context.Background(), "profiled")
+	require.NoError(t, err)
+	assert.NotNil(t, profiled.AudioExpiredAt)
+	assert.Nil(t, profiled.AudioArchiveURI)
+
+	// Expired audio is not removed again
+	report, err = svc.Preview(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Actions)
+}
+
+// finalizingRetentionRepo finalizes a job right after the candidates are
+// listed, as a reviewer finalizing it during a run would
+type finalizingRetentionRepo struct {
+	repository.RetentionRepository
+	db     *gorm.DB
+	jobIDs []string
+}
+
+func (r *finalizingRetentionRepo) ListCandidates(ctx context.Context, cutoff time.Time) ([]models.TranscriptionJob, error) {
+	jobs, err := r.RetentionRepository.ListCandidates(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return jobs, r.db.Model(&models.TranscriptionJob{}).Where("id IN ?", r.jobIDs).Update("finalized_at", &now).Error
+}
+
+func TestRetentionSkipsJobsFinalizedDuringRun(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.MultiTrackFile{}, &models.JobTag{}, &models.Note{}, &models.RetentionPolicy{}))
+
+	uploadDir := t.TempDir()
+	audio := filepath.Join(uploadDir, "audio.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+	for _, job := range []models.TranscriptionJob{
+		{ID: "old", Status: models.StatusCompleted, AudioPath: audio, CreatedAt: time.Now().AddDate(0, 0, -100)},
+		{ID: "audio", Status: models.StatusCompleted, AudioPath: audio, CreatedAt: time.Now().AddDate(0, 0, -40)},
+	} {
+		require.NoError(t, db.Create(&job).Error)
+	}
+	for _, policy := range []models.RetentionPolicy{
+		{Target: models.RetentionTargetAudio, Action: models.RetentionActionDelete, AfterDays: 30, Enforced: true},
+		{Target: models.RetentionTargetJob, Action: models.RetentionActionDelete, AfterDays: 90, Enforced: true},
+	} {
+		require.NoError(t, db.Create(&policy).Error)
+	}
+
+	jobRepo := repository.NewJobRepository(db)
+	deleter := &fakeJobDeleter{db: db}
+	retentionRepo := &finalizingRetentionRepo{RetentionRepository: repository.NewRetentionRepository(db), db: db, jobIDs: []string{"old", "audio"}}
+	svc := NewRetentionService(&config.Config{UploadDir: uploadDir}, retentionRepo, jobRepo,
+		repository.NewNoteRepository(db), &fakeArchiveStore{objects: map[string]string{}}, deleter)
+	report, err := svc.Enforce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Actions, 2)
+	for _, action := range report.Actions {
+		assert.Equal(t, RetentionSkipped, action.Result, action.JobID)
+	}
+
+	assert.Empty(t, deleter.deleted, "a finalized job is kept")
+	assert.Empty(t, deleter.removed, "a finalized job's audio is kept")
+	job, err := jobRepo.FindByID(context.Background(), "audio")
+	require.NoError(t, err)
+	assert.Nil(t, job.AudioExpiredAt)
+}
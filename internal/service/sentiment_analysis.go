@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"scriberr/internal/llm"
+)
+
+// sentimentBatchSize is the number of segments sent to the LLM per request
+const sentimentBatchSize = 40
+
+// Sentiment labels of a segment
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
+// sentimentEmotions are the emotions a segment may be labelled with
+var sentimentEmotions = []string{"neutral", "joy", "gratitude", "anger", "frustration", "sadness", "fear", "surprise", "confusion"}
+
+// SegmentSentiment is the sentiment and dominant emotion of one transcript segment
+type SegmentSentiment struct {
+	Segment   int     `json:"segment"` // index into the transcript's segments
+	Start     float64 `json:"start"`
+	End       float64 `json:"end"`
+	Speaker   string  `json:"speaker,omitempty"`
+	Sentiment string  `json:"sentiment"` // positive, neutral or negative
+	Score     float64 `json:"score"`     // -1 (most negative) to 1 (most positive)
+	Emotion   string  `json:"emotion"`
+}
+
+// transcriptSegment is a segment of a stored transcript, as sent to the LLM
+type transcriptSegment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+	Speaker string  `json:"speaker"`
+}
+
+// AnalyzeSentiment labels every segment of a transcript with its sentiment and
+// emotion using the given LLM. Segments the model leaves out are omitted from
+// the result rather than guessed.
+func AnalyzeSentiment(ctx context.Context, svc llm.Service, model string, transcript *string) ([]SegmentSentiment, error) {
+	if transcript == nil {
+		return nil, errors.New("transcript is empty")
+	}
+	var parsed struct {
+		Segments []transcriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	if len(parsed.Segments) == 0 {
+		return nil, errors.New("transcript has no segments")
+	}
+
+	results := make([]SegmentSentiment, 0, len(parsed.Segments))
+	for start := 0; start < len(parsed.Segments); start += sentimentBatchSize {
+		end := min(start+sentimentBatchSize, len(parsed.Segments))
+		batch, err := analyzeSentimentBatch(ctx, svc, model, parsed.Segments, start, end)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func analyzeSentimentBatch(ctx context.Context, svc llm.Service, model string, segments []transcriptSegment, start, end int) ([]SegmentSentiment, error) {
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		seg := segments[i]
+		fmt.Fprintf(&b, "[%d] ", i)
+		if seg.Speaker != "" {
+			fmt.Fprintf(&b, "%s: ", seg.Speaker)
+		}
+		b.WriteString(strings.TrimSpace(seg.Text))
+		b.WriteString("\n")
+	}
+
+	prompt := fmt.Sprintf("Transcript segments:\n%s\nInstructions:\n"+
+		"For each numbered segment, rate the speaker's sentiment and dominant emotion. "+
+		"Reply with only a JSON array containing one object per segment: "+
+		`{"index": <segment number>, "sentiment": "positive"|"neutral"|"negative", "score": <number from -1 to 1>, "emotion": <one of %s>}.`,
+		b.String(), strings.Join(sentimentEmotions, ", "))
+
+	reply, err := complete(ctx, svc, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+	first := strings.Index(reply, "[")
+	last := strings.LastIndex(reply, "]")
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("expected a JSON array, got %q", reply)
+	}
+	var labels []struct {
+		Index     int     `json:"index"`
+		Sentiment string  `json:"sentiment"`
+		Score     float64 `json:"score"`
+		Emotion   string  `json:"emotion"`
+	}
+	if err := json.Unmarshal([]byte(reply[first:last+1]), &labels); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of segment labels: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	results := make([]SegmentSentiment, 0, len(labels))
+	for _, label := range labels {
+		if label.Index < start || label.Index >= end || seen[label.Index] {
+			continue
+		}
+		seen[label.Index] = true
+
+		seg := segments[label.Index]
+		results = append(results, SegmentSentiment{
+			Segment:   label.Index,
+			Start:     seg.Start,
+			End:       seg.End,
+			Speaker:   seg.Speaker,
+			Sentiment: normalizeSentiment(label.Sentiment, label.Score),
+			Score:     max(-1, min(1, label.Score)),
+			Emotion:   normalizeEmotion(label.Emotion),
+		})
+	}
+	return results, nil
+}
+
+// normalizeSentiment maps the model's label onto the known labels, deriving it
+// from the score when the label is unusable
+func normalizeSentiment(label string, score float64) string {
+	switch label = strings.ToLower(strings.TrimSpace(label)); label {
+	case SentimentPositive, SentimentNeutral, SentimentNegative:
+		return label
+	}
+	switch {
+	case score > 0.25:
+		return SentimentPositive
+	case score < -0.25:
+		return SentimentNegative
+	default:
+		return SentimentNeutral
+	}
+}
+
+func normalizeEmotion(emotion string) string {
+	emotion = strings.ToLower(strings.TrimSpace(emotion))
+	if slices.Contains(sentimentEmotions, emotion) {
+		return emotion
+	}
+	return "neutral"
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// minAttributeSpeechSeconds is the least speech a speaker's attributes are estimated from
+	minAttributeSpeechSeconds = 3.0
+	// minGenderConfidence is the probability below which the gender is left unknown
+	minGenderConfidence = 0.6
+)
+
+// ErrSpeakerAttributesDisabled is returned when recording speaker attributes in
+// a workspace that disabled them
+var ErrSpeakerAttributesDisabled = errors.New("speaker attributes are disabled in this workspace")
+
+// VoiceEstimate is a model's raw estimate for one speaker's voice: the age in
+// years and the probabilities of a female, male or child voice
+type VoiceEstimate struct {
+	Age           float64
+	Female        float64
+	Male          float64
+	Child         float64
+	SpeechSeconds float64
+}
+
+// SpeakerAttributeService keeps the coarse gender and age range estimated for
+// diarized speakers, an opt-in analysis for research datasets that workspaces
+// can disable
+type SpeakerAttributeService interface {
+	// Allowed reports whether speaker attributes may be estimated in a
+	// workspace; it fails closed when the settings cannot be read
+	Allowed(ctx context.Context, workspace string) bool
+	// Record replaces a job's speaker attributes with coarse ones from estimates
+	Record(ctx context.Context, jobID, workspace, model string, estimates map[string]VoiceEstimate) ([]models.SpeakerAttribute, error)
+	List(ctx context.Context, jobID string) ([]models.SpeakerAttribute, error)
+	Settings(ctx context.Context, workspace string) (*models.WorkspaceSettings, error)
+	// UpdateSettings saves a workspace's settings. Disabling speaker attributes
+	// deletes those already estimated in the workspace, returning how many.
+	UpdateSettings(ctx context.Context, settings *models.WorkspaceSettings) (int64, error)
+}
+
+type speakerAttributeService struct {
+	repo repository.SpeakerAttributeRepository
+}
+
+func NewSpeakerAttributeService(repo repository.SpeakerAttributeRepository) SpeakerAttributeService {
+	return &speakerAttributeService{repo: repo}
+}
+
+func (s *speakerAttributeService) Allowed(ctx context.Context, workspace string) bool {
+	settings, err := s.repo.GetSettings(ctx, workspace)
+	if err != nil {
+		logger.Warn("Failed to read workspace settings", "workspace", workspace, "error", err)
+		return false
+	}
+	return !settings.SpeakerAttributesDisabled
+}
+
+func (s *speakerAttributeService) Record(ctx context.Context, jobID, workspace, model string, estimates map[string]VoiceEstimate) ([]models.SpeakerAttribute, error) {
+	if !s.Allowed(ctx, workspace) {
+		return nil, ErrSpeakerAttributesDisabled
+	}
+	attributes := make([]models.SpeakerAttribute, 0, len(estimates))
+	for speaker, estimate := range estimates {
+		if estimate.SpeechSeconds < minAttributeSpeechSeconds {
+			continue
+		}
+		attribute := coarseSpeakerAttribute(estimate)
+		attribute.Speaker = speaker
+		attribute.Model = model
+		attributes = append(attributes, attribute)
+	}
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Speaker < attributes[j].Speaker })
+	if err := s.repo.Replace(ctx, jobID, attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func (s *speakerAttributeService) List(ctx context.Context, jobID string) ([]models.SpeakerAttribute, error) {
+	return s.repo.ListByJob(ctx, jobID)
+}
+
+func (s *speakerAttributeService) Settings(ctx context.Context, workspace string) (*models.WorkspaceSettings, error) {
+	return s.repo.GetSettings(ctx, workspace)
+}
+
+func (s *speakerAttributeService) UpdateSettings(ctx context.Context, settings *models.WorkspaceSettings) (int64, error) {
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		return 0, err
+	}
+	if !settings.SpeakerAttributesDisabled {
+		return 0, nil
+	}
+	removed, err := s.repo.DeleteInWorkspace(ctx, settings.Workspace)
+	if err != nil {
+		return 0, err
+	}
+	if removed > 0 {
+		logger.Info("Deleted speaker attributes of workspace", "workspace", settings.Workspace, "removed", removed)
+	}
+	return removed, nil
+}
+
+// coarseSpeakerAttribute reduces a raw estimate to a gender and an age range
+func coarseSpeakerAttribute(estimate VoiceEstimate) models.SpeakerAttribute {
+	attribute := models.SpeakerAttribute{Gender: models.SpeakerGenderUnknown, SpeechSeconds: estimate.SpeechSeconds}
+	if estimate.Child > estimate.Female && estimate.Child > estimate.Male {
+		// Children's voices do not tell their gender apart reliably
+		attribute.AgeRange = "child"
+		return attribute
+	}
+	gender, confidence := models.SpeakerGenderFemale, estimate.Female
+	if estimate.Male > estimate.Female {
+		gender, confidence = models.SpeakerGenderMale, estimate.Male
+	}
+	if confidence >= minGenderConfidence {
+		attribute.Gender, attribute.GenderConfidence = gender, confidence
+	}
+	switch {
+	case estimate.Age < 13:
+		attribute.AgeRange = "child"
+	case estimate.Age < 18:
+		attribute.AgeRange = "13-17"
+	case estimate.Age < 30:
+		attribute.AgeRange = "18-29"
+	case estimate.Age < 45:
+		attribute.AgeRange = "30-44"
+	case estimate.Age < 60:
+		attribute.AgeRange = "45-59"
+	default:
+		attribute.AgeRange = "60+"
+	}
+	return attribute
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCoarseSpeakerAttribute(t *testing.T) {
+	cases := []struct {
+		estimate VoiceEstimate
+		gender   string
+		ageRange string
+	}{
+		{VoiceEstimate{Age: 34.6, Female: 0.9, Male: 0.08, Child: 0.02}, models.SpeakerGenderFemale, "30-44"},
+		{VoiceEstimate{Age: 61, Female: 0.1, Male: 0.85, Child: 0.05}, models.SpeakerGenderMale, "60+"},
+		{VoiceEstimate{Age: 22, Female: 0.45, Male: 0.5, Child: 0.05}, models.SpeakerGenderUnknown, "18-29"},
+		{VoiceEstimate{Age: 9, Female: 0.2, Male: 0.1, Child: 0.7}, models.SpeakerGenderUnknown, "child"},
+		{VoiceEstimate{Age: 15, Female: 0.7, Male: 0.2, Child: 0.1}, models.SpeakerGenderFemale, "13-17"},
+	}
+	for _, c := range cases {
+		attribute := coarseSpeakerAttribute(c.estimate)
+		assert.Equal(t, c.gender, attribute.Gender, "%+v", c.estimate)
+		assert.Equal(t, c.ageRange, attribute.AgeRange, "%+v", c.estimate)
+	}
+}
+
+func TestSpeakerAttributes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.SpeakerAttribute{}, &models.WorkspaceSettings{}))
+	ctx := context.Background()
+	for _, job := range []models.TranscriptionJob{{ID: "a", Workspace: "team"}, {ID: "b", Workspace: "other"}} {
+		require.NoError(t, db.Create(&job).Error)
+	}
+
+	svc := NewSpeakerAttributeService(repository.NewSpeakerAttributeRepository(db))
+	assert.True(t, svc.Allowed(ctx, "team"), "allowed unless disabled")
+
+	estimates := map[string]VoiceEstimate{
+		"SPEAKER_01": {Age: 40, Male: 0.9, SpeechSeconds: 30},
+		"SPEAKER_00": {Age: 25, Female: 0.8, SpeechSeconds: 12},
+		"SPEAKER_02": {Age: 50, Female: 0.9, SpeechSeconds: 1.5},
+	}
+	attributes, err := svc.Record(ctx, "a", "team", "age-gender", estimates)
+	require.NoError(t, err)
+	require.Len(t, attributes, 2, "speakers with little speech are left out")
+	assert.Equal(t, "SPEAKER_00", attributes[0].Speaker)
+	_, err = svc.Record(ctx, "b", "other", "age-gender", estimates)
+	require.NoError(t, err)
+
+	// Disabling a workspace deletes its attributes and refuses new ones
+	removed, err := svc.UpdateSettings(ctx, &models.WorkspaceSettings{Workspace: "team", SpeakerAttributesDisabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed)
+	assert.False(t, svc.Allowed(ctx, "team"))
+	_, err = svc.Record(ctx, "a", "team", "age-gender", estimates)
+	assert.ErrorIs(t, err, ErrSpeakerAttributesDisabled)
+	stored, err := svc.List(ctx, "a")
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	stored, err = svc.List(ctx, "b")
+	require.NoError(t, err)
+	assert.Len(t, stored, 2, "other workspaces keep theirs")
+
+	_, err = svc.UpdateSettings(ctx, &models.WorkspaceSettings{Workspace: "team"})
+	require.NoError(t, err)
+	assert.True(t, svc.Allowed(ctx, "team"))
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+)
+
+// DirectoryUsage is the disk usage of a directory tree
+type DirectoryUsage struct {
+	Path  string `json:"path"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// WorkspaceStorage is the disk usage of a workspace's uploads
+type WorkspaceStorage struct {
+	Workspace string `json:"workspace"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// JobStorage is the size of a job's audio files on disk
+type JobStorage struct {
+	JobID      string  `json:"job_id"`
+	Title      *string `json:"title,omitempty"`
+	Workspace  string  `json:"workspace"`
+	Status     string  `json:"status"`
+	Files      int     `json:"files"`
+	AudioBytes int64   `json:"audio_bytes"`
+}
+
+// DownloadCacheUsage is the disk usage of audio downloaded from S3 or URLs
+type DownloadCacheUsage struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// StorageReport summarises what uses the server's disk
+type StorageReport struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Uploads     DirectoryUsage `json:"uploads"`
+	// Workspaces splits the uploads by workspace, largest first; the legacy
+	// workspace includes the quick transcription temp files
+	Workspaces  []WorkspaceStorage `json:"workspaces"`
+	Transcripts DirectoryUsage     `json:"transcripts"`
+	Backups     DirectoryUsage     `json:"backups"`
+	// Database counts the database file with its WAL and shared memory files
+	Database        DirectoryUsage     `json:"database"`
+	DatabaseReplica *DirectoryUsage    `json:"database_replica,omitempty"`
+	DownloadCache   DownloadCacheUsage `json:"download_cache"`
+	// JobsWithAudio and JobAudioBytes count the jobs with audio on disk;
+	// LargestJobs lists the largest of them
+	JobsWithAudio int          `json:"jobs_with_audio"`
+	JobAudioBytes int64        `json:"job_audio_bytes"`
+	LargestJobs   []JobStorage `json:"largest_jobs"`
+}
+
+// StorageCleanup reports what a storage cleanup removed
+type StorageCleanup struct {
+	DownloadsRemoved    int   `json:"downloads_removed"`
+	DownloadBytes       int64 `json:"download_bytes"`
+	PartialFilesRemoved int   `json:"partial_files_removed"`
+	PartialFileBytes    int64 `json:"partial_file_bytes"`
+	BytesReclaimed      int64 `json:"bytes_reclaimed"`
+	MaxAgeSeconds       int64 `json:"max_age_seconds"`
+}
+
+// StorageService reports the disk usage of uploads, transcripts, the database
+// and the download cache, and cleans up what can be recreated
+type StorageService interface {
+	// Report measures disk usage, listing the largest jobs up to limit
+	Report(ctx context.Context, limit int) (*StorageReport, error)
+	// Cleanup removes cached downloads and abandoned partial downloads older
+	// than maxAge, at least MinCleanupAge, keeping the files of jobs waiting or
+	// being processed and the downloads still being written
+	Cleanup(ctx context.Context, maxAge time.Duration) (*StorageCleanup, error)
+}
+
+// MinCleanupAge is the youngest a file removed by a storage cleanup may be,
+// so downloads that just finished are not removed before they are used
+const MinCleanupAge = 15 * time.Minute
+
+type storageService struct {
+	cfg         *config.Config
+	storageRepo repository.StorageRepository
+	fileService FileService
+}
+
+func NewStorageService(cfg *config.Config, storageRepo repository.StorageRepository, fileService FileService) StorageService {
+	return &storageService{
+		cfg:         cfg,
+		storageRepo: storageRepo,
+		fileService: fileService,
+	}
+}
+
+func (s *storageService) Report(ctx context.Context, limit int) (*StorageReport, error) {
+	report := &StorageReport{
+		GeneratedAt: time.Now(),
+		Uploads:     directoryUsage(s.cfg.UploadDir),
+		Transcripts: directoryUsage(s.cfg.TranscriptsDir),
+		Backups:     directoryUsage(s.cfg.BackupDir),
+		Database:    databaseUsage(s.cfg.DatabasePath),
+		LargestJobs: []JobStorage{},
+	}
+	if s.cfg.DatabaseReplicaPath != "" {
+		replica := databaseUsage(s.cfg.DatabaseReplicaPath)
+		report.DatabaseReplica = &replica
+	}
+	report.DownloadCache.Files, report.DownloadCache.Bytes = s.fileService.DownloadCacheUsage()
+	report.Workspaces = s.workspaceUsage()
+
+	jobs, err := s.storageRepo.ListJobFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sizes []JobStorage
+	for i := range jobs {
+		job := &jobs[i]
+		if job.AudioExpiredAt != nil {
+			continue
+		}
+		size := JobStorage{JobID: job.ID, Title: job.Title, Workspace: job.Workspace, Status: string(job.Status)}
+		for _, path := range localAudioPaths(job) {
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				size.Files++
+				size.AudioBytes += info.Size()
+			}
+		}
+		if size.Files == 0 {
+			continue
+		}
+		report.JobsWithAudio++
+		report.JobAudioBytes += size.AudioBytes
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].AudioBytes > sizes[j].AudioBytes })
+	report.LargestJobs = append(report.LargestJobs, sizes[:min(limit, len(sizes))]...)
+	return report, nil
+}
+
+// workspaceUsage returns the upload usage of each workspace, largest first
+func (s *storageService) workspaceUsage() []WorkspaceStorage {
+	workspaces := []string{""}
+	entries, err := os.ReadDir(filepath.Join(s.cfg.UploadDir, workspacesDirName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("Failed to list upload workspaces", "error", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && workspaceNamePattern.MatchString(entry.Name()) {
+			workspaces = append(workspaces, entry.Name())
+		}
+	}
+
+	usage := []WorkspaceStorage{}
+	for _, workspace := range workspaces {
+		bytes, err := s.fileService.WorkspaceUsage(s.cfg.UploadDir, workspace)
+		if err != nil {
+			logger.Warn("Failed to measure workspace uploads", "workspace", workspace, "error", err)
+			continue
+		}
+		usage = append(usage, WorkspaceStorage{Workspace: workspace, Bytes: bytes})
+	}
+	sort.SliceStable(usage, func(i, j int) bool { return usage[i].Bytes > usage[j].Bytes })
+	return usage
+}
+
+func (s *storageService) Cleanup(ctx context.Context, maxAge time.Duration) (*StorageCleanup, error) {
+	maxAge = max(maxAge, MinCleanupAge)
+	jobs, err := s.storageRepo.ListActiveJobFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := map[string]bool{}
+	for i := range jobs {
+		for _, path := range localAudioPaths(&jobs[i]) {
+			active[filepath.Clean(path)] = true
+		}
+	}
+
+	cleanup := &StorageCleanup{MaxAgeSeconds: int64(maxAge / time.Second)}
+	cleanup.DownloadsRemoved, cleanup.DownloadBytes = s.fileService.CleanDownloadCache(maxAge, func(path string) bool {
+		return active[filepath.Clean(path)]
+	})
+
+	// Downloads write to a .part file next to their destination and move it
+	// into place when done; old ones were left behind by crashes
+	cutoff := time.Now().Add(-maxAge)
+	filepath.WalkDir(s.cfg.UploadDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasPrefix(d.Name(), ".") || !strings.HasSuffix(d.Name(), ".part") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().After(cutoff) || s.fileService.Downloading(partialDestination(path)) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			logger.Warn("Failed to remove partial download", "path", path, "error", err)
+			return nil
+		}
+		cleanup.PartialFilesRemoved++
+		cleanup.PartialFileBytes += info.Size()
+		return nil
+	})
+
+	cleanup.BytesReclaimed = cleanup.DownloadBytes + cleanup.PartialFileBytes
+	if cleanup.BytesReclaimed > 0 {
+		logger.Info("Storage cleaned up", "downloads_removed", cleanup.DownloadsRemoved,
+			"partial_files_removed", cleanup.PartialFilesRemoved, "bytes_reclaimed", cleanup.BytesReclaimed)
+	}
+	return cleanup, nil
+}
+
+// partialDestination is the file a partial download, named .<name>.<random>.part,
+// is moved to once complete
+func partialDestination(path string) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "."), ".part")
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return filepath.Join(filepath.Dir(path), name)
+}
+
+// directoryUsage walks a directory tree, counting what it can read
+func directoryUsage(root string) DirectoryUsage {
+	usage := DirectoryUsage{Path: root}
+	if root == "" {
+		return usage
+	}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			usage.Files++
+			usage.Bytes += info.Size()
+		}
+		return nil
+	})
+	return usage
+}
+
+// databaseUsage measures an SQLite database with its -wal and -shm files
+func databaseUsage(path string) DirectoryUsage {
+	usage := DirectoryUsage{Path: path}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if info, err := os.Stat(path + suffix); err == nil {
+			usage.Files++
+			usage.Bytes += info.Size()
+		}
+	}
+	return usage
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestStorageReport(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.MultiTrackFile{}))
+
+	dataDir := t.TempDir()
+	cfg := &config.Config{
+		UploadDir:      filepath.Join(dataDir, "uploads"),
+		TranscriptsDir: filepath.Join(dataDir, "transcripts"),
+		BackupDir:      filepath.Join(dataDir, "backups"),
+		DatabasePath:   filepath.Join(dataDir, "scriberr.db"),
+	}
+	write := func(path string, size int) string {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+		return path
+	}
+	write(cfg.DatabasePath, 100)
+	write(cfg.DatabasePath+"-wal", 20)
+	write(filepath.Join(cfg.TranscriptsDir, "a.json"), 5)
+	small := write(filepath.Join(cfg.UploadDir, "small.wav"), 10)
+	large := write(filepath.Join(cfg.UploadDir, "workspaces", "team", "large.wav"), 300)
+	merged := write(filepath.Join(cfg.UploadDir, "workspaces", "team", "merged.mp3"), 50)
+	expired := filepath.Join(cfg.UploadDir, "expired.wav")
+	now := time.Now()
+	for _, job := range []models.TranscriptionJob{
+		{ID: "small", Status: models.StatusCompleted, AudioPath: small},
+		{ID: "large", Status: models.StatusCompleted, Workspace: "team", AudioPath: large, MergedAudioPath: &merged},
+		{ID: "s3", Status: models.StatusCompleted, AudioPath: "s3://bucket/audio.wav"},
+		{ID: "expired", Status: models.StatusCompleted, AudioPath: expired, AudioExpiredAt: &now},
+	} {
+		require.NoError(t, db.Create(&job).Error)
+	}
+
+	files := &fileService{downloadedFiles: map[string]time.Time{}}
+	cached := write(filepath.Join(cfg.UploadDir, "cached.wav"), 40)
+	files.saveDownloadedFiles(cached)
+	svc := NewStorageService(cfg, repository.NewStorageRepository(db), files)
+
+	report, err := svc.Report(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, DirectoryUsage{Path: cfg.DatabasePath, Files: 2, Bytes: 120}, report.Database)
+	assert.Equal(t, DirectoryUsage{Path: cfg.TranscriptsDir, Files: 1, Bytes: 5}, report.Transcripts)
+	assert.Equal(t, DirectoryUsage{Path: cfg.UploadDir, Files: 4, Bytes: 400}, report.Uploads)
+	assert.Equal(t, []WorkspaceStorage{{Workspace: "team", Bytes: 350}, {Workspace: "", Bytes: 50}}, report.Workspaces)
+	assert.Equal(t, DownloadCacheUsage{Files: 1, Bytes: 40}, report.DownloadCache)
+	assert.Equal(t, 2, report.JobsWithAudio)
+	assert.Equal(t, int64(360), report.JobAudioBytes)
+	require.Len(t, report.LargestJobs, 1)
+	assert.Equal(t, JobStorage{JobID: "large", Workspace: "team", Status: "completed", Files: 2, AudioBytes: 350}, report.LargestJobs[0])
+
+	// Recent downloads survive a cleanup with a max age
+	partial := write(filepath.Join(cfg.UploadDir, ".audio.wav.123.part"), 7)
+	cleanup, err := svc.Cleanup(context.Background(), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &StorageCleanup{MaxAgeSeconds: 3600}, cleanup)
+	assert.FileExists(t, cached)
+
+	// Neither the audio of a job being processed nor a download still being
+	// written is removed, however old
+	inUse := write(filepath.Join(cfg.UploadDir, "in-use.wav"), 30)
+	files.saveDownloadedFiles(inUse)
+	require.NoError(t, db.Create(&models.TranscriptionJob{ID: "active", Status: models.StatusProcessing, AudioPath: inUse}).Error)
+	writing := write(filepath.Join(cfg.UploadDir, ".writing.wav.456.part"), 9)
+	files.startWriting(filepath.Join(cfg.UploadDir, "writing.wav"))
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, path := range []string{partial, writing} {
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+	files.downloadedFiles[cached] = old
+	files.downloadedFiles[inUse] = old
+	cleanup, err = svc.Cleanup(context.Background(), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &StorageCleanup{DownloadsRemoved: 1, DownloadBytes: 40, PartialFilesRemoved: 1, PartialFileBytes: 7, BytesReclaimed: 47, MaxAgeSeconds: 3600}, cleanup)
+	assert.NoFileExists(t, cached)
+	assert.NoFileExists(t, partial)
+	assert.FileExists(t, small)
+	assert.FileExists(t, inUse)
+	assert.FileExists(t, writing)
+	assert.Len(t, files.downloadedFiles, 1)
+
+	// A max age below the minimum is raised to it
+	cleanup, err = svc.Cleanup(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(MinCleanupAge/time.Second), cleanup.MaxAgeSeconds)
+}
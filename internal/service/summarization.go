@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+)
+
+// summaryChunkSegments is the number of segments condensed per request when a
+// transcript is too long to summarize at once. Chunks are cut by segment count
+// rather than length so that editing a segment changes only its own chunk, and
+// the notes of every other chunk are served from the LLM cache.
+const summaryChunkSegments = 80
+
+// SummarizeTranscript applies a summary template's instructions to a transcript.
+// Transcripts longer than a chapter window are first condensed chunk by chunk
+// into notes, and the instructions are applied to the notes.
+func SummarizeTranscript(ctx context.Context, svc llm.Service, model string, transcript *string, instructions string) (string, error) {
+	text := TranscriptPlainText(transcript)
+	if text == "" {
+		return "", errors.New("transcript is empty")
+	}
+	if len(text) <= chapterWindowChars {
+		return complete(ctx, svc, model, fmt.Sprintf("Transcript:\n%s\n\nInstructions:\n%s", text, instructions))
+	}
+
+	var parsed struct {
+		Segments []transcriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	segments := parsed.Segments
+
+	var notes strings.Builder
+	for start := 0; start < len(segments); start += summaryChunkSegments {
+		end := min(start+summaryChunkSegments, len(segments))
+		var b strings.Builder
+		for _, seg := range segments[start:end] {
+			if seg.Speaker != "" {
+				fmt.Fprintf(&b, "%s: ", seg.Speaker)
+			}
+			b.WriteString(strings.TrimSpace(seg.Text))
+			b.WriteString("\n")
+		}
+		prompt := fmt.Sprintf("Transcript excerpt:\n%s\nInstructions:\n"+
+			"Write concise notes on this excerpt of a longer transcript: the topics discussed, "+
+			"decisions, figures, names and any commitments, attributed to speakers where known. "+
+			"Reply with only the notes.", b.String())
+		part, err := complete(ctx, svc, model, prompt)
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize segments %d-%d: %w", start, end-1, err)
+		}
+		fmt.Fprintf(&notes, "Part %d:\n%s\n\n", start/summaryChunkSegments+1, part)
+	}
+
+	return complete(ctx, svc, model, fmt.Sprintf("Notes on consecutive parts of a transcript:\n%s"+
+		"Instructions:\n%s", notes.String(), instructions))
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+// +build darwin
+
+package service
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureSandboxedCmd runs the command in its own process group and makes
+// context cancellation kill the whole group, not just the direct child.
+func configureSandboxedCmd(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
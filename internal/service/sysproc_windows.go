@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package service
+
+import "os/exec"
+
+// configureSandboxedCmd is a no-op on Windows; exec.CommandContext still
+// kills the direct child when the context is cancelled.
+func configureSandboxedCmd(cmd *exec.Cmd) {
+	// No special attributes set on Windows here
+}
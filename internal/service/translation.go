@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+)
+
+// translationWindowChars bounds the transcript text translated per request. It is
+// smaller than the chaptering window because the reply is as long as the input.
+const translationWindowChars = 6000
+
+// Translation is a transcript translated segment by segment
+type Translation struct {
+	Language string `json:"language"`
+	// Segments holds one translated line per transcript segment, "" where the LLM
+	// returned none
+	Segments []string `json:"segments"`
+}
+
+// TranslateTranscript translates each segment of a transcript into language using
+// the given LLM, keeping the translation aligned with the segments
+func TranslateTranscript(ctx context.Context, svc llm.Service, model string, transcript *string, language string) (*Translation, error) {
+	if transcript == nil {
+		return nil, errors.New("transcript is empty")
+	}
+	var parsed struct {
+		Segments []transcriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(*transcript), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	segments := parsed.Segments
+	if len(segments) == 0 {
+		return nil, errors.New("transcript has no segments")
+	}
+
+	translation := &Translation{Language: language, Segments: make([]string, len(segments))}
+	translated := 0
+	for start := 0; start < len(segments); {
+		end := start
+		size := 0
+		for end < len(segments) && (end == start || size+len(segments[end].Text) <= translationWindowChars) {
+			size += len(segments[end].Text)
+			end++
+		}
+
+		n, err := translateWindow(ctx, svc, model, segments, start, end, language, translation.Segments)
+		if err != nil {
+			return nil, err
+		}
+		translated += n
+		start = end
+	}
+	if translated == 0 {
+		return nil, errors.New("the LLM returned no translations")
+	}
+	return translation, nil
+}
+
+// translateWindow translates segments[start:end] into out, returning the number
+// of segments translated
+func translateWindow(ctx context.Context, svc llm.Service, model string, segments []transcriptSegment, start, end int, language string, out []string) (int, error) {
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		fmt.Fprintf(&b, "[%d] %s\n", i, strings.TrimSpace(segments[i].Text))
+	}
+
+	prompt := fmt.Sprintf("Transcript segments:\n%s\nInstructions:\n"+
+		"Translate each segment into %s for a language learner reading the original alongside it. "+
+		"Translate every segment separately, keeping the meaning and register of the original; do not merge, split or skip segments. "+
+		`Reply with only a JSON array of objects ordered by segment: {"segment": <segment number>, "text": "<translation>"}.`,
+		b.String(), language)
+
+	reply, err := complete(ctx, svc, model, prompt)
+	if err != nil {
+		return 0, err
+	}
+	first := strings.Index(reply, "[")
+	last := strings.LastIndex(reply, "]")
+	if first < 0 || last < first {
+		return 0, fmt.Errorf("expected a JSON array, got %q", reply)
+	}
+	var found []struct {
+		Segment int    `json:"segment"`
+		Text    string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(reply[first:last+1]), &found); err != nil {
+		return 0, fmt.Errorf("expected a JSON array of translations: %w", err)
+	}
+
+	translated := 0
+	for _, f := range found {
+		text := strings.Join(strings.Fields(f.Text), " ")
+		if f.Segment < start || f.Segment >= end || text == "" {
+			continue
+		}
+		if out[f.Segment] == "" {
+			translated++
+		}
+		out[f.Segment] = text
+	}
+	return translated, nil
+}
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+)
+
+// URLIngestService downloads audio from media URLs (YouTube, podcasts, web pages)
+// with yt-dlp and hands the result to the transcription queue
+type URLIngestService interface {
+	Submit(ctx context.Context, job *models.TranscriptionJob, sourceURL string) error
+	// SubmitTracks creates a multi-track job whose tracks are downloaded from
+	// direct (e.g. presigned S3) URLs, and queues it once all are downloaded
+	SubmitTracks(ctx context.Context, job *models.TranscriptionJob, downloads []TrackDownload) error
+}
+
+// TrackDownload is a multi-track job's track to download: the URL of the audio
+// file and the track's file path to save it to
+type TrackDownload struct {
+	URL  string
+	Path string
+}
+
+// ErrInvalidSourceURL is returned when a submitted URL cannot be ingested
+var ErrInvalidSourceURL = errors.New("invalid source URL")
+
+// JobEnqueuer is the subset of the task queue needed to schedule a job
+type JobEnqueuer interface {
+	EnqueueJob(jobID string) error
+}
+
+type urlIngestService struct {
+	cfg     *config.Config
+	jobRepo repository.JobRepository
+	queue   JobEnqueuer
+
+	// client downloads track files; validateURL checks their URLs and every
+	// redirect. Tests replace both to download from a local server.
+	client      *http.Client
+	validateURL func(string) error
+}
+
+func NewURLIngestService(cfg *config.Config, jobRepo repository.JobRepository, queue JobEnqueuer) URLIngestService {
+	s := &urlIngestService{
+		cfg:         cfg,
+		jobRepo:     jobRepo,
+		queue:       queue,
+		validateURL: ValidateSourceURL,
+	}
+	s.client = &http.Client{
+		Transport: &http.Transport{DialContext: publicDialer.DialContext},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return s.validateURL(req.URL.String())
+		},
+	}
+	return s
+}
+
+// ValidateSourceURL rejects anything that is not a public http(s) URL so that
+// yt-dlp cannot be pointed at local files or internal services. The host is
+// checked again on every connection during the download, see publicDialer.
+func ValidateSourceURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("only http and https URLs are supported")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("URL must include a host")
+	}
+	if strings.EqualFold(host, "localhost") {
+		return errors.New("URL host is not allowed")
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		resolved, err := net.LookupIP(host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve host %s: %w", host, err)
+		}
+		ips = resolved
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return errors.New("URL host is not allowed")
+		}
+	}
+	return nil
+}
+
+func (s *urlIngestService) Submit(ctx context.Context, job *models.TranscriptionJob, sourceURL string) error {
+	sourceURL = strings.TrimSpace(sourceURL)
+	if err := ValidateSourceURL(sourceURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSourceURL, err)
+	}
+	if err := CheckQuota(ctx, s.jobRepo, job.Workspace, s.cfg.MonthlyQuotaMinutes); err != nil {
+		return err
+	}
+
+	job.SourceURL = &sourceURL
+	job.Status = models.StatusDownloading
+	job.IngestProgress = 0
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	tracing.LinkJob(tracing.WithJobID(ctx, job.ID), job.ID)
+	go s.ingest(job.ID, job.Workspace, sourceURL)
+	return nil
+}
+
+// ingest downloads the source audio and moves the job into the pending state
+func (s *urlIngestService) ingest(jobID, workspace, sourceURL string) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.YtDlpTimeoutMinutes)*time.Minute)
+	defer cancel()
+	ctx, span := tracing.StartJob(ctx, jobID, "url.ingest", tracing.String("url.full", sourceURL))
+	defer span.End()
+
+	logger.Info("Starting URL ingestion", "job_id", jobID, "url", sourceURL)
+	audioPath, title, downloadErr := s.download(ctx, jobID, workspace, sourceURL)
+	span.RecordError(downloadErr)
+
+	job, err := s.jobRepo.FindByID(context.Background(), jobID)
+	if err != nil {
+		// The job was deleted while downloading
+		logger.Warn("Job disappeared during URL ingestion", "job_id", jobID, "error", err)
+		if audioPath != "" {
+			os.Remove(audioPath)
+		}
+		return
+	}
+
+	if downloadErr != nil {
+		logger.Error("URL ingestion failed", "job_id", jobID, "url", sourceURL, "error", downloadErr, "duration", time.Since(start))
+		errMsg := fmt.Sprintf("Failed to download audio: %v", downloadErr)
+		job.Status = models.StatusFailed
+		job.ErrorMessage = &errMsg
+		job.ErrorCode = models.ErrorAudioDownloadFailed
+		if err := s.jobRepo.Update(context.Background(), job); err != nil {
+			logger.Error("Failed to mark job as failed", "job_id", jobID, "error", err)
+		}
+		return
+	}
+
+	job.AudioPath = audioPath
+	job.IngestProgress = 100
+	job.Status = models.StatusPending
+	if job.Title == nil && title != "" {
+		job.Title = &title
+	}
+	if err := s.jobRepo.Update(context.Background(), job); err != nil {
+		logger.Error("Failed to update job after URL ingestion", "job_id", jobID, "error", err)
+		return
+	}
+
+	logger.Info("URL ingestion completed", "job_id", jobID, "file_path", audioPath, "duration", time.Since(start))
+
+	// The pending-job scanner will pick the job up if this fails
+	tracing.LinkJob(ctx, jobID)
+	if err := s.queue.EnqueueJob(jobID); err != nil {
+		logger.Warn("Failed to enqueue ingested job", "job_id", jobID, "error", err)
+	}
+}
+
+func (s *urlIngestService) SubmitTracks(ctx context.Context, job *models.TranscriptionJob, downloads []TrackDownload) error {
+	for i := range downloads {
+		downloads[i].URL = strings.TrimSpace(downloads[i].URL)
+		if err := s.validateURL(downloads[i].URL); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSourceURL, err)
+		}
+	}
+	if err := CheckQuota(ctx, s.jobRepo, job.Workspace, s.cfg.MonthlyQuotaMinutes); err != nil {
+		return err
+	}
+
+	job.Status = models.StatusDownloading
+	job.IngestProgress = 0
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	tracing.LinkJob(tracing.WithJobID(ctx, job.ID), job.ID)
+	go s.ingestTracks(job.ID, downloads)
+	return nil
+}
+
+// ingestTracks downloads a multi-track job's tracks one after another and
+// moves the job into the pending state once all are downloaded
+func (s *urlIngestService) ingestTracks(jobID string, downloads []TrackDownload) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.YtDlpTimeoutMinutes)*time.Minute)
+	defer cancel()
+	ctx, span := tracing.StartJob(ctx, jobID, "url.ingest_tracks", tracing.Int("tracks", len(downloads)))
+	defer span.End()
+
+	logger.Info("Starting track downloads", "job_id", jobID, "tracks", len(downloads))
+	var downloadErr error
+	for i, download := range downloads {
+		if downloadErr = s.downloadFile(ctx, download.URL, download.Path); downloadErr != nil {
+			downloadErr = fmt.Errorf("track %d: %w", i+1, downloadErr)
+			break
+		}
+		progress := float64(i+1) / float64(len(downloads)) * 100
+		if err := s.jobRepo.UpdateIngestProgress(context.Background(), jobID, progress); err != nil {
+			logger.Debug("Failed to update ingest progress", "job_id", jobID, "error", err)
+		}
+	}
+	span.RecordError(downloadErr)
+
+	job, err := s.jobRepo.FindByID(context.Background(), jobID)
+	if err != nil {
+		// The job was deleted while downloading
+		logger.Warn("Job disappeared during track downloads", "job_id", jobID, "error", err)
+		for _, download := range downloads {
+			os.Remove(download.Path)
+		}
+		return
+	}
+
+	if downloadErr != nil {
+		logger.Error("Track download failed", "job_id", jobID, "error", downloadErr, "duration", time.Since(start))
+		errMsg := fmt.Sprintf("Failed to download audio: %v", downloadErr)
+		job.Status = models.StatusFailed
+		job.ErrorMessage = &errMsg
+		job.ErrorCode = models.ErrorAudioDownloadFailed
+		if err := s.jobRepo.Update(context.Background(), job); err != nil {
+			logger.Error("Failed to mark job as failed", "job_id", jobID, "error", err)
+		}
+		return
+	}
+
+	job.IngestProgress = 100
+	job.Status = models.StatusPending
+	if err := s.jobRepo.Update(context.Background(), job); err != nil {
+		logger.Error("Failed to update job after track downloads", "job_id", jobID, "error", err)
+		return
+	}
+	logger.Info("Track downloads completed", "job_id", jobID, "duration", time.Since(start))
+
+	// The pending-job scanner will pick the job up if this fails
+	tracing.LinkJob(ctx, jobID)
+	if err := s.queue.EnqueueJob(jobID); err != nil {
+		logger.Warn("Failed to enqueue ingested job", "job_id", jobID, "error", err)
+	}
+}
+
+// downloadFile saves the body of a GET of sourceURL to path, refusing bodies
+// larger than the configured maximum download size
+func (s *urlIngestService) downloadFile(ctx context.Context, sourceURL, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download returned HTTP %d", resp.StatusCode)
+	}
+
+	limit := parseByteSize(s.cfg.YtDlpMaxFileSize)
+	if limit > 0 && resp.ContentLength > limit {
+		return fmt.Errorf("file exceeds the %s size limit", s.cfg.YtDlpMaxFileSize)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create track directory: %w", err)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create track file: %w", err)
+	}
+	body := io.Reader(resp.Body)
+	if limit > 0 {
+		body = io.LimitReader(resp.Body, limit+1)
+	}
+	written, err := io.Copy(out, body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && limit > 0 && written > limit {
+		err = fmt.Errorf("file exceeds the %s size limit", s.cfg.YtDlpMaxFileSize)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// parseByteSize parses a yt-dlp style size such as "500M" or "2G"; it returns
+// 0, no limit, for an empty or invalid size
+func parseByteSize(size string) int64 {
+	size = strings.ToUpper(strings.TrimSpace(size))
+	multiplier := int64(1)
+	for suffix, m := range map[string]int64{"K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40} {
+		if strings.HasSuffix(size, suffix) {
+			size, multiplier = strings.TrimSuffix(size, suffix), m
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(size, 64)
+	if err != nil || value <= 0 {
+		return 0
+	}
+	return int64(value * float64(multiplier))
+}
+
+// download runs yt-dlp in a scratch directory and returns the extracted audio
+// path (inside the workspace's upload directory) and the media title
+func (s *urlIngestService) download(ctx context.Context, jobID, workspace, sourceURL string) (string, string, error) {
+	uploadDir, err := WorkspaceDir(s.cfg.UploadDir, workspace)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	workDir, err := os.MkdirTemp(uploadDir, "ingest-")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// yt-dlp resolves hosts and follows redirects itself, so its traffic goes
+	// through a proxy that refuses connections to non-public addresses
+	proxyCtx, stopProxy := context.WithCancel(ctx)
+	defer stopProxy()
+	proxyURL, err := startEgressProxy(proxyCtx)
+	if err != nil {
+		return "", "", err
+	}
+
+	args := []string{
+		"run", "--native-tls", "--project", s.cfg.WhisperXEnv, "python", "-m", "yt_dlp",
+		"--ignore-config",
+		"--no-exec",
+		"--no-cache-dir",
+		"--no-playlist",
+		"--no-mtime",
+		"--proxy", proxyURL,
+		"--max-filesize", s.cfg.YtDlpMaxFileSize,
+		"--extract-audio",
+		"--audio-format", "mp3",
+		"--audio-quality", "0", // best quality
+		"--output", "audio.%(ext)s",
+		"--print", "before_dl:TITLE %(title)s",
+		"--no-simulate",
+		"--progress",
+		"--newline",
+		"--progress-template", "download:PROGRESS %(progress._percent_str)s",
+		"--",
+		sourceURL,
+	}
+	cmd := exec.CommandContext(ctx, s.cfg.UVPath, args...)
+	cmd.Dir = workDir
+	cmd.Env = sandboxEnv()
+	cmd.WaitDelay = 10 * time.Second
+	configureSandboxedCmd(cmd)
+
+	// yt-dlp writes progress to stderr in quiet mode, so read both streams
+	output, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to attach to yt-dlp output: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return "", "", fmt.Errorf("failed to start yt-dlp: %w", err)
+	}
+
+	var title string
+	var messages []string
+	lastReported := -1.0
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if value, ok := parseTitleLine(line); ok {
+			title = value
+			continue
+		}
+		if progress, ok := parseProgressLine(line); ok {
+			// Keep headroom for audio extraction and avoid a DB write per line
+			progress = progress * 0.95
+			if progress-lastReported < 1 {
+				continue
+			}
+			lastReported = progress
+			if err := s.jobRepo.UpdateIngestProgress(context.Background(), jobID, progress); err != nil {
+				logger.Debug("Failed to update ingest progress", "job_id", jobID, "error", err)
+			}
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, "PROGRESS ") {
+			messages = append(messages, line)
+			if len(messages) > 5 {
+				messages = messages[1:]
+			}
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", "", fmt.Errorf("download timed out after %d minutes", s.cfg.YtDlpTimeoutMinutes)
+		}
+		return "", "", fmt.Errorf("yt-dlp failed: %w: %s", err, strings.Join(messages, "\n"))
+	}
+
+	matches, err := filepath.Glob(filepath.Join(workDir, "audio.*"))
+	if err != nil || len(matches) == 0 {
+		return "", "", errors.New("downloaded file not found (the media may exceed the size limit)")
+	}
+
+	audioPath := filepath.Join(uploadDir, jobID+filepath.Ext(matches[0]))
+	if err := os.Rename(matches[0], audioPath); err != nil {
+		return "", "", fmt.Errorf("failed to move downloaded file: %w", err)
+	}
+	return audioPath, title, nil
+}
+
+// parseTitleLine returns the title printed by the before_dl template
+func parseTitleLine(line string) (string, bool) {
+	if !strings.HasPrefix(line, "TITLE ") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "TITLE ")), true
+}
+
+// parseProgressLine returns the percentage printed by the progress template.
+// yt-dlp prints "N/A" or "Unknown" when the size is not known yet.
+func parseProgressLine(line string) (float64, bool) {
+	if !strings.HasPrefix(line, "PROGRESS ") {
+		return 0, false
+	}
+	value := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "PROGRESS ")), "%")
+	progress, err := strconv.ParseFloat(value, 64)
+	if err != nil || progress < 0 || progress > 100 {
+		return 0, false
+	}
+	return progress, true
+}
+
+// sandboxEnv passes through only the variables yt-dlp and uv need, keeping
+// API keys and other secrets out of the child process. Proxy variables are
+// dropped: downloads go through the egress proxy.
+func sandboxEnv() []string {
+	allowed := []string{"PATH=", "HOME=", "LANG=", "LC_", "TMPDIR=", "UV_", "XDG_", "SSL_CERT_", "SYSTEMROOT=", "USERPROFILE="}
+	var env []string
+	for _, kv := range os.Environ() {
+		for _, prefix := range allowed {
+			if strings.HasPrefix(kv, prefix) {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	return env
+}
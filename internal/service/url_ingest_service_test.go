@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSourceURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		valid bool
+	}{
+		{"PublicHTTPS", "https://93.184.216.34/watch?v=abc", true},
+		{"PublicHTTP", "http://93.184.216.34/episode.mp3", true},
+		{"FileScheme", "file:///etc/passwd", false},
+		{"FTPScheme", "ftp://93.184.216.34/audio.mp3", false},
+		{"NoHost", "https:///path", false},
+		{"Localhost", "http://localhost:8080/", false},
+		{"LocalhostUpperCase", "http://LOCALHOST/", false},
+		{"Loopback", "http://127.0.0.1/", false},
+		{"LoopbackIPv6", "http://[::1]/", false},
+		{"Private", "http://10.1.2.3/", false},
+		{"PrivateClassC", "http://192.168.1.10/", false},
+		{"CloudMetadata", "http://169.254.169.254/latest/meta-data/", false},
+		{"Unspecified", "http://0.0.0.0/", false},
+		{"Multicast", "http://224.0.0.1/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSourceURL(tt.url)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		name   string
+		ip     string
+		public bool
+	}{
+		{"Public", "93.184.216.34", true},
+		{"PublicIPv6", "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"ThisNetwork", "0.1.2.3", false},
+		{"Loopback", "127.0.0.1", false},
+		{"Private", "172.16.0.1", false},
+		{"LinkLocal", "169.254.169.254", false},
+		{"CarrierGradeNAT", "100.64.0.1", false},
+		{"CarrierGradeNATEnd", "100.127.255.254", false},
+		{"IETFProtocolAssignments", "192.0.0.170", false},
+		{"Documentation1", "192.0.2.1", false},
+		{"SixToFourRelay", "192.88.99.1", false},
+		{"Benchmarking", "198.18.0.1", false},
+		{"BenchmarkingEnd", "198.19.255.254", false},
+		{"Documentation2", "198.51.100.1", false},
+		{"Documentation3", "203.0.113.1", false},
+		{"Reserved", "240.0.0.1", false},
+		{"Broadcast", "255.255.255.255", false},
+		{"MappedPrivate", "::ffff:10.0.0.1", false},
+		{"MappedCarrierGradeNAT", "::ffff:100.64.0.1", false},
+		{"NAT64", "64:ff9b::a00:1", false},
+		{"NAT64Local", "64:ff9b:1::1", false},
+		{"Discard", "100::1", false},
+		{"Teredo", "2001::1", false},
+		{"DocumentationIPv6", "2001:db8::1", false},
+		{"SixToFour", "2002:a00:1::1", false},
+		{"SiteLocal", "fec0::1", false},
+		{"UniqueLocal", "fd00::1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.public, isPublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestParseYtDlpOutput(t *testing.T) {
+	titles := []struct {
+		line  string
+		title string
+		ok    bool
+	}{
+		{"TITLE My Podcast Episode", "My Podcast Episode", true},
+		{"TITLE   Padded  ", "Padded", true},
+		{"[youtube] abc: Downloading webpage", "", false},
+		{"PROGRESS 10%", "", false},
+	}
+	for _, tt := range titles {
+		title, ok := parseTitleLine(tt.line)
+		assert.Equal(t, tt.ok, ok, tt.line)
+		assert.Equal(t, tt.title, title, tt.line)
+	}
+
+	progress := []struct {
+		line     string
+		progress float64
+		ok       bool
+	}{
+		{"PROGRESS 42.5%", 42.5, true},
+		{"PROGRESS   7.0%", 7, true},
+		{"PROGRESS 100%", 100, true},
+		{"PROGRESS N/A", 0, false},
+		{"PROGRESS Unknown", 0, false},
+		{"PROGRESS 250%", 0, false},
+		{"TITLE 50%", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range progress {
+		value, ok := parseProgressLine(tt.line)
+		assert.Equal(t, tt.ok, ok, tt.line)
+		assert.Equal(t, tt.progress, value, tt.line)
+	}
+}
+
+func TestEgressProxy(t *testing.T) {
+	// The target stands in for an internal service a redirect or rebound DNS
+	// answer could point yt-dlp at
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer internal.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	proxyURL, err := startEgressProxy(ctx)
+	require.NoError(t, err)
+	parsed, err := url.Parse(proxyURL)
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(parsed)}}
+
+	t.Run("PlainHTTP", func(t *testing.T) {
+		resp, err := client.Get(internal.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+
+	t.Run("Tunnel", func(t *testing.T) {
+		tlsInternal := httptest.NewTLSServer(internal.Config.Handler)
+		defer tlsInternal.Close()
+		_, err := client.Get(tlsInternal.URL)
+		assert.Error(t, err)
+	})
+
+	t.Run("Dialer", func(t *testing.T) {
+		for _, address := range []string{"127.0.0.1:80", "[::1]:443", "10.0.0.1:80", "169.254.169.254:80"} {
+			_, err := publicDialer.DialContext(ctx, "tcp", address)
+			assert.ErrorIs(t, err, errNonPublicAddress, address)
+		}
+	})
+}
+
+func TestDownloadTrackFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.wav" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	s := &urlIngestService{cfg: &config.Config{YtDlpMaxFileSize: "1K"}, client: server.Client()}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tracks", "host.wav")
+	require.NoError(t, s.downloadFile(context.Background(), server.URL+"/host.wav", path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+
+	assert.Error(t, s.downloadFile(context.Background(), server.URL+"/missing.wav", filepath.Join(dir, "missing.wav")))
+
+	// Files over the size limit are removed
+	s.cfg.YtDlpMaxFileSize = "0.005K"
+	tooLarge := filepath.Join(dir, "large.wav")
+	assert.Error(t, s.downloadFile(context.Background(), server.URL+"/host.wav", tooLarge))
+	_, err = os.Stat(tooLarge)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParseByteSize(t *testing.T) {
+	assert.Equal(t, int64(2<<30), parseByteSize("2G"))
+	assert.Equal(t, int64(500<<20), parseByteSize("500m"))
+	assert.Equal(t, int64(1024), parseByteSize("1024"))
+	assert.Equal(t, int64(0), parseByteSize(""))
+	assert.Equal(t, int64(0), parseByteSize("lots"))
+}
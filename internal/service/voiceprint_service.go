@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// defaultVoiceprintMatchThreshold is used when no threshold is configured
+const defaultVoiceprintMatchThreshold = 0.6
+
+var (
+	// ErrNoSpeakerEmbedding is returned when enrolling a speaker whose job was
+	// diarized without voice embeddings
+	ErrNoSpeakerEmbedding = errors.New("no voice embedding was recorded for this speaker")
+	// ErrVoiceprintMismatch is returned when adding a sample produced by a
+	// different embedding model than the voiceprint's
+	ErrVoiceprintMismatch = errors.New("speaker embedding does not match the voiceprint's embedding model")
+)
+
+// VoiceprintService enrolls the voices of named people and recognises them
+// among the speakers diarized in new jobs
+type VoiceprintService interface {
+	HasVoiceprints(ctx context.Context, workspace string) bool
+	// StoreSpeakerEmbeddings replaces the speaker embeddings recorded for a job
+	StoreSpeakerEmbeddings(ctx context.Context, jobID string, embeddings map[string][]float64) error
+	// IdentifySpeakers matches the job's speakers not named by hand against the
+	// workspace's voiceprints and names the matched speakers
+	IdentifySpeakers(ctx context.Context, jobID, workspace string) ([]repository.VoiceprintMatch, error)
+	// Enroll adds a job's speaker to the named voiceprint, creating it if needed
+	Enroll(ctx context.Context, workspace, name, jobID, speaker string) (*models.Voiceprint, error)
+}
+
+type voiceprintService struct {
+	repo      repository.SpeakerMappingRepository
+	threshold float64
+}
+
+func NewVoiceprintService(cfg *config.Config, repo repository.SpeakerMappingRepository) VoiceprintService {
+	threshold := cfg.VoiceprintMatchThreshold
+	if threshold <= 0 {
+		threshold = defaultVoiceprintMatchThreshold
+	}
+	return &voiceprintService{repo: repo, threshold: threshold}
+}
+
+func (s *voiceprintService) HasVoiceprints(ctx context.Context, workspace string) bool {
+	found, err := s.repo.HasVoiceprints(ctx, workspace)
+	if err != nil {
+		logger.Warn("Failed to check for voiceprints", "workspace", workspace, "error", err)
+	}
+	return found
+}
+
+func (s *voiceprintService) StoreSpeakerEmbeddings(ctx context.Context, jobID string, embeddings map[string][]float64) error {
+	rows := make([]models.JobSpeakerEmbedding, 0, len(embeddings))
+	for speaker, embedding := range embeddings {
+		if len(embedding) == 0 {
+			continue
+		}
+		vector := make([]float32, len(embedding))
+		for i, x := range embedding {
+			vector[i] = float32(x)
+		}
+		rows = append(rows, models.JobSpeakerEmbedding{Speaker: speaker, Vector: vector})
+	}
+	return s.repo.ReplaceSpeakerEmbeddings(ctx, jobID, rows)
+}
+
+func (s *voiceprintService) IdentifySpeakers(ctx context.Context, jobID, workspace string) ([]repository.VoiceprintMatch, error) {
+	embeddings, err := s.repo.ListSpeakerEmbeddings(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load speaker embeddings: %w", err)
+	}
+	voiceprints, err := s.repo.ListVoiceprints(ctx, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voiceprints: %w", err)
+	}
+	mappings, err := s.repo.ListByJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load speaker mappings: %w", err)
+	}
+
+	// Speakers named by hand are known not to need a voiceprint
+	named := make(map[string]bool)
+	for _, mapping := range mappings {
+		if mapping.VoiceprintID == nil {
+			named[mapping.OriginalSpeaker] = true
+		}
+	}
+	unnamed := embeddings[:0]
+	for _, embedding := range embeddings {
+		if !named[embedding.Speaker] {
+			unnamed = append(unnamed, embedding)
+		}
+	}
+
+	matches := matchVoiceprints(unnamed, voiceprints, s.threshold)
+	if err := s.repo.ApplyVoiceprintMatches(ctx, jobID, matches); err != nil {
+		return nil, fmt.Errorf("failed to save voiceprint matches: %w", err)
+	}
+	return matches, nil
+}
+
+// matchVoiceprints pairs speakers with voiceprints by descending cosine
+// similarity, each speaker and each voiceprint used at most once
+func matchVoiceprints(embeddings []models.JobSpeakerEmbedding, voiceprints []models.Voiceprint, threshold float64) []repository.VoiceprintMatch {
+	var candidates []repository.VoiceprintMatch
+	for _, embedding := range embeddings {
+		for _, voiceprint := range voiceprints {
+			if similarity := cosineSimilarity(embedding.Vector, voiceprint.Vector); similarity >= threshold {
+				candidates = append(candidates, repository.VoiceprintMatch{
+					Speaker:    embedding.Speaker,
+					Voiceprint: voiceprint,
+					Similarity: similarity,
+				})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+
+	speakers := make(map[string]bool)
+	used := make(map[string]bool)
+	var matches []repository.VoiceprintMatch
+	for _, candidate := range candidates {
+		if speakers[candidate.Speaker] || used[candidate.Voiceprint.ID] {
+			continue
+		}
+		speakers[candidate.Speaker] = true
+		used[candidate.Voiceprint.ID] = true
+		matches = append(matches, candidate)
+	}
+	return matches
+}
+
+func (s *voiceprintService) Enroll(ctx context.Context, workspace, name, jobID, speaker string) (*models.Voiceprint, error) {
+	embeddings, err := s.repo.ListSpeakerEmbeddings(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load speaker embeddings: %w", err)
+	}
+	var sample []float32
+	for _, embedding := range embeddings {
+		if embedding.Speaker == speaker {
+			sample = normalizeVector(embedding.Vector)
+			break
+		}
+	}
+	if sample == nil {
+		return nil, ErrNoSpeakerEmbedding
+	}
+
+	voiceprint, err := s.repo.FindVoiceprintByName(ctx, workspace, name)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		voiceprint = &models.Voiceprint{Workspace: workspace, Name: name, Samples: 1, Vector: sample}
+	case err != nil:
+		return nil, err
+	default:
+		if len(voiceprint.Vector) != len(sample) {
+			return nil, ErrVoiceprintMismatch
+		}
+		// Running mean of the unit-length samples
+		n := float32(voiceprint.Samples)
+		for i := range sample {
+			voiceprint.Vector[i] = (voiceprint.Vector[i]*n + sample[i]) / (n + 1)
+		}
+		voiceprint.Samples++
+	}
+
+	if err := s.repo.SaveVoiceprint(ctx, voiceprint); err != nil {
+		return nil, err
+	}
+	return voiceprint, nil
+}
+
+// normalizeVector returns a unit-length copy of v, so every sample weighs the
+// same in a voiceprint's mean
+func normalizeVector(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if norm == 0 {
+		return out
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i, x := range v {
+		out[i] = x * scale
+	}
+	return out
+}
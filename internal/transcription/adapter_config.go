@@ -0,0 +1,468 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/secrets"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/registry"
+	"scriberr/pkg/logger"
+
+	"github.com/modal-labs/libmodal/modal-go"
+)
+
+// Adapter configuration errors
+var (
+	// ErrAdapterConfigDisabled is returned when adapters are registered without
+	// the settings stored through the admin API
+	ErrAdapterConfigDisabled = errors.New("adapter configuration is not enabled")
+	// ErrUnknownAdapter is returned for adapters that cannot be configured
+	ErrUnknownAdapter = errors.New("unknown adapter")
+)
+
+// Where an adapter's settings come from
+const (
+	AdapterSourceEnvironment = "environment"
+	AdapterSourceDatabase    = "database"
+)
+
+// AdapterEnvironment is how the environment registers adapters that have no
+// settings stored through the admin API
+type AdapterEnvironment struct {
+	EnvPath      string // WhisperX environment, under which the other local environments live
+	OpenAIAPIKey string
+	// EnableDefaults registers the local adapters and OpenAI besides the remote
+	// WhisperX adapters
+	EnableDefaults bool
+	// LocalWhisperXURL serves whisperx from a WhisperX server instead of the
+	// local environment
+	LocalWhisperXURL string
+}
+
+// NewAdapterEnvironment returns the adapter settings of cfg
+func NewAdapterEnvironment(cfg *config.Config) AdapterEnvironment {
+	return AdapterEnvironment{
+		EnvPath:          cfg.WhisperXEnv,
+		OpenAIAPIKey:     cfg.OpenAIAPIKey,
+		EnableDefaults:   cfg.EnableDefaultAdapters,
+		LocalWhisperXURL: cfg.LocalWhisperXBaseURL,
+	}
+}
+
+// nvidiaEnvPath is the environment shared by the NeMo-based models
+func (e AdapterEnvironment) nvidiaEnvPath() string {
+	return filepath.Join(e.EnvPath, "parakeet")
+}
+
+// adapterSpec describes how a configurable adapter is built
+type adapterSpec struct {
+	kind     string // ModelKindTranscription or ModelKindDiarization
+	endpoint bool   // takes an endpoint
+	apiKey   bool   // takes an API key
+	// enabled reports whether the environment registers the adapter
+	enabled func(env AdapterEnvironment) bool
+	// envEndpoint and envAPIKey return the environment's settings, if any
+	envEndpoint func(env AdapterEnvironment) string
+	envAPIKey   func(env AdapterEnvironment) string
+	// checkEndpoint and checkAPIKey reject malformed settings
+	checkEndpoint func(endpoint string) error
+	checkAPIKey   func(key string) error
+	build         func(env AdapterEnvironment, endpoint, apiKey string) any
+}
+
+// adapterSpecs are the adapters registered from the environment and the admin API
+var adapterSpecs = map[string]adapterSpec{
+	"whisperx": {
+		kind:     ModelKindTranscription,
+		endpoint: true,
+		apiKey:   true,
+		enabled: func(env AdapterEnvironment) bool {
+			return env.EnableDefaults || env.LocalWhisperXURL != ""
+		},
+		envEndpoint: func(env AdapterEnvironment) string { return env.LocalWhisperXURL },
+		checkEndpoint: func(endpoint string) error {
+			if !isHTTPURL(endpoint) {
+				return errors.New("the whisperx endpoint must be the URL of a WhisperX server")
+			}
+			return nil
+		},
+		build: func(env AdapterEnvironment, endpoint, apiKey string) any {
+			whisperx := adapters.NewWhisperXAdapter(env.EnvPath)
+			if endpoint == "" {
+				return whisperx
+			}
+			opts := []adapters.RunpodOption{adapters.WithRunpodBaseURL(endpoint), adapters.WithRunpodModelFamily(interfaces.LocalWhisperX)}
+			if apiKey != "" {
+				opts = append(opts, adapters.WithRunpodApiKey(apiKey))
+			}
+			return adapters.NewRunPodAdapter(whisperx, opts...)
+		},
+	},
+	interfaces.ModalWhisperX: {
+		kind:        ModelKindTranscription,
+		endpoint:    true,
+		apiKey:      true,
+		enabled:     func(AdapterEnvironment) bool { return true },
+		envEndpoint: func(AdapterEnvironment) string { return os.Getenv("MODAL_APP_NAME") },
+		envAPIKey: func(AdapterEnvironment) string {
+			if id := os.Getenv("MODAL_TOKEN_ID"); id != "" {
+				return id + ":" + os.Getenv("MODAL_TOKEN_SECRET")
+			}
+			return ""
+		},
+		checkAPIKey: func(key string) error {
+			if id, secret, ok := strings.Cut(key, ":"); !ok || id == "" || secret == "" {
+				return errors.New("the Modal API key must be a token ID and secret separated by a colon")
+			}
+			return nil
+		},
+		build: func(env AdapterEnvironment, endpoint, apiKey string) any {
+			var client *modal.Client
+			var err error
+			if id, secret, ok := strings.Cut(apiKey, ":"); ok {
+				client, err = modal.NewClientWithOptions(&modal.ClientParams{TokenID: id, TokenSecret: secret})
+			} else {
+				client, err = modal.NewClient()
+			}
+			if err != nil {
+				logger.Warn("Failed to initialize Modal client", "error", err)
+			}
+			adapter := adapters.NewModalAdapter(adapters.NewWhisperXAdapter(env.EnvPath), client)
+			if endpoint != "" {
+				adapter.FunctionName = endpoint
+			}
+			return adapter
+		},
+	},
+	interfaces.RunPodWhisperX: {
+		kind:        ModelKindTranscription,
+		endpoint:    true,
+		apiKey:      true,
+		enabled:     func(AdapterEnvironment) bool { return true },
+		envEndpoint: func(AdapterEnvironment) string { return os.Getenv("RUNPOD_ENDPOINT_ID") },
+		envAPIKey:   func(AdapterEnvironment) string { return os.Getenv("RUNPOD_AI_API_KEY") },
+		build: func(env AdapterEnvironment, endpoint, apiKey string) any {
+			var opts []adapters.RunpodOption
+			if endpoint != "" {
+				opts = append(opts, adapters.WithRunpodBaseURL(runpodBaseURL(endpoint)))
+			}
+			if apiKey != "" {
+				opts = append(opts, adapters.WithRunpodApiKey(apiKey))
+			}
+			return adapters.NewRunPodAdapter(adapters.NewWhisperXAdapter(env.EnvPath), opts...)
+		},
+	},
+	"parakeet": {
+		kind:    ModelKindTranscription,
+		enabled: func(env AdapterEnvironment) bool { return env.EnableDefaults },
+		build: func(env AdapterEnvironment, _, _ string) any {
+			return adapters.NewParakeetAdapter(env.nvidiaEnvPath())
+		},
+	},
+	"canary": {
+		kind:    ModelKindTranscription,
+		enabled: func(env AdapterEnvironment) bool { return env.EnableDefaults },
+		build: func(env AdapterEnvironment, _, _ string) any {
+			return adapters.NewCanaryAdapter(env.nvidiaEnvPath()) // Shares with Parakeet
+		},
+	},
+	"openai_whisper": {
+		kind:      ModelKindTranscription,
+		apiKey:    true,
+		enabled:   func(env AdapterEnvironment) bool { return env.EnableDefaults },
+		envAPIKey: func(env AdapterEnvironment) string { return env.OpenAIAPIKey },
+		build: func(_ AdapterEnvironment, _, apiKey string) any {
+			return adapters.NewOpenAIAdapter(apiKey)
+		},
+	},
+	"pyannote": {
+		kind:    ModelKindDiarization,
+		enabled: func(env AdapterEnvironment) bool { return env.EnableDefaults },
+		build: func(env AdapterEnvironment, _, _ string) any {
+			// Dedicated environment, to avoid dependency conflicts
+			return adapters.NewPyAnnoteAdapter(filepath.Join(env.EnvPath, "pyannote"))
+		},
+	},
+	"sortformer": {
+		kind:    ModelKindDiarization,
+		enabled: func(env AdapterEnvironment) bool { return env.EnableDefaults },
+		build: func(env AdapterEnvironment, _, _ string) any {
+			return adapters.NewSortformerAdapter(env.nvidiaEnvPath()) // Shares with Parakeet
+		},
+	},
+}
+
+// runpodBaseURL accepts either a RunPod endpoint ID or a base URL
+func runpodBaseURL(endpoint string) string {
+	if isHTTPURL(endpoint) {
+		return endpoint
+	}
+	return "https://api.runpod.ai/v2/" + endpoint
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// adapterSettings are the settings an adapter is registered with
+type adapterSettings struct {
+	enabled  bool
+	endpoint string
+	apiKey   string
+	stored   *models.AdapterConfig // nil when the environment's settings apply
+}
+
+// resolve returns the adapter's settings: the stored ones, falling back to
+// the environment's
+func (s adapterSpec) resolve(env AdapterEnvironment, stored *models.AdapterConfig) adapterSettings {
+	settings := adapterSettings{enabled: s.enabled(env), stored: stored}
+	if s.envEndpoint != nil {
+		settings.endpoint = s.envEndpoint(env)
+	}
+	if s.envAPIKey != nil {
+		settings.apiKey = s.envAPIKey(env)
+	}
+	if stored != nil {
+		settings.enabled = stored.Enabled
+		if stored.Endpoint != nil {
+			settings.endpoint = *stored.Endpoint
+		}
+		if stored.APIKey != nil {
+			settings.apiKey = *stored.APIKey
+		}
+	}
+	return settings
+}
+
+// fingerprint identifies the adapter the settings register
+func (s adapterSettings) fingerprint() string {
+	return fmt.Sprintf("%t\x00%s\x00%s", s.enabled, s.endpoint, s.apiKey)
+}
+
+// adapterConfigurator registers adapters with their stored settings
+type adapterConfigurator struct {
+	mu      sync.Mutex
+	env     AdapterEnvironment
+	repo    repository.AdapterConfigRepository
+	applied map[string]string // fingerprints of the settings each adapter was registered with
+}
+
+// AdapterStatus is an adapter's settings and whether it is registered
+type AdapterStatus struct {
+	ModelID string `json:"model_id"`
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+	// Source is where the settings come from: environment, or database when set
+	// through the admin API
+	Source    string `json:"source"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	HasAPIKey bool   `json:"has_api_key"`
+	// APIKey is the key masked, or the reference to the secret store holding it;
+	// APIKeySource is that store, or "value" for keys set directly
+	APIKey           string     `json:"api_key,omitempty"`
+	APIKeySource     string     `json:"api_key_source,omitempty"`
+	SupportsEndpoint bool       `json:"supports_endpoint"`
+	SupportsAPIKey   bool       `json:"supports_api_key"`
+	Registered       bool       `json:"registered"`
+	UpdatedBy        string     `json:"updated_by,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetAdapterConfigs registers the adapters with the settings stored in repo,
+// falling back to env's, and enables changing them through ReloadAdapters
+func (u *UnifiedTranscriptionService) SetAdapterConfigs(env AdapterEnvironment, repo repository.AdapterConfigRepository) error {
+	logger.Info("Registering adapters with environment path", "whisperx_env", env.EnvPath)
+	u.adapterConfigs = &adapterConfigurator{env: env, repo: repo, applied: make(map[string]string)}
+	if err := u.ReloadAdapters(context.Background()); err != nil {
+		return err
+	}
+	logger.Info("Adapter registration complete")
+	return nil
+}
+
+// ReloadAdapters brings the registered adapters in line with their stored
+// settings: adapters whose settings changed are registered again, and disabled
+// ones removed. Jobs already running finish with the adapter they started with.
+// When this process prepares environments, the new adapters' are prepared in
+// the background.
+func (u *UnifiedTranscriptionService) ReloadAdapters(ctx context.Context) error {
+	a := u.adapterConfigs
+	if a == nil {
+		return ErrAdapterConfigDisabled
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	configs, err := a.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load adapter settings: %w", err)
+	}
+	stored := make(map[string]*models.AdapterConfig, len(configs))
+	for i := range configs {
+		stored[configs[i].ModelID] = &configs[i]
+	}
+
+	registered := false
+	for _, modelID := range adapterSpecIDs() {
+		spec := adapterSpecs[modelID]
+		settings := spec.resolve(a.env, stored[modelID])
+		// Keys may reference a secret store; a rotated secret registers the
+		// adapter again once the resolver fetches it anew
+		apiKey, err := secrets.Resolve(ctx, settings.apiKey)
+		if err != nil {
+			logger.Error("Failed to resolve adapter API key", "model_id", modelID, "error", err)
+			continue
+		}
+		settings.apiKey = apiKey
+		fingerprint := settings.fingerprint()
+		if applied, ok := a.applied[modelID]; ok && applied == fingerprint {
+			continue
+		}
+		a.applied[modelID] = fingerprint
+
+		if !settings.enabled {
+			if registry.UnregisterAdapter(modelID) {
+				logger.Info("Adapter disabled", "model_id", modelID)
+			}
+			continue
+		}
+		switch adapter := spec.build(a.env, settings.endpoint, settings.apiKey).(type) {
+		case interfaces.TranscriptionAdapter:
+			registry.RegisterTranscriptionAdapter(modelID, adapter)
+		case interfaces.DiarizationAdapter:
+			registry.RegisterDiarizationAdapter(modelID, adapter)
+		}
+		registered = true
+		logger.Info("Adapter registered", "model_id", modelID, "configured", settings.stored != nil)
+	}
+
+	if registered && u.preparesEnvironments.Load() {
+		u.registry.MarkEnvironmentsPending()
+		go func() {
+			if err := u.registry.InitializeModels(context.Background()); err != nil {
+				logger.Error("Failed to prepare adapter environments", "error", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// WatchAdapterConfigs reloads the adapters every interval until ctx is done,
+// so processes other than the one serving the admin API pick up changes
+func (u *UnifiedTranscriptionService) WatchAdapterConfigs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := u.ReloadAdapters(ctx); err != nil && ctx.Err() == nil {
+				logger.Warn("Failed to reload adapters", "error", err)
+			}
+		}
+	}
+}
+
+// ListAdapters returns the settings of every configurable adapter, sorted by model ID
+func (u *UnifiedTranscriptionService) ListAdapters(ctx context.Context) ([]AdapterStatus, error) {
+	a := u.adapterConfigs
+	if a == nil {
+		return nil, ErrAdapterConfigDisabled
+	}
+	configs, err := a.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load adapter settings: %w", err)
+	}
+	stored := make(map[string]*models.AdapterConfig, len(configs))
+	for i := range configs {
+		stored[configs[i].ModelID] = &configs[i]
+	}
+
+	capabilities := u.registry.GetAllCapabilities()
+	statuses := make([]AdapterStatus, 0, len(adapterSpecs))
+	for _, modelID := range adapterSpecIDs() {
+		spec := adapterSpecs[modelID]
+		settings := spec.resolve(a.env, stored[modelID])
+		_, registered := capabilities[modelID]
+		status := AdapterStatus{
+			ModelID:          modelID,
+			Kind:             spec.kind,
+			Enabled:          settings.enabled,
+			Source:           AdapterSourceEnvironment,
+			Endpoint:         settings.endpoint,
+			HasAPIKey:        settings.apiKey != "",
+			APIKey:           secrets.Mask(settings.apiKey),
+			SupportsEndpoint: spec.endpoint,
+			SupportsAPIKey:   spec.apiKey,
+			Registered:       registered,
+		}
+		if settings.apiKey != "" {
+			status.APIKeySource = secrets.Source(settings.apiKey)
+		}
+		if settings.stored != nil {
+			status.Source = AdapterSourceDatabase
+			status.UpdatedBy = settings.stored.UpdatedBy
+			status.UpdatedAt = &settings.stored.UpdatedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// ValidateAdapterConfig checks settings before they are stored, fetching the
+// secrets they reference
+func (u *UnifiedTranscriptionService) ValidateAdapterConfig(ctx context.Context, cfg *models.AdapterConfig) error {
+	if u.adapterConfigs == nil {
+		return ErrAdapterConfigDisabled
+	}
+	spec, ok := adapterSpecs[cfg.ModelID]
+	if !ok {
+		return ErrUnknownAdapter
+	}
+	if cfg.Endpoint != nil && !spec.endpoint {
+		return fmt.Errorf("%s does not take an endpoint", cfg.ModelID)
+	}
+	if cfg.APIKey != nil && !spec.apiKey {
+		return fmt.Errorf("%s does not take an API key", cfg.ModelID)
+	}
+	if cfg.Endpoint != nil && *cfg.Endpoint != "" && spec.checkEndpoint != nil {
+		if err := spec.checkEndpoint(*cfg.Endpoint); err != nil {
+			return err
+		}
+	}
+	if cfg.APIKey != nil && *cfg.APIKey != "" {
+		key, err := secrets.Resolve(ctx, *cfg.APIKey)
+		if err != nil {
+			return err
+		}
+		if spec.checkAPIKey != nil {
+			if err := spec.checkAPIKey(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// adapterSpecIDs returns the configurable adapters in a stable order
+func adapterSpecIDs() []string {
+	ids := make([]string, 0, len(adapterSpecs))
+	for id := range adapterSpecs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
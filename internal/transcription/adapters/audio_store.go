@@ -0,0 +1,141 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// AudioStore makes audio files reachable by remote workers through presigned
+// URLs, so that long recordings are not sent in request bodies
+type AudioStore interface {
+	// Upload copies a local file to an s3:// object
+	Upload(ctx context.Context, uri, path string) error
+	// Presign returns a time-limited URL for an s3:// object and the headers
+	// the download must be sent with
+	Presign(ctx context.Context, uri string, ttl time.Duration) (string, map[string]string, error)
+	// Delete removes an s3:// object
+	Delete(ctx context.Context, uri string) error
+}
+
+// maxPresignTTL is the longest S3 allows presigned URLs to last
+const maxPresignTTL = 7 * 24 * time.Hour
+
+// s3AudioStore is an AudioStore on S3 with the default AWS credentials
+type s3AudioStore struct {
+	once   sync.Once
+	client *s3.Client
+	err    error
+}
+
+// NewS3AudioStore creates an AudioStore on S3. The AWS configuration is loaded
+// on first use, so adapters that never need it do not require it.
+func NewS3AudioStore() AudioStore {
+	return &s3AudioStore{}
+}
+
+func (s *s3AudioStore) s3Client(ctx context.Context) (*s3.Client, error) {
+	s.once.Do(func() {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			s.err = fmt.Errorf("failed to load AWS configuration: %w", err)
+			return
+		}
+		s.client = s3.NewFromConfig(cfg)
+	})
+	return s.client, s.err
+}
+
+// splitS3URI splits an s3://bucket/key URI
+func splitS3URI(uri string) (string, string, error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(uri, "s3://"), "/")
+	if !strings.HasPrefix(uri, "s3://") || !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid S3 URI format: %s", uri)
+	}
+	return bucket, key, nil
+}
+
+func (s *s3AudioStore) Upload(ctx context.Context, uri, path string) error {
+	bucket, key, err := splitS3URI(uri)
+	if err != nil {
+		return err
+	}
+	client, err := s.s3Client(ctx)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}); err != nil {
+		return fmt.Errorf("failed to upload S3 object: %w", err)
+	}
+	return nil
+}
+
+func (s *s3AudioStore) Presign(ctx context.Context, uri string, ttl time.Duration) (string, map[string]string, error) {
+	bucket, key, err := splitS3URI(uri)
+	if err != nil {
+		return "", nil, err
+	}
+	client, err := s.s3Client(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	if ttl <= 0 || ttl > maxPresignTTL {
+		ttl = maxPresignTTL
+	}
+	req, err := s3.NewPresignClient(client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to presign S3 URL: %w", err)
+	}
+	return req.URL, downloadHeaders(req.SignedHeader), nil
+}
+
+// downloadHeaders returns the signed headers a presigned download must send;
+// Host is implied by the URL
+func downloadHeaders(signed http.Header) map[string]string {
+	headers := make(map[string]string)
+	for name, values := range signed {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		headers[name] = strings.Join(values, ",")
+	}
+	return headers
+}
+
+func (s *s3AudioStore) Delete(ctx context.Context, uri string) error {
+	bucket, key, err := splitS3URI(uri)
+	if err != nil {
+		return err
+	}
+	client, err := s.s3Client(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return fmt.Errorf("failed to delete S3 object: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package adapters
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"io"
@@ -9,6 +11,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,7 +32,8 @@ var (
 	requestGroup  singleflight.Group
 )
 
-// CheckEnvironmentReady checks if a UV environment is ready with caching and singleflight
+// CheckEnvironmentReady checks if a UV environment is ready with caching and singleflight.
+// An environment synced for a different GPU backend is reported as not ready.
 func CheckEnvironmentReady(envPath, importStatement string) bool {
 	cacheKey := fmt.Sprintf("%s:%s", envPath, importStatement)
 
@@ -50,8 +56,14 @@ func CheckEnvironmentReady(envPath, importStatement string) bool {
 		envCacheMutex.RUnlock()
 
 		// Run the actual check
-		testCmd := exec.Command("uv", "run", "--native-tls", "--project", envPath, "python", "-c", importStatement)
-		ready := testCmd.Run() == nil
+		ready := gpuBackendMatches(envPath)
+		if ready {
+			testCmd := exec.Command("uv", "run", "--native-tls", "--project", envPath, "python", "-c", importStatement)
+			testCmd.Env = uvEnv()
+			ready = testCmd.Run() == nil
+		} else {
+			logger.Info("Environment was built for a different GPU backend", "env_path", envPath, "backend", DetectGPUBackend())
+		}
 
 		// Cache the result
 		envCacheMutex.Lock()
@@ -258,6 +270,36 @@ func (b *BaseAdapter) PrepareEnvironment(ctx context.Context) error {
 	return nil
 }
 
+// EnvironmentHash fingerprints what the adapter runs with: its version, the
+// platform and GPU backend, and the project, lock and script files at the top of
+// its environment directory. Executions with the same hash ran in the same
+// environment.
+func (b *BaseAdapter) EnvironmentHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s/%s\x00%s\x00", b.modelID, b.capabilities.Version, runtime.GOOS, runtime.GOARCH, DetectGPUBackend())
+
+	if b.modelPath != "" {
+		entries, _ := os.ReadDir(b.modelPath)
+		var names []string
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.Type().IsRegular() && (name == gpuBackendMarker || slices.Contains([]string{".py", ".toml", ".lock"}, filepath.Ext(name))) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			data, err := os.ReadFile(filepath.Join(b.modelPath, name))
+			if err != nil {
+				continue
+			}
+			sum := sha256.Sum256(data)
+			fmt.Fprintf(h, "%s\x00%x\x00", name, sum)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // IsReady checks if the adapter is ready to process jobs
 func (b *BaseAdapter) IsReady(ctx context.Context) bool {
 	if !b.initialized {
@@ -526,3 +568,13 @@ func (b *BaseAdapter) LogProcessingEnd(procCtx interfaces.ProcessingContext, pro
 			"processing_time", processingTime)
 	}
 }
+
+// envInt reads a non-negative number from the environment, or returns
+// fallback when it is not set or invalid
+func envInt(name string, fallback int64) int64 {
+	n, err := strconv.ParseInt(os.Getenv(name), 10, 64)
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
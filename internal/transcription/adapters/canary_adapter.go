@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"scriberr/internal/transcription/interfaces"
-	"scriberr/pkg/downloader"
 	"scriberr/pkg/logger"
 )
 
@@ -45,6 +44,7 @@ func NewCanaryAdapter(envPath string) *CanaryAdapter {
 			"code_switching": true,
 		},
 		Metadata: map[string]string{
+			"checkpoint":     "nvidia/canary-1b-v2",
 			"engine":         "nvidia_nemo",
 			"framework":      "nemo_toolkit",
 			"license":        "CC-BY-4.0",
@@ -143,9 +143,18 @@ func NewCanaryAdapter(envPath string) *CanaryAdapter {
 			Description: "Preserve punctuation and capitalization",
 			Group:       "advanced",
 		},
+		{
+			Name:        "device",
+			Type:        "string",
+			Required:    false,
+			Default:     "auto",
+			Options:     []string{"auto", "cpu", "cuda", "rocm", "mps"},
+			Description: "Device to use for computation (auto, cpu, cuda for NVIDIA, rocm for AMD, mps for Apple silicon)",
+			Group:       "advanced",
+		},
 	}
 
-	baseAdapter := NewBaseAdapter("canary", envPath, capabilities, schema)
+	baseAdapter := NewBaseAdapter("canary", envPath, capabilities, append(schema, nemoAdapterSchema...))
 
 	adapter := &CanaryAdapter{
 		BaseAdapter: baseAdapter,
@@ -168,6 +177,10 @@ func (c *CanaryAdapter) PrepareEnvironment(ctx context.Context) error {
 	if CheckEnvironmentReady(c.envPath, "import nemo.collections.asr") {
 		modelPath := filepath.Join(c.envPath, "canary-1b-v2.nemo")
 		if stat, err := os.Stat(modelPath); err == nil && stat.Size() > 1024*1024 {
+			// Rewrite the script so existing installs pick up new script options
+			if err := c.createTranscriptionScript(); err != nil {
+				return fmt.Errorf("failed to create transcription script: %w", err)
+			}
 			logger.Info("Canary environment already ready")
 			c.initialized = true
 			return nil
@@ -202,7 +215,7 @@ func (c *CanaryAdapter) setupCanaryEnvironment() error {
 
 	// Check if pyproject.toml already exists from Parakeet setup
 	pyprojectPath := filepath.Join(c.envPath, "pyproject.toml")
-	if _, err := os.Stat(pyprojectPath); err == nil {
+	if _, err := os.Stat(pyprojectPath); err == nil && gpuBackendMatches(c.envPath) {
 		logger.Info("Environment already configured by Parakeet")
 		return nil
 	}
@@ -225,6 +238,26 @@ dependencies = [
 
 [tool.uv.sources]
 nemo-toolkit = { git = "https://github.com/NVIDIA/NeMo.git", tag = "v2.5.3" }
+torch = [
+    { index = "pytorch-cpu", marker = "sys_platform == 'darwin'" },
+    { index = "pytorch-cpu", marker = "platform_machine != 'x86_64' and sys_platform != 'darwin'" },
+    { index = "pytorch", marker = "platform_machine == 'x86_64' and sys_platform == 'linux'" },
+]
+torchaudio = [
+    { index = "pytorch-cpu", marker = "sys_platform == 'darwin'" },
+    { index = "pytorch-cpu", marker = "platform_machine != 'x86_64' and sys_platform != 'darwin'" },
+    { index = "pytorch", marker = "platform_machine == 'x86_64' and sys_platform == 'linux'" },
+]
+
+[[tool.uv.index]]
+name = "pytorch"
+url = "` + DetectGPUBackend().PyTorchIndexURL() + `"
+explicit = true
+
+[[tool.uv.index]]
+name = "pytorch-cpu"
+url = "https://download.pytorch.org/whl/cpu"
+explicit = true
 `
 	if err := os.WriteFile(pyprojectPath, []byte(pyprojectContent), 0644); err != nil {
 		return fmt.Errorf("failed to write pyproject.toml: %w", err)
@@ -233,11 +266,13 @@ nemo-toolkit = { git = "https://github.com/NVIDIA/NeMo.git", tag = "v2.5.3" }
 	// Run uv sync
 	logger.Info("Installing Canary dependencies")
 	cmd := exec.Command("uv", "sync", "--native-tls")
+	cmd.Env = uvEnv()
 	cmd.Dir = c.envPath
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("uv sync failed: %w: %s", err, strings.TrimSpace(string(out)))
 	}
+	markGPUBackend(c.envPath)
 
 	return nil
 }
@@ -260,7 +295,7 @@ func (c *CanaryAdapter) downloadCanaryModel() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 
-	if err := downloader.DownloadFile(ctx, modelURL, modelPath); err != nil {
+	if err := fetchModelFile(ctx, modelURL, modelPath); err != nil {
 		return fmt.Errorf("failed to download Canary model: %w", err)
 	}
 
@@ -295,9 +330,21 @@ import json
 import sys
 import os
 from pathlib import Path
+import torch
 import nemo.collections.asr as nemo_asr
 
 
+def resolve_device(device):
+    """Resolve "auto" to the best available torch device (CUDA/ROCm, then MPS, then CPU)."""
+    if device and device != "auto":
+        return device
+    if torch.cuda.is_available():
+        return "cuda"
+    if torch.backends.mps.is_available():
+        return "mps"
+    return "cpu"
+` + nemoAdapterPython + `
+
 def transcribe_audio(
     audio_path: str,
     source_lang: str = "en",
@@ -307,6 +354,9 @@ def transcribe_audio(
     output_file: str = None,
     include_confidence: bool = True,
     preserve_formatting: bool = True,
+    device: str = "auto",
+    adapter_path: str = None,
+    adapter_name: str = None,
 ):
     """
     Transcribe or translate audio using NVIDIA Canary model.
@@ -320,7 +370,11 @@ def transcribe_audio(
         sys.exit(1)
     
     print(f"Loading NVIDIA Canary model from: {model_path}")
-    asr_model = nemo_asr.models.ASRModel.restore_from(model_path)
+    device = resolve_device(device)
+    print(f"Using device: {device}")
+    asr_model = nemo_asr.models.ASRModel.restore_from(model_path, map_location=torch.device(device))
+    asr_model = asr_model.to(device)
+    load_nemo_adapter(asr_model, adapter_path, adapter_name, device)
     
     print(f"Processing: {audio_path}")
     print(f"Task: {task}")
@@ -454,6 +508,16 @@ def main():
         "--preserve-formatting", action="store_true", default=True,
         help="Preserve punctuation and capitalization"
     )
+    parser.add_argument(
+        "--device", choices=["auto", "cpu", "cuda", "mps"], default="auto",
+        help="Device to use for inference (default: auto-detect)"
+    )
+    parser.add_argument(
+        "--adapter", help="NeMo adapter/LoRA weights saved with save_adapters()"
+    )
+    parser.add_argument(
+        "--adapter-name", help="Adapter to enable (default: all in the checkpoint)"
+    )
     
     args = parser.parse_args()
     
@@ -472,6 +536,9 @@ def main():
             output_file=args.output,
             include_confidence=args.include_confidence,
             preserve_formatting=args.preserve_formatting,
+            device=args.device,
+            adapter_path=args.adapter,
+            adapter_name=args.adapter_name,
         )
     except Exception as e:
         print(f"Error during transcription: {e}")
@@ -504,7 +571,7 @@ func (c *CanaryAdapter) Transcribe(ctx context.Context, input interfaces.AudioIn
 
 	// Validate parameters
 	if err := c.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
 	// Create temporary directory
@@ -533,7 +600,7 @@ func (c *CanaryAdapter) Transcribe(ctx context.Context, input interfaces.AudioIn
 
 	// Execute Canary
 	cmd := exec.CommandContext(ctx, "uv", args...)
-	cmd.Env = append(os.Environ(),
+	cmd.Env = uvEnv(
 		"PYTHONUNBUFFERED=1",
 		"PYTORCH_CUDA_ALLOC_CONF=expandable_segments:True")
 
@@ -599,6 +666,7 @@ func (c *CanaryAdapter) buildCanaryArgs(input interfaces.AudioInput, params map[
 	args = append(args, "--source-lang", c.GetStringParameter(params, "source_lang"))
 	args = append(args, "--target-lang", c.GetStringParameter(params, "target_lang"))
 	args = append(args, "--task", c.GetStringParameter(params, "task"))
+	args = append(args, "--device", DetectGPUBackend().TorchDevice(c.GetStringParameter(params, "device")))
 
 	// Add timestamps flag
 	if c.GetBoolParameter(params, "timestamps") {
@@ -619,6 +687,8 @@ func (c *CanaryAdapter) buildCanaryArgs(input interfaces.AudioInput, params map[
 		args = append(args, "--preserve-formatting")
 	}
 
+	args = append(args, nemoAdapterArgs(c.BaseAdapter, params)...)
+
 	return args, nil
 }
 
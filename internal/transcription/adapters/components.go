@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stampedPackages are the Python packages whose versions are recorded for every
+// job: those that decode, align or diarize audio, and the frameworks under them
+var stampedPackages = map[string]bool{
+	"whisperx":       true,
+	"faster-whisper": true,
+	"ctranslate2":    true,
+	"openai-whisper": true,
+	"transformers":   true,
+	"torch":          true,
+	"torchaudio":     true,
+	"pyannote-audio": true,
+	"nemo-toolkit":   true,
+	"silero-vad":     true,
+	"onnxruntime":    true,
+}
+
+// lockCache holds parsed lock files by path, until they change
+var lockCache sync.Map // path -> lockVersions
+
+type lockVersions struct {
+	modTime  time.Time
+	versions map[string]string
+}
+
+// PackageVersions returns the locked versions of the stamped packages in the
+// adapter's environment; nil when it has no uv.lock
+func (b *BaseAdapter) PackageVersions() map[string]string {
+	if b.modelPath == "" {
+		return nil
+	}
+	path := filepath.Join(b.modelPath, "uv.lock")
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if cached, ok := lockCache.Load(path); ok && cached.(lockVersions).modTime.Equal(info.ModTime()) {
+		return cached.(lockVersions).versions
+	}
+	versions, err := readLockVersions(path)
+	if err != nil {
+		return nil
+	}
+	lockCache.Store(path, lockVersions{modTime: info.ModTime(), versions: versions})
+	return versions
+}
+
+// readLockVersions reads the name and version of each [[package]] of a uv.lock
+// file, keeping the stamped packages
+func readLockVersions(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	versions := make(map[string]string)
+	var name string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "[[package]]"):
+			name = ""
+		case strings.HasPrefix(line, "name = "):
+			name, _ = strconv.Unquote(strings.TrimPrefix(line, "name = "))
+		case strings.HasPrefix(line, "version = ") && stampedPackages[name]:
+			if version, err := strconv.Unquote(strings.TrimPrefix(line, "version = ")); err == nil {
+				versions[name] = version
+			}
+		}
+	}
+	return versions, scanner.Err()
+}
+
+// Checkpoints returns the models a call with params loads: the model and, when
+// diarizing, the diarization model it names, or the checkpoint the adapter is
+// built around when params name none
+func (b *BaseAdapter) Checkpoints(params map[string]interface{}) []string {
+	var checkpoints []string
+	if model, _ := params["model"].(string); model != "" {
+		checkpoints = append(checkpoints, model)
+	}
+	if diarize, _ := params["diarize"].(bool); diarize {
+		if model, _ := params["diarize_model"].(string); model != "" {
+			checkpoints = append(checkpoints, model)
+		}
+	}
+	if len(checkpoints) == 0 && b.capabilities.Metadata["checkpoint"] != "" {
+		checkpoints = append(checkpoints, b.capabilities.Metadata["checkpoint"])
+	}
+	return checkpoints
+}
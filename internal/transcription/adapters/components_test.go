@@ -0,0 +1,56 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLock = `version = 1
+requires-python = ">=3.11"
+
+[[package]]
+name = "torch"
+version = "2.5.1"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [
+    { name = "filelock" },
+]
+
+[[package]]
+name = "filelock"
+version = "3.16.1"
+
+[[package]]
+name = "whisperx"
+version = "3.3.1"
+`
+
+func TestPackageVersions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uv.lock"), []byte(testLock), 0644))
+	adapter := NewBaseAdapter("whisperx", dir, interfaces.ModelCapabilities{}, nil)
+
+	assert.Equal(t, map[string]string{"torch": "2.5.1", "whisperx": "3.3.1"}, adapter.PackageVersions(),
+		"only stamped packages, not their dependencies")
+	assert.Nil(t, NewBaseAdapter("whisperx", t.TempDir(), interfaces.ModelCapabilities{}, nil).PackageVersions())
+}
+
+func TestCheckpoints(t *testing.T) {
+	adapter := NewBaseAdapter("parakeet", "", interfaces.ModelCapabilities{
+		Metadata: map[string]string{"checkpoint": "nvidia/parakeet-tdt-0.6b-v3"},
+	}, nil)
+
+	assert.Equal(t, []string{"nvidia/parakeet-tdt-0.6b-v3"}, adapter.Checkpoints(map[string]interface{}{}))
+	assert.Equal(t, []string{"large-v3"}, adapter.Checkpoints(map[string]interface{}{
+		"model": "large-v3", "diarize_model": "pyannote", "diarize": false,
+	}))
+	assert.Equal(t, []string{"large-v3", "pyannote"}, adapter.Checkpoints(map[string]interface{}{
+		"model": "large-v3", "diarize_model": "pyannote", "diarize": true,
+	}))
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A custom Whisper checkpoint is a fine-tuned model in the CTranslate2 format that
+// faster-whisper loads: either a local directory or a Hugging Face repository.
+// Transformers checkpoints must first be converted with ct2-transformers-converter.
+
+// customModelCacheTTL is how long a successful Hugging Face lookup is trusted
+const customModelCacheTTL = time.Hour
+
+var huggingFaceRepoPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*/[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var (
+	customModelCacheMu sync.Mutex
+	customModelCache   = make(map[string]time.Time)
+)
+
+// ValidateCustomWhisperModel checks that ref names a CTranslate2 Whisper checkpoint:
+// an absolute path to a directory holding model.bin, or a Hugging Face repo ID such
+// as "org/whisper-medium-legal-ct2". Private repos are looked up with hfToken.
+// Successful repo lookups are cached, and skipped entirely in offline mode.
+func ValidateCustomWhisperModel(ctx context.Context, ref, hfToken string) error {
+	if filepath.IsAbs(ref) {
+		return validateLocalWhisperModel(ref)
+	}
+	if !huggingFaceRepoPattern.MatchString(ref) {
+		return fmt.Errorf("custom model %q must be an absolute path or a Hugging Face repo ID (owner/name)", ref)
+	}
+	if offlineMode() {
+		return nil
+	}
+
+	key := customModelCacheKey(ref, hfToken)
+	customModelCacheMu.Lock()
+	validated, ok := customModelCache[key]
+	customModelCacheMu.Unlock()
+	if ok && time.Since(validated) < customModelCacheTTL {
+		return nil
+	}
+
+	if err := validateHuggingFaceWhisperModel(ctx, ref, hfToken); err != nil {
+		return err
+	}
+
+	customModelCacheMu.Lock()
+	customModelCache[key] = time.Now()
+	customModelCacheMu.Unlock()
+	return nil
+}
+
+func validateLocalWhisperModel(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("custom model directory %s does not exist", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "model.bin")); err != nil {
+		return fmt.Errorf("custom model directory %s has no model.bin; convert the checkpoint with ct2-transformers-converter", dir)
+	}
+	return nil
+}
+
+func validateHuggingFaceWhisperModel(ctx context.Context, repo, hfToken string) error {
+	endpoint := os.Getenv("HF_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://huggingface.co"
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// The repo pattern only admits URL-safe characters
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(endpoint, "/")+"/api/models/"+repo, nil)
+	if err != nil {
+		return err
+	}
+	if hfToken != "" {
+		req.Header.Set("Authorization", "Bearer "+hfToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to look up custom model %s: %w", repo, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		if hfToken == "" {
+			return fmt.Errorf("custom model %s was not found; private repositories need a Hugging Face token", repo)
+		}
+		return fmt.Errorf("custom model %s was not found or the Hugging Face token cannot access it", repo)
+	default:
+		return fmt.Errorf("failed to look up custom model %s: Hugging Face returned %s", repo, resp.Status)
+	}
+
+	var info struct {
+		Siblings []struct {
+			Filename string `json:"rfilename"`
+		} `json:"siblings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return fmt.Errorf("failed to read custom model %s: %w", repo, err)
+	}
+	for _, sibling := range info.Siblings {
+		if sibling.Filename == "model.bin" {
+			return nil
+		}
+	}
+	return fmt.Errorf("custom model %s is not a CTranslate2 checkpoint (no model.bin); convert it with ct2-transformers-converter", repo)
+}
+
+// customModelCacheKey keys cached lookups by repo and token, so a token without
+// access to a private repo does not reuse another token's result
+func customModelCacheKey(repo, hfToken string) string {
+	sum := sha256.Sum256([]byte(hfToken))
+	return repo + ":" + hex.EncodeToString(sum[:8])
+}
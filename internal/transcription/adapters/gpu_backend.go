@@ -0,0 +1,175 @@
+package adapters
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"scriberr/pkg/logger"
+)
+
+// GPUBackend identifies the accelerator stack the local Python environments are built for
+type GPUBackend string
+
+const (
+	GPUBackendCPU  GPUBackend = "cpu"
+	GPUBackendCUDA GPUBackend = "cuda"
+	GPUBackendROCm GPUBackend = "rocm"
+	GPUBackendMPS  GPUBackend = "mps"
+)
+
+const (
+	cudaTorchIndexURL        = "https://download.pytorch.org/whl/cu126"
+	cpuTorchIndexURL         = "https://download.pytorch.org/whl/cpu"
+	defaultROCmTorchIndexURL = "https://download.pytorch.org/whl/rocm6.3"
+
+	// gpuBackendMarker records which backend an environment was synced for
+	gpuBackendMarker = ".scriberr-gpu-backend"
+)
+
+var (
+	detectedBackend     GPUBackend
+	detectedBackendOnce sync.Once
+)
+
+// DetectGPUBackend returns the backend selected by GPU_BACKEND, or probes the host
+// when unset or "auto". The result is computed once per process.
+func DetectGPUBackend() GPUBackend {
+	detectedBackendOnce.Do(func() {
+		detectedBackend = detectGPUBackend()
+		logger.Info("Selected GPU backend for local adapters", "backend", detectedBackend)
+	})
+	return detectedBackend
+}
+
+func detectGPUBackend() GPUBackend {
+	switch GPUBackend(strings.ToLower(os.Getenv("GPU_BACKEND"))) {
+	case GPUBackendCPU:
+		return GPUBackendCPU
+	case GPUBackendCUDA:
+		return GPUBackendCUDA
+	case GPUBackendROCm:
+		return GPUBackendROCm
+	case GPUBackendMPS:
+		return GPUBackendMPS
+	}
+
+	if runtime.GOOS == "darwin" {
+		if runtime.GOARCH == "arm64" {
+			return GPUBackendMPS
+		}
+		return GPUBackendCPU
+	}
+	if runtime.GOOS != "linux" {
+		return GPUBackendCPU
+	}
+	if _, err := exec.LookPath("nvidia-smi"); err == nil {
+		return GPUBackendCUDA
+	}
+	if runtime.GOARCH == "amd64" {
+		if _, err := os.Stat("/dev/kfd"); err == nil {
+			return GPUBackendROCm
+		}
+		if _, err := exec.LookPath("rocm-smi"); err == nil {
+			return GPUBackendROCm
+		}
+	}
+	return GPUBackendCPU
+}
+
+// PyTorchIndexURL returns the wheel index used for torch/torchaudio on Linux x86_64.
+// macOS and non-x86 hosts always use the CPU index, whose macOS wheels include MPS.
+func (b GPUBackend) PyTorchIndexURL() string {
+	switch b {
+	case GPUBackendROCm:
+		if url := os.Getenv("ROCM_TORCH_INDEX_URL"); url != "" {
+			return url
+		}
+		return defaultROCmTorchIndexURL
+	case GPUBackendCPU, GPUBackendMPS:
+		return cpuTorchIndexURL
+	default:
+		return cudaTorchIndexURL
+	}
+}
+
+// TorchDevice maps a requested device ("auto", "cpu", "cuda", "rocm", "mps") to the
+// device string PyTorch expects. ROCm builds of PyTorch expose HIP devices as "cuda".
+func (b GPUBackend) TorchDevice(requested string) string {
+	switch strings.ToLower(requested) {
+	case "cpu":
+		return "cpu"
+	case "cuda", "rocm":
+		if b == GPUBackendCUDA || b == GPUBackendROCm {
+			return "cuda"
+		}
+	case "mps":
+		if b == GPUBackendMPS {
+			return "mps"
+		}
+	case "", "auto":
+		switch b {
+		case GPUBackendCUDA, GPUBackendROCm:
+			return "cuda"
+		case GPUBackendMPS:
+			return "mps"
+		}
+		return "cpu"
+	}
+
+	logger.Warn("Requested device is not available for this GPU backend, using CPU",
+		"requested", requested, "backend", b)
+	return "cpu"
+}
+
+// CTranslate2Device maps a requested device for faster-whisper/CTranslate2, which
+// only ships CPU and CUDA kernels. ROCm and MPS hosts run WhisperX ASR on the CPU.
+func (b GPUBackend) CTranslate2Device(requested string) string {
+	requested = strings.ToLower(requested)
+	switch {
+	case requested == "cpu":
+		return "cpu"
+	case b == GPUBackendCUDA && (requested == "" || requested == "auto" || requested == "cuda"):
+		return "cuda"
+	case requested == "" || requested == "auto":
+		return "cpu"
+	}
+
+	logger.Warn("WhisperX (CTranslate2) has no kernels for the requested device, using CPU",
+		"requested", requested, "backend", b)
+	return "cpu"
+}
+
+// gpuBackendEnv returns extra environment variables for adapter subprocesses
+func gpuBackendEnv() []string {
+	if DetectGPUBackend() == GPUBackendMPS {
+		// Let operators without an MPS kernel fall back to the CPU instead of failing
+		return []string{"PYTORCH_ENABLE_MPS_FALLBACK=1"}
+	}
+	return nil
+}
+
+// gpuBackendMatches reports whether envPath was synced for the current backend.
+// Environments created before the marker existed were built with CUDA wheels,
+// which also run on the CPU.
+func gpuBackendMatches(envPath string) bool {
+	return envMatchesBackend(envPath, DetectGPUBackend())
+}
+
+func envMatchesBackend(envPath string, backend GPUBackend) bool {
+	data, err := os.ReadFile(filepath.Join(envPath, gpuBackendMarker))
+	if err != nil {
+		return backend == GPUBackendCUDA || backend == GPUBackendCPU
+	}
+	return GPUBackend(strings.TrimSpace(string(data))) == backend
+}
+
+// markGPUBackend records the backend an environment was synced for
+func markGPUBackend(envPath string) {
+	if err := os.WriteFile(filepath.Join(envPath, gpuBackendMarker), []byte(DetectGPUBackend()), 0644); err != nil {
+		logger.Warn("Failed to write GPU backend marker", "env_path", envPath, "error", err)
+	}
+}
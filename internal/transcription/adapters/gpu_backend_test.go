@@ -0,0 +1,129 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectGPUBackendOverride(t *testing.T) {
+	tests := []struct {
+		env      string
+		expected GPUBackend
+	}{
+		{"cpu", GPUBackendCPU},
+		{"CUDA", GPUBackendCUDA},
+		{"rocm", GPUBackendROCm},
+		{"Mps", GPUBackendMPS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("GPU_BACKEND", tt.env)
+			assert.Equal(t, tt.expected, detectGPUBackend())
+		})
+	}
+}
+
+func TestTorchDevice(t *testing.T) {
+	tests := []struct {
+		backend   GPUBackend
+		requested string
+		expected  string
+	}{
+		{GPUBackendCUDA, "auto", "cuda"},
+		{GPUBackendCUDA, "", "cuda"},
+		{GPUBackendCUDA, "CUDA", "cuda"},
+		{GPUBackendCUDA, "cpu", "cpu"},
+		{GPUBackendCUDA, "mps", "cpu"},
+		// ROCm builds of PyTorch expose HIP devices as "cuda"
+		{GPUBackendROCm, "auto", "cuda"},
+		{GPUBackendROCm, "rocm", "cuda"},
+		{GPUBackendROCm, "cuda", "cuda"},
+		{GPUBackendMPS, "auto", "mps"},
+		{GPUBackendMPS, "mps", "mps"},
+		{GPUBackendMPS, "cuda", "cpu"},
+		{GPUBackendCPU, "auto", "cpu"},
+		{GPUBackendCPU, "cuda", "cpu"},
+		{GPUBackendCPU, "tpu", "cpu"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.backend)+"/"+tt.requested, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.backend.TorchDevice(tt.requested))
+		})
+	}
+}
+
+func TestCTranslate2Device(t *testing.T) {
+	tests := []struct {
+		backend   GPUBackend
+		requested string
+		expected  string
+	}{
+		{GPUBackendCUDA, "auto", "cuda"},
+		{GPUBackendCUDA, "", "cuda"},
+		{GPUBackendCUDA, "cuda", "cuda"},
+		{GPUBackendCUDA, "cpu", "cpu"},
+		// CTranslate2 has no ROCm or MPS kernels
+		{GPUBackendROCm, "auto", "cpu"},
+		{GPUBackendROCm, "cuda", "cpu"},
+		{GPUBackendMPS, "auto", "cpu"},
+		{GPUBackendMPS, "mps", "cpu"},
+		{GPUBackendCPU, "auto", "cpu"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.backend)+"/"+tt.requested, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.backend.CTranslate2Device(tt.requested))
+		})
+	}
+}
+
+func TestPyTorchIndexURL(t *testing.T) {
+	t.Setenv("ROCM_TORCH_INDEX_URL", "")
+	assert.Equal(t, cudaTorchIndexURL, GPUBackendCUDA.PyTorchIndexURL())
+	assert.Equal(t, cpuTorchIndexURL, GPUBackendCPU.PyTorchIndexURL())
+	assert.Equal(t, cpuTorchIndexURL, GPUBackendMPS.PyTorchIndexURL())
+	assert.Equal(t, defaultROCmTorchIndexURL, GPUBackendROCm.PyTorchIndexURL())
+
+	t.Setenv("ROCM_TORCH_INDEX_URL", "https://example.com/rocm6.4")
+	assert.Equal(t, "https://example.com/rocm6.4", GPUBackendROCm.PyTorchIndexURL())
+}
+
+func TestEnvMatchesBackend(t *testing.T) {
+	t.Run("Unmarked", func(t *testing.T) {
+		// Environments created before the marker existed hold CUDA wheels
+		dir := t.TempDir()
+		assert.True(t, envMatchesBackend(dir, GPUBackendCUDA))
+		assert.True(t, envMatchesBackend(dir, GPUBackendCPU))
+		assert.False(t, envMatchesBackend(dir, GPUBackendROCm))
+		assert.False(t, envMatchesBackend(dir, GPUBackendMPS))
+	})
+
+	t.Run("Marked", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, gpuBackendMarker), []byte("rocm\n"), 0644))
+		assert.True(t, envMatchesBackend(dir, GPUBackendROCm))
+		assert.False(t, envMatchesBackend(dir, GPUBackendCUDA))
+		assert.False(t, envMatchesBackend(dir, GPUBackendCPU))
+	})
+}
+
+func TestParseHostResources(t *testing.T) {
+	gpus, err := parseNvidiaSMIMemory("0, 24576, 20480\n1, 16384, 1024\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []GPUMemory{{Index: 0, TotalMB: 24576, FreeMB: 20480}, {Index: 1, TotalMB: 16384, FreeMB: 1024}}, gpus)
+	free, ok := HostResources{GPUs: gpus}.FreeGPUMB()
+	assert.True(t, ok)
+	assert.Equal(t, 20480, free)
+	_, ok = HostResources{}.FreeGPUMB()
+	assert.False(t, ok)
+
+	_, err = parseNvidiaSMIMemory("0, [N/A], 1024")
+	assert.Error(t, err)
+
+	load, err := parseLoadAverage("3.20 2.10 1.05 2/512 12345\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 3.2, load)
+	assert.Equal(t, 0.8, HostResources{CPUs: 4, LoadAverage: load}.LoadPerCPU())
+}
@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GPUMemory is the memory of one GPU, in MiB
+type GPUMemory struct {
+	Index   int `json:"index"`
+	TotalMB int `json:"total_mb"`
+	FreeMB  int `json:"free_mb"`
+}
+
+// HostResources is what the host running the local adapters has to spare
+type HostResources struct {
+	// GPUs is empty when there are none or nvidia-smi cannot report them
+	GPUs []GPUMemory `json:"gpus"`
+	CPUs int         `json:"cpus"`
+	// LoadAverage is the one-minute load average; zero where procfs is missing
+	LoadAverage float64 `json:"load_average"`
+}
+
+// FreeGPUMB returns the free memory of the GPU with the most of it, and false
+// when no GPU was found
+func (r HostResources) FreeGPUMB() (int, bool) {
+	free, found := 0, false
+	for _, gpu := range r.GPUs {
+		if !found || gpu.FreeMB > free {
+			free, found = gpu.FreeMB, true
+		}
+	}
+	return free, found
+}
+
+// LoadPerCPU returns the load average per CPU
+func (r HostResources) LoadPerCPU() float64 {
+	if r.CPUs == 0 {
+		return 0
+	}
+	return r.LoadAverage / float64(r.CPUs)
+}
+
+// nvidiaSMITimeout bounds how long probing the GPUs may take
+const nvidiaSMITimeout = 5 * time.Second
+
+// ProbeHostResources reads the GPUs' memory with nvidia-smi and the load
+// average from procfs. Either is left out when it cannot be read.
+func ProbeHostResources(ctx context.Context) HostResources {
+	resources := HostResources{CPUs: runtime.NumCPU()}
+	if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+		resources.LoadAverage, _ = parseLoadAverage(string(data))
+	}
+	if DetectGPUBackend() != GPUBackendCUDA {
+		return resources
+	}
+	ctx, cancel := context.WithTimeout(ctx, nvidiaSMITimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "nvidia-smi",
+		"--query-gpu=index,memory.total,memory.free", "--format=csv,noheader,nounits").Output()
+	if err == nil {
+		resources.GPUs, _ = parseNvidiaSMIMemory(string(output))
+	}
+	return resources
+}
+
+// parseNvidiaSMIMemory parses "index, total, free" lines of nvidia-smi CSV output
+func parseNvidiaSMIMemory(output string) ([]GPUMemory, error) {
+	var gpus []GPUMemory
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected nvidia-smi output: %q", line)
+		}
+		var values [3]int
+		for i, field := range fields {
+			value, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return nil, fmt.Errorf("unexpected nvidia-smi output: %q", line)
+			}
+			values[i] = value
+		}
+		gpus = append(gpus, GPUMemory{Index: values[0], TotalMB: values[1], FreeMB: values[2]})
+	}
+	return gpus, nil
+}
+
+// parseLoadAverage returns the one-minute load average of /proc/loadavg
+func parseLoadAverage(data string) (float64, error) {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty load average")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
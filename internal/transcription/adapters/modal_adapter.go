@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"scriberr/internal/transcription/interfaces"
@@ -14,10 +15,27 @@ import (
 	"github.com/modal-labs/libmodal/modal-go"
 )
 
+const (
+	DefaultModalPollInterval = 30 * time.Second
+	DefaultModalTimeout      = 3 * time.Hour
+)
+
 type ModalAdapter struct {
 	*BaseAdapter
 	client       *modal.Client
 	FunctionName string
+	// PollInterval is how often a spawned call is checked on, logging that it
+	// is still running
+	PollInterval time.Duration
+	// Timeout bounds a call, which is cancelled once it is reached; 0 waits
+	// for it
+	Timeout time.Duration
+}
+
+// modalCall is the part of a spawned function call the adapter uses
+type modalCall interface {
+	Get(ctx context.Context, params *modal.FunctionCallGetParams) (any, error)
+	Cancel(ctx context.Context, params *modal.FunctionCallCancelParams) error
 }
 
 func NewModalAdapter(w *WhisperXAdapter, client *modal.Client) *ModalAdapter {
@@ -32,6 +50,8 @@ func NewModalAdapter(w *WhisperXAdapter, client *modal.Client) *ModalAdapter {
 		BaseAdapter:  baseAdapter,
 		client:       client,
 		FunctionName: appName,
+		PollInterval: time.Duration(envInt("MODAL_POLL_INTERVAL_SECONDS", int64(DefaultModalPollInterval/time.Second))) * time.Second,
+		Timeout:      time.Duration(envInt("MODAL_TIMEOUT_MINUTES", int64(DefaultModalTimeout/time.Minute))) * time.Minute,
 	}
 }
 
@@ -54,7 +74,7 @@ func (m *ModalAdapter) Transcribe(ctx context.Context, input interfaces.AudioInp
 	}()
 
 	if err := m.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
 	transcribe, err := m.client.Functions.FromName(ctx, m.FunctionName, "transcribe", nil)
@@ -69,7 +89,14 @@ func (m *ModalAdapter) Transcribe(ctx context.Context, input interfaces.AudioInp
 	}
 	encodedAudio := base64.StdEncoding.EncodeToString(audioBytes)
 	params["audio_base64"] = encodedAudio
-	ret, err := transcribe.Remote(ctx, []any{procCtx.JobID, params}, nil)
+	// Spawned calls run on Modal whatever happens to this connection, and are
+	// waited for in polls short enough not to be cut off
+	call, err := transcribe.Spawn(ctx, []any{procCtx.JobID, params}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call Modal function: %w", err)
+	}
+	logger.Info("Modal function call spawned", "job_id", procCtx.JobID, "function_call_id", call.FunctionCallID)
+	ret, err := m.await(ctx, call, call.FunctionCallID, procCtx)
 	if err != nil {
 		return nil, fmt.Errorf("call Modal function: %w", err)
 	}
@@ -92,6 +119,57 @@ func (m *ModalAdapter) Transcribe(ctx context.Context, input interfaces.AudioInp
 	return result, nil
 }
 
+// await polls a spawned call until it returns. Calls still running when ctx
+// is done or the timeout is reached are cancelled on Modal.
+func (m *ModalAdapter) await(ctx context.Context, call modalCall, callID string, procCtx interfaces.ProcessingContext) (any, error) {
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+	interval := m.PollInterval
+	if interval <= 0 {
+		interval = DefaultModalPollInterval
+	}
+
+	// Modal does not say how far a call has got, only that it has not returned
+	procCtx.ReportProgress(interfaces.ProgressRunning, -1)
+	started := time.Now()
+	for {
+		ret, err := call.Get(ctx, &modal.FunctionCallGetParams{Timeout: &interval})
+		if err == nil {
+			return ret, nil
+		}
+		if ctx.Err() != nil {
+			m.cancel(call, callID)
+			return nil, ctx.Err()
+		}
+		if !modalPollTimedOut(err) {
+			return nil, err
+		}
+		logger.Info("Modal function call still running", "job_id", procCtx.JobID, "function_call_id", callID,
+			"elapsed", time.Since(started).Round(time.Second))
+	}
+}
+
+// modalPollTimedOut reports whether err only says a call did not return
+// within the poll, as opposed to the call itself timing out on Modal
+func modalPollTimedOut(err error) bool {
+	var timeout modal.FunctionTimeoutError
+	return errors.As(err, &timeout) && strings.HasPrefix(timeout.Exception, "Timeout exceeded")
+}
+
+// cancel stops a call on Modal, even though the job's context is done
+func (m *ModalAdapter) cancel(call modalCall, callID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := call.Cancel(ctx, nil); err != nil {
+		logger.Warn("Failed to cancel Modal function call", "function_call_id", callID, "error", err)
+		return
+	}
+	logger.Info("Modal function call cancelled", "function_call_id", callID)
+}
+
 func (m *ModalAdapter) GetSupportedModels() []string {
 	return []string{"modal-cloud"}
 }
@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/modal-labs/libmodal/modal-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeModalCall returns after a number of polls, each of which waits for up to
+// the poll's timeout like Modal does
+type fakeModalCall struct {
+	polls       int
+	returnAfter int
+	result      any
+	err         error
+	cancelled   bool
+}
+
+func (c *fakeModalCall) Get(ctx context.Context, params *modal.FunctionCallGetParams) (any, error) {
+	c.polls++
+	if c.polls >= c.returnAfter {
+		return c.result, c.err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("FunctionGetOutputs failed: %w", ctx.Err())
+	case <-time.After(*params.Timeout):
+		return nil, modal.FunctionTimeoutError{Exception: fmt.Sprintf("Timeout exceeded: %.1fs", params.Timeout.Seconds())}
+	}
+}
+
+func (c *fakeModalCall) Cancel(context.Context, *modal.FunctionCallCancelParams) error {
+	c.cancelled = true
+	return nil
+}
+
+func newTestModal(t *testing.T, interval, timeout time.Duration) *ModalAdapter {
+	adapter := NewModalAdapter(NewWhisperXAdapter(t.TempDir()), nil)
+	adapter.PollInterval = interval
+	adapter.Timeout = timeout
+	return adapter
+}
+
+func TestModalPollsSpawnedCalls(t *testing.T) {
+	adapter := newTestModal(t, time.Millisecond, time.Minute)
+	call := &fakeModalCall{returnAfter: 4, result: `{"language":"en"}`}
+
+	ret, err := adapter.await(context.Background(), call, "fc-1", interfaces.ProcessingContext{JobID: "job"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"language":"en"}`, ret)
+	assert.Equal(t, 4, call.polls)
+	assert.False(t, call.cancelled)
+}
+
+func TestModalReportsFailedCalls(t *testing.T) {
+	adapter := newTestModal(t, time.Millisecond, time.Minute)
+
+	// The function timing out on Modal is not mistaken for a poll timing out
+	for _, failure := range []error{
+		modal.RemoteError{Exception: "CUDA out of memory"},
+		modal.FunctionTimeoutError{Exception: "Function timed out after 3600s"},
+	} {
+		call := &fakeModalCall{returnAfter: 2, err: failure}
+		_, err := adapter.await(context.Background(), call, "fc-1", interfaces.ProcessingContext{JobID: "job"})
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 2, call.polls)
+		assert.False(t, call.cancelled)
+	}
+}
+
+func TestModalCancelsCallsThatTakeTooLong(t *testing.T) {
+	adapter := newTestModal(t, 5*time.Millisecond, 30*time.Millisecond)
+	call := &fakeModalCall{returnAfter: 1 << 30}
+
+	_, err := adapter.await(context.Background(), call, "fc-1", interfaces.ProcessingContext{JobID: "job"})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, call.cancelled)
+
+	// And when the job is stopped
+	adapter = newTestModal(t, 5*time.Millisecond, 0)
+	call = &fakeModalCall{returnAfter: 1 << 30}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = adapter.await(ctx, call, "fc-1", interfaces.ProcessingContext{JobID: "job"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, call.cancelled)
+}
+
+func TestModalSettingsFromEnvironment(t *testing.T) {
+	t.Setenv("MODAL_POLL_INTERVAL_SECONDS", "10")
+	t.Setenv("MODAL_TIMEOUT_MINUTES", "0")
+	adapter := NewModalAdapter(NewWhisperXAdapter(t.TempDir()), nil)
+	assert.Equal(t, 10*time.Second, adapter.PollInterval)
+	assert.Zero(t, adapter.Timeout)
+
+	t.Setenv("MODAL_POLL_INTERVAL_SECONDS", "")
+	t.Setenv("MODAL_TIMEOUT_MINUTES", "")
+	adapter = NewModalAdapter(NewWhisperXAdapter(t.TempDir()), nil)
+	assert.Equal(t, DefaultModalPollInterval, adapter.PollInterval)
+	assert.Equal(t, DefaultModalTimeout, adapter.Timeout)
+}
@@ -0,0 +1,82 @@
+package adapters
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// NeMo adapter/LoRA checkpoints are the .pt files written by NeMo's
+// model.save_adapters(). They are loaded onto the base Parakeet or Canary model
+// at the start of every run, so each profile can bring its own domain adaptation
+// without a separate Python environment.
+
+// nemoAdapterSchema describes the adapter parameters shared by the NeMo adapters
+var nemoAdapterSchema = []interfaces.ParameterSchema{
+	{
+		Name:        "adapter_path",
+		Type:        "string",
+		Required:    false,
+		Description: "Absolute path to NeMo adapter/LoRA weights saved with save_adapters()",
+		Group:       "advanced",
+	},
+	{
+		Name:        "adapter_name",
+		Type:        "string",
+		Required:    false,
+		Description: "Adapter to enable from the checkpoint (default: all of them)",
+		Group:       "advanced",
+	},
+}
+
+// nemoAdapterPython loads the --adapter checkpoint in the NeMo scripts
+const nemoAdapterPython = `
+def load_nemo_adapter(asr_model, adapter_path, adapter_name=None, device="cpu"):
+    """Load adapter/LoRA weights saved with save_adapters() and enable them."""
+    if not adapter_path:
+        return
+    if not os.path.exists(adapter_path):
+        raise FileNotFoundError(f"Adapter weights not found: {adapter_path}")
+    if not hasattr(asr_model, "load_adapters"):
+        raise RuntimeError("This model does not support NeMo adapters")
+
+    print(f"Loading adapter weights from: {adapter_path}")
+    asr_model.load_adapters(adapter_path, name=adapter_name or None, map_location=torch.device(device))
+    if adapter_name:
+        asr_model.set_enabled_adapters(enabled=False)
+        asr_model.set_enabled_adapters(name=adapter_name, enabled=True)
+    else:
+        asr_model.set_enabled_adapters(enabled=True)
+    print(f"Enabled adapters: {asr_model.get_enabled_adapters()}")
+`
+
+// nemoAdapterArgs returns the script arguments that load the adapter in params
+func nemoAdapterArgs(b *BaseAdapter, params map[string]interface{}) []string {
+	adapterPath := b.GetStringParameter(params, "adapter_path")
+	if adapterPath == "" {
+		return nil
+	}
+	args := []string{"--adapter", adapterPath}
+	if name := b.GetStringParameter(params, "adapter_name"); name != "" {
+		args = append(args, "--adapter-name", name)
+	}
+	return args
+}
+
+// ValidateNemoAdapter checks that path names a readable NeMo adapter checkpoint
+func ValidateNemoAdapter(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("adapter weights %q must be an absolute path", path)
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".pt" && ext != ".ckpt" {
+		return fmt.Errorf("adapter weights %s must be a .pt or .ckpt file saved with save_adapters()", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return fmt.Errorf("adapter weights %s do not exist", path)
+	}
+	return nil
+}
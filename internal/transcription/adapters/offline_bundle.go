@@ -0,0 +1,333 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/pkg/downloader"
+	"scriberr/pkg/logger"
+)
+
+// An offline bundle lets hosts without internet access bootstrap the local adapters.
+// It is built by the scriberr-bundle command on a connected machine with the same
+// OS, architecture and GPU backend, and laid out as:
+//
+//	uv-cache/      uv cache holding every wheel the environments install
+//	python/        uv-managed Python interpreters
+//	huggingface/   Hugging Face hub cache (Whisper, alignment, pyannote and
+//	               speaker age and gender weights)
+//	torch/         torch hub cache (torchaudio alignment models)
+//	models/        NeMo checkpoints for Parakeet, Canary and Sortformer
+//	src/WhisperX/  WhisperX source checkout
+//	bundle.json    manifest describing the bundle
+//
+// Setting OFFLINE_BUNDLE_DIR makes the adapters install and run from the bundle only.
+const bundleManifestFile = "bundle.json"
+
+// BundleManifest describes how an offline bundle was built
+type BundleManifest struct {
+	CreatedAt      time.Time  `json:"created_at"`
+	GOOS           string     `json:"goos"`
+	GOARCH         string     `json:"goarch"`
+	GPUBackend     GPUBackend `json:"gpu_backend"`
+	WhisperModels  []string   `json:"whisper_models"`
+	AlignLanguages []string   `json:"align_languages"`
+	Diarization    bool       `json:"diarization"`
+	NeMo           bool       `json:"nemo"`
+}
+
+var (
+	offlineBundleOnce  sync.Once
+	offlineBundleDir   string
+	offlineBundleBuild bool
+)
+
+// OfflineBundleDir returns the offline bundle directory, or "" when adapters
+// download from the internet as usual
+func OfflineBundleDir() string {
+	offlineBundleOnce.Do(func() {
+		dir := os.Getenv("OFFLINE_BUNDLE_DIR")
+		if dir == "" {
+			return
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		offlineBundleDir = dir
+		logger.Info("Using offline bundle for local adapters", "dir", dir)
+		checkBundleManifest(dir)
+	})
+	return offlineBundleDir
+}
+
+// ConfigureOfflineBundleBuild makes the adapters populate the bundle at dir while
+// preparing their environments instead of reading from it. It must be called
+// before any adapter is prepared.
+func ConfigureOfflineBundleBuild(dir string) {
+	offlineBundleOnce.Do(func() {})
+	offlineBundleDir = dir
+	offlineBundleBuild = true
+}
+
+// offlineMode reports whether adapters must not touch the network
+func offlineMode() bool {
+	return OfflineBundleDir() != "" && !offlineBundleBuild
+}
+
+// checkBundleManifest warns when the bundle was built for a different host
+func checkBundleManifest(dir string) {
+	manifest, err := ReadBundleManifest(dir)
+	if err != nil {
+		logger.Warn("Offline bundle has no readable manifest", "dir", dir, "error", err)
+		return
+	}
+	if manifest.GOOS != runtime.GOOS || manifest.GOARCH != runtime.GOARCH {
+		logger.Warn("Offline bundle was built for a different platform",
+			"bundle", manifest.GOOS+"/"+manifest.GOARCH, "host", runtime.GOOS+"/"+runtime.GOARCH)
+	}
+	if backend := DetectGPUBackend(); manifest.GPUBackend != backend {
+		logger.Warn("Offline bundle was built for a different GPU backend",
+			"bundle", manifest.GPUBackend, "host", backend)
+	}
+}
+
+// ReadBundleManifest reads the manifest of the bundle at dir
+func ReadBundleManifest(dir string) (*BundleManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// WriteBundleManifest writes the manifest of the bundle at dir
+func WriteBundleManifest(dir string, manifest BundleManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, bundleManifestFile), data, 0644)
+}
+
+// offlineBundleEnv points uv, Hugging Face and torch at the bundle's caches and,
+// outside of build mode, forbids them from using the network
+func offlineBundleEnv() []string {
+	dir := OfflineBundleDir()
+	if dir == "" {
+		return nil
+	}
+	env := []string{
+		"UV_CACHE_DIR=" + filepath.Join(dir, "uv-cache"),
+		"UV_PYTHON_INSTALL_DIR=" + filepath.Join(dir, "python"),
+		"UV_PYTHON_PREFERENCE=only-managed",
+		"HF_HOME=" + filepath.Join(dir, "huggingface"),
+		"TORCH_HOME=" + filepath.Join(dir, "torch"),
+	}
+	if offlineMode() {
+		env = append(env,
+			"UV_OFFLINE=1",
+			"UV_PYTHON_DOWNLOADS=never",
+			"HF_HUB_OFFLINE=1",
+			"TRANSFORMERS_OFFLINE=1")
+	}
+	return env
+}
+
+// uvEnv returns the environment for uv subprocesses. Later entries win, so extra
+// overrides the GPU backend and offline bundle settings.
+func uvEnv(extra ...string) []string {
+	env := append(os.Environ(), gpuBackendEnv()...)
+	env = append(env, offlineBundleEnv()...)
+	return append(env, extra...)
+}
+
+// fetchModelFile places the checkpoint at url into dest. With an offline bundle the
+// file comes from the bundle's models/ directory, which is filled first when
+// building one.
+func fetchModelFile(ctx context.Context, url, dest string) error {
+	dir := OfflineBundleDir()
+	if dir == "" {
+		return downloader.DownloadFile(ctx, url, dest)
+	}
+
+	bundled := filepath.Join(dir, "models", filepath.Base(dest))
+	if _, err := os.Stat(bundled); err != nil {
+		if offlineMode() {
+			return fmt.Errorf("%s not found in offline bundle %s", filepath.Base(dest), dir)
+		}
+		if err := os.MkdirAll(filepath.Dir(bundled), 0755); err != nil {
+			return err
+		}
+		if err := downloader.DownloadFile(ctx, url, bundled); err != nil {
+			return err
+		}
+	}
+
+	// Hard links avoid duplicating multi-gigabyte checkpoints on the same filesystem
+	if err := os.Link(bundled, dest); err == nil {
+		return nil
+	}
+	return copyFile(bundled, dest)
+}
+
+// fetchSourceTree places a git checkout of repoURL at dest, taking it from the
+// bundle's src/ directory when an offline bundle is configured
+func fetchSourceTree(repoURL, dest string) error {
+	dir := OfflineBundleDir()
+	if dir == "" {
+		return gitClone(repoURL, dest)
+	}
+
+	bundled := filepath.Join(dir, "src", filepath.Base(dest))
+	if _, err := os.Stat(bundled); err != nil {
+		if offlineMode() {
+			return fmt.Errorf("%s not found in offline bundle %s", filepath.Base(dest), dir)
+		}
+		if err := os.MkdirAll(filepath.Dir(bundled), 0755); err != nil {
+			return err
+		}
+		if err := gitClone(repoURL, bundled); err != nil {
+			return err
+		}
+	}
+	return copyDir(bundled, dest)
+}
+
+func gitClone(repoURL, dest string) error {
+	cmd := exec.Command("git", "clone", repoURL, dest)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git clone failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func copyDir(src, dest string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target)
+		}
+	})
+}
+
+// runPrefetch runs a Python snippet in the environment at projectPath so that the
+// models it loads land in the (bundle's) Hugging Face and torch caches
+func runPrefetch(ctx context.Context, projectPath, script string, args interface{}) error {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "uv", "run", "--native-tls", "--project", projectPath, "python", "-c", script, string(payload))
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("prefetch failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+const whisperXPrefetchScript = `
+import json, sys
+args = json.loads(sys.argv[1])
+
+from faster_whisper.utils import download_model
+for name in args["models"]:
+    print(f"Downloading Whisper model {name}", flush=True)
+    download_model(name)
+
+import whisperx
+for language in args["languages"]:
+    print(f"Downloading alignment model for {language}", flush=True)
+    whisperx.load_align_model(language_code=language, device="cpu")
+
+if args["hf_token"]:
+    from huggingface_hub import snapshot_download
+    for repo in ["pyannote/speaker-diarization-3.1", "pyannote/segmentation-3.0", "pyannote/wespeaker-voxceleb-resnet34-LM"]:
+        print(f"Downloading {repo}", flush=True)
+        snapshot_download(repo, token=args["hf_token"])
+`
+
+// PrefetchModels downloads Whisper weights, alignment models and, given a Hugging
+// Face token, the WhisperX diarization pipeline into the model caches
+func (w *WhisperXAdapter) PrefetchModels(ctx context.Context, models, languages []string, hfToken string) error {
+	return runPrefetch(ctx, filepath.Join(w.envPath, "WhisperX"), whisperXPrefetchScript, map[string]interface{}{
+		"models":    models,
+		"languages": languages,
+		"hf_token":  hfToken,
+	})
+}
+
+const pyannotePrefetchScript = `
+import json, sys
+args = json.loads(sys.argv[1])
+from pyannote.audio import Pipeline
+Pipeline.from_pretrained(args["model"], token=args["hf_token"])
+
+from huggingface_hub import snapshot_download
+print(f"Downloading {args['attribute_model']}", flush=True)
+snapshot_download(args["attribute_model"])
+`
+
+// PrefetchModels downloads the default pyannote pipeline and the speaker age
+// and gender model, which runs in the same environment, into the model caches
+func (p *PyAnnoteAdapter) PrefetchModels(ctx context.Context, hfToken string) error {
+	return runPrefetch(ctx, p.envPath, pyannotePrefetchScript, map[string]interface{}{
+		"model":           "pyannote/speaker-diarization-community-1",
+		"attribute_model": speakerAttributeModel,
+		"hf_token":        hfToken,
+	})
+}
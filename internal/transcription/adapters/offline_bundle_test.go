@@ -0,0 +1,224 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useOfflineBundle points the adapters at dir, in build mode if build is set,
+// for the duration of the test
+func useOfflineBundle(t *testing.T, dir string, build bool) {
+	t.Helper()
+	reset := func() {
+		offlineBundleOnce = sync.Once{}
+		offlineBundleDir = ""
+		offlineBundleBuild = false
+	}
+	reset()
+	t.Cleanup(reset)
+	if build {
+		ConfigureOfflineBundleBuild(dir)
+		return
+	}
+	t.Setenv("OFFLINE_BUNDLE_DIR", dir)
+}
+
+// envValue returns the last value of key in env, as the child process sees it
+func envValue(env []string, key string) (string, bool) {
+	value, found := "", false
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			value, found = strings.TrimPrefix(kv, key+"="), true
+		}
+	}
+	return value, found
+}
+
+func TestBundleManifestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	manifest := BundleManifest{
+		CreatedAt:      time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		GOOS:           "linux",
+		GOARCH:         "amd64",
+		GPUBackend:     GPUBackendROCm,
+		WhisperModels:  []string{"small", "large-v3"},
+		AlignLanguages: []string{"en", "de"},
+		Diarization:    true,
+		NeMo:           true,
+	}
+	require.NoError(t, WriteBundleManifest(dir, manifest))
+
+	read, err := ReadBundleManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, *read)
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := ReadBundleManifest(t.TempDir())
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, bundleManifestFile), []byte("{"), 0644))
+		_, err := ReadBundleManifest(dir)
+		assert.ErrorContains(t, err, "invalid bundle manifest")
+	})
+}
+
+func TestOfflineBundleEnv(t *testing.T) {
+	offlineKeys := []string{"UV_OFFLINE", "UV_PYTHON_DOWNLOADS", "HF_HUB_OFFLINE", "TRANSFORMERS_OFFLINE"}
+
+	t.Run("NoBundle", func(t *testing.T) {
+		useOfflineBundle(t, "", false)
+		assert.Empty(t, offlineBundleEnv())
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		dir := t.TempDir()
+		useOfflineBundle(t, dir, false)
+		env := uvEnv()
+		for key, expected := range map[string]string{
+			"UV_CACHE_DIR":          filepath.Join(dir, "uv-cache"),
+			"UV_PYTHON_INSTALL_DIR": filepath.Join(dir, "python"),
+			"UV_PYTHON_PREFERENCE":  "only-managed",
+			"HF_HOME":               filepath.Join(dir, "huggingface"),
+			"TORCH_HOME":            filepath.Join(dir, "torch"),
+			"UV_OFFLINE":            "1",
+			"UV_PYTHON_DOWNLOADS":   "never",
+			"HF_HUB_OFFLINE":        "1",
+			"TRANSFORMERS_OFFLINE":  "1",
+		} {
+			value, ok := envValue(env, key)
+			assert.True(t, ok, key)
+			assert.Equal(t, expected, value, key)
+		}
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		dir := t.TempDir()
+		useOfflineBundle(t, dir, true)
+		env := uvEnv()
+		value, _ := envValue(env, "HF_HOME")
+		assert.Equal(t, filepath.Join(dir, "huggingface"), value)
+		// Building a bundle downloads into it
+		for _, key := range offlineKeys {
+			_, ok := envValue(env, key)
+			assert.False(t, ok, key)
+		}
+	})
+
+	t.Run("ExtraOverrides", func(t *testing.T) {
+		useOfflineBundle(t, t.TempDir(), false)
+		value, _ := envValue(uvEnv("HF_HUB_OFFLINE=0"), "HF_HUB_OFFLINE")
+		assert.Equal(t, "0", value)
+	})
+}
+
+func TestFetchModelFile(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("checkpoint"))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("Build", func(t *testing.T) {
+		bundle := t.TempDir()
+		useOfflineBundle(t, bundle, true)
+		requests.Store(0)
+
+		dest := filepath.Join(t.TempDir(), "model.nemo")
+		require.NoError(t, fetchModelFile(ctx, server.URL+"/model.nemo", dest))
+		data, err := os.ReadFile(filepath.Join(bundle, "models", "model.nemo"))
+		require.NoError(t, err)
+		assert.Equal(t, "checkpoint", string(data))
+		data, err = os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "checkpoint", string(data))
+
+		// A second environment reuses the bundled file
+		require.NoError(t, fetchModelFile(ctx, server.URL+"/model.nemo", filepath.Join(t.TempDir(), "model.nemo")))
+		assert.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		bundle := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(bundle, "models"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(bundle, "models", "model.nemo"), []byte("bundled"), 0644))
+		useOfflineBundle(t, bundle, false)
+		requests.Store(0)
+
+		dest := filepath.Join(t.TempDir(), "model.nemo")
+		require.NoError(t, fetchModelFile(ctx, server.URL+"/model.nemo", dest))
+		data, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "bundled", string(data))
+
+		err = fetchModelFile(ctx, server.URL+"/other.nemo", filepath.Join(t.TempDir(), "other.nemo"))
+		assert.ErrorContains(t, err, "not found in offline bundle")
+		assert.EqualValues(t, 0, requests.Load())
+	})
+}
+
+func TestFetchSourceTree(t *testing.T) {
+	t.Run("Offline", func(t *testing.T) {
+		bundle := t.TempDir()
+		src := filepath.Join(bundle, "src", "WhisperX")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "whisperx"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "pyproject.toml"), []byte("[project]"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "whisperx", "__init__.py"), []byte(""), 0644))
+		require.NoError(t, os.Symlink("pyproject.toml", filepath.Join(src, "link.toml")))
+		useOfflineBundle(t, bundle, false)
+
+		dest := filepath.Join(t.TempDir(), "WhisperX")
+		require.NoError(t, fetchSourceTree("https://github.com/m-bain/WhisperX.git", dest))
+		assert.FileExists(t, filepath.Join(dest, "pyproject.toml"))
+		assert.FileExists(t, filepath.Join(dest, "whisperx", "__init__.py"))
+		link, err := os.Readlink(filepath.Join(dest, "link.toml"))
+		require.NoError(t, err)
+		assert.Equal(t, "pyproject.toml", link)
+
+		err = fetchSourceTree("https://github.com/example/Missing.git", filepath.Join(t.TempDir(), "Missing"))
+		assert.ErrorContains(t, err, "not found in offline bundle")
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git not installed")
+		}
+		// A local repository stands in for the upstream checkout
+		repo := filepath.Join(t.TempDir(), "Upstream")
+		require.NoError(t, os.MkdirAll(repo, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("upstream"), 0644))
+		for _, args := range [][]string{
+			{"init", "-q"},
+			{"add", "README.md"},
+			{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = repo
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+
+		bundle := t.TempDir()
+		useOfflineBundle(t, bundle, true)
+		dest := filepath.Join(t.TempDir(), "Upstream")
+		require.NoError(t, fetchSourceTree(repo, dest))
+		assert.FileExists(t, filepath.Join(bundle, "src", "Upstream", "README.md"))
+		assert.FileExists(t, filepath.Join(dest, "README.md"))
+	})
+}
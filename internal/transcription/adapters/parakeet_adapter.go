@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"scriberr/internal/transcription/interfaces"
-	"scriberr/pkg/downloader"
 	"scriberr/pkg/logger"
 )
 
@@ -42,6 +41,7 @@ func NewParakeetAdapter(envPath string) *ParakeetAdapter {
 			"high_quality":      true,
 		},
 		Metadata: map[string]string{
+			"checkpoint":  "nvidia/parakeet-tdt-0.6b-v3",
 			"engine":      "nvidia_nemo",
 			"framework":   "nemo_toolkit",
 			"license":     "CC-BY-4.0",
@@ -93,6 +93,17 @@ func NewParakeetAdapter(envPath string) *ParakeetAdapter {
 			Group:       "advanced",
 		},
 
+		// Performance settings
+		{
+			Name:        "device",
+			Type:        "string",
+			Required:    false,
+			Default:     "auto",
+			Options:     []string{"auto", "cpu", "cuda", "rocm", "mps"},
+			Description: "Device to use for computation (auto, cpu, cuda for NVIDIA, rocm for AMD, mps for Apple silicon)",
+			Group:       "advanced",
+		},
+
 		// Audio preprocessing
 		{
 			Name:        "auto_convert_audio",
@@ -106,7 +117,7 @@ func NewParakeetAdapter(envPath string) *ParakeetAdapter {
 		// Note: include_confidence removed as it's not supported by Parakeet script
 	}
 
-	baseAdapter := NewBaseAdapter("parakeet", envPath, capabilities, schema)
+	baseAdapter := NewBaseAdapter("parakeet", envPath, capabilities, append(schema, nemoAdapterSchema...))
 
 	adapter := &ParakeetAdapter{
 		BaseAdapter: baseAdapter,
@@ -128,21 +139,19 @@ func (p *ParakeetAdapter) PrepareEnvironment(ctx context.Context) error {
 	// Check if environment is already ready (using cache to speed up repeated checks)
 	if CheckEnvironmentReady(p.envPath, "import nemo.collections.asr") {
 		modelPath := filepath.Join(p.envPath, "parakeet-tdt-0.6b-v3.nemo")
-		scriptPath := filepath.Join(p.envPath, "transcribe.py")
-		bufferedScriptPath := filepath.Join(p.envPath, "transcribe_buffered.py")
 
-		// Check model, standard script, and buffered script all exist
+		// Check the model exists, then (re)write both scripts so existing installs
+		// pick up new script options
 		if stat, err := os.Stat(modelPath); err == nil && stat.Size() > 1024*1024 {
-			_, scriptErr := os.Stat(scriptPath)
-			_, bufferedErr := os.Stat(bufferedScriptPath)
-
-			if scriptErr == nil && bufferedErr == nil {
-				logger.Info("Parakeet environment already ready")
-				p.initialized = true
-				return nil
-			} else {
-				logger.Info("Parakeet model exists but scripts missing, recreating scripts")
+			if err := p.createTranscriptionScript(); err != nil {
+				return fmt.Errorf("failed to create transcription script: %w", err)
 			}
+			if err := p.createBufferedScript(); err != nil {
+				return fmt.Errorf("failed to create buffered script: %w", err)
+			}
+			logger.Info("Parakeet environment already ready")
+			p.initialized = true
+			return nil
 		} else {
 			logger.Info("Parakeet model file missing or incomplete, redownloading")
 		}
@@ -214,7 +223,7 @@ triton = [
 
 [[tool.uv.index]]
 name = "pytorch"
-url = "https://download.pytorch.org/whl/cu126"
+url = "` + DetectGPUBackend().PyTorchIndexURL() + `"
 explicit = true
 
 [[tool.uv.index]]
@@ -230,11 +239,13 @@ explicit = true
 	// Run uv sync
 	logger.Info("Installing Parakeet dependencies")
 	cmd := exec.Command("uv", "sync", "--native-tls")
+	cmd.Env = uvEnv()
 	cmd.Dir = p.envPath
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("uv sync failed: %w: %s", err, strings.TrimSpace(string(out)))
 	}
+	markGPUBackend(p.envPath)
 
 	return nil
 }
@@ -257,7 +268,7 @@ func (p *ParakeetAdapter) downloadParakeetModel() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 
-	if err := downloader.DownloadFile(ctx, modelURL, modelPath); err != nil {
+	if err := fetchModelFile(ctx, modelURL, modelPath); err != nil {
 		return fmt.Errorf("failed to download Parakeet model: %w", err)
 	}
 
@@ -285,9 +296,21 @@ import json
 import sys
 import os
 from pathlib import Path
+import torch
 import nemo.collections.asr as nemo_asr
 
 
+def resolve_device(device):
+    """Resolve "auto" to the best available torch device (CUDA/ROCm, then MPS, then CPU)."""
+    if device and device != "auto":
+        return device
+    if torch.cuda.is_available():
+        return "cuda"
+    if torch.backends.mps.is_available():
+        return "mps"
+    return "cpu"
+` + nemoAdapterPython + `
+
 def transcribe_audio(
     audio_path: str,
     timestamps: bool = True,
@@ -295,6 +318,9 @@ def transcribe_audio(
     context_left: int = 256,
     context_right: int = 256,
     include_confidence: bool = True,
+    device: str = "auto",
+    adapter_path: str = None,
+    adapter_name: str = None,
 ):
     """
     Transcribe audio using NVIDIA Parakeet model.
@@ -317,7 +343,11 @@ def transcribe_audio(
         sys.exit(1)
     
     print(f"Loading NVIDIA Parakeet model from: {model_path}")
-    asr_model = nemo_asr.models.ASRModel.restore_from(model_path)
+    device = resolve_device(device)
+    print(f"Using device: {device}")
+    asr_model = nemo_asr.models.ASRModel.restore_from(model_path, map_location=torch.device(device))
+    asr_model = asr_model.to(device)
+    load_nemo_adapter(asr_model, adapter_path, adapter_name, device)
 
     # Disable CUDA graphs to fix Error 35 on RTX 2000e Ada GPU
     # Uses change_decoding_strategy() to properly reconfigure the TDT decoder
@@ -439,6 +469,16 @@ def main():
         "--no-confidence", dest="include_confidence", action="store_false",
         help="Exclude confidence scores"
     )
+    parser.add_argument(
+        "--device", choices=["auto", "cpu", "cuda", "mps"], default="auto",
+        help="Device to use for inference (default: auto-detect)"
+    )
+    parser.add_argument(
+        "--adapter", help="NeMo adapter/LoRA weights saved with save_adapters()"
+    )
+    parser.add_argument(
+        "--adapter-name", help="Adapter to enable (default: all in the checkpoint)"
+    )
     
     args = parser.parse_args()
     
@@ -455,6 +495,9 @@ def main():
             context_left=args.context_left,
             context_right=args.context_right,
             include_confidence=args.include_confidence,
+            device=args.device,
+            adapter_path=args.adapter,
+            adapter_name=args.adapter_name,
         )
     except Exception as e:
         print(f"Error during transcription: {e}")
@@ -488,7 +531,7 @@ func (p *ParakeetAdapter) Transcribe(ctx context.Context, input interfaces.Audio
 
 	// Validate parameters
 	if err := p.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
 	// Create temporary directory
@@ -593,7 +636,7 @@ func (p *ParakeetAdapter) transcribeStandard(ctx context.Context, input interfac
 
 	// Execute Parakeet
 	cmd := exec.CommandContext(ctx, "uv", args...)
-	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
 
 	// Setup log file
 	logFile, err := os.OpenFile(filepath.Join(outputDir, "transcription.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -642,7 +685,7 @@ func (p *ParakeetAdapter) transcribeBuffered(ctx context.Context, input interfac
 
 	// Execute buffered inference
 	cmd := exec.CommandContext(ctx, "uv", args...)
-	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
 
 	// Setup log file
 	logFile, err := os.OpenFile(filepath.Join(outputDir, "transcription.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -701,6 +744,9 @@ func (p *ParakeetAdapter) buildParakeetArgs(input interfaces.AudioInput, params
 	args = append(args, "--context-left", strconv.Itoa(p.GetIntParameter(params, "context_left")))
 	args = append(args, "--context-right", strconv.Itoa(p.GetIntParameter(params, "context_right")))
 
+	args = append(args, "--device", DetectGPUBackend().TorchDevice(p.GetStringParameter(params, "device")))
+	args = append(args, nemoAdapterArgs(p.BaseAdapter, params)...)
+
 	// Note: --include-confidence is not supported by Parakeet script, removed
 
 	return args, nil
@@ -786,9 +832,21 @@ import librosa
 import soundfile as sf
 import numpy as np
 from pathlib import Path
+import torch
 import nemo.collections.asr as nemo_asr
 
 
+def resolve_device(device):
+    """Resolve "auto" to the best available torch device (CUDA/ROCm, then MPS, then CPU)."""
+    if device and device != "auto":
+        return device
+    if torch.cuda.is_available():
+        return "cuda"
+    if torch.backends.mps.is_available():
+        return "mps"
+    return "cpu"
+` + nemoAdapterPython + `
+
 def split_audio_file(audio_path, chunk_duration_secs=300):
     """Split audio file into chunks of specified duration."""
     audio, sr = librosa.load(audio_path, sr=None, mono=True)
@@ -813,6 +871,9 @@ def transcribe_buffered(
     audio_path: str,
     output_file: str = None,
     chunk_duration_secs: float = 300,  # 5 minutes default
+    device: str = "auto",
+    adapter_path: str = None,
+    adapter_name: str = None,
 ):
     """
     Transcribe long audio by splitting into chunks and merging results.
@@ -825,7 +886,11 @@ def transcribe_buffered(
         print(f"Error: Model not found at {model_path}")
         sys.exit(1)
 
-    asr_model = nemo_asr.models.ASRModel.restore_from(model_path)
+    device = resolve_device(device)
+    print(f"Using device: {device}")
+    asr_model = nemo_asr.models.ASRModel.restore_from(model_path, map_location=torch.device(device))
+    asr_model = asr_model.to(device)
+    load_nemo_adapter(asr_model, adapter_path, adapter_name, device)
 
     # Disable CUDA graphs to fix Error 35 on RTX 2000e Ada GPU
     # Uses change_decoding_strategy() to properly reconfigure the TDT decoder
@@ -927,6 +992,16 @@ def main():
         "--chunk-len", type=float, default=300,
         help="Chunk duration in seconds (default: 300 = 5 minutes)"
     )
+    parser.add_argument(
+        "--device", choices=["auto", "cpu", "cuda", "mps"], default="auto",
+        help="Device to use for inference (default: auto-detect)"
+    )
+    parser.add_argument(
+        "--adapter", help="NeMo adapter/LoRA weights saved with save_adapters()"
+    )
+    parser.add_argument(
+        "--adapter-name", help="Adapter to enable (default: all in the checkpoint)"
+    )
 
     args = parser.parse_args()
 
@@ -938,6 +1013,9 @@ def main():
         audio_path=args.audio_file,
         output_file=args.output,
         chunk_duration_secs=args.chunk_len,
+        device=args.device,
+        adapter_path=args.adapter,
+        adapter_name=args.adapter_name,
     )
 
 
@@ -970,7 +1048,9 @@ func (p *ParakeetAdapter) buildBufferedArgs(input interfaces.AudioInput, params
 		input.FilePath,
 		"--output", outputFile,
 		"--chunk-len", chunkDuration,
+		"--device", DetectGPUBackend().TorchDevice(p.GetStringParameter(params, "device")),
 	}
+	args = append(args, nemoAdapterArgs(p.BaseAdapter, params)...)
 
 	return args, nil
 }
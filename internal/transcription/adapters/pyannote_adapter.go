@@ -41,11 +41,12 @@ func NewPyAnnoteAdapter(envPath string) *PyAnnoteAdapter {
 			"flexible_speakers":   true,
 		},
 		Metadata: map[string]string{
-			"engine":    "pyannote_audio",
-			"framework": "pytorch",
-			"license":   "MIT",
-			"requires":  "huggingface_token",
-			"model_hub": "huggingface",
+			"checkpoint": "pyannote/speaker-diarization-community-1",
+			"engine":     "pyannote_audio",
+			"framework":  "pytorch",
+			"license":    "MIT",
+			"requires":   "huggingface_token",
+			"model_hub":  "huggingface",
 		},
 	}
 
@@ -92,6 +93,14 @@ func NewPyAnnoteAdapter(envPath string) *PyAnnoteAdapter {
 		},
 
 		// Output settings
+		{
+			Name:        "speaker_embeddings",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Return each speaker's voice embedding (JSON output only), used to match enrolled voiceprints",
+			Group:       "advanced",
+		},
 		{
 			Name:        "output_format",
 			Type:        "string",
@@ -116,8 +125,8 @@ func NewPyAnnoteAdapter(envPath string) *PyAnnoteAdapter {
 			Type:        "string",
 			Required:    false,
 			Default:     "auto",
-			Options:     []string{"auto", "cpu", "cuda"},
-			Description: "Device to use for computation (auto, cpu, or cuda)",
+			Options:     []string{"auto", "cpu", "cuda", "rocm", "mps"},
+			Description: "Device to use for computation (auto, cpu, cuda for NVIDIA, rocm for AMD, mps for Apple silicon)",
 			Group:       "advanced",
 		},
 
@@ -177,11 +186,11 @@ func (p *PyAnnoteAdapter) PrepareEnvironment(ctx context.Context) error {
 	logger.Info("Preparing PyAnnote environment", "env_path", p.envPath)
 
 	// Check if PyAnnote is already available (using cache to speed up repeated checks)
-	if CheckEnvironmentReady(p.envPath, "from pyannote.audio import Pipeline") {
+	if CheckEnvironmentReady(p.envPath, "from pyannote.audio import Pipeline; import transformers; import silero_vad") {
 		logger.Info("PyAnnote already available in environment")
-		// Still ensure script exists
-		if err := p.createDiarizationScript(); err != nil {
-			return fmt.Errorf("failed to create diarization script: %w", err)
+		// Still ensure the scripts exist
+		if err := p.createScripts(); err != nil {
+			return err
 		}
 		p.initialized = true
 		return nil
@@ -192,13 +201,14 @@ func (p *PyAnnoteAdapter) PrepareEnvironment(ctx context.Context) error {
 		return fmt.Errorf("failed to setup PyAnnote environment: %w", err)
 	}
 
-	// Always ensure diarization script exists
-	if err := p.createDiarizationScript(); err != nil {
-		return fmt.Errorf("failed to create diarization script: %w", err)
+	// Always ensure the scripts exist
+	if err := p.createScripts(); err != nil {
+		return err
 	}
 
 	// Verify PyAnnote is now available
 	testCmd := exec.Command("uv", "run", "--native-tls", "--project", p.envPath, "python", "-c", "from pyannote.audio import Pipeline")
+	testCmd.Env = uvEnv()
 	if testCmd.Run() != nil {
 		logger.Warn("PyAnnote environment test still failed after setup")
 	}
@@ -226,7 +236,9 @@ dependencies = [
     "torch>=2.5.0",
     "torchaudio>=2.5.0",
     "huggingface-hub>=0.28.1",
-    "pyannote.audio==4.0.2"
+    "pyannote.audio==4.0.2",
+    "transformers>=4.44.0",
+    "silero-vad>=5.1"
 ]
 
 [tool.uv.sources]
@@ -243,7 +255,7 @@ torchaudio = [
 
 [[tool.uv.index]]
 name = "pytorch"
-url = "https://download.pytorch.org/whl/cu126"
+url = "` + DetectGPUBackend().PyTorchIndexURL() + `"
 explicit = true
 
 [[tool.uv.index]]
@@ -259,15 +271,29 @@ explicit = true
 	// Run uv sync
 	logger.Info("Installing PyAnnote dependencies")
 	cmd := exec.Command("uv", "sync", "--native-tls")
+	cmd.Env = uvEnv()
 	cmd.Dir = p.envPath
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("uv sync failed: %w: %s", err, strings.TrimSpace(string(out)))
 	}
+	markGPUBackend(p.envPath)
 
 	return nil
 }
 
+// createScripts writes the diarization, speaker attribute and voice activity
+// detection scripts
+func (p *PyAnnoteAdapter) createScripts() error {
+	if err := p.createDiarizationScript(); err != nil {
+		return fmt.Errorf("failed to create diarization script: %w", err)
+	}
+	if err := p.createSpeakerAttributesScript(); err != nil {
+		return err
+	}
+	return p.createVADScript()
+}
+
 // createDiarizationScript creates the Python script for PyAnnote diarization
 func (p *PyAnnoteAdapter) createDiarizationScript() error {
 	scriptPath := filepath.Join(p.envPath, "pyannote_diarize.py")
@@ -311,7 +337,7 @@ def diarize_audio(
     min_speakers: int = None,
     max_speakers: int = None,
     output_format: str = "rttm",
-
+    embeddings: bool = False,
     device: str = "auto"
 ):
     """
@@ -326,18 +352,24 @@ def diarize_audio(
             token=hf_token
         )
         
-        # Move to specified device
-        # if device == "auto" or device == "cuda":
+        # Move to specified device (ROCm builds of PyTorch also report as "cuda")
         try:
-            if torch.cuda.is_available():
-                pipeline = pipeline.to(torch.device("cuda"))
-                print("Using CUDA for diarization")
-            elif device == "cuda":
+            if device == "auto":
+                if torch.cuda.is_available():
+                    device = "cuda"
+                elif torch.backends.mps.is_available():
+                    device = "mps"
+                else:
+                    device = "cpu"
+            if device == "cuda" and not torch.cuda.is_available():
                 print("CUDA requested but not available, falling back to CPU")
-            else:
-                print("CUDA not available, using CPU")
-        except ImportError:
-            print("PyTorch not available for CUDA, using CPU")
+                device = "cpu"
+            elif device == "mps" and not torch.backends.mps.is_available():
+                print("MPS requested but not available, falling back to CPU")
+                device = "cpu"
+            if device != "cpu":
+                pipeline = pipeline.to(torch.device(device))
+            print(f"Using {device} for diarization")
         except Exception as e:
             print(f"Error moving to device: {e}, using CPU")
         
@@ -372,7 +404,7 @@ def diarize_audio(
                 diarization.write_rttm(rttm)
         else:
             # Save as JSON format
-            save_json_format(diarization, output_file, audio_path)
+            save_json_format(diarization, output_file, audio_path, embeddings)
         
         # Print summary
         speakers = set()
@@ -406,7 +438,24 @@ def diarize_audio(
         sys.exit(1)
 
 
-def save_json_format(diarization, output_file: str, audio_path: str):
+def speaker_embeddings_of(diarization):
+    """Map each speaker label to its centroid embedding (PyAnnote 4.x only)."""
+    centroids = getattr(diarization, "speaker_embeddings", None)
+    if centroids is None:
+        print("Warning: this PyAnnote version does not return speaker embeddings")
+        return {}
+    labels = diarization.speaker_diarization.labels()
+    embeddings = {}
+    for label, centroid in zip(labels, centroids):
+        vector = [float(x) for x in centroid]
+        # Speakers without enough clean speech get a NaN centroid
+        if any(x != x for x in vector):
+            continue
+        embeddings[label] = vector
+    return embeddings
+
+
+def save_json_format(diarization, output_file: str, audio_path: str, embeddings: bool = False):
     """Save diarization results in JSON format."""
     segments = []
     speakers = set()
@@ -449,6 +498,8 @@ def save_json_format(diarization, output_file: str, audio_path: str):
             "total_speech_time": sum(seg["duration"] for seg in segments)
         }
     }
+    if embeddings:
+        results["speaker_embeddings"] = speaker_embeddings_of(diarization)
     
     with open(output_file, "w") as f:
         json.dump(results, f, indent=2)
@@ -493,9 +544,14 @@ def main():
         default="rttm",
         help="Output format"
     )
+    parser.add_argument(
+        "--embeddings",
+        action="store_true",
+        help="Include each speaker's voice embedding in JSON output"
+    )
     parser.add_argument(
         "--device",
-        choices=["cpu", "cuda", "auto"],
+        choices=["cpu", "cuda", "mps", "auto"],
         default="auto",
         help="Device to use for computation"
     )
@@ -534,6 +590,7 @@ def main():
             min_speakers=args.min_speakers,
             max_speakers=args.max_speakers,
             output_format=args.output_format,
+            embeddings=args.embeddings,
             device=args.device
         )
     except Exception as e:
@@ -567,7 +624,7 @@ func (p *PyAnnoteAdapter) Diarize(ctx context.Context, input interfaces.AudioInp
 
 	// Validate parameters
 	if err := p.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
 	// Check for required HF token
@@ -591,7 +648,7 @@ func (p *PyAnnoteAdapter) Diarize(ctx context.Context, input interfaces.AudioInp
 
 	// Execute PyAnnote
 	cmd := exec.CommandContext(ctx, "uv", args...)
-	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
 
 	// Setup log file
 	logFile, err := os.OpenFile(filepath.Join(procCtx.OutputDirectory, "transcription.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -672,8 +729,12 @@ func (p *PyAnnoteAdapter) buildPyAnnoteArgs(input interfaces.AudioInput, params
 
 	// Add output format
 	args = append(args, "--output-format", outputFormat)
+	if outputFormat == "json" && p.GetBoolParameter(params, "speaker_embeddings") {
+		args = append(args, "--embeddings")
+	}
 
-	// Device is handled automatically by the script
+	// Add device
+	args = append(args, "--device", DetectGPUBackend().TorchDevice(p.GetStringParameter(params, "device")))
 
 	return args, nil
 }
@@ -708,9 +769,10 @@ func (p *PyAnnoteAdapter) parseJSONResult(tempDir string) (*interfaces.Diarizati
 			Confidence float64 `json:"confidence"`
 			Duration   float64 `json:"duration"`
 		} `json:"segments"`
-		Speakers      []string `json:"speakers"`
-		SpeakerCount  int      `json:"speaker_count"`
-		TotalDuration float64  `json:"total_duration"`
+		Speakers          []string             `json:"speakers"`
+		SpeakerCount      int                  `json:"speaker_count"`
+		TotalDuration     float64              `json:"total_duration"`
+		SpeakerEmbeddings map[string][]float64 `json:"speaker_embeddings,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &pyannoteResult); err != nil {
@@ -719,9 +781,10 @@ func (p *PyAnnoteAdapter) parseJSONResult(tempDir string) (*interfaces.Diarizati
 
 	// Convert to standard format
 	result := &interfaces.DiarizationResult{
-		Segments:     make([]interfaces.DiarizationSegment, len(pyannoteResult.Segments)),
-		SpeakerCount: pyannoteResult.SpeakerCount,
-		Speakers:     pyannoteResult.Speakers,
+		Segments:          make([]interfaces.DiarizationSegment, len(pyannoteResult.Segments)),
+		SpeakerCount:      pyannoteResult.SpeakerCount,
+		Speakers:          pyannoteResult.Speakers,
+		SpeakerEmbeddings: pyannoteResult.SpeakerEmbeddings,
 	}
 
 	for i, seg := range pyannoteResult.Segments {
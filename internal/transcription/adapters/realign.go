@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// realignScript force-aligns the text of each segment against the audio within
+// the segment's time window, one segment at a time so the results map back to
+// the segments they came from. WhisperX may split a segment into sentences;
+// their words are joined again. Segments that cannot be aligned, e.g. because
+// they hold no characters the alignment model knows, get null.
+const realignScript = `
+import json, sys
+args = json.loads(sys.argv[1])
+
+import whisperx
+
+audio = whisperx.load_audio(args["audio"])
+model, metadata = whisperx.load_align_model(
+    language_code=args["language"],
+    device=args["device"],
+    model_name=args["align_model"] or None,
+    model_dir=args["model_dir"] or None,
+)
+
+results = []
+for seg in args["segments"]:
+    try:
+        aligned = whisperx.align([seg], model, metadata, audio, args["device"],
+            interpolate_method=args["interpolate_method"], return_char_alignments=False)
+    except Exception as e:
+        print(f"segment at {seg['start']:.2f}s could not be aligned: {e}", file=sys.stderr)
+        results.append(None)
+        continue
+    words = [
+        {"word": w["word"], "start": w["start"], "end": w["end"], "score": w.get("score", 0.0)}
+        for s in aligned["segments"] for w in s.get("words", [])
+        if "start" in w and "end" in w
+    ]
+    if not words:
+        results.append(None)
+        continue
+    results.append({"start": words[0]["start"], "end": words[-1]["end"], "words": words})
+
+with open(args["output"], "w") as f:
+    json.dump(results, f)
+`
+
+// AlignedSegment is the timing forced alignment found for a segment's text
+type AlignedSegment struct {
+	Start float64                     `json:"start"`
+	End   float64                     `json:"end"`
+	Words []interfaces.TranscriptWord `json:"words"`
+}
+
+// Realign force-aligns the text of segments against the audio in language,
+// searching each segment's own time window, and returns the timing found for
+// each segment; nil for those that could not be aligned. It is used to repair
+// word timestamps after a transcript's text was edited.
+func (w *WhisperXAdapter) Realign(ctx context.Context, audioPath, language string, segments []interfaces.TranscriptSegment, params map[string]interface{}, tempDir string) ([]*AlignedSegment, error) {
+	if len(segments) == 0 {
+		return nil, nil
+	}
+	type span struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	}
+	spans := make([]span, len(segments))
+	for i, seg := range segments {
+		spans[i] = span{Start: seg.Start, End: seg.End, Text: seg.Text}
+	}
+	interpolate := w.GetStringParameter(params, "interpolate_method")
+	if interpolate == "" {
+		interpolate = "nearest"
+	}
+	outputPath := filepath.Join(tempDir, "realigned.json")
+	payload, err := json.Marshal(map[string]interface{}{
+		"audio":              audioPath,
+		"language":           language,
+		"device":             DetectGPUBackend().TorchDevice(w.GetStringParameter(params, "device")),
+		"align_model":        w.GetStringParameter(params, "align_model"),
+		"model_dir":          w.GetStringParameter(params, "model_dir"),
+		"interpolate_method": interpolate,
+		"segments":           spans,
+		"output":             outputPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	whisperxPath := filepath.Join(w.envPath, "WhisperX")
+	cmd := exec.CommandContext(ctx, "uv", "run", "--native-tls", "--project", whisperxPath, "python", "-c", realignScript, string(payload))
+	cmd.Env = w.whisperXEnv(params)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("forced alignment failed: %w: %s", err, lastLines(string(out), 5))
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alignment results: %w", err)
+	}
+	var aligned []*AlignedSegment
+	if err := json.Unmarshal(data, &aligned); err != nil {
+		return nil, fmt.Errorf("failed to parse alignment results: %w", err)
+	}
+	if len(aligned) != len(segments) {
+		return nil, fmt.Errorf("forced alignment returned %d results for %d segments", len(aligned), len(segments))
+	}
+	return aligned, nil
+}
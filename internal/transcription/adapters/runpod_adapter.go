@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
+	"path/filepath"
 	"scriberr/internal/transcription/interfaces"
 	"scriberr/pkg/logger"
 	"strings"
@@ -17,6 +19,36 @@ import (
 
 const DefaultRunpodBaseURL = "http://localhost:8000"
 
+// RunPod submission modes: /run queues the job and its status is polled until
+// it finishes, /runsync waits for it in one request, which RunPod gives up on
+// after a few minutes
+const (
+	RunpodModeRun     = "run"
+	RunpodModeRunSync = "runsync"
+)
+
+const (
+	DefaultRunpodPollInterval = 5 * time.Second
+	DefaultRunpodTimeout      = 3 * time.Hour
+)
+
+// DefaultRunpodBase64MaxBytes is the largest file sent inline. RunPod takes
+// request bodies of up to 10MB, which base64 fills with about 7MB of audio.
+const DefaultRunpodBase64MaxBytes = 7 << 20
+
+// runpodMaxPollFailures is how many status requests in a row may fail before
+// the job is given up on
+const runpodMaxPollFailures = 5
+
+// RunPod job statuses
+const (
+	runpodStatusInQueue   = "IN_QUEUE"
+	runpodStatusCompleted = "COMPLETED"
+	runpodStatusFailed    = "FAILED"
+	runpodStatusCancelled = "CANCELLED"
+	runpodStatusTimedOut  = "TIMED_OUT"
+)
+
 type WhisperxResult struct {
 	Segments []struct {
 		Start   float64                     `json:"start"`
@@ -45,6 +77,22 @@ type RunPodAdapter struct {
 	ModelFamily   string
 	RunPodAPIKey  string
 	RunPodBaseURL string
+	// Mode is RunpodModeRun or RunpodModeRunSync
+	Mode string
+	// PollInterval is how often the status of a job submitted to /run is
+	// checked
+	PollInterval time.Duration
+	// Timeout bounds a job, which is cancelled once it is reached; RunPod is
+	// asked to stop it at the same time. 0 waits for it.
+	Timeout time.Duration
+	// Audio larger than Base64MaxBytes is downloaded by the worker from a
+	// presigned URL: the job's own s3:// object when the audio is unchanged,
+	// otherwise a copy uploaded under AudioS3URI (s3://bucket/prefix) for the
+	// duration of the job
+	Base64MaxBytes int64
+	AudioS3URI     string
+	AudioStore     AudioStore
+	client         *http.Client
 }
 
 type RunpodOption func(*RunPodAdapter)
@@ -67,6 +115,38 @@ func WithRunpodApiKey(key string) RunpodOption {
 	}
 }
 
+// WithRunpodMode submits jobs to /run or /runsync
+func WithRunpodMode(mode string) RunpodOption {
+	return func(r *RunPodAdapter) {
+		r.Mode = mode
+	}
+}
+
+// WithRunpodPolling sets how often jobs' status is checked and how long they
+// may take
+func WithRunpodPolling(interval, timeout time.Duration) RunpodOption {
+	return func(r *RunPodAdapter) {
+		r.PollInterval = interval
+		r.Timeout = timeout
+	}
+}
+
+// WithRunpodAudioS3 uploads audio too large to send inline under uri, an
+// s3://bucket/prefix, through store
+func WithRunpodAudioS3(uri string, store AudioStore) RunpodOption {
+	return func(r *RunPodAdapter) {
+		r.AudioS3URI = uri
+		r.AudioStore = store
+	}
+}
+
+// WithRunpodBase64MaxBytes sets the largest file sent inline
+func WithRunpodBase64MaxBytes(n int64) RunpodOption {
+	return func(r *RunPodAdapter) {
+		r.Base64MaxBytes = n
+	}
+}
+
 func NewRunPodAdapter(w *WhisperXAdapter, opts ...RunpodOption) *RunPodAdapter {
 	baseAdapter := NewBaseAdapter(interfaces.RunPodWhisperX, w.modelPath, w.capabilities, ExtendsWhisperXSchema(w))
 	endpoint := DefaultRunpodBaseURL
@@ -75,15 +155,28 @@ func NewRunPodAdapter(w *WhisperXAdapter, opts ...RunpodOption) *RunPodAdapter {
 	}
 
 	adapter := &RunPodAdapter{
-		BaseAdapter:   baseAdapter,
-		ModelFamily:   interfaces.RunPodWhisperX,
-		RunPodBaseURL: endpoint,
-		RunPodAPIKey:  os.Getenv("RUNPOD_AI_API_KEY"),
+		BaseAdapter:    baseAdapter,
+		ModelFamily:    interfaces.RunPodWhisperX,
+		RunPodBaseURL:  endpoint,
+		RunPodAPIKey:   os.Getenv("RUNPOD_AI_API_KEY"),
+		Mode:           RunpodModeRun,
+		PollInterval:   time.Duration(envInt("RUNPOD_POLL_INTERVAL_SECONDS", int64(DefaultRunpodPollInterval/time.Second))) * time.Second,
+		Timeout:        time.Duration(envInt("RUNPOD_TIMEOUT_MINUTES", int64(DefaultRunpodTimeout/time.Minute))) * time.Minute,
+		Base64MaxBytes: envInt("RUNPOD_BASE64_MAX_MB", DefaultRunpodBase64MaxBytes>>20) << 20,
+		AudioS3URI:     strings.TrimSuffix(os.Getenv("RUNPOD_AUDIO_S3_URI"), "/"),
+		AudioStore:     NewS3AudioStore(),
+		client:         &http.Client{},
+	}
+	if strings.EqualFold(os.Getenv("RUNPOD_MODE"), RunpodModeRunSync) {
+		adapter.Mode = RunpodModeRunSync
 	}
 
 	for _, opt := range opts {
 		opt(adapter)
 	}
+	if adapter.PollInterval <= 0 {
+		adapter.PollInterval = DefaultRunpodPollInterval
+	}
 
 	return adapter
 }
@@ -118,25 +211,26 @@ func (m *RunPodAdapter) Transcribe(ctx context.Context, input interfaces.AudioIn
 	}()
 
 	if err := m.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
-	logger.Debug("Executing Runpod", "endpoint", m.RunPodBaseURL)
-	audioBytes, err := os.ReadFile(input.FilePath)
+	logger.Debug("Executing Runpod", "endpoint", m.RunPodBaseURL, "mode", m.Mode)
+	// Chunks of a job are transcribed at once with the same parameters
+	params = maps.Clone(params)
+	cleanup, err := m.attachAudio(ctx, input, params, procCtx)
 	if err != nil {
-		return nil, fmt.Errorf("read audio file: %w", err)
+		return nil, err
 	}
-	encodedAudio := base64.StdEncoding.EncodeToString(audioBytes)
-	params["audio_base64"] = encodedAudio
+	defer cleanup()
 	params["job_id"] = procCtx.JobID
 
-	ret, err := m.request(ctx, params)
+	output, err := m.run(ctx, params, procCtx)
 	if err != nil {
 		return nil, fmt.Errorf("Runpod request: %w", err)
 	}
 
 	// Parse result
-	result, err := m.parseResult(ret)
+	result, err := m.parseResult(output)
 	if err != nil {
 		return nil, fmt.Errorf("parse result: %w", err)
 	}
@@ -214,53 +308,235 @@ func ExtendsWhisperXSchema(w *WhisperXAdapter) []interfaces.ParameterSchema {
 	)
 }
 
-func (m *RunPodAdapter) request(ctx context.Context, params map[string]interface{}) ([]byte, error) {
-	jsonData, err := json.Marshal(&struct {
-		Input map[string]interface{} `json:"input"`
-	}{
-		Input: params,
-	})
+// attachAudio adds the audio to params: inline as base64 when it is small
+// enough, otherwise as a presigned URL and the headers to download it with.
+// The returned function removes any copy uploaded for the job.
+func (m *RunPodAdapter) attachAudio(ctx context.Context, input interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) (func(), error) {
+	noop := func() {}
+	info, err := os.Stat(input.FilePath)
 	if err != nil {
-		return nil, err
+		return noop, fmt.Errorf("read audio file: %w", err)
+	}
+
+	uri, uploaded := "", false
+	switch {
+	case info.Size() <= m.Base64MaxBytes || m.AudioStore == nil:
+	case procCtx.AudioURI != "" && input.TempFilePath == "":
+		// The audio was not converted, cut or chunked, so the job's object
+		// holds the same audio
+		uri = procCtx.AudioURI
+	case m.AudioS3URI != "":
+		uri = fmt.Sprintf("%s/%s/%s", m.AudioS3URI, procCtx.JobID, filepath.Base(input.FilePath))
+		if err := m.AudioStore.Upload(ctx, uri, input.FilePath); err != nil {
+			return noop, fmt.Errorf("upload audio: %w", err)
+		}
+		uploaded = true
+	default:
+		logger.Warn("Sending large audio inline, set RUNPOD_AUDIO_S3_URI to send it by URL", "job_id", procCtx.JobID, "size", info.Size())
+	}
+
+	if uri == "" {
+		audioBytes, err := os.ReadFile(input.FilePath)
+		if err != nil {
+			return noop, fmt.Errorf("read audio file: %w", err)
+		}
+		params["audio_base64"] = base64.StdEncoding.EncodeToString(audioBytes)
+		return noop, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/runsync", m.RunPodBaseURL), bytes.NewBuffer(jsonData))
+	cleanup := noop
+	if uploaded {
+		cleanup = func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := m.AudioStore.Delete(ctx, uri); err != nil {
+				logger.Warn("Failed to delete audio uploaded for Runpod", "uri", uri, "error", err)
+			}
+		}
+	}
+	// The URL must outlast the time the job may wait in RunPod's queue
+	url, headers, err := m.AudioStore.Presign(ctx, uri, m.Timeout)
 	if err != nil {
+		cleanup()
+		return noop, fmt.Errorf("presign audio: %w", err)
+	}
+	params["audio"] = url
+	if len(headers) > 0 {
+		params["download_headers"] = headers
+	}
+	logger.Debug("Sending audio to Runpod by URL", "job_id", procCtx.JobID, "uri", uri, "uploaded", uploaded)
+	return cleanup, nil
+}
+
+// runpodJob is RunPod's view of a job
+type runpodJob struct {
+	ID     string          `json:"id"`
+	Status string          `json:"status"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// run submits a job and waits for its output, reporting its status as
+// progress. Jobs still running when ctx is done or the timeout is reached are
+// cancelled on RunPod, so they stop billing.
+func (m *RunPodAdapter) run(ctx context.Context, params map[string]interface{}, procCtx interfaces.ProcessingContext) (json.RawMessage, error) {
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+
+	body := map[string]interface{}{"input": params}
+	if m.Timeout > 0 {
+		// RunPod stops jobs after the endpoint's execution timeout, which is
+		// usually shorter than long audio takes
+		body["policy"] = map[string]interface{}{"executionTimeout": m.Timeout.Milliseconds()}
+	}
+	mode := m.Mode
+	if mode != RunpodModeRunSync {
+		mode = RunpodModeRun
+	}
+	var job runpodJob
+	if err := m.call(ctx, http.MethodPost, "/"+mode, body, &job); err != nil {
 		return nil, err
 	}
+	logger.Info("Runpod job submitted", "runpod_job_id", job.ID, "status", job.Status)
+
+	// /runsync answers with the job's status when it is not done in time
+	for !runpodFinished(job.Status) {
+		if job.ID == "" {
+			return nil, fmt.Errorf("job status %q without a job ID", job.Status)
+		}
+		procCtx.ReportProgress(runpodProgress(job))
+		if err := m.waitForJob(ctx, &job); err != nil {
+			m.cancel(job.ID)
+			return nil, err
+		}
+	}
+
+	switch job.Status {
+	case runpodStatusCompleted:
+		return job.Output, nil
+	case runpodStatusFailed:
+		return nil, fmt.Errorf("job %s failed: %s", job.ID, job.Error)
+	default:
+		return nil, fmt.Errorf("job %s ended with status %s", job.ID, job.Status)
+	}
+}
+
+// runpodProgress is the progress of an unfinished job. Workers can report a
+// percentage with progress_update, as a number or a {"progress": n} object.
+func runpodProgress(job runpodJob) (string, float64) {
+	if job.Status == runpodStatusInQueue {
+		return interfaces.ProgressQueued, 0
+	}
+	var percent float64
+	if err := json.Unmarshal(job.Output, &percent); err != nil {
+		var update struct {
+			Progress *float64 `json:"progress"`
+		}
+		if err := json.Unmarshal(job.Output, &update); err != nil || update.Progress == nil {
+			return interfaces.ProgressRunning, -1
+		}
+		percent = *update.Progress
+	}
+	return interfaces.ProgressRunning, min(max(percent, 0), 100)
+}
+
+func runpodFinished(status string) bool {
+	switch status {
+	case runpodStatusCompleted, runpodStatusFailed, runpodStatusCancelled, runpodStatusTimedOut:
+		return true
+	}
+	return false
+}
+
+// waitForJob checks job's status after the poll interval, tolerating a few
+// failed checks in a row
+func (m *RunPodAdapter) waitForJob(ctx context.Context, job *runpodJob) error {
+	for failures := 0; ; {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("job %s: %w", job.ID, ctx.Err())
+		case <-time.After(m.PollInterval):
+		}
+
+		var status runpodJob
+		err := m.call(ctx, http.MethodGet, "/status/"+job.ID, nil, &status)
+		if err == nil {
+			status.ID = job.ID
+			*job = status
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("job %s: %w", job.ID, ctx.Err())
+		}
+		failures++
+		if failures >= runpodMaxPollFailures {
+			return fmt.Errorf("check status of job %s: %w", job.ID, err)
+		}
+		logger.Warn("Failed to check Runpod job status", "runpod_job_id", job.ID, "error", err)
+	}
+}
+
+// cancel asks RunPod to stop a job, even though the job's context is done
+func (m *RunPodAdapter) cancel(jobID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := m.call(ctx, http.MethodPost, "/cancel/"+jobID, nil, nil); err != nil {
+		logger.Warn("Failed to cancel Runpod job", "runpod_job_id", jobID, "error", err)
+		return
+	}
+	logger.Info("Runpod job cancelled", "runpod_job_id", jobID)
+}
+
+// call sends a request to the endpoint and decodes its JSON response into out
+func (m *RunPodAdapter) call(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(jsonData)
+	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(m.RunPodBaseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	if m.RunPodAPIKey != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", m.RunPodAPIKey))
 	}
 
-	client := &http.Client{}
+	client := m.client
+	if client == nil {
+		client = http.DefaultClient
+	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
-
-	return io.ReadAll(resp.Body)
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (m *RunPodAdapter) parseResult(data []byte) (*interfaces.TranscriptResult, error) {
-	var runpodResult struct {
-		ID     string         `json:"id"`
-		Status string         `json:"status"`
-		Output WhisperxResult `json:"output"`
-	}
-	err := json.Unmarshal(data, &runpodResult)
-	if err != nil {
+func (m *RunPodAdapter) parseResult(output json.RawMessage) (*interfaces.TranscriptResult, error) {
+	var whisperxResult WhisperxResult
+	if err := json.Unmarshal(output, &whisperxResult); err != nil {
 		return nil, err
 	}
 
-	whisperxResult := runpodResult.Output
-
 	// Convert to standard format
 	result := &interfaces.TranscriptResult{
 		Language:     whisperxResult.Language,
@@ -0,0 +1,262 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunpod serves /run, /runsync, /status and /cancel, finishing jobs after
+// a number of status checks
+type fakeRunpod struct {
+	mu         sync.Mutex
+	polls      int
+	pollsUntil int
+	finalState string
+	runsync    string
+	cancelled  []string
+	policy     map[string]interface{}
+	input      map[string]interface{}
+	auth       string
+}
+
+func (f *fakeRunpod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = r.Header.Get("Authorization")
+	output := map[string]interface{}{
+		"language": "en",
+		"segments": []map[string]interface{}{{"start": 0.0, "end": 1.5, "text": "hello there"}},
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/run":
+		var body struct {
+			Input  map[string]interface{} `json:"input"`
+			Policy map[string]interface{} `json:"policy"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.input, f.policy = body.Input, body.Policy
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": "IN_QUEUE"})
+	case r.Method == http.MethodPost && r.URL.Path == "/runsync":
+		if f.runsync == runpodStatusCompleted {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": f.runsync, "output": output})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": f.runsync})
+	case r.Method == http.MethodGet && r.URL.Path == "/status/job-1":
+		f.polls++
+		switch {
+		case f.polls < f.pollsUntil:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": "IN_PROGRESS"})
+		case f.finalState == runpodStatusFailed:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": f.finalState, "error": "CUDA out of memory"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": f.finalState, "output": output})
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/cancel/job-1":
+		f.cancelled = append(f.cancelled, "job-1")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "status": runpodStatusCancelled})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestRunpod(t *testing.T, fake *fakeRunpod, opts ...RunpodOption) (*RunPodAdapter, interfaces.AudioInput) {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	audio := filepath.Join(t.TempDir(), "audio.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("RIFF"), 0600))
+
+	opts = append([]RunpodOption{
+		WithRunpodBaseURL(server.URL),
+		WithRunpodApiKey("rp-key"),
+		WithRunpodPolling(time.Millisecond, time.Minute),
+	}, opts...)
+	return NewRunPodAdapter(NewWhisperXAdapter(t.TempDir()), opts...), interfaces.AudioInput{FilePath: audio}
+}
+
+func TestRunpodPollsSubmittedJobs(t *testing.T) {
+	fake := &fakeRunpod{pollsUntil: 3, finalState: runpodStatusCompleted}
+	adapter, input := newTestRunpod(t, fake)
+
+	var stages []string
+	procCtx := interfaces.ProcessingContext{JobID: "job", Progress: func(stage string, percent float64) {
+		stages = append(stages, stage)
+	}}
+	result, err := adapter.Transcribe(context.Background(), input, map[string]interface{}{}, procCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{interfaces.ProgressQueued, interfaces.ProgressRunning, interfaces.ProgressRunning}, stages)
+	assert.Equal(t, "hello there", result.Text)
+	assert.Equal(t, "en", result.Language)
+	assert.Equal(t, 3, fake.polls)
+	assert.Equal(t, "Bearer rp-key", fake.auth)
+	assert.Equal(t, float64(time.Minute.Milliseconds()), fake.policy["executionTimeout"])
+	assert.Empty(t, fake.cancelled)
+}
+
+func TestRunpodProgress(t *testing.T) {
+	for output, want := range map[string]float64{
+		``:                  -1,
+		`"loading model"`:   -1,
+		`42.5`:              42.5,
+		`{"progress": 130}`: 100,
+	} {
+		stage, percent := runpodProgress(runpodJob{Status: "IN_PROGRESS", Output: json.RawMessage(output)})
+		assert.Equal(t, interfaces.ProgressRunning, stage)
+		assert.Equal(t, want, percent, output)
+	}
+	stage, percent := runpodProgress(runpodJob{Status: runpodStatusInQueue})
+	assert.Equal(t, interfaces.ProgressQueued, stage)
+	assert.Zero(t, percent)
+}
+
+func TestRunpodReportsFailedJobs(t *testing.T) {
+	fake := &fakeRunpod{pollsUntil: 1, finalState: runpodStatusFailed}
+	adapter, input := newTestRunpod(t, fake)
+
+	_, err := adapter.Transcribe(context.Background(), input, map[string]interface{}{}, interfaces.ProcessingContext{JobID: "job"})
+	assert.ErrorContains(t, err, "CUDA out of memory")
+}
+
+func TestRunpodCancelsJobsThatTakeTooLong(t *testing.T) {
+	fake := &fakeRunpod{pollsUntil: 1 << 30}
+	adapter, input := newTestRunpod(t, fake, WithRunpodPolling(time.Millisecond, 50*time.Millisecond))
+
+	_, err := adapter.Transcribe(context.Background(), input, map[string]interface{}{}, interfaces.ProcessingContext{JobID: "job"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"job-1"}, fake.cancelled)
+}
+
+func TestRunpodCancelsJobsWhenCancelled(t *testing.T) {
+	fake := &fakeRunpod{pollsUntil: 1 << 30}
+	adapter, input := newTestRunpod(t, fake)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := adapter.Transcribe(ctx, input, map[string]interface{}{}, interfaces.ProcessingContext{JobID: "job"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"job-1"}, fake.cancelled)
+}
+
+func TestRunpodRunSync(t *testing.T) {
+	fake := &fakeRunpod{runsync: runpodStatusCompleted}
+	adapter, input := newTestRunpod(t, fake, WithRunpodMode(RunpodModeRunSync))
+	result, err := adapter.Transcribe(context.Background(), input, map[string]interface{}{}, interfaces.ProcessingContext{JobID: "job"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", result.Text)
+	assert.Zero(t, fake.polls)
+
+	// Jobs /runsync stops waiting for are polled
+	fake = &fakeRunpod{runsync: "IN_PROGRESS", pollsUntil: 2, finalState: runpodStatusCompleted}
+	adapter, input = newTestRunpod(t, fake, WithRunpodMode(RunpodModeRunSync))
+	result, err = adapter.Transcribe(context.Background(), input, map[string]interface{}{}, interfaces.ProcessingContext{JobID: "job"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", result.Text)
+	assert.Equal(t, 2, fake.polls)
+}
+
+func TestRunpodSettingsFromEnvironment(t *testing.T) {
+	t.Setenv("RUNPOD_MODE", "RUNSYNC")
+	t.Setenv("RUNPOD_POLL_INTERVAL_SECONDS", "2")
+	t.Setenv("RUNPOD_TIMEOUT_MINUTES", "0")
+	t.Setenv("RUNPOD_BASE64_MAX_MB", "3")
+	t.Setenv("RUNPOD_AUDIO_S3_URI", "s3://staging/runpod/")
+	adapter := NewRunPodAdapter(NewWhisperXAdapter(t.TempDir()))
+	assert.Equal(t, RunpodModeRunSync, adapter.Mode)
+	assert.Equal(t, 2*time.Second, adapter.PollInterval)
+	assert.Zero(t, adapter.Timeout)
+	assert.Equal(t, int64(3<<20), adapter.Base64MaxBytes)
+	assert.Equal(t, "s3://staging/runpod", adapter.AudioS3URI)
+
+	t.Setenv("RUNPOD_MODE", "")
+	t.Setenv("RUNPOD_POLL_INTERVAL_SECONDS", "")
+	t.Setenv("RUNPOD_TIMEOUT_MINUTES", "")
+	t.Setenv("RUNPOD_BASE64_MAX_MB", "")
+	adapter = NewRunPodAdapter(NewWhisperXAdapter(t.TempDir()))
+	assert.Equal(t, RunpodModeRun, adapter.Mode)
+	assert.Equal(t, int64(DefaultRunpodBase64MaxBytes), adapter.Base64MaxBytes)
+	assert.Equal(t, DefaultRunpodPollInterval, adapter.PollInterval)
+	assert.Equal(t, DefaultRunpodTimeout, adapter.Timeout)
+}
+
+// fakeAudioStore records what is uploaded, presigned and deleted
+type fakeAudioStore struct {
+	mu        sync.Mutex
+	uploaded  []string
+	presigned []string
+	deleted   []string
+}
+
+func (s *fakeAudioStore) Upload(_ context.Context, uri, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploaded = append(s.uploaded, uri)
+	return nil
+}
+
+func (s *fakeAudioStore) Presign(_ context.Context, uri string, _ time.Duration) (string, map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.presigned = append(s.presigned, uri)
+	return "https://bucket.s3.amazonaws.com/" + filepath.Base(uri) + "?X-Amz-Signature=abc",
+		map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"}, nil
+}
+
+func (s *fakeAudioStore) Delete(_ context.Context, uri string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, uri)
+	return nil
+}
+
+func TestRunpodSendsLargeAudioByURL(t *testing.T) {
+	transcribe := func(input interfaces.AudioInput, adapter *RunPodAdapter, procCtx interfaces.ProcessingContext) {
+		t.Helper()
+		params := map[string]interface{}{"model": "large-v3"}
+		_, err := adapter.Transcribe(context.Background(), input, params, procCtx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"model": "large-v3"}, params, "the caller's parameters are left unchanged")
+	}
+
+	// Small files are sent inline
+	fake := &fakeRunpod{pollsUntil: 1, finalState: runpodStatusCompleted}
+	store := &fakeAudioStore{}
+	adapter, input := newTestRunpod(t, fake, WithRunpodAudioS3("s3://staging/runpod", store))
+	transcribe(input, adapter, interfaces.ProcessingContext{JobID: "job"})
+	assert.Equal(t, "UklGRg==", fake.input["audio_base64"])
+	assert.Nil(t, fake.input["audio"])
+	assert.Empty(t, store.uploaded)
+
+	// The job's own object is used when the audio is unchanged
+	adapter.Base64MaxBytes = 1
+	transcribe(input, adapter, interfaces.ProcessingContext{JobID: "job", AudioURI: "s3://uploads/meeting.wav"})
+	assert.Nil(t, fake.input["audio_base64"])
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/meeting.wav?X-Amz-Signature=abc", fake.input["audio"])
+	assert.Equal(t, map[string]interface{}{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"}, fake.input["download_headers"])
+	assert.Empty(t, store.uploaded)
+	assert.Empty(t, store.deleted)
+
+	// Converted or chunked audio is uploaded for the job, then deleted
+	input.TempFilePath = input.FilePath
+	transcribe(input, adapter, interfaces.ProcessingContext{JobID: "job", AudioURI: "s3://uploads/meeting.wav"})
+	assert.Equal(t, []string{"s3://staging/runpod/job/audio.wav"}, store.uploaded)
+	assert.Equal(t, []string{"s3://uploads/meeting.wav", "s3://staging/runpod/job/audio.wav"}, store.presigned)
+	assert.Equal(t, []string{"s3://staging/runpod/job/audio.wav"}, store.deleted)
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/audio.wav?X-Amz-Signature=abc", fake.input["audio"])
+
+	// Without somewhere to upload to, the audio is sent inline
+	adapter.AudioS3URI = ""
+	transcribe(input, adapter, interfaces.ProcessingContext{JobID: "job"})
+	assert.Equal(t, "UklGRg==", fake.input["audio_base64"])
+}
@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// segmentLanguageMinProbability is the detection confidence below which a
+// segment is left without a language rather than guessed
+const segmentLanguageMinProbability = 0.5
+
+// segmentLanguageScript runs Whisper language identification on every segment.
+// Segments shorter than two seconds are padded with surrounding audio, since
+// detection on a word or two is unreliable.
+const segmentLanguageScript = `
+import json, sys
+args = json.loads(sys.argv[1])
+
+import whisperx
+from faster_whisper import WhisperModel
+
+SAMPLE_RATE = 16000
+MIN_SECONDS = 2.0
+
+audio = whisperx.load_audio(args["audio"])
+model = WhisperModel(
+    args["model"],
+    device=args["device"],
+    device_index=args["device_index"],
+    compute_type=args["compute_type"],
+    download_root=args["model_dir"] or None,
+)
+
+results = []
+for seg in args["segments"]:
+    start, end = seg["start"], seg["end"]
+    if end - start < MIN_SECONDS:
+        pad = (MIN_SECONDS - (end - start)) / 2
+        start, end = max(0.0, start - pad), end + pad
+    clip = audio[int(start * SAMPLE_RATE):int(end * SAMPLE_RATE)]
+    if len(clip) == 0:
+        results.append(None)
+        continue
+    language, probability, _ = model.detect_language(clip)
+    results.append({"language": language, "probability": float(probability)})
+
+with open(args["output"], "w") as f:
+    json.dump(results, f)
+`
+
+// detectSegmentLanguages identifies the spoken language of each segment in result
+// and records it on the segment. Segments detected with low confidence keep no
+// language.
+func (w *WhisperXAdapter) detectSegmentLanguages(ctx context.Context, audioPath string, result *interfaces.TranscriptResult, params map[string]interface{}, tempDir string) error {
+	if len(result.Segments) == 0 {
+		return nil
+	}
+	model := w.GetStringParameter(params, "model")
+	if customModel := w.GetStringParameter(params, "custom_model"); customModel != "" {
+		model = customModel
+	}
+	if strings.HasSuffix(model, ".en") {
+		return fmt.Errorf("model %s is English-only and cannot identify languages", model)
+	}
+
+	type span struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	}
+	segments := make([]span, len(result.Segments))
+	for i, seg := range result.Segments {
+		segments[i] = span{Start: seg.Start, End: seg.End}
+	}
+	device, computeType := w.ctranslate2Device(params)
+	outputPath := filepath.Join(tempDir, "segment_languages.json")
+	payload, err := json.Marshal(map[string]interface{}{
+		"audio":        audioPath,
+		"model":        model,
+		"model_dir":    w.GetStringParameter(params, "model_dir"),
+		"device":       device,
+		"device_index": w.GetIntParameter(params, "device_index"),
+		"compute_type": computeType,
+		"segments":     segments,
+		"output":       outputPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	whisperxPath := filepath.Join(w.envPath, "WhisperX")
+	cmd := exec.CommandContext(ctx, "uv", "run", "--native-tls", "--project", whisperxPath, "python", "-c", segmentLanguageScript, string(payload))
+	cmd.Env = w.whisperXEnv(params)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("language identification failed: %w: %s", err, lastLines(string(out), 5))
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read language identification results: %w", err)
+	}
+	var detections []*struct {
+		Language    string  `json:"language"`
+		Probability float64 `json:"probability"`
+	}
+	if err := json.Unmarshal(data, &detections); err != nil {
+		return fmt.Errorf("failed to parse language identification results: %w", err)
+	}
+	if len(detections) != len(result.Segments) {
+		return fmt.Errorf("language identification returned %d results for %d segments", len(detections), len(result.Segments))
+	}
+
+	counts := make(map[string]int)
+	for i, detection := range detections {
+		if detection == nil || detection.Language == "" || detection.Probability < segmentLanguageMinProbability {
+			continue
+		}
+		language := detection.Language
+		result.Segments[i].Language = &language
+		counts[language]++
+	}
+	logger.Info("Identified segment languages", "segments", len(result.Segments), "languages", counts)
+	return nil
+}
+
+// lastLines returns the last n lines of output
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"scriberr/internal/transcription/interfaces"
-	"scriberr/pkg/downloader"
 	"scriberr/pkg/logger"
 )
 
@@ -42,6 +41,7 @@ func NewSortformerAdapter(envPath string) *SortformerAdapter {
 			"no_token_required":    true,
 		},
 		Metadata: map[string]string{
+			"checkpoint":   "nvidia/diar_streaming_sortformer_4spk-v2",
 			"engine":       "nvidia_nemo",
 			"framework":    "nemo_toolkit",
 			"license":      "CC-BY-4.0",
@@ -92,8 +92,8 @@ func NewSortformerAdapter(envPath string) *SortformerAdapter {
 			Type:        "string",
 			Required:    false,
 			Default:     "auto",
-			Options:     []string{"cpu", "cuda", "auto"},
-			Description: "Device to use for computation (cpu, cuda for NVIDIA GPUs, auto for automatic detection)",
+			Options:     []string{"cpu", "cuda", "rocm", "mps", "auto"},
+			Description: "Device to use for computation (cpu, cuda for NVIDIA GPUs, rocm for AMD GPUs, mps for Apple silicon, auto for automatic detection)",
 			Group:       "advanced",
 		},
 
@@ -156,19 +156,20 @@ func (s *SortformerAdapter) PrepareEnvironment(ctx context.Context) error {
 	if CheckEnvironmentReady(s.envPath, "from nemo.collections.asr.models import SortformerEncLabelModel") {
 		modelPath := filepath.Join(s.envPath, "diar_streaming_sortformer_4spk-v2.nemo")
 		if stat, err := os.Stat(modelPath); err == nil && stat.Size() > 1024*1024 {
-			scriptPath := filepath.Join(s.envPath, "sortformer_diarize.py")
-			if _, err := os.Stat(scriptPath); err == nil {
-				logger.Info("Sortformer environment already ready")
-				s.initialized = true
-				return nil
+			// Rewrite the script so existing installs pick up new script options
+			if err := s.createDiarizationScript(); err != nil {
+				return fmt.Errorf("failed to create diarization script: %w", err)
 			}
+			logger.Info("Sortformer environment already ready")
+			s.initialized = true
+			return nil
 		}
 	}
 
 	// Check if the shared environment exists (created by other NVIDIA adapters)
 	pyprojectPath := filepath.Join(s.envPath, "pyproject.toml")
-	if _, err := os.Stat(pyprojectPath); err != nil {
-		// Create environment if it doesn't exist
+	if _, err := os.Stat(pyprojectPath); err != nil || !gpuBackendMatches(s.envPath) {
+		// Create environment if it doesn't exist or was built for another GPU backend
 		if err := s.setupSortformerEnvironment(); err != nil {
 			return fmt.Errorf("failed to setup Sortformer environment: %w", err)
 		}
@@ -214,6 +215,26 @@ dependencies = [
 
 [tool.uv.sources]
 nemo-toolkit = { git = "https://github.com/NVIDIA/NeMo.git", tag = "v2.5.3" }
+torch = [
+    { index = "pytorch-cpu", marker = "sys_platform == 'darwin'" },
+    { index = "pytorch-cpu", marker = "platform_machine != 'x86_64' and sys_platform != 'darwin'" },
+    { index = "pytorch", marker = "platform_machine == 'x86_64' and sys_platform == 'linux'" },
+]
+torchaudio = [
+    { index = "pytorch-cpu", marker = "sys_platform == 'darwin'" },
+    { index = "pytorch-cpu", marker = "platform_machine != 'x86_64' and sys_platform != 'darwin'" },
+    { index = "pytorch", marker = "platform_machine == 'x86_64' and sys_platform == 'linux'" },
+]
+
+[[tool.uv.index]]
+name = "pytorch"
+url = "` + DetectGPUBackend().PyTorchIndexURL() + `"
+explicit = true
+
+[[tool.uv.index]]
+name = "pytorch-cpu"
+url = "https://download.pytorch.org/whl/cpu"
+explicit = true
 `
 	pyprojectPath := filepath.Join(s.envPath, "pyproject.toml")
 	if err := os.WriteFile(pyprojectPath, []byte(pyprojectContent), 0644); err != nil {
@@ -223,11 +244,13 @@ nemo-toolkit = { git = "https://github.com/NVIDIA/NeMo.git", tag = "v2.5.3" }
 	// Run uv sync
 	logger.Info("Installing Sortformer dependencies")
 	cmd := exec.Command("uv", "sync", "--native-tls")
+	cmd.Env = uvEnv()
 	cmd.Dir = s.envPath
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("uv sync failed: %w: %s", err, strings.TrimSpace(string(out)))
 	}
+	markGPUBackend(s.envPath)
 
 	return nil
 }
@@ -250,7 +273,7 @@ func (s *SortformerAdapter) downloadSortformerModel() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 
-	if err := downloader.DownloadFile(ctx, modelURL, modelPath); err != nil {
+	if err := fetchModelFile(ctx, modelURL, modelPath); err != nil {
 		return fmt.Errorf("failed to download Sortformer model: %w", err)
 	}
 
@@ -311,7 +334,8 @@ def diarize_audio(
     if device is None or device == "auto":
         if torch.cuda.is_available():
             device = "cuda"
-
+        elif torch.backends.mps.is_available():
+            device = "mps"
         else:
             device = "cpu"
 
@@ -559,7 +583,7 @@ Note: This script requires diar_streaming_sortformer_4spk-v2.nemo to be in the s
     parser.add_argument("audio_file", help="Path to input audio file (WAV, FLAC, etc.)")
     parser.add_argument("output_file", help="Path to output file (.json for JSON format, .rttm for RTTM format)")
     parser.add_argument("--batch-size", type=int, default=1, help="Batch size for processing (default: 1)")
-    parser.add_argument("--device", choices=["cuda", "cpu", "auto"], default="auto", help="Device to use for inference (default: auto-detect)")
+    parser.add_argument("--device", choices=["cuda", "mps", "cpu", "auto"], default="auto", help="Device to use for inference (default: auto-detect)")
     parser.add_argument("--max-speakers", type=int, default=4, help="Maximum number of speakers (default: 4, optimized for this model)")
     parser.add_argument("--output-format", choices=["json", "rttm"], help="Output format (auto-detected from file extension if not specified)")
     parser.add_argument("--streaming", action="store_true", help="Enable streaming mode")
@@ -626,7 +650,7 @@ func (s *SortformerAdapter) Diarize(ctx context.Context, input interfaces.AudioI
 
 	// Validate parameters
 	if err := s.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
 	// Create temporary directory
@@ -655,7 +679,7 @@ func (s *SortformerAdapter) Diarize(ctx context.Context, input interfaces.AudioI
 
 	// Execute Sortformer
 	cmd := exec.CommandContext(ctx, "uv", args...)
-	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
 
 	// Setup log file
 	logFile, err := os.OpenFile(filepath.Join(procCtx.OutputDirectory, "transcription.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -726,9 +750,7 @@ func (s *SortformerAdapter) buildSortformerArgs(input interfaces.AudioInput, par
 	}
 
 	// Add device
-	if device := s.GetStringParameter(params, "device"); device != "" {
-		args = append(args, "--device", device)
-	}
+	args = append(args, "--device", DetectGPUBackend().TorchDevice(s.GetStringParameter(params, "device")))
 
 	// Add max speakers
 	if maxSpeakers := s.GetIntParameter(params, "max_speakers"); maxSpeakers > 0 {
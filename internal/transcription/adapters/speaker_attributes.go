@@ -0,0 +1,204 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// speakerAttributeModel estimates age and gender from a voice; it runs in the
+// PyAnnote environment, which already has PyTorch
+const speakerAttributeModel = "audeering/wav2vec2-large-robust-6-ft-age-gender"
+
+// speakerAttributeSeconds caps how much of each speaker's speech is analysed
+const speakerAttributeSeconds = 30
+
+// EstimateSpeakerAttributes estimates the age and gender of each speaker's
+// voice from up to 30 seconds of their longest turns
+func (p *PyAnnoteAdapter) EstimateSpeakerAttributes(ctx context.Context, input interfaces.AudioInput, turns []interfaces.SpeakerTurn, procCtx interfaces.ProcessingContext) (map[string]interfaces.SpeakerVoiceEstimate, string, error) {
+	if !p.initialized {
+		return nil, "", fmt.Errorf("PyAnnote environment is not ready")
+	}
+	tempDir, err := p.CreateTempDirectory(procCtx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer p.CleanupTempDirectory(tempDir)
+
+	turnsPath := filepath.Join(tempDir, "turns.json")
+	data, err := json.Marshal(turns)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := os.WriteFile(turnsPath, data, 0644); err != nil {
+		return nil, "", fmt.Errorf("failed to write speaker turns: %w", err)
+	}
+	outputPath := filepath.Join(tempDir, "attributes.json")
+	args := []string{
+		"run", "--native-tls", "--project", p.envPath, "python", filepath.Join(p.envPath, "speaker_attributes.py"),
+		input.FilePath,
+		"--turns", turnsPath,
+		"--output", outputPath,
+		"--model", speakerAttributeModel,
+		"--max-seconds", fmt.Sprint(speakerAttributeSeconds),
+		"--device", DetectGPUBackend().TorchDevice("auto"),
+	}
+	cmd := exec.CommandContext(ctx, "uv", args...)
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logger.Error("Speaker attribute estimation failed", "output", string(output), "error", err)
+		return nil, "", fmt.Errorf("speaker attribute estimation failed: %w", err)
+	}
+
+	data, err = os.ReadFile(outputPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read speaker attributes: %w", err)
+	}
+	var result struct {
+		Speakers map[string]interfaces.SpeakerVoiceEstimate `json:"speakers"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, "", fmt.Errorf("failed to parse speaker attributes: %w", err)
+	}
+	return result.Speakers, speakerAttributeModel, nil
+}
+
+// createSpeakerAttributesScript writes the Python script estimating speaker attributes
+func (p *PyAnnoteAdapter) createSpeakerAttributesScript() error {
+	scriptContent := `#!/usr/bin/env python3
+"""
+Estimate the age and gender of each diarized speaker's voice.
+Reads the speakers' turns and writes, per speaker, the estimated age in years
+and the probabilities of a female, male or child voice.
+"""
+
+import argparse
+import json
+import sys
+
+import torch
+import torch.nn as nn
+import torchaudio
+from transformers import Wav2Vec2Processor
+from transformers.models.wav2vec2.modeling_wav2vec2 import Wav2Vec2Model, Wav2Vec2PreTrainedModel
+
+SAMPLE_RATE = 16000
+MIN_SECONDS = 1.0
+
+
+class ModelHead(nn.Module):
+    def __init__(self, config, num_labels):
+        super().__init__()
+        self.dense = nn.Linear(config.hidden_size, config.hidden_size)
+        self.dropout = nn.Dropout(config.final_dropout)
+        self.out_proj = nn.Linear(config.hidden_size, num_labels)
+
+    def forward(self, features):
+        x = self.dropout(features)
+        x = torch.tanh(self.dense(x))
+        x = self.dropout(x)
+        return self.out_proj(x)
+
+
+class AgeGenderModel(Wav2Vec2PreTrainedModel):
+    def __init__(self, config):
+        super().__init__(config)
+        self.config = config
+        self.wav2vec2 = Wav2Vec2Model(config)
+        self.age = ModelHead(config, 1)
+        self.gender = ModelHead(config, 3)
+        self.init_weights()
+
+    def forward(self, input_values):
+        hidden_states = torch.mean(self.wav2vec2(input_values)[0], dim=1)
+        return self.age(hidden_states), torch.softmax(self.gender(hidden_states), dim=1)
+
+
+def speaker_audio(waveform, turns, max_seconds):
+    """Concatenate each speaker's longest turns up to max_seconds."""
+    by_speaker = {}
+    for turn in turns:
+        by_speaker.setdefault(turn["speaker"], []).append(turn)
+    audio = {}
+    for speaker, speaker_turns in by_speaker.items():
+        speaker_turns.sort(key=lambda t: t["end"] - t["start"], reverse=True)
+        chunks, total = [], 0.0
+        for turn in speaker_turns:
+            start = int(turn["start"] * SAMPLE_RATE)
+            end = int(min(turn["end"], turn["start"] + max_seconds - total) * SAMPLE_RATE)
+            if end <= start:
+                continue
+            chunks.append(waveform[start:end])
+            total += (end - start) / SAMPLE_RATE
+            if total >= max_seconds:
+                break
+        if total >= MIN_SECONDS:
+            audio[speaker] = (torch.cat(chunks), total)
+    return audio
+
+
+def main():
+    parser = argparse.ArgumentParser(description="Estimate speaker age and gender")
+    parser.add_argument("audio_file")
+    parser.add_argument("--turns", required=True)
+    parser.add_argument("--output", required=True)
+    parser.add_argument("--model", required=True)
+    parser.add_argument("--max-seconds", type=float, default=30)
+    parser.add_argument("--device", default="auto")
+    args = parser.parse_args()
+
+    device = args.device
+    if device == "auto":
+        device = "cuda" if torch.cuda.is_available() else "cpu"
+
+    with open(args.turns) as f:
+        turns = json.load(f)
+
+    waveform, sample_rate = torchaudio.load(args.audio_file)
+    waveform = waveform.mean(dim=0)
+    if sample_rate != SAMPLE_RATE:
+        waveform = torchaudio.functional.resample(waveform, sample_rate, SAMPLE_RATE)
+
+    processor = Wav2Vec2Processor.from_pretrained(args.model)
+    model = AgeGenderModel.from_pretrained(args.model).to(device)
+    model.eval()
+
+    speakers = {}
+    for speaker, (audio, seconds) in speaker_audio(waveform, turns, args.max_seconds).items():
+        inputs = processor(audio.numpy(), sampling_rate=SAMPLE_RATE)["input_values"][0]
+        inputs = torch.tensor(inputs, device=device).unsqueeze(0)
+        with torch.no_grad():
+            age, gender = model(inputs)
+        female, male, child = (float(x) for x in gender[0])
+        speakers[speaker] = {
+            "age": float(age[0][0]) * 100,
+            "female": female,
+            "male": male,
+            "child": child,
+            "speech_seconds": seconds,
+        }
+        print(f"Estimated speaker {speaker} from {seconds:.1f}s of speech")
+
+    with open(args.output, "w") as f:
+        json.dump({"model": args.model, "speakers": speakers}, f)
+
+
+if __name__ == "__main__":
+    try:
+        main()
+    except Exception as e:
+        print(f"Error estimating speaker attributes: {e}")
+        sys.exit(1)
+`
+	scriptPath := filepath.Join(p.envPath, "speaker_attributes.py")
+	if err := os.WriteFile(scriptPath, []byte(scriptContent), 0755); err != nil {
+		return fmt.Errorf("failed to write speaker attributes script: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// vadMinSilenceMs is the shortest pause Silero VAD ends a speech region at
+const vadMinSilenceMs = 500
+
+// DetectSpeech finds the speech in the audio with Silero VAD, which runs in the
+// PyAnnote environment and, unlike silence detection, skips music as well
+func (p *PyAnnoteAdapter) DetectSpeech(ctx context.Context, input interfaces.AudioInput, procCtx interfaces.ProcessingContext) ([]interfaces.SpeechRegion, error) {
+	if !p.initialized {
+		return nil, fmt.Errorf("PyAnnote environment is not ready")
+	}
+	tempDir, err := p.CreateTempDirectory(procCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer p.CleanupTempDirectory(tempDir)
+
+	outputPath := filepath.Join(tempDir, "speech.json")
+	args := []string{
+		"run", "--native-tls", "--project", p.envPath, "python", filepath.Join(p.envPath, "vad.py"),
+		input.FilePath,
+		"--output", outputPath,
+		"--min-silence-ms", fmt.Sprint(vadMinSilenceMs),
+	}
+	cmd := exec.CommandContext(ctx, "uv", args...)
+	cmd.Env = uvEnv("PYTHONUNBUFFERED=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logger.Error("Voice activity detection failed", "output", string(output), "error", err)
+		return nil, fmt.Errorf("voice activity detection failed: %w", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read speech regions: %w", err)
+	}
+	var result struct {
+		Speech []interfaces.SpeechRegion `json:"speech"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse speech regions: %w", err)
+	}
+	return result.Speech, nil
+}
+
+// createVADScript writes the Python script finding speech with Silero VAD
+func (p *PyAnnoteAdapter) createVADScript() error {
+	scriptContent := `#!/usr/bin/env python3
+"""
+Find the speech in an audio file with Silero VAD.
+Writes the start and end, in seconds, of each stretch of speech.
+"""
+
+import argparse
+import json
+import sys
+
+import torch
+import torchaudio
+from silero_vad import get_speech_timestamps, load_silero_vad
+
+SAMPLE_RATE = 16000
+
+
+def main():
+    parser = argparse.ArgumentParser(description="Detect speech with Silero VAD")
+    parser.add_argument("audio_file")
+    parser.add_argument("--output", required=True)
+    parser.add_argument("--min-silence-ms", type=int, default=500)
+    args = parser.parse_args()
+
+    waveform, sample_rate = torchaudio.load(args.audio_file)
+    waveform = waveform.mean(dim=0)
+    if sample_rate != SAMPLE_RATE:
+        waveform = torchaudio.functional.resample(waveform, sample_rate, SAMPLE_RATE)
+
+    torch.set_num_threads(1)
+    model = load_silero_vad()
+    timestamps = get_speech_timestamps(
+        waveform,
+        model,
+        sampling_rate=SAMPLE_RATE,
+        min_silence_duration_ms=args.min_silence_ms,
+        return_seconds=True,
+    )
+    speech = [{"start": float(t["start"]), "end": float(t["end"])} for t in timestamps]
+    total = sum(s["end"] - s["start"] for s in speech)
+    print(f"Found {len(speech)} speech regions, {total:.1f}s of {waveform.shape[0] / SAMPLE_RATE:.1f}s")
+
+    with open(args.output, "w") as f:
+        json.dump({"speech": speech}, f)
+
+
+if __name__ == "__main__":
+    try:
+        main()
+    except Exception as e:
+        print(f"Error detecting speech: {e}")
+        sys.exit(1)
+`
+	scriptPath := filepath.Join(p.envPath, "vad.py")
+	if err := os.WriteFile(scriptPath, []byte(scriptContent), 0755); err != nil {
+		return fmt.Errorf("failed to write VAD script: %w", err)
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -69,6 +71,22 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Description: "Whisper model size to use",
 			Group:       "basic",
 		},
+		{
+			Name:        "custom_model",
+			Type:        "string",
+			Required:    false,
+			Default:     nil,
+			Description: "Fine-tuned CTranslate2 checkpoint used instead of model: an absolute directory path or a Hugging Face repo ID (private repos use hf_token)",
+			Group:       "advanced",
+		},
+		{
+			Name:        "model_dir",
+			Type:        "string",
+			Required:    false,
+			Default:     nil,
+			Description: "Directory to download and cache models in",
+			Group:       "advanced",
+		},
 
 		// Device and computation
 		{
@@ -76,8 +94,8 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Type:        "string",
 			Required:    false,
 			Default:     "cpu",
-			Options:     []string{"cpu", "cuda"},
-			Description: "Device to use for computation",
+			Options:     []string{"auto", "cpu", "cuda", "rocm", "mps"},
+			Description: "Device to use for computation (WhisperX runs on CPU on ROCm and Apple silicon hosts)",
 			Group:       "basic",
 		},
 		{
@@ -138,6 +156,14 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Description: "Task to perform",
 			Group:       "basic",
 		},
+		{
+			Name:        "segment_language_id",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Identify the language of each segment, for audio that switches languages",
+			Group:       "advanced",
+		},
 
 		// Diarization
 		{
@@ -185,6 +211,14 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Description: "HuggingFace token for diarization models",
 			Group:       "advanced",
 		},
+		{
+			Name:        "speaker_embeddings",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Return each diarized speaker's voice embedding, used to match enrolled voiceprints",
+			Group:       "advanced",
+		},
 
 		// Quality settings
 		{
@@ -268,6 +302,32 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Description: "Custom alignment model (e.g. KBLab/wav2vec2-large-voxrex-swedish)",
 			Group:       "advanced",
 		},
+		{
+			Name:        "no_align",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Skip word-level alignment and keep segment-level timestamps",
+			Group:       "advanced",
+		},
+
+		// Vocabulary
+		{
+			Name:        "initial_prompt",
+			Type:        "string",
+			Required:    false,
+			Default:     nil,
+			Description: "Text the first window is conditioned on, e.g. names and jargon to spell correctly",
+			Group:       "advanced",
+		},
+		{
+			Name:        "hotwords",
+			Type:        "string",
+			Required:    false,
+			Default:     nil,
+			Description: "Phrases decoding is biased towards",
+			Group:       "advanced",
+		},
 	}
 
 	baseAdapter := NewBaseAdapter("whisperx", filepath.Join(envPath, "WhisperX"), capabilities, schema)
@@ -309,9 +369,11 @@ func (w *WhisperXAdapter) PrepareEnvironment(ctx context.Context) error {
 		return fmt.Errorf("failed to create environment directory: %w", err)
 	}
 
-	// Clone WhisperX
-	if err := w.cloneWhisperX(); err != nil {
-		return fmt.Errorf("failed to clone WhisperX: %w", err)
+	// Clone WhisperX (an existing checkout is re-synced, e.g. after a GPU backend change)
+	if _, err := os.Stat(whisperxPath); os.IsNotExist(err) {
+		if err := w.cloneWhisperX(); err != nil {
+			return fmt.Errorf("failed to clone WhisperX: %w", err)
+		}
 	}
 
 	// Update dependencies
@@ -323,6 +385,7 @@ func (w *WhisperXAdapter) PrepareEnvironment(ctx context.Context) error {
 	if err := w.uvSyncWhisperX(whisperxPath); err != nil {
 		return fmt.Errorf("failed to sync WhisperX: %w", err)
 	}
+	markGPUBackend(whisperxPath)
 
 	w.initialized = true
 	logger.Info("WhisperX environment prepared successfully")
@@ -331,15 +394,13 @@ func (w *WhisperXAdapter) PrepareEnvironment(ctx context.Context) error {
 
 // cloneWhisperX clones the WhisperX repository
 func (w *WhisperXAdapter) cloneWhisperX() error {
-	cmd := exec.Command("git", "clone", "https://github.com/m-bain/WhisperX.git")
-	cmd.Dir = w.envPath
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("git clone failed: %w: %s", err, strings.TrimSpace(string(out)))
-	}
-	return nil
+	return fetchSourceTree("https://github.com/m-bain/WhisperX.git", filepath.Join(w.envPath, "WhisperX"))
 }
 
+// pytorchIndexPattern matches CUDA and ROCm PyTorch wheel indexes, including ones
+// written by an earlier sync for a different GPU backend
+var pytorchIndexPattern = regexp.MustCompile(`https://download\.pytorch\.org/whl/(cu\d+|rocm[\d.]+)`)
+
 // updateWhisperXDependencies modifies WhisperX pyproject.toml
 func (w *WhisperXAdapter) updateWhisperXDependencies(whisperxPath string) error {
 	pyprojectPath := filepath.Join(whisperxPath, "pyproject.toml")
@@ -359,9 +420,9 @@ func (w *WhisperXAdapter) updateWhisperXDependencies(whisperxPath string) error
     "yt-dlp[default]",`)
 	}
 
-	// Pin PyTorch to CUDA 12.6
-	// The repo already has the correct [tool.uv.sources] configuration, we just need to update the CUDA version
-	content = strings.ReplaceAll(content, "https://download.pytorch.org/whl/cu128", "https://download.pytorch.org/whl/cu126")
+	// Point the PyTorch index at wheels for the detected GPU backend (CUDA 12.6 or ROCm)
+	// The repo already has the correct [tool.uv.sources] configuration, we just need to update the index URL
+	content = pytorchIndexPattern.ReplaceAllString(content, DetectGPUBackend().PyTorchIndexURL())
 
 	if err := os.WriteFile(pyprojectPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write pyproject.toml: %w", err)
@@ -373,6 +434,7 @@ func (w *WhisperXAdapter) updateWhisperXDependencies(whisperxPath string) error
 // uvSyncWhisperX runs uv sync for WhisperX
 func (w *WhisperXAdapter) uvSyncWhisperX(whisperxPath string) error {
 	cmd := exec.Command("uv", "sync", "--all-extras", "--dev", "--native-tls")
+	cmd.Env = uvEnv()
 	cmd.Dir = whisperxPath
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -396,7 +458,7 @@ func (w *WhisperXAdapter) Transcribe(ctx context.Context, input interfaces.Audio
 
 	// Validate parameters
 	if err := w.ValidateParameters(params); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrInvalidParameters, err)
 	}
 
 	// Create temporary directory
@@ -412,11 +474,161 @@ func (w *WhisperXAdapter) Transcribe(ctx context.Context, input interfaces.Audio
 		return nil, fmt.Errorf("failed to build command: %w", err)
 	}
 
-	// Execute WhisperX
+	// Execute WhisperX. When the alignment model for the language cannot be loaded,
+	// retry without alignment and keep the segment-level timestamps.
+	var alignmentFallback string
+	logTail, err := w.runWhisperX(ctx, args, params, procCtx)
+	if err != nil && !w.GetBoolParameter(params, "no_align") {
+		if reason, ok := alignmentFailure(logTail); ok {
+			logger.Warn("WhisperX alignment failed, retrying with segment-level timestamps", "job_id", procCtx.JobID, "reason", reason)
+			alignmentFallback = reason
+			args = append(args, "--no_align")
+			logTail, err = w.runWhisperX(ctx, args, params, procCtx)
+		}
+	}
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return nil, fmt.Errorf("transcription was cancelled")
+		}
+		logger.Error("WhisperX execution failed", "error", err)
+		return nil, fmt.Errorf("WhisperX execution failed: %w\nLogs:\n%s", err, logTail)
+	}
+
+	// Parse result
+	result, err := w.parseResult(tempDir, input, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	// Language identification problems leave the segments untagged rather than
+	// failing the transcription
+	if w.GetBoolParameter(params, "segment_language_id") {
+		if err := w.detectSegmentLanguages(ctx, input.FilePath, result, params, tempDir); err != nil {
+			if ctx.Err() == context.Canceled {
+				return nil, fmt.Errorf("transcription was cancelled")
+			}
+			logger.Warn("Per-segment language identification failed", "job_id", procCtx.JobID, "error", err)
+		}
+	}
+
+	result.ProcessingTime = time.Since(startTime)
+	result.ModelUsed = w.GetStringParameter(params, "model")
+	if customModel := w.GetStringParameter(params, "custom_model"); customModel != "" {
+		result.ModelUsed = customModel
+	}
+	result.Metadata = w.CreateDefaultMetadata(params)
+	if alignmentFallback != "" {
+		result.Metadata[interfaces.MetadataAlignmentFallback] = alignmentFallback
+	}
+
+	logger.Info("WhisperX transcription completed",
+		"segments", len(result.Segments),
+		"words", len(result.WordSegments),
+		"processing_time", result.ProcessingTime)
+
+	return result, nil
+}
+
+// runWhisperX runs the WhisperX CLI, appending its output to the job's transcription
+// log. On failure it returns the tail of the log for context.
+func (w *WhisperXAdapter) runWhisperX(ctx context.Context, args []string, params map[string]interface{}, procCtx interfaces.ProcessingContext) (string, error) {
 	cmd := exec.CommandContext(ctx, "uv", args...)
+	cmd.Env = w.whisperXEnv(params)
+
+	// Setup log file
+	logPath := filepath.Join(procCtx.OutputDirectory, "transcription.log")
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		logger.Warn("Failed to create log file", "error", err)
+		cmd.Stdout = &whisperXProgress{procCtx: procCtx}
+	} else {
+		defer logFile.Close()
+		cmd.Stdout = &whisperXProgress{out: logFile, procCtx: procCtx}
+		cmd.Stderr = logFile
+	}
+
+	logger.Info("Executing WhisperX command", "args", strings.Join(args, " "))
+
+	if err := cmd.Run(); err != nil {
+		// Read tail of log file for context
+		logTail, readErr := w.ReadLogTail(logPath, 2048)
+		if readErr != nil {
+			logger.Warn("Failed to read log tail", "error", readErr)
+		}
+		return logTail, err
+	}
+	return "", nil
+}
+
+// whisperXProgressLine matches the lines WhisperX prints with --print_progress
+var whisperXProgressLine = regexp.MustCompile(`^Progress: ([0-9.]+)%`)
+
+// whisperXProgress passes WhisperX's output through to out, reporting the
+// progress it prints. Transcription is taken to be the first 80% of the work,
+// alignment the next 15% and diarization, which prints no progress, the rest.
+type whisperXProgress struct {
+	out     io.Writer
+	procCtx interfaces.ProcessingContext
+	stage   string
+	line    []byte
+}
+
+func (p *whisperXProgress) Write(b []byte) (int, error) {
+	for _, c := range b {
+		if c != '\n' && c != '\r' {
+			p.line = append(p.line, c)
+			continue
+		}
+		p.parse(string(p.line))
+		p.line = p.line[:0]
+	}
+	if p.out == nil {
+		return len(b), nil
+	}
+	return p.out.Write(b)
+}
+
+func (p *whisperXProgress) parse(line string) {
+	switch {
+	case strings.HasPrefix(line, ">>Performing transcription"):
+		p.stage = interfaces.ProgressTranscribing
+		p.procCtx.ReportProgress(p.stage, 0)
+	case strings.HasPrefix(line, ">>Performing alignment"):
+		p.stage = interfaces.ProgressAligning
+		p.procCtx.ReportProgress(p.stage, 80)
+	case strings.HasPrefix(line, ">>Performing diarization"):
+		p.stage = interfaces.ProgressDiarizing
+		p.procCtx.ReportProgress(p.stage, 95)
+	default:
+		match := whisperXProgressLine.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+		percent, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return
+		}
+		percent = min(max(percent, 0), 100)
+		switch p.stage {
+		case interfaces.ProgressAligning:
+			p.procCtx.ReportProgress(p.stage, 80+percent*0.15)
+		case interfaces.ProgressDiarizing:
+		default:
+			p.procCtx.ReportProgress(interfaces.ProgressTranscribing, percent*0.8)
+		}
+	}
+}
+
+// whisperXEnv is the environment for Python processes in the WhisperX project
+func (w *WhisperXAdapter) whisperXEnv(params map[string]interface{}) []string {
+	// The Hugging Face token also authorizes downloads of private custom models
+	var extraEnv []string
+	if hfToken := w.GetStringParameter(params, "hf_token"); hfToken != "" {
+		extraEnv = append(extraEnv, "HF_TOKEN="+hfToken)
+	}
 
 	// Add nvidia libraries to LD_LIBRARY_PATH
-	env := os.Environ()
+	env := uvEnv(extraEnv...)
 	if nvidiaPaths, err := w.findNvidiaLibPaths(); err == nil && len(nvidiaPaths) > 0 {
 		ldLibraryPath := os.Getenv("LD_LIBRARY_PATH")
 		newPath := strings.Join(nvidiaPaths, string(os.PathListSeparator))
@@ -439,52 +651,48 @@ func (w *WhisperXAdapter) Transcribe(ctx context.Context, input interfaces.Audio
 		logger.Debug("Updated LD_LIBRARY_PATH for WhisperX", "path", newPath)
 	}
 
-	cmd.Env = append(env, "PYTHONUNBUFFERED=1")
-
-	// Setup log file
-	logFile, err := os.OpenFile(filepath.Join(procCtx.OutputDirectory, "transcription.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		logger.Warn("Failed to create log file", "error", err)
-	} else {
-		defer logFile.Close()
-		cmd.Stdout = logFile
-		cmd.Stderr = logFile
-	}
+	return append(env, "PYTHONUNBUFFERED=1")
+}
 
-	logger.Info("Executing WhisperX command", "args", strings.Join(args, " "))
+// alignmentFailureMarkers identify a WhisperX run that failed while loading the
+// alignment model, e.g. for a language without a default wav2vec2 model or when
+// the model cannot be downloaded
+var alignmentFailureMarkers = []string{
+	"No default align-model for language",
+	"could not be found in huggingface",
+	"load_align_model",
+}
 
-	if err := cmd.Run(); err != nil {
-		if ctx.Err() == context.Canceled {
-			return nil, fmt.Errorf("transcription was cancelled")
+// alignmentFailure reports whether a WhisperX log tail shows an alignment model
+// failure, returning the most specific error line as the reason
+func alignmentFailure(logTail string) (string, bool) {
+	matched := false
+	for _, marker := range alignmentFailureMarkers {
+		if strings.Contains(logTail, marker) {
+			matched = true
+			break
 		}
-
-		// Read tail of log file for context
-		logPath := filepath.Join(procCtx.OutputDirectory, "transcription.log")
-		logTail, readErr := w.ReadLogTail(logPath, 2048)
-		if readErr != nil {
-			logger.Warn("Failed to read log tail", "error", readErr)
-		}
-
-		logger.Error("WhisperX execution failed", "error", err)
-		return nil, fmt.Errorf("WhisperX execution failed: %w\nLogs:\n%s", err, logTail)
 	}
-
-	// Parse result
-	result, err := w.parseResult(tempDir, input, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse result: %w", err)
+	if !matched {
+		return "", false
 	}
 
-	result.ProcessingTime = time.Since(startTime)
-	result.ModelUsed = w.GetStringParameter(params, "model")
-	result.Metadata = w.CreateDefaultMetadata(params)
-
-	logger.Info("WhisperX transcription completed",
-		"segments", len(result.Segments),
-		"words", len(result.WordSegments),
-		"processing_time", result.ProcessingTime)
+	reason := "alignment model could not be loaded"
+	lines := strings.Split(logTail, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.Contains(line, "Error:") {
+			reason = line
+			break
+		}
+	}
+	return reason, true
+}
 
-	return result, nil
+// PrefetchAlignmentModels downloads the default alignment models for the given
+// language codes so jobs in those languages get word-level timestamps offline
+func (w *WhisperXAdapter) PrefetchAlignmentModels(ctx context.Context, languages []string) error {
+	return w.PrefetchModels(ctx, []string{}, languages, "")
 }
 
 // buildWhisperXArgs builds the command arguments for WhisperX
@@ -498,11 +706,19 @@ func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params
 	}
 
 	// Core parameters
-	args = append(args, "--model", w.GetStringParameter(params, "model"))
-	args = append(args, "--device", w.GetStringParameter(params, "device"))
+	model := w.GetStringParameter(params, "model")
+	if customModel := w.GetStringParameter(params, "custom_model"); customModel != "" {
+		model = customModel
+	}
+	args = append(args, "--model", model)
+	if modelDir := w.GetStringParameter(params, "model_dir"); modelDir != "" {
+		args = append(args, "--model_dir", modelDir)
+	}
+	device, computeType := w.ctranslate2Device(params)
+	args = append(args, "--device", device)
 	args = append(args, "--device_index", strconv.Itoa(w.GetIntParameter(params, "device_index")))
 	args = append(args, "--batch_size", strconv.Itoa(w.GetIntParameter(params, "batch_size")))
-	args = append(args, "--compute_type", w.GetStringParameter(params, "compute_type"))
+	args = append(args, "--compute_type", computeType)
 
 	if threads := w.GetIntParameter(params, "threads"); threads > 0 {
 		args = append(args, "--threads", strconv.Itoa(threads))
@@ -523,8 +739,10 @@ func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params
 	args = append(args, "--vad_onset", fmt.Sprintf("%.3f", w.GetFloatParameter(params, "vad_onset")))
 	args = append(args, "--vad_offset", fmt.Sprintf("%.3f", w.GetFloatParameter(params, "vad_offset")))
 
-	// Custom alignment model
-	if alignModel := w.GetStringParameter(params, "align_model"); alignModel != "" {
+	// Alignment
+	if w.GetBoolParameter(params, "no_align") {
+		args = append(args, "--no_align")
+	} else if alignModel := w.GetStringParameter(params, "align_model"); alignModel != "" {
 		args = append(args, "--align_model", alignModel)
 	}
 
@@ -544,6 +762,9 @@ func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params
 		if maxSpeakers := w.GetIntParameter(params, "max_speakers"); maxSpeakers > 0 {
 			args = append(args, "--max_speakers", strconv.Itoa(maxSpeakers))
 		}
+		if w.GetBoolParameter(params, "speaker_embeddings") {
+			args = append(args, "--speaker_embeddings")
+		}
 	}
 
 	// Quality settings
@@ -551,18 +772,35 @@ func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params
 	args = append(args, "--best_of", strconv.Itoa(w.GetIntParameter(params, "best_of")))
 	args = append(args, "--beam_size", strconv.Itoa(w.GetIntParameter(params, "beam_size")))
 	args = append(args, "--patience", fmt.Sprintf("%.2f", w.GetFloatParameter(params, "patience")))
+	if prompt := w.GetStringParameter(params, "initial_prompt"); prompt != "" {
+		args = append(args, "--initial_prompt", prompt)
+	}
+	if hotwords := w.GetStringParameter(params, "hotwords"); hotwords != "" {
+		args = append(args, "--hotwords", hotwords)
+	}
 
 	// HuggingFace token
 	if hfToken := w.GetStringParameter(params, "hf_token"); hfToken != "" {
 		args = append(args, "--hf_token", hfToken)
 	}
 
-	// Disable print progress for cleaner output
-	args = append(args, "--print_progress", "False")
+	// Progress lines are parsed from the output to report the job's progress
+	args = append(args, "--print_progress", "True")
 
 	return args, nil
 }
 
+// ctranslate2Device resolves the device and compute type faster-whisper runs with
+func (w *WhisperXAdapter) ctranslate2Device(params map[string]interface{}) (string, string) {
+	device := DetectGPUBackend().CTranslate2Device(w.GetStringParameter(params, "device"))
+	computeType := w.GetStringParameter(params, "compute_type")
+	if device == "cpu" && computeType == "float16" {
+		// CTranslate2 has no float16 kernels on CPU
+		computeType = "float32"
+	}
+	return device, computeType
+}
+
 // parseResult parses the WhisperX output files
 func (w *WhisperXAdapter) parseResult(outputDir string, input interfaces.AudioInput, params map[string]interface{}) (*interfaces.TranscriptResult, error) {
 	// Find JSON result files
@@ -598,8 +836,9 @@ func (w *WhisperXAdapter) parseResult(outputDir string, input interfaces.AudioIn
 			Score   float64 `json:"score"`
 			Speaker *string `json:"speaker,omitempty"`
 		} `json:"word_segments,omitempty"`
-		Language string `json:"language"`
-		Text     string `json:"text,omitempty"`
+		Language          string               `json:"language"`
+		Text              string               `json:"text,omitempty"`
+		SpeakerEmbeddings map[string][]float64 `json:"speaker_embeddings,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &whisperxResult); err != nil {
@@ -608,10 +847,11 @@ func (w *WhisperXAdapter) parseResult(outputDir string, input interfaces.AudioIn
 
 	// Convert to standard format
 	result := &interfaces.TranscriptResult{
-		Language:     whisperxResult.Language,
-		Segments:     make([]interfaces.TranscriptSegment, len(whisperxResult.Segments)),
-		WordSegments: make([]interfaces.TranscriptWord, len(whisperxResult.Word)),
-		Confidence:   0.0, // WhisperX doesn't provide overall confidence
+		Language:          whisperxResult.Language,
+		Segments:          make([]interfaces.TranscriptSegment, len(whisperxResult.Segments)),
+		WordSegments:      make([]interfaces.TranscriptWord, len(whisperxResult.Word)),
+		Confidence:        0.0, // WhisperX doesn't provide overall confidence
+		SpeakerEmbeddings: whisperxResult.SpeakerEmbeddings,
 	}
 
 	// Convert segments
@@ -0,0 +1,37 @@
+package adapters
+
+import (
+	"bytes"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhisperXProgress(t *testing.T) {
+	type report struct {
+		stage   string
+		percent float64
+	}
+	var reports []report
+	var log bytes.Buffer
+	progress := &whisperXProgress{out: &log, procCtx: interfaces.ProcessingContext{
+		Progress: func(stage string, percent float64) { reports = append(reports, report{stage, percent}) },
+	}}
+
+	output := ">>Performing transcription...\nProgress: 50.00%...\nProg" +
+		"ress: 100.00%...\n>>Performing alignment...\nProgress: 40.00%...\r\nwarning: something\n>>Performing diarization...\n"
+	progress.Write([]byte(output[:40]))
+	progress.Write([]byte(output[40:]))
+
+	assert.Equal(t, output, log.String())
+	assert.Equal(t, []report{
+		{interfaces.ProgressTranscribing, 0},
+		{interfaces.ProgressTranscribing, 40},
+		{interfaces.ProgressTranscribing, 80},
+		{interfaces.ProgressAligning, 80},
+		{interfaces.ProgressAligning, 86},
+		{interfaces.ProgressDiarizing, 95},
+	}, reports)
+}
@@ -2,15 +2,28 @@ package transcription
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"scriberr/internal/models"
+	"scriberr/internal/repository"
 	"scriberr/internal/transcription/adapters"
 	"scriberr/internal/transcription/interfaces"
 	"scriberr/internal/transcription/registry"
 
+	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // MockJobRepository is a mock implementation of JobRepository
@@ -64,6 +77,112 @@ func (m *MockJobRepository) UpdateTranscript(ctx context.Context, jobID string,
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) UpdateIngestProgress(ctx context.Context, jobID string, progress float64) error {
+	args := m.Called(ctx, jobID, progress)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateProgress(ctx context.Context, jobID string, progress float64) error {
+	args := m.Called(ctx, jobID, progress)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) EnterStage(ctx context.Context, jobID, stage string) error {
+	args := m.Called(ctx, jobID, stage)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateScanResult(ctx context.Context, jobID string, scanStatus string, signature *string) error {
+	args := m.Called(ctx, jobID, scanStatus, signature)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateReview(ctx context.Context, job *models.TranscriptionJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListNeedingReview(ctx context.Context, offset, limit int) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListTranscriptRevisions(ctx context.Context, jobID string) ([]models.TranscriptRevision, error) {
+	args := m.Called(ctx, jobID)
+	return args.Get(0).([]models.TranscriptRevision), args.Error(1)
+}
+
+func (m *MockJobRepository) FindTranscriptRevision(ctx context.Context, jobID string, revision int) (*models.TranscriptRevision, error) {
+	args := m.Called(ctx, jobID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptRevision), args.Error(1)
+}
+
+func (m *MockJobRepository) DeleteTranscriptRevisionsByJobID(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateAlignmentFallback(ctx context.Context, jobID string, reason *string) error {
+	args := m.Called(ctx, jobID, reason)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateAudioSHA256(ctx context.Context, jobID string, sum string) error {
+	args := m.Called(ctx, jobID, sum)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateAudioSeconds(ctx context.Context, jobID string, seconds float64) error {
+	args := m.Called(ctx, jobID, seconds)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindDuplicate(ctx context.Context, job *models.TranscriptionJob) (*models.TranscriptionJob, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdateDuplicateOf(ctx context.Context, jobID string, originalID string) error {
+	args := m.Called(ctx, jobID, originalID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListByStatus(ctx context.Context, status models.JobStatus) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListForIntegrityCheck(ctx context.Context) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListPendingPostProcessing(ctx context.Context, limit int) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdatePostProcessing(ctx context.Context, job *models.TranscriptionJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateSummary(ctx context.Context, jobID string, summary string) error {
+	args := m.Called(ctx, jobID, summary)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) EnsureUnfinalized(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
 func (m *MockJobRepository) CreateExecution(ctx context.Context, execution *models.TranscriptionJobExecution) error {
 	args := m.Called(ctx, execution)
 	return args.Error(0)
@@ -74,6 +193,72 @@ func (m *MockJobRepository) UpdateExecution(ctx context.Context, execution *mode
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) CreateShadowRun(ctx context.Context, run *models.ShadowRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateShadowRun(ctx context.Context, run *models.ShadowRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindShadowRun(ctx context.Context, id string) (*models.ShadowRun, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShadowRun), args.Error(1)
+}
+
+func (m *MockJobRepository) ListShadowRuns(ctx context.Context, modelID string, limit int) ([]models.ShadowRun, error) {
+	args := m.Called(ctx, modelID, limit)
+	return args.Get(0).([]models.ShadowRun), args.Error(1)
+}
+
+func (m *MockJobRepository) DeleteShadowRunsByJobID(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) CreateExecutionComponents(ctx context.Context, components []models.ExecutionComponent) error {
+	args := m.Called(ctx, components)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListComponentVersions(ctx context.Context, kind, name string) ([]models.ComponentVersion, error) {
+	args := m.Called(ctx, kind, name)
+	return args.Get(0).([]models.ComponentVersion), args.Error(1)
+}
+
+func (m *MockJobRepository) ListComponentJobs(ctx context.Context, kind, name string, versions []string) ([]models.ComponentJob, error) {
+	args := m.Called(ctx, kind, name, versions)
+	return args.Get(0).([]models.ComponentJob), args.Error(1)
+}
+
+func (m *MockJobRepository) AddJobCost(ctx context.Context, jobID string, cost float64) error {
+	args := m.Called(ctx, jobID, cost)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) SummarizeCosts(ctx context.Context, groupBy string, from, to time.Time) ([]models.CostSummary, error) {
+	args := m.Called(ctx, groupBy, from, to)
+	return args.Get(0).([]models.CostSummary), args.Error(1)
+}
+
+func (m *MockJobRepository) SummarizeUsage(ctx context.Context, interval, groupBy string, from, to time.Time) ([]models.UsageSummary, error) {
+	args := m.Called(ctx, interval, groupBy, from, to)
+	return args.Get(0).([]models.UsageSummary), args.Error(1)
+}
+
+func (m *MockJobRepository) FindExecution(ctx context.Context, jobID, executionID string) (*models.TranscriptionJobExecution, error) {
+	args := m.Called(ctx, jobID, executionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptionJobExecution), args.Error(1)
+}
+
 func (m *MockJobRepository) DeleteExecutionsByJobID(ctx context.Context, jobID string) error {
 	args := m.Called(ctx, jobID)
 	return args.Error(0)
@@ -84,9 +269,40 @@ func (m *MockJobRepository) DeleteMultiTrackFilesByJobID(ctx context.Context, jo
 	return args.Error(0)
 }
 
-func (m *MockJobRepository) ListWithParams(ctx context.Context, offset, limit int, sortBy, sortOrder, searchQuery string) ([]models.TranscriptionJob, int64, error) {
-	args := m.Called(ctx, offset, limit, sortBy, sortOrder, searchQuery)
-	return args.Get(0).([]models.TranscriptionJob), args.Get(1).(int64), args.Error(2)
+func (m *MockJobRepository) DeleteTagAssignmentsByJobID(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) AddQuotaUsage(ctx context.Context, workspace, month string, seconds float64) error {
+	args := m.Called(ctx, workspace, month, seconds)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindQuotaUsage(ctx context.Context, workspace, month string) (float64, error) {
+	args := m.Called(ctx, workspace, month)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockJobRepository) FindByTitle(ctx context.Context, title string) (*models.TranscriptionJob, error) {
+	args := m.Called(ctx, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListByStatusAndTitle(ctx context.Context, statuses []models.JobStatus, titleContains string, offset, limit int) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, statuses, titleContains, offset, limit)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListJobs(ctx context.Context, q repository.JobListQuery) (*repository.JobListPage, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.JobListPage), args.Error(1)
 }
 
 // MockTranscriptionAdapter is a mock implementation of TranscriptionAdapter
@@ -228,6 +444,92 @@ func TestWhisperXAdapter(t *testing.T) {
 	}
 }
 
+func TestCustomWhisperModelValidation(t *testing.T) {
+	ctx := context.Background()
+
+	// Local checkpoints must be converted CTranslate2 directories
+	dir := t.TempDir()
+	if err := adapters.ValidateCustomWhisperModel(ctx, dir, ""); err == nil {
+		t.Error("Expected a directory without model.bin to be rejected")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "model.bin"), []byte("weights"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapters.ValidateCustomWhisperModel(ctx, dir, ""); err != nil {
+		t.Errorf("Expected converted checkpoint to validate: %v", err)
+	}
+	if err := adapters.ValidateCustomWhisperModel(ctx, "../relative/model", ""); err == nil {
+		t.Error("Expected a relative path to be rejected")
+	}
+
+	// Hugging Face repos are looked up with the token, and successful lookups cached
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		switch r.URL.Path {
+		case "/api/models/acme/whisper-legal-ct2":
+			if r.Header.Get("Authorization") != "Bearer hf_secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"siblings": [{"rfilename": "config.json"}, {"rfilename": "model.bin"}]}`))
+		case "/api/models/acme/whisper-legal":
+			w.Write([]byte(`{"siblings": [{"rfilename": "config.json"}, {"rfilename": "model.safetensors"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("HF_ENDPOINT", server.URL)
+
+	if err := adapters.ValidateCustomWhisperModel(ctx, "acme/whisper-legal-ct2", ""); err == nil {
+		t.Error("Expected a private repo to be rejected without a token")
+	}
+	if err := adapters.ValidateCustomWhisperModel(ctx, "acme/whisper-legal-ct2", "hf_secret"); err != nil {
+		t.Errorf("Expected private repo to validate with its token: %v", err)
+	}
+	if err := adapters.ValidateCustomWhisperModel(ctx, "acme/whisper-legal", ""); err == nil {
+		t.Error("Expected an unconverted transformers checkpoint to be rejected")
+	}
+	if err := adapters.ValidateCustomWhisperModel(ctx, "not a repo", ""); err == nil {
+		t.Error("Expected an invalid repo ID to be rejected")
+	}
+
+	before := lookups
+	if err := adapters.ValidateCustomWhisperModel(ctx, "acme/whisper-legal-ct2", "hf_secret"); err != nil {
+		t.Errorf("Expected cached repo to validate: %v", err)
+	}
+	if lookups != before {
+		t.Error("Expected a cached lookup not to call Hugging Face again")
+	}
+}
+
+func TestNemoAdapterValidation(t *testing.T) {
+	dir := t.TempDir()
+	weights := filepath.Join(dir, "legal_lora.pt")
+
+	if err := adapters.ValidateNemoAdapter(weights); err == nil {
+		t.Error("Expected missing adapter weights to be rejected")
+	}
+	if err := os.WriteFile(weights, []byte("weights"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapters.ValidateNemoAdapter(weights); err != nil {
+		t.Errorf("Expected adapter weights to validate: %v", err)
+	}
+	if err := adapters.ValidateNemoAdapter("adapters/legal_lora.pt"); err == nil {
+		t.Error("Expected a relative path to be rejected")
+	}
+
+	full := filepath.Join(dir, "parakeet-finetuned.nemo")
+	if err := os.WriteFile(full, []byte("model"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapters.ValidateNemoAdapter(full); err == nil {
+		t.Error("Expected a full .nemo model to be rejected as adapter weights")
+	}
+}
+
 func TestParakeetAdapter(t *testing.T) {
 	reg := registry.GetRegistry()
 	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewParakeetAdapter("/tmp/parakeet"))
@@ -254,6 +556,8 @@ func TestParakeetAdapter(t *testing.T) {
 		"timestamps":    true,
 		"context_left":  512,
 		"context_right": 512,
+		"adapter_path":  "/models/adapters/medical.pt",
+		"adapter_name":  "medical",
 	}
 
 	if err := adapter.ValidateParameters(validParams); err != nil {
@@ -440,6 +744,76 @@ func TestUnifiedTranscriptionService(t *testing.T) {
 	t.Logf("Model status: %+v", status)
 }
 
+// flakyEnvironmentAdapter fails to prepare its environment the first time
+type flakyEnvironmentAdapter struct {
+	MockTranscriptionAdapter
+	attempts int
+}
+
+func (f *flakyEnvironmentAdapter) PrepareEnvironment(ctx context.Context) error {
+	f.attempts++
+	if f.attempts == 1 {
+		return errors.New("network unreachable")
+	}
+	return nil
+}
+
+func TestInitializeModelsRetriesFailedEnvironments(t *testing.T) {
+	registry.ClearRegistry()
+	defer registry.ClearRegistry()
+	flaky := &flakyEnvironmentAdapter{}
+	registry.RegisterTranscriptionAdapter("flaky-model", flaky)
+	registry.RegisterTranscriptionAdapter("mock-model", new(MockTranscriptionAdapter))
+	reg := registry.GetRegistry()
+
+	// The retry is left to the test, not the background timer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	states := func() map[string]registry.EnvironmentState {
+		result := map[string]registry.EnvironmentState{}
+		for _, status := range reg.GetEnvironmentStatus() {
+			result[status.ModelID] = status.State
+		}
+		return result
+	}
+
+	if err := reg.InitializeModels(ctx); err != nil {
+		t.Fatalf("InitializeModels failed: %v", err)
+	}
+	if got := states(); got["flaky-model"] != registry.EnvironmentFailed || got["mock-model"] != registry.EnvironmentReady {
+		t.Fatalf("Unexpected states after first run: %v", got)
+	}
+	// Failed environments do not hold jobs back
+	if pending := reg.PendingEnvironments("flaky-model"); len(pending) != 0 {
+		t.Errorf("Failed environment reported as pending: %v", pending)
+	}
+
+	// A second run prepares only the environment that failed
+	if err := reg.InitializeModels(ctx); err != nil {
+		t.Fatalf("InitializeModels failed: %v", err)
+	}
+	if got := states(); got["flaky-model"] != registry.EnvironmentReady {
+		t.Errorf("Failed environment was not retried: %v", got)
+	}
+	if flaky.attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", flaky.attempts)
+	}
+	for _, status := range reg.GetEnvironmentStatus() {
+		if status.Error != "" {
+			t.Errorf("Stale error on %s: %s", status.ModelID, status.Error)
+		}
+	}
+
+	// Once everything is ready, further runs do nothing
+	if err := reg.InitializeModels(ctx); err != nil {
+		t.Fatalf("InitializeModels failed: %v", err)
+	}
+	if flaky.attempts != 2 {
+		t.Errorf("Ready environment was prepared again: %d attempts", flaky.attempts)
+	}
+}
+
 func TestAudioInputCreation(t *testing.T) {
 	mockRepo := new(MockJobRepository)
 	service := NewUnifiedTranscriptionService(mockRepo)
@@ -486,6 +860,308 @@ func TestParameterConversion(t *testing.T) {
 	}
 }
 
+func TestWordAgreement(t *testing.T) {
+	cases := []struct {
+		reference, hypothesis string
+		want                  float64
+	}{
+		{"Hello there, world.", "hello there world", 1},
+		{"the cat sat on the mat", "the cat sat on a mat", 1 - 1.0/6},
+		{"one two", "one two three four five", 0},
+		{"", "", 1},
+		{"", "noise", 0},
+	}
+	for _, c := range cases {
+		if got := wordAgreement(c.reference, c.hypothesis); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("wordAgreement(%q, %q) = %v, want %v", c.reference, c.hypothesis, got, c.want)
+		}
+	}
+}
+
+func TestDiffTexts(t *testing.T) {
+	diff := DiffTexts("The cat sat on the mat today.", "the cat sat on a mat, today! Yes")
+	stats := diff.Stats
+	if stats.ReferenceWords != 7 || stats.Substitutions != 1 || stats.Insertions != 1 || stats.Deletions != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if math.Abs(stats.WER-2.0/7) > 1e-9 {
+		t.Errorf("WER = %v, want 2/7", stats.WER)
+	}
+	// "the" to "a" and "yes": 3 + 3 character edits over 28 characters
+	if stats.CharErrors != 6 || stats.ReferenceChars != 28 {
+		t.Errorf("unexpected character stats %+v", stats)
+	}
+	want := []DiffChunk{
+		{Op: DiffEqual, Reference: "The cat sat on", Hypothesis: "the cat sat on"},
+		{Op: DiffReplace, Reference: "the", Hypothesis: "a"},
+		{Op: DiffEqual, Reference: "mat today.", Hypothesis: "mat, today!"},
+		{Op: DiffInsert, Hypothesis: "Yes"},
+	}
+	if len(diff.Chunks) != len(want) {
+		t.Fatalf("chunks = %+v, want %+v", diff.Chunks, want)
+	}
+	for i := range want {
+		if diff.Chunks[i] != want[i] {
+			t.Errorf("chunk %d = %+v, want %+v", i, diff.Chunks[i], want[i])
+		}
+	}
+
+	if stats := DiffTexts("", "").Stats; stats.WER != 0 || stats.CER != 0 {
+		t.Errorf("empty texts differ: %+v", stats)
+	}
+	if stats := DiffTexts("", "noise").Stats; stats.WER != 1 || stats.Insertions != 1 {
+		t.Errorf("unexpected stats against an empty reference %+v", stats)
+	}
+
+	// The alignment is as short as the edit distance, however the texts differ
+	words := strings.Fields("a b c d e")
+	for n := 0; n < 400; n++ {
+		var ref, hyp []string
+		for k, seed := 0, n; k < 6; k, seed = k+1, seed/5 {
+			ref = append(ref, words[(seed+k)%5])
+			if (n+k)%3 != 0 {
+				hyp = append(hyp, words[(seed*7+k)%5])
+			}
+		}
+		edits := 0
+		for _, e := range alignWords(ref, hyp) {
+			if e.op != editMatch {
+				edits++
+			}
+		}
+		if distance := editDistances(ref, hyp)[len(hyp)]; edits != distance {
+			t.Fatalf("alignWords(%v, %v) made %d edits, edit distance is %d", ref, hyp, edits, distance)
+		}
+	}
+}
+
+func TestOfferShadow(t *testing.T) {
+	service := NewUnifiedTranscriptionService(new(MockJobRepository))
+	job := &models.TranscriptionJob{ID: "job-1", AudioPath: "/tmp/job-1.wav"}
+	result := &interfaces.TranscriptResult{Segments: []interfaces.TranscriptSegment{{Text: "Hello"}, {Text: "world"}}}
+
+	// Disabled: nothing is sampled
+	service.offerShadow(job, &singleTrackPlan{transcriptionModelID: "whisperx"}, result)
+
+	service.shadow = &shadowEvaluator{modelID: "parakeet", percent: 100, queue: make(chan shadowJob, 1)}
+	service.offerShadow(job, &singleTrackPlan{transcriptionModelID: "parakeet"}, result)
+	if len(service.shadow.queue) != 0 {
+		t.Fatal("jobs already served by the candidate should not be sampled")
+	}
+	service.offerShadow(job, &singleTrackPlan{transcriptionModelID: "whisperx"}, result)
+	service.offerShadow(job, &singleTrackPlan{transcriptionModelID: "whisperx"}, result) // queue full: dropped
+	if len(service.shadow.queue) != 1 {
+		t.Fatalf("expected 1 queued shadow job, got %d", len(service.shadow.queue))
+	}
+	queued := <-service.shadow.queue
+	if queued.primaryModelID != "whisperx" || queued.primaryText != "Hello world" {
+		t.Errorf("unexpected shadow job %+v", queued)
+	}
+}
+
+func TestAudioPreprocessingPlan(t *testing.T) {
+	service := NewUnifiedTranscriptionService(new(MockJobRepository))
+	job := &models.TranscriptionJob{ID: "job-1", Parameters: models.WhisperXParams{ModelFamily: "whisper", Model: "small"}}
+	plan, err := service.planSingleTrack(context.Background(), job)
+	if err != nil {
+		t.Fatalf("planSingleTrack: %v", err)
+	}
+	if plan.preprocessing != nil {
+		t.Errorf("preprocessing should be off by default, got %+v", plan.preprocessing)
+	}
+
+	job.Parameters.PreprocessTrimSilence = true
+	job.Parameters.PreprocessDenoise = true
+	plan, err = service.planSingleTrack(context.Background(), job)
+	if err != nil {
+		t.Fatalf("planSingleTrack: %v", err)
+	}
+	if plan.preprocessing == nil || !plan.preprocessing.TrimSilence || !plan.preprocessing.Denoise || plan.preprocessing.Normalize {
+		t.Errorf("unexpected preprocessing %+v", plan.preprocessing)
+	}
+	if e := service.enhancement(*plan.preprocessing); e.DenoiseModel != "" || !e.TrimSilence {
+		t.Errorf("denoising without a model should be skipped, got %+v", e)
+	}
+	service.SetDenoiseModel("/models/sh.rnnn")
+	if e := service.enhancement(*plan.preprocessing); e.DenoiseModel != "/models/sh.rnnn" {
+		t.Errorf("expected the configured denoise model, got %+v", e)
+	}
+
+	// Voice activity detection only cuts audio sent to remote adapters
+	job.Parameters.PreprocessVAD = true
+	plan, err = service.planSingleTrack(context.Background(), job)
+	if err != nil {
+		t.Fatalf("planSingleTrack: %v", err)
+	}
+	if !plan.preprocessing.VAD || cutsToSpeech(plan) {
+		t.Errorf("VAD should be planned but not run for a local adapter, got %+v", plan.preprocessing)
+	}
+	plan.transcriptionModelID = interfaces.RunPodWhisperX
+	if !cutsToSpeech(plan) {
+		t.Error("VAD should run for a remote adapter")
+	}
+}
+
+func TestVocabulary(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Vocabulary{}, &models.VocabularyFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&models.Vocabulary{OrganizationID: "acme", Name: "products", Phrases: "Scriberr\nWhisperX", Corrections: "scribe r\tScriberr\nwhisper ex\tWhisperX\nscriber\tScriberr"})
+	db.Create(&models.Vocabulary{Name: "products", Phrases: "Other"})
+	db.Create(&models.VocabularyFilter{OrganizationID: "acme", Name: "profanity", Words: "darn\nheck"})
+
+	service := NewUnifiedTranscriptionService(new(MockJobRepository))
+	service.SetVocabularies(repository.NewVocabularyRepository(db))
+	name, filterName := "products", "profanity"
+	prompt := "Meeting notes."
+	job := &models.TranscriptionJob{ID: "job-1", Workspace: models.OrganizationWorkspace("acme"), Parameters: models.WhisperXParams{
+		ModelFamily: "whisper", Model: "small", InitialPrompt: &prompt, VocabularyName: &name, VocabularyFilterName: &filterName,
+	}}
+
+	// The organization's vocabulary is added to the prompt and hotwords
+	plan, err := service.planSingleTrack(context.Background(), job)
+	if err != nil {
+		t.Fatalf("planSingleTrack: %v", err)
+	}
+	if got := plan.transcriptionParams["initial_prompt"]; got != "Meeting notes. Scriberr, WhisperX" {
+		t.Errorf("unexpected initial_prompt %v", got)
+	}
+	if got := plan.transcriptionParams["hotwords"]; got != "Scriberr, WhisperX" {
+		t.Errorf("unexpected hotwords %v", got)
+	}
+
+	transcript := func() *interfaces.TranscriptResult {
+		return &interfaces.TranscriptResult{
+			Text:     "Darn, the heck-free build. Oh heck!",
+			Segments: []interfaces.TranscriptSegment{{Text: " Darn, the heck-free build."}, {Text: " Oh heck!"}},
+			WordSegments: []interfaces.TranscriptWord{
+				{Word: "Darn,"}, {Word: "the"}, {Word: "heck-free"}, {Word: "build."}, {Word: "Oh"}, {Word: "heck!"},
+			},
+		}
+	}
+	words := func(result *interfaces.TranscriptResult) string {
+		var out []string
+		for _, word := range result.WordSegments {
+			if word.VocabularyFilterMatch {
+				out = append(out, "["+word.Word+"]")
+			} else {
+				out = append(out, word.Word)
+			}
+		}
+		return strings.Join(out, " ")
+	}
+
+	// Known misspellings are corrected, whole words only
+	result := &interfaces.TranscriptResult{
+		Text:         "Scribe R uses whisper  ex; scribers use Scriber.",
+		Segments:     []interfaces.TranscriptSegment{{Text: " Scribe R uses whisper  ex;"}, {Text: " scribers use Scriber."}},
+		WordSegments: []interfaces.TranscriptWord{{Word: "Scribe"}, {Word: "R"}, {Word: "scribers"}, {Word: "Scriber."}},
+	}
+	service.correctVocabulary(context.Background(), job, result)
+	if result.Text != "Scriberr uses WhisperX; scribers use Scriberr." || result.Segments[0].Text != " Scriberr uses WhisperX;" {
+		t.Errorf("unexpected correction %q %+v", result.Text, result.Segments)
+	}
+	if got := words(result); got != "Scribe R scribers Scriberr." {
+		t.Errorf("unexpected corrected words %q", got)
+	}
+
+	// Removed by default, whole words only
+	result = transcript()
+	service.applyVocabularyFilter(context.Background(), job, result)
+	if result.Text != "the heck-free build. Oh" || result.Segments[0].Text != " the heck-free build." || result.Segments[1].Text != " Oh" {
+		t.Errorf("unexpected removal %q %+v", result.Text, result.Segments)
+	}
+	if got := words(result); got != "the heck-free build. Oh" {
+		t.Errorf("unexpected words after removal %q", got)
+	}
+
+	method := models.VocabularyFilterMask
+	job.Parameters.VocabularyFilterMethod = &method
+	result = transcript()
+	service.applyVocabularyFilter(context.Background(), job, result)
+	if result.Segments[0].Text != " ***, the heck-free build." || result.Segments[1].Text != " Oh ***!" {
+		t.Errorf("unexpected masking %+v", result.Segments)
+	}
+
+	method = models.VocabularyFilterTag
+	result = transcript()
+	service.applyVocabularyFilter(context.Background(), job, result)
+	if result.Text != transcript().Text || words(result) != "[Darn,] the heck-free build. Oh [heck!]" {
+		t.Errorf("unexpected tagging %q %q", result.Text, words(result))
+	}
+
+	// Jobs outside the organization use their own vocabularies of the name
+	job.Workspace = "user-1"
+	plan, err = service.planSingleTrack(context.Background(), job)
+	if err != nil {
+		t.Fatalf("planSingleTrack: %v", err)
+	}
+	if got := plan.transcriptionParams["initial_prompt"]; got != "Meeting notes. Other" {
+		t.Errorf("unexpected initial_prompt %v", got)
+	}
+	result = transcript()
+	service.applyVocabularyFilter(context.Background(), job, result)
+	if words(result) != words(transcript()) {
+		t.Errorf("another organization's filter was applied: %q", words(result))
+	}
+}
+
+func TestCorrectionRules(t *testing.T) {
+	compile := func(rule models.CorrectionRule) CorrectionRule {
+		c, err := CompileCorrectionRule(rule)
+		if err != nil {
+			t.Fatalf("CompileCorrectionRule(%q): %v", rule.Find, err)
+		}
+		return c
+	}
+	rules := CorrectionRules{
+		compile(models.CorrectionRule{Find: "scribber", Replace: "Scriberr"}),
+		compile(models.CorrectionRule{Find: "Whisper X", Replace: "WhisperX", CaseSensitive: true}),
+		compile(models.CorrectionRule{Find: `(\d+) percent`, Replace: "$1%", Regex: true}),
+	}
+
+	text, n := rules.Apply("SCRIBBER uses Whisper X, not whisper x; scribbers ran 5 Percent faster.")
+	if want := "Scriberr uses WhisperX, not whisper x; scribbers ran 5% faster."; text != want || n != 3 {
+		t.Errorf("Apply = %q, %d; want %q, 3", text, n, want)
+	}
+
+	result := &interfaces.TranscriptResult{
+		Text:         "Try scribber.",
+		Segments:     []interfaces.TranscriptSegment{{Text: " Try scribber."}},
+		WordSegments: []interfaces.TranscriptWord{{Word: "Try"}, {Word: "scribber."}},
+	}
+	if n := rules.ApplyTranscript(result); n != 1 {
+		t.Errorf("ApplyTranscript = %d, want 1", n)
+	}
+	if result.Text != "Try Scriberr." || result.Segments[0].Text != " Try Scriberr." || result.WordSegments[1].Word != "Scriberr." {
+		t.Errorf("unexpected corrected transcript %+v", result)
+	}
+
+	for _, invalid := range []models.CorrectionRule{{Find: ""}, {Find: "(", Regex: true}, {Find: "a*", Regex: true}} {
+		if _, err := CompileCorrectionRule(invalid); err == nil {
+			t.Errorf("CompileCorrectionRule(%q) succeeded", invalid.Find)
+		}
+	}
+}
+
+func TestShiftTranscript(t *testing.T) {
+	result := &interfaces.TranscriptResult{
+		Segments:     []interfaces.TranscriptSegment{{Start: 0, End: 2.5}, {Start: 3, End: 4}},
+		WordSegments: []interfaces.TranscriptWord{{Start: 0.5, End: 1}},
+	}
+	shiftTranscript(result, 1.5)
+	if result.Segments[0].Start != 1.5 || result.Segments[0].End != 4 || result.Segments[1].End != 5.5 {
+		t.Errorf("unexpected segments %+v", result.Segments)
+	}
+	if result.WordSegments[0].Start != 2 || result.WordSegments[0].End != 2.5 {
+		t.Errorf("unexpected words %+v", result.WordSegments)
+	}
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
@@ -496,6 +1172,194 @@ func intPtr(i int) *int {
 }
 
 // Benchmark tests
+func TestApplyAlignment(t *testing.T) {
+	speaker := "SPEAKER_00"
+	transcript := &interfaces.TranscriptResult{
+		Segments: []interfaces.TranscriptSegment{
+			{Start: 0, End: 3, Text: " hello there ", Speaker: &speaker},
+			{Start: 3, End: 5, Text: "???"},
+		},
+		WordSegments: []interfaces.TranscriptWord{{Start: 0, End: 1, Word: "stale"}},
+	}
+	aligned := []*adapters.AlignedSegment{
+		{Start: 0.4, End: 1.6, Words: []interfaces.TranscriptWord{
+			{Start: 0.4, End: 0.9, Word: "hello"}, {Start: 1.0, End: 1.6, Word: "there"},
+		}},
+		nil,
+	}
+
+	if count := applyAlignment(transcript, aligned); count != 1 {
+		t.Errorf("Expected 1 aligned segment, got %d", count)
+	}
+	if seg := transcript.Segments[0]; seg.Start != 0.4 || seg.End != 1.6 {
+		t.Errorf("Expected the aligned segment to move to 0.4-1.6, got %.1f-%.1f", seg.Start, seg.End)
+	}
+	if seg := transcript.Segments[1]; seg.Start != 3 || seg.End != 5 {
+		t.Errorf("Expected the unaligned segment to keep its times, got %.1f-%.1f", seg.Start, seg.End)
+	}
+	if len(transcript.WordSegments) != 2 || transcript.WordSegments[0].Speaker == nil || *transcript.WordSegments[0].Speaker != speaker {
+		t.Errorf("Expected the aligned words with their segment's speaker, got %+v", transcript.WordSegments)
+	}
+	if transcript.Text != "hello there ???" {
+		t.Errorf("Expected the text to be rebuilt from the segments, got %q", transcript.Text)
+	}
+}
+
+// hangingAdapter never finishes transcribing before its context is done
+type hangingAdapter struct {
+	MockTranscriptionAdapter
+}
+
+func (h *hangingAdapter) Transcribe(ctx context.Context, input interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) (*interfaces.TranscriptResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestAdapterFailover(t *testing.T) {
+	registry.ClearRegistry()
+	defer registry.ClearRegistry()
+	registry.RegisterTranscriptionAdapter(interfaces.ModalWhisperX, &hangingAdapter{})
+	registry.RegisterTranscriptionAdapter("whisperx", new(MockTranscriptionAdapter))
+
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "audio.wav")
+	if err := os.WriteFile(audioPath, make([]byte, 1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chain := "unknown, " + interfaces.ModalWhisperX + ", whisper"
+	job := &models.TranscriptionJob{ID: "failover", AudioPath: audioPath, Parameters: models.WhisperXParams{
+		ModelFamily: interfaces.ModalWhisperX, FallbackModelFamilies: &chain, AdapterTimeoutSeconds: 1,
+	}}
+	if got := job.Parameters.FallbackChain(); len(got) != 2 || got[0] != "unknown" || got[1] != "whisper" {
+		t.Fatalf("Expected the fallback chain without the primary family, got %v", got)
+	}
+
+	service := NewUnifiedTranscriptionService(new(MockJobRepository))
+	service.tempDirectory = dir
+	plan, err := service.planSingleTrack(context.Background(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execution := &models.TranscriptionJobExecution{ActualParameters: job.Parameters}
+	result, plan, err := service.runWithFailover(context.Background(), job, plan, interfaces.ProcessingContext{TempDirectory: dir}, execution)
+	if err != nil {
+		t.Fatalf("Expected the job to fail over to WhisperX: %v", err)
+	}
+	if result.Text != "mock transcript" || plan.transcriptionModelID != "whisperx" {
+		t.Errorf("Expected the result of WhisperX, got %q from %s", result.Text, plan.transcriptionModelID)
+	}
+	if execution.Adapter == nil || *execution.Adapter != "whisperx" || execution.ActualParameters.ModelFamily != "whisper" {
+		t.Errorf("Expected the execution to record WhisperX, got %v with family %s", execution.Adapter, execution.ActualParameters.ModelFamily)
+	}
+	var attempts []models.AdapterAttempt
+	if execution.FailedAdapters == nil || json.Unmarshal([]byte(*execution.FailedAdapters), &attempts) != nil || len(attempts) != 2 {
+		t.Fatalf("Expected two failed attempts, got %v", execution.FailedAdapters)
+	}
+	if attempts[0].ModelID != interfaces.ModalWhisperX || !strings.Contains(attempts[0].Error, "timed out") {
+		t.Errorf("Expected Modal to time out, got %+v", attempts[0])
+	}
+	if attempts[1].ModelFamily != "unknown" {
+		t.Errorf("Expected the unknown family to be skipped, got %+v", attempts[1])
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	invalid := fmt.Errorf("transcription failed: %w", fmt.Errorf("%w: unknown model", interfaces.ErrInvalidParameters))
+	oom := errors.New("diarization failed: PyAnnote execution failed: exit status 1\nLogs:\ntorch.OutOfMemoryError: CUDA out of memory")
+	timeout := models.WithErrorCode(models.ErrorAdapterTimeout, fmt.Errorf("whisperx timed out after 1s: %w", context.DeadlineExceeded))
+	tests := []struct {
+		name  string
+		err   error
+		stage string
+		want  models.ErrorCode
+	}{
+		{"invalid parameters", invalid, models.StageTranscribing, models.ErrorInvalidParams},
+		{"diarization out of memory", oom, models.StageDiarizing, models.ErrorDiarizationOOM},
+		{"transcription out of memory", oom, models.StageTranscribing, ""},
+		{"timeout while diarizing", timeout, models.StageDiarizing, models.ErrorAdapterTimeout},
+		{"other failure", errors.New("transcription failed: exit status 1"), models.StageTranscribing, ""},
+	}
+	for _, tt := range tests {
+		err := classifyFailure(tt.err, tt.stage)
+		if got := models.ErrorCodeOf(err); got != tt.want {
+			t.Errorf("%s: got code %q, want %q", tt.name, got, tt.want)
+		}
+		if err.Error() != tt.err.Error() {
+			t.Errorf("%s: message changed to %q", tt.name, err.Error())
+		}
+	}
+}
+
+func TestRunBenchmark(t *testing.T) {
+	registry.ClearRegistry()
+	defer registry.ClearRegistry()
+	registry.RegisterTranscriptionAdapter("whisperx", new(MockTranscriptionAdapter))
+
+	dir := t.TempDir()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(dir, "benchmarks.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Benchmark{}, &models.BenchmarkSample{}, &models.BenchmarkRun{}); err != nil {
+		t.Fatal(err)
+	}
+	audioPath := filepath.Join(dir, "call.wav")
+	if err := os.WriteFile(audioPath, make([]byte, 64000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewBenchmarkRepository(db)
+	benchmark := &models.Benchmark{
+		ModelIDs: "whisperx,missing",
+		Status:   models.StatusPending,
+		Samples:  []models.BenchmarkSample{{Name: "call.wav", AudioPath: audioPath, Reference: "The mock transcript."}},
+	}
+	if err := repo.Create(context.Background(), benchmark); err != nil {
+		t.Fatal(err)
+	}
+	if len(benchmark.Runs) != 2 {
+		t.Fatalf("Expected a run per adapter, got %d", len(benchmark.Runs))
+	}
+
+	service := NewUnifiedTranscriptionService(new(MockJobRepository))
+	service.tempDirectory = dir
+	service.SetCostModel(CostModel{"whisperx": 0.6})
+	service.benchmarks = &benchmarkRunner{repo: repo}
+	service.runBenchmark(context.Background(), benchmark.ID)
+
+	stored, err := repo.FindByID(context.Background(), benchmark.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != models.StatusCompleted || stored.StartedAt == nil || stored.CompletedAt == nil {
+		t.Errorf("Expected the benchmark to complete, got %s", stored.Status)
+	}
+	if stored.Samples[0].AudioSeconds == nil {
+		t.Fatal("Expected the sample's duration to be recorded")
+	}
+	run := stored.Runs[0]
+	if run.ModelID != "whisperx" || run.Status != models.StatusCompleted || run.Text == nil || *run.Text != "mock transcript" {
+		t.Fatalf("Expected WhisperX to transcribe the sample, got %+v", run)
+	}
+	// "the" is missing from the mock transcript
+	if run.ReferenceWords != 3 || run.WordErrors != 1 || run.WER == nil || math.Abs(*run.WER-1.0/3) > 1e-9 {
+		t.Errorf("Expected one word error in three, got %d in %d", run.WordErrors, run.ReferenceWords)
+	}
+	if run.ProcessingDuration == nil || run.Cost == nil || math.Abs(*run.Cost-0.6**stored.Samples[0].AudioSeconds/60) > 1e-9 {
+		t.Errorf("Expected the run to be timed and costed, got %v and %v", run.ProcessingDuration, run.Cost)
+	}
+	if failed := stored.Runs[1]; failed.Status != models.StatusFailed || failed.ErrorMessage == nil || failed.WER != nil {
+		t.Errorf("Expected the unknown adapter's run to fail, got %+v", failed)
+	}
+
+	// A deleted benchmark is not saved again
+	if err := repo.Delete(context.Background(), benchmark.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateRun(context.Background(), &run); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected the deleted run not to be found, got %v", err)
+	}
+}
+
 func BenchmarkModelRegistryLookup(b *testing.B) {
 	reg := registry.GetRegistry()
 
@@ -530,3 +1394,96 @@ func BenchmarkParameterValidation(b *testing.B) {
 		}
 	}
 }
+
+func TestRecordCost(t *testing.T) {
+	jobRepo := new(MockJobRepository)
+	service := NewUnifiedTranscriptionService(jobRepo)
+	job := &models.TranscriptionJob{ID: "costed"}
+	adapter := "openai_whisper"
+	audio, skipped := 180.0, 60.0
+	execution := &models.TranscriptionJobExecution{Adapter: &adapter, AudioSeconds: &audio, SkippedSeconds: &skipped}
+
+	// Unpriced adapters are not costed
+	service.SetCostModel(CostModel{"whisperx": 0.01})
+	service.recordCost(context.Background(), job, execution)
+	if execution.Cost != nil {
+		t.Errorf("Expected no cost for an unpriced adapter, got %f", *execution.Cost)
+	}
+
+	// Audio cut out by voice activity detection is not billed
+	service.SetCostModel(CostModel{"openai_whisper": 0.006})
+	jobRepo.On("AddJobCost", mock.Anything, "costed", mock.AnythingOfType("float64")).Return(nil)
+	service.recordCost(context.Background(), job, execution)
+	if execution.Cost == nil || math.Abs(*execution.Cost-0.012) > 1e-9 {
+		t.Errorf("Expected a cost of 0.012 for 2 billed minutes, got %v", execution.Cost)
+	}
+	jobRepo.AssertExpectations(t)
+}
+
+func TestResourceGate(t *testing.T) {
+	host := adapters.HostResources{GPUs: []adapters.GPUMemory{{TotalMB: 16384, FreeMB: 14000}}, CPUs: 4, LoadAverage: 2}
+	gate := newResourceGate()
+	gate.probe = func(context.Context) adapters.HostResources { return host }
+	ctx := context.Background()
+
+	large := gpuMemoryNeeded(adapters.GPUBackendCUDA, models.WhisperXParams{Model: "large-v3", Device: "auto"}, "whisperx", "pyannote")
+	if large != 12288 {
+		t.Fatalf("Expected large-v3 with diarization to need 12288 MiB, got %d", large)
+	}
+	if mb := gpuMemoryNeeded(adapters.GPUBackendCUDA, models.WhisperXParams{Model: "large-v3", Device: "cpu"}, "whisperx"); mb != 0 {
+		t.Errorf("Expected CPU jobs to need no GPU memory, got %d", mb)
+	}
+
+	// The first local job always starts; a second waits while it would not fit
+	if err := gate.acquire(ctx, "first", large); err != nil {
+		t.Fatalf("Expected the first job to start, got %v", err)
+	}
+	if err := gate.acquire(ctx, "second", large); err == nil {
+		t.Error("Expected a second large job to wait for GPU memory")
+	}
+	small := gpuMemoryNeeded(adapters.GPUBackendCUDA, models.WhisperXParams{Model: "small"}, "whisperx")
+	if err := gate.acquire(ctx, "small", small); err != nil {
+		t.Errorf("Expected a small job to fit next to the first, got %v", err)
+	}
+	gate.release("small")
+
+	// The CPU load only counts when limited
+	gate.maxLoadPerCPU = 0.5
+	if err := gate.acquire(ctx, "cpu", 0); err == nil {
+		t.Error("Expected a job to wait while the CPUs are loaded")
+	}
+	gate.release("first")
+	if err := gate.acquire(ctx, "second", large); err != nil {
+		t.Errorf("Expected the second job to start once the first finished, got %v", err)
+	}
+}
+
+func TestFlagLowConfidence(t *testing.T) {
+	result := &interfaces.TranscriptResult{
+		Segments: []interfaces.TranscriptSegment{
+			{Start: 0, End: 2, Text: "Clear speech."},
+			{Start: 2, End: 4, Text: "Mumbled words."},
+			{Start: 4, End: 5, Text: "Unscored."},
+		},
+		WordSegments: []interfaces.TranscriptWord{
+			{Start: 2.1, End: 2.8, Word: "Mumbled", Score: 0.3},
+			{Start: 0.1, End: 0.9, Word: "Clear", Score: 0.9},
+			{Start: 1.0, End: 1.9, Word: "speech.", Score: 0.7},
+			{Start: 2.9, End: 3.9, Word: "words.", Score: 0.5},
+			{Start: 4.1, End: 4.9, Word: "Unscored.", Score: 0},
+		},
+	}
+	if flagged := flagLowConfidence(result, 0.5); flagged != 1 {
+		t.Fatalf("flagLowConfidence = %d, want 1", flagged)
+	}
+	first, second, third := result.Segments[0], result.Segments[1], result.Segments[2]
+	if first.LowConfidence || first.Confidence == nil || math.Abs(*first.Confidence-0.8) > 1e-9 {
+		t.Errorf("unexpected first segment %+v", first)
+	}
+	if !second.LowConfidence || second.Confidence == nil || math.Abs(*second.Confidence-0.4) > 1e-9 {
+		t.Errorf("unexpected second segment %+v", second)
+	}
+	if third.LowConfidence || third.Confidence != nil {
+		t.Errorf("segment without scored words was rated: %+v", third)
+	}
+}
@@ -9,11 +9,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"scriberr/internal/database"
+	"scriberr/internal/events"
 	"scriberr/internal/models"
 	"scriberr/internal/repository"
 	"scriberr/internal/service"
 	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/webhook"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
 	"strings"
 	"time"
 
@@ -65,17 +68,10 @@ func (u *S3JobProcessor) Initialize(ctx context.Context) error {
 	return u.unifiedProcessor.Initialize(ctx)
 }
 
-// ProcessJob implements JobProcessor interface
+// ProcessJob implements JobProcessor interface. EventBridge hears how the job
+// went through the EventBridgeNotifier, once the queue published it.
 func (u *S3JobProcessor) ProcessJob(ctx context.Context, jobID string) error {
-	err := u.ProcessSingleJob(ctx, jobID)
-	event := "COMPLETED"
-	if err != nil {
-		event = "FAILED"
-	}
-
-	go u.publishNotifications(jobID, event)
-
-	return err
+	return u.ProcessSingleJob(ctx, jobID)
 }
 
 func (u *S3JobProcessor) ProcessSingleJob(ctx context.Context, jobID string) error {
@@ -90,12 +86,20 @@ func (u *S3JobProcessor) ProcessSingleJob(ctx context.Context, jobID string) err
 	if job.AudioUri != nil && strings.HasPrefix(*job.AudioUri, "s3://") {
 		isS3Job = true
 		filename = filepath.Base(*job.AudioUri)
-		audioPath := filepath.Join(u.uploadDir, filename)
+		uploadDir, err := service.WorkspaceDir(u.uploadDir, job.Workspace)
+		if err != nil {
+			return err
+		}
+		if err := u.fileService.CreateDirectory(uploadDir); err != nil {
+			return err
+		}
+		audioPath := filepath.Join(uploadDir, filename)
 		if _, err := os.Stat(audioPath); os.IsNotExist(err) {
 			logger.Debug("Downloading audio", "uri", *job.AudioUri, "audio_path", audioPath)
+			u.enterStage(ctx, jobID, models.StageDownloading)
 			err := u.fileService.DownloadFile(ctx, *job.AudioUri, audioPath)
 			if err != nil {
-				return err
+				return models.WithErrorCode(models.ErrorAudioDownloadFailed, err)
 			}
 		}
 
@@ -130,7 +134,7 @@ func (u *S3JobProcessor) ProcessSingleJob(ctx context.Context, jobID string) err
 	}
 
 	outputBucket := processedJob.OutputBucketName
-	transcriptFilename := fmt.Sprintf("%s.json", getJobName(processedJob))
+	transcriptFilename := service.TranscriptObjectName(&processedJob)
 
 	var tags []types.Tag
 	if processedJob.Tags != nil {
@@ -142,12 +146,17 @@ func (u *S3JobProcessor) ProcessSingleJob(ctx context.Context, jobID string) err
 	}
 
 	tags = append(tags, types.Tag{Key: aws.String("scriberr-id"), Value: aws.String(jobID)})
-	_, err = u.s3Client.PutObject(ctx, &s3.PutObjectInput{
+	u.enterStage(ctx, jobID, models.StageUploadingResults)
+	putCtx, span := tracing.StartKind(ctx, "s3.PutObject", tracing.KindClient,
+		tracing.String("aws.s3.bucket", *outputBucket), tracing.String("aws.s3.key", transcriptFilename))
+	_, err = u.s3Client.PutObject(putCtx, &s3.PutObjectInput{
 		Bucket:  outputBucket,
 		Key:     aws.String(transcriptFilename),
 		Body:    strings.NewReader(transcript),
 		Tagging: aws.String(tagsToS3TaggingString(tags)),
 	})
+	span.RecordError(err)
+	span.End()
 
 	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %w", err)
@@ -158,21 +167,48 @@ func (u *S3JobProcessor) ProcessSingleJob(ctx context.Context, jobID string) err
 	return nil
 }
 
-func (u *S3JobProcessor) publishNotifications(jobID string, event string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	var processedJob models.TranscriptionJob
-	if loadErr := database.DB.Where("id = ?", jobID).First(&processedJob).Error; loadErr != nil {
-		logger.Error("Failed to load job", "job_id", jobID, "event", event, "error", loadErr)
-		return
+// enterStage records the stage the job has entered
+func (u *S3JobProcessor) enterStage(ctx context.Context, jobID, stage string) {
+	if err := u.jobRepo.EnterStage(ctx, jobID, stage); err != nil {
+		logger.Debug("Failed to update job stage", "job_id", jobID, "error", err)
 	}
+}
 
-	if eventErr := u.sendEventBridgeEvent(ctx, processedJob, event); eventErr != nil {
-		logger.Error("Failed to send EventBridge event", "job_id", jobID, "event", event, "error", eventErr)
+// EventBridgeNotifier sends AWS Transcribe style job state changes to
+// EventBridge as jobs complete or fail
+type EventBridgeNotifier struct {
+	processor *S3JobProcessor
+}
+
+// EventBridgeNotifier returns the notifier sending through the processor's
+// EventBridge client
+func (u *S3JobProcessor) EventBridgeNotifier() *EventBridgeNotifier {
+	return &EventBridgeNotifier{processor: u}
+}
+
+// Name identifies the notifier in logs
+func (n *EventBridgeNotifier) Name() string {
+	return "eventbridge"
+}
+
+// Send sends a COMPLETED or FAILED state change with the job as saved;
+// other events are not sent
+func (n *EventBridgeNotifier) Send(ctx context.Context, event events.Event) error {
+	status := ""
+	switch event.Type {
+	case events.JobCompleted:
+		status = "COMPLETED"
+	case events.JobFailed:
+		status = "FAILED"
+	default:
+		return nil
 	}
 
-	logger.Info("Job notifications published", "job_id", jobID, "event", event)
+	var job models.TranscriptionJob
+	if err := database.DB.WithContext(ctx).Where("id = ?", event.Job.ID).First(&job).Error; err != nil {
+		return fmt.Errorf("failed to load job: %w", err)
+	}
+	return n.processor.sendEventBridgeEvent(ctx, job, status)
 }
 
 // sendEventBridgeEvent sends a job completion event to AWS EventBridge
@@ -189,6 +225,14 @@ func (u *S3JobProcessor) sendEventBridgeEvent(ctx context.Context, job models.Tr
 
 	detailType := "Transcribe Job State Change"
 
+	if tmpl := notificationTemplate(ctx, models.NotificationChannelEventBridge); tmpl != nil {
+		detailJSON, err := webhook.Render(tmpl, webhook.NewTemplateData(&job, eventStatus))
+		if err != nil {
+			return fmt.Errorf("failed to render EventBridge template: %w", err)
+		}
+		return u.putEvent(ctx, job, eventStatus, eventBusName, source, detailType, string(detailJSON))
+	}
+
 	detail := map[string]interface{}{
 		"TranscriptionJobName":   getJobName(job),
 		"TranscriptionJobID":     job.ID,
@@ -207,14 +251,17 @@ func (u *S3JobProcessor) sendEventBridgeEvent(ctx context.Context, job models.Tr
 	if err != nil {
 		return fmt.Errorf("failed to marshal event detail: %w", err)
 	}
+	return u.putEvent(ctx, job, eventStatus, eventBusName, source, detailType, string(detailJSON))
+}
 
-	_, err = u.eventBridgeClient.PutEvents(ctx, &eventbridge.PutEventsInput{
+func (u *S3JobProcessor) putEvent(ctx context.Context, job models.TranscriptionJob, eventStatus, eventBusName, source, detailType, detail string) error {
+	_, err := u.eventBridgeClient.PutEvents(ctx, &eventbridge.PutEventsInput{
 		Entries: []ebTypes.PutEventsRequestEntry{
 			{
 				EventBusName: aws.String(eventBusName),
 				Source:       aws.String(source),
 				DetailType:   aws.String(detailType),
-				Detail:       aws.String(string(detailJSON)),
+				Detail:       aws.String(detail),
 			},
 		},
 	})
@@ -311,11 +358,15 @@ func (u *S3JobProcessor) downloadS3File(ctx context.Context, uri string, saveTo
 	key := parts[1]
 
 	// Download the file
+	ctx, span := tracing.StartKind(ctx, "s3.GetObject", tracing.KindClient,
+		tracing.String("aws.s3.bucket", bucket), tracing.String("aws.s3.key", key))
+	defer span.End()
 	result, err := u.s3Client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
+		span.RecordError(err)
 		return fmt.Errorf("failed to download from S3: %w", err)
 	}
 	defer result.Body.Close()
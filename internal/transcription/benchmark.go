@@ -0,0 +1,218 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// Benchmark errors
+var (
+	// ErrBenchmarksDisabled is returned when this process does not run benchmarks
+	ErrBenchmarksDisabled = errors.New("benchmarks are not enabled")
+	// ErrBenchmarkQueueFull is returned when too many benchmarks are waiting
+	ErrBenchmarkQueueFull = errors.New("too many benchmarks are waiting")
+)
+
+// benchmarkQueueSize bounds the benchmarks waiting to run
+const benchmarkQueueSize = 16
+
+// benchmarkRunner runs queued benchmarks one at a time
+type benchmarkRunner struct {
+	repo  repository.BenchmarkRepository
+	queue chan string
+}
+
+// SetBenchmarks enables adapter benchmarks. Like shadow runs, benchmark runs
+// happen one at a time while no job is processing, so their latencies are not
+// skewed by other work. Benchmarks interrupted by a restart resume with the
+// runs they had left.
+func (u *UnifiedTranscriptionService) SetBenchmarks(repo repository.BenchmarkRepository) {
+	u.benchmarks = &benchmarkRunner{repo: repo, queue: make(chan string, benchmarkQueueSize)}
+	go u.runBenchmarkWorker()
+
+	ids, err := repo.ListUnfinished(context.Background())
+	if err != nil {
+		logger.Warn("Failed to list unfinished benchmarks", "error", err)
+		return
+	}
+	for _, id := range ids {
+		if err := u.QueueBenchmark(id); err != nil {
+			logger.Warn("Failed to resume benchmark", "benchmark_id", id, "error", err)
+		}
+	}
+}
+
+// QueueBenchmark queues a stored benchmark to be run
+func (u *UnifiedTranscriptionService) QueueBenchmark(id string) error {
+	if u.benchmarks == nil {
+		return ErrBenchmarksDisabled
+	}
+	select {
+	case u.benchmarks.queue <- id:
+		return nil
+	default:
+		return ErrBenchmarkQueueFull
+	}
+}
+
+// runBenchmarkWorker runs queued benchmarks in the background
+func (u *UnifiedTranscriptionService) runBenchmarkWorker() {
+	for id := range u.benchmarks.queue {
+		u.runBenchmark(context.Background(), id)
+	}
+}
+
+// runBenchmark runs the runs a benchmark has left. A benchmark deleted while
+// running stops after its current run.
+func (u *UnifiedTranscriptionService) runBenchmark(ctx context.Context, id string) {
+	repo := u.benchmarks.repo
+	benchmark, err := repo.FindByID(ctx, id)
+	if err != nil {
+		logger.Warn("Failed to load benchmark", "benchmark_id", id, "error", err)
+		return
+	}
+	if benchmark.StartedAt == nil {
+		startedAt := time.Now()
+		benchmark.StartedAt = &startedAt
+	}
+	benchmark.Status = models.StatusProcessing
+	if err := repo.Update(ctx, benchmark); err != nil {
+		logger.Warn("Failed to start benchmark", "benchmark_id", id, "error", err)
+		return
+	}
+	logger.Info("Running benchmark", "benchmark_id", id, "samples", len(benchmark.Samples), "model_ids", benchmark.ModelIDs)
+
+	samples := make(map[uint]*models.BenchmarkSample, len(benchmark.Samples))
+	for i := range benchmark.Samples {
+		samples[benchmark.Samples[i].ID] = &benchmark.Samples[i]
+	}
+	for i := range benchmark.Runs {
+		run := &benchmark.Runs[i]
+		if run.Status == models.StatusCompleted || run.Status == models.StatusFailed {
+			continue
+		}
+		for u.activeJobs.Load() > 0 || len(u.registry.PendingEnvironments(run.ModelID)) > 0 {
+			time.Sleep(shadowIdlePoll)
+		}
+		if err := u.runBenchmarkSample(ctx, benchmark, samples[run.SampleID], run); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Info("Benchmark was deleted, stopping", "benchmark_id", id)
+				return
+			}
+			logger.Warn("Failed to record benchmark run", "benchmark_id", id, "run_id", run.ID, "error", err)
+		}
+	}
+
+	completedAt := time.Now()
+	benchmark.CompletedAt = &completedAt
+	benchmark.Status = models.StatusCompleted
+	if err := repo.Update(ctx, benchmark); err != nil {
+		logger.Warn("Failed to complete benchmark", "benchmark_id", id, "error", err)
+		return
+	}
+	logger.Info("Benchmark completed", "benchmark_id", id)
+}
+
+// runBenchmarkSample transcribes a sample with a run's adapter, and scores the
+// transcript against the sample's reference
+func (u *UnifiedTranscriptionService) runBenchmarkSample(ctx context.Context, benchmark *models.Benchmark, sample *models.BenchmarkSample, run *models.BenchmarkRun) error {
+	repo := u.benchmarks.repo
+	startedAt := time.Now()
+	run.Status = models.StatusProcessing
+	run.StartedAt = &startedAt
+	if err := repo.UpdateRun(ctx, run); err != nil {
+		return err
+	}
+
+	var text string
+	var err error
+	if sample == nil {
+		err = fmt.Errorf("sample %d not found", run.SampleID)
+	} else {
+		text, err = u.transcribeBenchmark(ctx, benchmark, sample, run)
+	}
+
+	completedAt := time.Now()
+	duration := completedAt.Sub(startedAt).Milliseconds()
+	run.CompletedAt = &completedAt
+	run.ProcessingDuration = &duration
+	if err != nil {
+		msg := err.Error()
+		run.Status = models.StatusFailed
+		run.ErrorMessage = &msg
+		logger.Warn("Benchmark run failed", "benchmark_id", benchmark.ID, "model_id", run.ModelID, "error", err)
+		return repo.UpdateRun(ctx, run)
+	}
+
+	stats := DiffTexts(sample.Reference, text).Stats
+	run.Status = models.StatusCompleted
+	run.Text = &text
+	run.ReferenceWords = stats.ReferenceWords
+	run.WordErrors = stats.Substitutions + stats.Deletions + stats.Insertions
+	run.WER = &stats.WER
+	run.ReferenceChars = stats.ReferenceChars
+	run.CharErrors = stats.CharErrors
+	run.CER = &stats.CER
+	if sample.AudioSeconds != nil {
+		if cost, ok := u.costs.Estimate(*sample.AudioSeconds, run.ModelID); ok {
+			run.Cost = &cost
+		}
+	}
+	return repo.UpdateRun(ctx, run)
+}
+
+// transcribeBenchmark runs a benchmark's adapter over a sample in a scratch
+// directory of its own, and returns the transcript's text. The sample's
+// duration is recorded the first time it is probed.
+func (u *UnifiedTranscriptionService) transcribeBenchmark(ctx context.Context, benchmark *models.Benchmark, sample *models.BenchmarkSample, run *models.BenchmarkRun) (string, error) {
+	sandbox := filepath.Join(u.tempDirectory, "benchmark", benchmark.ID, fmt.Sprint(run.ID))
+	if err := os.MkdirAll(sandbox, 0755); err != nil {
+		return "", fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+	defer os.RemoveAll(sandbox)
+
+	audioInput, err := u.createAudioInput(sample.AudioPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create audio input: %w", err)
+	}
+	if sample.AudioSeconds == nil {
+		seconds := audioInput.Duration.Seconds()
+		sample.AudioSeconds = &seconds
+		if err := u.benchmarks.repo.UpdateSample(ctx, sample); err != nil {
+			logger.Warn("Failed to record benchmark sample duration", "sample_id", sample.ID, "error", err)
+		}
+	}
+
+	// Only the text is scored, so diarization is skipped
+	params := benchmark.Parameters
+	params.Diarize = false
+	plan := &singleTrackPlan{
+		transcriptionModelID: run.ModelID,
+		transcriptionParams:  u.convertParametersForModel(params, run.ModelID),
+	}
+	if preprocessing := params.Preprocessing(); preprocessing.Enabled() {
+		plan.preprocessing = &preprocessing
+	}
+	procCtx := interfaces.ProcessingContext{
+		JobID:           benchmark.ID,
+		OutputDirectory: sandbox,
+		TempDirectory:   u.tempDirectory,
+		Metadata:        map[string]string{"benchmark_id": benchmark.ID},
+	}
+	result, err := u.runAudio(ctx, audioInput, plan, procCtx)
+	if err != nil {
+		return "", err
+	}
+	return transcriptText(result), nil
+}
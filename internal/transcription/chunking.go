@@ -0,0 +1,140 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/pipeline"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+)
+
+// remoteModels are the adapters that run elsewhere, billed by the minute, and
+// so can take several chunks at once; local adapters share one GPU and take
+// them in turn
+var remoteModels = []string{interfaces.ModalWhisperX, interfaces.RunPodWhisperX, "openai_whisper"}
+
+// chunker splits long audio into chunks that are transcribed in parallel
+type chunker struct {
+	minDuration time.Duration
+	chunking    models.AudioChunking
+	workers     int
+}
+
+// SetChunking splits audio of at least minDuration into chunks of about
+// chunkSeconds, cut in pauses and overlapping by overlapSeconds, of which up to
+// workers are transcribed at once. A zero minDuration disables chunking.
+func (u *UnifiedTranscriptionService) SetChunking(minDuration time.Duration, chunkSeconds, overlapSeconds, workers int) {
+	if minDuration <= 0 || chunkSeconds <= 0 {
+		return
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	u.chunker = &chunker{
+		minDuration: minDuration,
+		chunking:    models.AudioChunking{ChunkSeconds: float64(chunkSeconds), OverlapSeconds: float64(overlapSeconds)},
+		workers:     workers,
+	}
+	logger.Info("Long-audio chunking enabled", "min_duration", minDuration, "chunk_seconds", chunkSeconds,
+		"overlap_seconds", overlapSeconds, "workers", workers)
+}
+
+// chunkingFor returns how the audio is chunked, or nil when chunking is
+// disabled or the audio is too short for it
+func (u *UnifiedTranscriptionService) chunkingFor(audioPath string) *models.AudioChunking {
+	if u.chunker == nil || audioPath == "" {
+		return nil
+	}
+	input, err := u.createAudioInput(audioPath)
+	if err != nil || input.Duration < u.chunker.minDuration {
+		return nil
+	}
+	chunking := u.chunker.chunking
+	return &chunking
+}
+
+// splitForChunking plans the chunks of the audio and cuts them out into the
+// temp directory
+func (u *UnifiedTranscriptionService) splitForChunking(ctx context.Context, input interfaces.AudioInput, chunking models.AudioChunking, procCtx interfaces.ProcessingContext) ([]pipeline.Chunk, []interfaces.AudioInput, error) {
+	ctx, span := tracing.Start(ctx, "audio.chunk")
+	defer span.End()
+
+	chunks, err := pipeline.PlanChunks(ctx, input, chunking.ChunkSeconds, chunking.OverlapSeconds)
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+	tempDir := procCtx.TempDirectory
+	if tempDir == "" {
+		tempDir = u.tempDirectory
+	}
+	inputs, err := pipeline.SplitAudio(ctx, input, chunks, filepath.Join(tempDir, "chunks", procCtx.JobID))
+	span.RecordError(err)
+	return chunks, inputs, err
+}
+
+// transcribeChunks transcribes each chunk with the adapter, up to the
+// configured number at once for remote adapters and one at a time for local
+// ones, and merges their transcripts. Each chunk gets its own job ID and output
+// directory so adapters running in parallel do not share files; the output
+// directories are removed once merged. The first failure cancels the other
+// chunks and fails the whole.
+func (u *UnifiedTranscriptionService) transcribeChunks(ctx context.Context, adapter interfaces.TranscriptionAdapter, chunks []pipeline.Chunk, inputs []interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) (*interfaces.TranscriptResult, error) {
+	workers := 1
+	if u.chunker != nil && slices.Contains(remoteModels, adapter.GetCapabilities().ModelID) {
+		workers = u.chunker.workers
+	}
+	chunkDir := filepath.Join(procCtx.OutputDirectory, "chunks")
+	defer func() {
+		if err := os.RemoveAll(chunkDir); err != nil {
+			logger.Warn("Failed to remove chunk output directory", "dir", chunkDir, "error", err)
+		}
+	}()
+	logger.Info("Transcribing long audio in chunks", "job_id", procCtx.JobID, "chunks", len(chunks), "workers", workers)
+
+	started := time.Now()
+	results := make([]*interfaces.TranscriptResult, len(chunks))
+	progress := chunkProgress(procCtx.Progress, len(inputs))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(workers)
+	for i, input := range inputs {
+		group.Go(func() error {
+			chunkCtx := procCtx
+			chunkCtx.JobID = fmt.Sprintf("%s-chunk%03d", procCtx.JobID, i)
+			chunkCtx.OutputDirectory = filepath.Join(chunkDir, fmt.Sprintf("%03d", i))
+			chunkCtx.Progress = progress[i]
+			if err := os.MkdirAll(chunkCtx.OutputDirectory, 0755); err != nil {
+				return fmt.Errorf("failed to create chunk output directory: %w", err)
+			}
+
+			spanCtx, span := tracing.Start(groupCtx, "adapter.transcribe_chunk", tracing.Int("chunk.index", i))
+			result, err := adapter.Transcribe(spanCtx, input, params, chunkCtx)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				return fmt.Errorf("chunk %d (%.0fs-%.0fs): %w", i, chunks[i].Start, chunks[i].End, err)
+			}
+			results[i] = result
+			chunkCtx.ReportProgress(interfaces.ProgressTranscribing, 100)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := pipeline.MergeChunks(chunks, results)
+	merged.ProcessingTime = time.Since(started)
+	logger.Info("Merged chunk transcripts", "job_id", procCtx.JobID, "segments", len(merged.Segments),
+		"duration", merged.ProcessingTime)
+	return merged, nil
+}
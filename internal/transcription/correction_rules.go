@@ -0,0 +1,150 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// SetCorrectionRules enables the find-and-replace rules transcripts are
+// corrected with once transcribed
+func (u *UnifiedTranscriptionService) SetCorrectionRules(r repository.CorrectionRuleRepository) {
+	u.correctionRules = r
+}
+
+// applyCorrectionRules corrects a transcript with the enabled rules of the
+// organization owning the job. A rule that no longer compiles is skipped.
+func (u *UnifiedTranscriptionService) applyCorrectionRules(ctx context.Context, job *models.TranscriptionJob, result *interfaces.TranscriptResult) {
+	if u.correctionRules == nil {
+		return
+	}
+	rules, err := u.correctionRules.List(vocabularyContext(ctx, job))
+	if err != nil {
+		logger.Warn("Failed to load correction rules, leaving the transcript uncorrected", "job_id", job.ID, "error", err)
+		return
+	}
+	var compiled CorrectionRules
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		c, err := CompileCorrectionRule(rule)
+		if err != nil {
+			logger.Warn("Skipping invalid correction rule", "job_id", job.ID, "rule_id", rule.ID, "error", err)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	if n := compiled.ApplyTranscript(result); n > 0 {
+		logger.Info("Applied correction rules", "job_id", job.ID, "replacements", n)
+	}
+}
+
+// CorrectionRule is a compiled correction rule
+type CorrectionRule struct {
+	pattern *regexp.Regexp
+	replace string
+	// exact rules replace literally, and only where the match is not part of
+	// a longer word
+	exact bool
+}
+
+// CorrectionRules are compiled correction rules, applied in order
+type CorrectionRules []CorrectionRule
+
+// CompileCorrectionRule compiles a rule, failing if its regular expression is
+// invalid or matches empty text, which would be replaced everywhere
+func CompileCorrectionRule(rule models.CorrectionRule) (CorrectionRule, error) {
+	if rule.Find == "" {
+		return CorrectionRule{}, fmt.Errorf("find must not be empty")
+	}
+	expr := rule.Find
+	if !rule.Regex {
+		expr = regexp.QuoteMeta(rule.Find)
+	}
+	if !rule.CaseSensitive {
+		expr = `(?i)` + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return CorrectionRule{}, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	if pattern.MatchString("") {
+		return CorrectionRule{}, fmt.Errorf("regular expression must not match empty text")
+	}
+	return CorrectionRule{pattern: pattern, replace: rule.Replace, exact: !rule.Regex}, nil
+}
+
+// Apply corrects text, returning it and how many replacements were made
+func (r CorrectionRule) Apply(text string) (string, int) {
+	matches := r.pattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text, 0
+	}
+	var b strings.Builder
+	last, count := 0, 0
+	for _, match := range matches {
+		if r.exact {
+			before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+			after, _ := utf8.DecodeRuneInString(text[match[1]:])
+			if isWordRune(before) || isWordRune(after) {
+				continue
+			}
+		}
+		b.WriteString(text[last:match[0]])
+		if r.exact {
+			b.WriteString(r.replace)
+		} else {
+			b.Write(r.pattern.ExpandString(nil, r.replace, text, match))
+		}
+		last = match[1]
+		count++
+	}
+	if count == 0 {
+		return text, 0
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// Apply corrects text with every rule in turn
+func (rules CorrectionRules) Apply(text string) (string, int) {
+	total := 0
+	for _, rule := range rules {
+		var n int
+		text, n = rule.Apply(text)
+		total += n
+	}
+	return text, total
+}
+
+// ApplyTranscript corrects a transcript's text, segments and word timings,
+// returning how many replacements were made in its segments, or in its text if
+// it has none. Words are corrected one by one, so only rules matching within
+// a single word change them.
+func (rules CorrectionRules) ApplyTranscript(result *interfaces.TranscriptResult) int {
+	if len(rules) == 0 {
+		return 0
+	}
+	text, total := rules.Apply(result.Text)
+	result.Text = text
+	if len(result.Segments) > 0 {
+		total = 0
+	}
+	for i := range result.Segments {
+		var n int
+		result.Segments[i].Text, n = rules.Apply(result.Segments[i].Text)
+		total += n
+	}
+	for i := range result.WordSegments {
+		result.WordSegments[i].Word, _ = rules.Apply(result.WordSegments[i].Word)
+	}
+	return total
+}
@@ -0,0 +1,85 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+// CostModel prices adapters per minute of the audio sent to them, by model ID
+type CostModel map[string]float64
+
+// Estimate returns what running the given adapters over seconds of audio is
+// estimated to cost, and false when none of them is priced
+func (m CostModel) Estimate(seconds float64, modelIDs ...string) (float64, bool) {
+	var cost float64
+	priced := false
+	for _, modelID := range modelIDs {
+		if price, ok := m[modelID]; ok {
+			cost += price * seconds / 60
+			priced = true
+		}
+	}
+	return cost, priced
+}
+
+// SetCostModel sets the per-minute adapter prices executions are costed at
+func (u *UnifiedTranscriptionService) SetCostModel(costs CostModel) {
+	u.costs = costs
+}
+
+// executionAdapters returns the model IDs of the adapters an execution ran:
+// those of its snapshot, or else the transcription adapter of its parameters
+func (u *UnifiedTranscriptionService) executionAdapters(execution *models.TranscriptionJobExecution) []string {
+	if execution.Snapshot != nil {
+		var snapshot models.ExecutionSnapshot
+		if err := json.Unmarshal([]byte(*execution.Snapshot), &snapshot); err == nil && len(snapshot.Steps) > 0 {
+			modelIDs := make([]string, len(snapshot.Steps))
+			for i, step := range snapshot.Steps {
+				modelIDs[i] = step.ModelID
+			}
+			return modelIDs
+		}
+	}
+	if execution.Adapter != nil {
+		return []string{*execution.Adapter}
+	}
+	transcriptionModelID, _, _ := u.selectModels(execution.ActualParameters)
+	return []string{transcriptionModelID}
+}
+
+// recordCost estimates what an execution cost over the audio sent to its
+// adapters, which excludes what voice activity detection cut out, and adds it
+// to the job's cost
+func (u *UnifiedTranscriptionService) recordCost(ctx context.Context, job *models.TranscriptionJob, execution *models.TranscriptionJobExecution) {
+	if len(u.costs) == 0 || execution.AudioSeconds == nil {
+		return
+	}
+	seconds := *execution.AudioSeconds
+	if execution.SkippedSeconds != nil {
+		seconds = max(seconds-*execution.SkippedSeconds, 0)
+	}
+	cost, ok := u.costs.Estimate(seconds, u.executionAdapters(execution)...)
+	if !ok {
+		return
+	}
+	execution.Cost = &cost
+	if err := u.jobRepo.AddJobCost(ctx, job.ID, cost); err != nil {
+		logger.Warn("Failed to record job cost", "job_id", job.ID, "error", err)
+	}
+}
+
+// recordReuseCost records that an execution reusing the transcript of identical
+// audio cost nothing
+func (u *UnifiedTranscriptionService) recordReuseCost(ctx context.Context, job *models.TranscriptionJob, execution *models.TranscriptionJobExecution) {
+	if len(u.costs) == 0 {
+		return
+	}
+	var cost float64
+	execution.Cost = &cost
+	if err := u.jobRepo.AddJobCost(ctx, job.ID, cost); err != nil {
+		logger.Warn("Failed to record job cost", "job_id", job.ID, "error", err)
+	}
+}
@@ -0,0 +1,64 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// findDuplicate hashes a job's audio, the first time it is processed, and
+// returns the earlier job whose transcript it can reuse when its profile
+// deduplicates audio, or nil. Audio still in S3 is hashed once the S3 processor
+// has downloaded it.
+func (u *UnifiedTranscriptionService) findDuplicate(ctx context.Context, job *models.TranscriptionJob) *models.TranscriptionJob {
+	if job.IsMultiTrack {
+		return nil
+	}
+	if strings.HasPrefix(job.AudioPath, "s3://") {
+		logger.Debug("Audio not downloaded, skipping deduplication", "job_id", job.ID)
+		return nil
+	}
+	if job.AudioSHA256 == "" {
+		sum, err := hashFile(job.AudioPath)
+		if err != nil {
+			// Transcription reports the missing audio
+			logger.Warn("Failed to hash job audio", "job_id", job.ID, "error", err)
+			return nil
+		}
+		job.AudioSHA256 = sum
+		if err := u.jobRepo.UpdateAudioSHA256(ctx, job.ID, sum); err != nil {
+			logger.Warn("Failed to record audio hash", "job_id", job.ID, "error", err)
+		}
+	}
+	if !job.Parameters.DeduplicateAudio || job.ProfileID == nil {
+		return nil
+	}
+
+	original, err := u.jobRepo.FindDuplicate(ctx, job)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Failed to look for a duplicate job", "job_id", job.ID, "error", err)
+		}
+		return nil
+	}
+	return original
+}
+
+// reuseTranscript completes a job with the transcript of the job found with
+// identical audio
+func (u *UnifiedTranscriptionService) reuseTranscript(ctx context.Context, job, original *models.TranscriptionJob) error {
+	logger.Info("Reusing the transcript of identical audio", "job_id", job.ID, "original_job_id", original.ID)
+	if err := u.jobRepo.UpdateTranscript(ctx, job.ID, *original.Transcript); err != nil {
+		return fmt.Errorf("failed to save transcript: %w", err)
+	}
+	if err := u.jobRepo.UpdateAlignmentFallback(ctx, job.ID, original.AlignmentFallback); err != nil {
+		logger.Warn("Failed to record alignment fallback", "job_id", job.ID, "error", err)
+	}
+	return u.jobRepo.UpdateDuplicateOf(ctx, job.ID, original.ID)
+}
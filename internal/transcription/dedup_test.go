@@ -0,0 +1,168 @@
+package transcription
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/repository"
+	"scriberr/internal/scanner"
+	"scriberr/internal/service"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// flaggingScanner flags the files with the given name
+type flaggingScanner struct {
+	flagged string
+}
+
+func (s flaggingScanner) Scan(ctx context.Context, path string) (scanner.Result, error) {
+	if filepath.Base(path) == s.flagged {
+		return scanner.Result{Signature: "Eicar-Test-Signature"}, nil
+	}
+	return scanner.Result{Clean: true}, nil
+}
+
+// s3Downloader downloads S3 objects with the given contents
+type s3Downloader struct {
+	service.FileService
+	contents   []byte
+	downloaded []string
+}
+
+func (d *s3Downloader) CreateDirectory(path string) error {
+	return os.MkdirAll(path, 0755)
+}
+
+func (d *s3Downloader) DownloadFile(ctx context.Context, url string, saveTo string) error {
+	d.downloaded = append(d.downloaded, url)
+	return os.WriteFile(saveTo, d.contents, 0644)
+}
+
+func TestDeduplicateAudio(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.MultiTrackFile{}, &models.TranscriptionJobExecution{}, &models.TranscriptRevision{}, &models.QuotaUsage{}))
+	jobRepo := repository.NewJobRepository(db)
+	service := NewUnifiedTranscriptionService(jobRepo)
+	service.SetScanner(flaggingScanner{flagged: "infected.wav"})
+	service.SetCostModel(CostModel{"whisperx": 0.6})
+
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "meeting.wav")
+	require.NoError(t, os.WriteFile(audioPath, []byte("RIFF meeting audio"), 0644))
+	sum, err := hashFile(audioPath)
+	require.NoError(t, err)
+
+	profileID, version := "profile-1", 2
+	transcript := `{"text":"Ship it","segments":[{"start":0,"end":1,"text":"Ship it"}]}`
+	original := &models.TranscriptionJob{ID: "original", AudioPath: audioPath, AudioSHA256: sum, Status: models.StatusCompleted,
+		Transcript: &transcript, ProfileID: &profileID, ProfileVersion: &version}
+	require.NoError(t, db.Create(original).Error)
+	otherWorkspace := &models.TranscriptionJob{ID: "other-workspace", AudioPath: audioPath, AudioSHA256: sum, Status: models.StatusCompleted,
+		Transcript: &transcript, ProfileID: &profileID, ProfileVersion: &version, Workspace: "org-acme"}
+	require.NoError(t, db.Create(otherWorkspace).Error)
+
+	newJobAt := func(id, audioPath string, dedup bool, version int) *models.TranscriptionJob {
+		job := &models.TranscriptionJob{ID: id, AudioPath: audioPath, Status: models.StatusProcessing, ProfileID: &profileID, ProfileVersion: &version,
+			Parameters: models.WhisperXParams{ModelFamily: "unknown", DeduplicateAudio: dedup}}
+		require.NoError(t, db.Create(job).Error)
+		return job
+	}
+	newJob := func(id string, dedup bool, version int) *models.TranscriptionJob {
+		return newJobAt(id, audioPath, dedup, version)
+	}
+
+	t.Run("Reuses the transcript of identical audio", func(t *testing.T) {
+		job := newJob("duplicate", true, version)
+		require.NoError(t, service.ProcessJob(context.Background(), job.ID))
+
+		saved, err := jobRepo.FindByID(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sum, saved.AudioSHA256)
+		require.NotNil(t, saved.Transcript)
+		assert.Equal(t, transcript, *saved.Transcript)
+		require.NotNil(t, saved.DuplicateOf)
+		assert.Equal(t, original.ID, *saved.DuplicateOf)
+		assert.Equal(t, float64(100), saved.Progress)
+
+		execution, err := jobRepo.FindExecution(context.Background(), job.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, execution.Status)
+		assert.Equal(t, models.ScanClean, saved.ScanStatus, "the audio is scanned all the same")
+
+		// The audio counts against the quota, at no cost
+		require.NotNil(t, execution.AudioSeconds)
+		assert.Equal(t, float64(1), *execution.AudioSeconds)
+		require.NotNil(t, execution.Cost)
+		assert.Zero(t, *execution.Cost)
+		require.NotNil(t, saved.EstimatedCost)
+		assert.Zero(t, *saved.EstimatedCost)
+		usage, err := jobRepo.FindQuotaUsage(context.Background(), "", models.QuotaMonth(time.Now()))
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), usage)
+	})
+
+	t.Run("Quarantines flagged audio instead of reusing a transcript", func(t *testing.T) {
+		infectedPath := filepath.Join(dir, "infected.wav")
+		require.NoError(t, os.WriteFile(infectedPath, []byte("RIFF meeting audio"), 0644))
+		job := newJobAt("infected", infectedPath, true, version)
+		err := service.ProcessJob(context.Background(), job.ID)
+		assert.ErrorIs(t, err, queue.ErrJobQuarantined)
+
+		saved, err := jobRepo.FindByID(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ScanQuarantined, saved.ScanStatus)
+		assert.Nil(t, saved.Transcript)
+		assert.Nil(t, saved.DuplicateOf)
+	})
+
+	t.Run("Hashes audio in S3 once downloaded", func(t *testing.T) {
+		uri := "s3://calls/meeting-copy.wav"
+		job := newJobAt("from-s3", uri, true, version)
+		job.AudioUri = &uri
+		require.NoError(t, db.Save(job).Error)
+
+		// Not yet downloaded, the audio is neither hashed nor deduplicated
+		assert.Nil(t, service.findDuplicate(context.Background(), job))
+		assert.Empty(t, job.AudioSHA256)
+
+		previousDB := database.DB
+		database.DB = db
+		t.Cleanup(func() { database.DB = previousDB })
+		downloader := &s3Downloader{contents: []byte("RIFF meeting audio")}
+		processor := &S3JobProcessor{unifiedProcessor: &UnifiedJobProcessor{unifiedService: service},
+			fileService: downloader, jobRepo: jobRepo, uploadDir: t.TempDir()}
+		require.NoError(t, processor.ProcessSingleJob(context.Background(), job.ID))
+		assert.Equal(t, []string{uri}, downloader.downloaded)
+
+		saved, err := jobRepo.FindByID(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sum, saved.AudioSHA256)
+		assert.False(t, strings.HasPrefix(saved.AudioPath, "s3://"))
+		require.NotNil(t, saved.DuplicateOf)
+		assert.Equal(t, original.ID, *saved.DuplicateOf)
+		_, err = os.Stat(saved.AudioPath)
+		assert.True(t, os.IsNotExist(err), "the downloaded audio is removed")
+	})
+
+	t.Run("Only with deduplication on and the same profile version", func(t *testing.T) {
+		for _, job := range []*models.TranscriptionJob{newJob("no-dedup", false, version), newJob("new-version", true, version+1)} {
+			assert.Nil(t, service.findDuplicate(context.Background(), job), job.ID)
+			saved, err := jobRepo.FindByID(context.Background(), job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, sum, saved.AudioSHA256, "the audio is hashed either way")
+		}
+	})
+}
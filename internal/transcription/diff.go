@@ -0,0 +1,314 @@
+package transcription
+
+import (
+	"encoding/json"
+	"strings"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// maxCharDiffCells bounds the character edit distance table of a differing
+// stretch; longer stretches count every character of the longer side as an error
+const maxCharDiffCells = 25_000_000
+
+// Kinds of diff chunks
+const (
+	DiffEqual   = "equal"
+	DiffInsert  = "insert"  // words only the hypothesis has
+	DiffDelete  = "delete"  // words only the reference has
+	DiffReplace = "replace" // words the hypothesis has in place of the reference's
+)
+
+// DiffChunk is a run of words the two texts share, or differ in
+type DiffChunk struct {
+	Op         string `json:"op"`
+	Reference  string `json:"reference,omitempty"`
+	Hypothesis string `json:"hypothesis,omitempty"`
+}
+
+// DiffStats are the error rates of a hypothesis measured against a reference
+type DiffStats struct {
+	ReferenceWords  int     `json:"reference_words"`
+	HypothesisWords int     `json:"hypothesis_words"`
+	Substitutions   int     `json:"substitutions"`
+	Deletions       int     `json:"deletions"`
+	Insertions      int     `json:"insertions"`
+	WER             float64 `json:"wer"`
+	ReferenceChars  int     `json:"reference_chars"`
+	CharErrors      int     `json:"char_errors"`
+	CER             float64 `json:"cer"`
+}
+
+// TextDiff is a word-level diff of two texts with its error rates
+type TextDiff struct {
+	Stats  DiffStats   `json:"stats"`
+	Chunks []DiffChunk `json:"chunks"`
+}
+
+// DecodeTranscriptText returns the text of a transcript in the job transcript format
+func DecodeTranscriptText(encoded string) (string, error) {
+	var result interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(encoded), &result); err != nil {
+		return "", err
+	}
+	return transcriptText(&result), nil
+}
+
+// diffWord is a word of a text and how it is compared: lowercase, without the
+// punctuation around it
+type diffWord struct {
+	text string
+	key  string
+}
+
+func diffWords(text string) []diffWord {
+	var words []diffWord
+	for _, token := range strings.Fields(text) {
+		if key := filterKey(token); key != "" {
+			words = append(words, diffWord{text: token, key: key})
+		}
+	}
+	return words
+}
+
+// DiffTexts aligns the words of hypothesis with those of reference by minimum
+// edit distance, and measures its word and character error rates. Case and
+// punctuation are ignored. Character errors are counted within the stretches
+// the words differ in.
+func DiffTexts(reference, hypothesis string) *TextDiff {
+	ref, hyp := diffWords(reference), diffWords(hypothesis)
+	refKeys, hypKeys := make([]string, len(ref)), make([]string, len(hyp))
+	for i, w := range ref {
+		refKeys[i] = w.key
+	}
+	for j, w := range hyp {
+		hypKeys[j] = w.key
+	}
+
+	diff := &TextDiff{Chunks: []DiffChunk{}}
+	diff.Stats.ReferenceWords, diff.Stats.HypothesisWords = len(ref), len(hyp)
+	for i, key := range refKeys {
+		if i > 0 {
+			diff.Stats.ReferenceChars++ // the space between words
+		}
+		diff.Stats.ReferenceChars += len([]rune(key))
+	}
+
+	// Group the edits into chunks of equal and differing words
+	var refRun, hypRun []diffWord
+	flush := func() {
+		if len(refRun) == 0 && len(hypRun) == 0 {
+			return
+		}
+		chunk := DiffChunk{Reference: joinDiffWords(refRun), Hypothesis: joinDiffWords(hypRun)}
+		switch {
+		case len(hypRun) == 0:
+			chunk.Op = DiffDelete
+		case len(refRun) == 0:
+			chunk.Op = DiffInsert
+		default:
+			chunk.Op = DiffReplace
+		}
+		diff.Stats.CharErrors += charErrors(refRun, hypRun)
+		diff.Chunks = append(diff.Chunks, chunk)
+		refRun, hypRun = nil, nil
+	}
+	for _, e := range alignWords(refKeys, hypKeys) {
+		switch e.op {
+		case editMatch:
+			flush()
+			if n := len(diff.Chunks); n > 0 && diff.Chunks[n-1].Op == DiffEqual {
+				diff.Chunks[n-1].Reference += " " + ref[e.i].text
+				diff.Chunks[n-1].Hypothesis += " " + hyp[e.j].text
+			} else {
+				diff.Chunks = append(diff.Chunks, DiffChunk{Op: DiffEqual, Reference: ref[e.i].text, Hypothesis: hyp[e.j].text})
+			}
+			continue
+		case editSubstitute:
+			diff.Stats.Substitutions++
+			refRun, hypRun = append(refRun, ref[e.i]), append(hypRun, hyp[e.j])
+		case editDelete:
+			diff.Stats.Deletions++
+			refRun = append(refRun, ref[e.i])
+		case editInsert:
+			diff.Stats.Insertions++
+			hypRun = append(hypRun, hyp[e.j])
+		}
+	}
+	flush()
+
+	errors := diff.Stats.Substitutions + diff.Stats.Deletions + diff.Stats.Insertions
+	diff.Stats.WER = errorRate(errors, len(ref), len(hyp))
+	diff.Stats.CER = errorRate(diff.Stats.CharErrors, diff.Stats.ReferenceChars, len(hyp))
+	return diff
+}
+
+// errorRate divides errors by the size of the reference; against an empty
+// reference, any hypothesis is entirely wrong
+func errorRate(errors, reference, hypothesis int) float64 {
+	if reference == 0 {
+		if hypothesis == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(errors) / float64(reference)
+}
+
+func joinDiffWords(words []diffWord) string {
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.text
+	}
+	return strings.Join(texts, " ")
+}
+
+// charErrors returns the character edit distance between two differing runs
+// of words, compared as they are in the error rates
+func charErrors(ref, hyp []diffWord) int {
+	keys := func(words []diffWord) []string {
+		runes := []string{}
+		for i, w := range words {
+			if i > 0 {
+				runes = append(runes, " ")
+			}
+			for _, r := range w.key {
+				runes = append(runes, string(r))
+			}
+		}
+		return runes
+	}
+	a, b := keys(ref), keys(hyp)
+	if len(a)*len(b) > maxCharDiffCells {
+		if len(a) > len(b) {
+			return len(a)
+		}
+		return len(b)
+	}
+	return editDistances(a, b)[len(b)]
+}
+
+// Edit operations turning a reference into a hypothesis
+const (
+	editMatch = iota
+	editSubstitute
+	editDelete
+	editInsert
+)
+
+// edit is an operation on reference word i and hypothesis word j
+type edit struct {
+	op   int
+	i, j int
+}
+
+// editDistances returns the edit distances between a and each prefix of b,
+// keeping two rows of the table
+func editDistances(a, b []string) []int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			curr[j] = prev[j-1] // match
+			if a[i-1] != b[j-1] {
+				curr[j]++ // substitution
+			}
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1 // deletion
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1 // insertion
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev
+}
+
+// alignWords returns a minimum-cost alignment of a with b. Hirschberg's
+// algorithm keeps it to linear memory for transcripts of any length.
+func alignWords(a, b []string) []edit {
+	al := &aligner{a: a, b: b}
+	// Shared beginnings and endings, most of a revision, need no table
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	endA, endB := len(a), len(b)
+	for endA > start && endB > start && a[endA-1] == b[endB-1] {
+		endA--
+		endB--
+	}
+	for k := 0; k < start; k++ {
+		al.edits = append(al.edits, edit{op: editMatch, i: k, j: k})
+	}
+	al.align(start, endA, start, endB)
+	for k := 0; endA+k < len(a); k++ {
+		al.edits = append(al.edits, edit{op: editMatch, i: endA + k, j: endB + k})
+	}
+	return al.edits
+}
+
+type aligner struct {
+	a, b  []string
+	edits []edit
+}
+
+// align appends the alignment of a[a0:a1] with b[b0:b1]
+func (al *aligner) align(a0, a1, b0, b1 int) {
+	switch {
+	case a0 == a1:
+		for j := b0; j < b1; j++ {
+			al.edits = append(al.edits, edit{op: editInsert, i: a0, j: j})
+		}
+		return
+	case b0 == b1:
+		for i := a0; i < a1; i++ {
+			al.edits = append(al.edits, edit{op: editDelete, i: i, j: b0})
+		}
+		return
+	case a1-a0 == 1:
+		// A single word matches its first occurrence, or replaces the first word
+		match := -1
+		for j := b0; j < b1; j++ {
+			if al.b[j] == al.a[a0] {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			al.edits = append(al.edits, edit{op: editSubstitute, i: a0, j: b0})
+			al.align(a1, a1, b0+1, b1)
+			return
+		}
+		al.align(a0, a0, b0, match)
+		al.edits = append(al.edits, edit{op: editMatch, i: a0, j: match})
+		al.align(a1, a1, match+1, b1)
+		return
+	}
+
+	// Split b where the best alignments of each half of a meet
+	mid := (a0 + a1) / 2
+	left := editDistances(al.a[a0:mid], al.b[b0:b1])
+	right := editDistances(reverseWords(al.a[mid:a1]), reverseWords(al.b[b0:b1]))
+	split, best := 0, -1
+	for j := 0; j <= b1-b0; j++ {
+		if cost := left[j] + right[b1-b0-j]; best < 0 || cost < best {
+			split, best = j, cost
+		}
+	}
+	al.align(a0, mid, b0, b0+split)
+	al.align(mid, a1, b0+split, b1)
+}
+
+func reverseWords(words []string) []string {
+	reversed := make([]string, len(words))
+	for i, w := range words {
+		reversed[len(words)-1-i] = w
+	}
+	return reversed
+}
@@ -0,0 +1,120 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"scriberr/internal/models"
+)
+
+// DryRunResult is what processing a job would do, worked out without running
+// any adapter. A job with problems would fail, or wait, if it were queued.
+type DryRunResult struct {
+	TranscriptionModel string `json:"transcription_model"`
+	// DiarizationModel is set when diarization would run separately from transcription
+	DiarizationModel string   `json:"diarization_model,omitempty"`
+	FallbackModels   []string `json:"fallback_models,omitempty"`
+	AudioSeconds     float64  `json:"audio_seconds"`
+	// EstimatedProcessingSeconds is the adapters' own estimate of how long they take
+	EstimatedProcessingSeconds float64 `json:"estimated_processing_seconds"`
+	// EstimatedCost is nil when none of the adapters is priced or the audio
+	// could not be probed; audio voice activity detection would cut is still counted
+	EstimatedCost *float64 `json:"estimated_cost,omitempty"`
+	Chunked       bool     `json:"chunked"`
+	Problems      []string `json:"problems"`
+	Ready         bool     `json:"ready"`
+}
+
+// DryRun plans a job the way processing it would: it resolves its adapters and
+// their parameters, checks the adapters are registered and their environments
+// ready, probes its audio, and estimates how long it would take and what it
+// would cost. Nothing is run, saved or queued.
+func (u *UnifiedTranscriptionService) DryRun(ctx context.Context, job *models.TranscriptionJob) (*DryRunResult, error) {
+	result := &DryRunResult{Problems: []string{}}
+	problem := func(format string, args ...interface{}) {
+		result.Problems = append(result.Problems, fmt.Sprintf(format, args...))
+	}
+
+	// Every track of a multi-track job is its own input, transcribed without diarization
+	inputs := []string{job.AudioPath}
+	var modelIDs []string
+	if job.IsMultiTrack {
+		inputs = inputs[:0]
+		for _, track := range job.MultiTrackFiles {
+			inputs = append(inputs, track.FilePath)
+		}
+		if len(inputs) == 0 {
+			problem("the job has no tracks")
+		}
+		transcriptionModelID, _, err := u.selectModels(job.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		result.TranscriptionModel = transcriptionModelID
+		modelIDs = []string{transcriptionModelID}
+		u.checkDryRunModel(transcriptionModelID, u.convertParametersForModel(job.Parameters, transcriptionModelID), problem)
+	} else {
+		plan, err := u.planSingleTrack(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		result.TranscriptionModel = plan.transcriptionModelID
+		result.DiarizationModel = plan.diarizationModelID
+		result.Chunked = plan.chunking != nil
+		modelIDs = []string{plan.transcriptionModelID}
+		u.checkDryRunModel(plan.transcriptionModelID, plan.transcriptionParams, problem)
+		if plan.diarizationModelID != "" {
+			modelIDs = append(modelIDs, plan.diarizationModelID)
+			u.checkDryRunModel(plan.diarizationModelID, plan.diarizationParams, problem)
+		}
+		for _, family := range job.Parameters.FallbackChain() {
+			fallback, err := u.planFallback(ctx, job, family)
+			if err != nil {
+				problem("fallback %s: %v", family, err)
+				continue
+			}
+			result.FallbackModels = append(result.FallbackModels, fallback.transcriptionModelID)
+		}
+	}
+	if pending := u.registry.PendingEnvironments(modelIDs...); len(pending) > 0 {
+		problem("model environments are not ready: %s", strings.Join(pending, ", "))
+	}
+
+	probed := 0
+	for _, path := range inputs {
+		if _, err := os.Stat(path); err != nil {
+			problem("audio is not accessible: %v", err)
+			continue
+		}
+		input, err := u.createAudioInput(path)
+		if err != nil {
+			problem("audio cannot be read: %v", err)
+			continue
+		}
+		probed++
+		result.AudioSeconds += input.Duration.Seconds()
+		for _, modelID := range modelIDs {
+			if estimate, err := u.registry.GetEstimatedProcessingTime(modelID, input); err == nil {
+				result.EstimatedProcessingSeconds += estimate.Seconds()
+			}
+		}
+	}
+	// Audio that could not be probed cannot be costed
+	if probed == len(inputs) {
+		if cost, ok := u.costs.Estimate(result.AudioSeconds, modelIDs...); ok {
+			result.EstimatedCost = &cost
+		}
+	}
+	result.Ready = len(result.Problems) == 0
+	return result, nil
+}
+
+// checkDryRunModel reports a problem when an adapter is not registered or
+// rejects its parameters
+func (u *UnifiedTranscriptionService) checkDryRunModel(modelID string, params map[string]interface{}, problem func(string, ...interface{})) {
+	if err := u.registry.ValidateModelParameters(modelID, params); err != nil {
+		problem("%s: %v", modelID, err)
+	}
+}
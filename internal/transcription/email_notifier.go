@@ -0,0 +1,229 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/events"
+	"scriberr/internal/mail"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/service"
+	"scriberr/internal/webhook"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// summaryPollInterval is how often a completed job is checked for its
+	// automatic summary before it is emailed
+	summaryPollInterval = 15 * time.Second
+	// summaryWait bounds the wait for an automatic summary; the email is sent
+	// without one after that
+	summaryWait = 15 * time.Minute
+)
+
+// EmailNotifier emails the submitter of a job when it completes or fails,
+// according to their notification settings
+type EmailNotifier struct {
+	mailer        *mail.Sender
+	jobRepo       repository.JobRepository
+	userRepo      repository.UserRepository
+	publicBaseURL string
+
+	pollInterval time.Duration
+	maxWait      time.Duration
+	stop         chan struct{}
+	stopOnce     sync.Once
+	wg           sync.WaitGroup
+}
+
+// NewEmailNotifier creates a notifier linking jobs under publicBaseURL; without
+// an SMTP server it returns nil
+func NewEmailNotifier(mailer *mail.Sender, jobRepo repository.JobRepository, userRepo repository.UserRepository, publicBaseURL string) *EmailNotifier {
+	if !mailer.Enabled() {
+		return nil
+	}
+	return &EmailNotifier{
+		mailer:        mailer,
+		jobRepo:       jobRepo,
+		userRepo:      userRepo,
+		publicBaseURL: strings.TrimSuffix(publicBaseURL, "/"),
+		pollInterval:  summaryPollInterval,
+		maxWait:       summaryWait,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Name identifies the notifier in logs
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Send emails a completed or failed job to its submitter; other events are
+// not sent. A completed job whose profile generates a summary is emailed once
+// the summary is ready, in the background.
+func (n *EmailNotifier) Send(ctx context.Context, event events.Event) error {
+	if event.Type != events.JobCompleted && event.Type != events.JobFailed {
+		return nil
+	}
+	job, user, err := n.recipient(ctx, event)
+	if err != nil || user == nil {
+		return err
+	}
+
+	if event.Type == events.JobCompleted && awaitingSummary(job) {
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.sendAfterSummary(job.ID, user, event.Type)
+		}()
+		return nil
+	}
+	return n.send(ctx, job, user, event.Type)
+}
+
+// Stop abandons the emails still waiting for a summary
+func (n *EmailNotifier) Stop() {
+	n.stopOnce.Do(func() { close(n.stop) })
+	n.wg.Wait()
+}
+
+// recipient loads the job of an event and the user to email about it, or a
+// nil user when its submitter does not want this email
+func (n *EmailNotifier) recipient(ctx context.Context, event events.Event) (*models.TranscriptionJob, *models.User, error) {
+	job, err := n.jobRepo.FindByID(ctx, event.Job.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load job: %w", err)
+	}
+	if job.UserID == nil {
+		return job, nil, nil
+	}
+	user, err := n.userRepo.FindByID(ctx, *job.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if user.NotificationEmail == "" ||
+		(event.Type == events.JobCompleted && !user.NotifyOnCompleted) ||
+		(event.Type == events.JobFailed && !user.NotifyOnFailed) {
+		return job, nil, nil
+	}
+	return job, user, nil
+}
+
+// awaitingSummary reports whether post-processing will still add a summary
+// to a completed job
+func awaitingSummary(job *models.TranscriptionJob) bool {
+	return job.Parameters.AutoSummaryTemplateID != nil && *job.Parameters.AutoSummaryTemplateID != "" &&
+		job.PostProcessedAt == nil && job.FinalizedAt == nil
+}
+
+// sendAfterSummary polls the job until it is post-processed, or maxWait has
+// passed, and emails it
+func (n *EmailNotifier) sendAfterSummary(jobID string, user *models.User, eventType string) {
+	ticker := time.NewTicker(n.pollInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(n.maxWait)
+
+	for {
+		select {
+		case <-n.stop:
+			logger.Warn("Email notification abandoned while waiting for the summary", "job_id", jobID)
+			return
+		case <-ticker.C:
+		}
+		job, err := n.jobRepo.FindByID(context.Background(), jobID)
+		if err != nil {
+			logger.Warn("Email notification abandoned, job not found", "job_id", jobID, "error", err)
+			return
+		}
+		if awaitingSummary(job) && time.Now().Before(deadline) {
+			continue
+		}
+		if err := n.send(context.Background(), job, user, eventType); err != nil {
+			logger.Warn("Failed to send email notification", "job_id", jobID, "error", err)
+		}
+		return
+	}
+}
+
+// send renders the email for a job and delivers it to the user
+func (n *EmailNotifier) send(ctx context.Context, job *models.TranscriptionJob, user *models.User, eventType string) error {
+	title := getJobName(*job)
+	subject := "Transcription completed: " + title
+	status := "COMPLETED"
+	if eventType == events.JobFailed {
+		subject = "Transcription failed: " + title
+		status = "FAILED"
+	}
+
+	data := webhook.NewTemplateData(job, status)
+	if n.publicBaseURL != "" {
+		data.Link = n.publicBaseURL + "/audio/" + job.ID
+	}
+	var body string
+	if tmpl := notificationTemplate(ctx, models.NotificationChannelEmail); tmpl != nil {
+		rendered, err := webhook.Render(tmpl, data)
+		if err != nil {
+			return fmt.Errorf("failed to render email template: %w", err)
+		}
+		body = string(rendered)
+	} else {
+		body = emailBody(title, data)
+	}
+
+	var attachments []mail.Attachment
+	if user.NotifyAttachTranscript && eventType == events.JobCompleted {
+		if text := service.TranscriptPlainText(job.Transcript); text != "" {
+			attachments = append(attachments, mail.Attachment{
+				Filename:    attachmentName(title) + ".txt",
+				ContentType: "text/plain; charset=utf-8",
+				Data:        []byte(text + "\n"),
+			})
+		}
+	}
+
+	if err := n.mailer.Send(ctx, []string{user.NotificationEmail}, subject, body, attachments...); err != nil {
+		return err
+	}
+	logger.Info("Sent email notification", "job_id", job.ID, "user_id", user.ID, "event", eventType)
+	return nil
+}
+
+// emailBody is the built-in message, used without an email template
+func emailBody(title string, data webhook.TemplateData) string {
+	var b strings.Builder
+	if data.Event == "FAILED" {
+		fmt.Fprintf(&b, "Your transcription %q failed.\n", title)
+		if data.ErrorMessage != "" {
+			fmt.Fprintf(&b, "\nError: %s\n", data.ErrorMessage)
+		}
+	} else {
+		fmt.Fprintf(&b, "Your transcription %q is ready.\n", title)
+		if summary := strings.TrimSpace(data.Summary); summary != "" {
+			fmt.Fprintf(&b, "\nSummary:\n%s\n", summary)
+		}
+	}
+	if data.Link != "" {
+		fmt.Fprintf(&b, "\nOpen it in Scriberr: %s\n", data.Link)
+	}
+	return b.String()
+}
+
+// attachmentName makes a job title usable as a file name
+func attachmentName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r == '"' || r == '/' || r == '\\' || r < 0x20:
+			return '_'
+		default:
+			return r
+		}
+	}, strings.TrimSpace(title))
+	if name == "" {
+		return "transcript"
+	}
+	return name
+}
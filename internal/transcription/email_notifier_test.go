@@ -0,0 +1,173 @@
+package transcription
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/events"
+	"scriberr/internal/mail"
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// smtpMessage is a message received by fakeSMTPServer
+type smtpMessage struct {
+	To   string
+	Data string
+}
+
+// fakeSMTPServer accepts messages without authentication or TLS, handing each
+// to the returned channel
+func fakeSMTPServer(t *testing.T) (string, int, <-chan smtpMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	messages := make(chan smtpMessage, 10)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+				reply("220 localhost ESMTP")
+				var message smtpMessage
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(strings.TrimSpace(line))
+					switch {
+					case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+						reply("250 localhost")
+					case strings.HasPrefix(command, "RCPT TO:"):
+						message.To = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+						reply("250 OK")
+					case command == "DATA":
+						reply("354 Go ahead")
+						var data strings.Builder
+						for {
+							line, err := reader.ReadString('\n')
+							if err != nil {
+								return
+							}
+							if line == ".\r\n" {
+								break
+							}
+							data.WriteString(line)
+						}
+						message.Data = data.String()
+						messages <- message
+						reply("250 OK")
+					case command == "QUIT":
+						reply("221 Bye")
+						return
+					default:
+						reply("250 OK")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return host, portNumber, messages
+}
+
+func TestEmailNotifier(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TranscriptionJob{}, &models.User{}))
+	jobRepo := repository.NewJobRepository(db)
+	userRepo := repository.NewUserRepository(db)
+
+	host, port, messages := fakeSMTPServer(t)
+	mailer := mail.NewSender(mail.Config{Host: host, Port: port, From: "scriberr@example.com"})
+	notifier := NewEmailNotifier(mailer, jobRepo, userRepo, "https://scriberr.example.com/")
+	require.NotNil(t, notifier)
+	defer notifier.Stop()
+
+	user := &models.User{Username: "alice", Password: "x", NotificationEmail: "alice@example.com", NotifyAttachTranscript: true}
+	require.NoError(t, db.Create(user).Error)
+	require.NoError(t, db.Model(user).Update("notify_on_failed", false).Error)
+
+	title := "Standup"
+	summary := "We agreed to ship on Friday."
+	transcript := `{"text":"Ship it","segments":[{"text":" Ship it on Friday. ","speaker":"SPEAKER_00"}]}`
+	completed := &models.TranscriptionJob{ID: "job-1", Title: &title, AudioPath: "a.wav", Status: models.StatusCompleted, UserID: &user.ID, Summary: &summary, Transcript: &transcript}
+	require.NoError(t, db.Create(completed).Error)
+
+	t.Run("Emails a completed job with its summary, link and transcript", func(t *testing.T) {
+		require.NoError(t, notifier.Send(context.Background(), events.NewJobEvent(events.JobCompleted, completed)))
+		message := <-messages
+		assert.Equal(t, "alice@example.com", message.To)
+		assert.Contains(t, message.Data, "Subject: Transcription completed: Standup")
+		assert.Contains(t, message.Data, summary)
+		assert.Contains(t, message.Data, "https://scriberr.example.com/audio/job-1")
+		assert.Contains(t, message.Data, `filename=Standup.txt`)
+		assert.Contains(t, message.Data, base64.StdEncoding.EncodeToString([]byte("SPEAKER_00: Ship it on Friday.\n")))
+	})
+
+	t.Run("Respects the user's preferences", func(t *testing.T) {
+		failed := &models.TranscriptionJob{ID: "job-2", AudioPath: "b.wav", Status: models.StatusFailed, UserID: &user.ID}
+		require.NoError(t, db.Create(failed).Error)
+		anonymous := &models.TranscriptionJob{ID: "job-3", AudioPath: "c.wav", Status: models.StatusCompleted}
+		require.NoError(t, db.Create(anonymous).Error)
+
+		require.NoError(t, notifier.Send(context.Background(), events.NewJobEvent(events.JobFailed, failed)))
+		require.NoError(t, notifier.Send(context.Background(), events.NewJobEvent(events.JobCompleted, anonymous)))
+		require.NoError(t, notifier.Send(context.Background(), events.NewJobEvent(events.JobProcessing, completed)))
+		select {
+		case message := <-messages:
+			t.Fatalf("unexpected email: %s", message.Data)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("Waits for the automatic summary", func(t *testing.T) {
+		notifier.pollInterval = 10 * time.Millisecond
+		templateID := "template-1"
+		pending := &models.TranscriptionJob{ID: "job-4", AudioPath: "d.wav", Status: models.StatusCompleted, UserID: &user.ID}
+		pending.Parameters.AutoSummaryTemplateID = &templateID
+		require.NoError(t, db.Create(pending).Error)
+
+		require.NoError(t, notifier.Send(context.Background(), events.NewJobEvent(events.JobCompleted, pending)))
+		select {
+		case message := <-messages:
+			t.Fatalf("emailed before the summary: %s", message.Data)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		require.NoError(t, db.Model(pending).Updates(map[string]interface{}{"summary": "Generated summary", "post_processed_at": time.Now()}).Error)
+		select {
+		case message := <-messages:
+			assert.Contains(t, message.Data, "Generated summary")
+		case <-time.After(2 * time.Second):
+			t.Fatal("no email after the summary was generated")
+		}
+	})
+
+	t.Run("Disabled without SMTP", func(t *testing.T) {
+		assert.Nil(t, NewEmailNotifier(mail.NewSender(mail.Config{}), jobRepo, userRepo, ""))
+	})
+}
@@ -0,0 +1,108 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// runWithFailover runs a single-track job's plan and, when it fails or runs
+// past the job's adapter timeout, the plans of the job's fallback model
+// families in order. It returns the result with the plan that produced it, or
+// the last plan tried with its error, and records on execution which adapter
+// produced the result and which failed before it.
+func (u *UnifiedTranscriptionService) runWithFailover(ctx context.Context, job *models.TranscriptionJob, plan *singleTrackPlan, procCtx interfaces.ProcessingContext, execution *models.TranscriptionJobExecution) (*interfaces.TranscriptResult, *singleTrackPlan, error) {
+	var attempts []models.AdapterAttempt
+	defer func() {
+		if len(attempts) == 0 {
+			return
+		}
+		if data, err := json.Marshal(attempts); err == nil {
+			encoded := string(data)
+			execution.FailedAdapters = &encoded
+		}
+	}()
+
+	timeout := time.Duration(job.Parameters.AdapterTimeoutSeconds) * time.Second
+	families := append([]string{job.Parameters.ModelFamily}, job.Parameters.FallbackChain()...)
+	var lastErr error
+	for i, family := range families {
+		if i > 0 {
+			// A killed job is not failed over
+			if ctx.Err() != nil {
+				break
+			}
+			next, err := u.planFallback(ctx, job, family)
+			if err != nil {
+				attempts = append(attempts, models.AdapterAttempt{ModelFamily: family, Error: err.Error()})
+				continue
+			}
+			logger.Warn("Failing over to the next adapter", "job_id", job.ID, "model_family", family,
+				"model_id", next.transcriptionModelID, "error", lastErr)
+			plan = next
+			execution.ActualParameters.ModelFamily = family
+		}
+
+		started := time.Now()
+		result, err := u.runAttempt(ctx, job.AudioPath, plan, procCtx, timeout)
+		if err == nil {
+			if plan.transcriptionModelID != "" {
+				execution.Adapter = &plan.transcriptionModelID
+			}
+			return result, plan, nil
+		}
+		lastErr = err
+		attempts = append(attempts, models.AdapterAttempt{
+			ModelFamily: family,
+			ModelID:     plan.transcriptionModelID,
+			Error:       err.Error(),
+			Duration:    time.Since(started).Milliseconds(),
+		})
+	}
+	if lastErr == nil {
+		lastErr = ctx.Err()
+	}
+	return nil, plan, lastErr
+}
+
+// planFallback plans a job as if it had been submitted with a fallback model family
+func (u *UnifiedTranscriptionService) planFallback(ctx context.Context, job *models.TranscriptionJob, family string) (*singleTrackPlan, error) {
+	if !KnownModelFamily(family) {
+		return nil, fmt.Errorf("unknown model family %q", family)
+	}
+	fallback := *job
+	fallback.Parameters.ModelFamily = family
+	plan, err := u.planSingleTrack(ctx, &fallback)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.registry.GetTranscriptionAdapter(plan.transcriptionModelID); err != nil {
+		return nil, fmt.Errorf("adapter %s is not available", plan.transcriptionModelID)
+	}
+	if pending := u.registry.PendingEnvironments(plan.transcriptionModelID, plan.diarizationModelID); len(pending) > 0 {
+		return nil, fmt.Errorf("model environments are not ready: %s", strings.Join(pending, ", "))
+	}
+	return plan, nil
+}
+
+// runAttempt runs a plan, giving up after timeout if it is set
+func (u *UnifiedTranscriptionService) runAttempt(ctx context.Context, audioPath string, plan *singleTrackPlan, procCtx interfaces.ProcessingContext, timeout time.Duration) (*interfaces.TranscriptResult, error) {
+	if timeout <= 0 {
+		return u.runSingleTrack(ctx, audioPath, plan, procCtx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := u.runSingleTrack(attemptCtx, audioPath, plan, procCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return nil, models.WithErrorCode(models.ErrorAdapterTimeout,
+			fmt.Errorf("%s timed out after %s: %w", plan.transcriptionModelID, timeout, err))
+	}
+	return result, err
+}
@@ -0,0 +1,134 @@
+package transcription
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+)
+
+// fastModelID is the adapter short clips are transcribed with, locally so
+// nothing is uploaded to a remote worker or storage
+const fastModelID = "whisperx"
+
+var (
+	// ErrClipTooLong is returned for clips longer than the fast path takes
+	ErrClipTooLong = errors.New("clip is too long for fast transcription")
+	// ErrFastModelNotReady is returned while the fast path's environment is
+	// still being installed
+	ErrFastModelNotReady = errors.New("fast transcription model is not ready")
+)
+
+// fastPlan is the plan for a short clip: the small model alone, without
+// diarization, preprocessing or chunking. The language and task are the
+// caller's.
+func (u *UnifiedTranscriptionService) fastPlan(params models.WhisperXParams, model string) *singleTrackPlan {
+	params.ModelFamily = "whisper"
+	params.Model = model
+	params.Diarize = false
+	params.SpeakerEmbeddings = false
+	params.ReturnCharAlignments = false
+	return &singleTrackPlan{
+		transcriptionModelID: fastModelID,
+		transcriptionParams:  u.convertParametersForModel(params, fastModelID),
+	}
+}
+
+// TranscribeClip transcribes a clip of at most maxDuration synchronously with
+// the small model. Nothing is written to the database or storage; the
+// adapter's scratch files are removed before it returns.
+func (u *UnifiedTranscriptionService) TranscribeClip(ctx context.Context, audioPath string, params models.WhisperXParams, model string, maxDuration time.Duration) (*interfaces.TranscriptResult, error) {
+	ctx, span := tracing.Start(ctx, "transcription.fast", tracing.String("fast.model", model))
+	defer span.End()
+
+	input, err := u.createAudioInput(audioPath)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to create audio input: %w", err)
+	}
+	if input.Duration > maxDuration {
+		return nil, fmt.Errorf("%w: %s is over %s", ErrClipTooLong, input.Duration.Round(time.Second), maxDuration)
+	}
+	if pending := u.registry.PendingEnvironments(fastModelID); len(pending) > 0 {
+		return nil, ErrFastModelNotReady
+	}
+	if err := u.Initialize(ctx); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("env setup failed: %w", err)
+	}
+
+	if err := os.MkdirAll(u.tempDirectory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	sandbox, err := os.MkdirTemp(u.tempDirectory, "fast-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fast transcription directory: %w", err)
+	}
+	defer os.RemoveAll(sandbox)
+
+	procCtx := interfaces.ProcessingContext{
+		JobID:           strings.TrimPrefix(filepath.Base(sandbox), "fast-"),
+		OutputDirectory: sandbox,
+		TempDirectory:   sandbox,
+		Metadata:        map[string]string{"fast": "true"},
+	}
+	result, err := u.runAudio(ctx, input, u.fastPlan(params, model), procCtx)
+	span.RecordError(err)
+	return result, err
+}
+
+// WarmFastModel transcribes a second of silence with the small model so its
+// weights are downloaded and cached before the first clip arrives
+func (u *UnifiedTranscriptionService) WarmFastModel(ctx context.Context, model string) error {
+	if err := os.MkdirAll(u.tempDirectory, 0755); err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	path := filepath.Join(u.tempDirectory, "fast-warmup.wav")
+	if err := writeSilence(path, time.Second); err != nil {
+		return fmt.Errorf("failed to write warm-up audio: %w", err)
+	}
+	defer os.Remove(path)
+
+	start := time.Now()
+	if _, err := u.TranscribeClip(ctx, path, models.WhisperXParams{Task: "transcribe"}, model, time.Minute); err != nil {
+		return err
+	}
+	logger.Info("Fast transcription model warmed up", "model", model, "duration", time.Since(start))
+	return nil
+}
+
+// writeSilence writes duration of 16kHz mono 16-bit silence as a wav file
+func writeSilence(path string, duration time.Duration) error {
+	const sampleRate = 16000
+	dataSize := uint32(duration.Seconds()*sampleRate) * 2
+	header := []interface{}{
+		[4]byte{'R', 'I', 'F', 'F'}, 36 + dataSize, [4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '}, uint32(16), uint16(1), uint16(1),
+		uint32(sampleRate), uint32(sampleRate * 2), uint16(2), uint16(16),
+		[4]byte{'d', 'a', 't', 'a'}, dataSize,
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, field := range header {
+		if err := binary.Write(f, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Write(make([]byte, dataSize)); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package transcription
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+func TestFastPlan(t *testing.T) {
+	u := &UnifiedTranscriptionService{}
+	language := "de"
+	plan := u.fastPlan(models.WhisperXParams{
+		ModelFamily:   "openai",
+		Model:         "large-v3",
+		Diarize:       true,
+		Language:      &language,
+		Task:          "transcribe",
+		PreprocessVAD: true,
+	}, "base")
+
+	if plan.transcriptionModelID != fastModelID {
+		t.Errorf("model ID = %q, want %q", plan.transcriptionModelID, fastModelID)
+	}
+	if plan.diarizationModelID != "" || plan.preprocessing != nil || plan.chunking != nil {
+		t.Errorf("plan runs more than transcription: %+v", plan)
+	}
+	if got := plan.transcriptionParams["model"]; got != "base" {
+		t.Errorf("model = %v, want base", got)
+	}
+	if got := plan.transcriptionParams["diarize"]; got != false {
+		t.Errorf("diarize = %v, want false", got)
+	}
+	if got := plan.transcriptionParams["language"]; got != "de" {
+		t.Errorf("language = %v, want de", got)
+	}
+}
+
+func TestWriteSilence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silence.wav")
+	if err := writeSilence(path, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 44+32000 {
+		t.Errorf("size = %d, want %d", len(data), 44+32000)
+	}
+	if string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" || string(data[36:40]) != "data" {
+		t.Errorf("bad header %q", data[:44])
+	}
+}
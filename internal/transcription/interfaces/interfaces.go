@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"scriberr/internal/models"
@@ -11,6 +12,18 @@ const ModalWhisperX = "modal-whisperx"
 const RunPodWhisperX = "runpod-whisperx"
 const LocalWhisperX = "local-whisperx"
 
+// MetadataAlignmentFallback is set in TranscriptResult.Metadata, with the reason, when
+// word-level alignment was skipped and the timestamps are segment-level only
+const MetadataAlignmentFallback = "alignment_fallback"
+
+// MetadataSkippedSeconds is set in TranscriptResult.Metadata to how many seconds
+// of silence and music voice activity detection cut from the audio
+const MetadataSkippedSeconds = "skipped_seconds"
+
+// ErrInvalidParameters is returned (wrapped) by adapters given parameters
+// they cannot run with
+var ErrInvalidParameters = errors.New("invalid parameters")
+
 // ModelCapabilities describes what a model can do and its requirements
 type ModelCapabilities struct {
 	ModelID            string            `json:"model_id"`
@@ -66,6 +79,10 @@ type TranscriptSegment struct {
 	Text     string  `json:"text"`
 	Speaker  *string `json:"speaker,omitempty"`
 	Language *string `json:"language,omitempty"`
+	// Confidence is the mean score of the segment's scored words, and
+	// LowConfidence flags it for review when that is below the threshold
+	Confidence    *float64 `json:"confidence,omitempty"`
+	LowConfidence bool     `json:"low_confidence,omitempty"`
 }
 
 // TranscriptWord represents word-level timing information
@@ -75,6 +92,9 @@ type TranscriptWord struct {
 	Word    string  `json:"word"`
 	Score   float64 `json:"score"`
 	Speaker *string `json:"speaker,omitempty"`
+	// VocabularyFilterMatch flags a word of the job's vocabulary filter when
+	// its method is tag
+	VocabularyFilterMatch bool `json:"vocabulary_filter_match,omitempty"`
 }
 
 // TranscriptResult represents the output of transcription
@@ -84,9 +104,12 @@ type TranscriptResult struct {
 	Segments       []TranscriptSegment `json:"segments"`
 	WordSegments   []TranscriptWord    `json:"word_segments,omitempty"`
 	Confidence     float64             `json:"confidence"`
-	ProcessingTime time.Duration       `json:"processing_time"`
+	ProcessingTime time.Duration       `json:"processing_time" swaggertype:"integer"`
 	ModelUsed      string              `json:"model_used"`
 	Metadata       map[string]string   `json:"metadata"`
+	// SpeakerEmbeddings holds each diarized speaker's voice embedding when
+	// requested; it is stored apart from the transcript
+	SpeakerEmbeddings map[string][]float64 `json:"-"`
 }
 
 // DiarizationSegment represents speaker diarization information
@@ -105,6 +128,8 @@ type DiarizationResult struct {
 	ProcessingTime time.Duration        `json:"processing_time"`
 	ModelUsed      string               `json:"model_used"`
 	Metadata       map[string]string    `json:"metadata"`
+	// SpeakerEmbeddings holds each speaker's voice embedding when requested
+	SpeakerEmbeddings map[string][]float64 `json:"speaker_embeddings,omitempty"`
 }
 
 // ProcessingContext contains context information for processing
@@ -114,6 +139,32 @@ type ProcessingContext struct {
 	OutputDirectory string            `json:"output_directory"`
 	TempDirectory   string            `json:"temp_directory"`
 	Metadata        map[string]string `json:"metadata"`
+	// AudioURI is the s3:// object the job's audio was fetched from, if any,
+	// which remote workers can download themselves
+	AudioURI string `json:"audio_uri,omitempty"`
+	// Progress, if set, is told how far the adapter has got
+	Progress ProgressFunc `json:"-"`
+}
+
+// Progress stages adapters report
+const (
+	ProgressConverting   = "converting"   // preparing the audio
+	ProgressQueued       = "queued"       // waiting for a remote worker
+	ProgressRunning      = "running"      // running, how far along unknown
+	ProgressTranscribing = "transcribing" // speech recognition
+	ProgressAligning     = "aligning"     // word-level alignment
+	ProgressDiarizing    = "diarizing"    // speaker diarization
+)
+
+// ProgressFunc receives an adapter's progress: the stage it is in and the
+// percentage, 0-100, of its work done, or -1 when that is not known
+type ProgressFunc func(stage string, percent float64)
+
+// ReportProgress passes progress on to the context's ProgressFunc, if any
+func (p ProcessingContext) ReportProgress(stage string, percent float64) {
+	if p.Progress != nil {
+		p.Progress(stage, percent)
+	}
 }
 
 // ModelAdapter is the base interface that all model adapters must implement
@@ -174,6 +225,57 @@ type CompositeAdapter interface {
 	ProcessCombined(ctx context.Context, input AudioInput, params map[string]interface{}, procCtx ProcessingContext) (*TranscriptResult, *DiarizationResult, error)
 }
 
+// EnvironmentFingerprinter is implemented by adapters that can fingerprint the
+// environment they run in, so a replayed job can tell whether it changed
+type EnvironmentFingerprinter interface {
+	EnvironmentHash() string
+}
+
+// ComponentReporter is implemented by adapters that can name what a call runs
+// on: the versions of the Python packages transcripts depend on in their
+// environment, and the model checkpoints loaded for the given parameters
+type ComponentReporter interface {
+	PackageVersions() map[string]string
+	Checkpoints(params map[string]interface{}) []string
+}
+
+// SpeakerTurn is a stretch of audio spoken by one diarized speaker
+type SpeakerTurn struct {
+	Speaker string  `json:"speaker"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+}
+
+// SpeakerVoiceEstimate is a model's estimate of one speaker's voice: the age in
+// years and the probabilities of a female, male or child voice
+type SpeakerVoiceEstimate struct {
+	Age           float64 `json:"age"`
+	Female        float64 `json:"female"`
+	Male          float64 `json:"male"`
+	Child         float64 `json:"child"`
+	SpeechSeconds float64 `json:"speech_seconds"`
+}
+
+// SpeakerAttributeEstimator is implemented by adapters that can estimate coarse
+// speaker attributes from the speakers' voices
+type SpeakerAttributeEstimator interface {
+	// EstimateSpeakerAttributes estimates each speaker's voice from their turns,
+	// returning the estimates by speaker and the model used
+	EstimateSpeakerAttributes(ctx context.Context, input AudioInput, turns []SpeakerTurn, procCtx ProcessingContext) (map[string]SpeakerVoiceEstimate, string, error)
+}
+
+// SpeechRegion is a stretch of audio containing speech, in seconds
+type SpeechRegion struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// VoiceActivityDetector is implemented by adapters that can find where in the
+// audio there is speech, as opposed to silence, noise or music
+type VoiceActivityDetector interface {
+	DetectSpeech(ctx context.Context, input AudioInput, procCtx ProcessingContext) ([]SpeechRegion, error)
+}
+
 // ModelRequirements specifies what capabilities are needed for a job
 type ModelRequirements struct {
 	Language          string            `json:"language"`
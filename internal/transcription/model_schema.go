@@ -0,0 +1,55 @@
+package transcription
+
+import (
+	"sort"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Adapter kinds of a model schema
+const (
+	ModelKindTranscription = "transcription"
+	ModelKindDiarization   = "diarization"
+	ModelKindComposite     = "composite"
+)
+
+// ModelSchema describes the parameters a registered adapter accepts, so
+// clients can build their forms from it
+type ModelSchema struct {
+	ModelID     string `json:"model_id"`
+	Kind        string `json:"kind"` // transcription, diarization or composite
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	// ModelFamilies are the profile model_family values that select the adapter
+	ModelFamilies []string                     `json:"model_families"`
+	Parameters    []interfaces.ParameterSchema `json:"parameters"`
+}
+
+// GetModelSchema returns the parameter schema of a registered adapter
+func (u *UnifiedTranscriptionService) GetModelSchema(modelID string) (*ModelSchema, error) {
+	parameters, err := u.registry.GetParameterSchema(modelID)
+	if err != nil {
+		return nil, err
+	}
+	schema := &ModelSchema{ModelID: modelID, ModelFamilies: []string{}, Parameters: parameters}
+	if schema.Parameters == nil {
+		schema.Parameters = []interfaces.ParameterSchema{}
+	}
+	if capabilities, err := u.registry.GetCapabilities(modelID); err == nil {
+		schema.DisplayName = capabilities.DisplayName
+		schema.Description = capabilities.Description
+	}
+	schema.Kind = ModelKindDiarization
+	if _, err := u.registry.GetCompositeAdapter(modelID); err == nil {
+		schema.Kind = ModelKindComposite
+	} else if _, err := u.registry.GetTranscriptionAdapter(modelID); err == nil {
+		schema.Kind = ModelKindTranscription
+	}
+	for family, familyModelID := range modelFamilies {
+		if familyModelID == modelID {
+			schema.ModelFamilies = append(schema.ModelFamilies, family)
+		}
+	}
+	sort.Strings(schema.ModelFamilies)
+	return schema, nil
+}
@@ -148,7 +148,7 @@ func (mt *MultiTrackTranscriber) ProcessMultiTrackTranscription(ctx context.Cont
 		// Create track transcript with metadata
 		trackTranscript := TrackTranscript{
 			FileName: trackFile.FileName,
-			Speaker:  getBaseFileName(trackFile.FileName), // Use filename as speaker name
+			Speaker:  trackSpeaker(trackFile),
 			Offset:   trackFile.Offset,
 			Result:   trackResult,
 		}
@@ -224,7 +224,14 @@ func (mt *MultiTrackTranscriber) ProcessMultiTrackTranscription(ctx context.Cont
 // transcribeIndividualTrack transcribes a single track file using the direct transcription method
 func (mt *MultiTrackTranscriber) transcribeIndividualTrack(ctx context.Context, job *models.TranscriptionJob, trackFile *models.MultiTrackFile) (*interfaces.TranscriptResult, error) {
 	// Create a proper copy of parameters for this track (disable diarization, enable word timestamps)
-	trackParams := job.Parameters
+	overrides, err := trackFile.ParameterOverrides()
+	if err != nil {
+		return nil, err
+	}
+	trackParams, err := job.Parameters.WithOverrides(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("track %s: %w", trackFile.FileName, err)
+	}
 
 	// Ensure essential fields are properly set for individual track processing
 	trackParams.Diarize = false             // Never diarize individual tracks
@@ -298,7 +305,7 @@ func (mt *MultiTrackTranscriber) transcribeIndividualTrack(ctx context.Context,
 	default:
 	}
 	
-	err := mt.unifiedProcessor.ProcessJob(ctx, trackJobID)
+	err = mt.unifiedProcessor.ProcessJob(ctx, trackJobID)
 	if err != nil {
 		// Clean up temp job and associated records
 		mt.cleanupTempJob(trackJobID)
@@ -390,6 +397,7 @@ func (mt *MultiTrackTranscriber) TerminateMultiTrackJob(jobID string) error {
 		Updates(map[string]interface{}{
 			"status": models.StatusFailed,
 			"error_message": "Job was terminated by user",
+			"error_code": models.ErrorJobCancelled,
 		}).Error; err != nil {
 		logger.Warn("Failed to update main job status after termination", "job_id", jobID, "error", err)
 	}
@@ -516,6 +524,15 @@ func (mt *MultiTrackTranscriber) mergeTrackTranscripts(trackTranscripts []TrackT
 	return mergedResult, nil
 }
 
+// trackSpeaker is the speaker a track's words are attributed to: the name it
+// was uploaded with, or else its file name
+func trackSpeaker(trackFile models.MultiTrackFile) string {
+	if trackFile.TrackName != "" {
+		return trackFile.TrackName
+	}
+	return getBaseFileName(trackFile.FileName)
+}
+
 // getBaseFileName extracts the filename without extension to use as speaker name
 func getBaseFileName(filename string) string {
 	base := filepath.Base(filename)
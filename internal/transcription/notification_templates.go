@@ -0,0 +1,26 @@
+package transcription
+
+import (
+	"context"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+// notificationTemplate returns the admin's template for a channel, or nil to
+// send the built-in message
+func notificationTemplate(ctx context.Context, channel string) *models.NotificationTemplate {
+	if database.DB == nil {
+		return nil
+	}
+	var templates []models.NotificationTemplate
+	if err := database.DB.WithContext(ctx).Where("channel = ?", channel).Limit(1).Find(&templates).Error; err != nil {
+		logger.Warn("Failed to load notification template, sending the built-in message", "channel", channel, "error", err)
+		return nil
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+	return &templates[0]
+}
@@ -0,0 +1,201 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// chunkSilenceThreshold and chunkSilenceMinDuration are the pauses long
+	// audio may be split at; shorter than what trimming looks for, since any
+	// gap between words will do
+	chunkSilenceThreshold   = "-35dB"
+	chunkSilenceMinDuration = 0.3
+)
+
+// Chunk is a stretch of long audio transcribed on its own. Audio is cut from
+// Start to End, overlapping the neighbouring chunks; the chunk owns the part
+// from From to To, and the segments whose midpoint falls within it.
+type Chunk struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+}
+
+// owns reports whether the chunk owns the stretch from start to end; last is
+// set on the final chunk, which also owns what runs past its end
+func (c Chunk) owns(start, end float64, last bool) bool {
+	mid := (start + end) / 2
+	return mid >= c.From && (mid < c.To || last)
+}
+
+// PlanChunks splits audio into chunks of about size seconds, cutting in the
+// pauses nearest each boundary and overlapping neighbouring chunks by overlap
+// seconds. Audio no longer than size is a single chunk.
+func PlanChunks(ctx context.Context, input interfaces.AudioInput, size, overlap float64) ([]Chunk, error) {
+	duration := input.Duration.Seconds()
+	if duration <= size {
+		return planChunks(nil, duration, size, overlap), nil
+	}
+	silences, err := detectSilences(ctx, input.FilePath, chunkSilenceThreshold, chunkSilenceMinDuration)
+	if err != nil {
+		return nil, err
+	}
+	return planChunks(silences, duration, size, overlap), nil
+}
+
+// planChunks cuts duration seconds into chunks at the middle of the silence
+// nearest each size-second boundary, within a quarter chunk of it, or at the
+// boundary when there is none. A final chunk of up to a quarter more than
+// size is left whole rather than split off into a short one.
+func planChunks(silences []silence, duration, size, overlap float64) []Chunk {
+	bounds := []float64{0}
+	if size > 0 {
+		window := size / 4
+		for pos := 0.0; duration-pos > size+window; pos = bounds[len(bounds)-1] {
+			target := pos + size
+			cut, best := target, window
+			for _, s := range silences {
+				if !s.ended {
+					continue
+				}
+				if mid := (s.start + s.end) / 2; math.Abs(mid-target) <= best {
+					cut, best = mid, math.Abs(mid-target)
+				}
+			}
+			bounds = append(bounds, cut)
+		}
+	}
+	bounds = append(bounds, duration)
+
+	chunks := make([]Chunk, 0, len(bounds)-1)
+	for i := 0; i < len(bounds)-1; i++ {
+		from, to := bounds[i], bounds[i+1]
+		chunks = append(chunks, Chunk{
+			Start: max(from-overlap, 0),
+			End:   min(to+overlap, duration),
+			From:  from,
+			To:    to,
+		})
+	}
+	return chunks
+}
+
+// SplitAudio cuts each chunk out of input into a wav file in outputDir. The
+// files are temporary and the caller removes them.
+func SplitAudio(ctx context.Context, input interfaces.AudioInput, chunks []Chunk, outputDir string) ([]interfaces.AudioInput, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create chunk directory: %w", err)
+	}
+
+	inputs := make([]interfaces.AudioInput, 0, len(chunks))
+	cleanup := func() {
+		for _, in := range inputs {
+			os.Remove(in.FilePath)
+		}
+	}
+	for i, chunk := range chunks {
+		outputPath := filepath.Join(outputDir, fmt.Sprintf("%s_chunk%03d.wav",
+			strings.TrimSuffix(filepath.Base(input.FilePath), filepath.Ext(input.FilePath)), i))
+		args := []string{"-hide_banner", "-ss", formatSeconds(chunk.Start), "-i", input.FilePath,
+			"-t", formatSeconds(chunk.End - chunk.Start)}
+		if input.SampleRate > 0 {
+			args = append(args, "-ar", strconv.Itoa(input.SampleRate))
+		}
+		args = append(args, "-c:a", "pcm_s16le", "-y", outputPath)
+
+		cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			os.Remove(outputPath)
+			cleanup()
+			logger.Error("FFmpeg chunk split failed", "output", string(output), "error", err)
+			return nil, fmt.Errorf("audio chunking failed: %w", err)
+		}
+
+		in := interfaces.AudioInput{
+			FilePath:     outputPath,
+			Format:       "wav",
+			SampleRate:   input.SampleRate,
+			Channels:     input.Channels,
+			Duration:     time.Duration((chunk.End - chunk.Start) * float64(time.Second)),
+			Metadata:     input.Metadata,
+			TempFilePath: outputPath,
+		}
+		if stat, err := os.Stat(outputPath); err == nil {
+			in.Size = stat.Size()
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// MergeChunks joins the transcripts of each chunk into one, shifting their
+// timestamps by where the chunk starts and keeping only the segments and words
+// each chunk owns, so nothing in the overlaps is transcribed twice
+func MergeChunks(chunks []Chunk, results []*interfaces.TranscriptResult) *interfaces.TranscriptResult {
+	merged := &interfaces.TranscriptResult{Metadata: map[string]string{}}
+	languages := map[string]int{}
+	var texts []string
+	var confidence, weight float64
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		chunk, last := chunks[i], i == len(chunks)-1
+		for _, segment := range result.Segments {
+			segment.Start += chunk.Start
+			segment.End += chunk.Start
+			if !chunk.owns(segment.Start, segment.End, last) {
+				continue
+			}
+			merged.Segments = append(merged.Segments, segment)
+			if text := strings.TrimSpace(segment.Text); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		for _, word := range result.WordSegments {
+			word.Start += chunk.Start
+			word.End += chunk.Start
+			if chunk.owns(word.Start, word.End, last) {
+				merged.WordSegments = append(merged.WordSegments, word)
+			}
+		}
+
+		if result.Language != "" {
+			languages[result.Language]++
+		}
+		confidence += result.Confidence * (chunk.To - chunk.From)
+		weight += chunk.To - chunk.From
+		merged.ProcessingTime += result.ProcessingTime
+		if merged.ModelUsed == "" {
+			merged.ModelUsed = result.ModelUsed
+		}
+		for key, value := range result.Metadata {
+			if _, ok := merged.Metadata[key]; !ok {
+				merged.Metadata[key] = value
+			}
+		}
+	}
+
+	merged.Text = strings.Join(texts, " ")
+	for language, count := range languages {
+		if count > languages[merged.Language] || (count == languages[merged.Language] && language < merged.Language) {
+			merged.Language = language
+		}
+	}
+	if weight > 0 {
+		merged.Confidence = confidence / weight
+	}
+	return merged
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestPlanChunks(t *testing.T) {
+	// Cuts land in the pauses near each 100s boundary, or on the boundary
+	// when there is none close enough; the short tail stays in the last chunk
+	silences := parseSilences(`[silencedetect @ 0x1] silence_start: 95
+[silencedetect @ 0x1] silence_end: 97 | silence_duration: 2
+[silencedetect @ 0x1] silence_start: 140
+[silencedetect @ 0x1] silence_end: 141 | silence_duration: 1`)
+	chunks := planChunks(silences, 310, 100, 2)
+	want := []Chunk{
+		{Start: 0, End: 98, From: 0, To: 96},
+		{Start: 94, End: 198, From: 96, To: 196},
+		{Start: 194, End: 310, From: 196, To: 310},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got chunks %+v, want %+v", chunks, want)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunk %d: got %+v, want %+v", i, chunks[i], want[i])
+		}
+	}
+
+	if chunks := planChunks(nil, 80, 100, 2); len(chunks) != 1 || chunks[0] != (Chunk{End: 80, To: 80}) {
+		t.Errorf("short audio: got %+v", chunks)
+	}
+}
+
+func TestMergeChunks(t *testing.T) {
+	chunks := []Chunk{
+		{Start: 0, End: 12, From: 0, To: 10},
+		{Start: 8, End: 20, From: 10, To: 20},
+	}
+	results := []*interfaces.TranscriptResult{
+		{
+			Language: "en",
+			Segments: []interfaces.TranscriptSegment{
+				{Start: 1, End: 4, Text: " hello there "},
+				{Start: 8.5, End: 11.5, Text: "in the overlap"},
+			},
+			WordSegments: []interfaces.TranscriptWord{{Start: 1, End: 2, Word: "hello"}, {Start: 10.5, End: 11, Word: "overlap"}},
+			Metadata:     map[string]string{"alignment_fallback": "no model"},
+		},
+		{
+			Language: "en",
+			Segments: []interfaces.TranscriptSegment{
+				{Start: 0.5, End: 3.5, Text: "in the overlap"},
+				{Start: 5, End: 9, Text: "goodbye"},
+			},
+			WordSegments: []interfaces.TranscriptWord{{Start: 2.5, End: 3, Word: "overlap"}},
+		},
+	}
+	merged := MergeChunks(chunks, results)
+	if merged.Text != "hello there in the overlap goodbye" {
+		t.Errorf("got text %q", merged.Text)
+	}
+	if len(merged.Segments) != 3 || merged.Segments[1].Start != 8.5 || merged.Segments[2].Start != 13 || merged.Segments[2].End != 17 {
+		t.Errorf("got segments %+v", merged.Segments)
+	}
+	if len(merged.WordSegments) != 2 || merged.WordSegments[1].Start != 10.5 {
+		t.Errorf("got words %+v", merged.WordSegments)
+	}
+	if merged.Language != "en" || merged.Metadata["alignment_fallback"] != "no model" {
+		t.Errorf("got language %q and metadata %v", merged.Language, merged.Metadata)
+	}
+}
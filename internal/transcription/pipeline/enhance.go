@@ -0,0 +1,217 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// silenceThreshold and silenceMinDuration are what silence trimming treats as silence
+	silenceThreshold   = "-50dB"
+	silenceMinDuration = 0.5
+	// silenceEdgeTolerance is how close to the start or end of the audio a
+	// silence must reach to be trimmed
+	silenceEdgeTolerance = 0.05
+)
+
+// Enhancement configures the ffmpeg filters that clean up audio before transcription
+type Enhancement struct {
+	Normalize   bool // EBU R128 loudness normalization
+	TrimSilence bool // trim leading and trailing silence
+	Resample    bool // resample to 16kHz mono
+	// DenoiseModel is the RNNoise model the arnndn filter loads; empty skips denoising
+	DenoiseModel string
+}
+
+// filters returns the ffmpeg audio filter chain of the enhancement
+func (e Enhancement) filters() []string {
+	var filters []string
+	if e.DenoiseModel != "" {
+		filters = append(filters, "arnndn=m="+quoteFilterValue(e.DenoiseModel))
+	}
+	if e.Normalize {
+		filters = append(filters, "loudnorm=I=-16:TP=-1.5:LRA=11")
+	}
+	return filters
+}
+
+// quoteFilterValue quotes a value for an ffmpeg filter graph
+func quoteFilterValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// Enhance runs the enhancement over input, writing a wav file to outputDir. It
+// returns the enhanced audio and how many seconds were trimmed from its start,
+// which transcript timestamps must be shifted by to match the original. Input is
+// returned unchanged when there is nothing to do.
+func Enhance(ctx context.Context, input interfaces.AudioInput, e Enhancement, outputDir string) (interfaces.AudioInput, float64, error) {
+	var start, end float64
+	if e.TrimSilence {
+		var err error
+		start, end, err = detectSilence(ctx, input)
+		if err != nil {
+			return input, 0, err
+		}
+	}
+	filters := e.filters()
+	if len(filters) == 0 && !e.Resample && start == 0 && end == 0 {
+		return input, 0, nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return input, 0, fmt.Errorf("failed to create preprocessing directory: %w", err)
+	}
+	out, err := os.CreateTemp(outputDir, "preprocessed-*.wav")
+	if err != nil {
+		return input, 0, fmt.Errorf("failed to create preprocessed file: %w", err)
+	}
+	outputPath := out.Name()
+	out.Close()
+
+	args := []string{"-hide_banner"}
+	if start > 0 {
+		args = append(args, "-ss", formatSeconds(start))
+	}
+	args = append(args, "-i", input.FilePath)
+	if end > 0 {
+		args = append(args, "-t", formatSeconds(end-start))
+	}
+	if len(filters) > 0 {
+		args = append(args, "-af", strings.Join(filters, ","))
+	}
+	sampleRate, channels := input.SampleRate, input.Channels
+	if e.Resample {
+		sampleRate, channels = 16000, 1
+		args = append(args, "-ar", "16000", "-ac", "1")
+	} else if e.Normalize && sampleRate > 0 {
+		// loudnorm upsamples to 192kHz, so keep the input's rate
+		args = append(args, "-ar", strconv.Itoa(sampleRate))
+	}
+	args = append(args, "-c:a", "pcm_s16le", "-y", outputPath)
+
+	logger.Info("Preprocessing audio", "file", input.FilePath, "filters", strings.Join(filters, ","),
+		"resample", e.Resample, "trim_start", start, "trim_end", end)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(outputPath)
+		logger.Error("FFmpeg preprocessing failed", "output", string(output), "error", err)
+		return input, 0, fmt.Errorf("audio preprocessing failed: %w", err)
+	}
+
+	duration := input.Duration
+	if end > 0 {
+		duration = time.Duration((end - start) * float64(time.Second))
+	} else if start > 0 {
+		duration -= time.Duration(start * float64(time.Second))
+	}
+	enhanced := interfaces.AudioInput{
+		FilePath:     outputPath,
+		Format:       "wav",
+		SampleRate:   sampleRate,
+		Channels:     channels,
+		Duration:     duration,
+		Metadata:     input.Metadata,
+		TempFilePath: outputPath,
+	}
+	if stat, err := os.Stat(outputPath); err == nil {
+		enhanced.Size = stat.Size()
+	}
+	return enhanced, start, nil
+}
+
+// silence is a stretch of silence found by ffmpeg's silencedetect; ended is
+// false when it runs to the end of the stream without a silence_end line
+type silence struct {
+	start, end float64
+	ended      bool
+}
+
+// detectSilences runs ffmpeg's silencedetect over a file
+func detectSilences(ctx context.Context, path, noise string, minDuration float64) ([]silence, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%s:d=%s", noise, formatSeconds(minDuration))
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-nostats", "-i", path, "-af", filter, "-f", "null", "-")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		logger.Error("FFmpeg silence detection failed", "output", string(output), "error", err)
+		return nil, fmt.Errorf("silence detection failed: %w", err)
+	}
+	return parseSilences(string(output)), nil
+}
+
+// parseSilences reads the silences from silencedetect output
+func parseSilences(output string) []silence {
+	var silences []silence
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := silenceValue(line, "silence_start:"); ok {
+			silences = append(silences, silence{start: value})
+		} else if value, ok := silenceValue(line, "silence_end:"); ok && len(silences) > 0 && !silences[len(silences)-1].ended {
+			silences[len(silences)-1].end, silences[len(silences)-1].ended = value, true
+		}
+	}
+	return silences
+}
+
+// detectSilence returns where speech starts in the input and, when the audio
+// ends in silence, where it ends (0 otherwise)
+func detectSilence(ctx context.Context, input interfaces.AudioInput) (float64, float64, error) {
+	silences, err := detectSilences(ctx, input.FilePath, silenceThreshold, silenceMinDuration)
+	if err != nil {
+		return 0, 0, err
+	}
+	start, end := silenceBounds(silences, input.Duration.Seconds())
+	return start, end, nil
+}
+
+// silenceBounds returns where speech starts and, when the audio ends in
+// silence, where it ends (0 otherwise). Audio that is silent throughout is
+// left alone.
+func silenceBounds(silences []silence, duration float64) (float64, float64) {
+	if len(silences) == 0 {
+		return 0, 0
+	}
+
+	atEnd := func(s silence) bool {
+		return !s.ended || (duration > 0 && s.end >= duration-silenceEdgeTolerance)
+	}
+	var start, end float64
+	if first := silences[0]; first.start <= silenceEdgeTolerance {
+		if atEnd(first) {
+			return 0, 0
+		}
+		start = first.end
+	}
+	if last := silences[len(silences)-1]; atEnd(last) && last.start > start {
+		end = last.start
+	}
+	return start, end
+}
+
+// silenceValue parses the number after key in a silencedetect line
+func silenceValue(line, key string) (float64, bool) {
+	i := strings.Index(line, key)
+	if i < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(line[i+len(key):])
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	return max(value, 0), err == nil
+}
+
+// formatSeconds formats seconds for an ffmpeg option
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
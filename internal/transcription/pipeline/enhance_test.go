@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestSilenceBounds(t *testing.T) {
+	cases := []struct {
+		name       string
+		output     string
+		duration   float64
+		start, end float64
+	}{
+		{name: "no silence", output: "size=N/A time=00:00:10.00", duration: 10},
+		{
+			name: "leading and trailing",
+			output: `[silencedetect @ 0x1] silence_start: 0
+[silencedetect @ 0x1] silence_end: 1.25 | silence_duration: 1.25
+[silencedetect @ 0x1] silence_start: 4.5
+[silencedetect @ 0x1] silence_end: 5.5 | silence_duration: 1
+[silencedetect @ 0x1] silence_start: 8.2`,
+			duration: 10, start: 1.25, end: 8.2,
+		},
+		{
+			name: "trailing silence ended at the end of the stream",
+			output: `[silencedetect @ 0x1] silence_start: 7.5
+[silencedetect @ 0x1] silence_end: 10 | silence_duration: 2.5`,
+			duration: 10, end: 7.5,
+		},
+		{
+			name: "pauses in speech are kept",
+			output: `[silencedetect @ 0x1] silence_start: 2
+[silencedetect @ 0x1] silence_end: 3 | silence_duration: 1`,
+			duration: 10,
+		},
+		{name: "silent throughout", output: "[silencedetect @ 0x1] silence_start: -0.01", duration: 10},
+	}
+	for _, c := range cases {
+		start, end := silenceBounds(parseSilences(c.output), c.duration)
+		if start != c.start || end != c.end {
+			t.Errorf("%s: got (%v, %v), want (%v, %v)", c.name, start, end, c.start, c.end)
+		}
+	}
+}
+
+func TestEnhancementFilters(t *testing.T) {
+	filters := Enhancement{Normalize: true, DenoiseModel: "/models/it's.rnnn"}.filters()
+	want := []string{`arnndn=m='/models/it'\''s.rnnn'`, "loudnorm=I=-16:TP=-1.5:LRA=11"}
+	if len(filters) != len(want) {
+		t.Fatalf("got filters %v, want %v", filters, want)
+	}
+	for i := range want {
+		if filters[i] != want[i] {
+			t.Errorf("filter %d: got %q, want %q", i, filters[i], want[i])
+		}
+	}
+
+	// Nothing to do leaves the input alone without running ffmpeg
+	input := interfaces.AudioInput{FilePath: "/missing.wav"}
+	got, start, err := Enhance(context.Background(), input, Enhancement{}, t.TempDir())
+	if err != nil || got.FilePath != input.FilePath || start != 0 {
+		t.Errorf("unexpected result %+v, %v, %v", got, start, err)
+	}
+}
@@ -0,0 +1,185 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// vadSilenceThreshold and vadMinSilence are what the ffmpeg fallback treats
+	// as a silence worth cutting
+	vadSilenceThreshold = "-45dB"
+	vadMinSilence       = 2.0
+	// vadPadding is how much audio is kept either side of speech, so words are
+	// not clipped
+	vadPadding = 0.3
+	// vadMinGap is the shortest gap between speech that is cut out
+	vadMinGap = 1.0
+	// vadMinSkipped is how much must be cut for the audio to be rewritten
+	vadMinSkipped = 2.0
+)
+
+// DetectSpeech finds the speech in the input with ffmpeg's silencedetect: all
+// but silences of at least two seconds. Unlike a VAD model it cannot tell music
+// from speech.
+func DetectSpeech(ctx context.Context, input interfaces.AudioInput) ([]interfaces.SpeechRegion, error) {
+	silences, err := detectSilences(ctx, input.FilePath, vadSilenceThreshold, vadMinSilence)
+	if err != nil {
+		return nil, err
+	}
+	return speechBetween(silences, input.Duration.Seconds()), nil
+}
+
+// speechBetween returns the audio between the silences
+func speechBetween(silences []silence, duration float64) []interfaces.SpeechRegion {
+	var regions []interfaces.SpeechRegion
+	var start float64
+	for _, s := range silences {
+		if s.start > start {
+			regions = append(regions, interfaces.SpeechRegion{Start: start, End: s.start})
+		}
+		if !s.ended {
+			return regions
+		}
+		start = s.end
+	}
+	if duration <= 0 || duration > start {
+		regions = append(regions, interfaces.SpeechRegion{Start: start, End: duration})
+	}
+	return regions
+}
+
+// SpeechMap maps times in audio cut down to its speech back to the original.
+// The zero value maps every time to itself.
+type SpeechMap struct {
+	// Kept are the stretches of the original audio kept, in order
+	Kept []interfaces.SpeechRegion
+	// Skipped is how many seconds were cut out
+	Skipped float64
+}
+
+// PlanSpeechCut pads the speech regions, merges those too close together to be
+// worth cutting between and returns what of audio of the given duration is kept
+func PlanSpeechCut(regions []interfaces.SpeechRegion, duration float64) SpeechMap {
+	sorted := append([]interfaces.SpeechRegion(nil), regions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var kept []interfaces.SpeechRegion
+	for _, r := range sorted {
+		start, end := max(r.Start-vadPadding, 0), r.End+vadPadding
+		if duration > 0 {
+			end = min(end, duration)
+		}
+		if end <= start {
+			continue
+		}
+		if n := len(kept); n > 0 && start-kept[n-1].End < vadMinGap {
+			kept[n-1].End = max(kept[n-1].End, end)
+			continue
+		}
+		kept = append(kept, interfaces.SpeechRegion{Start: start, End: end})
+	}
+	if len(kept) == 0 {
+		return SpeechMap{}
+	}
+	if duration <= 0 {
+		duration = kept[len(kept)-1].End
+	}
+	var speech float64
+	for _, r := range kept {
+		speech += r.End - r.Start
+	}
+	return SpeechMap{Kept: kept, Skipped: max(duration-speech, 0)}
+}
+
+// original maps t in the cut audio to the original. A time on the boundary
+// between two kept regions is the end of the first when end is set and the
+// start of the second otherwise.
+func (m SpeechMap) original(t float64, end bool) float64 {
+	if len(m.Kept) == 0 {
+		return t
+	}
+	var offset float64
+	for i, r := range m.Kept {
+		length := r.End - r.Start
+		last := i == len(m.Kept)-1
+		if t < offset+length || (end && t == offset+length) || last {
+			return r.Start + t - offset
+		}
+		offset += length
+	}
+	return t
+}
+
+// Remap moves a transcript's timestamps from the cut audio to the original
+func (m SpeechMap) Remap(result *interfaces.TranscriptResult) {
+	if len(m.Kept) == 0 {
+		return
+	}
+	for i := range result.Segments {
+		result.Segments[i].Start = m.original(result.Segments[i].Start, false)
+		result.Segments[i].End = m.original(result.Segments[i].End, true)
+	}
+	for i := range result.WordSegments {
+		result.WordSegments[i].Start = m.original(result.WordSegments[i].Start, false)
+		result.WordSegments[i].End = m.original(result.WordSegments[i].End, true)
+	}
+}
+
+// CutToSpeech writes the speech of the input, padded and with the gaps between
+// cut out, as a wav file to outputDir. It returns the cut audio and how to map
+// its times back to the input's. Input is returned unchanged, with a zero
+// SpeechMap, when there is no speech or too little to cut.
+func CutToSpeech(ctx context.Context, input interfaces.AudioInput, regions []interfaces.SpeechRegion, outputDir string) (interfaces.AudioInput, SpeechMap, error) {
+	m := PlanSpeechCut(regions, input.Duration.Seconds())
+	if len(m.Kept) == 0 || m.Skipped < vadMinSkipped {
+		return input, SpeechMap{}, nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return input, SpeechMap{}, fmt.Errorf("failed to create preprocessing directory: %w", err)
+	}
+	out, err := os.CreateTemp(outputDir, "speech-*.wav")
+	if err != nil {
+		return input, SpeechMap{}, fmt.Errorf("failed to create speech file: %w", err)
+	}
+	outputPath := out.Name()
+	out.Close()
+
+	selects := make([]string, len(m.Kept))
+	for i, r := range m.Kept {
+		selects[i] = fmt.Sprintf("between(t,%s,%s)", formatSeconds(r.Start), formatSeconds(r.End))
+	}
+	filter := fmt.Sprintf("aselect='%s',asetpts=N/SR/TB", strings.Join(selects, "+"))
+	args := []string{"-hide_banner", "-i", input.FilePath, "-af", filter, "-c:a", "pcm_s16le", "-y", outputPath}
+
+	logger.Info("Cutting audio to speech", "file", input.FilePath, "regions", len(m.Kept), "skipped_seconds", m.Skipped)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(outputPath)
+		logger.Error("FFmpeg speech cut failed", "output", string(output), "error", err)
+		return input, SpeechMap{}, fmt.Errorf("speech cut failed: %w", err)
+	}
+
+	cut := interfaces.AudioInput{
+		FilePath:     outputPath,
+		Format:       "wav",
+		SampleRate:   input.SampleRate,
+		Channels:     input.Channels,
+		Duration:     input.Duration - time.Duration(m.Skipped*float64(time.Second)),
+		Metadata:     input.Metadata,
+		TempFilePath: outputPath,
+	}
+	if stat, err := os.Stat(outputPath); err == nil {
+		cut.Size = stat.Size()
+	}
+	return cut, m, nil
+}
@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"reflect"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestSpeechBetween(t *testing.T) {
+	output := `[silencedetect @ 0x1] silence_start: 0
+[silencedetect @ 0x1] silence_end: 3 | silence_duration: 3
+[silencedetect @ 0x1] silence_start: 10
+[silencedetect @ 0x1] silence_end: 40 | silence_duration: 30
+[silencedetect @ 0x1] silence_start: 55`
+	got := speechBetween(parseSilences(output), 60)
+	want := []interfaces.SpeechRegion{{Start: 3, End: 10}, {Start: 40, End: 55}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := speechBetween(nil, 60); !reflect.DeepEqual(got, []interfaces.SpeechRegion{{Start: 0, End: 60}}) {
+		t.Errorf("no silence: got %v", got)
+	}
+}
+
+func TestPlanSpeechCut(t *testing.T) {
+	regions := []interfaces.SpeechRegion{{Start: 40, End: 55}, {Start: 3, End: 10}, {Start: 10.5, End: 12}}
+	m := PlanSpeechCut(regions, 60)
+	want := []interfaces.SpeechRegion{{Start: 2.7, End: 12.3}, {Start: 39.7, End: 55.3}}
+	if len(m.Kept) != len(want) {
+		t.Fatalf("got kept %v, want %v", m.Kept, want)
+	}
+	for i := range want {
+		if !near(m.Kept[i].Start, want[i].Start) || !near(m.Kept[i].End, want[i].End) {
+			t.Errorf("region %d: got %v, want %v", i, m.Kept[i], want[i])
+		}
+	}
+	if !near(m.Skipped, 60-9.6-15.6) {
+		t.Errorf("got skipped %v, want %v", m.Skipped, 60-9.6-15.6)
+	}
+
+	if m := PlanSpeechCut(nil, 60); len(m.Kept) != 0 || m.Skipped != 0 {
+		t.Errorf("no speech: got %+v, want the zero map", m)
+	}
+}
+
+func TestSpeechMapRemap(t *testing.T) {
+	m := SpeechMap{Kept: []interfaces.SpeechRegion{{Start: 5, End: 10}, {Start: 30, End: 40}}, Skipped: 25}
+	result := &interfaces.TranscriptResult{
+		Segments: []interfaces.TranscriptSegment{
+			{Start: 1, End: 5},
+			{Start: 5, End: 12},
+		},
+		WordSegments: []interfaces.TranscriptWord{{Start: 14, End: 20}},
+	}
+	m.Remap(result)
+
+	segments := [][2]float64{{6, 10}, {30, 37}}
+	for i, want := range segments {
+		if got := result.Segments[i]; !near(got.Start, want[0]) || !near(got.End, want[1]) {
+			t.Errorf("segment %d: got %v-%v, want %v-%v", i, got.Start, got.End, want[0], want[1])
+		}
+	}
+	if word := result.WordSegments[0]; !near(word.Start, 39) || !near(word.End, 45) {
+		t.Errorf("word past the end: got %v-%v, want 39-45", word.Start, word.End)
+	}
+
+	unchanged := &interfaces.TranscriptResult{Segments: []interfaces.TranscriptSegment{{Start: 1, End: 2}}}
+	SpeechMap{}.Remap(unchanged)
+	if unchanged.Segments[0].Start != 1 || unchanged.Segments[0].End != 2 {
+		t.Errorf("zero map changed timestamps: %v", unchanged.Segments[0])
+	}
+}
+
+func near(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
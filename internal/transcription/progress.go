@@ -0,0 +1,114 @@
+package transcription
+
+import (
+	"context"
+	"sync"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// jobProgress records the progress adapters report on their job, and the
+// stage they are in. The progress only moves forward, so a step retried after
+// a failure does not show the job going backwards, and is saved once it has
+// grown by a whole percent.
+type jobProgress struct {
+	ctx     context.Context
+	jobRepo repository.JobRepository
+	jobID   string
+
+	mu      sync.Mutex
+	stage   string
+	percent float64
+	saved   float64
+}
+
+// jobStages are the job stages of the progress stages
+var jobStages = map[string]string{
+	interfaces.ProgressConverting:   models.StageConverting,
+	interfaces.ProgressQueued:       models.StageTranscribing,
+	interfaces.ProgressRunning:      models.StageTranscribing,
+	interfaces.ProgressTranscribing: models.StageTranscribing,
+	interfaces.ProgressAligning:     models.StageAligning,
+	interfaces.ProgressDiarizing:    models.StageDiarizing,
+}
+
+func newJobProgress(ctx context.Context, jobRepo repository.JobRepository, jobID string) *jobProgress {
+	return &jobProgress{ctx: context.WithoutCancel(ctx), jobRepo: jobRepo, jobID: jobID}
+}
+
+// report is the job's interfaces.ProgressFunc
+func (p *jobProgress) report(stage string, percent float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if jobStage := jobStages[stage]; jobStage != "" && jobStage != p.stage {
+		p.stage = jobStage
+		if err := p.jobRepo.EnterStage(p.ctx, p.jobID, jobStage); err != nil {
+			logger.Debug("Failed to update job stage", "job_id", p.jobID, "error", err)
+		}
+	}
+	if percent <= p.percent {
+		return
+	}
+	p.percent = min(percent, 100)
+	if p.percent-p.saved < 1 && p.percent < 100 {
+		return
+	}
+	p.saved = p.percent
+	logger.Debug("Job progress", "job_id", p.jobID, "stage", stage, "percent", p.percent)
+	if err := p.jobRepo.UpdateProgress(p.ctx, p.jobID, p.percent); err != nil {
+		logger.Debug("Failed to update job progress", "job_id", p.jobID, "error", err)
+	}
+}
+
+// currentStage returns the job stage the job is in, or "" before its first
+func (p *jobProgress) currentStage() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stage
+}
+
+// scaleProgress maps the progress of a step of the job, 0-100, into the part
+// of the job's progress from from to to
+func scaleProgress(progress interfaces.ProgressFunc, from, to float64) interfaces.ProgressFunc {
+	if progress == nil {
+		return nil
+	}
+	return func(stage string, percent float64) {
+		if percent < 0 {
+			progress(stage, percent)
+			return
+		}
+		progress(stage, from+(to-from)*min(percent, 100)/100)
+	}
+}
+
+// chunkProgress splits progress between n chunks transcribed at once, the
+// progress being the mean of theirs. It returns each chunk's ProgressFunc.
+func chunkProgress(progress interfaces.ProgressFunc, n int) []interfaces.ProgressFunc {
+	funcs := make([]interfaces.ProgressFunc, n)
+	if progress == nil {
+		return funcs
+	}
+	var mu sync.Mutex
+	percents := make([]float64, n)
+	for i := range funcs {
+		funcs[i] = func(stage string, percent float64) {
+			if percent < 0 {
+				progress(stage, percent)
+				return
+			}
+			mu.Lock()
+			percents[i] = min(percent, 100)
+			var sum float64
+			for _, p := range percents {
+				sum += p
+			}
+			mu.Unlock()
+			progress(stage, sum/float64(n))
+		}
+	}
+	return funcs
+}
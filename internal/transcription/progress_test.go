@@ -0,0 +1,50 @@
+package transcription
+
+import (
+	"context"
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJobProgress(t *testing.T) {
+	mockRepo := new(MockJobRepository)
+	var saved []float64
+	var stages []string
+	mockRepo.On("EnterStage", mock.Anything, "job-1", mock.Anything).Run(func(args mock.Arguments) {
+		stages = append(stages, args.String(2))
+	}).Return(nil)
+	mockRepo.On("UpdateProgress", mock.Anything, "job-1", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(2).(float64))
+	}).Return(nil)
+	progress := newJobProgress(context.Background(), mockRepo, "job-1")
+
+	// Saved once grown by a whole percent, never going backwards
+	progress.report(interfaces.ProgressConverting, -1)
+	report := scaleProgress(progress.report, 5, 95)
+	report(interfaces.ProgressTranscribing, 0)
+	report(interfaces.ProgressTranscribing, 0.5)
+	report(interfaces.ProgressTranscribing, 50)
+	report(interfaces.ProgressRunning, -1)
+	report(interfaces.ProgressAligning, 10)
+	report(interfaces.ProgressAligning, 100)
+	assert.Equal(t, []float64{5, 50, 95}, saved)
+
+	// A stage is entered when it changes; remote polling is transcription
+	assert.Equal(t, []string{models.StageConverting, models.StageTranscribing, models.StageAligning}, stages)
+
+	// Chunks count for their share
+	saved = nil
+	progress = newJobProgress(context.Background(), mockRepo, "job-1")
+	chunks := chunkProgress(progress.report, 4)
+	chunks[0](interfaces.ProgressTranscribing, 100)
+	chunks[2](interfaces.ProgressTranscribing, 50)
+	assert.Equal(t, []float64{25, 37.5}, saved)
+
+	assert.Nil(t, scaleProgress(nil, 0, 50))
+	assert.Nil(t, chunkProgress(nil, 2)[1])
+}
@@ -3,8 +3,14 @@ package transcription
 import (
 	"context"
 	"os/exec"
+	"time"
 
+	"scriberr/internal/models"
 	"scriberr/internal/repository"
+	"scriberr/internal/scanner"
+	"scriberr/internal/service"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/registry"
 	"scriberr/pkg/logger"
 )
 
@@ -20,6 +26,105 @@ func NewUnifiedJobProcessor(jobRepo repository.JobRepository) *UnifiedJobProcess
 	}
 }
 
+// SetScanner enables scanning of uploads before they are processed
+func (u *UnifiedJobProcessor) SetScanner(s scanner.Scanner) {
+	u.unifiedService.SetScanner(s)
+}
+
+// SetVoiceprints enables naming diarized speakers after enrolled voiceprints
+func (u *UnifiedJobProcessor) SetVoiceprints(v service.VoiceprintService) {
+	u.unifiedService.SetVoiceprints(v)
+}
+
+// SetVocabularies enables the custom vocabularies and vocabulary filters jobs name
+func (u *UnifiedJobProcessor) SetVocabularies(v repository.VocabularyRepository) {
+	u.unifiedService.SetVocabularies(v)
+}
+
+// SetCorrectionRules enables the find-and-replace rules transcripts are
+// corrected with once transcribed
+func (u *UnifiedJobProcessor) SetCorrectionRules(r repository.CorrectionRuleRepository) {
+	u.unifiedService.SetCorrectionRules(r)
+}
+
+// SetReviewThresholds flags low-confidence segments and puts jobs with enough
+// of them up for review
+func (u *UnifiedJobProcessor) SetReviewThresholds(confidence float64, minFlagged int) {
+	u.unifiedService.SetReviewThresholds(confidence, minFlagged)
+}
+
+// SetSpeakerAttributes enables estimating speaker attributes for jobs that opt in
+func (u *UnifiedJobProcessor) SetSpeakerAttributes(s service.SpeakerAttributeService) {
+	u.unifiedService.SetSpeakerAttributes(s)
+}
+
+// SetShadow sends a sample of jobs to a candidate adapter for offline comparison
+func (u *UnifiedJobProcessor) SetShadow(modelID string, percent float64) {
+	u.unifiedService.SetShadow(modelID, percent)
+}
+
+// SetBenchmarks enables adapter benchmarks
+func (u *UnifiedJobProcessor) SetBenchmarks(repo repository.BenchmarkRepository) {
+	u.unifiedService.SetBenchmarks(repo)
+}
+
+// QueueBenchmark queues a stored benchmark to be run
+func (u *UnifiedJobProcessor) QueueBenchmark(id string) error {
+	return u.unifiedService.QueueBenchmark(id)
+}
+
+// SetDenoiseModel sets the RNNoise model used by profiles that denoise their audio
+func (u *UnifiedJobProcessor) SetDenoiseModel(path string) {
+	u.unifiedService.SetDenoiseModel(path)
+}
+
+// SetCostModel sets the per-minute adapter prices executions are costed at
+func (u *UnifiedJobProcessor) SetCostModel(costs CostModel) {
+	u.unifiedService.SetCostModel(costs)
+}
+
+// SetResourceLimits sets the GPU memory kept free when fitting local jobs and
+// the load per CPU at which they wait
+func (u *UnifiedJobProcessor) SetResourceLimits(gpuHeadroomMB int, maxLoadPerCPU float64) {
+	u.unifiedService.SetResourceLimits(gpuHeadroomMB, maxLoadPerCPU)
+}
+
+// ResourceStatus reports the host resources local jobs are fitted into
+func (u *UnifiedJobProcessor) ResourceStatus(ctx context.Context) ResourceStatus {
+	return u.unifiedService.ResourceStatus(ctx)
+}
+
+// SetChunking splits long audio into chunks transcribed in parallel
+func (u *UnifiedJobProcessor) SetChunking(minDuration time.Duration, chunkSeconds, overlapSeconds, workers int) {
+	u.unifiedService.SetChunking(minDuration, chunkSeconds, overlapSeconds, workers)
+}
+
+// SetAdapterConfigs registers the adapters with their stored settings,
+// falling back to the environment's
+func (u *UnifiedJobProcessor) SetAdapterConfigs(env AdapterEnvironment, repo repository.AdapterConfigRepository) error {
+	return u.unifiedService.SetAdapterConfigs(env, repo)
+}
+
+// ReloadAdapters registers the adapters again whose stored settings changed
+func (u *UnifiedJobProcessor) ReloadAdapters(ctx context.Context) error {
+	return u.unifiedService.ReloadAdapters(ctx)
+}
+
+// WatchAdapterConfigs reloads the adapters every interval until ctx is done
+func (u *UnifiedJobProcessor) WatchAdapterConfigs(ctx context.Context, interval time.Duration) {
+	u.unifiedService.WatchAdapterConfigs(ctx, interval)
+}
+
+// ListAdapters returns the settings of every configurable adapter
+func (u *UnifiedJobProcessor) ListAdapters(ctx context.Context) ([]AdapterStatus, error) {
+	return u.unifiedService.ListAdapters(ctx)
+}
+
+// ValidateAdapterConfig checks adapter settings before they are stored
+func (u *UnifiedJobProcessor) ValidateAdapterConfig(ctx context.Context, cfg *models.AdapterConfig) error {
+	return u.unifiedService.ValidateAdapterConfig(ctx, cfg)
+}
+
 // Initialize prepares the job processor
 func (u *UnifiedJobProcessor) Initialize(ctx context.Context) error {
 	return u.unifiedService.Initialize(ctx)
@@ -45,6 +150,21 @@ func (u *UnifiedJobProcessor) ProcessJobWithProcess(ctx context.Context, jobID s
 	return u.unifiedService.ProcessJob(ctx, jobID)
 }
 
+// ReplayExecution re-runs a job's execution in a debug sandbox
+func (u *UnifiedJobProcessor) ReplayExecution(ctx context.Context, jobID, executionID string, keepSandbox bool) (*ReplayResult, error) {
+	return u.unifiedService.ReplayExecution(ctx, jobID, executionID, keepSandbox)
+}
+
+// RealignTranscript regenerates a job's transcript timestamps by forced alignment
+func (u *UnifiedJobProcessor) RealignTranscript(ctx context.Context, job *models.TranscriptionJob, audioPath string, segments []interfaces.TranscriptSegment) (*RealignResult, error) {
+	return u.unifiedService.RealignTranscript(ctx, job, audioPath, segments)
+}
+
+// DryRun works out what processing a job would do without running it
+func (u *UnifiedJobProcessor) DryRun(ctx context.Context, job *models.TranscriptionJob) (*DryRunResult, error) {
+	return u.unifiedService.DryRun(ctx, job)
+}
+
 // GetUnifiedService returns the underlying unified service for direct access to new features
 func (u *UnifiedJobProcessor) GetUnifiedService() *UnifiedTranscriptionService {
 	return u.unifiedService
@@ -79,17 +199,50 @@ func (u *UnifiedJobProcessor) GetModelStatus(ctx context.Context) map[string]boo
 	return u.unifiedService.GetModelStatus(ctx)
 }
 
+// GetEnvironmentStatus returns the bootstrap progress of every model environment
+func (u *UnifiedJobProcessor) GetEnvironmentStatus() []registry.EnvironmentStatus {
+	return u.unifiedService.GetEnvironmentStatus()
+}
+
+// PrefetchAlignmentModels downloads the WhisperX alignment models for the given languages
+func (u *UnifiedJobProcessor) PrefetchAlignmentModels(ctx context.Context, languages []string) error {
+	return u.unifiedService.PrefetchAlignmentModels(ctx, languages)
+}
+
 // ValidateModelParameters validates parameters for a specific model
 func (u *UnifiedJobProcessor) ValidateModelParameters(modelID string, params map[string]interface{}) error {
 	return u.unifiedService.ValidateModelParameters(modelID, params)
 }
 
+// GetModelSchema returns the parameter schema of a registered adapter
+func (u *UnifiedJobProcessor) GetModelSchema(modelID string) (*ModelSchema, error) {
+	return u.unifiedService.GetModelSchema(modelID)
+}
+
+// ValidateJobParameters checks job parameters against the schema of the adapters they select
+func (u *UnifiedJobProcessor) ValidateJobParameters(params models.WhisperXParams) error {
+	return u.unifiedService.ValidateJobParameters(params)
+}
+
 // InitEmbeddedPythonEnv initializes the Python environment for all adapters
 func (u *UnifiedJobProcessor) InitEmbeddedPythonEnv() error {
 	ctx := context.Background()
 	return u.unifiedService.Initialize(ctx)
 }
 
+// StartEmbeddedPythonEnv prepares the Python environments in the background.
+// Environments are marked pending first, so jobs queued before their models are
+// installed are deferred rather than failed.
+func (u *UnifiedJobProcessor) StartEmbeddedPythonEnv() {
+	u.unifiedService.preparesEnvironments.Store(true)
+	u.unifiedService.registry.MarkEnvironmentsPending()
+	go func() {
+		if err := u.InitEmbeddedPythonEnv(); err != nil {
+			logger.Error("Failed to prepare Python environment", "error", err)
+		}
+	}()
+}
+
 // GetSupportedLanguages returns supported languages from all models
 func (u *UnifiedJobProcessor) GetSupportedLanguages() []string {
 	// Aggregate unique languages from all models
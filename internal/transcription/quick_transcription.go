@@ -1,21 +1,39 @@
 package transcription
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"scriberr/internal/config"
 	"scriberr/internal/database"
 	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
 
 	"github.com/google/uuid"
 )
 
+// Cleanup defaults, used when not configured
+const (
+	defaultQuickRetention       = 6 * time.Hour
+	defaultQuickCleanupInterval = 15 * time.Minute
+	defaultFastModel            = "base"
+	defaultFastMaxDuration      = time.Minute
+)
+
+// fastMaxBytes caps the clips the fast path reads into memory; a minute of
+// 48kHz stereo wav fits
+const fastMaxBytes = 16 << 20
+
 // QuickTranscriptionJob represents a temporary transcription job
 type QuickTranscriptionJob struct {
 	ID           string                `json:"id"`
@@ -37,6 +55,30 @@ type QuickTranscriptionService struct {
 	tempDir          string
 	cleanupTicker    *time.Ticker
 	stopCleanup      chan bool
+
+	// Cleanup policy and the space it has reclaimed, guarded by jobsMutex
+	retention       time.Duration
+	maxStorageBytes int64
+	cleanupInterval time.Duration
+	cleanupStats    QuickCleanupStats
+
+	// Fast path for short clips
+	fastModel       string
+	fastMaxDuration time.Duration
+}
+
+// QuickCleanupStats reports the files the quick transcription cleanup has
+// removed since startup and the size of the directory it keeps in check
+type QuickCleanupStats struct {
+	Runs              int64      `json:"runs"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	JobsExpired       int64      `json:"jobs_expired"`
+	FilesRemoved      int64      `json:"files_removed"`
+	BytesReclaimed    int64      `json:"bytes_reclaimed"`
+	StorageBytes      int64      `json:"storage_bytes"`       // as of the last run
+	StorageLimitBytes int64      `json:"storage_limit_bytes"` // 0: unlimited
+	RetentionSeconds  int64      `json:"retention_seconds"`
+	ActiveJobs        int        `json:"active_jobs"`
 }
 
 // NewQuickTranscriptionService creates a new quick transcription service
@@ -53,9 +95,29 @@ func NewQuickTranscriptionService(cfg *config.Config, unifiedProcessor *UnifiedJ
 		jobs:             make(map[string]*QuickTranscriptionJob),
 		tempDir:          tempDir,
 		stopCleanup:      make(chan bool),
+		retention:        time.Duration(cfg.QuickRetentionMinutes) * time.Minute,
+		maxStorageBytes:  int64(cfg.QuickMaxStorageMB) << 20,
+		cleanupInterval:  time.Duration(cfg.QuickCleanupIntervalMinutes) * time.Minute,
+		fastModel:        cfg.FastModel,
+		fastMaxDuration:  time.Duration(cfg.FastMaxSeconds) * time.Second,
+	}
+	if service.retention <= 0 {
+		service.retention = defaultQuickRetention
+	}
+	if service.maxStorageBytes < 0 {
+		service.maxStorageBytes = 0
+	}
+	if service.cleanupInterval <= 0 {
+		service.cleanupInterval = defaultQuickCleanupInterval
+	}
+	if service.fastModel == "" {
+		service.fastModel = defaultFastModel
+	}
+	if service.fastMaxDuration <= 0 {
+		service.fastMaxDuration = defaultFastMaxDuration
 	}
 
-	// Start cleanup routine (run every hour)
+	// Start cleanup routine
 	service.startCleanupRoutine()
 
 	return service, nil
@@ -71,40 +133,126 @@ func (qs *QuickTranscriptionService) SubmitQuickJob(audioData io.Reader, filenam
 	audioFilename := fmt.Sprintf("%s%s", jobID, ext)
 	audioPath := filepath.Join(qs.tempDir, audioFilename)
 
-	// Save audio file
-	audioFile, err := os.Create(audioPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create audio file: %v", err)
-	}
-	defer audioFile.Close()
-
-	if _, err := io.Copy(audioFile, audioData); err != nil {
-		os.Remove(audioPath)
-		return nil, fmt.Errorf("failed to save audio file: %v", err)
-	}
-
-	// Create quick transcription job
+	// Register the job first so cleanup leaves the upload in progress alone
 	now := time.Now()
 	job := &QuickTranscriptionJob{
 		ID:         jobID,
-		Status:     models.StatusPending,
+		Status:     models.StatusUploaded,
 		AudioPath:  audioPath,
 		Parameters: params,
 		CreatedAt:  now,
-		ExpiresAt:  now.Add(6 * time.Hour),
+		ExpiresAt:  now.Add(qs.retention),
 	}
-
-	// Store in memory
 	qs.jobsMutex.Lock()
 	qs.jobs[jobID] = job
 	qs.jobsMutex.Unlock()
 
+	// Save audio file
+	if err := saveQuickAudio(audioPath, audioData); err != nil {
+		qs.jobsMutex.Lock()
+		delete(qs.jobs, jobID)
+		qs.jobsMutex.Unlock()
+		return nil, err
+	}
+
+	qs.jobsMutex.Lock()
+	job.Status = models.StatusPending
+	qs.jobsMutex.Unlock()
+
 	// Start processing in background
 	go qs.processQuickJob(jobID)
 
 	return job, nil
 }
 
+// SubmitFastJob transcribes a short clip before returning, for voice commands
+// and memos, with TranscribeClip. The finished job can be fetched like any
+// other until it expires. Clips over the fast path's duration or size return
+// ErrClipTooLong.
+func (qs *QuickTranscriptionService) SubmitFastJob(ctx context.Context, audioData io.Reader, filename string, params models.WhisperXParams) (*QuickTranscriptionJob, error) {
+	now := time.Now()
+	job := &QuickTranscriptionJob{
+		ID:         uuid.New().String(),
+		Status:     models.StatusCompleted,
+		Parameters: params,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(qs.retention),
+	}
+	result, err := qs.TranscribeClip(ctx, audioData, filename, params)
+	if errors.Is(err, ErrClipTooLong) || errors.Is(err, ErrFastModelNotReady) {
+		return nil, err
+	}
+	if err == nil {
+		var transcript string
+		if transcript, err = qs.unifiedProcessor.unifiedService.convertTranscriptResultToJSON(result); err == nil {
+			job.Transcript = &transcript
+		}
+	}
+	if err != nil {
+		job.Status = models.StatusFailed
+		msg := err.Error()
+		job.ErrorMessage = &msg
+	}
+
+	qs.jobsMutex.Lock()
+	qs.jobs[job.ID] = job
+	qs.jobsMutex.Unlock()
+	return job, nil
+}
+
+// TranscribeClip transcribes a short clip and returns its transcript, keeping
+// no job. The clip is held in memory and only written to disk for the length
+// of the transcription; it skips the database, the job queue, diarization and
+// preprocessing, and is transcribed locally by the small fast model. ctx bounds
+// the transcription, whose adapter is stopped when ctx ends.
+func (qs *QuickTranscriptionService) TranscribeClip(ctx context.Context, audioData io.Reader, filename string, params models.WhisperXParams) (*interfaces.TranscriptResult, error) {
+	data, err := io.ReadAll(io.LimitReader(audioData, fastMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %v", err)
+	}
+	if len(data) > fastMaxBytes {
+		return nil, fmt.Errorf("%w: over %d MB", ErrClipTooLong, fastMaxBytes>>20)
+	}
+
+	audioPath := filepath.Join(qs.tempDir, uuid.New().String()+filepath.Ext(filename))
+	if err := saveQuickAudio(audioPath, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	defer os.Remove(audioPath)
+	return qs.unifiedProcessor.unifiedService.TranscribeClip(ctx, audioPath, params, qs.fastModel, qs.fastMaxDuration)
+}
+
+// WarmFastModel waits for the fast path's environment to be installed, then
+// loads its model ahead of the first clip
+func (qs *QuickTranscriptionService) WarmFastModel(ctx context.Context) error {
+	for {
+		err := qs.unifiedProcessor.unifiedService.WarmFastModel(ctx, qs.fastModel)
+		if !errors.Is(err, ErrFastModelNotReady) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// saveQuickAudio writes an uploaded audio file, removing it if the upload fails
+func saveQuickAudio(audioPath string, audioData io.Reader) error {
+	audioFile, err := os.Create(audioPath)
+	if err != nil {
+		return fmt.Errorf("failed to create audio file: %v", err)
+	}
+	defer audioFile.Close()
+
+	if _, err := io.Copy(audioFile, audioData); err != nil {
+		os.Remove(audioPath)
+		return fmt.Errorf("failed to save audio file: %v", err)
+	}
+	return nil
+}
+
 // GetQuickJob retrieves a quick transcription job by ID
 func (qs *QuickTranscriptionService) GetQuickJob(jobID string) (*QuickTranscriptionJob, error) {
 	qs.jobsMutex.RLock()
@@ -222,12 +370,12 @@ func (qs *QuickTranscriptionService) loadTranscriptFromTemp(jobID string) (strin
 
 // startCleanupRoutine starts the background cleanup routine
 func (qs *QuickTranscriptionService) startCleanupRoutine() {
-	qs.cleanupTicker = time.NewTicker(1 * time.Hour)
+	qs.cleanupTicker = time.NewTicker(qs.cleanupInterval)
 	go func() {
 		for {
 			select {
 			case <-qs.cleanupTicker.C:
-				qs.cleanupExpiredJobs()
+				qs.Cleanup()
 			case <-qs.stopCleanup:
 				qs.cleanupTicker.Stop()
 				return
@@ -236,25 +384,167 @@ func (qs *QuickTranscriptionService) startCleanupRoutine() {
 	}()
 }
 
-// cleanupExpiredJobs removes expired jobs and their files
-func (qs *QuickTranscriptionService) cleanupExpiredJobs() {
+// quickArtifact is the set of files in the temp directory belonging to one job:
+// its audio, transcript and output directory
+type quickArtifact struct {
+	paths   []string
+	size    int64
+	modTime time.Time // newest file
+	job     *QuickTranscriptionJob
+}
+
+// Cleanup applies the retention and storage policies to the temp directory. It
+// removes expired jobs and their files, then files no job owns once they are
+// older than the retention period (left behind by a restart or a failed job).
+// While the directory is over its size cap, it removes orphaned files and then
+// the files of finished jobs, oldest first; the results of those jobs stay
+// available in memory until they expire. Files of jobs still running are
+// never removed.
+func (qs *QuickTranscriptionService) Cleanup() QuickCleanupStats {
 	qs.jobsMutex.Lock()
 	defer qs.jobsMutex.Unlock()
 
 	now := time.Now()
+	var files, bytes, expired int64
+	remove := func(artifact *quickArtifact) {
+		for _, path := range artifact.paths {
+			if err := os.RemoveAll(path); err != nil {
+				logger.Warn("Failed to remove quick transcription file", "path", path, "error", err)
+				continue
+			}
+			files++
+		}
+		bytes += artifact.size
+	}
+
+	expiredJobs := make(map[string]bool)
 	for jobID, job := range qs.jobs {
 		if now.After(job.ExpiresAt) {
-			// Remove files
-			os.Remove(job.AudioPath)
-			os.Remove(filepath.Join(qs.tempDir, jobID+"_transcript.json"))
-			os.RemoveAll(filepath.Join(qs.tempDir, jobID+"_output"))
-
-			// Remove from memory
 			delete(qs.jobs, jobID)
+			expiredJobs[jobID] = true
+		}
+	}
+	expired = int64(len(expiredJobs))
 
-			fmt.Printf("DEBUG: Cleaned up expired quick transcription job: %s\n", jobID)
+	artifacts, err := qs.scanArtifacts()
+	if err != nil {
+		logger.Warn("Failed to scan quick transcription directory", "dir", qs.tempDir, "error", err)
+	}
+	var total int64
+	var evictable []*quickArtifact
+	for owner, artifact := range artifacts {
+		artifact.job = qs.jobs[owner]
+		if expiredJobs[owner] || (artifact.job == nil && now.Sub(artifact.modTime) > qs.retention) {
+			remove(artifact)
+			continue
+		}
+		total += artifact.size
+		if artifact.job == nil || artifact.job.Status == models.StatusCompleted || artifact.job.Status == models.StatusFailed {
+			evictable = append(evictable, artifact)
 		}
 	}
+
+	if qs.maxStorageBytes > 0 && total > qs.maxStorageBytes {
+		// Orphaned files go first, then finished jobs, each oldest first
+		sort.SliceStable(evictable, func(i, j int) bool {
+			a, b := evictable[i], evictable[j]
+			if (a.job == nil) != (b.job == nil) {
+				return a.job == nil
+			}
+			return a.modTime.Before(b.modTime)
+		})
+		for _, artifact := range evictable {
+			if total <= qs.maxStorageBytes {
+				break
+			}
+			remove(artifact)
+			total -= artifact.size
+		}
+		if total > qs.maxStorageBytes {
+			logger.Warn("Quick transcriptions in progress exceed the storage limit", "storage_bytes", total, "limit_bytes", qs.maxStorageBytes)
+		}
+	}
+
+	stats := &qs.cleanupStats
+	stats.Runs++
+	stats.LastRunAt = &now
+	stats.JobsExpired += expired
+	stats.FilesRemoved += files
+	stats.BytesReclaimed += bytes
+	stats.StorageBytes = total
+	if files > 0 || expired > 0 {
+		logger.Info("Cleaned up quick transcriptions", "jobs_expired", expired, "files_removed", files, "bytes_reclaimed", bytes, "storage_bytes", total)
+	}
+	return qs.statsLocked()
+}
+
+// scanArtifacts groups the files in the temp directory by the job ID they are
+// named after. Files not named after a job are grouped on their own.
+func (qs *QuickTranscriptionService) scanArtifacts() (map[string]*quickArtifact, error) {
+	entries, err := os.ReadDir(qs.tempDir)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make(map[string]*quickArtifact)
+	for _, entry := range entries {
+		name := entry.Name()
+		owner := name
+		if len(name) >= 36 {
+			if _, err := uuid.Parse(name[:36]); err == nil {
+				owner = name[:36]
+			}
+		}
+
+		path := filepath.Join(qs.tempDir, name)
+		var size int64
+		var modTime time.Time
+		walkErr := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				size += info.Size()
+			}
+			if info.ModTime().After(modTime) {
+				modTime = info.ModTime()
+			}
+			return nil
+		})
+		if walkErr != nil {
+			continue
+		}
+
+		artifact, ok := artifacts[owner]
+		if !ok {
+			artifact = &quickArtifact{}
+			artifacts[owner] = artifact
+		}
+		artifact.paths = append(artifact.paths, path)
+		artifact.size += size
+		if modTime.After(artifact.modTime) {
+			artifact.modTime = modTime
+		}
+	}
+	return artifacts, nil
+}
+
+// CleanupStats returns the cleanup policy and what it has reclaimed so far
+func (qs *QuickTranscriptionService) CleanupStats() QuickCleanupStats {
+	qs.jobsMutex.RLock()
+	defer qs.jobsMutex.RUnlock()
+	return qs.statsLocked()
+}
+
+func (qs *QuickTranscriptionService) statsLocked() QuickCleanupStats {
+	stats := qs.cleanupStats
+	stats.StorageLimitBytes = qs.maxStorageBytes
+	stats.RetentionSeconds = int64(qs.retention / time.Second)
+	stats.ActiveJobs = len(qs.jobs)
+	return stats
 }
 
 // Close stops the cleanup routine
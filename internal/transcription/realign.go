@@ -0,0 +1,134 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+)
+
+// Realignment errors
+var (
+	// ErrRealignUnsupported is returned when no local WhisperX adapter is
+	// registered to run forced alignment with
+	ErrRealignUnsupported = errors.New("forced alignment needs the local WhisperX adapter")
+	// ErrRealignEnvironmentPending is returned while the WhisperX environment is still being installed
+	ErrRealignEnvironmentPending = errors.New("the WhisperX environment is not ready")
+	// ErrRealignNoLanguage is returned for transcripts whose language is unknown,
+	// since alignment models are per language
+	ErrRealignNoLanguage = errors.New("the transcript's language is unknown")
+)
+
+// RealignResult is a transcript whose timestamps were regenerated from its text
+type RealignResult struct {
+	Transcript *interfaces.TranscriptResult `json:"transcript"`
+	// Segments that could not be aligned keep the times they had
+	AlignedSegments   int `json:"aligned_segments"`
+	UnalignedSegments int `json:"unaligned_segments"`
+}
+
+// RealignTranscript regenerates the segment and word timestamps of a job's
+// transcript by forced alignment of its text against the audio at audioPath,
+// and saves it. segments replaces the stored transcript's segments when given,
+// e.g. with heavily edited text; each segment's times should still roughly
+// cover its speech, since it is only searched for there. Speakers and segment
+// languages are kept.
+func (u *UnifiedTranscriptionService) RealignTranscript(ctx context.Context, job *models.TranscriptionJob, audioPath string, segments []interfaces.TranscriptSegment) (*RealignResult, error) {
+	ctx, span := tracing.Start(ctx, "transcription.realign")
+	defer span.End()
+
+	adapter, err := u.registry.GetTranscriptionAdapter("whisperx")
+	if err != nil {
+		return nil, ErrRealignUnsupported
+	}
+	whisperx, ok := adapter.(*adapters.WhisperXAdapter)
+	if !ok {
+		return nil, ErrRealignUnsupported
+	}
+	if pending := u.registry.PendingEnvironments("whisperx"); len(pending) > 0 {
+		return nil, ErrRealignEnvironmentPending
+	}
+
+	transcript := &interfaces.TranscriptResult{}
+	if job.Transcript != nil {
+		if err := json.Unmarshal([]byte(*job.Transcript), transcript); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript: %w", err)
+		}
+	}
+	if segments != nil {
+		transcript.Segments = segments
+	}
+	language := transcript.Language
+	if language == "" && job.Parameters.Language != nil {
+		language = *job.Parameters.Language
+	}
+	if language == "" {
+		return nil, ErrRealignNoLanguage
+	}
+
+	if err := os.MkdirAll(u.tempDirectory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	workDir, err := os.MkdirTemp(u.tempDirectory, "realign-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alignment directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	params := u.convertParametersForModel(job.Parameters, "whisperx")
+	aligned, err := whisperx.Realign(ctx, audioPath, language, transcript.Segments, params, workDir)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	result := &RealignResult{Transcript: transcript}
+	result.AlignedSegments = applyAlignment(transcript, aligned)
+	result.UnalignedSegments = len(transcript.Segments) - result.AlignedSegments
+	transcript.Language = language
+
+	encoded, err := u.convertTranscriptResultToJSON(transcript)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transcript: %w", err)
+	}
+	if err := u.jobRepo.UpdateTranscript(ctx, job.ID, encoded); err != nil {
+		return nil, err
+	}
+	if err := u.jobRepo.UpdateAlignmentFallback(ctx, job.ID, nil); err != nil {
+		logger.Warn("Failed to clear alignment fallback", "job_id", job.ID, "error", err)
+	}
+	logger.Info("Realigned transcript", "job_id", job.ID, "aligned", result.AlignedSegments, "unaligned", result.UnalignedSegments)
+	return result, nil
+}
+
+// applyAlignment moves the segments of transcript to the times alignment found
+// and rebuilds its words and text from them, returning how many segments were
+// aligned. Unaligned segments keep their times and contribute no words.
+func applyAlignment(transcript *interfaces.TranscriptResult, aligned []*adapters.AlignedSegment) int {
+	var words []interfaces.TranscriptWord
+	texts := make([]string, 0, len(transcript.Segments))
+	count := 0
+	for i := range transcript.Segments {
+		seg := &transcript.Segments[i]
+		texts = append(texts, strings.TrimSpace(seg.Text))
+		if i >= len(aligned) || aligned[i] == nil {
+			continue
+		}
+		count++
+		seg.Start, seg.End = aligned[i].Start, aligned[i].End
+		for _, word := range aligned[i].Words {
+			word.Speaker = seg.Speaker
+			words = append(words, word)
+		}
+	}
+	transcript.WordSegments = words
+	transcript.Text = strings.Join(texts, " ")
+	return count
+}
@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"sort"
+	"time"
+)
+
+// EnvironmentState is the bootstrap state of an adapter's environment
+type EnvironmentState string
+
+const (
+	EnvironmentPending   EnvironmentState = "pending"
+	EnvironmentPreparing EnvironmentState = "preparing"
+	EnvironmentReady     EnvironmentState = "ready"
+	EnvironmentFailed    EnvironmentState = "failed"
+)
+
+// EnvironmentStatus reports the progress of preparing one adapter's environment
+type EnvironmentStatus struct {
+	ModelID        string           `json:"model_id"`
+	Type           string           `json:"type"`
+	State          EnvironmentState `json:"state"`
+	Error          string           `json:"error,omitempty"`
+	StartedAt      *time.Time       `json:"started_at,omitempty"`
+	CompletedAt    *time.Time       `json:"completed_at,omitempty"`
+	ElapsedSeconds float64          `json:"elapsed_seconds"`
+}
+
+// setEnvironmentState records a state transition for modelID
+func (r *ModelRegistry) setEnvironmentState(modelID, typeName string, state EnvironmentState, err error) {
+	r.envMu.Lock()
+	defer r.envMu.Unlock()
+
+	status, ok := r.envStatus[modelID]
+	if !ok {
+		status = &EnvironmentStatus{ModelID: modelID, Type: typeName}
+		r.envStatus[modelID] = status
+	}
+	status.State = state
+
+	now := time.Now()
+	switch state {
+	case EnvironmentPreparing:
+		status.StartedAt = &now
+	case EnvironmentReady, EnvironmentFailed:
+		status.CompletedAt = &now
+	}
+	if err != nil {
+		status.Error = err.Error()
+	} else if state != EnvironmentFailed {
+		// A retry clears the previous failure
+		status.Error = ""
+	}
+}
+
+// environmentState returns the state of modelID's environment, or "" if unknown
+func (r *ModelRegistry) environmentState(modelID string) EnvironmentState {
+	r.envMu.RLock()
+	defer r.envMu.RUnlock()
+	if status, ok := r.envStatus[modelID]; ok {
+		return status.State
+	}
+	return ""
+}
+
+// GetEnvironmentStatus returns the bootstrap status of every adapter environment,
+// sorted by model ID
+func (r *ModelRegistry) GetEnvironmentStatus() []EnvironmentStatus {
+	r.envMu.RLock()
+	defer r.envMu.RUnlock()
+
+	statuses := make([]EnvironmentStatus, 0, len(r.envStatus))
+	for _, status := range r.envStatus {
+		s := *status
+		if s.StartedAt != nil {
+			end := time.Now()
+			if s.CompletedAt != nil {
+				end = *s.CompletedAt
+			}
+			s.ElapsedSeconds = end.Sub(*s.StartedAt).Seconds()
+		}
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ModelID < statuses[j].ModelID
+	})
+	return statuses
+}
+
+// PendingEnvironments returns the given models whose environments are still being
+// prepared. Failed and unknown models are not reported, so their jobs fail normally.
+func (r *ModelRegistry) PendingEnvironments(modelIDs ...string) []string {
+	r.envMu.RLock()
+	defer r.envMu.RUnlock()
+
+	var pending []string
+	for _, modelID := range modelIDs {
+		if status, ok := r.envStatus[modelID]; ok &&
+			(status.State == EnvironmentPending || status.State == EnvironmentPreparing) {
+			pending = append(pending, modelID)
+		}
+	}
+	return pending
+}
@@ -19,7 +19,17 @@ type ModelRegistry struct {
 	diarizationAdapters   map[string]interfaces.DiarizationAdapter
 	compositeAdapters     map[string]interfaces.CompositeAdapter
 	capabilities          map[string]interfaces.ModelCapabilities
-	initialized           bool
+	// initialized is set once every environment has been prepared; initializing
+	// guards against overlapping runs, and retryDelay backs off retries of
+	// environments that failed
+	initialized  bool
+	initializing bool
+	retryDelay   time.Duration
+
+	// Environment bootstrap progress, guarded separately so status reads and
+	// adapter lookups are not blocked while environments are being prepared
+	envMu     sync.RWMutex
+	envStatus map[string]*EnvironmentStatus
 }
 
 // Global registry instance
@@ -34,6 +44,7 @@ func GetRegistry() *ModelRegistry {
 			diarizationAdapters:   make(map[string]interfaces.DiarizationAdapter),
 			compositeAdapters:     make(map[string]interfaces.CompositeAdapter),
 			capabilities:          make(map[string]interfaces.ModelCapabilities),
+			envStatus:             make(map[string]*EnvironmentStatus),
 		}
 	})
 	return globalRegistry
@@ -47,6 +58,7 @@ func RegisterTranscriptionAdapter(modelID string, adapter interfaces.Transcripti
 
 	registry.transcriptionAdapters[modelID] = adapter
 	registry.capabilities[modelID] = adapter.GetCapabilities()
+	registry.forgetEnvironment(modelID)
 
 	logger.Debug("Registered transcription adapter",
 		"model_id", modelID,
@@ -62,6 +74,7 @@ func RegisterDiarizationAdapter(modelID string, adapter interfaces.DiarizationAd
 
 	registry.diarizationAdapters[modelID] = adapter
 	registry.capabilities[modelID] = adapter.GetCapabilities()
+	registry.forgetEnvironment(modelID)
 
 	logger.Debug("Registered diarization adapter",
 		"model_id", modelID,
@@ -77,6 +90,7 @@ func RegisterCompositeAdapter(modelID string, adapter interfaces.CompositeAdapte
 
 	registry.compositeAdapters[modelID] = adapter
 	registry.capabilities[modelID] = adapter.GetCapabilities()
+	registry.forgetEnvironment(modelID)
 
 	logger.Debug("Registered composite adapter",
 		"model_id", modelID,
@@ -84,6 +98,42 @@ func RegisterCompositeAdapter(modelID string, adapter interfaces.CompositeAdapte
 		"display_name", adapter.GetCapabilities().DisplayName)
 }
 
+// UnregisterAdapter removes the adapter registered under modelID, whatever
+// its kind, reporting whether there was one. Jobs already holding the adapter
+// finish with it.
+func UnregisterAdapter(modelID string) bool {
+	registry := GetRegistry()
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	_, transcription := registry.transcriptionAdapters[modelID]
+	_, diarization := registry.diarizationAdapters[modelID]
+	_, composite := registry.compositeAdapters[modelID]
+	delete(registry.transcriptionAdapters, modelID)
+	delete(registry.diarizationAdapters, modelID)
+	delete(registry.compositeAdapters, modelID)
+	delete(registry.capabilities, modelID)
+
+	registry.envMu.Lock()
+	delete(registry.envStatus, modelID)
+	registry.envMu.Unlock()
+
+	if transcription || diarization || composite {
+		logger.Debug("Unregistered adapter", "model_id", modelID)
+		return true
+	}
+	return false
+}
+
+// forgetEnvironment drops the bootstrap status of a newly registered adapter,
+// so the next InitializeModels prepares its environment; callers must hold r.mu
+func (r *ModelRegistry) forgetEnvironment(modelID string) {
+	r.initialized = false
+	r.envMu.Lock()
+	delete(r.envStatus, modelID)
+	r.envMu.Unlock()
+}
+
 // GetTranscriptionAdapter retrieves a transcription adapter by ID
 func (r *ModelRegistry) GetTranscriptionAdapter(modelID string) (interfaces.TranscriptionAdapter, error) {
 	r.mu.RLock()
@@ -386,51 +436,98 @@ func (r *ModelRegistry) scoreModel(capabilities interfaces.ModelCapabilities, re
 	return score, reasons
 }
 
-// InitializeModels ensures all registered models are ready to use (parallel)
-func (r *ModelRegistry) InitializeModels(ctx context.Context) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if r.initialized {
-		return nil
+// environmentTarget is an adapter whose environment is prepared by InitializeModels
+type environmentTarget struct {
+	id       string
+	typeName string
+	adapter  interface {
+		PrepareEnvironment(context.Context) error
 	}
+}
 
-	logger.Info("Initializing registered models in parallel...")
+// environmentTargets lists every registered adapter; callers must hold r.mu
+func (r *ModelRegistry) environmentTargets() []environmentTarget {
+	var targets []environmentTarget
+	for modelID, adapter := range r.transcriptionAdapters {
+		targets = append(targets, environmentTarget{modelID, "transcription", adapter})
+	}
+	for modelID, adapter := range r.diarizationAdapters {
+		targets = append(targets, environmentTarget{modelID, "diarization", adapter})
+	}
+	for modelID, adapter := range r.compositeAdapters {
+		targets = append(targets, environmentTarget{modelID, "composite", adapter})
+	}
+	return targets
+}
 
-	var wg sync.WaitGroup
-	initErrors := make(chan error, len(r.transcriptionAdapters)+len(r.diarizationAdapters)+len(r.compositeAdapters))
+// MarkEnvironmentsPending flags every registered environment not yet prepared as
+// pending ahead of a background InitializeModels, so jobs picked up in the
+// meantime are deferred
+func (r *ModelRegistry) MarkEnvironmentsPending() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	// Helper function to initialize an adapter
-	initAdapter := func(id string, adapter interface {
-		PrepareEnvironment(context.Context) error
-	}, typeName string) {
-		defer wg.Done()
-		logger.Debug(fmt.Sprintf("Initializing %s model", typeName), "model_id", id)
-		if err := adapter.PrepareEnvironment(ctx); err != nil {
-			logger.Error(fmt.Sprintf("Failed to initialize %s model", typeName),
-				"model_id", id, "error", err)
-			initErrors <- fmt.Errorf("%s model %s: %w", typeName, id, err)
-		} else {
-			logger.Info(fmt.Sprintf("%s model initialized", typeName), "model_id", id)
+	if r.initialized {
+		return
+	}
+	for _, t := range r.environmentTargets() {
+		if r.environmentState(t.id) != EnvironmentReady {
+			r.setEnvironmentState(t.id, t.typeName, EnvironmentPending, nil)
 		}
 	}
+}
 
-	// Initialize transcription adapters
-	for modelID, adapter := range r.transcriptionAdapters {
-		wg.Add(1)
-		go initAdapter(modelID, adapter, "transcription")
+// Environments that fail to prepare are retried after environmentRetryDelay,
+// doubling up to environmentMaxRetryDelay
+const (
+	environmentRetryDelay    = time.Minute
+	environmentMaxRetryDelay = 30 * time.Minute
+)
+
+// InitializeModels ensures all registered models are ready to use (parallel).
+// Adapter lookups are not blocked while environments are prepared; progress is
+// reported through GetEnvironmentStatus. Environments already prepared are
+// skipped, and those that fail are retried in the background until ctx is done.
+func (r *ModelRegistry) InitializeModels(ctx context.Context) error {
+	r.mu.Lock()
+	if r.initialized || r.initializing {
+		r.mu.Unlock()
+		return nil
+	}
+	r.initializing = true
+	var targets []environmentTarget
+	for _, t := range r.environmentTargets() {
+		if r.environmentState(t.id) != EnvironmentReady {
+			targets = append(targets, t)
+		}
 	}
+	r.mu.Unlock()
 
-	// Initialize diarization adapters
-	for modelID, adapter := range r.diarizationAdapters {
-		wg.Add(1)
-		go initAdapter(modelID, adapter, "diarization")
+	for _, t := range targets {
+		r.setEnvironmentState(t.id, t.typeName, EnvironmentPending, nil)
 	}
 
-	// Initialize composite adapters
-	for modelID, adapter := range r.compositeAdapters {
+	logger.Info("Initializing registered models in parallel...")
+
+	var wg sync.WaitGroup
+	initErrors := make(chan error, len(targets))
+
+	for _, t := range targets {
 		wg.Add(1)
-		go initAdapter(modelID, adapter, "composite")
+		go func(t environmentTarget) {
+			defer wg.Done()
+			logger.Debug(fmt.Sprintf("Initializing %s model", t.typeName), "model_id", t.id)
+			r.setEnvironmentState(t.id, t.typeName, EnvironmentPreparing, nil)
+			if err := t.adapter.PrepareEnvironment(ctx); err != nil {
+				logger.Error(fmt.Sprintf("Failed to initialize %s model", t.typeName),
+					"model_id", t.id, "error", err)
+				r.setEnvironmentState(t.id, t.typeName, EnvironmentFailed, err)
+				initErrors <- fmt.Errorf("%s model %s: %w", t.typeName, t.id, err)
+			} else {
+				r.setEnvironmentState(t.id, t.typeName, EnvironmentReady, nil)
+				logger.Info(fmt.Sprintf("%s model initialized", t.typeName), "model_id", t.id)
+			}
+		}(t)
 	}
 
 	// Wait for all initializations to complete
@@ -443,18 +540,55 @@ func (r *ModelRegistry) InitializeModels(ctx context.Context) error {
 		errorList = append(errorList, err)
 	}
 
+	// Adapters registered while preparing the others are prepared in another run
+	r.mu.Lock()
+	r.initializing = false
+	registered := false
+	for _, t := range r.environmentTargets() {
+		if r.environmentState(t.id) != EnvironmentReady && r.environmentState(t.id) != EnvironmentFailed {
+			registered = true
+		}
+	}
+	r.initialized = len(errorList) == 0 && !registered
+	r.mu.Unlock()
+	if registered {
+		go r.InitializeModels(ctx)
+	}
+
 	if len(errorList) > 0 {
 		logger.Warn("Some models failed to initialize", "error_count", len(errorList))
 		for _, err := range errorList {
 			logger.Warn("Model initialization error", "error", err)
 		}
+		r.scheduleRetry(ctx)
 	}
 
-	r.initialized = true
 	logger.Info("Model initialization completed")
 	return nil
 }
 
+// scheduleRetry runs InitializeModels again after a backoff, so that
+// environments which failed, for example while offline, are prepared once
+// they can be
+func (r *ModelRegistry) scheduleRetry(ctx context.Context) {
+	r.mu.Lock()
+	if r.retryDelay == 0 {
+		r.retryDelay = environmentRetryDelay
+	}
+	delay := r.retryDelay
+	r.retryDelay = min(r.retryDelay*2, environmentMaxRetryDelay)
+	r.mu.Unlock()
+
+	logger.Info("Retrying failed model environments later", "delay", delay)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+			r.InitializeModels(ctx)
+		}
+	}()
+}
+
 // GetModelStatus returns the status of all registered models
 func (r *ModelRegistry) GetModelStatus(ctx context.Context) map[string]bool {
 	r.mu.RLock()
@@ -562,6 +696,12 @@ func ClearRegistry() {
 	registry.compositeAdapters = make(map[string]interfaces.CompositeAdapter)
 	registry.capabilities = make(map[string]interfaces.ModelCapabilities)
 	registry.initialized = false
+	registry.initializing = false
+	registry.retryDelay = 0
+
+	registry.envMu.Lock()
+	registry.envStatus = make(map[string]*EnvironmentStatus)
+	registry.envMu.Unlock()
 }
 
 // GetTranscriptionAdapters returns all registered transcription adapters (for testing)
@@ -0,0 +1,288 @@
+package transcription
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Replay errors
+var (
+	// ErrReplayUnsupported is returned for executions without a snapshot: multi-track
+	// jobs and executions recorded before snapshots existed
+	ErrReplayUnsupported = errors.New("execution has no replay snapshot")
+	// ErrReplayEnvironmentPending is returned while an adapter's environment is still being installed
+	ErrReplayEnvironmentPending = errors.New("model environments are not ready")
+)
+
+// Snapshot stages
+const (
+	stageTranscription = "transcription"
+	stageDiarization   = "diarization"
+)
+
+// ReplayResult compares a replayed execution with the one it re-ran
+type ReplayResult struct {
+	Execution *models.TranscriptionJobExecution `json:"execution"` // the replay, with its own snapshot
+	ReplayOf  string                            `json:"replay_of"`
+	// The audio and adapter environments compared with the original's
+	InputMatches       bool `json:"input_matches"`
+	EnvironmentMatches bool `json:"environment_matches"`
+	// Changes lists the differences between the original's adapters and today's,
+	// e.g. "whisperx: version 3.3.1 -> 3.4.0"
+	Changes    []string                     `json:"changes"`
+	Transcript *interfaces.TranscriptResult `json:"transcript,omitempty"`
+	// OutputMatches reports whether the transcript is identical to the job's
+	// current transcript
+	OutputMatches bool   `json:"output_matches"`
+	SandboxDir    string `json:"sandbox_dir,omitempty"` // kept on request for inspection
+	Error         string `json:"error,omitempty"`
+}
+
+// recordSnapshot records on execution what plan will call the adapters with and
+// the hash of the audio, and returns the snapshot's steps
+func (u *UnifiedTranscriptionService) recordSnapshot(execution *models.TranscriptionJobExecution, plan *singleTrackPlan, audioPath string) []models.ExecutionStep {
+	if execution == nil {
+		return nil
+	}
+	snapshot := models.ExecutionSnapshot{Steps: u.snapshotSteps(plan), Preprocessing: plan.preprocessing, Chunking: plan.chunking}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		logger.Warn("Failed to encode execution snapshot", "execution_id", execution.ID, "error", err)
+		return snapshot.Steps
+	}
+	encoded := string(data)
+	envHash := environmentHash(snapshot.Steps)
+	execution.Snapshot = &encoded
+	execution.EnvironmentHash = &envHash
+	if inputHash, err := hashFile(audioPath); err == nil {
+		execution.InputHash = &inputHash
+	} else {
+		logger.Warn("Failed to hash job audio", "execution_id", execution.ID, "error", err)
+	}
+	return snapshot.Steps
+}
+
+// snapshotSteps describes the adapter calls of a plan with the adapters' current
+// versions and environments
+func (u *UnifiedTranscriptionService) snapshotSteps(plan *singleTrackPlan) []models.ExecutionStep {
+	var steps []models.ExecutionStep
+	describe := func(stage, modelID string, adapter interfaces.ModelAdapter, params map[string]interface{}) {
+		step := models.ExecutionStep{Stage: stage, ModelID: modelID, Parameters: params}
+		if adapter != nil {
+			step.AdapterVersion = adapter.GetCapabilities().Version
+			if fingerprinter, ok := adapter.(interfaces.EnvironmentFingerprinter); ok {
+				step.EnvironmentHash = fingerprinter.EnvironmentHash()
+			}
+			if reporter, ok := adapter.(interfaces.ComponentReporter); ok {
+				step.Packages = reporter.PackageVersions()
+				step.Checkpoints = reporter.Checkpoints(params)
+			}
+		}
+		steps = append(steps, step)
+	}
+	if plan.transcriptionModelID != "" {
+		adapter, _ := u.registry.GetTranscriptionAdapter(plan.transcriptionModelID)
+		describe(stageTranscription, plan.transcriptionModelID, adapter, plan.transcriptionParams)
+	}
+	if plan.diarizationModelID != "" {
+		adapter, _ := u.registry.GetDiarizationAdapter(plan.diarizationModelID)
+		describe(stageDiarization, plan.diarizationModelID, adapter, plan.diarizationParams)
+	}
+	return steps
+}
+
+// recordComponents records the adapters, packages and checkpoints of an
+// execution's snapshot steps, so the jobs a version produced can be found
+func (u *UnifiedTranscriptionService) recordComponents(ctx context.Context, execution *models.TranscriptionJobExecution, steps []models.ExecutionStep) {
+	if execution == nil || execution.ID == "" {
+		return
+	}
+	var components []models.ExecutionComponent
+	add := func(stage, kind, name, version string) {
+		components = append(components, models.ExecutionComponent{
+			ExecutionID:        execution.ID,
+			TranscriptionJobID: execution.TranscriptionJobID,
+			Stage:              stage,
+			Kind:               kind,
+			Name:               name,
+			Version:            version,
+		})
+	}
+	for _, step := range steps {
+		add(step.Stage, models.ComponentAdapter, step.ModelID, step.AdapterVersion)
+		for name, version := range step.Packages {
+			add(step.Stage, models.ComponentPackage, name, version)
+		}
+		for _, checkpoint := range step.Checkpoints {
+			add(step.Stage, models.ComponentCheckpoint, checkpoint, "")
+		}
+	}
+	if err := u.jobRepo.CreateExecutionComponents(ctx, components); err != nil {
+		logger.Warn("Failed to record execution components", "execution_id", execution.ID, "error", err)
+	}
+}
+
+// environmentHash combines the environment hashes of an execution's steps
+func environmentHash(steps []models.ExecutionStep) string {
+	h := sha256.New()
+	for _, step := range steps {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", step.Stage, step.ModelID, step.AdapterVersion, step.EnvironmentHash)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFile returns the hex SHA-256 of a file
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReplayExecution re-runs a job's execution with the parameters recorded in its
+// snapshot, in a sandbox directory, without touching the job. An empty
+// executionID replays the latest execution with a snapshot. The replay is recorded
+// as an execution of its own; a failing adapter is reported in the result rather
+// than as an error.
+func (u *UnifiedTranscriptionService) ReplayExecution(ctx context.Context, jobID, executionID string, keepSandbox bool) (*ReplayResult, error) {
+	job, err := u.jobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := u.jobRepo.FindExecution(ctx, jobID, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if original.Snapshot == nil {
+		return nil, ErrReplayUnsupported
+	}
+	var snapshot models.ExecutionSnapshot
+	if err := json.Unmarshal([]byte(*original.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse execution snapshot: %w", err)
+	}
+
+	plan := &singleTrackPlan{preprocessing: snapshot.Preprocessing, chunking: snapshot.Chunking}
+	var modelIDs []string
+	for _, step := range snapshot.Steps {
+		switch step.Stage {
+		case stageTranscription:
+			plan.transcriptionModelID, plan.transcriptionParams = step.ModelID, step.Parameters
+		case stageDiarization:
+			plan.diarizationModelID, plan.diarizationParams = step.ModelID, step.Parameters
+		default:
+			return nil, fmt.Errorf("unknown snapshot stage %q", step.Stage)
+		}
+		modelIDs = append(modelIDs, step.ModelID)
+	}
+	if pending := u.registry.PendingEnvironments(modelIDs...); len(pending) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrReplayEnvironmentPending, pending)
+	}
+
+	sandbox := filepath.Join(u.tempDirectory, "replay", jobID+"-"+uuid.New().String()[:8])
+	if err := os.MkdirAll(sandbox, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create replay sandbox: %w", err)
+	}
+	if !keepSandbox {
+		defer os.RemoveAll(sandbox)
+	}
+
+	replay := &models.TranscriptionJobExecution{
+		TranscriptionJobID: jobID,
+		StartedAt:          time.Now(),
+		ActualParameters:   original.ActualParameters,
+		Status:             models.StatusProcessing,
+		ReplayOf:           &original.ID,
+	}
+	u.recordSnapshot(replay, plan, job.AudioPath)
+	if err := u.jobRepo.CreateExecution(ctx, replay); err != nil {
+		return nil, fmt.Errorf("failed to create execution record: %w", err)
+	}
+
+	result := &ReplayResult{
+		Execution:          replay,
+		ReplayOf:           original.ID,
+		InputMatches:       original.InputHash != nil && replay.InputHash != nil && *original.InputHash == *replay.InputHash,
+		EnvironmentMatches: original.EnvironmentHash != nil && replay.EnvironmentHash != nil && *original.EnvironmentHash == *replay.EnvironmentHash,
+		Changes:            replayChanges(snapshot.Steps, u.snapshotSteps(plan)),
+	}
+	if !result.InputMatches {
+		result.Changes = append(result.Changes, "audio: content changed since the original execution")
+	}
+	if keepSandbox {
+		result.SandboxDir = sandbox
+	}
+
+	logger.Info("Replaying execution", "job_id", jobID, "execution_id", original.ID, "sandbox", sandbox)
+	procCtx := interfaces.ProcessingContext{
+		JobID:           jobID,
+		OutputDirectory: sandbox,
+		TempDirectory:   u.tempDirectory,
+		Metadata:        map[string]string{"replay_of": original.ID},
+	}
+	transcript, runErr := u.runSingleTrack(ctx, job.AudioPath, plan, procCtx)
+
+	completedAt := time.Now()
+	replay.CompletedAt = &completedAt
+	replay.CalculateProcessingDuration()
+	replay.Status = models.StatusCompleted
+	if runErr != nil {
+		msg := runErr.Error()
+		replay.Status = models.StatusFailed
+		replay.ErrorMessage = &msg
+		result.Error = msg
+	}
+	if err := u.jobRepo.UpdateExecution(ctx, replay); err != nil {
+		logger.Warn("Failed to update replay execution", "execution_id", replay.ID, "error", err)
+	}
+
+	if transcript != nil {
+		result.Transcript = transcript
+		if encoded, err := u.convertTranscriptResultToJSON(transcript); err == nil && job.Transcript != nil {
+			result.OutputMatches = encoded == *job.Transcript
+		}
+	}
+	return result, nil
+}
+
+// replayChanges describes how the adapters of a replay differ from the original's
+func replayChanges(original, current []models.ExecutionStep) []string {
+	changes := []string{}
+	for i, step := range original {
+		if i >= len(current) {
+			break
+		}
+		now := current[i]
+		if step.AdapterVersion != now.AdapterVersion {
+			changes = append(changes, fmt.Sprintf("%s: version %s -> %s", step.ModelID, step.AdapterVersion, now.AdapterVersion))
+		}
+		if step.EnvironmentHash != now.EnvironmentHash {
+			changes = append(changes, fmt.Sprintf("%s: environment changed", step.ModelID))
+		}
+		for name, version := range step.Packages {
+			if current := now.Packages[name]; current != version {
+				changes = append(changes, fmt.Sprintf("%s: %s %s -> %s", step.ModelID, name, version, current))
+			}
+		}
+	}
+	return changes
+}
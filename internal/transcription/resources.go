@@ -0,0 +1,193 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// defaultGPUHeadroomMB is the GPU memory left free for other processes and
+// CUDA's own overhead when deciding whether a local job fits
+const defaultGPUHeadroomMB = 512
+
+// localGPUMemoryMB is about how much GPU memory the local adapters other than
+// WhisperX load, in MiB
+var localGPUMemoryMB = map[string]int{
+	"parakeet":   4096,
+	"canary":     6144,
+	"pyannote":   2048,
+	"sortformer": 3072,
+}
+
+// whisperXGPUMemoryMB is about how much GPU memory WhisperX loads per Whisper
+// model size, alignment model included, in MiB
+var whisperXGPUMemoryMB = map[string]int{
+	"tiny":   1536,
+	"base":   1536,
+	"small":  2560,
+	"medium": 5120,
+	"turbo":  6144,
+	"large":  10240,
+}
+
+// whisperXModels are the model IDs of the local WhisperX adapter
+var whisperXModels = []string{"whisperx", interfaces.LocalWhisperX}
+
+// gpuMemoryNeeded estimates the GPU memory the local adapters of a job load on
+// backend, in MiB; zero when they run remotely or on the CPU
+func gpuMemoryNeeded(backend adapters.GPUBackend, params models.WhisperXParams, modelIDs ...string) int {
+	needed := 0
+	for _, modelID := range modelIDs {
+		if slices.Contains(whisperXModels, modelID) {
+			if backend.CTranslate2Device(params.Device) != "cuda" {
+				continue
+			}
+			size := strings.TrimSuffix(strings.ToLower(params.Model), ".en")
+			if strings.HasPrefix(size, "large") {
+				size = "large"
+			} else if strings.Contains(size, "turbo") {
+				size = "turbo"
+			}
+			if mb, ok := whisperXGPUMemoryMB[size]; ok {
+				needed += mb
+			} else {
+				needed += whisperXGPUMemoryMB["large"]
+			}
+			continue
+		}
+		if mb, ok := localGPUMemoryMB[modelID]; ok && backend.TorchDevice(params.Device) == "cuda" {
+			needed += mb
+		}
+	}
+	return needed
+}
+
+// isLocalModel reports whether an adapter runs on this host
+func isLocalModel(modelID string) bool {
+	_, ok := localGPUMemoryMB[modelID]
+	return ok || slices.Contains(whisperXModels, modelID)
+}
+
+// resourceGate keeps local jobs from starting while the host cannot fit them:
+// a job that needs more GPU memory than is free, or that would start while the
+// CPUs are overloaded, waits until a running local job finishes. The first
+// local job always starts, since there is nothing to wait for.
+type resourceGate struct {
+	mu sync.Mutex
+	// reserved is the GPU memory of running local jobs by job ID, which they
+	// may not have allocated yet while they load their models
+	reserved      map[string]int
+	headroomMB    int
+	maxLoadPerCPU float64 // zero ignores the CPU load
+	probe         func(ctx context.Context) adapters.HostResources
+}
+
+func newResourceGate() *resourceGate {
+	return &resourceGate{
+		reserved:   make(map[string]int),
+		headroomMB: defaultGPUHeadroomMB,
+		probe:      adapters.ProbeHostResources,
+	}
+}
+
+// acquire reserves the resources of a local job, or returns why it cannot start yet
+func (g *resourceGate) acquire(ctx context.Context, jobID string, gpuMB int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.reserved) == 0 {
+		g.reserved[jobID] = gpuMB
+		return nil
+	}
+
+	resources := g.probe(ctx)
+	if gpuMB > 0 {
+		if free, ok := resources.FreeGPUMB(); ok {
+			reserved, total := 0, 0
+			for _, mb := range g.reserved {
+				reserved += mb
+			}
+			for _, gpu := range resources.GPUs {
+				if gpu.TotalMB > total {
+					total = gpu.TotalMB
+				}
+			}
+			if gpuMB+g.headroomMB > free || reserved+gpuMB+g.headroomMB > total {
+				return fmt.Errorf("needs %d MiB of GPU memory, %d MiB free with %d MiB reserved by running jobs",
+					gpuMB, free, reserved)
+			}
+		}
+	}
+	if g.maxLoadPerCPU > 0 && resources.LoadPerCPU() >= g.maxLoadPerCPU {
+		return fmt.Errorf("CPU load is %.2f per CPU", resources.LoadPerCPU())
+	}
+	g.reserved[jobID] = gpuMB
+	return nil
+}
+
+// release frees the resources reserved by a job
+func (g *resourceGate) release(jobID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.reserved, jobID)
+}
+
+// ResourceStatus is what the host has to spare and what running local jobs reserved
+type ResourceStatus struct {
+	adapters.HostResources
+	LocalJobs     int     `json:"local_jobs"`
+	ReservedGPUMB int     `json:"reserved_gpu_mb"`
+	HeadroomMB    int     `json:"gpu_headroom_mb"`
+	MaxLoadPerCPU float64 `json:"max_load_per_cpu,omitempty"`
+}
+
+// status probes the host and reports it with the reservations of running jobs
+func (g *resourceGate) status(ctx context.Context) ResourceStatus {
+	resources := g.probe(ctx)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status := ResourceStatus{
+		HostResources: resources,
+		LocalJobs:     len(g.reserved),
+		HeadroomMB:    g.headroomMB,
+		MaxLoadPerCPU: g.maxLoadPerCPU,
+	}
+	for _, mb := range g.reserved {
+		status.ReservedGPUMB += mb
+	}
+	return status
+}
+
+// ResourceStatus reports the host resources local jobs are fitted into
+func (u *UnifiedTranscriptionService) ResourceStatus(ctx context.Context) ResourceStatus {
+	return u.resources.status(ctx)
+}
+
+// SetResourceLimits sets the GPU memory kept free when fitting local jobs,
+// and the load per CPU at which local jobs wait; zero ignores the CPU load
+func (u *UnifiedTranscriptionService) SetResourceLimits(gpuHeadroomMB int, maxLoadPerCPU float64) {
+	u.resources.mu.Lock()
+	defer u.resources.mu.Unlock()
+	u.resources.headroomMB = gpuHeadroomMB
+	u.resources.maxLoadPerCPU = maxLoadPerCPU
+}
+
+// reserveResources reserves the host resources of a job's local adapters,
+// returning a release func, or an error saying why the job must wait
+func (u *UnifiedTranscriptionService) reserveResources(ctx context.Context, job *models.TranscriptionJob, modelIDs ...string) (func(), error) {
+	if !slices.ContainsFunc(modelIDs, isLocalModel) {
+		return func() {}, nil
+	}
+	needed := gpuMemoryNeeded(adapters.DetectGPUBackend(), job.Parameters, modelIDs...)
+	if err := u.resources.acquire(ctx, job.ID, needed); err != nil {
+		return nil, err
+	}
+	logger.Debug("Reserved host resources", "job_id", job.ID, "gpu_mb", needed)
+	return func() { u.resources.release(job.ID) }, nil
+}
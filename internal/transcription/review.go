@@ -0,0 +1,87 @@
+package transcription
+
+import (
+	"context"
+	"sort"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// reviewTimingSlack is how far, in seconds, a word may stick out of its
+// segment and still count towards its confidence
+const reviewTimingSlack = 0.05
+
+// SetReviewThresholds flags the transcript segments whose confidence is below
+// confidence, and has jobs with at least minFlagged of them reviewed
+// (confidence 0: nothing is flagged; minFlagged 0: no job needs review)
+func (u *UnifiedTranscriptionService) SetReviewThresholds(confidence float64, minFlagged int) {
+	u.reviewConfidence = confidence
+	u.reviewMinFlagged = minFlagged
+}
+
+// flagLowConfidence flags a job's low-confidence segments in its transcript, before
+// it is saved, returning how many were flagged
+func (u *UnifiedTranscriptionService) flagLowConfidence(result *interfaces.TranscriptResult) int {
+	return flagLowConfidence(result, u.reviewConfidence)
+}
+
+// recordReview saves how many segments were flagged and whether the job needs
+// review, clearing the review of an earlier transcript
+func (u *UnifiedTranscriptionService) recordReview(ctx context.Context, job *models.TranscriptionJob, flagged int) {
+	job.LowConfidenceSegments = flagged
+	job.ReviewStatus = ""
+	if u.reviewMinFlagged > 0 && flagged >= u.reviewMinFlagged {
+		job.ReviewStatus = models.ReviewNeeded
+	}
+	job.ReviewedAt = nil
+	job.ReviewedBy = nil
+	if err := u.jobRepo.UpdateReview(ctx, job); err != nil {
+		logger.Warn("Failed to record low-confidence segments", "job_id", job.ID, "error", err)
+		return
+	}
+	if job.ReviewStatus == models.ReviewNeeded {
+		logger.Info("Transcript needs review", "job_id", job.ID, "low_confidence_segments", flagged)
+	}
+}
+
+// flagLowConfidence sets the confidence of each segment with scored words, and
+// flags those below threshold, returning how many it flagged. Words without a
+// score, which some adapters do not report, are left out.
+func flagLowConfidence(result *interfaces.TranscriptResult, threshold float64) int {
+	words := make([]interfaces.TranscriptWord, 0, len(result.WordSegments))
+	for _, word := range result.WordSegments {
+		if word.Score > 0 {
+			words = append(words, word)
+		}
+	}
+	sort.SliceStable(words, func(i, j int) bool { return words[i].Start < words[j].Start })
+
+	flagged := 0
+	for i := range result.Segments {
+		seg := &result.Segments[i]
+		seg.Confidence, seg.LowConfidence = nil, false
+		first := sort.Search(len(words), func(j int) bool { return words[j].Start >= seg.Start-reviewTimingSlack })
+		sum, count := 0.0, 0
+		for _, word := range words[first:] {
+			if word.Start >= seg.End {
+				break
+			}
+			if word.End <= seg.End+reviewTimingSlack {
+				sum += word.Score
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		confidence := sum / float64(count)
+		seg.Confidence = &confidence
+		if confidence < threshold {
+			seg.LowConfidence = true
+			flagged++
+		}
+	}
+	return flagged
+}
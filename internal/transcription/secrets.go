@@ -0,0 +1,48 @@
+package transcription
+
+import (
+	"context"
+	"maps"
+
+	"scriberr/internal/secrets"
+)
+
+// secretParameters are the adapter parameters that may reference a secret
+// store instead of holding the secret
+var secretParameters = []string{"hf_token"}
+
+// resolveSecretParams returns params with the secrets they reference in place
+// of the references, leaving params itself unchanged
+func resolveSecretParams(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	resolved, cloned := params, false
+	for _, name := range secretParameters {
+		value, ok := params[name].(string)
+		if !ok || !secrets.IsReference(value) {
+			continue
+		}
+		secret, err := secrets.Resolve(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		if !cloned {
+			resolved, cloned = maps.Clone(params), true
+		}
+		resolved[name] = secret
+	}
+	return resolved, nil
+}
+
+// withSecrets returns the plan to call the adapters with: p with the secrets
+// its parameters reference. Plans themselves keep the references, so that
+// snapshots and execution records never hold the secrets.
+func (p *singleTrackPlan) withSecrets(ctx context.Context) (*singleTrackPlan, error) {
+	resolved := *p
+	var err error
+	if resolved.transcriptionParams, err = resolveSecretParams(ctx, p.transcriptionParams); err != nil {
+		return nil, err
+	}
+	if resolved.diarizationParams, err = resolveSecretParams(ctx, p.diarizationParams); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
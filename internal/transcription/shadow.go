@@ -0,0 +1,198 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// Shadow evaluation settings
+const (
+	// shadowQueueSize bounds the jobs waiting for a shadow run; beyond it jobs are
+	// not sampled rather than delaying anything
+	shadowQueueSize = 32
+	// shadowIdlePoll is how often a waiting shadow run checks whether the queue
+	// has gone idle
+	shadowIdlePoll = 10 * time.Second
+)
+
+// shadowEvaluator sends a sample of completed jobs to a candidate adapter
+type shadowEvaluator struct {
+	modelID string
+	percent float64
+	queue   chan shadowJob
+}
+
+// shadowJob is a completed job waiting to be transcribed by the candidate
+type shadowJob struct {
+	jobID          string
+	audioPath      string
+	params         models.WhisperXParams
+	primaryModelID string
+	primaryText    string
+}
+
+// SetShadow sends percent of single-track jobs, once completed, to the candidate
+// transcription adapter modelID as well. Shadow runs happen one at a time while
+// no job is processing, and their results are only stored for comparison.
+func (u *UnifiedTranscriptionService) SetShadow(modelID string, percent float64) {
+	if modelID == "" || percent <= 0 {
+		return
+	}
+	u.shadow = &shadowEvaluator{
+		modelID: modelID,
+		percent: min(percent, 100),
+		queue:   make(chan shadowJob, shadowQueueSize),
+	}
+	go u.runShadowWorker()
+	logger.Info("Shadow evaluation enabled", "model_id", modelID, "percent", u.shadow.percent)
+}
+
+// offerShadow samples a job that the primary adapter has just transcribed
+func (u *UnifiedTranscriptionService) offerShadow(job *models.TranscriptionJob, plan *singleTrackPlan, result *interfaces.TranscriptResult) {
+	s := u.shadow
+	if s == nil || plan.transcriptionModelID == "" || plan.transcriptionModelID == s.modelID {
+		return
+	}
+	if rand.Float64()*100 >= s.percent {
+		return
+	}
+	select {
+	case s.queue <- shadowJob{
+		jobID:          job.ID,
+		audioPath:      job.AudioPath,
+		params:         job.Parameters,
+		primaryModelID: plan.transcriptionModelID,
+		primaryText:    transcriptText(result),
+	}:
+	default:
+		logger.Warn("Shadow queue is full, not sampling job", "job_id", job.ID)
+	}
+}
+
+// runShadowWorker runs queued shadow jobs in the background
+func (u *UnifiedTranscriptionService) runShadowWorker() {
+	for job := range u.shadow.queue {
+		// Shadow runs yield to real jobs and wait for the candidate's environment
+		for u.activeJobs.Load() > 0 || len(u.registry.PendingEnvironments(u.shadow.modelID)) > 0 {
+			time.Sleep(shadowIdlePoll)
+		}
+		u.runShadow(context.Background(), job)
+	}
+}
+
+// runShadow transcribes a job with the candidate adapter and records how its
+// text compares with the primary transcript
+func (u *UnifiedTranscriptionService) runShadow(ctx context.Context, job shadowJob) {
+	startedAt := time.Now()
+	run := &models.ShadowRun{
+		TranscriptionJobID: job.jobID,
+		ModelID:            u.shadow.modelID,
+		PrimaryModelID:     job.primaryModelID,
+		Status:             models.StatusProcessing,
+		StartedAt:          &startedAt,
+	}
+	if err := u.jobRepo.CreateShadowRun(ctx, run); err != nil {
+		logger.Warn("Failed to record shadow run", "job_id", job.jobID, "error", err)
+		return
+	}
+
+	result, err := u.transcribeShadow(ctx, run, job)
+
+	completedAt := time.Now()
+	duration := completedAt.Sub(startedAt).Milliseconds()
+	run.CompletedAt = &completedAt
+	run.ProcessingDuration = &duration
+	run.Status = models.StatusCompleted
+	if err == nil {
+		var encoded string
+		if encoded, err = u.convertTranscriptResultToJSON(result); err == nil {
+			agreement := wordAgreement(job.primaryText, transcriptText(result))
+			run.Transcript = &encoded
+			run.WordAgreement = &agreement
+		}
+	}
+	if err != nil {
+		msg := err.Error()
+		run.Status = models.StatusFailed
+		run.ErrorMessage = &msg
+		logger.Warn("Shadow run failed", "job_id", job.jobID, "model_id", run.ModelID, "error", err)
+	}
+	if err := u.jobRepo.UpdateShadowRun(ctx, run); err != nil {
+		logger.Warn("Failed to update shadow run", "shadow_run_id", run.ID, "error", err)
+	}
+}
+
+// transcribeShadow runs the candidate in a scratch directory of its own
+func (u *UnifiedTranscriptionService) transcribeShadow(ctx context.Context, run *models.ShadowRun, job shadowJob) (*interfaces.TranscriptResult, error) {
+	sandbox := filepath.Join(u.tempDirectory, "shadow", run.ID)
+	if err := os.MkdirAll(sandbox, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+	defer os.RemoveAll(sandbox)
+
+	// Only the transcription is compared, so the candidate skips diarization
+	params := job.params
+	params.Diarize = false
+	plan := &singleTrackPlan{
+		transcriptionModelID: run.ModelID,
+		transcriptionParams:  u.convertParametersForModel(params, run.ModelID),
+	}
+	if preprocessing := params.Preprocessing(); preprocessing.Enabled() {
+		plan.preprocessing = &preprocessing
+	}
+	procCtx := interfaces.ProcessingContext{
+		JobID:           job.jobID,
+		OutputDirectory: sandbox,
+		TempDirectory:   u.tempDirectory,
+		Metadata:        map[string]string{"shadow_of": job.jobID},
+	}
+	return u.runSingleTrack(ctx, job.audioPath, plan, procCtx)
+}
+
+// transcriptText returns the text of a transcript, joining its segments when the
+// adapter left the full text empty
+func transcriptText(result *interfaces.TranscriptResult) string {
+	if result == nil {
+		return ""
+	}
+	if result.Text != "" {
+		return result.Text
+	}
+	texts := make([]string, len(result.Segments))
+	for i, seg := range result.Segments {
+		texts[i] = seg.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// wordAgreement returns 1 minus the word error rate of hypothesis against
+// reference, floored at 0. Case and punctuation are ignored.
+func wordAgreement(reference, hypothesis string) float64 {
+	ref, hyp := comparableWords(reference), comparableWords(hypothesis)
+	if len(ref) == 0 {
+		if len(hyp) == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	errorRate := float64(editDistances(ref, hyp)[len(hyp)]) / float64(len(ref))
+	return max(0, 1-errorRate)
+}
+
+// comparableWords splits text into lowercase words without punctuation
+func comparableWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
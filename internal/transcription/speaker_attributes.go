@@ -0,0 +1,81 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+
+	"scriberr/internal/models"
+	"scriberr/internal/service"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+)
+
+// speakerAttributeEstimatorID is the diarization adapter that estimates speaker attributes
+const speakerAttributeEstimatorID = "pyannote"
+
+// SetSpeakerAttributes enables estimating speaker attributes for jobs that opt in
+func (u *UnifiedTranscriptionService) SetSpeakerAttributes(s service.SpeakerAttributeService) {
+	u.speakerAttributes = s
+}
+
+// estimateSpeakerAttributes records the coarse gender and age range of each
+// diarized speaker, unless the job's workspace disabled it. Failures leave the
+// job without speaker attributes.
+func (u *UnifiedTranscriptionService) estimateSpeakerAttributes(ctx context.Context, job *models.TranscriptionJob, procCtx interfaces.ProcessingContext, result *interfaces.TranscriptResult) {
+	if u.speakerAttributes == nil {
+		return
+	}
+	if !u.speakerAttributes.Allowed(ctx, job.Workspace) {
+		logger.Info("Speaker attributes are disabled in the job's workspace", "job_id", job.ID)
+		return
+	}
+	turns := speakerTurns(result)
+	if len(turns) == 0 {
+		return
+	}
+	adapter, err := u.registry.GetDiarizationAdapter(speakerAttributeEstimatorID)
+	estimator, ok := adapter.(interfaces.SpeakerAttributeEstimator)
+	if err != nil || !ok {
+		logger.Warn("No adapter can estimate speaker attributes", "job_id", job.ID, "adapter", speakerAttributeEstimatorID)
+		return
+	}
+	input, err := u.createAudioInput(job.AudioPath)
+	if err != nil {
+		logger.Warn("Failed to read job audio for speaker attributes", "job_id", job.ID, "error", err)
+		return
+	}
+
+	estimateCtx, span := tracing.Start(ctx, "speaker_attributes.estimate")
+	estimates, model, err := estimator.EstimateSpeakerAttributes(estimateCtx, input, turns, procCtx)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		logger.Warn("Failed to estimate speaker attributes", "job_id", job.ID, "error", err)
+		return
+	}
+	voices := make(map[string]service.VoiceEstimate, len(estimates))
+	for speaker, estimate := range estimates {
+		voices[speaker] = service.VoiceEstimate(estimate)
+	}
+	attributes, err := u.speakerAttributes.Record(ctx, job.ID, job.Workspace, model, voices)
+	if err != nil {
+		if !errors.Is(err, service.ErrSpeakerAttributesDisabled) {
+			logger.Warn("Failed to record speaker attributes", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+	logger.Info("Estimated speaker attributes", "job_id", job.ID, "speakers", len(attributes))
+}
+
+// speakerTurns returns the diarized turns of a transcript's segments
+func speakerTurns(result *interfaces.TranscriptResult) []interfaces.SpeakerTurn {
+	var turns []interfaces.SpeakerTurn
+	for _, seg := range result.Segments {
+		if seg.Speaker == nil || *seg.Speaker == "" || seg.End <= seg.Start {
+			continue
+		}
+		turns = append(turns, interfaces.SpeakerTurn{Speaker: *seg.Speaker, Start: seg.Start, End: seg.End})
+	}
+	return turns
+}
@@ -3,6 +3,7 @@ package transcription
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,15 +11,21 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"scriberr/internal/models"
+	"scriberr/internal/queue"
 	"scriberr/internal/repository"
+	"scriberr/internal/scanner"
+	"scriberr/internal/service"
+	"scriberr/internal/transcription/adapters"
 	"scriberr/internal/transcription/interfaces"
 	"scriberr/internal/transcription/pipeline"
 	"scriberr/internal/transcription/registry"
 	"scriberr/internal/webhook"
 	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
 )
 
 // UnifiedTranscriptionService provides a unified interface for all transcription and diarization models
@@ -33,6 +40,22 @@ type UnifiedTranscriptionService struct {
 	multiTrackTranscriber *MultiTrackTranscriber // For termination support
 	jobRepo               repository.JobRepository
 	webhookService        *webhook.Service
+	scanner               scanner.Scanner // nil when upload scanning is disabled
+	voiceprints           service.VoiceprintService
+	vocabularies          repository.VocabularyRepository     // nil leaves custom vocabularies unused
+	correctionRules       repository.CorrectionRuleRepository // nil leaves transcripts uncorrected
+	reviewConfidence      float64                             // segments below it are flagged for review; 0 flags none
+	reviewMinFlagged      int                                 // flagged segments that put a job up for review; 0: never
+	speakerAttributes     service.SpeakerAttributeService     // nil unless speaker attributes can be estimated
+	shadow                *shadowEvaluator                    // nil unless shadow evaluation is enabled
+	benchmarks            *benchmarkRunner                    // nil unless this process runs benchmarks
+	denoiseModel          string                              // RNNoise model for profiles that denoise; empty skips denoising
+	chunker               *chunker                            // nil unless long audio is chunked
+	costs                 CostModel                           // per-minute adapter prices; empty leaves executions uncosted
+	resources             *resourceGate                       // keeps local jobs from starting until the host fits them
+	adapterConfigs        *adapterConfigurator                // nil unless adapters are registered with stored settings
+	preparesEnvironments  atomic.Bool                         // set once this process prepares adapter environments
+	activeJobs            atomic.Int32                        // jobs being processed, which shadow and benchmark runs wait for
 }
 
 // NewUnifiedTranscriptionService creates a new unified transcription service
@@ -50,9 +73,25 @@ func NewUnifiedTranscriptionService(jobRepo repository.JobRepository) *UnifiedTr
 		},
 		jobRepo:        jobRepo,
 		webhookService: webhook.NewService(),
+		resources:      newResourceGate(),
 	}
 }
 
+// SetScanner enables scanning of a job's media before it is processed
+func (u *UnifiedTranscriptionService) SetScanner(s scanner.Scanner) {
+	u.scanner = s
+}
+
+// SetVoiceprints enables naming diarized speakers after enrolled voiceprints
+func (u *UnifiedTranscriptionService) SetVoiceprints(v service.VoiceprintService) {
+	u.voiceprints = v
+}
+
+// SetDenoiseModel sets the RNNoise model used by profiles that denoise their audio
+func (u *UnifiedTranscriptionService) SetDenoiseModel(path string) {
+	u.denoiseModel = path
+}
+
 // Initialize prepares all registered models for use
 func (u *UnifiedTranscriptionService) Initialize(ctx context.Context) error {
 	logger.Info("Initializing unified transcription service")
@@ -74,10 +113,46 @@ func (u *UnifiedTranscriptionService) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// scanJob scans the job's media once. Flagged jobs are quarantined; scanner
+// errors fail the job rather than letting unscanned media through.
+func (u *UnifiedTranscriptionService) scanJob(ctx context.Context, job *models.TranscriptionJob) error {
+	if u.scanner == nil || job.ScanStatus == models.ScanClean || job.ScanStatus == models.ScanReleased {
+		return nil
+	}
+
+	paths := []string{job.AudioPath}
+	for _, track := range job.MultiTrackFiles {
+		paths = append(paths, track.FilePath)
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		result, err := u.scanner.Scan(ctx, path)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %w", filepath.Base(path), err)
+		}
+		if !result.Clean {
+			logger.Warn("Upload flagged by scanner", "job_id", job.ID, "file", filepath.Base(path), "signature", result.Signature)
+			if err := u.jobRepo.UpdateScanResult(ctx, job.ID, models.ScanQuarantined, &result.Signature); err != nil {
+				return fmt.Errorf("failed to record scan result: %w", err)
+			}
+			return fmt.Errorf("%w: %s flagged as %s", queue.ErrJobQuarantined, filepath.Base(path), result.Signature)
+		}
+	}
+
+	if err := u.jobRepo.UpdateScanResult(ctx, job.ID, models.ScanClean, nil); err != nil {
+		return fmt.Errorf("failed to record scan result: %w", err)
+	}
+	return nil
+}
+
 // ProcessJob processes a transcription job using the new adapter architecture
 func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID string) error {
 	startTime := time.Now()
 	logger.Info("Processing job with unified service", "job_id", jobID)
+	u.activeJobs.Add(1)
+	defer u.activeJobs.Add(-1)
 
 	// Get the job from database
 	// Get the job from database
@@ -86,6 +161,33 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 		return fmt.Errorf("failed to get job: %w", err)
 	}
 
+	// Identical audio already transcribed with the job's profile needs none of
+	// the adapters or resources below, though it is still scanned and its usage
+	// recorded
+	original := u.findDuplicate(ctx, job)
+
+	// Leave the job queued while the environments it needs are still being installed
+	transcriptionModelID, diarizationModelID, err := u.selectModels(job.Parameters)
+	if err == nil && original == nil {
+		if pending := u.registry.PendingEnvironments(transcriptionModelID, diarizationModelID); len(pending) > 0 {
+			return fmt.Errorf("%w: waiting for model environments to be ready: %s",
+				queue.ErrJobDeferred, strings.Join(pending, ", "))
+		}
+	}
+
+	// Leave it queued too while the host cannot fit its local adapters next to the running jobs
+	if err == nil && original == nil {
+		release, err := u.reserveResources(ctx, job, transcriptionModelID, diarizationModelID)
+		if err != nil {
+			return fmt.Errorf("%w: waiting for host resources: %v", queue.ErrJobDeferred, err)
+		}
+		defer release()
+	}
+
+	if err := u.scanJob(ctx, job); err != nil {
+		return err
+	}
+
 	// Create execution record
 	execution := &models.TranscriptionJobExecution{
 		TranscriptionJobID: jobID,
@@ -97,6 +199,9 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 	if err := u.jobRepo.CreateExecution(ctx, execution); err != nil {
 		return fmt.Errorf("failed to create execution record: %w", err)
 	}
+	if err := u.jobRepo.UpdateProgress(ctx, jobID, 0); err != nil {
+		logger.Debug("Failed to reset job progress", "job_id", jobID, "error", err)
+	}
 
 	// Helper function to update execution status
 	updateExecutionStatus := func(status models.JobStatus, errorMsg string) {
@@ -109,7 +214,8 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 			execution.ErrorMessage = &errorMsg
 		}
 
-		u.jobRepo.UpdateExecution(ctx, execution)
+		// Recorded even when the job was cancelled
+		u.jobRepo.UpdateExecution(context.WithoutCancel(ctx), execution)
 
 		// Trigger webhook if callback URL is present
 		if job.Parameters.CallbackURL != nil && *job.Parameters.CallbackURL != "" {
@@ -134,46 +240,120 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 				webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 				defer cancel()
 
-				if err := u.webhookService.SendWebhook(webhookCtx, *job.Parameters.CallbackURL, payload); err != nil {
+				var err error
+				if tmpl := notificationTemplate(webhookCtx, models.NotificationChannelWebhook); tmpl != nil {
+					// Render with the job as saved by the run
+					current := job
+					if saved, findErr := u.jobRepo.FindByID(webhookCtx, job.ID); findErr == nil {
+						current = saved
+					}
+					data := webhook.NewTemplateData(current, string(status))
+					data.Status = string(status)
+					data.ErrorMessage = ""
+					if execution.ErrorMessage != nil {
+						data.ErrorMessage = *execution.ErrorMessage
+					}
+					data.Metadata = payload.Metadata
+					data.CompletedAt = completedAt
+					err = u.webhookService.SendTemplatedWebhook(webhookCtx, *job.Parameters.CallbackURL, tmpl, data)
+				} else {
+					err = u.webhookService.SendWebhook(webhookCtx, *job.Parameters.CallbackURL, payload)
+				}
+				if err != nil {
 					logger.Error("Failed to send webhook", "job_id", job.ID, "error", err)
 				}
 			}()
 		}
 	}
 
-	// Check for multi-track processing
-	if job.IsMultiTrack && job.Parameters.IsMultiTrackEnabled {
+	// Reuse the transcript of identical audio, or check for multi-track processing
+	if original != nil {
+		if err := u.reuseTranscript(ctx, job, original); err != nil {
+			err = fmt.Errorf("failed to reuse transcript of job %s: %w", original.ID, err)
+			updateExecutionStatus(failedStatus(ctx), err.Error())
+			return err
+		}
+	} else if job.IsMultiTrack && job.Parameters.IsMultiTrackEnabled {
 		logger.Info("Processing multi-track job", "job_id", jobID)
 		if err := u.processMultiTrackJob(ctx, job); err != nil {
-			errMsg := fmt.Sprintf("multi-track processing failed: %v", err)
-			updateExecutionStatus(models.StatusFailed, errMsg)
-			return fmt.Errorf("%s", errMsg)
+			err = fmt.Errorf("multi-track processing failed: %w", err)
+			updateExecutionStatus(failedStatus(ctx), err.Error())
+			return err
 		}
 	} else {
 		// Process single track
-		if err := u.processSingleTrackJob(ctx, job); err != nil {
-			errMsg := fmt.Sprintf("single-track processing failed: %v", err)
-			updateExecutionStatus(models.StatusFailed, errMsg)
-			return fmt.Errorf("%s", errMsg)
+		if err := u.processSingleTrackJob(ctx, job, execution); err != nil {
+			err = fmt.Errorf("single-track processing failed: %w", err)
+			updateExecutionStatus(failedStatus(ctx), err.Error())
+			return err
 		}
 	}
 
 	// Success
+	if err := u.jobRepo.UpdateProgress(ctx, jobID, 100); err != nil {
+		logger.Debug("Failed to update job progress", "job_id", jobID, "error", err)
+	}
+	u.recordUsage(ctx, job, execution, original != nil)
 	updateExecutionStatus(models.StatusCompleted, "")
 	logger.Info("Job processed successfully", "job_id", jobID, "duration", time.Since(startTime))
 	return nil
 }
 
-// processSingleTrackJob handles single audio file transcription
-func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context, job *models.TranscriptionJob) error {
+// failedStatus is the status of an execution that did not complete: cancelled
+// when the job was cancelled, failed otherwise
+func failedStatus(ctx context.Context) models.JobStatus {
+	if errors.Is(context.Cause(ctx), queue.ErrJobCancelled) {
+		return models.StatusCancelled
+	}
+	return models.StatusFailed
+}
+
+// classifyFailure gives the error a single-track job failed with its error
+// code, from the error and the stage the job was in
+func classifyFailure(err error, stage string) error {
+	switch {
+	case errors.Is(err, interfaces.ErrInvalidParameters):
+		return models.WithErrorCode(models.ErrorInvalidParams, err)
+	case stage == models.StageDiarizing && outOfMemory(err):
+		return models.WithErrorCode(models.ErrorDiarizationOOM, err)
+	}
+	return err
+}
+
+// outOfMemory reports whether err looks like a model ran out of GPU or host
+// memory: PyTorch's errors, or the process being killed by the OOM killer
+func outOfMemory(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, sign := range []string{"out of memory", "outofmemoryerror", "bad_alloc", "signal: killed"} {
+		if strings.Contains(message, sign) {
+			return true
+		}
+	}
+	return false
+}
+
+// processSingleTrackJob handles single audio file transcription, recording on
+// execution what the adapters were called with
+func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context, job *models.TranscriptionJob, execution *models.TranscriptionJobExecution) error {
 	logger.Info("Processing single-track job", "job_id", job.ID, "model_family", job.Parameters.ModelFamily)
 
+	// Outputs are partitioned by workspace like uploads
+	outputDir, err := service.WorkspaceDir(u.outputDirectory, job.Workspace)
+	if err != nil {
+		return err
+	}
+
 	// Create processing context
+	progress := newJobProgress(ctx, u.jobRepo, job.ID)
 	procCtx := interfaces.ProcessingContext{
 		JobID:           job.ID,
-		OutputDirectory: filepath.Join(u.outputDirectory, job.ID),
+		OutputDirectory: filepath.Join(outputDir, job.ID),
 		TempDirectory:   u.tempDirectory,
 		Metadata:        map[string]string{},
+		Progress:        progress.report,
+	}
+	if job.AudioUri != nil && strings.HasPrefix(*job.AudioUri, "s3://") {
+		procCtx.AudioURI = *job.AudioUri
 	}
 
 	// Create output directory
@@ -181,36 +361,181 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Create audio input
-	audioInput, err := u.createAudioInput(job.AudioPath)
+	// Determine models to use first
+	plan, err := u.planSingleTrack(ctx, job)
 	if err != nil {
-		return fmt.Errorf("failed to create audio input: %w", err)
+		return err
 	}
 
-	// Determine models to use first
+	// The snapshot records the plan that ran last, a fallback's if the job
+	// failed over, so a replay reproduces the result
+	transcriptResult, plan, err := u.runWithFailover(ctx, job, plan, procCtx, execution)
+	steps := u.recordSnapshot(execution, plan, job.AudioPath)
+	u.recordComponents(ctx, execution, steps)
+	if err != nil {
+		return classifyFailure(err, progress.currentStage())
+	}
+
+	// Save results to database
+	if transcriptResult != nil {
+		u.correctVocabulary(ctx, job, transcriptResult)
+		u.applyCorrectionRules(ctx, job, transcriptResult)
+		u.applyVocabularyFilter(ctx, job, transcriptResult)
+		flagged := u.flagLowConfidence(transcriptResult)
+		if err := u.saveTranscriptionResults(ctx, job.ID, transcriptResult); err != nil {
+			return fmt.Errorf("failed to save transcription results: %w", err)
+		}
+		u.recordReview(ctx, job, flagged)
+
+		// Record whether word-level alignment had to be skipped; a re-run may clear it
+		var alignmentFallback *string
+		if reason := transcriptResult.Metadata[interfaces.MetadataAlignmentFallback]; reason != "" {
+			alignmentFallback = &reason
+		}
+		if err := u.jobRepo.UpdateAlignmentFallback(ctx, job.ID, alignmentFallback); err != nil {
+			logger.Warn("Failed to record alignment fallback", "job_id", job.ID, "error", err)
+		}
+
+		if skipped, err := strconv.ParseFloat(transcriptResult.Metadata[interfaces.MetadataSkippedSeconds], 64); err == nil {
+			execution.SkippedSeconds = &skipped
+		}
+
+		if job.Parameters.Diarize {
+			u.identifySpeakers(ctx, job, transcriptResult)
+			if job.Parameters.SpeakerAttributes {
+				u.estimateSpeakerAttributes(ctx, job, procCtx, transcriptResult)
+			}
+		}
+
+		u.offerShadow(job, plan, transcriptResult)
+	}
+
+	return nil
+}
+
+// singleTrackPlan is what a single-track job runs: the adapters and the exact
+// parameters each is called with. Replays rebuild it from an execution snapshot.
+type singleTrackPlan struct {
+	transcriptionModelID string
+	transcriptionParams  map[string]interface{}
+	// diarizationModelID is set when diarization runs separately from transcription
+	diarizationModelID string
+	diarizationParams  map[string]interface{}
+	// preprocessing is run over the audio before the adapters; nil when disabled
+	preprocessing *models.AudioPreprocessing
+	// chunking splits the audio into chunks transcribed in parallel; nil when
+	// the audio is not long enough to be chunked
+	chunking *models.AudioChunking
+}
+
+// planSingleTrack selects the job's adapters and resolves their parameters
+func (u *UnifiedTranscriptionService) planSingleTrack(ctx context.Context, job *models.TranscriptionJob) (*singleTrackPlan, error) {
 	transcriptionModelID, diarizationModelID, err := u.selectModels(job.Parameters)
 	if err != nil {
-		return fmt.Errorf("failed to select models: %w", err)
+		return nil, fmt.Errorf("failed to select models: %w", err)
+	}
+
+	// Voice embeddings are needed to name speakers after enrolled voiceprints
+	jobParams := job.Parameters
+	if jobParams.Diarize && u.voiceprints != nil && u.voiceprints.HasVoiceprints(ctx, job.Workspace) {
+		jobParams.SpeakerEmbeddings = true
+	}
+	u.applyVocabulary(ctx, job, &jobParams)
+
+	plan := &singleTrackPlan{transcriptionModelID: transcriptionModelID}
+	if preprocessing := job.Parameters.Preprocessing(); preprocessing.Enabled() {
+		plan.preprocessing = &preprocessing
+	}
+	// Chunks diarized on their own would number their speakers independently,
+	// so chunked audio is diarized separately over the whole file
+	includesDiarization := u.transcriptionIncludesDiarization(transcriptionModelID, job.Parameters)
+	if transcriptionModelID != "" {
+		plan.chunking = u.chunkingFor(job.AudioPath)
+		transcriptionParams := jobParams
+		if plan.chunking != nil && includesDiarization {
+			transcriptionParams.Diarize = false
+			includesDiarization = false
+		}
+		// Convert parameters for this specific model
+		plan.transcriptionParams = u.convertParametersForModel(transcriptionParams, transcriptionModelID)
+	}
+	// Diarize separately if requested and not already done by transcription
+	if job.Parameters.Diarize && diarizationModelID != "" && !includesDiarization {
+		plan.diarizationModelID = diarizationModelID
+		plan.diarizationParams = u.convertParametersForModel(jobParams, diarizationModelID)
+	}
+	return plan, nil
+}
+
+// runSingleTrack runs a plan against an audio file
+func (u *UnifiedTranscriptionService) runSingleTrack(ctx context.Context, audioPath string, plan *singleTrackPlan, procCtx interfaces.ProcessingContext) (*interfaces.TranscriptResult, error) {
+	// Create audio input
+	audioInput, err := u.createAudioInput(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audio input: %w", err)
+	}
+	return u.runAudio(ctx, audioInput, plan, procCtx)
+}
+
+// runAudio runs a plan against audio already probed
+func (u *UnifiedTranscriptionService) runAudio(ctx context.Context, audioInput interfaces.AudioInput, plan *singleTrackPlan, procCtx interfaces.ProcessingContext) (*interfaces.TranscriptResult, error) {
+	plan, err := plan.withSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tempFilesToCleanup []string
+	// Ensure cleanup of temporary files when function exits
+	defer func() {
+		for _, tempFile := range tempFilesToCleanup {
+			if err := os.Remove(tempFile); err != nil {
+				logger.Warn("Failed to clean up temporary file", "file", tempFile, "error", err)
+			} else {
+				logger.Info("Cleaned up temporary file", "file", tempFile)
+			}
+		}
+	}()
+
+	// Run the profile's audio preprocessing; trimmed leading silence is added
+	// back to the transcript's timestamps
+	procCtx.ReportProgress(interfaces.ProgressConverting, -1)
+	var trimmedStart float64
+	if plan.preprocessing != nil {
+		tempDir := procCtx.TempDirectory
+		if tempDir == "" {
+			tempDir = u.tempDirectory
+		}
+		enhanceCtx, span := tracing.Start(ctx, "audio.enhance")
+		enhanced, start, err := pipeline.Enhance(enhanceCtx, audioInput, u.enhancement(*plan.preprocessing), tempDir)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			logger.Warn("Audio enhancement failed, using original", "error", err)
+		} else if enhanced.TempFilePath != "" {
+			tempFilesToCleanup = append(tempFilesToCleanup, enhanced.TempFilePath)
+			audioInput, trimmedStart = enhanced, start
+		}
 	}
 
 	// Apply preprocessing to ensure audio is in correct format (mono 16kHz)
 	var preprocessedInput interfaces.AudioInput
-	var tempFilesToCleanup []string
 
 	// Get model capabilities for preprocessing decisions
 	var capabilities interfaces.ModelCapabilities
-	if transcriptionModelID != "" {
-		if adapter, err := u.registry.GetTranscriptionAdapter(transcriptionModelID); err == nil {
+	if plan.transcriptionModelID != "" {
+		if adapter, err := u.registry.GetTranscriptionAdapter(plan.transcriptionModelID); err == nil {
 			capabilities = adapter.GetCapabilities()
 		}
-	} else if diarizationModelID != "" {
-		if adapter, err := u.registry.GetDiarizationAdapter(diarizationModelID); err == nil {
+	} else if plan.diarizationModelID != "" {
+		if adapter, err := u.registry.GetDiarizationAdapter(plan.diarizationModelID); err == nil {
 			capabilities = adapter.GetCapabilities()
 		}
 	}
 
 	// Apply preprocessing
-	preprocessedInput, err = u.pipeline.ProcessAudio(ctx, audioInput, capabilities)
+	preprocessCtx, span := tracing.Start(ctx, "audio.preprocess")
+	preprocessedInput, err = u.pipeline.ProcessAudio(preprocessCtx, audioInput, capabilities)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		logger.Warn("Audio preprocessing failed, using original", "error", err)
 		preprocessedInput = audioInput
@@ -228,70 +553,151 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 		}
 	}
 
-	// Ensure cleanup of temporary files when function exits
-	defer func() {
-		for _, tempFile := range tempFilesToCleanup {
-			if err := os.Remove(tempFile); err != nil {
-				logger.Warn("Failed to clean up temporary file", "file", tempFile, "error", err)
-			} else {
-				logger.Info("Cleaned up temporary file", "file", tempFile)
-			}
+	// Silence and music are cut out before remote adapters; the transcript's
+	// timestamps are mapped back afterwards
+	var speech pipeline.SpeechMap
+	if cutsToSpeech(plan) {
+		var cut interfaces.AudioInput
+		cut, speech = u.cutToSpeech(ctx, preprocessedInput, procCtx)
+		if cut.TempFilePath != "" && cut.TempFilePath != preprocessedInput.TempFilePath {
+			tempFilesToCleanup = append(tempFilesToCleanup, cut.TempFilePath)
 		}
-	}()
+		preprocessedInput = cut
+	}
 
 	var transcriptResult *interfaces.TranscriptResult
 	var diarizationResult *interfaces.DiarizationResult
 
+	// Audio preparation is counted as the first 5% of the job, separate
+	// diarization as the 5% before it is saved
+	transcribeCtx, diarizeCtx := procCtx, procCtx
+	transcribeCtx.Progress = scaleProgress(procCtx.Progress, 5, 95)
+	if plan.diarizationModelID != "" {
+		transcribeCtx.Progress = scaleProgress(procCtx.Progress, 5, 90)
+		diarizeCtx.Progress = scaleProgress(procCtx.Progress, 90, 95)
+	}
+
 	// Perform transcription using the preprocessed audio
-	if transcriptionModelID != "" {
-		logger.Info("Running transcription", "model_id", transcriptionModelID)
-		transcriptionAdapter, err := u.registry.GetTranscriptionAdapter(transcriptionModelID)
+	if plan.transcriptionModelID != "" {
+		logger.Info("Running transcription", "model_id", plan.transcriptionModelID)
+		transcribeCtx.ReportProgress(interfaces.ProgressTranscribing, 0)
+		transcriptionAdapter, err := u.registry.GetTranscriptionAdapter(plan.transcriptionModelID)
 		if err != nil {
-			return fmt.Errorf("failed to get transcription adapter: %w", err)
+			return nil, fmt.Errorf("failed to get transcription adapter: %w", err)
 		}
 
-		// Convert parameters for this specific model
-		params := u.convertParametersForModel(job.Parameters, transcriptionModelID)
+		// Long audio is split in pauses and its chunks transcribed in
+		// parallel; if it cannot be split it is transcribed whole
+		var chunks []pipeline.Chunk
+		var chunkInputs []interfaces.AudioInput
+		if plan.chunking != nil {
+			chunks, chunkInputs, err = u.splitForChunking(ctx, preprocessedInput, *plan.chunking, procCtx)
+			if err != nil {
+				logger.Warn("Audio chunking failed, transcribing the whole file", "error", err)
+			}
+			for _, input := range chunkInputs {
+				tempFilesToCleanup = append(tempFilesToCleanup, input.TempFilePath)
+			}
+			if len(chunkInputs) > 0 {
+				// Removed last, once emptied of the chunks
+				tempFilesToCleanup = append(tempFilesToCleanup, filepath.Dir(chunkInputs[0].FilePath))
+			}
+		}
 
-		transcriptResult, err = transcriptionAdapter.Transcribe(ctx, preprocessedInput, params, procCtx)
+		adapterCtx, span := tracing.Start(ctx, "adapter.transcribe", tracing.String("adapter.model_id", plan.transcriptionModelID))
+		if len(chunkInputs) > 1 {
+			transcriptResult, err = u.transcribeChunks(adapterCtx, transcriptionAdapter, chunks, chunkInputs, plan.transcriptionParams, transcribeCtx)
+		} else {
+			transcriptResult, err = transcriptionAdapter.Transcribe(adapterCtx, preprocessedInput, plan.transcriptionParams, transcribeCtx)
+		}
+		span.RecordError(err)
+		span.End()
 		if err != nil {
-			return fmt.Errorf("transcription failed: %w", err)
+			return nil, fmt.Errorf("transcription failed: %w", err)
 		}
+		transcribeCtx.ReportProgress(interfaces.ProgressTranscribing, 100)
 	}
 
-	// Perform diarization if requested and not already done by transcription
-	if job.Parameters.Diarize && diarizationModelID != "" {
-		// Convert parameters for diarization model
-		diarizationParams := u.convertParametersForModel(job.Parameters, diarizationModelID)
+	if plan.diarizationModelID != "" {
+		logger.Info("Running separate diarization", "model_id", plan.diarizationModelID)
+		diarizationAdapter, err := u.registry.GetDiarizationAdapter(plan.diarizationModelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diarization adapter: %w", err)
+		}
 
-		if !u.transcriptionIncludesDiarization(transcriptionModelID, job.Parameters) {
-			logger.Info("Running separate diarization", "model_id", diarizationModelID)
-			diarizationAdapter, err := u.registry.GetDiarizationAdapter(diarizationModelID)
-			if err != nil {
-				return fmt.Errorf("failed to get diarization adapter: %w", err)
-			}
+		// Use the same preprocessed audio for diarization
+		adapterCtx, span := tracing.Start(ctx, "adapter.diarize", tracing.String("adapter.model_id", plan.diarizationModelID))
+		diarizeCtx.ReportProgress(interfaces.ProgressDiarizing, 0)
+		diarizationResult, err = diarizationAdapter.Diarize(adapterCtx, preprocessedInput, plan.diarizationParams, diarizeCtx)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, fmt.Errorf("diarization failed: %w", err)
+		}
+		diarizeCtx.ReportProgress(interfaces.ProgressDiarizing, 100)
 
-			// Use the same preprocessed audio for diarization
-			diarizationResult, err = diarizationAdapter.Diarize(ctx, preprocessedInput, diarizationParams, procCtx)
-			if err != nil {
-				return fmt.Errorf("diarization failed: %w", err)
-			}
+		// Merge diarization results with transcription
+		if transcriptResult != nil && diarizationResult != nil {
+			transcriptResult = u.mergeDiarizationWithTranscription(transcriptResult, diarizationResult)
+		}
+	}
 
-			// Merge diarization results with transcription
-			if transcriptResult != nil && diarizationResult != nil {
-				transcriptResult = u.mergeDiarizationWithTranscription(transcriptResult, diarizationResult)
-			}
+	if transcriptResult != nil && len(speech.Kept) > 0 {
+		speech.Remap(transcriptResult)
+		if transcriptResult.Metadata == nil {
+			transcriptResult.Metadata = map[string]string{}
 		}
+		transcriptResult.Metadata[interfaces.MetadataSkippedSeconds] = strconv.FormatFloat(speech.Skipped, 'f', 1, 64)
+	}
+	if transcriptResult != nil && trimmedStart > 0 {
+		shiftTranscript(transcriptResult, trimmedStart)
 	}
+	return transcriptResult, nil
+}
 
-	// Save results to database
-	if transcriptResult != nil {
-		if err := u.saveTranscriptionResults(job.ID, transcriptResult); err != nil {
-			return fmt.Errorf("failed to save transcription results: %w", err)
+// enhancement returns the pipeline enhancement for a job's audio preprocessing
+func (u *UnifiedTranscriptionService) enhancement(p models.AudioPreprocessing) pipeline.Enhancement {
+	e := pipeline.Enhancement{Normalize: p.Normalize, TrimSilence: p.TrimSilence, Resample: p.Resample}
+	if p.Denoise {
+		if u.denoiseModel == "" {
+			logger.Warn("Denoising requested but RNNOISE_MODEL_PATH is not set, skipping it")
 		}
+		e.DenoiseModel = u.denoiseModel
 	}
+	return e
+}
 
-	return nil
+// shiftTranscript moves a transcript's timestamps later by offset seconds
+func shiftTranscript(result *interfaces.TranscriptResult, offset float64) {
+	for i := range result.Segments {
+		result.Segments[i].Start += offset
+		result.Segments[i].End += offset
+	}
+	for i := range result.WordSegments {
+		result.WordSegments[i].Start += offset
+		result.WordSegments[i].End += offset
+	}
+}
+
+// identifySpeakers records the job's speaker embeddings and names the speakers
+// matching an enrolled voiceprint. Failures leave the generic speaker labels.
+func (u *UnifiedTranscriptionService) identifySpeakers(ctx context.Context, job *models.TranscriptionJob, result *interfaces.TranscriptResult) {
+	if u.voiceprints == nil {
+		return
+	}
+	if err := u.voiceprints.StoreSpeakerEmbeddings(ctx, job.ID, result.SpeakerEmbeddings); err != nil {
+		logger.Warn("Failed to store speaker embeddings", "job_id", job.ID, "error", err)
+		return
+	}
+	matches, err := u.voiceprints.IdentifySpeakers(ctx, job.ID, job.Workspace)
+	if err != nil {
+		logger.Warn("Failed to match voiceprints", "job_id", job.ID, "error", err)
+		return
+	}
+	for _, match := range matches {
+		logger.Info("Identified speaker by voiceprint", "job_id", job.ID, "speaker", match.Speaker,
+			"name", match.Voiceprint.Name, "similarity", match.Similarity)
+	}
 }
 
 // processMultiTrackJob handles multi-track audio processing
@@ -328,23 +734,27 @@ func (u *UnifiedTranscriptionService) IsMultiTrackJob(jobID string) bool {
 	return job.IsMultiTrack
 }
 
+// modelFamilies maps model families to their transcription adapters
+var modelFamilies = map[string]string{
+	"nvidia_parakeet":         "parakeet",
+	"nvidia_canary":           "canary",
+	"whisper":                 "whisperx",
+	"openai":                  "openai_whisper",
+	interfaces.ModalWhisperX:  interfaces.ModalWhisperX,
+	interfaces.RunPodWhisperX: interfaces.RunPodWhisperX,
+}
+
+// KnownModelFamily reports whether a model family has a transcription adapter
+func KnownModelFamily(family string) bool {
+	_, ok := modelFamilies[family]
+	return ok
+}
+
 // selectModels determines which models to use based on job parameters
 func (u *UnifiedTranscriptionService) selectModels(params models.WhisperXParams) (transcriptionModelID, diarizationModelID string, err error) {
 	// Determine transcription model
-	switch params.ModelFamily {
-	case "nvidia_parakeet":
-		transcriptionModelID = "parakeet"
-	case "nvidia_canary":
-		transcriptionModelID = "canary"
-	case "whisper":
-		transcriptionModelID = "whisperx"
-	case "openai":
-		transcriptionModelID = "openai_whisper"
-	case interfaces.ModalWhisperX:
-		transcriptionModelID = interfaces.ModalWhisperX
-	case interfaces.RunPodWhisperX:
-		transcriptionModelID = interfaces.RunPodWhisperX
-	default:
+	transcriptionModelID, ok := modelFamilies[params.ModelFamily]
+	if !ok {
 		transcriptionModelID = "whisperx" // Default fallback
 	}
 
@@ -553,13 +963,26 @@ func (u *UnifiedTranscriptionService) convertToOpenAIParams(params models.Whispe
 
 // convertToParakeetParams converts to Parakeet-specific parameters
 func (u *UnifiedTranscriptionService) convertToParakeetParams(params models.WhisperXParams) map[string]interface{} {
-	return map[string]interface{}{
+	paramMap := map[string]interface{}{
 		"timestamps":         true,
 		"context_left":       params.AttentionContextLeft,
 		"context_right":      params.AttentionContextRight,
 		"output_format":      "json",
 		"auto_convert_audio": true,
 	}
+	addNemoAdapterParams(paramMap, params)
+
+	return paramMap
+}
+
+// addNemoAdapterParams passes the profile's NeMo adapter weights, if any
+func addNemoAdapterParams(paramMap map[string]interface{}, params models.WhisperXParams) {
+	if params.NemoAdapterPath != nil {
+		paramMap["adapter_path"] = *params.NemoAdapterPath
+	}
+	if params.NemoAdapterName != nil {
+		paramMap["adapter_name"] = *params.NemoAdapterName
+	}
 }
 
 // convertToCanaryParams converts to Canary-specific parameters
@@ -582,6 +1005,7 @@ func (u *UnifiedTranscriptionService) convertToCanaryParams(params models.Whispe
 	if params.Task == "translate" {
 		paramMap["target_lang"] = "en"
 	}
+	addNemoAdapterParams(paramMap, params)
 
 	return paramMap
 }
@@ -599,11 +1023,13 @@ func (u *UnifiedTranscriptionService) convertToWhisperXParams(params models.Whis
 		"threads":      params.Threads,
 
 		// Task and language
-		"task": params.Task,
+		"task":                params.Task,
+		"segment_language_id": params.SegmentLanguageID,
 
 		// Diarization
-		"diarize":       params.Diarize,
-		"diarize_model": params.DiarizeModel,
+		"diarize":            params.Diarize,
+		"diarize_model":      params.DiarizeModel,
+		"speaker_embeddings": params.SpeakerEmbeddings,
 
 		// Quality settings
 		"temperature": params.Temperature,
@@ -634,6 +1060,9 @@ func (u *UnifiedTranscriptionService) convertToWhisperXParams(params models.Whis
 	if params.ModelDir != nil {
 		paramMap["model_dir"] = *params.ModelDir
 	}
+	if params.CustomModel != nil {
+		paramMap["custom_model"] = *params.CustomModel
+	}
 	if params.AlignModel != nil {
 		paramMap["align_model"] = *params.AlignModel
 	}
@@ -643,6 +1072,9 @@ func (u *UnifiedTranscriptionService) convertToWhisperXParams(params models.Whis
 	if params.InitialPrompt != nil {
 		paramMap["initial_prompt"] = *params.InitialPrompt
 	}
+	if params.Hotwords != nil {
+		paramMap["hotwords"] = *params.Hotwords
+	}
 
 	return paramMap
 }
@@ -653,6 +1085,7 @@ func (u *UnifiedTranscriptionService) convertToPyannoteParams(params models.Whis
 		"output_format":      "json",
 		"auto_convert_audio": true,
 		"device":             "auto",
+		"speaker_embeddings": params.SpeakerEmbeddings,
 	}
 
 	if params.MinSpeakers != nil {
@@ -688,11 +1121,13 @@ func (u *UnifiedTranscriptionService) parametersToMap(params models.WhisperXPara
 		"threads":      params.Threads,
 
 		// Language and task
-		"task": params.Task,
+		"task":                params.Task,
+		"segment_language_id": params.SegmentLanguageID,
 
 		// Diarization
-		"diarize":       params.Diarize,
-		"diarize_model": params.DiarizeModel,
+		"diarize":            params.Diarize,
+		"diarize_model":      params.DiarizeModel,
+		"speaker_embeddings": params.SpeakerEmbeddings,
 	}
 
 	// Handle pointer fields - only add if not nil
@@ -711,6 +1146,9 @@ func (u *UnifiedTranscriptionService) parametersToMap(params models.WhisperXPara
 	if params.ModelDir != nil {
 		paramMap["model_dir"] = *params.ModelDir
 	}
+	if params.CustomModel != nil {
+		paramMap["custom_model"] = *params.CustomModel
+	}
 	if params.AlignModel != nil {
 		paramMap["align_model"] = *params.AlignModel
 	}
@@ -720,6 +1158,9 @@ func (u *UnifiedTranscriptionService) parametersToMap(params models.WhisperXPara
 	if params.InitialPrompt != nil {
 		paramMap["initial_prompt"] = *params.InitialPrompt
 	}
+	if params.Hotwords != nil {
+		paramMap["hotwords"] = *params.Hotwords
+	}
 
 	// Add remaining non-pointer fields
 	paramMap["temperature"] = params.Temperature
@@ -773,6 +1214,10 @@ func (u *UnifiedTranscriptionService) mergeDiarizationWithTranscription(transcri
 		}
 	}
 
+	if len(diarization.SpeakerEmbeddings) > 0 {
+		mergedTranscript.SpeakerEmbeddings = diarization.SpeakerEmbeddings
+	}
+
 	// Also assign speakers to words if available
 	if len(transcript.WordSegments) > 0 {
 		mergedTranscript.WordSegments = make([]interfaces.TranscriptWord, len(transcript.WordSegments))
@@ -810,8 +1255,52 @@ func (u *UnifiedTranscriptionService) findBestSpeakerForSegment(start, end float
 	return bestSpeaker
 }
 
+// recordUsage records how much audio a completed execution transcribed, on the
+// execution and against the monthly quotas of its workspace and AWS tag route.
+// An execution that reused the transcript of identical audio sent none of it to
+// the adapters and costs nothing.
+func (u *UnifiedTranscriptionService) recordUsage(ctx context.Context, job *models.TranscriptionJob, execution *models.TranscriptionJobExecution, reused bool) {
+	stored, err := u.jobRepo.FindByID(ctx, job.ID)
+	if err != nil || stored.Transcript == nil {
+		return
+	}
+	var result interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(*stored.Transcript), &result); err != nil {
+		logger.Warn("Failed to measure transcribed audio", "job_id", job.ID, "error", err)
+		return
+	}
+	var seconds float64
+	for _, segment := range result.Segments {
+		seconds = max(seconds, segment.End)
+	}
+	execution.AudioSeconds = &seconds
+	if err := u.jobRepo.UpdateAudioSeconds(ctx, job.ID, seconds); err != nil {
+		logger.Warn("Failed to record audio length", "job_id", job.ID, "error", err)
+	}
+	if reused {
+		u.recordReuseCost(ctx, job, execution)
+	} else {
+		u.recordCost(ctx, job, execution)
+	}
+	month := models.QuotaMonth(time.Now())
+	if err := u.jobRepo.AddQuotaUsage(ctx, job.Workspace, month, seconds); err != nil {
+		logger.Warn("Failed to record quota usage", "job_id", job.ID, "error", err)
+	}
+	if stored.AWSTagRouteID != nil {
+		if err := u.jobRepo.AddQuotaUsage(ctx, models.AWSTagRouteQuotaKey(*stored.AWSTagRouteID), month, seconds); err != nil {
+			logger.Warn("Failed to record tag route usage", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
 // saveTranscriptionResults saves the transcription results to the database
-func (u *UnifiedTranscriptionService) saveTranscriptionResults(jobID string, result *interfaces.TranscriptResult) error {
+func (u *UnifiedTranscriptionService) saveTranscriptionResults(ctx context.Context, jobID string, result *interfaces.TranscriptResult) (err error) {
+	_, span := tracing.Start(ctx, "transcript.write", tracing.Int("transcript.segments", len(result.Segments)))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	// Convert result to JSON string for database storage
 	resultJSON, err := u.convertTranscriptResultToJSON(result)
 	if err != nil {
@@ -843,6 +1332,28 @@ func (u *UnifiedTranscriptionService) GetSupportedModels() map[string]interfaces
 	return u.registry.GetAllCapabilities()
 }
 
+// GetEnvironmentStatus returns the bootstrap progress of every model environment
+func (u *UnifiedTranscriptionService) GetEnvironmentStatus() []registry.EnvironmentStatus {
+	return u.registry.GetEnvironmentStatus()
+}
+
+// ErrAlignmentPrefetchUnsupported is returned when no local WhisperX adapter is
+// registered to download alignment models into
+var ErrAlignmentPrefetchUnsupported = errors.New("alignment models can only be prefetched for the local WhisperX adapter")
+
+// PrefetchAlignmentModels downloads the WhisperX alignment models for the given languages
+func (u *UnifiedTranscriptionService) PrefetchAlignmentModels(ctx context.Context, languages []string) error {
+	adapter, err := u.registry.GetTranscriptionAdapter("whisperx")
+	if err != nil {
+		return ErrAlignmentPrefetchUnsupported
+	}
+	whisperx, ok := adapter.(*adapters.WhisperXAdapter)
+	if !ok {
+		return ErrAlignmentPrefetchUnsupported
+	}
+	return whisperx.PrefetchAlignmentModels(ctx, languages)
+}
+
 // GetModelStatus returns the status of all models
 func (u *UnifiedTranscriptionService) GetModelStatus(ctx context.Context) map[string]bool {
 	return u.registry.GetModelStatus(ctx)
@@ -853,6 +1364,29 @@ func (u *UnifiedTranscriptionService) ValidateModelParameters(modelID string, pa
 	return u.registry.ValidateModelParameters(modelID, params)
 }
 
+// ValidateJobParameters checks job parameters against the parameter schema of
+// the adapters they select, as converted for each. Adapters not registered in
+// this process are not checked.
+func (u *UnifiedTranscriptionService) ValidateJobParameters(params models.WhisperXParams) error {
+	transcriptionModelID, diarizationModelID, err := u.selectModels(params)
+	if err != nil {
+		return err
+	}
+	modelIDs := []string{transcriptionModelID}
+	if diarizationModelID != "" && !u.transcriptionIncludesDiarization(transcriptionModelID, params) {
+		modelIDs = append(modelIDs, diarizationModelID)
+	}
+	for _, modelID := range modelIDs {
+		if _, err := u.registry.GetParameterSchema(modelID); err != nil {
+			continue
+		}
+		if err := u.registry.ValidateModelParameters(modelID, u.convertParametersForModel(params, modelID)); err != nil {
+			return fmt.Errorf("%s: %w", modelID, err)
+		}
+	}
+	return nil
+}
+
 // Helper functions
 func max(a, b float64) float64 {
 	if a > b {
@@ -0,0 +1,64 @@
+package transcription
+
+import (
+	"context"
+	"slices"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/pipeline"
+	"scriberr/pkg/logger"
+	"scriberr/pkg/tracing"
+)
+
+// speechDetectorID is the diarization adapter that detects speech with a VAD model
+const speechDetectorID = "pyannote"
+
+// cutsToSpeech reports whether a plan's audio is cut down to its speech: only
+// when asked for and only for remote adapters, which bill by the minute
+func cutsToSpeech(plan *singleTrackPlan) bool {
+	return plan.preprocessing != nil && plan.preprocessing.VAD && slices.Contains(remoteModels, plan.transcriptionModelID)
+}
+
+// cutToSpeech cuts the silence and music out of the audio with Silero VAD, or
+// just the long silences with ffmpeg when the VAD model is unavailable. It
+// returns the audio to transcribe and how to map its times back to the
+// input's; failures leave the audio whole.
+func (u *UnifiedTranscriptionService) cutToSpeech(ctx context.Context, input interfaces.AudioInput, procCtx interfaces.ProcessingContext) (interfaces.AudioInput, pipeline.SpeechMap) {
+	ctx, span := tracing.Start(ctx, "audio.vad")
+	defer span.End()
+
+	var regions []interfaces.SpeechRegion
+	var err error
+	adapter, lookupErr := u.registry.GetDiarizationAdapter(speechDetectorID)
+	if detector, ok := adapter.(interfaces.VoiceActivityDetector); lookupErr == nil && ok {
+		regions, err = detector.DetectSpeech(ctx, input, procCtx)
+		if err != nil {
+			logger.Warn("Voice activity detection failed, falling back to silence detection", "job_id", procCtx.JobID, "error", err)
+		}
+	}
+	if regions == nil {
+		regions, err = pipeline.DetectSpeech(ctx, input)
+		if err != nil {
+			span.RecordError(err)
+			logger.Warn("Speech detection failed, sending the whole audio", "job_id", procCtx.JobID, "error", err)
+			return input, pipeline.SpeechMap{}
+		}
+	}
+
+	tempDir := procCtx.TempDirectory
+	if tempDir == "" {
+		tempDir = u.tempDirectory
+	}
+	cut, speech, err := pipeline.CutToSpeech(ctx, input, regions, tempDir)
+	if err != nil {
+		span.RecordError(err)
+		logger.Warn("Cutting audio to speech failed, sending the whole audio", "job_id", procCtx.JobID, "error", err)
+		return input, pipeline.SpeechMap{}
+	}
+	span.SetAttributes(tracing.Float("audio.skipped_seconds", speech.Skipped))
+	if speech.Skipped > 0 {
+		logger.Info("Cut audio to speech", "job_id", procCtx.JobID, "skipped_seconds", speech.Skipped,
+			"remaining_seconds", cut.Duration.Seconds())
+	}
+	return cut, speech
+}
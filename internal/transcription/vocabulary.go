@@ -0,0 +1,257 @@
+package transcription
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// vocabularyMask replaces the words a mask filter matches
+const vocabularyMask = "***"
+
+// SetVocabularies enables the custom vocabularies and vocabulary filters jobs name
+func (u *UnifiedTranscriptionService) SetVocabularies(v repository.VocabularyRepository) {
+	u.vocabularies = v
+}
+
+// vocabularyContext scopes ctx to the organization owning a job, whose
+// vocabularies it may name
+func vocabularyContext(ctx context.Context, job *models.TranscriptionJob) context.Context {
+	return models.WithOrganization(ctx, models.WorkspaceOrganization(job.Workspace))
+}
+
+// applyVocabulary adds the phrases of the job's custom vocabulary to the
+// prompt and hotwords of params. A vocabulary deleted since the job was
+// submitted is skipped.
+func (u *UnifiedTranscriptionService) applyVocabulary(ctx context.Context, job *models.TranscriptionJob, params *models.WhisperXParams) {
+	if u.vocabularies == nil || params.VocabularyName == nil || *params.VocabularyName == "" {
+		return
+	}
+	vocabulary, err := u.vocabularies.FindVocabulary(vocabularyContext(ctx, job), *params.VocabularyName)
+	if err != nil {
+		logger.Warn("Custom vocabulary not found, transcribing without it", "job_id", job.ID, "vocabulary", *params.VocabularyName, "error", err)
+		return
+	}
+	phrases := strings.Join(vocabulary.PhraseList(), ", ")
+	if phrases == "" {
+		return
+	}
+	params.InitialPrompt = appendPhrases(params.InitialPrompt, phrases, " ")
+	params.Hotwords = appendPhrases(params.Hotwords, phrases, ", ")
+}
+
+// appendPhrases appends phrases to an optional value
+func appendPhrases(value *string, phrases, separator string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return &phrases
+	}
+	joined := strings.TrimSpace(*value) + separator + phrases
+	return &joined
+}
+
+// correctVocabulary replaces the misspellings the job's custom vocabulary
+// lists with their phrase
+func (u *UnifiedTranscriptionService) correctVocabulary(ctx context.Context, job *models.TranscriptionJob, result *interfaces.TranscriptResult) {
+	params := job.Parameters
+	if u.vocabularies == nil || params.VocabularyName == nil || *params.VocabularyName == "" {
+		return
+	}
+	vocabulary, err := u.vocabularies.FindVocabulary(vocabularyContext(ctx, job), *params.VocabularyName)
+	if err != nil {
+		return // reported when the job was planned
+	}
+	correctTranscript(result, vocabulary.CorrectionList())
+}
+
+// vocabularyReplacement finds a misspelling, as a whole phrase regardless of
+// case and spacing, and replaces it
+type vocabularyReplacement struct {
+	pattern *regexp.Regexp
+	// word is the lowercased misspelling when it is a single word, which word
+	// timings are corrected for
+	word   string
+	phrase string
+}
+
+// correctTranscript replaces misspellings in a transcript's text, segments
+// and word timings, longest first
+func correctTranscript(result *interfaces.TranscriptResult, corrections []models.VocabularyCorrection) {
+	var replacements []vocabularyReplacement
+	for _, correction := range corrections {
+		fields := strings.Fields(correction.Misspelling)
+		if len(fields) == 0 {
+			continue
+		}
+		for i, field := range fields {
+			fields[i] = regexp.QuoteMeta(field)
+		}
+		replacement := vocabularyReplacement{
+			pattern: regexp.MustCompile(`(?i)` + strings.Join(fields, `\s+`)),
+			phrase:  correction.Phrase,
+		}
+		if len(fields) == 1 {
+			replacement.word = filterKey(correction.Misspelling)
+		}
+		replacements = append(replacements, replacement)
+	}
+	if len(replacements) == 0 {
+		return
+	}
+	sort.SliceStable(replacements, func(i, j int) bool {
+		return len(replacements[i].pattern.String()) > len(replacements[j].pattern.String())
+	})
+
+	for _, replacement := range replacements {
+		result.Text = replacement.replace(result.Text)
+		for i := range result.Segments {
+			result.Segments[i].Text = replacement.replace(result.Segments[i].Text)
+		}
+		if replacement.word == "" {
+			continue
+		}
+		for i := range result.WordSegments {
+			word := result.WordSegments[i].Word
+			if filterKey(word) == replacement.word {
+				core := strings.TrimFunc(word, notWordRune)
+				start := strings.Index(word, core)
+				result.WordSegments[i].Word = word[:start] + replacement.phrase + word[start+len(core):]
+			}
+		}
+	}
+}
+
+// replace replaces the misspelling where it is not part of a longer word
+func (r vocabularyReplacement) replace(text string) string {
+	matches := r.pattern.FindAllStringIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		b.WriteString(text[last:match[0]])
+		b.WriteString(r.phrase)
+		last = match[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// applyVocabularyFilter removes, masks or tags the words of the job's
+// vocabulary filter in its transcript
+func (u *UnifiedTranscriptionService) applyVocabularyFilter(ctx context.Context, job *models.TranscriptionJob, result *interfaces.TranscriptResult) {
+	params := job.Parameters
+	if u.vocabularies == nil || params.VocabularyFilterName == nil || *params.VocabularyFilterName == "" {
+		return
+	}
+	filter, err := u.vocabularies.FindFilter(vocabularyContext(ctx, job), *params.VocabularyFilterName)
+	if err != nil {
+		logger.Warn("Vocabulary filter not found, leaving the transcript unfiltered", "job_id", job.ID, "filter", *params.VocabularyFilterName, "error", err)
+		return
+	}
+	method := models.VocabularyFilterRemove
+	if params.VocabularyFilterMethod != nil && *params.VocabularyFilterMethod != "" {
+		method = *params.VocabularyFilterMethod
+	}
+	filterTranscript(result, filter.WordList(), method)
+}
+
+// filterTranscript removes, masks or tags words in a transcript's text,
+// segments and word timings. Words match whole and regardless of case and
+// surrounding punctuation; tagging flags the word timings only.
+func filterTranscript(result *interfaces.TranscriptResult, words []string, method string) {
+	filtered := make(map[string]bool, len(words))
+	for _, word := range words {
+		if key := filterKey(word); key != "" {
+			filtered[key] = true
+		}
+	}
+	if len(filtered) == 0 {
+		return
+	}
+
+	if method != models.VocabularyFilterTag {
+		result.Text = filterText(result.Text, filtered, method)
+		for i := range result.Segments {
+			result.Segments[i].Text = filterText(result.Segments[i].Text, filtered, method)
+		}
+	}
+
+	kept := result.WordSegments[:0]
+	for _, word := range result.WordSegments {
+		if filtered[filterKey(word.Word)] {
+			switch method {
+			case models.VocabularyFilterRemove:
+				continue
+			case models.VocabularyFilterMask:
+				word.Word = maskWord(word.Word)
+			case models.VocabularyFilterTag:
+				word.VocabularyFilterMatch = true
+			}
+		}
+		kept = append(kept, word)
+	}
+	result.WordSegments = kept
+}
+
+// filterText removes or masks the filtered words of text, keeping its leading
+// space, which segments of some adapters start with
+func filterText(text string, filtered map[string]bool, method string) string {
+	tokens := strings.Fields(text)
+	changed := false
+	kept := tokens[:0]
+	for _, token := range tokens {
+		if filtered[filterKey(token)] {
+			changed = true
+			if method == models.VocabularyFilterRemove {
+				continue
+			}
+			token = maskWord(token)
+		}
+		kept = append(kept, token)
+	}
+	if !changed {
+		return text
+	}
+	joined := strings.Join(kept, " ")
+	if strings.HasPrefix(text, " ") {
+		joined = " " + joined
+	}
+	return joined
+}
+
+// filterKey is how a word is compared with a filter's words
+func filterKey(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, notWordRune))
+}
+
+// maskWord masks a word, keeping the punctuation around it
+func maskWord(word string) string {
+	core := strings.TrimFunc(word, notWordRune)
+	if core == "" {
+		return word
+	}
+	start := strings.Index(word, core)
+	return word[:start] + vocabularyMask + word[start+len(core):]
+}
+
+func notWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+}
@@ -53,6 +53,7 @@ func TestWebhookIntegration_Failure(t *testing.T) {
 	mockRepo.On("FindWithAssociations", mock.Anything, jobID).Return(job, nil)
 	mockRepo.On("CreateExecution", mock.Anything, mock.Anything).Return(nil)
 	mockRepo.On("UpdateExecution", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpdateProgress", mock.Anything, jobID, mock.Anything).Return(nil)
 
 	// Execute
 	// We expect an error because the file doesn't exist
@@ -44,22 +44,30 @@ func (s *Service) SendWebhook(ctx context.Context, url string, payload WebhookPa
 		return nil
 	}
 
-	logger.Info("Sending webhook", "job_id", payload.JobID, "url", url, "status", payload.Status)
-
 	// Marshal payload
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal webhook payload: %w", err)
 	}
+	return s.send(ctx, url, payload.JobID, payload.Status, jsonData, "application/json")
+}
+
+// SendTemplatedWebhook sends the admin's webhook template rendered with data in
+// place of the built-in payload
+func (s *Service) SendTemplatedWebhook(ctx context.Context, url string, tmpl *models.NotificationTemplate, data TemplateData) error {
+	if url == "" {
+		return nil
+	}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	body, err := Render(tmpl, data)
 	if err != nil {
-		return fmt.Errorf("failed to create webhook request: %w", err)
+		return fmt.Errorf("failed to render webhook template: %w", err)
 	}
+	return s.send(ctx, url, data.JobID, models.JobStatus(data.Status), body, tmpl.ContentType)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "Scriberr-Webhook/1.0")
+func (s *Service) send(ctx context.Context, url, jobID string, status models.JobStatus, body []byte, contentType string) error {
+	logger.Info("Sending webhook", "job_id", jobID, "url", url, "status", status)
 
 	// Send request with retry logic
 	maxRetries := 3
@@ -68,19 +76,28 @@ func (s *Service) SendWebhook(ctx context.Context, url string, payload WebhookPa
 	for i := 0; i < maxRetries; i++ {
 		if i > 0 {
 			time.Sleep(time.Duration(i) * time.Second) // Simple backoff
-			logger.Info("Retrying webhook", "job_id", payload.JobID, "attempt", i+1)
+			logger.Info("Retrying webhook", "job_id", jobID, "attempt", i+1)
 		}
 
+		// A request per attempt, as sending consumes the body
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create webhook request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("User-Agent", "Scriberr-Webhook/1.0")
+
 		resp, err := s.client.Do(req)
 		if err != nil {
 			lastErr = err
 			logger.Warn("Webhook request failed", "error", err, "attempt", i+1)
 			continue
 		}
-		defer resp.Body.Close()
+		resp.Body.Close()
 
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			logger.Info("Webhook sent successfully", "job_id", payload.JobID, "status_code", resp.StatusCode)
+			logger.Info("Webhook sent successfully", "job_id", jobID, "status_code", resp.StatusCode)
 			return nil
 		}
 
@@ -3,6 +3,7 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -100,3 +101,66 @@ func TestSendWebhook(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestSendTemplatedWebhook(t *testing.T) {
+	service := NewService()
+	ctx := context.Background()
+
+	title := `Sales "Q3" call`
+	transcript := `{"text":"Hello there","segments":[]}`
+	job := &models.TranscriptionJob{ID: "job-456", Title: &title, Status: models.StatusCompleted, Transcript: &transcript}
+	data := NewTemplateData(job, "")
+
+	t.Run("RendersTemplate", func(t *testing.T) {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			// The body is sent again on retries
+			if len(bodies) < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		tmpl := &models.NotificationTemplate{
+			Channel:     models.NotificationChannelWebhook,
+			Body:        `{"id":{{json .JobID}},"name":{{json .Title}},"text":{{json (upper .Text)}},"state":{{json .Event}}}`,
+			ContentType: "application/json",
+		}
+		err := service.SendTemplatedWebhook(ctx, server.URL, tmpl, data)
+		assert.NoError(t, err)
+		expected := `{"id":"job-456","name":"Sales \"Q3\" call","text":"HELLO THERE","state":"completed"}`
+		assert.Equal(t, []string{expected, expected}, bodies)
+	})
+
+	t.Run("PlainText", func(t *testing.T) {
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+		}))
+		defer server.Close()
+
+		tmpl := &models.NotificationTemplate{Channel: models.NotificationChannelWebhook, Body: `{{.Title}} is {{.Status}}: {{truncate 5 .Text}}`, ContentType: "text/plain"}
+		assert.NoError(t, service.SendTemplatedWebhook(ctx, server.URL, tmpl, data))
+		assert.Equal(t, `Sales "Q3" call is completed: Hello`, body)
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		cases := map[string]*models.NotificationTemplate{
+			"unquoted string in JSON": {Channel: models.NotificationChannelWebhook, Body: `{"name":{{.Title}}}`, ContentType: "application/json"},
+			"EventBridge is JSON":     {Channel: models.NotificationChannelEventBridge, Body: `{{.Title}}`},
+			"unknown field":           {Channel: models.NotificationChannelWebhook, Body: `{{.Missing}}`, ContentType: "text/plain"},
+			"syntax error":            {Channel: models.NotificationChannelWebhook, Body: `{{.Title`, ContentType: "text/plain"},
+		}
+		for name, tmpl := range cases {
+			_, err := Render(tmpl, data)
+			assert.Error(t, err, name)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"text/template"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+// maxTemplateOutput bounds a rendered message; EventBridge rejects entries over 256 KB
+const maxTemplateOutput = 256 * 1024
+
+// TemplateData is what notification templates are rendered with
+type TemplateData struct {
+	JobID  string
+	Title  string
+	Status string
+	// Event is the EventBridge job state (COMPLETED or FAILED), or the status
+	Event     string
+	AudioPath string
+	AudioURI  string
+	// Transcript is the transcript as stored, Text its plain text
+	Transcript   string
+	Text         string
+	Summary      string
+	ErrorMessage string
+	// Tags are the job's tags, as key or key=value
+	Tags      []string
+	Metadata  map[string]interface{}
+	CreatedAt time.Time
+	// CompletedAt is when the notification's run finished
+	CompletedAt time.Time
+	// Link opens the job in the web interface; set in emails when
+	// PUBLIC_BASE_URL is configured
+	Link string
+	Job  *models.TranscriptionJob
+}
+
+// NewTemplateData returns the fields of job for a notification about event
+func NewTemplateData(job *models.TranscriptionJob, event string) TemplateData {
+	data := TemplateData{
+		JobID:     job.ID,
+		Status:    string(job.Status),
+		Event:     event,
+		AudioPath: job.AudioPath,
+		CreatedAt: job.CreatedAt,
+		// Callers with the run's own finish time replace it
+		CompletedAt: time.Now(),
+		Tags:        job.TagNames(),
+		Metadata:    map[string]interface{}{},
+		Job:         job,
+	}
+	if event == "" {
+		data.Event = data.Status
+	}
+	if job.Title != nil {
+		data.Title = *job.Title
+	}
+	if job.AudioUri != nil {
+		data.AudioURI = *job.AudioUri
+	}
+	if job.Transcript != nil {
+		data.Transcript = *job.Transcript
+		var result struct {
+			Text string `json:"text"`
+		}
+		if json.Unmarshal([]byte(data.Transcript), &result) == nil {
+			data.Text = result.Text
+		}
+	}
+	if job.Summary != nil {
+		data.Summary = *job.Summary
+	}
+	if job.ErrorMessage != nil {
+		data.ErrorMessage = *job.ErrorMessage
+	}
+	return data
+}
+
+var templateFuncs = template.FuncMap{
+	// json encodes a value, so strings are quoted and escaped inside JSON bodies
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"default": func(fallback, v interface{}) interface{} {
+		if v == nil {
+			return fallback
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return fallback
+		}
+		return v
+	},
+	"truncate": func(n int, s string) string {
+		if r := []rune(s); len(r) > n {
+			return string(r[:n])
+		}
+		return s
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"rfc3339": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}
+
+// ParseTemplate parses a notification template
+func ParseTemplate(body string) (*template.Template, error) {
+	return template.New("notification").Funcs(templateFuncs).Option("missingkey=error").Parse(body)
+}
+
+// Render renders a channel's template with data. JSON output is checked to be
+// well formed, as the channel's consumers would reject it otherwise.
+func Render(tmpl *models.NotificationTemplate, data TemplateData) ([]byte, error) {
+	parsed, err := ParseTemplate(tmpl.Body)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	if out.Len() > maxTemplateOutput {
+		return nil, fmt.Errorf("rendered message is %d bytes, over the %d byte limit", out.Len(), maxTemplateOutput)
+	}
+	if IsJSON(tmpl) && !json.Valid(out.Bytes()) {
+		return nil, fmt.Errorf("rendered message is not valid JSON")
+	}
+	return out.Bytes(), nil
+}
+
+// IsJSON reports whether a template must render JSON
+func IsJSON(tmpl *models.NotificationTemplate) bool {
+	if tmpl.Channel == models.NotificationChannelEventBridge {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(tmpl.ContentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
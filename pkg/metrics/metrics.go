@@ -0,0 +1,147 @@
+// Package metrics keeps the server's Prometheus metrics and writes them in the
+// Prometheus text exposition format. Only histograms, which is all the server
+// records, are implemented.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Histogram counts observations into buckets, by the values of its labels
+type Histogram struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is a histogram's observations for one set of label values
+type series struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*Histogram
+)
+
+// NewHistogram creates and registers a histogram with the upper bounds of its
+// buckets, in increasing order, and the names of its labels
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: slices.Clone(buckets),
+		series:  make(map[string]*series),
+	}
+	registryMu.Lock()
+	registry = append(registry, h)
+	registryMu.Unlock()
+	return h
+}
+
+// Observe records a value with the values of the histogram's labels, in order
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labels) {
+		panic(fmt.Sprintf("metrics: %s observed with %d label values, want %d", h.name, len(labelValues), len(h.labels)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &series{labelValues: slices.Clone(labelValues), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i, _ := slices.BinarySearch(h.buckets, value); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += value
+}
+
+// write writes the histogram in the text format
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.labelValues, formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.labelValues, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(s.labelValues, ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(s.labelValues, ""), s.count)
+	}
+}
+
+// labelPairs formats the labels of a series, with the le label of a bucket
+// when le is set
+func (h *Histogram) labelPairs(values []string, le string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, name := range h.labels {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Write writes every registered metric in the text format
+func Write(w io.Writer) {
+	registryMu.Lock()
+	histograms := slices.Clone(registry)
+	registryMu.Unlock()
+	for _, h := range histograms {
+		h.write(w)
+	}
+}
+
+// Handler serves the registered metrics to Prometheus
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w)
+	})
+}
+
+// JobStageDuration is how long jobs spend in each stage of the pipeline
+var JobStageDuration = NewHistogram("scriberr_job_stage_duration_seconds",
+	"Time transcription jobs spent in each processing stage.",
+	[]float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200, 14400},
+	"stage", "model_family")
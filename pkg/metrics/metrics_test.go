@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_duration_seconds", "How long tests take.", []float64{1, 10}, "stage", "family")
+	h.Observe(0.5, "transcribing", "whisper")
+	h.Observe(10, "transcribing", "whisper")
+	h.Observe(42, "transcribing", "whisper")
+	h.Observe(3, "diarizing", `say "hi"`)
+
+	var out strings.Builder
+	h.write(&out)
+	assert.Equal(t, `# HELP test_duration_seconds How long tests take.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{stage="diarizing",family="say \"hi\"",le="1"} 0
+test_duration_seconds_bucket{stage="diarizing",family="say \"hi\"",le="10"} 1
+test_duration_seconds_bucket{stage="diarizing",family="say \"hi\"",le="+Inf"} 1
+test_duration_seconds_sum{stage="diarizing",family="say \"hi\""} 3
+test_duration_seconds_count{stage="diarizing",family="say \"hi\""} 1
+test_duration_seconds_bucket{stage="transcribing",family="whisper",le="1"} 1
+test_duration_seconds_bucket{stage="transcribing",family="whisper",le="10"} 2
+test_duration_seconds_bucket{stage="transcribing",family="whisper",le="+Inf"} 3
+test_duration_seconds_sum{stage="transcribing",family="whisper"} 52.5
+test_duration_seconds_count{stage="transcribing",family="whisper"} 3
+`, out.String())
+
+	assert.Panics(t, func() { h.Observe(1, "transcribing") })
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, rec.Header().Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, rec.Body.String(), `test_duration_seconds_count{stage="transcribing",family="whisper"} 3`)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,10 +19,10 @@ func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 		// Check for API key first
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey != "" {
-			if validateAPIKey(apiKey) {
-				c.Set("auth_type", "api_key")
-				c.Set("api_key", apiKey)
-				c.Next()
+			if key, ok := validateAPIKey(apiKey); ok {
+				if authenticateAPIKey(c, key) {
+					c.Next()
+				}
 				return
 			}
 		}
@@ -50,29 +51,171 @@ func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		role, ok := userRole(claims.UserID)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User no longer exists"})
+			c.Abort()
+			return
+		}
+
+		organizationID, ok := userOrganization(claims.UserID, role, c.GetHeader(OrganizationHeader))
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of this organization"})
+			c.Abort()
+			return
+		}
+
 		c.Set("auth_type", "jwt")
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("role", role)
+		c.Request = c.Request.WithContext(models.WithUser(c.Request.Context(), claims.UserID))
+		setOrganization(c, organizationID)
+		scopeWorkspace(c, organizationID, role, models.UserWorkspace(claims.UserID))
 		c.Next()
 	}
 }
 
-// validateAPIKey validates an API key against the database and updates last used timestamp
-func validateAPIKey(key string) bool {
+// validateAPIKey looks up an active API key
+func validateAPIKey(key string) (*models.APIKey, bool) {
 	var apiKey models.APIKey
 	result := database.DB.Where("key = ? AND is_active = ?", key, true).First(&apiKey)
 	if result.Error != nil {
-		return false
+		return nil, false
 	}
+	return &apiKey, true
+}
+
+// apiKeyLimiter enforces the per-key rate limits
+var apiKeyLimiter = NewRateLimiter()
+
+// SubmitScopeRoutes are the requests a key with only the submit scope may make:
+// submitting jobs and following their progress
+var SubmitScopeRoutes = map[string]bool{
+	"POST /api/v1/transcription/upload":            true,
+	"POST /api/v1/transcription/upload-video":      true,
+	"POST /api/v1/transcription/upload-multitrack": true,
+	"POST /api/v1/transcription/multitrack":        true,
+	"POST /api/v1/transcription/youtube":           true,
+	"POST /api/v1/transcription/url":               true,
+	"POST /api/v1/transcription/submit":            true,
+	"POST /api/v1/transcription/quick":             true,
+	"POST /api/v1/transcribe/quick":                true,
+	"POST /api/v1/transcription/aws-transcribe":    true,
+	"POST /api/v1/transcription/:id/start":         true,
+	"GET /api/v1/transcription/:id/status":         true,
+	"GET /api/v1/transcription/quick/:id":          true,
+	"GET /api/v1/transcription/models":             true,
+	"GET /api/v1/quota":                            true,
+}
 
-	// Update last used timestamp
+// apiKeyScopeAllows reports whether the key's scopes cover a request. Only the
+// admin scope changes or deletes data.
+func apiKeyScopeAllows(key *models.APIKey, method, route string) bool {
+	if key.HasScope(models.ScopeAdmin) {
+		return true
+	}
+	if key.HasScope(models.ScopeSubmit) && SubmitScopeRoutes[method+" "+route] {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return key.HasScope(models.ScopeRead)
+	}
+	return false
+}
+
+// authenticateAPIKey enforces a valid key's expiry, scopes and rate limit, then
+// records its use and authenticates the request as the key. It writes the error
+// response and returns false if the request may not proceed.
+func authenticateAPIKey(c *gin.Context, key *models.APIKey) bool {
 	now := time.Now()
-	apiKey.LastUsed = &now
-	database.DB.Save(&apiKey)
+	if key.Expired(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key has expired"})
+		c.Abort()
+		return false
+	}
+	if !apiKeyScopeAllows(key, c.Request.Method, RouteKey(c)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key scope does not allow this request"})
+		c.Abort()
+		return false
+	}
+	limit := key.RateLimit
+	if limit == 0 {
+		limit = defaultAPIKeyRateLimit
+	}
+	if ok, wait := apiKeyLimiter.Allow(strconv.FormatUint(uint64(key.ID), 10), limit); !ok {
+		tooManyRequests(c, wait, "API key rate limit exceeded")
+		return false
+	}
 
+	database.DB.Model(key).UpdateColumns(map[string]interface{}{"last_used": now, "last_used_ip": c.ClientIP()})
+
+	c.Set("auth_type", "api_key")
+	c.Set("api_key", key.Key)
+	c.Set("role", key.Role)
+	c.Request = c.Request.WithContext(models.WithAPIKey(c.Request.Context(), key.ID))
+	setOrganization(c, key.OrganizationID)
+	scopeWorkspace(c, key.OrganizationID, key.Role, models.APIKeyWorkspace(key.ID))
 	return true
 }
 
+// userRole looks up the current role of a token's user, so role changes and
+// deleted accounts take effect without waiting for tokens to expire
+func userRole(userID uint) (string, bool) {
+	var user models.User
+	if err := database.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		return "", false
+	}
+	return user.Role, true
+}
+
+// OrganizationHeader selects which of a user's organizations a request acts in
+const OrganizationHeader = "X-Organization-ID"
+
+// userOrganization returns the organization a user's request acts in: the one
+// requested, which must be one of the user's unless they are an admin, or else
+// the user's first organization. Users of no organization act outside all of them.
+func userOrganization(userID uint, role, requested string) (string, bool) {
+	if requested != "" {
+		var count int64
+		query := database.DB.Model(&models.Organization{}).Where("organizations.id = ?", requested)
+		if role != models.RoleAdmin {
+			query = query.Joins("JOIN organization_members m ON m.organization_id = organizations.id").
+				Where("m.user_id = ?", userID)
+		}
+		if err := query.Count(&count).Error; err != nil || count == 0 {
+			return "", false
+		}
+		return requested, true
+	}
+
+	var member models.OrganizationMember
+	err := database.DB.Where("user_id = ?", userID).Order("created_at ASC").Limit(1).Find(&member).Error
+	if err != nil {
+		return "", false
+	}
+	return member.OrganizationID, true
+}
+
+// setOrganization scopes the request, and the data it reaches through its
+// context, to an organization
+func setOrganization(c *gin.Context, organizationID string) {
+	c.Set("organization_id", organizationID)
+	c.Request = c.Request.WithContext(models.WithOrganization(c.Request.Context(), organizationID))
+}
+
+// scopeWorkspace confines a request outside every organization to its own
+// workspace, so users and keys do not see each other's jobs. Admins keep access
+// to every workspace outside the organizations; members of an organization
+// share its workspace.
+func scopeWorkspace(c *gin.Context, organizationID, role, workspace string) {
+	if organizationID != "" || role == models.RoleAdmin {
+		return
+	}
+	c.Request = c.Request.WithContext(models.WithWorkspace(c.Request.Context(), workspace))
+}
+
 // APIKeyOnlyMiddleware only allows API key authentication
 func APIKeyOnlyMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -83,15 +226,16 @@ func APIKeyOnlyMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if !validateAPIKey(apiKey) {
+		key, ok := validateAPIKey(apiKey)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
 			c.Abort()
 			return
 		}
 
-		c.Set("auth_type", "api_key")
-		c.Set("api_key", apiKey)
-		c.Next()
+		if authenticateAPIKey(c, key) {
+			c.Next()
+		}
 	}
 }
 
@@ -120,9 +264,54 @@ func JWTOnlyMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		role, ok := userRole(claims.UserID)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User no longer exists"})
+			c.Abort()
+			return
+		}
+
+		organizationID, ok := userOrganization(claims.UserID, role, c.GetHeader(OrganizationHeader))
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of this organization"})
+			c.Abort()
+			return
+		}
+
 		c.Set("auth_type", "jwt")
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("role", role)
+		c.Request = c.Request.WithContext(models.WithUser(c.Request.Context(), claims.UserID))
+		setOrganization(c, organizationID)
+		scopeWorkspace(c, organizationID, role, models.UserWorkspace(claims.UserID))
+		c.Next()
+	}
+}
+
+// RequireRole only allows requests whose user or API key has at least the given
+// role. It must follow one of the authentication middlewares.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !models.RoleAllows(c.GetString("role"), role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+// RequireRoleForWrites lets every authenticated request read, and only allows
+// requests with at least the given role to create, change or delete
+func RequireRoleForWrites(role string) gin.HandlerFunc {
+	requireRole := RequireRole(role)
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+		default:
+			requireRole(c)
+		}
+	}
+}
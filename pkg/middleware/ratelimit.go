@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"scriberr/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenBucket holds the tokens left to a key and when they were last counted
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter keeps a token bucket per key. Each bucket holds up to a minute's
+// worth of requests and refills continuously.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+// NewRateLimiter creates an empty rate limiter
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*tokenBucket), swept: time.Now(), now: time.Now}
+}
+
+// Allow takes a token from key's bucket, which allows perMinute requests a
+// minute. When the bucket is empty it returns how long until the next token.
+func (l *RateLimiter) Allow(key string, perMinute int) (bool, time.Duration) {
+	if perMinute <= 0 {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// Buckets idle for a minute are full again, the same as no bucket at all
+	if now.Sub(l.swept) >= time.Minute {
+		for k, bucket := range l.buckets {
+			if now.Sub(bucket.last) >= time.Minute {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+
+	capacity := float64(perMinute)
+	perSecond := capacity / 60
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*perSecond)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// ipLimiter enforces the per-IP rate limit
+var ipLimiter = NewRateLimiter()
+
+// RateLimitMiddleware limits the requests per minute of each client IP to the
+// configured rate, answering 429 with Retry-After beyond it
+func RateLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, wait := ipLimiter.Allow(c.ClientIP(), cfg.RateLimitPerIP); !ok {
+			tooManyRequests(c, wait, "Rate limit exceeded")
+			return
+		}
+		c.Next()
+	}
+}
+
+// defaultAPIKeyRateLimit applies to API keys without a rate limit of their own
+var defaultAPIKeyRateLimit int
+
+// SetDefaultAPIKeyRateLimit sets the requests per minute allowed to API keys
+// without a rate limit of their own (0: unlimited)
+func SetDefaultAPIKeyRateLimit(perMinute int) {
+	defaultAPIKeyRateLimit = perMinute
+}
+
+// tooManyRequests aborts with 429, telling the client when to retry
+func tooManyRequests(c *gin.Context, wait time.Duration, message string) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": message})
+	c.Abort()
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"scriberr/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TracingMiddleware starts a server span per request, continuing the caller's
+// trace when it sends a traceparent header. Requests on a job's routes carry
+// the job ID, so their spans and the spans they start join the job's trace.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+		if id := c.Param("id"); id != "" && strings.HasPrefix(route, "/api/v1/transcription/") {
+			ctx = tracing.WithJobID(ctx, id)
+		}
+		ctx, span := tracing.StartKind(ctx, c.Request.Method+" "+route, tracing.KindServer,
+			tracing.String("http.request.method", c.Request.Method),
+			tracing.String("http.route", route),
+		)
+		if span == nil {
+			c.Next()
+			return
+		}
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		tracing.Inject(ctx, c.Writer.Header())
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.RecordError(fmt.Errorf("HTTP %d", status))
+		}
+	}
+}
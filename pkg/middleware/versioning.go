@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader names the API version that served a response
+const APIVersionHeader = "API-Version"
+
+// routePrefixes are the API version prefixes of routes. Every version serves
+// the routes of v1 it has not changed, so per-route settings are keyed by the
+// v1 route.
+var routePrefixes = []string{"/api/v2/"}
+
+// RouteKey returns the route that matched a request as its v1 route, e.g.
+// "/api/v1/transcription/:id" for "/api/v2/transcription/:id"
+func RouteKey(c *gin.Context) string {
+	route := c.FullPath()
+	for _, prefix := range routePrefixes {
+		if strings.HasPrefix(route, prefix) {
+			return "/api/v1/" + strings.TrimPrefix(route, prefix)
+		}
+	}
+	return route
+}
+
+// APIVersion stamps responses with the API version that served them
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// Deprecation announces that an API is deprecated and when it goes away
+type Deprecation struct {
+	// Since is when the API is or was deprecated
+	Since time.Time
+	// Sunset is when the API stops being served; zero if not decided
+	Sunset time.Time
+	// Successor returns the URL of the API replacing a request's, if any
+	Successor func(r *http.Request) string
+}
+
+// Deprecate sends the Deprecation (RFC 9745), Sunset (RFC 8594) and
+// successor-version Link headers of d on responses. Once the sunset has
+// passed, requests are answered with 410 Gone.
+func Deprecate(d Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+		if !d.Sunset.IsZero() {
+			c.Header("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		successor := ""
+		if d.Successor != nil {
+			successor = d.Successor(c.Request)
+		}
+		if successor != "" {
+			c.Writer.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+		if !d.Sunset.IsZero() && !time.Now().Before(d.Sunset) {
+			body := gin.H{"error": "This API version is no longer available"}
+			if successor != "" {
+				body["successor"] = successor
+			}
+			c.AbortWithStatusJSON(http.StatusGone, body)
+			return
+		}
+		c.Next()
+	}
+}
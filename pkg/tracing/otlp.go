@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Config selects where spans are exported
+type Config struct {
+	// Endpoint is the collector's base URL (spans go to <Endpoint>/v1/traces),
+	// or TracesEndpoint the full URL. Tracing is off when both are empty.
+	Endpoint       string
+	TracesEndpoint string
+	// Headers are sent with every export, e.g. for collector authentication
+	Headers     map[string]string
+	ServiceName string
+	Version     string
+}
+
+// exportTimeout bounds each export to the collector
+const exportTimeout = 10 * time.Second
+
+// active is the provider spans are recorded with, or nil while tracing is off
+var active atomic.Pointer[sdktrace.TracerProvider]
+
+// propagator reads and writes W3C traceparent and tracestate headers
+var propagator = propagation.TraceContext{}
+
+// Init starts exporting spans as configured and returns a function flushing
+// the remaining spans on shutdown. With no endpoint tracing stays off.
+func Init(cfg Config) (shutdown func(context.Context) error, err error) {
+	url := cfg.TracesEndpoint
+	if url == "" && cfg.Endpoint != "" {
+		url = strings.TrimRight(cfg.Endpoint, "/") + "/v1/traces"
+	}
+	if url == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: must be an http(s) URL", url)
+	}
+	exp, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(url),
+		otlptracehttp.WithHeaders(cfg.Headers),
+		otlptracehttp.WithTimeout(exportTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "scriberr"
+	}
+	attrs := []Attr{semconv.ServiceName(serviceName)}
+	if cfg.Version != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.Version))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the service: %w", err)
+	}
+
+	return install(sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)), nil
+}
+
+// install records spans with provider until the returned shutdown is called
+func install(provider *sdktrace.TracerProvider) func(context.Context) error {
+	active.Store(provider)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return func(ctx context.Context) error {
+		active.CompareAndSwap(provider, nil)
+		return provider.Shutdown(ctx)
+	}
+}
+
+// Flush exports the spans ended so far
+func Flush(ctx context.Context) error {
+	provider := active.Load()
+	if provider == nil {
+		return nil
+	}
+	return provider.ForceFlush(ctx)
+}
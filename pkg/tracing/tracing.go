@@ -0,0 +1,190 @@
+// Package tracing records spans across the pipeline, from upload through the
+// queue, adapters, S3 and LLM calls to the transcript write, and exports them
+// to an OpenTelemetry collector over OTLP/HTTP with the OpenTelemetry SDK.
+// Tracing is off until Init is given an endpoint; until then spans are nil and
+// cost nothing.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AttrJobID is the attribute carrying the transcription job ID
+const AttrJobID = "scriberr.job_id"
+
+// tracerName is the instrumentation scope of Scriberr's spans
+const tracerName = "scriberr"
+
+// SpanKind is the OpenTelemetry span kind
+type SpanKind = trace.SpanKind
+
+const (
+	KindInternal = trace.SpanKindInternal
+	KindServer   = trace.SpanKindServer
+	KindClient   = trace.SpanKindClient
+)
+
+// Attr is a span attribute
+type Attr = attribute.KeyValue
+
+// String, Int, Float and Bool build attributes
+func String(key, value string) Attr        { return attribute.String(key, value) }
+func Int(key string, value int) Attr       { return attribute.Int(key, value) }
+func Float(key string, value float64) Attr { return attribute.Float64(key, value) }
+func Bool(key string, value bool) Attr     { return attribute.Bool(key, value) }
+
+// Span is a timed operation. A nil span is valid and records nothing.
+type Span struct {
+	span trace.Span
+}
+
+type jobKey struct{}
+
+// Start begins a span named name, as a child of the span in ctx or of a remote
+// parent extracted from a request. The job ID in ctx, if any, is attached.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal, attrs...)
+}
+
+// StartKind is Start with a span kind
+func StartKind(ctx context.Context, name string, kind SpanKind, attrs ...Attr) (context.Context, *Span) {
+	provider := active.Load()
+	if provider == nil {
+		return ctx, nil
+	}
+	if jobID := JobID(ctx); jobID != "" {
+		attrs = append([]Attr{String(AttrJobID, jobID)}, attrs...)
+	}
+	ctx, span := provider.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, &Span{span: span}
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// RecordError marks the span failed when err is not nil
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End finishes the span and queues it for export. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// Context returns the span's identity, for propagation
+func (s *Span) Context() trace.SpanContext {
+	if s == nil {
+		return trace.SpanContext{}
+	}
+	return s.span.SpanContext()
+}
+
+// SpanFromContext returns the span recording in ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return nil
+	}
+	return &Span{span: span}
+}
+
+// WithJobID attaches a job ID to ctx; spans started from it carry the ID as
+// the scriberr.job_id attribute
+func WithJobID(ctx context.Context, jobID string) context.Context {
+	if jobID == "" {
+		return ctx
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		span.SetAttributes(String(AttrJobID, jobID))
+	}
+	return context.WithValue(ctx, jobKey{}, jobID)
+}
+
+// JobID returns the job ID attached to ctx
+func JobID(ctx context.Context) string {
+	jobID, _ := ctx.Value(jobKey{}).(string)
+	return jobID
+}
+
+// jobTraces remembers the trace each job was enqueued from, so the worker that
+// later picks the job up continues the upload's trace
+var jobTraces = struct {
+	sync.Mutex
+	m map[string]trace.SpanContext
+}{m: map[string]trace.SpanContext{}}
+
+// maxJobTraces bounds the remembered traces of jobs not yet picked up
+const maxJobTraces = 10000
+
+// LinkJob records the span in ctx as the origin of jobID's processing
+func LinkJob(ctx context.Context, jobID string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || jobID == "" {
+		return
+	}
+	jobTraces.Lock()
+	defer jobTraces.Unlock()
+	if len(jobTraces.m) >= maxJobTraces {
+		for id := range jobTraces.m {
+			delete(jobTraces.m, id)
+			break
+		}
+	}
+	jobTraces.m[jobID] = sc
+}
+
+// StartJob begins the span processing jobID, continuing the trace the job was
+// linked to with LinkJob, if any
+func StartJob(ctx context.Context, jobID, name string, attrs ...Attr) (context.Context, *Span) {
+	jobTraces.Lock()
+	sc, ok := jobTraces.m[jobID]
+	delete(jobTraces.m, jobID)
+	jobTraces.Unlock()
+	if ok {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+	}
+	return Start(WithJobID(ctx, jobID), name, attrs...)
+}
+
+// Extract returns ctx with the remote parent named by the W3C traceparent and
+// tracestate headers of a request
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject writes the span in ctx to header as W3C traceparent and tracestate
+// headers; it writes nothing when there is no span
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Traceparent renders the span in ctx as a W3C traceparent header, or "" when
+// there is none
+func Traceparent(ctx context.Context) string {
+	header := http.Header{}
+	Inject(ctx, header)
+	return header.Get("traceparent")
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDisabledTracingRecordsNothing(t *testing.T) {
+	ctx, span := Start(WithJobID(context.Background(), "job-1"), "noop")
+	assert.Nil(t, span)
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("ignored"))
+	span.End()
+	assert.Equal(t, "job-1", JobID(ctx))
+	assert.Empty(t, Traceparent(ctx))
+}
+
+func TestJobTraceContinuesAcrossQueue(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	shutdown := install(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// A request continuing a caller's trace queues a job
+	header := http.Header{"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}}
+	reqCtx, reqSpan := StartKind(Extract(context.Background(), header), "POST /upload", KindServer)
+	LinkJob(WithJobID(reqCtx, "job-42"), "job-42")
+	reqSpan.End()
+
+	// The worker picks it up later and calls out under its span
+	workCtx, workSpan := StartJob(context.Background(), "job-42", "queue.process_job")
+	_, llmSpan := StartKind(workCtx, "llm.chat_completion", KindClient)
+	llmSpan.RecordError(errors.New("boom"))
+	llmSpan.End()
+	workSpan.End()
+
+	require.NoError(t, shutdown(context.Background()))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 3)
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range ended {
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.SpanContext().TraceID().String(), span.Name())
+		byName[span.Name()] = span
+	}
+	assert.Equal(t, "b7ad6b7169203331", byName["POST /upload"].Parent().SpanID().String())
+	assert.Equal(t, byName["POST /upload"].SpanContext().SpanID(), byName["queue.process_job"].Parent().SpanID())
+	assert.Equal(t, byName["queue.process_job"].SpanContext().SpanID(), byName["llm.chat_completion"].Parent().SpanID())
+	assert.Equal(t, codes.Error, byName["llm.chat_completion"].Status().Code)
+	assert.Equal(t, "boom", byName["llm.chat_completion"].Status().Description)
+	for _, name := range []string{"POST /upload", "queue.process_job", "llm.chat_completion"} {
+		found := false
+		for _, attr := range byName[name].Attributes() {
+			if string(attr.Key) == AttrJobID && attr.Value.AsString() == "job-42" {
+				found = true
+			}
+		}
+		assert.True(t, found, "%s carries the job ID", name)
+	}
+
+	// Spans are no longer recorded after shutdown
+	_, span := Start(context.Background(), "after")
+	assert.Nil(t, span)
+}
+
+func TestSpansExportedOverOTLP(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	var service string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req coltracepb.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+		mu.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, attr := range rs.Resource.Attributes {
+				if attr.Key == "service.name" {
+					service = attr.Value.GetStringValue()
+				}
+			}
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					received = append(received, span.Name)
+				}
+			}
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer collector.Close()
+
+	shutdown, err := Init(Config{Endpoint: collector.URL + "/", Headers: map[string]string{"Authorization": "secret"}, ServiceName: "scriberr-test"})
+	require.NoError(t, err)
+	_, span := Start(context.Background(), "export.me")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"export.me"}, received)
+	assert.Equal(t, "scriberr-test", service)
+}
+
+func TestInitRejectsNonHTTPEndpoint(t *testing.T) {
+	_, err := Init(Config{TracesEndpoint: "collector:4318"})
+	assert.Error(t, err)
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	extract := func(traceparent string) context.Context {
+		return Extract(context.Background(), http.Header{"Traceparent": {traceparent}})
+	}
+	assert.Empty(t, Traceparent(extract("garbage")))
+	assert.Empty(t, Traceparent(extract("00-0af7651916cd43dd8448eb211c80319c00-b7ad6b7169203331-01")))
+	assert.Empty(t, Traceparent(extract("00-00000000000000000000000000000000-b7ad6b7169203331-01")))
+	header := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	assert.Equal(t, header, Traceparent(extract(header)))
+}
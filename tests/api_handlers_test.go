@@ -1,7 +1,12 @@
 package tests
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,19 +14,31 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"scriberr/internal/api"
+	"scriberr/internal/backup"
+	"scriberr/internal/export"
+	"scriberr/internal/mail"
 	"scriberr/internal/models"
 	"scriberr/internal/queue"
 	"scriberr/internal/repository"
+	"scriberr/internal/secrets"
 	"scriberr/internal/service"
 	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/registry"
+	"scriberr/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 type APIHandlerTestSuite struct {
@@ -47,6 +64,9 @@ func (suite *APIHandlerTestSuite) SetupSuite() {
 	chatRepo := repository.NewChatRepository(suite.helper.DB)
 	noteRepo := repository.NewNoteRepository(suite.helper.DB)
 	speakerMappingRepo := repository.NewSpeakerMappingRepository(suite.helper.DB)
+	speakerRepo := repository.NewSpeakerRepository(suite.helper.DB)
+	searchRepo := repository.NewSearchRepository(suite.helper.DB)
+	recordingRepo := repository.NewRecordingRepository(suite.helper.DB)
 
 	// Initialize services
 	userService := service.NewUserService(userRepo, suite.helper.AuthService)
@@ -59,24 +79,47 @@ func (suite *APIHandlerTestSuite) SetupSuite() {
 	assert.NoError(suite.T(), err)
 
 	suite.taskQueue = queue.NewTaskQueue(1, suite.unifiedProcessor)
-	suite.handler = api.NewHandler(
-		suite.helper.Config,
-		suite.helper.AuthService,
-		userService,
-		fileService,
-		jobRepo,
-		apiKeyRepo,
-		profileRepo,
-		userRepo,
-		llmConfigRepo,
-		summaryRepo,
-		chatRepo,
-		noteRepo,
-		speakerMappingRepo,
-		suite.taskQueue,
-		suite.unifiedProcessor,
-		suite.quickTranscription,
-	)
+	suite.handler = api.NewHandler(suite.helper.Config, api.Dependencies{
+		AuthService:         suite.helper.AuthService,
+		UserService:         userService,
+		FileService:         fileService,
+		JobRepo:             jobRepo,
+		APIKeyRepo:          apiKeyRepo,
+		ProfileRepo:         profileRepo,
+		UserRepo:            userRepo,
+		LLMConfigRepo:       llmConfigRepo,
+		SummaryRepo:         summaryRepo,
+		ChatRepo:            chatRepo,
+		NoteRepo:            noteRepo,
+		SpeakerMappingRepo:  speakerMappingRepo,
+		SpeakerRepo:         speakerRepo,
+		TagRepo:             repository.NewTagRepository(suite.helper.DB),
+		CollectionRepo:      repository.NewCollectionRepository(suite.helper.DB),
+		SearchRepo:          searchRepo,
+		RecordingRepo:       recordingRepo,
+		TaskQueue:           suite.taskQueue,
+		UnifiedProcessor:    suite.unifiedProcessor,
+		QuickTranscription:  suite.quickTranscription,
+		URLIngest:           service.NewURLIngestService(suite.helper.Config, jobRepo, suite.taskQueue),
+		Voiceprints:         service.NewVoiceprintService(suite.helper.Config, speakerMappingRepo),
+		SpeakerAttributes:   service.NewSpeakerAttributeService(repository.NewSpeakerAttributeRepository(suite.helper.DB)),
+		JobDeleter:          service.NewJobDeleter(suite.helper.Config.UploadDir, fileService, jobRepo, chatRepo, noteRepo, summaryRepo, speakerMappingRepo),
+		RetentionRepo:       repository.NewRetentionRepository(suite.helper.DB),
+		AWSTagRouteRepo:     repository.NewAWSTagRouteRepository(suite.helper.DB),
+		VocabularyRepo:      repository.NewVocabularyRepository(suite.helper.DB),
+		CorrectionRuleRepo:  repository.NewCorrectionRuleRepository(suite.helper.DB),
+		BenchmarkRepo:       repository.NewBenchmarkRepository(suite.helper.DB),
+		Storage:             service.NewStorageService(suite.helper.Config, repository.NewStorageRepository(suite.helper.DB), fileService),
+		ReportService:       service.NewReportService(repository.NewReportRepository(suite.helper.DB), fileService, mail.NewSender(mail.Config{})),
+		FieldPermissionRepo: repository.NewFieldPermissionRepository(suite.helper.DB),
+		AdapterConfigRepo:   repository.NewAdapterConfigRepository(suite.helper.DB),
+		FeedService:         service.NewFeedService(suite.helper.Config, repository.NewFeedRepository(suite.helper.DB), profileRepo, service.NewURLIngestService(suite.helper.Config, jobRepo, suite.taskQueue)),
+		Integrity:           service.NewIntegrityService(jobRepo, fileService, suite.taskQueue),
+		Backups:             backup.NewService(suite.helper.Config, suite.helper.DB, "test"),
+		Retention: service.NewRetentionService(suite.helper.Config, repository.NewRetentionRepository(suite.helper.DB), jobRepo, noteRepo, fileService,
+			service.NewJobDeleter(suite.helper.Config.UploadDir, fileService, jobRepo, chatRepo, noteRepo, summaryRepo, speakerMappingRepo)),
+		LibraryImports: service.NewLibraryImportService(suite.helper.Config, repository.NewLibraryImportRepository(suite.helper.DB), jobRepo, profileRepo, fileService, suite.taskQueue),
+	})
 
 	// Set up router
 	suite.router = api.SetupRoutes(suite.handler, suite.helper.AuthService)
@@ -271,6 +314,91 @@ func (suite *APIHandlerTestSuite) TestListTranscriptionJobs() {
 	assert.True(suite.T(), foundJob)
 }
 
+// Test filtering, sorting and cursor pagination of the job list
+func (suite *APIHandlerTestSuite) TestListTranscriptionJobsQuery() {
+	profileID := "list-query-profile"
+	english, french := "en", "fr"
+	jobRepo := repository.NewJobRepository(suite.helper.DB)
+	salesTags := `[{"Key":"team","Value":"sales"}]`
+	seconds := func(s float64) *float64 { return &s }
+	jobs := []*models.TranscriptionJob{
+		{Status: models.StatusCompleted, AudioSeconds: seconds(300), Tags: &salesTags, Parameters: models.WhisperXParams{ModelFamily: "whisper", Language: &english}},
+		{Status: models.StatusCompleted, AudioSeconds: seconds(60), Parameters: models.WhisperXParams{ModelFamily: "runpod-whisperx", Language: &french}},
+		{Status: models.StatusFailed, Tags: &salesTags, Parameters: models.WhisperXParams{ModelFamily: "whisper", Language: &english}},
+		{Status: models.StatusCompleted, AudioSeconds: seconds(1800), Parameters: models.WhisperXParams{ModelFamily: "whisper", Language: &english}},
+		{Status: models.StatusPending, Parameters: models.WhisperXParams{ModelFamily: "whisper"}},
+	}
+	for i, job := range jobs {
+		title := fmt.Sprintf("List query job %d", i)
+		if i == 1 {
+			title = "List_query job 1"
+		}
+		job.Title, job.AudioPath, job.ProfileID = &title, "test/path/audio.mp3", &profileID
+		suite.Require().NoError(jobRepo.Create(context.Background(), job))
+	}
+
+	list := func(query string) ([]string, map[string]interface{}) {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list?profile_id="+profileID+"&"+query, nil, false)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response struct {
+			Jobs       []models.TranscriptionJob `json:"jobs"`
+			Pagination map[string]interface{}    `json:"pagination"`
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ids := []string{}
+		for _, job := range response.Jobs {
+			ids = append(ids, job.ID)
+		}
+		return ids, response.Pagination
+	}
+
+	ids, _ := list("status=completed,failed&language=en")
+	assert.ElementsMatch(suite.T(), []string{jobs[0].ID, jobs[2].ID, jobs[3].ID}, ids)
+	ids, _ = list("tag=team=sales&adapter=whisper")
+	assert.ElementsMatch(suite.T(), []string{jobs[0].ID, jobs[2].ID}, ids)
+	ids, _ = list("min_duration=100&max_duration=600")
+	assert.Equal(suite.T(), []string{jobs[0].ID}, ids)
+	ids, _ = list("since=2000-01-01&until=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	assert.Len(suite.T(), ids, len(jobs))
+	ids, _ = list("until=2000-01-01")
+	assert.Empty(suite.T(), ids)
+
+	// Wildcards in the search are matched literally
+	ids, _ = list("q=List_query")
+	assert.Equal(suite.T(), []string{jobs[1].ID}, ids)
+	ids, _ = list("q=query%25job")
+	assert.Empty(suite.T(), ids)
+
+	// Walking the pages by cursor visits every job once, in order
+	var cursor string
+	walk := func(sort string) []string {
+		var walked []string
+		cursor = ""
+		for page := 0; page <= len(jobs); page++ {
+			ids, pagination := list(sort + "&limit=2&cursor=" + cursor)
+			walked = append(walked, ids...)
+			assert.EqualValues(suite.T(), len(jobs), pagination["total"])
+			next, _ := pagination["next_cursor"].(string)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		return walked
+	}
+	walked := walk("sort_by=duration&sort_order=asc")
+	suite.Require().Len(walked, len(jobs))
+	assert.ElementsMatch(suite.T(), []string{jobs[2].ID, jobs[4].ID}, walked[:2], "jobs without a duration sort first")
+	assert.Equal(suite.T(), []string{jobs[1].ID, jobs[0].ID, jobs[3].ID}, walked[2:])
+	walked = walk("sort_by=created_at")
+	assert.ElementsMatch(suite.T(), []string{jobs[0].ID, jobs[1].ID, jobs[2].ID, jobs[3].ID, jobs[4].ID}, walked)
+
+	for _, query := range []string{"sort_by=audio_path", "sort_order=sideways", "since=yesterday", "min_duration=long", "cursor=bogus", "sort_by=title&cursor=" + cursor} {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list?"+query, nil, false)
+		assert.Equal(suite.T(), 400, w.Code, query)
+	}
+}
+
 // Test getting transcription job by ID
 func (suite *APIHandlerTestSuite) TestGetTranscriptionJobByID() {
 	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Test Job by ID")
@@ -297,6 +425,20 @@ func (suite *APIHandlerTestSuite) TestGetJobStatus() {
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), testJob.ID, response.ID)
 	assert.Equal(suite.T(), models.StatusPending, response.Status)
+	assert.Empty(suite.T(), response.Stages)
+
+	// The stages a processing job has been through are listed in order
+	jobRepo := repository.NewJobRepository(suite.helper.DB)
+	for _, stage := range []string{models.StageConverting, models.StageTranscribing} {
+		suite.Require().NoError(jobRepo.EnterStage(context.Background(), testJob.ID, stage))
+	}
+	w = suite.makeAuthenticatedRequest("GET", fmt.Sprintf("/api/v1/transcription/%s/status", testJob.ID), nil, false)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), models.StageTranscribing, response.Stage)
+	suite.Require().Len(response.Stages, 2)
+	assert.Equal(suite.T(), models.StageConverting, response.Stages[0].Stage)
+	assert.NotNil(suite.T(), response.Stages[0].EndedAt)
+	assert.Nil(suite.T(), response.Stages[1].EndedAt)
 }
 
 // Test updating transcription title
@@ -332,6 +474,24 @@ func (suite *APIHandlerTestSuite) TestDeleteTranscriptionJob() {
 	assert.Equal(suite.T(), 404, w.Code)
 }
 
+// Test cancelling a transcription job
+func (suite *APIHandlerTestSuite) TestCancelJob() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Job to Cancel")
+
+	w := suite.makeAuthenticatedRequest("POST", fmt.Sprintf("/api/v1/transcription/%s/cancel", testJob.ID), nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	var job models.TranscriptionJob
+	suite.Require().NoError(suite.helper.DB.First(&job, "id = ?", testJob.ID).Error)
+	assert.Equal(suite.T(), models.StatusCancelled, job.Status)
+
+	// Only queued and running jobs can be cancelled
+	w = suite.makeAuthenticatedRequest("POST", fmt.Sprintf("/api/v1/transcription/%s/cancel", testJob.ID), nil, false)
+	assert.Equal(suite.T(), 409, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/non-existent/cancel", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
 // Test getting supported models
 func (suite *APIHandlerTestSuite) TestGetSupportedModels() {
 	w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/models", nil, false)
@@ -354,6 +514,140 @@ func (suite *APIHandlerTestSuite) TestGetSupportedModels() {
 	assert.NotNil(suite.T(), languages)
 }
 
+func (suite *APIHandlerTestSuite) TestModelSchema() {
+	env := suite.T().TempDir()
+	registry.RegisterTranscriptionAdapter("whisperx", adapters.NewWhisperXAdapter(env))
+	registry.RegisterDiarizationAdapter("pyannote", adapters.NewPyAnnoteAdapter(env))
+	defer registry.ClearRegistry()
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/models/whisperx/schema", nil, false)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var schema transcription.ModelSchema
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &schema))
+	assert.Equal(suite.T(), "whisperx", schema.ModelID)
+	assert.Equal(suite.T(), transcription.ModelKindTranscription, schema.Kind)
+	assert.Equal(suite.T(), []string{"whisper"}, schema.ModelFamilies)
+	var computeType *interfaces.ParameterSchema
+	for i := range schema.Parameters {
+		if schema.Parameters[i].Name == "compute_type" {
+			computeType = &schema.Parameters[i]
+		}
+	}
+	suite.Require().NotNil(computeType)
+	assert.Equal(suite.T(), "string", computeType.Type)
+	assert.Equal(suite.T(), "float32", computeType.Default)
+	assert.Contains(suite.T(), computeType.Options, "int8")
+	assert.NotEmpty(suite.T(), computeType.Group)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/models/pyannote/schema", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var diarization transcription.ModelSchema
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &diarization))
+	assert.Equal(suite.T(), transcription.ModelKindDiarization, diarization.Kind)
+	assert.NotEmpty(suite.T(), diarization.Parameters)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/models/unknown/schema", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestAdapterConfigs() {
+	env := transcription.AdapterEnvironment{EnvPath: suite.T().TempDir()}
+	suite.Require().NoError(suite.unifiedProcessor.SetAdapterConfigs(env, repository.NewAdapterConfigRepository(suite.helper.DB)))
+	defer registry.ClearRegistry()
+	reg := registry.GetRegistry()
+	statuses := func(w *httptest.ResponseRecorder) map[string]transcription.AdapterStatus {
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var list []transcription.AdapterStatus
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+		byID := map[string]transcription.AdapterStatus{}
+		for _, status := range list {
+			byID[status.ModelID] = status
+		}
+		return byID
+	}
+	configure := func(modelID string, body map[string]interface{}) *httptest.ResponseRecorder {
+		return suite.makeAuthenticatedRequest("PUT", "/api/v1/admin/adapters/"+modelID, body, true)
+	}
+
+	// Without ENABLE_DEFAULT_ADAPTERS only the remote adapters are registered
+	adapterStatuses := statuses(suite.makeAuthenticatedRequest("GET", "/api/v1/admin/adapters", nil, true))
+	assert.True(suite.T(), adapterStatuses[interfaces.RunPodWhisperX].Registered)
+	assert.False(suite.T(), adapterStatuses["parakeet"].Registered)
+	assert.Equal(suite.T(), transcription.AdapterSourceEnvironment, adapterStatuses["parakeet"].Source)
+
+	// Enabling an adapter registers it right away
+	adapterStatuses = statuses(configure("parakeet", map[string]interface{}{"enabled": true}))
+	assert.True(suite.T(), adapterStatuses["parakeet"].Registered)
+	assert.Equal(suite.T(), transcription.AdapterSourceDatabase, adapterStatuses["parakeet"].Source)
+	_, err := reg.GetTranscriptionAdapter("parakeet")
+	assert.NoError(suite.T(), err)
+
+	// Endpoints and API keys replace the environment's; keys are only returned masked
+	w := configure(interfaces.RunPodWhisperX, map[string]interface{}{"endpoint": "abc123", "api_key": "runpod-secret"})
+	assert.NotContains(suite.T(), w.Body.String(), "runpod-secret")
+	adapterStatuses = statuses(w)
+	assert.True(suite.T(), adapterStatuses[interfaces.RunPodWhisperX].HasAPIKey)
+	assert.Equal(suite.T(), "********cret", adapterStatuses[interfaces.RunPodWhisperX].APIKey)
+	assert.Equal(suite.T(), "value", adapterStatuses[interfaces.RunPodWhisperX].APIKeySource)
+	assert.Equal(suite.T(), "abc123", adapterStatuses[interfaces.RunPodWhisperX].Endpoint)
+	adapter, err := reg.GetTranscriptionAdapter(interfaces.RunPodWhisperX)
+	suite.Require().NoError(err)
+	runpod := adapter.(*adapters.RunPodAdapter)
+	assert.Equal(suite.T(), "https://api.runpod.ai/v2/abc123", runpod.RunPodBaseURL)
+	assert.Equal(suite.T(), "runpod-secret", runpod.RunPodAPIKey)
+
+	// Keys can reference a secret store instead; the secret is fetched when registering
+	secretsDir := suite.T().TempDir()
+	suite.Require().NoError(os.WriteFile(filepath.Join(secretsDir, "runpod"), []byte("runpod-from-file\n"), 0600))
+	previousResolver := secrets.Default()
+	secrets.SetDefault(secrets.NewResolver(secrets.Config{Dir: secretsDir}))
+	defer secrets.SetDefault(previousResolver)
+	w = configure(interfaces.RunPodWhisperX, map[string]interface{}{"api_key": "docker-secret://runpod"})
+	assert.NotContains(suite.T(), w.Body.String(), "runpod-from-file")
+	adapterStatuses = statuses(w)
+	assert.Equal(suite.T(), "docker-secret://runpod", adapterStatuses[interfaces.RunPodWhisperX].APIKey)
+	assert.Equal(suite.T(), secrets.SchemeDockerSecret, adapterStatuses[interfaces.RunPodWhisperX].APIKeySource)
+	adapter, err = reg.GetTranscriptionAdapter(interfaces.RunPodWhisperX)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "runpod-from-file", adapter.(*adapters.RunPodAdapter).RunPodAPIKey)
+	assert.Equal(suite.T(), 400, configure(interfaces.RunPodWhisperX, map[string]interface{}{"api_key": "docker-secret://missing"}).Code)
+
+	statuses(configure("whisperx", map[string]interface{}{"endpoint": "http://whisperx:8000"}))
+	adapter, err = reg.GetTranscriptionAdapter("whisperx")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), interfaces.LocalWhisperX, adapter.GetCapabilities().ModelFamily)
+
+	// Settings an adapter does not take are rejected
+	assert.Equal(suite.T(), 400, configure("parakeet", map[string]interface{}{"api_key": "key"}).Code)
+	assert.Equal(suite.T(), 400, configure("whisperx", map[string]interface{}{"endpoint": "whisperx:8000"}).Code)
+	assert.Equal(suite.T(), 400, configure(interfaces.ModalWhisperX, map[string]interface{}{"api_key": "no-secret"}).Code)
+	assert.Equal(suite.T(), 404, configure("unknown", map[string]interface{}{"enabled": true}).Code)
+
+	// Disabled adapters are removed
+	adapterStatuses = statuses(configure(interfaces.RunPodWhisperX, map[string]interface{}{"enabled": false}))
+	assert.False(suite.T(), adapterStatuses[interfaces.RunPodWhisperX].Registered)
+	_, err = reg.GetTranscriptionAdapter(interfaces.RunPodWhisperX)
+	assert.Error(suite.T(), err)
+
+	// Resetting an adapter brings back the environment's settings
+	adapterStatuses = statuses(suite.makeAuthenticatedRequest("DELETE", "/api/v1/admin/adapters/"+interfaces.RunPodWhisperX, nil, true))
+	assert.True(suite.T(), adapterStatuses[interfaces.RunPodWhisperX].Registered)
+	assert.False(suite.T(), adapterStatuses[interfaces.RunPodWhisperX].HasAPIKey)
+	adapterStatuses = statuses(suite.makeAuthenticatedRequest("DELETE", "/api/v1/admin/adapters/parakeet", nil, true))
+	assert.False(suite.T(), adapterStatuses["parakeet"].Registered)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("DELETE", "/api/v1/admin/adapters/parakeet", nil, true).Code)
+
+	// Settings changed elsewhere are picked up on reload
+	endpoint := "http://other-whisperx:8000"
+	suite.Require().NoError(suite.helper.DB.Model(&models.AdapterConfig{}).Where("model_id = ?", "whisperx").Update("endpoint", endpoint).Error)
+	adapterStatuses = statuses(suite.makeAuthenticatedRequest("POST", "/api/v1/admin/adapters/reload", nil, true))
+	assert.Equal(suite.T(), endpoint, adapterStatuses["whisperx"].Endpoint)
+	adapter, err = reg.GetTranscriptionAdapter("whisperx")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), endpoint, adapter.(*adapters.RunPodAdapter).RunPodBaseURL)
+	statuses(suite.makeAuthenticatedRequest("DELETE", "/api/v1/admin/adapters/whisperx", nil, true))
+}
+
 // Test profile management
 func (suite *APIHandlerTestSuite) TestProfileManagement() {
 	// List profiles
@@ -392,11 +686,88 @@ func (suite *APIHandlerTestSuite) TestProfileManagement() {
 	w = suite.makeAuthenticatedRequest("PUT", fmt.Sprintf("/api/v1/profiles/%s", createResponse.ID), updateData, false)
 	assert.Equal(suite.T(), 200, w.Code)
 
+	// Fallback chains only name known model families
+	updateData["parameters"] = map[string]interface{}{"model_family": "modal-whisperx", "fallback_model_families": "runpod-whisperx, whisper"}
+	w = suite.makeAuthenticatedRequest("PUT", fmt.Sprintf("/api/v1/profiles/%s", createResponse.ID), updateData, false)
+	assert.Equal(suite.T(), 200, w.Code, w.Body.String())
+	updateData["parameters"] = map[string]interface{}{"model_family": "modal-whisperx", "fallback_model_families": "runpod-whisperx,gpt"}
+	w = suite.makeAuthenticatedRequest("PUT", fmt.Sprintf("/api/v1/profiles/%s", createResponse.ID), updateData, false)
+	assert.Equal(suite.T(), 400, w.Code)
+
 	// Delete profile
 	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/profiles/%s", createResponse.ID), nil, false)
 	assert.Equal(suite.T(), 200, w.Code)
 }
 
+// Test profile versions and cloning
+func (suite *APIHandlerTestSuite) TestProfileVersions() {
+	profileData := map[string]interface{}{
+		"name":       "Versioned Profile",
+		"parameters": map[string]interface{}{"model": "base", "batch_size": 16},
+	}
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/profiles/", profileData, false)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var profile models.TranscriptionProfile
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(suite.T(), 1, profile.Version)
+	base := fmt.Sprintf("/api/v1/profiles/%s", profile.ID)
+
+	// Changing parameters makes a version; marking default does not
+	profileData["parameters"] = map[string]interface{}{"model": "large-v3", "batch_size": 16}
+	w = suite.makeAuthenticatedRequest("PUT", base, profileData, false)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var updated models.TranscriptionProfile
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(suite.T(), 2, updated.Version)
+	w = suite.makeAuthenticatedRequest("PUT", base, profileData, false)
+	suite.Require().Equal(200, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", base+"/set-default", nil, false)
+	suite.Require().Equal(200, w.Code)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/versions", nil, false)
+	suite.Require().Equal(200, w.Code)
+	var versions []models.ProfileVersion
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &versions))
+	suite.Require().Len(versions, 2)
+	assert.Equal(suite.T(), "base", versions[0].Parameters.Model)
+	assert.Equal(suite.T(), "large-v3", versions[1].Parameters.Model)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/versions/1", nil, false)
+	suite.Require().Equal(200, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", base+"/versions/3", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", base+"/versions/first", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	// A clone of an old version reproduces its parameters
+	w = suite.makeAuthenticatedRequest("POST", base+"/clone", map[string]interface{}{"version": 1}, false)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var clone models.TranscriptionProfile
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &clone))
+	assert.NotEqual(suite.T(), profile.ID, clone.ID)
+	assert.Equal(suite.T(), "Versioned Profile (copy)", clone.Name)
+	assert.Equal(suite.T(), "base", clone.Parameters.Model)
+	assert.Equal(suite.T(), 1, clone.Version)
+	assert.False(suite.T(), clone.IsDefault)
+
+	w = suite.makeAuthenticatedRequest("POST", base+"/clone", map[string]interface{}{"name": "Large"}, false)
+	suite.Require().Equal(201, w.Code)
+	var latest models.TranscriptionProfile
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &latest))
+	assert.Equal(suite.T(), "Large", latest.Name)
+	assert.Equal(suite.T(), "large-v3", latest.Parameters.Model)
+
+	w = suite.makeAuthenticatedRequest("POST", base+"/clone", map[string]interface{}{"version": 7}, false)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	for _, id := range []string{clone.ID, latest.ID, profile.ID} {
+		w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/profiles/"+id, nil, false)
+		assert.Equal(suite.T(), 200, w.Code)
+	}
+	w = suite.makeAuthenticatedRequest("GET", base+"/versions", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
 // Test notes management
 func (suite *APIHandlerTestSuite) TestNotesManagement() {
 	// Create a transcription job first
@@ -466,6 +837,8 @@ func (suite *APIHandlerTestSuite) TestGetQueueStats() {
 	assert.Contains(suite.T(), response, "processing_jobs")
 	assert.Contains(suite.T(), response, "completed_jobs")
 	assert.Contains(suite.T(), response, "failed_jobs")
+	suite.Require().Contains(response, "resources")
+	assert.Contains(suite.T(), response["resources"], "reserved_gpu_mb")
 }
 
 // Test multipart file upload (transcription submit)
@@ -513,6 +886,72 @@ func (suite *APIHandlerTestSuite) TestTranscriptionSubmit() {
 	assert.NotEmpty(suite.T(), response.ID)
 	assert.Equal(suite.T(), "API Handler Test Audio", *response.Title)
 	assert.Equal(suite.T(), models.StatusPending, response.Status)
+	// Jobs are attributed to the API key that submitted them
+	assert.NotNil(suite.T(), response.APIKeyID)
+}
+
+func (suite *APIHandlerTestSuite) TestDryRun() {
+	var before int64
+	suite.helper.DB.Model(&models.TranscriptionJob{}).Count(&before)
+	suite.helper.Config.AdapterCostPerMinute = map[string]float64{"whisperx": 0.01}
+	suite.unifiedProcessor.SetCostModel(suite.helper.Config.AdapterCostPerMinute)
+	defer func() {
+		suite.helper.Config.AdapterCostPerMinute = nil
+		suite.unifiedProcessor.SetCostModel(nil)
+	}()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "dry.mp3")
+	suite.Require().NoError(err)
+	part.Write(bytes.Repeat([]byte{0}, 32000*90)) // 90 seconds without ffprobe
+	writer.WriteField("dry_run", "true")
+	writer.WriteField("diarization", "true")
+	writer.Close()
+	req, _ := http.NewRequest("POST", "/api/v1/transcription/submit", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+
+	var response api.DryRunResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(suite.T(), response.DryRun)
+	assert.Equal(suite.T(), "whisperx", response.TranscriptionModel)
+	assert.Empty(suite.T(), response.DiarizationModel) // WhisperX diarizes itself
+	assert.Greater(suite.T(), response.AudioSeconds, 0.0)
+	suite.Require().NotNil(response.EstimatedCost)
+	assert.InDelta(suite.T(), response.AudioSeconds/60*0.01, *response.EstimatedCost, 1e-9)
+	assert.Equal(suite.T(), "USD", response.Currency)
+	assert.Equal(suite.T(), len(response.Problems) == 0, response.Ready)
+
+	// Nothing was created, queued or kept
+	var after int64
+	suite.helper.DB.Model(&models.TranscriptionJob{}).Count(&after)
+	assert.Equal(suite.T(), before, after)
+	uploads, _ := filepath.Glob(filepath.Join(suite.helper.Config.UploadDir, "*", "*.mp3"))
+	for _, upload := range uploads {
+		assert.NotContains(suite.T(), upload, "dry")
+	}
+	var events int64
+	suite.helper.DB.Model(&models.AuditEvent{}).Where("path = ?", "/api/v1/transcription/submit").Count(&events)
+	assert.Zero(suite.T(), events)
+
+	// Starting an uploaded job as a dry run leaves it untouched and reports its missing audio
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Dry start")
+	suite.helper.DB.Model(job).Update("status", models.StatusUploaded)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/start?dry_run=true",
+		map[string]interface{}{"model_family": "whisper"}, false)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var started api.DryRunResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &started))
+	assert.False(suite.T(), started.Ready)
+	assert.Nil(suite.T(), started.EstimatedCost)
+	assert.Contains(suite.T(), strings.Join(started.Problems, "\n"), "audio is not accessible")
+	var stored models.TranscriptionJob
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Equal(suite.T(), models.StatusUploaded, stored.Status)
 }
 
 // Test error responses for non-existent resources
@@ -556,6 +995,3471 @@ func (suite *APIHandlerTestSuite) TestLogout() {
 	assert.Equal(suite.T(), 200, w.Code)
 }
 
-func TestAPIHandlerTestSuite(t *testing.T) {
-	suite.Run(t, new(APIHandlerTestSuite))
+// Test browser recording sessions: ordered, idempotent chunk uploads and finalize
+func (suite *APIHandlerTestSuite) TestRecordingSession() {
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/recordings", map[string]string{"mime_type": "audio/webm;codecs=opus"}, true)
+	assert.Equal(suite.T(), 201, w.Code)
+
+	var session models.RecordingSession
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &session))
+	assert.NotEmpty(suite.T(), session.ID)
+	base := "/api/v1/recordings/" + session.ID
+
+	w = suite.makeAuthenticatedRequest("PUT", base+"/chunks/0", []byte("first-chunk"), true)
+	assert.Equal(suite.T(), 200, w.Code)
+	w = suite.makeAuthenticatedRequest("PUT", base+"/chunks/1", []byte("second-chunk"), true)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	// Re-sending a stored chunk is a no-op
+	w = suite.makeAuthenticatedRequest("PUT", base+"/chunks/0", []byte("first-chunk"), true)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	// A gap in the sequence is rejected with the index to resume from
+	w = suite.makeAuthenticatedRequest("PUT", base+"/chunks/3", []byte("fourth-chunk"), true)
+	assert.Equal(suite.T(), 409, w.Code)
+	var conflict map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &conflict))
+	assert.Equal(suite.T(), float64(2), conflict["expected_index"])
+
+	w = suite.makeAuthenticatedRequest("GET", base, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(suite.T(), 2, session.ChunkCount)
+	assert.Equal(suite.T(), int64(len("first-chunksecond-chunk")), session.Bytes)
+
+	w = suite.makeAuthenticatedRequest("POST", base+"/finalize", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var job models.TranscriptionJob
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(suite.T(), session.ID, job.ID)
+
+	data, err := os.ReadFile(job.AudioPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "first-chunksecond-chunk", string(data))
+
+	// Finalizing again returns the same job, and further chunks are refused
+	w = suite.makeAuthenticatedRequest("POST", base+"/finalize", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var again models.TranscriptionJob
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &again))
+	assert.Equal(suite.T(), job.ID, again.ID)
+
+	w = suite.makeAuthenticatedRequest("PUT", base+"/chunks/2", []byte("late-chunk"), true)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	// Recordings may not grow past the size limit
+	suite.helper.Config.RecordingMaxMB = 1
+	defer func() { suite.helper.Config.RecordingMaxMB = 0 }()
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/recordings", map[string]string{"mime_type": "audio/webm"}, true)
+	suite.Require().Equal(201, w.Code)
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &session))
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/recordings/"+session.ID+"/chunks/0", bytes.Repeat([]byte("a"), 1<<20), true)
+	assert.Equal(suite.T(), 200, w.Code)
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/recordings/"+session.ID+"/chunks/1", []byte("one byte too many"), true)
+	assert.Equal(suite.T(), 413, w.Code)
+
+	// Unknown sessions are refused before anything is locked
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/recordings/unknown/chunks/0", []byte("chunk"), true)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+// Test alignment model prefetch validation
+func (suite *APIHandlerTestSuite) TestPrefetchAlignmentModels() {
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/admin/alignment-models", map[string]interface{}{"languages": []string{}}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/alignment-models", map[string]interface{}{"languages": []string{"en; rm -rf /"}}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	// No local WhisperX adapter is registered in tests
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/alignment-models", map[string]interface{}{"languages": []string{"de", "sv"}}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+}
+
+// Test transcript export with chapters and speaker names
+func (suite *APIHandlerTestSuite) TestExportTranscript() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Weekly sync")
+	transcript := `{"text": "Hello there. Prices <go> up.", "segments": [{"start": 0, "end": 1.5, "text": " Hello there.", "speaker": "SPEAKER_00"}, {"start": 61.25, "end": 3725.5, "text": "Prices <go> up.", "speaker": "SPEAKER_01"}]}`
+	chapters := `[{"title": "Greetings", "start": 0, "end": 1.5, "first_segment": 0, "last_segment": 0}, {"title": "Pricing", "start": 61.25, "end": 3725.5, "first_segment": 1, "last_segment": 1}]`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript, "chapters": chapters,
+	}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "Alice"}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/export"
+
+	w := suite.makeAuthenticatedRequest("GET", base+"?format=srt", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), `filename="Weekly sync.srt"`)
+	assert.Equal(suite.T(), "1\n00:00:00,000 --> 00:00:01,500\n[Greetings]\nAlice: Hello there.\n\n"+
+		"2\n00:01:01,250 --> 01:02:05,500\n[Pricing]\nSPEAKER_01: Prices <go> up.\n\n", w.Body.String())
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=vtt", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "WEBVTT\n\nNOTE\nTitle: Weekly sync\nJob: "+job.ID+"\n\n"+
+		"NOTE Chapter: Greetings\n\n00:00:00.000 --> 00:00:01.500\n<v Alice>Hello there.\n\n"+
+		"NOTE Chapter: Pricing\n\n00:01:01.250 --> 01:02:05.500\n<v SPEAKER_01>Prices &lt;go&gt; up.\n\n", w.Body.String())
+
+	// Cue settings are written on every cue
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=vtt&line=-2&position=50%25&align=center", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "00:00:00.000 --> 00:00:01.500 line:-2 position:50% align:center\n<v Alice>")
+	assert.Contains(suite.T(), w.Body.String(), "00:01:01.250 --> 01:02:05.500 line:-2 position:50% align:center\n")
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"?format=vtt&position=150%25", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"?format=vtt&align=middle", nil, true).Code)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=chapters", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "chapter-2\n00:01:01.250 --> 01:02:05.500\nPricing\n")
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=json", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var exported struct {
+		Segments []struct {
+			Speaker string `json:"speaker"`
+		} `json:"segments"`
+		Chapters []service.Chapter `json:"chapters"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &exported))
+	assert.Equal(suite.T(), "Alice", exported.Segments[0].Speaker)
+	assert.Len(suite.T(), exported.Chapters, 2)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=docx", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestExportLocale() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Hearing")
+	transcript := `{"text": "Order. Thank you.", "segments": [{"start": 0, "end": 1.5, "text": "Order.", "speaker": "SPEAKER_00"}, {"start": 61.25, "end": 62, "text": "Thank you; seated."}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/export?format=csv"
+
+	w := suite.makeAuthenticatedRequest("GET", base, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "start,end,speaker,text\n0.000,1.500,SPEAKER_00,Order.\n61.250,62.000,,Thank you; seated.\n", w.Body.String())
+
+	// The user's locale applies to their exports unless one is requested
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/user/settings", map[string]interface{}{"locale": "de-DE", "time_zone": "Europe/Berlin"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	defer suite.makeAuthenticatedRequest("PUT", "/api/v1/user/settings", map[string]interface{}{"locale": "", "time_zone": ""}, true)
+	w = suite.makeAuthenticatedRequest("GET", base, nil, true)
+	assert.Equal(suite.T(), "start;end;speaker;text\n0,000;1,500;SPEAKER_00;Order.\n61,250;62,000;;\"Thank you; seated.\"\n", w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", base+"&locale=en-US", nil, true)
+	assert.Contains(suite.T(), w.Body.String(), "0.000,1.500,SPEAKER_00,Order.")
+
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"&timezone=Mars/Olympus", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"&locale=xx", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("PUT", "/api/v1/user/settings", map[string]interface{}{"time_zone": "Nowhere"}, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestNotificationSettings() {
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/user/settings", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var settings map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &settings))
+	assert.Equal(suite.T(), "", settings["notification_email"])
+	assert.Equal(suite.T(), true, settings["notify_on_completed"])
+	assert.Equal(suite.T(), true, settings["notify_on_failed"])
+	assert.Equal(suite.T(), false, settings["notify_attach_transcript"])
+
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/user/settings", map[string]interface{}{
+		"notification_email": " alice@example.com ", "notify_on_failed": false, "notify_attach_transcript": true,
+	}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	defer suite.makeAuthenticatedRequest("PUT", "/api/v1/user/settings", map[string]interface{}{
+		"notification_email": "", "notify_on_failed": true, "notify_attach_transcript": false,
+	}, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &settings))
+	assert.Equal(suite.T(), "alice@example.com", settings["notification_email"])
+	assert.Equal(suite.T(), true, settings["notify_on_completed"])
+	assert.Equal(suite.T(), false, settings["notify_on_failed"])
+	assert.Equal(suite.T(), true, settings["notify_attach_transcript"])
+
+	for _, email := range []string{"not an address", "Alice <alice@example.com>", "alice@example.com\nBcc: eve@example.com"} {
+		w = suite.makeAuthenticatedRequest("PUT", "/api/v1/user/settings", map[string]interface{}{"notification_email": email}, true)
+		assert.Equal(suite.T(), 400, w.Code, email)
+	}
+}
+
+func (suite *APIHandlerTestSuite) TestRecordedAt() {
+	upload := func(recordedAt string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("audio", "meeting.mp3")
+		suite.Require().NoError(err)
+		part.Write([]byte("not really audio"))
+		writer.WriteField("recorded_at", recordedAt)
+		writer.Close()
+		req, _ := http.NewRequest("POST", "/api/v1/transcription/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(suite.T(), 400, upload("yesterday afternoon").Code)
+	w := upload("2024-05-01T14:03:00+02:00")
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &job))
+	suite.Require().NotNil(job.RecordedAt)
+	assert.Equal(suite.T(), models.RecordedAtUser, job.RecordedAtSource)
+
+	transcript := `{"text": "Order.", "segments": [{"start": 22.25, "end": 24, "text": "Order."}]}`
+	suite.Require().NoError(suite.helper.DB.Model(&job).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": transcript}).Error)
+
+	// Segments get wall-clock times in the requested time zone
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/transcript?timezone=Europe/Berlin", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var response struct {
+		SegmentTimes []api.SegmentTime `json:"segment_times"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	suite.Require().Len(response.SegmentTimes, 1)
+	assert.Equal(suite.T(), "14:03:22", response.SegmentTimes[0].Start.Format("15:04:05"))
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/export?format=csv&timezone=UTC", nil, true)
+	assert.Equal(suite.T(), "start,end,start_time,end_time,speaker,text\n22.250,24.000,2024-05-01 12:03:22,2024-05-01 12:03:24,,Order.\n", w.Body.String())
+
+	// The start time can be corrected or cleared later
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/transcription/"+job.ID+"/recorded-at", map[string]interface{}{"recorded_at": "2024-05-01T09:00:00Z"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/transcription/"+job.ID+"/recorded-at", map[string]interface{}{"recorded_at": nil}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/transcript", nil, true)
+	assert.NotContains(suite.T(), w.Body.String(), "segment_times")
+}
+
+func (suite *APIHandlerTestSuite) TestParameterOverrides() {
+	registry.RegisterTranscriptionAdapter("whisperx", adapters.NewWhisperXAdapter(suite.T().TempDir()))
+	defer registry.ClearRegistry()
+	profile := models.TranscriptionProfile{Name: "Overridden", IsDefault: true, Parameters: models.WhisperXParams{
+		ModelFamily: "whisper", Model: "small", Device: "cpu", BatchSize: 8, ComputeType: "int8",
+		Task: "transcribe", VadMethod: "pyannote", VadOnset: 0.5, VadOffset: 0.363, BestOf: 5, BeamSize: 5, Patience: 1,
+	}}
+	suite.Require().NoError(repository.NewProfileRepository(suite.helper.DB).Create(context.Background(), &profile))
+	defer suite.helper.DB.Delete(&profile)
+
+	upload := func(fields map[string]string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("audio", "interview.mp3")
+		suite.Require().NoError(err)
+		part.Write([]byte("not really audio"))
+		for field, value := range fields {
+			writer.WriteField(field, value)
+		}
+		writer.Close()
+		req, _ := http.NewRequest("POST", "/api/v1/transcription/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Overrides are merged over the profile and the job is queued with them
+	w := upload(map[string]string{"profile_id": profile.ID, "parameter_overrides": `{"language": "de", "batch_size": 4}`})
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(suite.T(), models.StatusPending, job.Status)
+	assert.Equal(suite.T(), "small", job.Parameters.Model)
+	assert.Equal(suite.T(), 4, job.Parameters.BatchSize)
+	suite.Require().NotNil(job.Parameters.Language)
+	assert.Equal(suite.T(), "de", *job.Parameters.Language)
+	assert.Equal(suite.T(), profile.ID, *job.ProfileID)
+	assert.Equal(suite.T(), 1, *job.ProfileVersion)
+	suite.Require().NotNil(job.ParameterOverrides)
+	assert.JSONEq(suite.T(), `{"language": "de", "batch_size": 4}`, *job.ParameterOverrides)
+
+	var stored models.TranscriptionProfile
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", profile.ID).Error)
+	assert.Equal(suite.T(), 8, stored.Parameters.BatchSize)
+	assert.Nil(suite.T(), stored.Parameters.Language)
+
+	// Unknown fields, and values outside the adapter's schema, are rejected
+	for _, fields := range []map[string]string{
+		{"profile_id": profile.ID, "parameter_overrides": `{"colour": "blue"}`},
+		{"profile_id": profile.ID, "parameter_overrides": `{"batch_size": 100}`},
+		{"profile_id": profile.ID, "parameter_overrides": `{"compute_type": "int4"}`},
+		{"profile_id": profile.ID, "parameter_overrides": `not json`},
+		{"profile_id": "missing"},
+	} {
+		w = upload(fields)
+		assert.Equal(suite.T(), 400, w.Code, fields)
+	}
+
+	// The AWS endpoint applies overrides after the request's own settings
+	submit := func(overrides map[string]interface{}) *httptest.ResponseRecorder {
+		return suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/aws-transcribe", map[string]interface{}{
+			"TranscriptionJobName": "overridden", "LanguageCode": "de-DE",
+			"Media":              map[string]string{"MediaFileUri": "s3://calls/overridden.mp3"},
+			"ParameterOverrides": overrides,
+		}, true)
+	}
+	w = submit(map[string]interface{}{"language": "fr", "diarize": false})
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var resp struct {
+		TranscriptionJob struct{ TranscriptionJobID string }
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	var awsJob models.TranscriptionJob
+	suite.Require().NoError(suite.helper.DB.First(&awsJob, "id = ?", resp.TranscriptionJob.TranscriptionJobID).Error)
+	assert.Equal(suite.T(), "fr", *awsJob.Parameters.Language)
+	assert.False(suite.T(), awsJob.Parameters.Diarize)
+	assert.NotNil(suite.T(), awsJob.ParameterOverrides)
+	assert.Equal(suite.T(), 400, submit(map[string]interface{}{"beam_size": "wide"}).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestExportLyrics() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Voice memo [draft]")
+	transcript := `{"text": "Hello world. Again now.", "segments": [{"start": 0.5, "end": 2, "text": " Hello world"}, {"start": 62.5, "end": 64, "text": "Again now"}],
+		"word_segments": [{"start": 0.5, "end": 1, "word": "Hello"}, {"start": 1.2, "end": 2, "word": "world"}, {"start": 62.5, "end": 63, "word": "Again"}, {"word": "now"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/export"
+
+	w := suite.makeAuthenticatedRequest("GET", base+"?format=lrc", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), `filename="Voice memo [draft].lrc"`)
+	assert.Equal(suite.T(), "[ti:Voice memo (draft)]\n[length:01:04]\n[re:Scriberr]\n"+
+		"[00:00.50]Hello world\n[01:02.50]Again now\n", w.Body.String())
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=elrc", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "[ti:Voice memo (draft)]\n[length:01:04]\n[re:Scriberr]\n"+
+		"[00:00.50]<00:00.50> Hello <00:01.20> world <00:02.00>\n"+
+		"[01:02.50]<01:02.50> Again now <01:04.00>\n", w.Body.String())
+}
+
+func (suite *APIHandlerTestSuite) TestExportBilingual() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Spanish lesson")
+	transcript := `{"language": "es", "text": "Hola. ¿Qué tal? Bien.", "segments": [{"start": 0, "end": 1.5, "text": " Hola.", "speaker": "SPEAKER_00"}, {"start": 61.25, "end": 63, "text": "¿Qué tal?"}, {"start": 63, "end": 64, "text": "Bien."}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "Ana"}).Error)
+	base := "/api/v1/transcription/" + job.ID
+
+	w := suite.makeAuthenticatedRequest("GET", base+"/export?format=bilingual", nil, true)
+	assert.Equal(suite.T(), 400, w.Code, "the transcript has not been translated")
+	w = suite.makeAuthenticatedRequest("POST", base+"/translation", map[string]interface{}{}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", base+"/translation", map[string]interface{}{"language": "English"}, true)
+	assert.Equal(suite.T(), 400, w.Code, "no LLM is configured")
+
+	// The last segment was left untranslated
+	translation := `{"language": "English", "segments": ["Hello.", "How are you?", ""]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("translation", translation).Error)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/export?format=bilingual", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), `filename="Spanish lesson.bilingual.txt"`)
+	assert.Equal(suite.T(), "Spanish lesson\n(es / English)\n\n"+
+		"[00:00:00.000] Ana: Hola.\n               Hello.\n\n"+
+		"[00:01:01.250] ¿Qué tal?\n               How are you?\n\n"+
+		"[00:01:03.000] Bien.\n\n", w.Body.String())
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/export?format=json", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var exported struct {
+		Translation service.Translation `json:"translation"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &exported))
+	assert.Equal(suite.T(), service.Translation{Language: "English", Segments: []string{"Hello.", "How are you?", ""}}, exported.Translation)
+}
+
+func (suite *APIHandlerTestSuite) TestSegmentLanguages() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Code switching")
+	transcript := `{"language": "en", "text": "See you. Hasta luego.", "segments": [{"start": 0, "end": 1.5, "text": "See you.", "language": "en"}, {"start": 1.5, "end": 4, "text": "Hasta luego.", "language": "es"}, {"start": 4, "end": 5, "text": "Mm."}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/transcript", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var response struct {
+		Languages map[string]float64 `json:"languages"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), map[string]float64{"en": 1.5, "es": 2.5}, response.Languages)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/export?format=vtt", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "WEBVTT\n\nNOTE\nTitle: Code switching\nJob: "+job.ID+"\nLanguage: en\n\n"+
+		"00:00:00.000 --> 00:00:01.500\nSee you.\n\n"+
+		"00:00:01.500 --> 00:00:04.000\n<lang es>Hasta luego.</lang>\n\n"+
+		"00:00:04.000 --> 00:00:05.000\nMm.\n\n", w.Body.String())
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/export?format=json", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"language": "es"`)
+}
+
+func (suite *APIHandlerTestSuite) TestExportFlashcards() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Spanish lesson")
+	transcript := `{"text": "Hola. ¿Qué tal?", "segments": [{"start": 0, "end": 1.5, "text": "Hola."}, {"start": 61.25, "end": 63, "text": "¿Qué tal?"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/flashcards"
+
+	w := suite.makeAuthenticatedRequest("GET", base, nil, true)
+	assert.Equal(suite.T(), 400, w.Code, "a job without notes has no cards")
+
+	suite.Require().NoError(suite.helper.DB.Create(&models.Note{
+		ID: "flashcard-note-2", TranscriptionID: job.ID, StartTime: 61.25, EndTime: 63,
+		Quote: "¿Qué tal?", Content: "How are you?",
+	}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.Note{
+		ID: "flashcard-note-1", TranscriptionID: job.ID, StartTime: 0, EndTime: 1.5,
+		Quote: "Hola.", Content: "Hello, \"hi\"",
+	}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.Note{
+		ID: "flashcard-note-empty", TranscriptionID: job.ID, StartTime: 1, EndTime: 1.5, Quote: "Hola.",
+	}).Error)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=csv", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), `filename="Spanish lesson.csv"`)
+	assert.Equal(suite.T(), "#separator:Comma\n#html:false\n#columns:Front,Back,Tags\n"+
+		"Hola.,\"Hello, \"\"hi\"\"\n(00:00:00)\",Spanish_lesson\n"+
+		"¿Qué tal?,\"How are you?\n(00:01:01)\",Spanish_lesson\n", w.Body.String())
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=apkg", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	suite.Require().NoError(err)
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	suite.Require().Contains(files, "collection.anki2")
+	suite.Require().Contains(files, "media")
+
+	collection, err := files["collection.anki2"].Open()
+	suite.Require().NoError(err)
+	data, err := io.ReadAll(collection)
+	collection.Close()
+	suite.Require().NoError(err)
+	collectionPath := filepath.Join(suite.T().TempDir(), "collection.anki2")
+	suite.Require().NoError(os.WriteFile(collectionPath, data, 0644))
+	anki, err := gorm.Open(sqlite.Open(collectionPath), &gorm.Config{})
+	suite.Require().NoError(err)
+	var fields []string
+	suite.Require().NoError(anki.Raw("SELECT flds FROM notes ORDER BY id").Scan(&fields).Error)
+	assert.Equal(suite.T(), []string{"Hola.\x1fHello, &#34;hi&#34;<br>(00:00:00)", "¿Qué tal?\x1fHow are you?<br>(00:01:01)"}, fields)
+	var cards int64
+	suite.Require().NoError(anki.Raw("SELECT COUNT(*) FROM cards").Scan(&cards).Error)
+	assert.Equal(suite.T(), int64(2), cards)
+	if sqlDB, err := anki.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=pdf", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", base+"?source=qa&count=500", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestQuickTranscriptionCleanup() {
+	tempDir := filepath.Join(suite.helper.Config.UploadDir, "quick_transcriptions")
+	stale := filepath.Join(tempDir, "0d6f1e9c-4a55-4f1b-9a43-5b0c2d0f7e11.mp3")
+	staleOutput := filepath.Join(tempDir, "0d6f1e9c-4a55-4f1b-9a43-5b0c2d0f7e11_output")
+	fresh := filepath.Join(tempDir, "5e2a9b7d-8c1f-4d3e-a6b4-9f0e1d2c3b4a.wav")
+	suite.Require().NoError(os.WriteFile(stale, make([]byte, 100), 0644))
+	suite.Require().NoError(os.MkdirAll(staleOutput, 0755))
+	suite.Require().NoError(os.WriteFile(filepath.Join(staleOutput, "result.json"), make([]byte, 20), 0644))
+	suite.Require().NoError(os.WriteFile(fresh, make([]byte, 50), 0644))
+	old := time.Now().Add(-7 * time.Hour)
+	for _, path := range []string{filepath.Join(staleOutput, "result.json"), staleOutput, stale} {
+		suite.Require().NoError(os.Chtimes(path, old, old))
+	}
+	before := suite.quickTranscription.CleanupStats()
+
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/admin/quick-transcription/cleanup", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var stats transcription.QuickCleanupStats
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(suite.T(), before.Runs+1, stats.Runs)
+	assert.Equal(suite.T(), before.FilesRemoved+2, stats.FilesRemoved)
+	assert.Equal(suite.T(), before.BytesReclaimed+120, stats.BytesReclaimed)
+	assert.Equal(suite.T(), int64(6*3600), stats.RetentionSeconds)
+	assert.NoFileExists(suite.T(), stale)
+	assert.NoDirExists(suite.T(), staleOutput)
+	assert.FileExists(suite.T(), fresh, "files younger than the retention period are kept")
+	suite.Require().NoError(os.Remove(fresh))
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/quick-transcription/cleanup", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(suite.T(), before.Runs+1, stats.Runs)
+
+	// Over the storage cap the oldest files go first
+	cfg := *suite.helper.Config
+	cfg.UploadDir = suite.T().TempDir()
+	cfg.QuickMaxStorageMB = 1
+	capped, err := transcription.NewQuickTranscriptionService(&cfg, suite.unifiedProcessor)
+	suite.Require().NoError(err)
+	defer capped.Close()
+	older := filepath.Join(cfg.UploadDir, "quick_transcriptions", "older.mp3")
+	newer := filepath.Join(cfg.UploadDir, "quick_transcriptions", "newer.mp3")
+	suite.Require().NoError(os.WriteFile(older, make([]byte, 700<<10), 0644))
+	suite.Require().NoError(os.WriteFile(newer, make([]byte, 700<<10), 0644))
+	suite.Require().NoError(os.Chtimes(older, time.Now().Add(-time.Minute), time.Now().Add(-time.Minute)))
+
+	stats = capped.Cleanup()
+	assert.Equal(suite.T(), int64(1), stats.FilesRemoved)
+	assert.Equal(suite.T(), int64(700<<10), stats.StorageBytes)
+	assert.Equal(suite.T(), int64(1<<20), stats.StorageLimitBytes)
+	assert.NoFileExists(suite.T(), older)
+	assert.FileExists(suite.T(), newer)
+}
+
+func (suite *APIHandlerTestSuite) TestFastQuickTranscription() {
+	tempDir := filepath.Join(suite.helper.Config.UploadDir, "quick_transcriptions")
+	before, err := os.ReadDir(tempDir)
+	suite.Require().NoError(err)
+
+	// Clips over a minute are turned away, and their audio not kept
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "memo.wav")
+	suite.Require().NoError(err)
+	part.Write(make([]byte, 3<<20))
+	writer.WriteField("fast", "true")
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "/api/v1/transcription/quick", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Require().Equal(400, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), "too long for fast transcription")
+	after, err := os.ReadDir(tempDir)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), after, len(before))
+}
+
+func (suite *APIHandlerTestSuite) TestSynchronousQuickTranscription() {
+	post := func(size int, fields map[string]string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("audio", "memo.wav")
+		suite.Require().NoError(err)
+		part.Write(make([]byte, size))
+		for k, v := range fields {
+			writer.WriteField(k, v)
+		}
+		writer.Close()
+		req, _ := http.NewRequest("POST", "/api/v1/transcribe/quick", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(3<<20, nil)
+	assert.Equal(suite.T(), 400, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), "too long for fast transcription")
+	assert.Equal(suite.T(), 400, post(1024, map[string]string{"timeout": "soon"}).Code)
+	assert.Equal(suite.T(), 400, post(1024, map[string]string{"parameters": "{"}).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestAnnotations() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Review")
+	transcript := `{"segments": [{"start": 0, "end": 2.5, "text": "Hello."}, {"start": 2.5, "end": 7, "text": "Prices go up."}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.User{Username: "bob.smith", Password: "x"}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/annotations"
+
+	w := suite.makeAuthenticatedRequest("POST", base, map[string]interface{}{"content": "Check this number @bob.smith. cc @nobody", "segment_index": 1}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var thread api.AnnotationResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &thread))
+	assert.Equal(suite.T(), "testuser", thread.Author)
+	assert.Equal(suite.T(), 2.5, thread.StartTime)
+	assert.Equal(suite.T(), 7.0, thread.EndTime)
+	assert.Equal(suite.T(), []string{"bob.smith"}, thread.Mentions)
+
+	w = suite.makeAuthenticatedRequest("POST", base, map[string]interface{}{"content": "Nice intro", "start_time": 0.5}, true)
+	suite.Require().Equal(201, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", base, map[string]interface{}{"content": "Agreed", "parent_id": thread.ID}, true)
+	suite.Require().Equal(201, w.Code)
+	var reply api.AnnotationResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &reply))
+	assert.Equal(suite.T(), thread.ID, *reply.ParentID)
+	assert.Equal(suite.T(), 2.5, reply.StartTime)
+
+	for _, body := range []map[string]interface{}{
+		{"content": "No anchor"},
+		{"content": "Out of range", "segment_index": 2},
+		{"content": "Backwards", "start_time": 3, "end_time": 1},
+		{"content": "Orphan", "parent_id": "missing"},
+		{"content": "  "},
+	} {
+		w = suite.makeAuthenticatedRequest("POST", base, body, true)
+		assert.Equal(suite.T(), 400, w.Code, body["content"])
+	}
+
+	// Threads come in transcript order with their replies
+	w = suite.makeAuthenticatedRequest("GET", base, nil, true)
+	suite.Require().Equal(200, w.Code)
+	var threads []api.AnnotationResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &threads))
+	suite.Require().Len(threads, 2)
+	assert.Equal(suite.T(), "Nice intro", threads[0].Content)
+	assert.Equal(suite.T(), thread.ID, threads[1].ID)
+	suite.Require().Len(threads[1].Replies, 1)
+	assert.Equal(suite.T(), "Agreed", threads[1].Replies[0].Content)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?mention=@bob.smith", nil, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &threads))
+	suite.Require().Len(threads, 1)
+	assert.Equal(suite.T(), thread.ID, threads[0].ID)
+
+	// Resolving
+	w = suite.makeAuthenticatedRequest("PUT", base+"/"+reply.ID, map[string]interface{}{"resolved": true}, true)
+	assert.Equal(suite.T(), 400, w.Code, "replies cannot be resolved")
+	w = suite.makeAuthenticatedRequest("PUT", base+"/"+thread.ID, map[string]interface{}{"resolved": true}, true)
+	suite.Require().Equal(200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &thread))
+	assert.True(suite.T(), thread.Resolved)
+	assert.Equal(suite.T(), "testuser", *thread.ResolvedBy)
+	assert.NotNil(suite.T(), thread.ResolvedAt)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?resolved=false", nil, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &threads))
+	suite.Require().Len(threads, 1)
+	assert.Equal(suite.T(), "Nice intro", threads[0].Content)
+
+	// Only the author edits a comment; the author or an admin deletes it
+	suite.Require().NoError(suite.helper.DB.Model(&models.Annotation{}).Where("id = ?", reply.ID).Update("author", "bob.smith").Error)
+	w = suite.makeAuthenticatedRequest("PUT", base+"/"+reply.ID, map[string]interface{}{"content": "Edited"}, true)
+	assert.Equal(suite.T(), 403, w.Code)
+	editor := models.User{Username: "carol.editor", Password: "x", Role: models.RoleEditor}
+	suite.Require().NoError(suite.helper.DB.Create(&editor).Error)
+	editorToken, err := suite.helper.AuthService.GenerateToken(&editor)
+	suite.Require().NoError(err)
+	saved := suite.helper.TestToken
+	suite.helper.TestToken = editorToken
+	w = suite.makeAuthenticatedRequest("DELETE", base+"/"+reply.ID, nil, true)
+	suite.helper.TestToken = saved
+	assert.Equal(suite.T(), 403, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", base+"/"+reply.ID, nil, true)
+	assert.Equal(suite.T(), 200, w.Code, "admins delete any comment")
+
+	// Deleting a thread deletes its replies
+	w = suite.makeAuthenticatedRequest("DELETE", base+"/"+thread.ID, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var remaining int64
+	suite.helper.DB.Model(&models.Annotation{}).Where("transcription_id = ?", job.ID).Count(&remaining)
+	assert.Equal(suite.T(), int64(1), remaining)
+	w = suite.makeAuthenticatedRequest("DELETE", base+"/"+thread.ID, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestReplayJob() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Replay")
+	base := "/api/v1/transcription/" + job.ID
+
+	w := suite.makeAuthenticatedRequest("POST", base+"/replay", nil, true)
+	assert.Equal(suite.T(), 404, w.Code, "no executions yet")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/missing/replay", nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	// Executions recorded before snapshots cannot be replayed
+	completedAt := time.Now()
+	legacy := &models.TranscriptionJobExecution{
+		TranscriptionJobID: job.ID, StartedAt: completedAt.Add(-time.Minute), CompletedAt: &completedAt, Status: models.StatusCompleted,
+	}
+	suite.Require().NoError(suite.helper.DB.Create(legacy).Error)
+	w = suite.makeAuthenticatedRequest("POST", base+"/replay", nil, true)
+	assert.Equal(suite.T(), 409, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("POST", base+"/replay", map[string]interface{}{"execution_id": "missing"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	// Replays are not reported as the job's execution
+	replayedAt := completedAt.Add(time.Minute)
+	suite.Require().NoError(suite.helper.DB.Create(&models.TranscriptionJobExecution{
+		TranscriptionJobID: job.ID, StartedAt: completedAt, CompletedAt: &replayedAt, Status: models.StatusCompleted, ReplayOf: &legacy.ID,
+	}).Error)
+	w = suite.makeAuthenticatedRequest("GET", base+"/execution", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var execution map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &execution))
+	assert.Equal(suite.T(), legacy.ID, execution["id"])
+}
+
+func (suite *APIHandlerTestSuite) TestRealignTranscript() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Realign")
+	base := "/api/v1/transcription/" + job.ID
+
+	w := suite.makeAuthenticatedRequest("POST", base+"/realign", nil, true)
+	assert.Equal(suite.T(), 400, w.Code, "no transcript yet")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/missing/realign", nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	transcript := `{"language":"en","segments":[{"start":0,"end":2,"text":"hello world"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("transcript", transcript).Error)
+	for _, segments := range []interface{}{
+		[]interface{}{},
+		[]interface{}{map[string]interface{}{"start": 2, "end": 1, "text": "backwards"}},
+		[]interface{}{map[string]interface{}{"start": 0, "end": 1, "text": " "}},
+	} {
+		w = suite.makeAuthenticatedRequest("POST", base+"/realign", map[string]interface{}{"segments": segments}, true)
+		assert.Equal(suite.T(), 400, w.Code, w.Body.String())
+	}
+
+	// Forced alignment needs the WhisperX environment, which tests do not install
+	w = suite.makeAuthenticatedRequest("POST", base+"/realign", map[string]interface{}{
+		"segments": []interface{}{map[string]interface{}{"start": 0, "end": 2, "text": "hello there world"}},
+	}, true)
+	assert.Equal(suite.T(), 409, w.Code, w.Body.String())
+}
+
+func (suite *APIHandlerTestSuite) TestAPIVersioning() {
+	w := suite.makeAuthenticatedRequest("GET", "/api/versions", nil, false)
+	suite.Require().Equal(200, w.Code)
+	var versions []api.APIVersionInfo
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &versions))
+	suite.Require().Len(versions, 2)
+	assert.Equal(suite.T(), "supported", versions[0].Status)
+	assert.Equal(suite.T(), "current", versions[1].Status)
+
+	// v2 serves the v1 routes with structured errors
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Versioned")
+	w = suite.makeAuthenticatedRequest("GET", "/api/v2/transcription/"+job.ID, nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "2", w.Header().Get("API-Version"))
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/missing", nil, false)
+	assert.Equal(suite.T(), "1", w.Header().Get("API-Version"))
+	assert.JSONEq(suite.T(), `{"error": "Job not found"}`, w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", "/api/v2/transcription/missing", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+	assert.JSONEq(suite.T(), `{"error": {"code": "not_found", "message": "Job not found"}}`, w.Body.String())
+	assert.Empty(suite.T(), w.Header().Get("Deprecation"))
+
+	// Once configured, v1 announces its deprecation and then answers 410
+	config, router := suite.helper.Config, suite.router
+	defer func() {
+		config.APIV1DeprecatedAt, config.APIV1SunsetAt = time.Time{}, time.Time{}
+		suite.router = router
+	}()
+	config.APIV1DeprecatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	config.APIV1SunsetAt = time.Now().Add(24 * time.Hour)
+	suite.router = api.SetupRoutes(suite.handler, suite.helper.AuthService)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"?include=notes", nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), fmt.Sprintf("@%d", config.APIV1DeprecatedAt.Unix()), w.Header().Get("Deprecation"))
+	assert.Equal(suite.T(), config.APIV1SunsetAt.UTC().Format(http.TimeFormat), w.Header().Get("Sunset"))
+	assert.Equal(suite.T(), `</api/v2/transcription/`+job.ID+`?include=notes>; rel="successor-version"`, w.Header().Get("Link"))
+	w = suite.makeAuthenticatedRequest("GET", "/api/v2/transcription/"+job.ID, nil, false)
+	assert.Empty(suite.T(), w.Header().Get("Deprecation"))
+
+	config.APIV1SunsetAt = time.Now().Add(-time.Minute)
+	suite.router = api.SetupRoutes(suite.handler, suite.helper.AuthService)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, false)
+	assert.Equal(suite.T(), 410, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v2/transcription/"+job.ID, nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestCosts() {
+	var key models.APIKey
+	suite.Require().NoError(suite.helper.DB.Where("key = ?", suite.helper.TestAPIKey).First(&key).Error)
+	profile := suite.helper.CreateTestProfile(suite.T(), "Costed", false)
+	first := suite.helper.CreateTestTranscriptionJob(suite.T(), "Costed by key")
+	second := suite.helper.CreateTestTranscriptionJob(suite.T(), "Costed by profile")
+	suite.Require().NoError(suite.helper.DB.Model(first).Update("api_key_id", key.ID).Error)
+	suite.Require().NoError(suite.helper.DB.Model(second).Update("profile_id", profile.ID).Error)
+
+	now := time.Now().UTC()
+	execution := func(job *models.TranscriptionJob, adapter string, seconds, cost float64, completed time.Time, replayOf *string) *models.TranscriptionJobExecution {
+		e := &models.TranscriptionJobExecution{
+			TranscriptionJobID: job.ID,
+			StartedAt:          completed.Add(-time.Minute),
+			CompletedAt:        &completed,
+			Status:             models.StatusCompleted,
+			Adapter:            &adapter,
+			AudioSeconds:       &seconds,
+			Cost:               &cost,
+			ReplayOf:           replayOf,
+		}
+		suite.Require().NoError(suite.helper.DB.Create(e).Error)
+		return e
+	}
+	original := execution(first, "openai_whisper", 120, 0.012, now, nil)
+	execution(second, "whisperx", 300, 0.5, now.AddDate(0, 0, -1), nil)
+	execution(first, "openai_whisper", 120, 0.012, now, &original.ID)   // replays are not counted
+	execution(second, "whisperx", 60, 0.1, now.AddDate(0, 0, -60), nil) // out of range
+
+	report := func(query string) api.CostReportResponse {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/costs"+query, nil, false)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response api.CostReportResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	byAdapter := report("?group_by=adapter")
+	assert.Equal(suite.T(), "USD", byAdapter.Currency)
+	assert.InDelta(suite.T(), 0.512, byAdapter.Total, 1e-9)
+	suite.Require().Len(byAdapter.Groups, 2)
+	assert.Equal(suite.T(), "openai_whisper", byAdapter.Groups[0].Key)
+	assert.Equal(suite.T(), int64(1), byAdapter.Groups[0].Jobs)
+	assert.InDelta(suite.T(), 2.0, byAdapter.Groups[0].Minutes, 1e-9)
+	assert.Equal(suite.T(), "whisperx", byAdapter.Groups[1].Key)
+
+	byDay := report("?group_by=day")
+	suite.Require().Len(byDay.Groups, 2)
+	assert.Equal(suite.T(), now.AddDate(0, 0, -1).Format(time.DateOnly), byDay.Groups[0].Key)
+	assert.Equal(suite.T(), now.Format(time.DateOnly), byDay.Groups[1].Key)
+
+	byKey := report("?group_by=api_key")
+	suite.Require().Len(byKey.Groups, 2)
+	assert.Equal(suite.T(), "", byKey.Groups[0].Key)
+	assert.Equal(suite.T(), fmt.Sprint(key.ID), byKey.Groups[1].Key)
+	assert.Equal(suite.T(), key.Name, byKey.Groups[1].Name)
+	assert.InDelta(suite.T(), 0.012, byKey.Groups[1].Cost, 1e-9)
+
+	byProfile := report("?group_by=profile&from=" + now.AddDate(0, 0, -90).Format(time.DateOnly))
+	assert.InDelta(suite.T(), 0.612, byProfile.Total, 1e-9)
+	for _, group := range byProfile.Groups {
+		if group.Key == profile.ID {
+			assert.Equal(suite.T(), "Costed", group.Name)
+			assert.InDelta(suite.T(), 0.6, group.Cost, 1e-9)
+		}
+	}
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/costs?group_by=user", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/costs?from=yesterday", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestUsageAnalytics() {
+	var user models.User
+	suite.Require().NoError(suite.helper.DB.First(&user).Error)
+	english := suite.helper.CreateTestTranscriptionJob(suite.T(), "Analytics English")
+	german := suite.helper.CreateTestTranscriptionJob(suite.T(), "Analytics German")
+	suite.Require().NoError(suite.helper.DB.Model(english).Updates(map[string]interface{}{
+		"user_id": user.ID, "transcript": `{"language": "en", "segments": []}`,
+	}).Error)
+	language := "de"
+	suite.Require().NoError(suite.helper.DB.Model(german).Updates(map[string]interface{}{"language": language, "transcript": "not json"}).Error)
+
+	// Far enough back that other tests' executions do not fall in the same days
+	day := time.Date(2020, 3, 4, 12, 0, 0, 0, time.UTC) // a Wednesday
+	execution := func(job *models.TranscriptionJob, status models.JobStatus, adapter string, seconds float64, latency int64) {
+		e := &models.TranscriptionJobExecution{
+			TranscriptionJobID: job.ID,
+			StartedAt:          day,
+			Status:             status,
+			Adapter:            &adapter,
+			AudioSeconds:       &seconds,
+			ProcessingDuration: &latency,
+		}
+		suite.Require().NoError(suite.helper.DB.Create(e).Error)
+	}
+	execution(english, models.StatusFailed, "openai_whisper", 0, 0)
+	execution(english, models.StatusCompleted, "whisperx", 120, 4000)
+	execution(german, models.StatusCompleted, "whisperx", 60, 2000)
+
+	analytics := func(query string) api.UsageAnalyticsResponse {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/analytics?from=2020-03-01&to=2020-03-31"+query, nil, false)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response api.UsageAnalyticsResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	daily := analytics("")
+	suite.Require().Len(daily.Buckets, 1)
+	bucket := daily.Buckets[0]
+	assert.Equal(suite.T(), "2020-03-04", bucket.Period)
+	assert.Equal(suite.T(), int64(2), bucket.Jobs)
+	assert.Equal(suite.T(), int64(3), bucket.Executions)
+	assert.Equal(suite.T(), int64(1), bucket.Failed)
+	assert.InDelta(suite.T(), 1.0/3, bucket.FailureRate, 1e-9)
+	assert.InDelta(suite.T(), 3.0, bucket.Minutes, 1e-9)
+	assert.InDelta(suite.T(), 3.0, bucket.AverageLatencySeconds, 1e-9)
+	assert.Equal(suite.T(), map[string]int64{"en": 1, "de": 1}, bucket.Languages)
+
+	weekly := analytics("&interval=week&group_by=adapter")
+	suite.Require().Len(weekly.Buckets, 2)
+	assert.Equal(suite.T(), "2020-03-02", weekly.Buckets[0].Period)
+	assert.Equal(suite.T(), "openai_whisper", weekly.Buckets[0].Key)
+	assert.Equal(suite.T(), 1.0, weekly.Buckets[0].FailureRate)
+	assert.Equal(suite.T(), "whisperx", weekly.Buckets[1].Key)
+	assert.Equal(suite.T(), int64(2), weekly.Buckets[1].Jobs)
+
+	byUser := analytics("&interval=month&group_by=user")
+	suite.Require().Len(byUser.Buckets, 2)
+	assert.Equal(suite.T(), "2020-03-01", byUser.Buckets[1].Period)
+	assert.Equal(suite.T(), fmt.Sprint(user.ID), byUser.Buckets[1].Key)
+	assert.Equal(suite.T(), user.Username, byUser.Buckets[1].Name)
+	assert.Equal(suite.T(), map[string]int64{"en": 1}, byUser.Buckets[1].Languages)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/analytics?interval=hour", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/analytics?group_by=day", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	// Jobs submitted by signed-in users are attributed to them
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "attributed.mp3")
+	suite.Require().NoError(err)
+	part.Write([]byte("dummy audio"))
+	writer.Close()
+	req, _ := http.NewRequest("POST", "/api/v1/transcription/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var uploaded models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &uploaded))
+	suite.Require().NotNil(uploaded.UserID)
+	assert.Equal(suite.T(), user.ID, *uploaded.UserID)
+}
+
+func (suite *APIHandlerTestSuite) TestAWSTagRoutes() {
+	defaultProfile := suite.helper.CreateTestProfile(suite.T(), "AWS default", true)
+	researchProfile := *defaultProfile
+	researchProfile.ID, researchProfile.Name, researchProfile.IsDefault = "aws-research-profile", "Research", false
+	suite.Require().NoError(suite.helper.DB.Create(&researchProfile).Error)
+	createRoute := func(body map[string]interface{}) models.AWSTagRoute {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/admin/aws-tag-routes", body, true)
+		suite.Require().Equal(201, w.Code, w.Body.String())
+		var route models.AWSTagRoute
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &route))
+		return route
+	}
+	research := createRoute(map[string]interface{}{
+		"name": "Research", "tag_key": "CostCenter", "tag_value": "research", "priority": 10,
+		"profile_id": researchProfile.ID, "output_bucket_name": "research-transcripts", "monthly_quota_minutes": 60,
+	})
+	anyCostCenter := createRoute(map[string]interface{}{"name": "Billed", "tag_key": "CostCenter", "output_bucket_name": "billed-transcripts"})
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("POST", "/api/v1/admin/aws-tag-routes", map[string]interface{}{
+		"name": "Broken", "tag_key": "Team", "profile_id": "missing",
+	}, true).Code)
+
+	submit := func(tags ...string) (*httptest.ResponseRecorder, *models.TranscriptionJob) {
+		var awsTags []map[string]string
+		for i := 0; i < len(tags); i += 2 {
+			awsTags = append(awsTags, map[string]string{"Key": tags[i], "Value": tags[i+1]})
+		}
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/aws-transcribe", map[string]interface{}{
+			"TranscriptionJobName": "call", "OutputBucketName": "requested-bucket",
+			"Media": map[string]string{"MediaFileUri": "s3://calls/call.mp3"}, "Tags": awsTags,
+		}, true)
+		if w.Code != 200 {
+			return w, nil
+		}
+		var resp struct {
+			TranscriptionJob struct{ TranscriptionJobID string }
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		var job models.TranscriptionJob
+		suite.Require().NoError(suite.helper.DB.First(&job, "id = ?", resp.TranscriptionJob.TranscriptionJobID).Error)
+		return w, &job
+	}
+
+	// The highest-priority matching route applies its profile and bucket
+	w, job := submit("Project", "x", "CostCenter", "research")
+	suite.Require().NotNil(job, w.Body.String())
+	assert.Equal(suite.T(), researchProfile.ID, *job.ProfileID)
+	assert.Equal(suite.T(), "research-transcripts", *job.OutputBucketName)
+	assert.Equal(suite.T(), research.ID, *job.AWSTagRouteID)
+
+	_, job = submit("CostCenter", "sales")
+	suite.Require().NotNil(job)
+	assert.Equal(suite.T(), defaultProfile.ID, *job.ProfileID)
+	assert.Equal(suite.T(), "billed-transcripts", *job.OutputBucketName)
+	assert.Equal(suite.T(), anyCostCenter.ID, *job.AWSTagRouteID)
+
+	_, job = submit("Team", "support")
+	suite.Require().NotNil(job)
+	assert.Equal(suite.T(), "requested-bucket", *job.OutputBucketName)
+	assert.Nil(suite.T(), job.AWSTagRouteID)
+
+	// A route's quota is its own
+	suite.Require().NoError(suite.helper.DB.Create(&models.QuotaUsage{
+		Workspace: research.QuotaKey(), Month: models.QuotaMonth(time.Now()), AudioSeconds: 3600,
+	}).Error)
+	w, _ = submit("CostCenter", "research")
+	assert.Equal(suite.T(), 429, w.Code)
+	_, job = submit("CostCenter", "sales")
+	assert.NotNil(suite.T(), job)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/aws-tag-routes", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var routes []models.AWSTagRoute
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &routes))
+	suite.Require().Len(routes, 2)
+	assert.Equal(suite.T(), research.ID, routes[0].ID)
+	assert.Equal(suite.T(), 60.0, routes[0].UsedMinutes)
+
+	base := fmt.Sprintf("/api/v1/admin/aws-tag-routes/%d", research.ID)
+	assert.Equal(suite.T(), 204, suite.makeAuthenticatedRequest("DELETE", base, nil, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("DELETE", base, nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestAWSTranscribeJobs() {
+	db := suite.helper.DB
+	submit := func(name string) string {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/aws-transcribe", map[string]interface{}{
+			"TranscriptionJobName": name, "LanguageCode": "de-DE",
+			"Media": map[string]string{"MediaFileUri": "s3://calls/" + name + ".mp3"},
+			"Tags":  []map[string]string{{"Key": "Team", "Value": "support"}},
+		}, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var resp struct {
+			TranscriptionJob struct{ TranscriptionJobID string }
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.TranscriptionJob.TranscriptionJobID
+	}
+	queued := submit("aws-queued")
+	completed := submit("aws-completed")
+	failed := submit("aws-failed")
+	suite.Require().NoError(db.Model(&models.TranscriptionJob{}).Where("id = ?", completed).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": `{"text":"Hallo"}`,
+	}).Error)
+	suite.Require().NoError(db.Model(&models.TranscriptionJob{}).Where("id = ?", failed).Updates(map[string]interface{}{
+		"status": models.StatusFailed, "error_message": "Unsupported media format",
+	}).Error)
+
+	type awsJob struct {
+		TranscriptionJob api.AWSTranscriptionJob
+	}
+	get := func(name string) (int, api.AWSTranscriptionJob) {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/aws-transcribe/"+name, nil, true)
+		var resp awsJob
+		if w.Code == 200 {
+			suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.TranscriptionJob
+	}
+
+	// Jobs are found by name or ID
+	code, job := get("aws-completed")
+	suite.Require().Equal(200, code)
+	assert.Equal(suite.T(), completed, job.TranscriptionJobID)
+	assert.Equal(suite.T(), "COMPLETED", job.TranscriptionJobStatus)
+	assert.Equal(suite.T(), "de", *job.LanguageCode)
+	assert.Equal(suite.T(), "s3://calls/aws-completed.mp3", *job.Media.MediaFileUri)
+	suite.Require().NotNil(job.Transcript)
+	assert.True(suite.T(), strings.HasSuffix(*job.Transcript.TranscriptFileUri, "/api/v1/transcription/"+completed+"/transcript"))
+	suite.Require().Len(job.Tags, 1)
+	assert.Equal(suite.T(), "Team", *job.Tags[0].Key)
+	assert.NotNil(suite.T(), job.CompletionTime)
+
+	code, job = get(failed)
+	suite.Require().Equal(200, code)
+	assert.Equal(suite.T(), "FAILED", job.TranscriptionJobStatus)
+	assert.Equal(suite.T(), "Unsupported media format", *job.FailureReason)
+	assert.Nil(suite.T(), job.Transcript)
+
+	code, job = get("aws-queued")
+	suite.Require().Equal(200, code)
+	assert.Equal(suite.T(), "QUEUED", job.TranscriptionJobStatus)
+	assert.Nil(suite.T(), job.CompletionTime)
+	code, _ = get("missing")
+	assert.Equal(suite.T(), 404, code)
+
+	// Listing pages through the jobs, newest first
+	list := func(query string) api.AWSListTranscriptionJobsResponse {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/aws-transcribe?"+query, nil, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var resp api.AWSListTranscriptionJobsResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+	page := list("JobNameContains=aws-&MaxResults=2")
+	suite.Require().Len(page.TranscriptionJobSummaries, 2)
+	suite.Require().NotNil(page.NextToken)
+	assert.Equal(suite.T(), "aws-failed", page.TranscriptionJobSummaries[0].TranscriptionJobName)
+	assert.Equal(suite.T(), "SERVICE_BUCKET", page.TranscriptionJobSummaries[0].OutputLocationType)
+	page = list("JobNameContains=aws-&MaxResults=2&NextToken=" + *page.NextToken)
+	suite.Require().Len(page.TranscriptionJobSummaries, 1)
+	assert.Equal(suite.T(), queued, page.TranscriptionJobSummaries[0].TranscriptionJobID)
+	assert.Nil(suite.T(), page.NextToken)
+
+	page = list("Status=COMPLETED&JobNameContains=aws-")
+	suite.Require().Len(page.TranscriptionJobSummaries, 1)
+	assert.Equal(suite.T(), "aws-completed", page.TranscriptionJobSummaries[0].TranscriptionJobName)
+	assert.Equal(suite.T(), "COMPLETED", *page.Status)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/aws-transcribe?Status=DONE", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/aws-transcribe?MaxResults=500", nil, true).Code)
+
+	// Deleting removes the job
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/aws-transcribe/aws-failed", nil, true).Code)
+	code, _ = get(failed)
+	assert.Equal(suite.T(), 404, code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/aws-transcribe/aws-failed", nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestCorrectionRules() {
+	// Rules are validated when saved
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules", map[string]interface{}{"find": "(", "regex": true}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules", map[string]interface{}{"find": "x*", "regex": true}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules", map[string]interface{}{"name": "product", "find": "scribber", "replace": "Scriberr"}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var rule models.CorrectionRule
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &rule))
+	assert.True(suite.T(), rule.Enabled)
+	defer suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/correction-rules/%d", rule.ID), nil, true)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules", map[string]interface{}{
+		"find": `(\d+) percent`, "replace": "$1%", "regex": true, "case_sensitive": true, "position": 1,
+	}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var percent models.CorrectionRule
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &percent))
+	defer suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/correction-rules/%d", percent.ID), nil, true)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/correction-rules", nil, true)
+	var rules []models.CorrectionRule
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &rules))
+	suite.Require().Len(rules, 2)
+	assert.Equal(suite.T(), rule.ID, rules[0].ID)
+
+	// Previews correct text with the saved rules, or with an unsaved one
+	var preview api.CorrectionPreviewResponse
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/preview", map[string]interface{}{"text": "Scribber is 90 percent done, scribbers."}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), "Scriberr is 90% done, scribbers.", preview.Text)
+	assert.Equal(suite.T(), 2, preview.Replacements)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/preview", map[string]interface{}{
+		"text": "Scribber", "rule": map[string]interface{}{"find": "scribber", "replace": "Scribe", "case_sensitive": true},
+	}, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), "Scribber", preview.Text)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/preview", map[string]interface{}{}, true).Code)
+
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Standup")
+	transcript := `{"text": "We ship scribber today. All good.", "segments": [{"start": 0, "end": 2, "text": "We ship scribber today."}, {"start": 2, "end": 3, "text": "All good."}], "word_segments": [{"start": 0.5, "end": 1, "word": "scribber"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/preview", map[string]interface{}{"job_id": job.ID}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &preview))
+	suite.Require().Len(preview.Segments, 1)
+	assert.Equal(suite.T(), "We ship Scriberr today.", preview.Segments[0].After)
+
+	// Applying them corrects stored transcripts
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/apply", map[string]interface{}{"job_ids": []string{job.ID, "missing"}}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var applied api.ApplyCorrectionRulesResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &applied))
+	assert.Equal(suite.T(), map[string]int{job.ID: 1}, applied.Corrected)
+	assert.Equal(suite.T(), "job not found", applied.Skipped["missing"])
+	var stored models.TranscriptionJob
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Contains(suite.T(), *stored.Transcript, `"text":"We ship Scriberr today. All good."`)
+	assert.Contains(suite.T(), *stored.Transcript, `"word":"Scriberr"`)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/apply", map[string]interface{}{"job_ids": []string{job.ID}}, true)
+	var reapplied api.ApplyCorrectionRulesResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &reapplied))
+	assert.Empty(suite.T(), reapplied.Corrected)
+	assert.Equal(suite.T(), 1, reapplied.Unchanged)
+
+	// Disabled rules are kept but not applied
+	w = suite.makeAuthenticatedRequest("PUT", fmt.Sprintf("/api/v1/correction-rules/%d", rule.ID), map[string]interface{}{"find": "scribber", "replace": "Scriberr", "enabled": false}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/correction-rules/preview", map[string]interface{}{"text": "scribber"}, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), 0, preview.Replacements)
+}
+
+func (suite *APIHandlerTestSuite) TestConfidenceReview() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Noisy call")
+	transcript := `{"text": "Hello there. Mumbled words. Bye now.", "segments": [` +
+		`{"start": 0, "end": 2, "text": " Hello there.", "confidence": 0.9},` +
+		`{"start": 2, "end": 4, "text": " Mumbled words.", "confidence": 0.3, "low_confidence": true},` +
+		`{"start": 4, "end": 6, "text": " Bye now.", "confidence": 0.4, "low_confidence": true}],` +
+		`"word_segments": [{"start": 2.1, "end": 2.9, "word": "Mumbled", "score": 0.2}, {"start": 3, "end": 3.8, "word": "words.", "score": 0.4}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript, "low_confidence_segments": 2, "review_status": models.ReviewNeeded,
+	}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/review"
+
+	// Jobs needing review are queued
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/review-queue", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var queue []models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &queue))
+	suite.Require().Len(queue, 1)
+	assert.Equal(suite.T(), job.ID, queue[0].ID)
+
+	// Their flagged segments come with their words and an audio snippet
+	w = suite.makeAuthenticatedRequest("GET", base, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var review api.ReviewResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &review))
+	suite.Require().Len(review.Segments, 2)
+	assert.Equal(suite.T(), 1, review.Segments[0].Index)
+	assert.Equal(suite.T(), 0.3, review.Segments[0].Confidence)
+	assert.Len(suite.T(), review.Segments[0].Words, 2)
+	assert.Contains(suite.T(), review.Segments[0].AudioURL, "/api/v1/media/"+job.ID+"/audio?expires=")
+	assert.True(suite.T(), strings.HasSuffix(review.Segments[0].AudioURL, "#t=1.50,4.50"), review.Segments[0].AudioURL)
+
+	// Correcting or confirming every flagged segment completes the review
+	w = suite.makeAuthenticatedRequest("PUT", base+"/segments/1", map[string]interface{}{"text": " Muffled words."}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("PUT", base+"/segments/9", nil, true).Code)
+	var stored models.TranscriptionJob
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Contains(suite.T(), *stored.Transcript, `"text":"Hello there. Muffled words. Bye now."`)
+	assert.Equal(suite.T(), 1, stored.LowConfidenceSegments)
+	assert.Equal(suite.T(), models.ReviewNeeded, stored.ReviewStatus)
+
+	w = suite.makeAuthenticatedRequest("PUT", base+"/segments/2", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Equal(suite.T(), 0, stored.LowConfidenceSegments)
+	assert.Equal(suite.T(), models.ReviewComplete, stored.ReviewStatus)
+	assert.NotNil(suite.T(), stored.ReviewedBy)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/review-queue", nil, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &queue))
+	assert.Empty(suite.T(), queue)
+
+	// Jobs can also be marked reviewed outright
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("review_status", models.ReviewNeeded).Error)
+	w = suite.makeAuthenticatedRequest("POST", base, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Equal(suite.T(), models.ReviewComplete, stored.ReviewStatus)
+}
+
+func (suite *APIHandlerTestSuite) TestBenchmarks() {
+	registry.RegisterTranscriptionAdapter("benchmark-test", adapters.NewWhisperXAdapter(suite.T().TempDir()))
+	post := func(modelIDs string, files map[string]string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("model_ids", modelIDs)
+		for name, content := range files {
+			field := "audio"
+			if strings.HasSuffix(name, ".txt") {
+				field = "reference"
+			}
+			part, err := writer.CreateFormFile(field, name)
+			suite.Require().NoError(err)
+			part.Write([]byte(content))
+		}
+		writer.Close()
+		req, _ := http.NewRequest("POST", "/api/v1/admin/benchmarks", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	sample := map[string]string{"call1.wav": "RIFF", "call1.txt": "Hello there."}
+	assert.Equal(suite.T(), 400, post("", sample).Code)
+	assert.Equal(suite.T(), 400, post("benchmark-test,unknown", sample).Code)
+	assert.Equal(suite.T(), 400, post("benchmark-test", map[string]string{"call1.txt": "Hello there."}).Code)
+	w := post("benchmark-test", map[string]string{"call1.wav": "RIFF", "call2.txt": "Hello there."})
+	assert.Equal(suite.T(), 400, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "No reference transcript for call1.wav")
+	// Benchmarks are not run by this server, so none is kept
+	assert.Equal(suite.T(), 503, post("benchmark-test", sample).Code)
+	var count int64
+	suite.helper.DB.Model(&models.Benchmark{}).Count(&count)
+	assert.Zero(suite.T(), count)
+
+	// Finished benchmarks are reported per adapter
+	audio, ratio, slow, fast := 10.0, 0.25, int64(5000), int64(1000)
+	benchmark := models.Benchmark{
+		Name: "Support calls", ModelIDs: "whisperx,openai_whisper", Status: models.StatusCompleted,
+		Samples: []models.BenchmarkSample{{Name: "call1.wav", AudioPath: "call1.wav", Reference: "Hello there.", AudioSeconds: &audio}},
+	}
+	suite.Require().NoError(repository.NewBenchmarkRepository(suite.helper.DB).Create(context.Background(), &benchmark))
+	suite.helper.DB.Model(&benchmark.Runs[0]).Updates(models.BenchmarkRun{Status: models.StatusCompleted, ReferenceWords: 4, WordErrors: 1, WER: &ratio, ReferenceChars: 12, CharErrors: 3, ProcessingDuration: &slow})
+	suite.helper.DB.Model(&benchmark.Runs[1]).Updates(models.BenchmarkRun{Status: models.StatusFailed, ProcessingDuration: &fast})
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/benchmarks/"+benchmark.ID, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var report api.BenchmarkReport
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &report))
+	suite.Require().Len(report.Summary, 2)
+	whisperx := report.Summary[0]
+	assert.Equal(suite.T(), "whisperx", whisperx.ModelID)
+	suite.Require().NotNil(whisperx.WER)
+	assert.Equal(suite.T(), 0.25, *whisperx.WER)
+	assert.Equal(suite.T(), 0.25, *whisperx.CER)
+	assert.Equal(suite.T(), 0.5, *whisperx.RealTimeFactor)
+	assert.Equal(suite.T(), 1, report.Summary[1].Failed)
+	assert.Nil(suite.T(), report.Summary[1].WER)
+	assert.Len(suite.T(), report.Runs, 2)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/benchmarks", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var benchmarks []models.Benchmark
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &benchmarks))
+	suite.Require().Len(benchmarks, 1)
+	assert.Empty(suite.T(), benchmarks[0].Runs)
+
+	assert.Equal(suite.T(), 204, suite.makeAuthenticatedRequest("DELETE", "/api/v1/admin/benchmarks/"+benchmark.ID, nil, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", "/api/v1/admin/benchmarks/"+benchmark.ID, nil, true).Code)
+	suite.helper.DB.Model(&models.BenchmarkRun{}).Count(&count)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *APIHandlerTestSuite) TestTranscriptDiff() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Evaluated call")
+	transcript := `{"text": "The cat sat on the mat.", "model_used": "whisperx", "segments": [{"start": 0, "end": 2, "text": "The cat sat on the mat.", "low_confidence": true}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	base := "/api/v1/transcription/" + job.ID
+
+	// Editing the transcript keeps the original as the first revision
+	w := suite.makeAuthenticatedRequest("PUT", base+"/review/segments/0", map[string]interface{}{"text": "The cat sat on a mat."}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", base+"/revisions", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var revisions []models.TranscriptRevision
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &revisions))
+	suite.Require().Len(revisions, 2)
+	assert.Equal(suite.T(), 1, revisions[0].Revision)
+	assert.Equal(suite.T(), "whisperx", revisions[0].ModelUsed)
+	assert.Empty(suite.T(), revisions[0].Transcript)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/diff?base=revision:1&against=current", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var diff api.TranscriptDiffResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(suite.T(), 1, diff.Stats.Substitutions)
+	assert.InDelta(suite.T(), 1.0/6, diff.Stats.WER, 1e-9)
+	suite.Require().Len(diff.Chunks, 3)
+	assert.Equal(suite.T(), transcription.DiffChunk{Op: transcription.DiffReplace, Reference: "the", Hypothesis: "a"}, diff.Chunks[1])
+
+	// Other jobs on the same audio can be compared too
+	other := suite.helper.CreateTestTranscriptionJob(suite.T(), "Evaluated call, other profile")
+	suite.Require().NoError(suite.helper.DB.Model(other).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": `{"text": "The cat sat on the mat."}`,
+	}).Error)
+	w = suite.makeAuthenticatedRequest("GET", base+"/diff?against=job:"+other.ID+"&base=1", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(suite.T(), 0.0, diff.Stats.WER)
+
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"/diff", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"/diff?against=adapter:x", nil, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", base+"/diff?against=revision:9", nil, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", base+"/diff?against=shadow:missing", nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestAWSVocabularies() {
+	// Vocabularies are created, read, listed and updated by name
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/vocabularies", map[string]interface{}{
+		"VocabularyName": "products", "LanguageCode": "en-US", "Phrases": []string{"Scriberr", " WhisperX ", ""},
+	}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var vocabulary api.AWSVocabulary
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &vocabulary))
+	assert.Equal(suite.T(), "READY", vocabulary.VocabularyState)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/vocabularies", map[string]interface{}{
+		"VocabularyName": "products", "Phrases": []string{"Other"},
+	}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/vocabularies", map[string]interface{}{
+		"VocabularyName": "bad name", "Phrases": []string{"Other"},
+	}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/vocabularies/products", map[string]interface{}{
+		"LanguageCode": "en-US", "Phrases": []string{"Scriberr", "WhisperX", "Parakeet"},
+		"Corrections": map[string]string{"scribe  r": "Scriberr", "para keet": "Parakeet"},
+	}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/vocabularies/products", nil, true)
+	suite.Require().Equal(200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &vocabulary))
+	assert.Equal(suite.T(), []string{"Scriberr", "WhisperX", "Parakeet"}, vocabulary.Phrases)
+	assert.Equal(suite.T(), map[string]string{"scribe r": "Scriberr", "para keet": "Parakeet"}, vocabulary.Corrections)
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/vocabularies/products", map[string]interface{}{
+		"Phrases": []string{"Scriberr"}, "Corrections": map[string]string{"scribe r": " "},
+	}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/vocabularies", nil, true)
+	var vocabularies api.AWSListVocabulariesResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &vocabularies))
+	suite.Require().Len(vocabularies.Vocabularies, 1)
+	assert.Empty(suite.T(), vocabularies.Vocabularies[0].Phrases)
+
+	// Filters take single words
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/vocabulary-filters", map[string]interface{}{
+		"VocabularyFilterName": "profanity", "Words": []string{"darn", "oh heck"},
+	}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/vocabulary-filters", map[string]interface{}{
+		"VocabularyFilterName": "profanity", "Words": []string{"darn", "heck"},
+	}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/vocabulary-filters/profanity", nil, true)
+	var filter api.AWSVocabularyFilter
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &filter))
+	assert.Equal(suite.T(), []string{"darn", "heck"}, filter.Words)
+
+	// Jobs name them in their settings
+	submit := func(settings map[string]interface{}) *httptest.ResponseRecorder {
+		return suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/aws-transcribe", map[string]interface{}{
+			"TranscriptionJobName": "vocabulary-call",
+			"Media":                map[string]string{"MediaFileUri": "s3://calls/vocabulary-call.mp3"},
+			"Settings":             settings,
+		}, true)
+	}
+	w = submit(map[string]interface{}{"VocabularyName": "missing"})
+	assert.Equal(suite.T(), 400, w.Code)
+	w = submit(map[string]interface{}{"VocabularyFilterName": "profanity", "VocabularyFilterMethod": "redact"})
+	assert.Equal(suite.T(), 400, w.Code)
+	w = submit(map[string]interface{}{"VocabularyName": "products", "VocabularyFilterName": "profanity", "VocabularyFilterMethod": "mask"})
+	suite.Require().Equal(200, w.Code, w.Body.String())
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/aws-transcribe/vocabulary-call", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var resp struct {
+		TranscriptionJob api.AWSTranscriptionJob
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	suite.Require().NotNil(resp.TranscriptionJob.Settings)
+	assert.Equal(suite.T(), "products", *resp.TranscriptionJob.Settings.VocabularyName)
+	assert.Equal(suite.T(), "mask", *resp.TranscriptionJob.Settings.VocabularyFilterMethod)
+
+	// Profiles attach them to the jobs transcribed with them
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/profiles/", map[string]interface{}{
+		"name": "Unknown vocabulary", "parameters": map[string]interface{}{"vocabulary_name": "missing"},
+	}, false)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/profiles/", map[string]interface{}{
+		"name": "Product calls", "parameters": map[string]interface{}{"vocabulary_name": "products", "vocabulary_filter_name": "profanity"},
+	}, false)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var profile models.TranscriptionProfile
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(suite.T(), "products", *profile.Parameters.VocabularyName)
+	assert.Equal(suite.T(), "remove", *profile.Parameters.VocabularyFilterMethod)
+	suite.Require().NoError(suite.helper.DB.Delete(&profile).Error)
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/vocabularies/products", nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/vocabulary-filters/profanity", nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/vocabularies/products", nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestReproducibility() {
+	db := suite.helper.DB
+	stamp := func(job *models.TranscriptionJob, startedAt time.Time, torch string, replayOf *string) *models.TranscriptionJobExecution {
+		execution := &models.TranscriptionJobExecution{TranscriptionJobID: job.ID, StartedAt: startedAt, Status: models.StatusCompleted, ReplayOf: replayOf}
+		suite.Require().NoError(db.Create(execution).Error)
+		suite.Require().NoError(db.Create(&[]models.ExecutionComponent{
+			{ExecutionID: execution.ID, TranscriptionJobID: job.ID, Stage: "transcription", Kind: models.ComponentAdapter, Name: "whisperx", Version: "1.0.0"},
+			{ExecutionID: execution.ID, TranscriptionJobID: job.ID, Stage: "transcription", Kind: models.ComponentPackage, Name: "torch", Version: torch},
+		}).Error)
+		return execution
+	}
+	completed := func(title string) *models.TranscriptionJob {
+		job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+		suite.Require().NoError(db.Model(job).Update("status", models.StatusCompleted).Error)
+		return job
+	}
+	now := time.Now()
+	affected, fixed, finalized := completed("Affected"), completed("Fixed"), completed("Finalized")
+	original := stamp(affected, now.Add(-time.Hour), "2.5.0", nil)
+	// A replay on the fixed version does not change what produced the transcript
+	stamp(affected, now, "2.5.1", &original.ID)
+	// A job already reprocessed on the fixed version is no longer affected
+	stamp(fixed, now.Add(-time.Hour), "2.5.0", nil)
+	stamp(fixed, now.Add(-time.Minute), "2.5.1", nil)
+	stamp(finalized, now.Add(-time.Hour), "2.5.0", nil)
+	suite.Require().NoError(db.Model(finalized).Update("finalized_at", now).Error)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/reproducibility/components?kind=package&name=torch", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var versions []models.ComponentVersion
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &versions))
+	assert.Equal(suite.T(), []models.ComponentVersion{
+		{Kind: models.ComponentPackage, Name: "torch", Version: "2.5.0", Jobs: 2},
+		{Kind: models.ComponentPackage, Name: "torch", Version: "2.5.1", Jobs: 1},
+	}, versions)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/reproducibility/jobs?kind=package&name=torch&version=2.5.0", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var jobs []models.ComponentJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &jobs))
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.JobID)
+		assert.Equal(suite.T(), "2.5.0", job.Version)
+	}
+	assert.ElementsMatch(suite.T(), []string{affected.ID, finalized.ID}, ids)
+
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", "/api/v1/admin/reproducibility/jobs?kind=package", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", "/api/v1/admin/reproducibility/components?kind=library", nil, true).Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/reproducibility/reprocess", map[string]interface{}{
+		"kind": "package", "name": "torch", "versions": []string{"2.5.0"},
+	}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var resp api.ReprocessResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(suite.T(), []string{affected.ID}, resp.Queued)
+	assert.Equal(suite.T(), map[string]string{finalized.ID: "job is finalized"}, resp.Skipped)
+
+	var job models.TranscriptionJob
+	suite.Require().NoError(db.First(&job, "id = ?", affected.ID).Error)
+	assert.Equal(suite.T(), models.StatusPending, job.Status)
+	assert.Nil(suite.T(), job.Transcript)
+}
+
+func (suite *APIHandlerTestSuite) TestUserRoles() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Roles")
+	as := func(token, method, path string, body interface{}) *httptest.ResponseRecorder {
+		saved := suite.helper.TestToken
+		suite.helper.TestToken = token
+		defer func() { suite.helper.TestToken = saved }()
+		return suite.makeAuthenticatedRequest(method, path, body, true)
+	}
+	createUser := func(username, role string) (models.User, string) {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/users", map[string]interface{}{"username": username, "password": "password123", "role": role}, true)
+		suite.Require().Equal(201, w.Code, w.Body.String())
+		var user models.User
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &user))
+		assert.Equal(suite.T(), role, user.Role)
+		token, err := suite.helper.AuthService.GenerateToken(&user)
+		suite.Require().NoError(err)
+		return user, token
+	}
+	viewer, viewerToken := createUser("viewer.vic", models.RoleViewer)
+	_, editorToken := createUser("editor.eve", models.RoleEditor)
+
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/users", map[string]interface{}{"username": "someone", "password": "password123", "role": "owner"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/users", map[string]interface{}{"username": "viewer.vic", "password": "password123", "role": models.RoleViewer}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	// Viewers read, editors also write, only admins manage users, keys and the instance
+	annotations := "/api/v1/transcription/" + job.ID + "/annotations"
+	comment := map[string]interface{}{"content": "Looks good", "start_time": 0}
+	assert.Equal(suite.T(), 200, as(viewerToken, "GET", "/api/v1/transcription/"+job.ID, nil).Code)
+	assert.Equal(suite.T(), 403, as(viewerToken, "POST", annotations, comment).Code)
+	assert.Equal(suite.T(), 403, as(viewerToken, "GET", "/api/v1/users", nil).Code)
+	assert.Equal(suite.T(), 201, as(editorToken, "POST", annotations, comment).Code)
+	assert.Equal(suite.T(), 403, as(editorToken, "GET", "/api/v1/api-keys/", nil).Code)
+	assert.Equal(suite.T(), 403, as(editorToken, "GET", "/api/v1/admin/queue/stats", nil).Code)
+	assert.Equal(suite.T(), 403, as(editorToken, "POST", "/api/v1/llm/config", map[string]interface{}{}).Code)
+
+	// Role changes apply to tokens already issued
+	base := fmt.Sprintf("/api/v1/users/%d", viewer.ID)
+	w = suite.makeAuthenticatedRequest("PUT", base, map[string]interface{}{"role": models.RoleEditor}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), 201, as(viewerToken, "POST", annotations, comment).Code)
+
+	// API keys act with their own role
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/api-keys/", map[string]interface{}{"name": "Read only", "role": models.RoleViewer}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var key models.APIKey
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &key))
+	saved := suite.helper.TestAPIKey
+	suite.helper.TestAPIKey = key.Key
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", annotations, nil, false).Code)
+	assert.Equal(suite.T(), 403, suite.makeAuthenticatedRequest("POST", annotations, comment, false).Code)
+	suite.helper.TestAPIKey = saved
+
+	// Deleting an account signs it out; admins cannot delete themselves
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", suite.helper.TestUser.ID), nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", base, nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), 401, as(viewerToken, "GET", "/api/v1/transcription/"+job.ID, nil).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestWorkspaceIsolation() {
+	as := func(token, method, path string) *httptest.ResponseRecorder {
+		saved := suite.helper.TestToken
+		suite.helper.TestToken = token
+		defer func() { suite.helper.TestToken = saved }()
+		return suite.makeAuthenticatedRequest(method, path, nil, true)
+	}
+	createEditor := func(username string) (models.User, string) {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/users", map[string]interface{}{"username": username, "password": "password123", "role": models.RoleEditor}, true)
+		suite.Require().Equal(201, w.Code, w.Body.String())
+		var user models.User
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &user))
+		token, err := suite.helper.AuthService.GenerateToken(&user)
+		suite.Require().NoError(err)
+		return user, token
+	}
+	alice, aliceToken := createEditor("iso.alice")
+	_, bobToken := createEditor("iso.bob")
+
+	// Alice's job and its audio live in her workspace
+	workspace := models.UserWorkspace(alice.ID)
+	dir, err := service.WorkspaceDir(suite.helper.Config.UploadDir, workspace)
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.MkdirAll(dir, 0755))
+	audioPath := filepath.Join(dir, "alice.mp3")
+	suite.Require().NoError(os.WriteFile(audioPath, []byte("alice audio"), 0644))
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Alice's interview")
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{"workspace": workspace, "audio_path": audioPath}).Error)
+	legacy := suite.helper.CreateTestTranscriptionJob(suite.T(), "Shared before workspaces")
+
+	listed := func(token string) []string {
+		w := as(token, "GET", "/api/v1/transcription/list")
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response struct {
+			Jobs []models.TranscriptionJob `json:"jobs"`
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ids := []string{}
+		for _, job := range response.Jobs {
+			ids = append(ids, job.ID)
+		}
+		return ids
+	}
+
+	w := as(aliceToken, "GET", "/api/v1/transcription/"+job.ID+"/audio")
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "alice audio", w.Body.String())
+	assert.Contains(suite.T(), listed(aliceToken), job.ID)
+
+	// Another user can neither list, read, stream nor delete it
+	assert.NotContains(suite.T(), listed(bobToken), job.ID)
+	assert.Equal(suite.T(), 404, as(bobToken, "GET", "/api/v1/transcription/"+job.ID).Code)
+	assert.Equal(suite.T(), 404, as(bobToken, "GET", "/api/v1/transcription/"+job.ID+"/audio").Code)
+	assert.Equal(suite.T(), 404, as(bobToken, "DELETE", "/api/v1/transcription/"+job.ID).Code)
+	_, err = os.Stat(audioPath)
+	assert.NoError(suite.T(), err)
+
+	// Nor reach it through its notes, chat sessions, voiceprints or feeds
+	note := models.Note{ID: "iso-note", TranscriptionID: job.ID, Quote: "q", Content: "alice's note"}
+	suite.Require().NoError(suite.helper.DB.Omit("Transcription").Create(&note).Error)
+	session := models.ChatSession{ID: "iso-session", JobID: job.ID, TranscriptionID: job.ID, Title: "Alice's chat", Model: "gpt-test", IsActive: true}
+	suite.Require().NoError(suite.helper.DB.Omit("Transcription").Create(&session).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.FeedSubscription{ID: "iso-feed", URL: "https://example.com/feed.xml", Workspace: workspace, Active: true}).Error)
+	assert.Equal(suite.T(), 200, as(aliceToken, "GET", "/api/v1/notes/"+note.ID).Code)
+	assert.Equal(suite.T(), 200, as(aliceToken, "GET", "/api/v1/chat/sessions/"+session.ID).Code)
+	for _, path := range []string{"/api/v1/notes/" + note.ID, "/api/v1/chat/sessions/" + session.ID, "/api/v1/feeds/iso-feed"} {
+		assert.Equal(suite.T(), 404, as(bobToken, "GET", path).Code, path)
+		assert.Equal(suite.T(), 404, as(bobToken, "DELETE", path).Code, path)
+	}
+	saved := suite.helper.TestToken
+	suite.helper.TestToken = bobToken
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/notes/"+note.ID, map[string]interface{}{"content": "bob was here"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/voiceprints", map[string]interface{}{"name": "Alice", "job_id": job.ID, "speaker": "SPEAKER_00"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/feeds/", nil, true)
+	suite.helper.TestToken = saved
+	suite.Require().Equal(200, w.Code)
+	assert.NotContains(suite.T(), w.Body.String(), "iso-feed")
+	var stored models.Note
+	suite.Require().NoError(suite.helper.DB.First(&stored, "id = ?", note.ID).Error)
+	assert.Equal(suite.T(), "alice's note", stored.Content)
+	var sessions int64
+	suite.helper.DB.Model(&models.ChatSession{}).Where("id = ?", session.ID).Count(&sessions)
+	assert.Equal(suite.T(), int64(1), sessions)
+
+	// Jobs from before workspaces stay shared, and admins see every workspace
+	assert.Contains(suite.T(), listed(bobToken), legacy.ID)
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestAudioStreaming() {
+	audioPath := filepath.Join(suite.helper.Config.UploadDir, "podcast.mp3")
+	suite.Require().NoError(os.WriteFile(audioPath, []byte("0123456789"), 0644))
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Podcast")
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("audio_path", audioPath).Error)
+	base := "/api/v1/transcription/" + job.ID + "/audio"
+
+	// Players seek with Range requests
+	req, _ := http.NewRequest("GET", base, nil)
+	req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+	req.Header.Set("Range", "bytes=2-5")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(206, w.Code)
+	assert.Equal(suite.T(), "2345", w.Body.String())
+	assert.Equal(suite.T(), "bytes 2-5/10", w.Header().Get("Content-Range"))
+	assert.Equal(suite.T(), "audio/mpeg", w.Header().Get("Content-Type"))
+
+	// Audio already in the requested format is served as is, with ranges
+	w = suite.makeAuthenticatedRequest("GET", base+"?format=mp3", nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), "0123456789", w.Body.String())
+	assert.Equal(suite.T(), "bytes", w.Header().Get("Accept-Ranges"))
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"?format=flac", nil, true).Code)
+
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("audio_uri", "s3://bucket/podcast.mp3").Error)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"?redirect=maybe", nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestVideoDownload() {
+	// Only video containers are accepted
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("video", "slides.pdf")
+	suite.Require().NoError(err)
+	part.Write([]byte("not a video"))
+	writer.Close()
+	req, _ := http.NewRequest("POST", "/api/v1/transcription/upload-video", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	videoPath := filepath.Join(suite.helper.Config.UploadDir, "standup.mp4")
+	suite.Require().NoError(os.WriteFile(videoPath, []byte("video frames"), 0644))
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Standup")
+	base := "/api/v1/transcription/" + job.ID + "/video"
+
+	w = suite.makeAuthenticatedRequest("GET", base, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("video_path", videoPath).Error)
+	w = suite.makeAuthenticatedRequest("GET", base, nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), "video frames", w.Body.String())
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), "Standup.mp4")
+
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"?subtitles=hardsub", nil, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", base+"?subtitles=sidecar", nil, true).Code)
+
+	// Sidecar subtitles are named after the video
+	transcript := `{"segments": [{"start": 0, "end": 2, "text": "Morning all.", "speaker": "SPEAKER_00"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	w = suite.makeAuthenticatedRequest("GET", base+"?subtitles=sidecar", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	suite.Require().NoError(err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		suite.Require().NoError(err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	assert.Equal(suite.T(), "video frames", files["Standup.mp4"])
+	assert.Contains(suite.T(), files["Standup.srt"], "00:00:00,000 --> 00:00:02,000")
+	assert.Contains(suite.T(), files["Standup.vtt"], "WEBVTT")
+}
+
+func (suite *APIHandlerTestSuite) TestAudioPlaybackURL() {
+	audioPath := filepath.Join(suite.helper.Config.UploadDir, "playback.mp3")
+	suite.Require().NoError(os.WriteFile(audioPath, []byte("playback audio"), 0644))
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Playback")
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("audio_path", audioPath).Error)
+
+	playbackURL := func() string {
+		req, err := http.NewRequest("GET", "/api/v1/transcription/"+job.ID+"/audio-url", nil)
+		suite.Require().NoError(err)
+		req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+		// A client-supplied forwarded host must not end up in the link
+		req.Header.Set("X-Forwarded-Host", "attacker.example.com")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response api.PlaybackURLResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response.URL
+	}
+
+	link := playbackURL()
+	assert.True(suite.T(), strings.HasPrefix(link, "/api/v1/media/"+job.ID+"/audio?"), link)
+	req, err := http.NewRequest("GET", link, nil)
+	suite.Require().NoError(err)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "playback audio", w.Body.String())
+
+	suite.helper.Config.PublicBaseURL = "https://scriberr.example.com"
+	defer func() { suite.helper.Config.PublicBaseURL = "" }()
+	assert.True(suite.T(), strings.HasPrefix(playbackURL(), "https://scriberr.example.com/api/v1/media/"))
+
+	// Requested lifetimes are capped to keep links short-lived
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/audio-url?ttl=604800", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var capped api.PlaybackURLResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &capped))
+	assert.WithinDuration(suite.T(), time.Now().Add(time.Hour), capped.ExpiresAt, time.Minute)
+}
+
+func (suite *APIHandlerTestSuite) TestOrganizations() {
+	outside := suite.helper.CreateTestTranscriptionJob(suite.T(), "Instance job")
+	send := func(token, organizationID, method, path string, body interface{}) *httptest.ResponseRecorder {
+		reader := bytes.NewBuffer(nil)
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewBuffer(data)
+		}
+		req, err := http.NewRequest(method, path, reader)
+		suite.Require().NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if organizationID != "" {
+			req.Header.Set(middleware.OrganizationHeader, organizationID)
+		}
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	listedJobs := func(w *httptest.ResponseRecorder) []string {
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response struct {
+			Jobs []models.TranscriptionJob `json:"jobs"`
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ids := []string{}
+		for _, job := range response.Jobs {
+			ids = append(ids, job.ID)
+		}
+		return ids
+	}
+	createOrganization := func(name string) models.Organization {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/organizations", map[string]interface{}{"name": name}, true)
+		suite.Require().Equal(201, w.Code, w.Body.String())
+		var organization models.Organization
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &organization))
+		return organization
+	}
+	acme := createOrganization("Acme")
+	globex := createOrganization("Globex")
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/organizations", map[string]interface{}{"name": "acme"}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/users", map[string]interface{}{"username": "acme.ann", "password": "password123", "role": models.RoleEditor}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var member models.User
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &member))
+	memberToken, err := suite.helper.AuthService.GenerateToken(&member)
+	suite.Require().NoError(err)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/organizations/"+acme.ID+"/members", map[string]interface{}{"user_id": member.ID}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+
+	w = send(memberToken, "", "GET", "/api/v1/user/organizations", nil)
+	suite.Require().Equal(200, w.Code)
+	var mine []models.Organization
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &mine))
+	suite.Require().Len(mine, 1)
+	assert.Equal(suite.T(), acme.ID, mine[0].ID)
+	assert.Equal(suite.T(), 403, send(memberToken, globex.ID, "GET", "/api/v1/transcription/list", nil).Code)
+	assert.Equal(suite.T(), 200, send(memberToken, acme.ID, "GET", "/api/v1/transcription/list", nil).Code)
+
+	// Members act in their organization and only see its jobs
+	inside := suite.helper.CreateTestTranscriptionJob(suite.T(), "Acme job")
+	suite.Require().NoError(suite.helper.DB.Model(inside).Update("workspace", models.OrganizationWorkspace(acme.ID)).Error)
+	jobs := listedJobs(send(memberToken, "", "GET", "/api/v1/transcription/list", nil))
+	assert.Contains(suite.T(), jobs, inside.ID)
+	assert.NotContains(suite.T(), jobs, outside.ID)
+	assert.Equal(suite.T(), 404, send(memberToken, "", "GET", "/api/v1/transcription/"+outside.ID, nil).Code)
+	assert.Equal(suite.T(), 200, send(memberToken, "", "GET", "/api/v1/transcription/"+inside.ID, nil).Code)
+
+	// Admins outside every organization do not see it, unless they act in it
+	assert.NotContains(suite.T(), listedJobs(suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list", nil, true)), inside.ID)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+inside.ID, nil, true).Code)
+	assert.Equal(suite.T(), 200, send(suite.helper.TestToken, acme.ID, "GET", "/api/v1/transcription/"+inside.ID, nil).Code)
+
+	// Profiles belong to the organization they are created in
+	w = send(memberToken, "", "POST", "/api/v1/profiles/", map[string]interface{}{"name": "Acme profile", "parameters": map[string]interface{}{"model": "base"}})
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var profile models.TranscriptionProfile
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(suite.T(), acme.ID, profile.OrganizationID)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", "/api/v1/profiles/"+profile.ID, nil, true).Code)
+	assert.Equal(suite.T(), 200, send(memberToken, "", "GET", "/api/v1/profiles/"+profile.ID, nil).Code)
+
+	// Organization API keys are confined to it
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/api-keys/", map[string]interface{}{"name": "Acme key", "organization_id": acme.ID}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var key models.APIKey
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &key))
+	assert.Equal(suite.T(), acme.ID, key.OrganizationID)
+	saved := suite.helper.TestAPIKey
+	suite.helper.TestAPIKey = key.Key
+	jobs = listedJobs(suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list", nil, false))
+	assert.Contains(suite.T(), jobs, inside.ID)
+	assert.NotContains(suite.T(), jobs, outside.ID)
+	suite.helper.TestAPIKey = saved
+
+	// Organizations with jobs are kept; empty ones can be deleted
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("DELETE", "/api/v1/organizations/"+acme.ID, nil, true).Code)
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("DELETE", "/api/v1/organizations/"+globex.ID, nil, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("DELETE", "/api/v1/organizations/"+globex.ID, nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestFinalizeTranscript() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Deposition")
+	finalize := "/api/v1/transcription/" + job.ID + "/finalize"
+	title := "/api/v1/transcription/" + job.ID + "/title"
+
+	// Only completed transcripts become records
+	w := suite.makeAuthenticatedRequest("POST", finalize, nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	transcript := `{"segments": [{"start": 0, "end": 1, "text": "I do"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": transcript}).Error)
+
+	w = suite.makeAuthenticatedRequest("POST", finalize, map[string]interface{}{"reason": "Entered into evidence"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var finalized models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &finalized))
+	suite.Require().NotNil(finalized.FinalizedAt)
+	assert.Equal(suite.T(), suite.helper.TestUser.Username, *finalized.FinalizedBy)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("POST", finalize, nil, true).Code)
+
+	// Editors can no longer change or reprocess it; admins can, on the record
+	editor := models.User{Username: "editor.fin", Password: "x", Role: models.RoleEditor}
+	suite.Require().NoError(suite.helper.DB.Create(&editor).Error)
+	editorToken, err := suite.helper.AuthService.GenerateToken(&editor)
+	suite.Require().NoError(err)
+	saved := suite.helper.TestToken
+	suite.helper.TestToken = editorToken
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("PUT", title, map[string]interface{}{"title": "Changed"}, true).Code)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/"+job.ID, nil, true).Code)
+	assert.Equal(suite.T(), 403, suite.makeAuthenticatedRequest("DELETE", finalize, nil, true).Code)
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true).Code)
+
+	// Nor through the routes around it: summaries, notes, annotations and replays
+	note := models.Note{ID: "fin-note", TranscriptionID: job.ID, Quote: "I do", Content: "Sworn"}
+	suite.Require().NoError(suite.helper.DB.Omit("Transcription").Create(&note).Error)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("POST", "/api/v1/summarize/", map[string]interface{}{"model": "gpt-test", "content": "Summarize", "transcription_id": job.ID}, true).Code)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/notes", map[string]interface{}{"quote": "I do", "content": "Added"}, true).Code)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("PUT", "/api/v1/notes/"+note.ID, map[string]interface{}{"content": "Changed"}, true).Code)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("DELETE", "/api/v1/notes/"+note.ID, nil, true).Code)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/annotations", map[string]interface{}{"content": "Objection", "start_time": 0.5}, true).Code)
+	assert.Equal(suite.T(), 409, suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/replay", nil, true).Code)
+	suite.helper.TestToken = saved
+
+	// Automation writing to the job directly is refused as well
+	ctx := context.Background()
+	jobRepo := repository.NewJobRepository(suite.helper.DB)
+	stored, err := jobRepo.FindByID(ctx, job.ID)
+	suite.Require().NoError(err)
+	summary := "Automated summary"
+	stored.Summary = &summary
+	assert.ErrorIs(suite.T(), jobRepo.UpdatePostProcessing(ctx, stored), repository.ErrJobFinalized)
+	assert.ErrorIs(suite.T(), jobRepo.UpdateTranscript(ctx, job.ID, transcript), repository.ErrJobFinalized)
+	assert.ErrorIs(suite.T(), repository.NewSummaryRepository(suite.helper.DB).SaveSummary(ctx, &models.Summary{TranscriptionID: job.ID, Model: "gpt-test", Content: summary}), repository.ErrJobFinalized)
+	assert.ErrorIs(suite.T(), jobRepo.CreateExecution(ctx, &models.TranscriptionJobExecution{TranscriptionJobID: job.ID, StartedAt: time.Now(), Status: models.StatusProcessing}), repository.ErrJobFinalized)
+
+	// Admins may change the notes on it
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("PUT", "/api/v1/notes/"+note.ID, map[string]interface{}{"content": "Sworn (corrected)"}, true).Code)
+
+	w = suite.makeAuthenticatedRequest("PUT", title, map[string]interface{}{"title": "Deposition (corrected)"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("DELETE", finalize, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+
+	var events []models.AuditEvent
+	suite.Require().NoError(suite.helper.DB.Where("resource_id = ?", job.ID).Order("id").Find(&events).Error)
+	suite.Require().Len(events, 3)
+	assert.Equal(suite.T(), models.AuditTranscriptFinalized, events[0].Action)
+	assert.Contains(suite.T(), *events[0].Details, "Entered into evidence")
+	assert.Equal(suite.T(), models.AuditFinalizedJobModified, events[1].Action)
+	assert.Equal(suite.T(), models.AuditTranscriptUnfinalized, events[2].Action)
+	assert.Equal(suite.T(), suite.helper.TestUser.Username, events[2].Actor)
+}
+
+func (suite *APIHandlerTestSuite) TestCustodyManifest() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Interview")
+	audioPath := filepath.Join(suite.T().TempDir(), "interview.wav")
+	suite.Require().NoError(os.WriteFile(audioPath, []byte("RIFF audio"), 0644))
+	inputHash := "1b6a3e1d1a6fd1d2fd6e4b5bd7d65b1e0b3d5a4c4e32c8a9e1f3c47a0b7e6a11"
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{"audio_path": audioPath, "transcript": `{"text": "hello"}`}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.TranscriptionJobExecution{
+		TranscriptionJobID: job.ID, StartedAt: time.Now(), Status: models.StatusCompleted, InputHash: &inputHash,
+	}).Error)
+	custody := "/api/v1/transcription/" + job.ID + "/custody"
+
+	w := suite.makeAuthenticatedRequest("GET", custody, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var manifest export.CustodyManifest
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &manifest))
+	assert.Equal(suite.T(), "Ed25519", manifest.Signature.Algorithm)
+
+	// The published public key verifies the manifest without the instance;
+	// neither the JWT secret nor another key does
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/custody/public-key", nil))
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var published api.CustodyPublicKey
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &published))
+	raw, err := base64.StdEncoding.DecodeString(published.PublicKey)
+	suite.Require().NoError(err)
+	publicKey := ed25519.PublicKey(raw)
+	assert.Equal(suite.T(), manifest.Signature.KeyID, published.KeyID)
+	assert.Contains(suite.T(), published.PEM, "BEGIN PUBLIC KEY")
+	assert.True(suite.T(), manifest.Verify(publicKey))
+	otherKey, _, err := ed25519.GenerateKey(nil)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), manifest.Verify(otherKey))
+	assert.False(suite.T(), manifest.Verify(ed25519.PublicKey(suite.helper.Config.JWTSecret)))
+	assert.Len(suite.T(), manifest.Audio.SHA256, 64)
+	assert.Equal(suite.T(), int64(10), manifest.Audio.SizeBytes)
+	suite.Require().NotNil(manifest.Audio.MatchesProcessedAudio)
+	assert.False(suite.T(), *manifest.Audio.MatchesProcessedAudio)
+	suite.Require().Len(manifest.Executions, 1)
+	assert.Equal(suite.T(), inputHash, manifest.Executions[0].InputSHA256)
+	assert.NotEmpty(suite.T(), manifest.Transcript.SHA256)
+
+	// The verify endpoint accepts the manifest as issued and rejects any change
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/custody/verify", manifest, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), `"valid":true`)
+	manifest.Job.Title = "Altered"
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/custody/verify", manifest, true)
+	assert.Contains(suite.T(), w.Body.String(), `"valid":false`)
+
+	w = suite.makeAuthenticatedRequest("GET", custody+"?format=pdf", nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(suite.T(), strings.HasPrefix(w.Body.String(), "%PDF-"))
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", custody+"?format=docx", nil, true).Code)
+
+	// Each export is itself on the record
+	var exports int64
+	suite.helper.DB.Model(&models.AuditEvent{}).Where("resource_id = ? AND action = ?", job.ID, models.AuditCustodyExported).Count(&exports)
+	assert.Equal(suite.T(), int64(2), exports)
+}
+
+func (suite *APIHandlerTestSuite) TestRateLimitsAndQuotas() {
+	cfg := suite.helper.Config
+	defer func() { cfg.RateLimitPerIP, cfg.MonthlyQuotaMinutes = 0, 0 }()
+
+	// Clients beyond their per-minute rate are told when to come back
+	cfg.RateLimitPerIP = 2
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/quota", nil, true).Code)
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/quota", nil, true).Code)
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/quota", nil, true)
+	assert.Equal(suite.T(), 429, w.Code)
+	assert.Equal(suite.T(), "30", w.Header().Get("Retry-After"))
+
+	// No proxies are trusted by default, so a client cannot pick a fresh
+	// X-Forwarded-For to get a new allowance
+	fromClient := func(forwardedFor string) int {
+		req, err := http.NewRequest("GET", "/api/v1/quota", nil)
+		suite.Require().NoError(err)
+		req.RemoteAddr = "203.0.113.7:51000"
+		req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(suite.T(), 200, fromClient("198.51.100.1"))
+	assert.Equal(suite.T(), 200, fromClient("198.51.100.2"))
+	assert.Equal(suite.T(), 429, fromClient("198.51.100.3"))
+	cfg.RateLimitPerIP = 0
+
+	// Submissions stop once the workspace has transcribed its monthly minutes
+	cfg.MonthlyQuotaMinutes = 1
+	workspace := fmt.Sprintf("user-%d", suite.helper.TestUser.ID)
+	jobRepo := repository.NewJobRepository(suite.helper.DB)
+	month := models.QuotaMonth(time.Now())
+	suite.Require().NoError(jobRepo.AddQuotaUsage(context.Background(), workspace, month, 45))
+	suite.Require().NoError(jobRepo.AddQuotaUsage(context.Background(), workspace, month, 45))
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/quota", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var quota api.QuotaResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &quota))
+	assert.Equal(suite.T(), 1, quota.LimitMinutes)
+	assert.Equal(suite.T(), 1.5, quota.UsedMinutes)
+	assert.Equal(suite.T(), models.QuotaResetsAt(time.Now()), quota.ResetsAt)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/url", map[string]interface{}{"url": "https://example.com/a.mp3"}, true)
+	assert.Equal(suite.T(), 429, w.Code)
+	assert.NotEmpty(suite.T(), w.Header().Get("Retry-After"))
+	// Reading is unaffected
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list", nil, true).Code)
+
+	// Recordings finalized with a profile are refused before the audio is moved,
+	// so they can be finalized once the quota resets
+	profile := suite.helper.CreateTestProfile(suite.T(), "Quota profile", false)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/recordings", map[string]string{"mime_type": "audio/webm"}, true)
+	suite.Require().Equal(201, w.Code)
+	var session models.RecordingSession
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("PUT", "/api/v1/recordings/"+session.ID+"/chunks/0", []byte("chunk"), true).Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/recordings/"+session.ID+"/finalize", map[string]string{"profile_id": profile.ID}, true)
+	assert.Equal(suite.T(), 429, w.Code)
+	assert.NotEmpty(suite.T(), w.Header().Get("Retry-After"))
+	suite.Require().NoError(suite.helper.DB.First(&session, "id = ?", session.ID).Error)
+	_, err := os.Stat(session.FilePath)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), session.JobID)
+
+	// Without a profile the recording becomes a job that is not queued
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/recordings/"+session.ID+"/finalize", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(suite.T(), models.StatusUploaded, job.Status)
+
+	// URL and feed ingestion share the check, so feed episodes wait for the reset
+	ingest := service.NewURLIngestService(cfg, jobRepo, suite.taskQueue)
+	err = ingest.Submit(context.Background(), &models.TranscriptionJob{ID: "quota-feed-episode", Workspace: workspace}, "https://93.184.216.34/episode.mp3")
+	assert.ErrorIs(suite.T(), err, service.ErrQuotaExceeded)
+	_, err = jobRepo.FindByID(context.Background(), "quota-feed-episode")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *APIHandlerTestSuite) TestScopedAPIKeys() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Integration")
+	createKey := func(body map[string]interface{}) models.APIKey {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/api-keys/", body, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var key models.APIKey
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &key))
+		return key
+	}
+	saved := suite.helper.TestAPIKey
+	defer func() { suite.helper.TestAPIKey = saved }()
+
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("POST", "/api/v1/api-keys/", map[string]interface{}{"name": "bad", "scopes": []string{"delete"}}, true).Code)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("POST", "/api/v1/api-keys/", map[string]interface{}{"name": "old", "expires_at": time.Now().Add(-time.Hour)}, true).Code)
+
+	// A submit key follows its jobs but cannot read or delete anything else
+	submit := createKey(map[string]interface{}{"name": "ingest", "scopes": []string{"submit"}, "rate_limit": 3})
+	suite.helper.TestAPIKey = submit.Key
+	assert.Equal(suite.T(), 403, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list", nil, false).Code)
+	assert.Equal(suite.T(), 403, suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/"+job.ID, nil, false).Code)
+	for i := 0; i < 3; i++ {
+		assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/status", nil, false).Code)
+	}
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/status", nil, false)
+	assert.Equal(suite.T(), 429, w.Code)
+	assert.NotEmpty(suite.T(), w.Header().Get("Retry-After"))
+
+	var used models.APIKey
+	suite.Require().NoError(suite.helper.DB.First(&used, submit.ID).Error)
+	assert.NotNil(suite.T(), used.LastUsed)
+
+	// A read key reads but cannot delete
+	suite.helper.TestAPIKey = createKey(map[string]interface{}{"name": "dashboard", "scopes": []string{"read"}}).Key
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list", nil, false).Code)
+	assert.Equal(suite.T(), 403, suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/"+job.ID, nil, false).Code)
+
+	// Expired keys are rejected
+	expires := time.Now().Add(time.Hour)
+	expiring := createKey(map[string]interface{}{"name": "temporary", "expires_at": expires})
+	suite.Require().NoError(suite.helper.DB.Model(&models.APIKey{}).Where("id = ?", expiring.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	suite.helper.TestAPIKey = expiring.Key
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list", nil, false)
+	assert.Equal(suite.T(), 401, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "expired")
+}
+
+func (suite *APIHandlerTestSuite) TestShadowRuns() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Shadowed")
+	agreement, duration, transcript := 0.9, int64(1200), `{"text": "hello"}`
+	completed := &models.ShadowRun{
+		TranscriptionJobID: job.ID, ModelID: "parakeet", PrimaryModelID: "whisperx", Status: models.StatusCompleted,
+		WordAgreement: &agreement, ProcessingDuration: &duration, Transcript: &transcript,
+	}
+	suite.Require().NoError(suite.helper.DB.Create(completed).Error)
+	failure := "out of memory"
+	suite.Require().NoError(suite.helper.DB.Create(&models.ShadowRun{
+		TranscriptionJobID: job.ID, ModelID: "parakeet", PrimaryModelID: "whisperx", Status: models.StatusFailed, ErrorMessage: &failure,
+	}).Error)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/shadow-runs?model_id=parakeet", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var listed api.ShadowRunsResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &listed))
+	suite.Require().Len(listed.Runs, 2)
+	for _, run := range listed.Runs {
+		assert.Nil(suite.T(), run.Transcript, "transcripts are left out of the list")
+	}
+	suite.Require().Len(listed.Summary, 1)
+	assert.Equal(suite.T(), 1, listed.Summary[0].Completed)
+	assert.Equal(suite.T(), 1, listed.Summary[0].Failed)
+	assert.Equal(suite.T(), 0.9, *listed.Summary[0].MeanWordAgreement)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/shadow-runs/"+completed.ID, nil, true)
+	suite.Require().Equal(200, w.Code)
+	var run models.ShadowRun
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &run))
+	assert.Equal(suite.T(), transcript, *run.Transcript)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/shadow-runs?limit=0", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	// Shadow results never reach the job's transcript
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.NotContains(suite.T(), w.Body.String(), "parakeet")
+}
+
+func (suite *APIHandlerTestSuite) TestDiagnostics() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Private meeting")
+	failedAt := time.Now()
+	message := "transcription failed: open /data/uploads/private-meeting.wav: no such file (see https://user:pw@example.com/x?token=abc)"
+	suite.Require().NoError(suite.helper.DB.Create(&models.TranscriptionJobExecution{
+		TranscriptionJobID: job.ID, StartedAt: failedAt, CompletedAt: &failedAt, Status: models.StatusFailed, ErrorMessage: &message,
+	}).Error)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/diagnostics?download=true", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), "scriberr-diagnostics-")
+	body := w.Body.String()
+	signingKey := suite.helper.Config.CustodySigningKey
+	for _, private := range []string{"private-meeting", "Private meeting", "user:pw", "token=abc", suite.helper.Config.JWTSecret,
+		base64.StdEncoding.EncodeToString(signingKey), base64.StdEncoding.EncodeToString(signingKey.Seed()), hex.EncodeToString(signingKey.Seed())} {
+		assert.NotContains(suite.T(), body, private)
+	}
+
+	var bundle api.DiagnosticsBundle
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.Equal(suite.T(), "[set]", bundle.Config["JWTSecret"])
+	assert.NotContains(suite.T(), bundle.Config, "CustodySigningKey")
+	assert.NotContains(suite.T(), bundle.Queue, "completed_jobs")
+	suite.Require().NotEmpty(bundle.RecentErrors)
+	assert.Equal(suite.T(), "transcription failed: open [path]: no such file (see [url]", bundle.RecentErrors[0].Message)
+	assert.NotEmpty(suite.T(), bundle.Versions.Go)
+}
+
+func (suite *APIHandlerTestSuite) TestVoiceprints() {
+	ctx := context.Background()
+	repo := repository.NewSpeakerMappingRepository(suite.helper.DB)
+	workspace := fmt.Sprintf("user-%d", suite.helper.TestUser.ID)
+
+	enrolled := suite.helper.CreateTestTranscriptionJob(suite.T(), "Standup")
+	suite.Require().NoError(repo.ReplaceSpeakerEmbeddings(ctx, enrolled.ID, []models.JobSpeakerEmbedding{
+		{Speaker: "SPEAKER_00", Vector: []float32{2, 0, 0}},
+		{Speaker: "SPEAKER_01", Vector: []float32{0, 1, 0}},
+	}))
+
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/voiceprints", map[string]string{
+		"name": "Alice", "job_id": enrolled.ID, "speaker": "SPEAKER_07",
+	}, true)
+	assert.Equal(suite.T(), 409, w.Code, "speaker without an embedding")
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/voiceprints", map[string]string{
+		"name": "Alice", "job_id": enrolled.ID, "speaker": "SPEAKER_00",
+	}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var voiceprint models.Voiceprint
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &voiceprint))
+	assert.Equal(suite.T(), 1, voiceprint.Samples)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/voiceprints", map[string]string{
+		"name": "Alice", "job_id": enrolled.ID, "speaker": "SPEAKER_00",
+	}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &voiceprint))
+	assert.Equal(suite.T(), 2, voiceprint.Samples, "enrolling the same name refines its voiceprint")
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/voiceprints", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var voiceprints []models.Voiceprint
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &voiceprints))
+	suite.Require().Len(voiceprints, 1)
+	assert.Equal(suite.T(), "Alice", voiceprints[0].Name)
+
+	// A new job in the same workspace where SPEAKER_02, closest to Alice, was named by hand
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Retro")
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"workspace": workspace, "diarization": true,
+	}).Error)
+	suite.Require().NoError(repo.ReplaceSpeakerEmbeddings(ctx, job.ID, []models.JobSpeakerEmbedding{
+		{Speaker: "SPEAKER_00", Vector: []float32{0, 1, 0.1}},
+		{Speaker: "SPEAKER_01", Vector: []float32{0.9, 0.1, 0}},
+		{Speaker: "SPEAKER_02", Vector: []float32{0.95, 0, 0.05}},
+	}))
+	suite.Require().NoError(repo.UpdateMappings(ctx, job.ID, []models.SpeakerMapping{
+		{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_02", CustomName: "Bob"},
+	}))
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/speakers/identify", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var matches []api.VoiceprintMatchResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &matches))
+	suite.Require().Len(matches, 1, "each voiceprint names one speaker")
+	assert.Equal(suite.T(), "SPEAKER_01", matches[0].Speaker)
+	assert.Equal(suite.T(), "Alice", matches[0].Name)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/speakers", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var named []api.SpeakerMappingResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &named))
+	suite.Require().Len(named, 2)
+	for _, mapping := range named {
+		switch mapping.OriginalSpeaker {
+		case "SPEAKER_01":
+			assert.Equal(suite.T(), "Alice", mapping.CustomName)
+			assert.Equal(suite.T(), &voiceprint.ID, mapping.VoiceprintID)
+		case "SPEAKER_02":
+			assert.Equal(suite.T(), "Bob", mapping.CustomName)
+			assert.Nil(suite.T(), mapping.VoiceprintID)
+		}
+	}
+
+	// Without the hand-picked name, the closer speaker is matched instead
+	suite.Require().NoError(repo.UpdateMappings(ctx, job.ID, nil))
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/speakers/identify", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	mappings, err := repo.ListByJob(ctx, job.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(mappings, 1)
+	assert.Equal(suite.T(), "SPEAKER_02", mappings[0].OriginalSpeaker)
+	assert.Equal(suite.T(), "Alice", mappings[0].CustomName)
+	assert.Equal(suite.T(), &voiceprint.ID, mappings[0].VoiceprintID)
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/voiceprints/"+voiceprint.ID, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/voiceprints/"+voiceprint.ID, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	// Names from a deleted voiceprint are dropped when matching again
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/speakers/identify", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	mappings, err = repo.ListByJob(ctx, job.ID)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), mappings)
+}
+
+func (suite *APIHandlerTestSuite) TestSpeakerDirectory() {
+	newJob := func(title string) *models.TranscriptionJob {
+		job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+		suite.Require().NoError(suite.helper.DB.Model(job).Update("diarization", true).Error)
+		return job
+	}
+	mapSpeakers := func(job *models.TranscriptionJob, mappings ...map[string]string) []api.SpeakerMappingResponse {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+job.ID+"/speakers", map[string]interface{}{"mappings": mappings}, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response []api.SpeakerMappingResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	byLabel := func(mappings []api.SpeakerMappingResponse) map[string]api.SpeakerMappingResponse {
+		labels := map[string]api.SpeakerMappingResponse{}
+		for _, mapping := range mappings {
+			labels[mapping.OriginalSpeaker] = mapping
+		}
+		return labels
+	}
+
+	planning := newJob("Planning")
+	review := newJob("Review")
+	mapped := byLabel(mapSpeakers(planning,
+		map[string]string{"original_speaker": "SPEAKER_00", "custom_name": "Alice"},
+		map[string]string{"original_speaker": "SPEAKER_01", "custom_name": "SPEAKER_01"},
+	))
+	suite.Require().NotNil(mapped["SPEAKER_00"].SpeakerID)
+	assert.Nil(suite.T(), mapped["SPEAKER_01"].SpeakerID, "generic labels stay out of the directory")
+	alice := *mapped["SPEAKER_00"].SpeakerID
+	mapped = byLabel(mapSpeakers(review, map[string]string{"original_speaker": "SPEAKER_03", "custom_name": " Alice "}))
+	assert.Equal(suite.T(), &alice, mapped["SPEAKER_03"].SpeakerID, "the same name links the same speaker")
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/speakers", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var speakers []repository.SpeakerWithJobs
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &speakers))
+	suite.Require().Len(speakers, 1)
+	assert.Equal(suite.T(), "Alice", speakers[0].Name)
+	assert.Equal(suite.T(), int64(2), speakers[0].JobCount)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/speakers/"+alice, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var detail api.SpeakerResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &detail))
+	suite.Require().Len(detail.Jobs, 2)
+	jobs := map[string]string{}
+	for _, appearance := range detail.Jobs {
+		jobs[appearance.JobID] = appearance.OriginalSpeaker
+	}
+	assert.Equal(suite.T(), map[string]string{planning.ID: "SPEAKER_00", review.ID: "SPEAKER_03"}, jobs)
+
+	// Renaming renames the speaker in every job
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/speakers/"+alice, map[string]string{"name": "Alicia"}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	for _, job := range []*models.TranscriptionJob{planning, review} {
+		w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/speakers", nil, true)
+		assert.Equal(suite.T(), 200, w.Code)
+		assert.Contains(suite.T(), w.Body.String(), `"custom_name":"Alicia"`)
+		assert.NotContains(suite.T(), w.Body.String(), `"custom_name":"Alice"`)
+	}
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/speakers", map[string]string{"name": "Bob"}, true)
+	assert.Equal(suite.T(), 201, w.Code)
+	var bob models.Speaker
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &bob))
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/speakers", map[string]string{"name": "Bob"}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/speakers/"+alice, map[string]string{"name": "Bob"}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	mapped = byLabel(mapSpeakers(review,
+		map[string]string{"original_speaker": "SPEAKER_03", "speaker_id": alice},
+		map[string]string{"original_speaker": "SPEAKER_04", "speaker_id": bob.ID},
+	))
+	assert.Equal(suite.T(), "Alicia", mapped["SPEAKER_03"].CustomName)
+	assert.Equal(suite.T(), "Bob", mapped["SPEAKER_04"].CustomName)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+review.ID+"/speakers", map[string]interface{}{
+		"mappings": []map[string]string{{"original_speaker": "SPEAKER_04", "speaker_id": "missing"}},
+	}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+review.ID+"/speakers", map[string]interface{}{
+		"mappings": []map[string]string{{"original_speaker": "SPEAKER_04"}},
+	}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	// Deleting a speaker keeps the names in its jobs
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/speakers/"+alice, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/speakers/"+alice, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+planning.ID+"/speakers", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var remaining []api.SpeakerMappingResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &remaining))
+	mapped = byLabel(remaining)
+	assert.Equal(suite.T(), "Alicia", mapped["SPEAKER_00"].CustomName)
+	assert.Nil(suite.T(), mapped["SPEAKER_00"].SpeakerID)
+}
+
+func (suite *APIHandlerTestSuite) TestTags() {
+	planning := suite.helper.CreateTestTranscriptionJob(suite.T(), "Tagged planning")
+	review := suite.helper.CreateTestTranscriptionJob(suite.T(), "Tagged review")
+	jobTags := func(job *models.TranscriptionJob) (*models.TranscriptionJob, []models.Tag) {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/tags", nil, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var tags []models.Tag
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &tags))
+		stored, err := repository.NewJobRepository(suite.helper.DB).FindByID(context.Background(), job.ID)
+		suite.Require().NoError(err)
+		return stored, tags
+	}
+
+	for _, invalid := range []map[string]string{{"key": " "}, {"key": "desk=sales"}, {"key": "desk", "color": "red"}} {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/tags", invalid, true)
+		assert.Equal(suite.T(), 400, w.Code, invalid)
+	}
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/tags", map[string]string{"key": "desk", "value": "sales", "color": "#1f77b4"}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var sales models.Tag
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &sales))
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/tags", map[string]string{"key": "desk", "value": "sales"}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	// Tags are assigned by ID, or by name, which adds missing tags
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+planning.ID+"/tags", map[string]string{"tag_id": sales.ID}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+review.ID+"/tags", map[string]string{"name": "desk=sales"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+review.ID+"/tags", map[string]string{"name": "urgent"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/"+review.ID+"/tags", map[string]string{}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	stored, tags := jobTags(review)
+	suite.Require().Len(tags, 2)
+	assert.Equal(suite.T(), sales.ID, tags[0].ID, "naming an existing tag assigns it")
+	assert.Equal(suite.T(), "urgent", tags[1].Name())
+	suite.Require().NotNil(stored.Tags)
+	assert.JSONEq(suite.T(), `[{"Key":"desk","Value":"sales"},{"Key":"urgent","Value":""}]`, *stored.Tags)
+	urgent := tags[1]
+
+	// Autocompletion lists the most used tags starting with the prefix
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/tags?q=desk", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var suggestions []repository.TagWithJobs
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &suggestions))
+	suite.Require().Len(suggestions, 1)
+	assert.Equal(suite.T(), sales.ID, suggestions[0].ID)
+	assert.Equal(suite.T(), int64(2), suggestions[0].JobCount)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/tags", nil, true)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &suggestions))
+	order := []string{}
+	for _, tag := range suggestions {
+		if tag.ID == sales.ID || tag.ID == urgent.ID {
+			order = append(order, tag.ID)
+		}
+	}
+	assert.Equal(suite.T(), []string{sales.ID, urgent.ID}, order, "most used first")
+
+	// The jobs list filters by tag
+	listed := func(query string) []string {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/list?limit=100&"+query, nil, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response struct {
+			Jobs []models.TranscriptionJob `json:"jobs"`
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ids := []string{}
+		for _, job := range response.Jobs {
+			ids = append(ids, job.ID)
+		}
+		return ids
+	}
+	assert.ElementsMatch(suite.T(), []string{planning.ID, review.ID}, listed("tag=desk=sales"))
+	assert.Equal(suite.T(), []string{review.ID}, listed("tag_id="+urgent.ID))
+
+	// Renaming a tag renames it on its jobs
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/tags/"+sales.ID, map[string]string{"key": "desk", "value": "marketing"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	stored, _ = jobTags(planning)
+	suite.Require().NotNil(stored.Tags)
+	assert.JSONEq(suite.T(), `[{"Key":"desk","Value":"marketing"}]`, *stored.Tags)
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/tags/"+sales.ID, map[string]string{"key": "urgent"}, true)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/"+review.ID+"/tags/"+urgent.ID, nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/transcription/"+review.ID+"/tags/"+urgent.ID, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	// Tags with a retention policy are kept until the policy is deleted
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "job", "action": "delete", "after_days": 30, "tag_id": "missing"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "job", "action": "delete", "after_days": 30, "tag_id": sales.ID}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var policy models.RetentionPolicy
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &policy))
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/tags/"+sales.ID, nil, true)
+	assert.Equal(suite.T(), 409, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/admin/retention/policies/%d", policy.ID), nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/tags/"+sales.ID, nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	stored, tags = jobTags(review)
+	assert.Empty(suite.T(), tags)
+	assert.Nil(suite.T(), stored.Tags)
+}
+
+func (suite *APIHandlerTestSuite) TestCollections() {
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/organizations", map[string]interface{}{"name": "Studio"}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var studio models.Organization
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &studio))
+	workspace := models.OrganizationWorkspace(studio.ID)
+	member := func(username string) string {
+		user := models.User{Username: username, Password: "x", Role: models.RoleEditor}
+		suite.Require().NoError(suite.helper.DB.Create(&user).Error)
+		suite.Require().NoError(suite.helper.DB.Create(&models.OrganizationMember{OrganizationID: studio.ID, UserID: user.ID}).Error)
+		token, err := suite.helper.AuthService.GenerateToken(&user)
+		suite.Require().NoError(err)
+		return token
+	}
+	ann, bob := member("studio.ann"), member("studio.bob")
+	var annID uint
+	suite.Require().NoError(suite.helper.DB.Model(&models.User{}).Where("username = ?", "studio.ann").Pluck("id", &annID).Error)
+	send := func(token, method, path string, body interface{}) *httptest.ResponseRecorder {
+		reader := bytes.NewBuffer(nil)
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewBuffer(data)
+		}
+		req, err := http.NewRequest(method, path, reader)
+		suite.Require().NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(middleware.OrganizationHeader, studio.ID)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	admin := suite.helper.TestToken
+	listed := func(token, query string) []string {
+		w := send(token, "GET", "/api/v1/transcription/list?limit=100&"+query, nil)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response struct {
+			Jobs []models.TranscriptionJob `json:"jobs"`
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ids := []string{}
+		for _, job := range response.Jobs {
+			ids = append(ids, job.ID)
+		}
+		return ids
+	}
+	create := func(token string, body map[string]interface{}) models.Collection {
+		w := send(token, "POST", "/api/v1/collections", body)
+		suite.Require().Equal(201, w.Code, w.Body.String())
+		var collection models.Collection
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &collection))
+		return collection
+	}
+
+	outside := suite.helper.CreateTestTranscriptionJob(suite.T(), "Instance job")
+	jobs := []*models.TranscriptionJob{}
+	for _, title := range []string{"Episode 1", "Episode 2", "Unfiled"} {
+		job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+		suite.Require().NoError(suite.helper.DB.Model(job).Update("workspace", workspace).Error)
+		jobs = append(jobs, job)
+	}
+	episode1, episode2, unfiled := jobs[0], jobs[1], jobs[2]
+	suite.Require().NoError(suite.helper.DB.Model(episode1).Updates(map[string]interface{}{
+		"title": "Episode 1 quokka", "review_status": models.ReviewNeeded,
+	}).Error)
+
+	podcast := create(ann, map[string]interface{}{"name": "Podcast"})
+	season := create(ann, map[string]interface{}{"name": "Season 2", "parent_id": podcast.ID})
+	assert.Equal(suite.T(), 400, send(ann, "POST", "/api/v1/collections", map[string]interface{}{"name": " "}).Code)
+	assert.Equal(suite.T(), 400, send(ann, "POST", "/api/v1/collections", map[string]interface{}{"name": "Lost", "parent_id": "missing"}).Code)
+	w = send(ann, "PUT", "/api/v1/collections/"+podcast.ID, map[string]interface{}{"name": "Podcast", "parent_id": season.ID})
+	assert.Equal(suite.T(), 400, w.Code, "a collection cannot move inside itself")
+
+	// Jobs are filed in collections and listed by them
+	w = send(ann, "POST", "/api/v1/collections/"+season.ID+"/jobs", map[string]interface{}{"job_ids": []string{episode1.ID, outside.ID}})
+	assert.Equal(suite.T(), 404, w.Code, "jobs of other workspaces cannot be filed")
+	w = send(ann, "POST", "/api/v1/collections/"+season.ID+"/jobs", map[string]interface{}{"job_ids": []string{episode1.ID, episode2.ID}})
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.ElementsMatch(suite.T(), []string{episode1.ID, episode2.ID}, listed(ann, "collection_id="+season.ID))
+	assert.Equal(suite.T(), []string{unfiled.ID}, listed(ann, "collection_id=none"))
+
+	w = send(ann, "GET", "/api/v1/collections", nil)
+	suite.Require().Equal(200, w.Code)
+	var collections []api.CollectionResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &collections))
+	suite.Require().Len(collections, 2)
+	assert.Equal(suite.T(), season.ID, collections[1].ID)
+	assert.Equal(suite.T(), int64(2), collections[1].JobCount)
+	assert.Equal(suite.T(), models.RoleEditor, collections[1].Role)
+
+	// Exports hold a directory per collection inside the exported one
+	w = send(ann, "GET", "/api/v1/collections/"+podcast.ID+"/export", nil)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	suite.Require().NoError(err)
+	names := []string{}
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Contains(suite.T(), names, "Season 2 - "+season.ID+"/Episode 1 quokka - "+episode1.ID+"/context.json")
+
+	// Restricted collections are only accessible to admins and their members
+	restrict := map[string]interface{}{"restricted": true, "members": []map[string]interface{}{{"user_id": annID, "role": models.RoleViewer}}}
+	assert.Equal(suite.T(), 403, send(ann, "PUT", "/api/v1/collections/"+season.ID+"/permissions", restrict).Code)
+	w = send(admin, "PUT", "/api/v1/collections/"+season.ID+"/permissions", map[string]interface{}{"restricted": true, "members": []map[string]interface{}{{"user_id": annID, "role": models.RoleAdmin}}})
+	assert.Equal(suite.T(), 400, w.Code)
+	w = send(admin, "PUT", "/api/v1/collections/"+season.ID+"/permissions", restrict)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+
+	assert.Equal(suite.T(), 404, send(bob, "GET", "/api/v1/collections/"+season.ID, nil).Code)
+	assert.Equal(suite.T(), 404, send(bob, "GET", "/api/v1/transcription/"+episode1.ID, nil).Code)
+	assert.Equal(suite.T(), 404, send(bob, "GET", "/api/v1/transcription/bundle?ids="+episode1.ID, nil).Code)
+	assert.Equal(suite.T(), []string{unfiled.ID}, listed(bob, ""))
+	for _, path := range []string{"/api/v1/search?q=quokka", "/api/v1/transcription/review-queue", "/api/v1/transcription/aws-transcribe"} {
+		w = send(bob, "GET", path, nil)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		assert.NotContains(suite.T(), w.Body.String(), episode1.ID, path)
+		w = send(ann, "GET", path, nil)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		if path != "/api/v1/transcription/aws-transcribe" {
+			assert.Contains(suite.T(), w.Body.String(), episode1.ID, path)
+		}
+	}
+	w = send(bob, "GET", "/api/v1/collections/"+podcast.ID+"/export", nil)
+	suite.Require().Equal(200, w.Code)
+	archive, err = zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), archive.File)
+
+	// The member may view the collection's jobs, but not change them
+	assert.Equal(suite.T(), 200, send(ann, "GET", "/api/v1/transcription/"+episode1.ID, nil).Code)
+	assert.Equal(suite.T(), 403, send(ann, "PUT", "/api/v1/transcription/"+episode1.ID+"/title", map[string]interface{}{"title": "Renamed"}).Code)
+	assert.Equal(suite.T(), 403, send(ann, "POST", "/api/v1/collections/"+season.ID+"/jobs", map[string]interface{}{"job_ids": []string{unfiled.ID}}).Code)
+	assert.ElementsMatch(suite.T(), []string{episode1.ID, episode2.ID, unfiled.ID}, listed(ann, ""))
+	assert.ElementsMatch(suite.T(), []string{episode1.ID, episode2.ID, unfiled.ID}, listed(admin, ""))
+
+	// Editors of a restricted collection cannot move what it restricts where
+	// the restriction does not apply
+	editor := map[string]interface{}{"restricted": true, "members": []map[string]interface{}{{"user_id": annID, "role": models.RoleEditor}}}
+	suite.Require().Equal(200, send(admin, "PUT", "/api/v1/collections/"+season.ID+"/permissions", editor).Code)
+	inner := create(ann, map[string]interface{}{"name": "Bonus", "parent_id": season.ID})
+	w = send(ann, "POST", "/api/v1/collections/"+inner.ID+"/jobs", map[string]interface{}{"job_ids": []string{episode2.ID}})
+	suite.Require().Equal(200, w.Code, "moving deeper keeps the restriction")
+	assert.Equal(suite.T(), 403, send(ann, "POST", "/api/v1/collections/"+podcast.ID+"/jobs", map[string]interface{}{"job_ids": []string{episode2.ID}}).Code)
+	assert.Equal(suite.T(), 403, send(ann, "DELETE", "/api/v1/collections/"+inner.ID+"/jobs/"+episode2.ID, nil).Code)
+	assert.Equal(suite.T(), 403, send(ann, "PUT", "/api/v1/collections/"+inner.ID, map[string]interface{}{"name": "Bonus"}).Code, "Bonus is moved to the top level")
+	assert.Equal(suite.T(), 403, send(ann, "DELETE", "/api/v1/collections/"+season.ID, nil).Code)
+	assert.Equal(suite.T(), 200, send(ann, "PUT", "/api/v1/collections/"+inner.ID, map[string]interface{}{"name": "Extras", "parent_id": season.ID}).Code)
+	assert.Equal(suite.T(), 204, send(ann, "DELETE", "/api/v1/collections/"+inner.ID, nil).Code, "its jobs stay in Season 2")
+	assert.Equal(suite.T(), 404, send(bob, "GET", "/api/v1/transcription/"+episode2.ID, nil).Code)
+
+	w = send(admin, "DELETE", "/api/v1/collections/"+season.ID+"/jobs/"+episode2.ID, nil)
+	assert.Equal(suite.T(), 204, w.Code)
+	assert.Equal(suite.T(), 404, send(admin, "DELETE", "/api/v1/collections/"+season.ID+"/jobs/"+episode2.ID, nil).Code)
+	assert.Equal(suite.T(), 200, send(bob, "GET", "/api/v1/transcription/"+episode2.ID, nil).Code)
+
+	// Deleting a collection moves its jobs into its parent
+	assert.Equal(suite.T(), 204, send(admin, "DELETE", "/api/v1/collections/"+season.ID, nil).Code)
+	assert.Equal(suite.T(), 404, send(admin, "GET", "/api/v1/collections/"+season.ID, nil).Code)
+	assert.Equal(suite.T(), []string{episode1.ID}, listed(bob, "collection_id="+podcast.ID))
+	assert.Equal(suite.T(), 200, send(bob, "PUT", "/api/v1/transcription/"+episode1.ID+"/title", map[string]interface{}{"title": "Renamed"}).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestAuditLog() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Board meeting")
+
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/api-keys/", map[string]string{"name": "Audited key"}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var key models.APIKey
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &key))
+	keyID := fmt.Sprintf("%d", key.ID)
+	suite.Require().Equal(200, suite.makeAuthenticatedRequest("DELETE", "/api/v1/api-keys/"+keyID, nil, true).Code)
+	suite.Require().Equal(200, suite.makeAuthenticatedRequest("PUT", "/api/v1/transcription/"+job.ID+"/title", map[string]string{"title": "Board meeting (Q3)"}, true).Code)
+	// Reads and failed changes are not recorded
+	suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true)
+	suite.makeAuthenticatedRequest("DELETE", "/api/v1/api-keys/999999", nil, true)
+
+	list := func(query string) api.AuditEventsResponse {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/audit?"+query, nil, true)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var page api.AuditEventsResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+
+	keyEvents := list("resource_type=api_key&resource_id=" + keyID)
+	suite.Require().Len(keyEvents.Events, 2)
+	assert.Equal(suite.T(), models.AuditAPIKeyDeleted, keyEvents.Events[0].Action)
+	assert.Equal(suite.T(), models.AuditAPIKeyCreated, keyEvents.Events[1].Action)
+	assert.Equal(suite.T(), suite.helper.TestUser.Username, keyEvents.Events[1].Actor)
+	assert.Equal(suite.T(), "POST", keyEvents.Events[1].Method)
+	assert.Equal(suite.T(), 200, keyEvents.Events[1].Status)
+
+	jobEvents := list("resource_id=" + job.ID)
+	suite.Require().Len(jobEvents.Events, 1)
+	assert.Equal(suite.T(), models.AuditTranscriptEdited, jobEvents.Events[0].Action)
+	assert.Equal(suite.T(), "/api/v1/transcription/"+job.ID+"/title", jobEvents.Events[0].Path)
+
+	paged := list("limit=1&offset=1")
+	assert.Len(suite.T(), paged.Events, 1)
+	assert.GreaterOrEqual(suite.T(), paged.Total, int64(3))
+	assert.Empty(suite.T(), list("since="+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)).Events)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", "/api/v1/admin/audit?since=yesterday", nil, true).Code)
+
+	// The export has one event per line, oldest first
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/audit/export?resource_type=api_key&resource_id="+keyID, nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "application/x-ndjson", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	suite.Require().Len(lines, 2)
+	var first models.AuditEvent
+	suite.Require().NoError(json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(suite.T(), models.AuditAPIKeyCreated, first.Action)
+}
+
+func (suite *APIHandlerTestSuite) TestChatPolicy() {
+	// A fake OpenAI-compatible endpoint keeping the messages of each request
+	var sent [][]map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []map[string]string `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req.Messages)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Noted.\"}}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+	apiKey := "test-key"
+	llmConfig := models.LLMConfig{Provider: "openai", APIKey: &apiKey, OpenAIBaseURL: &server.URL, IsActive: true}
+	suite.Require().NoError(suite.helper.DB.Create(&llmConfig).Error)
+	defer suite.helper.DB.Delete(&llmConfig)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/chat/policy", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), `"banned_topics":[]`)
+
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/chat/policy", map[string]interface{}{
+		"system_prompt":        "Answer in English only.",
+		"banned_topics":        []string{"salaries", "  stock   options "},
+		"max_history_messages": 2,
+	}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var policy api.ChatPolicyResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &policy))
+	assert.Equal(suite.T(), []string{"salaries", "stock options"}, policy.BannedTopics)
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("PUT", "/api/v1/chat/policy", map[string]interface{}{"max_context_tokens": -1}, true).Code)
+
+	transcript := `{"segments": [{"start": 0, "end": 3, "text": "Budgets are tight this quarter.", "speaker": "SPEAKER_00"}]}`
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Budget review")
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript, "workspace": fmt.Sprintf("user-%d", suite.helper.TestUser.ID),
+	}).Error)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/chat/sessions", map[string]string{"transcription_id": job.ID, "model": "gpt-4o"}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var session api.ChatSessionResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &session))
+	send := func(content string) *httptest.ResponseRecorder {
+		return suite.makeAuthenticatedRequest("POST", "/api/v1/chat/sessions/"+session.ID+"/messages", map[string]string{"content": content}, true)
+	}
+
+	// Banned topics are refused before they are saved or sent, and the refusal is on record
+	w = send("What are the team's Salaries?")
+	assert.Equal(suite.T(), 422, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "salaries")
+	assert.Empty(suite.T(), sent)
+	var refusals int64
+	suite.helper.DB.Model(&models.AuditEvent{}).Where("action = ? AND resource_id = ?", models.AuditChatRefused, session.ID).Count(&refusals)
+	assert.Equal(suite.T(), int64(1), refusals)
+
+	// The system prompt leads the instructions and only the latest messages are sent
+	suite.Require().Equal(200, send("Summarize the budget").Code)
+	suite.Require().Equal(200, send("Who raised it?").Code)
+	suite.Require().Len(sent, 2)
+	last := sent[1]
+	suite.Require().Len(last, 3)
+	assert.Equal(suite.T(), "system", last[0]["role"])
+	assert.True(suite.T(), strings.HasPrefix(last[0]["content"], "Answer in English only."))
+	assert.Contains(suite.T(), last[0]["content"], "stock options")
+	assert.Contains(suite.T(), last[0]["content"], "Budgets are tight")
+	assert.Equal(suite.T(), "Who raised it?", last[2]["content"])
+
+	// A context cap below the transcript's size rejects the request
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/chat/policy", map[string]interface{}{"max_context_tokens": 20}, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), 400, send("And the timeline?").Code)
+	assert.Len(suite.T(), sent, 2)
+	suite.Require().Equal(200, suite.makeAuthenticatedRequest("PUT", "/api/v1/chat/policy", map[string]interface{}{}, true).Code)
+
+	// An instance-wide policy applies on top of every workspace's own
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/chat/policy?workspace=*", map[string]interface{}{"system_prompt": "Be brief.", "banned_topics": []string{"layoffs"}}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	defer suite.helper.DB.Where("workspace = ?", models.InstanceChatPolicy).Delete(&models.ChatPolicy{})
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/chat/policy", nil, true)
+	suite.Require().Equal(200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &policy))
+	assert.Equal(suite.T(), []string{"layoffs"}, policy.BannedTopics)
+	assert.Equal(suite.T(), 422, send("Are layoffs coming?").Code)
+
+	// Admins can set another workspace's policy, which leaves their own alone
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/chat/policy?workspace=user-999", map[string]interface{}{"banned_topics": []string{"bonuses"}}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/chat/policy?workspace=user-999", nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"banned_topics":["bonuses"]`)
+	assert.Equal(suite.T(), 200, send("Any bonuses this year?").Code)
+
+	// Other users can only read the policy in effect for themselves
+	viewer := models.User{Username: "chat.viewer", Password: "x", Role: models.RoleViewer}
+	suite.Require().NoError(suite.helper.DB.Create(&viewer).Error)
+	viewerToken, err := suite.helper.AuthService.GenerateToken(&viewer)
+	suite.Require().NoError(err)
+	saved := suite.helper.TestToken
+	suite.helper.TestToken = viewerToken
+	assert.Equal(suite.T(), 403, suite.makeAuthenticatedRequest("GET", "/api/v1/chat/policy?workspace=*", nil, true).Code)
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/chat/policy", nil, true).Code)
+	suite.helper.TestToken = saved
+
+	// Chats are refused, not run unconstrained, when the policy cannot be loaded
+	requests := len(sent)
+	suite.Require().NoError(suite.helper.DB.Migrator().DropTable(&models.ChatPolicy{}))
+	w = send("What changed?")
+	suite.Require().NoError(suite.helper.DB.AutoMigrate(&models.ChatPolicy{}))
+	assert.Equal(suite.T(), 503, w.Code)
+	assert.Len(suite.T(), sent, requests)
+}
+
+func (suite *APIHandlerTestSuite) TestSummarizeWithTemplate() {
+	// A fake OpenAI-compatible endpoint numbering its replies
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": fmt.Sprintf("Reply %d", requests)}}},
+		})
+	}))
+	defer server.Close()
+	apiKey := "test-key"
+	llmConfig := models.LLMConfig{Provider: "openai", APIKey: &apiKey, OpenAIBaseURL: &server.URL, IsActive: true}
+	suite.Require().NoError(suite.helper.DB.Create(&llmConfig).Error)
+	defer suite.helper.DB.Delete(&llmConfig)
+
+	// A transcript long enough to be summarized in three chunks
+	segments := make([]map[string]interface{}, 200)
+	for i := range segments {
+		segments[i] = map[string]interface{}{"start": i, "end": i + 1, "text": fmt.Sprintf("Segment %d %s", i, strings.Repeat("talk ", 30))}
+	}
+	transcript, _ := json.Marshal(map[string]interface{}{"segments": segments})
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Long meeting")
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": string(transcript)}).Error)
+	template := suite.helper.CreateTestSummaryTemplate(suite.T(), "Brief")
+
+	// The stored transcript is condensed chunk by chunk, whatever content says
+	summarize := func() *httptest.ResponseRecorder {
+		return suite.makeAuthenticatedRequest("POST", "/api/v1/summarize/", map[string]interface{}{
+			"model": "gpt-test", "content": "ignored", "transcription_id": job.ID, "template_id": template.ID,
+		}, true)
+	}
+	w := summarize()
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "Reply 4", w.Body.String())
+	assert.Equal(suite.T(), 4, requests, "three chunks and the combined summary")
+	var summary models.Summary
+	suite.Require().NoError(suite.helper.DB.Where("transcription_id = ?", job.ID).Order("id DESC").First(&summary).Error)
+	assert.Equal(suite.T(), "Reply 4", summary.Content)
+
+	// Summarizing again is served from the cache
+	w = summarize()
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "Reply 4", w.Body.String())
+	assert.Equal(suite.T(), 4, requests)
+
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("POST", "/api/v1/summarize/", map[string]interface{}{"model": "gpt-test", "transcription_id": job.ID}, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("POST", "/api/v1/summarize/", map[string]interface{}{"model": "gpt-test", "transcription_id": job.ID, "template_id": "missing"}, true).Code)
+}
+
+func TestAPIHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(APIHandlerTestSuite))
+}
+
+func (suite *APIHandlerTestSuite) TestRecordingBundles() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Board meeting")
+	workspace := fmt.Sprintf("user-%d", suite.helper.TestUser.ID)
+	suite.Require().NoError(suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "workspace": workspace,
+		"transcript": `{"text": "We approve the budget.", "segments": [{"start": 0, "end": 2, "text": "We approve the budget.", "speaker": "SPEAKER_00"}]}`,
+	}).Error)
+	suite.Require().NoError(suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "Dana"}).Error)
+	suite.Require().NoError(suite.helper.DB.Omit("Transcription").Create(&models.Summary{TranscriptionID: job.ID, Model: "gpt-test", Content: "The budget passed."}).Error)
+	suite.helper.CreateTestNote(suite.T(), job.ID)
+	session := suite.helper.CreateTestChatSession(suite.T(), job.ID)
+	suite.Require().NoError(suite.helper.DB.Omit("ChatSession").Create(&models.ChatMessage{ChatSessionID: session.ID, Role: "user", Content: "Who approved it?"}).Error)
+	pending := suite.helper.CreateTestTranscriptionJob(suite.T(), "Not yet transcribed")
+
+	readZip := func(w *httptest.ResponseRecorder) map[string]string {
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		assert.Equal(suite.T(), "application/zip", w.Header().Get("Content-Type"))
+		zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		suite.Require().NoError(err)
+		files := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			suite.Require().NoError(err)
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			suite.Require().NoError(err)
+			files[f.Name] = string(data)
+		}
+		return files
+	}
+
+	// A recording's bundle holds its context as JSON and Markdown next to the transcript
+	files := readZip(suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/bundle", nil, true))
+	suite.Require().Contains(files, "context.json")
+	var bundle export.Bundle
+	suite.Require().NoError(json.Unmarshal([]byte(files["context.json"]), &bundle))
+	assert.Equal(suite.T(), []export.BundleSpeaker{{Label: "SPEAKER_00", Name: "Dana"}}, bundle.Speakers)
+	suite.Require().Len(bundle.Summaries, 1)
+	assert.Equal(suite.T(), "The budget passed.", bundle.Summaries[0].Content)
+	suite.Require().Len(bundle.Notes, 1)
+	assert.Equal(suite.T(), "Test note content", bundle.Notes[0].Content)
+	suite.Require().Len(bundle.Chats, 1)
+	suite.Require().Len(bundle.Chats[0].Messages, 1)
+	assert.Equal(suite.T(), "Who approved it?", bundle.Chats[0].Messages[0].Content)
+	assert.Contains(suite.T(), files["context.md"], "| SPEAKER_00 | Dana |")
+	assert.Contains(suite.T(), files["context.md"], "> Test quote text")
+	assert.Contains(suite.T(), files["context.md"], "**User:** Who approved it?")
+	assert.Contains(suite.T(), files["transcript.srt"], "Dana: We approve the budget.")
+	assert.Contains(suite.T(), files, "transcript.json")
+
+	// A batch puts each recording in its own directory; unfinished ones have no transcript
+	files = readZip(suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/bundle?ids="+job.ID+","+pending.ID, nil, true))
+	assert.Contains(suite.T(), files, "Board meeting - "+job.ID+"/transcript.srt")
+	assert.Contains(suite.T(), files, "Not yet transcribed - "+pending.ID+"/context.md")
+	assert.NotContains(suite.T(), files, "Not yet transcribed - "+pending.ID+"/transcript.json")
+	assert.Equal(suite.T(), 400, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/bundle", nil, true).Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/bundle?ids="+job.ID+",missing", nil, true).Code)
+
+	// The takeout holds the account and the recordings of the user's own workspace
+	files = readZip(suite.makeAuthenticatedRequest("GET", "/api/v1/user/takeout", nil, true))
+	assert.Contains(suite.T(), files["account.json"], suite.helper.TestUser.Username)
+	assert.NotContains(suite.T(), files["account.json"], "password")
+	assert.Contains(suite.T(), files, "recordings/Board meeting - "+job.ID+"/context.json")
+	assert.NotContains(suite.T(), files, "recordings/Not yet transcribed - "+pending.ID+"/context.json")
+}
+
+// Test backups through the admin API
+func (suite *APIHandlerTestSuite) TestBackups() {
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/backups", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/backups?upload=false", nil, true)
+	assert.Equal(suite.T(), 201, w.Code)
+	var created backup.Info
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(suite.T(), created.Name)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/backups", nil, true)
+	var backups []backup.Info
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &backups))
+	assert.Equal(suite.T(), created.Name, backups[0].Name)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/backups/"+created.Name, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	_, err := backup.Inspect(bytes.NewReader(w.Body.Bytes()))
+	assert.NoError(suite.T(), err)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/backups/missing.tar.gz", nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/backups/restore", map[string]string{"name": "missing.tar.gz"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	// Restoring is staged for the next startup
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/backups/restore", map[string]string{"name": created.Name}, true)
+	assert.Equal(suite.T(), 202, w.Code)
+	pending := filepath.Join(suite.helper.Config.BackupDir, backup.PendingRestoreName)
+	assert.FileExists(suite.T(), pending)
+	os.Remove(pending)
+}
+
+// Test notification template management
+func (suite *APIHandlerTestSuite) TestNotificationTemplates() {
+	w := suite.makeAuthenticatedRequest("PUT", "/api/v1/admin/notification-templates/slack", map[string]string{"body": "{}"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	// Templates that would not render valid JSON are refused
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/admin/notification-templates/eventbridge", map[string]string{"body": `{"title":{{.Title}}}`}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	body := `{"job":{{json .JobID}},"state":{{json .Event}},"text":{{json .Text}}}`
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/admin/notification-templates/eventbridge", map[string]string{"body": body, "content_type": "text/plain"}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var saved models.NotificationTemplate
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &saved))
+	assert.Equal(suite.T(), "application/json", saved.ContentType, "EventBridge details are always JSON")
+	assert.Equal(suite.T(), "testuser", saved.UpdatedBy)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/notification-templates", nil, true)
+	var templates []models.NotificationTemplate
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &templates))
+	assert.Len(suite.T(), templates, 1)
+	assert.Equal(suite.T(), body, templates[0].Body)
+
+	// Previews render with a sample job, or a real one
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/notification-templates/eventbridge/preview", map[string]string{"body": body}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var preview map[string]string
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), `{"job":"00000000-0000-0000-0000-000000000000","state":"COMPLETED","text":"Hello and welcome."}`, preview["body"])
+
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Template preview")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/notification-templates/webhook/preview", map[string]string{"body": "{{.JobID}} {{.Status}}", "content_type": "text/plain", "job_id": job.ID}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), job.ID+" "+string(job.Status), preview["body"])
+
+	// Email bodies are plain text and can link to the job
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/notification-templates/email/preview", map[string]string{"body": "{{.Title}}: {{.Link}}", "content_type": "application/json"}, true)
+	assert.Equal(suite.T(), 200, w.Code, w.Body.String())
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), "Weekly sync: https://scriberr.example.com/audio/00000000-0000-0000-0000-000000000000", preview["body"])
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/admin/notification-templates/eventbridge", nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/notification-templates", nil, true)
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &templates))
+	assert.Empty(suite.T(), templates)
+}
+
+func (suite *APIHandlerTestSuite) TestRetentionPolicies() {
+	// Archive policies need somewhere to archive to
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "audio", "action": "archive", "after_days": 30}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "audio", "action": "archive", "after_days": 30, "archive_uri": "s3://archive/scriberr", "storage_class": "CHEAP"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "job", "action": "delete", "after_days": 0}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "job", "action": "delete", "after_days": 5, "profile_id": "missing"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "audio", "action": "archive", "after_days": 30, "archive_uri": "s3://archive/scriberr/"}, true)
+	assert.Equal(suite.T(), 201, w.Code)
+	var policy models.RetentionPolicy
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &policy))
+	assert.Equal(suite.T(), "s3://archive/scriberr", policy.ArchiveURI)
+	assert.Equal(suite.T(), "GLACIER", policy.StorageClass)
+	assert.False(suite.T(), policy.Enforced, "policies start as a dry run")
+	assert.Equal(suite.T(), "testuser", policy.CreatedBy)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/policies", map[string]interface{}{"target": "job", "action": "delete", "after_days": 365}, true)
+	assert.Equal(suite.T(), 201, w.Code)
+	var jobPolicy models.RetentionPolicy
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &jobPolicy))
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/retention/policies", nil, true)
+	var policies []models.RetentionPolicy
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &policies))
+	assert.Len(suite.T(), policies, 2)
+
+	// The preview lists what the policies would do without doing it
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Old meeting")
+	assert.NoError(suite.T(), suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"status": models.StatusCompleted, "created_at": time.Now().AddDate(0, 0, -40)}).Error)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/retention/preview", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var report service.RetentionReport
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &report))
+	assert.True(suite.T(), report.DryRun)
+	assert.Len(suite.T(), report.Actions, 1)
+	assert.Equal(suite.T(), job.ID, report.Actions[0].JobID)
+	assert.Equal(suite.T(), policy.ID, report.Actions[0].PolicyID)
+	assert.Equal(suite.T(), service.RetentionPlanned, report.Actions[0].Result)
+
+	// Policies that are not enforced leave jobs alone
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/enforce", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(suite.T(), service.RetentionPlanned, report.Actions[0].Result)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	// Enforcing the job policy deletes the job
+	w = suite.makeAuthenticatedRequest("PUT", fmt.Sprintf("/api/v1/admin/retention/policies/%d", jobPolicy.ID), map[string]interface{}{"target": "job", "action": "delete", "after_days": 30, "enforced": true}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/retention/enforce", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Len(suite.T(), report.Actions, 1)
+	assert.Equal(suite.T(), jobPolicy.ID, report.Actions[0].PolicyID)
+	assert.Equal(suite.T(), service.RetentionDone, report.Actions[0].Result)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/admin/retention/policies/%d", policy.ID), nil, true)
+	assert.Equal(suite.T(), 204, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/admin/retention/policies/%d", policy.ID), nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+func (suite *APIHandlerTestSuite) TestLibraryImports() {
+	// Local imports are off until an import directory is configured
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/admin/imports", map[string]interface{}{"source": suite.T().TempDir()}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	root := suite.T().TempDir()
+	suite.helper.Config.LibraryImportDir = root
+	defer func() { suite.helper.Config.LibraryImportDir = "" }()
+	archive := filepath.Join(root, "2019")
+	assert.NoError(suite.T(), os.MkdirAll(archive, 0755))
+	assert.NoError(suite.T(), os.WriteFile(filepath.Join(archive, "standup.mp3"), []byte("standup"), 0644))
+	assert.NoError(suite.T(), os.WriteFile(filepath.Join(archive, "standup-copy.mp3"), []byte("standup"), 0644))
+	assert.NoError(suite.T(), os.WriteFile(filepath.Join(archive, "notes.txt"), []byte("notes"), 0644))
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/imports", map[string]interface{}{"source": suite.T().TempDir(), "dry_run": true}, true)
+	assert.Equal(suite.T(), 400, w.Code, "sources outside the import directory are refused")
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/imports", map[string]interface{}{"source": "2019", "dry_run": true}, true)
+	assert.Equal(suite.T(), 202, w.Code)
+	var imp models.LibraryImport
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &imp))
+	assert.Equal(suite.T(), models.LibraryImportRunning, imp.Status)
+	assert.True(suite.T(), imp.Dedup)
+	assert.Equal(suite.T(), "testuser", imp.CreatedBy)
+
+	assert.Eventually(suite.T(), func() bool {
+		w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/imports/"+imp.ID, nil, true)
+		return json.Unmarshal(w.Body.Bytes(), &imp) == nil && imp.Status != models.LibraryImportRunning
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(suite.T(), models.LibraryImportCompleted, imp.Status)
+	assert.Equal(suite.T(), 2, imp.FilesFound)
+	assert.Equal(suite.T(), 1, imp.Duplicates, "copies of a file are found by content")
+	assert.Equal(suite.T(), 0, imp.Queued, "dry runs queue nothing")
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/imports/"+imp.ID+"/files?status=planned", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var files api.LibraryImportFilesResponse
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &files))
+	assert.Equal(suite.T(), int64(1), files.Total)
+	assert.Nil(suite.T(), files.Files[0].JobID)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/imports", nil, true)
+	var imports []models.LibraryImport
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &imports))
+	assert.NotEmpty(suite.T(), imports)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/imports/"+imp.ID+"/cancel", nil, true)
+	assert.Equal(suite.T(), 404, w.Code, "finished imports cannot be cancelled")
+}
+
+func (suite *APIHandlerTestSuite) TestStorageUsage() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Storage usage")
+	audioPath := filepath.Join(suite.helper.Config.UploadDir, job.ID+".wav")
+	assert.NoError(suite.T(), os.MkdirAll(suite.helper.Config.UploadDir, 0755))
+	assert.NoError(suite.T(), os.WriteFile(audioPath, make([]byte, 64), 0644))
+	defer os.Remove(audioPath)
+	assert.NoError(suite.T(), suite.helper.DB.Model(job).Update("audio_path", audioPath).Error)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/storage?limit=0", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/storage?limit=1000", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var report service.StorageReport
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(suite.T(), suite.helper.Config.UploadDir, report.Uploads.Path)
+	assert.Greater(suite.T(), report.Database.Bytes, int64(0))
+	found := false
+	for _, size := range report.LargestJobs {
+		if size.JobID == job.ID {
+			found = true
+			assert.Equal(suite.T(), int64(64), size.AudioBytes)
+		}
+	}
+	assert.True(suite.T(), found, "jobs with audio on disk are listed")
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/storage/cleanup?max_age_minutes=-1", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/storage/cleanup?max_age_minutes=0", nil, true)
+	assert.Equal(suite.T(), 400, w.Code, "files just downloaded are never cleaned up")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/storage/cleanup", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var cleanup api.StorageCleanupResponse
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &cleanup))
+	assert.Equal(suite.T(), int64(3600), cleanup.Storage.MaxAgeSeconds)
+	assert.NotNil(suite.T(), cleanup.QuickTranscription)
+}
+
+func (suite *APIHandlerTestSuite) TestSpeakerAttributes() {
+	ctx := context.Background()
+	workspace := fmt.Sprintf("user-%d", suite.helper.TestUser.ID)
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Interview")
+	suite.Require().NoError(suite.helper.DB.Model(job).Update("workspace", workspace).Error)
+
+	attributes := service.NewSpeakerAttributeService(repository.NewSpeakerAttributeRepository(suite.helper.DB))
+	_, err := attributes.Record(ctx, job.ID, workspace, "age-gender", map[string]service.VoiceEstimate{
+		"SPEAKER_00": {Age: 33, Female: 0.92, Male: 0.05, Child: 0.03, SpeechSeconds: 30},
+	})
+	suite.Require().NoError(err)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/speakers/attributes", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var got []models.SpeakerAttribute
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &got))
+	suite.Require().Len(got, 1)
+	assert.Equal(suite.T(), models.SpeakerGenderFemale, got[0].Gender)
+	assert.Equal(suite.T(), "30-44", got[0].AgeRange)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/workspace/settings", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), `"speaker_attributes_disabled":false`)
+
+	// Disabling them in the workspace deletes what was estimated
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/workspace/settings", map[string]bool{"speaker_attributes_disabled": true}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var updated api.WorkspaceSettingsResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.True(suite.T(), updated.SpeakerAttributesDisabled)
+	assert.Equal(suite.T(), workspace, updated.Workspace)
+	assert.Equal(suite.T(), int64(1), updated.SpeakerAttributesDeleted)
+	assert.False(suite.T(), attributes.Allowed(ctx, workspace))
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/speakers/attributes", nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), "[]", w.Body.String())
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/missing/speakers/attributes", nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestReportSchedules() {
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/reports/schedules", map[string]interface{}{"name": "Weekly", "cron": "every monday", "s3_uri": "s3://reports"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/reports/schedules", map[string]interface{}{"name": "Weekly", "cron": "@weekly"}, true)
+	assert.Equal(suite.T(), 400, w.Code, "a destination is required")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/reports/schedules", map[string]interface{}{"name": "Weekly", "cron": "@weekly", "email_to": []string{"ops@example.com"}}, true)
+	assert.Equal(suite.T(), 400, w.Code, "email needs SMTP")
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/reports/schedules", map[string]interface{}{
+		"name": "Weekly", "cron": "0 8 * * mon", "timezone": "America/New_York", "keywords": []string{" refund ", "churn"}, "s3_uri": "s3://reports/scriberr/",
+	}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var schedule api.ReportScheduleResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &schedule))
+	assert.Equal(suite.T(), []string{"refund", "churn"}, schedule.Keywords)
+	assert.Equal(suite.T(), "s3://reports/scriberr", schedule.S3URI)
+	assert.Equal(suite.T(), models.ReportFormatMarkdown, schedule.Format)
+	assert.Equal(suite.T(), fmt.Sprintf("user-%d", suite.helper.TestUser.ID), schedule.Workspace)
+	suite.Require().NotNil(schedule.NextRunAt)
+	assert.Equal(suite.T(), time.Monday, schedule.NextRunAt.Add(-5*time.Hour).Weekday())
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/reports/schedules", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var schedules []api.ReportScheduleResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &schedules))
+	assert.Len(suite.T(), schedules, 1)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/reports/schedules/"+schedule.ID+"/preview", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Header().Get("Content-Type"), "text/markdown")
+	assert.Contains(suite.T(), w.Body.String(), "# Weekly")
+	assert.Contains(suite.T(), w.Body.String(), "## Failed jobs (0)")
+
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/reports/schedules/"+schedule.ID, map[string]interface{}{"name": "Weekly", "cron": "@daily", "format": "json", "s3_uri": "s3://reports", "enabled": false}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	var updated api.ReportScheduleResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.False(suite.T(), updated.Enabled)
+	assert.Nil(suite.T(), updated.NextRunAt, "disabled schedules are not due")
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/reports/schedules/"+schedule.ID, nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), 404, suite.makeAuthenticatedRequest("GET", "/api/v1/reports/schedules/"+schedule.ID, nil, true).Code)
+}
+
+func (suite *APIHandlerTestSuite) TestFieldPermissions() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "HR interview")
+	transcript := `{"segments":[{"start":0,"end":1,"text":"confidential grievance"}]}`
+	summary := "An HR grievance was discussed."
+	suite.Require().NoError(suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": transcript, "summary": summary}).Error)
+
+	viewer := models.User{Username: "hr.viewer", Password: "x", Role: models.RoleViewer}
+	suite.Require().NoError(suite.helper.DB.Create(&viewer).Error)
+	viewerToken, err := suite.helper.AuthService.GenerateToken(&viewer)
+	suite.Require().NoError(err)
+	asViewer := func(method, path string) *httptest.ResponseRecorder {
+		saved := suite.helper.TestToken
+		suite.helper.TestToken = viewerToken
+		defer func() { suite.helper.TestToken = saved }()
+		return suite.makeAuthenticatedRequest(method, path, nil, true)
+	}
+
+	w := suite.makeAuthenticatedRequest("PUT", "/api/v1/workspace/field-permissions/admin?workspace=", map[string]interface{}{"hidden_fields": []string{"audio"}}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/workspace/field-permissions/viewer?workspace=", map[string]interface{}{"hidden_fields": []string{"video"}}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/workspace/field-permissions/viewer?workspace=", map[string]interface{}{"hidden_fields": []string{"transcript", "audio", "transcript"}}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), `"hidden_fields":["audio","transcript"]`)
+	assert.Equal(suite.T(), 403, asViewer("PUT", "/api/v1/workspace/field-permissions/viewer").Code)
+
+	// The viewer gets the job without its audio or transcript, but with its summary
+	w = asViewer("GET", "/api/v1/transcription/"+job.ID)
+	suite.Require().Equal(200, w.Code)
+	var seen map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &seen))
+	assert.NotContains(suite.T(), seen, "transcript")
+	assert.Equal(suite.T(), "", seen["audio_path"])
+	assert.Equal(suite.T(), summary, seen["summary"])
+	w = asViewer("GET", "/api/v1/transcription/list?limit=100")
+	suite.Require().Equal(200, w.Code)
+	assert.NotContains(suite.T(), w.Body.String(), "confidential grievance")
+	assert.NotContains(suite.T(), w.Body.String(), "test/path/audio.mp3")
+
+	assert.Equal(suite.T(), 403, asViewer("GET", "/api/v1/transcription/"+job.ID+"/transcript").Code)
+	assert.Equal(suite.T(), 403, asViewer("GET", "/api/v1/transcription/"+job.ID+"/export?format=srt").Code)
+	assert.Equal(suite.T(), 403, asViewer("GET", "/api/v1/transcription/"+job.ID+"/audio").Code)
+	assert.Equal(suite.T(), 403, asViewer("GET", "/api/v1/transcription/"+job.ID+"/audio-url").Code)
+	assert.NotEqual(suite.T(), 403, asViewer("GET", "/api/v1/transcription/"+job.ID+"/summary").Code)
+
+	// Admins see every field
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID, nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "confidential grievance")
+	assert.Equal(suite.T(), 200, suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/"+job.ID+"/transcript", nil, true).Code)
+
+	w = asViewer("GET", "/api/v1/workspace/field-permissions")
+	assert.Equal(suite.T(), 200, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/workspace/field-permissions?workspace=", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var permissions []api.FieldPermissionResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &permissions))
+	suite.Require().Len(permissions, 1)
+
+	// An empty list shows every field again
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/workspace/field-permissions/viewer?workspace=", map[string]interface{}{"hidden_fields": []string{}}, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Equal(suite.T(), 200, asViewer("GET", "/api/v1/transcription/"+job.ID+"/transcript").Code)
+}
+
+func (suite *APIHandlerTestSuite) TestCreateMultiTrackJob() {
+	multiTrack := func(manifest string, files map[string]string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("manifest", manifest)
+		for field, name := range files {
+			part, err := writer.CreateFormFile(field, name)
+			suite.Require().NoError(err)
+			part.Write([]byte("dummy audio for " + name))
+		}
+		writer.Close()
+		req, _ := http.NewRequest("POST", "/api/v1/transcription/multitrack", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-API-Key", suite.helper.TestAPIKey)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	manifest := `{"title": "Episode 12", "parameters": {"model_family": "whisper", "model": "small"},
+		"tracks": [{"name": "Host", "file": "host_audio"}, {"name": "Guest", "file": "guest_audio", "offset": 1.5, "parameters": {"language": "de"}}]}`
+	w := multiTrack(manifest, map[string]string{"host_audio": "host.wav", "guest_audio": "guest.mp3"})
+	suite.Require().Equal(202, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &job))
+	assert.True(suite.T(), job.IsMultiTrack)
+	assert.True(suite.T(), job.Parameters.IsMultiTrackEnabled)
+	assert.Equal(suite.T(), "Episode 12", *job.Title)
+
+	var tracks []models.MultiTrackFile
+	suite.Require().NoError(suite.helper.DB.Where("transcription_job_id = ?", job.ID).Order("track_index").Find(&tracks).Error)
+	suite.Require().Len(tracks, 2)
+	assert.Equal(suite.T(), "Host", tracks[0].TrackName)
+	assert.Equal(suite.T(), "Host.wav", tracks[0].FileName)
+	assert.Nil(suite.T(), tracks[0].Parameters)
+	assert.Equal(suite.T(), "Guest", tracks[1].TrackName)
+	assert.Equal(suite.T(), 1.5, tracks[1].Offset)
+	overrides, err := tracks[1].ParameterOverrides()
+	suite.Require().NoError(err)
+	guestParams, err := job.Parameters.WithOverrides(overrides)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(guestParams.Language)
+	assert.Equal(suite.T(), "de", *guestParams.Language)
+	assert.Equal(suite.T(), "small", guestParams.Model)
+	for _, track := range tracks {
+		_, err := os.Stat(track.FilePath)
+		assert.NoError(suite.T(), err)
+	}
+
+	// Invalid manifests are rejected before anything is saved
+	for name, bad := range map[string]string{
+		"missing file":       `{"tracks": [{"name": "Host", "file": "nothing_here"}]}`,
+		"duplicate names":    `{"tracks": [{"name": "Host", "file": "host_audio"}, {"name": "host", "file": "host_audio"}]}`,
+		"no source":          `{"tracks": [{"name": "Host"}]}`,
+		"unknown parameter":  `{"tracks": [{"name": "Host", "file": "host_audio", "parameters": {"no_such_option": 1}}]}`,
+		"diarization":        `{"parameters": {"diarize": true}, "tracks": [{"name": "Host", "file": "host_audio"}]}`,
+		"private URL source": `{"tracks": [{"name": "Host", "url": "http://127.0.0.1/host.wav"}]}`,
+	} {
+		w := multiTrack(bad, map[string]string{"host_audio": "host.wav"})
+		assert.Equal(suite.T(), 400, w.Code, name+": "+w.Body.String())
+	}
+
+	// JSON requests can only name URLs
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/transcription/multitrack", map[string]interface{}{
+		"tracks": []map[string]interface{}{{"name": "Host", "file": "host_audio"}},
+	}, true)
+	assert.Equal(suite.T(), 400, w.Code)
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"scriberr/internal/api"
+	"scriberr/internal/mail"
 	"scriberr/internal/queue"
 	"scriberr/internal/repository"
 	"scriberr/internal/service"
@@ -43,6 +44,9 @@ func (suite *CLIHandlerTestSuite) SetupSuite() {
 	chatRepo := repository.NewChatRepository(suite.helper.DB)
 	noteRepo := repository.NewNoteRepository(suite.helper.DB)
 	speakerMappingRepo := repository.NewSpeakerMappingRepository(suite.helper.DB)
+	speakerRepo := repository.NewSpeakerRepository(suite.helper.DB)
+	searchRepo := repository.NewSearchRepository(suite.helper.DB)
+	recordingRepo := repository.NewRecordingRepository(suite.helper.DB)
 
 	// Initialize services
 	userService := service.NewUserService(userRepo, suite.helper.AuthService)
@@ -55,24 +59,41 @@ func (suite *CLIHandlerTestSuite) SetupSuite() {
 	assert.NoError(suite.T(), err)
 
 	suite.taskQueue = queue.NewTaskQueue(1, suite.unifiedProcessor)
-	suite.handler = api.NewHandler(
-		suite.helper.Config,
-		suite.helper.AuthService,
-		userService,
-		fileService,
-		jobRepo,
-		apiKeyRepo,
-		profileRepo,
-		userRepo,
-		llmConfigRepo,
-		summaryRepo,
-		chatRepo,
-		noteRepo,
-		speakerMappingRepo,
-		suite.taskQueue,
-		suite.unifiedProcessor,
-		suite.quickTranscription,
-	)
+	suite.handler = api.NewHandler(suite.helper.Config, api.Dependencies{
+		AuthService:         suite.helper.AuthService,
+		UserService:         userService,
+		FileService:         fileService,
+		JobRepo:             jobRepo,
+		APIKeyRepo:          apiKeyRepo,
+		ProfileRepo:         profileRepo,
+		UserRepo:            userRepo,
+		LLMConfigRepo:       llmConfigRepo,
+		SummaryRepo:         summaryRepo,
+		ChatRepo:            chatRepo,
+		NoteRepo:            noteRepo,
+		SpeakerMappingRepo:  speakerMappingRepo,
+		SpeakerRepo:         speakerRepo,
+		TagRepo:             repository.NewTagRepository(suite.helper.DB),
+		CollectionRepo:      repository.NewCollectionRepository(suite.helper.DB),
+		SearchRepo:          searchRepo,
+		RecordingRepo:       recordingRepo,
+		TaskQueue:           suite.taskQueue,
+		UnifiedProcessor:    suite.unifiedProcessor,
+		QuickTranscription:  suite.quickTranscription,
+		URLIngest:           service.NewURLIngestService(suite.helper.Config, jobRepo, suite.taskQueue),
+		Voiceprints:         service.NewVoiceprintService(suite.helper.Config, speakerMappingRepo),
+		SpeakerAttributes:   service.NewSpeakerAttributeService(repository.NewSpeakerAttributeRepository(suite.helper.DB)),
+		JobDeleter:          service.NewJobDeleter(suite.helper.Config.UploadDir, fileService, jobRepo, chatRepo, noteRepo, summaryRepo, speakerMappingRepo),
+		RetentionRepo:       repository.NewRetentionRepository(suite.helper.DB),
+		AWSTagRouteRepo:     repository.NewAWSTagRouteRepository(suite.helper.DB),
+		VocabularyRepo:      repository.NewVocabularyRepository(suite.helper.DB),
+		CorrectionRuleRepo:  repository.NewCorrectionRuleRepository(suite.helper.DB),
+		BenchmarkRepo:       repository.NewBenchmarkRepository(suite.helper.DB),
+		Storage:             service.NewStorageService(suite.helper.Config, repository.NewStorageRepository(suite.helper.DB), fileService),
+		ReportService:       service.NewReportService(repository.NewReportRepository(suite.helper.DB), fileService, mail.NewSender(mail.Config{})),
+		FieldPermissionRepo: repository.NewFieldPermissionRepository(suite.helper.DB),
+		AdapterConfigRepo:   repository.NewAdapterConfigRepository(suite.helper.DB),
+	})
 
 	// Set up router
 	suite.router = api.SetupRoutes(suite.handler, suite.helper.AuthService)
@@ -1,12 +1,23 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"scriberr/internal/database"
+	"scriberr/internal/llm"
 	"scriberr/internal/models"
+	"scriberr/internal/repository"
+	"scriberr/internal/service"
 
+	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
@@ -47,6 +58,45 @@ func (suite *DatabaseTestSuite) TestDatabaseInitialization() {
 	database.DB = originalDB
 }
 
+// Accounts and keys from before roles existed keep full access; new ones
+// default to the least privileged role
+func (suite *DatabaseTestSuite) TestLegacyRoleBackfill() {
+	testDbPath := "test_legacy_roles.db"
+	defer os.Remove(testDbPath)
+	originalDB := database.DB
+	defer func() { database.DB = originalDB }()
+
+	legacy, err := gorm.Open(sqlite.Open(testDbPath), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(legacy.Exec(`CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, username varchar(50) NOT NULL UNIQUE, password varchar(255) NOT NULL, created_at datetime, updated_at datetime)`).Error)
+	suite.Require().NoError(legacy.Exec(`INSERT INTO users (username, password) VALUES ('old.admin', 'x')`).Error)
+	sqlDB, err := legacy.DB()
+	suite.Require().NoError(err)
+	sqlDB.Close()
+
+	suite.Require().NoError(database.Initialize(testDbPath))
+	defer database.Close()
+
+	var old models.User
+	suite.Require().NoError(database.DB.Where("username = ?", "old.admin").First(&old).Error)
+	assert.Equal(suite.T(), models.RoleAdmin, old.Role)
+
+	fresh := models.User{Username: "new.user", Password: "x"}
+	suite.Require().NoError(database.DB.Create(&fresh).Error)
+	suite.Require().NoError(database.DB.First(&fresh, fresh.ID).Error)
+	assert.Equal(suite.T(), models.RoleViewer, fresh.Role)
+	key := models.APIKey{Key: "legacy-test-key", Name: "New key", IsActive: true}
+	suite.Require().NoError(database.DB.Create(&key).Error)
+	suite.Require().NoError(database.DB.First(&key, key.ID).Error)
+	assert.Equal(suite.T(), models.RoleViewer, key.Role)
+
+	// Initializing again leaves assigned roles alone
+	database.Close()
+	suite.Require().NoError(database.Initialize(testDbPath))
+	suite.Require().NoError(database.DB.First(&fresh, fresh.ID).Error)
+	assert.Equal(suite.T(), models.RoleViewer, fresh.Role)
+}
+
 // Test database initialization with invalid path
 func (suite *DatabaseTestSuite) TestDatabaseInitializationInvalidPath() {
 	// Try to initialize with an invalid path (directory doesn't exist and can't be created)
@@ -448,6 +498,380 @@ func (suite *DatabaseTestSuite) TestDatabaseQueries() {
 	db.Delete(&inactiveKey)
 }
 
+// Test full-text search index stays in sync with jobs and notes
+func (suite *DatabaseTestSuite) TestFullTextSearch() {
+	db := suite.helper.GetDB()
+	searchRepo := repository.NewSearchRepository(db)
+	ctx := context.Background()
+
+	title := "Quarterly planning"
+	job := models.TranscriptionJob{
+		ID:        "test-job-search-123",
+		Title:     &title,
+		Status:    models.StatusPending,
+		AudioPath: "/path/to/search.mp3",
+	}
+	assert.NoError(suite.T(), db.Create(&job).Error)
+
+	// Transcript segments are indexed when the transcript is saved
+	transcript := `{"segments": [{"start": 0.0, "end": 4.0, "text": "Welcome everyone"}, {"start": 4.0, "end": 9.5, "text": "The zeppelin budget is approved"}]}`
+	job.Transcript = &transcript
+	job.Status = models.StatusCompleted
+	assert.NoError(suite.T(), db.Save(&job).Error)
+
+	results, err := searchRepo.Search(ctx, "zeppel", 10, 5)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), results, 1) {
+		assert.Equal(suite.T(), job.ID, results[0].JobID)
+		assert.Equal(suite.T(), title, *results[0].Title)
+		match := results[0].Matches[0]
+		assert.Equal(suite.T(), "segment", match.Kind)
+		assert.Equal(suite.T(), "1", match.SourceID)
+		assert.Equal(suite.T(), 4.0, *match.StartTime)
+		assert.Equal(suite.T(), 9.5, *match.EndTime)
+		assert.Equal(suite.T(), "The zeppelin budget is approved", match.Snippet)
+		assert.Equal(suite.T(), []models.SearchHighlight{{Start: 4, End: 12}}, match.Highlights)
+	}
+
+	// Notes are searchable and removed from the index with their job
+	note := models.Note{
+		ID:              "test-note-search-123",
+		TranscriptionID: job.ID,
+		StartWordIndex:  0,
+		EndWordIndex:    1,
+		StartTime:       0,
+		EndTime:         4,
+		Quote:           "Welcome everyone",
+		Content:         "Follow up on the xylophone order",
+	}
+	assert.NoError(suite.T(), db.Create(&note).Error)
+
+	results, err = searchRepo.Search(ctx, "xylophone", 10, 5)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), results, 1) {
+		assert.Equal(suite.T(), "note", results[0].Matches[0].Kind)
+		assert.Equal(suite.T(), note.ID, results[0].Matches[0].SourceID)
+	}
+
+	assert.NoError(suite.T(), db.Delete(&note).Error)
+	assert.NoError(suite.T(), db.Delete(&job).Error)
+
+	results, err = searchRepo.Search(ctx, "zeppelin", 10, 5)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), results)
+}
+
+// Test the transcript embedding index
+func (suite *DatabaseTestSuite) TestEmbeddingIndex() {
+	db := suite.helper.GetDB()
+	embeddingRepo := repository.NewEmbeddingRepository(db)
+	ctx := context.Background()
+
+	transcript := `{"segments": [{"start": 0.0, "end": 4.0, "text": "We agreed on tiered pricing"}]}`
+	job := models.TranscriptionJob{
+		ID:         "test-job-embedding-123",
+		Status:     models.StatusCompleted,
+		AudioPath:  "/path/to/embedding.mp3",
+		Transcript: &transcript,
+	}
+	assert.NoError(suite.T(), db.Create(&job).Error)
+
+	stale, err := embeddingRepo.ListStaleJobs(ctx, "test-model", 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), stale, 1)
+
+	chunks := []models.TranscriptChunk{{
+		StartTime: 0,
+		EndTime:   4,
+		Text:      "We agreed on tiered pricing",
+		Vector:    []float32{0.25, -1, 3.5},
+	}}
+	assert.NoError(suite.T(), embeddingRepo.ReplaceChunks(ctx, job.ID, "test-model", chunks))
+
+	// Indexed jobs are only stale for a different embedding model
+	stale, err = embeddingRepo.ListStaleJobs(ctx, "test-model", 10)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), stale)
+	stale, err = embeddingRepo.ListStaleJobs(ctx, "other-model", 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), stale, 1)
+
+	stored, err := embeddingRepo.ListChunks(ctx, "test-model", nil, nil)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), stored, 1) {
+		assert.Equal(suite.T(), job.ID, stored[0].JobID)
+		assert.Equal(suite.T(), []float32{0.25, -1, 3.5}, stored[0].Vector)
+	}
+
+	// Chunks are removed with their job
+	assert.NoError(suite.T(), db.Delete(&job).Error)
+	stored, err = embeddingRepo.ListChunks(ctx, "test-model", nil, nil)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), stored)
+}
+
+func (suite *DatabaseTestSuite) TestJobStages() {
+	db := suite.helper.GetDB()
+	ctx := context.Background()
+	jobRepo := repository.NewJobRepository(db)
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Staged Job")
+
+	for _, stage := range []string{models.StageDownloading, models.StageTranscribing, models.StageUploadingResults, ""} {
+		suite.Require().NoError(jobRepo.EnterStage(ctx, job.ID, stage))
+	}
+
+	var stages []models.JobStage
+	suite.Require().NoError(db.Where("transcription_job_id = ?", job.ID).Order("id").Find(&stages).Error)
+	suite.Require().Len(stages, 3)
+	for i, stage := range []string{models.StageDownloading, models.StageTranscribing, models.StageUploadingResults} {
+		assert.Equal(suite.T(), stage, stages[i].Stage)
+		suite.Require().NotNil(stages[i].EndedAt, stage)
+		assert.False(suite.T(), stages[i].EndedAt.Before(stages[i].StartedAt))
+	}
+	found, err := jobRepo.FindByID(ctx, job.ID)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), found.Stage)
+
+	suite.Require().NoError(jobRepo.DeleteExecutionsByJobID(ctx, job.ID))
+	var count int64
+	db.Model(&models.JobStage{}).Where("transcription_job_id = ?", job.ID).Count(&count)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *DatabaseTestSuite) TestPostProcessing() {
+	db := suite.helper.GetDB()
+	ctx := context.Background()
+
+	// A fake OpenAI-compatible endpoint answering each automation prompt
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt := req.Messages[0].Content
+
+		answer := "Pricing moves to tiers."
+		if strings.Contains(prompt, "into chapters") {
+			answer = `[{"segment": 0, "title": "Tiered pricing"}, {"segment": 1, "title": "Customer reaction"}, {"segment": 9, "title": "Out of range"}]`
+		} else if strings.Contains(prompt, "dominant emotion") {
+			answer = `[{"index": 0, "sentiment": "Positive", "score": 1.4, "emotion": "joy"}, {"index": 1, "sentiment": "unsure", "score": -0.6, "emotion": "rage"}, {"index": 7, "sentiment": "negative", "score": -1, "emotion": "anger"}]`
+		} else if strings.Contains(prompt, "action items") {
+			answer = "Sure:\n```json\n[\"Alice drafts the price list\"]\n```"
+		} else if strings.Contains(prompt, "topic tags") {
+			answer = `["Pricing", "sales", "pricing"]`
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": answer}}},
+		})
+	}))
+	defer server.Close()
+
+	apiKey := "test-key"
+	suite.Require().NoError(db.Create(&models.LLMConfig{Provider: "openai", APIKey: &apiKey, OpenAIBaseURL: &server.URL, IsActive: true}).Error)
+	template := models.SummaryTemplate{Name: "Brief", Model: "gpt-test", Prompt: "Summarize briefly"}
+	suite.Require().NoError(db.Create(&template).Error)
+
+	transcript := `{"segments": [{"start": 0.0, "end": 4.0, "text": "Let's move to tiered pricing", "speaker": "SPEAKER_00"}, {"start": 4.0, "end": 6.5, "text": "That will upset customers", "speaker": "SPEAKER_01"}]}`
+	tags := `[{"Key": "source", "Value": "upload"}]`
+	job := models.TranscriptionJob{
+		ID:         "test-job-postprocess-123",
+		Status:     models.StatusCompleted,
+		AudioPath:  "/path/to/postprocess.mp3",
+		Transcript: &transcript,
+		Tags:       &tags,
+	}
+	job.Parameters.AutoSummaryTemplateID = &template.ID
+	job.Parameters.AutoActionItems = true
+	job.Parameters.AutoTag = true
+	job.Parameters.AutoSentiment = true
+	job.Parameters.AutoChapters = true
+	suite.Require().NoError(db.Create(&job).Error)
+
+	jobRepo := repository.NewJobRepository(db)
+	summaryRepo := repository.NewSummaryRepository(db)
+	postProcessing := service.NewPostProcessingService(jobRepo, repository.NewLLMConfigRepository(db), summaryRepo)
+
+	processed, err := postProcessing.ProcessPending(ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, processed)
+
+	stored, err := jobRepo.FindByID(ctx, job.ID)
+	suite.Require().NoError(err)
+	assert.NotNil(suite.T(), stored.PostProcessedAt)
+	assert.Nil(suite.T(), stored.PostProcessError)
+	assert.NotNil(suite.T(), stored.StageTimings.PostProcessingMs)
+	if assert.NotNil(suite.T(), stored.Summary) {
+		assert.Equal(suite.T(), "Pricing moves to tiers.", *stored.Summary)
+	}
+	if assert.NotNil(suite.T(), stored.ActionItems) {
+		assert.JSONEq(suite.T(), `["Alice drafts the price list"]`, *stored.ActionItems)
+	}
+	if assert.NotNil(suite.T(), stored.Tags) {
+		assert.JSONEq(suite.T(), `[{"Key": "source", "Value": "upload"}, {"Key": "topic", "Value": "pricing"}, {"Key": "topic", "Value": "sales"}]`, *stored.Tags)
+	}
+
+	if assert.NotNil(suite.T(), stored.Chapters) {
+		var chapters []service.Chapter
+		suite.Require().NoError(json.Unmarshal([]byte(*stored.Chapters), &chapters))
+		assert.Equal(suite.T(), []service.Chapter{
+			{Title: "Tiered pricing", Start: 0, End: 4, FirstSegment: 0, LastSegment: 0},
+			{Title: "Customer reaction", Start: 4, End: 6.5, FirstSegment: 1, LastSegment: 1},
+		}, chapters)
+	}
+
+	// Labels are normalized and indexes outside the transcript dropped
+	if assert.NotNil(suite.T(), stored.SegmentSentiment) {
+		var sentiment []service.SegmentSentiment
+		suite.Require().NoError(json.Unmarshal([]byte(*stored.SegmentSentiment), &sentiment))
+		assert.Equal(suite.T(), []service.SegmentSentiment{
+			{Segment: 0, Start: 0, End: 4, Speaker: "SPEAKER_00", Sentiment: service.SentimentPositive, Score: 1, Emotion: "joy"},
+			{Segment: 1, Start: 4, End: 6.5, Speaker: "SPEAKER_01", Sentiment: service.SentimentNegative, Score: -0.6, Emotion: "neutral"},
+		}, sentiment)
+	}
+
+	summary, err := summaryRepo.GetLatestSummary(ctx, job.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), template.ID, *summary.TemplateID)
+
+	// Each job is post-processed once
+	processed, err = postProcessing.ProcessPending(ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, processed)
+}
+
+func (suite *DatabaseTestSuite) TestLLMOutputCache() {
+	db := suite.helper.GetDB()
+	ctx := context.Background()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": fmt.Sprintf("Reply %d", requests)}}},
+		})
+	}))
+	defer server.Close()
+
+	summaryRepo := repository.NewSummaryRepository(db)
+	llmConfig := &models.LLMConfig{Provider: "openai", OpenAIBaseURL: &server.URL}
+	svc := llm.WithCache(llm.NewOpenAIService("test-key", &server.URL), summaryRepo, llmConfig)
+
+	// A transcript long enough to be summarized in three chunks
+	type segment struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	}
+	segments := make([]segment, 200)
+	for i := range segments {
+		segments[i] = segment{Start: float64(i), End: float64(i + 1), Text: fmt.Sprintf("Segment %d %s", i, strings.Repeat("talk ", 30))}
+	}
+	transcriptFor := func() *string {
+		data, _ := json.Marshal(map[string]interface{}{"segments": segments})
+		s := string(data)
+		return &s
+	}
+
+	_, err := service.SummarizeTranscript(ctx, svc, "gpt-test", transcriptFor(), "Summarize briefly")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, requests, "three chunks and the combined summary")
+
+	// An edit re-processes only its chunk, then the combined summary
+	segments[100].Text = "An edited segment"
+	_, err = service.SummarizeTranscript(ctx, svc, "gpt-test", transcriptFor(), "Summarize briefly")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 6, requests)
+
+	// Unchanged, everything comes from the cache; another template only reruns the final step
+	_, err = service.SummarizeTranscript(ctx, svc, "gpt-test", transcriptFor(), "Summarize briefly")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 6, requests)
+	_, err = service.SummarizeTranscript(ctx, svc, "gpt-test", transcriptFor(), "List the decisions")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 7, requests)
+
+	// Requests at a nonzero temperature are never cached
+	messages := []llm.ChatMessage{{Role: "user", Content: "Hello"}}
+	for i := 0; i < 2; i++ {
+		_, err = svc.ChatCompletion(ctx, "gpt-test", messages, 0.7)
+		suite.Require().NoError(err)
+	}
+	assert.Equal(suite.T(), 9, requests)
+
+	// Another endpoint may serve another model under the same name, so it does
+	// not share the cache
+	otherURL := server.URL + "/v1"
+	other := llm.WithCache(llm.NewOpenAIService("test-key", &otherURL), summaryRepo, &models.LLMConfig{Provider: "openai", OpenAIBaseURL: &otherURL})
+	_, err = service.SummarizeTranscript(ctx, other, "gpt-test", transcriptFor(), "Summarize briefly")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 13, requests)
+	assert.NotEqual(suite.T(), llm.RequestHash("openai", server.URL, "gpt-test", messages), llm.RequestHash("ollama", server.URL, "gpt-test", messages))
+
+	cached, err := summaryRepo.FindLLMOutput(ctx, llm.RequestHash("openai", server.URL, "gpt-test", []llm.ChatMessage{{Role: "user", Content: "missing"}}))
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), cached)
+	var stored int64
+	db.Model(&models.LLMOutput{}).Count(&stored)
+	assert.GreaterOrEqual(suite.T(), stored, int64(7))
+	deleted, err := summaryRepo.DeleteLLMOutputsUnusedSince(ctx, time.Now().Add(time.Minute))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), stored, deleted)
+}
+
+// Marked queries read from the replica; writes, transactions and unmarked
+// queries stay on the primary
+func (suite *DatabaseTestSuite) TestReadReplica() {
+	replicaPath := "test_read_replica.db"
+	defer os.Remove(replicaPath)
+	replicaDB, err := gorm.Open(sqlite.Open(replicaPath), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(replicaDB.AutoMigrate(&models.TranscriptionJob{}))
+	suite.Require().NoError(replicaDB.Create(&models.TranscriptionJob{ID: "replica-only", AudioPath: "/a.wav", Status: models.StatusCompleted}).Error)
+	sqlDB, _ := replicaDB.DB()
+	sqlDB.Close()
+
+	db := suite.helper.GetDB()
+	originalDB := database.DB
+	database.DB = db
+	defer func() { database.DB = originalDB }()
+	suite.Require().NoError(database.InitializeReplica(replicaPath))
+	defer database.CloseReplica()
+
+	jobRepo := repository.NewJobRepository(db)
+	ctx := context.Background()
+	readCtx := database.WithReplica(ctx)
+
+	job, err := jobRepo.FindByID(readCtx, "replica-only")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "/a.wav", job.AudioPath)
+	_, err = jobRepo.FindByID(ctx, "replica-only")
+	assert.Error(suite.T(), err, "unmarked queries read from the primary")
+
+	// Writes reach the primary even from a marked context
+	suite.Require().NoError(jobRepo.Create(readCtx, &models.TranscriptionJob{ID: "primary-write", AudioPath: "/b.wav", Status: models.StatusPending}))
+	var count int64
+	suite.Require().NoError(db.WithContext(readCtx).Model(&models.TranscriptionJob{}).Where("id = ?", "primary-write").Count(&count).Error)
+	assert.Equal(suite.T(), int64(0), count, "the replica has not seen the write")
+	suite.Require().NoError(db.WithContext(readCtx).Model(&models.TranscriptionJob{}).Where("id = ?", "primary-write").Update("status", models.StatusCompleted).Error)
+	job, err = jobRepo.FindByID(ctx, "primary-write")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.StatusCompleted, job.Status)
+
+	// Transactions read their own writes
+	suite.Require().NoError(db.WithContext(readCtx).Transaction(func(tx *gorm.DB) error {
+		var inTx models.TranscriptionJob
+		return tx.First(&inTx, "id = ?", "primary-write").Error
+	}))
+
+	// Without a replica marked queries read from the primary
+	suite.Require().NoError(database.CloseReplica())
+	_, err = jobRepo.FindByID(readCtx, "primary-write")
+	assert.NoError(suite.T(), err)
+	db.Where("id = ?", "primary-write").Delete(&models.TranscriptionJob{})
+}
+
 // Test database close functionality
 func (suite *DatabaseTestSuite) TestDatabaseClose() {
 	// Test that the Close function exists and can be called
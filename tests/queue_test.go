@@ -2,14 +2,18 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"scriberr/internal/models"
 	"scriberr/internal/queue"
+	"scriberr/internal/repository"
+	"scriberr/pkg/metrics"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -60,6 +64,14 @@ func (suite *QueueTestSuite) TearDownSuite() {
 	suite.helper.Cleanup()
 }
 
+// TearDownTest cancels the jobs a test left pending, like deferred jobs, so
+// that the queues of the next tests do not pick them up
+func (suite *QueueTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.helper.DB.Model(&models.TranscriptionJob{}).
+		Where("status IN ?", []models.JobStatus{models.StatusPending, models.StatusProcessing}).
+		Update("status", models.StatusCancelled).Error)
+}
+
 // Test queue creation
 func (suite *QueueTestSuite) TestNewTaskQueue() {
 	mockProcessor := &MockJobProcessor{}
@@ -72,16 +84,17 @@ func (suite *QueueTestSuite) TestNewTaskQueue() {
 	stats := tq.GetQueueStats()
 	assert.Equal(suite.T(), 2, stats["current_workers"])
 	assert.Equal(suite.T(), 0, stats["queue_size"])
-	assert.Equal(suite.T(), 200, stats["queue_capacity"])
+	assert.Equal(suite.T(), 1000, stats["queue_capacity"])
 }
 
 // Test enqueuing jobs
 func (suite *QueueTestSuite) TestEnqueueJob() {
 	mockProcessor := &MockJobProcessor{}
 	tq := queue.NewTaskQueue(1, mockProcessor)
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Enqueued Job")
 
-	// Test successful enqueue
-	err := tq.EnqueueJob("test-job-1")
+	// Test successful enqueue: a free worker picks up the pending job
+	err := tq.EnqueueJob(job.ID)
 	assert.NoError(suite.T(), err)
 
 	// Test queue stats after enqueue
@@ -119,6 +132,39 @@ func (suite *QueueTestSuite) TestJobProcessing() {
 	assert.Equal(suite.T(), models.StatusCompleted, updatedJob.Status)
 }
 
+// Test that the stages recorded are those of the job's latest run
+func (suite *QueueTestSuite) TestJobStages() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Test Job Stages")
+	jobRepo := repository.NewJobRepository(suite.helper.DB)
+	suite.Require().NoError(jobRepo.EnterStage(context.Background(), job.ID, models.StageDownloading))
+
+	mockProcessor := &MockJobProcessor{}
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, job.ID).Run(func(args mock.Arguments) {
+		jobRepo.EnterStage(context.Background(), job.ID, models.StageTranscribing)
+	}).Return(nil)
+
+	tq := queue.NewTaskQueue(1, mockProcessor)
+	tq.Start()
+	defer tq.Stop()
+	suite.Require().NoError(tq.EnqueueJob(job.ID))
+	time.Sleep(100 * time.Millisecond)
+
+	updatedJob, err := tq.GetJobStatus(job.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.StatusCompleted, updatedJob.Status)
+	assert.Empty(suite.T(), updatedJob.Stage)
+	suite.Require().Len(updatedJob.Stages, 1)
+	assert.Equal(suite.T(), models.StageTranscribing, updatedJob.Stages[0].Stage)
+	assert.NotNil(suite.T(), updatedJob.Stages[0].EndedAt)
+
+	// The time spent in each stage is recorded on the job and in the metrics
+	assert.NotNil(suite.T(), updatedJob.StageTimings.TranscriptionMs)
+	assert.Nil(suite.T(), updatedJob.StageTimings.DownloadMs)
+	var out strings.Builder
+	metrics.Write(&out)
+	assert.Contains(suite.T(), out.String(), `scriberr_job_stage_duration_seconds_count{stage="transcribing",model_family="whisper"}`)
+}
+
 // Test job processing failure
 func (suite *QueueTestSuite) TestJobProcessingFailure() {
 	mockProcessor := &MockJobProcessor{}
@@ -148,6 +194,73 @@ func (suite *QueueTestSuite) TestJobProcessingFailure() {
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), models.StatusFailed, updatedJob.Status)
 	assert.NotNil(suite.T(), updatedJob.ErrorMessage)
+	assert.Equal(suite.T(), models.ErrorTranscriptionFailed, updatedJob.ErrorCode)
+}
+
+// Test failed jobs record the error code the processor gave, or one guessed
+// from the error
+func (suite *QueueTestSuite) TestJobErrorCodes() {
+	downloadFailed := suite.helper.CreateTestTranscriptionJob(suite.T(), "Download Failed")
+	timedOut := suite.helper.CreateTestTranscriptionJob(suite.T(), "Timed Out")
+	mockProcessor := &MockJobProcessor{}
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, downloadFailed.ID).
+		Return(models.WithErrorCode(models.ErrorAudioDownloadFailed, errors.New("NoSuchKey"))).Once()
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, timedOut.ID).
+		Return(fmt.Errorf("transcription failed: job abc: %w", context.DeadlineExceeded)).Once()
+
+	tq := queue.NewTaskQueue(1, mockProcessor)
+	tq.Start()
+	defer tq.Stop()
+	assert.NoError(suite.T(), tq.EnqueueJob(downloadFailed.ID))
+	assert.NoError(suite.T(), tq.EnqueueJob(timedOut.ID))
+
+	for id, code := range map[string]models.ErrorCode{
+		downloadFailed.ID: models.ErrorAudioDownloadFailed,
+		timedOut.ID:       models.ErrorAdapterTimeout,
+	} {
+		assert.Eventually(suite.T(), func() bool {
+			job, err := tq.GetJobStatus(id)
+			return err == nil && job.Status == models.StatusFailed
+		}, time.Second, 10*time.Millisecond)
+		job, err := tq.GetJobStatus(id)
+		suite.Require().NoError(err)
+		assert.Equal(suite.T(), code, job.ErrorCode)
+	}
+
+	// Running the job again clears its code
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, mock.Anything).Return(nil)
+	suite.Require().NoError(suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id = ?", timedOut.ID).
+		Update("status", models.StatusPending).Error)
+	assert.NoError(suite.T(), tq.EnqueueJob(timedOut.ID))
+	assert.Eventually(suite.T(), func() bool {
+		job, err := tq.GetJobStatus(timedOut.ID)
+		return err == nil && job.Status == models.StatusCompleted && job.ErrorCode == ""
+	}, time.Second, 10*time.Millisecond)
+}
+
+// Test deferred jobs go back to pending instead of failing
+func (suite *QueueTestSuite) TestJobDeferred() {
+	mockProcessor := &MockJobProcessor{}
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("%w: waiting for model environments to be ready", queue.ErrJobDeferred))
+
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Test Job Deferred")
+
+	tq := queue.NewTaskQueue(1, mockProcessor)
+	tq.Start()
+	defer tq.Stop()
+
+	err := tq.EnqueueJob(job.ID)
+	assert.NoError(suite.T(), err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	mockProcessor.AssertCalled(suite.T(), "ProcessJobWithProcess", mock.Anything, job.ID, mock.Anything)
+
+	updatedJob, err := tq.GetJobStatus(job.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.StatusPending, updatedJob.Status)
+	assert.Nil(suite.T(), updatedJob.ErrorMessage)
 }
 
 // Test job cancellation
@@ -192,6 +305,52 @@ func (suite *QueueTestSuite) TestJobCancellation() {
 	assert.Equal(suite.T(), models.StatusFailed, updatedJob.Status)
 }
 
+// Test cancelling queued, running and finished jobs
+func (suite *QueueTestSuite) TestCancelJob() {
+	mockProcessor := &MockJobProcessor{processDelay: 5 * time.Second}
+	causes := make(chan error, 1)
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		go func() {
+			<-ctx.Done()
+			causes <- context.Cause(ctx)
+		}()
+	}).Return(nil)
+
+	tq := queue.NewTaskQueue(1, mockProcessor)
+
+	// Queued jobs are cancelled before a worker picks them up
+	queued := suite.helper.CreateTestTranscriptionJob(suite.T(), "Queued Job")
+	assert.NoError(suite.T(), tq.CancelJob(queued.ID))
+	updated, err := tq.GetJobStatus(queued.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.StatusCancelled, updated.Status)
+
+	// Finished jobs cannot be cancelled
+	assert.ErrorIs(suite.T(), tq.CancelJob(queued.ID), queue.ErrJobNotCancellable)
+
+	// Running jobs are stopped, freeing the worker
+	tq.Start()
+	defer tq.Stop()
+	running := suite.helper.CreateTestTranscriptionJob(suite.T(), "Running Job")
+	assert.NoError(suite.T(), tq.EnqueueJob(running.ID))
+	assert.Eventually(suite.T(), func() bool { return tq.IsJobRunning(running.ID) }, time.Second, 10*time.Millisecond)
+
+	assert.NoError(suite.T(), tq.CancelJob(running.ID))
+	assert.Eventually(suite.T(), func() bool { return !tq.IsJobRunning(running.ID) }, time.Second, 10*time.Millisecond)
+	updated, err = tq.GetJobStatus(running.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.StatusCancelled, updated.Status)
+	assert.Equal(suite.T(), "Job was cancelled by user", *updated.ErrorMessage)
+	assert.Equal(suite.T(), models.ErrorJobCancelled, updated.ErrorCode)
+	select {
+	case cause := <-causes:
+		assert.ErrorIs(suite.T(), cause, queue.ErrJobCancelled)
+	case <-time.After(time.Second):
+		suite.T().Error("job context was not cancelled")
+	}
+}
+
 // Test killing non-running job
 func (suite *QueueTestSuite) TestKillNonRunningJob() {
 	mockProcessor := &MockJobProcessor{}
@@ -226,7 +385,7 @@ func (suite *QueueTestSuite) TestGetQueueStats() {
 
 	assert.Equal(suite.T(), 3, stats["current_workers"])
 	assert.Equal(suite.T(), 0, stats["queue_size"]) // No jobs in queue buffer
-	assert.Equal(suite.T(), 200, stats["queue_capacity"])
+	assert.Equal(suite.T(), 1000, stats["queue_capacity"])
 
 	// Note: The actual counts depend on what's in the database
 	assert.Contains(suite.T(), stats, "pending_jobs")
@@ -302,17 +461,13 @@ func (suite *QueueTestSuite) TestQueueOverflow() {
 
 	tq := queue.NewTaskQueue(1, mockProcessor)
 
-	// Fill up the queue (capacity is 200)
-	for i := 0; i < 200; i++ {
+	// Jobs wait in the database rather than in the buffer, so enqueueing more
+	// than its capacity (1000) does not fail
+	for i := 0; i <= 1000; i++ {
 		err := tq.EnqueueJob(fmt.Sprintf("job-%d", i))
 		assert.NoError(suite.T(), err)
 	}
-
-	// The 201st job should fail
-	err := tq.EnqueueJob("overflow-job")
-	if assert.Error(suite.T(), err, "Expected error for queue overflow") {
-		assert.Contains(suite.T(), err.Error(), "queue is full")
-	}
+	assert.Equal(suite.T(), 0, tq.GetQueueStats()["queue_size"])
 }
 
 // Test job status retrieval
@@ -379,21 +534,22 @@ func (suite *QueueTestSuite) TestConcurrentAccess() {
 	numGoroutines := 10
 	jobsPerGoroutine := 5
 
+	// Create the jobs up front, since SQLite does not take concurrent writes
+	jobIDs := make([][]string, numGoroutines)
+	for i := range jobIDs {
+		for j := 0; j < jobsPerGoroutine; j++ {
+			job := suite.helper.CreateTestTranscriptionJob(suite.T(), fmt.Sprintf("Concurrent Job %d-%d", i, j))
+			jobIDs[i] = append(jobIDs[i], job.ID)
+		}
+	}
+
 	// Concurrently enqueue jobs
 	for i := 0; i < numGoroutines; i++ {
 		wg.Add(1)
 		go func(goroutineID int) {
 			defer wg.Done()
-			for j := 0; j < jobsPerGoroutine; j++ {
-				jobID := fmt.Sprintf("concurrent-job-%d-%d", goroutineID, j)
-				job := suite.helper.CreateTestTranscriptionJob(suite.T(), fmt.Sprintf("Concurrent Job %d-%d", goroutineID, j))
-				job.ID = jobID
-
-				err := tq.EnqueueJob(jobID)
-				// Some enqueues might fail if queue fills up, but shouldn't panic
-				if err != nil && !assert.Contains(suite.T(), err.Error(), "queue is full") {
-					assert.NoError(suite.T(), err)
-				}
+			for _, jobID := range jobIDs[goroutineID] {
+				assert.NoError(suite.T(), tq.EnqueueJob(jobID))
 			}
 		}(i)
 	}
@@ -431,9 +587,70 @@ func (suite *QueueTestSuite) TestResetZombieJobs() {
 	// Run ResetZombieJobs
 	tq.ResetZombieJobs()
 
-	// Verify it is now failed
+	// Verify it is pending again, to be run anew
 	var updatedJob models.TranscriptionJob
 	suite.helper.DB.First(&updatedJob, "id = ?", job.ID)
-	assert.Equal(suite.T(), models.StatusFailed, updatedJob.Status)
+	assert.Equal(suite.T(), models.StatusPending, updatedJob.Status)
+	assert.Equal(suite.T(), models.ErrorJobInterrupted, updatedJob.ErrorCode)
 	assert.Contains(suite.T(), *updatedJob.ErrorMessage, "interrupted by server restart")
 }
+
+// Test worker processes sharing the database
+func (suite *QueueTestSuite) TestSharedWorkers() {
+	mockProcessor := &MockJobProcessor{}
+	tq := queue.NewTaskQueue(1, mockProcessor)
+	tq.SetWorkerID("worker-a")
+	assert.Equal(suite.T(), "worker-a", tq.WorkerID())
+
+	claim := func(title, worker string, heartbeat time.Time) string {
+		job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+		err := suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"status":       models.StatusProcessing,
+			"worker_id":    worker,
+			"heartbeat_at": heartbeat,
+		}).Error
+		assert.NoError(suite.T(), err)
+		return job.ID
+	}
+	own := claim("Own Job", "worker-a", time.Now())
+	other := claim("Other Worker Job", "worker-b", time.Now())
+	stale := claim("Stale Worker Job", "worker-c", time.Now().Add(-10*time.Minute))
+
+	// Only the worker's own jobs and those of stale workers are taken back
+	tq.ResetZombieJobs()
+	status := func(jobID string) models.TranscriptionJob {
+		var job models.TranscriptionJob
+		suite.helper.DB.First(&job, "id = ?", jobID)
+		return job
+	}
+	assert.Equal(suite.T(), models.StatusPending, status(own).Status)
+	assert.Equal(suite.T(), models.StatusProcessing, status(other).Status)
+	assert.Equal(suite.T(), models.StatusPending, status(stale).Status)
+
+	// A job on another live worker counts as running, and killing it asks that worker
+	assert.True(suite.T(), tq.IsJobRunning(other))
+	assert.NoError(suite.T(), tq.KillJob(other))
+	killed := status(other)
+	assert.Equal(suite.T(), models.StatusProcessing, killed.Status)
+	assert.NotNil(suite.T(), killed.CancelRequestedAt)
+
+	stats := tq.GetQueueStats()
+	assert.Equal(suite.T(), "worker-a", stats["worker_id"])
+	workers := stats["workers"].([]queue.WorkerStatus)
+	if assert.Len(suite.T(), workers, 1) {
+		assert.Equal(suite.T(), "worker-b", workers[0].ID)
+		assert.Equal(suite.T(), 1, workers[0].RunningJobs)
+		assert.False(suite.T(), workers[0].Stale)
+	}
+	suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id = ?", other).Update("status", models.StatusFailed)
+
+	// A submit-only queue leaves jobs pending for the workers
+	api := queue.NewTaskQueue(1, mockProcessor)
+	api.DisableWorkers()
+	api.Start()
+	defer api.Stop()
+	assert.NoError(suite.T(), api.EnqueueJob(own))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(suite.T(), models.StatusPending, status(own).Status)
+	mockProcessor.AssertNotCalled(suite.T(), "ProcessJobWithProcess", mock.Anything, own)
+}
@@ -2,6 +2,11 @@ package tests
 
 import (
 	"bytes"
+	"context"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,15 +14,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"scriberr/internal/api"
 	"scriberr/internal/auth"
 	"scriberr/internal/config"
 	"scriberr/internal/database"
+	"scriberr/internal/mail"
 	"scriberr/internal/queue"
 	"scriberr/internal/repository"
+	"scriberr/internal/scanner"
 	"scriberr/internal/service"
 	"scriberr/internal/transcription"
 
@@ -69,6 +78,9 @@ func (suite *SecurityTestSuite) SetupSuite() {
 	chatRepo := repository.NewChatRepository(database.DB)
 	noteRepo := repository.NewNoteRepository(database.DB)
 	speakerMappingRepo := repository.NewSpeakerMappingRepository(database.DB)
+	speakerRepo := repository.NewSpeakerRepository(database.DB)
+	searchRepo := repository.NewSearchRepository(database.DB)
+	recordingRepo := repository.NewRecordingRepository(database.DB)
 
 	// Initialize services
 	userService := service.NewUserService(userRepo, suite.authService)
@@ -82,24 +94,41 @@ func (suite *SecurityTestSuite) SetupSuite() {
 		suite.T().Fatal("Failed to initialize quick transcription service:", err)
 	}
 	suite.taskQueue = queue.NewTaskQueue(1, suite.unifiedProcessor)
-	suite.handler = api.NewHandler(
-		suite.config,
-		suite.authService,
-		userService,
-		fileService,
-		jobRepo,
-		apiKeyRepo,
-		profileRepo,
-		userRepo,
-		llmConfigRepo,
-		summaryRepo,
-		chatRepo,
-		noteRepo,
-		speakerMappingRepo,
-		suite.taskQueue,
-		suite.unifiedProcessor,
-		suite.quickTranscriptionService,
-	)
+	suite.handler = api.NewHandler(suite.config, api.Dependencies{
+		AuthService:         suite.authService,
+		UserService:         userService,
+		FileService:         fileService,
+		JobRepo:             jobRepo,
+		APIKeyRepo:          apiKeyRepo,
+		ProfileRepo:         profileRepo,
+		UserRepo:            userRepo,
+		LLMConfigRepo:       llmConfigRepo,
+		SummaryRepo:         summaryRepo,
+		ChatRepo:            chatRepo,
+		NoteRepo:            noteRepo,
+		SpeakerMappingRepo:  speakerMappingRepo,
+		SpeakerRepo:         speakerRepo,
+		TagRepo:             repository.NewTagRepository(database.DB),
+		CollectionRepo:      repository.NewCollectionRepository(database.DB),
+		SearchRepo:          searchRepo,
+		RecordingRepo:       recordingRepo,
+		TaskQueue:           suite.taskQueue,
+		UnifiedProcessor:    suite.unifiedProcessor,
+		QuickTranscription:  suite.quickTranscriptionService,
+		URLIngest:           service.NewURLIngestService(suite.config, jobRepo, suite.taskQueue),
+		Voiceprints:         service.NewVoiceprintService(suite.config, speakerMappingRepo),
+		SpeakerAttributes:   service.NewSpeakerAttributeService(repository.NewSpeakerAttributeRepository(database.DB)),
+		JobDeleter:          service.NewJobDeleter(suite.config.UploadDir, fileService, jobRepo, chatRepo, noteRepo, summaryRepo, speakerMappingRepo),
+		RetentionRepo:       repository.NewRetentionRepository(database.DB),
+		AWSTagRouteRepo:     repository.NewAWSTagRouteRepository(database.DB),
+		VocabularyRepo:      repository.NewVocabularyRepository(database.DB),
+		CorrectionRuleRepo:  repository.NewCorrectionRuleRepository(database.DB),
+		BenchmarkRepo:       repository.NewBenchmarkRepository(database.DB),
+		Storage:             service.NewStorageService(suite.config, repository.NewStorageRepository(database.DB), fileService),
+		ReportService:       service.NewReportService(repository.NewReportRepository(database.DB), fileService, mail.NewSender(mail.Config{})),
+		FieldPermissionRepo: repository.NewFieldPermissionRepository(database.DB),
+		AdapterConfigRepo:   repository.NewAdapterConfigRepository(database.DB),
+	})
 
 	// Set up router
 	suite.router = api.SetupRoutes(suite.handler, suite.authService)
@@ -240,6 +269,7 @@ func (suite *SecurityTestSuite) TestTranscriptionEndpointsUnauthorized() {
 		{"GET", "/api/v1/transcription/test-id/status", nil, false},
 		{"GET", "/api/v1/transcription/test-id/transcript", nil, false},
 		{"GET", "/api/v1/transcription/test-id/audio", nil, false},
+		{"GET", "/api/v1/transcription/test-id/audio-url", nil, false},
 		{"PUT", "/api/v1/transcription/test-id/title", map[string]string{"title": "New Title"}, false},
 		{"GET", "/api/v1/transcription/test-id/summary", nil, false},
 		{"GET", "/api/v1/transcription/test-id", nil, false},
@@ -315,6 +345,9 @@ func (suite *SecurityTestSuite) TestAdminEndpointsUnauthorized() {
 		body   interface{}
 	}{
 		{"GET", "/api/v1/admin/queue/stats", nil},
+		{"GET", "/api/v1/admin/quarantine", nil},
+		{"POST", "/api/v1/admin/quarantine/test-id/release", nil},
+		{"DELETE", "/api/v1/admin/quarantine/test-id", nil},
 	}
 
 	for _, tc := range testCases {
@@ -545,6 +578,128 @@ func (suite *SecurityTestSuite) TestSecurityHeaders() {
 	assert.NotEmpty(suite.T(), w.Header().Get("Access-Control-Allow-Headers"))
 }
 
+// Test that file paths are confined to their workspace
+func (suite *SecurityTestSuite) TestWorkspacePathScoping() {
+	root := suite.config.UploadDir
+	aliceDir, err := service.WorkspaceDir(root, "user-1")
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.MkdirAll(aliceDir, 0755))
+
+	alicePath := filepath.Join(aliceDir, "audio.mp3")
+	suite.Require().NoError(os.WriteFile(alicePath, []byte("audio"), 0644))
+
+	_, err = service.ScopePath(root, "user-1", alicePath)
+	assert.NoError(suite.T(), err)
+
+	// Other workspaces and the legacy root cannot address the file
+	_, err = service.ScopePath(root, "user-2", alicePath)
+	assert.ErrorIs(suite.T(), err, service.ErrPathOutsideWorkspace)
+	_, err = service.ScopePath(root, "", alicePath)
+	assert.ErrorIs(suite.T(), err, service.ErrPathOutsideWorkspace)
+
+	// Traversal and symlinks out of the workspace are rejected
+	_, err = service.ScopePath(root, "user-2", filepath.Join(root, "workspaces", "user-2", "..", "user-1", "audio.mp3"))
+	assert.ErrorIs(suite.T(), err, service.ErrPathOutsideWorkspace)
+
+	bobDir, err := service.WorkspaceDir(root, "user-2")
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.MkdirAll(bobDir, 0755))
+	// An absolute target, so the link resolves wherever the test runs from
+	aliceTarget, err := filepath.Abs(alicePath)
+	suite.Require().NoError(err)
+	link := filepath.Join(bobDir, "link.mp3")
+	suite.Require().NoError(os.Symlink(aliceTarget, link))
+	_, err = service.ScopePath(root, "user-2", link)
+	assert.ErrorIs(suite.T(), err, service.ErrPathOutsideWorkspace)
+
+	// A dangling link inside the workspace would be followed by a later write
+	dangling := filepath.Join(bobDir, "dangling.mp3")
+	suite.Require().NoError(os.Symlink(filepath.Join(aliceDir, "missing.mp3"), dangling))
+	_, err = service.ScopePath(root, "user-2", dangling)
+	assert.ErrorIs(suite.T(), err, service.ErrPathOutsideWorkspace)
+
+	// Workspace names cannot contain path elements
+	_, err = service.WorkspaceDir(root, "../user-1")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SecurityTestSuite) TestUploadScanner() {
+	path := filepath.Join(suite.config.UploadDir, "scan-me.mp3")
+	suite.Require().NoError(os.WriteFile(path, []byte("audio"), 0644))
+
+	// Scanning is off unless configured
+	s, err := scanner.New(&config.Config{})
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), s)
+
+	_, err = scanner.New(&config.Config{ScanMode: scanner.ModeClamAV})
+	assert.Error(suite.T(), err)
+
+	verdict := `{"clean": true}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(suite.T(), "audio", string(body))
+		assert.Equal(suite.T(), "scan-me.mp3", r.Header.Get("X-Filename"))
+		w.Write([]byte(verdict))
+	}))
+	defer server.Close()
+
+	s, err = scanner.New(&config.Config{ScanMode: scanner.ModeHTTP, ScanHTTPURL: server.URL, ScanTimeoutSeconds: 5})
+	suite.Require().NoError(err)
+
+	result, err := s.Scan(context.Background(), path)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), result.Clean)
+
+	verdict = `{"clean": false, "signature": "Eicar-Test-Signature"}`
+	result, err = s.Scan(context.Background(), path)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), result.Clean)
+	assert.Equal(suite.T(), "Eicar-Test-Signature", result.Signature)
+
+	// A malformed verdict is an error, never a clean result
+	verdict = `{}`
+	_, err = s.Scan(context.Background(), path)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SecurityTestSuite) TestSignedPlaybackURL() {
+	signWith := func(key []byte, jobID string, expires int64) string {
+		mac := hmac.New(sha256.New, key)
+		fmt.Fprintf(mac, "audio-playback:%s:%d", jobID, expires)
+		return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	}
+	playbackKey, err := hkdf.Key(sha256.New, []byte(suite.config.JWTSecret), nil, "scriberr audio playback v1", sha256.Size)
+	suite.Require().NoError(err)
+	sign := func(jobID string, expires int64) string {
+		return signWith(playbackKey, jobID, expires)
+	}
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Minute).Unix()
+
+	testCases := []struct {
+		name string
+		path string
+		code int
+	}{
+		{"missing signature", "/api/v1/media/test-id/audio", http.StatusForbidden},
+		{"wrong signature", fmt.Sprintf("/api/v1/media/test-id/audio?expires=%d&signature=%s", future, sign("other-id", future)), http.StatusForbidden},
+		{"tampered expiry", fmt.Sprintf("/api/v1/media/test-id/audio?expires=%d&signature=%s", future+1, sign("test-id", future)), http.StatusForbidden},
+		{"expired", fmt.Sprintf("/api/v1/media/test-id/audio?expires=%d&signature=%s", past, sign("test-id", past)), http.StatusForbidden},
+		// The JWT secret itself is not the playback key
+		{"jwt secret", fmt.Sprintf("/api/v1/media/test-id/audio?expires=%d&signature=%s", future, signWith([]byte(suite.config.JWTSecret), "test-id", future)), http.StatusForbidden},
+		// A valid signature gets past authorization to the (missing) job
+		{"valid", fmt.Sprintf("/api/v1/media/test-id/audio?expires=%d&signature=%s", future, sign("test-id", future)), http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		suite.T().Run(tc.name, func(t *testing.T) {
+			w := suite.makeUnauthenticatedRequest("GET", tc.path, nil)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
 func TestSecurityTestSuite(t *testing.T) {
 	suite.Run(t, new(SecurityTestSuite))
 }
@@ -1,9 +1,11 @@
 package tests
 
 import (
+	"crypto/ed25519"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"scriberr/internal/auth"
 	"scriberr/internal/config"
@@ -40,8 +42,12 @@ func NewTestHelper(t *testing.T, dbName string) *TestHelper {
 		DatabasePath: dbName,
 		JWTSecret:    "test-secret-key-for-unit-tests",
 		UploadDir:    "test_uploads_" + dbName,
+		BackupDir:    "test_backups_" + dbName,
 		UVPath:       "uv",
 		WhisperXEnv:  "test_whisperx_env",
+		CostCurrency: "USD",
+		// Fixed seed so custody signatures are reproducible across runs
+		CustodySigningKey: ed25519.NewKeyFromSeed([]byte(strings.Repeat("k", ed25519.SeedSize))),
 	}
 
 	// Initialize test database
@@ -80,6 +86,7 @@ func (h *TestHelper) Cleanup() {
 	database.Close()
 	os.Remove(h.Config.DatabasePath)
 	os.RemoveAll(h.Config.UploadDir)
+	os.RemoveAll(h.Config.BackupDir)
 }
 
 // createTestCredentials creates a test user and API key for testing
@@ -91,6 +98,7 @@ func (h *TestHelper) createTestCredentials(t *testing.T) {
 	user := models.User{
 		Username: "testuser",
 		Password: hashedPassword,
+		Role:     models.RoleAdmin,
 	}
 
 	result := h.DB.Create(&user)
@@ -107,6 +115,7 @@ func (h *TestHelper) createTestCredentials(t *testing.T) {
 		Key:      "test-api-key-" + strings.ReplaceAll(t.Name(), "/", "_"),
 		Name:     "Test API Key for " + strings.ReplaceAll(t.Name(), "/", "_"),
 		IsActive: true,
+		Role:     models.RoleAdmin,
 	}
 
 	result = h.DB.Create(&apiKey)
@@ -290,6 +299,97 @@ func (m *MockJobRepository) UpdateTranscript(ctx context.Context, jobID string,
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) UpdateIngestProgress(ctx context.Context, jobID string, progress float64) error {
+	args := m.Called(ctx, jobID, progress)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateProgress(ctx context.Context, jobID string, progress float64) error {
+	args := m.Called(ctx, jobID, progress)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) EnterStage(ctx context.Context, jobID, stage string) error {
+	args := m.Called(ctx, jobID, stage)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateScanResult(ctx context.Context, jobID string, scanStatus string, signature *string) error {
+	args := m.Called(ctx, jobID, scanStatus, signature)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateReview(ctx context.Context, job *models.TranscriptionJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListNeedingReview(ctx context.Context, offset, limit int) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListTranscriptRevisions(ctx context.Context, jobID string) ([]models.TranscriptRevision, error) {
+	args := m.Called(ctx, jobID)
+	return args.Get(0).([]models.TranscriptRevision), args.Error(1)
+}
+
+func (m *MockJobRepository) FindTranscriptRevision(ctx context.Context, jobID string, revision int) (*models.TranscriptRevision, error) {
+	args := m.Called(ctx, jobID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptRevision), args.Error(1)
+}
+
+func (m *MockJobRepository) DeleteTranscriptRevisionsByJobID(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateAlignmentFallback(ctx context.Context, jobID string, reason *string) error {
+	args := m.Called(ctx, jobID, reason)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateAudioSHA256(ctx context.Context, jobID string, sum string) error {
+	args := m.Called(ctx, jobID, sum)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateAudioSeconds(ctx context.Context, jobID string, seconds float64) error {
+	args := m.Called(ctx, jobID, seconds)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindDuplicate(ctx context.Context, job *models.TranscriptionJob) (*models.TranscriptionJob, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdateDuplicateOf(ctx context.Context, jobID string, originalID string) error {
+	args := m.Called(ctx, jobID, originalID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListByStatus(ctx context.Context, status models.JobStatus) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListPendingPostProcessing(ctx context.Context, limit int) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdatePostProcessing(ctx context.Context, job *models.TranscriptionJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
 func (m *MockJobRepository) CreateExecution(ctx context.Context, execution *models.TranscriptionJobExecution) error {
 	args := m.Called(ctx, execution)
 	return args.Error(0)
@@ -299,3 +399,92 @@ func (m *MockJobRepository) UpdateExecution(ctx context.Context, execution *mode
 	args := m.Called(ctx, execution)
 	return args.Error(0)
 }
+
+func (m *MockJobRepository) CreateShadowRun(ctx context.Context, run *models.ShadowRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateShadowRun(ctx context.Context, run *models.ShadowRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindShadowRun(ctx context.Context, id string) (*models.ShadowRun, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShadowRun), args.Error(1)
+}
+
+func (m *MockJobRepository) ListShadowRuns(ctx context.Context, modelID string, limit int) ([]models.ShadowRun, error) {
+	args := m.Called(ctx, modelID, limit)
+	return args.Get(0).([]models.ShadowRun), args.Error(1)
+}
+
+func (m *MockJobRepository) DeleteShadowRunsByJobID(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) CreateExecutionComponents(ctx context.Context, components []models.ExecutionComponent) error {
+	args := m.Called(ctx, components)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListComponentVersions(ctx context.Context, kind, name string) ([]models.ComponentVersion, error) {
+	args := m.Called(ctx, kind, name)
+	return args.Get(0).([]models.ComponentVersion), args.Error(1)
+}
+
+func (m *MockJobRepository) ListComponentJobs(ctx context.Context, kind, name string, versions []string) ([]models.ComponentJob, error) {
+	args := m.Called(ctx, kind, name, versions)
+	return args.Get(0).([]models.ComponentJob), args.Error(1)
+}
+
+func (m *MockJobRepository) AddJobCost(ctx context.Context, jobID string, cost float64) error {
+	args := m.Called(ctx, jobID, cost)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) SummarizeCosts(ctx context.Context, groupBy string, from, to time.Time) ([]models.CostSummary, error) {
+	args := m.Called(ctx, groupBy, from, to)
+	return args.Get(0).([]models.CostSummary), args.Error(1)
+}
+
+func (m *MockJobRepository) SummarizeUsage(ctx context.Context, interval, groupBy string, from, to time.Time) ([]models.UsageSummary, error) {
+	args := m.Called(ctx, interval, groupBy, from, to)
+	return args.Get(0).([]models.UsageSummary), args.Error(1)
+}
+
+func (m *MockJobRepository) FindExecution(ctx context.Context, jobID, executionID string) (*models.TranscriptionJobExecution, error) {
+	args := m.Called(ctx, jobID, executionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptionJobExecution), args.Error(1)
+}
+
+func (m *MockJobRepository) AddQuotaUsage(ctx context.Context, workspace, month string, seconds float64) error {
+	args := m.Called(ctx, workspace, month, seconds)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindQuotaUsage(ctx context.Context, workspace, month string) (float64, error) {
+	args := m.Called(ctx, workspace, month)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockJobRepository) FindByTitle(ctx context.Context, title string) (*models.TranscriptionJob, error) {
+	args := m.Called(ctx, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptionJob), args.Error(1)
+}
+
+func (m *MockJobRepository) ListByStatusAndTitle(ctx context.Context, statuses []models.JobStatus, titleContains string, offset, limit int) ([]models.TranscriptionJob, error) {
+	args := m.Called(ctx, statuses, titleContains, offset, limit)
+	return args.Get(0).([]models.TranscriptionJob), args.Error(1)
+}
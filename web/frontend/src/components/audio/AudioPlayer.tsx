@@ -59,7 +59,16 @@ export const AudioPlayer = forwardRef<AudioPlayerRef, AudioPlayerProps>(({
                 setError(null);
                 setLoadingProgress(0);
 
-                const audioUrl = `/api/v1/transcription/${audioId}/audio`;
+                // Media requests carry a signed URL instead of the auth headers
+                const urlRes = await fetch(`/api/v1/transcription/${audioId}/audio-url`, {
+                    headers: { ...getAuthHeaders() }
+                });
+                if (!urlRes.ok) {
+                    setError(urlRes.status === 403 ? "This audio file is not available." : "Failed to load audio. Please try again.");
+                    setIsLoading(false);
+                    return;
+                }
+                const { url: audioUrl } = await urlRes.json();
 
                 const isDark = theme === 'dark';
                 // Aesthetic Gray / True Black Palette
@@ -79,9 +88,6 @@ export const AudioPlayer = forwardRef<AudioPlayerRef, AudioPlayerProps>(({
                     normalize: true,
                     backend: 'WebAudio',
                     dragToSeek: true,
-                    fetchParams: {
-                        headers: { ...getAuthHeaders() }
-                    }
                 });
 
                 wavesurferRef.current = ws;